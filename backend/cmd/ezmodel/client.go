@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+)
+
+// Environment variables the API flags default to, so CI jobs keep the token
+// out of their command lines
+const (
+	apiURLEnv   = "EZMODEL_API_URL"
+	apiTokenEnv = "EZMODEL_API_TOKEN"
+)
+
+// apiTimeout bounds each request to the API
+const apiTimeout = time.Minute
+
+// apiClient calls the HTTP API of a running deployment with an API token
+type apiClient struct {
+	baseURL string // Up to and including /api, e.g. https://api.ezmodel.org/api
+	token   string
+	http    *http.Client
+}
+
+func newAPIClient(baseURL, token string) (*apiClient, error) {
+	if token == "" {
+		return nil, fmt.Errorf("the API needs a token: pass --token or set %s", apiTokenEnv)
+	}
+	return &apiClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: apiTimeout},
+	}, nil
+}
+
+// download returns the body of a GET request answered without the JSON envelope
+func (c *apiClient) download(path string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
+
+// postJSON sends body as JSON and decodes the data of the response into result
+func (c *apiClient) postJSON(path string, body []byte, result any) error {
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.decode(req, result)
+}
+
+// upload sends a file as the multipart field named field and decodes the data
+// of the response into result
+func (c *apiClient) upload(path, field, filename string, result any) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile(field, filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return c.decode(req, result)
+}
+
+func (c *apiClient) decode(req *http.Request, result any) error {
+	body, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	response := dto.APIResponse{Data: result}
+	return json.Unmarshal(body, &response)
+}
+
+// do sends req with the token, turning error responses into errors with
+// the message of the API
+func (c *apiClient) do(req *http.Request) ([]byte, error) {
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		var response dto.APIResponse
+		if json.Unmarshal(body, &response) != nil || response.Message == "" {
+			return nil, fmt.Errorf("API answered %s", resp.Status)
+		}
+		if response.Errors != nil {
+			details, _ := json.Marshal(response.Errors)
+			return nil, fmt.Errorf("API answered %s: %s %s", resp.Status, response.Message, details)
+		}
+		return nil, fmt.Errorf("API answered %s: %s", resp.Status, response.Message)
+	}
+	return body, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIClientDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ezm_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"success":false,"message":"Invalid token"}`))
+			return
+		}
+		assert.Equal(t, "/api/projects/42/export", r.URL.Path)
+		assert.Equal(t, "sql", r.URL.Query().Get("format"))
+		w.Write([]byte("CREATE TABLE"))
+	}))
+	defer server.Close()
+
+	client, err := newAPIClient(server.URL+"/api/", "ezm_token")
+	require.NoError(t, err)
+	data, err := client.download("/projects/42/export?format=sql")
+	require.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE", string(data))
+
+	client, err = newAPIClient(server.URL+"/api", "wrong")
+	require.NoError(t, err)
+	_, err = client.download("/projects/42/export?format=sql")
+	assert.EqualError(t, err, "API answered 401 Unauthorized: Invalid token")
+
+	_, err = newAPIClient(server.URL, "")
+	assert.ErrorContains(t, err, apiTokenEnv)
+}
+
+func TestAPIClientUpload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		defer file.Close()
+		assert.Equal(t, "tables.csv", header.Filename)
+		w.Write([]byte(`{"success":true,"data":{"tables":[{"name":"users"}],"fields":[],"relationships":[],"conflicts":[],"dry_run":true}}`))
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "tables.csv")
+	require.NoError(t, os.WriteFile(path, []byte("table,field\nusers,id\n"), 0o600))
+
+	client, err := newAPIClient(server.URL, "ezm_token")
+	require.NoError(t, err)
+	var schema dto.SchemaResponse
+	require.NoError(t, client.upload("/projects/42/schema/import?dry_run=true", "file", path, &schema))
+	assert.True(t, schema.DryRun)
+	require.Len(t, schema.Tables, 1)
+	assert.Equal(t, "users", schema.Tables[0].Name)
+}
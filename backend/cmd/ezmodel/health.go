@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+)
+
+// healthTimeout bounds the liveness request, below the container health check timeout
+const healthTimeout = 2 * time.Second
+
+// healthResult is printed when the API answers
+type healthResult struct {
+	Status int `json:"status"`
+}
+
+// health checks the liveness probe of the API listening on this host, for
+// container health checks in images without curl
+func health(args []string) error {
+	newFlags("health", "health [flags]").Parse(args)
+	cfg := config.Load()
+
+	client := &http.Client{Timeout: healthTimeout}
+	resp, err := client.Get("http://localhost" + cfg.Port + "/healthz")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("liveness probe answered %s", resp.Status)
+	}
+	return printResult(healthResult{Status: resp.StatusCode}, "ok")
+}
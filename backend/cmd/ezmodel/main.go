@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"gorm.io/gorm"
+)
+
+const usage = `Usage: ezmodel <command> [flags]
+
+Commands:
+  serve              Run the HTTP API
+  migrate            Apply or revert database migrations
+  seed               Clear the database and fill it with fixtures or random data
+  export             Write a project's schema as SQL or JSON through the API, or as a fixture
+  import             Add a schema to a project through the API, or fixtures to the database
+  user create-admin  Create an account allowed to use the admin API
+  health             Check that the API of this host is alive
+
+Every command reads the .env file of its ENV and takes --output json to
+print its result for scripts; logs go to stderr. Run ezmodel <command> -h
+for the flags of a command.
+`
+
+// commands run the subcommands with the arguments after their name
+var commands = map[string]func(args []string) error{
+	"serve":   serve,
+	"migrate": migrateCommand,
+	"seed":    seedCommand,
+	"export":  exportCommand,
+	"import":  importCommand,
+	"user":    userCommand,
+	"health":  health,
+}
+
+// output is the format of results, set by the --output flag of every command
+var output = "text"
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		fmt.Print(usage)
+		return
+	}
+	run, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
+	}
+
+	if err := run(os.Args[2:]); err != nil {
+		if output == "json" {
+			printJSON(map[string]string{"error": err.Error()})
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// newFlags starts the flags of a command, with the --output flag they all take
+func newFlags(name, synopsis string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: ezmodel %s\n\nFlags:\n", synopsis)
+		flags.PrintDefaults()
+	}
+	flags.Func("output", "Format of the result: text or json", func(value string) error {
+		if value != "text" && value != "json" {
+			return errors.New("must be text or json")
+		}
+		output = value
+		return nil
+	})
+	return flags
+}
+
+// printResult prints the result of a command to stdout: as JSON with
+// --output json, otherwise as the text of format
+func printResult(result any, format string, args ...any) error {
+	if output == "json" {
+		return printJSON(result)
+	}
+	_, err := fmt.Printf(format+"\n", args...)
+	return err
+}
+
+func printJSON(value any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// connect opens the database of cfg, to be released with the returned close
+func connect(cfg *config.Config) (*gorm.DB, func(), error) {
+	database, err := db.Connect(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	sqlDB, err := database.DB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get database: %w", err)
+	}
+	return database, func() { sqlDB.Close() }, nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/golang-migrate/migrate/v4"
+)
+
+const migrateSynopsis = `migrate [flags] <command>
+
+Commands:
+  up          Apply all pending migrations
+  down [n]    Revert the last n migrations (default 1)
+  version     Print the applied and the latest version
+  force <v>   Record version v as cleanly applied, after fixing a failed migration by hand`
+
+// migrationState is printed by every migrate command once it ran
+type migrationState struct {
+	Applied *uint `json:"applied"` // Null before the first migration
+	Dirty   bool  `json:"dirty"`   // The applied migration failed part way
+	Latest  uint  `json:"latest"`
+}
+
+func migrateCommand(args []string) error {
+	flags := newFlags("migrate", migrateSynopsis)
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("migrate needs a command")
+	}
+
+	cfg := config.Load()
+
+	m, err := db.NewMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+	m.Log = logger{}
+
+	if err := runMigration(m, flags.Arg(0), flags.Args()[1:]); err != nil {
+		return err
+	}
+
+	state, err := readMigrationState(m)
+	if err != nil {
+		return err
+	}
+	if state.Applied == nil {
+		return printResult(state, "applied: none\nlatest: %d", state.Latest)
+	}
+	dirty := ""
+	if state.Dirty {
+		dirty = " (dirty)"
+	}
+	return printResult(state, "applied: %d%s\nlatest: %d", *state.Applied, dirty, state.Latest)
+}
+
+func runMigration(m *migrate.Migrate, command string, args []string) error {
+	switch command {
+	case "up":
+		return ignoreNoChange(m.Up())
+	case "down":
+		steps := 1
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of migrations %q", args[0])
+			}
+			steps = n
+		}
+		return ignoreNoChange(m.Steps(-steps))
+	case "version":
+		return nil
+	case "force":
+		if len(args) == 0 {
+			return errors.New("force needs a version")
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[0])
+		}
+		return m.Force(version)
+	default:
+		return fmt.Errorf("unknown migrate command %q", command)
+	}
+}
+
+func readMigrationState(m *migrate.Migrate) (*migrationState, error) {
+	latest, err := db.LatestVersion()
+	if err != nil {
+		return nil, err
+	}
+	state := &migrationState{Latest: latest}
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state.Applied, state.Dirty = &version, dirty
+	return state, nil
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		log.Println("No migrations to apply")
+		return nil
+	}
+	return err
+}
+
+// logger prints the progress of golang-migrate
+type logger struct{}
+
+func (logger) Printf(format string, v ...any) {
+	log.Printf(format, v...)
+}
+
+func (logger) Verbose() bool {
+	return false
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/seed"
+)
+
+// seedResult is printed once seed ran
+type seedResult struct {
+	Cleared bool         `json:"cleared"`
+	Seeded  *seed.Seeded `json:"seeded"` // Null with --clear-only
+}
+
+func seedCommand(args []string) error {
+	flags := newFlags("seed", "seed [flags]")
+	clearOnly := flags.Bool("clear-only", false, "Only clear the database without seeding")
+	seedOnly := flags.Bool("seed-only", false, "Only seed the database without clearing")
+	force := flags.Bool("force", false, "Skip confirmation prompt")
+	fixtureNames := flags.String("fixture", "ecommerce", "Comma separated datasets to seed: "+strings.Join(seed.AvailableDatasets(), ", ")+", or paths to YAML or JSON fixture files")
+	fakeUsers := flags.Int("users", 0, "Generate this many random users instead of seeding fixtures, all with password "+seed.FakePassword)
+	fakeProjects := flags.Int("projects", 0, "Generate this many random projects, owned by and shared between the generated users")
+	tablesPerProject := flags.Int("tables-per-project", 5, "Tables of each generated project")
+	fakeSeed := flags.Uint64("seed", 0, "Seed generating the same random data again; random when 0")
+	project := flags.String("project", "", "Only clear and seed the project of this name, keeping other projects and all users")
+	onlySchema := flags.Bool("only-schema", false, "Only clear and seed the tables, fields and relationships of existing projects")
+	confirmed := flags.Bool("i-know-what-im-doing", false, "Allow clearing the database when ENV=production")
+	flags.Parse(args)
+
+	scope := seed.Scope{Project: *project, OnlySchema: *onlySchema}
+
+	// Read or generate the data first, so a broken fixture fails before anything is cleared
+	var fixture *seed.Fixture
+	if !*clearOnly {
+		var err error
+		if *fakeUsers > 0 || *fakeProjects > 0 {
+			fixture, err = seed.FakeFixture(seed.Volume{Users: *fakeUsers, Projects: *fakeProjects, TablesPerProject: *tablesPerProject}, *fakeSeed)
+		} else {
+			fixture, err = seed.LoadFixtures(*fixtureNames)
+		}
+		if err == nil {
+			fixture, err = scope.Select(fixture)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load fixtures: %w", err)
+		}
+	}
+
+	cfg := config.Load()
+
+	// Refuse to wipe production before even connecting to it
+	if !*seedOnly {
+		if err := seed.GuardClear(cfg.Env, *confirmed); err != nil {
+			return err
+		}
+	}
+
+	database, closeDB, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	seeder := seed.NewSeeder(database)
+
+	// Get confirmation if not forced, on stderr to keep stdout for the result
+	if !*force && !*seedOnly {
+		fmt.Fprintf(os.Stderr, "This will clear %s from the database. Are you sure? (y/N): ", scope.Describe())
+		var response string
+		fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			return errors.New("operation cancelled")
+		}
+	}
+
+	var result seedResult
+	if !*seedOnly {
+		log.Println("Clearing database...")
+		if err := seeder.ClearData(scope); err != nil {
+			return fmt.Errorf("failed to clear database: %w", err)
+		}
+		log.Println("Database cleared successfully!")
+		result.Cleared = true
+	}
+	if !*clearOnly {
+		log.Println("Seeding database...")
+		if result.Seeded, err = seeder.SeedData(fixture, scope); err != nil {
+			return fmt.Errorf("failed to seed database: %w", err)
+		}
+		log.Println("Database seeded successfully!")
+	}
+
+	if result.Seeded == nil {
+		return printResult(result, "Cleared %s", scope.Describe())
+	}
+	return printResult(result, "Seeded %d projects with %d tables, %d fields and %d relationships; created %d users",
+		len(result.Seeded.Projects), result.Seeded.Tables, result.Seeded.Fields, result.Seeded.Relationships, result.Seeded.Users)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/server"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+)
+
+// errorReportFlushTimeout bounds how long exiting waits for queued error reports
+const errorReportFlushTimeout = 2 * time.Second
+
+// serve runs the API until a termination signal, then drains it
+func serve(args []string) error {
+	newFlags("serve", "serve [flags]").Parse(args)
+
+	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	// Report panics and unexpected errors when a DSN is configured
+	if err := errorreport.Init(cfg); err != nil {
+		log.Printf("Warning: error reporting disabled: %v", err)
+	}
+	defer errorreport.Flush(errorReportFlushTimeout)
+
+	database, closeDB, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	// Initialize and start server
+	srv := server.New(cfg, database)
+	log.Printf("Starting server on port %s...", cfg.Port)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- srv.Start()
+	}()
+
+	// Wait for a termination signal, then drain within the configured deadline
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down, draining connections for up to %s...", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Shutdown incomplete: %v", err)
+	}
+	log.Println("Server stopped")
+	return nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/seed"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// formatFixture is the export format read from the database rather than
+// through the API, with the project's owner and collaborators
+const formatFixture = "fixture"
+
+// exportResult is printed once a project is exported to a file
+type exportResult struct {
+	ProjectID uuid.UUID `json:"project_id"`
+	Format    string    `json:"format"`
+	Path      string    `json:"path"`
+	Bytes     int       `json:"bytes"`
+}
+
+// importResult is printed once a file is imported into a project through the API
+type importResult struct {
+	ProjectID     uuid.UUID `json:"project_id"`
+	DryRun        bool      `json:"dry_run"`
+	Tables        int       `json:"tables"`
+	Fields        int       `json:"fields"` // Merged into existing tables
+	Relationships int       `json:"relationships"`
+	Conflicts     int       `json:"conflicts"` // Tables named like existing ones, left unresolved
+}
+
+// exportCommand writes a project through the API, as DDL or as a schema
+// request, or from the database as a fixture file, which import and seed
+// --fixture read back
+func exportCommand(args []string) error {
+	flags := newFlags("export", "export [flags] --project <id>")
+	projectID := flags.String("project", "", "ID of the project to export")
+	format := flags.String("format", "", "sql, json, dbt, avro or protobuf through the API, default sql; fixture from the database")
+	out := flags.String("out", "", "File to write the export to; stdout when empty")
+	apiURL := flags.String("api", os.Getenv(apiURLEnv), "URL of the API to export through, up to /api; the database when empty (default $"+apiURLEnv+")")
+	token := flags.String("token", os.Getenv(apiTokenEnv), "API token reading the project (default $"+apiTokenEnv+")")
+	flags.Parse(args)
+
+	id, err := uuid.Parse(*projectID)
+	if err != nil {
+		return fmt.Errorf("--project needs a project ID: %w", err)
+	}
+
+	var data []byte
+	if *apiURL != "" {
+		if *format == "" {
+			*format = services.ExportFormatSQL
+		}
+		if *format == formatFixture {
+			return errors.New("fixtures are exported from the database, without --api")
+		}
+		client, err := newAPIClient(*apiURL, *token)
+		if err != nil {
+			return err
+		}
+		if data, err = client.download("/projects/" + id.String() + "/export?format=" + url.QueryEscape(*format)); err != nil {
+			return err
+		}
+	} else {
+		if *format == "" {
+			*format = formatFixture
+		}
+		if *format != formatFixture {
+			return fmt.Errorf("format %s is exported through the API: pass --api or set %s", *format, apiURLEnv)
+		}
+		if data, err = exportFixture(id); err != nil {
+			return err
+		}
+	}
+
+	if *out == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		return err
+	}
+	return printResult(exportResult{ProjectID: id, Format: *format, Path: *out, Bytes: len(data)}, "Exported project %s to %s", id, *out)
+}
+
+// exportFixture reads a project from the database as a fixture file
+func exportFixture(projectID uuid.UUID) ([]byte, error) {
+	database, closeDB, err := connect(config.Load())
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB()
+
+	fixture, err := seed.NewSeeder(database).ExportProject(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project %s: %w", projectID, err)
+	}
+	return yaml.Marshal(fixture)
+}
+
+// importCommand adds the schema of a file to a project through the API, or
+// the users and projects of fixture files to the database, reusing the users
+// that exist already. Nothing is cleared.
+func importCommand(args []string) error {
+	flags := newFlags("import", "import [flags] <file>...")
+	projectID := flags.String("project", "", "ID of the project to add the schema to, through the API")
+	dryRun := flags.Bool("dry-run", false, "Report what the API would create without creating it")
+	apiURL := flags.String("api", os.Getenv(apiURLEnv), "URL of the API to import through, up to /api; the database when empty (default $"+apiURLEnv+")")
+	token := flags.String("token", os.Getenv(apiTokenEnv), "API token changing the project's schema (default $"+apiTokenEnv+")")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("import needs files")
+	}
+
+	if *apiURL != "" {
+		if flags.NArg() > 1 {
+			return errors.New("the API imports one file at a time")
+		}
+		id, err := uuid.Parse(*projectID)
+		if err != nil {
+			return fmt.Errorf("--project needs a project ID: %w", err)
+		}
+		client, err := newAPIClient(*apiURL, *token)
+		if err != nil {
+			return err
+		}
+		return importThroughAPI(client, id, flags.Arg(0), *dryRun)
+	}
+	if *projectID != "" || *dryRun {
+		return fmt.Errorf("--project and --dry-run import through the API: pass --api or set %s", apiURLEnv)
+	}
+
+	fixture, err := seed.LoadFixtures(strings.Join(flags.Args(), ","))
+	if err != nil {
+		return fmt.Errorf("failed to load fixtures: %w", err)
+	}
+
+	database, closeDB, err := connect(config.Load())
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	seeded, err := seed.NewSeeder(database).SeedData(fixture, seed.Scope{})
+	if err != nil {
+		return fmt.Errorf("failed to import: %w", err)
+	}
+	return printResult(seeded, "Imported %d projects with %d tables, %d fields and %d relationships; created %d users",
+		len(seeded.Projects), seeded.Tables, seeded.Fields, seeded.Relationships, seeded.Users)
+}
+
+// importThroughAPI sends a schema request exported as JSON to createSchema,
+// or a spreadsheet to importSchema
+func importThroughAPI(client *apiClient, projectID uuid.UUID, filename string, dryRun bool) error {
+	path := "/projects/" + projectID.String() + "/schema"
+	query := ""
+	if dryRun {
+		query = "?dry_run=true"
+	}
+
+	var schema dto.SchemaResponse
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		if err := client.postJSON(path+query, data, &schema); err != nil {
+			return err
+		}
+	case ".csv", ".xlsx":
+		if err := client.upload(path+"/import"+query, "file", filename, &schema); err != nil {
+			return err
+		}
+	default:
+		return errors.New("the API imports .json schema requests, as exported with --format json, and .csv or .xlsx spreadsheets")
+	}
+
+	unresolved := 0
+	for _, conflict := range schema.Conflicts {
+		if conflict.Strategy == "" {
+			unresolved++
+		}
+	}
+	result := importResult{
+		ProjectID:     projectID,
+		DryRun:        schema.DryRun,
+		Tables:        len(schema.Tables),
+		Fields:        len(schema.Fields),
+		Relationships: len(schema.Relationships),
+		Conflicts:     unresolved,
+	}
+	verb := "Imported"
+	if result.DryRun {
+		verb = "Would import"
+	}
+	return printResult(result, "%s %d tables, %d fields and %d relationships into project %s; %d conflicts",
+		verb, result.Tables, result.Fields, result.Relationships, projectID, result.Conflicts)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"gorm.io/gorm"
+)
+
+// userCommand manages accounts without going through the API
+func userCommand(args []string) error {
+	if len(args) == 0 || args[0] != "create-admin" {
+		return errors.New("usage: ezmodel user create-admin [flags]")
+	}
+	return createAdmin(args[1:])
+}
+
+// createAdmin creates an account with the admin role, such as the first
+// admin of a deployment. The password is read from stdin, to keep it out of
+// the process list and shell history.
+func createAdmin(args []string) error {
+	flags := newFlags("user create-admin", "user create-admin [flags] --email <email> --username <name> < password")
+	email := flags.String("email", "", "Email of the account")
+	username := flags.String("username", "", "Username of the account")
+	flags.Parse(args)
+	if *email == "" || *username == "" {
+		flags.Usage()
+		return errors.New("--email and --username are required")
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		return fmt.Errorf("failed to read the password: %w", err)
+	}
+	password = strings.TrimRight(password, "\r\n")
+
+	database, closeDB, err := connect(config.Load())
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	var user *models.User
+	err = database.Transaction(func(tx *gorm.DB) error {
+		userRepo := repository.NewUserRepository(tx)
+		var err error
+		if user, err = services.NewUserService(userRepo).CreateUser(*email, *username, password); err != nil {
+			return err
+		}
+		user.Role = models.RoleAdmin
+		return userRepo.Update(user)
+	})
+	switch {
+	case errors.Is(err, services.ErrInvalidInput):
+		return errors.New("the email needs at least 5 characters, the username 3 and the password 6")
+	case errors.Is(err, services.ErrUserAlreadyExists):
+		return fmt.Errorf("an account with email %s exists already", *email)
+	case err != nil:
+		return fmt.Errorf("failed to create the account: %w", err)
+	}
+
+	return printResult(user, "Created admin %s (%s)", user.Username, user.ID)
+}
@@ -0,0 +1,42 @@
+// Command sdkgen writes the TypeScript client generated from the OpenAPI
+// document, so the frontend's API types follow the DTOs.
+//
+//	go run ./cmd/sdkgen -o ../frontend/src/lib/api/client.gen.ts
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/openapi"
+	"github.com/Bug-Bugger/ezmodel/internal/api/routes"
+)
+
+func main() {
+	output := flag.String("o", "../frontend/src/lib/api/client.gen.ts", "File to write the client to")
+	check := flag.Bool("check", false, "Fail if the file is not up to date instead of writing it")
+	flag.Parse()
+
+	source, err := openapi.TypeScript(routes.APIDocument())
+	if err != nil {
+		log.Fatalf("Failed to generate TypeScript client: %v", err)
+	}
+
+	if *check {
+		current, err := os.ReadFile(*output)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", *output, err)
+		}
+		if !bytes.Equal(current, source) {
+			log.Fatalf("%s is out of date; run go run ./cmd/sdkgen", *output)
+		}
+		return
+	}
+
+	if err := os.WriteFile(*output, source, 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *output, err)
+	}
+	log.Printf("Wrote %s", *output)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/server"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+)
+
+// errorReportFlushTimeout bounds how long exiting waits for queued error reports
+const errorReportFlushTimeout = 2 * time.Second
+
+// The worker runs the background jobs queued in Redis by the API, such as
+// building data exports. Run any number of them next to API processes started
+// with JOBS_IN_PROCESS=false.
+func main() {
+	cfg := config.Load()
+
+	if err := errorreport.Init(cfg); err != nil {
+		log.Printf("Warning: error reporting disabled: %v", err)
+	}
+	defer errorreport.Flush(errorReportFlushTimeout)
+
+	database, err := db.Connect(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	sqlDB, err := database.DB()
+	if err != nil {
+		log.Fatalf("Failed to get database: %v", err)
+	}
+	defer sqlDB.Close()
+
+	worker, err := server.NewWorker(cfg, database)
+	if err != nil {
+		log.Fatalf("Failed to start worker: %v", err)
+	}
+	worker.Start()
+	log.Printf("Worker running %d jobs at once", cfg.Jobs.Concurrency)
+
+	// Wait for a termination signal, then let running jobs finish within the configured deadline
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Printf("Shutting down, waiting for running jobs for up to %s...", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := worker.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Shutdown incomplete: %v", err)
+	}
+	log.Println("Worker stopped")
+}
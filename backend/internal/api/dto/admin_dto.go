@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Request DTOs
+type SetUserDisabledRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+type SetUserRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=user admin"`
+}
+
+type ImpersonateUserRequest struct {
+	Reason string `json:"reason" validate:"required,min=1,max=500"` // Recorded in the audit log, e.g. a support ticket
+}
+
+// Response DTOs
+type AdminUserResponse struct {
+	ID                    uuid.UUID  `json:"id"`
+	Email                 string     `json:"email"`
+	Username              string     `json:"username"`
+	Role                  string     `json:"role"`
+	IsServiceAccount      bool       `json:"is_service_account"`
+	DisabledAt            *time.Time `json:"disabled_at"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+type AdminAuditLogResponse struct {
+	ID           uuid.UUID `json:"id"`
+	ActorID      uuid.UUID `json:"actor_id"`
+	Action       string    `json:"action"`
+	TargetUserID uuid.UUID `json:"target_user_id"`
+	Details      string    `json:"details,omitempty"`
+	IPAddress    string    `json:"ip_address"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// InstanceStatsResponse holds aggregates over the whole instance for an ops dashboard
+type InstanceStatsResponse struct {
+	Users             int64                  `json:"users"`
+	Projects          int64                  `json:"projects"`
+	ActiveConnections int                    `json:"active_connections"` // WebSocket clients of the node answering
+	DatabaseBytes     int64                  `json:"database_bytes"`
+	Tenants           []TenantUsageResponse  `json:"tenants"` // Owners whose projects take up the most space, largest first
+	Signups           []DailySignupsResponse `json:"signups"` // One per day, oldest first
+	GeneratedAt       time.Time              `json:"generated_at"`
+}
+
+// TenantUsageResponse is what the projects of one owner take up; bytes counts row data only
+type TenantUsageResponse struct {
+	OwnerID  uuid.UUID `json:"owner_id"`
+	Username string    `json:"username"`
+	Projects int64     `json:"projects"`
+	Tables   int64     `json:"tables"`
+	Fields   int64     `json:"fields"`
+	Bytes    int64     `json:"bytes"`
+}
+
+type DailySignupsResponse struct {
+	Date  string `json:"date"` // YYYY-MM-DD in UTC
+	Count int64  `json:"count"`
+}
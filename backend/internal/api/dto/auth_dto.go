@@ -1,5 +1,11 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 // Request DTOs
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -17,3 +23,13 @@ type TokenResponse struct {
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
+
+type LoginEventResponse struct {
+	ID            uuid.UUID `json:"id"`
+	IPAddress     string    `json:"ip_address"`
+	UserAgent     string    `json:"user_agent"`
+	Success       bool      `json:"success"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	Suspicious    bool      `json:"suspicious"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// PlanResponse is a plan on offer with its quotas; 0 means unlimited
+type PlanResponse struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	MaxProjectsPerUser  int    `json:"max_projects_per_user"`
+	MaxTablesPerProject int    `json:"max_tables_per_project"`
+	MaxCollaborators    int    `json:"max_collaborators"`
+}
+
+type SubscriptionResponse struct {
+	Plan             PlanResponse `json:"plan"`
+	Status           string       `json:"status"` // Of the Stripe subscription, empty on the free plan
+	CurrentPeriodEnd *time.Time   `json:"current_period_end"`
+}
+
+type CreateCheckoutRequest struct {
+	Plan string `json:"plan" validate:"required"`
+}
+
+type CheckoutResponse struct {
+	URL string `json:"url"` // Stripe Checkout page to send the browser to
+}
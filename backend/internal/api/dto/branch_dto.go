@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateBranchRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+}
+
+// UpdateBranchRequest renames a branch or replaces its schema, layout included
+type UpdateBranchRequest struct {
+	Name   *string              `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Schema *CreateSchemaRequest `json:"schema,omitempty"` // Conflict resolutions are ignored
+
+	// Version the change was made against; If-Match sets it too
+	Version *int64 `json:"version,omitempty"`
+}
+
+type BranchResponse struct {
+	ID        uuid.UUID            `json:"branch_id"`
+	ProjectID uuid.UUID            `json:"project_id"`
+	Name      string               `json:"name"`
+	Version   int64                `json:"version"`
+	CreatedBy uuid.UUID            `json:"created_by"`
+	MergedBy  *uuid.UUID           `json:"merged_by"` // Null until the branch is merged
+	MergedAt  *time.Time           `json:"merged_at"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+	Schema    *CreateSchemaRequest `json:"schema,omitempty"` // Omitted from lists of branches
+}
+
+// BranchMergeResponse is a merged branch with what its merge changed in the project
+type BranchMergeResponse struct {
+	Branch  BranchResponse     `json:"branch"`
+	Changes SchemaDiffResponse `json:"changes"`
+}
+
+// MergeConflictResponse is a part of the schema the project and a branch both
+// changed differently since the branch was made
+type MergeConflictResponse struct {
+	Table        string                    `json:"table"`
+	Field        string                    `json:"field,omitempty"`
+	Relationship *DiffRelationshipResponse `json:"relationship,omitempty"`
+	Reason       string                    `json:"reason"` // modified_on_both_sides, removed_and_modified, added_on_both_sides or links_removed_field
+}
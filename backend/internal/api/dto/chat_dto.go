@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SendChatMessageRequest struct {
+	Content string `json:"content" validate:"required,max=4000"`
+}
+
+type ChatMessageResponse struct {
+	ID        uuid.UUID `json:"message_id"`
+	ProjectID uuid.UUID `json:"project_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -29,6 +29,7 @@ type CollaborationSessionResponse struct {
 	CursorX    *float64   `json:"cursor_x"`
 	CursorY    *float64   `json:"cursor_y"`
 	UserColor  string     `json:"user_color"`
+	Status     string     `json:"status"` // active, idle or away
 	IsActive   bool       `json:"is_active"`
 	LastPingAt time.Time  `json:"last_ping_at"`
 	JoinedAt   time.Time  `json:"joined_at"`
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UpdateDocRequest struct {
+	Content string `json:"content" validate:"max=200000"` // Markdown; empty clears the doc
+
+	// Version the edit was made against; If-Match sets it too
+	Version *int64 `json:"version,omitempty"`
+}
+
+type DocResponse struct {
+	ProjectID uuid.UUID  `json:"project_id"`
+	Version   int64      `json:"version"` // 0 until the doc is first edited
+	Content   string     `json:"content"`
+	EditedBy  *uuid.UUID `json:"edited_by"` // Null until the doc is first edited
+	EditedAt  *time.Time `json:"edited_at"`
+}
+
+// DocRevisionResponse is an edit of a project's doc, without its content
+type DocRevisionResponse struct {
+	Version  int64     `json:"version"`
+	EditedBy uuid.UUID `json:"edited_by"`
+	EditedAt time.Time `json:"edited_at"`
+}
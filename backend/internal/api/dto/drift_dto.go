@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SetConnectionProfileRequest sets how the project's live database is reached
+// to check it for drift. Only postgres:// URLs are accepted, so the password
+// can be hidden when the profile is shown, and only to public hosts.
+type SetConnectionProfileRequest struct {
+	DSN             string  `json:"dsn,omitempty" validate:"omitempty,max=2048"`             // Required unless the project has a profile already, whose connection string is kept
+	SchemaName      string  `json:"schema_name,omitempty" validate:"omitempty,max=63"`       // public when empty
+	ScheduledChecks *bool   `json:"scheduled_checks,omitempty"`                              // Checked daily too; true when omitted
+	WebhookURL      string  `json:"webhook_url,omitempty" validate:"omitempty,url,max=2048"` // Notified when drift is found; https to a public address only
+	WebhookSecret   *string `json:"webhook_secret,omitempty" validate:"omitempty,max=255"`   // Signs deliveries; kept when omitted, removed when empty
+}
+
+type ConnectionProfileResponse struct {
+	ProjectID       uuid.UUID `json:"project_id"`
+	DSN             string    `json:"dsn"` // Password hidden
+	SchemaName      string    `json:"schema_name"`
+	ScheduledChecks bool      `json:"scheduled_checks"`
+	WebhookURL      string    `json:"webhook_url"`
+	WebhookSigned   bool      `json:"webhook_signed"` // Deliveries carry an X-EzModel-Signature header
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type DriftCheckResponse struct {
+	ID          uuid.UUID           `json:"id"`
+	Status      string              `json:"status"`  // pending, clean, drifted or failed
+	Trigger     string              `json:"trigger"` // manual or scheduled
+	Error       string              `json:"error,omitempty"`
+	Diff        *SchemaDiffResponse `json:"diff,omitempty"` // From the model to the database; only when a single check is retrieved
+	CreatedAt   time.Time           `json:"created_at"`
+	CompletedAt *time.Time          `json:"completed_at"`
+}
+
+type DataProfileResponse struct {
+	Status     string                 `json:"status"` // pending, ready or failed; empty when never profiled
+	Error      string                 `json:"error,omitempty"`
+	ProfiledAt *time.Time             `json:"profiled_at"`
+	Tables     []TableProfileResponse `json:"tables"`
+}
+
+// TableProfileResponse are the statistics of the live counterpart of a table
+// of the model, as estimated by the database when last analyzed
+type TableProfileResponse struct {
+	TableID  uuid.UUID              `json:"table_id"`
+	RowCount *int64                 `json:"row_count"` // Null when the database never analyzed the table
+	Fields   []FieldProfileResponse `json:"fields"`
+}
+
+type FieldProfileResponse struct {
+	FieldID       uuid.UUID `json:"field_id"`
+	NullRatio     float64   `json:"null_ratio"` // Share of rows that are null, 0 to 1
+	DistinctCount int64     `json:"distinct_count"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProjectEventResponse struct {
+	Sequence  int64          `json:"sequence"`
+	Type      string         `json:"type"`
+	UserID    uuid.UUID      `json:"user_id"` // Who made the change
+	Data      map[string]any `json:"data"`    // Payload of the WebSocket message
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type ProjectEventsResponse struct {
+	Events         []ProjectEventResponse `json:"events"`
+	LatestSequence int64                  `json:"latest_sequence"`
+	Truncated      bool                   `json:"truncated"` // Events after since_seq are no longer kept; reload the project instead
+}
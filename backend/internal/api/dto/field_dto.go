@@ -7,12 +7,18 @@ import (
 )
 
 type CreateFieldRequest struct {
-	Name         string `json:"name" validate:"required,min=1,max=255"`
-	DataType     string `json:"data_type" validate:"required"`
-	IsPrimaryKey bool   `json:"is_primary_key"`
-	IsNullable   bool   `json:"is_nullable"`
-	DefaultValue string `json:"default_value"`
-	Position     int    `json:"position"`
+	Name         string         `json:"name" validate:"required,min=1,max=255"`
+	DataType     string         `json:"data_type" validate:"required"`
+	IsPrimaryKey bool           `json:"is_primary_key"`
+	IsNullable   bool           `json:"is_nullable"`
+	DefaultValue string         `json:"default_value"`
+	Position     int            `json:"position"`
+	Metadata     map[string]any `json:"metadata,omitempty" validate:"omitempty,max=50"` // Keys the organization defines, e.g. data_owner
+}
+
+// BulkCreateFieldsRequest adds fields to a table in order, all or none of them
+type BulkCreateFieldsRequest struct {
+	Fields []CreateFieldRequest `json:"fields" validate:"required,min=1,max=200,dive"`
 }
 
 type UpdateFieldRequest struct {
@@ -20,23 +26,33 @@ type UpdateFieldRequest struct {
 	DataType     *string `json:"data_type,omitempty"`
 	IsPrimaryKey *bool   `json:"is_primary_key,omitempty"`
 	IsNullable   *bool   `json:"is_nullable,omitempty"`
-	DefaultValue *string `json:"default_value,omitempty"`
+	DefaultValue *string `json:"default_value,omitempty" patch:"nullable"`
 	Position     *int    `json:"position,omitempty"`
+	// Metadata is merged into the object's as a JSON Merge Patch: members set
+	// to null are removed, and null clears the metadata
+	Metadata *map[string]any `json:"metadata,omitempty" validate:"omitempty,max=50" patch:"nullable"`
+	// Version the change was made against; If-Match sets it too
+	Version *int64 `json:"version,omitempty"`
 }
 
 type ReorderFieldsRequest struct {
 	FieldPositions map[uuid.UUID]int `json:"field_positions" validate:"required"`
+	// Last project sequence the client applied. When another reorder of the
+	// table was confirmed after it, the request fails with the order it left.
+	BaseSequence *int64 `json:"base_sequence,omitempty"`
 }
 
 type FieldResponse struct {
-	ID           uuid.UUID `json:"field_id"`
-	TableID      uuid.UUID `json:"table_id"`
-	Name         string    `json:"name"`
-	DataType     string    `json:"data_type"`
-	IsPrimaryKey bool      `json:"is_primary_key"`
-	IsNullable   bool      `json:"is_nullable"`
-	DefaultValue string    `json:"default_value"`
-	Position     int       `json:"position"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uuid.UUID      `json:"field_id"`
+	TableID      uuid.UUID      `json:"table_id"`
+	Name         string         `json:"name"`
+	DataType     string         `json:"data_type"`
+	IsPrimaryKey bool           `json:"is_primary_key"`
+	IsNullable   bool           `json:"is_nullable"`
+	DefaultValue string         `json:"default_value"`
+	Position     int            `json:"position"`
+	Metadata     map[string]any `json:"metadata"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	Version      int64          `json:"version"`
 }
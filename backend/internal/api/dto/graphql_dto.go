@@ -0,0 +1,9 @@
+package dto
+
+// GraphQLRequest is a GraphQL operation, as posted to /graphql or sent in a
+// subscribe message on its WebSocket
+type GraphQLRequest struct {
+	Query         string         `json:"query" validate:"required"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+}
@@ -13,14 +13,23 @@ type CreateProjectRequest struct {
 
 type UpdateProjectRequest struct {
 	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
-	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
-	CanvasData  *string `json:"canvas_data,omitempty"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000" patch:"nullable"`
+	CanvasData  *string `json:"canvas_data,omitempty" patch:"nullable"`
+	// Metadata is merged into the object's as a JSON Merge Patch: members set
+	// to null are removed, and null clears the metadata
+	Metadata *map[string]any `json:"metadata,omitempty" validate:"omitempty,max=50" patch:"nullable"`
+	// Version the change was made against; If-Match sets it too
+	Version *int64 `json:"version,omitempty"`
 }
 
 type AddCollaboratorRequest struct {
 	CollaboratorID uuid.UUID `json:"collaborator_id" validate:"required"`
 }
 
+type SetCollaboratorRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=editor viewer"`
+}
+
 type ProjectResponse struct {
 	ID            uuid.UUID                 `json:"id"`
 	Name          string                    `json:"name"`
@@ -28,12 +37,30 @@ type ProjectResponse struct {
 	OwnerID       uuid.UUID                 `json:"owner_id"`
 	DatabaseType  string                    `json:"database_type"`
 	CanvasData    string                    `json:"canvas_data"`
+	Metadata      map[string]any            `json:"metadata"`
 	Owner         UserResponse              `json:"owner"`
 	Collaborators []UserResponse            `json:"collaborators,omitempty"`
 	Tables        []TableWithFieldsResponse `json:"tables,omitempty"`
 	Relationships []RelationshipResponse    `json:"relationships,omitempty"`
+	Tags          []string                  `json:"tags"`
 	CreatedAt     time.Time                 `json:"created_at"`
 	UpdatedAt     time.Time                 `json:"updated_at"`
+	Version       int64                     `json:"version"`
+	Sequence      int64                     `json:"sequence"` // Of the last change included; later WebSocket events have higher ones
+}
+
+// FullProjectResponse is everything the canvas needs to open a project
+type FullProjectResponse struct {
+	ProjectResponse
+	ActiveCollaborators []ActiveCollaboratorResponse `json:"active_collaborators"`
+}
+
+// ActiveCollaboratorResponse is a user connected to the project's collaboration WebSocket
+type ActiveCollaboratorResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	UserColor string    `json:"user_color"`
+	AvatarURL string    `json:"avatar_url,omitempty"`
 }
 
 type ProjectSummaryResponse struct {
@@ -41,6 +68,23 @@ type ProjectSummaryResponse struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	OwnerID     uuid.UUID `json:"owner_id"`
+	Tags        []string  `json:"tags"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	Version     int64     `json:"version"`
+}
+
+// MyProjectResponse is a project of the current user, with whether they starred it
+// and what the listing was asked to include
+type MyProjectResponse struct {
+	ProjectSummaryResponse
+	Starred       bool           `json:"starred"`
+	Collaborators []UserResponse `json:"collaborators,omitempty"` // With include=collaborators
+	TablesCount   *int64         `json:"tables_count,omitempty"`  // With include=tables_count
+}
+
+// RecentProjectResponse is a project the current user opened, with when they last did
+type RecentProjectResponse struct {
+	ProjectSummaryResponse
+	ViewedAt time.Time `json:"viewed_at"`
 }
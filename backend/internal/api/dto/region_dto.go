@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateRegionRequest struct {
+	Name     string      `json:"name" validate:"required,min=1,max=255"`
+	Color    string      `json:"color" validate:"required,hexcolor"`
+	PosX     float64     `json:"pos_x" validate:"gte=-10000000,lte=10000000"`
+	PosY     float64     `json:"pos_y" validate:"gte=-10000000,lte=10000000"`
+	Width    float64     `json:"width" validate:"gt=0,lte=10000000"`
+	Height   float64     `json:"height" validate:"gt=0,lte=10000000"`
+	TableIDs []uuid.UUID `json:"table_ids,omitempty" validate:"omitempty,max=1000"`
+}
+
+type UpdateRegionRequest struct {
+	Name   *string  `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Color  *string  `json:"color,omitempty" validate:"omitempty,hexcolor"`
+	PosX   *float64 `json:"pos_x,omitempty" validate:"omitempty,gte=-10000000,lte=10000000"`
+	PosY   *float64 `json:"pos_y,omitempty" validate:"omitempty,gte=-10000000,lte=10000000"`
+	Width  *float64 `json:"width,omitempty" validate:"omitempty,gt=0,lte=10000000"`
+	Height *float64 `json:"height,omitempty" validate:"omitempty,gt=0,lte=10000000"`
+	// TableIDs replaces the tables in the region when set
+	TableIDs *[]uuid.UUID `json:"table_ids,omitempty" validate:"omitempty,max=1000"`
+	// Version the change was made against; If-Match sets it too
+	Version *int64 `json:"version,omitempty"`
+}
+
+// MoveRegionRequest moves a region and every table in it by the same offset
+type MoveRegionRequest struct {
+	DX float64 `json:"dx" validate:"gte=-10000000,lte=10000000"`
+	DY float64 `json:"dy" validate:"gte=-10000000,lte=10000000"`
+	// Version the move was made against; If-Match sets it too
+	Version *int64 `json:"version,omitempty"`
+}
+
+type RegionResponse struct {
+	ID        uuid.UUID   `json:"region_id"`
+	ProjectID uuid.UUID   `json:"project_id"`
+	Name      string      `json:"name"`
+	Color     string      `json:"color"`
+	PosX      float64     `json:"pos_x"`
+	PosY      float64     `json:"pos_y"`
+	Width     float64     `json:"width"`
+	Height    float64     `json:"height"`
+	TableIDs  []uuid.UUID `json:"table_ids"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	Version   int64       `json:"version"`
+}
+
+// TablePositionResponse is where a table moved with its region now sits
+type TablePositionResponse struct {
+	ID      uuid.UUID `json:"table_id"`
+	PosX    float64   `json:"pos_x"`
+	PosY    float64   `json:"pos_y"`
+	Version int64     `json:"version"`
+}
+
+type MoveRegionResponse struct {
+	Region RegionResponse          `json:"region"`
+	Tables []TablePositionResponse `json:"tables"`
+}
@@ -12,6 +12,14 @@ type CreateRelationshipRequest struct {
 	TargetTableID uuid.UUID `json:"target_table_id" validate:"required"`
 	TargetFieldID uuid.UUID `json:"target_field_id" validate:"required"`
 	RelationType  string    `json:"relation_type" validate:"oneof=one_to_one one_to_many many_to_many"`
+
+	// How the edge is drawn; auto anchors and a centered label when left out
+	SourceAnchor  string   `json:"source_anchor,omitempty" validate:"omitempty,oneof=auto top right bottom left"`
+	TargetAnchor  string   `json:"target_anchor,omitempty" validate:"omitempty,oneof=auto top right bottom left"`
+	Waypoints     []Point  `json:"waypoints,omitempty" validate:"omitempty,max=100,dive"`
+	LabelPosition *float64 `json:"label_position,omitempty" validate:"omitempty,gte=0,lte=1"`
+
+	Metadata map[string]any `json:"metadata,omitempty" validate:"omitempty,max=50"` // Keys the organization defines, e.g. data_owner
 }
 
 type UpdateRelationshipRequest struct {
@@ -20,16 +28,37 @@ type UpdateRelationshipRequest struct {
 	TargetTableID *uuid.UUID `json:"target_table_id,omitempty"`
 	TargetFieldID *uuid.UUID `json:"target_field_id,omitempty"`
 	RelationType  *string    `json:"relation_type,omitempty" validate:"omitempty,oneof=one_to_one one_to_many many_to_many"`
+	SourceAnchor  *string    `json:"source_anchor,omitempty" validate:"omitempty,oneof=auto top right bottom left"`
+	TargetAnchor  *string    `json:"target_anchor,omitempty" validate:"omitempty,oneof=auto top right bottom left"`
+	Waypoints     *[]Point   `json:"waypoints,omitempty" validate:"omitempty,max=100,dive" patch:"nullable"`
+	LabelPosition *float64   `json:"label_position,omitempty" validate:"omitempty,gte=0,lte=1"`
+	// Metadata is merged into the object's as a JSON Merge Patch: members set
+	// to null are removed, and null clears the metadata
+	Metadata *map[string]any `json:"metadata,omitempty" validate:"omitempty,max=50" patch:"nullable"`
+	// Version the change was made against; If-Match sets it too
+	Version *int64 `json:"version,omitempty"`
 }
 
 type RelationshipResponse struct {
-	ID            uuid.UUID `json:"relationship_id"`
-	ProjectID     uuid.UUID `json:"project_id"`
-	SourceTableID uuid.UUID `json:"source_table_id"`
-	SourceFieldID uuid.UUID `json:"source_field_id"`
-	TargetTableID uuid.UUID `json:"target_table_id"`
-	TargetFieldID uuid.UUID `json:"target_field_id"`
-	RelationType  string    `json:"relation_type"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            uuid.UUID      `json:"relationship_id"`
+	ProjectID     uuid.UUID      `json:"project_id"`
+	SourceTableID uuid.UUID      `json:"source_table_id"`
+	SourceFieldID uuid.UUID      `json:"source_field_id"`
+	TargetTableID uuid.UUID      `json:"target_table_id"`
+	TargetFieldID uuid.UUID      `json:"target_field_id"`
+	RelationType  string         `json:"relation_type"`
+	SourceAnchor  string         `json:"source_anchor"`
+	TargetAnchor  string         `json:"target_anchor"`
+	Waypoints     []Point        `json:"waypoints"`
+	LabelPosition float64        `json:"label_position"`
+	Metadata      map[string]any `json:"metadata"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	Version       int64          `json:"version"`
+}
+
+// Point is a position on the canvas
+type Point struct {
+	X float64 `json:"x" validate:"gte=-10000000,lte=10000000"`
+	Y float64 `json:"y" validate:"gte=-10000000,lte=10000000"`
 }
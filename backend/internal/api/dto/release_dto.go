@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateReleaseRequest names a snapshot of the project, the one of snapshot_id
+// or one taken of the project as it is now
+type CreateReleaseRequest struct {
+	Name       string     `json:"name" validate:"required,min=1,max=100"`
+	Notes      string     `json:"notes,omitempty" validate:"max=10000"`
+	SnapshotID *uuid.UUID `json:"snapshot_id,omitempty"`
+	Locked     bool       `json:"locked,omitempty"`
+}
+
+// UpdateReleaseRequest changes a release; a locked one can only be unlocked
+type UpdateReleaseRequest struct {
+	Name       *string    `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Notes      *string    `json:"notes,omitempty" validate:"omitempty,max=10000"`
+	SnapshotID *uuid.UUID `json:"snapshot_id,omitempty"`
+	Locked     *bool      `json:"locked,omitempty"`
+}
+
+type ReleaseResponse struct {
+	ID         uuid.UUID `json:"release_id"`
+	ProjectID  uuid.UUID `json:"project_id"`
+	Name       string    `json:"name"`
+	Notes      string    `json:"notes"`
+	SnapshotID uuid.UUID `json:"snapshot_id"`
+	Locked     bool      `json:"locked"`
+	CreatedBy  uuid.UUID `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewCommentResponse is a remark of a schema review. TableID and FieldID
+// are null once what the comment is about is deleted; FieldID is null too
+// for comments about a whole table.
+type ReviewCommentResponse struct {
+	ID         uuid.UUID  `json:"comment_id"`
+	ProjectID  uuid.UUID  `json:"project_id"`
+	TableID    *uuid.UUID `json:"table_id"`
+	FieldID    *uuid.UUID `json:"field_id"`
+	TableName  string     `json:"table_name"`
+	FieldName  string     `json:"field_name"`
+	Kind       string     `json:"kind"` // normalization, missing_index or naming
+	Message    string     `json:"message"`
+	Suggestion string     `json:"suggestion"`
+	Provider   string     `json:"provider"`
+	CreatedBy  uuid.UUID  `json:"created_by"`
+	ResolvedBy *uuid.UUID `json:"resolved_by"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
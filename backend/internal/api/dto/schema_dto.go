@@ -0,0 +1,253 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateSchemaRequest adds tables, their fields and the relationships between
+// them to a project at once, e.g. from a pasted or imported schema. Either all
+// of it is created or nothing is.
+type CreateSchemaRequest struct {
+	Tables        []SchemaTableRequest        `json:"tables" validate:"required,min=1,max=100,dive"`
+	Relationships []SchemaRelationshipRequest `json:"relationships,omitempty" validate:"omitempty,max=500,dive"`
+	Conflicts     []SchemaConflictResolution  `json:"conflicts,omitempty" validate:"omitempty,max=100,dive"`
+}
+
+// ImportSchemaForm holds the form fields sent along an imported file
+type ImportSchemaForm struct {
+	Conflicts []SchemaConflictResolution `json:"conflicts,omitempty" validate:"omitempty,max=100,dive"`
+}
+
+// SchemaConflictResolution says what to do with a table of the request named
+// like one the project has already: skip it, create it under another name,
+// overwrite the existing table or merge its fields into the existing table
+type SchemaConflictResolution struct {
+	Table    string `json:"table" validate:"required"`
+	Strategy string `json:"strategy" validate:"required,oneof=skip rename overwrite merge"`
+	NewName  string `json:"new_name,omitempty" validate:"omitempty,max=255"` // For rename; table_2, table_3, ... when empty
+}
+
+type SchemaTableRequest struct {
+	Name     string               `json:"name" validate:"required,min=1,max=255"`
+	PosX     float64              `json:"pos_x"`
+	PosY     float64              `json:"pos_y"`
+	Color    string               `json:"color,omitempty" validate:"omitempty,hexcolor"`
+	Icon     string               `json:"icon,omitempty" validate:"omitempty,oneof=database users user credit-card shopping-cart package file-text settings lock globe calendar mail tag activity"`
+	Fields   []CreateFieldRequest `json:"fields,omitempty" validate:"omitempty,max=200,dive"`
+	Metadata map[string]any       `json:"metadata,omitempty" validate:"omitempty,max=50"` // Keys the organization defines, e.g. data_owner
+}
+
+// SchemaRelationshipRequest names the fields it links, as their IDs do not
+// exist yet. Names refer to the tables of the request first, then to the
+// tables already in the project.
+type SchemaRelationshipRequest struct {
+	SourceTable  string         `json:"source_table" validate:"required"`
+	SourceField  string         `json:"source_field" validate:"required"`
+	TargetTable  string         `json:"target_table" validate:"required"`
+	TargetField  string         `json:"target_field" validate:"required"`
+	RelationType string         `json:"relation_type,omitempty" validate:"omitempty,oneof=one_to_one one_to_many many_to_many"`
+	Metadata     map[string]any `json:"metadata,omitempty" validate:"omitempty,max=50"` // Keys the organization defines, e.g. data_owner
+}
+
+// SchemaResponse is the created schema, or what would be created for a dry run
+type SchemaResponse struct {
+	Tables        []TableWithFieldsResponse `json:"tables"`
+	Fields        []FieldResponse           `json:"fields"` // Merged into existing tables
+	Relationships []RelationshipResponse    `json:"relationships"`
+	Conflicts     []SchemaConflictResponse  `json:"conflicts"`
+	DryRun        bool                      `json:"dry_run"`
+}
+
+// SchemaConflictResponse is a table named like one the project has already,
+// with how it is resolved, if it is
+type SchemaConflictResponse struct {
+	Table           string    `json:"table"`
+	ExistingTableID uuid.UUID `json:"existing_table_id"`
+	Strategy        string    `json:"strategy,omitempty"`
+	NewName         string    `json:"new_name,omitempty"`
+}
+
+// InferSchemaRequest describes MongoDB collections to propose tables for
+type InferSchemaRequest struct {
+	Collections []InferCollectionRequest `json:"collections" validate:"required,min=1,max=50,dive"`
+}
+
+// InferCollectionRequest describes a collection by sample documents, in
+// MongoDB extended JSON, or by its Mongoose schema definition as JSON
+type InferCollectionRequest struct {
+	Name           string            `json:"name" validate:"required,min=1,max=255"`
+	Documents      []json.RawMessage `json:"documents,omitempty" validate:"required_without=MongooseSchema,excluded_with=MongooseSchema,max=1000"`
+	MongooseSchema json.RawMessage   `json:"mongoose_schema,omitempty"`
+}
+
+// InferSchemaResponse proposes a schema without creating it. The schema can
+// be edited and sent to create it.
+type InferSchemaResponse struct {
+	Schema CreateSchemaRequest `json:"schema"`
+	Notes  []string            `json:"notes"` // What could not be inferred with confidence
+}
+
+// SchemaDiffResponse is what changes from the schema of a project to the
+// schema of another, matching tables, fields and relationships by name
+type SchemaDiffResponse struct {
+	Identical            bool                       `json:"identical"`
+	AddedTables          []DiffTableResponse        `json:"added_tables"`
+	RemovedTables        []DiffTableResponse        `json:"removed_tables"`
+	ChangedTables        []TableDiffResponse        `json:"changed_tables"`
+	AddedRelationships   []DiffRelationshipResponse `json:"added_relationships"`
+	RemovedRelationships []DiffRelationshipResponse `json:"removed_relationships"`
+	ChangedRelationships []RelationshipDiffResponse `json:"changed_relationships"`
+}
+
+type DiffTableResponse struct {
+	Name   string              `json:"name"`
+	Fields []DiffFieldResponse `json:"fields"`
+}
+
+type DiffFieldResponse struct {
+	Name         string `json:"name"`
+	DataType     string `json:"data_type"`
+	IsPrimaryKey bool   `json:"is_primary_key"`
+	IsNullable   bool   `json:"is_nullable"`
+	DefaultValue string `json:"default_value"`
+}
+
+// TableDiffResponse is a table of both projects whose fields differ
+type TableDiffResponse struct {
+	Name          string              `json:"name"`
+	AddedFields   []DiffFieldResponse `json:"added_fields"`
+	RemovedFields []DiffFieldResponse `json:"removed_fields"`
+	ChangedFields []FieldDiffResponse `json:"changed_fields"`
+}
+
+type FieldDiffResponse struct {
+	Name    string               `json:"name"`
+	Changes []DiffChangeResponse `json:"changes"`
+}
+
+// DiffChangeResponse is an attribute whose value differs, as text
+type DiffChangeResponse struct {
+	Attribute string `json:"attribute"` // data_type, is_primary_key, is_nullable, default_value or relation_type
+	From      string `json:"from"`
+	To        string `json:"to"`
+}
+
+type DiffRelationshipResponse struct {
+	SourceTable  string `json:"source_table"`
+	SourceField  string `json:"source_field"`
+	TargetTable  string `json:"target_table"`
+	TargetField  string `json:"target_field"`
+	RelationType string `json:"relation_type"`
+}
+
+// RelationshipDiffResponse is a relationship of both projects that differs,
+// as it is in the first one
+type RelationshipDiffResponse struct {
+	Relationship DiffRelationshipResponse `json:"relationship"`
+	Changes      []DiffChangeResponse     `json:"changes"`
+}
+
+// GenerateDataRequest asks for fake rows for every table of a project
+type GenerateDataRequest struct {
+	Rows   int     `json:"rows,omitempty" validate:"omitempty,min=1,max=1000"`  // Per table, 10 when omitted
+	Format string  `json:"format,omitempty" validate:"omitempty,oneof=sql csv"` // sql when omitted
+	Seed   *uint64 `json:"seed,omitempty"`                                      // Repeats the rows of an earlier request; random when omitted
+}
+
+// PlanApplyRequest names the PostgreSQL database to bring to the project's
+// model. The connection string is used for this request only, never stored.
+type PlanApplyRequest struct {
+	DSN        string `json:"dsn" validate:"required,max=2048"`
+	SchemaName string `json:"schema_name,omitempty" validate:"omitempty,max=63"` // public when empty
+}
+
+// ExecuteApplyRequest brings a PostgreSQL database to the project's model,
+// planning again and running the plan
+type ExecuteApplyRequest struct {
+	DSN              string `json:"dsn" validate:"required,max=2048"`
+	SchemaName       string `json:"schema_name,omitempty" validate:"omitempty,max=63"`                     // public when empty
+	Checksum         string `json:"checksum" validate:"required,len=64,hexadecimal"`                       // Of the plan reviewed; refused when the plan has changed since
+	AllowDestructive bool   `json:"allow_destructive,omitempty"`                                           // Required to drop tables or columns or convert columns
+	Transaction      string `json:"transaction,omitempty" validate:"omitempty,oneof=single per_statement"` // single when empty
+}
+
+// ApplyPlanResponse lists the statements bringing a database to the model, in order
+type ApplyPlanResponse struct {
+	Statements  []ApplyStatementResponse `json:"statements"`
+	Unsupported []string                 `json:"unsupported"` // Changes to be made by hand, e.g. of primary keys
+	Destructive bool                     `json:"destructive"`
+	Checksum    string                   `json:"checksum"` // Pass to executeApply to run this very plan
+}
+
+type ApplyStatementResponse struct {
+	SQL         string `json:"sql"`
+	Destructive bool   `json:"destructive"`
+}
+
+// ApplyLogResponse is what became of every statement of an executed plan
+type ApplyLogResponse struct {
+	Checksum    string                `json:"checksum"`
+	Transaction string                `json:"transaction"`
+	Status      string                `json:"status"` // applied, failed or unchanged when there was nothing to run
+	Statements  []ApplyResultResponse `json:"statements"`
+	Unsupported []string              `json:"unsupported"`
+}
+
+type ApplyResultResponse struct {
+	SQL        string `json:"sql"`
+	Status     string `json:"status"` // applied, failed, rolled_back or skipped
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// LintFindingResponse is a likely design problem of a table
+type LintFindingResponse struct {
+	Rule        string   `json:"rule"` // e.g. 2nf-partial-dependency
+	Table       string   `json:"table"`
+	Fields      []string `json:"fields"`
+	Message     string   `json:"message"`
+	Explanation string   `json:"explanation"`
+}
+
+// RelationshipSuggestionResponse is a relationship the schema seems to miss.
+// Its names make a relationship of AcceptRelationshipSuggestionsRequest as
+// they are; its IDs make a createRelationship request.
+type RelationshipSuggestionResponse struct {
+	SourceTable   string    `json:"source_table"`
+	SourceField   string    `json:"source_field"`
+	TargetTable   string    `json:"target_table"`
+	TargetField   string    `json:"target_field"`
+	RelationType  string    `json:"relation_type"`
+	SourceTableID uuid.UUID `json:"source_table_id"`
+	SourceFieldID uuid.UUID `json:"source_field_id"`
+	TargetTableID uuid.UUID `json:"target_table_id"`
+	TargetFieldID uuid.UUID `json:"target_field_id"`
+	Reason        string    `json:"reason"`
+}
+
+// AcceptRelationshipSuggestionsRequest creates suggested relationships at once
+type AcceptRelationshipSuggestionsRequest struct {
+	Relationships []SchemaRelationshipRequest `json:"relationships" validate:"required,min=1,max=500,dive"`
+}
+
+// RenameRequest renames a table, or one of its fields when FieldID is set
+type RenameRequest struct {
+	TableID uuid.UUID  `json:"table_id" validate:"required"`
+	FieldID *uuid.UUID `json:"field_id,omitempty"`
+	Name    string     `json:"name" validate:"required,min=1,max=255"`
+}
+
+// RenameResponse is a table or field renamed by refactoring
+type RenameResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	TableID   uuid.UUID  `json:"table_id"`
+	FieldID   *uuid.UUID `json:"field_id"`   // Null when the table was renamed
+	TableName string     `json:"table_name"` // Of the table at the time
+	OldName   string     `json:"old_name"`
+	NewName   string     `json:"new_name"`
+	RenamedBy uuid.UUID  `json:"renamed_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
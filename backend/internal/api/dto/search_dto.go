@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/google/uuid"
+
+type SearchResultResponse struct {
+	Type        string     `json:"type"` // project, table or field
+	ID          uuid.UUID  `json:"id"`
+	ProjectID   uuid.UUID  `json:"project_id"`
+	ProjectName string     `json:"project_name"`
+	TableID     *uuid.UUID `json:"table_id"`   // Table of a field
+	TableName   *string    `json:"table_name"` // Table of a field
+	Name        string     `json:"name"`
+	Detail      string     `json:"detail"` // Project description or field data type
+	Rank        float64    `json:"rank"`
+}
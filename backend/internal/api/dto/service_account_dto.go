@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateServiceAccountRequest struct {
+	Name   string   `json:"name" validate:"required,min=1,max=100"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=read:projects write:schema"`
+}
+
+type ServiceAccountResponse struct {
+	ID          uuid.UUID `json:"id"`
+	ProjectID   uuid.UUID `json:"project_id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Name        string    `json:"name"`
+	Scopes      []string  `json:"scopes"`
+	CreatedByID uuid.UUID `json:"created_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SetSnapshotRetentionRequest overrides how many snapshots a project keeps; null goes back to the instance default
+type SetSnapshotRetentionRequest struct {
+	KeepDaily   *int `json:"keep_daily" validate:"omitempty,min=0,max=366"`
+	KeepMonthly *int `json:"keep_monthly" validate:"omitempty,min=0,max=120"`
+}
+
+type SnapshotResponse struct {
+	ID              uuid.UUID `json:"id"`
+	ProjectVersion  int64     `json:"project_version"`
+	ProjectSequence int64     `json:"project_sequence"`
+	SizeBytes       int64     `json:"size_bytes"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type SnapshotRetentionResponse struct {
+	KeepDaily   int  `json:"keep_daily"`   // Latest snapshot of each of this many days is kept
+	KeepMonthly int  `json:"keep_monthly"` // Latest snapshot of each of this many months is kept
+	Overridden  bool `json:"overridden"`   // The project differs from the instance defaults
+}
@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateSnippetRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=255"`
+	Description string `json:"description,omitempty" validate:"max=1000"`
+	SQL         string `json:"sql" validate:"required,max=100000"`
+}
+
+type UpdateSnippetRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
+	SQL         *string `json:"sql,omitempty" validate:"omitempty,min=1,max=100000"`
+}
+
+type SnippetResponse struct {
+	ID          uuid.UUID `json:"snippet_id"`
+	ProjectID   uuid.UUID `json:"project_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	SQL         string    `json:"sql"`
+	CreatedBy   uuid.UUID `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -7,15 +7,26 @@ import (
 )
 
 type CreateTableRequest struct {
-	Name string  `json:"name" validate:"required,min=1,max=255"`
-	PosX float64 `json:"pos_x"`
-	PosY float64 `json:"pos_y"`
+	Name     string         `json:"name" validate:"required,min=1,max=255"`
+	PosX     float64        `json:"pos_x"`
+	PosY     float64        `json:"pos_y"`
+	Color    string         `json:"color,omitempty" validate:"omitempty,hexcolor"`
+	Icon     string         `json:"icon,omitempty" validate:"omitempty,oneof=database users user credit-card shopping-cart package file-text settings lock globe calendar mail tag activity"`
+	Metadata map[string]any `json:"metadata,omitempty" validate:"omitempty,max=50"` // Keys the organization defines, e.g. data_owner
 }
 
 type UpdateTableRequest struct {
 	Name *string  `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
 	PosX *float64 `json:"pos_x,omitempty"`
 	PosY *float64 `json:"pos_y,omitempty"`
+	// Color and Icon are cleared with an empty string or null
+	Color *string `json:"color,omitempty" validate:"omitempty,len=0|hexcolor" patch:"nullable"`
+	Icon  *string `json:"icon,omitempty" validate:"omitempty,len=0|oneof=database users user credit-card shopping-cart package file-text settings lock globe calendar mail tag activity" patch:"nullable"`
+	// Metadata is merged into the object's as a JSON Merge Patch: members set
+	// to null are removed, and null clears the metadata
+	Metadata *map[string]any `json:"metadata,omitempty" validate:"omitempty,max=50" patch:"nullable"`
+	// Version the change was made against; If-Match sets it too
+	Version *int64 `json:"version,omitempty"`
 }
 
 type UpdateTablePositionRequest struct {
@@ -24,13 +35,17 @@ type UpdateTablePositionRequest struct {
 }
 
 type TableResponse struct {
-	ID        uuid.UUID `json:"table_id"`
-	ProjectID uuid.UUID `json:"project_id"`
-	Name      string    `json:"name"`
-	PosX      float64   `json:"pos_x"`
-	PosY      float64   `json:"pos_y"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uuid.UUID      `json:"table_id"`
+	ProjectID uuid.UUID      `json:"project_id"`
+	Name      string         `json:"name"`
+	PosX      float64        `json:"pos_x"`
+	PosY      float64        `json:"pos_y"`
+	Color     string         `json:"color"`
+	Icon      string         `json:"icon"`
+	Metadata  map[string]any `json:"metadata"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Version   int64          `json:"version"`
 }
 
 type TableWithFieldsResponse struct {
@@ -39,7 +54,11 @@ type TableWithFieldsResponse struct {
 	Name      string          `json:"name"`
 	PosX      float64         `json:"pos_x"`
 	PosY      float64         `json:"pos_y"`
+	Color     string          `json:"color"`
+	Icon      string          `json:"icon"`
+	Metadata  map[string]any  `json:"metadata"`
 	Fields    []FieldResponse `json:"fields,omitempty"`
 	CreatedAt time.Time       `json:"created_at"`
 	UpdatedAt time.Time       `json:"updated_at"`
+	Version   int64           `json:"version"`
 }
@@ -0,0 +1,27 @@
+package dto
+
+import "github.com/google/uuid"
+
+// QuotaUsageResponse is how much of a quota is used; a limit of 0 means unlimited
+type QuotaUsageResponse struct {
+	Used  int `json:"used"`
+	Limit int `json:"limit"`
+}
+
+type ProjectUsageResponse struct {
+	ProjectID     uuid.UUID          `json:"project_id"`
+	Name          string             `json:"name"`
+	Tables        QuotaUsageResponse `json:"tables"`
+	Collaborators QuotaUsageResponse `json:"collaborators"`
+}
+
+type UsageResponse struct {
+	Projects      QuotaUsageResponse     `json:"projects"`
+	OwnedProjects []ProjectUsageResponse `json:"owned_projects"`
+}
+
+// QuotaExceededResponse is the data of a 402 response to a change over a quota
+type QuotaExceededResponse struct {
+	Quota string `json:"quota"` // projects, tables or collaborators
+	Limit int    `json:"limit"`
+}
@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -20,10 +22,35 @@ type UpdatePasswordRequest struct {
 	NewPassword     string `json:"new_password" validate:"required,min=6"`
 }
 
+// DeleteAccountRequest confirms deleting the current user's account. Owned
+// projects other users collaborate on must be transferred to one of them or
+// deleted with delete_shared_projects; projects nobody else uses are deleted.
+type DeleteAccountRequest struct {
+	Password             string                   `json:"password,omitempty"`      // Required for accounts with a password
+	ConfirmEmail         string                   `json:"confirm_email,omitempty"` // Confirms accounts that only sign in through SSO
+	ProjectTransfers     []ProjectTransferRequest `json:"project_transfers,omitempty" validate:"omitempty,dive"`
+	DeleteSharedProjects bool                     `json:"delete_shared_projects"`
+}
+
+// ProjectTransferRequest hands an owned project to one of its collaborators
+type ProjectTransferRequest struct {
+	ProjectID  uuid.UUID `json:"project_id" validate:"required"`
+	NewOwnerID uuid.UUID `json:"new_owner_id" validate:"required"`
+}
+
+// SharedProjectResponse is an owned project with collaborators, listed when
+// deleting an account needs a decision about it
+type SharedProjectResponse struct {
+	ID            uuid.UUID      `json:"id"`
+	Name          string         `json:"name"`
+	Collaborators []UserResponse `json:"collaborators"`
+}
+
 type UserResponse struct {
-	ID       uuid.UUID `json:"id"`
-	Email    string    `json:"email"`
-	Username string    `json:"username"`
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Username  string    `json:"username"`
+	AvatarURL string    `json:"avatar_url,omitempty"`
 }
 
 type APIResponse struct {
@@ -31,4 +58,30 @@ type APIResponse struct {
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Errors  interface{} `json:"errors,omitempty"`
+	Meta    *PageMeta   `json:"meta,omitempty"`
+}
+
+// TakenResponse is the data of a 409 response to a value another record
+// already has
+type TakenResponse struct {
+	Field string `json:"field"` // email, username or name
+}
+
+// PageMeta accompanies a page of a list. Pass next_cursor as ?cursor= to get
+// the following page; it is omitted on the last page.
+type PageMeta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// DataExportResponse describes an archive of the user's data. download_url is
+// set once it is ready and works without signing in until expires_at.
+type DataExportResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Status      string     `json:"status"` // pending, ready or failed
+	SizeBytes   int64      `json:"size_bytes"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	DownloadURL string     `json:"download_url,omitempty"`
 }
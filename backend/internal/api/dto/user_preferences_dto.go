@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type UpdateUserPreferencesRequest struct {
+	Theme                    *string `json:"theme,omitempty" validate:"omitempty,oneof=light dark system"`
+	DefaultDatabaseType      *string `json:"default_database_type,omitempty" validate:"omitempty,oneof=postgresql mysql sqlite sqlserver"`
+	CanvasShowGrid           *bool   `json:"canvas_show_grid,omitempty"`
+	CanvasSnapToGrid         *bool   `json:"canvas_snap_to_grid,omitempty"`
+	CanvasGridSize           *int    `json:"canvas_grid_size,omitempty" validate:"omitempty,min=5,max=100"`
+	NotifyCollaboratorJoined *bool   `json:"notify_collaborator_joined,omitempty"`
+	NotifyProjectShared      *bool   `json:"notify_project_shared,omitempty"`
+	EmailNotifications       *bool   `json:"email_notifications,omitempty"`
+}
+
+type UserPreferencesResponse struct {
+	Theme                    string     `json:"theme"`
+	DefaultDatabaseType      string     `json:"default_database_type"`
+	CanvasShowGrid           bool       `json:"canvas_show_grid"`
+	CanvasSnapToGrid         bool       `json:"canvas_snap_to_grid"`
+	CanvasGridSize           int        `json:"canvas_grid_size"`
+	NotifyCollaboratorJoined bool       `json:"notify_collaborator_joined"`
+	NotifyProjectShared      bool       `json:"notify_project_shared"`
+	EmailNotifications       bool       `json:"email_notifications"`
+	UpdatedAt                *time.Time `json:"updated_at"` // Null until the user changes a preference
+}
@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Response DTOs
+type UserSessionResponse struct {
+	ID           uuid.UUID `json:"id"`
+	IPAddress    string    `json:"ip_address"`
+	UserAgent    string    `json:"user_agent"`
+	LastSeenAt   time.Time `json:"last_seen_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	Current      bool      `json:"current"`      // Session the request was made with
+	Impersonated bool      `json:"impersonated"` // Support session started by an admin
+}
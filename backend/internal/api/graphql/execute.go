@@ -0,0 +1,135 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// MaxDepth limits how deeply selections nest. The graph has cycles, such as
+// table → relationships → source_table, so unbounded queries could be huge.
+const MaxDepth = 10
+
+var errSubscriptionOverHTTP = &Error{Message: "Subscriptions require the WebSocket transport", Code: CodeBadUserInput}
+
+// Execute runs a query or mutation
+func Execute(ctx context.Context, schema gql.Schema, req dto.GraphQLRequest) *gql.Result {
+	operation, err := inspect(req)
+	if err != nil {
+		return errorResult(err)
+	}
+	if operation == ast.OperationTypeSubscription {
+		return errorResult(errSubscriptionOverHTTP)
+	}
+
+	return gql.Do(gql.Params{
+		Schema:         schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+}
+
+// Subscribe runs any operation, streaming results until ctx ends for
+// subscriptions. Queries and mutations yield a single result.
+func Subscribe(ctx context.Context, schema gql.Schema, req dto.GraphQLRequest) <-chan *gql.Result {
+	operation, err := inspect(req)
+	if err != nil {
+		return single(errorResult(err))
+	}
+	if operation != ast.OperationTypeSubscription {
+		return single(Execute(ctx, schema, req))
+	}
+
+	return gql.Subscribe(gql.Params{
+		Schema:         schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        ctx,
+	})
+}
+
+// inspect parses the request, enforces MaxDepth and returns the type of the
+// selected operation. Other errors are left to the executor to report.
+func inspect(req dto.GraphQLRequest) (string, error) {
+	doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil {
+		return "", err
+	}
+
+	fragments := make(map[string]*ast.FragmentDefinition)
+	var operations []*ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch d := definition.(type) {
+		case *ast.FragmentDefinition:
+			fragments[d.Name.Value] = d
+		case *ast.OperationDefinition:
+			operations = append(operations, d)
+		}
+	}
+
+	operation := ""
+	for _, op := range operations {
+		if depth := selectionDepth(op.SelectionSet, fragments, map[string]bool{}); depth > MaxDepth {
+			return "", &Error{Message: fmt.Sprintf("Query is nested %d levels deep; the limit is %d", depth, MaxDepth), Code: CodeBadUserInput}
+		}
+		if len(operations) == 1 || (op.Name != nil && op.Name.Value == req.OperationName) {
+			operation = op.Operation
+		}
+	}
+	return operation, nil
+}
+
+func selectionDepth(set *ast.SelectionSet, fragments map[string]*ast.FragmentDefinition, visiting map[string]bool) int {
+	if set == nil {
+		return 0
+	}
+
+	deepest := 0
+	for _, selection := range set.Selections {
+		depth := 0
+		switch s := selection.(type) {
+		case *ast.Field:
+			depth = 1 + selectionDepth(s.SelectionSet, fragments, visiting)
+		case *ast.InlineFragment:
+			depth = selectionDepth(s.SelectionSet, fragments, visiting)
+		case *ast.FragmentSpread:
+			// Cyclic spreads are rejected by validation; just stop following them
+			if fragment, ok := fragments[s.Name.Value]; ok && !visiting[s.Name.Value] {
+				visiting[s.Name.Value] = true
+				depth = selectionDepth(fragment.SelectionSet, fragments, visiting)
+				delete(visiting, s.Name.Value)
+			}
+		}
+		deepest = max(deepest, depth)
+	}
+	return deepest
+}
+
+func errorResult(err error) *gql.Result {
+	if formatted, ok := err.(*gqlerrors.Error); ok {
+		return &gql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(formatted)}}
+	}
+	return &gql.Result{Errors: []gqlerrors.FormattedError{{Message: err.Error(), Extensions: extensionsOf(err)}}}
+}
+
+func extensionsOf(err error) map[string]any {
+	if extended, ok := err.(gqlerrors.ExtendedError); ok {
+		return extended.Extensions()
+	}
+	return nil
+}
+
+func single(result *gql.Result) <-chan *gql.Result {
+	results := make(chan *gql.Result, 1)
+	results <- result
+	close(results)
+	return results
+}
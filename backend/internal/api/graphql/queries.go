@@ -0,0 +1,417 @@
+package graphql
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	gql "github.com/graphql-go/graphql"
+)
+
+var (
+	errNotOwner    = &Error{Message: "Only the project owner can delete it", Code: CodeForbidden}
+	errSessionOnly = &Error{Message: "API tokens cannot delete projects", Code: CodeForbidden}
+)
+
+// Queries
+
+func (r *Resolver) projects(p gql.ResolveParams) (any, error) {
+	userID, err := r.currentUser(p.Context, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// A project-restricted token sees only its project
+	if tokenProject, restricted := middleware.GetAPITokenProjectFromContext(p.Context); restricted {
+		project, err := r.projectService.GetProjectByID(tokenProject)
+		if err != nil {
+			return nil, toGraphQLError(err)
+		}
+		return []*models.Project{project}, nil
+	}
+
+	owned, err := r.projectService.GetProjectsByOwnerID(userID, repository.ProjectIncludes{})
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	shared, err := r.projectService.GetProjectsByCollaboratorID(userID, repository.ProjectIncludes{})
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(owned))
+	projects := make([]*models.Project, 0, len(owned)+len(shared))
+	for _, project := range append(owned, shared...) {
+		if !seen[project.ID] {
+			seen[project.ID] = true
+			projects = append(projects, project)
+		}
+	}
+	return projects, nil
+}
+
+func (r *Resolver) project(p gql.ResolveParams) (any, error) {
+	id, err := parseID(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.authorize(p.Context, id, false); err != nil {
+		return nil, err
+	}
+
+	// Loads tables with their fields and the relationships in one go
+	project, err := r.projectService.GetProjectByID(id)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return project, nil
+}
+
+// Nested fields. Access was checked when the root project was resolved.
+
+func (r *Resolver) projectTables(p gql.ResolveParams) (any, error) {
+	project := asProject(p.Source)
+	if len(project.Tables) > 0 {
+		return pointers(project.Tables), nil
+	}
+	tables, err := r.tableService.GetTablesByProjectID(project.ID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return tables, nil
+}
+
+func (r *Resolver) projectRelationships(p gql.ResolveParams) (any, error) {
+	project := asProject(p.Source)
+	if len(project.Relationships) > 0 {
+		return pointers(project.Relationships), nil
+	}
+	relationships, err := r.relationshipService.GetRelationshipsByProjectID(project.ID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return relationships, nil
+}
+
+func (r *Resolver) tableFields(p gql.ResolveParams) (any, error) {
+	table := asTable(p.Source)
+	if len(table.Fields) > 0 {
+		return pointers(table.Fields), nil
+	}
+	fields, err := r.fieldService.GetFieldsByTableID(table.ID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return fields, nil
+}
+
+func (r *Resolver) tableRelationships(p gql.ResolveParams) (any, error) {
+	relationships, err := r.relationshipService.GetRelationshipsByTableID(asTable(p.Source).ID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return relationships, nil
+}
+
+func (r *Resolver) relationshipTable(source bool) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (any, error) {
+		relationship := asRelationship(p.Source)
+		id := relationship.TargetTableID
+		if source {
+			id = relationship.SourceTableID
+		}
+		table, err := r.tableService.GetTableByID(id)
+		if err != nil {
+			return nil, toGraphQLError(err)
+		}
+		return table, nil
+	}
+}
+
+func (r *Resolver) relationshipField(source bool) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (any, error) {
+		relationship := asRelationship(p.Source)
+		id := relationship.TargetFieldID
+		if source {
+			id = relationship.SourceFieldID
+		}
+		field, err := r.fieldService.GetFieldByID(id)
+		if err != nil {
+			return nil, toGraphQLError(err)
+		}
+		return field, nil
+	}
+}
+
+// Project mutations
+
+func (r *Resolver) createProject(p gql.ResolveParams) (any, error) {
+	userID, err := r.currentUser(p.Context, true)
+	if err != nil {
+		return nil, err
+	}
+	if _, restricted := middleware.GetAPITokenProjectFromContext(p.Context); restricted {
+		return nil, errTokenProject
+	}
+
+	var req dto.CreateProjectRequest
+	if err := decodeInput(p.Args["input"], &req); err != nil {
+		return nil, err
+	}
+
+	project, err := r.projectService.CreateProject(req.Name, req.Description, userID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return project, nil
+}
+
+func (r *Resolver) updateProject(p gql.ResolveParams) (any, error) {
+	id, err := parseID(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+	userID, err := r.authorize(p.Context, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var req dto.UpdateProjectRequest
+	if err := decodeInput(p.Args["input"], &req); err != nil {
+		return nil, err
+	}
+
+	project, err := r.projectService.UpdateProject(id, &req, userID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return project, nil
+}
+
+func (r *Resolver) deleteProject(p gql.ResolveParams) (any, error) {
+	// Like DELETE /projects/{id}, deleting a project takes a signed-in session
+	if _, isAPIToken := middleware.GetAPITokenScopesFromContext(p.Context); isAPIToken {
+		return nil, errSessionOnly
+	}
+
+	id, err := parseID(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+	userID, err := r.authorize(p.Context, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := r.projectService.GetProjectByID(id)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	if project.OwnerID != userID {
+		return nil, errNotOwner
+	}
+
+	if err := r.projectService.DeleteProject(id); err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return true, nil
+}
+
+// Table mutations
+
+func (r *Resolver) createTable(p gql.ResolveParams) (any, error) {
+	projectID, err := parseID(p.Args, "project_id")
+	if err != nil {
+		return nil, err
+	}
+	userID, err := r.authorize(p.Context, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var req dto.CreateTableRequest
+	if err := decodeInput(p.Args["input"], &req); err != nil {
+		return nil, err
+	}
+
+	table, err := r.tableService.CreateTable(projectID, &req, userID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return table, nil
+}
+
+func (r *Resolver) updateTable(p gql.ResolveParams) (any, error) {
+	id, userID, err := r.authorizeTable(p, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	var req dto.UpdateTableRequest
+	if err := decodeInput(p.Args["input"], &req); err != nil {
+		return nil, err
+	}
+
+	table, err := r.tableService.UpdateTable(id, &req, userID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return table, nil
+}
+
+func (r *Resolver) deleteTable(p gql.ResolveParams) (any, error) {
+	id, userID, err := r.authorizeTable(p, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.tableService.DeleteTable(id, userID); err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return true, nil
+}
+
+// authorizeTable parses a table ID argument and checks write access to its project
+func (r *Resolver) authorizeTable(p gql.ResolveParams, arg string) (uuid.UUID, uuid.UUID, error) {
+	id, err := parseID(p.Args, arg)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	projectID, err := r.authService.GetProjectIDFromTable(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, toGraphQLError(err)
+	}
+	userID, err := r.authorize(p.Context, projectID, true)
+	return id, userID, err
+}
+
+// Field mutations
+
+func (r *Resolver) createField(p gql.ResolveParams) (any, error) {
+	tableID, userID, err := r.authorizeTable(p, "table_id")
+	if err != nil {
+		return nil, err
+	}
+
+	var req dto.CreateFieldRequest
+	if err := decodeInput(p.Args["input"], &req); err != nil {
+		return nil, err
+	}
+
+	field, err := r.fieldService.CreateField(tableID, &req, userID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return field, nil
+}
+
+func (r *Resolver) updateField(p gql.ResolveParams) (any, error) {
+	id, userID, err := r.authorizeField(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var req dto.UpdateFieldRequest
+	if err := decodeInput(p.Args["input"], &req); err != nil {
+		return nil, err
+	}
+
+	field, err := r.fieldService.UpdateField(id, &req, userID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return field, nil
+}
+
+func (r *Resolver) deleteField(p gql.ResolveParams) (any, error) {
+	id, userID, err := r.authorizeField(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.fieldService.DeleteField(id, userID); err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return true, nil
+}
+
+func (r *Resolver) authorizeField(p gql.ResolveParams) (uuid.UUID, uuid.UUID, error) {
+	id, err := parseID(p.Args, "id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	projectID, err := r.authService.GetProjectIDFromField(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, toGraphQLError(err)
+	}
+	userID, err := r.authorize(p.Context, projectID, true)
+	return id, userID, err
+}
+
+// Relationship mutations
+
+func (r *Resolver) createRelationship(p gql.ResolveParams) (any, error) {
+	projectID, err := parseID(p.Args, "project_id")
+	if err != nil {
+		return nil, err
+	}
+	userID, err := r.authorize(p.Context, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var req dto.CreateRelationshipRequest
+	if err := decodeInput(p.Args["input"], &req); err != nil {
+		return nil, err
+	}
+
+	relationship, err := r.relationshipService.CreateRelationship(projectID, &req, userID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return relationship, nil
+}
+
+func (r *Resolver) updateRelationship(p gql.ResolveParams) (any, error) {
+	id, userID, err := r.authorizeRelationship(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var req dto.UpdateRelationshipRequest
+	if err := decodeInput(p.Args["input"], &req); err != nil {
+		return nil, err
+	}
+
+	relationship, err := r.relationshipService.UpdateRelationship(id, &req, userID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return relationship, nil
+}
+
+func (r *Resolver) deleteRelationship(p gql.ResolveParams) (any, error) {
+	id, userID, err := r.authorizeRelationship(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.relationshipService.DeleteRelationship(id, userID); err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return true, nil
+}
+
+func (r *Resolver) authorizeRelationship(p gql.ResolveParams) (uuid.UUID, uuid.UUID, error) {
+	id, err := parseID(p.Args, "id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	projectID, err := r.authService.GetProjectIDFromRelationship(id)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, toGraphQLError(err)
+	}
+	userID, err := r.authorize(p.Context, projectID, true)
+	return id, userID, err
+}
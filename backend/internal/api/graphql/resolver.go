@@ -0,0 +1,244 @@
+// Package graphql exposes projects, tables, fields and relationships as a
+// GraphQL graph, with subscriptions fed by the WebSocket hub.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
+	"github.com/Bug-Bugger/ezmodel/internal/canvas"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/validation"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+	"github.com/google/uuid"
+)
+
+// Error codes reported in the extensions of GraphQL errors
+const (
+	CodeUnauthenticated = "UNAUTHENTICATED"
+	CodeForbidden       = "FORBIDDEN"
+	CodeNotFound        = "NOT_FOUND"
+	CodeBadUserInput    = "BAD_USER_INPUT"
+	CodeConflict        = "CONFLICT"
+	CodeQuotaExceeded   = "QUOTA_EXCEEDED"
+	CodeInternal        = "INTERNAL_SERVER_ERROR"
+)
+
+// Error is a GraphQL error with a machine-readable code and, for invalid
+// input, a message per field
+type Error struct {
+	Message string
+	Code    string
+	Fields  map[string]string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions implements gqlerrors.ExtendedError
+func (e *Error) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.Code}
+	if len(e.Fields) > 0 {
+		extensions["fields"] = e.Fields
+	}
+	return extensions
+}
+
+var (
+	errUnauthenticated = &Error{Message: "Authentication required", Code: CodeUnauthenticated}
+	errForbidden       = &Error{Message: "You do not have access to this project", Code: CodeForbidden}
+	errMissingScope    = &Error{Message: "API token is missing the required scope", Code: CodeForbidden}
+	errTokenProject    = &Error{Message: "API token is not valid for this project", Code: CodeForbidden}
+	errInvalidInput    = &Error{Message: "Invalid input", Code: CodeBadUserInput}
+)
+
+// Resolver answers GraphQL fields from the services
+type Resolver struct {
+	projectService      services.ProjectServiceInterface
+	tableService        services.TableServiceInterface
+	fieldService        services.FieldServiceInterface
+	relationshipService services.RelationshipServiceInterface
+	authService         services.AuthorizationServiceInterface
+	hub                 *websocketPkg.Hub
+}
+
+func NewResolver(
+	projectService services.ProjectServiceInterface,
+	tableService services.TableServiceInterface,
+	fieldService services.FieldServiceInterface,
+	relationshipService services.RelationshipServiceInterface,
+	authService services.AuthorizationServiceInterface,
+	hub *websocketPkg.Hub,
+) *Resolver {
+	return &Resolver{
+		projectService:      projectService,
+		tableService:        tableService,
+		fieldService:        fieldService,
+		relationshipService: relationshipService,
+		authService:         authService,
+		hub:                 hub,
+	}
+}
+
+// currentUser returns the authenticated user, checking that an API token holds
+// the scope the operation needs
+func (r *Resolver) currentUser(ctx context.Context, write bool) (uuid.UUID, error) {
+	userIDStr, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		return uuid.Nil, errUnauthenticated
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, errUnauthenticated
+	}
+
+	if scopes, isAPIToken := middleware.GetAPITokenScopesFromContext(ctx); isAPIToken {
+		allowed := slices.Contains(scopes, services.ScopeWriteSchema)
+		if !allowed && !write {
+			allowed = slices.Contains(scopes, services.ScopeReadProjects)
+		}
+		if !allowed {
+			return uuid.Nil, errMissingScope
+		}
+	}
+	return userID, nil
+}
+
+// authorize checks that the current user can read, or for writes modify, the project
+func (r *Resolver) authorize(ctx context.Context, projectID uuid.UUID, write bool) (uuid.UUID, error) {
+	userID, err := r.currentUser(ctx, write)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if tokenProject, restricted := middleware.GetAPITokenProjectFromContext(ctx); restricted && tokenProject != projectID {
+		return uuid.Nil, errTokenProject
+	}
+
+	var allowed bool
+	if write {
+		allowed, err = r.authService.CanUserModifyProject(userID, projectID)
+	} else {
+		allowed, err = r.authService.CanUserAccessProject(userID, projectID)
+	}
+	if err != nil {
+		return uuid.Nil, toGraphQLError(err)
+	}
+	if !allowed {
+		return uuid.Nil, errForbidden
+	}
+	return userID, nil
+}
+
+// decodeInput fills dst from a GraphQL input object and validates it like a REST body
+func decodeInput(input any, dst any) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return errInvalidInput
+	}
+	if err := json.Unmarshal(body, dst); err != nil {
+		return errInvalidInput
+	}
+	if err := validation.Validate(dst); err != nil {
+		return &Error{Message: "Validation failed", Code: CodeBadUserInput, Fields: validation.ValidationErrors(err)}
+	}
+	return nil
+}
+
+// parseID reads an ID argument as a UUID
+func parseID(args map[string]any, name string) (uuid.UUID, error) {
+	value, _ := args[name].(string)
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, &Error{Message: "Invalid " + name, Code: CodeBadUserInput}
+	}
+	return id, nil
+}
+
+// toGraphQLError maps service errors to GraphQL errors, hiding unexpected ones
+func toGraphQLError(err error) error {
+	var gqlErr *Error
+	var canvasErr *canvas.ValidationError
+	switch {
+	case errors.As(err, &gqlErr):
+		return err
+	case errors.As(err, &canvasErr):
+		fields := make(map[string]string, len(canvasErr.Errors))
+		for path, message := range canvasErr.Errors {
+			fields[strings.TrimSuffix("canvas_data."+path, ".")] = message
+		}
+		return &Error{Message: "Invalid canvas data", Code: CodeBadUserInput, Fields: fields}
+	case errors.Is(err, canvas.ErrTooLarge):
+		return &Error{Message: "Canvas data is too large", Code: CodeBadUserInput}
+	case errors.Is(err, services.ErrProjectNotFound):
+		return &Error{Message: "Project not found", Code: CodeNotFound}
+	case errors.Is(err, services.ErrTableNotFound):
+		return &Error{Message: "Table not found", Code: CodeNotFound}
+	case errors.Is(err, services.ErrFieldNotFound):
+		return &Error{Message: "Field not found", Code: CodeNotFound}
+	case errors.Is(err, services.ErrRelationshipNotFound):
+		return &Error{Message: "Relationship not found", Code: CodeNotFound}
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrUnauthorized):
+		return errForbidden
+	case errors.Is(err, services.ErrInvalidInput):
+		return errInvalidInput
+	case errors.Is(err, services.ErrVersionConflict):
+		return &Error{Message: "Modified by someone else since the given version", Code: CodeConflict}
+	case errors.Is(err, services.ErrFieldNameTaken):
+		return &Error{Message: "A field of the table already has this name", Code: CodeConflict, Fields: map[string]string{"name": "is already taken"}}
+	case errors.Is(err, services.ErrNameTaken):
+		return &Error{Message: "A table of the project already has this name", Code: CodeConflict, Fields: map[string]string{"name": "is already taken"}}
+	case errors.Is(err, services.ErrQuotaExceeded):
+		return &Error{Message: err.Error(), Code: CodeQuotaExceeded}
+	default:
+		log.Printf("GraphQL: resolver failed: %v", err)
+		return &Error{Message: "Internal server error", Code: CodeInternal}
+	}
+}
+
+// Sources arrive as values when preloaded by GORM and as pointers from the services
+
+func asProject(source any) *models.Project {
+	switch p := source.(type) {
+	case *models.Project:
+		return p
+	case models.Project:
+		return &p
+	}
+	return nil
+}
+
+func asTable(source any) *models.Table {
+	switch t := source.(type) {
+	case *models.Table:
+		return t
+	case models.Table:
+		return &t
+	}
+	return nil
+}
+
+func asRelationship(source any) *models.Relationship {
+	switch rel := source.(type) {
+	case *models.Relationship:
+		return rel
+	case models.Relationship:
+		return &rel
+	}
+	return nil
+}
+
+func pointers[T any](values []T) []*T {
+	result := make([]*T, len(values))
+	for i := range values {
+		result[i] = &values[i]
+	}
+	return result
+}
@@ -0,0 +1,280 @@
+package graphql
+
+import (
+	"encoding/json"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// jsonScalar carries arbitrary JSON, such as the payload of a project event
+var jsonScalar = gql.NewScalar(gql.ScalarConfig{
+	Name:        "JSON",
+	Description: "Arbitrary JSON value",
+	Serialize: func(value any) any {
+		if raw, ok := value.(json.RawMessage); ok {
+			var decoded any
+			if err := json.Unmarshal(raw, &decoded); err != nil {
+				return nil
+			}
+			return decoded
+		}
+		return value
+	},
+	ParseValue: func(value any) any { return value },
+	ParseLiteral: func(valueAST ast.Value) any {
+		return valueAST.GetValue()
+	},
+})
+
+// NewSchema builds the schema. Field names match the JSON of the REST API.
+func NewSchema(r *Resolver) (gql.Schema, error) {
+	nonNullList := func(t gql.Type) gql.Output { return gql.NewNonNull(gql.NewList(gql.NewNonNull(t))) }
+	nonNullID := gql.NewNonNull(gql.ID)
+
+	userType := gql.NewObject(gql.ObjectConfig{
+		Name: "User",
+		Fields: gql.Fields{
+			"id":       {Type: nonNullID},
+			"username": {Type: gql.NewNonNull(gql.String)},
+			"email":    {Type: gql.NewNonNull(gql.String)},
+		},
+	})
+
+	fieldType := gql.NewObject(gql.ObjectConfig{
+		Name:        "Field",
+		Description: "A column of a table",
+		Fields: gql.Fields{
+			"id":             {Type: nonNullID},
+			"table_id":       {Type: nonNullID},
+			"name":           {Type: gql.NewNonNull(gql.String)},
+			"data_type":      {Type: gql.NewNonNull(gql.String)},
+			"is_primary_key": {Type: gql.NewNonNull(gql.Boolean)},
+			"is_nullable":    {Type: gql.NewNonNull(gql.Boolean)},
+			"default_value":  {Type: gql.NewNonNull(gql.String)},
+			"position":       {Type: gql.NewNonNull(gql.Int)},
+			"created_at":     {Type: gql.NewNonNull(gql.DateTime)},
+			"updated_at":     {Type: gql.NewNonNull(gql.DateTime)},
+			"version":        {Type: gql.NewNonNull(gql.Int), Description: "Incremented by every update"},
+		},
+	})
+
+	tableType := gql.NewObject(gql.ObjectConfig{
+		Name:        "Table",
+		Description: "A table of a project's schema",
+		Fields: gql.Fields{
+			"id":         {Type: nonNullID},
+			"project_id": {Type: nonNullID},
+			"name":       {Type: gql.NewNonNull(gql.String)},
+			"pos_x":      {Type: gql.NewNonNull(gql.Float)},
+			"pos_y":      {Type: gql.NewNonNull(gql.Float)},
+			"color":      {Type: gql.NewNonNull(gql.String), Description: "Hex color of the header, or empty for the default"},
+			"icon":       {Type: gql.NewNonNull(gql.String), Description: "Icon shown next to the name, or empty for none"},
+			"created_at": {Type: gql.NewNonNull(gql.DateTime)},
+			"updated_at": {Type: gql.NewNonNull(gql.DateTime)},
+			"version":    {Type: gql.NewNonNull(gql.Int), Description: "Incremented by every update"},
+			"fields":     {Type: nonNullList(fieldType), Resolve: r.tableFields},
+		},
+	})
+
+	pointType := gql.NewObject(gql.ObjectConfig{
+		Name:        "Point",
+		Description: "A position on the canvas",
+		Fields: gql.Fields{
+			"x": {Type: gql.NewNonNull(gql.Float)},
+			"y": {Type: gql.NewNonNull(gql.Float)},
+		},
+	})
+
+	relationshipType := gql.NewObject(gql.ObjectConfig{
+		Name:        "Relationship",
+		Description: "A foreign key between two tables",
+		Fields: gql.Fields{
+			"id":              {Type: nonNullID},
+			"project_id":      {Type: nonNullID},
+			"source_table_id": {Type: nonNullID},
+			"source_field_id": {Type: nonNullID},
+			"target_table_id": {Type: nonNullID},
+			"target_field_id": {Type: nonNullID},
+			"relation_type":   {Type: gql.NewNonNull(gql.String)},
+			"source_anchor":   {Type: gql.NewNonNull(gql.String), Description: "Side of the source table the edge leaves by: auto, top, right, bottom or left"},
+			"target_anchor":   {Type: gql.NewNonNull(gql.String), Description: "Side of the target table the edge enters by: auto, top, right, bottom or left"},
+			"waypoints":       {Type: nonNullList(pointType), Description: "Points the edge passes through, in order"},
+			"label_position":  {Type: gql.NewNonNull(gql.Float), Description: "Fraction of the edge's length from the source at which the label sits"},
+			"created_at":      {Type: gql.NewNonNull(gql.DateTime)},
+			"updated_at":      {Type: gql.NewNonNull(gql.DateTime)},
+			"version":         {Type: gql.NewNonNull(gql.Int), Description: "Incremented by every update"},
+			"source_table":    {Type: tableType, Resolve: r.relationshipTable(true)},
+			"target_table":    {Type: tableType, Resolve: r.relationshipTable(false)},
+			"source_field":    {Type: fieldType, Resolve: r.relationshipField(true)},
+			"target_field":    {Type: fieldType, Resolve: r.relationshipField(false)},
+		},
+	})
+
+	// Added here because the two types refer to each other
+	tableType.AddFieldConfig("relationships", &gql.Field{
+		Type:        nonNullList(relationshipType),
+		Description: "Relationships in which the table is the source or the target",
+		Resolve:     r.tableRelationships,
+	})
+
+	projectType := gql.NewObject(gql.ObjectConfig{
+		Name: "Project",
+		Fields: gql.Fields{
+			"id":            {Type: nonNullID},
+			"name":          {Type: gql.NewNonNull(gql.String)},
+			"description":   {Type: gql.NewNonNull(gql.String)},
+			"owner_id":      {Type: nonNullID},
+			"database_type": {Type: gql.NewNonNull(gql.String)},
+			"canvas_data":   {Type: gql.NewNonNull(gql.String)},
+			"created_at":    {Type: gql.NewNonNull(gql.DateTime)},
+			"updated_at":    {Type: gql.NewNonNull(gql.DateTime)},
+			"version":       {Type: gql.NewNonNull(gql.Int), Description: "Incremented by every update"},
+			"owner":         {Type: gql.NewNonNull(userType)},
+			"collaborators": {Type: nonNullList(userType)},
+			"tables":        {Type: nonNullList(tableType), Resolve: r.projectTables},
+			"relationships": {Type: nonNullList(relationshipType), Resolve: r.projectRelationships},
+		},
+	})
+
+	projectEventType := gql.NewObject(gql.ObjectConfig{
+		Name:        "ProjectEvent",
+		Description: "A message broadcast on the project's collaboration WebSocket",
+		Fields: gql.Fields{
+			"type":       {Type: gql.NewNonNull(gql.String), Description: "WebSocket message type, e.g. table_created"},
+			"project_id": {Type: nonNullID},
+			"user_id":    {Type: nonNullID, Description: "User who caused the event"},
+			"timestamp":  {Type: gql.NewNonNull(gql.DateTime)},
+			"data":       {Type: jsonScalar, Description: "Message payload, as sent on the WebSocket"},
+		},
+	})
+
+	input := func(name string, fields gql.InputObjectConfigFieldMap) *gql.InputObject {
+		return gql.NewInputObject(gql.InputObjectConfig{Name: name, Fields: fields})
+	}
+	createProjectInput := input("CreateProjectInput", gql.InputObjectConfigFieldMap{
+		"name":        {Type: gql.NewNonNull(gql.String)},
+		"description": {Type: gql.String},
+	})
+	updateProjectInput := input("UpdateProjectInput", gql.InputObjectConfigFieldMap{
+		"name":        {Type: gql.String},
+		"description": {Type: gql.String},
+		"canvas_data": {Type: gql.String},
+		"version":     {Type: gql.Int, Description: "Version the update was made against; fails with CONFLICT once another writer changed it"},
+	})
+	createTableInput := input("CreateTableInput", gql.InputObjectConfigFieldMap{
+		"name":  {Type: gql.NewNonNull(gql.String)},
+		"pos_x": {Type: gql.Float},
+		"pos_y": {Type: gql.Float},
+		"color": {Type: gql.String, Description: "Hex color, e.g. #3b82f6"},
+		"icon":  {Type: gql.String, Description: "database, users, user, credit-card, shopping-cart, package, file-text, settings, lock, globe, calendar, mail, tag or activity"},
+	})
+	updateTableInput := input("UpdateTableInput", gql.InputObjectConfigFieldMap{
+		"name":    {Type: gql.String},
+		"pos_x":   {Type: gql.Float},
+		"pos_y":   {Type: gql.Float},
+		"color":   {Type: gql.String, Description: "Empty for the default"},
+		"icon":    {Type: gql.String, Description: "Empty for none"},
+		"version": {Type: gql.Int, Description: "Version the update was made against; fails with CONFLICT once another writer changed it"},
+	})
+	createFieldInput := input("CreateFieldInput", gql.InputObjectConfigFieldMap{
+		"name":           {Type: gql.NewNonNull(gql.String)},
+		"data_type":      {Type: gql.NewNonNull(gql.String)},
+		"is_primary_key": {Type: gql.Boolean},
+		"is_nullable":    {Type: gql.Boolean},
+		"default_value":  {Type: gql.String},
+		"position":       {Type: gql.Int},
+	})
+	updateFieldInput := input("UpdateFieldInput", gql.InputObjectConfigFieldMap{
+		"name":           {Type: gql.String},
+		"data_type":      {Type: gql.String},
+		"is_primary_key": {Type: gql.Boolean},
+		"is_nullable":    {Type: gql.Boolean},
+		"default_value":  {Type: gql.String},
+		"position":       {Type: gql.Int},
+		"version":        {Type: gql.Int, Description: "Version the update was made against; fails with CONFLICT once another writer changed it"},
+	})
+	pointInput := input("PointInput", gql.InputObjectConfigFieldMap{
+		"x": {Type: gql.NewNonNull(gql.Float)},
+		"y": {Type: gql.NewNonNull(gql.Float)},
+	})
+	createRelationshipInput := input("CreateRelationshipInput", gql.InputObjectConfigFieldMap{
+		"source_table_id": {Type: nonNullID},
+		"source_field_id": {Type: nonNullID},
+		"target_table_id": {Type: nonNullID},
+		"target_field_id": {Type: nonNullID},
+		"relation_type":   {Type: gql.NewNonNull(gql.String), Description: "one_to_one, one_to_many or many_to_many"},
+		"source_anchor":   {Type: gql.String, Description: "auto, top, right, bottom or left; auto when left out"},
+		"target_anchor":   {Type: gql.String, Description: "auto, top, right, bottom or left; auto when left out"},
+		"waypoints":       {Type: gql.NewList(gql.NewNonNull(pointInput))},
+		"label_position":  {Type: gql.Float, Description: "Between 0 and 1; 0.5 when left out"},
+	})
+	updateRelationshipInput := input("UpdateRelationshipInput", gql.InputObjectConfigFieldMap{
+		"source_table_id": {Type: gql.ID},
+		"source_field_id": {Type: gql.ID},
+		"target_table_id": {Type: gql.ID},
+		"target_field_id": {Type: gql.ID},
+		"relation_type":   {Type: gql.String},
+		"source_anchor":   {Type: gql.String},
+		"target_anchor":   {Type: gql.String},
+		"waypoints":       {Type: gql.NewList(gql.NewNonNull(pointInput))},
+		"label_position":  {Type: gql.Float},
+		"version":         {Type: gql.Int, Description: "Version the update was made against; fails with CONFLICT once another writer changed it"},
+	})
+
+	idArgs := gql.FieldConfigArgument{"id": {Type: nonNullID}}
+	withInput := func(parent string, inputType *gql.InputObject) gql.FieldConfigArgument {
+		args := gql.FieldConfigArgument{"input": {Type: gql.NewNonNull(inputType)}}
+		if parent != "" {
+			args[parent] = &gql.ArgumentConfig{Type: nonNullID}
+		}
+		return args
+	}
+
+	query := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"projects": {Type: nonNullList(projectType), Description: "Projects the user owns or collaborates on", Resolve: r.projects},
+			"project":  {Type: projectType, Args: idArgs, Resolve: r.project},
+		},
+	})
+
+	mutation := gql.NewObject(gql.ObjectConfig{
+		Name: "Mutation",
+		Fields: gql.Fields{
+			"create_project": {Type: gql.NewNonNull(projectType), Args: withInput("", createProjectInput), Resolve: r.createProject},
+			"update_project": {Type: gql.NewNonNull(projectType), Args: withInput("id", updateProjectInput), Resolve: r.updateProject},
+			"delete_project": {Type: gql.NewNonNull(gql.Boolean), Args: idArgs, Resolve: r.deleteProject},
+
+			"create_table": {Type: gql.NewNonNull(tableType), Args: withInput("project_id", createTableInput), Resolve: r.createTable},
+			"update_table": {Type: gql.NewNonNull(tableType), Args: withInput("id", updateTableInput), Resolve: r.updateTable},
+			"delete_table": {Type: gql.NewNonNull(gql.Boolean), Args: idArgs, Resolve: r.deleteTable},
+
+			"create_field": {Type: gql.NewNonNull(fieldType), Args: withInput("table_id", createFieldInput), Resolve: r.createField},
+			"update_field": {Type: gql.NewNonNull(fieldType), Args: withInput("id", updateFieldInput), Resolve: r.updateField},
+			"delete_field": {Type: gql.NewNonNull(gql.Boolean), Args: idArgs, Resolve: r.deleteField},
+
+			"create_relationship": {Type: gql.NewNonNull(relationshipType), Args: withInput("project_id", createRelationshipInput), Resolve: r.createRelationship},
+			"update_relationship": {Type: gql.NewNonNull(relationshipType), Args: withInput("id", updateRelationshipInput), Resolve: r.updateRelationship},
+			"delete_relationship": {Type: gql.NewNonNull(gql.Boolean), Args: idArgs, Resolve: r.deleteRelationship},
+		},
+	})
+
+	subscription := gql.NewObject(gql.ObjectConfig{
+		Name: "Subscription",
+		Fields: gql.Fields{
+			"project_events": {
+				Type:        gql.NewNonNull(projectEventType),
+				Description: "Changes made to the project by anyone, as broadcast to WebSocket collaborators",
+				Args: gql.FieldConfigArgument{
+					"project_id": {Type: nonNullID},
+					"types":      {Type: gql.NewList(gql.NewNonNull(gql.String)), Description: "Only these message types; all when omitted"},
+				},
+				Subscribe: r.subscribeProjectEvents,
+				Resolve:   r.projectEvent,
+			},
+		},
+	})
+
+	return gql.NewSchema(gql.SchemaConfig{Query: query, Mutation: mutation, Subscription: subscription})
+}
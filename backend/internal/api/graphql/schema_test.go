@@ -0,0 +1,261 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+	"github.com/google/uuid"
+	gql "github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchemaTestSuite struct {
+	suite.Suite
+	projectService      *mockService.MockProjectService
+	tableService        *mockService.MockTableService
+	fieldService        *mockService.MockFieldService
+	relationshipService *mockService.MockRelationshipService
+	authService         *mockService.MockAuthorizationService
+	hub                 *websocketPkg.Hub
+	schema              gql.Schema
+	userID              uuid.UUID
+	ctx                 context.Context
+}
+
+func (suite *SchemaTestSuite) SetupTest() {
+	suite.projectService = new(mockService.MockProjectService)
+	suite.tableService = new(mockService.MockTableService)
+	suite.fieldService = new(mockService.MockFieldService)
+	suite.relationshipService = new(mockService.MockRelationshipService)
+	suite.authService = new(mockService.MockAuthorizationService)
+	suite.hub = websocketPkg.NewHub()
+
+	schema, err := NewSchema(NewResolver(suite.projectService, suite.tableService, suite.fieldService,
+		suite.relationshipService, suite.authService, suite.hub))
+	suite.Require().NoError(err)
+	suite.schema = schema
+
+	suite.userID = uuid.New()
+	suite.ctx = testutil.WithUserContext(httptest.NewRequest("POST", "/graphql", nil), suite.userID).Context()
+}
+
+func TestSchemaSuite(t *testing.T) {
+	suite.Run(t, new(SchemaTestSuite))
+}
+
+// execute runs a request and returns its result as JSON, the way clients see it
+func (suite *SchemaTestSuite) execute(ctx context.Context, query string, variables map[string]any) map[string]any {
+	result := Execute(ctx, suite.schema, dto.GraphQLRequest{Query: query, Variables: variables})
+	body, err := json.Marshal(result)
+	suite.Require().NoError(err)
+
+	var decoded map[string]any
+	suite.Require().NoError(json.Unmarshal(body, &decoded))
+	return decoded
+}
+
+func (suite *SchemaTestSuite) errorCode(response map[string]any) string {
+	errs, ok := response["errors"].([]any)
+	suite.Require().True(ok, "expected errors, got %v", response)
+	extensions, _ := errs[0].(map[string]any)["extensions"].(map[string]any)
+	code, _ := extensions["code"].(string)
+	return code
+}
+
+func (suite *SchemaTestSuite) TestProject_NestedSchema() {
+	project := testutil.CreateTestProject(suite.userID)
+	table := testutil.CreateTestTable(project.ID)
+	field := testutil.CreateTestField(table.ID)
+	table.Fields = []models.Field{*field}
+	project.Tables = []models.Table{*table}
+
+	suite.authService.On("CanUserAccessProject", suite.userID, project.ID).Return(true, nil)
+	suite.projectService.On("GetProjectByID", project.ID).Return(project, nil)
+
+	response := suite.execute(suite.ctx, `query($id: ID!) {
+		project(id: $id) { id name tables { name fields { name data_type } } }
+	}`, map[string]any{"id": project.ID.String()})
+
+	suite.Nil(response["errors"])
+	data := response["data"].(map[string]any)["project"].(map[string]any)
+	suite.Equal(project.ID.String(), data["id"])
+	tables := data["tables"].([]any)
+	suite.Require().Len(tables, 1)
+	fields := tables[0].(map[string]any)["fields"].([]any)
+	suite.Require().Len(fields, 1)
+	suite.Equal("test_field", fields[0].(map[string]any)["name"])
+
+	// Preloaded tables and fields are used without further lookups
+	suite.tableService.AssertNotCalled(suite.T(), "GetTablesByProjectID", mock.Anything)
+	suite.fieldService.AssertNotCalled(suite.T(), "GetFieldsByTableID", mock.Anything)
+}
+
+func (suite *SchemaTestSuite) TestProject_Forbidden() {
+	projectID := uuid.New()
+	suite.authService.On("CanUserAccessProject", suite.userID, projectID).Return(false, nil)
+
+	response := suite.execute(suite.ctx, `query($id: ID!) { project(id: $id) { id } }`,
+		map[string]any{"id": projectID.String()})
+
+	suite.Equal(CodeForbidden, suite.errorCode(response))
+	suite.projectService.AssertNotCalled(suite.T(), "GetProjectByID", mock.Anything)
+}
+
+func (suite *SchemaTestSuite) TestProject_NotFound() {
+	projectID := uuid.New()
+	suite.authService.On("CanUserAccessProject", suite.userID, projectID).Return(true, nil)
+	suite.projectService.On("GetProjectByID", projectID).Return(nil, services.ErrProjectNotFound)
+
+	response := suite.execute(suite.ctx, `query($id: ID!) { project(id: $id) { id } }`,
+		map[string]any{"id": projectID.String()})
+
+	suite.Equal(CodeNotFound, suite.errorCode(response))
+}
+
+func (suite *SchemaTestSuite) TestUnauthenticated() {
+	response := suite.execute(context.Background(), `{ projects { id } }`, nil)
+
+	suite.Equal(CodeUnauthenticated, suite.errorCode(response))
+}
+
+func (suite *SchemaTestSuite) TestProjects_DeduplicatesOwnedAndShared() {
+	owned := testutil.CreateTestProject(suite.userID)
+	shared := testutil.CreateTestProject(uuid.New())
+
+	suite.projectService.On("GetProjectsByOwnerID", suite.userID, repository.ProjectIncludes{}).Return([]*models.Project{owned}, nil)
+	suite.projectService.On("GetProjectsByCollaboratorID", suite.userID, repository.ProjectIncludes{}).Return([]*models.Project{owned, shared}, nil)
+
+	response := suite.execute(suite.ctx, `{ projects { id } }`, nil)
+
+	suite.Nil(response["errors"])
+	suite.Len(response["data"].(map[string]any)["projects"], 2)
+}
+
+func (suite *SchemaTestSuite) TestCreateTable_ValidatesInput() {
+	projectID := uuid.New()
+	suite.authService.On("CanUserModifyProject", suite.userID, projectID).Return(true, nil)
+
+	response := suite.execute(suite.ctx, `mutation($project: ID!) {
+		create_table(project_id: $project, input: {name: ""}) { id }
+	}`, map[string]any{"project": projectID.String()})
+
+	suite.Equal(CodeBadUserInput, suite.errorCode(response))
+	extensions := response["errors"].([]any)[0].(map[string]any)["extensions"].(map[string]any)
+	suite.Contains(extensions["fields"], "name")
+	suite.tableService.AssertNotCalled(suite.T(), "CreateTable", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *SchemaTestSuite) TestCreateTable_Success() {
+	projectID := uuid.New()
+	table := testutil.CreateTestTable(projectID)
+	suite.authService.On("CanUserModifyProject", suite.userID, projectID).Return(true, nil)
+	suite.tableService.On("CreateTable", projectID, &dto.CreateTableRequest{Name: "users", PosX: 10, PosY: 20}, suite.userID).Return(table, nil)
+
+	response := suite.execute(suite.ctx, `mutation($project: ID!) {
+		create_table(project_id: $project, input: {name: "users", pos_x: 10, pos_y: 20}) { id }
+	}`, map[string]any{"project": projectID.String()})
+
+	suite.Nil(response["errors"])
+	suite.Equal(table.ID.String(), response["data"].(map[string]any)["create_table"].(map[string]any)["id"])
+	suite.tableService.AssertExpectations(suite.T())
+}
+
+func (suite *SchemaTestSuite) TestMutation_ReadOnlyTokenRejected() {
+	projectID := uuid.New()
+	ctx := context.WithValue(suite.ctx, "apiTokenScopes", []string{services.ScopeReadProjects})
+
+	response := suite.execute(ctx, `mutation($project: ID!) {
+		create_table(project_id: $project, input: {name: "users"}) { id }
+	}`, map[string]any{"project": projectID.String()})
+
+	suite.Equal(CodeForbidden, suite.errorCode(response))
+	suite.authService.AssertNotCalled(suite.T(), "CanUserModifyProject", mock.Anything, mock.Anything)
+}
+
+func (suite *SchemaTestSuite) TestDeleteProject_OnlyOwner() {
+	project := testutil.CreateTestProject(uuid.New())
+	suite.authService.On("CanUserModifyProject", suite.userID, project.ID).Return(true, nil)
+	suite.projectService.On("GetProjectByID", project.ID).Return(project, nil)
+
+	response := suite.execute(suite.ctx, `mutation($id: ID!) { delete_project(id: $id) }`,
+		map[string]any{"id": project.ID.String()})
+
+	suite.Equal(CodeForbidden, suite.errorCode(response))
+	suite.projectService.AssertNotCalled(suite.T(), "DeleteProject", mock.Anything)
+}
+
+func (suite *SchemaTestSuite) TestDeleteProject_TokenRejected() {
+	projectID := uuid.New()
+	ctx := context.WithValue(suite.ctx, "apiTokenScopes", []string{services.ScopeWriteSchema})
+
+	response := suite.execute(ctx, `mutation($id: ID!) { delete_project(id: $id) }`,
+		map[string]any{"id": projectID.String()})
+
+	suite.Equal(CodeForbidden, suite.errorCode(response))
+	suite.projectService.AssertNotCalled(suite.T(), "DeleteProject", mock.Anything)
+}
+
+func (suite *SchemaTestSuite) TestDepthLimit() {
+	query := "{ project(id: \"" + uuid.NewString() + "\") " +
+		strings.Repeat("{ relationships { source_table ", 5) + "{ id }" + strings.Repeat(" } }", 5) + " }"
+
+	response := suite.execute(suite.ctx, query, nil)
+
+	suite.Equal(CodeBadUserInput, suite.errorCode(response))
+	suite.authService.AssertNotCalled(suite.T(), "CanUserAccessProject", mock.Anything, mock.Anything)
+}
+
+func (suite *SchemaTestSuite) TestSubscriptionOverHTTPRejected() {
+	response := suite.execute(suite.ctx, `subscription { project_events(project_id: "`+uuid.NewString()+`") { type } }`, nil)
+
+	suite.Equal(CodeBadUserInput, suite.errorCode(response))
+}
+
+func (suite *SchemaTestSuite) TestSubscribe_ProjectEvents() {
+	projectID := uuid.New()
+	suite.authService.On("CanUserAccessProject", suite.userID, projectID).Return(true, nil)
+
+	ctx, cancel := context.WithCancel(suite.ctx)
+	results := Subscribe(ctx, suite.schema, dto.GraphQLRequest{
+		Query:     `subscription($id: ID!) { project_events(project_id: $id, types: ["table_created"]) { type project_id } }`,
+		Variables: map[string]any{"id": projectID.String()},
+	})
+
+	// Observing starts once the subscription resolver has run
+	suite.Eventually(func() bool {
+		return suite.hub.Stats().Observers > 0
+	}, time.Second, 10*time.Millisecond)
+
+	skipped, err := websocketPkg.NewWebSocketMessage(websocketPkg.MessageTypeTableUpdated, nil, suite.userID, projectID)
+	suite.Require().NoError(err)
+	created, err := websocketPkg.NewWebSocketMessage(websocketPkg.MessageTypeTableCreated, nil, suite.userID, projectID)
+	suite.Require().NoError(err)
+	suite.hub.BroadcastToProject(projectID, skipped, nil)
+	suite.hub.BroadcastToProject(projectID, created, nil)
+
+	select {
+	case result := <-results:
+		suite.Empty(result.Errors)
+		event := result.Data.(map[string]any)["project_events"].(map[string]any)
+		suite.Equal("table_created", event["type"])
+		suite.Equal(projectID.String(), event["project_id"])
+	case <-time.After(time.Second):
+		suite.Fail("no event received")
+	}
+
+	cancel()
+	for range results {
+	}
+}
@@ -0,0 +1,61 @@
+package graphql
+
+import (
+	"slices"
+
+	gql "github.com/graphql-go/graphql"
+)
+
+// subscribeProjectEvents bridges the hub's broadcasts for a project to a
+// subscription. The observer is released when the subscription's context ends.
+func (r *Resolver) subscribeProjectEvents(p gql.ResolveParams) (any, error) {
+	projectID, err := parseID(p.Args, "project_id")
+	if err != nil {
+		return nil, err
+	}
+	userID, err := r.authorize(p.Context, projectID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var types []string
+	if list, ok := p.Args["types"].([]any); ok {
+		for _, t := range list {
+			if messageType, ok := t.(string); ok {
+				types = append(types, messageType)
+			}
+		}
+	}
+
+	messages, cancel := r.hub.Observe(projectID, userID)
+	events := make(chan any)
+	go func() {
+		defer close(events)
+		defer cancel()
+
+		for {
+			select {
+			case <-p.Context.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				if len(types) > 0 && !slices.Contains(types, string(message.Type)) {
+					continue
+				}
+				select {
+				case events <- message:
+				case <-p.Context.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+// projectEvent resolves each event to the WebSocket message it came from
+func (r *Resolver) projectEvent(p gql.ResolveParams) (any, error) {
+	return p.Source, nil
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type AccountDeletionHandler struct {
+	accountDeletionService services.AccountDeletionServiceInterface
+	cfg                    *config.Config
+}
+
+func NewAccountDeletionHandler(accountDeletionService services.AccountDeletionServiceInterface, cfg *config.Config) *AccountDeletionHandler {
+	return &AccountDeletionHandler{
+		accountDeletionService: accountDeletionService,
+		cfg:                    cfg,
+	}
+}
+
+// DeleteMe deletes the current user's account and signs them out. Responds with
+// 409 listing the shared projects that need a new owner or explicit deletion.
+func (h *AccountDeletionHandler) DeleteMe() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+		sessionID, _ := middleware.GetSessionIDFromContext(r.Context())
+
+		var req dto.DeleteAccountRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		shared, err := h.accountDeletionService.DeleteAccount(r.Context(), userID, sessionID, &req)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrTransferRequired):
+				response := make([]dto.SharedProjectResponse, len(shared))
+				for i, project := range shared {
+					response[i] = dto.SharedProjectResponse{ID: project.ID, Name: project.Name, Collaborators: []dto.UserResponse{}}
+					for _, collaborator := range project.Collaborators {
+						response[i].Collaborators = append(response[i].Collaborators, dto.UserResponse{
+							ID:        collaborator.ID,
+							Email:     collaborator.Email,
+							Username:  collaborator.Username,
+							AvatarURL: collaborator.AvatarURL,
+						})
+					}
+				}
+				responses.RespondWithErrorData(w, http.StatusConflict, "Transfer or delete your shared projects first", response)
+			case errors.Is(err, services.ErrInvalidCredentials):
+				responses.RespondWithError(w, http.StatusBadRequest, "Password or email confirmation is incorrect")
+			case errors.Is(err, services.ErrInvalidInput):
+				responses.RespondWithError(w, http.StatusBadRequest, "Projects can only be transferred to one of their collaborators")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "Accounts cannot be deleted while impersonated")
+			case errors.Is(err, services.ErrUserNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "User not found")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to delete account")
+			}
+			return
+		}
+
+		clearAuthCookies(w, h.cfg)
+		responses.RespondWithSuccess(w, http.StatusOK, "Account deleted successfully", nil)
+	}
+}
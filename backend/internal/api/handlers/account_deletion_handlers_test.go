@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AccountDeletionHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockAccountDeletionService
+	handler     *AccountDeletionHandler
+	userID      uuid.UUID
+	sessionID   uuid.UUID
+}
+
+func (suite *AccountDeletionHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockAccountDeletionService)
+	suite.handler = NewAccountDeletionHandler(suite.mockService, &config.Config{})
+	suite.userID = uuid.New()
+	suite.sessionID = uuid.New()
+}
+
+func TestAccountDeletionHandlerSuite(t *testing.T) {
+	suite.Run(t, new(AccountDeletionHandlerTestSuite))
+}
+
+func (suite *AccountDeletionHandlerTestSuite) deleteRequest(body dto.DeleteAccountRequest) *http.Request {
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodDelete, "/users/me", body)
+	return testutil.WithSessionContext(testutil.WithUserContext(req, suite.userID), suite.sessionID)
+}
+
+// Test DeleteMe - Deletes the account and clears the auth cookies
+func (suite *AccountDeletionHandlerTestSuite) TestDeleteMe_Success() {
+	suite.mockService.On("DeleteAccount", mock.Anything, suite.userID, suite.sessionID, mock.MatchedBy(func(req *dto.DeleteAccountRequest) bool {
+		return req.Password == "secret123"
+	})).Return(nil, nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.DeleteMe()(w, suite.deleteRequest(dto.DeleteAccountRequest{Password: "secret123"}))
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Account deleted successfully")
+	cookies := w.Header().Values("Set-Cookie")
+	assert.Len(suite.T(), cookies, 3)
+	for _, cookie := range cookies {
+		assert.True(suite.T(), strings.Contains(cookie, "Max-Age=0"), cookie)
+	}
+}
+
+// Test DeleteMe - Shared projects are listed with their collaborators
+func (suite *AccountDeletionHandlerTestSuite) TestDeleteMe_TransferRequired() {
+	project := &models.Project{ID: uuid.New(), Name: "Shop", Collaborators: []models.User{{ID: uuid.New(), Username: "bob"}}}
+	suite.mockService.On("DeleteAccount", mock.Anything, suite.userID, suite.sessionID, mock.Anything).
+		Return([]*models.Project{project}, services.ErrTransferRequired)
+	w := httptest.NewRecorder()
+
+	suite.handler.DeleteMe()(w, suite.deleteRequest(dto.DeleteAccountRequest{Password: "secret123"}))
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "Transfer or delete your shared projects first")
+	data, ok := response.Data.([]any)
+	suite.Require().True(ok)
+	suite.Require().Len(data, 1)
+	shared := data[0].(map[string]any)
+	assert.Equal(suite.T(), "Shop", shared["name"])
+	assert.Len(suite.T(), shared["collaborators"], 1)
+	assert.Empty(suite.T(), w.Header().Values("Set-Cookie"))
+}
+
+// Test DeleteMe - A wrong password is a bad request, not a sign-out
+func (suite *AccountDeletionHandlerTestSuite) TestDeleteMe_WrongPassword() {
+	suite.mockService.On("DeleteAccount", mock.Anything, suite.userID, suite.sessionID, mock.Anything).Return(nil, services.ErrInvalidCredentials)
+	w := httptest.NewRecorder()
+
+	suite.handler.DeleteMe()(w, suite.deleteRequest(dto.DeleteAccountRequest{Password: "guess"}))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Password or email confirmation is incorrect")
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+)
+
+// defaultStatsDays is how many days of signups the stats report without ?days=
+const defaultStatsDays = 30
+
+type AdminHandler struct {
+	hub          *websocketPkg.Hub
+	statsService services.AdminStatsServiceInterface
+}
+
+func NewAdminHandler(hub *websocketPkg.Hub, statsService services.AdminStatsServiceInterface) *AdminHandler {
+	return &AdminHandler{
+		hub:          hub,
+		statsService: statsService,
+	}
+}
+
+// WebSocketStats returns a snapshot of WebSocket hub health
+func (h *AdminHandler) WebSocketStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		responses.RespondWithSuccess(w, http.StatusOK, "WebSocket stats retrieved successfully", h.hub.Stats())
+	}
+}
+
+// InstanceStats returns instance-wide aggregates for an ops dashboard. Supports ?days= for the signups, 30 by default.
+func (h *AdminHandler) InstanceStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		days := defaultStatsDays
+		if value := r.URL.Query().Get("days"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid days")
+				return
+			}
+			days = parsed
+		}
+
+		stats, err := h.statsService.GetStats(r.Context(), days)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidInput) {
+				responses.RespondWithError(w, http.StatusBadRequest, "Days must be between 1 and "+strconv.Itoa(services.MaxStatsDays))
+			} else {
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve stats")
+			}
+			return
+		}
+
+		response := dto.InstanceStatsResponse{
+			Users:         stats.Users,
+			Projects:      stats.Projects,
+			DatabaseBytes: stats.DatabaseBytes,
+			Tenants:       make([]dto.TenantUsageResponse, len(stats.Tenants)),
+			Signups:       make([]dto.DailySignupsResponse, len(stats.Signups)),
+			GeneratedAt:   stats.GeneratedAt,
+		}
+		// Connections are live rather than cached with the rest
+		if h.hub != nil {
+			response.ActiveConnections = h.hub.Stats().TotalConnections
+		}
+		for i, tenant := range stats.Tenants {
+			response.Tenants[i] = dto.TenantUsageResponse{
+				OwnerID:  tenant.OwnerID,
+				Username: tenant.Username,
+				Projects: tenant.Projects,
+				Tables:   tenant.Tables,
+				Fields:   tenant.Fields,
+				Bytes:    tenant.Bytes,
+			}
+		}
+		for i, signups := range stats.Signups {
+			response.Signups[i] = dto.DailySignupsResponse{Date: signups.Day.Format("2006-01-02"), Count: signups.Count}
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Stats retrieved successfully", response)
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AdminHandlerTestSuite struct {
+	suite.Suite
+	mockStatsService *mockService.MockAdminStatsService
+	handler          *AdminHandler
+}
+
+func (suite *AdminHandlerTestSuite) SetupTest() {
+	suite.mockStatsService = new(mockService.MockAdminStatsService)
+	suite.handler = NewAdminHandler(websocketPkg.NewHub(), suite.mockStatsService)
+}
+
+func TestAdminHandlerSuite(t *testing.T) {
+	suite.Run(t, new(AdminHandlerTestSuite))
+}
+
+// Test InstanceStats - Default of 30 days
+func (suite *AdminHandlerTestSuite) TestInstanceStats_Success() {
+	ownerID := uuid.New()
+	generatedAt := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+	suite.mockStatsService.On("GetStats", mock.Anything, 30).Return(&services.InstanceStats{
+		Users:         12,
+		Projects:      30,
+		DatabaseBytes: 2048,
+		Tenants:       []*repository.OwnerStorage{{OwnerID: ownerID, Username: "ada", Projects: 3, Tables: 8, Fields: 40, Bytes: 1024}},
+		Signups:       []repository.DailyCount{{Day: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), Count: 4}},
+		GeneratedAt:   generatedAt,
+	}, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.InstanceStats()(w, httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Stats retrieved successfully")
+	suite.Equal(map[string]any{
+		"users":              float64(12),
+		"projects":           float64(30),
+		"active_connections": float64(0),
+		"database_bytes":     float64(2048),
+		"tenants": []any{map[string]any{
+			"owner_id": ownerID.String(),
+			"username": "ada",
+			"projects": float64(3),
+			"tables":   float64(8),
+			"fields":   float64(40),
+			"bytes":    float64(1024),
+		}},
+		"signups":      []any{map[string]any{"date": "2026-03-10", "count": float64(4)}},
+		"generated_at": "2026-03-10T15:30:00Z",
+	}, response.Data)
+}
+
+// Test InstanceStats - Days given in the query
+func (suite *AdminHandlerTestSuite) TestInstanceStats_Days() {
+	suite.mockStatsService.On("GetStats", mock.Anything, 7).Return(&services.InstanceStats{}, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.InstanceStats()(w, httptest.NewRequest(http.MethodGet, "/admin/stats?days=7", nil))
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.mockStatsService.AssertExpectations(suite.T())
+}
+
+// Test InstanceStats - Invalid days
+func (suite *AdminHandlerTestSuite) TestInstanceStats_InvalidDays() {
+	suite.mockStatsService.On("GetStats", mock.Anything, 0).Return(nil, services.ErrInvalidInput)
+
+	for _, query := range []string{"?days=week", "?days=0"} {
+		w := httptest.NewRecorder()
+		suite.handler.InstanceStats()(w, httptest.NewRequest(http.MethodGet, "/admin/stats"+query, nil))
+
+		suite.Equal(http.StatusBadRequest, w.Code)
+	}
+}
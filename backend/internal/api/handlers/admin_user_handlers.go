@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+)
+
+type AdminUserHandler struct {
+	adminUserService services.AdminUserServiceInterface
+	jwtService       services.JWTServiceInterface
+	cfg              *config.Config
+}
+
+func NewAdminUserHandler(adminUserService services.AdminUserServiceInterface, jwtService services.JWTServiceInterface, cfg *config.Config) *AdminUserHandler {
+	return &AdminUserHandler{
+		adminUserService: adminUserService,
+		jwtService:       jwtService,
+		cfg:              cfg,
+	}
+}
+
+// List returns a page of users with the total number of matches. Supports ?q=, ?role= and ?disabled=true|false.
+func (h *AdminUserHandler) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, ok := utils.ParsePageQuery(w, r)
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		filter := repository.UserFilter{
+			Query: query.Get("q"),
+			Role:  query.Get("role"),
+		}
+		if disabled := query.Get("disabled"); disabled != "" {
+			value, err := strconv.ParseBool(disabled)
+			if err != nil {
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid disabled filter")
+				return
+			}
+			filter.Disabled = &value
+		}
+
+		users, next, total, err := h.adminUserService.ListUsers(filter, page)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidRole) {
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid role")
+			} else {
+				utils.RespondWithPageError(w, err, "Failed to retrieve users")
+			}
+			return
+		}
+
+		response := make([]dto.AdminUserResponse, len(users))
+		for i, user := range users {
+			response[i] = adminUserResponse(user)
+		}
+
+		responses.RespondWithPage(w, "Users retrieved successfully", response, dto.PageMeta{NextCursor: next, Total: &total})
+	}
+}
+
+// Disable disables an account and signs it out everywhere
+func (h *AdminUserHandler) Disable() http.HandlerFunc {
+	return h.setDisabled(true)
+}
+
+// Enable re-enables a disabled account
+func (h *AdminUserHandler) Enable() http.HandlerFunc {
+	return h.setDisabled(false)
+}
+
+func (h *AdminUserHandler) setDisabled(disabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actorID, targetID, ok := h.actorAndTarget(w, r)
+		if !ok {
+			return
+		}
+
+		var req dto.SetUserDisabledRequest
+		if r.ContentLength != 0 && !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		user, err := h.adminUserService.SetUserDisabled(actorID, targetID, disabled, req.Reason, h.clientIP(r))
+		if err != nil {
+			h.respondWithError(w, err, "Failed to update user")
+			return
+		}
+
+		message := "User enabled successfully"
+		if disabled {
+			message = "User disabled successfully"
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, message, adminUserResponse(user))
+	}
+}
+
+// SetRole grants or removes the admin role
+func (h *AdminUserHandler) SetRole() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actorID, targetID, ok := h.actorAndTarget(w, r)
+		if !ok {
+			return
+		}
+
+		var req dto.SetUserRoleRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		user, err := h.adminUserService.SetUserRole(actorID, targetID, req.Role, h.clientIP(r))
+		if err != nil {
+			h.respondWithError(w, err, "Failed to update user role")
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "User role updated successfully", adminUserResponse(user))
+	}
+}
+
+// ForcePasswordReset signs the user out and requires a new password
+func (h *AdminUserHandler) ForcePasswordReset() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actorID, targetID, ok := h.actorAndTarget(w, r)
+		if !ok {
+			return
+		}
+
+		user, err := h.adminUserService.ForcePasswordReset(actorID, targetID, h.clientIP(r))
+		if err != nil {
+			h.respondWithError(w, err, "Failed to force password reset")
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Password reset required for user", adminUserResponse(user))
+	}
+}
+
+// Impersonate signs the admin in as the user for a short, audited support session.
+// The session cookies replace the admin's own, as with a regular login.
+func (h *AdminUserHandler) Impersonate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actorID, targetID, ok := h.actorAndTarget(w, r)
+		if !ok {
+			return
+		}
+
+		var req dto.ImpersonateUserRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		user, tokens, err := h.adminUserService.Impersonate(actorID, targetID, req.Reason, h.clientIP(r), r.UserAgent())
+		if err != nil {
+			h.respondWithError(w, err, "Failed to impersonate user")
+			return
+		}
+
+		setAuthCookies(w, h.cfg, h.jwtService, tokens)
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Impersonation session started", adminUserResponse(user))
+	}
+}
+
+// AuditLogs returns a page of admin actions, most recent first. Supports ?user_id=,
+// ?actor_id= and ?action= filters.
+func (h *AdminUserHandler) AuditLogs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, ok := utils.ParsePageQuery(w, r)
+		if !ok {
+			return
+		}
+		targetUserID, ok := utils.ParseUUIDQuery(w, r, "user_id", "Invalid user ID format")
+		if !ok {
+			return
+		}
+		actorID, ok := utils.ParseUUIDQuery(w, r, "actor_id", "Invalid actor ID format")
+		if !ok {
+			return
+		}
+
+		filter := repository.AuditLogFilter{
+			ActorID:      actorID,
+			TargetUserID: targetUserID,
+			Action:       r.URL.Query().Get("action"),
+		}
+		entries, next, err := h.adminUserService.GetAuditLogs(filter, page)
+		if err != nil {
+			utils.RespondWithPageError(w, err, "Failed to retrieve audit logs")
+			return
+		}
+
+		response := make([]dto.AdminAuditLogResponse, len(entries))
+		for i, entry := range entries {
+			response[i] = dto.AdminAuditLogResponse{
+				ID:           entry.ID,
+				ActorID:      entry.ActorID,
+				Action:       entry.Action,
+				TargetUserID: entry.TargetUserID,
+				Details:      entry.Details,
+				IPAddress:    entry.IPAddress,
+				CreatedAt:    entry.CreatedAt,
+			}
+		}
+
+		responses.RespondWithPage(w, "Audit logs retrieved successfully", response, dto.PageMeta{NextCursor: next})
+	}
+}
+
+func (h *AdminUserHandler) actorAndTarget(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	actorID, ok := currentUserID(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	targetID, ok := utils.ParseUUIDParam(w, r, "user_id")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return actorID, targetID, true
+}
+
+func (h *AdminUserHandler) clientIP(r *http.Request) string {
+	return utils.ClientIP(r, h.cfg.RateLimit.TrustProxy)
+}
+
+func (h *AdminUserHandler) respondWithError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "User not found")
+	case errors.Is(err, services.ErrCannotModifySelf):
+		responses.RespondWithError(w, http.StatusBadRequest, "Admins cannot perform this action on their own account")
+	case errors.Is(err, services.ErrCannotImpersonateAdmin):
+		responses.RespondWithError(w, http.StatusForbidden, "Admins cannot be impersonated")
+	case errors.Is(err, services.ErrAccountDisabled):
+		responses.RespondWithError(w, http.StatusConflict, "Account is disabled")
+	case errors.Is(err, services.ErrInvalidRole), errors.Is(err, services.ErrInvalidInput):
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, fallback)
+	}
+}
+
+func adminUserResponse(user *models.User) dto.AdminUserResponse {
+	return dto.AdminUserResponse{
+		ID:                    user.ID,
+		Email:                 user.Email,
+		Username:              user.Username,
+		Role:                  user.Role,
+		IsServiceAccount:      user.IsServiceAccount,
+		DisabledAt:            user.DisabledAt,
+		PasswordResetRequired: user.PasswordResetRequired,
+		CreatedAt:             user.CreatedAt,
+	}
+}
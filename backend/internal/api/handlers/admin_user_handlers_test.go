@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AdminUserHandlerTestSuite struct {
+	suite.Suite
+	mockService    *mockService.MockAdminUserService
+	mockJWTService *mockService.MockJWTService
+	handler        *AdminUserHandler
+	adminID        uuid.UUID
+	user           *models.User
+}
+
+func (suite *AdminUserHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockAdminUserService)
+	suite.mockJWTService = new(mockService.MockJWTService)
+	suite.handler = NewAdminUserHandler(suite.mockService, suite.mockJWTService, config.New())
+	suite.adminID = uuid.New()
+	suite.user = testutil.CreateTestUser()
+	suite.user.Role = models.RoleUser
+}
+
+func TestAdminUserHandlerSuite(t *testing.T) {
+	suite.Run(t, new(AdminUserHandlerTestSuite))
+}
+
+// adminRequest builds a request made by the admin against the given target user
+func (suite *AdminUserHandlerTestSuite) adminRequest(req *http.Request, userID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("user_id", userID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return testutil.WithUserContext(req, suite.adminID)
+}
+
+// Test List - Passes filters and the page to the service
+func (suite *AdminUserHandlerTestSuite) TestList_Success() {
+	disabled := true
+	filter := repository.UserFilter{Query: "alice", Role: models.RoleUser, Disabled: &disabled}
+	page := repository.PageQuery{Limit: 10, Cursor: "abc", Sort: "-username"}
+	suite.mockService.On("ListUsers", filter, page).Return([]*models.User{suite.user}, "next", int64(21), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users?q=alice&role=user&disabled=true&limit=10&cursor=abc&sort=-username", nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.List()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Users retrieved successfully")
+	suite.Require().NotNil(response.Meta)
+	suite.Equal("next", response.Meta.NextCursor)
+	suite.Equal(int64(21), *response.Meta.Total)
+	users := response.Data.([]any)
+	suite.Require().Len(users, 1)
+	suite.Equal(suite.user.Email, users[0].(map[string]any)["email"])
+	suite.NotContains(users[0], "password_hash")
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Test List - Invalid query parameters
+func (suite *AdminUserHandlerTestSuite) TestList_InvalidParams() {
+	cases := map[string]string{
+		"?limit=0":        "Invalid limit",
+		"?limit=x":        "Invalid limit",
+		"?disabled=maybe": "Invalid disabled filter",
+	}
+	for query, message := range cases {
+		w := httptest.NewRecorder()
+		suite.handler.List()(w, httptest.NewRequest(http.MethodGet, "/api/admin/users"+query, nil))
+		testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, message)
+	}
+
+	suite.mockService.On("ListUsers", repository.UserFilter{Role: "owner"}, mock.Anything).Return(nil, "", int64(0), services.ErrInvalidRole)
+	w := httptest.NewRecorder()
+	suite.handler.List()(w, httptest.NewRequest(http.MethodGet, "/api/admin/users?role=owner", nil))
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid role")
+
+	suite.mockService.On("ListUsers", repository.UserFilter{}, mock.Anything).Return(nil, "", int64(0), repository.ErrInvalidCursor)
+	w = httptest.NewRecorder()
+	suite.handler.List()(w, httptest.NewRequest(http.MethodGet, "/api/admin/users?cursor=stale", nil))
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid cursor")
+}
+
+// Test Disable - Reason is optional and forwarded
+func (suite *AdminUserHandlerTestSuite) TestDisable_Success() {
+	disabledAt := time.Now()
+	suite.user.DisabledAt = &disabledAt
+	suite.mockService.On("SetUserDisabled", suite.adminID, suite.user.ID, true, "spam", mock.AnythingOfType("string")).Return(suite.user, nil)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/api/admin/users/"+suite.user.ID.String()+"/disable", dto.SetUserDisabledRequest{Reason: "spam"})
+	w := httptest.NewRecorder()
+
+	suite.handler.Disable()(w, suite.adminRequest(req, suite.user.ID.String()))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "User disabled successfully")
+	suite.NotNil(response.Data.(map[string]any)["disabled_at"])
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Test Enable - Works without a body
+func (suite *AdminUserHandlerTestSuite) TestEnable_Success() {
+	suite.mockService.On("SetUserDisabled", suite.adminID, suite.user.ID, false, "", mock.AnythingOfType("string")).Return(suite.user, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/"+suite.user.ID.String()+"/enable", nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Enable()(w, suite.adminRequest(req, suite.user.ID.String()))
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "User enabled successfully")
+}
+
+// Test Disable - Service errors map to status codes
+func (suite *AdminUserHandlerTestSuite) TestDisable_Errors() {
+	cases := []struct {
+		err     error
+		status  int
+		message string
+	}{
+		{services.ErrUserNotFound, http.StatusNotFound, "User not found"},
+		{services.ErrCannotModifySelf, http.StatusBadRequest, "Admins cannot perform this action on their own account"},
+		{assert.AnError, http.StatusInternalServerError, "Failed to update user"},
+	}
+	for _, tc := range cases {
+		suite.SetupTest()
+		suite.mockService.On("SetUserDisabled", suite.adminID, suite.user.ID, true, "", mock.Anything).Return(nil, tc.err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/users/"+suite.user.ID.String()+"/disable", nil)
+		w := httptest.NewRecorder()
+
+		suite.handler.Disable()(w, suite.adminRequest(req, suite.user.ID.String()))
+
+		testutil.AssertErrorResponse(suite.T(), w, tc.status, tc.message)
+	}
+}
+
+// Test Disable - Invalid user ID
+func (suite *AdminUserHandlerTestSuite) TestDisable_InvalidUserID() {
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/invalid/disable", nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Disable()(w, suite.adminRequest(req, "invalid"))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid user ID format")
+}
+
+// Test SetRole - Success and validation
+func (suite *AdminUserHandlerTestSuite) TestSetRole() {
+	suite.user.Role = models.RoleAdmin
+	suite.mockService.On("SetUserRole", suite.adminID, suite.user.ID, models.RoleAdmin, mock.AnythingOfType("string")).Return(suite.user, nil)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/api/admin/users/"+suite.user.ID.String()+"/role", dto.SetUserRoleRequest{Role: models.RoleAdmin})
+	w := httptest.NewRecorder()
+	suite.handler.SetRole()(w, suite.adminRequest(req, suite.user.ID.String()))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "User role updated successfully")
+	suite.Equal(models.RoleAdmin, response.Data.(map[string]any)["role"])
+
+	req = testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/api/admin/users/"+suite.user.ID.String()+"/role", dto.SetUserRoleRequest{Role: "owner"})
+	w = httptest.NewRecorder()
+	suite.handler.SetRole()(w, suite.adminRequest(req, suite.user.ID.String()))
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNumberOfCalls(suite.T(), "SetUserRole", 1)
+}
+
+// Test ForcePasswordReset - Success
+func (suite *AdminUserHandlerTestSuite) TestForcePasswordReset_Success() {
+	suite.user.PasswordResetRequired = true
+	suite.mockService.On("ForcePasswordReset", suite.adminID, suite.user.ID, mock.AnythingOfType("string")).Return(suite.user, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/users/"+suite.user.ID.String()+"/password-reset", nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.ForcePasswordReset()(w, suite.adminRequest(req, suite.user.ID.String()))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Password reset required for user")
+	suite.Equal(true, response.Data.(map[string]any)["password_reset_required"])
+}
+
+// Test Impersonate - Sets the session cookies for the impersonated user
+func (suite *AdminUserHandlerTestSuite) TestImpersonate_Success() {
+	tokens := &services.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
+	suite.mockService.On("Impersonate", suite.adminID, suite.user.ID, "ticket 42", mock.AnythingOfType("string"), "support-browser").Return(suite.user, tokens, nil)
+	suite.mockJWTService.On("GetAccessTokenExpiration").Return(15 * time.Minute)
+	suite.mockJWTService.On("GetRefreshTokenExpiration").Return(time.Hour)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/api/admin/users/"+suite.user.ID.String()+"/impersonate", dto.ImpersonateUserRequest{Reason: "ticket 42"})
+	req.Header.Set("User-Agent", "support-browser")
+	w := httptest.NewRecorder()
+
+	suite.handler.Impersonate()(w, suite.adminRequest(req, suite.user.ID.String()))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Impersonation session started")
+	suite.Equal(suite.user.ID.String(), response.Data.(map[string]any)["id"])
+	cookies := map[string]string{}
+	for _, c := range w.Result().Cookies() {
+		cookies[c.Name] = c.Value
+	}
+	suite.Equal("access", cookies["access_token"])
+	suite.Equal("refresh", cookies["refresh_token"])
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Test Impersonate - A reason is required
+func (suite *AdminUserHandlerTestSuite) TestImpersonate_MissingReason() {
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/api/admin/users/"+suite.user.ID.String()+"/impersonate", dto.ImpersonateUserRequest{})
+	w := httptest.NewRecorder()
+
+	suite.handler.Impersonate()(w, suite.adminRequest(req, suite.user.ID.String()))
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "Impersonate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Impersonate - Admin accounts cannot be impersonated
+func (suite *AdminUserHandlerTestSuite) TestImpersonate_Admin() {
+	suite.mockService.On("Impersonate", suite.adminID, suite.user.ID, "ticket 42", mock.Anything, mock.Anything).Return(nil, nil, services.ErrCannotImpersonateAdmin)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/api/admin/users/"+suite.user.ID.String()+"/impersonate", dto.ImpersonateUserRequest{Reason: "ticket 42"})
+	w := httptest.NewRecorder()
+
+	suite.handler.Impersonate()(w, suite.adminRequest(req, suite.user.ID.String()))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "Admins cannot be impersonated")
+	suite.Empty(w.Result().Cookies())
+}
+
+// Test AuditLogs - Optional filters
+func (suite *AdminUserHandlerTestSuite) TestAuditLogs() {
+	entries := []*models.AdminAuditLog{{ID: uuid.New(), ActorID: suite.adminID, Action: models.AuditActionUserImpersonated, TargetUserID: suite.user.ID, Details: "ticket 42"}}
+	filter := repository.AuditLogFilter{ActorID: &suite.adminID, TargetUserID: &suite.user.ID, Action: models.AuditActionUserImpersonated}
+	suite.mockService.On("GetAuditLogs", filter, repository.PageQuery{}).Return(entries, "", nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/audit-logs?user_id="+suite.user.ID.String()+
+		"&actor_id="+suite.adminID.String()+"&action="+models.AuditActionUserImpersonated, nil)
+	w := httptest.NewRecorder()
+	suite.handler.AuditLogs()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Audit logs retrieved successfully")
+	data := response.Data.([]any)
+	suite.Require().Len(data, 1)
+	suite.Equal(models.AuditActionUserImpersonated, data[0].(map[string]any)["action"])
+
+	req = httptest.NewRequest(http.MethodGet, "/api/admin/audit-logs?user_id=invalid", nil)
+	w = httptest.NewRecorder()
+	suite.handler.AuditLogs()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid user ID format")
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+)
+
+type APITokenHandler struct {
+	apiTokenService services.APITokenServiceInterface
+}
+
+func NewAPITokenHandler(apiTokenService services.APITokenServiceInterface) *APITokenHandler {
+	return &APITokenHandler{
+		apiTokenService: apiTokenService,
+	}
+}
+
+// Create mints a personal access token for the current user
+func (h *APITokenHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var req dto.CreateAPITokenRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		token, plaintext, err := h.apiTokenService.CreateToken(userID, req.Name, req.Scopes, req.ExpiresAt)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidScope):
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid token scope")
+			case errors.Is(err, services.ErrInvalidInput):
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+			case errors.Is(err, services.ErrUserNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "User not found")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to create API token")
+			}
+			return
+		}
+
+		response := dto.CreateAPITokenResponse{
+			APITokenResponse: apiTokenResponse(token),
+			Token:            plaintext,
+		}
+
+		responses.RespondWithSuccess(w, http.StatusCreated, "API token created successfully", response)
+	}
+}
+
+// GetMine lists the current user's tokens, including revoked and expired ones
+func (h *APITokenHandler) GetMine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		h.respondWithTokens(w, userID)
+	}
+}
+
+// Revoke revokes one of the current user's tokens
+func (h *APITokenHandler) Revoke() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		tokenID, ok := utils.ParseUUIDParamWithError(w, r, "token_id", "Invalid token ID format")
+		if !ok {
+			return
+		}
+
+		h.respondToRevoke(w, h.apiTokenService.RevokeToken(tokenID, userID))
+	}
+}
+
+// AdminGetByUserID lists any user's tokens
+func (h *APITokenHandler) AdminGetByUserID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := utils.ParseUUIDParam(w, r, "user_id")
+		if !ok {
+			return
+		}
+
+		h.respondWithTokens(w, userID)
+	}
+}
+
+// AdminRevoke revokes any user's token
+func (h *APITokenHandler) AdminRevoke() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tokenID, ok := utils.ParseUUIDParamWithError(w, r, "token_id", "Invalid token ID format")
+		if !ok {
+			return
+		}
+
+		h.respondToRevoke(w, h.apiTokenService.AdminRevokeToken(tokenID))
+	}
+}
+
+func (h *APITokenHandler) respondWithTokens(w http.ResponseWriter, userID uuid.UUID) {
+	tokens, err := h.apiTokenService.GetTokensByUserID(userID)
+	if err != nil {
+		responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve API tokens")
+		return
+	}
+
+	tokenResponses := make([]dto.APITokenResponse, len(tokens))
+	for i, token := range tokens {
+		tokenResponses[i] = apiTokenResponse(token)
+	}
+
+	responses.RespondWithSuccess(w, http.StatusOK, "API tokens retrieved successfully", tokenResponses)
+}
+
+func (h *APITokenHandler) respondToRevoke(w http.ResponseWriter, err error) {
+	if err != nil {
+		if errors.Is(err, services.ErrAPITokenNotFound) {
+			responses.RespondWithError(w, http.StatusNotFound, "API token not found")
+		} else {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to revoke API token")
+		}
+		return
+	}
+
+	responses.RespondWithSuccess(w, http.StatusOK, "API token revoked successfully", nil)
+}
+
+// currentUserID returns the authenticated user's ID, responding with an error if it is missing
+func currentUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userIDStr, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		responses.RespondWithError(w, http.StatusUnauthorized, "User context not found")
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid user ID format")
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+func apiTokenResponse(token *models.APIToken) dto.APITokenResponse {
+	return dto.APITokenResponse{
+		ID:         token.ID,
+		UserID:     token.UserID,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.ScopeList(),
+		LastUsedAt: token.LastUsedAt,
+		ExpiresAt:  token.ExpiresAt,
+		RevokedAt:  token.RevokedAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type APITokenHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockAPITokenService
+	handler     *APITokenHandler
+	userID      uuid.UUID
+}
+
+func (suite *APITokenHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockAPITokenService)
+	suite.handler = NewAPITokenHandler(suite.mockService)
+	suite.userID = uuid.New()
+}
+
+func TestAPITokenHandlerSuite(t *testing.T) {
+	suite.Run(t, new(APITokenHandlerTestSuite))
+}
+
+func (suite *APITokenHandlerTestSuite) withTokenID(req *http.Request, tokenID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("token_id", tokenID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+// Test Create - Returns the plaintext token once
+func (suite *APITokenHandlerTestSuite) TestCreate_Success() {
+	requestBody := dto.CreateAPITokenRequest{Name: "CI", Scopes: []string{services.ScopeReadProjects}}
+	token := &models.APIToken{ID: uuid.New(), UserID: suite.userID, Name: "CI", Prefix: "ezm_abcdefgh", Scopes: services.ScopeReadProjects, CreatedAt: time.Now()}
+
+	suite.mockService.On("CreateToken", suite.userID, "CI", []string{services.ScopeReadProjects}, (*time.Time)(nil)).
+		Return(token, "ezm_abcdefghsecret", nil)
+
+	req := testutil.WithUserContext(testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/api/tokens", requestBody), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.Create()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "API token created successfully")
+	data, ok := response.Data.(map[string]any)
+	suite.Require().True(ok)
+	assert.Equal(suite.T(), "ezm_abcdefghsecret", data["token"])
+	assert.Equal(suite.T(), "ezm_abcdefgh", data["prefix"])
+	assert.Equal(suite.T(), []any{services.ScopeReadProjects}, data["scopes"])
+}
+
+// Test Create - Unknown scope fails validation
+func (suite *APITokenHandlerTestSuite) TestCreate_InvalidScope() {
+	requestBody := dto.CreateAPITokenRequest{Name: "CI", Scopes: []string{"delete:everything"}}
+
+	req := testutil.WithUserContext(testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/api/tokens", requestBody), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.Create()(w, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "CreateToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test GetMine - Lists the current user's tokens without hashes
+func (suite *APITokenHandlerTestSuite) TestGetMine_Success() {
+	tokens := []*models.APIToken{{ID: uuid.New(), UserID: suite.userID, Name: "CI", TokenHash: "secret-hash", Scopes: services.ScopeReadProjects}}
+	suite.mockService.On("GetTokensByUserID", suite.userID).Return(tokens, nil)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/api/tokens", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetMine()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "API tokens retrieved successfully")
+	assert.NotContains(suite.T(), w.Body.String(), "secret-hash")
+}
+
+// Test Revoke - Token of another user is not found
+func (suite *APITokenHandlerTestSuite) TestRevoke_NotFound() {
+	tokenID := uuid.New()
+	suite.mockService.On("RevokeToken", tokenID, suite.userID).Return(services.ErrAPITokenNotFound)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodDelete, "/api/tokens/"+tokenID.String(), nil), suite.userID)
+	req = suite.withTokenID(req, tokenID.String())
+	w := httptest.NewRecorder()
+
+	suite.handler.Revoke()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusNotFound, "API token not found")
+}
+
+// Test AdminRevoke - Revokes any token
+func (suite *APITokenHandlerTestSuite) TestAdminRevoke_Success() {
+	tokenID := uuid.New()
+	suite.mockService.On("AdminRevokeToken", tokenID).Return(nil)
+
+	req := suite.withTokenID(httptest.NewRequest(http.MethodDelete, "/api/admin/tokens/"+tokenID.String(), nil), tokenID.String())
+	w := httptest.NewRecorder()
+
+	suite.handler.AdminRevoke()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "API token revoked successfully")
+	suite.mockService.AssertExpectations(suite.T())
+}
@@ -3,26 +3,33 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
 	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
 	"github.com/Bug-Bugger/ezmodel/internal/config"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
 )
 
 type AuthHandler struct {
-	userService services.UserServiceInterface
-	jwtService  services.JWTServiceInterface
-	cfg         *config.Config
+	loginSecurityService services.LoginSecurityServiceInterface
+	userSessionService   services.UserSessionServiceInterface
+	jwtService           services.JWTServiceInterface
+	cfg                  *config.Config
 }
 
-func NewAuthHandler(userService services.UserServiceInterface, jwtService services.JWTServiceInterface, cfg *config.Config) *AuthHandler {
+func NewAuthHandler(loginSecurityService services.LoginSecurityServiceInterface, userSessionService services.UserSessionServiceInterface, jwtService services.JWTServiceInterface, cfg *config.Config) *AuthHandler {
 	return &AuthHandler{
-		userService: userService,
-		jwtService:  jwtService,
-		cfg:         cfg,
+		loginSecurityService: loginSecurityService,
+		userSessionService:   userSessionService,
+		jwtService:           jwtService,
+		cfg:                  cfg,
 	}
 }
 
@@ -34,44 +41,35 @@ func (h *AuthHandler) Login() http.HandlerFunc {
 			return
 		}
 
-		user, err := h.userService.AuthenticateUser(req.Email, req.Password)
+		ipAddress := utils.ClientIP(r, h.cfg.RateLimit.TrustProxy)
+		user, err := h.loginSecurityService.Authenticate(req.Email, req.Password, ipAddress, r.UserAgent())
 		if err != nil {
-			if errors.Is(err, services.ErrInvalidCredentials) {
+			var lockoutErr *services.LockoutError
+			switch {
+			case errors.As(err, &lockoutErr):
+				retryAfter := int(math.Ceil(lockoutErr.RetryAfter.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+				responses.RespondWithError(w, http.StatusTooManyRequests, "Too many failed login attempts, please try again later")
+			case errors.Is(err, services.ErrInvalidCredentials):
 				responses.RespondWithError(w, http.StatusUnauthorized, "Wrong email or password")
-			} else {
+			default:
 				responses.RespondWithError(w, http.StatusInternalServerError, "Login failed")
 			}
 			return
 		}
 
-		// Generate JWT tokens
-		tokens, err := h.jwtService.GenerateTokenPair(user)
+		// Start a session and generate JWT tokens bound to it
+		tokens, err := h.userSessionService.StartSession(user, ipAddress, r.UserAgent())
 		if err != nil {
-			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to generate tokens")
+			if errors.Is(err, services.ErrAccountDisabled) {
+				responses.RespondWithError(w, http.StatusForbidden, "Account is disabled")
+			} else {
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to generate tokens")
+			}
 			return
 		}
 
-		// Set access token as httpOnly cookie
-		http.SetCookie(w, &http.Cookie{
-			Name:     "access_token",
-			Value:    tokens.AccessToken,
-			Path:     "/",
-			HttpOnly: true,
-			Secure:   h.cfg.Env == "production", // Only send over HTTPS in production
-			SameSite: http.SameSiteStrictMode,
-			MaxAge:   int(h.jwtService.GetAccessTokenExpiration() / time.Second),
-		})
-
-		// Set refresh token as httpOnly cookie
-		http.SetCookie(w, &http.Cookie{
-			Name:     "refresh_token",
-			Value:    tokens.RefreshToken,
-			Path:     "/",
-			HttpOnly: true,
-			Secure:   h.cfg.Env == "production", // Only send over HTTPS in production
-			SameSite: http.SameSiteStrictMode,
-			MaxAge:   int(h.jwtService.GetRefreshTokenExpiration() / time.Second),
-		})
+		setAuthCookies(w, h.cfg, h.jwtService, tokens)
 
 		// Return user data without tokens
 		userResponse := map[string]interface{}{
@@ -93,12 +91,13 @@ func (h *AuthHandler) RefreshToken() http.HandlerFunc {
 
 		refreshToken := cookie.Value
 
-		tokens, err := h.jwtService.RefreshTokens(refreshToken)
+		ipAddress := utils.ClientIP(r, h.cfg.RateLimit.TrustProxy)
+		tokens, err := h.userSessionService.RefreshSession(refreshToken, ipAddress, r.UserAgent())
 		if err != nil {
 			statusCode := http.StatusInternalServerError
 			message := "Failed to refresh token"
 
-			if errors.Is(err, services.ErrInvalidToken) || errors.Is(err, services.ErrExpiredToken) {
+			if errors.Is(err, services.ErrInvalidToken) || errors.Is(err, services.ErrExpiredToken) || errors.Is(err, services.ErrUserSessionRevoked) {
 				statusCode = http.StatusUnauthorized
 				message = "Invalid or expired refresh token"
 			}
@@ -107,27 +106,7 @@ func (h *AuthHandler) RefreshToken() http.HandlerFunc {
 			return
 		}
 
-		// Set new access token as httpOnly cookie
-		http.SetCookie(w, &http.Cookie{
-			Name:     "access_token",
-			Value:    tokens.AccessToken,
-			Path:     "/",
-			HttpOnly: true,
-			Secure:   h.cfg.Env == "production",
-			SameSite: http.SameSiteStrictMode,
-			MaxAge:   int(h.jwtService.GetAccessTokenExpiration() / time.Second),
-		})
-
-		// Set new refresh token as httpOnly cookie
-		http.SetCookie(w, &http.Cookie{
-			Name:     "refresh_token",
-			Value:    tokens.RefreshToken,
-			Path:     "/",
-			HttpOnly: true,
-			Secure:   h.cfg.Env == "production",
-			SameSite: http.SameSiteStrictMode,
-			MaxAge:   int(h.jwtService.GetRefreshTokenExpiration() / time.Second),
-		})
+		setAuthCookies(w, h.cfg, h.jwtService, tokens)
 
 		responses.RespondWithSuccess(w, http.StatusOK, "Token refreshed successfully", nil)
 	}
@@ -135,28 +114,117 @@ func (h *AuthHandler) RefreshToken() http.HandlerFunc {
 
 func (h *AuthHandler) Logout() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Clear access token cookie
-		http.SetCookie(w, &http.Cookie{
-			Name:     "access_token",
-			Value:    "",
-			Path:     "/",
-			HttpOnly: true,
-			Secure:   h.cfg.Env == "production",
-			SameSite: http.SameSiteStrictMode,
-			MaxAge:   -1, // Delete cookie
-		})
-
-		// Clear refresh token cookie
-		http.SetCookie(w, &http.Cookie{
-			Name:     "refresh_token",
-			Value:    "",
-			Path:     "/",
-			HttpOnly: true,
-			Secure:   h.cfg.Env == "production",
-			SameSite: http.SameSiteStrictMode,
-			MaxAge:   -1, // Delete cookie
-		})
+		// Revoke the session so its tokens stop working, even if copied elsewhere
+		if cookie, err := r.Cookie("refresh_token"); err == nil && cookie.Value != "" {
+			if err := h.userSessionService.EndSession(cookie.Value); err != nil {
+				log.Printf("Failed to end session on logout: %v", err)
+			}
+		}
+
+		clearAuthCookies(w, h.cfg)
 
 		responses.RespondWithSuccess(w, http.StatusOK, "Logout successful", nil)
 	}
 }
+
+// LoginHistory lists the current user's recent login attempts
+func (h *AuthHandler) LoginHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		events, err := h.loginSecurityService.GetLoginHistory(userID)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve login history")
+			return
+		}
+
+		response := make([]dto.LoginEventResponse, len(events))
+		for i, event := range events {
+			response[i] = dto.LoginEventResponse{
+				ID:            event.ID,
+				IPAddress:     event.IPAddress,
+				UserAgent:     event.UserAgent,
+				Success:       event.Success,
+				FailureReason: event.FailureReason,
+				Suspicious:    event.Suspicious,
+				CreatedAt:     event.CreatedAt,
+			}
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Login history retrieved successfully", response)
+	}
+}
+
+// clearAuthCookies deletes the cookies set by setAuthCookies
+func clearAuthCookies(w http.ResponseWriter, cfg *config.Config) {
+	// Clear access token cookie
+	http.SetCookie(w, &http.Cookie{
+		Name:     "access_token",
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   cfg.Env == "production",
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1, // Delete cookie
+	})
+
+	// Clear refresh token cookie
+	http.SetCookie(w, &http.Cookie{
+		Name:     "refresh_token",
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   cfg.Env == "production",
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1, // Delete cookie
+	})
+
+	// Clear CSRF token cookie
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.CSRFCookieName,
+		Value:    "",
+		Path:     "/",
+		Secure:   cfg.Env == "production",
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1, // Delete cookie
+	})
+}
+
+// setAuthCookies stores a token pair in httpOnly cookies
+func setAuthCookies(w http.ResponseWriter, cfg *config.Config, jwtService services.JWTServiceInterface, tokens *services.TokenPair) {
+	// Set access token as httpOnly cookie
+	http.SetCookie(w, &http.Cookie{
+		Name:     "access_token",
+		Value:    tokens.AccessToken,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   cfg.Env == "production", // Only send over HTTPS in production
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   int(jwtService.GetAccessTokenExpiration() / time.Second),
+	})
+
+	// Set refresh token as httpOnly cookie
+	http.SetCookie(w, &http.Cookie{
+		Name:     "refresh_token",
+		Value:    tokens.RefreshToken,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   cfg.Env == "production", // Only send over HTTPS in production
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   int(jwtService.GetRefreshTokenExpiration() / time.Second),
+	})
+
+	// Set CSRF token cookie, readable by the frontend so it can echo it in a header
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.CSRFCookieName,
+		Value:    middleware.NewCSRFToken(),
+		Path:     "/",
+		HttpOnly: false,
+		Secure:   cfg.Env == "production",
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   int(jwtService.GetRefreshTokenExpiration() / time.Second),
+	})
+}
@@ -7,27 +7,33 @@ import (
 	"time"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
 	"github.com/Bug-Bugger/ezmodel/internal/config"
 	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
 	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type AuthHandlerTestSuite struct {
 	suite.Suite
-	mockUserService *mockService.MockUserService
-	mockJWTService  *mockService.MockJWTService
-	handler         *AuthHandler
-	cfg             *config.Config
+	mockLoginService   *mockService.MockLoginSecurityService
+	mockSessionService *mockService.MockUserSessionService
+	mockJWTService     *mockService.MockJWTService
+	handler            *AuthHandler
+	cfg                *config.Config
 }
 
 func (suite *AuthHandlerTestSuite) SetupTest() {
-	suite.mockUserService = new(mockService.MockUserService)
+	suite.mockLoginService = new(mockService.MockLoginSecurityService)
+	suite.mockSessionService = new(mockService.MockUserSessionService)
 	suite.mockJWTService = new(mockService.MockJWTService)
 	suite.cfg = config.New()
-	suite.handler = NewAuthHandler(suite.mockUserService, suite.mockJWTService, suite.cfg)
+	suite.handler = NewAuthHandler(suite.mockLoginService, suite.mockSessionService, suite.mockJWTService, suite.cfg)
 }
 
 func TestAuthHandlerSuite(t *testing.T) {
@@ -57,9 +63,9 @@ func (suite *AuthHandlerTestSuite) TestLogin_Success() {
 		RefreshToken: "refresh_token_123",
 	}
 
-	suite.mockUserService.On("AuthenticateUser", loginRequest.Email, loginRequest.Password).
+	suite.mockLoginService.On("Authenticate", loginRequest.Email, loginRequest.Password, mock.Anything, mock.Anything).
 		Return(user, nil)
-	suite.mockJWTService.On("GenerateTokenPair", user).Return(tokenPair, nil)
+	suite.mockSessionService.On("StartSession", user, mock.Anything, mock.Anything).Return(tokenPair, nil)
 	suite.mockJWTService.On("GetAccessTokenExpiration").Return(15 * time.Minute)
 	suite.mockJWTService.On("GetRefreshTokenExpiration").Return(7 * 24 * time.Hour)
 
@@ -99,7 +105,15 @@ func (suite *AuthHandlerTestSuite) TestLogin_Success() {
 	suite.Equal("/", refreshCookie.Path)
 	suite.Equal(7*24*60*60, refreshCookie.MaxAge)
 
-	suite.mockUserService.AssertExpectations(suite.T())
+	csrfCookie := suite.getCookie(result.Cookies(), middleware.CSRFCookieName)
+	suite.Require().NotNil(csrfCookie)
+	suite.NotEmpty(csrfCookie.Value)
+	suite.False(csrfCookie.HttpOnly) // Read by the frontend
+	suite.Equal(http.SameSiteStrictMode, csrfCookie.SameSite)
+	suite.Equal(7*24*60*60, csrfCookie.MaxAge)
+
+	suite.mockLoginService.AssertExpectations(suite.T())
+	suite.mockSessionService.AssertExpectations(suite.T())
 	suite.mockJWTService.AssertExpectations(suite.T())
 }
 
@@ -120,7 +134,7 @@ func (suite *AuthHandlerTestSuite) TestLogin_InvalidCredentials() {
 		Password: "wrongpassword",
 	}
 
-	suite.mockUserService.On("AuthenticateUser", loginRequest.Email, loginRequest.Password).
+	suite.mockLoginService.On("Authenticate", loginRequest.Email, loginRequest.Password, mock.Anything, mock.Anything).
 		Return(nil, services.ErrInvalidCredentials)
 
 	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/login", loginRequest)
@@ -129,7 +143,7 @@ func (suite *AuthHandlerTestSuite) TestLogin_InvalidCredentials() {
 	suite.handler.Login()(w, req)
 
 	testutil.AssertErrorResponse(suite.T(), w, http.StatusUnauthorized, "Wrong email or password")
-	suite.mockUserService.AssertExpectations(suite.T())
+	suite.mockLoginService.AssertExpectations(suite.T())
 }
 
 // Test Login - Authentication Service Error
@@ -139,7 +153,7 @@ func (suite *AuthHandlerTestSuite) TestLogin_AuthenticationServiceError() {
 		Password: "password123",
 	}
 
-	suite.mockUserService.On("AuthenticateUser", loginRequest.Email, loginRequest.Password).
+	suite.mockLoginService.On("Authenticate", loginRequest.Email, loginRequest.Password, mock.Anything, mock.Anything).
 		Return(nil, assert.AnError)
 
 	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/login", loginRequest)
@@ -148,7 +162,28 @@ func (suite *AuthHandlerTestSuite) TestLogin_AuthenticationServiceError() {
 	suite.handler.Login()(w, req)
 
 	testutil.AssertErrorResponse(suite.T(), w, http.StatusInternalServerError, "Login failed")
-	suite.mockUserService.AssertExpectations(suite.T())
+	suite.mockLoginService.AssertExpectations(suite.T())
+}
+
+// Test Login - Account Locked
+func (suite *AuthHandlerTestSuite) TestLogin_AccountLocked() {
+	loginRequest := dto.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	}
+
+	suite.mockLoginService.On("Authenticate", loginRequest.Email, loginRequest.Password, "192.0.2.1", "test-agent").
+		Return(nil, &services.LockoutError{RetryAfter: 90 * time.Second})
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/login", loginRequest)
+	req.Header.Set("User-Agent", "test-agent")
+	w := httptest.NewRecorder()
+
+	suite.handler.Login()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusTooManyRequests, "Too many failed login attempts, please try again later")
+	suite.Equal("90", w.Header().Get("Retry-After"))
+	suite.mockLoginService.AssertExpectations(suite.T())
 }
 
 // Test Login - JWT Generation Error
@@ -159,9 +194,9 @@ func (suite *AuthHandlerTestSuite) TestLogin_JWTGenerationError() {
 	}
 
 	user := testutil.CreateTestUser()
-	suite.mockUserService.On("AuthenticateUser", loginRequest.Email, loginRequest.Password).
+	suite.mockLoginService.On("Authenticate", loginRequest.Email, loginRequest.Password, mock.Anything, mock.Anything).
 		Return(user, nil)
-	suite.mockJWTService.On("GenerateTokenPair", user).Return(nil, assert.AnError)
+	suite.mockSessionService.On("StartSession", user, mock.Anything, mock.Anything).Return(nil, assert.AnError)
 
 	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/login", loginRequest)
 	w := httptest.NewRecorder()
@@ -169,10 +204,32 @@ func (suite *AuthHandlerTestSuite) TestLogin_JWTGenerationError() {
 	suite.handler.Login()(w, req)
 
 	testutil.AssertErrorResponse(suite.T(), w, http.StatusInternalServerError, "Failed to generate tokens")
-	suite.mockUserService.AssertExpectations(suite.T())
+	suite.mockLoginService.AssertExpectations(suite.T())
+	suite.mockSessionService.AssertExpectations(suite.T())
 	suite.mockJWTService.AssertExpectations(suite.T())
 }
 
+// Test Login - Disabled account
+func (suite *AuthHandlerTestSuite) TestLogin_AccountDisabled() {
+	loginRequest := dto.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	}
+
+	user := testutil.CreateTestUser()
+	suite.mockLoginService.On("Authenticate", loginRequest.Email, loginRequest.Password, mock.Anything, mock.Anything).
+		Return(user, nil)
+	suite.mockSessionService.On("StartSession", user, mock.Anything, mock.Anything).Return(nil, services.ErrAccountDisabled)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/login", loginRequest)
+	w := httptest.NewRecorder()
+
+	suite.handler.Login()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "Account is disabled")
+	suite.Empty(w.Result().Cookies())
+}
+
 // Test RefreshToken - Success
 func (suite *AuthHandlerTestSuite) TestRefreshToken_Success() {
 	refreshToken := "valid_refresh_token"
@@ -181,7 +238,7 @@ func (suite *AuthHandlerTestSuite) TestRefreshToken_Success() {
 		RefreshToken: "new_refresh_token_123",
 	}
 
-	suite.mockJWTService.On("RefreshTokens", refreshToken).Return(newTokenPair, nil)
+	suite.mockSessionService.On("RefreshSession", refreshToken, mock.Anything, mock.Anything).Return(newTokenPair, nil)
 	suite.mockJWTService.On("GetAccessTokenExpiration").Return(15 * time.Minute)
 	suite.mockJWTService.On("GetRefreshTokenExpiration").Return(7 * 24 * time.Hour)
 
@@ -205,6 +262,7 @@ func (suite *AuthHandlerTestSuite) TestRefreshToken_Success() {
 	suite.Equal(newTokenPair.RefreshToken, refreshCookie.Value)
 	suite.Equal(7*24*60*60, refreshCookie.MaxAge)
 
+	suite.mockSessionService.AssertExpectations(suite.T())
 	suite.mockJWTService.AssertExpectations(suite.T())
 }
 
@@ -220,7 +278,7 @@ func (suite *AuthHandlerTestSuite) TestRefreshToken_MissingCookie() {
 // Test RefreshToken - Invalid Token
 func (suite *AuthHandlerTestSuite) TestRefreshToken_InvalidToken() {
 	refreshToken := "invalid_refresh_token"
-	suite.mockJWTService.On("RefreshTokens", refreshToken).
+	suite.mockSessionService.On("RefreshSession", refreshToken, mock.Anything, mock.Anything).
 		Return(nil, services.ErrInvalidToken)
 
 	req := httptest.NewRequest(http.MethodPost, "/refresh-token", nil)
@@ -230,13 +288,14 @@ func (suite *AuthHandlerTestSuite) TestRefreshToken_InvalidToken() {
 	suite.handler.RefreshToken()(w, req)
 
 	testutil.AssertErrorResponse(suite.T(), w, http.StatusUnauthorized, "Invalid or expired refresh token")
+	suite.mockSessionService.AssertExpectations(suite.T())
 	suite.mockJWTService.AssertExpectations(suite.T())
 }
 
 // Test RefreshToken - Expired Token
 func (suite *AuthHandlerTestSuite) TestRefreshToken_ExpiredToken() {
 	refreshToken := "expired_refresh_token"
-	suite.mockJWTService.On("RefreshTokens", refreshToken).
+	suite.mockSessionService.On("RefreshSession", refreshToken, mock.Anything, mock.Anything).
 		Return(nil, services.ErrExpiredToken)
 
 	req := httptest.NewRequest(http.MethodPost, "/refresh-token", nil)
@@ -246,13 +305,31 @@ func (suite *AuthHandlerTestSuite) TestRefreshToken_ExpiredToken() {
 	suite.handler.RefreshToken()(w, req)
 
 	testutil.AssertErrorResponse(suite.T(), w, http.StatusUnauthorized, "Invalid or expired refresh token")
+	suite.mockSessionService.AssertExpectations(suite.T())
 	suite.mockJWTService.AssertExpectations(suite.T())
 }
 
+// Test RefreshToken - Revoked Session
+func (suite *AuthHandlerTestSuite) TestRefreshToken_RevokedSession() {
+	refreshToken := "revoked_refresh_token"
+	suite.mockSessionService.On("RefreshSession", refreshToken, "192.0.2.1", "test-agent").
+		Return(nil, services.ErrUserSessionRevoked)
+
+	req := httptest.NewRequest(http.MethodPost, "/refresh-token", nil)
+	req.Header.Set("User-Agent", "test-agent")
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: refreshToken})
+	w := httptest.NewRecorder()
+
+	suite.handler.RefreshToken()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusUnauthorized, "Invalid or expired refresh token")
+	suite.mockSessionService.AssertExpectations(suite.T())
+}
+
 // Test RefreshToken - Service Error
 func (suite *AuthHandlerTestSuite) TestRefreshToken_ServiceError() {
 	refreshToken := "some_refresh_token"
-	suite.mockJWTService.On("RefreshTokens", refreshToken).
+	suite.mockSessionService.On("RefreshSession", refreshToken, mock.Anything, mock.Anything).
 		Return(nil, assert.AnError)
 
 	req := httptest.NewRequest(http.MethodPost, "/refresh-token", nil)
@@ -262,6 +339,7 @@ func (suite *AuthHandlerTestSuite) TestRefreshToken_ServiceError() {
 	suite.handler.RefreshToken()(w, req)
 
 	testutil.AssertErrorResponse(suite.T(), w, http.StatusInternalServerError, "Failed to refresh token")
+	suite.mockSessionService.AssertExpectations(suite.T())
 	suite.mockJWTService.AssertExpectations(suite.T())
 }
 
@@ -285,4 +363,85 @@ func (suite *AuthHandlerTestSuite) TestLogout_Success() {
 	suite.NotNil(refreshCookie)
 	suite.Equal(-1, refreshCookie.MaxAge)
 	suite.Equal("", refreshCookie.Value)
+
+	csrfCookie := suite.getCookie(result.Cookies(), middleware.CSRFCookieName)
+	suite.Require().NotNil(csrfCookie)
+	suite.Equal(-1, csrfCookie.MaxAge)
+}
+
+func (suite *AuthHandlerTestSuite) TestLogout_EndsSession() {
+	suite.mockSessionService.On("EndSession", "refresh_token_123").Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "refresh_token_123"})
+	w := httptest.NewRecorder()
+
+	suite.handler.Logout()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Logout successful")
+	suite.mockSessionService.AssertExpectations(suite.T())
+}
+
+func (suite *AuthHandlerTestSuite) TestLogout_EndSessionErrorStillClearsCookies() {
+	suite.mockSessionService.On("EndSession", "refresh_token_123").Return(assert.AnError)
+
+	req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "refresh_token_123"})
+	w := httptest.NewRecorder()
+
+	suite.handler.Logout()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Logout successful")
+	refreshCookie := suite.getCookie(w.Result().Cookies(), "refresh_token")
+	suite.NotNil(refreshCookie)
+	suite.Equal(-1, refreshCookie.MaxAge)
+}
+
+func (suite *AuthHandlerTestSuite) TestLoginHistory_Success() {
+	userID := uuid.New()
+	events := []*models.LoginEvent{
+		{ID: uuid.New(), UserID: &userID, IPAddress: "192.0.2.1", UserAgent: "test-agent", Success: true, Suspicious: true, CreatedAt: time.Now()},
+		{ID: uuid.New(), UserID: &userID, IPAddress: "192.0.2.2", FailureReason: models.LoginFailureInvalidCredentials, CreatedAt: time.Now()},
+	}
+
+	suite.mockLoginService.On("GetLoginHistory", userID).Return(events, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me/security/logins", nil)
+	req = testutil.WithUserContext(req, userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.LoginHistory()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Login history retrieved successfully")
+	data, ok := response.Data.([]any)
+	suite.Require().True(ok)
+	suite.Len(data, 2)
+	first := data[0].(map[string]any)
+	suite.Equal("192.0.2.1", first["ip_address"])
+	suite.Equal(true, first["suspicious"])
+	suite.NotContains(first, "email")
+	suite.mockLoginService.AssertExpectations(suite.T())
+}
+
+func (suite *AuthHandlerTestSuite) TestLoginHistory_Unauthenticated() {
+	req := httptest.NewRequest(http.MethodGet, "/users/me/security/logins", nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.LoginHistory()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusUnauthorized, "User context not found")
+}
+
+func (suite *AuthHandlerTestSuite) TestLoginHistory_ServiceError() {
+	userID := uuid.New()
+	suite.mockLoginService.On("GetLoginHistory", userID).Return(nil, assert.AnError)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/me/security/logins", nil)
+	req = testutil.WithUserContext(req, userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.LoginHistory()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusInternalServerError, "Failed to retrieve login history")
+	suite.mockLoginService.AssertExpectations(suite.T())
 }
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+// avatarFormField is the multipart field holding the uploaded image
+const avatarFormField = "avatar"
+
+// multipartOverhead allows for the boundaries and headers around the image
+const multipartOverhead = 64 * 1024
+
+type AvatarHandler struct {
+	avatarService services.AvatarServiceInterface
+}
+
+func NewAvatarHandler(avatarService services.AvatarServiceInterface) *AvatarHandler {
+	return &AvatarHandler{
+		avatarService: avatarService,
+	}
+}
+
+// Upload replaces the current user's avatar with the image in the avatar field
+// of a multipart/form-data body
+func (h *AvatarHandler) Upload() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		maxBytes := h.avatarService.MaxBytes()
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes+multipartOverhead)
+
+		file, _, err := r.FormFile(avatarFormField)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				responses.RespondWithError(w, http.StatusRequestEntityTooLarge, "Avatar is too large")
+			} else {
+				responses.RespondWithError(w, http.StatusBadRequest, "Avatar image is required")
+			}
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+		if err != nil {
+			responses.RespondWithError(w, http.StatusBadRequest, "Failed to read avatar")
+			return
+		}
+
+		user, err := h.avatarService.UploadAvatar(r.Context(), userID, data)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrImageTooLarge):
+				responses.RespondWithError(w, http.StatusRequestEntityTooLarge, "Avatar is too large")
+			case errors.Is(err, services.ErrInvalidImage):
+				responses.RespondWithError(w, http.StatusBadRequest, "Avatar must be a PNG, JPEG, GIF or WebP image")
+			case errors.Is(err, services.ErrUserNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "User not found")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to upload avatar")
+			}
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Avatar uploaded successfully", avatarUserResponse(user))
+	}
+}
+
+// Delete removes the current user's avatar
+func (h *AvatarHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		user, err := h.avatarService.DeleteAvatar(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, services.ErrUserNotFound) {
+				responses.RespondWithError(w, http.StatusNotFound, "User not found")
+			} else {
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to delete avatar")
+			}
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Avatar deleted successfully", avatarUserResponse(user))
+	}
+}
+
+func avatarUserResponse(user *models.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Username:  user.Username,
+		AvatarURL: user.AvatarURL,
+	}
+}
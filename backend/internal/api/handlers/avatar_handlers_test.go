@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AvatarHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockAvatarService
+	handler     *AvatarHandler
+	userID      uuid.UUID
+}
+
+func (suite *AvatarHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockAvatarService)
+	suite.mockService.On("MaxBytes").Return(int64(1024)).Maybe()
+	suite.handler = NewAvatarHandler(suite.mockService)
+	suite.userID = uuid.New()
+}
+
+func TestAvatarHandlerSuite(t *testing.T) {
+	suite.Run(t, new(AvatarHandlerTestSuite))
+}
+
+func (suite *AvatarHandlerTestSuite) uploadRequest(field string, data []byte) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, "avatar.png")
+	suite.Require().NoError(err)
+	_, _ = part.Write(data)
+	suite.Require().NoError(writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/users/me/avatar", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return testutil.WithUserContext(req, suite.userID)
+}
+
+// Test Upload - The file is passed on and the user returned with its avatar URL
+func (suite *AvatarHandlerTestSuite) TestUpload_Success() {
+	user := &models.User{ID: suite.userID, Username: "alice", AvatarURL: "https://cdn.example.com/avatars/a.png"}
+	suite.mockService.On("UploadAvatar", mock.Anything, suite.userID, []byte("image")).Return(user, nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Upload()(w, suite.uploadRequest("avatar", []byte("image")))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Avatar uploaded successfully")
+	data, ok := response.Data.(map[string]any)
+	suite.Require().True(ok)
+	assert.Equal(suite.T(), user.AvatarURL, data["avatar_url"])
+}
+
+// Test Upload - The avatar field is required
+func (suite *AvatarHandlerTestSuite) TestUpload_MissingFile() {
+	w := httptest.NewRecorder()
+
+	suite.handler.Upload()(w, suite.uploadRequest("picture", []byte("image")))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "UploadAvatar", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Upload - Bodies over the limit are refused before reaching the service
+func (suite *AvatarHandlerTestSuite) TestUpload_TooLarge() {
+	w := httptest.NewRecorder()
+
+	suite.handler.Upload()(w, suite.uploadRequest("avatar", make([]byte, 1024+multipartOverhead)))
+
+	assert.Equal(suite.T(), http.StatusRequestEntityTooLarge, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "UploadAvatar", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Upload - Files that are not images are rejected
+func (suite *AvatarHandlerTestSuite) TestUpload_InvalidImage() {
+	suite.mockService.On("UploadAvatar", mock.Anything, suite.userID, mock.Anything).Return(nil, services.ErrInvalidImage)
+	w := httptest.NewRecorder()
+
+	suite.handler.Upload()(w, suite.uploadRequest("avatar", []byte("not an image")))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+// maxWebhookBytes bounds the body of a payment provider webhook
+const maxWebhookBytes = 1 << 20
+
+type BillingHandler struct {
+	billingService services.BillingServiceInterface
+}
+
+func NewBillingHandler(billingService services.BillingServiceInterface) *BillingHandler {
+	return &BillingHandler{
+		billingService: billingService,
+	}
+}
+
+// Plans lists the plans on offer
+func (h *BillingHandler) Plans() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		plans := h.billingService.Plans()
+		planResponses := make([]dto.PlanResponse, len(plans))
+		for i, plan := range plans {
+			planResponses[i] = planResponse(plan)
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Plans retrieved successfully", planResponses)
+	}
+}
+
+// GetSubscription returns the plan of the current user
+func (h *BillingHandler) GetSubscription() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		current, err := h.billingService.GetCurrentPlan(userID)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve subscription")
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Subscription retrieved successfully", dto.SubscriptionResponse{
+			Plan:             planResponse(current.Plan),
+			Status:           current.Status,
+			CurrentPeriodEnd: current.CurrentPeriodEnd,
+		})
+	}
+}
+
+// Checkout starts buying a plan and returns the payment page to send the browser to
+func (h *BillingHandler) Checkout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var req dto.CreateCheckoutRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		url, err := h.billingService.CreateCheckout(r.Context(), userID, req.Plan)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrPlanNotFound):
+				responses.RespondWithError(w, http.StatusBadRequest, "Unknown plan")
+			case errors.Is(err, services.ErrAlreadySubscribed):
+				responses.RespondWithError(w, http.StatusConflict, "Already subscribed to a plan")
+			default:
+				log.Printf("Billing: checkout failed: %v", err)
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to start checkout")
+			}
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Checkout started", dto.CheckoutResponse{URL: url})
+	}
+}
+
+// Webhook receives the Stripe events about subscriptions, authenticated by
+// the Stripe-Signature header. Failures other than a bad signature answer
+// with 500 so Stripe retries.
+func (h *BillingHandler) Webhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+		if err != nil {
+			responses.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+
+		if err := h.billingService.HandleWebhook(payload, r.Header.Get("Stripe-Signature")); err != nil {
+			if errors.Is(err, services.ErrInvalidWebhook) {
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid webhook")
+			} else {
+				log.Printf("Billing: webhook failed: %v", err)
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to process webhook")
+			}
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Webhook processed", nil)
+	}
+}
+
+func planResponse(plan services.Plan) dto.PlanResponse {
+	return dto.PlanResponse{
+		ID:                  plan.ID,
+		Name:                plan.Name,
+		MaxProjectsPerUser:  plan.Quotas.MaxProjectsPerUser,
+		MaxTablesPerProject: plan.Quotas.MaxTablesPerProject,
+		MaxCollaborators:    plan.Quotas.MaxCollaborators,
+	}
+}
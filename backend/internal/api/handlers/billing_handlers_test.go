@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type BillingHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockBillingService
+	handler     *BillingHandler
+	userID      uuid.UUID
+}
+
+func (suite *BillingHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockBillingService)
+	suite.handler = NewBillingHandler(suite.mockService)
+	suite.userID = uuid.New()
+}
+
+func TestBillingHandlerSuite(t *testing.T) {
+	suite.Run(t, new(BillingHandlerTestSuite))
+}
+
+// Test GetSubscription - Users without a subscription are on the free plan
+func (suite *BillingHandlerTestSuite) TestGetSubscription_Free() {
+	suite.mockService.On("GetCurrentPlan", suite.userID).Return(&services.CurrentPlan{
+		Plan: services.Plan{ID: services.PlanFree, Name: "Free", Quotas: services.Quotas{MaxProjectsPerUser: 3}},
+	}, nil)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/billing/subscription", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetSubscription()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Subscription retrieved successfully")
+	data, ok := response.Data.(map[string]any)
+	suite.Require().True(ok)
+	suite.Equal("", data["status"])
+	suite.Equal(map[string]any{
+		"id": "free", "name": "Free", "max_projects_per_user": float64(3), "max_tables_per_project": float64(0), "max_collaborators": float64(0),
+	}, data["plan"])
+}
+
+// Test Checkout - Returns the payment page
+func (suite *BillingHandlerTestSuite) TestCheckout_Success() {
+	suite.mockService.On("CreateCheckout", mock.Anything, suite.userID, "pro").Return("https://checkout.stripe.com/c/pay/cs_1", nil)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/billing/checkout", dto.CreateCheckoutRequest{Plan: "pro"})
+	req = testutil.WithUserContext(req, suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.Checkout()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Checkout started")
+	suite.Equal(map[string]any{"url": "https://checkout.stripe.com/c/pay/cs_1"}, response.Data)
+}
+
+// Test Checkout - Running subscriptions conflict
+func (suite *BillingHandlerTestSuite) TestCheckout_AlreadySubscribed() {
+	suite.mockService.On("CreateCheckout", mock.Anything, suite.userID, "pro").Return("", services.ErrAlreadySubscribed)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/billing/checkout", dto.CreateCheckoutRequest{Plan: "pro"})
+	req = testutil.WithUserContext(req, suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.Checkout()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "Already subscribed to a plan")
+}
+
+// Test Webhook - The raw body and signature are passed on
+func (suite *BillingHandlerTestSuite) TestWebhook_Success() {
+	payload := `{"id":"evt_1","type":"customer.subscription.updated"}`
+	suite.mockService.On("HandleWebhook", []byte(payload), "t=1,v1=abc").Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/billing/webhook", strings.NewReader(payload))
+	req.Header.Set("Stripe-Signature", "t=1,v1=abc")
+	w := httptest.NewRecorder()
+
+	suite.handler.Webhook()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Webhook processed")
+}
+
+// Test Webhook - Bad signatures are rejected
+func (suite *BillingHandlerTestSuite) TestWebhook_InvalidSignature() {
+	suite.mockService.On("HandleWebhook", mock.Anything, "").Return(services.ErrInvalidWebhook)
+
+	w := httptest.NewRecorder()
+
+	suite.handler.Webhook()(w, httptest.NewRequest(http.MethodPost, "/billing/webhook", strings.NewReader("{}")))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid webhook")
+}
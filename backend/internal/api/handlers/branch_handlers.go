@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type BranchHandler struct {
+	branchService services.BranchServiceInterface
+}
+
+func NewBranchHandler(branchService services.BranchServiceInterface) *BranchHandler {
+	return &BranchHandler{
+		branchService: branchService,
+	}
+}
+
+// Create handles branching a project's schema
+func (h *BranchHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.CreateBranchRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		branch, err := h.branchService.CreateBranch(projectID, &req, userID)
+		if err != nil {
+			respondWithBranchError(w, err)
+			return
+		}
+
+		respondWithBranch(w, http.StatusCreated, "Branch created successfully", branch)
+	}
+}
+
+// GetByProjectID handles retrieving the branches of a project by name
+func (h *BranchHandler) GetByProjectID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		branches, err := h.branchService.GetBranches(projectID, userID)
+		if err != nil {
+			respondWithBranchError(w, err)
+			return
+		}
+
+		branchResponses := make([]dto.BranchResponse, len(branches))
+		for i, branch := range branches {
+			branchResponses[i] = newBranchResponse(branch)
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Branches retrieved successfully", branchResponses)
+	}
+}
+
+// GetByID handles retrieving a specific branch with its schema
+func (h *BranchHandler) GetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		branchID, ok := utils.ParseUUIDParam(w, r, "branch_id")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		branch, err := h.branchService.GetBranch(projectID, branchID, userID)
+		if err != nil {
+			respondWithBranchError(w, err)
+			return
+		}
+
+		utils.SetVersionETag(w, branch.Version)
+		respondWithBranch(w, http.StatusOK, "Branch retrieved successfully", branch)
+	}
+}
+
+// Update handles renaming a branch or replacing its schema
+func (h *BranchHandler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		branchID, ok := utils.ParseUUIDParam(w, r, "branch_id")
+		if !ok {
+			return
+		}
+
+		var req dto.UpdateBranchRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		// The If-Match header takes precedence over a version in the body
+		version, ok := utils.ParseIfMatch(w, r)
+		if !ok {
+			return
+		}
+		if version != nil {
+			req.Version = version
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		branch, err := h.branchService.UpdateBranch(projectID, branchID, &req, userID)
+		if err != nil && !errors.Is(err, services.ErrVersionConflict) {
+			respondWithBranchError(w, err)
+			return
+		}
+
+		utils.SetVersionETag(w, branch.Version)
+		if err != nil {
+			response, err := newBranchSchemaResponse(branch)
+			if err != nil {
+				responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+				return
+			}
+			responses.RespondWithErrorData(w, http.StatusConflict, "Branch was modified by someone else", response)
+			return
+		}
+
+		respondWithBranch(w, http.StatusOK, "Branch updated successfully", branch)
+	}
+}
+
+// Delete handles branch deletion
+func (h *BranchHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		branchID, ok := utils.ParseUUIDParam(w, r, "branch_id")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.branchService.DeleteBranch(projectID, branchID, userID); err != nil {
+			respondWithBranchError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Branch deleted successfully", nil)
+	}
+}
+
+// Diff handles diffing the project's schema against a branch's
+func (h *BranchHandler) Diff() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		branchID, ok := utils.ParseUUIDParam(w, r, "branch_id")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		diff, err := h.branchService.DiffBranch(projectID, branchID, userID)
+		if err != nil {
+			respondWithBranchError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Branch compared successfully", newSchemaDiffResponse(diff))
+	}
+}
+
+// Merge handles merging a branch into its project
+func (h *BranchHandler) Merge() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		branchID, ok := utils.ParseUUIDParam(w, r, "branch_id")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		branch, diff, err := h.branchService.MergeBranch(projectID, branchID, userID)
+		if err != nil {
+			respondWithBranchError(w, err)
+			return
+		}
+
+		responses.SetProjectSequence(w, branch.Sequence)
+		responses.RespondWithSuccess(w, http.StatusOK, "Branch merged successfully", dto.BranchMergeResponse{
+			Branch:  newBranchResponse(branch),
+			Changes: newSchemaDiffResponse(diff),
+		})
+	}
+}
+
+func respondWithBranchError(w http.ResponseWriter, err error) {
+	var conflictErr *services.MergeConflictError
+	switch {
+	case errors.As(err, &conflictErr):
+		responses.RespondWithErrorData(w, http.StatusConflict, "Branch conflicts with the project", newMergeConflictResponses(conflictErr.Conflicts))
+	case errors.Is(err, services.ErrProjectNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, services.ErrBranchNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Branch not found")
+	case errors.Is(err, services.ErrBranchMerged):
+		responses.RespondWithError(w, http.StatusConflict, "Branch was merged already")
+	case errors.Is(err, services.ErrFieldNameTaken):
+		responses.RespondWithError(w, http.StatusConflict, "The branch adds a field whose name, ignoring case, a table of the project already has")
+	case errors.Is(err, services.ErrNameTaken):
+		responses.RespondWithError(w, http.StatusConflict, "A branch of the project already has this name")
+	case errors.Is(err, services.ErrVersionConflict):
+		responses.RespondWithError(w, http.StatusConflict, "Project was modified during the merge")
+	case errors.Is(err, services.ErrTableNotFound):
+		responses.RespondWithError(w, http.StatusBadRequest, "A relationship refers to an unknown table")
+	case errors.Is(err, services.ErrFieldNotFound):
+		responses.RespondWithError(w, http.StatusBadRequest, "A relationship refers to an unknown field")
+	case errors.Is(err, services.ErrInvalidInput):
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+	case errors.Is(err, services.ErrForbidden):
+		responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+	case errors.Is(err, services.ErrQuotaExceeded):
+		respondWithQuotaExceeded(w, err)
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// respondWithBranch responds with a branch and its schema
+func respondWithBranch(w http.ResponseWriter, status int, message string, branch *models.Branch) {
+	response, err := newBranchSchemaResponse(branch)
+	if err != nil {
+		responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	responses.RespondWithSuccess(w, status, message, response)
+}
+
+func newBranchResponse(branch *models.Branch) dto.BranchResponse {
+	return dto.BranchResponse{
+		ID:        branch.ID,
+		ProjectID: branch.ProjectID,
+		Name:      branch.Name,
+		Version:   branch.Version,
+		CreatedBy: branch.CreatedBy,
+		MergedBy:  branch.MergedBy,
+		MergedAt:  branch.MergedAt,
+		CreatedAt: branch.CreatedAt,
+		UpdatedAt: branch.UpdatedAt,
+	}
+}
+
+// newBranchSchemaResponse describes a branch with its schema
+func newBranchSchemaResponse(branch *models.Branch) (dto.BranchResponse, error) {
+	response := newBranchResponse(branch)
+	var schema dto.CreateSchemaRequest
+	if err := json.Unmarshal([]byte(branch.Schema), &schema); err != nil {
+		return dto.BranchResponse{}, err
+	}
+	response.Schema = &schema
+	return response, nil
+}
+
+func newMergeConflictResponses(conflicts []schemadiff.Conflict) []dto.MergeConflictResponse {
+	response := make([]dto.MergeConflictResponse, len(conflicts))
+	for i, conflict := range conflicts {
+		response[i] = dto.MergeConflictResponse{
+			Table:  conflict.Table,
+			Field:  conflict.Field,
+			Reason: conflict.Reason,
+		}
+		if conflict.Relationship != nil {
+			relationship := newDiffRelationshipResponse(*conflict.Relationship)
+			response[i].Relationship = &relationship
+		}
+	}
+	return response
+}
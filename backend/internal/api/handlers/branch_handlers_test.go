@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type BranchHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockBranchService
+	handler     *BranchHandler
+	userID      uuid.UUID
+	projectID   uuid.UUID
+	branchID    uuid.UUID
+}
+
+func (suite *BranchHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockBranchService)
+	suite.handler = NewBranchHandler(suite.mockService)
+	suite.userID = uuid.New()
+	suite.projectID = uuid.New()
+	suite.branchID = uuid.New()
+}
+
+func TestBranchHandlerSuite(t *testing.T) {
+	suite.Run(t, new(BranchHandlerTestSuite))
+}
+
+// withBranch adds the project and branch IDs to the route context, and the user
+func (suite *BranchHandlerTestSuite) withBranch(req *http.Request) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", suite.projectID.String())
+	rctx.URLParams.Add("branch_id", suite.branchID.String())
+	return testutil.WithUserContext(req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)), suite.userID)
+}
+
+func (suite *BranchHandlerTestSuite) branchURL() string {
+	return "/projects/" + suite.projectID.String() + "/branches/" + suite.branchID.String()
+}
+
+// Test Create - The branch is returned with its schema
+func (suite *BranchHandlerTestSuite) TestCreate_Success() {
+	branch := &models.Branch{ID: suite.branchID, ProjectID: suite.projectID, Name: "audit", Version: 1, Schema: `{"tables":[{"name":"users","pos_x":0,"pos_y":0}]}`}
+	suite.mockService.On("CreateBranch", suite.projectID, &dto.CreateBranchRequest{Name: "audit"}, suite.userID).Return(branch, nil)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+suite.projectID.String()+"/branches", dto.CreateBranchRequest{Name: "audit"})
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, suite.withBranch(req))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "Branch created successfully")
+	data := response.Data.(map[string]any)
+	suite.Equal(suite.branchID.String(), data["branch_id"])
+	suite.Equal("users", data["schema"].(map[string]any)["tables"].([]any)[0].(map[string]any)["name"])
+}
+
+// Test Create - Branch names are unique within the project
+func (suite *BranchHandlerTestSuite) TestCreate_NameTaken() {
+	suite.mockService.On("CreateBranch", suite.projectID, mock.AnythingOfType("*dto.CreateBranchRequest"), suite.userID).Return(nil, services.ErrNameTaken)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+suite.projectID.String()+"/branches", dto.CreateBranchRequest{Name: "audit"})
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, suite.withBranch(req))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "A branch of the project already has this name")
+}
+
+// Test GetByProjectID - Lists leave the schemas out
+func (suite *BranchHandlerTestSuite) TestGetByProjectID() {
+	suite.mockService.On("GetBranches", suite.projectID, suite.userID).Return([]*models.Branch{{ID: suite.branchID, ProjectID: suite.projectID, Name: "audit"}}, nil)
+
+	req := suite.withBranch(httptest.NewRequest(http.MethodGet, "/projects/"+suite.projectID.String()+"/branches", nil))
+	w := httptest.NewRecorder()
+	suite.handler.GetByProjectID()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Branches retrieved successfully")
+	branch := response.Data.([]any)[0].(map[string]any)
+	suite.Equal("audit", branch["name"])
+	suite.NotContains(branch, "schema")
+	suite.Nil(branch["merged_at"])
+}
+
+// Test Update - A stale edit is answered with the current branch
+func (suite *BranchHandlerTestSuite) TestUpdate_Conflict() {
+	current := &models.Branch{ID: suite.branchID, ProjectID: suite.projectID, Name: "theirs", Version: 5, Schema: `{"tables":[]}`}
+	version := int64(4)
+	name := "mine"
+	suite.mockService.On("UpdateBranch", suite.projectID, suite.branchID, &dto.UpdateBranchRequest{Name: &name, Version: &version}, suite.userID).Return(current, services.ErrVersionConflict)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPut, suite.branchURL(), dto.UpdateBranchRequest{Name: &name})
+	req.Header.Set("If-Match", `"4"`)
+	w := httptest.NewRecorder()
+	suite.handler.Update()(w, suite.withBranch(req))
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "Branch was modified by someone else")
+	suite.Equal("theirs", response.Data.(map[string]any)["name"])
+	suite.Equal(`"5"`, w.Header().Get("ETag"))
+}
+
+// Test Update - Merged branches no longer change
+func (suite *BranchHandlerTestSuite) TestUpdate_Merged() {
+	suite.mockService.On("UpdateBranch", suite.projectID, suite.branchID, mock.AnythingOfType("*dto.UpdateBranchRequest"), suite.userID).Return(nil, services.ErrBranchMerged)
+
+	name := "mine"
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPut, suite.branchURL(), dto.UpdateBranchRequest{Name: &name})
+	w := httptest.NewRecorder()
+	suite.handler.Update()(w, suite.withBranch(req))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "Branch was merged already")
+}
+
+// Test Merge - The merged branch comes with what changed in the project
+func (suite *BranchHandlerTestSuite) TestMerge_Success() {
+	mergedAt := time.Now()
+	branch := &models.Branch{ID: suite.branchID, ProjectID: suite.projectID, Name: "audit", MergedBy: &suite.userID, MergedAt: &mergedAt, Sequence: 9}
+	diff := &schemadiff.Diff{AddedTables: []schemadiff.Table{{Name: "audit_log", Fields: []schemadiff.Field{{Name: "id", DataType: "UUID"}}}}}
+	suite.mockService.On("MergeBranch", suite.projectID, suite.branchID, suite.userID).Return(branch, diff, nil)
+
+	req := suite.withBranch(httptest.NewRequest(http.MethodPost, suite.branchURL()+"/merge", nil))
+	w := httptest.NewRecorder()
+	suite.handler.Merge()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Branch merged successfully")
+	data := response.Data.(map[string]any)
+	suite.Equal(suite.userID.String(), data["branch"].(map[string]any)["merged_by"])
+	suite.Equal("audit_log", data["changes"].(map[string]any)["added_tables"].([]any)[0].(map[string]any)["name"])
+	suite.Equal("9", w.Header().Get("X-Project-Sequence"))
+}
+
+// Test Merge - Conflicts are listed
+func (suite *BranchHandlerTestSuite) TestMerge_Conflict() {
+	relationship := schemadiff.Relationship{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_many"}
+	suite.mockService.On("MergeBranch", suite.projectID, suite.branchID, suite.userID).Return(nil, nil, &services.MergeConflictError{Conflicts: []schemadiff.Conflict{
+		{Table: "users", Field: "email", Reason: schemadiff.ConflictModified},
+		{Table: "posts", Field: "author_id", Relationship: &relationship, Reason: schemadiff.ConflictDangling},
+	}})
+
+	req := suite.withBranch(httptest.NewRequest(http.MethodPost, suite.branchURL()+"/merge", nil))
+	w := httptest.NewRecorder()
+	suite.handler.Merge()(w, req)
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "Branch conflicts with the project")
+	conflicts := response.Data.([]any)
+	suite.Require().Len(conflicts, 2)
+	suite.Equal(map[string]any{"table": "users", "field": "email", "reason": "modified_on_both_sides"}, conflicts[0])
+	suite.Equal("users", conflicts[1].(map[string]any)["relationship"].(map[string]any)["source_table"])
+}
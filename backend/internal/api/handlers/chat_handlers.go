@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type ChatHandler struct {
+	chatService services.ChatServiceInterface
+}
+
+func NewChatHandler(chatService services.ChatServiceInterface) *ChatHandler {
+	return &ChatHandler{
+		chatService: chatService,
+	}
+}
+
+// Create handles sending a chat message to a project's collaborators
+func (h *ChatHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.SendChatMessageRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		message, err := h.chatService.SendMessage(projectID, req.Content, userID)
+		if err != nil {
+			respondWithChatError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusCreated, "Message sent successfully", newChatMessageResponse(message))
+	}
+}
+
+// GetByProjectID handles retrieving a page of a project's chat, newest first by default
+func (h *ChatHandler) GetByProjectID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		page, ok := utils.ParsePageQuery(w, r)
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		messages, next, err := h.chatService.ListMessages(projectID, userID, page)
+		if err != nil {
+			respondWithChatError(w, err)
+			return
+		}
+
+		messageResponses := make([]dto.ChatMessageResponse, len(messages))
+		for i, message := range messages {
+			messageResponses[i] = newChatMessageResponse(message)
+		}
+		responses.RespondWithPage(w, "Messages retrieved successfully", messageResponses, dto.PageMeta{NextCursor: next})
+	}
+}
+
+func respondWithChatError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrProjectNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, services.ErrInvalidInput):
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+	case errors.Is(err, services.ErrForbidden):
+		responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+	default:
+		utils.RespondWithPageError(w, err, "Internal server error")
+	}
+}
+
+func newChatMessageResponse(message *models.ChatMessage) dto.ChatMessageResponse {
+	return dto.ChatMessageResponse{
+		ID:        message.ID,
+		ProjectID: message.ProjectID,
+		UserID:    message.UserID,
+		Username:  message.User.Username,
+		Content:   message.Content,
+		CreatedAt: message.CreatedAt,
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChatHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockChatService
+	handler     *ChatHandler
+	userID      uuid.UUID
+	projectID   uuid.UUID
+}
+
+func (suite *ChatHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockChatService)
+	suite.handler = NewChatHandler(suite.mockService)
+	suite.userID = uuid.New()
+	suite.projectID = uuid.New()
+}
+
+func TestChatHandlerSuite(t *testing.T) {
+	suite.Run(t, new(ChatHandlerTestSuite))
+}
+
+// withProject adds the project ID to the route context, and the user
+func (suite *ChatHandlerTestSuite) withProject(req *http.Request) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", suite.projectID.String())
+	return testutil.WithUserContext(req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)), suite.userID)
+}
+
+func (suite *ChatHandlerTestSuite) chatURL() string {
+	return "/projects/" + suite.projectID.String() + "/chat"
+}
+
+// Test Create - The sent message is returned with its sender
+func (suite *ChatHandlerTestSuite) TestCreate_Success() {
+	message := &models.ChatMessage{ID: uuid.New(), ProjectID: suite.projectID, UserID: suite.userID, Content: "Hi", CreatedAt: time.Now(), User: models.User{Username: "ada"}}
+	suite.mockService.On("SendMessage", suite.projectID, "Hi", suite.userID).Return(message, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, suite.withProject(testutil.MakeJSONRequest(suite.T(), http.MethodPost, suite.chatURL(), dto.SendChatMessageRequest{Content: "Hi"})))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "Message sent successfully")
+	data := response.Data.(map[string]any)
+	suite.Equal(message.ID.String(), data["message_id"])
+	suite.Equal("ada", data["username"])
+}
+
+// Test Create - Only people with access to the project chat in it
+func (suite *ChatHandlerTestSuite) TestCreate_Forbidden() {
+	suite.mockService.On("SendMessage", suite.projectID, "Hi", suite.userID).Return(nil, services.ErrForbidden)
+
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, suite.withProject(testutil.MakeJSONRequest(suite.T(), http.MethodPost, suite.chatURL(), dto.SendChatMessageRequest{Content: "Hi"})))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "You don't have access to this project")
+}
+
+// Test GetByProjectID - The history is paginated
+func (suite *ChatHandlerTestSuite) TestGetByProjectID() {
+	messages := []*models.ChatMessage{{ID: uuid.New(), ProjectID: suite.projectID, Content: "Hi"}}
+	suite.mockService.On("ListMessages", suite.projectID, suite.userID, repository.PageQuery{Limit: 10}).Return(messages, "next-page", nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.GetByProjectID()(w, suite.withProject(httptest.NewRequest(http.MethodGet, suite.chatURL()+"?limit=10", nil)))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Messages retrieved successfully")
+	suite.Equal("Hi", response.Data.([]any)[0].(map[string]any)["content"])
+	suite.Contains(w.Body.String(), `"next_cursor":"next-page"`)
+}
+
+// Test GetByProjectID - Cursors of other lists are rejected
+func (suite *ChatHandlerTestSuite) TestGetByProjectID_InvalidCursor() {
+	suite.mockService.On("ListMessages", suite.projectID, suite.userID, repository.PageQuery{Cursor: "bogus"}).Return(nil, "", repository.ErrInvalidCursor)
+
+	w := httptest.NewRecorder()
+	suite.handler.GetByProjectID()(w, suite.withProject(httptest.NewRequest(http.MethodGet, suite.chatURL()+"?cursor=bogus", nil)))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid cursor")
+}
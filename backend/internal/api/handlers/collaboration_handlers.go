@@ -74,6 +74,7 @@ func (h *CollaborationHandler) Create() http.HandlerFunc {
 			CursorX:    session.CursorX,
 			CursorY:    session.CursorY,
 			UserColor:  session.UserColor,
+			Status:     session.Status,
 			IsActive:   session.IsActive,
 			LastPingAt: session.LastPingAt,
 			JoinedAt:   session.JoinedAt,
@@ -113,6 +114,7 @@ func (h *CollaborationHandler) GetByID() http.HandlerFunc {
 			CursorX:    session.CursorX,
 			CursorY:    session.CursorY,
 			UserColor:  session.UserColor,
+			Status:     session.Status,
 			IsActive:   session.IsActive,
 			LastPingAt: session.LastPingAt,
 			JoinedAt:   session.JoinedAt,
@@ -149,6 +151,7 @@ func (h *CollaborationHandler) GetByProjectID() http.HandlerFunc {
 				CursorX:    session.CursorX,
 				CursorY:    session.CursorY,
 				UserColor:  session.UserColor,
+				Status:     session.Status,
 				IsActive:   session.IsActive,
 				LastPingAt: session.LastPingAt,
 				JoinedAt:   session.JoinedAt,
@@ -186,6 +189,7 @@ func (h *CollaborationHandler) GetActiveByProjectID() http.HandlerFunc {
 				CursorX:    session.CursorX,
 				CursorY:    session.CursorY,
 				UserColor:  session.UserColor,
+				Status:     session.Status,
 				IsActive:   session.IsActive,
 				LastPingAt: session.LastPingAt,
 				JoinedAt:   session.JoinedAt,
@@ -264,6 +268,7 @@ func (h *CollaborationHandler) Update() http.HandlerFunc {
 			CursorX:    session.CursorX,
 			CursorY:    session.CursorY,
 			UserColor:  session.UserColor,
+			Status:     session.Status,
 			IsActive:   session.IsActive,
 			LastPingAt: session.LastPingAt,
 			JoinedAt:   session.JoinedAt,
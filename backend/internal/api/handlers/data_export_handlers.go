@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type DataExportHandler struct {
+	dataExportService services.DataExportServiceInterface
+}
+
+func NewDataExportHandler(dataExportService services.DataExportServiceInterface) *DataExportHandler {
+	return &DataExportHandler{
+		dataExportService: dataExportService,
+	}
+}
+
+// GetMine returns the current user's data export, starting one when there is
+// none. Responds with 202 while it is being built.
+func (h *DataExportHandler) GetMine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		export, err := h.dataExportService.GetExport(userID)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve data export")
+			return
+		}
+
+		h.respond(w, export, "Data export retrieved successfully")
+	}
+}
+
+// Start begins a new data export of the current user, e.g. to include changes
+// made since the last one
+func (h *DataExportHandler) Start() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		export, err := h.dataExportService.StartExport(userID)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to start data export")
+			return
+		}
+
+		h.respond(w, export, "Data export started successfully")
+	}
+}
+
+// Download streams the archive of a signed download link. The link is the
+// credential, so no session is needed.
+func (h *DataExportHandler) Download() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		exportID, ok := utils.ParseUUIDParamWithError(w, r, "export_id", "Invalid export ID")
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		file, export, err := h.dataExportService.OpenDownload(r.Context(), exportID, query.Get("expires"), query.Get("signature"))
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidDownloadLink):
+				responses.RespondWithError(w, http.StatusForbidden, "Download link is invalid or expired")
+			case errors.Is(err, services.ErrDataExportNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Data export not found")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to download data export")
+			}
+			return
+		}
+		defer file.Close()
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="ezmodel-export-%s.zip"`, export.CreatedAt.Format("2006-01-02")))
+		w.Header().Set("Cache-Control", "private, no-store")
+		if export.SizeBytes > 0 {
+			w.Header().Set("Content-Length", fmt.Sprint(export.SizeBytes))
+		}
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, file); err != nil {
+			log.Printf("Failed to send data export %s: %v", export.ID, err)
+		}
+	}
+}
+
+func (h *DataExportHandler) respond(w http.ResponseWriter, export *models.DataExport, message string) {
+	status := http.StatusOK
+	if export.Status == models.DataExportPending {
+		status = http.StatusAccepted
+	}
+
+	responses.RespondWithSuccess(w, status, message, dto.DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		SizeBytes:   export.SizeBytes,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+		DownloadURL: h.dataExportService.DownloadURL(export),
+	})
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type DataExportHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockDataExportService
+	handler     *DataExportHandler
+	userID      uuid.UUID
+}
+
+func (suite *DataExportHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockDataExportService)
+	suite.handler = NewDataExportHandler(suite.mockService)
+	suite.userID = uuid.New()
+}
+
+func TestDataExportHandlerSuite(t *testing.T) {
+	suite.Run(t, new(DataExportHandlerTestSuite))
+}
+
+func (suite *DataExportHandlerTestSuite) downloadRequest(exportID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/exports/"+exportID.String()+"/download?expires=123&signature=abc", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("export_id", exportID.String())
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+// Test GetMine - An export being built is accepted without a download link
+func (suite *DataExportHandlerTestSuite) TestGetMine_Pending() {
+	export := &models.DataExport{ID: uuid.New(), UserID: suite.userID, Status: models.DataExportPending}
+	suite.mockService.On("GetExport", suite.userID).Return(export, nil)
+	suite.mockService.On("DownloadURL", export).Return("")
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/users/me/export", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetMine()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusAccepted, "Data export retrieved successfully")
+	data, ok := response.Data.(map[string]any)
+	suite.Require().True(ok)
+	assert.Equal(suite.T(), "pending", data["status"])
+	assert.NotContains(suite.T(), data, "download_url")
+}
+
+// Test GetMine - A ready export comes with its download link
+func (suite *DataExportHandlerTestSuite) TestGetMine_Ready() {
+	expiresAt := time.Now().Add(time.Hour)
+	export := &models.DataExport{ID: uuid.New(), UserID: suite.userID, Status: models.DataExportReady, ExpiresAt: &expiresAt}
+	suite.mockService.On("GetExport", suite.userID).Return(export, nil)
+	suite.mockService.On("DownloadURL", export).Return("https://ezmodel.example.com/api/exports/x/download")
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/users/me/export", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetMine()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Data export retrieved successfully")
+	data, ok := response.Data.(map[string]any)
+	suite.Require().True(ok)
+	assert.Equal(suite.T(), "https://ezmodel.example.com/api/exports/x/download", data["download_url"])
+}
+
+// Test Download - The archive is streamed as an attachment
+func (suite *DataExportHandlerTestSuite) TestDownload_Success() {
+	export := &models.DataExport{ID: uuid.New(), Status: models.DataExportReady, SizeBytes: 3,
+		CreatedAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	suite.mockService.On("OpenDownload", mock.Anything, export.ID, "123", "abc").
+		Return(io.NopCloser(strings.NewReader("zip")), export, nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Download()(w, suite.downloadRequest(export.ID))
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "application/zip", w.Header().Get("Content-Type"))
+	assert.Equal(suite.T(), `attachment; filename="ezmodel-export-2026-03-01.zip"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(suite.T(), "zip", w.Body.String())
+}
+
+// Test Download - A bad signature is forbidden
+func (suite *DataExportHandlerTestSuite) TestDownload_InvalidLink() {
+	exportID := uuid.New()
+	suite.mockService.On("OpenDownload", mock.Anything, exportID, "123", "abc").Return(nil, nil, services.ErrInvalidDownloadLink)
+	w := httptest.NewRecorder()
+
+	suite.handler.Download()(w, suite.downloadRequest(exportID))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "Download link is invalid or expired")
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type DocHandler struct {
+	docService services.DocServiceInterface
+}
+
+func NewDocHandler(docService services.DocServiceInterface) *DocHandler {
+	return &DocHandler{
+		docService: docService,
+	}
+}
+
+// Get handles retrieving the project's doc. Supports ?version= for an earlier version.
+func (h *DocHandler) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		version, ok := parseDocVersion(w, r)
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		doc, err := h.docService.GetDoc(projectID, version, userID)
+		if err != nil {
+			respondWithDocError(w, err)
+			return
+		}
+
+		utils.SetVersionETag(w, doc.Version)
+		responses.RespondWithSuccess(w, http.StatusOK, "Doc retrieved successfully", newDocResponse(doc))
+	}
+}
+
+// GetRevisions handles listing the edits of the project's doc, newest first
+func (h *DocHandler) GetRevisions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		revisions, err := h.docService.GetDocRevisions(projectID, userID)
+		if err != nil {
+			respondWithDocError(w, err)
+			return
+		}
+
+		revisionResponses := make([]dto.DocRevisionResponse, len(revisions))
+		for i, revision := range revisions {
+			revisionResponses[i] = dto.DocRevisionResponse{
+				Version:  revision.Version,
+				EditedBy: revision.EditedBy,
+				EditedAt: revision.CreatedAt,
+			}
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Doc revisions retrieved successfully", revisionResponses)
+	}
+}
+
+// Update handles saving a new version of the project's doc
+func (h *DocHandler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.UpdateDocRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		// The If-Match header takes precedence over a version in the body
+		version, ok := utils.ParseIfMatch(w, r)
+		if !ok {
+			return
+		}
+		if version != nil {
+			req.Version = version
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		doc, err := h.docService.UpdateDoc(projectID, &req, userID)
+		if err != nil && !errors.Is(err, services.ErrVersionConflict) {
+			respondWithDocError(w, err)
+			return
+		}
+
+		utils.SetVersionETag(w, doc.Version)
+		if err != nil {
+			responses.RespondWithErrorData(w, http.StatusConflict, "Doc was modified by someone else", newDocResponse(doc))
+			return
+		}
+
+		responses.SetProjectSequence(w, doc.Sequence)
+		responses.RespondWithSuccess(w, http.StatusOK, "Doc updated successfully", newDocResponse(doc))
+	}
+}
+
+// Render handles retrieving the project's doc as HTML. Supports ?version= for an earlier version.
+func (h *DocHandler) Render() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		version, ok := parseDocVersion(w, r)
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		html, err := h.docService.RenderDoc(projectID, version, userID)
+		if err != nil {
+			respondWithDocError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Length", strconv.Itoa(len(html)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(html)
+	}
+}
+
+// parseDocVersion reads the optional ?version= of a doc, 0 for the current
+// one, responding itself when it is not a positive number
+func parseDocVersion(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	value := r.URL.Query().Get("version")
+	if value == "" {
+		return 0, true
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 1 {
+		responses.RespondWithError(w, http.StatusBadRequest, "Version must be a positive number")
+		return 0, false
+	}
+	return version, true
+}
+
+func respondWithDocError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrProjectNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, services.ErrDocRevisionNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Doc version not found")
+	case errors.Is(err, services.ErrForbidden):
+		responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func newDocResponse(doc *models.DocRevision) dto.DocResponse {
+	response := dto.DocResponse{
+		ProjectID: doc.ProjectID,
+		Version:   doc.Version,
+		Content:   doc.Content,
+	}
+	if doc.Version > 0 {
+		editedBy := doc.EditedBy
+		editedAt := doc.CreatedAt
+		response.EditedBy = &editedBy
+		response.EditedAt = &editedAt
+	}
+	return response
+}
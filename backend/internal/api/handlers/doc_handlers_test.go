@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type DocHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockDocService
+	handler     *DocHandler
+	userID      uuid.UUID
+	projectID   uuid.UUID
+}
+
+func (suite *DocHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockDocService)
+	suite.handler = NewDocHandler(suite.mockService)
+	suite.userID = uuid.New()
+	suite.projectID = uuid.New()
+}
+
+func TestDocHandlerSuite(t *testing.T) {
+	suite.Run(t, new(DocHandlerTestSuite))
+}
+
+// withProject adds the project ID to the route context, and the user
+func (suite *DocHandlerTestSuite) withProject(req *http.Request) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", suite.projectID.String())
+	return testutil.WithUserContext(req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)), suite.userID)
+}
+
+// Test Get - A doc never edited has no editor
+func (suite *DocHandlerTestSuite) TestGet_NeverEdited() {
+	suite.mockService.On("GetDoc", suite.projectID, int64(0), suite.userID).Return(&models.DocRevision{ProjectID: suite.projectID}, nil)
+
+	req := suite.withProject(httptest.NewRequest(http.MethodGet, "/projects/"+suite.projectID.String()+"/doc", nil))
+	w := httptest.NewRecorder()
+	suite.handler.Get()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Doc retrieved successfully")
+	data := response.Data.(map[string]any)
+	suite.Equal(float64(0), data["version"])
+	suite.Nil(data["edited_by"])
+	suite.Equal(`"0"`, w.Header().Get("ETag"))
+}
+
+// Test Get - Versions must be positive numbers
+func (suite *DocHandlerTestSuite) TestGet_InvalidVersion() {
+	req := suite.withProject(httptest.NewRequest(http.MethodGet, "/projects/"+suite.projectID.String()+"/doc?version=latest", nil))
+	w := httptest.NewRecorder()
+	suite.handler.Get()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Version must be a positive number")
+	suite.mockService.AssertNotCalled(suite.T(), "GetDoc", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Update - If-Match gives the version the edit was made against
+func (suite *DocHandlerTestSuite) TestUpdate_Success() {
+	version := int64(2)
+	doc := &models.DocRevision{ID: uuid.New(), ProjectID: suite.projectID, Version: 3, Content: "# Why", EditedBy: suite.userID, Sequence: 12}
+	suite.mockService.On("UpdateDoc", suite.projectID, &dto.UpdateDocRequest{Content: "# Why", Version: &version}, suite.userID).Return(doc, nil)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/projects/"+suite.projectID.String()+"/doc", dto.UpdateDocRequest{Content: "# Why"})
+	req.Header.Set("If-Match", `"2"`)
+	w := httptest.NewRecorder()
+	suite.handler.Update()(w, suite.withProject(req))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Doc updated successfully")
+	data := response.Data.(map[string]any)
+	suite.Equal(float64(3), data["version"])
+	suite.Equal(suite.userID.String(), data["edited_by"])
+	suite.Equal(`"3"`, w.Header().Get("ETag"))
+	suite.Equal("12", w.Header().Get("X-Project-Sequence"))
+}
+
+// Test Update - A stale edit is answered with the current doc
+func (suite *DocHandlerTestSuite) TestUpdate_Conflict() {
+	current := &models.DocRevision{ID: uuid.New(), ProjectID: suite.projectID, Version: 5, Content: "Theirs", EditedBy: uuid.New()}
+	suite.mockService.On("UpdateDoc", suite.projectID, mock.AnythingOfType("*dto.UpdateDocRequest"), suite.userID).Return(current, services.ErrVersionConflict)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/projects/"+suite.projectID.String()+"/doc", dto.UpdateDocRequest{Content: "Mine"})
+	w := httptest.NewRecorder()
+	suite.handler.Update()(w, suite.withProject(req))
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "Doc was modified by someone else")
+	suite.Equal("Theirs", response.Data.(map[string]any)["content"])
+	suite.Equal(`"5"`, w.Header().Get("ETag"))
+}
+
+// Test Render - The doc is sent as HTML
+func (suite *DocHandlerTestSuite) TestRender() {
+	suite.mockService.On("RenderDoc", suite.projectID, int64(2), suite.userID).Return([]byte("<h1>Why</h1>\n"), nil)
+
+	req := suite.withProject(httptest.NewRequest(http.MethodGet, "/projects/"+suite.projectID.String()+"/doc/render?version=2", nil))
+	w := httptest.NewRecorder()
+	suite.handler.Render()(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	suite.Equal("<h1>Why</h1>\n", w.Body.String())
+}
+
+// Test Render - Versions the doc never had are not found
+func (suite *DocHandlerTestSuite) TestRender_VersionNotFound() {
+	suite.mockService.On("RenderDoc", suite.projectID, int64(7), suite.userID).Return(nil, services.ErrDocRevisionNotFound)
+
+	req := suite.withProject(httptest.NewRequest(http.MethodGet, "/projects/"+suite.projectID.String()+"/doc/render?version=7", nil))
+	w := httptest.NewRecorder()
+	suite.handler.Render()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusNotFound, "Doc version not found")
+}
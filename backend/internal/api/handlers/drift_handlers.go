@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+)
+
+type DriftHandler struct {
+	driftService services.DriftServiceInterface
+}
+
+func NewDriftHandler(driftService services.DriftServiceInterface) *DriftHandler {
+	return &DriftHandler{
+		driftService: driftService,
+	}
+}
+
+// GetProfile returns how the project's live database is reached, its password hidden
+func (h *DriftHandler) GetProfile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		profile, err := h.driftService.GetProfile(projectID, userID)
+		if err != nil {
+			respondWithDriftError(w, err)
+			return
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Connection profile retrieved successfully", connectionProfileResponse(profile))
+	}
+}
+
+// SetProfile creates or replaces the project's connection profile; owner only
+func (h *DriftHandler) SetProfile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.SetConnectionProfileRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		profile, err := h.driftService.SetProfile(projectID, userID, &req)
+		if err != nil {
+			respondWithDriftError(w, err)
+			return
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Connection profile updated successfully", connectionProfileResponse(profile))
+	}
+}
+
+// DeleteProfile removes the project's connection profile; owner only
+func (h *DriftHandler) DeleteProfile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.driftService.DeleteProfile(projectID, userID); err != nil {
+			respondWithDriftError(w, err)
+			return
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Connection profile deleted successfully", nil)
+	}
+}
+
+// StartCheck starts checking the project's live database for drift from the model
+func (h *DriftHandler) StartCheck() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		check, err := h.driftService.StartCheck(projectID, userID)
+		if err != nil {
+			respondWithDriftError(w, err)
+			return
+		}
+		responses.RespondWithSuccess(w, http.StatusAccepted, "Drift check started successfully", driftCheckResponse(check, nil))
+	}
+}
+
+// GetChecks lists the project's latest drift checks, newest first
+func (h *DriftHandler) GetChecks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		checks, err := h.driftService.GetChecks(projectID, userID)
+		if err != nil {
+			respondWithDriftError(w, err)
+			return
+		}
+
+		checkResponses := make([]dto.DriftCheckResponse, len(checks))
+		for i, check := range checks {
+			checkResponses[i] = driftCheckResponse(check, nil)
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Drift checks retrieved successfully", checkResponses)
+	}
+}
+
+// GetCheck returns a drift check with what differs from the model to the database
+func (h *DriftHandler) GetCheck() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		checkID, ok := utils.ParseUUIDParamWithError(w, r, "check_id", "Invalid drift check ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		check, diff, err := h.driftService.GetCheck(projectID, checkID, userID)
+		if err != nil {
+			respondWithDriftError(w, err)
+			return
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Drift check retrieved successfully", driftCheckResponse(check, diff))
+	}
+}
+
+// StartDataProfile starts reading how much data the tables and fields of the
+// project's live database hold
+func (h *DriftHandler) StartDataProfile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		profile, err := h.driftService.StartDataProfile(projectID, userID)
+		if err != nil {
+			respondWithDriftError(w, err)
+			return
+		}
+		responses.RespondWithSuccess(w, http.StatusAccepted, "Data profiling started successfully", dataProfileResponse(profile))
+	}
+}
+
+// GetDataProfile returns the statistics of the latest profiling, by table and field
+func (h *DriftHandler) GetDataProfile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		profile, err := h.driftService.GetDataProfile(projectID, userID)
+		if err != nil {
+			respondWithDriftError(w, err)
+			return
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Data profile retrieved successfully", dataProfileResponse(profile))
+	}
+}
+
+func respondWithDriftError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrProjectNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, services.ErrConnectionProfileNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Connection profile not found")
+	case errors.Is(err, services.ErrDriftCheckNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Drift check not found")
+	case errors.Is(err, services.ErrForbidden):
+		responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+	case errors.Is(err, services.ErrInvalidWebhookURL):
+		responses.RespondWithError(w, http.StatusBadRequest, "The webhook URL must be https and not point to a private, loopback or link-local address")
+	case errors.Is(err, services.ErrInvalidInput):
+		responses.RespondWithError(w, http.StatusBadRequest, "A postgres:// connection string to a public host is required")
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func connectionProfileResponse(profile *services.ConnectionProfile) dto.ConnectionProfileResponse {
+	return dto.ConnectionProfileResponse{
+		ProjectID:       profile.ProjectID,
+		DSN:             profile.DSN,
+		SchemaName:      profile.SchemaName,
+		ScheduledChecks: profile.ScheduledChecks,
+		WebhookURL:      profile.WebhookURL,
+		WebhookSigned:   profile.WebhookSigned,
+		UpdatedAt:       profile.UpdatedAt,
+	}
+}
+
+func driftCheckResponse(check *models.DriftCheck, diff *schemadiff.Diff) dto.DriftCheckResponse {
+	response := dto.DriftCheckResponse{
+		ID:          check.ID,
+		Status:      check.Status,
+		Trigger:     check.Trigger,
+		Error:       check.Error,
+		CreatedAt:   check.CreatedAt,
+		CompletedAt: check.CompletedAt,
+	}
+	if diff != nil {
+		diffResponse := newSchemaDiffResponse(diff)
+		response.Diff = &diffResponse
+	}
+	return response
+}
+
+// dataProfileResponse groups the field profiles under their tables
+func dataProfileResponse(profile *services.DataProfile) dto.DataProfileResponse {
+	response := dto.DataProfileResponse{
+		Status:     profile.Status,
+		Error:      profile.Error,
+		ProfiledAt: profile.ProfiledAt,
+		Tables:     make([]dto.TableProfileResponse, len(profile.Tables)),
+	}
+	tableIndex := make(map[uuid.UUID]int, len(profile.Tables))
+	for i, table := range profile.Tables {
+		tableIndex[table.TableID] = i
+		response.Tables[i] = dto.TableProfileResponse{TableID: table.TableID, RowCount: table.RowCount, Fields: []dto.FieldProfileResponse{}}
+	}
+	for _, field := range profile.Fields {
+		i, ok := tableIndex[field.TableID]
+		if !ok {
+			continue
+		}
+		response.Tables[i].Fields = append(response.Tables[i].Fields, dto.FieldProfileResponse{
+			FieldID:       field.FieldID,
+			NullRatio:     field.NullRatio,
+			DistinctCount: field.DistinctCount,
+		})
+	}
+	return response
+}
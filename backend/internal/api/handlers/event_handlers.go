@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+)
+
+type EventHandler struct {
+	eventHistoryService services.EventHistoryServiceInterface
+}
+
+func NewEventHandler(eventHistoryService services.EventHistoryServiceInterface) *EventHandler {
+	return &EventHandler{
+		eventHistoryService: eventHistoryService,
+	}
+}
+
+// GetByProjectID handles retrieving the schema-change events of a project after
+// ?since_seq=, oldest first. Supports ?limit=, 100 by default.
+func (h *EventHandler) GetByProjectID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		var sinceSequence int64
+		if value := query.Get("since_seq"); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || parsed < 0 {
+				responses.RespondWithError(w, http.StatusBadRequest, "since_seq must be a sequence number")
+				return
+			}
+			sinceSequence = parsed
+		}
+		limit := services.DefaultEventPageSize
+		if value := query.Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 || parsed > services.MaxEventPageSize {
+				responses.RespondWithError(w, http.StatusBadRequest, "Limit must be between 1 and "+strconv.Itoa(services.MaxEventPageSize))
+				return
+			}
+			limit = parsed
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		history, err := h.eventHistoryService.ListEvents(projectID, userID, sinceSequence, limit)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrProjectNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+			case errors.Is(err, services.ErrInvalidInput):
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve events")
+			}
+			return
+		}
+
+		response := dto.ProjectEventsResponse{
+			Events:         make([]dto.ProjectEventResponse, 0, len(history.Events)),
+			LatestSequence: history.LatestSequence,
+			Truncated:      history.Truncated,
+		}
+		for _, event := range history.Events {
+			eventResponse, err := newProjectEventResponse(event)
+			if err != nil {
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve events")
+				return
+			}
+			response.Events = append(response.Events, eventResponse)
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Events retrieved successfully", response)
+	}
+}
+
+func newProjectEventResponse(event *models.ProjectEvent) (dto.ProjectEventResponse, error) {
+	var message websocketPkg.WebSocketMessage
+	if err := json.Unmarshal(event.Message, &message); err != nil {
+		return dto.ProjectEventResponse{}, err
+	}
+	var data map[string]any
+	if err := json.Unmarshal(message.Data, &data); err != nil {
+		return dto.ProjectEventResponse{}, err
+	}
+	return dto.ProjectEventResponse{
+		Sequence:  event.Sequence,
+		Type:      event.Type,
+		UserID:    message.UserID,
+		Data:      data,
+		CreatedAt: event.CreatedAt,
+	}, nil
+}
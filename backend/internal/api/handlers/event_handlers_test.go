@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type EventHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockEventHistoryService
+	handler     *EventHandler
+	userID      uuid.UUID
+	projectID   uuid.UUID
+}
+
+func (suite *EventHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockEventHistoryService)
+	suite.handler = NewEventHandler(suite.mockService)
+	suite.userID = uuid.New()
+	suite.projectID = uuid.New()
+}
+
+func TestEventHandlerSuite(t *testing.T) {
+	suite.Run(t, new(EventHandlerTestSuite))
+}
+
+func (suite *EventHandlerTestSuite) getEvents(query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/projects/"+suite.projectID.String()+"/events"+query, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", suite.projectID.String())
+	req = testutil.WithUserContext(req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)), suite.userID)
+
+	w := httptest.NewRecorder()
+	suite.handler.GetByProjectID()(w, req)
+	return w
+}
+
+// Test GetByProjectID - Events carry the payload of their WebSocket message
+func (suite *EventHandlerTestSuite) TestGetByProjectID_Success() {
+	tableID := uuid.New()
+	message, err := websocketPkg.NewWebSocketMessage(websocketPkg.MessageTypeTableCreated, websocketPkg.TablePayload{TableID: tableID, Name: "users"}, suite.userID, suite.projectID)
+	suite.Require().NoError(err)
+	message.Sequence = 8
+	data, err := json.Marshal(message)
+	suite.Require().NoError(err)
+
+	history := &services.ProjectEvents{
+		Events:         []*models.ProjectEvent{{ProjectID: suite.projectID, Sequence: 8, Type: string(message.Type), Message: data, CreatedAt: time.Now()}},
+		LatestSequence: 9,
+	}
+	suite.mockService.On("ListEvents", suite.projectID, suite.userID, int64(7), 50).Return(history, nil)
+
+	w := suite.getEvents("?since_seq=7&limit=50")
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Events retrieved successfully")
+	body := response.Data.(map[string]any)
+	suite.Equal(float64(9), body["latest_sequence"])
+	suite.Equal(false, body["truncated"])
+	events := body["events"].([]any)
+	suite.Require().Len(events, 1)
+	event := events[0].(map[string]any)
+	suite.Equal(float64(8), event["sequence"])
+	suite.Equal("table_created", event["type"])
+	suite.Equal(suite.userID.String(), event["user_id"])
+	suite.Equal("users", event["data"].(map[string]any)["name"])
+}
+
+// Test GetByProjectID - since_seq and limit default when absent
+func (suite *EventHandlerTestSuite) TestGetByProjectID_Defaults() {
+	suite.mockService.On("ListEvents", suite.projectID, suite.userID, int64(0), services.DefaultEventPageSize).
+		Return(&services.ProjectEvents{Truncated: true}, nil)
+
+	w := suite.getEvents("")
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Events retrieved successfully")
+	body := response.Data.(map[string]any)
+	suite.Equal(true, body["truncated"])
+	suite.Empty(body["events"])
+}
+
+// Test GetByProjectID - Malformed parameters are rejected before the service is asked
+func (suite *EventHandlerTestSuite) TestGetByProjectID_InvalidQuery() {
+	testutil.AssertErrorResponse(suite.T(), suite.getEvents("?since_seq=-1"), http.StatusBadRequest, "since_seq must be a sequence number")
+	testutil.AssertErrorResponse(suite.T(), suite.getEvents("?limit=5000"), http.StatusBadRequest, "Limit must be between 1 and 1000")
+	suite.mockService.AssertNotCalled(suite.T(), "ListEvents")
+}
+
+// Test GetByProjectID - Only people with access to the project read its events
+func (suite *EventHandlerTestSuite) TestGetByProjectID_Forbidden() {
+	suite.mockService.On("ListEvents", suite.projectID, suite.userID, int64(0), services.DefaultEventPageSize).Return(nil, services.ErrForbidden)
+
+	testutil.AssertErrorResponse(suite.T(), suite.getEvents(""), http.StatusForbidden, "You don't have access to this project")
+}
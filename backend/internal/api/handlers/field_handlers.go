@@ -60,6 +60,8 @@ func (h *FieldHandler) Create() http.HandlerFunc {
 				responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
 			case errors.Is(err, services.ErrForbidden):
 				responses.RespondWithError(w, http.StatusForbidden, "You don't have permission to create fields in this project")
+			case errors.Is(err, services.ErrNameTaken):
+				respondWithTaken(w, err, "A field of the table already has this name")
 			default:
 				responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
 			}
@@ -76,14 +78,82 @@ func (h *FieldHandler) Create() http.HandlerFunc {
 			IsNullable:   field.IsNullable,
 			DefaultValue: field.DefaultValue,
 			Position:     field.Position,
+			Metadata:     metadataResponse(field.Metadata),
 			CreatedAt:    field.CreatedAt,
 			UpdatedAt:    field.UpdatedAt,
+			Version:      field.Version,
 		}
 
+		responses.SetProjectSequence(w, field.Sequence)
 		responses.RespondWithSuccess(w, http.StatusCreated, "Field created successfully", fieldResponse)
 	}
 }
 
+// BulkCreate handles creating several fields in a table at once
+func (h *FieldHandler) BulkCreate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tableID, ok := utils.ParseUUIDParam(w, r, "table_id")
+		if !ok {
+			return
+		}
+
+		var req dto.BulkCreateFieldsRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userIDStr, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			responses.RespondWithError(w, http.StatusUnauthorized, "User context not found")
+			return
+		}
+
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+
+		fields, err := h.fieldService.CreateFields(tableID, req.Fields, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrTableNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Table not found")
+			case errors.Is(err, services.ErrInvalidInput):
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have permission to create fields in this project")
+			case errors.Is(err, services.ErrNameTaken):
+				respondWithTaken(w, err, "A field of the table already has this name")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+			}
+			return
+		}
+
+		fieldResponses := make([]dto.FieldResponse, len(fields))
+		for i, field := range fields {
+			fieldResponses[i] = dto.FieldResponse{
+				ID:           field.ID,
+				TableID:      field.TableID,
+				Name:         field.Name,
+				DataType:     field.DataType,
+				IsPrimaryKey: field.IsPrimaryKey,
+				IsNullable:   field.IsNullable,
+				DefaultValue: field.DefaultValue,
+				Position:     field.Position,
+				Metadata:     metadataResponse(field.Metadata),
+				CreatedAt:    field.CreatedAt,
+				UpdatedAt:    field.UpdatedAt,
+				Version:      field.Version,
+			}
+		}
+
+		responses.SetProjectSequence(w, fields[len(fields)-1].Sequence)
+		responses.RespondWithSuccess(w, http.StatusCreated, "Fields created successfully", fieldResponses)
+	}
+}
+
 // GetByID handles retrieving a specific field
 func (h *FieldHandler) GetByID() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -115,10 +185,13 @@ func (h *FieldHandler) GetByID() http.HandlerFunc {
 			IsNullable:   field.IsNullable,
 			DefaultValue: field.DefaultValue,
 			Position:     field.Position,
+			Metadata:     metadataResponse(field.Metadata),
 			CreatedAt:    field.CreatedAt,
 			UpdatedAt:    field.UpdatedAt,
+			Version:      field.Version,
 		}
 
+		utils.SetVersionETag(w, field.Version)
 		responses.RespondWithSuccess(w, http.StatusOK, "Field retrieved successfully", fieldResponse)
 	}
 }
@@ -151,8 +224,10 @@ func (h *FieldHandler) GetByTableID() http.HandlerFunc {
 				IsNullable:   field.IsNullable,
 				DefaultValue: field.DefaultValue,
 				Position:     field.Position,
+				Metadata:     metadataResponse(field.Metadata),
 				CreatedAt:    field.CreatedAt,
 				UpdatedAt:    field.UpdatedAt,
+				Version:      field.Version,
 			})
 		}
 
@@ -162,6 +237,16 @@ func (h *FieldHandler) GetByTableID() http.HandlerFunc {
 
 // Update handles field updates
 func (h *FieldHandler) Update() http.HandlerFunc {
+	return h.update(utils.DecodeAndValidate)
+}
+
+// Patch applies a JSON Merge Patch to a field
+func (h *FieldHandler) Patch() http.HandlerFunc {
+	return h.update(utils.DecodeMergePatch)
+}
+
+// update reads the changes with decode, which responds itself when they are invalid
+func (h *FieldHandler) update(decode func(http.ResponseWriter, *http.Request, any) bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Get field ID from URL
 		fieldID, ok := utils.ParseUUIDParam(w, r, "field_id")
@@ -171,10 +256,19 @@ func (h *FieldHandler) Update() http.HandlerFunc {
 
 		// Parse and validate request body
 		var req dto.UpdateFieldRequest
-		if !utils.DecodeAndValidate(w, r, &req) {
+		if !decode(w, r, &req) {
 			return
 		}
 
+		// The If-Match header takes precedence over a version in the body
+		version, ok := utils.ParseIfMatch(w, r)
+		if !ok {
+			return
+		}
+		if version != nil {
+			req.Version = version
+		}
+
 		// Get current user ID from context for collaboration
 		userIDStr, ok := middleware.GetUserIDFromContext(r.Context())
 		if !ok {
@@ -190,12 +284,14 @@ func (h *FieldHandler) Update() http.HandlerFunc {
 
 		// Update field through service
 		field, err := h.fieldService.UpdateField(fieldID, &req, userID)
-		if err != nil {
+		if err != nil && !errors.Is(err, services.ErrVersionConflict) {
 			switch {
 			case errors.Is(err, services.ErrFieldNotFound):
 				responses.RespondWithError(w, http.StatusNotFound, "Field not found")
 			case errors.Is(err, services.ErrInvalidInput):
 				responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+			case errors.Is(err, services.ErrNameTaken):
+				respondWithTaken(w, err, "A field of the table already has this name")
 			default:
 				responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
 			}
@@ -212,10 +308,19 @@ func (h *FieldHandler) Update() http.HandlerFunc {
 			IsNullable:   field.IsNullable,
 			DefaultValue: field.DefaultValue,
 			Position:     field.Position,
+			Metadata:     metadataResponse(field.Metadata),
 			CreatedAt:    field.CreatedAt,
 			UpdatedAt:    field.UpdatedAt,
+			Version:      field.Version,
 		}
 
+		utils.SetVersionETag(w, field.Version)
+		if err != nil {
+			responses.RespondWithErrorData(w, http.StatusConflict, "Field was modified by someone else", fieldResponse)
+			return
+		}
+
+		responses.SetProjectSequence(w, field.Sequence)
 		responses.RespondWithSuccess(w, http.StatusOK, "Field updated successfully", fieldResponse)
 	}
 }
@@ -235,8 +340,14 @@ func (h *FieldHandler) Reorder() http.HandlerFunc {
 			return
 		}
 
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
 		// Reorder fields through service
-		if err := h.fieldService.ReorderFields(tableID, req.FieldPositions); err != nil {
+		order, err := h.fieldService.ReorderFields(tableID, req.FieldPositions, req.BaseSequence, userID)
+		if err != nil && !errors.Is(err, services.ErrVersionConflict) {
 			switch {
 			case errors.Is(err, services.ErrTableNotFound):
 				responses.RespondWithError(w, http.StatusNotFound, "Table not found")
@@ -244,13 +355,39 @@ func (h *FieldHandler) Reorder() http.HandlerFunc {
 				responses.RespondWithError(w, http.StatusNotFound, "One or more fields not found")
 			case errors.Is(err, services.ErrInvalidInput):
 				responses.RespondWithError(w, http.StatusBadRequest, "Invalid field positions")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have permission to modify this project")
 			default:
 				responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
 			}
 			return
 		}
 
-		responses.RespondWithSuccess(w, http.StatusOK, "Fields reordered successfully", nil)
+		fieldResponses := make([]dto.FieldResponse, len(order.Fields))
+		for i, field := range order.Fields {
+			fieldResponses[i] = dto.FieldResponse{
+				ID:           field.ID,
+				TableID:      field.TableID,
+				Name:         field.Name,
+				DataType:     field.DataType,
+				IsPrimaryKey: field.IsPrimaryKey,
+				IsNullable:   field.IsNullable,
+				DefaultValue: field.DefaultValue,
+				Position:     field.Position,
+				Metadata:     metadataResponse(field.Metadata),
+				CreatedAt:    field.CreatedAt,
+				UpdatedAt:    field.UpdatedAt,
+				Version:      field.Version,
+			}
+		}
+
+		if err != nil {
+			responses.RespondWithErrorData(w, http.StatusConflict, "Fields were reordered by someone else", fieldResponses)
+			return
+		}
+
+		responses.SetProjectSequence(w, order.Sequence)
+		responses.RespondWithSuccess(w, http.StatusOK, "Fields reordered successfully", fieldResponses)
 	}
 }
 
@@ -277,7 +414,8 @@ func (h *FieldHandler) Delete() http.HandlerFunc {
 		}
 
 		// Delete field through service with authorization check
-		if err := h.fieldService.DeleteField(fieldID, userID); err != nil {
+		sequence, err := h.fieldService.DeleteField(fieldID, userID)
+		if err != nil {
 			switch {
 			case errors.Is(err, services.ErrFieldNotFound):
 				responses.RespondWithError(w, http.StatusNotFound, "Field not found")
@@ -289,6 +427,7 @@ func (h *FieldHandler) Delete() http.HandlerFunc {
 			return
 		}
 
+		responses.SetProjectSequence(w, sequence)
 		responses.RespondWithSuccess(w, http.StatusOK, "Field deleted successfully", nil)
 	}
 }
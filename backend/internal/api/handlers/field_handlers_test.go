@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
 	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
@@ -222,6 +223,51 @@ func (suite *FieldHandlerTestSuite) TestCreate_ServiceError() {
 	suite.mockFieldService.AssertExpectations(suite.T())
 }
 
+// Test BulkCreate - Success
+func (suite *FieldHandlerTestSuite) TestBulkCreate_Success() {
+	tableID := uuid.New()
+	userID := uuid.New()
+	first, second := createValidFieldRequest(), createValidFieldRequest()
+	second.Name, second.Position = "second_field", 2
+	bulkRequest := dto.BulkCreateFieldsRequest{Fields: []dto.CreateFieldRequest{first, second}}
+	fields := []*models.Field{createTestField(tableID), createTestField(tableID)}
+
+	suite.mockFieldService.On("CreateFields", tableID, bulkRequest.Fields, userID).Return(fields, nil)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/tables/"+tableID.String()+"/fields/bulk", bulkRequest)
+	req = testutil.WithUserContext(req, userID)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("table_id", tableID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	w := httptest.NewRecorder()
+	suite.handler.BulkCreate()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "Fields created successfully")
+	fieldResponses, ok := response.Data.([]any)
+	suite.True(ok, "Response data should be a list of fields")
+	suite.Len(fieldResponses, 2)
+
+	suite.mockFieldService.AssertExpectations(suite.T())
+}
+
+// Test BulkCreate - Empty list
+func (suite *FieldHandlerTestSuite) TestBulkCreate_Empty() {
+	tableID := uuid.New()
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/tables/"+tableID.String()+"/fields/bulk", dto.BulkCreateFieldsRequest{})
+	req = testutil.WithUserContext(req, uuid.New())
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("table_id", tableID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	w := httptest.NewRecorder()
+	suite.handler.BulkCreate()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "")
+	suite.mockFieldService.AssertNotCalled(suite.T(), "CreateFields")
+}
+
 // Test GetByID - Success
 func (suite *FieldHandlerTestSuite) TestGetByID_Success() {
 	fieldID := uuid.New()
@@ -437,17 +483,55 @@ func (suite *FieldHandlerTestSuite) TestUpdate_FieldNotFound() {
 // Test Reorder - Success
 func (suite *FieldHandlerTestSuite) TestReorder_Success() {
 	tableID := uuid.New()
+	userID := uuid.New()
+	field1 := &models.Field{ID: uuid.New(), TableID: tableID, Name: "id", Position: 1}
+	field2 := &models.Field{ID: uuid.New(), TableID: tableID, Name: "name", Position: 2}
 	fieldPositions := map[uuid.UUID]int{
-		uuid.New(): 1,
-		uuid.New(): 2,
+		field1.ID: 1,
+		field2.ID: 2,
 	}
 	reorderRequest := dto.ReorderFieldsRequest{
 		FieldPositions: fieldPositions,
 	}
+	order := &services.FieldOrder{TableID: tableID, Fields: []*models.Field{field1, field2}, Sequence: 9}
+
+	suite.mockFieldService.On("ReorderFields", tableID, fieldPositions, (*int64)(nil), userID).Return(order, nil)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/tables/"+tableID.String()+"/fields/reorder", reorderRequest)
+	req = testutil.WithUserContext(req, userID)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("table_id", tableID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	w := httptest.NewRecorder()
+	suite.handler.Reorder()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Fields reordered successfully")
+	fields := response.Data.([]interface{})
+	suite.Len(fields, 2)
+	suite.Equal(field1.ID.String(), fields[0].(map[string]interface{})["field_id"])
+	suite.Equal("9", w.Header().Get(responses.ProjectSequenceHeader))
+	suite.mockFieldService.AssertExpectations(suite.T())
+}
+
+// Test Reorder - A later reorder was confirmed first
+func (suite *FieldHandlerTestSuite) TestReorder_Conflict() {
+	tableID := uuid.New()
+	userID := uuid.New()
+	baseSequence := int64(3)
+	field := &models.Field{ID: uuid.New(), TableID: tableID, Name: "id", Position: 4}
+	reorderRequest := dto.ReorderFieldsRequest{
+		FieldPositions: map[uuid.UUID]int{field.ID: 1},
+		BaseSequence:   &baseSequence,
+	}
+	current := &services.FieldOrder{TableID: tableID, Fields: []*models.Field{field}, Sequence: 5}
 
-	suite.mockFieldService.On("ReorderFields", tableID, fieldPositions).Return(nil)
+	suite.mockFieldService.On("ReorderFields", tableID, reorderRequest.FieldPositions, &baseSequence, userID).
+		Return(current, services.ErrVersionConflict)
 
 	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/tables/"+tableID.String()+"/fields/reorder", reorderRequest)
+	req = testutil.WithUserContext(req, userID)
 
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("table_id", tableID.String())
@@ -456,7 +540,9 @@ func (suite *FieldHandlerTestSuite) TestReorder_Success() {
 	w := httptest.NewRecorder()
 	suite.handler.Reorder()(w, req)
 
-	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Fields reordered successfully")
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "Fields were reordered by someone else")
+	fields := response.Data.([]interface{})
+	suite.Equal(float64(4), fields[0].(map[string]interface{})["position"])
 	suite.mockFieldService.AssertExpectations(suite.T())
 }
 
@@ -491,9 +577,12 @@ func (suite *FieldHandlerTestSuite) TestReorder_TableNotFound() {
 		FieldPositions: fieldPositions,
 	}
 
-	suite.mockFieldService.On("ReorderFields", tableID, fieldPositions).Return(services.ErrTableNotFound)
+	userID := uuid.New()
+
+	suite.mockFieldService.On("ReorderFields", tableID, fieldPositions, (*int64)(nil), userID).Return(nil, services.ErrTableNotFound)
 
 	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/tables/"+tableID.String()+"/fields/reorder", reorderRequest)
+	req = testutil.WithUserContext(req, userID)
 
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("table_id", tableID.String())
@@ -511,7 +600,7 @@ func (suite *FieldHandlerTestSuite) TestDelete_Success() {
 	fieldID := uuid.New()
 	userID := uuid.New()
 
-	suite.mockFieldService.On("DeleteField", fieldID, userID).Return(nil)
+	suite.mockFieldService.On("DeleteField", fieldID, userID).Return(int64(7), nil)
 
 	req := testutil.MakeJSONRequest(suite.T(), http.MethodDelete, "/fields/"+fieldID.String(), nil)
 	req = testutil.WithUserContext(req, userID) // Add user context
@@ -524,6 +613,7 @@ func (suite *FieldHandlerTestSuite) TestDelete_Success() {
 	suite.handler.Delete()(w, req)
 
 	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Field deleted successfully")
+	suite.Equal("7", w.Header().Get(responses.ProjectSequenceHeader))
 	suite.mockFieldService.AssertExpectations(suite.T())
 }
 
@@ -565,7 +655,7 @@ func (suite *FieldHandlerTestSuite) TestDelete_FieldNotFound() {
 	fieldID := uuid.New()
 	userID := uuid.New()
 
-	suite.mockFieldService.On("DeleteField", fieldID, userID).Return(services.ErrFieldNotFound)
+	suite.mockFieldService.On("DeleteField", fieldID, userID).Return(int64(0), services.ErrFieldNotFound)
 
 	req := testutil.MakeJSONRequest(suite.T(), http.MethodDelete, "/fields/"+fieldID.String(), nil)
 	req = testutil.WithUserContext(req, userID)
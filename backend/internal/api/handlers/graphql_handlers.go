@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/graphql"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/validation"
+	"github.com/gorilla/websocket"
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// graphQLTransportWS is the WebSocket subprotocol spoken for subscriptions
+// (https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md)
+const graphQLTransportWS = "graphql-transport-ws"
+
+// graphql-transport-ws message types
+const (
+	gqlConnectionInit = "connection_init"
+	gqlConnectionAck  = "connection_ack"
+	gqlPing           = "ping"
+	gqlPong           = "pong"
+	gqlSubscribe      = "subscribe"
+	gqlNext           = "next"
+	gqlError          = "error"
+	gqlComplete       = "complete"
+)
+
+// graphql-transport-ws close codes
+const (
+	gqlCloseBadRequest        = 4400
+	gqlCloseUnauthorized      = 4401
+	gqlCloseInitTimeout       = 4408
+	gqlCloseSubscriberExists  = 4409
+	gqlCloseTooManyInitialise = 4429
+)
+
+type graphQLMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type GraphQLHandler struct {
+	schema   gql.Schema
+	upgrader websocket.Upgrader
+
+	writeWait      time.Duration
+	pongWait       time.Duration
+	pingPeriod     time.Duration
+	initTimeout    time.Duration
+	maxMessageSize int64
+}
+
+func NewGraphQLHandler(cfg *config.Config, schema gql.Schema) *GraphQLHandler {
+	h := &GraphQLHandler{
+		schema:         schema,
+		writeWait:      cfg.WebSocket.WriteWait,
+		pongWait:       cfg.WebSocket.PongWait,
+		pingPeriod:     cfg.WebSocket.PingPeriod,
+		initTimeout:    cfg.WebSocket.AuthTimeout,
+		maxMessageSize: cfg.WebSocket.MaxMessageSize,
+	}
+
+	h.upgrader = websocket.Upgrader{
+		ReadBufferSize:  cfg.WebSocket.ReadBufferSize,
+		WriteBufferSize: cfg.WebSocket.WriteBufferSize,
+		Subprotocols:    []string{graphQLTransportWS},
+		CheckOrigin: func(r *http.Request) bool {
+			return originAllowed(r, cfg.AllowedOrigins)
+		},
+	}
+
+	return h
+}
+
+// Query executes a query or mutation posted as JSON. Like other GraphQL servers
+// it answers 200 with an errors list; only unreadable requests get a 400.
+func (h *GraphQLHandler) Query() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req dto.GraphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeGraphQLResult(w, http.StatusBadRequest, graphQLErrorResult("Invalid request body"))
+			return
+		}
+		if err := validation.Validate(&req); err != nil {
+			writeGraphQLResult(w, http.StatusBadRequest, graphQLErrorResult("A query is required"))
+			return
+		}
+
+		writeGraphQLResult(w, http.StatusOK, graphql.Execute(r.Context(), h.schema, req))
+	}
+}
+
+func writeGraphQLResult(w http.ResponseWriter, code int, result *gql.Result) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(result)
+}
+
+func graphQLErrorResult(message string) *gql.Result {
+	return &gql.Result{Errors: []gqlerrors.FormattedError{{Message: message}}}
+}
+
+// Subscribe upgrades to a WebSocket speaking graphql-transport-ws. The request
+// was authenticated by middleware, so connection_init carries no credentials.
+func (h *GraphQLHandler) Subscribe() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := h.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Printf("GraphQL: Failed to upgrade connection: %v", err)
+			return
+		}
+		if conn.Subprotocol() != graphQLTransportWS {
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseProtocolError, "Unsupported subprotocol"),
+				time.Now().Add(h.writeWait))
+			conn.Close()
+			return
+		}
+
+		// The request's values, including the authenticated user, outlive the
+		// request itself for as long as the connection is open
+		ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+		defer cancel()
+
+		s := &graphQLSession{handler: h, conn: conn, ctx: ctx, operations: make(map[string]context.CancelFunc)}
+		s.run()
+	}
+}
+
+// graphQLSession is one graphql-transport-ws connection
+type graphQLSession struct {
+	handler *GraphQLHandler
+	conn    *websocket.Conn
+	ctx     context.Context
+
+	writeMu sync.Mutex
+
+	mu           sync.Mutex
+	acknowledged bool
+	operations   map[string]context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+func (s *graphQLSession) run() {
+	defer func() {
+		s.mu.Lock()
+		for _, cancel := range s.operations {
+			cancel()
+		}
+		s.mu.Unlock()
+		s.wg.Wait()
+		s.conn.Close()
+	}()
+
+	s.conn.SetReadLimit(s.handler.maxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(s.handler.pongWait))
+	s.conn.SetPongHandler(func(string) error {
+		s.conn.SetReadDeadline(time.Now().Add(s.handler.pongWait))
+		return nil
+	})
+
+	// Closing with the protocol's code, rather than letting a read deadline expire
+	initTimer := time.AfterFunc(s.handler.initTimeout, func() {
+		s.mu.Lock()
+		acknowledged := s.acknowledged
+		s.mu.Unlock()
+		if !acknowledged {
+			s.close(gqlCloseInitTimeout, "Connection initialisation timeout")
+		}
+	})
+	defer initTimer.Stop()
+
+	go s.keepAlive()
+
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Printf("GraphQL: WebSocket error: %v", err)
+			}
+			return
+		}
+
+		var message graphQLMessage
+		if err := json.Unmarshal(data, &message); err != nil || message.Type == "" {
+			s.close(gqlCloseBadRequest, "Invalid message received")
+			return
+		}
+		if !s.handle(message) {
+			return
+		}
+	}
+}
+
+// handle processes one client message and reports whether the connection stays open
+func (s *graphQLSession) handle(message graphQLMessage) bool {
+	switch message.Type {
+	case gqlConnectionInit:
+		s.mu.Lock()
+		repeated := s.acknowledged
+		s.acknowledged = true
+		s.mu.Unlock()
+		if repeated {
+			s.close(gqlCloseTooManyInitialise, "Too many initialisation requests")
+			return false
+		}
+		s.write(graphQLMessage{Type: gqlConnectionAck})
+
+	case gqlPing:
+		s.write(graphQLMessage{Type: gqlPong})
+
+	case gqlPong:
+		// Nothing to do
+
+	case gqlSubscribe:
+		s.mu.Lock()
+		acknowledged := s.acknowledged
+		_, exists := s.operations[message.ID]
+		s.mu.Unlock()
+
+		if !acknowledged {
+			s.close(gqlCloseUnauthorized, "Unauthorized")
+			return false
+		}
+		if message.ID == "" {
+			s.close(gqlCloseBadRequest, "Invalid message received")
+			return false
+		}
+		if exists {
+			s.close(gqlCloseSubscriberExists, fmt.Sprintf("Subscriber for %s already exists", message.ID))
+			return false
+		}
+
+		var req dto.GraphQLRequest
+		if err := json.Unmarshal(message.Payload, &req); err != nil || validation.Validate(&req) != nil {
+			s.close(gqlCloseBadRequest, "Invalid message received")
+			return false
+		}
+		s.start(message.ID, req)
+
+	case gqlComplete:
+		s.mu.Lock()
+		if cancel, ok := s.operations[message.ID]; ok {
+			cancel()
+			delete(s.operations, message.ID)
+		}
+		s.mu.Unlock()
+
+	default:
+		s.close(gqlCloseBadRequest, "Invalid message received")
+		return false
+	}
+	return true
+}
+
+// start runs an operation, streaming its results until it ends or the client completes it
+func (s *graphQLSession) start(id string, req dto.GraphQLRequest) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.mu.Lock()
+	s.operations[id] = cancel
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+
+		first, failed := true, false
+		// Drained to the end: the executor blocks until every result is received
+		for result := range graphql.Subscribe(ctx, s.handler.schema, req) {
+			if failed || ctx.Err() != nil {
+				continue
+			}
+			if first && result.Data == nil && result.HasErrors() {
+				// Rejected before execution, e.g. by validation, so the operation ends without complete
+				payload, _ := json.Marshal(result.Errors)
+				s.write(graphQLMessage{ID: id, Type: gqlError, Payload: payload})
+				failed = true
+				continue
+			}
+			first = false
+
+			payload, _ := json.Marshal(result)
+			s.write(graphQLMessage{ID: id, Type: gqlNext, Payload: payload})
+		}
+
+		if failed {
+			s.finish(id)
+			return
+		}
+		// A client that completed the operation expects nothing more for it
+		if s.finish(id) {
+			s.write(graphQLMessage{ID: id, Type: gqlComplete})
+		}
+	}()
+}
+
+// finish forgets an operation, reporting whether it was still running
+func (s *graphQLSession) finish(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.operations[id]; !ok {
+		return false
+	}
+	delete(s.operations, id)
+	return true
+}
+
+// keepAlive pings the client until the connection ends
+func (s *graphQLSession) keepAlive() {
+	ticker := time.NewTicker(s.handler.pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.writeMu.Lock()
+			err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(s.handler.writeWait))
+			s.writeMu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *graphQLSession) write(message graphQLMessage) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.conn.SetWriteDeadline(time.Now().Add(s.handler.writeWait))
+	if err := s.conn.WriteJSON(message); err != nil {
+		log.Printf("GraphQL: Failed to write %s message: %v", message.Type, err)
+	}
+}
+
+func (s *graphQLSession) close(code int, reason string) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(s.handler.writeWait))
+	s.conn.Close()
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/graphql"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/suite"
+)
+
+const testOrigin = "http://localhost:3000"
+
+type GraphQLHandlerTestSuite struct {
+	suite.Suite
+	mockProjectService *mockService.MockProjectService
+	mockAuthService    *mockService.MockAuthorizationService
+	handler            *GraphQLHandler
+	userID             uuid.UUID
+}
+
+func (suite *GraphQLHandlerTestSuite) SetupTest() {
+	suite.mockProjectService = new(mockService.MockProjectService)
+	suite.mockAuthService = new(mockService.MockAuthorizationService)
+
+	schema, err := graphql.NewSchema(graphql.NewResolver(suite.mockProjectService, new(mockService.MockTableService),
+		new(mockService.MockFieldService), new(mockService.MockRelationshipService), suite.mockAuthService, websocketPkg.NewHub()))
+	suite.Require().NoError(err)
+
+	cfg := config.New()
+	cfg.AllowedOrigins = []string{testOrigin}
+	cfg.WebSocket.AuthTimeout = 200 * time.Millisecond
+	suite.handler = NewGraphQLHandler(cfg, schema)
+	suite.userID = uuid.New()
+}
+
+func TestGraphQLHandlerSuite(t *testing.T) {
+	suite.Run(t, new(GraphQLHandlerTestSuite))
+}
+
+func (suite *GraphQLHandlerTestSuite) TestQuery_Success() {
+	project := testutil.CreateTestProject(suite.userID)
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, project.ID).Return(true, nil)
+	suite.mockProjectService.On("GetProjectByID", project.ID).Return(project, nil)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/graphql", dto.GraphQLRequest{
+		Query:     `query($id: ID!) { project(id: $id) { name } }`,
+		Variables: map[string]any{"id": project.ID.String()},
+	})
+	req = testutil.WithUserContext(req, suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.Query()(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	var response map[string]any
+	testutil.ParseJSONResponse(suite.T(), w, &response)
+	suite.Nil(response["errors"])
+	suite.Equal("Test Project", response["data"].(map[string]any)["project"].(map[string]any)["name"])
+}
+
+func (suite *GraphQLHandlerTestSuite) TestQuery_MissingQuery() {
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/graphql", dto.GraphQLRequest{})
+	w := httptest.NewRecorder()
+
+	suite.handler.Query()(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "A query is required")
+}
+
+// connect opens a graphql-transport-ws connection as the suite's user
+func (suite *GraphQLHandlerTestSuite) connect() *websocket.Conn {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.handler.Subscribe()(w, testutil.WithUserContext(r, suite.userID))
+	}))
+	suite.T().Cleanup(server.Close)
+
+	dialer := websocket.Dialer{Subprotocols: []string{graphQLTransportWS}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), http.Header{"Origin": {testOrigin}})
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func (suite *GraphQLHandlerTestSuite) send(conn *websocket.Conn, message graphQLMessage) {
+	suite.Require().NoError(conn.WriteJSON(message))
+}
+
+func (suite *GraphQLHandlerTestSuite) read(conn *websocket.Conn) graphQLMessage {
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	var message graphQLMessage
+	suite.Require().NoError(conn.ReadJSON(&message))
+	return message
+}
+
+func (suite *GraphQLHandlerTestSuite) TestSubscribe_QueryOverWebSocket() {
+	project := testutil.CreateTestProject(suite.userID)
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, project.ID).Return(true, nil)
+	suite.mockProjectService.On("GetProjectByID", project.ID).Return(project, nil)
+
+	conn := suite.connect()
+	suite.send(conn, graphQLMessage{Type: gqlConnectionInit})
+	suite.Equal(gqlConnectionAck, suite.read(conn).Type)
+
+	suite.send(conn, graphQLMessage{Type: gqlPing})
+	suite.Equal(gqlPong, suite.read(conn).Type)
+
+	payload, _ := json.Marshal(dto.GraphQLRequest{Query: `{ project(id: "` + project.ID.String() + `") { name } }`})
+	suite.send(conn, graphQLMessage{ID: "1", Type: gqlSubscribe, Payload: payload})
+
+	next := suite.read(conn)
+	suite.Equal(gqlNext, next.Type)
+	suite.Equal("1", next.ID)
+	suite.Contains(string(next.Payload), "Test Project")
+
+	complete := suite.read(conn)
+	suite.Equal(gqlComplete, complete.Type)
+	suite.Equal("1", complete.ID)
+}
+
+func (suite *GraphQLHandlerTestSuite) TestSubscribe_ValidationErrorEndsOperation() {
+	conn := suite.connect()
+	suite.send(conn, graphQLMessage{Type: gqlConnectionInit})
+	suite.Equal(gqlConnectionAck, suite.read(conn).Type)
+
+	payload, _ := json.Marshal(dto.GraphQLRequest{Query: `{ no_such_field }`})
+	suite.send(conn, graphQLMessage{ID: "1", Type: gqlSubscribe, Payload: payload})
+
+	message := suite.read(conn)
+	suite.Equal(gqlError, message.Type)
+	suite.Equal("1", message.ID)
+}
+
+func (suite *GraphQLHandlerTestSuite) TestSubscribe_BeforeInitIsUnauthorized() {
+	conn := suite.connect()
+
+	payload, _ := json.Marshal(dto.GraphQLRequest{Query: `{ projects { id } }`})
+	suite.send(conn, graphQLMessage{ID: "1", Type: gqlSubscribe, Payload: payload})
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := conn.ReadMessage()
+	suite.True(websocket.IsCloseError(err, gqlCloseUnauthorized), "unexpected error: %v", err)
+}
+
+func (suite *GraphQLHandlerTestSuite) TestSubscribe_InitTimeout() {
+	conn := suite.connect()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := conn.ReadMessage()
+	suite.True(websocket.IsCloseError(err, gqlCloseInitTimeout), "unexpected error: %v", err)
+}
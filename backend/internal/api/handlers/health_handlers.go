@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+)
+
+// readinessTimeout bounds all readiness checks together, below the usual probe timeout
+const readinessTimeout = 2 * time.Second
+
+// ReadinessCheck reports whether a dependency can serve requests
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type HealthHandler struct {
+	checks []ReadinessCheck
+}
+
+func NewHealthHandler(checks []ReadinessCheck) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+	}
+}
+
+// Liveness responds as long as the process serves HTTP, so a restart only
+// follows a hang and not an outage of a dependency
+func (h *HealthHandler) Liveness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		responses.RespondWithSuccess(w, http.StatusOK, "OK", nil)
+	}
+}
+
+// Readiness runs every check and responds with 503 when any fails, so load
+// balancers stop routing to instances that are shutting down or cut off from
+// their dependencies. The data maps each check to "ok" or its error.
+func (h *HealthHandler) Readiness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		defer cancel()
+
+		results := make(map[string]string, len(h.checks))
+		ready := true
+		for _, check := range h.checks {
+			if err := check.Check(ctx); err != nil {
+				results[check.Name] = err.Error()
+				ready = false
+				continue
+			}
+			results[check.Name] = "ok"
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		if !ready {
+			responses.RespondWithErrorData(w, http.StatusServiceUnavailable, "Not ready", results)
+			return
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Ready", results)
+	}
+}
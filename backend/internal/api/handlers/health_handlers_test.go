@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthHandlerTestSuite struct {
+	suite.Suite
+	redisErr error
+	handler  *HealthHandler
+}
+
+func (suite *HealthHandlerTestSuite) SetupTest() {
+	suite.redisErr = nil
+	suite.handler = NewHealthHandler([]ReadinessCheck{
+		{Name: "database", Check: func(ctx context.Context) error { return nil }},
+		{Name: "redis", Check: func(ctx context.Context) error { return suite.redisErr }},
+	})
+}
+
+func TestHealthHandlerSuite(t *testing.T) {
+	suite.Run(t, new(HealthHandlerTestSuite))
+}
+
+// Test Liveness - Responds without running the checks
+func (suite *HealthHandlerTestSuite) TestLiveness() {
+	suite.redisErr = errors.New("connection refused")
+	w := httptest.NewRecorder()
+
+	suite.handler.Liveness()(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "OK")
+}
+
+// Test Readiness - Every check passing
+func (suite *HealthHandlerTestSuite) TestReadiness_Ready() {
+	w := httptest.NewRecorder()
+
+	suite.handler.Readiness()(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Ready")
+	assert.Equal(suite.T(), map[string]any{"database": "ok", "redis": "ok"}, response.Data)
+}
+
+// Test Readiness - A failing check makes the instance unavailable
+func (suite *HealthHandlerTestSuite) TestReadiness_NotReady() {
+	suite.redisErr = errors.New("connection refused")
+	w := httptest.NewRecorder()
+
+	suite.handler.Readiness()(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusServiceUnavailable, "Not ready")
+	assert.Equal(suite.T(), map[string]any{"database": "ok", "redis": "connection refused"}, response.Data)
+}
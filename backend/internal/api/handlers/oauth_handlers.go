@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oauthStateCookie    = "oauth_state"
+	oauthVerifierCookie = "oauth_verifier"
+	oauthCookiePath     = "/api/auth/oauth"
+	oauthCookieMaxAge   = 10 * 60 // Seconds the user has to complete the provider login
+)
+
+type OAuthHandler struct {
+	oauthService       services.OAuthServiceInterface
+	userSessionService services.UserSessionServiceInterface
+	jwtService         services.JWTServiceInterface
+	cfg                *config.Config
+}
+
+func NewOAuthHandler(oauthService services.OAuthServiceInterface, userSessionService services.UserSessionServiceInterface, jwtService services.JWTServiceInterface, cfg *config.Config) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService:       oauthService,
+		userSessionService: userSessionService,
+		jwtService:         jwtService,
+		cfg:                cfg,
+	}
+}
+
+// Start redirects the browser to the provider's consent page. The state and
+// PKCE verifier are kept in short-lived cookies until the callback.
+func (h *OAuthHandler) Start() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		provider := chi.URLParam(r, "provider")
+		state := oauth2.GenerateVerifier()
+		verifier := oauth2.GenerateVerifier()
+
+		authURL, err := h.oauthService.AuthCodeURL(provider, state, verifier)
+		if err != nil {
+			if errors.Is(err, services.ErrUnknownOAuthProvider) {
+				responses.RespondWithError(w, http.StatusNotFound, "Unknown login provider")
+			} else {
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to start login")
+			}
+			return
+		}
+
+		h.setOAuthCookie(w, oauthStateCookie, state, oauthCookieMaxAge)
+		h.setOAuthCookie(w, oauthVerifierCookie, verifier, oauthCookieMaxAge)
+
+		http.Redirect(w, r, authURL, http.StatusFound)
+	}
+}
+
+// Callback completes the provider login, issues the usual auth cookies and
+// redirects back to the frontend. Failures are reported to the frontend in the
+// oauth_error query parameter.
+func (h *OAuthHandler) Callback() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		provider := chi.URLParam(r, "provider")
+		query := r.URL.Query()
+
+		stateCookie, stateErr := r.Cookie(oauthStateCookie)
+		verifierCookie, verifierErr := r.Cookie(oauthVerifierCookie)
+
+		// The state and verifier are single use
+		h.setOAuthCookie(w, oauthStateCookie, "", -1)
+		h.setOAuthCookie(w, oauthVerifierCookie, "", -1)
+
+		if query.Get("error") != "" {
+			h.redirectToFrontend(w, r, "access_denied")
+			return
+		}
+		if stateErr != nil || verifierErr != nil || stateCookie.Value == "" || stateCookie.Value != query.Get("state") {
+			h.redirectToFrontend(w, r, "invalid_state")
+			return
+		}
+		if query.Get("code") == "" {
+			h.redirectToFrontend(w, r, "missing_code")
+			return
+		}
+
+		user, err := h.oauthService.Authenticate(r.Context(), provider, query.Get("code"), verifierCookie.Value)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrUnknownOAuthProvider):
+				h.redirectToFrontend(w, r, "unknown_provider")
+			case errors.Is(err, services.ErrOAuthEmailNotVerified):
+				h.redirectToFrontend(w, r, "email_not_verified")
+			default:
+				log.Printf("OAuth login with %s failed: %v", provider, err)
+				h.redirectToFrontend(w, r, "login_failed")
+			}
+			return
+		}
+
+		tokens, err := h.userSessionService.StartSession(user, utils.ClientIP(r, h.cfg.RateLimit.TrustProxy), r.UserAgent())
+		if err != nil {
+			if errors.Is(err, services.ErrAccountDisabled) {
+				h.redirectToFrontend(w, r, "account_disabled")
+			} else {
+				h.redirectToFrontend(w, r, "login_failed")
+			}
+			return
+		}
+
+		setAuthCookies(w, h.cfg, h.jwtService, tokens)
+		h.redirectToFrontend(w, r, "")
+	}
+}
+
+// setOAuthCookie sets a cookie scoped to the OAuth routes. SameSite=Lax lets
+// the browser send it on the top-level redirect back from the provider.
+func (h *OAuthHandler) setOAuthCookie(w http.ResponseWriter, name, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     oauthCookiePath,
+		HttpOnly: true,
+		Secure:   h.cfg.Env == "production",
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   maxAge,
+	})
+}
+
+// redirectToFrontend sends the browser back to the frontend, with an error code if login failed
+func (h *OAuthHandler) redirectToFrontend(w http.ResponseWriter, r *http.Request, errorCode string) {
+	target := h.cfg.OAuth.FrontendURL
+	if errorCode != "" {
+		if u, err := url.Parse(target); err == nil {
+			q := u.Query()
+			q.Set("oauth_error", errorCode)
+			u.RawQuery = q.Encode()
+			target = u.String()
+		}
+	}
+
+	http.Redirect(w, r, target, http.StatusFound)
+}
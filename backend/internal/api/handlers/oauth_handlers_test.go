@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type OAuthHandlerTestSuite struct {
+	suite.Suite
+	mockOAuthService   *mockService.MockOAuthService
+	mockSessionService *mockService.MockUserSessionService
+	mockJWTService     *mockService.MockJWTService
+	handler            *OAuthHandler
+	cfg                *config.Config
+}
+
+func (suite *OAuthHandlerTestSuite) SetupTest() {
+	suite.mockOAuthService = new(mockService.MockOAuthService)
+	suite.mockSessionService = new(mockService.MockUserSessionService)
+	suite.mockJWTService = new(mockService.MockJWTService)
+	suite.cfg = config.New()
+	suite.cfg.OAuth.FrontendURL = "http://app.example.com/"
+	suite.handler = NewOAuthHandler(suite.mockOAuthService, suite.mockSessionService, suite.mockJWTService, suite.cfg)
+}
+
+func TestOAuthHandlerSuite(t *testing.T) {
+	suite.Run(t, new(OAuthHandlerTestSuite))
+}
+
+func (suite *OAuthHandlerTestSuite) withProvider(req *http.Request, provider string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("provider", provider)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func (suite *OAuthHandlerTestSuite) getCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, c := range cookies {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (suite *OAuthHandlerTestSuite) oauthErrorFrom(w *httptest.ResponseRecorder) string {
+	location, err := url.Parse(w.Header().Get("Location"))
+	suite.Require().NoError(err)
+	return location.Query().Get("oauth_error")
+}
+
+// Test Start - Redirects to provider and stores state and verifier
+func (suite *OAuthHandlerTestSuite) TestStart_Success() {
+	suite.mockOAuthService.On("AuthCodeURL", "github", mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		Return("https://github.com/login/oauth/authorize?state=x", nil)
+
+	req := suite.withProvider(httptest.NewRequest(http.MethodGet, "/api/auth/oauth/github/start", nil), "github")
+	w := httptest.NewRecorder()
+
+	suite.handler.Start()(w, req)
+
+	assert.Equal(suite.T(), http.StatusFound, w.Code)
+	assert.Equal(suite.T(), "https://github.com/login/oauth/authorize?state=x", w.Header().Get("Location"))
+
+	stateCookie := suite.getCookie(w.Result().Cookies(), oauthStateCookie)
+	verifierCookie := suite.getCookie(w.Result().Cookies(), oauthVerifierCookie)
+	suite.Require().NotNil(stateCookie)
+	suite.Require().NotNil(verifierCookie)
+	assert.True(suite.T(), stateCookie.HttpOnly)
+	assert.Equal(suite.T(), http.SameSiteLaxMode, stateCookie.SameSite)
+
+	// State and verifier passed to the service match the cookies
+	call := suite.mockOAuthService.Calls[0]
+	assert.Equal(suite.T(), stateCookie.Value, call.Arguments.String(1))
+	assert.Equal(suite.T(), verifierCookie.Value, call.Arguments.String(2))
+}
+
+// Test Start - Unknown provider
+func (suite *OAuthHandlerTestSuite) TestStart_UnknownProvider() {
+	suite.mockOAuthService.On("AuthCodeURL", "myspace", mock.Anything, mock.Anything).
+		Return("", services.ErrUnknownOAuthProvider)
+
+	req := suite.withProvider(httptest.NewRequest(http.MethodGet, "/api/auth/oauth/myspace/start", nil), "myspace")
+	w := httptest.NewRecorder()
+
+	suite.handler.Start()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusNotFound, "Unknown login provider")
+}
+
+// Test Callback - State mismatch is rejected
+func (suite *OAuthHandlerTestSuite) TestCallback_InvalidState() {
+	req := suite.withProvider(httptest.NewRequest(http.MethodGet, "/api/auth/oauth/github/callback?state=forged&code=abc", nil), "github")
+	req.AddCookie(&http.Cookie{Name: oauthStateCookie, Value: "expected"})
+	req.AddCookie(&http.Cookie{Name: oauthVerifierCookie, Value: "verifier"})
+	w := httptest.NewRecorder()
+
+	suite.handler.Callback()(w, req)
+
+	assert.Equal(suite.T(), http.StatusFound, w.Code)
+	assert.Equal(suite.T(), "invalid_state", suite.oauthErrorFrom(w))
+	suite.mockOAuthService.AssertNotCalled(suite.T(), "Authenticate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Callback - Unverified email
+func (suite *OAuthHandlerTestSuite) TestCallback_EmailNotVerified() {
+	suite.mockOAuthService.On("Authenticate", mock.Anything, "github", "abc", "verifier").
+		Return(nil, services.ErrOAuthEmailNotVerified)
+
+	req := suite.withProvider(httptest.NewRequest(http.MethodGet, "/api/auth/oauth/github/callback?state=s1&code=abc", nil), "github")
+	req.AddCookie(&http.Cookie{Name: oauthStateCookie, Value: "s1"})
+	req.AddCookie(&http.Cookie{Name: oauthVerifierCookie, Value: "verifier"})
+	w := httptest.NewRecorder()
+
+	suite.handler.Callback()(w, req)
+
+	assert.Equal(suite.T(), "email_not_verified", suite.oauthErrorFrom(w))
+}
+
+// Test Callback - Success issues auth cookies and redirects to the frontend
+func (suite *OAuthHandlerTestSuite) TestCallback_Success() {
+	user := testutil.CreateTestUser()
+	tokens := &services.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
+
+	suite.mockOAuthService.On("Authenticate", mock.Anything, "github", "abc", "verifier").Return(user, nil)
+	suite.mockSessionService.On("StartSession", user, mock.Anything, mock.Anything).Return(tokens, nil)
+	suite.mockJWTService.On("GetAccessTokenExpiration").Return(15 * time.Minute)
+	suite.mockJWTService.On("GetRefreshTokenExpiration").Return(7 * 24 * time.Hour)
+
+	req := suite.withProvider(httptest.NewRequest(http.MethodGet, "/api/auth/oauth/github/callback?state=s1&code=abc", nil), "github")
+	req.AddCookie(&http.Cookie{Name: oauthStateCookie, Value: "s1"})
+	req.AddCookie(&http.Cookie{Name: oauthVerifierCookie, Value: "verifier"})
+	w := httptest.NewRecorder()
+
+	suite.handler.Callback()(w, req)
+
+	assert.Equal(suite.T(), http.StatusFound, w.Code)
+	assert.Equal(suite.T(), "http://app.example.com/", w.Header().Get("Location"))
+
+	accessCookie := suite.getCookie(w.Result().Cookies(), "access_token")
+	suite.Require().NotNil(accessCookie)
+	assert.Equal(suite.T(), "access", accessCookie.Value)
+
+	// State cookie is cleared
+	stateCookie := suite.getCookie(w.Result().Cookies(), oauthStateCookie)
+	suite.Require().NotNil(stateCookie)
+	assert.Equal(suite.T(), -1, stateCookie.MaxAge)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/openapi"
+)
+
+// swaggerUIVersion pins the Swagger UI assets loaded by the docs page
+const swaggerUIVersion = "5.17.14"
+
+var docsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>EzModel API</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@{{.Version}}/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@{{.Version}}/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: {{.SpecURL}}, dom_id: '#swagger-ui', withCredentials: true });
+    };
+  </script>
+</body>
+</html>
+`))
+
+// OpenAPIHandler serves the OpenAPI document. It is encoded once, up front.
+func OpenAPIHandler(document any) http.HandlerFunc {
+	body, err := json.Marshal(document)
+	if err != nil {
+		panic(fmt.Sprintf("encoding OpenAPI document: %v", err))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+}
+
+// DocsHandler serves Swagger UI for the OpenAPI document at specURL
+func DocsHandler(specURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		docsTemplate.Execute(w, struct {
+			Version string
+			SpecURL string
+		}{swaggerUIVersion, specURL})
+	}
+}
+
+// TypeScriptClientHandler serves the TypeScript client generated from the document.
+// The spec hash is its ETag, so unchanged clients are not downloaded again.
+func TypeScriptClientHandler(document *openapi.Document) http.HandlerFunc {
+	source, err := openapi.TypeScript(document)
+	if err != nil {
+		panic(fmt.Sprintf("generating TypeScript client: %v", err))
+	}
+	hash, err := openapi.SpecHash(document)
+	if err != nil {
+		panic(fmt.Sprintf("hashing OpenAPI document: %v", err))
+	}
+	etag := `"` + hash + `"`
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="ezmodel-client.ts"`)
+		w.Write(source)
+	}
+}
@@ -4,22 +4,32 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
 	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
 	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
 	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/canvas"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 type ProjectHandler struct {
 	projectService services.ProjectServiceInterface
+	hub            *websocketPkg.Hub
 }
 
-func NewProjectHandler(projectService services.ProjectServiceInterface) *ProjectHandler {
+func NewProjectHandler(projectService services.ProjectServiceInterface, hub *websocketPkg.Hub) *ProjectHandler {
 	return &ProjectHandler{
 		projectService: projectService,
+		hub:            hub,
 	}
 }
 
@@ -52,6 +62,8 @@ func (h *ProjectHandler) Create() http.HandlerFunc {
 				responses.RespondWithError(w, http.StatusBadRequest, "Invalid owner")
 			case errors.Is(err, services.ErrInvalidInput):
 				responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+			case errors.Is(err, services.ErrQuotaExceeded):
+				respondWithQuotaExceeded(w, err)
 			default:
 				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to create project")
 			}
@@ -59,14 +71,7 @@ func (h *ProjectHandler) Create() http.HandlerFunc {
 		}
 
 		// Create project response
-		projectResponse := dto.ProjectSummaryResponse{
-			ID:          project.ID,
-			Name:        project.Name,
-			Description: project.Description,
-			OwnerID:     project.OwnerID,
-			CreatedAt:   project.CreatedAt,
-			UpdatedAt:   project.UpdatedAt,
-		}
+		projectResponse := newProjectSummaryResponse(project)
 
 		responses.RespondWithSuccess(w, http.StatusCreated, "Project created successfully", projectResponse)
 	}
@@ -89,91 +94,30 @@ func (h *ProjectHandler) GetByID() http.HandlerFunc {
 			return
 		}
 
-		// Convert to response format with full details
-		var collaboratorResponses []dto.UserResponse
-		for _, collaborator := range project.Collaborators {
-			collaboratorResponses = append(collaboratorResponses, dto.UserResponse{
-				ID:       collaborator.ID,
-				Email:    collaborator.Email,
-				Username: collaborator.Username,
-			})
-		}
-
-		// Convert tables with fields
-		var tableResponses []dto.TableWithFieldsResponse
-		for _, table := range project.Tables {
-			var fieldResponses []dto.FieldResponse
-			for _, field := range table.Fields {
-				fieldResponses = append(fieldResponses, dto.FieldResponse{
-					ID:           field.ID,
-					TableID:      field.TableID,
-					Name:         field.Name,
-					DataType:     field.DataType,
-					IsPrimaryKey: field.IsPrimaryKey,
-					IsNullable:   field.IsNullable,
-					DefaultValue: field.DefaultValue,
-					Position:     field.Position,
-					CreatedAt:    field.CreatedAt,
-					UpdatedAt:    field.UpdatedAt,
-				})
-			}
-
-			tableResponses = append(tableResponses, dto.TableWithFieldsResponse{
-				ID:        table.ID,
-				ProjectID: table.ProjectID,
-				Name:      table.Name,
-				PosX:      table.PosX,
-				PosY:      table.PosY,
-				Fields:    fieldResponses,
-				CreatedAt: table.CreatedAt,
-				UpdatedAt: table.UpdatedAt,
-			})
-		}
-
-		// Convert relationships
-		var relationshipResponses []dto.RelationshipResponse
-		for _, relationship := range project.Relationships {
-			relationshipResponses = append(relationshipResponses, dto.RelationshipResponse{
-				ID:            relationship.ID,
-				ProjectID:     relationship.ProjectID,
-				SourceTableID: relationship.SourceTableID,
-				SourceFieldID: relationship.SourceFieldID,
-				TargetTableID: relationship.TargetTableID,
-				TargetFieldID: relationship.TargetFieldID,
-				RelationType:  relationship.RelationType,
-				CreatedAt:     relationship.CreatedAt,
-				UpdatedAt:     relationship.UpdatedAt,
-			})
-		}
+		h.recordView(r, project)
 
-		projectResponse := dto.ProjectResponse{
-			ID:           project.ID,
-			Name:         project.Name,
-			Description:  project.Description,
-			OwnerID:      project.OwnerID,
-			DatabaseType: project.DatabaseType,
-			CanvasData:   project.CanvasData,
-			Owner: dto.UserResponse{
-				ID:       project.Owner.ID,
-				Email:    project.Owner.Email,
-				Username: project.Owner.Username,
-			},
-			Collaborators: collaboratorResponses,
-			Tables:        tableResponses,
-			Relationships: relationshipResponses,
-			CreatedAt:     project.CreatedAt,
-			UpdatedAt:     project.UpdatedAt,
-		}
+		projectResponse := newProjectResponse(project)
 
 		// Debug logging for project retrieval
 		log.Printf("CANVAS DEBUG: Returning project %s with canvas data length: %d",
 			project.ID.String(), len(project.CanvasData))
 
+		utils.SetVersionETag(w, project.Version)
 		responses.RespondWithSuccess(w, http.StatusOK, "Project retrieved successfully", projectResponse)
 	}
 }
 
 func (h *ProjectHandler) Update() http.HandlerFunc {
+	return h.update(utils.DecodeAndValidate)
+}
+
+// Patch applies a JSON Merge Patch to a project
+func (h *ProjectHandler) Patch() http.HandlerFunc {
+	return h.update(utils.DecodeMergePatch)
+}
+
+// update reads the changes with decode, which responds itself when they are invalid
+func (h *ProjectHandler) update(decode func(http.ResponseWriter, *http.Request, any) bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID")
 		if !ok {
@@ -181,9 +125,18 @@ func (h *ProjectHandler) Update() http.HandlerFunc {
 		}
 
 		var req dto.UpdateProjectRequest
-		if !utils.DecodeAndValidate(w, r, &req) {
+		if !decode(w, r, &req) {
+			return
+		}
+
+		// The If-Match header takes precedence over a version in the body
+		version, ok := utils.ParseIfMatch(w, r)
+		if !ok {
 			return
 		}
+		if version != nil {
+			req.Version = version
+		}
 
 		// Empty update request
 		if req.Name == nil && req.Description == nil && req.CanvasData == nil {
@@ -205,8 +158,13 @@ func (h *ProjectHandler) Update() http.HandlerFunc {
 		}
 
 		project, err := h.projectService.UpdateProject(id, &req, userID)
-		if err != nil {
+		if err != nil && !errors.Is(err, services.ErrVersionConflict) {
+			var canvasErr *canvas.ValidationError
 			switch {
+			case errors.As(err, &canvasErr):
+				responses.RespondWithValidationErrors(w, canvasValidationErrors(canvasErr))
+			case errors.Is(err, canvas.ErrTooLarge):
+				responses.RespondWithError(w, http.StatusRequestEntityTooLarge, "Canvas data is too large")
 			case errors.Is(err, services.ErrProjectNotFound):
 				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
 			case errors.Is(err, services.ErrInvalidInput):
@@ -217,19 +175,34 @@ func (h *ProjectHandler) Update() http.HandlerFunc {
 			return
 		}
 
-		projectResponse := dto.ProjectSummaryResponse{
-			ID:          project.ID,
-			Name:        project.Name,
-			Description: project.Description,
-			OwnerID:     project.OwnerID,
-			CreatedAt:   project.CreatedAt,
-			UpdatedAt:   project.UpdatedAt,
+		projectResponse := newProjectSummaryResponse(project)
+
+		utils.SetVersionETag(w, project.Version)
+		if err != nil {
+			responses.RespondWithErrorData(w, http.StatusConflict, "Project was modified by someone else", projectResponse)
+			return
 		}
 
+		if req.CanvasData != nil { // Only canvas changes are announced to collaborators
+			responses.SetProjectSequence(w, project.Sequence)
+		}
 		responses.RespondWithSuccess(w, http.StatusOK, "Project updated successfully", projectResponse)
 	}
 }
 
+// canvasValidationErrors keys canvas problems by their path under canvas_data
+func canvasValidationErrors(err *canvas.ValidationError) map[string]string {
+	errs := make(map[string]string, len(err.Errors))
+	for path, message := range err.Errors {
+		if path == "" {
+			errs["canvas_data"] = message
+		} else {
+			errs["canvas_data."+path] = message
+		}
+	}
+	return errs
+}
+
 func (h *ProjectHandler) Delete() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID")
@@ -250,30 +223,37 @@ func (h *ProjectHandler) Delete() http.HandlerFunc {
 	}
 }
 
+// GetAll returns a page of projects. Supports ?name= and ?owner_id= filters.
 func (h *ProjectHandler) GetAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		projects, err := h.projectService.GetAllProjects()
+		page, ok := utils.ParsePageQuery(w, r)
+		if !ok {
+			return
+		}
+		ownerID, ok := utils.ParseUUIDQuery(w, r, "owner_id", "Invalid owner ID format")
+		if !ok {
+			return
+		}
+
+		filter := repository.ProjectFilter{Name: r.URL.Query().Get("name"), OwnerID: ownerID}
+		projects, next, err := h.projectService.ListProjects(filter, page)
 		if err != nil {
-			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve projects")
+			utils.RespondWithPageError(w, err, "Failed to retrieve projects")
 			return
 		}
 
-		var projectResponses []dto.ProjectSummaryResponse
+		projectResponses := make([]dto.ProjectSummaryResponse, 0, len(projects))
 		for _, project := range projects {
-			projectResponses = append(projectResponses, dto.ProjectSummaryResponse{
-				ID:          project.ID,
-				Name:        project.Name,
-				Description: project.Description,
-				OwnerID:     project.OwnerID,
-				CreatedAt:   project.CreatedAt,
-				UpdatedAt:   project.UpdatedAt,
-			})
+			projectResponses = append(projectResponses, newProjectSummaryResponse(project))
 		}
 
-		responses.RespondWithSuccess(w, http.StatusOK, "Projects retrieved successfully", projectResponses)
+		responses.RespondWithPage(w, "Projects retrieved successfully", projectResponses, dto.PageMeta{NextCursor: next})
 	}
 }
 
+// GetMyProjects returns the projects the user owns or collaborates on. Supports
+// ?tag= to keep projects with that tag, ?starred=true to keep favorites and
+// ?include= to add collaborators and tables_count, both left out by default.
 func (h *ProjectHandler) GetMyProjects() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Get user ID from context
@@ -289,49 +269,73 @@ func (h *ProjectHandler) GetMyProjects() http.HandlerFunc {
 			return
 		}
 
+		query := r.URL.Query()
+		tag := strings.ToLower(strings.TrimSpace(query.Get("tag")))
+		starredOnly := false
+		if value := query.Get("starred"); value != "" {
+			starredOnly, err = strconv.ParseBool(value)
+			if err != nil {
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid starred filter")
+				return
+			}
+		}
+		includes, ok := parseProjectIncludes(w, r)
+		if !ok {
+			return
+		}
+
 		// Get projects owned by user
-		ownedProjects, err := h.projectService.GetProjectsByOwnerID(userID)
+		ownedProjects, err := h.projectService.GetProjectsByOwnerID(userID, includes)
 		if err != nil {
 			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve owned projects")
 			return
 		}
 
 		// Get projects where user is collaborator
-		collaboratedProjects, err := h.projectService.GetProjectsByCollaboratorID(userID)
+		collaboratedProjects, err := h.projectService.GetProjectsByCollaboratorID(userID, includes)
 		if err != nil {
 			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve collaborated projects")
 			return
 		}
 
+		starredIDs, err := h.projectService.GetStarredProjectIDs(userID)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve starred projects")
+			return
+		}
+
 		// Combine and deduplicate
-		projectMap := make(map[uuid.UUID]*dto.ProjectSummaryResponse)
-
-		for _, project := range ownedProjects {
-			projectMap[project.ID] = &dto.ProjectSummaryResponse{
-				ID:          project.ID,
-				Name:        project.Name,
-				Description: project.Description,
-				OwnerID:     project.OwnerID,
-				CreatedAt:   project.CreatedAt,
-				UpdatedAt:   project.UpdatedAt,
+		projectMap := make(map[uuid.UUID]*dto.MyProjectResponse)
+
+		for _, project := range slices.Concat(ownedProjects, collaboratedProjects) {
+			if _, exists := projectMap[project.ID]; exists {
+				continue
+			}
+
+			starred := slices.Contains(starredIDs, project.ID)
+			if starredOnly && !starred {
+				continue
+			}
+			summary := newProjectSummaryResponse(project)
+			if tag != "" && !slices.Contains(summary.Tags, tag) {
+				continue
 			}
-		}
 
-		for _, project := range collaboratedProjects {
-			if _, exists := projectMap[project.ID]; !exists {
-				projectMap[project.ID] = &dto.ProjectSummaryResponse{
-					ID:          project.ID,
-					Name:        project.Name,
-					Description: project.Description,
-					OwnerID:     project.OwnerID,
-					CreatedAt:   project.CreatedAt,
-					UpdatedAt:   project.UpdatedAt,
-				}
+			myProject := &dto.MyProjectResponse{
+				ProjectSummaryResponse: summary,
+				Starred:                starred,
+			}
+			if includes.Collaborators {
+				myProject.Collaborators = newCollaboratorResponses(project)
+			}
+			if includes.TablesCount {
+				myProject.TablesCount = &project.TablesCount
 			}
+			projectMap[project.ID] = myProject
 		}
 
 		// Convert map to slice
-		var projectResponses []dto.ProjectSummaryResponse
+		var projectResponses []dto.MyProjectResponse
 		for _, project := range projectMap {
 			projectResponses = append(projectResponses, *project)
 		}
@@ -340,6 +344,126 @@ func (h *ProjectHandler) GetMyProjects() http.HandlerFunc {
 	}
 }
 
+// GetRecent returns the projects the user opened last, for resuming work. Supports ?limit=.
+func (h *ProjectHandler) GetRecent() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		limit := 0
+		if value := r.URL.Query().Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 {
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid limit")
+				return
+			}
+			limit = parsed
+		}
+
+		views, err := h.projectService.GetRecentProjects(userID, limit)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidInput) {
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid limit")
+			} else {
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve recent projects")
+			}
+			return
+		}
+
+		projectResponses := make([]dto.RecentProjectResponse, len(views))
+		for i, view := range views {
+			projectResponses[i] = dto.RecentProjectResponse{
+				ProjectSummaryResponse: newProjectSummaryResponse(&view.Project),
+				ViewedAt:               view.ViewedAt,
+			}
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Recent projects retrieved successfully", projectResponses)
+	}
+}
+
+// AddTag labels the project
+func (h *ProjectHandler) AddTag() http.HandlerFunc {
+	return h.changeTag(services.ProjectServiceInterface.AddProjectTag, "Project tagged successfully")
+}
+
+// RemoveTag removes a label from the project
+func (h *ProjectHandler) RemoveTag() http.HandlerFunc {
+	return h.changeTag(services.ProjectServiceInterface.RemoveProjectTag, "Project untagged successfully")
+}
+
+func (h *ProjectHandler) changeTag(change func(s services.ProjectServiceInterface, projectID uuid.UUID, tag string, userID uuid.UUID) error, message string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID")
+		if !ok {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := change(h.projectService, projectID, chi.URLParam(r, "tag"), userID); err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidInput):
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid tag")
+			case errors.Is(err, services.ErrTooManyTags):
+				responses.RespondWithError(w, http.StatusBadRequest, "Project has too many tags")
+			case errors.Is(err, services.ErrProjectNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to update project tags")
+			}
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, message, nil)
+	}
+}
+
+// Star adds the project to the user's favorites
+func (h *ProjectHandler) Star() http.HandlerFunc {
+	return h.changeStar(services.ProjectServiceInterface.StarProject, "Project starred successfully")
+}
+
+// Unstar removes the project from the user's favorites
+func (h *ProjectHandler) Unstar() http.HandlerFunc {
+	return h.changeStar(services.ProjectServiceInterface.UnstarProject, "Project unstarred successfully")
+}
+
+func (h *ProjectHandler) changeStar(change func(s services.ProjectServiceInterface, projectID, userID uuid.UUID) error, message string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID")
+		if !ok {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := change(h.projectService, projectID, userID); err != nil {
+			switch {
+			case errors.Is(err, services.ErrProjectNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to update starred projects")
+			}
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, message, nil)
+	}
+}
+
 func (h *ProjectHandler) AddCollaborator() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID")
@@ -359,6 +483,8 @@ func (h *ProjectHandler) AddCollaborator() http.HandlerFunc {
 				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
 			case errors.Is(err, services.ErrCollaboratorNotFound):
 				responses.RespondWithError(w, http.StatusBadRequest, "Collaborator not found")
+			case errors.Is(err, services.ErrQuotaExceeded):
+				respondWithQuotaExceeded(w, err)
 			default:
 				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to add collaborator")
 			}
@@ -397,3 +523,269 @@ func (h *ProjectHandler) RemoveCollaborator() http.HandlerFunc {
 		responses.RespondWithSuccess(w, http.StatusOK, "Collaborator removed successfully", nil)
 	}
 }
+
+// SetCollaboratorRole makes a collaborator an editor or a viewer
+func (h *ProjectHandler) SetCollaboratorRole() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID")
+		if !ok {
+			return
+		}
+
+		collaboratorID, ok := utils.ParseUUIDParam(w, r, "user_id")
+		if !ok {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var req dto.SetCollaboratorRoleRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		if err := h.projectService.SetCollaboratorRole(projectID, collaboratorID, req.Role, userID); err != nil {
+			switch {
+			case errors.Is(err, services.ErrProjectNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "Only the project owner can change roles")
+			case errors.Is(err, services.ErrCollaboratorNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Collaborator not found")
+			case errors.Is(err, services.ErrInvalidInput):
+				responses.RespondWithError(w, http.StatusBadRequest, "Role must be editor or viewer")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to change collaborator role")
+			}
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Collaborator role updated successfully", nil)
+	}
+}
+
+// KickCollaborator disconnects a collaborator from the project's WebSockets
+// without removing them from the project
+func (h *ProjectHandler) KickCollaborator() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID")
+		if !ok {
+			return
+		}
+
+		collaboratorID, ok := utils.ParseUUIDParam(w, r, "user_id")
+		if !ok {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.projectService.KickCollaborator(projectID, collaboratorID, userID); err != nil {
+			switch {
+			case errors.Is(err, services.ErrProjectNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "Only the project owner can kick collaborators")
+			case errors.Is(err, services.ErrCollaboratorNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Collaborator not found")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to kick collaborator")
+			}
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Collaborator disconnected successfully", nil)
+	}
+}
+
+// Full returns the project with its schema and the users connected to it, so
+// the canvas can open it in one request. The ETag lets clients skip unchanged reloads.
+func (h *ProjectHandler) Full() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID")
+		if !ok {
+			return
+		}
+
+		userIDStr, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			responses.RespondWithError(w, http.StatusUnauthorized, "User context not found")
+			return
+		}
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusUnauthorized, "Invalid user ID")
+			return
+		}
+
+		project, err := h.projectService.GetProjectByID(id)
+		if err != nil {
+			if errors.Is(err, services.ErrProjectNotFound) {
+				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+			} else {
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve project")
+			}
+			return
+		}
+
+		if !isProjectMember(project, userID) {
+			responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+			return
+		}
+
+		h.recordView(r, project)
+
+		// Cursor positions and last-seen times are left out: they change constantly
+		// and would defeat the ETag. They arrive over the WebSocket instead.
+		activeCollaborators := []dto.ActiveCollaboratorResponse{}
+		for _, user := range h.hub.GetActiveUsers(id) {
+			activeCollaborators = append(activeCollaborators, dto.ActiveCollaboratorResponse{
+				UserID:    user.UserID,
+				Username:  user.Username,
+				UserColor: user.UserColor,
+				AvatarURL: user.AvatarURL,
+			})
+		}
+		slices.SortFunc(activeCollaborators, func(a, b dto.ActiveCollaboratorResponse) int {
+			return strings.Compare(a.Username, b.Username)
+		})
+
+		responses.RespondWithSuccessETag(w, r, "Project retrieved successfully", dto.FullProjectResponse{
+			ProjectResponse:     newProjectResponse(project),
+			ActiveCollaborators: activeCollaborators,
+		})
+	}
+}
+
+// recordView adds the project to the recently viewed projects of the requesting
+// member. Failures are logged rather than failing the read.
+func (h *ProjectHandler) recordView(r *http.Request, project *models.Project) {
+	userIDStr, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil || !isProjectMember(project, userID) {
+		return
+	}
+
+	if err := h.projectService.RecordProjectView(project.ID, userID); err != nil {
+		log.Printf("Failed to record view of project %s: %v", project.ID, err)
+	}
+}
+
+func isProjectMember(project *models.Project, userID uuid.UUID) bool {
+	if project.OwnerID == userID {
+		return true
+	}
+	for _, collaborator := range project.Collaborators {
+		if collaborator.ID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func newProjectSummaryResponse(project *models.Project) dto.ProjectSummaryResponse {
+	return dto.ProjectSummaryResponse{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		OwnerID:     project.OwnerID,
+		Tags:        projectTagNames(project),
+		CreatedAt:   project.CreatedAt,
+		UpdatedAt:   project.UpdatedAt,
+		Version:     project.Version,
+	}
+}
+
+// parseProjectIncludes reads the comma-separated ?include= of a project
+// listing, responding itself when it names something that cannot be included
+func parseProjectIncludes(w http.ResponseWriter, r *http.Request) (repository.ProjectIncludes, bool) {
+	var includes repository.ProjectIncludes
+	value := r.URL.Query().Get("include")
+	if value == "" {
+		return includes, true
+	}
+	for _, include := range strings.Split(value, ",") {
+		switch strings.TrimSpace(include) {
+		case "collaborators":
+			includes.Collaborators = true
+		case "tables_count":
+			includes.TablesCount = true
+		default:
+			responses.RespondWithError(w, http.StatusBadRequest, "Include must be collaborators or tables_count")
+			return repository.ProjectIncludes{}, false
+		}
+	}
+	return includes, true
+}
+
+func projectTagNames(project *models.Project) []string {
+	tags := make([]string, len(project.Tags))
+	for i, tag := range project.Tags {
+		tags[i] = tag.Name
+	}
+	return tags
+}
+
+// newCollaboratorResponses converts the collaborators loaded with a project
+func newCollaboratorResponses(project *models.Project) []dto.UserResponse {
+	var collaboratorResponses []dto.UserResponse
+	for _, collaborator := range project.Collaborators {
+		collaboratorResponses = append(collaboratorResponses, dto.UserResponse{
+			ID:        collaborator.ID,
+			Email:     collaborator.Email,
+			Username:  collaborator.Username,
+			AvatarURL: collaborator.AvatarURL,
+		})
+	}
+	return collaboratorResponses
+}
+
+// newProjectResponse converts a project loaded with its schema to its full response
+func newProjectResponse(project *models.Project) dto.ProjectResponse {
+	collaboratorResponses := newCollaboratorResponses(project)
+
+	// Convert tables with fields
+	var tableResponses []dto.TableWithFieldsResponse
+	for _, table := range project.Tables {
+		tableResponses = append(tableResponses, newTableWithFieldsResponse(&table))
+	}
+
+	// Convert relationships
+	var relationshipResponses []dto.RelationshipResponse
+	for _, relationship := range project.Relationships {
+		relationshipResponses = append(relationshipResponses, newRelationshipResponse(&relationship))
+	}
+
+	return dto.ProjectResponse{
+		ID:           project.ID,
+		Name:         project.Name,
+		Description:  project.Description,
+		OwnerID:      project.OwnerID,
+		DatabaseType: project.DatabaseType,
+		CanvasData:   project.CanvasData,
+		Metadata:     metadataResponse(project.Metadata),
+		Owner: dto.UserResponse{
+			ID:        project.Owner.ID,
+			Email:     project.Owner.Email,
+			Username:  project.Owner.Username,
+			AvatarURL: project.Owner.AvatarURL,
+		},
+		Collaborators: collaboratorResponses,
+		Tables:        tableResponses,
+		Relationships: relationshipResponses,
+		Tags:          projectTagNames(project),
+		CreatedAt:     project.CreatedAt,
+		UpdatedAt:     project.UpdatedAt,
+		Version:       project.Version,
+		Sequence:      project.Sequence,
+	}
+}
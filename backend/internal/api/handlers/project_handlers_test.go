@@ -2,15 +2,20 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/canvas"
 	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
 	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -27,8 +32,11 @@ type ProjectHandlerTestSuite struct {
 
 func (suite *ProjectHandlerTestSuite) SetupTest() {
 	suite.mockService = new(mockService.MockProjectService)
-	suite.handler = NewProjectHandler(suite.mockService)
+	suite.handler = NewProjectHandler(suite.mockService, websocketPkg.NewHub())
 	suite.userID = uuid.New()
+
+	// Reads by members add to their recently viewed projects
+	suite.mockService.On("RecordProjectView", mock.Anything, mock.Anything).Return(nil).Maybe()
 }
 
 func TestProjectHandlerSuite(t *testing.T) {
@@ -195,8 +203,9 @@ func (suite *ProjectHandlerTestSuite) TestGetMyProjects_Success() {
 		testutil.CreateTestProject(uuid.New()), // Different owner
 	}
 
-	suite.mockService.On("GetProjectsByOwnerID", suite.userID).Return(ownedProjects, nil)
-	suite.mockService.On("GetProjectsByCollaboratorID", suite.userID).Return(collaboratedProjects, nil)
+	suite.mockService.On("GetProjectsByOwnerID", suite.userID, repository.ProjectIncludes{}).Return(ownedProjects, nil)
+	suite.mockService.On("GetProjectsByCollaboratorID", suite.userID, repository.ProjectIncludes{}).Return(collaboratedProjects, nil)
+	suite.mockService.On("GetStarredProjectIDs", suite.userID).Return([]uuid.UUID{}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/projects/my", nil)
 	req = testutil.WithUserContext(req, suite.userID)
@@ -213,6 +222,142 @@ func (suite *ProjectHandlerTestSuite) TestGetMyProjects_Success() {
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+// Test Get My Projects - Filtered by tag and starred
+func (suite *ProjectHandlerTestSuite) TestGetMyProjects_TagAndStarred() {
+	tagged := testutil.CreateTestProject(suite.userID)
+	tagged.Tags = []models.ProjectTag{{ProjectID: tagged.ID, Name: "billing"}}
+	starredTagged := testutil.CreateTestProject(suite.userID)
+	starredTagged.Tags = []models.ProjectTag{{ProjectID: starredTagged.ID, Name: "billing"}}
+	starredUntagged := testutil.CreateTestProject(uuid.New())
+
+	suite.mockService.On("GetProjectsByOwnerID", suite.userID, repository.ProjectIncludes{}).Return([]*models.Project{tagged, starredTagged}, nil)
+	suite.mockService.On("GetProjectsByCollaboratorID", suite.userID, repository.ProjectIncludes{}).Return([]*models.Project{starredUntagged}, nil)
+	suite.mockService.On("GetStarredProjectIDs", suite.userID).Return([]uuid.UUID{starredTagged.ID, starredUntagged.ID}, nil)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/projects/my?tag=Billing&starred=true", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetMyProjects()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "My projects retrieved successfully")
+	projectsResponse, ok := response.Data.([]any)
+	suite.Require().True(ok)
+	suite.Require().Len(projectsResponse, 1)
+	project := projectsResponse[0].(map[string]any)
+	suite.Equal(starredTagged.ID.String(), project["id"])
+	suite.Equal(true, project["starred"])
+	suite.Equal([]any{"billing"}, project["tags"])
+}
+
+// Test Get My Projects - Collaborators and table counts only when included
+func (suite *ProjectHandlerTestSuite) TestGetMyProjects_Include() {
+	project := testutil.CreateTestProject(suite.userID)
+	project.Collaborators = []models.User{{ID: uuid.New(), Username: "grace"}}
+	project.TablesCount = 4
+	includes := repository.ProjectIncludes{Collaborators: true, TablesCount: true}
+
+	suite.mockService.On("GetProjectsByOwnerID", suite.userID, includes).Return([]*models.Project{project}, nil)
+	suite.mockService.On("GetProjectsByCollaboratorID", suite.userID, includes).Return([]*models.Project{}, nil)
+	suite.mockService.On("GetStarredProjectIDs", suite.userID).Return([]uuid.UUID{}, nil)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/projects/my?include=collaborators,tables_count", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetMyProjects()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "My projects retrieved successfully")
+	projectsResponse := response.Data.([]any)
+	suite.Require().Len(projectsResponse, 1)
+	body := projectsResponse[0].(map[string]any)
+	suite.Equal(float64(4), body["tables_count"])
+	collaborators := body["collaborators"].([]any)
+	suite.Require().Len(collaborators, 1)
+	suite.Equal("grace", collaborators[0].(map[string]any)["username"])
+}
+
+// Test Get My Projects - Only known includes are accepted
+func (suite *ProjectHandlerTestSuite) TestGetMyProjects_InvalidInclude() {
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/projects/my?include=tables", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetMyProjects()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Include must be collaborators or tables_count")
+	suite.mockService.AssertNotCalled(suite.T(), "GetProjectsByOwnerID", mock.Anything, mock.Anything)
+}
+
+// Test Get Recent - Views are returned with the project summary
+func (suite *ProjectHandlerTestSuite) TestGetRecent_Success() {
+	project := testutil.CreateTestProject(suite.userID)
+	viewedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	views := []*models.ProjectView{{UserID: suite.userID, ProjectID: project.ID, ViewedAt: viewedAt, Project: *project}}
+
+	suite.mockService.On("GetRecentProjects", suite.userID, 5).Return(views, nil)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/projects/recent?limit=5", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetRecent()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Recent projects retrieved successfully")
+	projectsResponse, ok := response.Data.([]any)
+	suite.Require().True(ok)
+	suite.Require().Len(projectsResponse, 1)
+	recent := projectsResponse[0].(map[string]any)
+	suite.Equal(project.ID.String(), recent["id"])
+	suite.Equal("2026-03-01T12:00:00Z", recent["viewed_at"])
+}
+
+// Test Full Project - Opening a project records a view
+func (suite *ProjectHandlerTestSuite) TestFullProject_RecordsView() {
+	project := testutil.CreateTestProject(suite.userID)
+
+	suite.mockService.On("GetProjectByID", project.ID).Return(project, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Full()(w, suite.fullProjectRequest(project.ID))
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.mockService.AssertCalled(suite.T(), "RecordProjectView", project.ID, suite.userID)
+}
+
+// Test Add Tag - Invalid tag
+func (suite *ProjectHandlerTestSuite) TestAddTag_InvalidTag() {
+	projectID := uuid.New()
+
+	suite.mockService.On("AddProjectTag", projectID, "not a tag", suite.userID).Return(services.ErrInvalidInput)
+
+	req := httptest.NewRequest(http.MethodPut, "/projects/"+projectID.String()+"/tags/not%20a%20tag", nil)
+	req = testutil.WithUserContext(req, suite.userID)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	rctx.URLParams.Add("tag", "not a tag")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	suite.handler.AddTag()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid tag")
+}
+
+// Test Star - Forbidden for projects the user cannot access
+func (suite *ProjectHandlerTestSuite) TestStar_Forbidden() {
+	projectID := uuid.New()
+
+	suite.mockService.On("StarProject", projectID, suite.userID).Return(services.ErrForbidden)
+
+	req := httptest.NewRequest(http.MethodPut, "/projects/"+projectID.String()+"/star", nil)
+	req = testutil.WithUserContext(req, suite.userID)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	suite.handler.Star()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "You don't have access to this project")
+}
+
 // Test Update Project - Success
 func (suite *ProjectHandlerTestSuite) TestUpdateProject_Success() {
 	projectID := uuid.New()
@@ -250,6 +395,79 @@ func (suite *ProjectHandlerTestSuite) TestUpdateProject_Success() {
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+// Test Update Project - Canvas problems are reported by path under canvas_data
+func (suite *ProjectHandlerTestSuite) TestUpdateProject_InvalidCanvasData() {
+	projectID := uuid.New()
+	canvasData := `{"nodes":{}}`
+	updateRequest := dto.UpdateProjectRequest{CanvasData: &canvasData}
+
+	suite.mockService.On("UpdateProject", projectID, &updateRequest, suite.userID).
+		Return(nil, &canvas.ValidationError{Errors: map[string]string{"nodes": "Must be an array"}})
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/projects/"+projectID.String(), updateRequest)
+	req = testutil.WithUserContext(req, suite.userID)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	suite.handler.Update()(w, req)
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Validation failed")
+	suite.Equal(map[string]any{"canvas_data.nodes": "Must be an array"}, response.Errors)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Test Update Project - Canvas data over the size limit
+func (suite *ProjectHandlerTestSuite) TestUpdateProject_CanvasDataTooLarge() {
+	projectID := uuid.New()
+	canvasData := `{}`
+	updateRequest := dto.UpdateProjectRequest{CanvasData: &canvasData}
+
+	suite.mockService.On("UpdateProject", projectID, &updateRequest, suite.userID).
+		Return(nil, fmt.Errorf("%w: 3 bytes, at most 2 are allowed", canvas.ErrTooLarge))
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/projects/"+projectID.String(), updateRequest)
+	req = testutil.WithUserContext(req, suite.userID)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	suite.handler.Update()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusRequestEntityTooLarge, "Canvas data is too large")
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Test Patch Project - null clears the description and the name is left alone
+func (suite *ProjectHandlerTestSuite) TestPatchProject_ClearsNullableField() {
+	projectID := uuid.New()
+	cleared := ""
+	expectedRequest := dto.UpdateProjectRequest{Description: &cleared}
+
+	updatedProject := testutil.CreateTestProject(suite.userID)
+	updatedProject.ID = projectID
+	updatedProject.Description = ""
+
+	suite.mockService.On("UpdateProject", projectID, &expectedRequest, suite.userID).Return(updatedProject, nil)
+
+	req := testutil.MakeMergePatchRequest("/projects/"+projectID.String(), `{"description": null}`)
+	req = testutil.WithUserContext(req, suite.userID)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	suite.handler.Patch()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Project updated successfully")
+	suite.mockService.AssertExpectations(suite.T())
+}
+
 // Test Delete Project - Success
 func (suite *ProjectHandlerTestSuite) TestDeleteProject_Success() {
 	projectID := uuid.New()
@@ -312,3 +530,121 @@ func (suite *ProjectHandlerTestSuite) TestRemoveCollaborator_Success() {
 	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Collaborator removed successfully")
 	suite.mockService.AssertExpectations(suite.T())
 }
+
+// Test Set Collaborator Role - Success
+func (suite *ProjectHandlerTestSuite) TestSetCollaboratorRole_Success() {
+	projectID := uuid.New()
+	collaboratorID := uuid.New()
+
+	suite.mockService.On("SetCollaboratorRole", projectID, collaboratorID, models.ProjectRoleViewer, suite.userID).Return(nil)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/projects/"+projectID.String()+"/collaborators/"+collaboratorID.String()+"/role",
+		dto.SetCollaboratorRoleRequest{Role: models.ProjectRoleViewer})
+	req = testutil.WithUserContext(req, suite.userID)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	rctx.URLParams.Add("user_id", collaboratorID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	suite.handler.SetCollaboratorRole()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Collaborator role updated successfully")
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Test Kick Collaborator - Only the owner may kick
+func (suite *ProjectHandlerTestSuite) TestKickCollaborator_Forbidden() {
+	projectID := uuid.New()
+	collaboratorID := uuid.New()
+
+	suite.mockService.On("KickCollaborator", projectID, collaboratorID, suite.userID).Return(services.ErrForbidden)
+
+	req := httptest.NewRequest(http.MethodPost, "/projects/"+projectID.String()+"/collaborators/"+collaboratorID.String()+"/kick", nil)
+	req = testutil.WithUserContext(req, suite.userID)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	rctx.URLParams.Add("user_id", collaboratorID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	suite.handler.KickCollaborator()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "Only the project owner can kick collaborators")
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// fullProjectRequest builds a request for the full project as the suite's user
+func (suite *ProjectHandlerTestSuite) fullProjectRequest(projectID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/projects/"+projectID.String()+"/full", nil)
+	req = testutil.WithUserContext(req, suite.userID)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+// Test Full Project - Success
+func (suite *ProjectHandlerTestSuite) TestFullProject_Success() {
+	project := testutil.CreateTestProject(suite.userID)
+	table := testutil.CreateTestTable(project.ID)
+	table.Fields = []models.Field{*testutil.CreateTestField(table.ID)}
+	project.Tables = []models.Table{*table}
+
+	suite.mockService.On("GetProjectByID", project.ID).Return(project, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Full()(w, suite.fullProjectRequest(project.ID))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Project retrieved successfully")
+	suite.NotEmpty(w.Header().Get("ETag"))
+
+	data, ok := response.Data.(map[string]any)
+	suite.True(ok)
+	suite.Equal(project.Name, data["name"])
+	suite.Len(data["tables"], 1)
+	suite.Len(data["tables"].([]any)[0].(map[string]any)["fields"], 1)
+	suite.Equal([]any{}, data["active_collaborators"])
+}
+
+// Test Full Project - Unchanged project answers 304
+func (suite *ProjectHandlerTestSuite) TestFullProject_NotModified() {
+	project := testutil.CreateTestProject(suite.userID)
+	suite.mockService.On("GetProjectByID", project.ID).Return(project, nil)
+
+	first := httptest.NewRecorder()
+	suite.handler.Full()(first, suite.fullProjectRequest(project.ID))
+	etag := first.Header().Get("ETag")
+
+	req := suite.fullProjectRequest(project.ID)
+	req.Header.Set("If-None-Match", etag)
+	second := httptest.NewRecorder()
+	suite.handler.Full()(second, req)
+
+	suite.Equal(http.StatusNotModified, second.Code)
+	suite.Empty(second.Body.String())
+	suite.Equal(etag, second.Header().Get("ETag"))
+
+	// A change to the project gives a new ETag
+	project.Name = "Renamed"
+	third := httptest.NewRecorder()
+	req = suite.fullProjectRequest(project.ID)
+	req.Header.Set("If-None-Match", etag)
+	suite.handler.Full()(third, req)
+
+	suite.Equal(http.StatusOK, third.Code)
+	suite.NotEqual(etag, third.Header().Get("ETag"))
+}
+
+// Test Full Project - Not a member
+func (suite *ProjectHandlerTestSuite) TestFullProject_Forbidden() {
+	project := testutil.CreateTestProject(uuid.New())
+	suite.mockService.On("GetProjectByID", project.ID).Return(project, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Full()(w, suite.fullProjectRequest(project.ID))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "You don't have access to this project")
+}
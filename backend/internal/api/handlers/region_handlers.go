@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+)
+
+type RegionHandler struct {
+	regionService services.RegionServiceInterface
+}
+
+func NewRegionHandler(regionService services.RegionServiceInterface) *RegionHandler {
+	return &RegionHandler{
+		regionService: regionService,
+	}
+}
+
+// Create handles region creation within a project
+func (h *RegionHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.CreateRegionRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		region, err := h.regionService.CreateRegion(projectID, &req, userID)
+		if err != nil {
+			respondWithRegionError(w, err)
+			return
+		}
+
+		responses.SetProjectSequence(w, region.Sequence)
+		responses.RespondWithSuccess(w, http.StatusCreated, "Region created successfully", newRegionResponse(region))
+	}
+}
+
+// GetByProjectID handles retrieving all regions of a project
+func (h *RegionHandler) GetByProjectID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		regions, err := h.regionService.GetRegionsByProjectID(projectID)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+
+		regionResponses := make([]dto.RegionResponse, len(regions))
+		for i, region := range regions {
+			regionResponses[i] = newRegionResponse(region)
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Regions retrieved successfully", regionResponses)
+	}
+}
+
+// GetByID handles retrieving a specific region
+func (h *RegionHandler) GetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		regionID, ok := utils.ParseUUIDParam(w, r, "region_id")
+		if !ok {
+			return
+		}
+
+		region, err := h.regionService.GetRegionByID(projectID, regionID)
+		if err != nil {
+			respondWithRegionError(w, err)
+			return
+		}
+
+		utils.SetVersionETag(w, region.Version)
+		responses.RespondWithSuccess(w, http.StatusOK, "Region retrieved successfully", newRegionResponse(region))
+	}
+}
+
+// Update handles region updates
+func (h *RegionHandler) Update() http.HandlerFunc {
+	return h.update(utils.DecodeAndValidate)
+}
+
+// Patch applies a JSON Merge Patch to a region
+func (h *RegionHandler) Patch() http.HandlerFunc {
+	return h.update(utils.DecodeMergePatch)
+}
+
+// update reads the changes with decode, which responds itself when they are invalid
+func (h *RegionHandler) update(decode func(http.ResponseWriter, *http.Request, any) bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		regionID, ok := utils.ParseUUIDParam(w, r, "region_id")
+		if !ok {
+			return
+		}
+
+		var req dto.UpdateRegionRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		// The If-Match header takes precedence over a version in the body
+		version, ok := utils.ParseIfMatch(w, r)
+		if !ok {
+			return
+		}
+		if version != nil {
+			req.Version = version
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		region, err := h.regionService.UpdateRegion(projectID, regionID, &req, userID)
+		if err != nil && !errors.Is(err, services.ErrVersionConflict) {
+			respondWithRegionError(w, err)
+			return
+		}
+
+		regionResponse := newRegionResponse(region)
+
+		utils.SetVersionETag(w, region.Version)
+		if err != nil {
+			responses.RespondWithErrorData(w, http.StatusConflict, "Region was modified by someone else", regionResponse)
+			return
+		}
+
+		responses.SetProjectSequence(w, region.Sequence)
+		responses.RespondWithSuccess(w, http.StatusOK, "Region updated successfully", regionResponse)
+	}
+}
+
+// Move handles moving a region together with its tables
+func (h *RegionHandler) Move() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		regionID, ok := utils.ParseUUIDParam(w, r, "region_id")
+		if !ok {
+			return
+		}
+
+		var req dto.MoveRegionRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		// The If-Match header takes precedence over a version in the body
+		version, ok := utils.ParseIfMatch(w, r)
+		if !ok {
+			return
+		}
+		if version != nil {
+			req.Version = version
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		move, err := h.regionService.MoveRegion(projectID, regionID, &req, userID)
+		if err != nil && !errors.Is(err, services.ErrVersionConflict) {
+			respondWithRegionError(w, err)
+			return
+		}
+
+		utils.SetVersionETag(w, move.Region.Version)
+		if err != nil {
+			responses.RespondWithErrorData(w, http.StatusConflict, "Region was modified by someone else", newRegionResponse(move.Region))
+			return
+		}
+
+		moveResponse := dto.MoveRegionResponse{
+			Region: newRegionResponse(move.Region),
+			Tables: make([]dto.TablePositionResponse, len(move.Tables)),
+		}
+		for i, table := range move.Tables {
+			moveResponse.Tables[i] = dto.TablePositionResponse{
+				ID:      table.ID,
+				PosX:    table.PosX,
+				PosY:    table.PosY,
+				Version: table.Version,
+			}
+		}
+
+		responses.SetProjectSequence(w, move.Region.Sequence)
+		responses.RespondWithSuccess(w, http.StatusOK, "Region moved successfully", moveResponse)
+	}
+}
+
+// Delete handles region deletion. The region's tables stay where they are.
+func (h *RegionHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		regionID, ok := utils.ParseUUIDParam(w, r, "region_id")
+		if !ok {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		sequence, err := h.regionService.DeleteRegion(projectID, regionID, userID)
+		if err != nil {
+			respondWithRegionError(w, err)
+			return
+		}
+
+		responses.SetProjectSequence(w, sequence)
+		responses.RespondWithSuccess(w, http.StatusOK, "Region deleted successfully", nil)
+	}
+}
+
+func respondWithRegionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrProjectNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, services.ErrRegionNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Region not found")
+	case errors.Is(err, services.ErrTableNotFound):
+		responses.RespondWithError(w, http.StatusBadRequest, "A table of the region is not in the project")
+	case errors.Is(err, services.ErrInvalidInput):
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+	case errors.Is(err, services.ErrForbidden):
+		responses.RespondWithError(w, http.StatusForbidden, "You don't have permission to modify this project")
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func newRegionResponse(region *models.Region) dto.RegionResponse {
+	response := dto.RegionResponse{
+		ID:        region.ID,
+		ProjectID: region.ProjectID,
+		Name:      region.Name,
+		Color:     region.Color,
+		PosX:      region.PosX,
+		PosY:      region.PosY,
+		Width:     region.Width,
+		Height:    region.Height,
+		TableIDs:  region.TableIDs,
+		CreatedAt: region.CreatedAt,
+		UpdatedAt: region.UpdatedAt,
+		Version:   region.Version,
+	}
+	if response.TableIDs == nil {
+		response.TableIDs = []uuid.UUID{}
+	}
+	return response
+}
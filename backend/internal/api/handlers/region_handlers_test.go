@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+func createTestRegion(projectID uuid.UUID) *models.Region {
+	return &models.Region{
+		ID:        uuid.New(),
+		ProjectID: projectID,
+		Name:      "Billing domain",
+		Color:     "#3b82f6",
+		Width:     400,
+		Height:    300,
+		Version:   2,
+		TableIDs:  []uuid.UUID{uuid.New()},
+	}
+}
+
+type RegionHandlerTestSuite struct {
+	suite.Suite
+	mockRegionService *mockService.MockRegionService
+	handler           *RegionHandler
+	userID            uuid.UUID
+}
+
+func (suite *RegionHandlerTestSuite) SetupTest() {
+	suite.mockRegionService = new(mockService.MockRegionService)
+	suite.handler = NewRegionHandler(suite.mockRegionService)
+	suite.userID = uuid.New()
+}
+
+func TestRegionHandlerSuite(t *testing.T) {
+	suite.Run(t, new(RegionHandlerTestSuite))
+}
+
+// withRegionParams adds the project and region IDs to the route context
+func withRegionParams(req *http.Request, projectID, regionID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	rctx.URLParams.Add("region_id", regionID.String())
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func (suite *RegionHandlerTestSuite) TestCreate_Success() {
+	projectID := uuid.New()
+	region := createTestRegion(projectID)
+	region.Sequence = 7
+	createReq := dto.CreateRegionRequest{Name: region.Name, Color: region.Color, Width: 400, Height: 300, TableIDs: region.TableIDs}
+
+	suite.mockRegionService.On("CreateRegion", projectID, &createReq, suite.userID).Return(region, nil)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+projectID.String()+"/regions", createReq)
+	req = withRegionParams(testutil.WithUserContext(req, suite.userID), projectID, uuid.Nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "Region created successfully")
+	data := response.Data.(map[string]any)
+	suite.Equal(region.ID.String(), data["region_id"])
+	suite.Equal([]any{region.TableIDs[0].String()}, data["table_ids"])
+	suite.Equal("7", w.Header().Get("X-Project-Sequence"))
+	suite.mockRegionService.AssertExpectations(suite.T())
+}
+
+func (suite *RegionHandlerTestSuite) TestCreate_InvalidColor() {
+	projectID := uuid.New()
+	createReq := dto.CreateRegionRequest{Name: "Billing", Color: "blue", Width: 400, Height: 300}
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+projectID.String()+"/regions", createReq)
+	req = withRegionParams(testutil.WithUserContext(req, suite.userID), projectID, uuid.Nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockRegionService.AssertNotCalled(suite.T(), "CreateRegion", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RegionHandlerTestSuite) TestCreate_TableNotInProject() {
+	projectID := uuid.New()
+	createReq := dto.CreateRegionRequest{Name: "Billing", Color: "#fff", Width: 400, Height: 300, TableIDs: []uuid.UUID{uuid.New()}}
+
+	suite.mockRegionService.On("CreateRegion", projectID, &createReq, suite.userID).Return(nil, services.ErrTableNotFound)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+projectID.String()+"/regions", createReq)
+	req = withRegionParams(testutil.WithUserContext(req, suite.userID), projectID, uuid.Nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "A table of the region is not in the project")
+}
+
+func (suite *RegionHandlerTestSuite) TestGetByID_NotFound() {
+	projectID := uuid.New()
+	regionID := uuid.New()
+
+	suite.mockRegionService.On("GetRegionByID", projectID, regionID).Return(nil, services.ErrRegionNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/projects/"+projectID.String()+"/regions/"+regionID.String(), nil)
+	req = withRegionParams(req, projectID, regionID)
+
+	w := httptest.NewRecorder()
+	suite.handler.GetByID()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusNotFound, "Region not found")
+}
+
+func (suite *RegionHandlerTestSuite) TestUpdate_Conflict() {
+	projectID := uuid.New()
+	current := createTestRegion(projectID)
+	name := "Payments"
+
+	suite.mockRegionService.On("UpdateRegion", projectID, current.ID, mock.MatchedBy(func(req *dto.UpdateRegionRequest) bool {
+		return *req.Name == name && *req.Version == 1
+	}), suite.userID).Return(current, services.ErrVersionConflict)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/projects/"+projectID.String()+"/regions/"+current.ID.String(), dto.UpdateRegionRequest{Name: &name})
+	req.Header.Set("If-Match", `"1"`)
+	req = withRegionParams(testutil.WithUserContext(req, suite.userID), projectID, current.ID)
+
+	w := httptest.NewRecorder()
+	suite.handler.Update()(w, req)
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "Region was modified by someone else")
+	suite.Equal(current.Name, response.Data.(map[string]any)["name"])
+	suite.Equal(`"2"`, w.Header().Get("ETag"))
+}
+
+func (suite *RegionHandlerTestSuite) TestMove_Success() {
+	projectID := uuid.New()
+	region := createTestRegion(projectID)
+	tableID := region.TableIDs[0]
+	moveReq := dto.MoveRegionRequest{DX: 25, DY: -10}
+
+	suite.mockRegionService.On("MoveRegion", projectID, region.ID, &moveReq, suite.userID).Return(&services.RegionMove{
+		Region: region,
+		Tables: []repository.TablePosition{{ID: tableID, PosX: 125, PosY: 190, Version: 5}},
+	}, nil)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+projectID.String()+"/regions/"+region.ID.String()+"/move", moveReq)
+	req = withRegionParams(testutil.WithUserContext(req, suite.userID), projectID, region.ID)
+
+	w := httptest.NewRecorder()
+	suite.handler.Move()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Region moved successfully")
+	tables := response.Data.(map[string]any)["tables"].([]any)
+	suite.Len(tables, 1)
+	table := tables[0].(map[string]any)
+	suite.Equal(tableID.String(), table["table_id"])
+	suite.Equal(125.0, table["pos_x"])
+	suite.mockRegionService.AssertExpectations(suite.T())
+}
+
+func (suite *RegionHandlerTestSuite) TestDelete_Forbidden() {
+	projectID := uuid.New()
+	regionID := uuid.New()
+
+	suite.mockRegionService.On("DeleteRegion", projectID, regionID, suite.userID).Return(int64(0), services.ErrForbidden)
+
+	req := httptest.NewRequest(http.MethodDelete, "/projects/"+projectID.String()+"/regions/"+regionID.String(), nil)
+	req = withRegionParams(testutil.WithUserContext(req, suite.userID), projectID, regionID)
+
+	w := httptest.NewRecorder()
+	suite.handler.Delete()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "You don't have permission to modify this project")
+}
@@ -69,18 +69,9 @@ func (h *RelationshipHandler) Create() http.HandlerFunc {
 		}
 
 		// Convert to response format
-		relationshipResponse := dto.RelationshipResponse{
-			ID:            relationship.ID,
-			ProjectID:     relationship.ProjectID,
-			SourceTableID: relationship.SourceTableID,
-			SourceFieldID: relationship.SourceFieldID,
-			TargetTableID: relationship.TargetTableID,
-			TargetFieldID: relationship.TargetFieldID,
-			RelationType:  relationship.RelationType,
-			CreatedAt:     relationship.CreatedAt,
-			UpdatedAt:     relationship.UpdatedAt,
-		}
+		relationshipResponse := newRelationshipResponse(relationship)
 
+		responses.SetProjectSequence(w, relationship.Sequence)
 		responses.RespondWithSuccess(w, http.StatusCreated, "Relationship created successfully", relationshipResponse)
 	}
 }
@@ -107,18 +98,9 @@ func (h *RelationshipHandler) GetByID() http.HandlerFunc {
 		}
 
 		// Convert to response format
-		relationshipResponse := dto.RelationshipResponse{
-			ID:            relationship.ID,
-			ProjectID:     relationship.ProjectID,
-			SourceTableID: relationship.SourceTableID,
-			SourceFieldID: relationship.SourceFieldID,
-			TargetTableID: relationship.TargetTableID,
-			TargetFieldID: relationship.TargetFieldID,
-			RelationType:  relationship.RelationType,
-			CreatedAt:     relationship.CreatedAt,
-			UpdatedAt:     relationship.UpdatedAt,
-		}
+		relationshipResponse := newRelationshipResponse(relationship)
 
+		utils.SetVersionETag(w, relationship.Version)
 		responses.RespondWithSuccess(w, http.StatusOK, "Relationship retrieved successfully", relationshipResponse)
 	}
 }
@@ -142,17 +124,7 @@ func (h *RelationshipHandler) GetByProjectID() http.HandlerFunc {
 		// Convert to response format
 		var relationshipResponses []dto.RelationshipResponse
 		for _, relationship := range relationships {
-			relationshipResponses = append(relationshipResponses, dto.RelationshipResponse{
-				ID:            relationship.ID,
-				ProjectID:     relationship.ProjectID,
-				SourceTableID: relationship.SourceTableID,
-				SourceFieldID: relationship.SourceFieldID,
-				TargetTableID: relationship.TargetTableID,
-				TargetFieldID: relationship.TargetFieldID,
-				RelationType:  relationship.RelationType,
-				CreatedAt:     relationship.CreatedAt,
-				UpdatedAt:     relationship.UpdatedAt,
-			})
+			relationshipResponses = append(relationshipResponses, newRelationshipResponse(relationship))
 		}
 
 		responses.RespondWithSuccess(w, http.StatusOK, "Relationships retrieved successfully", relationshipResponses)
@@ -178,17 +150,7 @@ func (h *RelationshipHandler) GetByTableID() http.HandlerFunc {
 		// Convert to response format
 		var relationshipResponses []dto.RelationshipResponse
 		for _, relationship := range relationships {
-			relationshipResponses = append(relationshipResponses, dto.RelationshipResponse{
-				ID:            relationship.ID,
-				ProjectID:     relationship.ProjectID,
-				SourceTableID: relationship.SourceTableID,
-				SourceFieldID: relationship.SourceFieldID,
-				TargetTableID: relationship.TargetTableID,
-				TargetFieldID: relationship.TargetFieldID,
-				RelationType:  relationship.RelationType,
-				CreatedAt:     relationship.CreatedAt,
-				UpdatedAt:     relationship.UpdatedAt,
-			})
+			relationshipResponses = append(relationshipResponses, newRelationshipResponse(relationship))
 		}
 
 		responses.RespondWithSuccess(w, http.StatusOK, "Relationships retrieved successfully", relationshipResponses)
@@ -197,6 +159,16 @@ func (h *RelationshipHandler) GetByTableID() http.HandlerFunc {
 
 // Update handles relationship updates
 func (h *RelationshipHandler) Update() http.HandlerFunc {
+	return h.update(utils.DecodeAndValidate)
+}
+
+// Patch applies a JSON Merge Patch to a relationship
+func (h *RelationshipHandler) Patch() http.HandlerFunc {
+	return h.update(utils.DecodeMergePatch)
+}
+
+// update reads the changes with decode, which responds itself when they are invalid
+func (h *RelationshipHandler) update(decode func(http.ResponseWriter, *http.Request, any) bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Get relationship ID from URL
 		relationshipID, ok := utils.ParseUUIDParam(w, r, "relationship_id")
@@ -206,10 +178,19 @@ func (h *RelationshipHandler) Update() http.HandlerFunc {
 
 		// Parse and validate request body
 		var req dto.UpdateRelationshipRequest
-		if !utils.DecodeAndValidate(w, r, &req) {
+		if !decode(w, r, &req) {
 			return
 		}
 
+		// The If-Match header takes precedence over a version in the body
+		version, ok := utils.ParseIfMatch(w, r)
+		if !ok {
+			return
+		}
+		if version != nil {
+			req.Version = version
+		}
+
 		// Get current user ID from context for collaboration
 		userIDStr, ok := middleware.GetUserIDFromContext(r.Context())
 		if !ok {
@@ -225,7 +206,7 @@ func (h *RelationshipHandler) Update() http.HandlerFunc {
 
 		// Update relationship through service
 		relationship, err := h.relationshipService.UpdateRelationship(relationshipID, &req, userID)
-		if err != nil {
+		if err != nil && !errors.Is(err, services.ErrVersionConflict) {
 			switch {
 			case errors.Is(err, services.ErrRelationshipNotFound):
 				responses.RespondWithError(w, http.StatusNotFound, "Relationship not found")
@@ -242,18 +223,15 @@ func (h *RelationshipHandler) Update() http.HandlerFunc {
 		}
 
 		// Convert to response format
-		relationshipResponse := dto.RelationshipResponse{
-			ID:            relationship.ID,
-			ProjectID:     relationship.ProjectID,
-			SourceTableID: relationship.SourceTableID,
-			SourceFieldID: relationship.SourceFieldID,
-			TargetTableID: relationship.TargetTableID,
-			TargetFieldID: relationship.TargetFieldID,
-			RelationType:  relationship.RelationType,
-			CreatedAt:     relationship.CreatedAt,
-			UpdatedAt:     relationship.UpdatedAt,
+		relationshipResponse := newRelationshipResponse(relationship)
+
+		utils.SetVersionETag(w, relationship.Version)
+		if err != nil {
+			responses.RespondWithErrorData(w, http.StatusConflict, "Relationship was modified by someone else", relationshipResponse)
+			return
 		}
 
+		responses.SetProjectSequence(w, relationship.Sequence)
 		responses.RespondWithSuccess(w, http.StatusOK, "Relationship updated successfully", relationshipResponse)
 	}
 }
@@ -281,7 +259,8 @@ func (h *RelationshipHandler) Delete() http.HandlerFunc {
 		}
 
 		// Delete relationship through service with authorization check
-		if err := h.relationshipService.DeleteRelationship(relationshipID, userID); err != nil {
+		sequence, err := h.relationshipService.DeleteRelationship(relationshipID, userID)
+		if err != nil {
 			switch {
 			case errors.Is(err, services.ErrRelationshipNotFound):
 				responses.RespondWithError(w, http.StatusNotFound, "Relationship not found")
@@ -293,6 +272,7 @@ func (h *RelationshipHandler) Delete() http.HandlerFunc {
 			return
 		}
 
+		responses.SetProjectSequence(w, sequence)
 		responses.RespondWithSuccess(w, http.StatusOK, "Relationship deleted successfully", nil)
 	}
 }
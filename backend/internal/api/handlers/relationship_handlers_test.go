@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
 	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
@@ -490,7 +491,7 @@ func (suite *RelationshipHandlerTestSuite) TestDelete_Success() {
 	relationshipID := uuid.New()
 	userID := uuid.New()
 
-	suite.mockRelationshipService.On("DeleteRelationship", relationshipID, userID).Return(nil)
+	suite.mockRelationshipService.On("DeleteRelationship", relationshipID, userID).Return(int64(7), nil)
 
 	req := testutil.MakeJSONRequest(suite.T(), http.MethodDelete, "/relationships/"+relationshipID.String(), nil)
 	req = testutil.WithUserContext(req, userID) // Add user context
@@ -503,6 +504,7 @@ func (suite *RelationshipHandlerTestSuite) TestDelete_Success() {
 	suite.handler.Delete()(w, req)
 
 	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Relationship deleted successfully")
+	suite.Equal("7", w.Header().Get(responses.ProjectSequenceHeader))
 	suite.mockRelationshipService.AssertExpectations(suite.T())
 }
 
@@ -525,7 +527,7 @@ func (suite *RelationshipHandlerTestSuite) TestDelete_RelationshipNotFound() {
 	relationshipID := uuid.New()
 	userID := uuid.New()
 
-	suite.mockRelationshipService.On("DeleteRelationship", relationshipID, userID).Return(services.ErrRelationshipNotFound)
+	suite.mockRelationshipService.On("DeleteRelationship", relationshipID, userID).Return(int64(0), services.ErrRelationshipNotFound)
 
 	req := testutil.MakeJSONRequest(suite.T(), http.MethodDelete, "/relationships/"+relationshipID.String(), nil)
 	req = testutil.WithUserContext(req, userID) // Add user context
@@ -546,7 +548,7 @@ func (suite *RelationshipHandlerTestSuite) TestDelete_ServiceError() {
 	relationshipID := uuid.New()
 	userID := uuid.New()
 
-	suite.mockRelationshipService.On("DeleteRelationship", relationshipID, userID).Return(assert.AnError)
+	suite.mockRelationshipService.On("DeleteRelationship", relationshipID, userID).Return(int64(0), assert.AnError)
 
 	req := testutil.MakeJSONRequest(suite.T(), http.MethodDelete, "/relationships/"+relationshipID.String(), nil)
 	req = testutil.WithUserContext(req, userID) // Add user context
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type ReleaseHandler struct {
+	releaseService services.ReleaseServiceInterface
+}
+
+func NewReleaseHandler(releaseService services.ReleaseServiceInterface) *ReleaseHandler {
+	return &ReleaseHandler{
+		releaseService: releaseService,
+	}
+}
+
+// Create handles naming a snapshot of a project as a release
+func (h *ReleaseHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.CreateReleaseRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		release, err := h.releaseService.CreateRelease(projectID, &req, userID)
+		if err != nil {
+			respondWithReleaseError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusCreated, "Release created successfully", newReleaseResponse(release))
+	}
+}
+
+// GetByProjectID handles retrieving the releases of a project newest first
+func (h *ReleaseHandler) GetByProjectID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		releases, err := h.releaseService.GetReleases(projectID, userID)
+		if err != nil {
+			respondWithReleaseError(w, err)
+			return
+		}
+
+		releaseResponses := make([]dto.ReleaseResponse, len(releases))
+		for i, release := range releases {
+			releaseResponses[i] = newReleaseResponse(release)
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Releases retrieved successfully", releaseResponses)
+	}
+}
+
+// GetByID handles retrieving a specific release
+func (h *ReleaseHandler) GetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		releaseID, ok := utils.ParseUUIDParam(w, r, "release_id")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		release, err := h.releaseService.GetRelease(projectID, releaseID, userID)
+		if err != nil {
+			respondWithReleaseError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Release retrieved successfully", newReleaseResponse(release))
+	}
+}
+
+// Update handles editing, moving, locking or unlocking a release
+func (h *ReleaseHandler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		releaseID, ok := utils.ParseUUIDParam(w, r, "release_id")
+		if !ok {
+			return
+		}
+
+		var req dto.UpdateReleaseRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		release, err := h.releaseService.UpdateRelease(projectID, releaseID, &req, userID)
+		if err != nil {
+			respondWithReleaseError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Release updated successfully", newReleaseResponse(release))
+	}
+}
+
+// Delete handles release deletion
+func (h *ReleaseHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		releaseID, ok := utils.ParseUUIDParam(w, r, "release_id")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.releaseService.DeleteRelease(projectID, releaseID, userID); err != nil {
+			respondWithReleaseError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Release deleted successfully", nil)
+	}
+}
+
+func respondWithReleaseError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrProjectNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, services.ErrReleaseNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Release not found")
+	case errors.Is(err, services.ErrSnapshotNotFound):
+		responses.RespondWithError(w, http.StatusBadRequest, "Snapshot not found")
+	case errors.Is(err, services.ErrReleaseLocked):
+		responses.RespondWithError(w, http.StatusConflict, "Release is locked")
+	case errors.Is(err, services.ErrNameTaken):
+		responses.RespondWithError(w, http.StatusConflict, "A release of the project already has this name")
+	case errors.Is(err, services.ErrInvalidInput):
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+	case errors.Is(err, services.ErrForbidden):
+		responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func newReleaseResponse(release *models.Release) dto.ReleaseResponse {
+	return dto.ReleaseResponse{
+		ID:         release.ID,
+		ProjectID:  release.ProjectID,
+		Name:       release.Name,
+		Notes:      release.Notes,
+		SnapshotID: release.SnapshotID,
+		Locked:     release.Locked,
+		CreatedBy:  release.CreatedBy,
+		CreatedAt:  release.CreatedAt,
+		UpdatedAt:  release.UpdatedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReleaseHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockReleaseService
+	handler     *ReleaseHandler
+	userID      uuid.UUID
+	projectID   uuid.UUID
+	releaseID   uuid.UUID
+}
+
+func (suite *ReleaseHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockReleaseService)
+	suite.handler = NewReleaseHandler(suite.mockService)
+	suite.userID = uuid.New()
+	suite.projectID = uuid.New()
+	suite.releaseID = uuid.New()
+}
+
+func TestReleaseHandlerSuite(t *testing.T) {
+	suite.Run(t, new(ReleaseHandlerTestSuite))
+}
+
+// withRelease adds the project and release IDs to the route context, and the user
+func (suite *ReleaseHandlerTestSuite) withRelease(req *http.Request) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", suite.projectID.String())
+	rctx.URLParams.Add("release_id", suite.releaseID.String())
+	return testutil.WithUserContext(req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)), suite.userID)
+}
+
+func (suite *ReleaseHandlerTestSuite) releaseURL() string {
+	return "/projects/" + suite.projectID.String() + "/releases/" + suite.releaseID.String()
+}
+
+// Test Create - The release is returned with its pinned snapshot
+func (suite *ReleaseHandlerTestSuite) TestCreate_Success() {
+	snapshotID := uuid.New()
+	req := dto.CreateReleaseRequest{Name: "v1.0", Notes: "First cut", Locked: true}
+	release := &models.Release{ID: suite.releaseID, ProjectID: suite.projectID, Name: "v1.0", Notes: "First cut", SnapshotID: snapshotID, Locked: true}
+	suite.mockService.On("CreateRelease", suite.projectID, &req, suite.userID).Return(release, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, suite.withRelease(testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+suite.projectID.String()+"/releases", req)))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "Release created successfully")
+	data := response.Data.(map[string]any)
+	suite.Equal(suite.releaseID.String(), data["release_id"])
+	suite.Equal(snapshotID.String(), data["snapshot_id"])
+	suite.Equal(true, data["locked"])
+}
+
+// Test Create - Only snapshots of the project can be released
+func (suite *ReleaseHandlerTestSuite) TestCreate_SnapshotNotFound() {
+	suite.mockService.On("CreateRelease", suite.projectID, mock.AnythingOfType("*dto.CreateReleaseRequest"), suite.userID).Return(nil, services.ErrSnapshotNotFound)
+
+	snapshotID := uuid.New()
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, suite.withRelease(testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+suite.projectID.String()+"/releases",
+		dto.CreateReleaseRequest{Name: "v1.0", SnapshotID: &snapshotID})))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Snapshot not found")
+}
+
+// Test Update - Locked releases answer 409
+func (suite *ReleaseHandlerTestSuite) TestUpdate_Locked() {
+	suite.mockService.On("UpdateRelease", suite.projectID, suite.releaseID, mock.AnythingOfType("*dto.UpdateReleaseRequest"), suite.userID).Return(nil, services.ErrReleaseLocked)
+
+	name := "v1.0.1"
+	w := httptest.NewRecorder()
+	suite.handler.Update()(w, suite.withRelease(testutil.MakeJSONRequest(suite.T(), http.MethodPut, suite.releaseURL(), dto.UpdateReleaseRequest{Name: &name})))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "Release is locked")
+}
+
+// Test Delete - Locked releases are kept
+func (suite *ReleaseHandlerTestSuite) TestDelete_Locked() {
+	suite.mockService.On("DeleteRelease", suite.projectID, suite.releaseID, suite.userID).Return(services.ErrReleaseLocked)
+
+	w := httptest.NewRecorder()
+	suite.handler.Delete()(w, suite.withRelease(httptest.NewRequest(http.MethodDelete, suite.releaseURL(), nil)))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "Release is locked")
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type ReviewHandler struct {
+	reviewService services.ReviewServiceInterface
+}
+
+func NewReviewHandler(reviewService services.ReviewServiceInterface) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+	}
+}
+
+// Review handles reviewing a project's schema, answering the open comments
+// about what was found
+func (h *ReviewHandler) Review() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		comments, err := h.reviewService.ReviewSchema(r.Context(), projectID, userID)
+		if err != nil {
+			respondWithReviewError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Schema reviewed successfully", newReviewCommentResponses(comments))
+	}
+}
+
+// GetComments handles retrieving the review comments of a project, oldest first
+func (h *ReviewHandler) GetComments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		comments, err := h.reviewService.GetComments(projectID, userID)
+		if err != nil {
+			respondWithReviewError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Review comments retrieved successfully", newReviewCommentResponses(comments))
+	}
+}
+
+// Resolve handles marking a review comment resolved
+func (h *ReviewHandler) Resolve() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		commentID, ok := utils.ParseUUIDParam(w, r, "comment_id")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		comment, err := h.reviewService.ResolveComment(projectID, commentID, userID)
+		if err != nil {
+			respondWithReviewError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Review comment resolved successfully", newReviewCommentResponse(comment))
+	}
+}
+
+func respondWithReviewError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrProjectNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, services.ErrReviewCommentNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Review comment not found")
+	case errors.Is(err, services.ErrReviewFailed):
+		// The provider's error may name its endpoint; keep it out of the response
+		log.Printf("Review: %v", err)
+		responses.RespondWithError(w, http.StatusBadGateway, "The schema could not be reviewed, try again later")
+	case errors.Is(err, services.ErrForbidden):
+		responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func newReviewCommentResponses(comments []*models.ReviewComment) []dto.ReviewCommentResponse {
+	commentResponses := make([]dto.ReviewCommentResponse, len(comments))
+	for i, comment := range comments {
+		commentResponses[i] = newReviewCommentResponse(comment)
+	}
+	return commentResponses
+}
+
+func newReviewCommentResponse(comment *models.ReviewComment) dto.ReviewCommentResponse {
+	return dto.ReviewCommentResponse{
+		ID:         comment.ID,
+		ProjectID:  comment.ProjectID,
+		TableID:    comment.TableID,
+		FieldID:    comment.FieldID,
+		TableName:  comment.TableName,
+		FieldName:  comment.FieldName,
+		Kind:       comment.Kind,
+		Message:    comment.Message,
+		Suggestion: comment.Suggestion,
+		Provider:   comment.Provider,
+		CreatedBy:  comment.CreatedBy,
+		ResolvedBy: comment.ResolvedBy,
+		ResolvedAt: comment.ResolvedAt,
+		CreatedAt:  comment.CreatedAt,
+	}
+}
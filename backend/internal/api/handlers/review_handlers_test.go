@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReviewHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockReviewService
+	handler     *ReviewHandler
+	userID      uuid.UUID
+	projectID   uuid.UUID
+}
+
+func (suite *ReviewHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockReviewService)
+	suite.handler = NewReviewHandler(suite.mockService)
+	suite.userID = uuid.New()
+	suite.projectID = uuid.New()
+}
+
+func TestReviewHandlerSuite(t *testing.T) {
+	suite.Run(t, new(ReviewHandlerTestSuite))
+}
+
+// withComment adds the project and comment IDs to the route context, and the user
+func (suite *ReviewHandlerTestSuite) withComment(req *http.Request, commentID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", suite.projectID.String())
+	rctx.URLParams.Add("comment_id", commentID.String())
+	return testutil.WithUserContext(req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)), suite.userID)
+}
+
+// Test Review - The open comments of the review are answered
+func (suite *ReviewHandlerTestSuite) TestReview_Success() {
+	tableID := uuid.New()
+	comment := &models.ReviewComment{ID: uuid.New(), ProjectID: suite.projectID, TableID: &tableID, TableName: "orders", Kind: "naming", Message: "Plural"}
+	suite.mockService.On("ReviewSchema", mock.Anything, suite.projectID, suite.userID).Return([]*models.ReviewComment{comment}, nil)
+
+	req := suite.withComment(httptest.NewRequest(http.MethodPost, "/projects/"+suite.projectID.String()+"/ai/review", nil), uuid.Nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Review()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Schema reviewed successfully")
+	data := response.Data.([]any)
+	suite.Require().Len(data, 1)
+	suite.Equal(comment.ID.String(), data[0].(map[string]any)["comment_id"])
+	suite.Equal(tableID.String(), data[0].(map[string]any)["table_id"])
+	suite.Nil(data[0].(map[string]any)["field_id"])
+}
+
+// Test Review - A failing provider is a bad gateway, its error kept out of the response
+func (suite *ReviewHandlerTestSuite) TestReview_ProviderFailed() {
+	err := fmt.Errorf("%w: %w", services.ErrReviewFailed, errors.New("dial tcp 10.0.0.7:443: connection refused"))
+	suite.mockService.On("ReviewSchema", mock.Anything, suite.projectID, suite.userID).Return(nil, err)
+
+	req := suite.withComment(httptest.NewRequest(http.MethodPost, "/projects/"+suite.projectID.String()+"/ai/review", nil), uuid.Nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Review()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadGateway, "The schema could not be reviewed, try again later")
+	suite.NotContains(w.Body.String(), "10.0.0.7")
+}
+
+// Test GetComments - Forbidden
+func (suite *ReviewHandlerTestSuite) TestGetComments_Forbidden() {
+	suite.mockService.On("GetComments", suite.projectID, suite.userID).Return(nil, services.ErrForbidden)
+
+	req := suite.withComment(httptest.NewRequest(http.MethodGet, "/projects/"+suite.projectID.String()+"/ai/review-comments", nil), uuid.Nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetComments()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "You don't have access to this project")
+}
+
+// Test Resolve - Success
+func (suite *ReviewHandlerTestSuite) TestResolve_Success() {
+	comment := &models.ReviewComment{ID: uuid.New(), ProjectID: suite.projectID, ResolvedBy: &suite.userID}
+	suite.mockService.On("ResolveComment", suite.projectID, comment.ID, suite.userID).Return(comment, nil)
+
+	req := suite.withComment(httptest.NewRequest(http.MethodPost, "/projects/"+suite.projectID.String()+"/ai/review-comments/"+comment.ID.String()+"/resolve", nil), comment.ID)
+	w := httptest.NewRecorder()
+
+	suite.handler.Resolve()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Review comment resolved successfully")
+	suite.Equal(suite.userID.String(), response.Data.(map[string]any)["resolved_by"])
+}
+
+// Test Resolve - Not found
+func (suite *ReviewHandlerTestSuite) TestResolve_NotFound() {
+	commentID := uuid.New()
+	suite.mockService.On("ResolveComment", suite.projectID, commentID, suite.userID).Return(nil, services.ErrReviewCommentNotFound)
+
+	req := suite.withComment(httptest.NewRequest(http.MethodPost, "/projects/"+suite.projectID.String()+"/ai/review-comments/"+commentID.String()+"/resolve", nil), commentID)
+	w := httptest.NewRecorder()
+
+	suite.handler.Resolve()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusNotFound, "Review comment not found")
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+const (
+	samlRequestIDCookie  = "saml_request_id"
+	samlCookiePath       = "/api/auth/saml"
+	samlCookieMaxAge     = 10 * 60 // Seconds the user has to complete the IdP login
+	samlMaxResponseBytes = 1 << 20 // SAML responses are small; refuse anything larger
+)
+
+type SAMLHandler struct {
+	samlService        services.SAMLServiceInterface
+	userSessionService services.UserSessionServiceInterface
+	jwtService         services.JWTServiceInterface
+	cfg                *config.Config
+}
+
+func NewSAMLHandler(samlService services.SAMLServiceInterface, userSessionService services.UserSessionServiceInterface, jwtService services.JWTServiceInterface, cfg *config.Config) *SAMLHandler {
+	return &SAMLHandler{
+		samlService:        samlService,
+		userSessionService: userSessionService,
+		jwtService:         jwtService,
+		cfg:                cfg,
+	}
+}
+
+// Metadata serves the service provider metadata for registration with the IdP
+func (h *SAMLHandler) Metadata() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		metadata, err := h.samlService.Metadata()
+		if err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to build SAML metadata")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/samlmetadata+xml")
+		w.WriteHeader(http.StatusOK)
+		w.Write(metadata)
+	}
+}
+
+// Login redirects the browser to the IdP. The request ID is kept in a cookie
+// so the ACS endpoint only accepts the response to this request.
+func (h *SAMLHandler) Login() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		redirectURL, requestID, err := h.samlService.LoginURL("")
+		if err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to start login")
+			return
+		}
+
+		h.setRequestIDCookie(w, requestID, samlCookieMaxAge)
+		http.Redirect(w, r, redirectURL, http.StatusFound)
+	}
+}
+
+// ACS consumes the SAML response posted by the IdP, issues the usual auth
+// cookies and redirects back to the frontend. Failures are reported to the
+// frontend in the saml_error query parameter.
+func (h *SAMLHandler) ACS() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, samlMaxResponseBytes)
+		if err := r.ParseForm(); err != nil {
+			h.redirectToFrontend(w, r, "invalid_response")
+			return
+		}
+
+		var requestIDs []string
+		if cookie, err := r.Cookie(samlRequestIDCookie); err == nil && cookie.Value != "" {
+			requestIDs = append(requestIDs, cookie.Value)
+		}
+
+		// The request ID is single use
+		h.setRequestIDCookie(w, "", -1)
+
+		user, err := h.samlService.Authenticate(r, requestIDs)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrSAMLAssertionInvalid):
+				h.redirectToFrontend(w, r, "invalid_response")
+			case errors.Is(err, services.ErrUserNotFound):
+				h.redirectToFrontend(w, r, "not_provisioned")
+			default:
+				log.Printf("SAML login failed: %v", err)
+				h.redirectToFrontend(w, r, "login_failed")
+			}
+			return
+		}
+
+		tokens, err := h.userSessionService.StartSession(user, utils.ClientIP(r, h.cfg.RateLimit.TrustProxy), r.UserAgent())
+		if err != nil {
+			if errors.Is(err, services.ErrAccountDisabled) {
+				h.redirectToFrontend(w, r, "account_disabled")
+			} else {
+				h.redirectToFrontend(w, r, "login_failed")
+			}
+			return
+		}
+
+		setAuthCookies(w, h.cfg, h.jwtService, tokens)
+		h.redirectToFrontend(w, r, "")
+	}
+}
+
+// setRequestIDCookie sets the request ID cookie. The IdP posts the response
+// cross-site, which browsers only allow for SameSite=None cookies over HTTPS;
+// outside production Lax is used and IdP-initiated validation applies.
+func (h *SAMLHandler) setRequestIDCookie(w http.ResponseWriter, value string, maxAge int) {
+	secure := h.cfg.Env == "production"
+	sameSite := http.SameSiteLaxMode
+	if secure {
+		sameSite = http.SameSiteNoneMode
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     samlRequestIDCookie,
+		Value:    value,
+		Path:     samlCookiePath,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: sameSite,
+		MaxAge:   maxAge,
+	})
+}
+
+// redirectToFrontend sends the browser back to the frontend, with an error code if login failed
+func (h *SAMLHandler) redirectToFrontend(w http.ResponseWriter, r *http.Request, errorCode string) {
+	target := h.cfg.OAuth.FrontendURL
+	if errorCode != "" {
+		if u, err := url.Parse(target); err == nil {
+			q := u.Query()
+			q.Set("saml_error", errorCode)
+			u.RawQuery = q.Encode()
+			target = u.String()
+		}
+	}
+
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SAMLHandlerTestSuite struct {
+	suite.Suite
+	mockSAMLService    *mockService.MockSAMLService
+	mockSessionService *mockService.MockUserSessionService
+	mockJWTService     *mockService.MockJWTService
+	handler            *SAMLHandler
+	cfg                *config.Config
+}
+
+func (suite *SAMLHandlerTestSuite) SetupTest() {
+	suite.mockSAMLService = new(mockService.MockSAMLService)
+	suite.mockSessionService = new(mockService.MockUserSessionService)
+	suite.mockJWTService = new(mockService.MockJWTService)
+	suite.cfg = config.New()
+	suite.cfg.OAuth.FrontendURL = "http://app.example.com/"
+	suite.handler = NewSAMLHandler(suite.mockSAMLService, suite.mockSessionService, suite.mockJWTService, suite.cfg)
+}
+
+func TestSAMLHandlerSuite(t *testing.T) {
+	suite.Run(t, new(SAMLHandlerTestSuite))
+}
+
+func (suite *SAMLHandlerTestSuite) getCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, c := range cookies {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (suite *SAMLHandlerTestSuite) samlErrorFrom(w *httptest.ResponseRecorder) string {
+	location, err := url.Parse(w.Header().Get("Location"))
+	suite.Require().NoError(err)
+	return location.Query().Get("saml_error")
+}
+
+func (suite *SAMLHandlerTestSuite) acsRequest(requestID string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/saml/acs", strings.NewReader("SAMLResponse=abc"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if requestID != "" {
+		req.AddCookie(&http.Cookie{Name: samlRequestIDCookie, Value: requestID})
+	}
+	return req
+}
+
+// Test Metadata - Serves SP metadata XML
+func (suite *SAMLHandlerTestSuite) TestMetadata_Success() {
+	suite.mockSAMLService.On("Metadata").Return([]byte("<EntityDescriptor/>"), nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Metadata()(w, httptest.NewRequest(http.MethodGet, "/api/auth/saml/metadata", nil))
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Equal(suite.T(), "application/samlmetadata+xml", w.Header().Get("Content-Type"))
+	assert.Equal(suite.T(), "<EntityDescriptor/>", w.Body.String())
+}
+
+// Test Login - Redirects to IdP and stores the request ID
+func (suite *SAMLHandlerTestSuite) TestLogin_Success() {
+	suite.mockSAMLService.On("LoginURL", "").Return("https://idp.example.com/sso?SAMLRequest=x", "id-123", nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Login()(w, httptest.NewRequest(http.MethodGet, "/api/auth/saml/login", nil))
+
+	assert.Equal(suite.T(), http.StatusFound, w.Code)
+	assert.Equal(suite.T(), "https://idp.example.com/sso?SAMLRequest=x", w.Header().Get("Location"))
+
+	cookie := suite.getCookie(w.Result().Cookies(), samlRequestIDCookie)
+	suite.Require().NotNil(cookie)
+	assert.Equal(suite.T(), "id-123", cookie.Value)
+	assert.True(suite.T(), cookie.HttpOnly)
+	assert.Equal(suite.T(), samlCookiePath, cookie.Path)
+}
+
+// Test Login - Production cookie survives the cross-site POST from the IdP
+func (suite *SAMLHandlerTestSuite) TestLogin_ProductionCookie() {
+	suite.cfg.Env = "production"
+	suite.mockSAMLService.On("LoginURL", "").Return("https://idp.example.com/sso", "id-123", nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Login()(w, httptest.NewRequest(http.MethodGet, "/api/auth/saml/login", nil))
+
+	cookie := suite.getCookie(w.Result().Cookies(), samlRequestIDCookie)
+	suite.Require().NotNil(cookie)
+	assert.True(suite.T(), cookie.Secure)
+	assert.Equal(suite.T(), http.SameSiteNoneMode, cookie.SameSite)
+}
+
+// Test ACS - Valid response issues auth cookies and redirects to frontend
+func (suite *SAMLHandlerTestSuite) TestACS_Success() {
+	user := testutil.CreateTestUser()
+	tokens := &services.TokenPair{AccessToken: "access", RefreshToken: "refresh"}
+
+	suite.mockSAMLService.On("Authenticate", mock.AnythingOfType("*http.Request"), []string{"id-123"}).Return(user, nil)
+	suite.mockSessionService.On("StartSession", user, mock.Anything, mock.Anything).Return(tokens, nil)
+	suite.mockJWTService.On("GetAccessTokenExpiration").Return(15 * time.Minute)
+	suite.mockJWTService.On("GetRefreshTokenExpiration").Return(7 * 24 * time.Hour)
+
+	w := httptest.NewRecorder()
+	suite.handler.ACS()(w, suite.acsRequest("id-123"))
+
+	assert.Equal(suite.T(), http.StatusSeeOther, w.Code)
+	assert.Equal(suite.T(), "http://app.example.com/", w.Header().Get("Location"))
+
+	accessCookie := suite.getCookie(w.Result().Cookies(), "access_token")
+	suite.Require().NotNil(accessCookie)
+	assert.Equal(suite.T(), tokens.AccessToken, accessCookie.Value)
+
+	requestIDCookie := suite.getCookie(w.Result().Cookies(), samlRequestIDCookie)
+	suite.Require().NotNil(requestIDCookie)
+	assert.Equal(suite.T(), -1, requestIDCookie.MaxAge)
+}
+
+// Test ACS - IdP-initiated login has no request ID
+func (suite *SAMLHandlerTestSuite) TestACS_IDPInitiated() {
+	user := testutil.CreateTestUser()
+
+	suite.mockSAMLService.On("Authenticate", mock.AnythingOfType("*http.Request"), []string(nil)).Return(user, nil)
+	suite.mockSessionService.On("StartSession", user, mock.Anything, mock.Anything).Return(&services.TokenPair{AccessToken: "access", RefreshToken: "refresh"}, nil)
+	suite.mockJWTService.On("GetAccessTokenExpiration").Return(15 * time.Minute)
+	suite.mockJWTService.On("GetRefreshTokenExpiration").Return(7 * 24 * time.Hour)
+
+	w := httptest.NewRecorder()
+	suite.handler.ACS()(w, suite.acsRequest(""))
+
+	assert.Equal(suite.T(), http.StatusSeeOther, w.Code)
+	assert.Empty(suite.T(), suite.samlErrorFrom(w))
+}
+
+// Test ACS - Maps service errors to frontend error codes
+func (suite *SAMLHandlerTestSuite) TestACS_Errors() {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"invalid assertion", services.ErrSAMLAssertionInvalid, "invalid_response"},
+		{"not provisioned", services.ErrUserNotFound, "not_provisioned"},
+		{"unexpected", errors.New("database down"), "login_failed"},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.SetupTest()
+			suite.mockSAMLService.On("Authenticate", mock.Anything, mock.Anything).Return(nil, tc.err)
+
+			w := httptest.NewRecorder()
+			suite.handler.ACS()(w, suite.acsRequest("id-123"))
+
+			assert.Equal(suite.T(), http.StatusSeeOther, w.Code)
+			assert.Equal(suite.T(), tc.expected, suite.samlErrorFrom(w))
+			suite.mockSessionService.AssertNotCalled(suite.T(), "StartSession", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,877 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/apply"
+	"github.com/Bug-Bugger/ezmodel/internal/ddl"
+	"github.com/Bug-Bugger/ezmodel/internal/fixtures"
+	"github.com/Bug-Bugger/ezmodel/internal/inference"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/spreadsheet"
+	"github.com/google/uuid"
+)
+
+// spreadsheetFormField is the multipart field holding an imported spreadsheet
+const spreadsheetFormField = "file"
+
+type SchemaHandler struct {
+	schemaService services.SchemaServiceInterface
+}
+
+func NewSchemaHandler(schemaService services.SchemaServiceInterface) *SchemaHandler {
+	return &SchemaHandler{
+		schemaService: schemaService,
+	}
+}
+
+// Create handles adding tables, fields and relationships to a project at once
+func (h *SchemaHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.CreateSchemaRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userIDStr, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			responses.RespondWithError(w, http.StatusUnauthorized, "User context not found")
+			return
+		}
+
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+
+		dryRun, ok := parseDryRun(w, r)
+		if !ok {
+			return
+		}
+
+		var schema *services.Schema
+		if dryRun {
+			schema, err = h.schemaService.PlanSchema(projectID, &req, userID)
+		} else {
+			schema, err = h.schemaService.CreateSchema(projectID, &req, userID)
+		}
+		if err != nil {
+			respondWithSchemaError(w, err)
+			return
+		}
+
+		respondWithSchema(w, schema, dryRun)
+	}
+}
+
+// Import handles creating tables from the spreadsheet in the file field of a
+// multipart/form-data body, one table per sheet. Conflicts with existing
+// tables are resolved as the JSON conflicts field says.
+func (h *SchemaHandler) Import() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+		dryRun, ok := parseDryRun(w, r)
+		if !ok {
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, spreadsheet.MaxSize+multipartOverhead)
+		file, header, err := r.FormFile(spreadsheetFormField)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				responses.RespondWithError(w, http.StatusRequestEntityTooLarge, "Spreadsheet is too large")
+			} else {
+				responses.RespondWithError(w, http.StatusBadRequest, "Spreadsheet file is required")
+			}
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, spreadsheet.MaxSize+1))
+		if err != nil {
+			responses.RespondWithError(w, http.StatusBadRequest, "Failed to read spreadsheet")
+			return
+		}
+
+		var form dto.ImportSchemaForm
+		if value := r.FormValue("conflicts"); value != "" {
+			if err := json.Unmarshal([]byte(value), &form.Conflicts); err != nil {
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid conflicts field")
+				return
+			}
+		}
+		if !utils.Validate(w, r, &form) {
+			return
+		}
+
+		var schema *services.Schema
+		if dryRun {
+			schema, err = h.schemaService.PlanSpreadsheet(projectID, header.Filename, data, form.Conflicts, userID)
+		} else {
+			schema, err = h.schemaService.ImportSpreadsheet(projectID, header.Filename, data, form.Conflicts, userID)
+		}
+		if err != nil {
+			var spreadsheetErr *spreadsheet.ValidationError
+			switch {
+			case errors.As(err, &spreadsheetErr):
+				responses.RespondWithValidationErrors(w, spreadsheetValidationErrors(spreadsheetErr))
+			case errors.Is(err, spreadsheet.ErrUnsupportedFormat):
+				responses.RespondWithError(w, http.StatusBadRequest, "Spreadsheet must be an .xlsx or .csv file")
+			case errors.Is(err, spreadsheet.ErrTooLarge):
+				responses.RespondWithError(w, http.StatusRequestEntityTooLarge, "Spreadsheet is too large")
+			default:
+				respondWithSchemaError(w, err)
+			}
+			return
+		}
+
+		respondWithSchema(w, schema, dryRun)
+	}
+}
+
+// Infer handles proposing tables for MongoDB collections without creating them
+func (h *SchemaHandler) Infer() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.InferSchemaRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		proposal, err := h.schemaService.InferSchema(projectID, &req, userID)
+		if err != nil {
+			if errors.Is(err, inference.ErrNotObject) {
+				responses.RespondWithError(w, http.StatusBadRequest, "Documents and Mongoose schemas must be JSON objects")
+			} else {
+				respondWithSchemaError(w, err)
+			}
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Schema proposed successfully", dto.InferSchemaResponse{
+			Schema: proposal.Schema,
+			Notes:  proposal.Notes,
+		})
+	}
+}
+
+// Compare handles diffing the schema of a project against another's
+func (h *SchemaHandler) Compare() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		otherProjectID, ok := utils.ParseUUIDParamWithError(w, r, "other_project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		diff, err := h.schemaService.CompareProjects(projectID, otherProjectID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrProjectNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to compare projects")
+			}
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Projects compared successfully", newSchemaDiffResponse(diff))
+	}
+}
+
+// GenerateData handles generating fake rows for every table of a project
+func (h *SchemaHandler) GenerateData() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.GenerateDataRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		archive, err := h.schemaService.GenerateData(projectID, &req, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, fixtures.ErrCycle):
+				responses.RespondWithError(w, http.StatusBadRequest, "Tables reference each other through required foreign keys")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to generate data")
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="ezmodel-data.zip"`)
+		w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(archive)
+	}
+}
+
+// Export serves the project's schema as a file, in the format of the format
+// query parameter, sql by default. With the release query parameter, the
+// schema is the one of the project's release of that name.
+func (h *SchemaHandler) Export() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = services.ExportFormatSQL
+		}
+
+		var export *services.SchemaExport
+		var err error
+		if release := r.URL.Query().Get("release"); release != "" {
+			export, err = h.schemaService.ExportRelease(projectID, release, format, userID)
+		} else {
+			export, err = h.schemaService.ExportSchema(projectID, format, userID)
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrUnknownExportFormat):
+				responses.RespondWithError(w, http.StatusBadRequest, "Format must be sql, json, dbt, avro or protobuf")
+			case errors.Is(err, ddl.ErrUnsupportedDialect):
+				responses.RespondWithError(w, http.StatusBadRequest, "Projects of this database type cannot be exported as SQL")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+			case errors.Is(err, services.ErrProjectNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+			case errors.Is(err, services.ErrReleaseNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Release not found")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to export schema")
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", export.ContentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.Filename))
+		w.Header().Set("Content-Length", strconv.Itoa(len(export.Data)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(export.Data)
+	}
+}
+
+// PlanApply handles planning how to bring a target database to the project's model
+func (h *SchemaHandler) PlanApply() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.PlanApplyRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		plan, err := h.schemaService.PlanApply(projectID, &req, userID)
+		if err != nil {
+			respondWithApplyError(w, err)
+			return
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Plan computed successfully", newApplyPlanResponse(plan))
+	}
+}
+
+// ExecuteApply handles bringing a target database to the project's model
+func (h *SchemaHandler) ExecuteApply() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.ExecuteApplyRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		applyLog, err := h.schemaService.ExecuteApply(projectID, &req, userID)
+		if err != nil {
+			respondWithApplyError(w, err)
+			return
+		}
+
+		response := dto.ApplyLogResponse{
+			Checksum:    applyLog.Plan.Checksum,
+			Transaction: applyLog.Transaction,
+			Status:      "unchanged",
+			Statements:  make([]dto.ApplyResultResponse, len(applyLog.Results)),
+			Unsupported: append([]string{}, applyLog.Plan.Unsupported...),
+		}
+		for i, result := range applyLog.Results {
+			response.Statements[i] = dto.ApplyResultResponse{
+				SQL:        result.SQL,
+				Status:     result.Status,
+				Error:      result.Error,
+				DurationMS: result.Duration.Milliseconds(),
+			}
+			response.Status = apply.StatusApplied
+			if result.Status == apply.StatusFailed {
+				response.Status = apply.StatusFailed
+				break
+			}
+		}
+		message := "Plan applied successfully"
+		if response.Status == apply.StatusFailed {
+			message = "Plan failed"
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, message, response)
+	}
+}
+
+// Rename handles renaming a table or field, recording it for migrations
+func (h *SchemaHandler) Rename() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.RenameRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		rename, err := h.schemaService.Rename(projectID, &req, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidInput):
+				responses.RespondWithError(w, http.StatusBadRequest, "The new name must differ from the current one")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have permission to modify this project")
+			case errors.Is(err, services.ErrTableNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Table not found")
+			case errors.Is(err, services.ErrFieldNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Field not found")
+			case errors.Is(err, services.ErrNameTaken):
+				respondWithTaken(w, err, "The name is already taken")
+			case errors.Is(err, services.ErrVersionConflict):
+				responses.RespondWithError(w, http.StatusConflict, "Modified concurrently; try again")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to rename")
+			}
+			return
+		}
+
+		responses.SetProjectSequence(w, rename.Sequence)
+		responses.RespondWithSuccess(w, http.StatusCreated, "Renamed successfully", newRenameResponse(rename))
+	}
+}
+
+// ListRenames handles listing the tables and fields renamed by refactoring
+func (h *SchemaHandler) ListRenames() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		renames, err := h.schemaService.ListRenames(projectID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrProjectNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to list renames")
+			}
+			return
+		}
+
+		response := make([]dto.RenameResponse, len(renames))
+		for i, rename := range renames {
+			response[i] = newRenameResponse(rename)
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Renames retrieved successfully", response)
+	}
+}
+
+func newRenameResponse(rename *models.SchemaRename) dto.RenameResponse {
+	return dto.RenameResponse{
+		ID:        rename.ID,
+		TableID:   rename.TableID,
+		FieldID:   rename.FieldID,
+		TableName: rename.TableName,
+		OldName:   rename.OldName,
+		NewName:   rename.NewName,
+		RenamedBy: rename.RenamedBy,
+		CreatedAt: rename.CreatedAt,
+	}
+}
+
+// Lint handles checking a project's schema for likely design problems
+func (h *SchemaHandler) Lint() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		findings, err := h.schemaService.LintSchema(projectID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrProjectNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to lint schema")
+			}
+			return
+		}
+
+		response := make([]dto.LintFindingResponse, len(findings))
+		for i, finding := range findings {
+			response[i] = dto.LintFindingResponse{
+				Rule:        finding.Rule,
+				Table:       finding.Table,
+				Fields:      finding.Fields,
+				Message:     finding.Message,
+				Explanation: finding.Explanation,
+			}
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Schema linted successfully", response)
+	}
+}
+
+// SuggestRelationships handles proposing the foreign keys a project's schema seems to miss
+func (h *SchemaHandler) SuggestRelationships() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		suggestions, err := h.schemaService.SuggestRelationships(projectID, userID)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrProjectNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to suggest relationships")
+			}
+			return
+		}
+
+		response := make([]dto.RelationshipSuggestionResponse, len(suggestions))
+		for i, suggestion := range suggestions {
+			response[i] = dto.RelationshipSuggestionResponse{
+				SourceTable:   suggestion.SourceTable,
+				SourceField:   suggestion.SourceField,
+				TargetTable:   suggestion.TargetTable,
+				TargetField:   suggestion.TargetField,
+				RelationType:  suggestion.RelationType,
+				SourceTableID: suggestion.SourceTableID,
+				SourceFieldID: suggestion.SourceFieldID,
+				TargetTableID: suggestion.TargetTableID,
+				TargetFieldID: suggestion.TargetFieldID,
+				Reason:        suggestion.Reason,
+			}
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Relationships suggested successfully", response)
+	}
+}
+
+// AcceptRelationshipSuggestions handles creating suggested relationships at once
+func (h *SchemaHandler) AcceptRelationshipSuggestions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.AcceptRelationshipSuggestionsRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		schema, err := h.schemaService.AcceptRelationshipSuggestions(projectID, req.Relationships, userID)
+		if err != nil {
+			respondWithSchemaError(w, err)
+			return
+		}
+
+		response := make([]dto.RelationshipResponse, len(schema.Relationships))
+		for i, relationship := range schema.Relationships {
+			response[i] = newRelationshipResponse(relationship)
+		}
+		responses.SetProjectSequence(w, schema.Sequence)
+		responses.RespondWithSuccess(w, http.StatusCreated, "Relationships created successfully", response)
+	}
+}
+
+func respondWithApplyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ddl.ErrInvalidDataType), errors.Is(err, ddl.ErrInvalidDefaultValue):
+		responses.RespondWithError(w, http.StatusUnprocessableEntity, "The model cannot be applied until this field is fixed: "+err.Error())
+	case errors.Is(err, services.ErrInvalidInput):
+		responses.RespondWithError(w, http.StatusBadRequest, "The connection string must be a postgres:// URL to a public host")
+	case errors.Is(err, ddl.ErrUnsupportedDialect):
+		responses.RespondWithError(w, http.StatusBadRequest, "Only PostgreSQL projects can be applied")
+	case errors.Is(err, services.ErrForbidden):
+		responses.RespondWithError(w, http.StatusForbidden, "You don't have permission to modify this project")
+	case errors.Is(err, services.ErrProjectNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, services.ErrApplyPlanChanged):
+		responses.RespondWithError(w, http.StatusConflict, "The plan changed since it was reviewed; plan again")
+	case errors.Is(err, services.ErrDestructiveChanges):
+		responses.RespondWithError(w, http.StatusConflict, "The plan drops or converts data; allow destructive changes to apply it")
+	case errors.Is(err, services.ErrTargetDatabase):
+		log.Printf("Apply: %v", err)
+		responses.RespondWithError(w, http.StatusBadGateway, "Could not connect to or read the target database")
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, "Failed to apply schema")
+	}
+}
+
+func newApplyPlanResponse(plan *services.ApplyPlan) dto.ApplyPlanResponse {
+	response := dto.ApplyPlanResponse{
+		Statements:  make([]dto.ApplyStatementResponse, len(plan.Statements)),
+		Unsupported: append([]string{}, plan.Unsupported...),
+		Destructive: plan.Destructive(),
+		Checksum:    plan.Checksum,
+	}
+	for i, statement := range plan.Statements {
+		response.Statements[i] = dto.ApplyStatementResponse{SQL: statement.SQL, Destructive: statement.Destructive}
+	}
+	return response
+}
+
+func respondWithSchemaError(w http.ResponseWriter, err error) {
+	var conflictErr *services.SchemaConflictError
+	switch {
+	case errors.As(err, &conflictErr):
+		responses.RespondWithErrorData(w, http.StatusConflict, "Tables of the schema already exist", newSchemaConflictResponses(conflictErr.Conflicts))
+	case errors.Is(err, services.ErrProjectNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, services.ErrTableNotFound):
+		responses.RespondWithError(w, http.StatusBadRequest, "A relationship refers to an unknown table")
+	case errors.Is(err, services.ErrFieldNotFound):
+		responses.RespondWithError(w, http.StatusBadRequest, "A relationship refers to an unknown field")
+	case errors.Is(err, services.ErrInvalidInput):
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+	case errors.Is(err, services.ErrForbidden):
+		responses.RespondWithError(w, http.StatusForbidden, "You don't have permission to modify this project")
+	case errors.Is(err, services.ErrQuotaExceeded):
+		respondWithQuotaExceeded(w, err)
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+// respondWithSchema responds with a created schema, or with what would be
+// created when dryRun is set
+func respondWithSchema(w http.ResponseWriter, schema *services.Schema, dryRun bool) {
+	response := dto.SchemaResponse{
+		Tables:        make([]dto.TableWithFieldsResponse, len(schema.Tables)),
+		Fields:        make([]dto.FieldResponse, len(schema.Fields)),
+		Relationships: make([]dto.RelationshipResponse, len(schema.Relationships)),
+		Conflicts:     newSchemaConflictResponses(schema.Conflicts),
+		DryRun:        dryRun,
+	}
+	for i, table := range schema.Tables {
+		response.Tables[i] = newTableWithFieldsResponse(table)
+	}
+	for i, field := range schema.Fields {
+		response.Fields[i] = newFieldResponse(field)
+	}
+	for i, relationship := range schema.Relationships {
+		response.Relationships[i] = newRelationshipResponse(relationship)
+	}
+
+	if dryRun {
+		responses.RespondWithSuccess(w, http.StatusOK, "Schema checked successfully", response)
+		return
+	}
+	responses.SetProjectSequence(w, schema.Sequence)
+	responses.RespondWithSuccess(w, http.StatusCreated, "Schema created successfully", response)
+}
+
+func newSchemaConflictResponses(conflicts []services.SchemaConflict) []dto.SchemaConflictResponse {
+	response := make([]dto.SchemaConflictResponse, len(conflicts))
+	for i, conflict := range conflicts {
+		response[i] = dto.SchemaConflictResponse{
+			Table:           conflict.Table,
+			ExistingTableID: conflict.ExistingTableID,
+			Strategy:        conflict.Strategy,
+			NewName:         conflict.NewName,
+		}
+	}
+	return response
+}
+
+// parseDryRun reads the dry_run query parameter, false when it is absent
+func parseDryRun(w http.ResponseWriter, r *http.Request) (bool, bool) {
+	value := r.URL.Query().Get("dry_run")
+	if value == "" {
+		return false, true
+	}
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid dry_run value")
+		return false, false
+	}
+	return dryRun, true
+}
+
+// spreadsheetValidationErrors keys spreadsheet problems by their cell under file
+func spreadsheetValidationErrors(err *spreadsheet.ValidationError) map[string]string {
+	errs := make(map[string]string, len(err.Errors))
+	for cell, message := range err.Errors {
+		if cell == "" {
+			errs[spreadsheetFormField] = message
+		} else {
+			errs[spreadsheetFormField+"."+cell] = message
+		}
+	}
+	return errs
+}
+
+// newTableWithFieldsResponse converts a table loaded with its fields
+func newTableWithFieldsResponse(table *models.Table) dto.TableWithFieldsResponse {
+	var fieldResponses []dto.FieldResponse
+	for i := range table.Fields {
+		fieldResponses = append(fieldResponses, newFieldResponse(&table.Fields[i]))
+	}
+
+	return dto.TableWithFieldsResponse{
+		ID:        table.ID,
+		ProjectID: table.ProjectID,
+		Name:      table.Name,
+		PosX:      table.PosX,
+		PosY:      table.PosY,
+		Color:     table.Color,
+		Icon:      table.Icon,
+		Metadata:  metadataResponse(table.Metadata),
+		Fields:    fieldResponses,
+		CreatedAt: table.CreatedAt,
+		UpdatedAt: table.UpdatedAt,
+		Version:   table.Version,
+	}
+}
+
+func newFieldResponse(field *models.Field) dto.FieldResponse {
+	return dto.FieldResponse{
+		ID:           field.ID,
+		TableID:      field.TableID,
+		Name:         field.Name,
+		DataType:     field.DataType,
+		IsPrimaryKey: field.IsPrimaryKey,
+		IsNullable:   field.IsNullable,
+		DefaultValue: field.DefaultValue,
+		Position:     field.Position,
+		Metadata:     metadataResponse(field.Metadata),
+		CreatedAt:    field.CreatedAt,
+		UpdatedAt:    field.UpdatedAt,
+		Version:      field.Version,
+	}
+}
+
+func newRelationshipResponse(relationship *models.Relationship) dto.RelationshipResponse {
+	response := dto.RelationshipResponse{
+		ID:            relationship.ID,
+		ProjectID:     relationship.ProjectID,
+		SourceTableID: relationship.SourceTableID,
+		SourceFieldID: relationship.SourceFieldID,
+		TargetTableID: relationship.TargetTableID,
+		TargetFieldID: relationship.TargetFieldID,
+		RelationType:  relationship.RelationType,
+		SourceAnchor:  relationship.SourceAnchor,
+		TargetAnchor:  relationship.TargetAnchor,
+		Waypoints:     make([]dto.Point, len(relationship.Waypoints)),
+		LabelPosition: relationship.LabelPosition,
+		Metadata:      metadataResponse(relationship.Metadata),
+		CreatedAt:     relationship.CreatedAt,
+		UpdatedAt:     relationship.UpdatedAt,
+		Version:       relationship.Version,
+	}
+	for i, point := range relationship.Waypoints {
+		response.Waypoints[i] = dto.Point{X: point.X, Y: point.Y}
+	}
+	return response
+}
+
+// metadataResponse writes no metadata as an empty object
+func metadataResponse(metadata models.Metadata) map[string]any {
+	if metadata == nil {
+		return map[string]any{}
+	}
+	return metadata
+}
+
+func newSchemaDiffResponse(diff *schemadiff.Diff) dto.SchemaDiffResponse {
+	response := dto.SchemaDiffResponse{
+		Identical:            diff.Empty(),
+		AddedTables:          make([]dto.DiffTableResponse, len(diff.AddedTables)),
+		RemovedTables:        make([]dto.DiffTableResponse, len(diff.RemovedTables)),
+		ChangedTables:        make([]dto.TableDiffResponse, len(diff.ChangedTables)),
+		AddedRelationships:   make([]dto.DiffRelationshipResponse, len(diff.AddedRelationships)),
+		RemovedRelationships: make([]dto.DiffRelationshipResponse, len(diff.RemovedRelationships)),
+		ChangedRelationships: make([]dto.RelationshipDiffResponse, len(diff.ChangedRelationships)),
+	}
+	for i, table := range diff.AddedTables {
+		response.AddedTables[i] = newDiffTableResponse(table)
+	}
+	for i, table := range diff.RemovedTables {
+		response.RemovedTables[i] = newDiffTableResponse(table)
+	}
+	for i, table := range diff.ChangedTables {
+		tableResponse := dto.TableDiffResponse{
+			Name:          table.Name,
+			AddedFields:   newDiffFieldResponses(table.AddedFields),
+			RemovedFields: newDiffFieldResponses(table.RemovedFields),
+			ChangedFields: make([]dto.FieldDiffResponse, len(table.ChangedFields)),
+		}
+		for j, field := range table.ChangedFields {
+			tableResponse.ChangedFields[j] = dto.FieldDiffResponse{Name: field.Name, Changes: newDiffChangeResponses(field.Changes)}
+		}
+		response.ChangedTables[i] = tableResponse
+	}
+	for i, relationship := range diff.AddedRelationships {
+		response.AddedRelationships[i] = newDiffRelationshipResponse(relationship)
+	}
+	for i, relationship := range diff.RemovedRelationships {
+		response.RemovedRelationships[i] = newDiffRelationshipResponse(relationship)
+	}
+	for i, relationship := range diff.ChangedRelationships {
+		response.ChangedRelationships[i] = dto.RelationshipDiffResponse{
+			Relationship: newDiffRelationshipResponse(relationship.Relationship),
+			Changes:      newDiffChangeResponses(relationship.Changes),
+		}
+	}
+	return response
+}
+
+func newDiffTableResponse(table schemadiff.Table) dto.DiffTableResponse {
+	return dto.DiffTableResponse{Name: table.Name, Fields: newDiffFieldResponses(table.Fields)}
+}
+
+func newDiffFieldResponses(fields []schemadiff.Field) []dto.DiffFieldResponse {
+	response := make([]dto.DiffFieldResponse, len(fields))
+	for i, field := range fields {
+		response[i] = dto.DiffFieldResponse{
+			Name:         field.Name,
+			DataType:     field.DataType,
+			IsPrimaryKey: field.IsPrimaryKey,
+			IsNullable:   field.IsNullable,
+			DefaultValue: field.DefaultValue,
+		}
+	}
+	return response
+}
+
+func newDiffChangeResponses(changes []schemadiff.Change) []dto.DiffChangeResponse {
+	response := make([]dto.DiffChangeResponse, len(changes))
+	for i, change := range changes {
+		response[i] = dto.DiffChangeResponse{Attribute: change.Attribute, From: change.From, To: change.To}
+	}
+	return response
+}
+
+func newDiffRelationshipResponse(relationship schemadiff.Relationship) dto.DiffRelationshipResponse {
+	return dto.DiffRelationshipResponse{
+		SourceTable:  relationship.SourceTable,
+		SourceField:  relationship.SourceField,
+		TargetTable:  relationship.TargetTable,
+		TargetField:  relationship.TargetField,
+		RelationType: relationship.RelationType,
+	}
+}
@@ -0,0 +1,747 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/apply"
+	"github.com/Bug-Bugger/ezmodel/internal/ddl"
+	"github.com/Bug-Bugger/ezmodel/internal/fixtures"
+	"github.com/Bug-Bugger/ezmodel/internal/inference"
+	"github.com/Bug-Bugger/ezmodel/internal/lint"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/spreadsheet"
+	"github.com/Bug-Bugger/ezmodel/internal/suggest"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchemaHandlerTestSuite struct {
+	suite.Suite
+	mockSchemaService *mockService.MockSchemaService
+	handler           *SchemaHandler
+}
+
+func (suite *SchemaHandlerTestSuite) SetupTest() {
+	suite.mockSchemaService = new(mockService.MockSchemaService)
+	suite.handler = NewSchemaHandler(suite.mockSchemaService)
+}
+
+func TestSchemaHandlerSuite(t *testing.T) {
+	suite.Run(t, new(SchemaHandlerTestSuite))
+}
+
+func createValidSchemaRequest() dto.CreateSchemaRequest {
+	return dto.CreateSchemaRequest{
+		Tables: []dto.SchemaTableRequest{
+			{Name: "users", Fields: []dto.CreateFieldRequest{{Name: "id", DataType: "UUID", IsPrimaryKey: true}}},
+			{Name: "posts", Fields: []dto.CreateFieldRequest{{Name: "author_id", DataType: "UUID"}}},
+		},
+		Relationships: []dto.SchemaRelationshipRequest{
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id"},
+		},
+	}
+}
+
+func (suite *SchemaHandlerTestSuite) makeRequest(projectID, userID uuid.UUID, body any) *http.Request {
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+projectID.String()+"/schema", body)
+	req = testutil.WithUserContext(req, userID)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+// Test Create - Success
+func (suite *SchemaHandlerTestSuite) TestCreate_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	schemaRequest := createValidSchemaRequest()
+	users := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id"}}}
+	posts := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "posts", Fields: []models.Field{{ID: uuid.New(), Name: "author_id"}}}
+	schema := &services.Schema{
+		Tables: []*models.Table{users, posts},
+		Relationships: []*models.Relationship{{ID: uuid.New(), ProjectID: projectID,
+			SourceTableID: users.ID, SourceFieldID: users.Fields[0].ID, TargetTableID: posts.ID, TargetFieldID: posts.Fields[0].ID}},
+	}
+
+	suite.mockSchemaService.On("CreateSchema", projectID, &schemaRequest, userID).Return(schema, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, suite.makeRequest(projectID, userID, schemaRequest))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "Schema created successfully")
+	data, ok := response.Data.(map[string]any)
+	suite.True(ok, "Response data should be a schema")
+	suite.Len(data["tables"], 2)
+	suite.Len(data["relationships"], 1)
+
+	suite.mockSchemaService.AssertExpectations(suite.T())
+}
+
+// Test Create - A dry run returns what would be created, with conflicts
+func (suite *SchemaHandlerTestSuite) TestCreate_DryRun() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	schemaRequest := createValidSchemaRequest()
+	users := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "users"}
+	existingID := uuid.New()
+	schema := &services.Schema{
+		Tables:    []*models.Table{users},
+		Conflicts: []services.SchemaConflict{{Table: "users", ExistingTableID: existingID}},
+	}
+
+	suite.mockSchemaService.On("PlanSchema", projectID, &schemaRequest, userID).Return(schema, nil)
+
+	req := suite.makeRequest(projectID, userID, schemaRequest)
+	req.URL.RawQuery = "dry_run=true"
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Schema checked successfully")
+	suite.Empty(w.Header().Get(responses.ProjectSequenceHeader))
+	data := response.Data.(map[string]any)
+	suite.Equal(true, data["dry_run"])
+	conflicts := data["conflicts"].([]any)
+	suite.Require().Len(conflicts, 1)
+	suite.Equal(existingID.String(), conflicts[0].(map[string]any)["existing_table_id"])
+	suite.mockSchemaService.AssertNotCalled(suite.T(), "CreateSchema", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Create - Unresolved conflicts are listed
+func (suite *SchemaHandlerTestSuite) TestCreate_Conflict() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	schemaRequest := createValidSchemaRequest()
+	existingID := uuid.New()
+
+	suite.mockSchemaService.On("CreateSchema", projectID, &schemaRequest, userID).
+		Return(nil, &services.SchemaConflictError{Conflicts: []services.SchemaConflict{{Table: "users", ExistingTableID: existingID}}})
+
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, suite.makeRequest(projectID, userID, schemaRequest))
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "Tables of the schema already exist")
+	conflicts := response.Data.([]any)
+	suite.Require().Len(conflicts, 1)
+	suite.Equal("users", conflicts[0].(map[string]any)["table"])
+}
+
+// Test Create - Invalid dry_run value
+func (suite *SchemaHandlerTestSuite) TestCreate_InvalidDryRun() {
+	req := suite.makeRequest(uuid.New(), uuid.New(), createValidSchemaRequest())
+	req.URL.RawQuery = "dry_run=maybe"
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid dry_run value")
+}
+
+// Test Create - Unknown field in a relationship
+func (suite *SchemaHandlerTestSuite) TestCreate_UnknownField() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	schemaRequest := createValidSchemaRequest()
+
+	suite.mockSchemaService.On("CreateSchema", projectID, &schemaRequest, userID).Return(nil, services.ErrFieldNotFound)
+
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, suite.makeRequest(projectID, userID, schemaRequest))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "")
+}
+
+// Test Create - Forbidden
+func (suite *SchemaHandlerTestSuite) TestCreate_Forbidden() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	schemaRequest := createValidSchemaRequest()
+
+	suite.mockSchemaService.On("CreateSchema", projectID, &schemaRequest, userID).Return(nil, services.ErrForbidden)
+
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, suite.makeRequest(projectID, userID, schemaRequest))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "")
+}
+
+// Test Create - No tables
+func (suite *SchemaHandlerTestSuite) TestCreate_NoTables() {
+	w := httptest.NewRecorder()
+	suite.handler.Create()(w, suite.makeRequest(uuid.New(), uuid.New(), dto.CreateSchemaRequest{}))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "")
+	suite.mockSchemaService.AssertNotCalled(suite.T(), "CreateSchema")
+}
+
+func (suite *SchemaHandlerTestSuite) importRequest(projectID, userID uuid.UUID, filename string, data []byte) *http.Request {
+	return suite.importFormRequest(projectID, userID, filename, data, nil)
+}
+
+func (suite *SchemaHandlerTestSuite) importFormRequest(projectID, userID uuid.UUID, filename string, data []byte, fields map[string]string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	suite.Require().NoError(err)
+	_, _ = part.Write(data)
+	for name, value := range fields {
+		suite.Require().NoError(writer.WriteField(name, value))
+	}
+	suite.Require().NoError(writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/projects/"+projectID.String()+"/schema/import", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req = testutil.WithUserContext(req, userID)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+// Test Import - The file is passed on with its name and the created schema returned
+func (suite *SchemaHandlerTestSuite) TestImport_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	data := []byte("name,type\nid,uuid\n")
+	users := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id"}}}
+
+	suite.mockSchemaService.On("ImportSpreadsheet", projectID, "users.csv", data, []dto.SchemaConflictResolution(nil), userID).
+		Return(&services.Schema{Tables: []*models.Table{users}, Sequence: 3}, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Import()(w, suite.importRequest(projectID, userID, "users.csv", data))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "Schema created successfully")
+	suite.Equal("3", w.Header().Get(responses.ProjectSequenceHeader))
+	tables := response.Data.(map[string]any)["tables"].([]any)
+	suite.Len(tables, 1)
+	suite.Equal("users", tables[0].(map[string]any)["name"])
+}
+
+// Test Import - A dry run plans the import instead
+func (suite *SchemaHandlerTestSuite) TestImport_DryRun() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	data := []byte("name,type\nid,uuid\n")
+
+	suite.mockSchemaService.On("PlanSpreadsheet", projectID, "users.csv", data, []dto.SchemaConflictResolution(nil), userID).
+		Return(&services.Schema{Tables: []*models.Table{{ID: uuid.New(), Name: "users"}}}, nil)
+
+	req := suite.importRequest(projectID, userID, "users.csv", data)
+	req.URL.RawQuery = "dry_run=1"
+	w := httptest.NewRecorder()
+	suite.handler.Import()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Schema checked successfully")
+	suite.mockSchemaService.AssertNotCalled(suite.T(), "ImportSpreadsheet", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Import - Conflicts are resolved as the conflicts field says
+func (suite *SchemaHandlerTestSuite) TestImport_Conflicts() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	data := []byte("name,type\nid,uuid\n")
+	conflicts := []dto.SchemaConflictResolution{{Table: "users", Strategy: "rename", NewName: "people"}}
+
+	suite.mockSchemaService.On("ImportSpreadsheet", projectID, "users.csv", data, conflicts, userID).
+		Return(&services.Schema{Tables: []*models.Table{{ID: uuid.New(), Name: "people"}}}, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Import()(w, suite.importFormRequest(projectID, userID, "users.csv", data,
+		map[string]string{"conflicts": `[{"table":"users","strategy":"rename","new_name":"people"}]`}))
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "Schema created successfully")
+	suite.mockSchemaService.AssertExpectations(suite.T())
+}
+
+// Test Import - Invalid conflicts field
+func (suite *SchemaHandlerTestSuite) TestImport_InvalidConflicts() {
+	data := []byte("name,type\nid,uuid\n")
+
+	for _, value := range []string{"not json", `[{"table":"users","strategy":"replace"}]`} {
+		w := httptest.NewRecorder()
+		suite.handler.Import()(w, suite.importFormRequest(uuid.New(), uuid.New(), "users.csv", data, map[string]string{"conflicts": value}))
+
+		testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "")
+	}
+	suite.mockSchemaService.AssertNotCalled(suite.T(), "ImportSpreadsheet")
+}
+
+// Test Import - Problems are reported by cell under the file field
+func (suite *SchemaHandlerTestSuite) TestImport_InvalidSpreadsheet() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	data := []byte("name,type\nid,\n")
+
+	suite.mockSchemaService.On("ImportSpreadsheet", projectID, "users.csv", data, []dto.SchemaConflictResolution(nil), userID).
+		Return(nil, &spreadsheet.ValidationError{Errors: map[string]string{"users!B2": "Data type is required", "": "Defines no tables"}})
+
+	w := httptest.NewRecorder()
+	suite.handler.Import()(w, suite.importRequest(projectID, userID, "users.csv", data))
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Validation failed")
+	suite.Equal(map[string]any{"file.users!B2": "Data type is required", "file": "Defines no tables"}, response.Errors)
+}
+
+// Test Import - Other file types are refused
+func (suite *SchemaHandlerTestSuite) TestImport_UnsupportedFormat() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.mockSchemaService.On("ImportSpreadsheet", projectID, "model.ods", []byte("data"), []dto.SchemaConflictResolution(nil), userID).Return(nil, spreadsheet.ErrUnsupportedFormat)
+
+	w := httptest.NewRecorder()
+	suite.handler.Import()(w, suite.importRequest(projectID, userID, "model.ods", []byte("data")))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Spreadsheet must be an .xlsx or .csv file")
+}
+
+// Test Import - Without a file
+func (suite *SchemaHandlerTestSuite) TestImport_MissingFile() {
+	w := httptest.NewRecorder()
+	suite.handler.Import()(w, suite.makeRequest(uuid.New(), uuid.New(), map[string]any{}))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Spreadsheet file is required")
+	suite.mockSchemaService.AssertNotCalled(suite.T(), "ImportSpreadsheet")
+}
+
+// Test Infer - The proposal is returned for editing
+func (suite *SchemaHandlerTestSuite) TestInfer_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	inferRequest := dto.InferSchemaRequest{Collections: []dto.InferCollectionRequest{
+		{Name: "users", MongooseSchema: json.RawMessage(`{"email":"String"}`)},
+	}}
+	proposal := &services.SchemaProposal{
+		Schema: dto.CreateSchemaRequest{Tables: []dto.SchemaTableRequest{{Name: "users", Fields: []dto.CreateFieldRequest{{Name: "email", DataType: "STRING"}}}}},
+		Notes:  []string{"users.email: something to check"},
+	}
+	suite.mockSchemaService.On("InferSchema", projectID, &inferRequest, userID).Return(proposal, nil)
+
+	w := httptest.NewRecorder()
+	req := suite.makeRequest(projectID, userID, inferRequest)
+	suite.handler.Infer()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Schema proposed successfully")
+	data := response.Data.(map[string]any)
+	suite.Equal([]any{"users.email: something to check"}, data["notes"])
+	tables := data["schema"].(map[string]any)["tables"].([]any)
+	suite.Equal("users", tables[0].(map[string]any)["name"])
+}
+
+// Test Infer - A collection needs documents or a Mongoose schema, not both
+func (suite *SchemaHandlerTestSuite) TestInfer_DocumentsAndSchema() {
+	w := httptest.NewRecorder()
+	suite.handler.Infer()(w, suite.makeRequest(uuid.New(), uuid.New(), map[string]any{
+		"collections": []any{map[string]any{"name": "users", "documents": []any{map[string]any{}}, "mongoose_schema": map[string]any{}}},
+	}))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Validation failed")
+	suite.mockSchemaService.AssertNotCalled(suite.T(), "InferSchema")
+}
+
+// Test Infer - Documents that are not objects
+func (suite *SchemaHandlerTestSuite) TestInfer_NotObject() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.mockSchemaService.On("InferSchema", projectID, mock.Anything, userID).Return(nil, inference.ErrNotObject)
+
+	w := httptest.NewRecorder()
+	suite.handler.Infer()(w, suite.makeRequest(projectID, userID, map[string]any{
+		"collections": []any{map[string]any{"name": "users", "documents": []any{42}}},
+	}))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Documents and Mongoose schemas must be JSON objects")
+}
+
+func (suite *SchemaHandlerTestSuite) compareRequest(projectID, otherID, userID uuid.UUID) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/projects/"+projectID.String()+"/compare/"+otherID.String(), nil)
+	req = testutil.WithUserContext(req, userID)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	rctx.URLParams.Add("other_project_id", otherID.String())
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+// Test Compare - The diff is returned with every list present
+func (suite *SchemaHandlerTestSuite) TestCompare_Success() {
+	projectID := uuid.New()
+	otherID := uuid.New()
+	userID := uuid.New()
+	diff := &schemadiff.Diff{ChangedTables: []schemadiff.TableDiff{{
+		Name:          "users",
+		ChangedFields: []schemadiff.FieldDiff{{Name: "id", Changes: []schemadiff.Change{{Attribute: schemadiff.AttributeDataType, From: "UUID", To: "BIGINT"}}}},
+	}}}
+
+	suite.mockSchemaService.On("CompareProjects", projectID, otherID, userID).Return(diff, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Compare()(w, suite.compareRequest(projectID, otherID, userID))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Projects compared successfully")
+	data := response.Data.(map[string]any)
+	suite.Equal(false, data["identical"])
+	suite.Equal([]any{}, data["added_tables"])
+	table := data["changed_tables"].([]any)[0].(map[string]any)
+	suite.Equal([]any{}, table["added_fields"])
+	change := table["changed_fields"].([]any)[0].(map[string]any)["changes"].([]any)[0].(map[string]any)
+	suite.Equal(map[string]any{"attribute": "data_type", "from": "UUID", "to": "BIGINT"}, change)
+}
+
+// Test Compare - Either project missing
+func (suite *SchemaHandlerTestSuite) TestCompare_NotFound() {
+	projectID := uuid.New()
+	otherID := uuid.New()
+	userID := uuid.New()
+
+	suite.mockSchemaService.On("CompareProjects", projectID, otherID, userID).Return(nil, services.ErrProjectNotFound)
+
+	w := httptest.NewRecorder()
+	suite.handler.Compare()(w, suite.compareRequest(projectID, otherID, userID))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusNotFound, "Project not found")
+}
+
+// Test GenerateData - The archive is sent as a download
+func (suite *SchemaHandlerTestSuite) TestGenerateData_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	archive := []byte("PK\x05\x06")
+
+	suite.mockSchemaService.On("GenerateData", projectID, &dto.GenerateDataRequest{Rows: 50, Format: "sql"}, userID).Return(archive, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.GenerateData()(w, suite.makeRequest(projectID, userID, map[string]any{"rows": 50, "format": "sql"}))
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("application/zip", w.Header().Get("Content-Type"))
+	suite.Contains(w.Header().Get("Content-Disposition"), "ezmodel-data.zip")
+	suite.Equal(archive, w.Body.Bytes())
+}
+
+// Test GenerateData - Tables requiring each other cannot be filled
+func (suite *SchemaHandlerTestSuite) TestGenerateData_Cycle() {
+	projectID := uuid.New()
+	userID := uuid.New()
+
+	suite.mockSchemaService.On("GenerateData", projectID, mock.Anything, userID).Return(nil, fmt.Errorf("%w: teams, players", fixtures.ErrCycle))
+
+	w := httptest.NewRecorder()
+	suite.handler.GenerateData()(w, suite.makeRequest(projectID, userID, map[string]any{}))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Tables reference each other through required foreign keys")
+}
+
+// Test Export - The schema is sent as a download in the requested format
+func (suite *SchemaHandlerTestSuite) TestExport_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	export := &services.SchemaExport{Filename: "schema.json", ContentType: "application/json", Data: []byte(`{"tables":[]}`)}
+
+	suite.mockSchemaService.On("ExportSchema", projectID, services.ExportFormatJSON, userID).Return(export, nil)
+
+	req := suite.makeRequest(projectID, userID, nil)
+	req.URL.RawQuery = "format=json"
+	w := httptest.NewRecorder()
+	suite.handler.Export()(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("application/json", w.Header().Get("Content-Type"))
+	suite.Contains(w.Header().Get("Content-Disposition"), "schema.json")
+	suite.Equal(export.Data, w.Body.Bytes())
+}
+
+// Test Export - A release is exported from its snapshot
+func (suite *SchemaHandlerTestSuite) TestExport_Release() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	export := &services.SchemaExport{Filename: "schema.sql", ContentType: "application/sql", Data: []byte("CREATE TABLE users ();\n")}
+
+	suite.mockSchemaService.On("ExportRelease", projectID, "v1.0", services.ExportFormatSQL, userID).Return(export, nil)
+
+	req := suite.makeRequest(projectID, userID, nil)
+	req.URL.RawQuery = "release=v1.0"
+	w := httptest.NewRecorder()
+	suite.handler.Export()(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal(export.Data, w.Body.Bytes())
+	suite.mockSchemaService.AssertNotCalled(suite.T(), "ExportSchema", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Export - Unknown releases are not found
+func (suite *SchemaHandlerTestSuite) TestExport_ReleaseNotFound() {
+	projectID := uuid.New()
+	userID := uuid.New()
+
+	suite.mockSchemaService.On("ExportRelease", projectID, "v9", services.ExportFormatJSON, userID).Return(nil, services.ErrReleaseNotFound)
+
+	req := suite.makeRequest(projectID, userID, nil)
+	req.URL.RawQuery = "format=json&release=v9"
+	w := httptest.NewRecorder()
+	suite.handler.Export()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusNotFound, "Release not found")
+}
+
+// Test Export - SQL needs a dialect for the project's database type
+func (suite *SchemaHandlerTestSuite) TestExport_UnsupportedDialect() {
+	projectID := uuid.New()
+	userID := uuid.New()
+
+	suite.mockSchemaService.On("ExportSchema", projectID, services.ExportFormatSQL, userID).Return(nil, fmt.Errorf("%w %q", ddl.ErrUnsupportedDialect, "oracle"))
+
+	w := httptest.NewRecorder()
+	suite.handler.Export()(w, suite.makeRequest(projectID, userID, nil))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Projects of this database type cannot be exported as SQL")
+}
+
+// Test PlanApply - The plan is returned with its checksum
+func (suite *SchemaHandlerTestSuite) TestPlanApply_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	body := dto.PlanApplyRequest{DSN: "postgres://app@db.example.com/app"}
+	plan := &services.ApplyPlan{
+		Migration: &ddl.Migration{Statements: []ddl.Statement{{SQL: `DROP TABLE "audit";`, Destructive: true}}},
+		Checksum:  "abc",
+	}
+
+	suite.mockSchemaService.On("PlanApply", projectID, &body, userID).Return(plan, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.PlanApply()(w, suite.makeRequest(projectID, userID, body))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Plan computed successfully")
+	data := response.Data.(map[string]any)
+	suite.Equal(true, data["destructive"])
+	suite.Equal("abc", data["checksum"])
+	suite.Equal([]any{}, data["unsupported"])
+	suite.Equal(`DROP TABLE "audit";`, data["statements"].([]any)[0].(map[string]any)["sql"])
+}
+
+// Test PlanApply - Why the target database failed is logged, not returned
+func (suite *SchemaHandlerTestSuite) TestPlanApply_TargetDatabaseFailed() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	body := dto.PlanApplyRequest{DSN: "postgres://app@db.example.com:6379/app"}
+
+	suite.mockSchemaService.On("PlanApply", projectID, &body, userID).
+		Return(nil, fmt.Errorf("%w: connecting: dial tcp 93.184.216.34:6379: connection reset by peer", services.ErrTargetDatabase))
+
+	w := httptest.NewRecorder()
+	suite.handler.PlanApply()(w, suite.makeRequest(projectID, userID, body))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadGateway, "Could not connect to or read the target database")
+	suite.NotContains(w.Body.String(), "connection reset")
+}
+
+// Test ExecuteApply - A failed statement fails the apply, with the log
+func (suite *SchemaHandlerTestSuite) TestExecuteApply_Failed() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	body := dto.ExecuteApplyRequest{DSN: "postgres://app@db.example.com/app", Checksum: strings.Repeat("a", 64)}
+	applyLog := &services.ApplyLog{
+		Plan:        &services.ApplyPlan{Migration: &ddl.Migration{}, Checksum: "abc"},
+		Transaction: apply.ModeSingle,
+		Results: []apply.Result{
+			{SQL: "a", Status: apply.StatusRolledBack},
+			{SQL: "b", Status: apply.StatusFailed, Error: "boom"},
+			{SQL: "c", Status: apply.StatusSkipped},
+		},
+	}
+
+	suite.mockSchemaService.On("ExecuteApply", projectID, &body, userID).Return(applyLog, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.ExecuteApply()(w, suite.makeRequest(projectID, userID, body))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Plan failed")
+	data := response.Data.(map[string]any)
+	suite.Equal("failed", data["status"])
+	suite.Equal("boom", data["statements"].([]any)[1].(map[string]any)["error"])
+}
+
+// Test ExecuteApply - Destructive plans need to be allowed
+func (suite *SchemaHandlerTestSuite) TestExecuteApply_Destructive() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	body := dto.ExecuteApplyRequest{DSN: "postgres://app@db.example.com/app", Checksum: strings.Repeat("a", 64)}
+
+	suite.mockSchemaService.On("ExecuteApply", projectID, &body, userID).Return(nil, services.ErrDestructiveChanges)
+
+	w := httptest.NewRecorder()
+	suite.handler.ExecuteApply()(w, suite.makeRequest(projectID, userID, body))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "The plan drops or converts data; allow destructive changes to apply it")
+}
+
+// Test ExecuteApply - The checksum of the reviewed plan is required
+func (suite *SchemaHandlerTestSuite) TestExecuteApply_NoChecksum() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	body := dto.ExecuteApplyRequest{DSN: "postgres://app@db.example.com/app"}
+
+	w := httptest.NewRecorder()
+	suite.handler.ExecuteApply()(w, suite.makeRequest(projectID, userID, body))
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockSchemaService.AssertNotCalled(suite.T(), "ExecuteApply", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test PlanApply - Fields the database cannot be given are named
+func (suite *SchemaHandlerTestSuite) TestPlanApply_InvalidField() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	body := dto.PlanApplyRequest{DSN: "postgres://app@db.example.com/app"}
+
+	suite.mockSchemaService.On("PlanApply", projectID, &body, userID).Return(nil, fmt.Errorf("users.id: %w %q", ddl.ErrInvalidDataType, "UUID;"))
+
+	w := httptest.NewRecorder()
+	suite.handler.PlanApply()(w, suite.makeRequest(projectID, userID, body))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusUnprocessableEntity, `The model cannot be applied until this field is fixed: users.id: invalid data type "UUID;"`)
+}
+
+// Test Rename - The rename is returned with the project sequence
+func (suite *SchemaHandlerTestSuite) TestRename_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	fieldID := uuid.New()
+	body := dto.RenameRequest{TableID: uuid.New(), FieldID: &fieldID, Name: "email"}
+	rename := &models.SchemaRename{ID: uuid.New(), TableID: body.TableID, FieldID: &fieldID, TableName: "users", OldName: "mail", NewName: "email", Sequence: 4}
+
+	suite.mockSchemaService.On("Rename", projectID, &body, userID).Return(rename, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Rename()(w, suite.makeRequest(projectID, userID, body))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "Renamed successfully")
+	suite.Equal("4", w.Header().Get(responses.ProjectSequenceHeader))
+	data := response.Data.(map[string]any)
+	suite.Equal("mail", data["old_name"])
+	suite.Equal(fieldID.String(), data["field_id"])
+}
+
+// Test Rename - Names taken conflict
+func (suite *SchemaHandlerTestSuite) TestRename_NameTaken() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	body := dto.RenameRequest{TableID: uuid.New(), Name: "users"}
+
+	suite.mockSchemaService.On("Rename", projectID, &body, userID).Return(nil, services.ErrNameTaken)
+
+	w := httptest.NewRecorder()
+	suite.handler.Rename()(w, suite.makeRequest(projectID, userID, body))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "The name is already taken")
+}
+
+// Test Lint - Findings are listed with their rule
+func (suite *SchemaHandlerTestSuite) TestLint_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	finding := lint.Finding{Rule: lint.RuleRepeatingGroup, Table: "contacts", Fields: []string{"email1", "email2"}, Message: "m", Explanation: "e"}
+
+	suite.mockSchemaService.On("LintSchema", projectID, userID).Return([]lint.Finding{finding}, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.Lint()(w, suite.makeRequest(projectID, userID, nil))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Schema linted successfully")
+	suite.Equal([]any{map[string]any{
+		"rule": "1nf-repeating-group", "table": "contacts", "fields": []any{"email1", "email2"}, "message": "m", "explanation": "e",
+	}}, response.Data)
+}
+
+// Test Lint - Missing project
+func (suite *SchemaHandlerTestSuite) TestLint_NotFound() {
+	projectID := uuid.New()
+	userID := uuid.New()
+
+	suite.mockSchemaService.On("LintSchema", projectID, userID).Return(nil, services.ErrProjectNotFound)
+
+	w := httptest.NewRecorder()
+	suite.handler.Lint()(w, suite.makeRequest(projectID, userID, nil))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusNotFound, "Project not found")
+}
+
+// Test SuggestRelationships - Suggestions have names and IDs
+func (suite *SchemaHandlerTestSuite) TestSuggestRelationships_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suggestion := services.RelationshipSuggestion{
+		Suggestion: suggest.Suggestion{
+			Relationship: schemadiff.Relationship{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "user_id", RelationType: "one_to_many"},
+			Reason:       "user_id is named after users and has the type of users.id",
+		},
+		SourceTableID: uuid.New(), SourceFieldID: uuid.New(), TargetTableID: uuid.New(), TargetFieldID: uuid.New(),
+	}
+
+	suite.mockSchemaService.On("SuggestRelationships", projectID, userID).Return([]services.RelationshipSuggestion{suggestion}, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.SuggestRelationships()(w, suite.makeRequest(projectID, userID, nil))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Relationships suggested successfully")
+	data := response.Data.([]any)[0].(map[string]any)
+	suite.Equal("posts", data["target_table"])
+	suite.Equal(suggestion.TargetFieldID.String(), data["target_field_id"])
+	suite.Equal(suggestion.Reason, data["reason"])
+}
+
+// Test SuggestRelationships - Forbidden
+func (suite *SchemaHandlerTestSuite) TestSuggestRelationships_Forbidden() {
+	projectID := uuid.New()
+	userID := uuid.New()
+
+	suite.mockSchemaService.On("SuggestRelationships", projectID, userID).Return(nil, services.ErrForbidden)
+
+	w := httptest.NewRecorder()
+	suite.handler.SuggestRelationships()(w, suite.makeRequest(projectID, userID, nil))
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "You don't have access to this project")
+}
+
+// Test AcceptRelationshipSuggestions - The created relationships are returned
+// with the project sequence
+func (suite *SchemaHandlerTestSuite) TestAcceptRelationshipSuggestions_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	body := dto.AcceptRelationshipSuggestionsRequest{Relationships: []dto.SchemaRelationshipRequest{
+		{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "user_id"},
+	}}
+	relationship := &models.Relationship{ID: uuid.New(), ProjectID: projectID, RelationType: "one_to_many"}
+
+	suite.mockSchemaService.On("AcceptRelationshipSuggestions", projectID, body.Relationships, userID).
+		Return(&services.Schema{Relationships: []*models.Relationship{relationship}, Sequence: 7}, nil)
+
+	w := httptest.NewRecorder()
+	suite.handler.AcceptRelationshipSuggestions()(w, suite.makeRequest(projectID, userID, body))
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "Relationships created successfully")
+	suite.Equal("7", w.Header().Get(responses.ProjectSequenceHeader))
+	suite.Equal(relationship.ID.String(), response.Data.([]any)[0].(map[string]any)["relationship_id"])
+}
+
+// Test AcceptRelationshipSuggestions - At least one relationship is needed
+func (suite *SchemaHandlerTestSuite) TestAcceptRelationshipSuggestions_Empty() {
+	w := httptest.NewRecorder()
+	suite.handler.AcceptRelationshipSuggestions()(w, suite.makeRequest(uuid.New(), uuid.New(), dto.AcceptRelationshipSuggestionsRequest{}))
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockSchemaService.AssertNotCalled(suite.T(), "AcceptRelationshipSuggestions", mock.Anything, mock.Anything, mock.Anything)
+}
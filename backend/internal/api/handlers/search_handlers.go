@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type SearchHandler struct {
+	searchService services.SearchServiceInterface
+}
+
+func NewSearchHandler(searchService services.SearchServiceInterface) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search finds projects, tables and fields by name across the user's projects, best
+// matches first. Project-restricted API tokens only search their own project.
+func (h *SearchHandler) Search() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		query := r.URL.Query()
+		text := query.Get("q")
+		if strings.TrimSpace(text) == "" {
+			responses.RespondWithError(w, http.StatusBadRequest, "Search query is required")
+			return
+		}
+
+		projectID, ok := utils.ParseUUIDQuery(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		if tokenProjectID, restricted := middleware.GetAPITokenProjectFromContext(r.Context()); restricted {
+			if projectID != nil && *projectID != tokenProjectID {
+				responses.RespondWithError(w, http.StatusForbidden, "API token is not valid for this project")
+				return
+			}
+			projectID = &tokenProjectID
+		}
+
+		var types []string
+		if value := query.Get("type"); value != "" {
+			types = strings.Split(value, ",")
+		}
+
+		limit := 0
+		if value := query.Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 {
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid limit")
+				return
+			}
+			limit = parsed
+		}
+
+		hits, err := h.searchService.Search(userID, text, projectID, types, limit)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidInput):
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid search query")
+			case errors.Is(err, services.ErrProjectNotFound):
+				responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+			default:
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to search")
+			}
+			return
+		}
+
+		results := make([]dto.SearchResultResponse, len(hits))
+		for i, hit := range hits {
+			results[i] = searchResultResponse(hit)
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Search results retrieved successfully", results)
+	}
+}
+
+func searchResultResponse(hit *repository.SearchHit) dto.SearchResultResponse {
+	return dto.SearchResultResponse{
+		Type:        hit.Type,
+		ID:          hit.ID,
+		ProjectID:   hit.ProjectID,
+		ProjectName: hit.ProjectName,
+		TableID:     hit.TableID,
+		TableName:   hit.TableName,
+		Name:        hit.Name,
+		Detail:      hit.Detail,
+		Rank:        hit.Rank,
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockSearchService
+	handler     *SearchHandler
+	userID      uuid.UUID
+}
+
+func (suite *SearchHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockSearchService)
+	suite.handler = NewSearchHandler(suite.mockService)
+	suite.userID = uuid.New()
+}
+
+func TestSearchHandlerSuite(t *testing.T) {
+	suite.Run(t, new(SearchHandlerTestSuite))
+}
+
+// Test Search - Success with typed results
+func (suite *SearchHandlerTestSuite) TestSearch_Success() {
+	projectID := uuid.New()
+	tableID := uuid.New()
+	tableName := "orders"
+	hits := []*repository.SearchHit{
+		{Type: repository.SearchTypeField, ID: uuid.New(), ProjectID: projectID, ProjectName: "Shop", TableID: &tableID, TableName: &tableName,
+			Name: "customer_id", Detail: "uuid", Rank: 0.6},
+	}
+
+	suite.mockService.On("Search", suite.userID, "cust", (*uuid.UUID)(nil), []string{"table", "field"}, 5).Return(hits, nil)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/search?q=cust&type=table,field&limit=5", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.Search()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Search results retrieved successfully")
+	results, ok := response.Data.([]any)
+	suite.Require().True(ok)
+	suite.Require().Len(results, 1)
+	result := results[0].(map[string]any)
+	assert.Equal(suite.T(), "field", result["type"])
+	assert.Equal(suite.T(), "orders", result["table_name"])
+	assert.Equal(suite.T(), "uuid", result["detail"])
+}
+
+// Test Search - The query is required
+func (suite *SearchHandlerTestSuite) TestSearch_MissingQuery() {
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/search?q=%20", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.Search()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Search query is required")
+	suite.mockService.AssertNotCalled(suite.T(), "Search")
+}
+
+// Test Search - Project-restricted tokens only search their project
+func (suite *SearchHandlerTestSuite) TestSearch_ProjectToken() {
+	tokenProjectID := uuid.New()
+
+	suite.mockService.On("Search", suite.userID, "orders", &tokenProjectID, []string(nil), 0).Return([]*repository.SearchHit{}, nil)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/search?q=orders", nil), suite.userID)
+	req = req.WithContext(context.WithValue(req.Context(), "apiTokenProject", tokenProjectID))
+	w := httptest.NewRecorder()
+
+	suite.handler.Search()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Search results retrieved successfully")
+
+	req = testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/search?q=orders&project_id="+uuid.NewString(), nil), suite.userID)
+	req = req.WithContext(context.WithValue(req.Context(), "apiTokenProject", tokenProjectID))
+	w = httptest.NewRecorder()
+
+	suite.handler.Search()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "API token is not valid for this project")
+}
+
+// Test Search - Forbidden project
+func (suite *SearchHandlerTestSuite) TestSearch_Forbidden() {
+	projectID := uuid.New()
+
+	suite.mockService.On("Search", suite.userID, "orders", &projectID, []string(nil), 0).Return(nil, services.ErrForbidden)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/search?q=orders&project_id="+projectID.String(), nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.Search()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "You don't have access to this project")
+}
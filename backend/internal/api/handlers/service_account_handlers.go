@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+)
+
+type ServiceAccountHandler struct {
+	serviceAccountService services.ServiceAccountServiceInterface
+}
+
+func NewServiceAccountHandler(serviceAccountService services.ServiceAccountServiceInterface) *ServiceAccountHandler {
+	return &ServiceAccountHandler{
+		serviceAccountService: serviceAccountService,
+	}
+}
+
+// Create adds a service account to the project
+func (h *ServiceAccountHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, userID, ok := h.projectAndUser(w, r)
+		if !ok {
+			return
+		}
+
+		var req dto.CreateServiceAccountRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		account, err := h.serviceAccountService.CreateServiceAccount(projectID, req.Name, req.Scopes, userID)
+		if err != nil {
+			h.respondWithError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusCreated, "Service account created successfully", serviceAccountResponse(account))
+	}
+}
+
+// GetByProjectID lists the project's service accounts
+func (h *ServiceAccountHandler) GetByProjectID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, userID, ok := h.projectAndUser(w, r)
+		if !ok {
+			return
+		}
+
+		accounts, err := h.serviceAccountService.GetServiceAccountsByProjectID(projectID, userID)
+		if err != nil {
+			h.respondWithError(w, err)
+			return
+		}
+
+		accountResponses := make([]dto.ServiceAccountResponse, len(accounts))
+		for i, account := range accounts {
+			accountResponses[i] = serviceAccountResponse(account)
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Service accounts retrieved successfully", accountResponses)
+	}
+}
+
+// Delete removes a service account and all of its tokens
+func (h *ServiceAccountHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, userID, ok := h.projectAndUser(w, r)
+		if !ok {
+			return
+		}
+
+		accountID, ok := utils.ParseUUIDParamWithError(w, r, "service_account_id", "Invalid service account ID format")
+		if !ok {
+			return
+		}
+
+		if err := h.serviceAccountService.DeleteServiceAccount(projectID, accountID, userID); err != nil {
+			h.respondWithError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Service account deleted successfully", nil)
+	}
+}
+
+// CreateToken mints a token for a service account and returns the plaintext once
+func (h *ServiceAccountHandler) CreateToken() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, userID, ok := h.projectAndUser(w, r)
+		if !ok {
+			return
+		}
+
+		accountID, ok := utils.ParseUUIDParamWithError(w, r, "service_account_id", "Invalid service account ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.CreateAPITokenRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		token, plaintext, err := h.serviceAccountService.CreateToken(projectID, accountID, req.Name, req.Scopes, req.ExpiresAt, userID)
+		if err != nil {
+			h.respondWithError(w, err)
+			return
+		}
+
+		response := dto.CreateAPITokenResponse{
+			APITokenResponse: apiTokenResponse(token),
+			Token:            plaintext,
+		}
+
+		responses.RespondWithSuccess(w, http.StatusCreated, "API token created successfully", response)
+	}
+}
+
+// GetTokens lists a service account's tokens
+func (h *ServiceAccountHandler) GetTokens() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, userID, ok := h.projectAndUser(w, r)
+		if !ok {
+			return
+		}
+
+		accountID, ok := utils.ParseUUIDParamWithError(w, r, "service_account_id", "Invalid service account ID format")
+		if !ok {
+			return
+		}
+
+		tokens, err := h.serviceAccountService.GetTokens(projectID, accountID, userID)
+		if err != nil {
+			h.respondWithError(w, err)
+			return
+		}
+
+		tokenResponses := make([]dto.APITokenResponse, len(tokens))
+		for i, token := range tokens {
+			tokenResponses[i] = apiTokenResponse(token)
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "API tokens retrieved successfully", tokenResponses)
+	}
+}
+
+// RevokeToken revokes one of a service account's tokens
+func (h *ServiceAccountHandler) RevokeToken() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, userID, ok := h.projectAndUser(w, r)
+		if !ok {
+			return
+		}
+
+		accountID, ok := utils.ParseUUIDParamWithError(w, r, "service_account_id", "Invalid service account ID format")
+		if !ok {
+			return
+		}
+
+		tokenID, ok := utils.ParseUUIDParamWithError(w, r, "token_id", "Invalid token ID format")
+		if !ok {
+			return
+		}
+
+		if err := h.serviceAccountService.RevokeToken(projectID, accountID, tokenID, userID); err != nil {
+			h.respondWithError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "API token revoked successfully", nil)
+	}
+}
+
+func (h *ServiceAccountHandler) projectAndUser(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return projectID, userID, true
+}
+
+func (h *ServiceAccountHandler) respondWithError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrProjectNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, services.ErrServiceAccountNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Service account not found")
+	case errors.Is(err, services.ErrAPITokenNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "API token not found")
+	case errors.Is(err, services.ErrForbidden):
+		responses.RespondWithError(w, http.StatusForbidden, "You don't have permission to manage service accounts of this project")
+	case errors.Is(err, services.ErrInvalidScope):
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid token scope")
+	case errors.Is(err, services.ErrInvalidInput):
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func serviceAccountResponse(account *models.ServiceAccount) dto.ServiceAccountResponse {
+	return dto.ServiceAccountResponse{
+		ID:          account.ID,
+		ProjectID:   account.ProjectID,
+		UserID:      account.UserID,
+		Name:        account.Name,
+		Scopes:      strings.Split(account.Scopes, ","),
+		CreatedByID: account.CreatedByID,
+		CreatedAt:   account.CreatedAt,
+	}
+}
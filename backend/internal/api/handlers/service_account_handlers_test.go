@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServiceAccountHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockServiceAccountService
+	handler     *ServiceAccountHandler
+	projectID   uuid.UUID
+	userID      uuid.UUID
+}
+
+func (suite *ServiceAccountHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockServiceAccountService)
+	suite.handler = NewServiceAccountHandler(suite.mockService)
+	suite.projectID = uuid.New()
+	suite.userID = uuid.New()
+}
+
+func TestServiceAccountHandlerSuite(t *testing.T) {
+	suite.Run(t, new(ServiceAccountHandlerTestSuite))
+}
+
+func (suite *ServiceAccountHandlerTestSuite) withParams(req *http.Request, params map[string]string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", suite.projectID.String())
+	for key, value := range params {
+		rctx.URLParams.Add(key, value)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	return testutil.WithUserContext(req, suite.userID)
+}
+
+// Test Create - Success
+func (suite *ServiceAccountHandlerTestSuite) TestCreate_Success() {
+	requestBody := dto.CreateServiceAccountRequest{Name: "Export pipeline", Scopes: []string{services.ScopeReadProjects}}
+	account := &models.ServiceAccount{ID: uuid.New(), ProjectID: suite.projectID, UserID: uuid.New(), Name: "Export pipeline", Scopes: services.ScopeReadProjects}
+
+	suite.mockService.On("CreateServiceAccount", suite.projectID, "Export pipeline", []string{services.ScopeReadProjects}, suite.userID).Return(account, nil)
+
+	req := suite.withParams(testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/", requestBody), nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Create()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "Service account created successfully")
+	data, ok := response.Data.(map[string]any)
+	suite.Require().True(ok)
+	assert.Equal(suite.T(), []any{services.ScopeReadProjects}, data["scopes"])
+}
+
+// Test Create - Forbidden for users who cannot modify the project
+func (suite *ServiceAccountHandlerTestSuite) TestCreate_Forbidden() {
+	requestBody := dto.CreateServiceAccountRequest{Name: "CI", Scopes: []string{services.ScopeReadProjects}}
+
+	suite.mockService.On("CreateServiceAccount", suite.projectID, "CI", []string{services.ScopeReadProjects}, suite.userID).Return(nil, services.ErrForbidden)
+
+	req := suite.withParams(testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/", requestBody), nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Create()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "You don't have permission to manage service accounts of this project")
+}
+
+// Test CreateToken - Scope beyond the account's is rejected
+func (suite *ServiceAccountHandlerTestSuite) TestCreateToken_InvalidScope() {
+	accountID := uuid.New()
+	requestBody := dto.CreateAPITokenRequest{Name: "deploy", Scopes: []string{services.ScopeWriteSchema}}
+
+	suite.mockService.On("CreateToken", suite.projectID, accountID, "deploy", []string{services.ScopeWriteSchema}, (*time.Time)(nil), suite.userID).
+		Return(nil, "", services.ErrInvalidScope)
+
+	req := suite.withParams(testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/", requestBody), map[string]string{"service_account_id": accountID.String()})
+	w := httptest.NewRecorder()
+
+	suite.handler.CreateToken()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid token scope")
+}
+
+// Test Delete - Unknown service account
+func (suite *ServiceAccountHandlerTestSuite) TestDelete_NotFound() {
+	accountID := uuid.New()
+	suite.mockService.On("DeleteServiceAccount", suite.projectID, accountID, suite.userID).Return(services.ErrServiceAccountNotFound)
+
+	req := suite.withParams(httptest.NewRequest(http.MethodDelete, "/", nil), map[string]string{"service_account_id": accountID.String()})
+	w := httptest.NewRecorder()
+
+	suite.handler.Delete()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusNotFound, "Service account not found")
+}
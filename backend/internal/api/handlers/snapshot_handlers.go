@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type SnapshotHandler struct {
+	snapshotService services.SnapshotServiceInterface
+}
+
+func NewSnapshotHandler(snapshotService services.SnapshotServiceInterface) *SnapshotHandler {
+	return &SnapshotHandler{
+		snapshotService: snapshotService,
+	}
+}
+
+// GetByProjectID lists the snapshots of a project, newest first
+func (h *SnapshotHandler) GetByProjectID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		snapshots, err := h.snapshotService.GetSnapshots(projectID, userID)
+		if err != nil {
+			respondWithSnapshotError(w, err)
+			return
+		}
+
+		snapshotResponses := make([]dto.SnapshotResponse, len(snapshots))
+		for i, snapshot := range snapshots {
+			snapshotResponses[i] = dto.SnapshotResponse{
+				ID:              snapshot.ID,
+				ProjectVersion:  snapshot.ProjectVersion,
+				ProjectSequence: snapshot.ProjectSequence,
+				SizeBytes:       snapshot.SizeBytes,
+				CreatedAt:       snapshot.CreatedAt,
+			}
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Snapshots retrieved successfully", snapshotResponses)
+	}
+}
+
+// GetRetention returns how many snapshots a project keeps
+func (h *SnapshotHandler) GetRetention() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		retention, err := h.snapshotService.GetRetention(projectID, userID)
+		if err != nil {
+			respondWithSnapshotError(w, err)
+			return
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Snapshot retention retrieved successfully", snapshotRetentionResponse(retention))
+	}
+}
+
+// SetRetention overrides how many snapshots a project keeps; owner only
+func (h *SnapshotHandler) SetRetention() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.SetSnapshotRetentionRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		retention, err := h.snapshotService.SetRetention(projectID, userID, req.KeepDaily, req.KeepMonthly)
+		if err != nil {
+			respondWithSnapshotError(w, err)
+			return
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Snapshot retention updated successfully", snapshotRetentionResponse(retention))
+	}
+}
+
+func respondWithSnapshotError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrProjectNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, services.ErrForbidden):
+		responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+	case errors.Is(err, services.ErrInvalidInput):
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid retention")
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func snapshotRetentionResponse(retention *services.SnapshotRetention) dto.SnapshotRetentionResponse {
+	return dto.SnapshotRetentionResponse{
+		KeepDaily:   retention.KeepDaily,
+		KeepMonthly: retention.KeepMonthly,
+		Overridden:  retention.Overridden,
+	}
+}
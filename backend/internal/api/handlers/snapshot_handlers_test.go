@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SnapshotHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockSnapshotService
+	handler     *SnapshotHandler
+	userID      uuid.UUID
+	projectID   uuid.UUID
+}
+
+func (suite *SnapshotHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockSnapshotService)
+	suite.handler = NewSnapshotHandler(suite.mockService)
+	suite.userID = uuid.New()
+	suite.projectID = uuid.New()
+}
+
+func TestSnapshotHandlerSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotHandlerTestSuite))
+}
+
+func (suite *SnapshotHandlerTestSuite) withProject(req *http.Request) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", suite.projectID.String())
+	return testutil.WithUserContext(req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)), suite.userID)
+}
+
+// Test GetByProjectID - Snapshots are listed without their data
+func (suite *SnapshotHandlerTestSuite) TestGetByProjectID_Success() {
+	createdAt := time.Date(2026, 3, 15, 2, 0, 0, 0, time.UTC)
+	snapshot := &models.ProjectSnapshot{ID: uuid.New(), ProjectID: suite.projectID, ProjectVersion: 4, ProjectSequence: 9, SizeBytes: 512, CreatedAt: createdAt}
+	suite.mockService.On("GetSnapshots", suite.projectID, suite.userID).Return([]*models.ProjectSnapshot{snapshot}, nil)
+
+	req := suite.withProject(httptest.NewRequest(http.MethodGet, "/projects/"+suite.projectID.String()+"/snapshots", nil))
+	w := httptest.NewRecorder()
+
+	suite.handler.GetByProjectID()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Snapshots retrieved successfully")
+	suite.Equal([]any{map[string]any{
+		"id":               snapshot.ID.String(),
+		"project_version":  float64(4),
+		"project_sequence": float64(9),
+		"size_bytes":       float64(512),
+		"created_at":       "2026-03-15T02:00:00Z",
+	}}, response.Data)
+}
+
+// Test GetByProjectID - No access to the project
+func (suite *SnapshotHandlerTestSuite) TestGetByProjectID_Forbidden() {
+	suite.mockService.On("GetSnapshots", suite.projectID, suite.userID).Return(nil, services.ErrForbidden)
+
+	req := suite.withProject(httptest.NewRequest(http.MethodGet, "/projects/"+suite.projectID.String()+"/snapshots", nil))
+	w := httptest.NewRecorder()
+
+	suite.handler.GetByProjectID()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "You don't have access to this project")
+}
+
+// Test SetRetention - Null counts go back to the defaults
+func (suite *SnapshotHandlerTestSuite) TestSetRetention_Success() {
+	suite.mockService.On("SetRetention", suite.projectID, suite.userID,
+		mock.MatchedBy(func(keepDaily *int) bool { return keepDaily != nil && *keepDaily == 14 }), (*int)(nil)).
+		Return(&services.SnapshotRetention{KeepDaily: 14, KeepMonthly: 12, Overridden: true}, nil)
+
+	req := suite.withProject(testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/projects/"+suite.projectID.String()+"/snapshot-retention",
+		map[string]any{"keep_daily": 14, "keep_monthly": nil}))
+	w := httptest.NewRecorder()
+
+	suite.handler.SetRetention()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Snapshot retention updated successfully")
+	suite.Equal(map[string]any{"keep_daily": float64(14), "keep_monthly": float64(12), "overridden": true}, response.Data)
+}
+
+// Test SetRetention - Only the owner may change it
+func (suite *SnapshotHandlerTestSuite) TestSetRetention_NotOwner() {
+	suite.mockService.On("SetRetention", suite.projectID, suite.userID, (*int)(nil), (*int)(nil)).Return(nil, services.ErrForbidden)
+
+	req := suite.withProject(testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/projects/"+suite.projectID.String()+"/snapshot-retention", map[string]any{}))
+	w := httptest.NewRecorder()
+
+	suite.handler.SetRetention()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "You don't have access to this project")
+}
+
+// Test SetRetention - Negative counts are rejected before the service
+func (suite *SnapshotHandlerTestSuite) TestSetRetention_Invalid() {
+	req := suite.withProject(testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/projects/"+suite.projectID.String()+"/snapshot-retention",
+		map[string]any{"keep_daily": -1}))
+	w := httptest.NewRecorder()
+
+	suite.handler.SetRetention()(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "SetRetention", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
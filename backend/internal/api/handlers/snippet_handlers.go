@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type SnippetHandler struct {
+	snippetService services.SnippetServiceInterface
+}
+
+func NewSnippetHandler(snippetService services.SnippetServiceInterface) *SnippetHandler {
+	return &SnippetHandler{
+		snippetService: snippetService,
+	}
+}
+
+// Create handles saving a snippet with a project
+func (h *SnippetHandler) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+
+		var req dto.CreateSnippetRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		snippet, err := h.snippetService.CreateSnippet(projectID, &req, userID)
+		if err != nil {
+			respondWithSnippetError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusCreated, "Snippet created successfully", newSnippetResponse(snippet))
+	}
+}
+
+// GetByProjectID handles retrieving the snippets of a project by name
+func (h *SnippetHandler) GetByProjectID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		snippets, err := h.snippetService.GetSnippets(projectID, userID)
+		if err != nil {
+			respondWithSnippetError(w, err)
+			return
+		}
+
+		snippetResponses := make([]dto.SnippetResponse, len(snippets))
+		for i, snippet := range snippets {
+			snippetResponses[i] = newSnippetResponse(snippet)
+		}
+		responses.RespondWithSuccess(w, http.StatusOK, "Snippets retrieved successfully", snippetResponses)
+	}
+}
+
+// GetByID handles retrieving a specific snippet
+func (h *SnippetHandler) GetByID() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		snippetID, ok := utils.ParseUUIDParam(w, r, "snippet_id")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		snippet, err := h.snippetService.GetSnippet(projectID, snippetID, userID)
+		if err != nil {
+			respondWithSnippetError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Snippet retrieved successfully", newSnippetResponse(snippet))
+	}
+}
+
+// Update handles changing the name, description or SQL of a snippet
+func (h *SnippetHandler) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		snippetID, ok := utils.ParseUUIDParam(w, r, "snippet_id")
+		if !ok {
+			return
+		}
+
+		var req dto.UpdateSnippetRequest
+		if !utils.DecodeAndValidate(w, r, &req) {
+			return
+		}
+
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		snippet, err := h.snippetService.UpdateSnippet(projectID, snippetID, &req, userID)
+		if err != nil {
+			respondWithSnippetError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Snippet updated successfully", newSnippetResponse(snippet))
+	}
+}
+
+// Delete handles snippet deletion
+func (h *SnippetHandler) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		projectID, ok := utils.ParseUUIDParamWithError(w, r, "project_id", "Invalid project ID format")
+		if !ok {
+			return
+		}
+		snippetID, ok := utils.ParseUUIDParam(w, r, "snippet_id")
+		if !ok {
+			return
+		}
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.snippetService.DeleteSnippet(projectID, snippetID, userID); err != nil {
+			respondWithSnippetError(w, err)
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Snippet deleted successfully", nil)
+	}
+}
+
+func respondWithSnippetError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrProjectNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Project not found")
+	case errors.Is(err, services.ErrSnippetNotFound):
+		responses.RespondWithError(w, http.StatusNotFound, "Snippet not found")
+	case errors.Is(err, services.ErrNameTaken):
+		responses.RespondWithError(w, http.StatusConflict, "A snippet of the project already has this name")
+	case errors.Is(err, services.ErrInvalidInput):
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+	case errors.Is(err, services.ErrForbidden):
+		responses.RespondWithError(w, http.StatusForbidden, "You don't have access to this project")
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+	}
+}
+
+func newSnippetResponse(snippet *models.Snippet) dto.SnippetResponse {
+	return dto.SnippetResponse{
+		ID:          snippet.ID,
+		ProjectID:   snippet.ProjectID,
+		Name:        snippet.Name,
+		Description: snippet.Description,
+		SQL:         snippet.SQL,
+		CreatedBy:   snippet.CreatedBy,
+		CreatedAt:   snippet.CreatedAt,
+		UpdatedAt:   snippet.UpdatedAt,
+	}
+}
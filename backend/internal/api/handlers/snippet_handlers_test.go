@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SnippetHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockSnippetService
+	handler     *SnippetHandler
+	userID      uuid.UUID
+	projectID   uuid.UUID
+}
+
+func (suite *SnippetHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockSnippetService)
+	suite.handler = NewSnippetHandler(suite.mockService)
+	suite.userID = uuid.New()
+	suite.projectID = uuid.New()
+}
+
+func TestSnippetHandlerSuite(t *testing.T) {
+	suite.Run(t, new(SnippetHandlerTestSuite))
+}
+
+// withSnippet adds the project and snippet IDs to the route context, and the user
+func (suite *SnippetHandlerTestSuite) withSnippet(req *http.Request, snippetID uuid.UUID) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", suite.projectID.String())
+	rctx.URLParams.Add("snippet_id", snippetID.String())
+	return testutil.WithUserContext(req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)), suite.userID)
+}
+
+// Test Create - Success
+func (suite *SnippetHandlerTestSuite) TestCreate_Success() {
+	createReq := dto.CreateSnippetRequest{Name: "Seed", SQL: "INSERT INTO users VALUES (1);"}
+	snippet := &models.Snippet{ID: uuid.New(), ProjectID: suite.projectID, Name: "Seed", SQL: createReq.SQL, CreatedBy: suite.userID}
+	suite.mockService.On("CreateSnippet", suite.projectID, &createReq, suite.userID).Return(snippet, nil)
+
+	req := suite.withSnippet(testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+suite.projectID.String()+"/snippets", createReq), uuid.Nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Create()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusCreated, "Snippet created successfully")
+	data := response.Data.(map[string]any)
+	suite.Equal(snippet.ID.String(), data["snippet_id"])
+	suite.Equal(createReq.SQL, data["sql"])
+}
+
+// Test Create - SQL is required
+func (suite *SnippetHandlerTestSuite) TestCreate_MissingSQL() {
+	req := suite.withSnippet(testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+suite.projectID.String()+"/snippets",
+		map[string]any{"name": "Seed"}), uuid.Nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Create()(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "CreateSnippet", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Create - A taken name conflicts
+func (suite *SnippetHandlerTestSuite) TestCreate_NameTaken() {
+	createReq := dto.CreateSnippetRequest{Name: "Seed", SQL: "SELECT 1;"}
+	suite.mockService.On("CreateSnippet", suite.projectID, &createReq, suite.userID).Return(nil, services.ErrNameTaken)
+
+	req := suite.withSnippet(testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+suite.projectID.String()+"/snippets", createReq), uuid.Nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.Create()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "A snippet of the project already has this name")
+}
+
+// Test GetByProjectID - Success
+func (suite *SnippetHandlerTestSuite) TestGetByProjectID_Success() {
+	snippet := &models.Snippet{ID: uuid.New(), ProjectID: suite.projectID, Name: "Seed", SQL: "SELECT 1;"}
+	suite.mockService.On("GetSnippets", suite.projectID, suite.userID).Return([]*models.Snippet{snippet}, nil)
+
+	req := suite.withSnippet(httptest.NewRequest(http.MethodGet, "/projects/"+suite.projectID.String()+"/snippets", nil), uuid.Nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetByProjectID()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Snippets retrieved successfully")
+	suite.Len(response.Data, 1)
+}
+
+// Test Delete - A snippet of another project is not found
+func (suite *SnippetHandlerTestSuite) TestDelete_NotFound() {
+	snippetID := uuid.New()
+	suite.mockService.On("DeleteSnippet", suite.projectID, snippetID, suite.userID).Return(services.ErrSnippetNotFound)
+
+	req := suite.withSnippet(httptest.NewRequest(http.MethodDelete, "/projects/"+suite.projectID.String()+"/snippets/"+snippetID.String(), nil), snippetID)
+	w := httptest.NewRecorder()
+
+	suite.handler.Delete()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusNotFound, "Snippet not found")
+}
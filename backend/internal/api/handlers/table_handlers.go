@@ -8,6 +8,7 @@ import (
 	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
 	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
 	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
 	"github.com/google/uuid"
 )
@@ -51,7 +52,7 @@ func (h *TableHandler) Create() http.HandlerFunc {
 		}
 
 		// Create table through service
-		table, err := h.tableService.CreateTable(projectID, req.Name, req.PosX, req.PosY, userID)
+		table, err := h.tableService.CreateTable(projectID, &req, userID)
 		if err != nil {
 			switch {
 			case errors.Is(err, services.ErrProjectNotFound):
@@ -60,6 +61,10 @@ func (h *TableHandler) Create() http.HandlerFunc {
 				responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
 			case errors.Is(err, services.ErrForbidden):
 				responses.RespondWithError(w, http.StatusForbidden, "You don't have permission to create tables in this project")
+			case errors.Is(err, services.ErrQuotaExceeded):
+				respondWithQuotaExceeded(w, err)
+			case errors.Is(err, services.ErrNameTaken):
+				respondWithTaken(w, err, "A table of the project already has this name")
 			default:
 				responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
 			}
@@ -73,10 +78,15 @@ func (h *TableHandler) Create() http.HandlerFunc {
 			Name:      table.Name,
 			PosX:      table.PosX,
 			PosY:      table.PosY,
+			Color:     table.Color,
+			Icon:      table.Icon,
+			Metadata:  metadataResponse(table.Metadata),
 			CreatedAt: table.CreatedAt,
 			UpdatedAt: table.UpdatedAt,
+			Version:   table.Version,
 		}
 
+		responses.SetProjectSequence(w, table.Sequence)
 		responses.RespondWithSuccess(w, http.StatusCreated, "Table created successfully", tableResponse)
 	}
 }
@@ -109,15 +119,20 @@ func (h *TableHandler) GetByID() http.HandlerFunc {
 			Name:      table.Name,
 			PosX:      table.PosX,
 			PosY:      table.PosY,
+			Color:     table.Color,
+			Icon:      table.Icon,
+			Metadata:  metadataResponse(table.Metadata),
 			CreatedAt: table.CreatedAt,
 			UpdatedAt: table.UpdatedAt,
+			Version:   table.Version,
 		}
 
+		utils.SetVersionETag(w, table.Version)
 		responses.RespondWithSuccess(w, http.StatusOK, "Table retrieved successfully", tableResponse)
 	}
 }
 
-// GetByProjectID handles retrieving all tables for a project
+// GetByProjectID handles retrieving a page of a project's tables. Supports ?name= to filter by name.
 func (h *TableHandler) GetByProjectID() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Get project ID from URL
@@ -125,16 +140,21 @@ func (h *TableHandler) GetByProjectID() http.HandlerFunc {
 		if !ok {
 			return
 		}
+		page, ok := utils.ParsePageQuery(w, r)
+		if !ok {
+			return
+		}
 
 		// Get tables from service
-		tables, err := h.tableService.GetTablesByProjectID(projectID)
+		filter := repository.TableFilter{Name: r.URL.Query().Get("name")}
+		tables, next, err := h.tableService.ListTables(projectID, filter, page)
 		if err != nil {
-			responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+			utils.RespondWithPageError(w, err, "Internal server error")
 			return
 		}
 
 		// Convert to response format
-		var tableResponses []dto.TableResponse
+		tableResponses := make([]dto.TableResponse, 0, len(tables))
 		for _, table := range tables {
 			tableResponses = append(tableResponses, dto.TableResponse{
 				ID:        table.ID,
@@ -142,17 +162,31 @@ func (h *TableHandler) GetByProjectID() http.HandlerFunc {
 				Name:      table.Name,
 				PosX:      table.PosX,
 				PosY:      table.PosY,
+				Color:     table.Color,
+				Icon:      table.Icon,
+				Metadata:  metadataResponse(table.Metadata),
 				CreatedAt: table.CreatedAt,
 				UpdatedAt: table.UpdatedAt,
+				Version:   table.Version,
 			})
 		}
 
-		responses.RespondWithSuccess(w, http.StatusOK, "Tables retrieved successfully", tableResponses)
+		responses.RespondWithPage(w, "Tables retrieved successfully", tableResponses, dto.PageMeta{NextCursor: next})
 	}
 }
 
 // Update handles table updates
 func (h *TableHandler) Update() http.HandlerFunc {
+	return h.update(utils.DecodeAndValidate)
+}
+
+// Patch applies a JSON Merge Patch to a table
+func (h *TableHandler) Patch() http.HandlerFunc {
+	return h.update(utils.DecodeMergePatch)
+}
+
+// update reads the changes with decode, which responds itself when they are invalid
+func (h *TableHandler) update(decode func(http.ResponseWriter, *http.Request, any) bool) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Get table ID from URL
 		tableID, ok := utils.ParseUUIDParam(w, r, "table_id")
@@ -162,9 +196,18 @@ func (h *TableHandler) Update() http.HandlerFunc {
 
 		// Parse and validate request body
 		var req dto.UpdateTableRequest
-		if !utils.DecodeAndValidate(w, r, &req) {
+		if !decode(w, r, &req) {
+			return
+		}
+
+		// The If-Match header takes precedence over a version in the body
+		version, ok := utils.ParseIfMatch(w, r)
+		if !ok {
 			return
 		}
+		if version != nil {
+			req.Version = version
+		}
 
 		// Get current user ID from context for collaboration
 		userIDStr, ok := middleware.GetUserIDFromContext(r.Context())
@@ -181,12 +224,14 @@ func (h *TableHandler) Update() http.HandlerFunc {
 
 		// Update table through service
 		table, err := h.tableService.UpdateTable(tableID, &req, userID)
-		if err != nil {
+		if err != nil && !errors.Is(err, services.ErrVersionConflict) {
 			switch {
 			case errors.Is(err, services.ErrTableNotFound):
 				responses.RespondWithError(w, http.StatusNotFound, "Table not found")
 			case errors.Is(err, services.ErrInvalidInput):
 				responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+			case errors.Is(err, services.ErrNameTaken):
+				respondWithTaken(w, err, "A table of the project already has this name")
 			default:
 				responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
 			}
@@ -200,10 +245,21 @@ func (h *TableHandler) Update() http.HandlerFunc {
 			Name:      table.Name,
 			PosX:      table.PosX,
 			PosY:      table.PosY,
+			Color:     table.Color,
+			Icon:      table.Icon,
+			Metadata:  metadataResponse(table.Metadata),
 			CreatedAt: table.CreatedAt,
 			UpdatedAt: table.UpdatedAt,
+			Version:   table.Version,
+		}
+
+		utils.SetVersionETag(w, table.Version)
+		if err != nil {
+			responses.RespondWithErrorData(w, http.StatusConflict, "Table was modified by someone else", tableResponse)
+			return
 		}
 
+		responses.SetProjectSequence(w, table.Sequence)
 		responses.RespondWithSuccess(w, http.StatusOK, "Table updated successfully", tableResponse)
 	}
 }
@@ -241,6 +297,8 @@ func (h *TableHandler) UpdatePosition() http.HandlerFunc {
 			switch {
 			case errors.Is(err, services.ErrTableNotFound):
 				responses.RespondWithError(w, http.StatusNotFound, "Table not found")
+			case errors.Is(err, services.ErrForbidden):
+				responses.RespondWithError(w, http.StatusForbidden, "You don't have permission to move this table")
 			default:
 				responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
 			}
@@ -274,7 +332,8 @@ func (h *TableHandler) Delete() http.HandlerFunc {
 		}
 
 		// Delete table through service with authorization check
-		if err := h.tableService.DeleteTable(tableID, userID); err != nil {
+		sequence, err := h.tableService.DeleteTable(tableID, userID)
+		if err != nil {
 			switch {
 			case errors.Is(err, services.ErrTableNotFound):
 				responses.RespondWithError(w, http.StatusNotFound, "Table not found")
@@ -286,6 +345,7 @@ func (h *TableHandler) Delete() http.HandlerFunc {
 			return
 		}
 
+		responses.SetProjectSequence(w, sequence)
 		responses.RespondWithSuccess(w, http.StatusOK, "Table deleted successfully", nil)
 	}
 }
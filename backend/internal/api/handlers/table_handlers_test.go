@@ -7,8 +7,10 @@ import (
 	"testing"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
 	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
 	"github.com/Bug-Bugger/ezmodel/internal/testutil"
 	"github.com/go-chi/chi/v5"
@@ -40,7 +42,7 @@ func (suite *TableHandlerTestSuite) TestCreateTable_Success() {
 	requestBody := testutil.CreateValidTableRequest()
 	expectedTable := testutil.CreateTestTable(projectID)
 
-	suite.mockService.On("CreateTable", projectID, requestBody.Name, requestBody.PosX, requestBody.PosY, mock.AnythingOfType("uuid.UUID")).
+	suite.mockService.On("CreateTable", projectID, &requestBody, mock.AnythingOfType("uuid.UUID")).
 		Return(expectedTable, nil)
 
 	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+projectID.String()+"/tables", requestBody)
@@ -63,6 +65,28 @@ func (suite *TableHandlerTestSuite) TestCreateTable_Success() {
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+// Test Create Table - Quota exceeded
+func (suite *TableHandlerTestSuite) TestCreateTable_QuotaExceeded() {
+	projectID := uuid.New()
+	requestBody := testutil.CreateValidTableRequest()
+
+	suite.mockService.On("CreateTable", projectID, &requestBody, suite.userID).
+		Return(nil, &services.QuotaExceededError{Quota: services.QuotaTables, Limit: 50})
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/projects/"+projectID.String()+"/tables", requestBody)
+	req = testutil.WithUserContext(req, suite.userID)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	suite.handler.Create()(w, req)
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusPaymentRequired, "Quota exceeded")
+	suite.Equal(map[string]any{"quota": "tables", "limit": float64(50)}, response.Data)
+}
+
 // Test Create Table - Invalid Project ID
 func (suite *TableHandlerTestSuite) TestCreateTable_InvalidProjectID() {
 	requestBody := testutil.CreateValidTableRequest()
@@ -135,9 +159,11 @@ func (suite *TableHandlerTestSuite) TestGetTablesByProjectID_Success() {
 		testutil.CreateTestTable(projectID),
 	}
 
-	suite.mockService.On("GetTablesByProjectID", projectID).Return(tables, nil)
+	filter := repository.TableFilter{Name: "user"}
+	page := repository.PageQuery{Limit: 2, Sort: "name"}
+	suite.mockService.On("ListTables", projectID, filter, page).Return(tables, "next", nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/projects/"+projectID.String()+"/tables", nil)
+	req := httptest.NewRequest(http.MethodGet, "/projects/"+projectID.String()+"/tables?name=user&limit=2&sort=name", nil)
 	w := httptest.NewRecorder()
 
 	rctx := chi.NewRouteContext()
@@ -151,10 +177,29 @@ func (suite *TableHandlerTestSuite) TestGetTablesByProjectID_Success() {
 	tablesResponse, ok := response.Data.([]any)
 	suite.True(ok)
 	suite.Len(tablesResponse, 2)
+	suite.Equal("next", response.Meta.NextCursor)
 
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+// Test Get Tables By Project ID - Unknown sort field
+func (suite *TableHandlerTestSuite) TestGetTablesByProjectID_InvalidSort() {
+	projectID := uuid.New()
+	page := repository.PageQuery{Sort: "pos_x"}
+	suite.mockService.On("ListTables", projectID, repository.TableFilter{}, page).Return(nil, "", repository.ErrInvalidSort)
+
+	req := httptest.NewRequest(http.MethodGet, "/projects/"+projectID.String()+"/tables?sort=pos_x", nil)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("project_id", projectID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	suite.handler.GetByProjectID()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid sort")
+}
+
 // Test Update Table - Success
 func (suite *TableHandlerTestSuite) TestUpdateTable_Success() {
 	tableID := uuid.New()
@@ -188,6 +233,171 @@ func (suite *TableHandlerTestSuite) TestUpdateTable_Success() {
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+// Test Patch Table - Only the members sent are changed
+func (suite *TableHandlerTestSuite) TestPatchTable_Success() {
+	tableID := uuid.New()
+	posX := 120.5
+	expectedRequest := dto.UpdateTableRequest{PosX: &posX}
+
+	updatedTable := testutil.CreateTestTable(uuid.New())
+	updatedTable.ID = tableID
+	updatedTable.PosX = posX
+
+	suite.mockService.On("UpdateTable", tableID, &expectedRequest, suite.userID).Return(updatedTable, nil)
+
+	req := testutil.WithUserContext(testutil.MakeMergePatchRequest("/tables/"+tableID.String(), `{"pos_x": 120.5}`), suite.userID)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("table_id", tableID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	suite.handler.Patch()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Table updated successfully")
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Test Patch Table - Unknown members, null names and wrong types are reported per field
+func (suite *TableHandlerTestSuite) TestPatchTable_InvalidMembers() {
+	tableID := uuid.New()
+	req := testutil.MakeMergePatchRequest("/tables/"+tableID.String(), `{"name": null, "colour": "red", "pos_y": "top"}`)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("table_id", tableID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	suite.handler.Patch()(w, req)
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Validation failed")
+	errs := response.Errors.(map[string]any)
+	suite.Equal("Value cannot be null", errs["name"])
+	suite.Equal("Unknown field", errs["colour"])
+	suite.Equal("Invalid value", errs["pos_y"])
+	suite.mockService.AssertNotCalled(suite.T(), "UpdateTable", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Patch Table - Null clears the color and icon, unknown icons and colors are rejected
+func (suite *TableHandlerTestSuite) TestPatchTable_Appearance() {
+	tableID := uuid.New()
+	empty := ""
+	expectedRequest := dto.UpdateTableRequest{Color: &empty, Icon: &empty}
+
+	suite.mockService.On("UpdateTable", tableID, &expectedRequest, suite.userID).Return(testutil.CreateTestTable(uuid.New()), nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("table_id", tableID.String())
+
+	req := testutil.WithUserContext(testutil.MakeMergePatchRequest("/tables/"+tableID.String(), `{"color": null, "icon": null}`), suite.userID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	suite.handler.Patch()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Table updated successfully")
+	suite.mockService.AssertExpectations(suite.T())
+
+	req = testutil.WithUserContext(testutil.MakeMergePatchRequest("/tables/"+tableID.String(), `{"color": "red", "icon": "rocket"}`), suite.userID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w = httptest.NewRecorder()
+	suite.handler.Patch()(w, req)
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Validation failed")
+	errs := response.Errors.(map[string]any)
+	suite.Contains(errs, "color")
+	suite.Contains(errs, "icon")
+}
+
+// Test Patch Table - Metadata is passed on with its null members, and null clears it
+func (suite *TableHandlerTestSuite) TestPatchTable_Metadata() {
+	tableID := uuid.New()
+	patch := map[string]any{"data_owner": "crm", "pii": nil}
+	var cleared map[string]any
+
+	suite.mockService.On("UpdateTable", tableID, &dto.UpdateTableRequest{Metadata: &patch}, suite.userID).Return(testutil.CreateTestTable(uuid.New()), nil)
+	suite.mockService.On("UpdateTable", tableID, &dto.UpdateTableRequest{Metadata: &cleared}, suite.userID).Return(testutil.CreateTestTable(uuid.New()), nil)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("table_id", tableID.String())
+
+	for _, body := range []string{`{"metadata": {"data_owner": "crm", "pii": null}}`, `{"metadata": null}`} {
+		req := testutil.WithUserContext(testutil.MakeMergePatchRequest("/tables/"+tableID.String(), body), suite.userID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		suite.handler.Patch()(w, req)
+
+		response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Table updated successfully")
+		suite.Equal(map[string]any{}, response.Data.(map[string]any)["metadata"])
+	}
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Test Patch Table - Bodies that are not a merge patch are rejected
+func (suite *TableHandlerTestSuite) TestPatchTable_NotAMergePatch() {
+	tableID := uuid.New()
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("table_id", tableID.String())
+
+	req := testutil.MakeMergePatchRequest("/tables/"+tableID.String(), `[{"op": "replace", "path": "/name", "value": "t"}]`)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	suite.handler.Patch()(w, req)
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid request body")
+
+	req = testutil.MakeMergePatchRequest("/tables/"+tableID.String(), `{"name": "t"}`)
+	req.Header.Set("Content-Type", "application/json-patch+json")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w = httptest.NewRecorder()
+	suite.handler.Patch()(w, req)
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusUnsupportedMediaType, "Content-Type must be application/merge-patch+json")
+}
+
+// Test Update Table - A stale If-Match answers 409 with the current table
+func (suite *TableHandlerTestSuite) TestUpdateTable_VersionConflict() {
+	tableID := uuid.New()
+	staleVersion := int64(1)
+	expectedRequest := dto.UpdateTableRequest{Name: testutil.StringPtr("Renamed"), Version: &staleVersion}
+
+	currentTable := testutil.CreateTestTable(uuid.New())
+	currentTable.ID = tableID
+	currentTable.Version = 2
+
+	suite.mockService.On("UpdateTable", tableID, &expectedRequest, suite.userID).Return(currentTable, services.ErrVersionConflict)
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/tables/"+tableID.String(), dto.UpdateTableRequest{Name: testutil.StringPtr("Renamed")})
+	req.Header.Set("If-Match", `"1"`)
+	req = testutil.WithUserContext(req, suite.userID)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("table_id", tableID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	suite.handler.Update()(w, req)
+
+	response := testutil.AssertErrorResponse(suite.T(), w, http.StatusConflict, "Table was modified by someone else")
+	suite.Equal(`"2"`, w.Header().Get("ETag"))
+	suite.Equal(float64(2), response.Data.(map[string]any)["version"])
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Test Update Table - If-Match must hold a version ETag
+func (suite *TableHandlerTestSuite) TestUpdateTable_InvalidIfMatch() {
+	tableID := uuid.New()
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPut, "/tables/"+tableID.String(), dto.UpdateTableRequest{Name: testutil.StringPtr("Renamed")})
+	req.Header.Set("If-Match", "1")
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("table_id", tableID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	suite.handler.Update()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid If-Match header")
+	suite.mockService.AssertNotCalled(suite.T(), "UpdateTable", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Test Update Table Position - Success
 func (suite *TableHandlerTestSuite) TestUpdateTablePosition_Success() {
 	tableID := uuid.New()
@@ -217,7 +427,7 @@ func (suite *TableHandlerTestSuite) TestDeleteTable_Success() {
 	tableID := uuid.New()
 	userID := uuid.New()
 
-	suite.mockService.On("DeleteTable", tableID, userID).Return(nil)
+	suite.mockService.On("DeleteTable", tableID, userID).Return(int64(7), nil)
 
 	req := httptest.NewRequest(http.MethodDelete, "/tables/"+tableID.String(), nil)
 	req = testutil.WithUserContext(req, userID)
@@ -230,6 +440,7 @@ func (suite *TableHandlerTestSuite) TestDeleteTable_Success() {
 	suite.handler.Delete()(w, req)
 
 	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Table deleted successfully")
+	suite.Equal("7", w.Header().Get(responses.ProjectSequenceHeader))
 	suite.mockService.AssertExpectations(suite.T())
 }
 
@@ -238,7 +449,7 @@ func (suite *TableHandlerTestSuite) TestDeleteTable_NotFound() {
 	tableID := uuid.New()
 	userID := uuid.New()
 
-	suite.mockService.On("DeleteTable", tableID, userID).Return(services.ErrTableNotFound)
+	suite.mockService.On("DeleteTable", tableID, userID).Return(int64(0), services.ErrTableNotFound)
 
 	req := httptest.NewRequest(http.MethodDelete, "/tables/"+tableID.String(), nil)
 	req = testutil.WithUserContext(req, userID)
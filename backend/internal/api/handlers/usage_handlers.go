@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type UsageHandler struct {
+	usageService services.UsageServiceInterface
+}
+
+func NewUsageHandler(usageService services.UsageServiceInterface) *UsageHandler {
+	return &UsageHandler{
+		usageService: usageService,
+	}
+}
+
+// GetMine returns how much of the quotas the current user and the projects they own use
+func (h *UsageHandler) GetMine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		usage, err := h.usageService.GetUsage(userID)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve usage")
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Usage retrieved successfully", usageResponse(usage))
+	}
+}
+
+func usageResponse(usage *services.Usage) dto.UsageResponse {
+	response := dto.UsageResponse{
+		Projects:      quotaUsageResponse(usage.Projects),
+		OwnedProjects: make([]dto.ProjectUsageResponse, len(usage.OwnedProjects)),
+	}
+	for i, project := range usage.OwnedProjects {
+		response.OwnedProjects[i] = dto.ProjectUsageResponse{
+			ProjectID:     project.ProjectID,
+			Name:          project.Name,
+			Tables:        quotaUsageResponse(project.Tables),
+			Collaborators: quotaUsageResponse(project.Collaborators),
+		}
+	}
+	return response
+}
+
+func quotaUsageResponse(usage services.QuotaUsage) dto.QuotaUsageResponse {
+	return dto.QuotaUsageResponse{Used: usage.Used, Limit: usage.Limit}
+}
+
+// respondWithQuotaExceeded answers a change refused for going over a quota with
+// 402 Payment Required, naming the quota so clients can suggest a larger plan
+func respondWithQuotaExceeded(w http.ResponseWriter, err error) {
+	var quotaErr *services.QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		responses.RespondWithError(w, http.StatusPaymentRequired, "Quota exceeded")
+		return
+	}
+	responses.RespondWithErrorData(w, http.StatusPaymentRequired, "Quota exceeded", dto.QuotaExceededResponse{
+		Quota: quotaErr.Quota,
+		Limit: quotaErr.Limit,
+	})
+}
+
+// respondWithTaken answers a change to a value another record already has,
+// such as a taken email or table name, with 409 Conflict naming the attribute
+func respondWithTaken(w http.ResponseWriter, err error, message string) {
+	var takenErr *services.TakenError
+	if !errors.As(err, &takenErr) {
+		responses.RespondWithError(w, http.StatusConflict, message)
+		return
+	}
+	responses.RespondWithErrorData(w, http.StatusConflict, message, dto.TakenResponse{Field: takenErr.Field})
+}
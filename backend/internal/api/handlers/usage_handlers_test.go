@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type UsageHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockUsageService
+	handler     *UsageHandler
+	userID      uuid.UUID
+}
+
+func (suite *UsageHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockUsageService)
+	suite.handler = NewUsageHandler(suite.mockService)
+	suite.userID = uuid.New()
+}
+
+func TestUsageHandlerSuite(t *testing.T) {
+	suite.Run(t, new(UsageHandlerTestSuite))
+}
+
+// Test GetMine - Usage of the current user and their projects
+func (suite *UsageHandlerTestSuite) TestGetMine_Success() {
+	projectID := uuid.New()
+	suite.mockService.On("GetUsage", suite.userID).Return(&services.Usage{
+		Projects: services.QuotaUsage{Used: 1, Limit: 3},
+		OwnedProjects: []services.ProjectUsage{
+			{ProjectID: projectID, Name: "Shop", Tables: services.QuotaUsage{Used: 4}, Collaborators: services.QuotaUsage{Used: 2, Limit: 5}},
+		},
+	}, nil)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/users/me/usage", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetMine()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Usage retrieved successfully")
+	suite.Equal(map[string]any{
+		"projects": map[string]any{"used": float64(1), "limit": float64(3)},
+		"owned_projects": []any{map[string]any{
+			"project_id":    projectID.String(),
+			"name":          "Shop",
+			"tables":        map[string]any{"used": float64(4), "limit": float64(0)},
+			"collaborators": map[string]any{"used": float64(2), "limit": float64(5)},
+		}},
+	}, response.Data)
+}
+
+// Test GetMine - Missing user context
+func (suite *UsageHandlerTestSuite) TestGetMine_Unauthorized() {
+	w := httptest.NewRecorder()
+
+	suite.handler.GetMine()(w, httptest.NewRequest(http.MethodGet, "/users/me/usage", nil))
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "GetUsage", suite.userID)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
 	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
 	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
 	"github.com/google/uuid"
 )
@@ -35,7 +36,7 @@ func (h *UserHandler) Create() http.HandlerFunc {
 		if err != nil {
 			switch {
 			case errors.Is(err, services.ErrUserAlreadyExists):
-				responses.RespondWithError(w, http.StatusConflict, "User already exists")
+				respondWithTaken(w, err, "User already exists")
 			case errors.Is(err, services.ErrInvalidInput):
 				responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
 			default:
@@ -46,9 +47,10 @@ func (h *UserHandler) Create() http.HandlerFunc {
 
 		// Create user response without password
 		userResponse := dto.UserResponse{
-			ID:       user.ID,
-			Email:    user.Email,
-			Username: user.Username,
+			ID:        user.ID,
+			Email:     user.Email,
+			Username:  user.Username,
+			AvatarURL: user.AvatarURL,
 		}
 
 		responses.RespondWithSuccess(w, http.StatusCreated, "User created successfully", userResponse)
@@ -79,7 +81,7 @@ func (h *UserHandler) Update() http.HandlerFunc {
 			case errors.Is(err, services.ErrUserNotFound):
 				responses.RespondWithError(w, http.StatusNotFound, "User not found")
 			case errors.Is(err, services.ErrUserAlreadyExists):
-				responses.RespondWithError(w, http.StatusConflict, "Email already in use")
+				respondWithTaken(w, err, "Email or username already in use")
 			case errors.Is(err, services.ErrInvalidInput):
 				responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
 			default:
@@ -89,9 +91,10 @@ func (h *UserHandler) Update() http.HandlerFunc {
 		}
 
 		userResponse := dto.UserResponse{
-			ID:       user.ID,
-			Email:    user.Email,
-			Username: user.Username,
+			ID:        user.ID,
+			Email:     user.Email,
+			Username:  user.Username,
+			AvatarURL: user.AvatarURL,
 		}
 
 		responses.RespondWithSuccess(w, http.StatusOK, "User updated successfully", userResponse)
@@ -147,33 +150,42 @@ func (h *UserHandler) GetByID() http.HandlerFunc {
 		}
 
 		userResponse := dto.UserResponse{
-			ID:       user.ID,
-			Email:    user.Email,
-			Username: user.Username,
+			ID:        user.ID,
+			Email:     user.Email,
+			Username:  user.Username,
+			AvatarURL: user.AvatarURL,
 		}
 
 		responses.RespondWithSuccess(w, http.StatusOK, "User retrieved successfully", userResponse)
 	}
 }
 
+// GetAll returns a page of users. Supports ?q= to match email or username.
 func (h *UserHandler) GetAll() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		users, err := h.userService.GetAllUsers()
+		page, ok := utils.ParsePageQuery(w, r)
+		if !ok {
+			return
+		}
+
+		filter := repository.UserFilter{Query: r.URL.Query().Get("q")}
+		users, next, err := h.userService.ListUsers(filter, page)
 		if err != nil {
-			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve users")
+			utils.RespondWithPageError(w, err, "Failed to retrieve users")
 			return
 		}
 
-		var userResponses []dto.UserResponse
+		userResponses := make([]dto.UserResponse, 0, len(users))
 		for _, user := range users {
 			userResponses = append(userResponses, dto.UserResponse{
-				ID:       user.ID,
-				Email:    user.Email,
-				Username: user.Username,
+				ID:        user.ID,
+				Email:     user.Email,
+				Username:  user.Username,
+				AvatarURL: user.AvatarURL,
 			})
 		}
 
-		responses.RespondWithSuccess(w, http.StatusOK, "Users retrieved successfully", userResponses)
+		responses.RespondWithPage(w, "Users retrieved successfully", userResponses, dto.PageMeta{NextCursor: next})
 	}
 }
 
@@ -225,9 +237,10 @@ func (h *UserHandler) GetMe() http.HandlerFunc {
 		}
 
 		userResponse := dto.UserResponse{
-			ID:       user.ID,
-			Email:    user.Email,
-			Username: user.Username,
+			ID:        user.ID,
+			Email:     user.Email,
+			Username:  user.Username,
+			AvatarURL: user.AvatarURL,
 		}
 
 		responses.RespondWithSuccess(w, http.StatusOK, "Current user retrieved successfully", userResponse)
@@ -9,6 +9,7 @@ import (
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
 	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
 	"github.com/Bug-Bugger/ezmodel/internal/testutil"
 	"github.com/go-chi/chi/v5"
@@ -89,6 +90,27 @@ func (suite *UserHandlerTestSuite) TestCreateUser_ValidationError() {
 	suite.NotNil(response.Errors)
 }
 
+// Test Create User - Validation messages follow Accept-Language
+func (suite *UserHandlerTestSuite) TestCreateUser_LocalizedValidationError() {
+	invalidRequest := dto.CreateUserRequest{
+		Email:    "invalid-email",
+		Username: "testuser",
+		Password: "password123",
+	}
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/users", invalidRequest)
+	req.Header.Set("Accept-Language", "es-ES,es;q=0.9")
+	w := httptest.NewRecorder()
+
+	suite.handler.Create()(w, req)
+
+	response := testutil.AssertJSONResponse(suite.T(), w, http.StatusBadRequest)
+	suite.Equal("es", w.Header().Get("Content-Language"))
+	errs, ok := response.Errors.(map[string]any)
+	suite.Require().True(ok)
+	suite.Equal("Debe ser una dirección de correo electrónico válida", errs["email"])
+}
+
 // Test Create User - User Already Exists
 func (suite *UserHandlerTestSuite) TestCreateUser_UserAlreadyExists() {
 	requestBody := testutil.CreateValidUserRequest()
@@ -105,6 +127,25 @@ func (suite *UserHandlerTestSuite) TestCreateUser_UserAlreadyExists() {
 	suite.mockService.AssertExpectations(suite.T())
 }
 
+// Test Create User - Username Taken names the taken attribute
+func (suite *UserHandlerTestSuite) TestCreateUser_UsernameTaken() {
+	requestBody := testutil.CreateValidUserRequest()
+
+	suite.mockService.On("CreateUser", requestBody.Email, requestBody.Username, requestBody.Password).
+		Return(nil, &services.TakenError{Field: "username", Err: services.ErrUserAlreadyExists})
+
+	req := testutil.MakeJSONRequest(suite.T(), http.MethodPost, "/users", requestBody)
+	w := httptest.NewRecorder()
+
+	suite.handler.Create()(w, req)
+
+	response := testutil.AssertJSONResponse(suite.T(), w, http.StatusConflict)
+	suite.Equal("User already exists", response.Message)
+	data, ok := response.Data.(map[string]any)
+	suite.Require().True(ok)
+	suite.Equal("username", data["field"])
+}
+
 // Test Create User - Service Error
 func (suite *UserHandlerTestSuite) TestCreateUser_ServiceError() {
 	requestBody := testutil.CreateValidUserRequest()
@@ -257,9 +298,9 @@ func (suite *UserHandlerTestSuite) TestGetAllUsers_Success() {
 		testutil.CreateTestUserWithData("user2@test.com", "user2"),
 	}
 
-	suite.mockService.On("GetAllUsers").Return(realUsers, nil)
+	suite.mockService.On("ListUsers", repository.UserFilter{Query: "user"}, repository.PageQuery{}).Return(realUsers, "", nil)
 
-	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req := httptest.NewRequest(http.MethodGet, "/users?q=user", nil)
 	w := httptest.NewRecorder()
 
 	suite.handler.GetAll()(w, req)
@@ -269,6 +310,7 @@ func (suite *UserHandlerTestSuite) TestGetAllUsers_Success() {
 	usersResponse, ok := response.Data.([]any)
 	suite.True(ok)
 	suite.Len(usersResponse, 2)
+	suite.Empty(response.Meta.NextCursor)
 
 	suite.mockService.AssertExpectations(suite.T())
 }
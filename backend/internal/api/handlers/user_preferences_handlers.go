@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type UserPreferencesHandler struct {
+	preferencesService services.UserPreferencesServiceInterface
+}
+
+func NewUserPreferencesHandler(preferencesService services.UserPreferencesServiceInterface) *UserPreferencesHandler {
+	return &UserPreferencesHandler{
+		preferencesService: preferencesService,
+	}
+}
+
+// GetMine returns the current user's preferences
+func (h *UserPreferencesHandler) GetMine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		preferences, err := h.preferencesService.GetPreferences(userID)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve preferences")
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Preferences retrieved successfully", userPreferencesResponse(preferences))
+	}
+}
+
+// PatchMine applies a JSON Merge Patch to the current user's preferences
+func (h *UserPreferencesHandler) PatchMine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		var req dto.UpdateUserPreferencesRequest
+		if !utils.DecodeMergePatch(w, r, &req) {
+			return
+		}
+
+		preferences, err := h.preferencesService.UpdatePreferences(userID, &req)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidInput) {
+				responses.RespondWithError(w, http.StatusBadRequest, "Invalid input")
+			} else {
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to update preferences")
+			}
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Preferences updated successfully", userPreferencesResponse(preferences))
+	}
+}
+
+func userPreferencesResponse(preferences *models.UserPreferences) dto.UserPreferencesResponse {
+	response := dto.UserPreferencesResponse{
+		Theme:                    preferences.Theme,
+		DefaultDatabaseType:      preferences.DefaultDatabaseType,
+		CanvasShowGrid:           preferences.CanvasShowGrid,
+		CanvasSnapToGrid:         preferences.CanvasSnapToGrid,
+		CanvasGridSize:           preferences.CanvasGridSize,
+		NotifyCollaboratorJoined: preferences.NotifyCollaboratorJoined,
+		NotifyProjectShared:      preferences.NotifyProjectShared,
+		EmailNotifications:       preferences.EmailNotifications,
+	}
+	if !preferences.UpdatedAt.IsZero() {
+		response.UpdatedAt = &preferences.UpdatedAt
+	}
+	return response
+}
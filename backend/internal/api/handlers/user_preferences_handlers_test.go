@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserPreferencesHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockUserPreferencesService
+	handler     *UserPreferencesHandler
+	userID      uuid.UUID
+}
+
+func (suite *UserPreferencesHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockUserPreferencesService)
+	suite.handler = NewUserPreferencesHandler(suite.mockService)
+	suite.userID = uuid.New()
+}
+
+func TestUserPreferencesHandlerSuite(t *testing.T) {
+	suite.Run(t, new(UserPreferencesHandlerTestSuite))
+}
+
+// Test GetMine - Defaults have no update time
+func (suite *UserPreferencesHandlerTestSuite) TestGetMine_Defaults() {
+	suite.mockService.On("GetPreferences", suite.userID).Return(models.DefaultUserPreferences(suite.userID), nil)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/users/me/preferences", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetMine()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Preferences retrieved successfully")
+	data, ok := response.Data.(map[string]any)
+	suite.Require().True(ok)
+	assert.Equal(suite.T(), "system", data["theme"])
+	assert.Nil(suite.T(), data["updated_at"])
+}
+
+// Test PatchMine - Only the members of the patch are passed on
+func (suite *UserPreferencesHandlerTestSuite) TestPatchMine_Success() {
+	updated := models.DefaultUserPreferences(suite.userID)
+	updated.Theme = "dark"
+
+	suite.mockService.On("UpdatePreferences", suite.userID, mock.MatchedBy(func(req *dto.UpdateUserPreferencesRequest) bool {
+		return req.Theme != nil && *req.Theme == "dark" && req.CanvasShowGrid == nil
+	})).Return(updated, nil)
+
+	req := testutil.WithUserContext(testutil.MakeMergePatchRequest("/users/me/preferences", `{"theme": "dark"}`), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.PatchMine()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Preferences updated successfully")
+	data, ok := response.Data.(map[string]any)
+	suite.Require().True(ok)
+	assert.Equal(suite.T(), "dark", data["theme"])
+}
+
+// Test PatchMine - Unsupported values fail validation
+func (suite *UserPreferencesHandlerTestSuite) TestPatchMine_InvalidTheme() {
+	req := testutil.WithUserContext(testutil.MakeMergePatchRequest("/users/me/preferences", `{"theme": "neon"}`), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.PatchMine()(w, req)
+
+	assert.Equal(suite.T(), http.StatusBadRequest, w.Code)
+	suite.mockService.AssertNotCalled(suite.T(), "UpdatePreferences", mock.Anything, mock.Anything)
+}
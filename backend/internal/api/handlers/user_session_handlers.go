@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+)
+
+type UserSessionHandler struct {
+	userSessionService services.UserSessionServiceInterface
+}
+
+func NewUserSessionHandler(userSessionService services.UserSessionServiceInterface) *UserSessionHandler {
+	return &UserSessionHandler{
+		userSessionService: userSessionService,
+	}
+}
+
+// GetMine lists the devices the current user is signed in on
+func (h *UserSessionHandler) GetMine() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		sessions, err := h.userSessionService.GetActiveSessions(userID)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to retrieve sessions")
+			return
+		}
+
+		currentSessionID, _ := middleware.GetSessionIDFromContext(r.Context())
+
+		response := make([]dto.UserSessionResponse, len(sessions))
+		for i, session := range sessions {
+			response[i] = dto.UserSessionResponse{
+				ID:           session.ID,
+				IPAddress:    session.IPAddress,
+				UserAgent:    session.UserAgent,
+				LastSeenAt:   session.LastSeenAt,
+				ExpiresAt:    session.ExpiresAt,
+				CreatedAt:    session.CreatedAt,
+				Current:      session.ID == currentSessionID,
+				Impersonated: session.ImpersonatorID != nil,
+			}
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Sessions retrieved successfully", response)
+	}
+}
+
+// Revoke signs one of the current user's devices out
+func (h *UserSessionHandler) Revoke() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		sessionID, ok := utils.ParseUUIDParam(w, r, "session_id")
+		if !ok {
+			return
+		}
+
+		if err := h.userSessionService.RevokeSession(userID, sessionID); err != nil {
+			if errors.Is(err, services.ErrUserSessionNotFound) {
+				responses.RespondWithError(w, http.StatusNotFound, "Session not found")
+			} else {
+				responses.RespondWithError(w, http.StatusInternalServerError, "Failed to revoke session")
+			}
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Session revoked successfully", nil)
+	}
+}
+
+// RevokeOthers signs the current user out of every device except the one making the request
+func (h *UserSessionHandler) RevokeOthers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := currentUserID(w, r)
+		if !ok {
+			return
+		}
+
+		// Without a current session, e.g. an old token, the ID is nil and every session is revoked
+		currentSessionID, _ := middleware.GetSessionIDFromContext(r.Context())
+
+		if err := h.userSessionService.RevokeOtherSessions(userID, currentSessionID); err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Failed to revoke sessions")
+			return
+		}
+
+		responses.RespondWithSuccess(w, http.StatusOK, "Other sessions revoked successfully", nil)
+	}
+}
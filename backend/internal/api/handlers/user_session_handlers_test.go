@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserSessionHandlerTestSuite struct {
+	suite.Suite
+	mockService *mockService.MockUserSessionService
+	handler     *UserSessionHandler
+	userID      uuid.UUID
+}
+
+func (suite *UserSessionHandlerTestSuite) SetupTest() {
+	suite.mockService = new(mockService.MockUserSessionService)
+	suite.handler = NewUserSessionHandler(suite.mockService)
+	suite.userID = uuid.New()
+}
+
+func TestUserSessionHandlerSuite(t *testing.T) {
+	suite.Run(t, new(UserSessionHandlerTestSuite))
+}
+
+func (suite *UserSessionHandlerTestSuite) withSessionID(req *http.Request, sessionID string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("session_id", sessionID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+// Test GetMine - Marks the session the request was made with
+func (suite *UserSessionHandlerTestSuite) TestGetMine_Success() {
+	currentID := uuid.New()
+	now := time.Now()
+	sessions := []*models.UserSession{
+		{ID: currentID, UserID: suite.userID, IPAddress: "192.0.2.1", UserAgent: "laptop", LastSeenAt: now, ExpiresAt: now.Add(time.Hour)},
+		{ID: uuid.New(), UserID: suite.userID, IPAddress: "198.51.100.2", UserAgent: "phone", LastSeenAt: now, ExpiresAt: now.Add(time.Hour)},
+	}
+	suite.mockService.On("GetActiveSessions", suite.userID).Return(sessions, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/me/sessions", nil)
+	req = testutil.WithSessionContext(testutil.WithUserContext(req, suite.userID), currentID)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetMine()(w, req)
+
+	response := testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Sessions retrieved successfully")
+	data, ok := response.Data.([]any)
+	suite.Require().True(ok)
+	suite.Require().Len(data, 2)
+	first := data[0].(map[string]any)
+	second := data[1].(map[string]any)
+	suite.Equal(currentID.String(), first["id"])
+	suite.Equal("laptop", first["user_agent"])
+	suite.Equal(true, first["current"])
+	suite.Equal(false, second["current"])
+	suite.NotContains(first, "refresh_token_hash")
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Test GetMine - Service error
+func (suite *UserSessionHandlerTestSuite) TestGetMine_ServiceError() {
+	suite.mockService.On("GetActiveSessions", suite.userID).Return(nil, assert.AnError)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodGet, "/api/users/me/sessions", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetMine()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusInternalServerError, "Failed to retrieve sessions")
+}
+
+// Test GetMine - Unauthenticated
+func (suite *UserSessionHandlerTestSuite) TestGetMine_Unauthenticated() {
+	req := httptest.NewRequest(http.MethodGet, "/api/users/me/sessions", nil)
+	w := httptest.NewRecorder()
+
+	suite.handler.GetMine()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusUnauthorized, "User context not found")
+}
+
+// Test Revoke - Success
+func (suite *UserSessionHandlerTestSuite) TestRevoke_Success() {
+	sessionID := uuid.New()
+	suite.mockService.On("RevokeSession", suite.userID, sessionID).Return(nil)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodDelete, "/api/users/me/sessions/"+sessionID.String(), nil), suite.userID)
+	req = suite.withSessionID(req, sessionID.String())
+	w := httptest.NewRecorder()
+
+	suite.handler.Revoke()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Session revoked successfully")
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Test Revoke - Another user's session is not found
+func (suite *UserSessionHandlerTestSuite) TestRevoke_NotFound() {
+	sessionID := uuid.New()
+	suite.mockService.On("RevokeSession", suite.userID, sessionID).Return(services.ErrUserSessionNotFound)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodDelete, "/api/users/me/sessions/"+sessionID.String(), nil), suite.userID)
+	req = suite.withSessionID(req, sessionID.String())
+	w := httptest.NewRecorder()
+
+	suite.handler.Revoke()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusNotFound, "Session not found")
+}
+
+// Test Revoke - Invalid session ID
+func (suite *UserSessionHandlerTestSuite) TestRevoke_InvalidID() {
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodDelete, "/api/users/me/sessions/bad", nil), suite.userID)
+	req = suite.withSessionID(req, "bad")
+	w := httptest.NewRecorder()
+
+	suite.handler.Revoke()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusBadRequest, "Invalid session ID format")
+	suite.mockService.AssertNotCalled(suite.T(), "RevokeSession")
+}
+
+// Test RevokeOthers - Keeps the current session
+func (suite *UserSessionHandlerTestSuite) TestRevokeOthers_Success() {
+	currentID := uuid.New()
+	suite.mockService.On("RevokeOtherSessions", suite.userID, currentID).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/users/me/sessions", nil)
+	req = testutil.WithSessionContext(testutil.WithUserContext(req, suite.userID), currentID)
+	w := httptest.NewRecorder()
+
+	suite.handler.RevokeOthers()(w, req)
+
+	testutil.AssertSuccessResponse(suite.T(), w, http.StatusOK, "Other sessions revoked successfully")
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+// Test RevokeOthers - Service error
+func (suite *UserSessionHandlerTestSuite) TestRevokeOthers_ServiceError() {
+	suite.mockService.On("RevokeOtherSessions", suite.userID, uuid.Nil).Return(assert.AnError)
+
+	req := testutil.WithUserContext(httptest.NewRequest(http.MethodDelete, "/api/users/me/sessions", nil), suite.userID)
+	w := httptest.NewRecorder()
+
+	suite.handler.RevokeOthers()(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusInternalServerError, "Failed to revoke sessions")
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/canvas"
 	"github.com/Bug-Bugger/ezmodel/internal/config"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
@@ -19,46 +21,65 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-const (
-	// WebSocket configuration
-	writeWait      = 10 * time.Second
-	pongWait       = 60 * time.Second
-	pingPeriod     = (pongWait * 9) / 10
-	maxMessageSize = 512
-)
-
 type WebSocketHandler struct {
-	config         *config.Config
-	hub            *websocketPkg.Hub
-	jwtService     services.JWTServiceInterface
-	userService    services.UserServiceInterface
-	projectService services.ProjectServiceInterface
-	tableService   services.TableServiceInterface
-	upgrader       websocket.Upgrader
+	config             *config.Config
+	hub                *websocketPkg.Hub
+	jwtService         services.JWTServiceInterface
+	userSessionService services.UserSessionServiceInterface
+	userService        services.UserServiceInterface
+	projectService     services.ProjectServiceInterface
+	tableService       services.TableServiceInterface
+	fieldService       services.FieldServiceInterface
+	chatService        services.ChatServiceInterface
+	upgrader           websocket.Upgrader
+
+	// Connection settings from config
+	writeWait      time.Duration
+	pongWait       time.Duration
+	pingPeriod     time.Duration
+	authTimeout    time.Duration
+	maxMessageSize int64
+	maxCanvasSize  int // CANVAS_MAX_SIZE, for chunked updates and whole ones alike
 }
 
 func NewWebSocketHandler(
 	cfg *config.Config,
 	hub *websocketPkg.Hub,
 	jwtService services.JWTServiceInterface,
+	userSessionService services.UserSessionServiceInterface,
 	userService services.UserServiceInterface,
 	projectService services.ProjectServiceInterface,
 	tableService services.TableServiceInterface,
+	fieldService services.FieldServiceInterface,
+	chatService services.ChatServiceInterface,
 ) *WebSocketHandler {
 	h := &WebSocketHandler{
-		config:         cfg,
-		hub:            hub,
-		jwtService:     jwtService,
-		userService:    userService,
-		projectService: projectService,
-		tableService:   tableService,
+		config:             cfg,
+		hub:                hub,
+		jwtService:         jwtService,
+		userSessionService: userSessionService,
+		userService:        userService,
+		projectService:     projectService,
+		tableService:       tableService,
+		fieldService:       fieldService,
+		chatService:        chatService,
+		writeWait:          cfg.WebSocket.WriteWait,
+		pongWait:           cfg.WebSocket.PongWait,
+		pingPeriod:         cfg.WebSocket.PingPeriod,
+		authTimeout:        cfg.WebSocket.AuthTimeout,
+		maxMessageSize:     cfg.WebSocket.MaxMessageSize,
+		maxCanvasSize:      cfg.Canvas.MaxSize,
+	}
+	if h.maxCanvasSize <= 0 {
+		h.maxCanvasSize = canvas.DefaultMaxSize // As canvas.Validate would
 	}
 
 	// Initialize upgrader with origin validation
 	h.upgrader = websocket.Upgrader{
-		ReadBufferSize:  1024,
-		WriteBufferSize: 1024,
-		CheckOrigin:     h.checkOrigin,
+		ReadBufferSize:    cfg.WebSocket.ReadBufferSize,
+		WriteBufferSize:   cfg.WebSocket.WriteBufferSize,
+		EnableCompression: true, // Negotiate permessage-deflate when the client supports it
+		CheckOrigin:       h.checkOrigin,
 	}
 
 	return h
@@ -66,6 +87,12 @@ func NewWebSocketHandler(
 
 // checkOrigin validates WebSocket connection origins against allowed origins
 func (h *WebSocketHandler) checkOrigin(r *http.Request) bool {
+	return originAllowed(r, h.config.AllowedOrigins)
+}
+
+// originAllowed reports whether a WebSocket upgrade comes from an allowed origin.
+// Browsers send cookies on cross-site upgrades, so every WebSocket endpoint checks it.
+func originAllowed(r *http.Request, allowedOrigins []string) bool {
 	origin := r.Header.Get("Origin")
 
 	if origin == "" {
@@ -74,7 +101,7 @@ func (h *WebSocketHandler) checkOrigin(r *http.Request) bool {
 	}
 
 	// Check if origin is in allowed list
-	for _, allowed := range h.config.AllowedOrigins {
+	for _, allowed := range allowedOrigins {
 		if origin == allowed {
 			log.Printf("WebSocket: Accepted connection from authorized origin: %s", origin)
 			return true
@@ -82,7 +109,7 @@ func (h *WebSocketHandler) checkOrigin(r *http.Request) bool {
 	}
 
 	// Log rejected origin for security monitoring
-	log.Printf("WebSocket: Rejected connection from unauthorized origin: %s (allowed: %v)", origin, h.config.AllowedOrigins)
+	log.Printf("WebSocket: Rejected connection from unauthorized origin: %s (allowed: %v)", origin, allowedOrigins)
 	return false
 }
 
@@ -114,17 +141,25 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 
 // handleUnauthenticatedConnection waits for an auth message on a new WebSocket connection
 func (h *WebSocketHandler) handleUnauthenticatedConnection(conn *websocket.Conn, r *http.Request, projectID uuid.UUID) {
-	// Set read deadline for authentication (10 seconds)
-	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	// Set read deadline for authentication
+	conn.SetReadLimit(h.maxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(h.authTimeout))
 
 	// Wait for auth message
-	_, messageBytes, err := conn.ReadMessage()
+	frameType, frameBytes, err := conn.ReadMessage()
 	if err != nil {
 		log.Printf("WebSocket: Failed to read auth message: %v", err)
 		h.sendErrorAndClose(conn, "Authentication timeout or failed to read message")
 		return
 	}
 
+	messageBytes, err := websocketPkg.DecodeFrame(frameType, frameBytes)
+	if err != nil {
+		log.Printf("WebSocket: Failed to decode auth message: %v", err)
+		h.sendErrorAndClose(conn, "Invalid message format")
+		return
+	}
+
 	// Parse message
 	var message websocketPkg.WebSocketMessage
 	if err := json.Unmarshal(messageBytes, &message); err != nil {
@@ -168,19 +203,22 @@ func (h *WebSocketHandler) handleUnauthenticatedConnection(conn *websocket.Conn,
 	}
 
 	// Verify user has access to the project
-	if err := h.verifyProjectAccess(user.ID, projectID); err != nil {
+	role, err := h.verifyProjectAccess(user.ID, projectID)
+	if err != nil {
 		log.Printf("WebSocket: Access denied: %v", err)
 		h.sendErrorAndClose(conn, err.Error())
 		return
 	}
 
-	log.Printf("WebSocket: Authentication successful for user %s (%s)", user.Username, user.ID)
+	encoding := websocketPkg.NegotiateEncoding(authPayload.Encoding)
+
+	log.Printf("WebSocket: Authentication successful for user %s (%s), encoding %s", user.Username, user.ID, encoding)
 
 	// Send auth success message
-	h.sendAuthSuccess(conn, user.ID)
+	h.sendAuthSuccess(conn, user.ID, encoding, role)
 
 	// Register authenticated client
-	h.registerAuthenticatedClient(conn, user, projectID)
+	h.registerAuthenticatedClient(conn, user, projectID, encoding, role)
 }
 
 // extractTokenFromRequest attempts to read a JWT token from cookies or headers
@@ -219,6 +257,12 @@ func (h *WebSocketHandler) authenticateToken(token string) (*models.User, error)
 		return nil, fmt.Errorf("invalid token")
 	}
 
+	if claims.SessionID != uuid.Nil {
+		if _, err := h.userSessionService.ValidateSession(claims.SessionID); err != nil {
+			return nil, fmt.Errorf("session has been revoked")
+		}
+	}
+
 	// Get user information
 	user, err := h.userService.GetUserByID(claims.UserID)
 	if err != nil {
@@ -228,29 +272,18 @@ func (h *WebSocketHandler) authenticateToken(token string) (*models.User, error)
 	return user, nil
 }
 
-// verifyProjectAccess checks if user has access to the project
-func (h *WebSocketHandler) verifyProjectAccess(userID, projectID uuid.UUID) error {
-	project, err := h.projectService.GetProjectByID(projectID)
+// verifyProjectAccess checks if user has access to the project and returns their role in it
+func (h *WebSocketHandler) verifyProjectAccess(userID, projectID uuid.UUID) (string, error) {
+	role, err := h.projectService.GetMemberRole(projectID, userID)
 	if err != nil {
-		return fmt.Errorf("project not found")
-	}
-
-	// Check if user is owner or collaborator
-	hasAccess := project.OwnerID == userID
-	if !hasAccess {
-		for _, collaborator := range project.Collaborators {
-			if collaborator.ID == userID {
-				hasAccess = true
-				break
-			}
+		// Only owners and collaborators have a role
+		if errors.Is(err, services.ErrForbidden) {
+			return "", fmt.Errorf("access denied to project")
 		}
+		return "", fmt.Errorf("project not found")
 	}
 
-	if !hasAccess {
-		return fmt.Errorf("access denied to project")
-	}
-
-	return nil
+	return role, nil
 }
 
 // sendErrorAndClose sends an error message and closes the connection
@@ -272,10 +305,13 @@ func (h *WebSocketHandler) sendErrorAndClose(conn *websocket.Conn, message strin
 }
 
 // sendAuthSuccess sends an authentication success message
-func (h *WebSocketHandler) sendAuthSuccess(conn *websocket.Conn, userID uuid.UUID) {
+// It is always JSON so clients can read the negotiated encoding before switching
+func (h *WebSocketHandler) sendAuthSuccess(conn *websocket.Conn, userID uuid.UUID, encoding, role string) {
 	successMsg := websocketPkg.AuthSuccessPayload{
-		Message: "Authentication successful",
-		UserID:  userID.String(),
+		Message:  "Authentication successful",
+		UserID:   userID.String(),
+		Encoding: encoding,
+		Role:     role,
 	}
 	msgBytes, _ := json.Marshal(map[string]interface{}{
 		"type": websocketPkg.MessageTypeAuth,
@@ -285,7 +321,7 @@ func (h *WebSocketHandler) sendAuthSuccess(conn *websocket.Conn, userID uuid.UUI
 }
 
 // registerAuthenticatedClient creates and registers an authenticated client
-func (h *WebSocketHandler) registerAuthenticatedClient(conn *websocket.Conn, user *models.User, projectID uuid.UUID) {
+func (h *WebSocketHandler) registerAuthenticatedClient(conn *websocket.Conn, user *models.User, projectID uuid.UUID, encoding, role string) {
 	// Generate a random color for the user
 	userColor := generateRandomColor()
 
@@ -296,11 +332,17 @@ func (h *WebSocketHandler) registerAuthenticatedClient(conn *websocket.Conn, use
 		ProjectID: projectID,
 		Username:  user.Username,
 		UserColor: userColor,
+		AvatarURL: user.AvatarURL,
+		Encoding:  encoding,
 		Conn:      conn,
 		Send:      make(chan []byte, 256),
 		Hub:       h.hub,
 		LastPing:  time.Now(),
+
+		CanvasChunks: websocketPkg.NewCanvasChunkAssembler(h.maxCanvasSize),
+		TableDrags:   websocketPkg.NewTableDragDebouncer(websocketPkg.TableDragCommitDelay),
 	}
+	client.SetReadOnly(role == models.ProjectRoleViewer)
 
 	// Register client with hub
 	h.hub.RegisterClient(client)
@@ -315,18 +357,22 @@ func (h *WebSocketHandler) readPump(client *websocketPkg.Client) {
 	defer func() {
 		h.hub.UnregisterClient(client)
 		client.Conn.Close()
+		// Save where the client left the tables it was dragging
+		if client.TableDrags != nil {
+			client.TableDrags.Flush()
+		}
 	}()
 
-	client.Conn.SetReadLimit(maxMessageSize)
-	client.Conn.SetReadDeadline(time.Now().Add(pongWait))
+	client.Conn.SetReadLimit(h.maxMessageSize)
+	client.Conn.SetReadDeadline(time.Now().Add(h.pongWait))
 	client.Conn.SetPongHandler(func(string) error {
 		client.LastPing = time.Now()
-		client.Conn.SetReadDeadline(time.Now().Add(pongWait))
+		client.Conn.SetReadDeadline(time.Now().Add(h.pongWait))
 		return nil
 	})
 
 	for {
-		_, messageBytes, err := client.Conn.ReadMessage()
+		frameType, frameBytes, err := client.Conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
@@ -334,6 +380,12 @@ func (h *WebSocketHandler) readPump(client *websocketPkg.Client) {
 			break
 		}
 
+		messageBytes, err := websocketPkg.DecodeFrame(frameType, frameBytes)
+		if err != nil {
+			log.Printf("Error decoding message: %v", err)
+			continue
+		}
+
 		// Parse message
 		var message websocketPkg.WebSocketMessage
 		if err := json.Unmarshal(messageBytes, &message); err != nil {
@@ -353,7 +405,7 @@ func (h *WebSocketHandler) readPump(client *websocketPkg.Client) {
 
 // writePump pumps messages from the hub to the WebSocket connection
 func (h *WebSocketHandler) writePump(client *websocketPkg.Client) {
-	ticker := time.NewTicker(pingPeriod)
+	ticker := time.NewTicker(h.pingPeriod)
 	defer func() {
 		ticker.Stop()
 		client.Conn.Close()
@@ -362,13 +414,21 @@ func (h *WebSocketHandler) writePump(client *websocketPkg.Client) {
 	for {
 		select {
 		case message, ok := <-client.Send:
-			client.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+			client.Conn.SetWriteDeadline(time.Now().Add(h.writeWait))
 			if !ok {
 				// Hub closed the channel
 				client.Conn.WriteMessage(websocket.CloseMessage, []byte{})
 				return
 			}
 
+			// Binary encodings are sent one message per frame
+			if client.Encoding == websocketPkg.EncodingMessagePack {
+				if err := h.writeEncodedMessage(client, message); err != nil {
+					return
+				}
+				continue
+			}
+
 			w, err := client.Conn.NextWriter(websocket.TextMessage)
 			if err != nil {
 				return
@@ -387,7 +447,7 @@ func (h *WebSocketHandler) writePump(client *websocketPkg.Client) {
 			}
 
 		case <-ticker.C:
-			client.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+			client.Conn.SetWriteDeadline(time.Now().Add(h.writeWait))
 			if err := client.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
@@ -395,22 +455,60 @@ func (h *WebSocketHandler) writePump(client *websocketPkg.Client) {
 	}
 }
 
+// writeEncodedMessage writes a single message using the client's negotiated encoding
+func (h *WebSocketHandler) writeEncodedMessage(client *websocketPkg.Client, message []byte) error {
+	frameType, frameBytes, err := websocketPkg.EncodeFrame(client.Encoding, message)
+	if err != nil {
+		log.Printf("Error encoding message for client %s: %v", client.UserID, err)
+		return nil // Drop the message but keep the connection
+	}
+	return client.Conn.WriteMessage(frameType, frameBytes)
+}
+
 // handleMessage processes incoming WebSocket messages
 func (h *WebSocketHandler) handleMessage(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) {
+	message.Sequence = 0 // Numbered by the hub, never by clients
+
+	// Anything but heartbeats and status reports is activity
+	if message.Type != websocketPkg.MessageTypePong && message.Type != websocketPkg.MessageTypePresenceStatus {
+		h.hub.TouchClient(client)
+	}
+
+	// Viewers follow along but cannot change the project
+	if client.ReadOnly() && (message.Type.IsSchemaChange() || message.Type == websocketPkg.MessageTypeCanvasChunk) {
+		h.sendError(client, "You don't have permission to modify this project", "forbidden")
+		return
+	}
+
 	switch message.Type {
 	case websocketPkg.MessageTypeUserCursor:
 		h.handleCursorUpdate(client, message)
 	case websocketPkg.MessageTypePong:
 		h.handlePong(client, message)
+	case websocketPkg.MessageTypePresenceStatus:
+		h.handlePresenceStatus(client, message)
 	case websocketPkg.MessageTypeCanvasUpdated:
 		h.handleCanvasUpdate(client, message)
+	case websocketPkg.MessageTypeCanvasChunk:
+		h.handleCanvasChunk(client, message)
 	case websocketPkg.MessageTypeTableUpdated:
 		h.handleTableUpdate(client, message)
 	case websocketPkg.MessageTypeTableMoved:
 		h.handleTableMove(client, message)
+	case websocketPkg.MessageTypeFieldsReordered:
+		h.handleFieldsReorder(client, message)
+	case websocketPkg.MessageTypeChatMessage:
+		h.handleChatMessage(client, message)
+	case websocketPkg.MessageTypeViewportUpdate:
+		h.handleViewportUpdate(client, message)
+	case websocketPkg.MessageTypeFollowUser:
+		h.handleFollowUser(client, message)
+	case websocketPkg.MessageTypeCanvasPing:
+		h.handleCanvasPing(client, message)
 	default:
-		// For other message types, broadcast to all clients in the project
-		h.hub.BroadcastToProject(client.ProjectID, message, client)
+		// Everything else comes from the server only; relaying it would let a
+		// client forge schema changes that were never saved
+		h.sendError(client, fmt.Sprintf("Clients cannot send %s messages", message.Type), "unknown_message_type")
 	}
 }
 
@@ -443,6 +541,78 @@ func (h *WebSocketHandler) handleCursorUpdate(client *websocketPkg.Client, messa
 	h.hub.BroadcastToProject(client.ProjectID, newMessage, nil)
 }
 
+// handleViewportUpdate relays a client's pan and zoom to the users following
+// it. The hub throttles the updates of each user.
+func (h *WebSocketHandler) handleViewportUpdate(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) {
+	var payload websocketPkg.ViewportPayload
+	if err := message.UnmarshalData(&payload); err != nil || payload.Zoom <= 0 {
+		h.sendError(client, "Invalid viewport", "invalid_viewport")
+		return
+	}
+	payload.UserID = client.UserID
+
+	newMessage, err := websocketPkg.NewWebSocketMessage(
+		websocketPkg.MessageTypeViewportUpdate,
+		payload,
+		client.UserID,
+		client.ProjectID,
+	)
+	if err != nil {
+		log.Printf("Error creating viewport message: %v", err)
+		return
+	}
+	h.hub.BroadcastToProject(client.ProjectID, newMessage, client)
+}
+
+// handleFollowUser starts or stops following another user's viewport
+func (h *WebSocketHandler) handleFollowUser(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) {
+	var payload websocketPkg.FollowUserPayload
+	if err := message.UnmarshalData(&payload); err != nil {
+		h.sendError(client, "Invalid follow request", "invalid_follow")
+		return
+	}
+
+	followed := uuid.Nil
+	if payload.UserID != nil {
+		followed = *payload.UserID
+	}
+	if followed == client.UserID {
+		h.sendError(client, "You cannot follow yourself", "invalid_follow")
+		return
+	}
+	h.hub.FollowUser(client, followed)
+}
+
+// handleCanvasPing relays a ping to the other clients of the project. Pings
+// are never saved.
+func (h *WebSocketHandler) handleCanvasPing(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) {
+	var payload websocketPkg.CanvasPingPayload
+	if err := message.UnmarshalData(&payload); err != nil || payload.TTL < 0 || payload.TTL > int(websocketPkg.MaxCanvasPingTTL.Milliseconds()) {
+		h.sendError(client, fmt.Sprintf("Pings hold coordinates and a ttl of up to %d milliseconds", websocketPkg.MaxCanvasPingTTL.Milliseconds()), "invalid_ping")
+		return
+	}
+	if payload.TTL == 0 {
+		payload.TTL = int(websocketPkg.DefaultCanvasPingTTL.Milliseconds())
+	}
+
+	// Update payload with client information
+	payload.UserID = client.UserID
+	payload.Username = client.Username
+	payload.UserColor = client.UserColor
+
+	newMessage, err := websocketPkg.NewWebSocketMessage(
+		websocketPkg.MessageTypeCanvasPing,
+		payload,
+		client.UserID,
+		client.ProjectID,
+	)
+	if err != nil {
+		log.Printf("Error creating ping message: %v", err)
+		return
+	}
+	h.hub.BroadcastToProject(client.ProjectID, newMessage, client)
+}
+
 // handlePong processes pong messages for heartbeat
 func (h *WebSocketHandler) handlePong(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) {
 	client.LastPing = time.Now()
@@ -455,6 +625,19 @@ func (h *WebSocketHandler) handlePong(client *websocketPkg.Client, message *webs
 
 	// Update last ping time
 	client.LastPing = payload.Timestamp
+	h.hub.RecordPong(client, payload.Timestamp)
+}
+
+// handlePresenceStatus records whether a client's tab is in the background.
+// Clients report active or away; idle is up to the hub.
+func (h *WebSocketHandler) handlePresenceStatus(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) {
+	var payload websocketPkg.PresenceStatusPayload
+	if err := message.UnmarshalData(&payload); err != nil ||
+		(payload.Status != websocketPkg.PresenceActive && payload.Status != websocketPkg.PresenceAway) {
+		h.sendError(client, "Presence status must be active or away", "invalid_presence_status")
+		return
+	}
+	h.hub.SetClientAway(client, payload.Status == websocketPkg.PresenceAway)
 }
 
 // handleCanvasUpdate processes canvas update messages
@@ -465,61 +648,231 @@ func (h *WebSocketHandler) handleCanvasUpdate(client *websocketPkg.Client, messa
 		return
 	}
 
-	// Update project canvas data in database
-	// This is a simple implementation - in production you might want to
-	// implement operational transformation or conflict resolution
-	go func() {
+	// Invalid canvas data is neither saved nor relayed, so collaborators never
+	// receive a layout they cannot render
+	if _, err := canvas.Validate(payload.CanvasData, h.maxCanvasSize); err != nil {
+		h.sendError(client, err.Error(), "invalid_canvas")
+		return
+	}
+
+	// The project service broadcasts the canvas to everyone, the sender
+	// included, once it is saved
+	h.submitWrite(client, func() {
 		if err := h.updateProjectCanvasData(client.ProjectID, payload.CanvasData, client.UserID); err != nil {
 			log.Printf("Error updating canvas data: %v", err)
+			h.sendError(client, "Canvas could not be saved", "canvas_update_failed")
 		}
-	}()
+	})
+}
 
-	// Broadcast to all clients in the project including sender
-	h.hub.BroadcastToProject(client.ProjectID, message, nil)
+// submitWrite hands a database write for a client to the hub's write pool,
+// telling the client to retry when the pool is saturated
+func (h *WebSocketHandler) submitWrite(client *websocketPkg.Client, write func()) {
+	if !h.hub.SubmitWrite(write) {
+		h.sendError(client, "Server is busy, please retry", "server_busy")
+	}
 }
 
-// handleTableUpdate processes table position update messages
-func (h *WebSocketHandler) handleTableUpdate(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) {
-	var payload websocketPkg.TablePayload
+// sendError tells a client that a message it sent was rejected
+func (h *WebSocketHandler) sendError(client *websocketPkg.Client, message, code string) {
+	errorMessage, err := websocketPkg.NewWebSocketMessage(
+		websocketPkg.MessageTypeError,
+		websocketPkg.ErrorPayload{Message: message, Code: code},
+		client.UserID,
+		client.ProjectID,
+	)
+	if err != nil {
+		log.Printf("Error creating error message: %v", err)
+		return
+	}
+	h.hub.SendToClient(client, errorMessage)
+}
+
+// handleCanvasChunk collects chunks of a large canvas update and processes the
+// update once all chunks have arrived
+func (h *WebSocketHandler) handleCanvasChunk(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) {
+	if client.CanvasChunks == nil {
+		return
+	}
+
+	var payload websocketPkg.CanvasChunkPayload
 	if err := message.UnmarshalData(&payload); err != nil {
-		log.Printf("Error unmarshaling table payload: %v", err)
+		log.Printf("Error unmarshaling canvas chunk payload: %v", err)
 		return
 	}
 
-	log.Printf("Table position update received: table_id=%s, position=(%f, %f)",
-		payload.TableID, payload.X, payload.Y)
+	canvasData, complete, err := client.CanvasChunks.Add(payload)
+	if err != nil {
+		log.Printf("Error assembling canvas chunk %s from client %s: %v", payload.ChunkID, client.UserID, err)
+		return
+	}
+	if !complete {
+		return
+	}
 
-	// Update table position in database asynchronously
-	go func() {
-		if err := h.updateTablePosition(client.ProjectID, payload.TableID, payload.X, payload.Y, client.UserID); err != nil {
-			log.Printf("Error updating table position: %v", err)
-		}
-	}()
+	canvasMessage, err := websocketPkg.NewWebSocketMessage(
+		websocketPkg.MessageTypeCanvasUpdated,
+		websocketPkg.CanvasUpdatedPayload{CanvasData: canvasData},
+		client.UserID,
+		client.ProjectID,
+	)
+	if err != nil {
+		log.Printf("Error creating canvas message: %v", err)
+		return
+	}
 
-	// Broadcast to other clients in the project (exclude sender for position updates)
-	h.hub.BroadcastToProject(client.ProjectID, message, client)
+	h.handleCanvasUpdate(client, canvasMessage)
+}
+
+// handleTableUpdate relays the position of a table being dragged to the other
+// clients of the project, throttled by the hub. Positions mid-drag are not
+// saved; the drag is saved when it is committed with table_moved, or once the
+// table has rested for a while.
+func (h *WebSocketHandler) handleTableUpdate(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) {
+	position, ok := h.tablePosition(client, message)
+	if !ok {
+		return
+	}
+
+	dragMessage, err := websocketPkg.NewWebSocketMessage(websocketPkg.MessageTypeTableUpdated, position, client.UserID, client.ProjectID)
+	if err != nil {
+		log.Printf("Error creating table drag message: %v", err)
+		return
+	}
+	h.hub.RelayTableDrag(client, position.TableID, dragMessage)
+
+	if client.TableDrags != nil {
+		client.TableDrags.Schedule(position.TableID, func() {
+			h.submitWrite(client, func() { h.commitTablePosition(client, position) })
+		})
+	}
 }
 
-// handleTableMove processes table position move messages (visual only, no activity)
+// handleTableMove commits the final position of a dragged table
 func (h *WebSocketHandler) handleTableMove(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) {
+	position, ok := h.tablePosition(client, message)
+	if !ok {
+		return
+	}
+
+	if client.TableDrags != nil {
+		client.TableDrags.Cancel(position.TableID)
+	}
+
+	h.submitWrite(client, func() { h.commitTablePosition(client, position) })
+}
+
+// tablePosition reads the table and position of a table message. Only these
+// are relayed, whatever else the client sent.
+func (h *WebSocketHandler) tablePosition(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) (websocketPkg.TablePayload, bool) {
 	var payload websocketPkg.TablePayload
+	if err := message.UnmarshalData(&payload); err != nil || payload.TableID == uuid.Nil {
+		h.sendError(client, "Table positions hold a table_id, x and y", "invalid_table_position")
+		return websocketPkg.TablePayload{}, false
+	}
+	return websocketPkg.TablePayload{TableID: payload.TableID, X: payload.X, Y: payload.Y}, true
+}
+
+// commitTablePosition saves the position of a table and then broadcasts it to
+// the other clients of the project as a table_moved
+func (h *WebSocketHandler) commitTablePosition(client *websocketPkg.Client, position websocketPkg.TablePayload) {
+	err := h.updateTablePosition(client.ProjectID, position.TableID, position.X, position.Y, client.UserID)
+	switch {
+	case err == nil:
+	case errors.Is(err, services.ErrTableNotFound):
+		h.sendError(client, "Table not found", "table_not_found")
+		return
+	case errors.Is(err, services.ErrForbidden):
+		h.sendError(client, "You don't have permission to modify this project", "forbidden")
+		return
+	default:
+		log.Printf("Error updating table position: %v", err)
+		h.sendError(client, "Table position could not be saved", "table_position_failed")
+		return
+	}
+
+	movedMessage, err := websocketPkg.NewWebSocketMessage(websocketPkg.MessageTypeTableMoved, position, client.UserID, client.ProjectID)
+	if err != nil {
+		log.Printf("Error creating table moved message: %v", err)
+		return
+	}
+	// Broadcast to other clients in the project (exclude sender for position updates)
+	h.hub.BroadcastToProject(client.ProjectID, movedMessage, client)
+}
+
+// handleFieldsReorder saves a reorder of a table's fields. The service
+// broadcasts the resulting order to everyone, the sender included. It runs
+// before the client's next message is read, so reorders from one client are
+// applied in the order they were sent.
+func (h *WebSocketHandler) handleFieldsReorder(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) {
+	var payload websocketPkg.FieldsReorderedPayload
 	if err := message.UnmarshalData(&payload); err != nil {
-		log.Printf("Error unmarshaling table move payload: %v", err)
+		log.Printf("Error unmarshaling fields reorder payload: %v", err)
 		return
 	}
 
-	log.Printf("Table position move received: table_id=%s, position=(%f, %f)",
-		payload.TableID, payload.X, payload.Y)
+	fieldPositions := make(map[uuid.UUID]int, len(payload.Fields))
+	for _, field := range payload.Fields {
+		fieldPositions[field.FieldID] = field.Position
+	}
 
-	// Update table position in database asynchronously
-	go func() {
-		if err := h.updateTablePosition(client.ProjectID, payload.TableID, payload.X, payload.Y, client.UserID); err != nil {
-			log.Printf("Error updating table position: %v", err)
-		}
-	}()
+	order, err := h.fieldService.ReorderFields(payload.TableID, fieldPositions, &payload.BaseSequence, client.UserID)
+	switch {
+	case err == nil:
+	case errors.Is(err, services.ErrVersionConflict):
+		// Another reorder was confirmed first. The sender missed it, so it gets
+		// the order that reorder left; everyone else has it already.
+		h.sendFieldOrder(client, order)
+	case errors.Is(err, services.ErrTableNotFound), errors.Is(err, services.ErrFieldNotFound), errors.Is(err, services.ErrInvalidInput):
+		h.sendError(client, "Invalid field positions", "invalid_reorder")
+	case errors.Is(err, services.ErrForbidden):
+		h.sendError(client, "You don't have permission to modify this project", "forbidden")
+	default:
+		log.Printf("Error reordering fields of table %s: %v", payload.TableID, err)
+		h.sendError(client, "Fields could not be reordered", "reorder_failed")
+	}
+}
+
+// handleChatMessage saves a chat message, which the service delivers to
+// everyone in the project, the sender included
+func (h *WebSocketHandler) handleChatMessage(client *websocketPkg.Client, message *websocketPkg.WebSocketMessage) {
+	var payload websocketPkg.ChatSendPayload
+	if err := message.UnmarshalData(&payload); err != nil {
+		log.Printf("Error unmarshaling chat payload: %v", err)
+		return
+	}
 
-	// Broadcast visual position update to other clients only (exclude sender, no activity entries)
-	h.hub.BroadcastToProject(client.ProjectID, message, client)
+	_, err := h.chatService.SendMessage(client.ProjectID, payload.Content, client.UserID)
+	switch {
+	case err == nil:
+	case errors.Is(err, services.ErrInvalidInput):
+		h.sendError(client, fmt.Sprintf("Chat messages hold 1 to %d characters", services.MaxChatMessageLength), "invalid_chat_message")
+	case errors.Is(err, services.ErrForbidden):
+		h.sendError(client, "You don't have access to this project", "forbidden")
+	default:
+		log.Printf("Error sending chat message of client %s: %v", client.UserID, err)
+		h.sendError(client, "Message could not be sent", "chat_failed")
+	}
+}
+
+// sendFieldOrder sends a client the order of a table's fields, numbered with
+// the sequence of the reorder that left it
+func (h *WebSocketHandler) sendFieldOrder(client *websocketPkg.Client, order *services.FieldOrder) {
+	payload := websocketPkg.FieldsReorderedPayload{
+		TableID: order.TableID,
+		Fields:  make([]websocketPkg.FieldPositionPayload, len(order.Fields)),
+	}
+	for i, field := range order.Fields {
+		payload.Fields[i] = websocketPkg.FieldPositionPayload{FieldID: field.ID, Position: field.Position}
+	}
+
+	orderMessage, err := websocketPkg.NewWebSocketMessage(websocketPkg.MessageTypeFieldsReordered, payload, client.UserID, client.ProjectID)
+	if err != nil {
+		log.Printf("Error creating fields reordered message: %v", err)
+		return
+	}
+	orderMessage.Sequence = order.Sequence
+	h.hub.SendToClient(client, orderMessage)
 }
 
 // updateProjectCanvasData updates the canvas data in the database
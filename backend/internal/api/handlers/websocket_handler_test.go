@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Bug-Bugger/ezmodel/internal/canvas"
 	"github.com/Bug-Bugger/ezmodel/internal/config"
 	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
@@ -17,45 +18,54 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type WebSocketHandlerTestSuite struct {
 	suite.Suite
-	cfg              *config.Config
-	handler          *WebSocketHandler
-	hub              *websocketPkg.Hub
-	mockJWTService   *mockService.MockJWTService
-	mockUserService  *mockService.MockUserService
-	mockProjService  *mockService.MockProjectService
-	mockTableService *mockService.MockTableService
-	upgrader         websocket.Upgrader
+	cfg                *config.Config
+	handler            *WebSocketHandler
+	hub                *websocketPkg.Hub
+	mockJWTService     *mockService.MockJWTService
+	mockSessionService *mockService.MockUserSessionService
+	mockUserService    *mockService.MockUserService
+	mockProjService    *mockService.MockProjectService
+	mockTableService   *mockService.MockTableService
+	mockFieldService   *mockService.MockFieldService
+	mockChatService    *mockService.MockChatService
+	upgrader           websocket.Upgrader
 }
 
 func (suite *WebSocketHandlerTestSuite) SetupTest() {
 	suite.hub = websocketPkg.NewHub()
 	go suite.hub.Run()
 
-	suite.cfg = &config.Config{
-		AllowedOrigins: []string{
-			"http://localhost:5173",
-			"http://127.0.0.1:5173",
-			"http://localhost:4173",
-		},
+	suite.cfg = config.New()
+	suite.cfg.AllowedOrigins = []string{
+		"http://localhost:5173",
+		"http://127.0.0.1:5173",
+		"http://localhost:4173",
 	}
 
 	suite.mockJWTService = new(mockService.MockJWTService)
+	suite.mockSessionService = new(mockService.MockUserSessionService)
 	suite.mockUserService = new(mockService.MockUserService)
 	suite.mockProjService = new(mockService.MockProjectService)
 	suite.mockTableService = new(mockService.MockTableService)
+	suite.mockFieldService = new(mockService.MockFieldService)
+	suite.mockChatService = new(mockService.MockChatService)
 
 	suite.handler = NewWebSocketHandler(
 		suite.cfg,
 		suite.hub,
 		suite.mockJWTService,
+		suite.mockSessionService,
 		suite.mockUserService,
 		suite.mockProjService,
 		suite.mockTableService,
+		suite.mockFieldService,
+		suite.mockChatService,
 	)
 
 	suite.upgrader = websocket.Upgrader{
@@ -75,6 +85,15 @@ func TestWebSocketHandlerSuite(t *testing.T) {
 	suite.Run(t, new(WebSocketHandlerTestSuite))
 }
 
+// Test the canvas limit - Chunked and whole updates share CANVAS_MAX_SIZE
+func (suite *WebSocketHandlerTestSuite) TestCanvasLimitFromCanvasConfig() {
+	suite.Equal(suite.cfg.Canvas.MaxSize, suite.handler.maxCanvasSize)
+
+	suite.cfg.Canvas.MaxSize = 0
+	handler := NewWebSocketHandler(suite.cfg, suite.hub, nil, nil, nil, nil, nil, nil, nil)
+	suite.Equal(canvas.DefaultMaxSize, handler.maxCanvasSize)
+}
+
 // Test invalid project ID format
 func (suite *WebSocketHandlerTestSuite) TestHandleWebSocket_InvalidProjectID() {
 	req := httptest.NewRequest(http.MethodGet, "/projects/invalid-uuid/collaborate", nil)
@@ -282,7 +301,7 @@ func (suite *WebSocketHandlerTestSuite) TestHandleWebSocket_ProjectNotFound() {
 	}
 	suite.mockJWTService.On("ValidateToken", token).Return(claims, nil)
 	suite.mockUserService.On("GetUserByID", userID).Return(user, nil)
-	suite.mockProjService.On("GetProjectByID", projectID).Return(nil, services.ErrProjectNotFound)
+	suite.mockProjService.On("GetMemberRole", projectID, userID).Return("", services.ErrProjectNotFound)
 
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -326,17 +345,12 @@ func (suite *WebSocketHandlerTestSuite) TestHandleWebSocket_ProjectNotFound() {
 func (suite *WebSocketHandlerTestSuite) TestHandleWebSocket_AccessDenied() {
 	projectID := uuid.New()
 	userID := uuid.New()
-	ownerID := uuid.New()
 	token := "valid-token"
 
 	// Setup test data
 	user := testutil.CreateTestUser()
 	user.ID = userID
 
-	project := testutil.CreateTestProject(ownerID)
-	project.ID = projectID
-	project.Collaborators = []models.User{} // User is not a collaborator
-
 	// Setup mocks
 	claims := &services.CustomClaims{
 		UserID: userID,
@@ -344,7 +358,7 @@ func (suite *WebSocketHandlerTestSuite) TestHandleWebSocket_AccessDenied() {
 	}
 	suite.mockJWTService.On("ValidateToken", token).Return(claims, nil)
 	suite.mockUserService.On("GetUserByID", userID).Return(user, nil)
-	suite.mockProjService.On("GetProjectByID", projectID).Return(project, nil)
+	suite.mockProjService.On("GetMemberRole", projectID, userID).Return("", services.ErrForbidden) // User is not a collaborator
 
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -393,16 +407,13 @@ func (suite *WebSocketHandlerTestSuite) TestHandleWebSocket_TokenFromAuthorizati
 	user := testutil.CreateTestUser()
 	user.ID = userID
 
-	project := testutil.CreateTestProject(userID)
-	project.ID = projectID
-
 	claims := &services.CustomClaims{
 		UserID: userID,
 		Email:  user.Email,
 	}
 	suite.mockJWTService.On("ValidateToken", token).Return(claims, nil)
 	suite.mockUserService.On("GetUserByID", userID).Return(user, nil)
-	suite.mockProjService.On("GetProjectByID", projectID).Return(project, nil)
+	suite.mockProjService.On("GetMemberRole", projectID, userID).Return(models.ProjectRoleOwner, nil)
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		r = suite.addURLParam(r, "project_id", projectID.String())
@@ -445,16 +456,13 @@ func (suite *WebSocketHandlerTestSuite) TestHandleWebSocket_TokenFromCookie() {
 	user := testutil.CreateTestUser()
 	user.ID = userID
 
-	project := testutil.CreateTestProject(userID)
-	project.ID = projectID
-
 	claims := &services.CustomClaims{
 		UserID: userID,
 		Email:  user.Email,
 	}
 	suite.mockJWTService.On("ValidateToken", token).Return(claims, nil)
 	suite.mockUserService.On("GetUserByID", userID).Return(user, nil)
-	suite.mockProjService.On("GetProjectByID", projectID).Return(project, nil)
+	suite.mockProjService.On("GetMemberRole", projectID, userID).Return(models.ProjectRoleOwner, nil)
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		r = suite.addURLParam(r, "project_id", projectID.String())
@@ -493,17 +501,12 @@ func (suite *WebSocketHandlerTestSuite) TestHandleWebSocket_TokenFromCookie() {
 func (suite *WebSocketHandlerTestSuite) TestHandleWebSocket_SuccessAsCollaborator() {
 	projectID := uuid.New()
 	userID := uuid.New()
-	ownerID := uuid.New()
 	token := "valid-token"
 
 	// Setup test data
 	user := testutil.CreateTestUser()
 	user.ID = userID
 
-	project := testutil.CreateTestProject(ownerID)
-	project.ID = projectID
-	project.Collaborators = []models.User{*user} // User is a collaborator
-
 	// Setup mocks
 	claims := &services.CustomClaims{
 		UserID: userID,
@@ -511,7 +514,7 @@ func (suite *WebSocketHandlerTestSuite) TestHandleWebSocket_SuccessAsCollaborato
 	}
 	suite.mockJWTService.On("ValidateToken", token).Return(claims, nil)
 	suite.mockUserService.On("GetUserByID", userID).Return(user, nil)
-	suite.mockProjService.On("GetProjectByID", projectID).Return(project, nil)
+	suite.mockProjService.On("GetMemberRole", projectID, userID).Return(models.ProjectRoleEditor, nil) // User is a collaborator
 
 	// Create test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -545,6 +548,7 @@ func (suite *WebSocketHandlerTestSuite) TestHandleWebSocket_SuccessAsCollaborato
 	assert.Equal(suite.T(), "auth", response["type"])
 	data := response["data"].(map[string]interface{})
 	assert.Equal(suite.T(), "Authentication successful", data["message"])
+	assert.Equal(suite.T(), models.ProjectRoleEditor, data["role"])
 
 	// Wait a moment for client registration
 	time.Sleep(50 * time.Millisecond)
@@ -554,6 +558,177 @@ func (suite *WebSocketHandlerTestSuite) TestHandleWebSocket_SuccessAsCollaborato
 	suite.mockProjService.AssertExpectations(suite.T())
 }
 
+// Test a reorder that lost to one confirmed after its base sequence
+func (suite *WebSocketHandlerTestSuite) TestFieldsReordered_ConflictSendsConfirmedOrder() {
+	projectID := uuid.New()
+	tableID := uuid.New()
+	user := testutil.CreateTestUser()
+	first := &models.Field{ID: uuid.New(), TableID: tableID, Position: 1}
+	second := &models.Field{ID: uuid.New(), TableID: tableID, Position: 2}
+	confirmed := &services.FieldOrder{TableID: tableID, Fields: []*models.Field{first, second}, Sequence: 8}
+	baseSequence := int64(5)
+
+	suite.mockJWTService.On("ValidateToken", "valid-token").Return(&services.CustomClaims{UserID: user.ID}, nil)
+	suite.mockUserService.On("GetUserByID", user.ID).Return(user, nil)
+	suite.mockProjService.On("GetMemberRole", projectID, user.ID).Return(models.ProjectRoleOwner, nil)
+	suite.mockFieldService.On("ReorderFields", tableID, map[uuid.UUID]int{second.ID: 1, first.ID: 2}, &baseSequence, user.ID).
+		Return(confirmed, services.ErrVersionConflict)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = suite.addURLParam(r, "project_id", projectID.String())
+		suite.handler.HandleWebSocket(w, r)
+	}))
+	defer server.Close()
+
+	ws, err := suite.dialWebSocket("ws"+server.URL[4:], nil)
+	suite.Require().NoError(err)
+	defer ws.Close()
+
+	suite.Require().NoError(ws.WriteJSON(map[string]interface{}{"type": "auth", "data": map[string]interface{}{"token": "valid-token"}}))
+	var authResponse websocketPkg.WebSocketMessage
+	suite.Require().NoError(ws.ReadJSON(&authResponse))
+	suite.Require().Equal(websocketPkg.MessageTypeAuth, authResponse.Type)
+
+	suite.Require().NoError(ws.WriteJSON(map[string]interface{}{
+		"type": "fields_reordered",
+		"data": websocketPkg.FieldsReorderedPayload{
+			TableID:      tableID,
+			Fields:       []websocketPkg.FieldPositionPayload{{FieldID: second.ID, Position: 1}, {FieldID: first.ID, Position: 2}},
+			BaseSequence: baseSequence,
+		},
+	}))
+
+	// Skip presence messages until the order arrives
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var message websocketPkg.WebSocketMessage
+	for message.Type != websocketPkg.MessageTypeFieldsReordered {
+		suite.Require().NoError(ws.ReadJSON(&message))
+	}
+
+	var payload websocketPkg.FieldsReorderedPayload
+	suite.Require().NoError(message.UnmarshalData(&payload))
+	suite.Equal(int64(8), message.Sequence)
+	suite.Equal([]websocketPkg.FieldPositionPayload{{FieldID: first.ID, Position: 1}, {FieldID: second.ID, Position: 2}}, payload.Fields)
+	suite.mockFieldService.AssertExpectations(suite.T())
+}
+
+// Test chat_message - A rejected message is answered with an error
+func (suite *WebSocketHandlerTestSuite) TestChatMessage_InvalidSendsError() {
+	projectID := uuid.New()
+	user := testutil.CreateTestUser()
+
+	suite.mockJWTService.On("ValidateToken", "valid-token").Return(&services.CustomClaims{UserID: user.ID}, nil)
+	suite.mockUserService.On("GetUserByID", user.ID).Return(user, nil)
+	suite.mockProjService.On("GetMemberRole", projectID, user.ID).Return(models.ProjectRoleOwner, nil)
+	suite.mockChatService.On("SendMessage", projectID, "   ", user.ID).Return(nil, services.ErrInvalidInput)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = suite.addURLParam(r, "project_id", projectID.String())
+		suite.handler.HandleWebSocket(w, r)
+	}))
+	defer server.Close()
+
+	ws, err := suite.dialWebSocket("ws"+server.URL[4:], nil)
+	suite.Require().NoError(err)
+	defer ws.Close()
+
+	suite.Require().NoError(ws.WriteJSON(map[string]interface{}{"type": "auth", "data": map[string]interface{}{"token": "valid-token"}}))
+	var authResponse websocketPkg.WebSocketMessage
+	suite.Require().NoError(ws.ReadJSON(&authResponse))
+	suite.Require().Equal(websocketPkg.MessageTypeAuth, authResponse.Type)
+
+	suite.Require().NoError(ws.WriteJSON(map[string]interface{}{"type": "chat_message", "data": websocketPkg.ChatSendPayload{Content: "   "}}))
+
+	// Skip presence messages until the error arrives
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var message websocketPkg.WebSocketMessage
+	for message.Type != websocketPkg.MessageTypeError {
+		suite.Require().NoError(ws.ReadJSON(&message))
+	}
+
+	var payload websocketPkg.ErrorPayload
+	suite.Require().NoError(message.UnmarshalData(&payload))
+	suite.Equal("invalid_chat_message", payload.Code)
+	suite.mockChatService.AssertExpectations(suite.T())
+}
+
+// Test schema changes the server did not make are rejected rather than relayed
+func (suite *WebSocketHandlerTestSuite) TestForgedSchemaChange_Rejected() {
+	projectID := uuid.New()
+	user := testutil.CreateTestUser()
+
+	suite.mockJWTService.On("ValidateToken", "valid-token").Return(&services.CustomClaims{UserID: user.ID}, nil)
+	suite.mockUserService.On("GetUserByID", user.ID).Return(user, nil)
+	suite.mockProjService.On("GetMemberRole", projectID, user.ID).Return(models.ProjectRoleOwner, nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = suite.addURLParam(r, "project_id", projectID.String())
+		suite.handler.HandleWebSocket(w, r)
+	}))
+	defer server.Close()
+
+	ws, err := suite.dialWebSocket("ws"+server.URL[4:], nil)
+	suite.Require().NoError(err)
+	defer ws.Close()
+
+	suite.Require().NoError(ws.WriteJSON(map[string]interface{}{"type": "auth", "data": map[string]interface{}{"token": "valid-token"}}))
+	var authResponse websocketPkg.WebSocketMessage
+	suite.Require().NoError(ws.ReadJSON(&authResponse))
+	suite.Require().Equal(websocketPkg.MessageTypeAuth, authResponse.Type)
+
+	suite.Require().NoError(ws.WriteJSON(map[string]interface{}{"type": "table_created", "data": websocketPkg.TablePayload{TableID: uuid.New(), Name: "forged"}}))
+
+	// Skip presence messages until the error arrives
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var message websocketPkg.WebSocketMessage
+	for message.Type != websocketPkg.MessageTypeError {
+		suite.Require().NoError(ws.ReadJSON(&message))
+	}
+
+	var payload websocketPkg.ErrorPayload
+	suite.Require().NoError(message.UnmarshalData(&payload))
+	suite.Equal("unknown_message_type", payload.Code)
+}
+
+// Test a viewer's changes are rejected rather than relayed
+func (suite *WebSocketHandlerTestSuite) TestViewer_ChangesRejected() {
+	projectID := uuid.New()
+	user := testutil.CreateTestUser()
+
+	suite.mockJWTService.On("ValidateToken", "valid-token").Return(&services.CustomClaims{UserID: user.ID}, nil)
+	suite.mockUserService.On("GetUserByID", user.ID).Return(user, nil)
+	suite.mockProjService.On("GetMemberRole", projectID, user.ID).Return(models.ProjectRoleViewer, nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = suite.addURLParam(r, "project_id", projectID.String())
+		suite.handler.HandleWebSocket(w, r)
+	}))
+	defer server.Close()
+
+	ws, err := suite.dialWebSocket("ws"+server.URL[4:], nil)
+	suite.Require().NoError(err)
+	defer ws.Close()
+
+	suite.Require().NoError(ws.WriteJSON(map[string]interface{}{"type": "auth", "data": map[string]interface{}{"token": "valid-token"}}))
+	var authResponse websocketPkg.WebSocketMessage
+	suite.Require().NoError(ws.ReadJSON(&authResponse))
+	suite.Require().Equal(websocketPkg.MessageTypeAuth, authResponse.Type)
+
+	suite.Require().NoError(ws.WriteJSON(map[string]interface{}{"type": "table_moved", "data": websocketPkg.TablePayload{TableID: uuid.New(), X: 1, Y: 2}}))
+
+	// Skip presence messages until the error arrives
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var message websocketPkg.WebSocketMessage
+	for message.Type != websocketPkg.MessageTypeError {
+		suite.Require().NoError(ws.ReadJSON(&message))
+	}
+
+	var payload websocketPkg.ErrorPayload
+	suite.Require().NoError(message.UnmarshalData(&payload))
+	suite.Equal("forbidden", payload.Code)
+	suite.mockTableService.AssertNotCalled(suite.T(), "UpdateTablePosition", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Helper method to dial WebSocket connections with default allowed origin
 func (suite *WebSocketHandlerTestSuite) dialWebSocket(wsURL string, headers http.Header) (*websocket.Conn, error) {
 	if headers == nil {
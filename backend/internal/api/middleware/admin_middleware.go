@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+)
+
+// AdminMiddleware restricts routes to accounts with the admin role or listed in
+// ADMIN_USER_IDS, and audits what admins change while impersonating a user
+type AdminMiddleware struct {
+	adminUserService services.AdminUserServiceInterface
+	trustProxy       bool
+}
+
+func NewAdminMiddleware(cfg *config.Config, adminUserService services.AdminUserServiceInterface) *AdminMiddleware {
+	return &AdminMiddleware{
+		adminUserService: adminUserService,
+		trustProxy:       cfg.RateLimit.TrustProxy,
+	}
+}
+
+// RequireAdmin must run after Authenticate so the user ID is in the request context
+func (m *AdminMiddleware) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userIDStr, ok := GetUserIDFromContext(r.Context())
+		if !ok {
+			responses.RespondWithError(w, http.StatusUnauthorized, "User context not found")
+			return
+		}
+
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusForbidden, "Admin access required")
+			return
+		}
+
+		isAdmin, err := m.adminUserService.IsAdmin(userID)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+		if !isAdmin {
+			responses.RespondWithError(w, http.StatusForbidden, "Admin access required")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AuditImpersonation writes an admin audit entry for every request other than
+// a read made in an impersonation session, before the request is served so no
+// change goes unrecorded. It must run after Authenticate.
+func (m *AdminMiddleware) AuditImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		impersonatorID, impersonated := GetImpersonatorIDFromContext(r.Context())
+		if !impersonated || isSafeMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userIDStr, _ := GetUserIDFromContext(r.Context())
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			responses.RespondWithError(w, http.StatusUnauthorized, "User context not found")
+			return
+		}
+		if err := m.adminUserService.RecordImpersonatedRequest(impersonatorID, userID, r.Method+" "+r.URL.Path, utils.ClientIP(r, m.trustProxy)); err != nil {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
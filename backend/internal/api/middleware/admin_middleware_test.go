@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	adminID := uuid.New()
+	regularID := uuid.New()
+	failingID := uuid.New()
+
+	adminUserService := new(mockService.MockAdminUserService)
+	adminUserService.On("IsAdmin", adminID).Return(true, nil)
+	adminUserService.On("IsAdmin", regularID).Return(false, nil)
+	adminUserService.On("IsAdmin", failingID).Return(false, assert.AnError)
+	m := NewAdminMiddleware(config.New(), adminUserService)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		userID         string
+		expectedStatus int
+	}{
+		{name: "admin user", userID: adminID.String(), expectedStatus: http.StatusOK},
+		{name: "regular user", userID: regularID.String(), expectedStatus: http.StatusForbidden},
+		{name: "lookup error", userID: failingID.String(), expectedStatus: http.StatusInternalServerError},
+		{name: "malformed user ID", userID: "not-a-uuid", expectedStatus: http.StatusForbidden},
+		{name: "no user context", userID: "", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/ws/stats", nil)
+			if tt.userID != "" {
+				req = req.WithContext(context.WithValue(req.Context(), userIDKey, tt.userID))
+			}
+			w := httptest.NewRecorder()
+
+			m.RequireAdmin(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestAuditImpersonation(t *testing.T) {
+	adminID := uuid.New()
+	userID := uuid.New()
+
+	adminUserService := new(mockService.MockAdminUserService)
+	adminUserService.On("RecordImpersonatedRequest", adminID, userID, "DELETE /api/projects/1", "192.0.2.1").Return(nil).Once()
+	m := NewAdminMiddleware(config.New(), adminUserService)
+
+	served := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served++
+		w.WriteHeader(http.StatusOK)
+	})
+
+	request := func(method string, impersonated bool) *http.Request {
+		req := httptest.NewRequest(method, "/api/projects/1", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		ctx := context.WithValue(req.Context(), userIDKey, userID.String())
+		if impersonated {
+			ctx = context.WithValue(ctx, impersonatorIDKey, adminID)
+		}
+		return req.WithContext(ctx)
+	}
+
+	for _, req := range []*http.Request{request(http.MethodDelete, true), request(http.MethodGet, true), request(http.MethodDelete, false)} {
+		w := httptest.NewRecorder()
+		m.AuditImpersonation(next).ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, 3, served)
+	adminUserService.AssertExpectations(t)
+
+	// Changes that cannot be audited are not made
+	adminUserService.On("RecordImpersonatedRequest", adminID, userID, "DELETE /api/projects/1", "192.0.2.1").Return(assert.AnError)
+	w := httptest.NewRecorder()
+	m.AuditImpersonation(next).ServeHTTP(w, request(http.MethodDelete, true))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, 3, served)
+}
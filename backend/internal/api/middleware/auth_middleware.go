@@ -2,30 +2,50 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
 const (
 	authorizationHeader = "Authorization"
 	userIDKey           = "userID"
+	apiTokenScopesKey   = "apiTokenScopes"
+	apiTokenProjectKey  = "apiTokenProject"
+	sessionIDKey        = "sessionID"
+	impersonatorIDKey   = "impersonatorID"
 )
 
 type AuthMiddleware struct {
-	jwtService services.JWTServiceInterface
+	jwtService         services.JWTServiceInterface
+	apiTokenService    services.APITokenServiceInterface
+	userSessionService services.UserSessionServiceInterface
 }
 
-func NewAuthMiddleware(jwtService services.JWTServiceInterface) *AuthMiddleware {
+func NewAuthMiddleware(jwtService services.JWTServiceInterface, apiTokenService services.APITokenServiceInterface, userSessionService services.UserSessionServiceInterface) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtService: jwtService,
+		jwtService:         jwtService,
+		apiTokenService:    apiTokenService,
+		userSessionService: userSessionService,
 	}
 }
 
 func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// API tokens are sent explicitly, so they take precedence over a session cookie
+		if apiToken, ok := bearerAPIToken(r); ok {
+			m.authenticateAPIToken(w, r, next, apiToken)
+			return
+		}
+
 		var token string
 
 		// Try to get token from cookie first (preferred method)
@@ -60,13 +80,173 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			return
 		}
 
-		// Set the userID in the request context
+		// Tokens of a revoked session stop working before they expire
+		var session *models.UserSession
+		if claims.SessionID != uuid.Nil {
+			if session, err = m.userSessionService.ValidateSession(claims.SessionID); err != nil {
+				if errors.Is(err, services.ErrUserSessionRevoked) {
+					responses.RespondWithError(w, http.StatusUnauthorized, "Session has been revoked")
+				} else {
+					responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+				}
+				return
+			}
+		}
+
+		// Set the userID and session in the request context
 		ctx := context.WithValue(r.Context(), userIDKey, claims.UserID.String())
+		errorreport.SetUser(ctx, claims.UserID.String())
+		if claims.SessionID != uuid.Nil {
+			ctx = context.WithValue(ctx, sessionIDKey, claims.SessionID)
+		}
+		if session != nil && session.ImpersonatorID != nil {
+			ctx = context.WithValue(ctx, impersonatorIDKey, *session.ImpersonatorID)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// authenticateAPIToken validates an API token and sets the owner and the token scopes in the request context
+func (m *AuthMiddleware) authenticateAPIToken(w http.ResponseWriter, r *http.Request, next http.Handler, plaintext string) {
+	token, err := m.apiTokenService.AuthenticateToken(plaintext)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAPIToken) {
+			responses.RespondWithError(w, http.StatusUnauthorized, "Invalid API token")
+		} else {
+			responses.RespondWithError(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), userIDKey, token.UserID.String())
+	errorreport.SetUser(ctx, token.UserID.String())
+	ctx = context.WithValue(ctx, apiTokenScopesKey, token.ScopeList())
+	if token.ProjectID != nil {
+		ctx = context.WithValue(ctx, apiTokenProjectKey, *token.ProjectID)
+	}
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// RequireScope limits API token requests to tokens holding readScope for safe
+// methods and writeScope for everything else. The write scope implies read.
+// Session-authenticated requests are not affected.
+func (m *AuthMiddleware) RequireScope(readScope, writeScope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scopes, isAPIToken := GetAPITokenScopesFromContext(r.Context())
+			if !isAPIToken {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed := slices.Contains(scopes, writeScope)
+			if !allowed && isSafeMethod(r.Method) {
+				allowed = slices.Contains(scopes, readScope)
+			}
+			if !allowed {
+				responses.RespondWithError(w, http.StatusForbidden, "API token is missing the required scope")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession rejects API token requests, for routes that manage accounts and credentials
+func (m *AuthMiddleware) RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, isAPIToken := GetAPITokenScopesFromContext(r.Context()); isAPIToken {
+			responses.RespondWithError(w, http.StatusForbidden, "API tokens cannot access this endpoint")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RejectImpersonation rejects sessions of admins impersonating the user, for
+// routes that mint credentials, change the password, export the user's data or
+// spend their money
+func (m *AuthMiddleware) RejectImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, impersonated := GetImpersonatorIDFromContext(r.Context()); impersonated {
+			responses.RespondWithError(w, http.StatusForbidden, "Impersonation sessions cannot access this endpoint")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireTokenProject rejects project-restricted API tokens used for another project.
+// It must be mounted below the route that defines the project_id URL parameter.
+func (m *AuthMiddleware) RequireTokenProject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		projectID, restricted := r.Context().Value(apiTokenProjectKey).(uuid.UUID)
+		if restricted && chi.URLParam(r, "project_id") != projectID.String() {
+			responses.RespondWithError(w, http.StatusForbidden, "API token is not valid for this project")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RejectProjectTokens rejects project-restricted API tokens on routes that span projects
+func (m *AuthMiddleware) RejectProjectTokens(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, restricted := r.Context().Value(apiTokenProjectKey).(uuid.UUID); restricted {
+			responses.RespondWithError(w, http.StatusForbidden, "API token is not valid for this endpoint")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bearerAPIToken returns the API token from the Authorization header, if one is present
+func bearerAPIToken(r *http.Request) (string, bool) {
+	token, found := strings.CutPrefix(r.Header.Get(authorizationHeader), "Bearer ")
+	if !found || !strings.HasPrefix(token, services.APITokenPrefix) {
+		return "", false
+	}
+	return token, true
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// GetAPITokenScopesFromContext returns the scopes of the API token that authenticated
+// the request. The boolean is false for session-authenticated requests.
+func GetAPITokenScopesFromContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(apiTokenScopesKey).([]string)
+	return scopes, ok
+}
+
+// GetAPITokenProjectFromContext returns the project a project-restricted API token
+// is limited to. The boolean is false for sessions and unrestricted tokens.
+func GetAPITokenProjectFromContext(ctx context.Context) (uuid.UUID, bool) {
+	projectID, ok := ctx.Value(apiTokenProjectKey).(uuid.UUID)
+	return projectID, ok
+}
+
 func GetUserIDFromContext(ctx context.Context) (string, bool) {
 	userID, ok := ctx.Value(userIDKey).(string)
 	return userID, ok
 }
+
+// GetSessionIDFromContext returns the sign-in session of the request.
+// The boolean is false for API tokens and for tokens issued without a session.
+func GetSessionIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	sessionID, ok := ctx.Value(sessionIDKey).(uuid.UUID)
+	return sessionID, ok
+}
+
+// GetImpersonatorIDFromContext returns the admin acting as the user in the
+// session of the request. The boolean is false outside impersonation sessions.
+func GetImpersonatorIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	impersonatorID, ok := ctx.Value(impersonatorIDKey).(uuid.UUID)
+	return impersonatorID, ok
+}
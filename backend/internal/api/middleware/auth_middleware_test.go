@@ -1,26 +1,34 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
 	"github.com/Bug-Bugger/ezmodel/internal/testutil"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type AuthMiddlewareTestSuite struct {
 	suite.Suite
-	mockJWTService *mockService.MockJWTService
-	middleware     *AuthMiddleware
+	mockJWTService      *mockService.MockJWTService
+	mockAPITokenService *mockService.MockAPITokenService
+	mockSessionService  *mockService.MockUserSessionService
+	middleware          *AuthMiddleware
 }
 
 func (suite *AuthMiddlewareTestSuite) SetupTest() {
 	suite.mockJWTService = new(mockService.MockJWTService)
-	suite.middleware = NewAuthMiddleware(suite.mockJWTService)
+	suite.mockAPITokenService = new(mockService.MockAPITokenService)
+	suite.mockSessionService = new(mockService.MockUserSessionService)
+	suite.middleware = NewAuthMiddleware(suite.mockJWTService, suite.mockAPITokenService, suite.mockSessionService)
 }
 
 func TestAuthMiddlewareSuite(t *testing.T) {
@@ -54,6 +62,58 @@ func (suite *AuthMiddlewareTestSuite) TestAuthenticate_WithCookie() {
 	suite.mockJWTService.AssertExpectations(suite.T())
 }
 
+func (suite *AuthMiddlewareTestSuite) TestAuthenticate_ActiveSession() {
+	token := "session-token"
+	userID, sessionID := uuid.New(), uuid.New()
+	claims := &services.CustomClaims{UserID: userID, SessionID: sessionID}
+
+	suite.mockJWTService.On("ValidateToken", token).Return(claims, nil)
+	suite.mockSessionService.On("ValidateSession", sessionID).Return(&models.UserSession{ID: sessionID, UserID: userID}, nil)
+
+	nextCalled := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+		ctxSessionID, ok := GetSessionIDFromContext(r.Context())
+		suite.True(ok)
+		suite.Equal(sessionID, ctxSessionID)
+		_, impersonated := GetImpersonatorIDFromContext(r.Context())
+		suite.False(impersonated)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	w := httptest.NewRecorder()
+
+	suite.middleware.Authenticate(next).ServeHTTP(w, req)
+
+	suite.True(nextCalled)
+	suite.Equal(http.StatusOK, w.Code)
+	suite.mockSessionService.AssertExpectations(suite.T())
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthenticate_RevokedSession() {
+	token := "revoked-token"
+	sessionID := uuid.New()
+	claims := &services.CustomClaims{UserID: uuid.New(), SessionID: sessionID}
+
+	suite.mockJWTService.On("ValidateToken", token).Return(claims, nil)
+	suite.mockSessionService.On("ValidateSession", sessionID).Return(nil, services.ErrUserSessionRevoked)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Fail("next handler should not be called")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	w := httptest.NewRecorder()
+
+	suite.middleware.Authenticate(next).ServeHTTP(w, req)
+
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusUnauthorized, "Session has been revoked")
+	suite.mockSessionService.AssertExpectations(suite.T())
+}
+
 func (suite *AuthMiddlewareTestSuite) TestAuthenticate_HeaderFallback() {
 	token := "header-token"
 	userID := uuid.New()
@@ -129,3 +189,197 @@ func (suite *AuthMiddlewareTestSuite) TestAuthenticate_ExpiredToken() {
 	testutil.AssertErrorResponse(suite.T(), w, http.StatusUnauthorized, "Token has expired")
 	suite.mockJWTService.AssertExpectations(suite.T())
 }
+
+func (suite *AuthMiddlewareTestSuite) TestAuthenticate_APIToken() {
+	token := "ezm_abcdefgh"
+	apiToken := &models.APIToken{UserID: uuid.New(), Scopes: services.ScopeReadProjects}
+
+	suite.mockAPITokenService.On("AuthenticateToken", token).Return(apiToken, nil)
+
+	nextCalled := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+		ctxUserID, ok := GetUserIDFromContext(r.Context())
+		suite.True(ok)
+		suite.Equal(apiToken.UserID.String(), ctxUserID)
+		scopes, isAPIToken := GetAPITokenScopesFromContext(r.Context())
+		suite.True(isAPIToken)
+		suite.Equal([]string{services.ScopeReadProjects}, scopes)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// The API token wins over a session cookie
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: "cookie-token"})
+	w := httptest.NewRecorder()
+
+	suite.middleware.Authenticate(next).ServeHTTP(w, req)
+
+	suite.True(nextCalled)
+	suite.Equal(http.StatusOK, w.Code)
+	suite.mockJWTService.AssertNotCalled(suite.T(), "ValidateToken", mock.Anything)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthenticate_InvalidAPIToken() {
+	token := "ezm_revoked"
+
+	suite.mockAPITokenService.On("AuthenticateToken", token).Return(nil, services.ErrInvalidAPIToken)
+
+	nextCalled := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	suite.middleware.Authenticate(next).ServeHTTP(w, req)
+
+	suite.False(nextCalled)
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusUnauthorized, "Invalid API token")
+}
+
+func (suite *AuthMiddlewareTestSuite) TestRequireScope() {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := suite.middleware.RequireScope(services.ScopeReadProjects, services.ScopeWriteSchema)(next)
+
+	tests := []struct {
+		name           string
+		method         string
+		scopes         []string
+		isAPIToken     bool
+		expectedStatus int
+	}{
+		{name: "session request", method: http.MethodDelete, expectedStatus: http.StatusOK},
+		{name: "read scope on GET", method: http.MethodGet, scopes: []string{services.ScopeReadProjects}, isAPIToken: true, expectedStatus: http.StatusOK},
+		{name: "read scope on POST", method: http.MethodPost, scopes: []string{services.ScopeReadProjects}, isAPIToken: true, expectedStatus: http.StatusForbidden},
+		{name: "write scope on POST", method: http.MethodPost, scopes: []string{services.ScopeWriteSchema}, isAPIToken: true, expectedStatus: http.StatusOK},
+		{name: "write scope implies read", method: http.MethodGet, scopes: []string{services.ScopeWriteSchema}, isAPIToken: true, expectedStatus: http.StatusOK},
+		{name: "no scopes", method: http.MethodGet, scopes: []string{}, isAPIToken: true, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			req := httptest.NewRequest(tt.method, "/api/projects", nil)
+			if tt.isAPIToken {
+				req = req.WithContext(context.WithValue(req.Context(), apiTokenScopesKey, tt.scopes))
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			suite.Equal(tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func (suite *AuthMiddlewareTestSuite) TestRequireSession_RejectsAPIToken() {
+	nextCalled := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tokens", nil)
+	req = req.WithContext(context.WithValue(req.Context(), apiTokenScopesKey, []string{services.ScopeWriteSchema}))
+	w := httptest.NewRecorder()
+
+	suite.middleware.RequireSession(next).ServeHTTP(w, req)
+
+	suite.False(nextCalled)
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "API tokens cannot access this endpoint")
+}
+
+func (suite *AuthMiddlewareTestSuite) TestAuthenticate_ImpersonationSession() {
+	token := "session-token"
+	userID, sessionID, adminID := uuid.New(), uuid.New(), uuid.New()
+	claims := &services.CustomClaims{UserID: userID, SessionID: sessionID}
+
+	suite.mockJWTService.On("ValidateToken", token).Return(claims, nil)
+	suite.mockSessionService.On("ValidateSession", sessionID).Return(&models.UserSession{ID: sessionID, UserID: userID, ImpersonatorID: &adminID}, nil)
+
+	var impersonatorID uuid.UUID
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		impersonatorID, _ = GetImpersonatorIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	w := httptest.NewRecorder()
+
+	suite.middleware.Authenticate(next).ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal(adminID, impersonatorID)
+}
+
+func (suite *AuthMiddlewareTestSuite) TestRejectImpersonation() {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/tokens", nil)
+	w := httptest.NewRecorder()
+	suite.middleware.RejectImpersonation(next).ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+
+	req = req.WithContext(context.WithValue(req.Context(), impersonatorIDKey, uuid.New()))
+	w = httptest.NewRecorder()
+	suite.middleware.RejectImpersonation(next).ServeHTTP(w, req)
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "Impersonation sessions cannot access this endpoint")
+}
+
+func (suite *AuthMiddlewareTestSuite) TestRequireTokenProject() {
+	projectID := uuid.New()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		urlProjectID   string
+		restricted     bool
+		expectedStatus int
+	}{
+		{name: "unrestricted token", urlProjectID: uuid.New().String(), expectedStatus: http.StatusOK},
+		{name: "matching project", urlProjectID: projectID.String(), restricted: true, expectedStatus: http.StatusOK},
+		{name: "other project", urlProjectID: uuid.New().String(), restricted: true, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("project_id", tt.urlProjectID)
+			ctx := context.WithValue(context.Background(), chi.RouteCtxKey, rctx)
+			if tt.restricted {
+				ctx = context.WithValue(ctx, apiTokenProjectKey, projectID)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/api/projects/"+tt.urlProjectID, nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			suite.middleware.RequireTokenProject(next).ServeHTTP(w, req)
+
+			suite.Equal(tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func (suite *AuthMiddlewareTestSuite) TestRejectProjectTokens() {
+	nextCalled := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/projects", nil)
+	req = req.WithContext(context.WithValue(req.Context(), apiTokenProjectKey, uuid.New()))
+	w := httptest.NewRecorder()
+
+	suite.middleware.RejectProjectTokens(next).ServeHTTP(w, req)
+
+	suite.False(nextCalled)
+	testutil.AssertErrorResponse(suite.T(), w, http.StatusForbidden, "API token is not valid for this endpoint")
+}
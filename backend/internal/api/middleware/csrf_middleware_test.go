@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSRFProtect(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.CSRF.Enabled = true
+	m := NewCSRFMiddleware(cfg)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		method         string
+		authCookie     string
+		csrfCookie     string
+		csrfHeader     string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "matching token", method: http.MethodPost, authCookie: "access_token", csrfCookie: "abc", csrfHeader: "abc", expectedStatus: http.StatusOK},
+		{name: "missing header", method: http.MethodPost, authCookie: "access_token", csrfCookie: "abc", expectedStatus: http.StatusForbidden},
+		{name: "mismatched header", method: http.MethodDelete, authCookie: "access_token", csrfCookie: "abc", csrfHeader: "xyz", expectedStatus: http.StatusForbidden},
+		{name: "missing cookie", method: http.MethodPut, authCookie: "access_token", csrfHeader: "abc", expectedStatus: http.StatusForbidden},
+		{name: "refresh cookie only", method: http.MethodPost, authCookie: "refresh_token", expectedStatus: http.StatusForbidden},
+		{name: "safe method", method: http.MethodGet, authCookie: "access_token", expectedStatus: http.StatusOK},
+		{name: "no session cookie", method: http.MethodPost, expectedStatus: http.StatusOK},
+		{name: "authorization header", method: http.MethodPost, authCookie: "access_token", authorization: "Bearer ezm_token", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/projects", nil)
+			if tt.authCookie != "" {
+				req.AddCookie(&http.Cookie{Name: tt.authCookie, Value: "session"})
+			}
+			if tt.csrfCookie != "" {
+				req.AddCookie(&http.Cookie{Name: CSRFCookieName, Value: tt.csrfCookie})
+			}
+			if tt.csrfHeader != "" {
+				req.Header.Set(CSRFHeaderName, tt.csrfHeader)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			m.Protect(next).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestCSRFProtect_Disabled(t *testing.T) {
+	m := NewCSRFMiddleware(&config.Config{})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/projects", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: "session"})
+	w := httptest.NewRecorder()
+
+	m.Protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestNewCSRFToken(t *testing.T) {
+	first, second := NewCSRFToken(), NewCSRFToken()
+
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+}
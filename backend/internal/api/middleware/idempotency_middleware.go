@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/idempotency"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	idempotencyInProgressTTL = time.Minute // Frees the key of a request that never finished, e.g. on a crash
+)
+
+// replayedHeaders are the response headers stored with a response and sent again on replay
+var replayedHeaders = []string{"Content-Type", "ETag", "Location", responses.ProjectSequenceHeader}
+
+// IdempotencyMiddleware makes retried POST requests safe. A request with an
+// Idempotency-Key header runs once; retries with the same key get its stored
+// response instead of, say, creating a second table.
+type IdempotencyMiddleware struct {
+	store            idempotency.Store
+	enabled          bool
+	ttl              time.Duration
+	maxRequestBytes  int64
+	maxResponseBytes int
+}
+
+func NewIdempotencyMiddleware(cfg *config.Config, store idempotency.Store) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		store:            store,
+		enabled:          cfg.Idempotency.Enabled,
+		ttl:              cfg.Idempotency.TTL,
+		maxRequestBytes:  cfg.Idempotency.MaxRequestBytes,
+		maxResponseBytes: cfg.Idempotency.MaxResponseBytes,
+	}
+}
+
+// Idempotent replays the stored response of POST requests whose key was seen
+// before. Keys are per user, so the middleware must run after Authenticate.
+// Uploads are streamed to their handler, which bounds them, rather than read
+// here to be fingerprinted, so they run as if they had no key.
+func (m *IdempotencyMiddleware) Idempotent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		userID, ok := GetUserIDFromContext(r.Context())
+		if !m.enabled || key == "" || !ok || r.Method != http.MethodPost || isMultipart(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			responses.RespondWithError(w, http.StatusBadRequest, "Invalid Idempotency-Key header")
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, m.maxRequestBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				responses.RespondWithError(w, http.StatusRequestEntityTooLarge, "Request body is too large")
+				return
+			}
+			responses.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		storeKey := "idempotency:" + userID + ":" + key
+		fingerprint := requestFingerprint(r, body)
+		existing, err := m.store.Claim(storeKey, &idempotency.Record{Fingerprint: fingerprint}, idempotencyInProgressTTL)
+		if err != nil {
+			// Fail open; the request runs as if it had no key
+			log.Printf("Idempotency store error: %v", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch {
+		case existing == nil:
+			m.record(w, r, next, storeKey, fingerprint)
+		case existing.Fingerprint != fingerprint:
+			responses.RespondWithError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+		case existing.Status == 0:
+			responses.RespondWithError(w, http.StatusConflict, "A request with this Idempotency-Key is still in progress")
+		default:
+			for name, values := range existing.Header {
+				w.Header()[name] = values
+			}
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(existing.Status)
+			w.Write(existing.Body)
+		}
+	})
+}
+
+// record runs the request and stores its response. Server errors and responses
+// above the size limit are not stored, so the request can be retried with the
+// same key.
+func (m *IdempotencyMiddleware) record(w http.ResponseWriter, r *http.Request, next http.Handler, storeKey, fingerprint string) {
+	recorder := &responseRecorder{ResponseWriter: w, limit: m.maxResponseBytes}
+	next.ServeHTTP(recorder, r)
+
+	if recorder.status == 0 || recorder.status >= http.StatusInternalServerError || recorder.overflow {
+		if err := m.store.Delete(storeKey); err != nil {
+			log.Printf("Idempotency store error: %v", err)
+		}
+		return
+	}
+
+	header := make(http.Header)
+	for _, name := range replayedHeaders {
+		if value := w.Header().Get(name); value != "" {
+			header.Set(name, value)
+		}
+	}
+	record := &idempotency.Record{Fingerprint: fingerprint, Status: recorder.status, Header: header, Body: recorder.body.Bytes()}
+	if err := m.store.Save(storeKey, record, m.ttl); err != nil {
+		log.Printf("Idempotency store error: %v", err)
+	}
+}
+
+// requestFingerprint identifies a request by its target and body
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	io.WriteString(hash, r.Method+" "+r.URL.RequestURI()+"\n")
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// isMultipart reports whether the request body is a multipart upload
+func isMultipart(r *http.Request) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return strings.HasPrefix(mediaType, "multipart/")
+}
+
+// responseRecorder passes a response through while keeping a copy of it, up
+// to limit bytes, beyond which it stops copying and sets overflow
+type responseRecorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (w *responseRecorder) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.overflow {
+		if w.body.Len()+len(b) > w.limit {
+			w.overflow = true
+			w.body.Reset()
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/idempotency"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestIdempotencyMiddleware() *IdempotencyMiddleware {
+	cfg := &config.Config{}
+	cfg.Idempotency.Enabled = true
+	cfg.Idempotency.TTL = time.Hour
+	cfg.Idempotency.MaxRequestBytes = 64
+	cfg.Idempotency.MaxResponseBytes = 64
+	return NewIdempotencyMiddleware(cfg, idempotency.NewMemoryStore())
+}
+
+func TestIdempotentReplaysResponse(t *testing.T) {
+	m := newTestIdempotencyMiddleware()
+	created := 0
+	handler := m.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		created++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"success":true}`))
+	}))
+	userID := uuid.New().String()
+
+	send := func(user, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/projects", strings.NewReader(body))
+		req.Header.Set(IdempotencyKeyHeader, key)
+		req = req.WithContext(context.WithValue(req.Context(), userIDKey, user))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(userID, "key-1", `{"name":"Shop"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+
+	// A retry gets the stored response without running the handler again
+	w = send(userID, "key-1", `{"name":"Shop"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"success":true}`, w.Body.String())
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, created)
+
+	// The key cannot be reused for another request
+	w = send(userID, "key-1", `{"name":"Blog"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	// Keys are per user, and requests without one always run
+	assert.Equal(t, http.StatusCreated, send(uuid.New().String(), "key-1", `{"name":"Shop"}`).Code)
+	assert.Equal(t, http.StatusCreated, send(userID, "", `{"name":"Shop"}`).Code)
+	assert.Equal(t, 3, created)
+}
+
+func TestIdempotentServerErrorsAreRetried(t *testing.T) {
+	m := newTestIdempotencyMiddleware()
+	calls := 0
+	handler := m.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	userID := uuid.New().String()
+
+	send := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/api/projects", strings.NewReader(`{}`))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		req = req.WithContext(context.WithValue(req.Context(), userIDKey, userID))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, send())
+	assert.Equal(t, http.StatusCreated, send())
+	assert.Equal(t, http.StatusCreated, send())
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotentRequestInProgress(t *testing.T) {
+	m := newTestIdempotencyMiddleware()
+	userID := uuid.New().String()
+	handler := m.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A retry arriving while the first request is still running
+		retry := httptest.NewRequest(http.MethodPost, "/api/projects", strings.NewReader(`{}`))
+		retry.Header.Set(IdempotencyKeyHeader, "key-1")
+		retry = retry.WithContext(context.WithValue(retry.Context(), userIDKey, userID))
+		retryW := httptest.NewRecorder()
+		m.Idempotent(http.NotFoundHandler()).ServeHTTP(retryW, retry)
+		assert.Equal(t, http.StatusConflict, retryW.Code)
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/projects", strings.NewReader(`{}`))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	req = req.WithContext(context.WithValue(req.Context(), userIDKey, userID))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestIdempotentBoundsBodies(t *testing.T) {
+	m := newTestIdempotencyMiddleware()
+	userID := uuid.New().String()
+	calls := 0
+	handler := m.Idempotent(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+		if r.URL.Path == "/api/projects/export" {
+			w.Write([]byte(strings.Repeat("x", 65)))
+		}
+	}))
+
+	send := func(path, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(IdempotencyKeyHeader, "key-"+path)
+		req.Header.Set("Content-Type", contentType)
+		req = req.WithContext(context.WithValue(req.Context(), userIDKey, userID))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	// Bodies above the limit are refused before the handler runs
+	w := send("/api/projects", "application/json", strings.Repeat("x", 65))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, 0, calls)
+
+	// Uploads are left to their handler and never replayed
+	upload := strings.Repeat("x", 65)
+	assert.Equal(t, http.StatusCreated, send("/api/users/me/avatar", "multipart/form-data; boundary=x", upload).Code)
+	w = send("/api/users/me/avatar", "multipart/form-data; boundary=x", upload)
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 2, calls)
+
+	// Responses above the limit are not stored, so retries run again
+	assert.Equal(t, 65, send("/api/projects/export", "application/json", `{}`).Body.Len())
+	w = send("/api/projects/export", "application/json", `{}`)
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 4, calls)
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/api/utils"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/ratelimit"
+)
+
+// RateLimitMiddleware rejects clients that exceed a request budget with 429 Too Many Requests
+type RateLimitMiddleware struct {
+	limiter    ratelimit.Limiter
+	enabled    bool
+	trustProxy bool
+}
+
+func NewRateLimitMiddleware(cfg *config.Config, limiter ratelimit.Limiter) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		limiter:    limiter,
+		enabled:    cfg.RateLimit.Enabled,
+		trustProxy: cfg.RateLimit.TrustProxy,
+	}
+}
+
+// PerIP limits every request to the wrapped routes by client IP. Used for
+// unauthenticated endpoints such as login and registration.
+func (m *RateLimitMiddleware) PerIP(name string, limit int, window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if m.allow(w, "ratelimit:"+name+":ip:"+m.clientIP(r), limit, window) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// PerUser limits write requests to the wrapped routes by authenticated user.
+// Reads are not counted. Must run after Authenticate.
+func (m *RateLimitMiddleware) PerUser(name string, limit int, window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserIDFromContext(r.Context())
+			if !ok || isSafeMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if m.allow(w, "ratelimit:"+name+":user:"+userID, limit, window) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// allow counts the request and writes the 429 response when the limit is exceeded
+func (m *RateLimitMiddleware) allow(w http.ResponseWriter, key string, limit int, window time.Duration) bool {
+	if !m.enabled || limit <= 0 {
+		return true
+	}
+
+	result, err := m.limiter.Allow(key, limit, window)
+	if err != nil {
+		// Fail open; a limiter outage must not take the API down
+		log.Printf("Rate limiter error: %v", err)
+		return true
+	}
+
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+
+	if !result.Allowed {
+		retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		responses.RespondWithError(w, http.StatusTooManyRequests, "Too many requests, please try again later")
+		return false
+	}
+
+	return true
+}
+
+// clientIP returns the IP the request came from
+func (m *RateLimitMiddleware) clientIP(r *http.Request) string {
+	return utils.ClientIP(r, m.trustProxy)
+}
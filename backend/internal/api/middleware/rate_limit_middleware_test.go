@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/ratelimit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRateLimitMiddleware(trustProxy bool) *RateLimitMiddleware {
+	cfg := &config.Config{}
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.TrustProxy = trustProxy
+	return NewRateLimitMiddleware(cfg, ratelimit.NewMemoryLimiter())
+}
+
+func TestRateLimitPerIP(t *testing.T) {
+	m := newTestRateLimitMiddleware(false)
+	handler := m.PerIP("auth", 2, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/login", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, send("10.0.0.1:1111").Code)
+	assert.Equal(t, http.StatusOK, send("10.0.0.1:2222").Code)
+
+	w := send("10.0.0.1:3333")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	// Another client is unaffected
+	assert.Equal(t, http.StatusOK, send("10.0.0.2:1111").Code)
+}
+
+func TestRateLimitPerIP_TrustProxy(t *testing.T) {
+	m := newTestRateLimitMiddleware(true)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/login", nil)
+	req.RemoteAddr = "10.0.0.254:443"
+	req.Header.Set("X-Forwarded-For", "6.6.6.6, 203.0.113.7")
+
+	assert.Equal(t, "203.0.113.7", m.clientIP(req))
+
+	// Without a trusted proxy the header is ignored
+	assert.Equal(t, "10.0.0.254", newTestRateLimitMiddleware(false).clientIP(req))
+}
+
+func TestRateLimitPerUser(t *testing.T) {
+	m := newTestRateLimitMiddleware(false)
+	handler := m.PerUser("mutation", 1, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	userID := uuid.New().String()
+
+	send := func(method string) int {
+		req := httptest.NewRequest(method, "/api/projects", nil)
+		req = req.WithContext(context.WithValue(req.Context(), userIDKey, userID))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send(http.MethodPost))
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodPut))
+
+	// Reads are not limited
+	assert.Equal(t, http.StatusOK, send(http.MethodGet))
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	m := NewRateLimitMiddleware(&config.Config{}, ratelimit.NewMemoryLimiter())
+	handler := m.PerIP("auth", 1, time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/login", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
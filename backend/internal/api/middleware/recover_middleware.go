@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+	"github.com/go-chi/chi/v5"
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// Recoverer turns panics into 500 responses and reports them, along with any
+// other 500 response, to the error reporter. Each request gets an error
+// report scope that later middleware fills in, e.g. with the user ID, so
+// errors captured while serving it carry the same tags.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, scope := errorreport.NewContext(r.Context())
+		scope.SetTag(errorreport.TagMethod, r.Method)
+		ww := chiMiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		defer func() {
+			// Routing has finished, so the pattern and URL parameters are known
+			if rctx := chi.RouteContext(ctx); rctx != nil {
+				scope.SetTag(errorreport.TagRoute, rctx.RoutePattern())
+				if projectID := rctx.URLParam("project_id"); projectID != "" {
+					scope.SetTag(errorreport.TagProjectID, projectID)
+				}
+			}
+
+			if rec := recover(); rec != nil {
+				if rec == http.ErrAbortHandler {
+					// Deliberately aborted response; let net/http handle it
+					panic(rec)
+				}
+				log.Printf("panic: %v\n%s", rec, debug.Stack())
+				errorreport.CapturePanic(ctx, rec)
+				// Upgraded connections have been hijacked and cannot be written to
+				if ww.Status() == 0 && r.Header.Get("Connection") != "Upgrade" {
+					responses.RespondWithError(ww, http.StatusInternalServerError, "Internal server error")
+				}
+				return
+			}
+
+			if ww.Status() == http.StatusInternalServerError {
+				errorreport.Capture(ctx, fmt.Errorf("%s %s responded with 500", r.Method, scope.Tags()[errorreport.TagRoute]))
+			}
+		}()
+
+		next.ServeHTTP(ww, r.WithContext(ctx))
+	})
+}
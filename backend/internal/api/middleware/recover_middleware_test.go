@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingReporter struct {
+	errors int
+	panics []map[string]string
+}
+
+func (r *recordingReporter) Capture(scope *errorreport.Scope, err error) { r.errors++ }
+func (r *recordingReporter) CapturePanic(scope *errorreport.Scope, value any) {
+	r.panics = append(r.panics, scope.Tags())
+}
+func (r *recordingReporter) Flush(time.Duration) bool { return true }
+
+func TestRecoverer(t *testing.T) {
+	reporter := &recordingReporter{}
+	errorreport.SetReporter(reporter)
+	defer errorreport.SetReporter(nil)
+
+	r := chi.NewRouter()
+	r.Use(Recoverer)
+	r.Get("/projects/{project_id}", func(w http.ResponseWriter, r *http.Request) {
+		errorreport.SetUser(r.Context(), "user-1")
+		panic("boom")
+	})
+	r.Get("/failing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	r.Get("/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/projects/p1", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, []map[string]string{{
+		errorreport.TagMethod:    "GET",
+		errorreport.TagRoute:     "/projects/{project_id}",
+		errorreport.TagProjectID: "p1",
+		errorreport.TagUserID:    "user-1",
+	}}, reporter.panics)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/failing", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	assert.Equal(t, 1, reporter.errors)
+}
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+)
+
+// TrackWrites lets the reads a request makes with its context go to the read
+// replica until the request first writes, then keeps them on the primary
+func TrackWrites(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(db.TrackWrites(r.Context())))
+	})
+}
@@ -0,0 +1,312 @@
+package openapi
+
+import (
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+)
+
+// Route describes one API operation. The table of routes is kept next to the
+// router, and a test checks that both list the same endpoints.
+type Route struct {
+	ID          string // operationId, used as the method name by generated clients
+	Method      string
+	Path        string // chi pattern relative to the server URL, e.g. /projects/{project_id}
+	Tag         string
+	Summary     string
+	Description string
+	Public      bool   // No authentication required
+	SessionOnly bool   // API tokens are rejected
+	OwnSession  bool   // Sessions of admins impersonating the user are rejected too
+	Admin       bool   // Requires an admin account
+	Request     any    // JSON request body, e.g. dto.LoginRequest{}
+	MergePatch  bool   // Request is sent as a JSON Merge Patch, where null clears nullable properties
+	Upload      string // Name of the file field of a multipart/form-data request body
+	Form        any    // Struct of the fields sent as JSON alongside the Upload file, e.g. dto.ImportSchemaForm{}
+	Versioned   bool   // Update honoring If-Match with the version ETag, 409 when it is stale
+	Sequenced   bool   // Schema change answered with the X-Project-Sequence of its WebSocket event
+	Response    any    // Value of the data field of a successful response
+	Status      int    // Success status, 200 when zero
+	Query       []QueryParam
+	Sort        []string // Fields a cursor-paginated list can be sorted by, default first
+	Redirect    bool     // Responds with a redirect instead of JSON
+	ContentType string   // Media type of a success response without the JSON envelope
+}
+
+// QueryParam is an optional query string parameter
+type QueryParam struct {
+	Name        string
+	Type        string // JSON schema type, "string" when empty
+	Description string
+}
+
+// Messages lists the WebSocket message types each side sends, with a zero
+// value of the type of their data
+type Messages struct {
+	Server map[string]any
+	Client map[string]any
+}
+
+// multipartFormData is the media type of file uploads
+const multipartFormData = "multipart/form-data"
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Build returns the OpenAPI document for the routes and WebSocket messages
+func Build(info Info, serverURL string, routes []Route, messages Messages) *Document {
+	registry := newSchemaRegistry()
+	envelope := registry.schemaOf(dto.APIResponse{})
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Servers: []Server{{URL: serverURL}},
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas: registry.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				"cookieAuth": {
+					Type: "apiKey",
+					In:   "cookie",
+					Name: "access_token",
+					Description: "Session cookie set by login. Write requests must echo the csrf_token cookie " +
+						"in the X-CSRF-Token header.",
+				},
+				"bearerAuth": {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "JWT access token, or a personal or service account API token (ezm_...)",
+				},
+			},
+		},
+	}
+
+	var tags []string
+	for _, route := range routes {
+		item, ok := doc.Paths[route.Path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[route.Path] = item
+		}
+		(*item)[strings.ToLower(route.Method)] = buildOperation(registry, envelope, route)
+
+		if !slices.Contains(tags, route.Tag) {
+			tags = append(tags, route.Tag)
+		}
+	}
+	for _, tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+
+	if len(messages.Server) > 0 || len(messages.Client) > 0 {
+		doc.WebSocket = &WebSocketMessages{
+			Server: messageSchemas(registry, messages.Server, false),
+			Client: messageSchemas(registry, messages.Client, true),
+		}
+	}
+
+	return doc
+}
+
+func messageSchemas(registry *schemaRegistry, payloads map[string]any, request bool) map[string]*Schema {
+	schemas := make(map[string]*Schema, len(payloads))
+	// Sorted so component names are assigned in the same order on every build
+	for _, messageType := range slices.Sorted(maps.Keys(payloads)) {
+		payload := payloads[messageType]
+		if request {
+			schemas[messageType] = registry.requestSchemaOf(payload)
+		} else {
+			schemas[messageType] = registry.schemaOf(payload)
+		}
+	}
+	return schemas
+}
+
+func buildOperation(registry *schemaRegistry, envelope *Schema, route Route) *Operation {
+	op := &Operation{
+		Tags:        []string{route.Tag},
+		Summary:     route.Summary,
+		Description: description(route),
+		OperationID: route.ID,
+		Responses:   make(map[string]Response),
+	}
+
+	pathParams := pathParamPattern.FindAllStringSubmatch(route.Path, -1)
+	for _, match := range pathParams {
+		schema := &Schema{Type: "string"}
+		if strings.HasSuffix(match[1], "_id") {
+			schema.Format = "uuid"
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: schema})
+	}
+	for _, param := range route.Query {
+		paramType := param.Type
+		if paramType == "" {
+			paramType = "string"
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: param.Name, In: "query", Description: param.Description, Schema: &Schema{Type: paramType}})
+	}
+	if len(route.Sort) > 0 {
+		op.Parameters = append(op.Parameters, pageParameters(route.Sort)...)
+	}
+	idempotent := route.Method == http.MethodPost && !route.Public
+	if idempotent {
+		op.Parameters = append(op.Parameters, Parameter{Name: "Idempotency-Key", In: "header",
+			Description: "Unique key of up to 255 characters. Retries with the key get the first response, " +
+				"marked with an Idempotent-Replayed header, instead of running again.", Schema: &Schema{Type: "string"}})
+	}
+	if route.Versioned {
+		op.Parameters = append(op.Parameters, Parameter{Name: "If-Match", In: "header",
+			Description: `ETag of the version the update was made against, e.g. "3"`, Schema: &Schema{Type: "string"}})
+	}
+
+	if route.Request != nil {
+		content := map[string]MediaType{"application/json": {Schema: registry.requestSchemaOf(route.Request)}}
+		if route.MergePatch {
+			content["application/merge-patch+json"] = content["application/json"]
+		}
+		op.RequestBody = &RequestBody{Required: true, Content: content}
+	}
+	if route.Upload != "" {
+		form := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		if route.Form != nil {
+			// Object and array fields of a multipart body are JSON encoded
+			form = registry.requestFormOf(route.Form)
+		}
+		form.Properties[route.Upload] = &Schema{Type: "string", Format: "binary"}
+		form.Required = append([]string{route.Upload}, form.Required...)
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{multipartFormData: {Schema: form}}}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	op.Responses[strconv.Itoa(status)] = successResponse(registry, envelope, route, status)
+
+	errorResponse := func(code int) {
+		op.Responses[strconv.Itoa(code)] = Response{
+			Description: http.StatusText(code),
+			Content:     map[string]MediaType{"application/json": {Schema: envelope}},
+		}
+	}
+	if route.Upload != "" {
+		errorResponse(http.StatusRequestEntityTooLarge)
+	}
+	if route.Request != nil || route.Upload != "" || len(route.Query) > 0 || len(route.Sort) > 0 || len(pathParams) > 0 {
+		errorResponse(http.StatusBadRequest)
+	}
+	if !route.Public {
+		errorResponse(http.StatusUnauthorized)
+		errorResponse(http.StatusForbidden)
+		op.Security = []map[string][]string{{"cookieAuth": {}}, {"bearerAuth": {}}}
+	}
+	if len(pathParams) > 0 {
+		errorResponse(http.StatusNotFound)
+	}
+	if route.Sequenced {
+		success := op.Responses[strconv.Itoa(status)]
+		success.Headers = map[string]Header{"X-Project-Sequence": {
+			Description: "Sequence of the WebSocket event announcing the change, absent when none was sent",
+			Schema:      &Schema{Type: "integer"},
+		}}
+		op.Responses[strconv.Itoa(status)] = success
+	}
+	if route.Versioned {
+		etag := map[string]Header{"ETag": {Schema: &Schema{Type: "string"}}}
+		success := op.Responses[strconv.Itoa(status)]
+		if success.Headers == nil {
+			success.Headers = make(map[string]Header)
+		}
+		success.Headers["ETag"] = etag["ETag"]
+		op.Responses[strconv.Itoa(status)] = success
+		op.Responses[strconv.Itoa(http.StatusConflict)] = Response{
+			Description: "Changed since the given version; data holds the current state",
+			Headers:     etag,
+			Content:     successResponse(registry, envelope, route, http.StatusConflict).Content,
+		}
+	}
+	if idempotent {
+		errorResponse(http.StatusConflict)
+		errorResponse(http.StatusUnprocessableEntity)
+	}
+	errorResponse(http.StatusInternalServerError)
+
+	return op
+}
+
+// pageParameters are the query parameters of a cursor-paginated list
+func pageParameters(sorts []string) []Parameter {
+	values := make([]string, 0, 2*len(sorts))
+	for _, sort := range sorts {
+		values = append(values, sort, "-"+sort)
+	}
+	return []Parameter{
+		{Name: "limit", In: "query", Description: "At most 100, 50 by default", Schema: &Schema{Type: "integer"}},
+		{Name: "cursor", In: "query", Description: "meta.next_cursor of the previous page", Schema: &Schema{Type: "string"}},
+		{Name: "sort", In: "query", Description: "Prefix with - for descending order", Schema: &Schema{Type: "string", Enum: values}},
+	}
+}
+
+func successResponse(registry *schemaRegistry, envelope *Schema, route Route, status int) Response {
+	switch {
+	case route.Redirect:
+		return Response{
+			Description: http.StatusText(status),
+			Headers:     map[string]Header{"Location": {Schema: &Schema{Type: "string", Format: "uri"}}},
+		}
+	case route.ContentType != "":
+		// Bodies outside the envelope; Response describes the whole body when set
+		schema := &Schema{Type: "string"}
+		if route.Response != nil {
+			schema = registry.schemaOf(route.Response)
+		}
+		return Response{
+			Description: http.StatusText(status),
+			Content:     map[string]MediaType{route.ContentType: {Schema: schema}},
+		}
+	case status == http.StatusSwitchingProtocols:
+		return Response{Description: http.StatusText(status)}
+	}
+
+	schema := envelope
+	if route.Response != nil {
+		schema = &Schema{AllOf: []*Schema{envelope, {
+			Type:       "object",
+			Properties: map[string]*Schema{"data": registry.schemaOf(route.Response)},
+		}}}
+	}
+	return Response{
+		Description: http.StatusText(status),
+		Content:     map[string]MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+func description(route Route) string {
+	parts := []string{}
+	if route.Description != "" {
+		parts = append(parts, route.Description)
+	}
+	if route.MergePatch {
+		parts = append(parts, "Properties left out are unchanged and unknown properties are rejected.")
+	}
+	if route.Versioned {
+		parts = append(parts, "Send the version from the ETag header or the version property as If-Match, "+
+			"or as version in the body, to fail with 409 instead of overwriting someone else's changes.")
+	}
+	if route.Admin {
+		parts = append(parts, "Requires an admin account.")
+	}
+	if route.SessionOnly {
+		parts = append(parts, "API tokens cannot call this endpoint.")
+	}
+	if route.OwnSession {
+		parts = append(parts, "Admins impersonating the user cannot call it either.")
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,107 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testNode struct {
+	ID       uuid.UUID   `json:"id"`
+	Name     string      `json:"name" validate:"required,min=1,max=255"`
+	Kind     string      `json:"kind" validate:"omitempty,oneof=a b"`
+	Email    *string     `json:"email,omitempty" validate:"omitempty,email"`
+	Children []*testNode `json:"children,omitempty"`
+	Parent   *testNode   `json:"parent"`
+	Secret   string      `json:"-"`
+	Tags     []string    `json:"tags" validate:"required,min=1,dive,max=10"`
+	Seen     time.Time   `json:"seen"`
+}
+
+func TestSchemaFor_Struct(t *testing.T) {
+	registry := newSchemaRegistry()
+
+	ref := registry.requestSchemaOf(testNode{})
+
+	assert.Equal(t, "#/components/schemas/testNode", ref.Ref)
+	schema := registry.schemas["testNode"]
+	require.NotNil(t, schema)
+	assert.ElementsMatch(t, []string{"name", "tags"}, schema.Required)
+	assert.NotContains(t, schema.Properties, "Secret")
+
+	assert.Equal(t, "uuid", schema.Properties["id"].Format)
+	assert.Equal(t, 1, *schema.Properties["name"].MinLength)
+	assert.Equal(t, 255, *schema.Properties["name"].MaxLength)
+	assert.Equal(t, []string{"a", "b"}, schema.Properties["kind"].Enum)
+	assert.Equal(t, "email", schema.Properties["email"].Format)
+	assert.True(t, schema.Properties["email"].Nullable)
+	assert.Equal(t, "date-time", schema.Properties["seen"].Format)
+
+	// Rules after dive describe the elements, not the list
+	assert.Equal(t, 1, *schema.Properties["tags"].MinItems)
+	assert.Nil(t, schema.Properties["tags"].MaxItems)
+
+	// Recursive references resolve to the same component
+	assert.Equal(t, ref.Ref, schema.Properties["children"].Items.AllOf[0].Ref)
+	assert.True(t, schema.Properties["parent"].Nullable)
+}
+
+func TestSchemaFor_ResponseRequiresFieldsWithoutOmitempty(t *testing.T) {
+	registry := newSchemaRegistry()
+
+	registry.schemaOf(testNode{})
+
+	assert.ElementsMatch(t, []string{"id", "name", "kind", "parent", "tags", "seen"}, registry.schemas["testNode"].Required)
+}
+
+func TestBuild(t *testing.T) {
+	type request struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	doc := Build(Info{Title: "Test", Version: "1"}, "/api", []Route{
+		{ID: "createThing", Method: http.MethodPost, Path: "/things/{thing_id}", Tag: "Things", Summary: "Create", Request: request{}, Status: http.StatusCreated},
+		{ID: "ping", Method: http.MethodGet, Path: "/ping", Tag: "Meta", Summary: "Ping", Public: true},
+	}, Messages{})
+
+	op := (*doc.Paths["/things/{thing_id}"])["post"]
+	require.NotNil(t, op)
+	assert.Equal(t, "createThing", op.OperationID)
+	assert.Equal(t, []string{"Things"}, op.Tags)
+	require.Len(t, op.Parameters, 2)
+	assert.Equal(t, "path", op.Parameters[0].In)
+	assert.Equal(t, "uuid", op.Parameters[0].Schema.Format)
+	assert.Equal(t, "Idempotency-Key", op.Parameters[1].Name)
+	assert.Equal(t, "header", op.Parameters[1].In)
+	assert.NotNil(t, op.RequestBody)
+	assert.Contains(t, op.Responses, "201")
+	assert.Contains(t, op.Responses, "401")
+	assert.Contains(t, op.Responses, "404")
+	assert.Contains(t, op.Responses, "422")
+	assert.NotEmpty(t, op.Security)
+
+	ping := (*doc.Paths["/ping"])["get"]
+	assert.Empty(t, ping.Security)
+	assert.NotContains(t, ping.Responses, "401")
+	assert.Equal(t, []Tag{{Name: "Things"}, {Name: "Meta"}}, doc.Tags)
+	assert.Nil(t, doc.WebSocket)
+}
+
+func TestBuild_WebSocketMessages(t *testing.T) {
+	type cursor struct {
+		X float64 `json:"x"`
+	}
+
+	doc := Build(Info{Title: "Test", Version: "1"}, "/api", nil, Messages{
+		Server: map[string]any{"cursor": cursor{}},
+		Client: map[string]any{"cursor": cursor{}},
+	})
+
+	require.NotNil(t, doc.WebSocket)
+	assert.Equal(t, "#/components/schemas/cursor", doc.WebSocket.Server["cursor"].Ref)
+	assert.Equal(t, "#/components/schemas/cursor", doc.WebSocket.Client["cursor"].Ref)
+}
@@ -0,0 +1,381 @@
+package openapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const componentPrefix = "#/components/schemas/"
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypeScript renders a client for the document: an interface per component
+// schema, a method per JSON operation and unions of the WebSocket messages.
+// The output is indented with tabs and single-quoted like the frontend sources.
+func TypeScript(doc *Document) ([]byte, error) {
+	hash, err := SpecHash(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by cmd/sdkgen from the OpenAPI document. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "export const API_VERSION = %s;\n", tsString(doc.Info.Version))
+	fmt.Fprintf(&b, "export const SPEC_HASH = %s;\n", tsString(hash))
+
+	for _, name := range slices.Sorted(maps.Keys(doc.Components.Schemas)) {
+		writeInterface(&b, name, doc.Components.Schemas[name])
+	}
+
+	if doc.WebSocket != nil {
+		writeMessages(&b, doc.WebSocket)
+	}
+
+	writeClient(&b, doc)
+	return []byte(b.String()), nil
+}
+
+// SpecHash identifies a version of the document, so clients can tell when
+// the API they were generated from has changed
+func SpecHash(doc *Document) (string, error) {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("encoding OpenAPI document: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:6]), nil
+}
+
+func writeInterface(b *strings.Builder, name string, schema *Schema) {
+	b.WriteString("\n")
+	if schema.Type != "object" || len(schema.Properties) == 0 {
+		fmt.Fprintf(b, "export type %s = %s;\n", name, tsType(schema))
+		return
+	}
+
+	fmt.Fprintf(b, "export interface %s {\n", name)
+	for _, prop := range slices.Sorted(maps.Keys(schema.Properties)) {
+		propSchema := schema.Properties[prop]
+		if propSchema.Description != "" {
+			fmt.Fprintf(b, "\t/** %s */\n", propSchema.Description)
+		}
+		fmt.Fprintf(b, "\t%s%s: %s;\n", tsKey(prop), optional(schema, prop), tsType(propSchema))
+	}
+	b.WriteString("}\n")
+}
+
+func writeMessages(b *strings.Builder, messages *WebSocketMessages) {
+	b.WriteString(`
+/** Envelope of every message on the collaboration WebSocket */
+export interface WebSocketMessage<T extends string = string, D = unknown> {
+	type: T;
+	data: D;
+	user_id?: string;
+	project_id?: string;
+	timestamp?: string;
+	/** Orders a project's schema changes, REST and WebSocket alike; set only on those */
+	sequence?: number;
+}
+`)
+
+	writePayloadMap := func(name string, payloads map[string]*Schema) {
+		fmt.Fprintf(b, "\nexport interface %s {\n", name)
+		for _, messageType := range slices.Sorted(maps.Keys(payloads)) {
+			fmt.Fprintf(b, "\t%s: %s;\n", tsKey(messageType), tsType(payloads[messageType]))
+		}
+		b.WriteString("}\n")
+	}
+	writePayloadMap("ServerMessagePayloads", messages.Server)
+	writePayloadMap("ClientMessagePayloads", messages.Client)
+
+	b.WriteString(`
+/** Messages the server sends, discriminated by type */
+export type ServerMessage = {
+	[K in keyof ServerMessagePayloads]: WebSocketMessage<K, ServerMessagePayloads[K]>;
+}[keyof ServerMessagePayloads];
+
+/** Messages the server accepts from clients, discriminated by type */
+export type ClientMessage = {
+	[K in keyof ClientMessagePayloads]: WebSocketMessage<K, ClientMessagePayloads[K]>;
+}[keyof ClientMessagePayloads];
+`)
+}
+
+func writeClient(b *strings.Builder, doc *Document) {
+	b.WriteString(`
+export type HttpMethod = 'GET' | 'POST' | 'PUT' | 'PATCH' | 'DELETE';
+
+export interface RequestOptions {
+	query?: Record<string, string | number | boolean | undefined>;
+	body?: unknown;
+	/** Resolve with the data and meta fields of a paginated list */
+	page?: boolean;
+}
+
+/** One page of a list; pass meta.next_cursor as the cursor query parameter for the next */
+export interface Page<T> {
+	data: T[];
+	meta: PageMeta;
+}
+
+/**
+ * Sends a request and resolves with the data field of a successful response,
+ * or with a Page when options.page is set.
+ * The path is relative to the API base URL and starts with a slash.
+ */
+export type Transport = <T>(method: HttpMethod, path: string, options: RequestOptions) => Promise<T>;
+
+/** Rejected by fetchTransport when the response is not a success */
+export class ApiRequestError extends Error {
+	constructor(
+		readonly status: number,
+		readonly response?: APIResponse
+	) {
+		super(response?.message || ` + "`Request failed with status ${status}`" + `);
+	}
+}
+
+/** Transport built on fetch that sends cookies and echoes the CSRF cookie on writes */
+export function fetchTransport(baseUrl: string, fetchImpl: typeof fetch = fetch): Transport {
+	return async <T>(method: HttpMethod, path: string, options: RequestOptions): Promise<T> => {
+		const url = new URL(baseUrl.replace(/\/$/, '') + path, globalThis.location?.href);
+		for (const [key, value] of Object.entries(options.query ?? {})) {
+			if (value !== undefined) url.searchParams.set(key, String(value));
+		}
+
+		// FormData bodies are sent as multipart/form-data with the boundary set by fetch
+		const form = options.body instanceof FormData;
+		const headers: Record<string, string> = {};
+		if (options.body !== undefined && !form) headers['Content-Type'] = 'application/json';
+		const csrf = globalThis.document?.cookie.match(/(?:^|; )csrf_token=([^;]*)/);
+		if (csrf && method !== 'GET') headers['X-CSRF-Token'] = decodeURIComponent(csrf[1]);
+
+		const response = await fetchImpl(url, {
+			method,
+			headers,
+			credentials: 'include',
+			body: options.body === undefined || form ? (options.body as FormData | undefined) : JSON.stringify(options.body)
+		});
+		const envelope = (await response.json().catch(() => undefined)) as APIResponse | undefined;
+		if (!response.ok || !envelope?.success) {
+			throw new ApiRequestError(response.status, envelope);
+		}
+		if (options.page) {
+			return { data: envelope.data ?? [], meta: envelope.meta ?? {} } as T;
+		}
+		return envelope.data as T;
+	};
+}
+
+/** Typed methods for the JSON and file upload endpoints. Redirect, WebSocket and non-JSON endpoints are left out. */
+export class EzModelClient {
+	constructor(private readonly transport: Transport) {}
+`)
+
+	for _, path := range slices.Sorted(maps.Keys(doc.Paths)) {
+		item := *doc.Paths[path]
+		for _, method := range slices.Sorted(maps.Keys(item)) {
+			writeMethod(b, path, strings.ToUpper(method), item[method])
+		}
+	}
+	b.WriteString("}\n")
+}
+
+func writeMethod(b *strings.Builder, path, method string, op *Operation) {
+	result, ok := resultType(op)
+	if !ok {
+		return
+	}
+
+	var params []string
+	tsPath := path
+	for _, param := range op.Parameters {
+		if param.In != "path" {
+			continue
+		}
+		name := camelCase(param.Name)
+		params = append(params, name+": string")
+		tsPath = strings.ReplaceAll(tsPath, "{"+param.Name+"}", "${encodeURIComponent("+name+")}")
+	}
+
+	var options []string
+	var form string
+	var formFields []string
+	if op.RequestBody != nil {
+		if media, ok := op.RequestBody.Content[multipartFormData]; ok {
+			// File uploads take the file, and the other form fields as an
+			// object whose members are sent JSON encoded
+			form = media.Schema.Required[0]
+			params = append(params, camelCase(form)+": Blob")
+			var members []string
+			for _, name := range slices.Sorted(maps.Keys(media.Schema.Properties)) {
+				if name == form {
+					continue
+				}
+				formFields = append(formFields, name)
+				members = append(members, fmt.Sprintf("%s%s: %s", tsKey(name), optional(media.Schema, name), tsType(media.Schema.Properties[name])))
+			}
+			if len(members) > 0 {
+				params = append(params, "fields?: { "+strings.Join(members, "; ")+" }")
+			}
+		} else {
+			params = append(params, "body: "+tsType(op.RequestBody.Content["application/json"].Schema))
+		}
+		options = append(options, "body")
+	}
+
+	var query []string
+	paginated := false
+	for _, param := range op.Parameters {
+		if param.In == "query" {
+			query = append(query, fmt.Sprintf("%s?: %s", tsKey(param.Name), tsType(param.Schema)))
+			paginated = paginated || param.Name == "cursor"
+		}
+	}
+	if len(query) > 0 {
+		params = append(params, "query?: { "+strings.Join(query, "; ")+" }")
+		options = append(options, "query")
+	}
+	if paginated && strings.HasSuffix(result, "[]") {
+		result = "Page<" + strings.TrimSuffix(result, "[]") + ">"
+		options = append(options, "page: true")
+	}
+
+	fmt.Fprintf(b, "\n\t/** %s */\n", op.Summary)
+	fmt.Fprintf(b, "\t%s(%s): Promise<%s> {\n", op.OperationID, strings.Join(params, ", "), result)
+	if form != "" {
+		b.WriteString("\t\tconst body = new FormData();\n")
+		fmt.Fprintf(b, "\t\tbody.append(%s, %s);\n", tsString(form), camelCase(form))
+		for _, name := range formFields {
+			value := "fields[" + tsString(name) + "]"
+			fmt.Fprintf(b, "\t\tif (fields?.[%s] !== undefined) body.append(%s, JSON.stringify(%s));\n", tsString(name), tsString(name), value)
+		}
+	}
+	fmt.Fprintf(b, "\t\treturn this.transport(%s, `%s`, {%s});\n", tsString(method), tsPath, padded(strings.Join(options, ", ")))
+	b.WriteString("\t}\n")
+}
+
+// resultType returns the type of the data field of the success response, and
+// false when the operation does not answer with the JSON envelope
+func resultType(op *Operation) (string, bool) {
+	for code, response := range op.Responses {
+		status, _ := strconv.Atoi(code)
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			continue
+		}
+
+		media, ok := response.Content["application/json"]
+		if !ok {
+			return "", false
+		}
+		schema := media.Schema
+		switch {
+		case schema.Ref == componentPrefix+"APIResponse":
+			return "void", true
+		case len(schema.AllOf) == 2 && schema.AllOf[0].Ref == componentPrefix+"APIResponse":
+			return tsType(schema.AllOf[1].Properties["data"]), true
+		}
+		return "", false
+	}
+	return "", false
+}
+
+func tsType(schema *Schema) string {
+	if schema == nil {
+		return "unknown"
+	}
+
+	var t string
+	switch {
+	case schema.Ref != "":
+		t = strings.TrimPrefix(schema.Ref, componentPrefix)
+	case len(schema.AllOf) > 0:
+		parts := make([]string, len(schema.AllOf))
+		for i, part := range schema.AllOf {
+			parts[i] = tsType(part)
+		}
+		t = strings.Join(parts, " & ")
+	case len(schema.Enum) > 0:
+		parts := make([]string, len(schema.Enum))
+		for i, value := range schema.Enum {
+			parts[i] = tsString(value)
+		}
+		t = strings.Join(parts, " | ")
+	case schema.Type == "string":
+		t = "string"
+	case schema.Type == "integer", schema.Type == "number":
+		t = "number"
+	case schema.Type == "boolean":
+		t = "boolean"
+	case schema.Type == "array":
+		t = tsType(schema.Items)
+		if strings.ContainsAny(t, "|&") {
+			t = "(" + t + ")"
+		}
+		t += "[]"
+	case schema.Type == "object" && len(schema.Properties) > 0:
+		fields := make([]string, 0, len(schema.Properties))
+		for _, prop := range slices.Sorted(maps.Keys(schema.Properties)) {
+			fields = append(fields, fmt.Sprintf("%s%s: %s", tsKey(prop), optional(schema, prop), tsType(schema.Properties[prop])))
+		}
+		t = "{ " + strings.Join(fields, "; ") + " }"
+	case schema.Type == "object" && schema.AdditionalProperties != nil:
+		t = "Record<string, " + tsType(schema.AdditionalProperties) + ">"
+	case schema.Type == "object":
+		t = "Record<string, unknown>"
+	default:
+		t = "unknown"
+	}
+
+	if schema.Nullable && t != "unknown" {
+		t += " | null"
+	}
+	return t
+}
+
+func optional(schema *Schema, prop string) string {
+	if slices.Contains(schema.Required, prop) {
+		return ""
+	}
+	return "?"
+}
+
+func tsKey(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return tsString(name)
+}
+
+// tsString quotes s with single quotes, as prettier would
+func tsString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// camelCase turns a snake_case path parameter into an argument name
+func camelCase(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// padded surrounds the members of an object literal with spaces, leaving {} empty
+func padded(members string) string {
+	if members == "" {
+		return ""
+	}
+	return " " + members + " "
+}
@@ -0,0 +1,107 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tsThing struct {
+	ID       string   `json:"id"`
+	Kind     string   `json:"kind" validate:"oneof=a b"`
+	Note     *string  `json:"note,omitempty"`
+	Children []string `json:"children"`
+}
+
+type tsCreateThing struct {
+	Name  string `json:"name" validate:"required"`
+	Notes string `json:"notes"`
+}
+
+type tsImportForm struct {
+	Things []tsCreateThing `json:"things,omitempty"`
+}
+
+type tsPing struct {
+	At string `json:"at"`
+}
+
+func TestTypeScript(t *testing.T) {
+	doc := Build(Info{Title: "Test", Version: "2.1.0"}, "/api", []Route{
+		{ID: "createThing", Method: http.MethodPost, Path: "/groups/{group_id}/things", Tag: "Things", Summary: "Create a thing",
+			Request: tsCreateThing{}, Response: tsThing{}, Status: http.StatusCreated},
+		{ID: "listThings", Method: http.MethodGet, Path: "/things", Tag: "Things", Summary: "List things",
+			Query: []QueryParam{{Name: "kind"}}, Sort: []string{"id"}, Response: []tsThing{}},
+		{ID: "importThings", Method: http.MethodPost, Path: "/things/import", Tag: "Things", Summary: "Import things",
+			Upload: "file", Form: tsImportForm{}, Response: []tsThing{}},
+		{ID: "deleteThing", Method: http.MethodDelete, Path: "/things/{thing_id}", Tag: "Things", Summary: "Delete a thing"},
+		{ID: "startLogin", Method: http.MethodGet, Path: "/login", Tag: "Auth", Summary: "Redirect", Public: true,
+			Redirect: true, Status: http.StatusFound},
+	}, Messages{
+		Server: map[string]any{"ping": tsPing{}},
+		Client: map[string]any{"pong": tsPing{}},
+	})
+
+	source, err := TypeScript(doc)
+	require.NoError(t, err)
+	ts := string(source)
+
+	hash, err := SpecHash(doc)
+	require.NoError(t, err)
+	assert.Contains(t, ts, "export const API_VERSION = '2.1.0';")
+	assert.Contains(t, ts, "export const SPEC_HASH = '"+hash+"';")
+
+	// Response fields are required unless omitempty; request fields unless validated as required
+	assert.Contains(t, ts, "export interface tsThing {\n\tchildren: string[];\n\tid: string;\n\tkind: 'a' | 'b';\n\tnote?: string | null;\n}")
+	assert.Contains(t, ts, "export interface tsCreateThing {\n\tname: string;\n\tnotes?: string;\n}")
+
+	assert.Contains(t, ts, "createThing(groupId: string, body: tsCreateThing): Promise<tsThing> {\n"+
+		"\t\treturn this.transport('POST', `/groups/${encodeURIComponent(groupId)}/things`, { body });")
+	assert.Contains(t, ts, "listThings(query?: { kind?: string; limit?: number; cursor?: string; sort?: 'id' | '-id' }): Promise<Page<tsThing>> {\n"+
+		"\t\treturn this.transport('GET', `/things`, { query, page: true });")
+	assert.Contains(t, ts, "importThings(file: Blob, fields?: { things?: tsCreateThing[] }): Promise<tsThing[]> {\n"+
+		"\t\tconst body = new FormData();\n"+
+		"\t\tbody.append('file', file);\n"+
+		"\t\tif (fields?.['things'] !== undefined) body.append('things', JSON.stringify(fields['things']));\n")
+	assert.Contains(t, ts, "deleteThing(thingId: string): Promise<void> {\n"+
+		"\t\treturn this.transport('DELETE', `/things/${encodeURIComponent(thingId)}`, {});")
+	assert.NotContains(t, ts, "startLogin")
+
+	assert.Contains(t, ts, "export interface ServerMessagePayloads {\n\tping: tsPing;\n}")
+	assert.Contains(t, ts, "export interface ClientMessagePayloads {\n\tpong: tsPing;\n}")
+}
+
+func TestTypeScript_IsDeterministic(t *testing.T) {
+	build := func() []byte {
+		doc := Build(Info{Title: "Test", Version: "1"}, "/api", []Route{
+			{ID: "getThing", Method: http.MethodGet, Path: "/things/{thing_id}", Tag: "Things", Summary: "Get", Response: tsThing{}},
+		}, Messages{Server: map[string]any{"a": tsPing{}, "b": tsThing{}}})
+		source, err := TypeScript(doc)
+		require.NoError(t, err)
+		return source
+	}
+
+	assert.Equal(t, string(build()), string(build()))
+}
+
+func TestTSType(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *Schema
+		want   string
+	}{
+		{"nullable reference", &Schema{AllOf: []*Schema{{Ref: componentPrefix + "User"}}, Nullable: true}, "User | null"},
+		{"map", &Schema{Type: "object", AdditionalProperties: &Schema{Type: "integer"}}, "Record<string, number>"},
+		{"array of unions", &Schema{Type: "array", Items: &Schema{Type: "string", Enum: []string{"x", "y"}}}, "('x' | 'y')[]"},
+		{"inline object", &Schema{Type: "object", Properties: map[string]*Schema{"user-id": {Type: "string"}}}, "{ 'user-id'?: string }"},
+		{"any", &Schema{}, "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tsType(tt.schema))
+		})
+	}
+}
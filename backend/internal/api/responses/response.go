@@ -1,8 +1,12 @@
 package responses
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
 )
@@ -21,6 +25,16 @@ func RespondWithError(w http.ResponseWriter, code int, message string) {
 	})
 }
 
+// RespondWithErrorData is RespondWithError with data describing the failure,
+// such as the current state of a resource an update conflicted with
+func RespondWithErrorData(w http.ResponseWriter, code int, message string, data interface{}) {
+	respondWithJSON(w, code, dto.APIResponse{
+		Success: false,
+		Message: message,
+		Data:    data,
+	})
+}
+
 func RespondWithValidationErrors(w http.ResponseWriter, errors map[string]string) {
 	respondWithJSON(w, http.StatusBadRequest, dto.APIResponse{
 		Success: false,
@@ -36,3 +50,56 @@ func RespondWithSuccess(w http.ResponseWriter, code int, message string, data in
 		Data:    data,
 	})
 }
+
+// RespondWithPage responds with one page of a list and how to get the next
+func RespondWithPage(w http.ResponseWriter, message string, data interface{}, meta dto.PageMeta) {
+	respondWithJSON(w, http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+		Meta:    &meta,
+	})
+}
+
+// RespondWithSuccessETag is RespondWithSuccess with an ETag derived from the
+// body. A request whose If-None-Match holds it gets 304 without a body.
+func RespondWithSuccessETag(w http.ResponseWriter, r *http.Request, message string, data interface{}) {
+	response, _ := json.Marshal(dto.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+	sum := sha256.Sum256(response)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing weakly
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectSequenceHeader carries the project sequence of a change made by a
+// request, the same as on the WebSocket event announcing it
+const ProjectSequenceHeader = "X-Project-Sequence"
+
+// SetProjectSequence sets the ProjectSequenceHeader unless the change went unannounced
+func SetProjectSequence(w http.ResponseWriter, sequence int64) {
+	if sequence > 0 {
+		w.Header().Set(ProjectSequenceHeader, strconv.FormatInt(sequence, 10))
+	}
+}
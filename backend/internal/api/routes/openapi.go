@@ -0,0 +1,556 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/api/openapi"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+)
+
+// APIDocument returns the OpenAPI description of the routes mounted under /api
+func APIDocument() *openapi.Document {
+	info := openapi.Info{
+		Title:   "EzModel API",
+		Version: "1.0.0",
+		Description: "Every JSON response uses the same envelope: success, message, data and, " +
+			"for validation failures, errors keyed by field.",
+	}
+	messages := openapi.Messages{Server: map[string]any{}, Client: map[string]any{}}
+	for messageType, payload := range websocketPkg.ServerMessagePayloads {
+		messages.Server[string(messageType)] = payload
+	}
+	for messageType, payload := range websocketPkg.ClientMessagePayloads {
+		messages.Client[string(messageType)] = payload
+	}
+	return openapi.Build(info, "/api", apiRoutes, messages)
+}
+
+// dryRunQueryParam makes an import check its input and respond with what it
+// would create, without saving anything
+var dryRunQueryParam = openapi.QueryParam{Name: "dry_run", Type: "boolean", Description: "Only check and show what would be created when true"}
+
+// apiRoutes lists every endpoint under /api. Keep it in step with SetupRoutes;
+// TestAPIRoutesMatchDocument fails when the two disagree.
+var apiRoutes = []openapi.Route{
+	{ID: "getAPIInfo", Method: http.MethodGet, Path: "/", Tag: "Meta", Summary: "API information", Public: true,
+		ContentType: "application/json", Response: struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+		}{}},
+
+	// Authentication
+	{ID: "login", Method: http.MethodPost, Path: "/login", Tag: "Auth", Summary: "Sign in with email and password", Public: true,
+		Description: "Sets the access_token, refresh_token and csrf_token cookies. Responds 429 with Retry-After while the account is locked.",
+		Request:     dto.LoginRequest{}, Response: struct {
+			User models.User `json:"user"`
+		}{}},
+	{ID: "refreshToken", Method: http.MethodPost, Path: "/refresh-token", Tag: "Auth", Summary: "Rotate the session cookies", Public: true,
+		Description: "Uses the refresh_token cookie."},
+	{ID: "register", Method: http.MethodPost, Path: "/register", Tag: "Auth", Summary: "Create an account", Public: true,
+		Request: dto.CreateUserRequest{}, Response: dto.UserResponse{}, Status: http.StatusCreated,
+		Description: "Responds with 409 when the email or username is taken, naming it in the field of the data."},
+	{ID: "logout", Method: http.MethodPost, Path: "/logout", Tag: "Auth", Summary: "Sign out and clear the session cookies", Public: true},
+	{ID: "startOAuthLogin", Method: http.MethodGet, Path: "/auth/oauth/{provider}/start", Tag: "Auth", Summary: "Redirect to an OAuth provider", Public: true,
+		Redirect: true, Status: http.StatusFound},
+	{ID: "completeOAuthLogin", Method: http.MethodGet, Path: "/auth/oauth/{provider}/callback", Tag: "Auth", Summary: "OAuth provider callback", Public: true,
+		Description: "Signs the user in and redirects to the frontend.",
+		Query:       []openapi.QueryParam{{Name: "code"}, {Name: "state"}, {Name: "error"}}, Redirect: true, Status: http.StatusFound},
+	{ID: "getSAMLMetadata", Method: http.MethodGet, Path: "/auth/saml/metadata", Tag: "Auth", Summary: "SAML service provider metadata", Public: true,
+		Description: "Only available when SAML is configured.", ContentType: "application/samlmetadata+xml"},
+	{ID: "startSAMLLogin", Method: http.MethodGet, Path: "/auth/saml/login", Tag: "Auth", Summary: "Redirect to the SAML identity provider", Public: true,
+		Description: "Only available when SAML is configured.", Redirect: true, Status: http.StatusFound},
+	{ID: "completeSAMLLogin", Method: http.MethodPost, Path: "/auth/saml/acs", Tag: "Auth", Summary: "SAML assertion consumer service", Public: true,
+		Description: "The identity provider posts a form with SAMLResponse and RelayState. Only available when SAML is configured.",
+		Redirect:    true, Status: http.StatusFound},
+
+	// Billing
+	{ID: "listPlans", Method: http.MethodGet, Path: "/billing/plans", Tag: "Billing", Summary: "Plans on offer with their quotas",
+		Description: "A quota of 0 means there is none. Only available when billing is configured.",
+		SessionOnly: true, Response: []dto.PlanResponse{}},
+	{ID: "getMySubscription", Method: http.MethodGet, Path: "/billing/subscription", Tag: "Billing", Summary: "Plan of the current user",
+		Description: "The free plan, with an empty status, for users who do not pay for one. Only available when billing is configured.",
+		SessionOnly: true, Response: dto.SubscriptionResponse{}},
+	{ID: "startCheckout", Method: http.MethodPost, Path: "/billing/checkout", Tag: "Billing", Summary: "Start buying a paid plan",
+		Description: "Responds with the Stripe Checkout page to send the browser to, or 409 while a subscription is running. Only available when billing is configured.",
+		SessionOnly: true, OwnSession: true, Request: dto.CreateCheckoutRequest{}, Response: dto.CheckoutResponse{}},
+	{ID: "receiveBillingWebhook", Method: http.MethodPost, Path: "/billing/webhook", Tag: "Billing", Summary: "Stripe webhook", Public: true,
+		Description: "Stripe posts subscription events here, signed in the Stripe-Signature header. Only available when billing is configured."},
+
+	// Current user
+	{ID: "getCurrentUser", Method: http.MethodGet, Path: "/me", Tag: "Users", Summary: "Get the signed-in user", Response: dto.UserResponse{}},
+	{ID: "getLoginHistory", Method: http.MethodGet, Path: "/users/me/security/logins", Tag: "Users", Summary: "Recent login attempts of the current user",
+		SessionOnly: true, Response: []dto.LoginEventResponse{}},
+	{ID: "listMySessions", Method: http.MethodGet, Path: "/users/me/sessions", Tag: "Sessions", Summary: "Devices the current user is signed in on",
+		SessionOnly: true, Response: []dto.UserSessionResponse{}},
+	{ID: "revokeOtherSessions", Method: http.MethodDelete, Path: "/users/me/sessions", Tag: "Sessions", Summary: "Sign out everywhere else",
+		SessionOnly: true},
+	{ID: "revokeSession", Method: http.MethodDelete, Path: "/users/me/sessions/{session_id}", Tag: "Sessions", Summary: "Sign out one device",
+		SessionOnly: true},
+	{ID: "getMyPreferences", Method: http.MethodGet, Path: "/users/me/preferences", Tag: "Users", Summary: "Settings of the current user",
+		Description: "Defaults until the user changes a preference.",
+		SessionOnly: true, Response: dto.UserPreferencesResponse{}},
+	{ID: "patchMyPreferences", Method: http.MethodPatch, Path: "/users/me/preferences", Tag: "Users", Summary: "Change some of the current user's settings",
+		SessionOnly: true, Request: dto.UpdateUserPreferencesRequest{}, MergePatch: true, Response: dto.UserPreferencesResponse{}},
+	{ID: "getMyUsage", Method: http.MethodGet, Path: "/users/me/usage", Tag: "Users", Summary: "Usage of the current user against the quotas",
+		Description: "Projects the user owns, and the tables and collaborators of each. A limit of 0 means there is none.",
+		SessionOnly: true, Response: dto.UsageResponse{}},
+	{ID: "getMyDataExport", Method: http.MethodGet, Path: "/users/me/export", Tag: "Users", Summary: "Archive of the current user's data",
+		Description: "Profile, preferences, owned projects with their schema, collaborations, API tokens and activity as JSON files in a zip. " +
+			"Starts building one when there is none; responds with 202 until it is ready and has a download_url.",
+		SessionOnly: true, OwnSession: true, Response: dto.DataExportResponse{}},
+	{ID: "startMyDataExport", Method: http.MethodPost, Path: "/users/me/export", Tag: "Users", Summary: "Build a fresh archive of the current user's data",
+		SessionOnly: true, OwnSession: true, Response: dto.DataExportResponse{}, Status: http.StatusAccepted},
+	{ID: "uploadMyAvatar", Method: http.MethodPost, Path: "/users/me/avatar", Tag: "Users", Summary: "Set the current user's avatar",
+		Description: "PNG, JPEG, GIF or WebP image, cropped to a square and resized. avatar_url of the returned user points to it.",
+		SessionOnly: true, Upload: "avatar", Response: dto.UserResponse{}},
+	{ID: "deleteMyAvatar", Method: http.MethodDelete, Path: "/users/me/avatar", Tag: "Users", Summary: "Remove the current user's avatar",
+		SessionOnly: true, Response: dto.UserResponse{}},
+
+	{ID: "downloadDataExport", Method: http.MethodGet, Path: "/exports/{export_id}/download", Tag: "Users", Summary: "Download a data export",
+		Description: "Use the download_url of the export, which is signed and works without signing in until the export expires.",
+		Public:      true, ContentType: "application/zip", Query: []openapi.QueryParam{
+			{Name: "expires", Type: "integer", Description: "Unix time the link expires"},
+			{Name: "signature", Description: "Signature of the link"},
+		}},
+
+	// Users
+	{ID: "deleteMyAccount", Method: http.MethodDelete, Path: "/users/me", Tag: "Users", Summary: "Delete the current user's account",
+		Description: "Confirm with the password, or with confirm_email for accounts that only sign in through SSO. " +
+			"Owned projects without collaborators are deleted. Those with collaborators are transferred with project_transfers " +
+			"or deleted with delete_shared_projects; otherwise 409 lists them in data. Sessions and API tokens stop working " +
+			"and admin audit entries no longer name the user.",
+		SessionOnly: true, Request: dto.DeleteAccountRequest{}},
+	{ID: "listUsers", Method: http.MethodGet, Path: "/users", Tag: "Users", Summary: "List users", SessionOnly: true, Response: []dto.UserResponse{},
+		Query: []openapi.QueryParam{{Name: "q", Description: "Matches email or username"}},
+		Sort:  []string{"created_at", "username", "email"}},
+	{ID: "getUser", Method: http.MethodGet, Path: "/users/{user_id}", Tag: "Users", Summary: "Get a user", SessionOnly: true, Response: dto.UserResponse{}},
+	{ID: "updateUser", Method: http.MethodPut, Path: "/users/{user_id}", Tag: "Users", Summary: "Update a user", SessionOnly: true,
+		Request: dto.UpdateUserRequest{}, Response: dto.UserResponse{},
+		Description: "Responds with 409 when the email or username is taken, naming it in the field of the data."},
+	{ID: "deleteUser", Method: http.MethodDelete, Path: "/users/{user_id}", Tag: "Users", Summary: "Delete a user", SessionOnly: true},
+	{ID: "updatePassword", Method: http.MethodPut, Path: "/users/{user_id}/password", Tag: "Users", Summary: "Change a password", SessionOnly: true, OwnSession: true,
+		Request: dto.UpdatePasswordRequest{}},
+
+	// Personal access tokens
+	{ID: "createAPIToken", Method: http.MethodPost, Path: "/tokens", Tag: "Tokens", Summary: "Create a personal access token",
+		Description: "The plaintext token is only returned once.", SessionOnly: true, OwnSession: true,
+		Request: dto.CreateAPITokenRequest{}, Response: dto.CreateAPITokenResponse{}, Status: http.StatusCreated},
+	{ID: "listAPITokens", Method: http.MethodGet, Path: "/tokens", Tag: "Tokens", Summary: "List own tokens", SessionOnly: true, OwnSession: true,
+		Response: []dto.APITokenResponse{}},
+	{ID: "revokeAPIToken", Method: http.MethodDelete, Path: "/tokens/{token_id}", Tag: "Tokens", Summary: "Revoke own token", SessionOnly: true, OwnSession: true},
+
+	// Search
+	{ID: "search", Method: http.MethodGet, Path: "/search", Tag: "Search", Summary: "Search projects, tables and fields by name",
+		Description: "Matches every word of the query as the start of a word in project names and descriptions, table names " +
+			"and field names, within the projects the user owns or collaborates on. Best matches first.",
+		Response: []dto.SearchResultResponse{},
+		Query: []openapi.QueryParam{
+			{Name: "q", Description: "Words to find, e.g. cust id"},
+			{Name: "project_id", Description: "Only this project"},
+			{Name: "type", Description: "Comma-separated kinds to match: project, table, field"},
+			{Name: "limit", Type: "integer", Description: "At most 100, 20 by default"},
+		}},
+
+	// Projects
+	{ID: "createProject", Method: http.MethodPost, Path: "/projects", Tag: "Projects", Summary: "Create a project",
+		Description: "Responds with 402 when the user already owns as many projects as the quota allows.",
+		Request:     dto.CreateProjectRequest{}, Response: dto.ProjectSummaryResponse{}, Status: http.StatusCreated},
+	{ID: "listProjects", Method: http.MethodGet, Path: "/projects", Tag: "Projects", Summary: "List projects", Response: []dto.ProjectSummaryResponse{},
+		Query: []openapi.QueryParam{
+			{Name: "name", Description: "Matches part of the name"},
+			{Name: "owner_id", Description: "Only projects this user owns"},
+		},
+		Sort: []string{"created_at", "updated_at", "name"}},
+	{ID: "listMyProjects", Method: http.MethodGet, Path: "/projects/my", Tag: "Projects", Summary: "Projects the user owns or collaborates on",
+		Response: []dto.MyProjectResponse{},
+		Query: []openapi.QueryParam{
+			{Name: "tag", Description: "Only projects with this tag"},
+			{Name: "starred", Type: "boolean", Description: "Only the user's favorites when true"},
+			{Name: "include", Description: "Comma-separated extras: collaborators, tables_count. Neither is loaded by default"},
+		}},
+	{ID: "listRecentProjects", Method: http.MethodGet, Path: "/projects/recent", Tag: "Projects", Summary: "Projects the user opened last",
+		Description: "Opening a project with getProject or getFullProject records a view. Most recent first, once per project.",
+		Response:    []dto.RecentProjectResponse{},
+		Query:       []openapi.QueryParam{{Name: "limit", Type: "integer", Description: "At most 50, 10 by default"}}},
+	{ID: "getProject", Method: http.MethodGet, Path: "/projects/{project_id}", Tag: "Projects", Summary: "Get a project with its schema",
+		Response: dto.ProjectResponse{}},
+	{ID: "getFullProject", Method: http.MethodGet, Path: "/projects/{project_id}/full", Tag: "Projects", Summary: "Get a project with its schema and active collaborators",
+		Description: "Everything the canvas needs in one request. Sends an ETag and answers 304 when If-None-Match holds it.",
+		Response:    dto.FullProjectResponse{}},
+	{ID: "updateProject", Method: http.MethodPut, Path: "/projects/{project_id}", Tag: "Projects", Summary: "Update a project",
+		Request: dto.UpdateProjectRequest{}, Versioned: true, Response: dto.ProjectSummaryResponse{}, Sequenced: true},
+	{ID: "patchProject", Method: http.MethodPatch, Path: "/projects/{project_id}", Tag: "Projects", Summary: "Change some of a project's properties",
+		Request: dto.UpdateProjectRequest{}, MergePatch: true, Versioned: true, Response: dto.ProjectSummaryResponse{}, Sequenced: true},
+	{ID: "deleteProject", Method: http.MethodDelete, Path: "/projects/{project_id}", Tag: "Projects", Summary: "Delete a project", SessionOnly: true},
+	{ID: "addCollaborator", Method: http.MethodPost, Path: "/projects/{project_id}/collaborators", Tag: "Projects", Summary: "Add a collaborator",
+		Description: "Responds with 402 when the project already has as many collaborators as the quota allows. Service accounts do not count.",
+		SessionOnly: true, Request: dto.AddCollaboratorRequest{}},
+	{ID: "removeCollaborator", Method: http.MethodDelete, Path: "/projects/{project_id}/collaborators/{user_id}", Tag: "Projects", Summary: "Remove a collaborator",
+		Description: "Their WebSockets to the project get an access_revoked message and are closed.", SessionOnly: true},
+	{ID: "setCollaboratorRole", Method: http.MethodPut, Path: "/projects/{project_id}/collaborators/{user_id}/role", Tag: "Projects", Summary: "Make a collaborator an editor or a viewer",
+		Description: "Owner only. Collaborators are editors until made viewers, who can open the project but not change it. The collaborator's WebSockets get a permissions_updated message.",
+		SessionOnly: true, Request: dto.SetCollaboratorRoleRequest{}},
+	{ID: "kickCollaborator", Method: http.MethodPost, Path: "/projects/{project_id}/collaborators/{user_id}/kick", Tag: "Projects", Summary: "Disconnect a collaborator",
+		Description: "Owner only. The collaborator's WebSockets to the project get an access_revoked message and are closed; they keep access and may reconnect.", SessionOnly: true},
+	{ID: "addProjectTag", Method: http.MethodPut, Path: "/projects/{project_id}/tags/{tag}", Tag: "Projects", Summary: "Label a project",
+		Description: "Tags are lowercase letters, digits, - and _, at most 50 characters, and shared by everyone on the project. A project has at most 20."},
+	{ID: "removeProjectTag", Method: http.MethodDelete, Path: "/projects/{project_id}/tags/{tag}", Tag: "Projects", Summary: "Remove a label from a project"},
+	{ID: "starProject", Method: http.MethodPut, Path: "/projects/{project_id}/star", Tag: "Projects", Summary: "Add a project to the user's favorites"},
+	{ID: "unstarProject", Method: http.MethodDelete, Path: "/projects/{project_id}/star", Tag: "Projects", Summary: "Remove a project from the user's favorites"},
+	{ID: "createSchema", Method: http.MethodPost, Path: "/projects/{project_id}/schema", Tag: "Projects", Summary: "Add tables, fields and relationships at once",
+		Description: "Everything is created in one transaction, or nothing is when any part fails. Relationships name their tables and fields, looked up in the request first, then in the project. " +
+			"Responds with 402 when the project would have more tables than the quota allows. " +
+			"Tables named like existing ones respond with 409 listing the conflicts, until conflicts in the request choose a strategy for each: " +
+			"skip keeps the existing table, rename creates the table as new_name or name_2, overwrite replaces the existing table and its relationships, and merge adds the fields it lacks to it. " +
+			"Relationships keep naming tables as in the request. A dry run checks everything the same way and responds with 200 and what would be created, listing every conflict, without saving anything.",
+		Request: dto.CreateSchemaRequest{}, Response: dto.SchemaResponse{}, Status: http.StatusCreated, Sequenced: true, Query: []openapi.QueryParam{dryRunQueryParam}},
+	{ID: "importSchema", Method: http.MethodPost, Path: "/projects/{project_id}/schema/import", Tag: "Projects", Summary: "Add tables defined in a spreadsheet",
+		Description: "An .xlsx workbook or .csv file of at most 5 MB. Each sheet is a table named after it, or after the file for CSV, and each row below the header defines one of its columns. " +
+			"The header names the name and type columns, and optionally nullable, default, primary key and table, which puts a row in another table. " +
+			"Everything is created in one transaction; problems with the file are reported by cell, e.g. file.Users!B4. Conflicts are resolved and dry runs respond like for createSchema.",
+		Upload: "file", Form: dto.ImportSchemaForm{}, Response: dto.SchemaResponse{}, Status: http.StatusCreated, Sequenced: true, Query: []openapi.QueryParam{dryRunQueryParam}},
+	{ID: "compareProjects", Method: http.MethodGet, Path: "/projects/{project_id}/compare/{other_project_id}", Tag: "Projects", Summary: "Diff a project's schema against another's",
+		Description: "Lists what changes from the schema of the project to the schema of the other one, e.g. to check a model against an import of the live database. " +
+			"Tables are matched by name, fields by name within their table and relationships by the fields they link; layout is ignored, and data types are compared regardless of case. " +
+			"The user needs access to both projects, and project-restricted API tokens are rejected.",
+		Response: dto.SchemaDiffResponse{}},
+	{ID: "inferSchema", Method: http.MethodPost, Path: "/projects/{project_id}/schema/infer", Tag: "Projects", Summary: "Propose tables for MongoDB collections",
+		Description: "Infers tables from sample documents in MongoDB extended JSON, or from a Mongoose schema definition, and creates nothing. " +
+			"Embedded documents are flattened into prefixed columns, arrays of documents become child tables and ObjectIds named after another collection, or with a Mongoose ref, become relationships. " +
+			"The proposed schema can be edited and sent to createSchema; notes list what could not be inferred with confidence.",
+		Request: dto.InferSchemaRequest{}, Response: dto.InferSchemaResponse{}},
+	{ID: "generateData", Method: http.MethodPost, Path: "/projects/{project_id}/generate-data", Tag: "Projects", Summary: "Generate seed data for a project's tables",
+		Description: "Responds with a zip of fake but plausible rows for every table: seed.sql with INSERT statements in foreign key order, or with format csv a CSV file per table, numbered in the order they load in. " +
+			"Primary keys, one-to-one foreign keys and identifier-like columns such as email are unique, and values follow the data type and name of their column. " +
+			"Nullable foreign keys between tables that reference each other are left null; tables referencing each other through required ones are rejected. Send the same seed to get the same rows again.",
+		Request: dto.GenerateDataRequest{}, ContentType: "application/zip"},
+	{ID: "exportSchema", Method: http.MethodGet, Path: "/projects/{project_id}/export", Tag: "Projects", Summary: "Export a project's schema",
+		Description: "Responds with the schema as a file: with format sql, CREATE TABLE statements in the dialect of the project's database type followed by its foreign keys; with format json, a request to createSchema creating the same tables, fields and relationships again, layout included; " +
+			"with format dbt, a zip scaffolding the staging layer of a dbt project: a source named after the project declaring every table, with unique, not_null and relationships tests following from keys and foreign keys, and a staging model per table under models/staging/<source>; " +
+			"the project's snippets follow the statements of an sql export as an appendix and are analyses of a dbt one; the json export carries the metadata of tables, fields and relationships, and the metadata of the project, tables and fields is the meta of the dbt source, its tables and columns; " +
+			"with format avro, a zip of an Avro record schema per table; with format protobuf, a proto3 file with a message per table, its fields numbered in table order. " +
+			"Both map data types by kind, with logical types or comments for UUIDs, timestamps, dates and decimals, and make nullable fields outside the primary key optional. " +
+			"With release, the schema is the one of the project's release of that name, which stays the same however the project changes; snippets are left out.",
+		Query: []openapi.QueryParam{
+			{Name: "format", Description: "sql (default), json, dbt, avro or protobuf"},
+			{Name: "release", Description: "Name of a release to export instead of the current schema"},
+		},
+		ContentType: "application/octet-stream"},
+	{ID: "planApply", Method: http.MethodPost, Path: "/projects/{project_id}/apply/plan", Tag: "Projects", Summary: "Plan bringing a database to a project's model",
+		Description: "Connects to the PostgreSQL database of the connection string, which is not stored and must name a public host, compares it to the model as drift checks do and lists the statements bringing it to the model, changing nothing. " +
+			"Foreign keys are dropped first and added last; destructive statements drop tables or columns or convert columns. Primary key and relationship type changes are listed as unsupported, to be made by hand. " +
+			"Only PostgreSQL projects can be applied, by those who may modify the project; a field whose data type or default value is not a plain type or literal is refused with 422.",
+		Request: dto.PlanApplyRequest{}, Response: dto.ApplyPlanResponse{}},
+	{ID: "executeApply", Method: http.MethodPost, Path: "/projects/{project_id}/apply/execute", Tag: "Projects", Summary: "Bring a database to a project's model",
+		Description: "Plans again and runs the statements in order, stopping at the first that fails: with transaction single, the default, in one transaction rolled back on failure; with per_statement, each in its own, so those before the failure stay applied. " +
+			"The checksum of the reviewed plan is required, the execution being refused with 409 if the plan has changed since; pass allow_destructive to run a plan that drops or converts data. " +
+			"The log gives the status of every statement: applied, failed, rolled_back or skipped.",
+		Request: dto.ExecuteApplyRequest{}, Response: dto.ApplyLogResponse{}},
+	{ID: "lintSchema", Method: http.MethodGet, Path: "/projects/{project_id}/lint", Tag: "Projects", Summary: "Check a project's schema for normalization problems",
+		Description: "Deterministic checks of the names, types and keys of fields, each finding with the rule it breaks and an explanation: " +
+			"1nf-repeating-group for numbered fields such as phone1 and phone2, 1nf-multi-valued-field for text fields named for lists such as tag_ids, " +
+			"2nf-partial-dependency for fields named after part of a composite key such as product_name keyed by order_id and product_id, " +
+			"3nf-transitive-dependency for fields named after a foreign key such as customer_name next to customer_id. Findings are hints, not errors.",
+		Response: []dto.LintFindingResponse{}},
+	{ID: "renameSchemaObject", Method: http.MethodPost, Path: "/projects/{project_id}/refactor/rename", Tag: "Projects", Summary: "Rename a table or field",
+		Description: "Renames the table, or its field when field_id is given, and records the rename. Plans applying the model to a database rename the table or column there, " +
+			"where it still has its old name, rather than dropping and creating it. Relationships follow, as they link tables and fields by ID. " +
+			"Responds with 409 when another table of the project, or another field of the table, has the name.",
+		Request: dto.RenameRequest{}, Response: dto.RenameResponse{}, Status: http.StatusCreated, Sequenced: true},
+	{ID: "listRenames", Method: http.MethodGet, Path: "/projects/{project_id}/refactor/renames", Tag: "Projects", Summary: "List renamed tables and fields",
+		Description: "The renames made by refactoring, oldest first, as applying the model replays them.",
+		Response:    []dto.RenameResponse{}},
+	{ID: "suggestRelationships", Method: http.MethodGet, Path: "/projects/{project_id}/suggestions/relationships", Tag: "Projects", Summary: "Suggest missing relationships",
+		Description: "Proposes a one to many relationship for every field named after a table, such as user_id or userId for users, whose type is of the kind of that table's primary key, unless the field is already related. " +
+			"One to one is proposed when the field is its table's sole primary key. Nothing is created.",
+		Response: []dto.RelationshipSuggestionResponse{}},
+	{ID: "acceptRelationshipSuggestions", Method: http.MethodPost, Path: "/projects/{project_id}/suggestions/relationships", Tag: "Projects", Summary: "Create suggested relationships",
+		Description: "Creates the relationships, named as suggestions name them, in one transaction. Relationships linking fields already linked are skipped, so accepting twice creates nothing more.",
+		Request:     dto.AcceptRelationshipSuggestionsRequest{}, Response: []dto.RelationshipResponse{}, Status: http.StatusCreated, Sequenced: true},
+
+	// Tables
+	{ID: "createTable", Method: http.MethodPost, Path: "/projects/{project_id}/tables", Tag: "Tables", Summary: "Create a table",
+		Description: "Responds with 402 when the project already has as many tables as the quota allows, and with 409 when another table of the project has the name.",
+		Request:     dto.CreateTableRequest{}, Response: dto.TableResponse{}, Status: http.StatusCreated, Sequenced: true},
+	{ID: "listTables", Method: http.MethodGet, Path: "/projects/{project_id}/tables", Tag: "Tables", Summary: "List tables", Response: []dto.TableResponse{},
+		Query: []openapi.QueryParam{{Name: "name", Description: "Matches part of the name"}},
+		Sort:  []string{"created_at", "updated_at", "name"}},
+	{ID: "getTable", Method: http.MethodGet, Path: "/projects/{project_id}/tables/{table_id}", Tag: "Tables", Summary: "Get a table", Response: dto.TableResponse{}},
+	{ID: "updateTable", Method: http.MethodPut, Path: "/projects/{project_id}/tables/{table_id}", Tag: "Tables", Summary: "Update a table",
+		Request: dto.UpdateTableRequest{}, Versioned: true, Response: dto.TableResponse{}, Sequenced: true,
+		Description: "Responds with 409 when another table of the project has the name."},
+	{ID: "patchTable", Method: http.MethodPatch, Path: "/projects/{project_id}/tables/{table_id}", Tag: "Tables", Summary: "Change some of a table's properties",
+		Request: dto.UpdateTableRequest{}, MergePatch: true, Versioned: true, Response: dto.TableResponse{}, Sequenced: true},
+	{ID: "deleteTable", Method: http.MethodDelete, Path: "/projects/{project_id}/tables/{table_id}", Tag: "Tables", Summary: "Delete a table", Sequenced: true},
+	{ID: "updateTablePosition", Method: http.MethodPut, Path: "/projects/{project_id}/tables/{table_id}/position", Tag: "Tables", Summary: "Move a table on the canvas",
+		Request: dto.UpdateTablePositionRequest{}},
+
+	// Fields
+	{ID: "createField", Method: http.MethodPost, Path: "/projects/{project_id}/tables/{table_id}/fields", Tag: "Fields", Summary: "Create a field",
+		Request: dto.CreateFieldRequest{}, Response: dto.FieldResponse{}, Status: http.StatusCreated, Sequenced: true,
+		Description: "Responds with 409 when another field of the table has the name."},
+	{ID: "createFields", Method: http.MethodPost, Path: "/projects/{project_id}/tables/{table_id}/fields/bulk", Tag: "Fields", Summary: "Create several fields at once",
+		Request: dto.BulkCreateFieldsRequest{}, Response: []dto.FieldResponse{}, Status: http.StatusCreated, Sequenced: true,
+		Description: "Responds with 409, creating none of the fields, when a field of the table has the name of one."},
+	{ID: "listFields", Method: http.MethodGet, Path: "/projects/{project_id}/tables/{table_id}/fields", Tag: "Fields", Summary: "List fields",
+		Response: []dto.FieldResponse{}},
+	{ID: "reorderFields", Method: http.MethodPut, Path: "/projects/{project_id}/tables/{table_id}/fields/reorder", Tag: "Fields", Summary: "Reorder fields",
+		Request: dto.ReorderFieldsRequest{}, Response: []dto.FieldResponse{}, Sequenced: true,
+		Description: "Returns every field of the table in its new order. With base_sequence, a reorder confirmed after that sequence wins: the request fails with 409 and the order it left."},
+	{ID: "getField", Method: http.MethodGet, Path: "/projects/{project_id}/tables/{table_id}/fields/{field_id}", Tag: "Fields", Summary: "Get a field",
+		Response: dto.FieldResponse{}},
+	{ID: "updateField", Method: http.MethodPut, Path: "/projects/{project_id}/tables/{table_id}/fields/{field_id}", Tag: "Fields", Summary: "Update a field",
+		Request: dto.UpdateFieldRequest{}, Versioned: true, Response: dto.FieldResponse{}, Sequenced: true,
+		Description: "Responds with 409 when another field of the table has the name."},
+	{ID: "patchField", Method: http.MethodPatch, Path: "/projects/{project_id}/tables/{table_id}/fields/{field_id}", Tag: "Fields", Summary: "Change some of a field's properties",
+		Request: dto.UpdateFieldRequest{}, MergePatch: true, Versioned: true, Response: dto.FieldResponse{}, Sequenced: true},
+	{ID: "deleteField", Method: http.MethodDelete, Path: "/projects/{project_id}/tables/{table_id}/fields/{field_id}", Tag: "Fields", Summary: "Delete a field", Sequenced: true},
+
+	// Relationships
+	{ID: "createRelationship", Method: http.MethodPost, Path: "/projects/{project_id}/relationships", Tag: "Relationships", Summary: "Create a relationship",
+		Request: dto.CreateRelationshipRequest{}, Response: dto.RelationshipResponse{}, Status: http.StatusCreated, Sequenced: true},
+	{ID: "listRelationships", Method: http.MethodGet, Path: "/projects/{project_id}/relationships", Tag: "Relationships", Summary: "List relationships",
+		Response: []dto.RelationshipResponse{}},
+	{ID: "getRelationship", Method: http.MethodGet, Path: "/projects/{project_id}/relationships/{relationship_id}", Tag: "Relationships", Summary: "Get a relationship",
+		Response: dto.RelationshipResponse{}},
+	{ID: "updateRelationship", Method: http.MethodPut, Path: "/projects/{project_id}/relationships/{relationship_id}", Tag: "Relationships", Summary: "Update a relationship",
+		Request: dto.UpdateRelationshipRequest{}, Versioned: true, Response: dto.RelationshipResponse{}, Sequenced: true},
+	{ID: "patchRelationship", Method: http.MethodPatch, Path: "/projects/{project_id}/relationships/{relationship_id}", Tag: "Relationships", Summary: "Change some of a relationship's properties",
+		Request: dto.UpdateRelationshipRequest{}, MergePatch: true, Versioned: true, Response: dto.RelationshipResponse{}, Sequenced: true},
+	{ID: "deleteRelationship", Method: http.MethodDelete, Path: "/projects/{project_id}/relationships/{relationship_id}", Tag: "Relationships", Summary: "Delete a relationship", Sequenced: true},
+
+	// Regions
+	{ID: "createRegion", Method: http.MethodPost, Path: "/projects/{project_id}/regions", Tag: "Regions", Summary: "Create a region",
+		Request: dto.CreateRegionRequest{}, Response: dto.RegionResponse{}, Status: http.StatusCreated, Sequenced: true,
+		Description: "A region is a named, colored area of the canvas grouping tables. A table is in at most one region; adding it to a region takes it out of any other."},
+	{ID: "listRegions", Method: http.MethodGet, Path: "/projects/{project_id}/regions", Tag: "Regions", Summary: "List regions",
+		Response: []dto.RegionResponse{}},
+	{ID: "getRegion", Method: http.MethodGet, Path: "/projects/{project_id}/regions/{region_id}", Tag: "Regions", Summary: "Get a region",
+		Response: dto.RegionResponse{}},
+	{ID: "updateRegion", Method: http.MethodPut, Path: "/projects/{project_id}/regions/{region_id}", Tag: "Regions", Summary: "Update a region",
+		Request: dto.UpdateRegionRequest{}, Versioned: true, Response: dto.RegionResponse{}, Sequenced: true},
+	{ID: "patchRegion", Method: http.MethodPatch, Path: "/projects/{project_id}/regions/{region_id}", Tag: "Regions", Summary: "Change some of a region's properties",
+		Request: dto.UpdateRegionRequest{}, MergePatch: true, Versioned: true, Response: dto.RegionResponse{}, Sequenced: true},
+	{ID: "moveRegion", Method: http.MethodPost, Path: "/projects/{project_id}/regions/{region_id}/move", Tag: "Regions", Summary: "Move a region with its tables",
+		Request: dto.MoveRegionRequest{}, Versioned: true, Response: dto.MoveRegionResponse{}, Sequenced: true,
+		Description: "Moves the region and every table in it by dx and dy in one transaction."},
+	{ID: "deleteRegion", Method: http.MethodDelete, Path: "/projects/{project_id}/regions/{region_id}", Tag: "Regions", Summary: "Delete a region",
+		Sequenced: true, Description: "The region's tables stay where they are."},
+
+	// Snapshots
+	{ID: "listSnapshots", Method: http.MethodGet, Path: "/projects/{project_id}/snapshots", Tag: "Snapshots", Summary: "List a project's snapshots",
+		Response:    []dto.SnapshotResponse{},
+		Description: "Newest first. A snapshot is taken nightly of every project changed since its latest one."},
+	{ID: "getSnapshotRetention", Method: http.MethodGet, Path: "/projects/{project_id}/snapshot-retention", Tag: "Snapshots", Summary: "Get how many snapshots a project keeps",
+		Response: dto.SnapshotRetentionResponse{}},
+	{ID: "setSnapshotRetention", Method: http.MethodPut, Path: "/projects/{project_id}/snapshot-retention", Tag: "Snapshots", Summary: "Change how many snapshots a project keeps",
+		Request: dto.SetSnapshotRetentionRequest{}, Response: dto.SnapshotRetentionResponse{},
+		Description: "Owner only. The latest snapshot of each of the last keep_daily days and keep_monthly months is kept, and always the latest one; a null count goes back to the instance default. Snapshots beyond the new retention are deleted at once; those pinned by a release are kept."},
+
+	// Doc
+	{ID: "getDoc", Method: http.MethodGet, Path: "/projects/{project_id}/doc", Tag: "Doc", Summary: "Get a project's doc",
+		Description: "The Markdown document kept with the project for the reasons behind its model, with its version as the ETag. A doc never edited is empty, at version 0.",
+		Query:       []openapi.QueryParam{{Name: "version", Type: "integer", Description: "Earlier version to get instead of the current one"}},
+		Response:    dto.DocResponse{}},
+	{ID: "updateDoc", Method: http.MethodPut, Path: "/projects/{project_id}/doc", Tag: "Doc", Summary: "Edit a project's doc",
+		Description: "Saves the content as the next version of the doc and sends doc_updated to collaborators. Content equal to the current doc's saves nothing. " +
+			"Answers 409 with the current doc when another edit was saved since the given version.",
+		Request: dto.UpdateDocRequest{}, Response: dto.DocResponse{}, Versioned: true, Sequenced: true},
+	{ID: "listDocRevisions", Method: http.MethodGet, Path: "/projects/{project_id}/doc/revisions", Tag: "Doc", Summary: "List the versions of a project's doc",
+		Response: []dto.DocRevisionResponse{}, Description: "Newest first, without their content."},
+	{ID: "renderDoc", Method: http.MethodGet, Path: "/projects/{project_id}/doc/render", Tag: "Doc", Summary: "Render a project's doc as HTML",
+		Description: "An HTML fragment of the doc, with GitHub's tables, strikethrough, autolinks and task lists. Raw HTML is left out and unsafe links dropped.",
+		Query:       []openapi.QueryParam{{Name: "version", Type: "integer", Description: "Earlier version to render instead of the current one"}},
+		ContentType: "text/html"},
+
+	// Branches
+	{ID: "createBranch", Method: http.MethodPost, Path: "/projects/{project_id}/branches", Tag: "Branches", Summary: "Branch a project's schema",
+		Request: dto.CreateBranchRequest{}, Response: dto.BranchResponse{}, Status: http.StatusCreated,
+		Description: "Copies the schema as it is now, layout included, to edit apart from the project and merge back. Names are unique within the project; 409 when taken."},
+	{ID: "listBranches", Method: http.MethodGet, Path: "/projects/{project_id}/branches", Tag: "Branches", Summary: "List a project's branches",
+		Response: []dto.BranchResponse{}, Description: "By name, without their schemas."},
+	{ID: "getBranch", Method: http.MethodGet, Path: "/projects/{project_id}/branches/{branch_id}", Tag: "Branches", Summary: "Get a branch with its schema",
+		Response: dto.BranchResponse{}},
+	{ID: "updateBranch", Method: http.MethodPut, Path: "/projects/{project_id}/branches/{branch_id}", Tag: "Branches", Summary: "Rename a branch or replace its schema",
+		Description: "The schema is checked like a createSchema request and replaces the branch's whole. " +
+			"Answers 409 with the current branch when it changed since the given version, and when it was merged already.",
+		Request: dto.UpdateBranchRequest{}, Response: dto.BranchResponse{}, Versioned: true},
+	{ID: "deleteBranch", Method: http.MethodDelete, Path: "/projects/{project_id}/branches/{branch_id}", Tag: "Branches", Summary: "Delete a branch"},
+	{ID: "diffBranch", Method: http.MethodGet, Path: "/projects/{project_id}/branches/{branch_id}/diff", Tag: "Branches", Summary: "Diff a project's schema against a branch",
+		Description: "Lists what changes from the project's schema as it is now to the branch's, matched like compareProjects does.",
+		Response:    dto.SchemaDiffResponse{}},
+	{ID: "mergeBranch", Method: http.MethodPost, Path: "/projects/{project_id}/branches/{branch_id}/merge", Tag: "Branches", Summary: "Merge a branch into its project",
+		Description: "Applies the changes made on the branch since it was made to the project in one transaction, sends collaborators the usual schema events and marks the branch merged. " +
+			"Tables, fields and relationships changed on one side only take that side's change. " +
+			"Answers 409 with the conflicts, and changes nothing, when the project changed any of them differently, removed what the branch changed or the other way round, " +
+			"or keeps a relationship linking what the other side removed. Layout and metadata are only taken for what the branch adds.",
+		Response: dto.BranchMergeResponse{}, Sequenced: true},
+
+	// Releases
+	{ID: "createRelease", Method: http.MethodPost, Path: "/projects/{project_id}/releases", Tag: "Releases", Summary: "Release a project's schema",
+		Request: dto.CreateReleaseRequest{}, Response: dto.ReleaseResponse{}, Status: http.StatusCreated,
+		Description: "Names the snapshot of snapshot_id, or a snapshot of the project taken now, such as v1.0; exportSchema fetches it by that name. " +
+			"The snapshot is pinned: it is kept whatever the snapshot retention. Names are unique within the project; 409 when taken."},
+	{ID: "listReleases", Method: http.MethodGet, Path: "/projects/{project_id}/releases", Tag: "Releases", Summary: "List a project's releases",
+		Response: []dto.ReleaseResponse{}, Description: "Newest first."},
+	{ID: "getRelease", Method: http.MethodGet, Path: "/projects/{project_id}/releases/{release_id}", Tag: "Releases", Summary: "Get a release",
+		Response: dto.ReleaseResponse{}},
+	{ID: "updateRelease", Method: http.MethodPut, Path: "/projects/{project_id}/releases/{release_id}", Tag: "Releases", Summary: "Edit, lock or unlock a release",
+		Description: "Renames a release, edits its notes or moves it to another snapshot of the project. A locked release answers 409 to any change but unlocking, which only the owner may do; " +
+			"the other changes of a request unlocking it apply after.",
+		Request: dto.UpdateReleaseRequest{}, Response: dto.ReleaseResponse{}},
+	{ID: "deleteRelease", Method: http.MethodDelete, Path: "/projects/{project_id}/releases/{release_id}", Tag: "Releases", Summary: "Delete a release",
+		Description: "Answers 409 for a locked release. The snapshot is left to the snapshot retention."},
+
+	// Chat
+	{ID: "sendChatMessage", Method: http.MethodPost, Path: "/projects/{project_id}/chat", Tag: "Chat", Summary: "Send a chat message to a project's collaborators",
+		Request: dto.SendChatMessageRequest{}, Response: dto.ChatMessageResponse{}, Status: http.StatusCreated,
+		Description: "Saves the message and delivers it as chat_message to the project's WebSockets, the sender's included. " +
+			"WebSocket clients can send chat_message with the content instead. Messages hold 1 to 4000 characters."},
+	{ID: "listChatMessages", Method: http.MethodGet, Path: "/projects/{project_id}/chat", Tag: "Chat", Summary: "A project's chat history",
+		Response: []dto.ChatMessageResponse{}, Description: "Most recent first unless sorted otherwise. Chat messages carry no sequence; clients read the history to catch up after reconnecting.",
+		Sort: []string{"created_at"}},
+	{ID: "listProjectEvents", Method: http.MethodGet, Path: "/projects/{project_id}/events", Tag: "Projects", Summary: "Schema changes of a project after a sequence",
+		Response: dto.ProjectEventsResponse{}, Description: "The schema-change events broadcast to the project's WebSockets after since_seq, oldest first, for clients and integrations catching up over REST. " +
+			"Only the latest events of each project are kept, 1000 by default. When the event right after since_seq is no longer kept, truncated is true and the client should reload the project. " +
+			"Page by asking again after the last sequence until it reaches latest_sequence.",
+		Query: []openapi.QueryParam{
+			{Name: "since_seq", Type: "integer", Description: "Sequence the client has seen, 0 by default"},
+			{Name: "limit", Type: "integer", Description: "At most 1000, 100 by default"},
+		}},
+
+	// Snippets
+	{ID: "createSnippet", Method: http.MethodPost, Path: "/projects/{project_id}/snippets", Tag: "Snippets", Summary: "Save SQL with a project",
+		Request: dto.CreateSnippetRequest{}, Response: dto.SnippetResponse{}, Status: http.StatusCreated,
+		Description: "Seed scripts, example queries and other SQL that explains the design. Names are unique within the project; 409 when taken."},
+	{ID: "listSnippets", Method: http.MethodGet, Path: "/projects/{project_id}/snippets", Tag: "Snippets", Summary: "List a project's snippets",
+		Response: []dto.SnippetResponse{}, Description: "By name."},
+	{ID: "getSnippet", Method: http.MethodGet, Path: "/projects/{project_id}/snippets/{snippet_id}", Tag: "Snippets", Summary: "Get a snippet",
+		Response: dto.SnippetResponse{}},
+	{ID: "updateSnippet", Method: http.MethodPut, Path: "/projects/{project_id}/snippets/{snippet_id}", Tag: "Snippets", Summary: "Update a snippet",
+		Request: dto.UpdateSnippetRequest{}, Response: dto.SnippetResponse{}},
+	{ID: "deleteSnippet", Method: http.MethodDelete, Path: "/projects/{project_id}/snippets/{snippet_id}", Tag: "Snippets", Summary: "Delete a snippet"},
+
+	// Review
+	{ID: "reviewSchema", Method: http.MethodPost, Path: "/projects/{project_id}/ai/review", Tag: "Review", Summary: "Review a project's schema",
+		Response: []dto.ReviewCommentResponse{},
+		Description: "For people who can edit the project. Points out normalization issues, unindexed foreign keys and inconsistent names, with a language model when the server has one configured and built-in rules otherwise. " +
+			"Each finding is kept as a comment; the open comments of the review are returned. Findings already commented on, open or resolved, are not commented on again. 502 when the model fails."},
+	{ID: "listReviewComments", Method: http.MethodGet, Path: "/projects/{project_id}/ai/review-comments", Tag: "Review", Summary: "List a project's review comments",
+		Response: []dto.ReviewCommentResponse{}, Description: "Oldest first, resolved or not."},
+	{ID: "resolveReviewComment", Method: http.MethodPost, Path: "/projects/{project_id}/ai/review-comments/{comment_id}/resolve", Tag: "Review", Summary: "Resolve a review comment",
+		Response: dto.ReviewCommentResponse{}, Description: "For people who can edit the project. Resolving a resolved comment changes nothing."},
+
+	// Drift
+	{ID: "getConnectionProfile", Method: http.MethodGet, Path: "/projects/{project_id}/connection-profile", Tag: "Drift", Summary: "Get how a project's live database is reached",
+		SessionOnly: true, Response: dto.ConnectionProfileResponse{},
+		Description: "For people who can edit the project. The password of the connection string is hidden and the webhook secret is never returned."},
+	{ID: "setConnectionProfile", Method: http.MethodPut, Path: "/projects/{project_id}/connection-profile", Tag: "Drift", Summary: "Set how a project's live database is reached",
+		SessionOnly: true, Request: dto.SetConnectionProfileRequest{}, Response: dto.ConnectionProfileResponse{},
+		Description: "Owner only. The connection string must be a postgres:// URL to a public host and is stored encrypted; use credentials that can only read, as checks never write. " +
+			"Omitting it keeps the current one. When scheduled_checks is on, the database is checked daily as well as on demand. " +
+			"The webhook is POSTed a drift.detected summary whenever a check finds drift that differs from the previous check's, signed in the X-EzModel-Signature header as sha256=<hex HMAC of the body> when a secret is set."},
+	{ID: "deleteConnectionProfile", Method: http.MethodDelete, Path: "/projects/{project_id}/connection-profile", Tag: "Drift", Summary: "Remove a project's connection profile",
+		SessionOnly: true, Description: "Owner only. Past drift checks are kept."},
+	{ID: "startDriftCheck", Method: http.MethodPost, Path: "/projects/{project_id}/drift-checks", Tag: "Drift", Summary: "Check a project's live database for drift",
+		Response: dto.DriftCheckResponse{}, Status: http.StatusAccepted,
+		Description: "Compares the live database to the model in the background; poll getDriftCheck for the result. A check already running is returned instead of starting another. " +
+			"Data types are compared by kind, e.g. varchar(255) and STRING match, and defaults and many to many relationships are ignored."},
+	{ID: "listDriftChecks", Method: http.MethodGet, Path: "/projects/{project_id}/drift-checks", Tag: "Drift", Summary: "List a project's drift checks",
+		Response: []dto.DriftCheckResponse{}, Description: "The latest 50, newest first, without their diffs."},
+	{ID: "getDriftCheck", Method: http.MethodGet, Path: "/projects/{project_id}/drift-checks/{check_id}", Tag: "Drift", Summary: "Get a drift check",
+		Response: dto.DriftCheckResponse{}, Description: "Completed checks have the diff from the model to the database: added tables exist only in the database, removed ones only in the model."},
+	{ID: "getDataProfile", Method: http.MethodGet, Path: "/projects/{project_id}/data-profile", Tag: "Drift", Summary: "Get how much data a project's tables and fields hold",
+		Response: dto.DataProfileResponse{},
+		Description: "Statistics of the latest profiling of the live database, attached to the tables and fields of the model with the same names: estimated row counts, null ratios and distinct counts. " +
+			"They are the database's own planner statistics, as current as its latest ANALYZE; columns it has none of, or the connecting user cannot read, are left out."},
+	{ID: "startDataProfile", Method: http.MethodPost, Path: "/projects/{project_id}/data-profile", Tag: "Drift", Summary: "Profile a project's live database",
+		Response: dto.DataProfileResponse{}, Status: http.StatusAccepted,
+		Description: "Reads the statistics in the background without scanning any table; poll getDataProfile until the status is no longer pending. A profiling already running is returned instead of starting another."},
+
+	// Service accounts
+	{ID: "createServiceAccount", Method: http.MethodPost, Path: "/projects/{project_id}/service-accounts", Tag: "Service Accounts", Summary: "Create a service account",
+		SessionOnly: true, Request: dto.CreateServiceAccountRequest{}, Response: dto.ServiceAccountResponse{}, Status: http.StatusCreated},
+	{ID: "listServiceAccounts", Method: http.MethodGet, Path: "/projects/{project_id}/service-accounts", Tag: "Service Accounts", Summary: "List service accounts",
+		SessionOnly: true, Response: []dto.ServiceAccountResponse{}},
+	{ID: "deleteServiceAccount", Method: http.MethodDelete, Path: "/projects/{project_id}/service-accounts/{service_account_id}", Tag: "Service Accounts",
+		Summary: "Delete a service account and its tokens", SessionOnly: true},
+	{ID: "createServiceAccountToken", Method: http.MethodPost, Path: "/projects/{project_id}/service-accounts/{service_account_id}/tokens", Tag: "Service Accounts",
+		Summary: "Create a service account token", Description: "The plaintext token is only returned once.", SessionOnly: true,
+		Request: dto.CreateAPITokenRequest{}, Response: dto.CreateAPITokenResponse{}, Status: http.StatusCreated},
+	{ID: "listServiceAccountTokens", Method: http.MethodGet, Path: "/projects/{project_id}/service-accounts/{service_account_id}/tokens", Tag: "Service Accounts",
+		Summary: "List service account tokens", SessionOnly: true, Response: []dto.APITokenResponse{}},
+	{ID: "revokeServiceAccountToken", Method: http.MethodDelete, Path: "/projects/{project_id}/service-accounts/{service_account_id}/tokens/{token_id}",
+		Tag: "Service Accounts", Summary: "Revoke a service account token", SessionOnly: true},
+
+	// Collaboration
+	{ID: "connectCollaboration", Method: http.MethodGet, Path: "/projects/{project_id}/collaborate", Tag: "Collaboration", Summary: "Open the real-time collaboration WebSocket",
+		Description: "Upgrades the connection. The client authenticates with its first message, or with the access_token cookie or Authorization header.",
+		Public:      true, Status: http.StatusSwitchingProtocols},
+	{ID: "createCollaborationSession", Method: http.MethodPost, Path: "/projects/{project_id}/sessions", Tag: "Collaboration", Summary: "Create a collaboration session",
+		Request: dto.CreateSessionRequest{}, Response: dto.CollaborationSessionResponse{}, Status: http.StatusCreated},
+	{ID: "listCollaborationSessions", Method: http.MethodGet, Path: "/projects/{project_id}/sessions", Tag: "Collaboration", Summary: "List collaboration sessions",
+		Response: []dto.CollaborationSessionResponse{}},
+	{ID: "listActiveCollaborationSessions", Method: http.MethodGet, Path: "/projects/{project_id}/sessions/active", Tag: "Collaboration",
+		Summary: "List active collaboration sessions", Response: []dto.CollaborationSessionResponse{}},
+	{ID: "getCollaborationSession", Method: http.MethodGet, Path: "/projects/{project_id}/sessions/{session_id}", Tag: "Collaboration",
+		Summary: "Get a collaboration session", Response: dto.CollaborationSessionResponse{}},
+	{ID: "updateCollaborationSession", Method: http.MethodPut, Path: "/projects/{project_id}/sessions/{session_id}", Tag: "Collaboration",
+		Summary: "Update a collaboration session", Request: dto.UpdateSessionRequest{}, Response: dto.CollaborationSessionResponse{}},
+	{ID: "deleteCollaborationSession", Method: http.MethodDelete, Path: "/projects/{project_id}/sessions/{session_id}", Tag: "Collaboration",
+		Summary: "Delete a collaboration session"},
+	{ID: "updateCollaborationCursor", Method: http.MethodPut, Path: "/projects/{project_id}/sessions/{session_id}/cursor", Tag: "Collaboration",
+		Summary: "Update the cursor position", Request: dto.UpdateCursorRequest{}},
+	{ID: "setCollaborationSessionInactive", Method: http.MethodPut, Path: "/projects/{project_id}/sessions/{session_id}/inactive", Tag: "Collaboration",
+		Summary: "Mark a collaboration session inactive"},
+
+	// GraphQL
+	{ID: "queryGraphQL", Method: http.MethodPost, Path: "/graphql", Tag: "GraphQL", Summary: "Run a GraphQL query or mutation",
+		Description: "Projects, tables, fields and relationships as a graph. Responds with the standard data and errors body rather than the JSON envelope. API tokens need read:projects for queries and write:schema for mutations, and cannot run delete_project.",
+		Request:     dto.GraphQLRequest{}, ContentType: "application/json"},
+	{ID: "subscribeGraphQL", Method: http.MethodGet, Path: "/graphql", Tag: "GraphQL", Summary: "Open a GraphQL subscription WebSocket",
+		Description: "Upgrades the connection to the graphql-transport-ws protocol. The project_events subscription streams collaboration messages.",
+		Status:      http.StatusSwitchingProtocols},
+
+	// Admin
+	{ID: "getInstanceStats", Method: http.MethodGet, Path: "/admin/stats", Tag: "Admin", Summary: "Instance-wide statistics",
+		Description: "Totals, WebSocket connections of the answering node, the largest tenants by stored size and signups per day. Aggregates are cached for a minute.",
+		Admin:       true, SessionOnly: true, Response: dto.InstanceStatsResponse{},
+		Query: []openapi.QueryParam{{Name: "days", Type: "integer", Description: "Days of signups, at most 365, 30 by default"}}},
+	{ID: "getWebSocketStats", Method: http.MethodGet, Path: "/admin/ws/stats", Tag: "Admin", Summary: "WebSocket hub statistics",
+		Admin: true, SessionOnly: true, Response: websocketPkg.HubStats{}},
+	{ID: "adminListUserTokens", Method: http.MethodGet, Path: "/admin/users/{user_id}/tokens", Tag: "Admin", Summary: "List a user's API tokens",
+		Admin: true, SessionOnly: true, Response: []dto.APITokenResponse{}},
+	{ID: "adminRevokeToken", Method: http.MethodDelete, Path: "/admin/tokens/{token_id}", Tag: "Admin", Summary: "Revoke any API token",
+		Admin: true, SessionOnly: true},
+	{ID: "adminListUsers", Method: http.MethodGet, Path: "/admin/users", Tag: "Admin", Summary: "List users with filters",
+		Description: "meta.total counts every matching user.",
+		Admin:       true, SessionOnly: true, Response: []dto.AdminUserResponse{}, Query: []openapi.QueryParam{
+			{Name: "q", Description: "Matches email or username"},
+			{Name: "role", Description: "user or admin"},
+			{Name: "disabled", Type: "boolean"},
+		},
+		Sort: []string{"created_at", "username", "email"}},
+	{ID: "adminDisableUser", Method: http.MethodPost, Path: "/admin/users/{user_id}/disable", Tag: "Admin", Summary: "Disable an account and revoke its sessions",
+		Admin: true, SessionOnly: true, Request: dto.SetUserDisabledRequest{}, Response: dto.AdminUserResponse{}},
+	{ID: "adminEnableUser", Method: http.MethodPost, Path: "/admin/users/{user_id}/enable", Tag: "Admin", Summary: "Re-enable an account",
+		Admin: true, SessionOnly: true, Request: dto.SetUserDisabledRequest{}, Response: dto.AdminUserResponse{}},
+	{ID: "adminSetUserRole", Method: http.MethodPut, Path: "/admin/users/{user_id}/role", Tag: "Admin", Summary: "Grant or remove the admin role",
+		Admin: true, SessionOnly: true, Request: dto.SetUserRoleRequest{}, Response: dto.AdminUserResponse{}},
+	{ID: "adminForcePasswordReset", Method: http.MethodPost, Path: "/admin/users/{user_id}/password-reset", Tag: "Admin", Summary: "Require a new password",
+		Admin: true, SessionOnly: true, Response: dto.AdminUserResponse{}},
+	{ID: "adminImpersonateUser", Method: http.MethodPost, Path: "/admin/users/{user_id}/impersonate", Tag: "Admin", Summary: "Start an audited support session as the user",
+		Description: "Replaces the session cookies with a short-lived session of the user.",
+		Admin:       true, SessionOnly: true, Request: dto.ImpersonateUserRequest{}, Response: dto.AdminUserResponse{}},
+	{ID: "adminListAuditLogs", Method: http.MethodGet, Path: "/admin/audit-logs", Tag: "Admin", Summary: "Admin action history",
+		Description: "Most recent first unless sorted otherwise.",
+		Admin:       true, SessionOnly: true, Response: []dto.AdminAuditLogResponse{},
+		Query: []openapi.QueryParam{
+			{Name: "user_id", Description: "Only entries about this user"},
+			{Name: "actor_id", Description: "Only actions by this admin"},
+			{Name: "action", Description: "e.g. user.disabled"},
+		},
+		Sort: []string{"created_at"}},
+}
@@ -1,10 +1,15 @@
 package routes
 
 import (
+	"fmt"
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/graphql"
 	"github.com/Bug-Bugger/ezmodel/internal/api/handlers"
 	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
 	"github.com/Bug-Bugger/ezmodel/internal/config"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/storage"
 	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
 	"github.com/go-chi/chi/v5"
 )
@@ -17,70 +22,263 @@ func SetupRoutes(
 	tableService services.TableServiceInterface,
 	fieldService services.FieldServiceInterface,
 	relationshipService services.RelationshipServiceInterface,
+	schemaService services.SchemaServiceInterface,
+	regionService services.RegionServiceInterface,
 	collaborationService services.CollaborationSessionServiceInterface,
+	oauthService services.OAuthServiceInterface,
+	samlService services.SAMLServiceInterface,
+	apiTokenService services.APITokenServiceInterface,
+	serviceAccountService services.ServiceAccountServiceInterface,
+	loginSecurityService services.LoginSecurityServiceInterface,
+	userSessionService services.UserSessionServiceInterface,
+	adminUserService services.AdminUserServiceInterface,
+	adminStatsService services.AdminStatsServiceInterface,
+	searchService services.SearchServiceInterface,
+	userPreferencesService services.UserPreferencesServiceInterface,
+	usageService services.UsageServiceInterface,
+	billingService services.BillingServiceInterface,
+	avatarService services.AvatarServiceInterface,
+	accountDeletionService services.AccountDeletionServiceInterface,
+	dataExportService services.DataExportServiceInterface,
+	snapshotService services.SnapshotServiceInterface,
+	driftService services.DriftServiceInterface,
+	snippetService services.SnippetServiceInterface,
+	reviewService services.ReviewServiceInterface,
+	docService services.DocServiceInterface,
+	branchService services.BranchServiceInterface,
+	releaseService services.ReleaseServiceInterface,
+	chatService services.ChatServiceInterface,
+	eventHistoryService services.EventHistoryServiceInterface,
+	authService services.AuthorizationServiceInterface,
 	jwtService *services.JWTService,
 	authMiddleware *middleware.AuthMiddleware,
+	adminMiddleware *middleware.AdminMiddleware,
+	rateLimitMiddleware *middleware.RateLimitMiddleware,
+	idempotencyMiddleware *middleware.IdempotencyMiddleware,
+	csrfMiddleware *middleware.CSRFMiddleware,
 	websocketHub *websocketPkg.Hub,
+	metricsHandler http.Handler,
+	readinessChecks []handlers.ReadinessCheck,
+	uploadsHandler http.Handler,
 ) {
 	// Basic routes
 	r.Get("/", handlers.HomeHandler())
+	if metricsHandler != nil {
+		r.Handle("/metrics", metricsHandler) // Prometheus scrape endpoint
+	}
+	healthHandler := handlers.NewHealthHandler(readinessChecks)
+	r.Get("/healthz", healthHandler.Liveness()) // Liveness probe
+	r.Get("/readyz", healthHandler.Readiness()) // Readiness probe checking the dependencies
+	if uploadsHandler != nil {
+		r.Handle(storage.LocalPath+"/*", http.StripPrefix(storage.LocalPath, uploadsHandler)) // Avatars on local disk
+	}
+
+	// API documentation
+	apiDocument := APIDocument()
+	r.Get("/openapi.json", handlers.OpenAPIHandler(apiDocument))                   // OpenAPI 3 document
+	r.Get("/docs", handlers.DocsHandler("/openapi.json"))                          // Swagger UI
+	r.Get("/sdk/ezmodel-client.ts", handlers.TypeScriptClientHandler(apiDocument)) // Generated TypeScript client
 
 	// Handlers
 	userHandler := handlers.NewUserHandler(userService)
-	authHandler := handlers.NewAuthHandler(userService, jwtService, cfg)
-	projectHandler := handlers.NewProjectHandler(projectService)
+	accountDeletionHandler := handlers.NewAccountDeletionHandler(accountDeletionService, cfg)
+	authHandler := handlers.NewAuthHandler(loginSecurityService, userSessionService, jwtService, cfg)
+	oauthHandler := handlers.NewOAuthHandler(oauthService, userSessionService, jwtService, cfg)
+	projectHandler := handlers.NewProjectHandler(projectService, websocketHub)
 	tableHandler := handlers.NewTableHandler(tableService)
 	fieldHandler := handlers.NewFieldHandler(fieldService)
 	relationshipHandler := handlers.NewRelationshipHandler(relationshipService)
+	schemaHandler := handlers.NewSchemaHandler(schemaService)
+	regionHandler := handlers.NewRegionHandler(regionService)
+	snapshotHandler := handlers.NewSnapshotHandler(snapshotService)
+	snippetHandler := handlers.NewSnippetHandler(snippetService)
+	reviewHandler := handlers.NewReviewHandler(reviewService)
+	docHandler := handlers.NewDocHandler(docService)
+	branchHandler := handlers.NewBranchHandler(branchService)
+	releaseHandler := handlers.NewReleaseHandler(releaseService)
+	chatHandler := handlers.NewChatHandler(chatService)
+	eventHandler := handlers.NewEventHandler(eventHistoryService)
 	collaborationHandler := handlers.NewCollaborationHandler(collaborationService)
-	websocketHandler := handlers.NewWebSocketHandler(cfg, websocketHub, jwtService, userService, projectService, tableService)
+	websocketHandler := handlers.NewWebSocketHandler(cfg, websocketHub, jwtService, userSessionService, userService, projectService, tableService, fieldService, chatService)
+	adminHandler := handlers.NewAdminHandler(websocketHub, adminStatsService)
+	apiTokenHandler := handlers.NewAPITokenHandler(apiTokenService)
+	serviceAccountHandler := handlers.NewServiceAccountHandler(serviceAccountService)
+	userSessionHandler := handlers.NewUserSessionHandler(userSessionService)
+	adminUserHandler := handlers.NewAdminUserHandler(adminUserService, jwtService, cfg)
+	searchHandler := handlers.NewSearchHandler(searchService)
+	userPreferencesHandler := handlers.NewUserPreferencesHandler(userPreferencesService)
+	usageHandler := handlers.NewUsageHandler(usageService)
+
+	graphQLSchema, err := graphql.NewSchema(graphql.NewResolver(projectService, tableService, fieldService, relationshipService, authService, websocketHub))
+	if err != nil {
+		panic(fmt.Sprintf("building GraphQL schema: %v", err))
+	}
+	graphQLHandler := handlers.NewGraphQLHandler(cfg, graphQLSchema)
 
 	// Mount all API routes under /api prefix
 	r.Route("/api", func(r chi.Router) {
 		// API info route
 		r.Get("/", handlers.APIHandler())
 
-		// Public auth routes
-		r.Post("/login", authHandler.Login())
-		r.Post("/refresh-token", authHandler.RefreshToken())
-		r.Post("/register", userHandler.Create())
-		r.Post("/logout", authHandler.Logout())
+		// Public auth routes, limited per client IP against brute forcing
+		r.Group(func(r chi.Router) {
+			r.Use(rateLimitMiddleware.PerIP("auth", cfg.RateLimit.AuthRequests, cfg.RateLimit.AuthWindow))
+
+			r.Post("/login", authHandler.Login())
+			r.Post("/refresh-token", authHandler.RefreshToken()) // No CSRF check: it only rotates the caller's own cookies and issues a fresh CSRF token
+			r.Post("/register", userHandler.Create())
+
+			// OAuth login routes
+			r.Get("/auth/oauth/{provider}/start", oauthHandler.Start())       // Redirect to provider consent page
+			r.Get("/auth/oauth/{provider}/callback", oauthHandler.Callback()) // Provider redirects back here
+
+			// SAML single sign-on routes, only mounted when SAML is configured
+			if samlService != nil {
+				samlHandler := handlers.NewSAMLHandler(samlService, userSessionService, jwtService, cfg)
+				r.Get("/auth/saml/metadata", samlHandler.Metadata()) // Service provider metadata for the IdP
+				r.Get("/auth/saml/login", samlHandler.Login())       // Redirect to the IdP
+				r.Post("/auth/saml/acs", samlHandler.ACS())          // IdP posts the SAML response here
+			}
+		})
+		r.With(csrfMiddleware.Protect).Post("/logout", authHandler.Logout())
+
+		// Stripe webhooks, authorized by their signature; only mounted when billing is configured
+		if billingService != nil {
+			r.Post("/billing/webhook", handlers.NewBillingHandler(billingService).Webhook())
+		}
+
+		// Data export downloads, authorized by the signature in the link
+		if dataExportService != nil {
+			r.Get("/exports/{export_id}/download", handlers.NewDataExportHandler(dataExportService).Download())
+		}
 
 		// WebSocket routes (handle authentication internally)
 		r.Get("/projects/{project_id}/collaborate", websocketHandler.HandleWebSocket) // WebSocket endpoint for real-time collaboration
 
 		// Protected routes
 		r.Group(func(r chi.Router) {
-			// Apply JWT authentication middleware
+			// Apply CSRF check for cookie sessions, then JWT and API token authentication middleware
+			r.Use(csrfMiddleware.Protect)
 			r.Use(authMiddleware.Authenticate)
+			r.Use(adminMiddleware.AuditImpersonation)
+			r.Use(rateLimitMiddleware.PerUser("mutation", cfg.RateLimit.MutationRequests, cfg.RateLimit.MutationWindow))
+			r.Use(idempotencyMiddleware.Idempotent)
 
 			// Current user route
 			r.Get("/me", userHandler.GetMe())
 
 			// User routes
 			r.Route("/users", func(r chi.Router) {
+				r.Use(authMiddleware.RequireSession)
+
 				r.Get("/", userHandler.GetAll())
+				r.Delete("/me", accountDeletionHandler.DeleteMe())                 // Delete own account with password confirmation
+				r.Get("/me/security/logins", authHandler.LoginHistory())           // Recent login attempts of the current user
+				r.Get("/me/sessions", userSessionHandler.GetMine())                // Devices the current user is signed in on
+				r.Delete("/me/sessions", userSessionHandler.RevokeOthers())        // Sign out everywhere else
+				r.Delete("/me/sessions/{session_id}", userSessionHandler.Revoke()) // Sign out one device
+				r.Get("/me/preferences", userPreferencesHandler.GetMine())         // Settings shared across devices
+				r.Patch("/me/preferences", userPreferencesHandler.PatchMine())     // JSON Merge Patch
+				r.Get("/me/usage", usageHandler.GetMine())                         // Usage against the quotas
+				if dataExportService != nil {
+					dataExportHandler := handlers.NewDataExportHandler(dataExportService)
+					r.With(authMiddleware.RejectImpersonation).Get("/me/export", dataExportHandler.GetMine()) // Latest archive of the user's data, started when missing
+					r.With(authMiddleware.RejectImpersonation).Post("/me/export", dataExportHandler.Start())  // Build a fresh archive
+				}
+				if avatarService != nil {
+					avatarHandler := handlers.NewAvatarHandler(avatarService)
+					r.Post("/me/avatar", avatarHandler.Upload())   // Multipart image upload
+					r.Delete("/me/avatar", avatarHandler.Delete()) // Back to the default avatar
+				}
 
 				r.Route("/{user_id}", func(r chi.Router) {
 					r.Get("/", userHandler.GetByID())
 					r.Put("/", userHandler.Update())
 					r.Delete("/", userHandler.Delete())
-					r.Put("/password", userHandler.UpdatePassword())
+					r.With(authMiddleware.RejectImpersonation).Put("/password", userHandler.UpdatePassword())
 				})
 			})
 
+			// Personal access token routes; tokens cannot mint or revoke tokens, nor can admins impersonating the user
+			r.Route("/tokens", func(r chi.Router) {
+				r.Use(authMiddleware.RequireSession)
+				r.Use(authMiddleware.RejectImpersonation)
+
+				r.Post("/", apiTokenHandler.Create())             // Create token, returns the plaintext once
+				r.Get("/", apiTokenHandler.GetMine())             // List own tokens
+				r.Delete("/{token_id}", apiTokenHandler.Revoke()) // Revoke own token
+			})
+
+			// Billing routes, only mounted when billing is configured
+			if billingService != nil {
+				billingHandler := handlers.NewBillingHandler(billingService)
+				r.Route("/billing", func(r chi.Router) {
+					r.Use(authMiddleware.RequireSession)
+
+					r.Get("/plans", billingHandler.Plans())                  // Plans on offer with their quotas
+					r.Get("/subscription", billingHandler.GetSubscription()) // Plan of the current user
+
+					// Stripe Checkout page for a paid plan, not for admins impersonating the user
+					r.With(authMiddleware.RejectImpersonation).Post("/checkout", billingHandler.Checkout())
+				})
+			}
+
+			// Search names across the user's projects; project-restricted tokens search their project
+			r.With(authMiddleware.RequireScope(services.ScopeReadProjects, services.ScopeWriteSchema)).Get("/search", searchHandler.Search())
+
 			// Project routes
 			r.Route("/projects", func(r chi.Router) {
-				r.Post("/", projectHandler.Create())
-				r.Get("/", projectHandler.GetAll())
-				r.Get("/my", projectHandler.GetMyProjects())
+				r.Use(authMiddleware.RequireScope(services.ScopeReadProjects, services.ScopeWriteSchema))
+
+				// Routes spanning projects are closed to project-restricted tokens
+				r.Group(func(r chi.Router) {
+					r.Use(authMiddleware.RejectProjectTokens)
+
+					r.Post("/", projectHandler.Create())
+					r.Get("/", projectHandler.GetAll())
+					r.Get("/my", projectHandler.GetMyProjects())
+					r.Get("/recent", projectHandler.GetRecent()) // Recently opened projects
+				})
 
 				r.Route("/{project_id}", func(r chi.Router) {
+					r.Use(authMiddleware.RequireTokenProject)
+
 					r.Get("/", projectHandler.GetByID())
+					r.Get("/full", projectHandler.Full()) // Schema and active collaborators in one request, with ETag
 					r.Put("/", projectHandler.Update())
-					r.Delete("/", projectHandler.Delete())
-					r.Post("/collaborators", projectHandler.AddCollaborator())
-					r.Delete("/collaborators/{user_id}", projectHandler.RemoveCollaborator())
+					r.Patch("/", projectHandler.Patch()) // JSON Merge Patch
+					// Deleting the project and deciding who may open it take a session, not an API token
+					r.Group(func(r chi.Router) {
+						r.Use(authMiddleware.RequireSession)
+
+						r.Delete("/", projectHandler.Delete())
+						r.Post("/collaborators", projectHandler.AddCollaborator())
+						r.Delete("/collaborators/{user_id}", projectHandler.RemoveCollaborator())
+						r.Post("/collaborators/{user_id}/kick", projectHandler.KickCollaborator())
+						r.Put("/collaborators/{user_id}/role", projectHandler.SetCollaboratorRole())
+					})
+					r.Put("/tags/{tag}", projectHandler.AddTag())       // Label the project
+					r.Delete("/tags/{tag}", projectHandler.RemoveTag()) // Remove a label
+					r.Put("/star", projectHandler.Star())               // Add to the user's favorites
+					r.Delete("/star", projectHandler.Unstar())          // Remove from the user's favorites
+					r.Post("/schema", schemaHandler.Create())           // Add tables, fields and relationships in one transaction
+					r.Post("/schema/import", schemaHandler.Import())    // Add tables defined in an Excel or CSV file
+					r.Post("/schema/infer", schemaHandler.Infer())      // Propose tables for MongoDB collections, creating nothing
+					// Diff against another project, which project-restricted tokens cannot read
+					r.With(authMiddleware.RejectProjectTokens).Get("/compare/{other_project_id}", schemaHandler.Compare())
+					r.Post("/generate-data", schemaHandler.GenerateData()) // Fake rows for every table, as SQL or CSV
+					r.Get("/export", schemaHandler.Export())               // The schema as DDL or as a createSchema request
+					r.Post("/apply/plan", schemaHandler.PlanApply())       // Statements bringing a given database to the model
+					r.Post("/apply/execute", schemaHandler.ExecuteApply()) // Run them, logging each
+					r.Get("/lint", schemaHandler.Lint())                   // Likely normalization problems of the schema
+
+					// Renaming a table or field, so applying the model renames it in the database too, and the renames so far
+					r.Post("/refactor/rename", schemaHandler.Rename())
+					r.Get("/refactor/renames", schemaHandler.ListRenames())
+
+					// Foreign keys the schema seems to miss, and creating those chosen at once
+					r.Get("/suggestions/relationships", schemaHandler.SuggestRelationships())
+					r.Post("/suggestions/relationships", schemaHandler.AcceptRelationshipSuggestions())
 
 					// Table routes within projects
 					r.Route("/tables", func(r chi.Router) {
@@ -90,18 +288,21 @@ func SetupRoutes(
 						r.Route("/{table_id}", func(r chi.Router) {
 							r.Get("/", tableHandler.GetByID())                // Get specific table
 							r.Put("/", tableHandler.Update())                 // Update table
+							r.Patch("/", tableHandler.Patch())                // Merge patch table
 							r.Delete("/", tableHandler.Delete())              // Delete table
 							r.Put("/position", tableHandler.UpdatePosition()) // Update table position
 
 							// Field routes within tables
 							r.Route("/fields", func(r chi.Router) {
-								r.Post("/", fieldHandler.Create())        // Create field in table
-								r.Get("/", fieldHandler.GetByTableID())   // Get all fields in table
-								r.Put("/reorder", fieldHandler.Reorder()) // Reorder fields
+								r.Post("/", fieldHandler.Create())         // Create field in table
+								r.Post("/bulk", fieldHandler.BulkCreate()) // Create several fields at once
+								r.Get("/", fieldHandler.GetByTableID())    // Get all fields in table
+								r.Put("/reorder", fieldHandler.Reorder())  // Reorder fields
 
 								r.Route("/{field_id}", func(r chi.Router) {
 									r.Get("/", fieldHandler.GetByID())   // Get specific field
 									r.Put("/", fieldHandler.Update())    // Update field
+									r.Patch("/", fieldHandler.Patch())   // Merge patch field
 									r.Delete("/", fieldHandler.Delete()) // Delete field
 								})
 							})
@@ -116,10 +317,127 @@ func SetupRoutes(
 						r.Route("/{relationship_id}", func(r chi.Router) {
 							r.Get("/", relationshipHandler.GetByID())   // Get specific relationship
 							r.Put("/", relationshipHandler.Update())    // Update relationship
+							r.Patch("/", relationshipHandler.Patch())   // Merge patch relationship
 							r.Delete("/", relationshipHandler.Delete()) // Delete relationship
 						})
 					})
 
+					// Region routes within projects
+					r.Route("/regions", func(r chi.Router) {
+						r.Post("/", regionHandler.Create())        // Create region in project
+						r.Get("/", regionHandler.GetByProjectID()) // Get all regions in project
+
+						r.Route("/{region_id}", func(r chi.Router) {
+							r.Get("/", regionHandler.GetByID())   // Get specific region
+							r.Put("/", regionHandler.Update())    // Update region and its tables
+							r.Patch("/", regionHandler.Patch())   // Merge patch region
+							r.Delete("/", regionHandler.Delete()) // Delete region, keeping its tables
+							r.Post("/move", regionHandler.Move()) // Move region with its tables
+						})
+					})
+
+					// SQL kept with the project, e.g. seed scripts and example queries
+					r.Route("/snippets", func(r chi.Router) {
+						r.Post("/", snippetHandler.Create())        // Save a snippet with the project
+						r.Get("/", snippetHandler.GetByProjectID()) // Get all snippets of the project
+
+						r.Route("/{snippet_id}", func(r chi.Router) {
+							r.Get("/", snippetHandler.GetByID())   // Get specific snippet
+							r.Put("/", snippetHandler.Update())    // Update snippet
+							r.Delete("/", snippetHandler.Delete()) // Delete snippet
+						})
+					})
+
+					// Schema reviews, their findings kept as comments until resolved
+					r.Route("/ai", func(r chi.Router) {
+						r.Post("/review", reviewHandler.Review())                                // Review the schema
+						r.Get("/review-comments", reviewHandler.GetComments())                   // Get all review comments of the project
+						r.Post("/review-comments/{comment_id}/resolve", reviewHandler.Resolve()) // Resolve a review comment
+					})
+
+					// Markdown document of the project, every edit kept as a version
+					r.Route("/doc", func(r chi.Router) {
+						r.Get("/", docHandler.Get())                   // Get the doc, or an earlier version of it
+						r.Put("/", docHandler.Update())                // Save a new version
+						r.Get("/revisions", docHandler.GetRevisions()) // Get the versions, newest first
+						r.Get("/render", docHandler.Render())          // The doc as HTML
+					})
+
+					// Copies of the schema edited apart from the project and merged back
+					r.Route("/branches", func(r chi.Router) {
+						r.Post("/", branchHandler.Create())        // Branch the project's schema
+						r.Get("/", branchHandler.GetByProjectID()) // Get all branches of the project
+
+						r.Route("/{branch_id}", func(r chi.Router) {
+							r.Get("/", branchHandler.GetByID())     // Get specific branch with its schema
+							r.Put("/", branchHandler.Update())      // Rename branch or replace its schema
+							r.Delete("/", branchHandler.Delete())   // Delete branch
+							r.Get("/diff", branchHandler.Diff())    // Diff the project against the branch
+							r.Post("/merge", branchHandler.Merge()) // Merge the branch into the project
+						})
+					})
+
+					// Named snapshots of the project, exported by name
+					r.Route("/releases", func(r chi.Router) {
+						r.Post("/", releaseHandler.Create())        // Release the project or one of its snapshots
+						r.Get("/", releaseHandler.GetByProjectID()) // Get all releases of the project
+
+						r.Route("/{release_id}", func(r chi.Router) {
+							r.Get("/", releaseHandler.GetByID())   // Get specific release
+							r.Put("/", releaseHandler.Update())    // Edit, lock or unlock release
+							r.Delete("/", releaseHandler.Delete()) // Delete unlocked release
+						})
+					})
+
+					// Chat of the project's collaborators, also delivered over WebSockets
+					r.Route("/chat", func(r chi.Router) {
+						r.Post("/", chatHandler.Create())        // Send a message
+						r.Get("/", chatHandler.GetByProjectID()) // Get the history, newest first
+					})
+
+					// Schema changes after a sequence, for clients catching up without a WebSocket
+					r.Get("/events", eventHandler.GetByProjectID())
+
+					// Nightly snapshots of the project and how many are kept
+					r.Get("/snapshots", snapshotHandler.GetByProjectID())
+					r.Get("/snapshot-retention", snapshotHandler.GetRetention())
+					r.Put("/snapshot-retention", snapshotHandler.SetRetention()) // Owner only
+
+					// Drift of the project's live database from the model; the
+					// connection profile holds credentials, so people manage it
+					if driftService != nil {
+						driftHandler := handlers.NewDriftHandler(driftService)
+						r.Route("/connection-profile", func(r chi.Router) {
+							r.Use(authMiddleware.RequireSession)
+
+							r.Get("/", driftHandler.GetProfile())
+							r.Put("/", driftHandler.SetProfile())       // Owner only
+							r.Delete("/", driftHandler.DeleteProfile()) // Owner only
+						})
+						r.Route("/drift-checks", func(r chi.Router) {
+							r.Post("/", driftHandler.StartCheck())        // Check the database now
+							r.Get("/", driftHandler.GetChecks())          // Latest checks, without their diffs
+							r.Get("/{check_id}", driftHandler.GetCheck()) // Check with its diff
+						})
+						r.Get("/data-profile", driftHandler.GetDataProfile())    // Row counts, null ratios and distinct counts
+						r.Post("/data-profile", driftHandler.StartDataProfile()) // Profile the database now
+					}
+
+					// Service account routes within projects; managed by people only
+					r.Route("/service-accounts", func(r chi.Router) {
+						r.Use(authMiddleware.RequireSession)
+
+						r.Post("/", serviceAccountHandler.Create())        // Create service account
+						r.Get("/", serviceAccountHandler.GetByProjectID()) // List service accounts
+
+						r.Route("/{service_account_id}", func(r chi.Router) {
+							r.Delete("/", serviceAccountHandler.Delete())                       // Delete service account and its tokens
+							r.Post("/tokens", serviceAccountHandler.CreateToken())              // Create token, returns the plaintext once
+							r.Get("/tokens", serviceAccountHandler.GetTokens())                 // List tokens
+							r.Delete("/tokens/{token_id}", serviceAccountHandler.RevokeToken()) // Revoke token
+						})
+					})
+
 					// Collaboration session routes within projects
 					r.Route("/sessions", func(r chi.Router) {
 						r.Post("/", collaborationHandler.Create())                    // Create collaboration session
@@ -137,6 +455,31 @@ func SetupRoutes(
 				})
 			})
 
+			// GraphQL over the same services; resolvers check scopes and project access per field
+			r.Route("/graphql", func(r chi.Router) {
+				r.Post("/", graphQLHandler.Query())    // Queries and mutations
+				r.Get("/", graphQLHandler.Subscribe()) // Subscriptions over graphql-transport-ws
+			})
+
+			// Admin routes
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(authMiddleware.RequireSession)
+				r.Use(adminMiddleware.RequireAdmin)
+
+				r.Get("/stats", adminHandler.InstanceStats())                        // Instance-wide aggregates for dashboards
+				r.Get("/ws/stats", adminHandler.WebSocketStats())                    // WebSocket hub statistics
+				r.Get("/users/{user_id}/tokens", apiTokenHandler.AdminGetByUserID()) // List a user's API tokens
+				r.Delete("/tokens/{token_id}", apiTokenHandler.AdminRevoke())        // Revoke any API token
+
+				// User management
+				r.Get("/users", adminUserHandler.List())                                         // List users with filters
+				r.Post("/users/{user_id}/disable", adminUserHandler.Disable())                   // Disable account and revoke sessions
+				r.Post("/users/{user_id}/enable", adminUserHandler.Enable())                     // Re-enable account
+				r.Put("/users/{user_id}/role", adminUserHandler.SetRole())                       // Grant or remove admin role
+				r.Post("/users/{user_id}/password-reset", adminUserHandler.ForcePasswordReset()) // Require a new password
+				r.Post("/users/{user_id}/impersonate", adminUserHandler.Impersonate())           // Audited support session
+				r.Get("/audit-logs", adminUserHandler.AuditLogs())                               // Admin action history
+			})
 		})
 	})
 }
@@ -0,0 +1,194 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockService "github.com/Bug-Bugger/ezmodel/internal/mocks/service"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRouter mounts every route, including the optional SAML ones. Handlers
+// are never invoked, so the services are left nil.
+func newTestRouter() *chi.Mux {
+	r := chi.NewRouter()
+	SetupRoutes(r, config.New(), nil, nil, nil, nil, nil, nil, nil, nil, nil, new(mockService.MockSAMLService),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, new(mockService.MockBillingService), new(mockService.MockAvatarService), nil, new(mockService.MockDataExportService), new(mockService.MockSnapshotService), new(mockService.MockDriftService), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, http.NotFoundHandler(), nil, nil)
+	return r
+}
+
+// newAuthenticatedTestRouter mounts every route behind the real middleware,
+// authenticating API tokens with apiTokenService, so requests stop in the
+// middleware before reaching the nil services. projectService backs GraphQL.
+func newAuthenticatedTestRouter(apiTokenService services.APITokenServiceInterface, projectService services.ProjectServiceInterface) *chi.Mux {
+	cfg := config.New()
+	cfg.RateLimit.Enabled = false
+	cfg.Idempotency.Enabled = false
+	r := chi.NewRouter()
+	SetupRoutes(r, cfg, nil, projectService, nil, nil, nil, nil, nil, nil, nil, new(mockService.MockSAMLService),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, new(mockService.MockBillingService), new(mockService.MockAvatarService), nil, new(mockService.MockDataExportService), new(mockService.MockSnapshotService), new(mockService.MockDriftService), nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		middleware.NewAuthMiddleware(nil, apiTokenService, nil), nil, middleware.NewRateLimitMiddleware(cfg, nil), middleware.NewIdempotencyMiddleware(cfg, nil), middleware.NewCSRFMiddleware(cfg),
+		nil, http.NotFoundHandler(), nil, nil)
+	return r
+}
+
+// TestAPIRoutesMatchDocument fails when a route is added to SetupRoutes without
+// being described in apiRoutes, or the other way round.
+func TestAPIRoutesMatchDocument(t *testing.T) {
+	var mounted []string
+	err := chi.Walk(newTestRouter(), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		path, ok := strings.CutPrefix(route, "/api")
+		if !ok {
+			return nil
+		}
+		if path != "/" {
+			path = strings.TrimSuffix(path, "/")
+		}
+		mounted = append(mounted, method+" "+path)
+		return nil
+	})
+	require.NoError(t, err)
+
+	var documented []string
+	for path, item := range APIDocument().Paths {
+		for method := range *item {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	slices.Sort(mounted)
+	slices.Sort(documented)
+	assert.Equal(t, mounted, documented)
+}
+
+func TestAPIRoutesHaveUniqueOperationIDs(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, route := range apiRoutes {
+		assert.NotEmpty(t, route.ID, "%s %s", route.Method, route.Path)
+		assert.False(t, seen[route.ID], "duplicate operation ID %s", route.ID)
+		seen[route.ID] = true
+	}
+}
+
+func TestOpenAPIEndpoints(t *testing.T) {
+	r := newTestRouter()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &document))
+	assert.Equal(t, "3.0.3", document["openapi"])
+	schemas := document["components"].(map[string]any)["schemas"].(map[string]any)
+	assert.Contains(t, schemas, "LoginRequest")
+	assert.Contains(t, schemas, "APIResponse")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "swagger-ui")
+	assert.Contains(t, w.Body.String(), "/openapi.json")
+}
+
+func TestTypeScriptClientEndpoint(t *testing.T) {
+	r := newTestRouter()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sdk/ezmodel-client.ts", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "export class EzModelClient")
+	assert.Contains(t, w.Body.String(), "export type ServerMessage")
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/sdk/ezmodel-client.ts", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+// TestProjectAdministrationRequiresSession checks that API tokens, even with
+// the write:schema scope, cannot delete projects or manage collaborators
+func TestProjectAdministrationRequiresSession(t *testing.T) {
+	plaintext := services.APITokenPrefix + "test"
+	apiTokenService := new(mockService.MockAPITokenService)
+	apiTokenService.On("AuthenticateToken", plaintext).Return(&models.APIToken{UserID: uuid.New(), Scopes: services.ScopeWriteSchema}, nil)
+	r := newAuthenticatedTestRouter(apiTokenService, nil)
+
+	project := "/api/projects/" + uuid.New().String()
+	collaborator := project + "/collaborators/" + uuid.New().String()
+	for _, route := range []struct{ method, path string }{
+		{http.MethodDelete, project},
+		{http.MethodPost, project + "/collaborators"},
+		{http.MethodDelete, collaborator},
+		{http.MethodPost, collaborator + "/kick"},
+		{http.MethodPut, collaborator + "/role"},
+	} {
+		req := httptest.NewRequest(route.method, route.path, nil)
+		req.Header.Set("Authorization", "Bearer "+plaintext)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code, "%s %s", route.method, route.path)
+		assert.Contains(t, w.Body.String(), "API tokens cannot access this endpoint")
+	}
+
+	// Nor through the GraphQL mutation
+	projectService := new(mockService.MockProjectService)
+	r = newAuthenticatedTestRouter(apiTokenService, projectService)
+	w := postGraphQL(r, plaintext, `mutation { delete_project(id: "`+uuid.NewString()+`") }`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "API tokens cannot delete projects")
+	projectService.AssertNotCalled(t, "DeleteProject", mock.Anything)
+}
+
+// TestGraphQLReadOnlyToken checks that a read:projects token can run queries,
+// which are posted like mutations
+func TestGraphQLReadOnlyToken(t *testing.T) {
+	plaintext := services.APITokenPrefix + "test"
+	userID := uuid.New()
+	apiTokenService := new(mockService.MockAPITokenService)
+	apiTokenService.On("AuthenticateToken", plaintext).Return(&models.APIToken{UserID: userID, Scopes: services.ScopeReadProjects}, nil)
+	project := &models.Project{ID: uuid.New(), OwnerID: userID}
+	projectService := new(mockService.MockProjectService)
+	projectService.On("GetProjectsByOwnerID", userID, repository.ProjectIncludes{}).Return([]*models.Project{project}, nil)
+	projectService.On("GetProjectsByCollaboratorID", userID, repository.ProjectIncludes{}).Return([]*models.Project{}, nil)
+	r := newAuthenticatedTestRouter(apiTokenService, projectService)
+
+	w := postGraphQL(r, plaintext, `{ projects { id } }`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"projects":[{"id":"`+project.ID.String()+`"}]}}`, w.Body.String())
+}
+
+// postGraphQL posts a GraphQL request with an API token
+func postGraphQL(r http.Handler, token, query string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
@@ -1,42 +1,108 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
 
+	"github.com/Bug-Bugger/ezmodel/internal/api/handlers"
 	"github.com/Bug-Bugger/ezmodel/internal/api/middleware"
+	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
 	"github.com/Bug-Bugger/ezmodel/internal/api/routes"
+	"github.com/Bug-Bugger/ezmodel/internal/billing"
+	"github.com/Bug-Bugger/ezmodel/internal/broker"
+	"github.com/Bug-Bugger/ezmodel/internal/cache"
 	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/idempotency"
+	"github.com/Bug-Bugger/ezmodel/internal/jobs"
+	"github.com/Bug-Bugger/ezmodel/internal/metrics"
+	"github.com/Bug-Bugger/ezmodel/internal/ratelimit"
 	redisClient "github.com/Bug-Bugger/ezmodel/internal/redis"
 	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/review"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/storage"
 	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"github.com/prometheus/client_golang/prometheus"
 	"gorm.io/gorm"
 )
 
+var errHubShuttingDown = errors.New("shutting down")
+
 type Server struct {
-	config               *config.Config
-	router               *chi.Mux
-	db                   *gorm.DB
-	userRepo             repository.UserRepositoryInterface
-	projectRepo          repository.ProjectRepositoryInterface
-	tableRepo            repository.TableRepositoryInterface
-	fieldRepo            repository.FieldRepositoryInterface
-	relationshipRepo     repository.RelationshipRepositoryInterface
-	collaborationRepo    repository.CollaborationSessionRepositoryInterface
-	authService          services.AuthorizationServiceInterface
-	userService          services.UserServiceInterface
-	projectService       services.ProjectServiceInterface
-	tableService         services.TableServiceInterface
-	fieldService         services.FieldServiceInterface
-	relationshipService  services.RelationshipServiceInterface
-	collaborationService services.CollaborationSessionServiceInterface
-	jwtService           *services.JWTService
-	authMiddleware       *middleware.AuthMiddleware
-	websocketHub         *websocketPkg.Hub
+	config                 *config.Config
+	router                 *chi.Mux
+	db                     *gorm.DB
+	userRepo               repository.UserRepositoryInterface
+	userIdentityRepo       repository.UserIdentityRepositoryInterface
+	apiTokenRepo           repository.APITokenRepositoryInterface
+	serviceAccountRepo     repository.ServiceAccountRepositoryInterface
+	loginEventRepo         repository.LoginEventRepositoryInterface
+	userSessionRepo        repository.UserSessionRepositoryInterface
+	adminAuditRepo         repository.AdminAuditLogRepositoryInterface
+	projectRepo            repository.ProjectRepositoryInterface
+	tableRepo              repository.TableRepositoryInterface
+	fieldRepo              repository.FieldRepositoryInterface
+	relationshipRepo       repository.RelationshipRepositoryInterface
+	regionRepo             repository.RegionRepositoryInterface
+	collaborationRepo      repository.CollaborationSessionRepositoryInterface
+	searchRepo             repository.SearchRepositoryInterface
+	statsRepo              repository.StatsRepositoryInterface
+	preferencesRepo        repository.UserPreferencesRepositoryInterface
+	dataExportRepo         repository.DataExportRepositoryInterface
+	snapshotRepo           repository.SnapshotRepositoryInterface
+	authService            services.AuthorizationServiceInterface
+	userService            services.UserServiceInterface
+	projectService         services.ProjectServiceInterface
+	tableService           services.TableServiceInterface
+	fieldService           services.FieldServiceInterface
+	relationshipService    services.RelationshipServiceInterface
+	schemaService          services.SchemaServiceInterface
+	regionService          services.RegionServiceInterface
+	snippetService         services.SnippetServiceInterface
+	reviewService          services.ReviewServiceInterface
+	docService             services.DocServiceInterface
+	branchService          services.BranchServiceInterface
+	releaseService         services.ReleaseServiceInterface
+	chatService            services.ChatServiceInterface
+	eventHistoryService    services.EventHistoryServiceInterface
+	collaborationService   services.CollaborationSessionServiceInterface
+	oauthService           services.OAuthServiceInterface
+	samlService            services.SAMLServiceInterface
+	apiTokenService        services.APITokenServiceInterface
+	serviceAccountService  services.ServiceAccountServiceInterface
+	loginSecurityService   services.LoginSecurityServiceInterface
+	userSessionService     services.UserSessionServiceInterface
+	adminUserService       services.AdminUserServiceInterface
+	adminStatsService      services.AdminStatsServiceInterface
+	searchService          services.SearchServiceInterface
+	preferencesService     services.UserPreferencesServiceInterface
+	usageService           services.UsageServiceInterface
+	billingService         services.BillingServiceInterface
+	avatarService          services.AvatarServiceInterface
+	accountDeletionService services.AccountDeletionServiceInterface
+	dataExportService      services.DataExportServiceInterface
+	snapshotService        services.SnapshotServiceInterface
+	driftService           services.DriftServiceInterface
+	uploadsHandler         http.Handler // Serves files of the local storage driver
+	jwtService             *services.JWTService
+	authMiddleware         *middleware.AuthMiddleware
+	adminMiddleware        *middleware.AdminMiddleware
+	rateLimitMiddleware    *middleware.RateLimitMiddleware
+	idempotencyMiddleware  *middleware.IdempotencyMiddleware
+	csrfMiddleware         *middleware.CSRFMiddleware
+	websocketHub           *websocketPkg.Hub
+	outboxDispatcher       *services.OutboxDispatcher
+	jobWorker              *jobs.Worker    // Nil when jobs are left to worker processes
+	jobScheduler           *jobs.Scheduler // Nil when nothing is scheduled
+	broker                 broker.Broker
+	redis                  *redisClient.Client
+	metricsRegistry        *prometheus.Registry
+	httpServer             *http.Server
 }
 
 func New(cfg *config.Config, db *gorm.DB) *Server {
@@ -48,14 +114,15 @@ func New(cfg *config.Config, db *gorm.DB) *Server {
 
 	// Apply global middleware
 	s.router.Use(chiMiddleware.Logger)
-	s.router.Use(chiMiddleware.Recoverer)
+	s.router.Use(middleware.Recoverer)
+	s.router.Use(middleware.TrackWrites)
 
 	// CORS middleware
 	s.router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   cfg.AllowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "Idempotency-Key", "If-Match", "X-CSRF-Token"},
+		ExposedHeaders:   []string{"ETag", "Idempotent-Replayed", "Link", responses.ProjectSequenceHeader},
 		AllowCredentials: true,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
@@ -63,44 +130,257 @@ func New(cfg *config.Config, db *gorm.DB) *Server {
 	// Initialize WebSocket hub
 	s.websocketHub = websocketPkg.NewHub()
 
-	// Initialize Redis client and connect to hub
-	redis := redisClient.NewClient(cfg)
-	s.websocketHub.SetRedisClient(redis)
+	// Initialize Redis client and message broker and connect them to the hub
+	s.redis = redisClient.NewClient(cfg)
+	s.broker = broker.New(cfg, s.redis)
+	s.websocketHub.SetRedisClient(s.redis)
+	s.websocketHub.SetBroker(s.broker)
+	s.websocketHub.SetEventSinks(broker.NewSinks(cfg))
+	s.websocketHub.SetIdleTimeout(cfg.WebSocket.IdleTimeout)
+	s.websocketHub.SetWriteWorkers(cfg.WebSocket.WriteWorkers, cfg.WebSocket.WriteQueueSize)
+
+	// Initialize Prometheus metrics: the hub, HTTP requests by route and the
+	// database queries and connection pool
+	s.metricsRegistry = metrics.NewRegistry(s.websocketHub)
+	s.router.Use(metrics.NewHTTPMetrics(s.metricsRegistry).Middleware)
+	if err := metrics.RegisterDB(s.metricsRegistry, db, cfg.Database.SlowQueryThreshold); err != nil {
+		log.Printf("Warning: database metrics disabled: %v", err)
+	}
 
 	log.Printf("Server initialized for region: %s", cfg.Region)
 
 	// Initialize repositories
 	s.userRepo = repository.NewUserRepository(db)
+	s.userIdentityRepo = repository.NewUserIdentityRepository(db)
+	s.apiTokenRepo = repository.NewAPITokenRepository(db)
+	s.serviceAccountRepo = repository.NewServiceAccountRepository(db)
+	s.loginEventRepo = repository.NewLoginEventRepository(db)
+	s.userSessionRepo = repository.NewUserSessionRepository(db)
+	s.adminAuditRepo = repository.NewAdminAuditLogRepository(db)
 	s.projectRepo = repository.NewProjectRepository(db)
 	s.tableRepo = repository.NewTableRepository(db)
 	s.fieldRepo = repository.NewFieldRepository(db)
 	s.relationshipRepo = repository.NewRelationshipRepository(db)
+	s.regionRepo = repository.NewRegionRepository(db)
 	s.collaborationRepo = repository.NewCollaborationSessionRepository(db)
+	s.searchRepo = repository.NewSearchRepository(db)
+	s.statsRepo = repository.NewStatsRepository(db)
+	s.preferencesRepo = repository.NewUserPreferencesRepository(db)
+	s.dataExportRepo = repository.NewDataExportRepository(db)
+	s.snapshotRepo = repository.NewSnapshotRepository(db)
+	s.websocketHub.SetSequencer(s.projectRepo) // Numbers schema changes relayed from clients
+
+	// Projects and permission checks are cached only when enabled; a nil cache caches nothing
+	var projectCache *services.ProjectCache
+	var accessCache *services.AccessCache
+	if cfg.ProjectCache.Enabled {
+		store := cache.New(s.redis, cfg.ProjectCache.UseRedis)
+		projectCache = services.NewProjectCache(store, cfg.ProjectCache.TTL)
+		accessCache = services.NewAccessCache(store, cfg.ProjectCache.AccessTTL)
+	}
 
 	// Initialize authorization service first
-	s.authService = services.NewAuthorizationService(s.projectRepo, s.tableRepo, s.fieldRepo, s.relationshipRepo, s.collaborationRepo)
+	s.authService = services.NewAuthorizationService(s.projectRepo, s.tableRepo, s.fieldRepo, s.relationshipRepo, s.collaborationRepo, accessCache)
 
 	// Initialize services with authorization service
 	s.userService = services.NewUserService(s.userRepo)
-	s.collaborationService = services.NewCollaborationSessionService(s.collaborationRepo, s.projectRepo, s.userRepo, s.tableRepo, s.relationshipRepo, s.authService, s.websocketHub)
-	s.projectService = services.NewProjectService(s.projectRepo, s.userRepo, s.collaborationService)
-	s.tableService = services.NewTableService(s.tableRepo, s.projectRepo, s.authService, s.collaborationService)
-	s.fieldService = services.NewFieldService(s.fieldRepo, s.tableRepo, s.authService, s.collaborationService)
-	s.relationshipService = services.NewRelationshipService(s.relationshipRepo, s.projectRepo, s.tableRepo, s.fieldRepo, s.authService, s.collaborationService)
+	collaborationService := services.NewCollaborationSessionService(s.collaborationRepo, s.projectRepo, s.userRepo, s.tableRepo, s.relationshipRepo, s.authService, s.websocketHub, projectCache)
+	s.websocketHub.SetSessionListener(collaborationService) // Persist session lifecycle from socket presence
+	s.outboxDispatcher = services.NewOutboxDispatcher(repository.NewOutboxRepository(db), s.websocketHub, cfg.Outbox.PollInterval, cfg.Outbox.Retention)
+	collaborationService.SetOutbox(s.outboxDispatcher) // Schema changes and their notifications commit together
+	s.collaborationService = collaborationService
+	unitOfWork := services.NewUnitOfWork(repository.NewUnitOfWork(db))
+	quotas := services.Quotas{
+		MaxProjectsPerUser:  cfg.Quotas.MaxProjectsPerUser,
+		MaxTablesPerProject: cfg.Quotas.MaxTablesPerProject,
+		MaxCollaborators:    cfg.Quotas.MaxCollaborators,
+	}
+	// With billing, the quotas are those of the plan the project owner pays for
+	var quotaPolicy services.QuotaPolicy = quotas
+	if cfg.Billing.StripeSecretKey != "" {
+		stripe := billing.NewStripeClient(cfg.Billing.StripeAPIURL, cfg.Billing.StripeSecretKey, cfg.Billing.StripeWebhookSecret)
+		billingService := services.NewBillingService(cfg, repository.NewSubscriptionRepository(db), s.userRepo, stripe, quotas)
+		s.billingService = billingService
+		quotaPolicy = billingService
+	}
+	s.projectService = services.NewProjectService(s.projectRepo, s.userRepo, s.collaborationService, projectCache, accessCache, unitOfWork, cfg.Canvas.MaxSize, quotaPolicy)
+	s.tableService = services.NewTableService(s.tableRepo, s.projectRepo, s.authService, s.collaborationService, projectCache, unitOfWork, quotaPolicy)
+	s.fieldService = services.NewFieldService(s.fieldRepo, s.tableRepo, s.authService, s.collaborationService, projectCache, unitOfWork)
+	s.relationshipService = services.NewRelationshipService(s.relationshipRepo, s.projectRepo, s.tableRepo, s.fieldRepo, s.authService, s.collaborationService, unitOfWork)
+	s.schemaService = services.NewSchemaService(unitOfWork, s.authService, s.collaborationService, quotaPolicy)
+	s.regionService = services.NewRegionService(s.regionRepo, s.projectRepo, s.authService, s.collaborationService, unitOfWork)
+	s.snippetService = services.NewSnippetService(repository.NewSnippetRepository(db), s.authService)
+	// Schemas are reviewed by a model when one is configured, otherwise by rules
+	var reviewer review.Provider = review.Rules{}
+	if cfg.Review.URL != "" {
+		reviewer = review.NewChatClient(cfg.Review.URL, cfg.Review.APIKey, cfg.Review.Model, cfg.Review.Timeout)
+	}
+	s.reviewService = services.NewReviewService(repository.NewReviewCommentRepository(db), s.tableRepo, s.relationshipRepo, s.authService, reviewer)
+	s.docService = services.NewDocService(repository.NewDocRepository(db), s.authService, s.collaborationService, unitOfWork)
+	s.branchService = services.NewBranchService(repository.NewBranchRepository(db), s.authService, s.collaborationService, unitOfWork, quotaPolicy)
+	s.releaseService = services.NewReleaseService(repository.NewReleaseRepository(db), s.authService, unitOfWork)
+	s.chatService = services.NewChatService(repository.NewChatRepository(db), s.userRepo, s.authService, s.collaborationService)
+	eventHistoryService := services.NewEventHistoryService(repository.NewProjectEventRepository(db), s.projectRepo, s.authService, cfg.EventHistory.Size)
+	s.websocketHub.SetEventSinks([]broker.Publisher{eventHistoryService}) // Keeps the latest schema changes for clients catching up over the API
+	s.eventHistoryService = eventHistoryService
 	s.jwtService = services.NewJWTService(cfg)
+	s.apiTokenService = services.NewAPITokenService(s.apiTokenRepo, s.userRepo)
+	s.serviceAccountService = services.NewServiceAccountService(s.serviceAccountRepo, s.userRepo, s.projectRepo, s.authService, s.apiTokenService, projectCache, accessCache)
+	s.loginSecurityService = services.NewLoginSecurityService(s.config, s.userService, s.userRepo, s.loginEventRepo)
+	s.userSessionService = services.NewUserSessionService(s.config, s.userSessionRepo, s.userRepo, s.jwtService)
+	s.adminUserService = services.NewAdminUserService(s.config, s.userRepo, s.adminAuditRepo, s.userSessionService)
+	s.adminStatsService = services.NewAdminStatsService(s.statsRepo)
+	s.searchService = services.NewSearchService(s.searchRepo, s.authService)
+	s.preferencesService = services.NewUserPreferencesService(s.preferencesRepo)
+	s.usageService = services.NewUsageService(s.projectRepo, s.tableRepo, quotaPolicy)
+	s.oauthService = services.NewOAuthService(cfg, s.userRepo, s.userIdentityRepo)
+	if cfg.SAML.Enabled {
+		// Leave samlService nil on failure so the SAML routes are not mounted
+		if samlService, err := services.NewSAMLService(cfg, s.userRepo, s.userIdentityRepo); err != nil {
+			log.Printf("Warning: SAML login disabled: %v", err)
+		} else {
+			s.samlService = samlService
+		}
+	}
+
+	// Slow work is queued as jobs, which this process runs too unless
+	// configured otherwise and the queue is shared with worker processes
+	jobBackend := jobs.New(s.redis, cfg.Jobs.UseRedis)
+	jobQueue := jobs.NewClient(jobBackend)
+	if cfg.Jobs.InProcess || !jobs.IsShared(jobBackend) {
+		s.jobWorker = jobs.NewWorker(jobBackend, cfg.Jobs.Concurrency, cfg.Jobs.PollInterval, cfg.Jobs.Lease)
+	}
+
+	snapshotService := services.NewSnapshotService(cfg, s.snapshotRepo, s.projectRepo, s.regionRepo, s.authService, jobQueue)
+	s.snapshotService = snapshotService
+	// Leave driftService nil when disabled so its routes are not mounted
+	var driftService *services.DriftService
+	if cfg.Drift.Enabled {
+		driftService = services.NewDriftService(cfg, repository.NewDriftRepository(db), s.projectRepo, unitOfWork, s.authService, jobQueue)
+		s.driftService = driftService
+	}
+	if cfg.Snapshots.Enabled || cfg.Drift.Enabled {
+		s.jobScheduler = newScheduler(cfg, jobQueue)
+	}
+
+	// Leave the services storing files nil on failure so their routes are not mounted
+	var dataExportService *services.DataExportService
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Printf("Warning: avatar uploads and data exports disabled: %v", err)
+	} else {
+		s.avatarService = services.NewAvatarService(cfg, s.userRepo, store)
+		dataExportService = services.NewDataExportService(cfg, s.dataExportRepo, s.userRepo, s.preferencesRepo, s.projectRepo, s.loginEventRepo, s.userSessionRepo, s.apiTokenRepo, store, jobQueue)
+		s.dataExportService = dataExportService
+		if localStore, ok := store.(*storage.LocalStore); ok {
+			s.uploadsHandler = localStore.Handler()
+		}
+	}
+	if s.jobWorker != nil {
+		registerJobs(s.jobWorker, dataExportService, snapshotService, driftService)
+	}
+	s.accountDeletionService = services.NewAccountDeletionService(s.userRepo, s.projectRepo, s.userSessionRepo, s.dataExportRepo, store, projectCache, accessCache)
 
 	// Initialize middleware
-	s.authMiddleware = middleware.NewAuthMiddleware(s.jwtService)
+	s.authMiddleware = middleware.NewAuthMiddleware(s.jwtService, s.apiTokenService, s.userSessionService)
+	s.adminMiddleware = middleware.NewAdminMiddleware(s.config, s.adminUserService)
+	s.rateLimitMiddleware = middleware.NewRateLimitMiddleware(cfg, ratelimit.New(s.redis, cfg.RateLimit.UseRedis))
+	s.idempotencyMiddleware = middleware.NewIdempotencyMiddleware(cfg, idempotency.New(s.redis, cfg.Idempotency.UseRedis))
+	s.csrfMiddleware = middleware.NewCSRFMiddleware(cfg)
 
 	// Setup routes
-	routes.SetupRoutes(s.router, s.config, s.userService, s.projectService, s.tableService, s.fieldService, s.relationshipService, s.collaborationService, s.jwtService, s.authMiddleware, s.websocketHub)
+	routes.SetupRoutes(s.router, s.config, s.userService, s.projectService, s.tableService, s.fieldService, s.relationshipService, s.schemaService, s.regionService, s.collaborationService, s.oauthService, s.samlService, s.apiTokenService, s.serviceAccountService, s.loginSecurityService, s.userSessionService, s.adminUserService, s.adminStatsService, s.searchService, s.preferencesService, s.usageService, s.billingService, s.avatarService, s.accountDeletionService, s.dataExportService, s.snapshotService, s.driftService, s.snippetService, s.reviewService, s.docService, s.branchService, s.releaseService, s.chatService, s.eventHistoryService, s.authService, s.jwtService, s.authMiddleware, s.adminMiddleware, s.rateLimitMiddleware, s.idempotencyMiddleware, s.csrfMiddleware, s.websocketHub, s.metricsHandler(), s.readinessChecks(), s.uploadsHandler)
 
 	return s
 }
 
+// metricsHandler serves Prometheus metrics to scrapers holding the metrics
+// token, or returns nil, leaving /metrics unmounted, when none is configured
+func (s *Server) metricsHandler() http.Handler {
+	if s.config.Metrics.Token == "" {
+		log.Printf("Metrics are not served: set METRICS_TOKEN to scrape /metrics")
+		return nil
+	}
+	return metrics.Handler(s.metricsRegistry, s.config.Metrics.Token)
+}
+
+// readinessChecks lists the dependencies /readyz checks: the database, Redis
+// when enabled, and the WebSocket hub, which stops accepting clients once
+// shutdown begins
+func (s *Server) readinessChecks() []handlers.ReadinessCheck {
+	return []handlers.ReadinessCheck{
+		{Name: "database", Check: func(ctx context.Context) error {
+			sqlDB, err := s.db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		}},
+		{Name: "redis", Check: s.redis.Ping},
+		{Name: "websocket_hub", Check: func(ctx context.Context) error {
+			if !s.websocketHub.Accepting() {
+				return errHubShuttingDown
+			}
+			return nil
+		}},
+	}
+}
+
+// Start serves HTTP until Shutdown is called. Returns http.ErrServerClosed after a graceful shutdown.
 func (s *Server) Start() error {
 	// Start WebSocket hub in goroutine
 	go s.websocketHub.Run()
 
-	return http.ListenAndServe(s.config.Port, s.router)
+	// Deliver collaboration messages saved in the outbox
+	go s.outboxDispatcher.Run()
+
+	if s.jobWorker != nil {
+		s.jobWorker.Run()
+	}
+	if s.jobScheduler != nil {
+		go s.jobScheduler.Run()
+	}
+
+	s.httpServer = &http.Server{
+		Addr:    s.config.Port,
+		Handler: s.router,
+	}
+	return s.httpServer.ListenAndServe()
+}
+
+// Shutdown stops accepting connections, waits for in-flight requests, drains
+// WebSocket clients and closes the broker and Redis connections. Work still
+// pending when ctx expires is abandoned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	var shutdownErr error
+
+	// Stop the listener and wait for regular HTTP requests. Upgraded WebSocket
+	// connections are hijacked and not tracked here; the hub drains them below.
+	if s.httpServer != nil {
+		if err := s.httpServer.Shutdown(ctx); err != nil {
+			shutdownErr = errors.Join(shutdownErr, err)
+		}
+	}
+
+	// Deliver the messages of committed changes before clients are let go
+	s.outboxDispatcher.Stop()
+	s.websocketHub.Drain(ctx)
+
+	if s.jobScheduler != nil {
+		s.jobScheduler.Stop()
+	}
+	if s.jobWorker != nil {
+		s.jobWorker.Stop(ctx)
+	}
+
+	if s.broker != nil {
+		if err := s.broker.Close(); err != nil {
+			shutdownErr = errors.Join(shutdownErr, err)
+		}
+	}
+	if err := s.redis.Close(); err != nil {
+		shutdownErr = errors.Join(shutdownErr, err)
+	}
+
+	return shutdownErr
 }
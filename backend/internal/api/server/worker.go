@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/jobs"
+	redisClient "github.com/Bug-Bugger/ezmodel/internal/redis"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/Bug-Bugger/ezmodel/internal/storage"
+	"gorm.io/gorm"
+)
+
+// errJobsNotShared is returned by NewWorker when the jobs of the API are kept in its own memory
+var errJobsNotShared = errors.New("jobs are not queued in Redis, so only the API process can run them")
+
+// Worker runs the jobs queued by API processes, so slow work can be scaled
+// apart from serving requests
+type Worker struct {
+	redis     *redisClient.Client
+	worker    *jobs.Worker
+	scheduler *jobs.Scheduler // Nil when nothing is scheduled
+}
+
+// NewWorker sets up the services jobs need. It fails unless jobs are queued
+// in Redis, where API processes can reach them too.
+func NewWorker(cfg *config.Config, db *gorm.DB) (*Worker, error) {
+	redis := redisClient.NewClient(cfg)
+	backend := jobs.New(redis, cfg.Jobs.UseRedis)
+	if !jobs.IsShared(backend) {
+		redis.Close()
+		return nil, errJobsNotShared
+	}
+	worker := jobs.NewWorker(backend, cfg.Jobs.Concurrency, cfg.Jobs.PollInterval, cfg.Jobs.Lease)
+
+	jobQueue := jobs.NewClient(backend)
+	projectRepo := repository.NewProjectRepository(db)
+
+	var dataExportService *services.DataExportService
+	store, err := storage.New(cfg)
+	if err != nil {
+		log.Printf("Warning: data export jobs disabled: %v", err)
+	} else {
+		dataExportService = services.NewDataExportService(cfg, repository.NewDataExportRepository(db), repository.NewUserRepository(db),
+			repository.NewUserPreferencesRepository(db), projectRepo, repository.NewLoginEventRepository(db),
+			repository.NewUserSessionRepository(db), repository.NewAPITokenRepository(db), store, jobQueue)
+	}
+	authService := services.NewAuthorizationService(projectRepo, repository.NewTableRepository(db), repository.NewFieldRepository(db),
+		repository.NewRelationshipRepository(db), repository.NewCollaborationSessionRepository(db), nil)
+	snapshotService := services.NewSnapshotService(cfg, repository.NewSnapshotRepository(db), projectRepo,
+		repository.NewRegionRepository(db), authService, jobQueue)
+	var driftService *services.DriftService
+	if cfg.Drift.Enabled {
+		driftService = services.NewDriftService(cfg, repository.NewDriftRepository(db), projectRepo,
+			services.NewUnitOfWork(repository.NewUnitOfWork(db)), authService, jobQueue)
+	}
+	registerJobs(worker, dataExportService, snapshotService, driftService)
+
+	var scheduler *jobs.Scheduler
+	if cfg.Snapshots.Enabled || cfg.Drift.Enabled {
+		scheduler = newScheduler(cfg, jobQueue)
+	}
+
+	return &Worker{redis: redis, worker: worker, scheduler: scheduler}, nil
+}
+
+// Start runs jobs until Shutdown is called. It returns at once.
+func (w *Worker) Start() {
+	w.worker.Run()
+	if w.scheduler != nil {
+		go w.scheduler.Run()
+	}
+}
+
+// Shutdown waits for the running jobs, canceling those still running when ctx
+// ends, and closes the Redis connection
+func (w *Worker) Shutdown(ctx context.Context) error {
+	if w.scheduler != nil {
+		w.scheduler.Stop()
+	}
+	w.worker.Stop(ctx)
+	return w.redis.Close()
+}
+
+// registerJobs sets the handlers of every job type; the data export service
+// is nil when no file storage is configured, the drift service when drift
+// checks are disabled
+func registerJobs(worker *jobs.Worker, dataExportService *services.DataExportService, snapshotService *services.SnapshotService, driftService *services.DriftService) {
+	if dataExportService != nil {
+		worker.Register(services.JobBuildDataExport, dataExportService.BuildExport)
+	}
+	worker.Register(services.JobScheduleSnapshots, snapshotService.ScheduleSnapshots)
+	worker.Register(services.JobTakeSnapshot, snapshotService.TakeSnapshot)
+	if driftService != nil {
+		worker.Register(services.JobScheduleDriftChecks, driftService.ScheduleChecks)
+		worker.Register(services.JobCheckDrift, driftService.RunCheck)
+		worker.Register(services.JobProfileData, driftService.ProfileData)
+	}
+}
+
+// newScheduler schedules the recurring jobs. Every API and worker process
+// runs one; each run is enqueued once however many there are.
+func newScheduler(cfg *config.Config, jobQueue jobs.Queue) *jobs.Scheduler {
+	scheduler := jobs.NewScheduler(jobQueue)
+	if cfg.Snapshots.Enabled {
+		scheduler.Daily(services.JobScheduleSnapshots, cfg.Snapshots.Hour)
+	}
+	if cfg.Drift.Enabled {
+		scheduler.Daily(services.JobScheduleDriftChecks, cfg.Drift.Hour)
+	}
+	return scheduler
+}
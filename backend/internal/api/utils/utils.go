@@ -1,10 +1,18 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"mime"
+	"net"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/responses"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/Bug-Bugger/ezmodel/internal/validation"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -49,6 +57,74 @@ func ParseUUIDParamWithError(w http.ResponseWriter, r *http.Request, paramName,
 	return paramUUID, true
 }
 
+// ParseUUIDQuery parses an optional UUID query parameter, nil when absent
+func ParseUUIDQuery(w http.ResponseWriter, r *http.Request, paramName, errorMessage string) (*uuid.UUID, bool) {
+	value := r.URL.Query().Get(paramName)
+	if value == "" {
+		return nil, true
+	}
+	paramUUID, err := uuid.Parse(value)
+	if err != nil {
+		responses.RespondWithError(w, http.StatusBadRequest, errorMessage)
+		return nil, false
+	}
+	return &paramUUID, true
+}
+
+// ParsePageQuery reads the ?limit=, ?cursor= and ?sort= parameters of a list endpoint
+func ParsePageQuery(w http.ResponseWriter, r *http.Request) (repository.PageQuery, bool) {
+	query := r.URL.Query()
+	page := repository.PageQuery{
+		Cursor: query.Get("cursor"),
+		Sort:   query.Get("sort"),
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			responses.RespondWithError(w, http.StatusBadRequest, "Invalid limit")
+			return repository.PageQuery{}, false
+		}
+		page.Limit = limit
+	}
+	return page, true
+}
+
+// RespondWithPageError reports a cursor or sort the list rejected as a bad
+// request, and any other error as a failure to retrieve the list
+func RespondWithPageError(w http.ResponseWriter, err error, errorMessage string) {
+	switch {
+	case errors.Is(err, repository.ErrInvalidCursor):
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid cursor")
+	case errors.Is(err, repository.ErrInvalidSort):
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid sort")
+	default:
+		responses.RespondWithError(w, http.StatusInternalServerError, errorMessage)
+	}
+}
+
+// ParseIfMatch reads the version an update was made against from an If-Match
+// header holding the ETag of a versioned resource. Absent or * means any version.
+func ParseIfMatch(w http.ResponseWriter, r *http.Request) (*int64, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	etag := strings.TrimPrefix(header, "W/")
+	version, err := strconv.ParseInt(strings.Trim(etag, `"`), 10, 64)
+	if err != nil || len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid If-Match header")
+		return nil, false
+	}
+	return &version, true
+}
+
+// SetVersionETag sets the ETag an If-Match header sends back with an update
+func SetVersionETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", `"`+strconv.FormatInt(version, 10)+`"`)
+}
+
 // DecodeAndValidate decodes JSON request body into the provided struct and validates it
 func DecodeAndValidate(w http.ResponseWriter, r *http.Request, requestStruct any) bool {
 	// Parse request body
@@ -57,12 +133,118 @@ func DecodeAndValidate(w http.ResponseWriter, r *http.Request, requestStruct any
 		return false
 	}
 
-	// Validate input
+	return Validate(w, r, requestStruct)
+}
+
+// Validate validates a request decoded otherwise than by DecodeAndValidate,
+// e.g. from form fields, responding with the field errors when it is invalid
+func Validate(w http.ResponseWriter, r *http.Request, requestStruct any) bool {
 	if err := validation.Validate(requestStruct); err != nil {
-		validationErrors := validation.ValidationErrors(err)
+		// Field messages follow the client's Accept-Language
+		lang := validation.Language(r.Header.Get("Accept-Language"))
+		validationErrors := validation.LocalizedValidationErrors(err, lang)
+		w.Header().Set("Content-Language", lang)
 		responses.RespondWithValidationErrors(w, validationErrors)
 		return false
 	}
 
 	return true
 }
+
+// MergePatchContentType is the media type of a JSON Merge Patch (RFC 7396)
+const MergePatchContentType = "application/merge-patch+json"
+
+// DecodeMergePatch decodes a JSON Merge Patch into an update DTO of pointer
+// fields and validates it, like DecodeAndValidate. Members left out stay
+// unchanged; null clears fields tagged patch:"nullable" and is rejected
+// elsewhere. Unknown members are rejected rather than silently ignored.
+func DecodeMergePatch(w http.ResponseWriter, r *http.Request, requestStruct any) bool {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != MergePatchContentType && mediaType != "application/json" {
+		responses.RespondWithError(w, http.StatusUnsupportedMediaType, "Content-Type must be "+MergePatchContentType)
+		return false
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&members); err != nil || members == nil {
+		responses.RespondWithError(w, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	lang := validation.Language(r.Header.Get("Accept-Language"))
+	target := reflect.ValueOf(requestStruct).Elem()
+	fields := patchFields(target.Type())
+	fieldErrors := make(map[string]string)
+	for name, raw := range members {
+		field, ok := fields[name]
+		if !ok {
+			fieldErrors[name] = validation.LocalizedMessage("unknown_field", lang)
+			continue
+		}
+
+		value := target.Field(field.index)
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			if !field.nullable {
+				fieldErrors[name] = validation.LocalizedMessage("not_null", lang)
+				continue
+			}
+			// A pointer to the zero value clears the field
+			value.Set(reflect.New(value.Type().Elem()))
+			continue
+		}
+		if err := json.Unmarshal(raw, value.Addr().Interface()); err != nil {
+			fieldErrors[name] = validation.LocalizedMessage("default", lang)
+		}
+	}
+	if len(fieldErrors) > 0 {
+		w.Header().Set("Content-Language", lang)
+		responses.RespondWithValidationErrors(w, fieldErrors)
+		return false
+	}
+
+	if err := validation.Validate(requestStruct); err != nil {
+		w.Header().Set("Content-Language", lang)
+		responses.RespondWithValidationErrors(w, validation.LocalizedValidationErrors(err, lang))
+		return false
+	}
+	return true
+}
+
+type patchField struct {
+	index    int
+	nullable bool
+}
+
+// patchFields maps the JSON names of a DTO's pointer fields to their position
+func patchFields(t reflect.Type) map[string]patchField {
+	fields := make(map[string]patchField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" || field.Type.Kind() != reflect.Pointer {
+			continue
+		}
+		fields[name] = patchField{index: i, nullable: field.Tag.Get("patch") == "nullable"}
+	}
+	return fields
+}
+
+// ClientIP returns the IP the request came from. Behind a trusted proxy this is
+// the last X-Forwarded-For entry, the address the proxy itself saw; earlier
+// entries are client-supplied and could be forged.
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			entries := strings.Split(forwarded, ",")
+			if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
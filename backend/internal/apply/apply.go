@@ -0,0 +1,122 @@
+// Package apply runs the statements of a migration on a PostgreSQL database,
+// either all in one transaction or each in its own, and logs what became of
+// every statement.
+package apply
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/introspect"
+	"github.com/jackc/pgx/v5"
+)
+
+// How statements are grouped into transactions
+const (
+	ModeSingle       = "single"        // One transaction: a failure rolls every statement back
+	ModePerStatement = "per_statement" // One per statement: those before a failure stay applied
+)
+
+// Statuses of a statement once the migration ran
+const (
+	StatusApplied    = "applied"
+	StatusFailed     = "failed"
+	StatusRolledBack = "rolled_back" // Ran, but undone with its transaction
+	StatusSkipped    = "skipped"     // Not run, as a statement before it failed
+)
+
+// Result is what became of a statement
+type Result struct {
+	SQL      string
+	Status   string
+	Error    string
+	Duration time.Duration
+}
+
+// Execute runs statements in order on the database of dsn, stopping at the
+// first that fails. It only returns an error when the database cannot be
+// reached or a transaction cannot be started or committed; failing
+// statements are reported by their result.
+func Execute(ctx context.Context, dsn string, statements []string, mode string) ([]Result, error) {
+	if mode != ModeSingle && mode != ModePerStatement {
+		return nil, fmt.Errorf("unknown transaction mode %q", mode)
+	}
+	cfg, err := introspect.ConnConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	cfg.RuntimeParams["application_name"] = "ezmodel-apply"
+
+	conn, err := pgx.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("connecting: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	results := make([]Result, len(statements))
+	for i, statement := range statements {
+		results[i] = Result{SQL: statement, Status: StatusSkipped}
+	}
+	if mode == ModePerStatement {
+		for i := range results {
+			if !run(ctx, conn, &results[i]) {
+				break
+			}
+		}
+		return results, nil
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(context.Background())
+	for i := range results {
+		if !exec(ctx, tx, &results[i]) {
+			for j := range results[:i] {
+				results[j].Status = StatusRolledBack
+			}
+			return results, nil
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing: %w", err)
+	}
+	return results, nil
+}
+
+// run runs the statement of result in a transaction of its own, reporting
+// whether it was applied
+func run(ctx context.Context, conn *pgx.Conn, result *Result) bool {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		return false
+	}
+	defer tx.Rollback(context.Background())
+	if !exec(ctx, tx, result) {
+		return false
+	}
+	if err := tx.Commit(ctx); err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		return false
+	}
+	return true
+}
+
+// exec runs the statement of result within tx, recording how it went
+func exec(ctx context.Context, tx pgx.Tx, result *Result) bool {
+	start := time.Now()
+	_, err := tx.Exec(ctx, result.SQL)
+	result.Duration = time.Since(start)
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		return false
+	}
+	result.Status = StatusApplied
+	return true
+}
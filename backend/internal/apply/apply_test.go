@@ -0,0 +1,20 @@
+package apply
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/introspect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteRejectsBadInput(t *testing.T) {
+	_, err := Execute(context.Background(), "postgres://db.example.com/app", []string{"SELECT 1"}, "batch")
+	assert.ErrorContains(t, err, "unknown transaction mode")
+
+	_, err = Execute(context.Background(), "mysql://db.example.com/app", []string{"SELECT 1"}, ModeSingle)
+	assert.ErrorIs(t, err, introspect.ErrInvalidDSN)
+
+	_, err = Execute(context.Background(), "postgres://app@localhost:5432/app", []string{"SELECT 1"}, ModeSingle)
+	assert.ErrorIs(t, err, introspect.ErrInvalidDSN)
+}
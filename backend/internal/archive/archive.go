@@ -0,0 +1,32 @@
+// Package archive bundles the files of multi-file exports into a zip.
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+)
+
+// File is a file of an archive, named by its path within it
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Zip archives files under their names, in order
+func Zip(files []File) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, file := range files {
+		f, err := w.Create(file.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(file.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,110 @@
+package billing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// Types of the webhook events EzModel acts on
+const (
+	EventCheckoutCompleted   = "checkout.session.completed"
+	EventSubscriptionCreated = "customer.subscription.created"
+	EventSubscriptionUpdated = "customer.subscription.updated"
+	EventSubscriptionDeleted = "customer.subscription.deleted"
+)
+
+// ErrInvalidSignature is returned for a webhook that was not signed with the
+// endpoint's secret, is too old, or cannot be read
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Provider sells subscriptions through a hosted checkout and reports changes to
+// them through webhooks
+type Provider interface {
+	CreateCheckoutSession(ctx context.Context, params *CheckoutParams) (*CheckoutSession, error)
+	// ParseWebhook verifies the signature of a webhook and decodes its event
+	ParseWebhook(payload []byte, signature string) (*Event, error)
+}
+
+// CheckoutParams describes the subscription a checkout sells
+type CheckoutParams struct {
+	PriceID           string
+	CustomerID        string // Existing customer, so repeat buyers keep one; CustomerEmail is used when empty
+	CustomerEmail     string
+	ClientReferenceID string // Our ID of the buyer, returned with the completed session
+	SuccessURL        string
+	CancelURL         string
+	Metadata          map[string]string // Copied to the session and to the subscription it creates
+}
+
+// CheckoutSession is a page where the buyer pays for a subscription
+type CheckoutSession struct {
+	ID                string            `json:"id"`
+	URL               string            `json:"url"`
+	Customer          string            `json:"customer"`
+	Subscription      string            `json:"subscription"`
+	ClientReferenceID string            `json:"client_reference_id"`
+	Metadata          map[string]string `json:"metadata"`
+}
+
+// Event is a webhook notification about the object in Data, a CheckoutSession
+// or a Subscription depending on Type
+type Event struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"` // Unix time
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// CheckoutSession decodes the object of a checkout event
+func (e *Event) CheckoutSession() (*CheckoutSession, error) {
+	var session CheckoutSession
+	if err := json.Unmarshal(e.Data.Object, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Subscription decodes the object of a subscription event
+func (e *Event) Subscription() (*Subscription, error) {
+	var subscription Subscription
+	if err := json.Unmarshal(e.Data.Object, &subscription); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// Subscription is the state of a customer's subscription
+type Subscription struct {
+	ID               string            `json:"id"`
+	Customer         string            `json:"customer"`
+	Status           string            `json:"status"`             // e.g. active, trialing, past_due, canceled
+	CurrentPeriodEnd int64             `json:"current_period_end"` // Unix time; on the items in newer API versions
+	Metadata         map[string]string `json:"metadata"`
+	Items            struct {
+		Data []struct {
+			Price struct {
+				ID string `json:"id"`
+			} `json:"price"`
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// PriceID returns the price of the first item, which is the plan subscribed to
+func (s *Subscription) PriceID() string {
+	if len(s.Items.Data) == 0 {
+		return ""
+	}
+	return s.Items.Data[0].Price.ID
+}
+
+// PeriodEnd returns when the paid period ends as Unix time, or 0 when unknown
+func (s *Subscription) PeriodEnd() int64 {
+	if s.CurrentPeriodEnd != 0 || len(s.Items.Data) == 0 {
+		return s.CurrentPeriodEnd
+	}
+	return s.Items.Data[0].CurrentPeriodEnd
+}
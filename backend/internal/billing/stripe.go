@@ -0,0 +1,149 @@
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	stripeTimeout = 10 * time.Second
+	// signatureTolerance bounds the age of a webhook, so a captured one cannot be replayed later
+	signatureTolerance = 5 * time.Minute
+)
+
+// StripeClient creates Stripe Checkout sessions and verifies Stripe webhooks
+// through the REST API
+type StripeClient struct {
+	client        *http.Client
+	apiURL        string
+	secretKey     string
+	webhookSecret string
+	now           func() time.Time
+}
+
+// NewStripeClient creates a client with the secret API key and the signing secret of the webhook endpoint
+func NewStripeClient(apiURL, secretKey, webhookSecret string) *StripeClient {
+	return &StripeClient{
+		client:        &http.Client{Timeout: stripeTimeout},
+		apiURL:        strings.TrimSuffix(apiURL, "/"),
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+		now:           time.Now,
+	}
+}
+
+// CreateCheckoutSession implements Provider
+func (c *StripeClient) CreateCheckoutSession(ctx context.Context, params *CheckoutParams) (*CheckoutSession, error) {
+	form := url.Values{
+		"mode":                    {"subscription"},
+		"line_items[0][price]":    {params.PriceID},
+		"line_items[0][quantity]": {"1"},
+		"success_url":             {params.SuccessURL},
+		"cancel_url":              {params.CancelURL},
+		"client_reference_id":     {params.ClientReferenceID},
+	}
+	if params.CustomerID != "" {
+		form.Set("customer", params.CustomerID)
+	} else if params.CustomerEmail != "" {
+		form.Set("customer_email", params.CustomerEmail)
+	}
+	for key, value := range params.Metadata {
+		form.Set("metadata["+key+"]", value)
+		form.Set("subscription_data[metadata]["+key+"]", value)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/v1/checkout/sessions", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var stripeErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(body, &stripeErr)
+		return nil, fmt.Errorf("stripe checkout failed with status %d: %s", resp.StatusCode, stripeErr.Error.Message)
+	}
+
+	var session CheckoutSession
+	if err := json.Unmarshal(body, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ParseWebhook implements Provider. The Stripe-Signature header holds the
+// timestamp and one or more HMAC-SHA256 signatures of "timestamp.payload".
+func (c *StripeClient) ParseWebhook(payload []byte, signature string) (*Event, error) {
+	// Anyone can sign with an empty secret
+	if c.webhookSecret == "" {
+		return nil, ErrInvalidSignature
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return nil, ErrInvalidSignature
+	}
+	if age := c.now().Sub(time.Unix(seconds, 0)); age > signatureTolerance || age < -signatureTolerance {
+		return nil, ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(c.webhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	valid := false
+	for _, candidate := range signatures {
+		decoded, err := hex.DecodeString(candidate)
+		if err == nil && hmac.Equal(decoded, expected) {
+			valid = true
+		}
+	}
+	if !valid {
+		return nil, ErrInvalidSignature
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, errors.Join(ErrInvalidSignature, err)
+	}
+	return &event, nil
+}
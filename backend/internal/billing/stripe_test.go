@@ -0,0 +1,113 @@
+package billing
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "whsec_test"
+
+func sign(payload string, timestamp int64, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s", timestamp, payload)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func newTestClient(now time.Time) *StripeClient {
+	client := NewStripeClient("http://stripe.invalid", "sk_test", testWebhookSecret)
+	client.now = func() time.Time { return now }
+	return client
+}
+
+func TestParseWebhook(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	payload := `{"id":"evt_1","type":"customer.subscription.updated","created":1700000000,` +
+		`"data":{"object":{"id":"sub_1","customer":"cus_1","status":"past_due","items":{"data":[{"price":{"id":"price_pro"},"current_period_end":1702592000}]}}}}`
+
+	event, err := newTestClient(now).ParseWebhook([]byte(payload), sign(payload, now.Unix(), testWebhookSecret))
+	require.NoError(t, err)
+	assert.Equal(t, EventSubscriptionUpdated, event.Type)
+
+	subscription, err := event.Subscription()
+	require.NoError(t, err)
+	assert.Equal(t, "past_due", subscription.Status)
+	assert.Equal(t, "price_pro", subscription.PriceID())
+	assert.Equal(t, int64(1702592000), subscription.PeriodEnd())
+}
+
+func TestParseWebhookRejectsBadSignatures(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	payload := `{"id":"evt_1","type":"checkout.session.completed"}`
+
+	for name, signature := range map[string]string{
+		"missing":        "",
+		"other secret":   sign(payload, now.Unix(), "whsec_other"),
+		"too old":        sign(payload, now.Add(-10*time.Minute).Unix(), testWebhookSecret),
+		"other payload":  sign(`{"id":"evt_2"}`, now.Unix(), testWebhookSecret),
+		"only timestamp": fmt.Sprintf("t=%d", now.Unix()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := newTestClient(now).ParseWebhook([]byte(payload), signature)
+			assert.ErrorIs(t, err, ErrInvalidSignature)
+		})
+	}
+}
+
+func TestParseWebhookRejectsAllWithoutSecret(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	payload := `{"id":"evt_1","type":"checkout.session.completed"}`
+	client := NewStripeClient("http://stripe.invalid", "sk_test", "")
+	client.now = func() time.Time { return now }
+
+	_, err := client.ParseWebhook([]byte(payload), sign(payload, now.Unix(), ""))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestCreateCheckoutSession(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/checkout/sessions", r.URL.Path)
+		assert.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "subscription", r.PostForm.Get("mode"))
+		assert.Equal(t, "price_pro", r.PostForm.Get("line_items[0][price]"))
+		assert.Equal(t, "cus_1", r.PostForm.Get("customer"))
+		assert.Empty(t, r.PostForm.Get("customer_email"))
+		assert.Equal(t, "pro", r.PostForm.Get("subscription_data[metadata][plan]"))
+		fmt.Fprint(w, `{"id":"cs_1","url":"https://checkout.stripe.com/c/pay/cs_1"}`)
+	}))
+	defer server.Close()
+
+	client := NewStripeClient(server.URL, "sk_test", testWebhookSecret)
+	session, err := client.CreateCheckoutSession(context.Background(), &CheckoutParams{
+		PriceID:       "price_pro",
+		CustomerID:    "cus_1",
+		CustomerEmail: "ada@example.com",
+		Metadata:      map[string]string{"plan": "pro"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://checkout.stripe.com/c/pay/cs_1", session.URL)
+}
+
+func TestCreateCheckoutSessionReportsStripeErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"message":"No such price: 'price_gone'"}}`)
+	}))
+	defer server.Close()
+
+	_, err := NewStripeClient(server.URL, "sk_test", testWebhookSecret).
+		CreateCheckoutSession(context.Background(), &CheckoutParams{PriceID: "price_gone"})
+
+	assert.ErrorContains(t, err, "No such price")
+}
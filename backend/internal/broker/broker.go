@@ -0,0 +1,72 @@
+package broker
+
+import (
+	"context"
+	"log"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/redis"
+)
+
+// Supported broker types, selected with BROKER_TYPE
+const (
+	TypeRedis = "redis"
+	TypeNATS  = "nats"
+	TypeKafka = "kafka"
+)
+
+// Handler receives the payload of a message published to a topic
+type Handler func(data []byte)
+
+// Publisher sends messages to topics
+type Publisher interface {
+	// Name identifies the implementation in logs
+	Name() string
+
+	// Publish sends a message to every subscriber of the topic
+	Publish(topic string, data []byte) error
+
+	// Close releases the publisher's connections
+	Close() error
+}
+
+// Broker carries WebSocket messages between backend nodes so clients of the
+// same project connected to different nodes see each other's changes
+type Broker interface {
+	Publisher
+
+	// Subscribe delivers messages published to the topic to handler until ctx
+	// is cancelled. Messages this node published are not delivered; its local
+	// clients and observers already have them. It returns once the
+	// subscription is in place.
+	Subscribe(ctx context.Context, topic string, handler Handler) error
+}
+
+// New creates the broker selected in the config. It returns nil when the
+// selected broker is unavailable, in which case messages stay on this node.
+func New(cfg *config.Config, redisClient *redis.Client) Broker {
+	switch cfg.Broker.Type {
+	case TypeNATS:
+		b, err := NewNATSBroker(cfg.Broker.NATSURL)
+		if err != nil {
+			log.Printf("WARNING: Failed to connect to NATS at %s: %v. WebSocket sync disabled.", cfg.Broker.NATSURL, err)
+			return nil
+		}
+		log.Printf("NATS broker connected at %s", cfg.Broker.NATSURL)
+		return b
+
+	case TypeKafka:
+		log.Printf("Kafka broker using topic %s on %v", cfg.Broker.KafkaTopic, cfg.Broker.KafkaBrokers)
+		return NewKafkaBroker(cfg.Broker.KafkaBrokers, cfg.Broker.KafkaTopic)
+
+	case TypeRedis, "":
+		if redisClient == nil || !redisClient.IsEnabled() {
+			return nil
+		}
+		return NewRedisBroker(redisClient)
+
+	default:
+		log.Printf("WARNING: Unknown broker type %q. WebSocket sync disabled.", cfg.Broker.Type)
+		return nil
+	}
+}
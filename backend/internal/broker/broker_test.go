@@ -0,0 +1,136 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNATSSubject(t *testing.T) {
+	assert.Equal(t, "ezmodel.project.123", natsSubject("project:123"))
+}
+
+func TestNewReturnsNilWithoutRedis(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Broker.Type = TypeRedis
+
+	assert.Nil(t, New(cfg, nil))
+}
+
+func TestNewReturnsNilForUnknownType(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Broker.Type = "carrier-pigeon"
+
+	assert.Nil(t, New(cfg, nil))
+}
+
+func TestKafkaDispatch(t *testing.T) {
+	b := &KafkaBroker{
+		nodeID:   "node-a",
+		handlers: make(map[string]map[uint64]Handler),
+	}
+
+	var received []string
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, b.Subscribe(ctx, "project:1", func(data []byte) {
+		received = append(received, string(data))
+	}))
+
+	// Messages for other topics and from this node are ignored
+	b.dispatch(kafka.Message{Key: []byte("project:1"), Value: []byte("remote")})
+	b.dispatch(kafka.Message{Key: []byte("project:2"), Value: []byte("other project")})
+	b.dispatch(kafka.Message{
+		Key:     []byte("project:1"),
+		Value:   []byte("own"),
+		Headers: []kafka.Header{{Key: originHeader, Value: []byte("node-a")}},
+	})
+	assert.Equal(t, []string{"remote"}, received)
+
+	// Cancelling the subscription removes the handler
+	cancel()
+	assert.Eventually(t, func() bool {
+		b.mu.RLock()
+		defer b.mu.RUnlock()
+		return len(b.handlers) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestRedisUnwrap(t *testing.T) {
+	b := &RedisBroker{nodeID: "node-a"}
+	wrap := func(origin, data string) string {
+		payload, err := json.Marshal(redisEnvelope{Origin: origin, Data: []byte(data)})
+		assert.NoError(t, err)
+		return string(payload)
+	}
+
+	data, ok := b.unwrap("project:1", wrap("node-b", "remote"))
+	assert.True(t, ok)
+	assert.Equal(t, "remote", string(data))
+
+	// Messages from this node and malformed ones are dropped
+	_, ok = b.unwrap("project:1", wrap("node-a", "own"))
+	assert.False(t, ok)
+	_, ok = b.unwrap("project:1", "remote")
+	assert.False(t, ok)
+}
+
+func TestNewSinksSkipsMisconfiguredSinks(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.EventSinks.Types = []string{SinkSNS, SinkPubSub, "carrier-pigeon", SinkKafka}
+	cfg.EventSinks.KafkaBrokers = []string{"localhost:9092"}
+	cfg.EventSinks.KafkaTopic = "events"
+
+	sinks := NewSinks(cfg)
+	assert.Len(t, sinks, 1)
+	assert.Equal(t, "kafka sink", sinks[0].Name())
+	assert.NoError(t, sinks[0].Close())
+}
+
+func TestSNSPublishInput(t *testing.T) {
+	standard := &SNSSink{topicARN: "arn:aws:sns:eu-west-1:123456789012:events"}
+	input := standard.publishInput("project:1", []byte(`{"type":"table_created"}`))
+	assert.Equal(t, `{"type":"table_created"}`, *input.Message)
+	assert.Equal(t, "project:1", *input.MessageAttributes[topicAttribute].StringValue)
+	assert.Nil(t, input.MessageGroupId)
+
+	// FIFO topics order a project's events and need a deduplication ID
+	fifo := &SNSSink{topicARN: "arn:aws:sns:eu-west-1:123456789012:events.fifo", fifo: true}
+	input = fifo.publishInput("project:1", []byte(`{"type":"table_created"}`))
+	assert.Equal(t, "project:1", *input.MessageGroupId)
+	assert.Len(t, *input.MessageDeduplicationId, 64)
+}
+
+func TestPubSubSinkPublish(t *testing.T) {
+	var received struct {
+		Messages []pubSubMessage `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/projects/data/topics/events:publish", r.URL.Path)
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.Write([]byte(`{"messageIds":["1"]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("PUBSUB_EMULATOR_HOST", strings.TrimPrefix(server.URL, "http://"))
+	sink, err := NewPubSubSink("data", "events")
+	assert.NoError(t, err)
+
+	assert.NoError(t, sink.Publish("project:1", []byte(`{"type":"table_created"}`)))
+	assert.Len(t, received.Messages, 1)
+	assert.Equal(t, `{"type":"table_created"}`, string(received.Messages[0].Data))
+	assert.Equal(t, "project:1", received.Messages[0].OrderingKey)
+	assert.Equal(t, "project:1", received.Messages[0].Attributes[topicAttribute])
+
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "topic not found", http.StatusNotFound)
+	})
+	assert.ErrorContains(t, sink.Publish("project:1", []byte(`{}`)), "status 404")
+}
@@ -0,0 +1,254 @@
+package broker
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaRetryDelay is how long the consumer waits after a failed read
+const kafkaRetryDelay = time.Second
+
+// originHeader carries the ID of the node that published a Kafka message
+const originHeader = "origin"
+
+// KafkaBroker sends all messages through a single Kafka topic keyed by broker
+// topic. Every node reads every partition of the Kafka topic from its end,
+// outside any consumer group so restarts leave no group behind, and
+// dispatches messages to the local subscribers of their key. Partitions added
+// to the topic later are read once the node restarts.
+type KafkaBroker struct {
+	writer  *kafka.Writer
+	brokers []string
+	topic   string
+	nodeID  string
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	readersMu sync.Mutex
+	readers   []*kafka.Reader
+
+	// Handlers by broker topic and subscription ID
+	mu       sync.RWMutex
+	handlers map[string]map[uint64]Handler
+	nextID   uint64
+}
+
+// NewKafkaBroker creates a broker on the given Kafka brokers and topic and
+// starts consuming new messages
+func NewKafkaBroker(brokers []string, topic string) *KafkaBroker {
+	nodeID := uuid.New().String()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	b := &KafkaBroker{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
+			BatchTimeout:           10 * time.Millisecond,
+			RequiredAcks:           kafka.RequireOne,
+			AllowAutoTopicCreation: true,
+		},
+		brokers:  brokers,
+		topic:    topic,
+		nodeID:   nodeID,
+		ctx:      ctx,
+		cancel:   cancel,
+		handlers: make(map[string]map[uint64]Handler),
+	}
+
+	go b.consume()
+
+	return b
+}
+
+// Name implements Broker
+func (b *KafkaBroker) Name() string {
+	return "kafka"
+}
+
+// Publish implements Broker
+func (b *KafkaBroker) Publish(topic string, data []byte) error {
+	return b.writer.WriteMessages(context.Background(), kafka.Message{
+		Key:   []byte(topic),
+		Value: data,
+		Headers: []kafka.Header{
+			{Key: originHeader, Value: []byte(b.nodeID)},
+		},
+	})
+}
+
+// Subscribe implements Broker
+func (b *KafkaBroker) Subscribe(ctx context.Context, topic string, handler Handler) error {
+	b.mu.Lock()
+	b.nextID++
+	id := b.nextID
+	if b.handlers[topic] == nil {
+		b.handlers[topic] = make(map[uint64]Handler)
+	}
+	b.handlers[topic][id] = handler
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers[topic], id)
+		if len(b.handlers[topic]) == 0 {
+			delete(b.handlers, topic)
+		}
+	}()
+
+	return nil
+}
+
+// Close implements Broker
+func (b *KafkaBroker) Close() error {
+	b.readersMu.Lock()
+	b.cancel()
+	readers := b.readers
+	b.readers = nil
+	b.readersMu.Unlock()
+
+	for _, reader := range readers {
+		if err := reader.Close(); err != nil {
+			return err
+		}
+	}
+	return b.writer.Close()
+}
+
+// consume looks up the partitions of the Kafka topic, retrying until the
+// topic exists, and reads each from its last offset until the broker closes
+func (b *KafkaBroker) consume() {
+	var partitions []kafka.Partition
+	for {
+		var err error
+		if partitions, err = b.lookupPartitions(); err == nil && len(partitions) > 0 {
+			break
+		}
+		if err != nil {
+			log.Printf("Failed to look up Kafka partitions: %v", err)
+		}
+		select {
+		case <-time.After(kafkaRetryDelay):
+		case <-b.ctx.Done():
+			return
+		}
+	}
+
+	b.readersMu.Lock()
+	defer b.readersMu.Unlock()
+	if b.ctx.Err() != nil {
+		return
+	}
+	for _, partition := range partitions {
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:   b.brokers,
+			Topic:     b.topic,
+			Partition: partition.ID,
+			MaxWait:   time.Second,
+		})
+		if err := reader.SetOffset(kafka.LastOffset); err != nil {
+			log.Printf("Failed to read Kafka partition %d: %v", partition.ID, err)
+			reader.Close()
+			continue
+		}
+		b.readers = append(b.readers, reader)
+		go b.read(reader)
+	}
+}
+
+// lookupPartitions returns the partitions of the topic from the first broker that answers
+func (b *KafkaBroker) lookupPartitions() ([]kafka.Partition, error) {
+	var err error
+	for _, address := range b.brokers {
+		var partitions []kafka.Partition
+		if partitions, err = kafka.LookupPartitions(b.ctx, "tcp", address, b.topic); err == nil {
+			return partitions, nil
+		}
+	}
+	return nil, err
+}
+
+// read reads one partition until the broker closes
+func (b *KafkaBroker) read(reader *kafka.Reader) {
+	ctx := b.ctx
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to read from Kafka: %v", err)
+			select {
+			case <-time.After(kafkaRetryDelay):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		b.dispatch(msg)
+	}
+}
+
+// dispatch hands a Kafka message to the local subscribers of its key, skipping
+// messages this node published
+func (b *KafkaBroker) dispatch(msg kafka.Message) {
+	for _, header := range msg.Headers {
+		if header.Key == originHeader && string(header.Value) == b.nodeID {
+			return
+		}
+	}
+
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers[string(msg.Key)]))
+	for _, handler := range b.handlers[string(msg.Key)] {
+		handlers = append(handlers, handler)
+	}
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(msg.Value)
+	}
+}
+
+// KafkaSink publishes events to a Kafka topic keyed by broker topic, so the
+// events of a project stay in order within one partition
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaSink(brokers []string, topic string) *KafkaSink {
+	return &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(brokers...),
+			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
+			BatchTimeout:           10 * time.Millisecond,
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+		},
+	}
+}
+
+// Name implements Publisher
+func (s *KafkaSink) Name() string {
+	return "kafka sink"
+}
+
+// Publish implements Publisher
+func (s *KafkaSink) Publish(topic string, data []byte) error {
+	return s.writer.WriteMessages(context.Background(), kafka.Message{Key: []byte(topic), Value: data})
+}
+
+// Close implements Publisher
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
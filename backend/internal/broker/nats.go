@@ -0,0 +1,65 @@
+package broker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSBroker sends messages over NATS core subjects
+type NATSBroker struct {
+	conn *nats.Conn
+}
+
+// NewNATSBroker connects to a NATS server. Messages published by this node are
+// not echoed back to it.
+func NewNATSBroker(url string) (*NATSBroker, error) {
+	conn, err := nats.Connect(url,
+		nats.Name("ezmodel"),
+		nats.NoEcho(),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NATSBroker{conn: conn}, nil
+}
+
+// Name implements Broker
+func (b *NATSBroker) Name() string {
+	return "nats"
+}
+
+// Publish implements Broker
+func (b *NATSBroker) Publish(topic string, data []byte) error {
+	return b.conn.Publish(natsSubject(topic), data)
+}
+
+// Subscribe implements Broker
+func (b *NATSBroker) Subscribe(ctx context.Context, topic string, handler Handler) error {
+	sub, err := b.conn.Subscribe(natsSubject(topic), func(msg *nats.Msg) {
+		handler(msg.Data)
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		sub.Unsubscribe()
+	}()
+
+	return nil
+}
+
+// Close implements Broker
+func (b *NATSBroker) Close() error {
+	return b.conn.Drain()
+}
+
+// natsSubject maps a topic such as "project:<id>" onto a dot-separated NATS subject
+func natsSubject(topic string) string {
+	return "ezmodel." + strings.ReplaceAll(topic, ":", ".")
+}
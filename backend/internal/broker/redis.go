@@ -0,0 +1,155 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/redis"
+	"github.com/google/uuid"
+)
+
+const (
+	// streamBlockTimeout bounds a single blocking read of a Redis stream
+	streamBlockTimeout = 5 * time.Second
+
+	// streamRetryDelay is how long a stream reader waits after a failed read
+	streamRetryDelay = time.Second
+)
+
+// redisEnvelope wraps a pub/sub payload with the node that published it, as
+// stream entries carry it in a field of their own
+type redisEnvelope struct {
+	Origin string `json:"origin"`
+	Data   []byte `json:"data"`
+}
+
+// RedisBroker sends messages over Redis pub/sub, or over Redis Streams when the
+// client is configured for streams mode
+type RedisBroker struct {
+	client *redis.Client
+	nodeID string
+}
+
+// NewRedisBroker creates a broker on top of an existing Redis client
+func NewRedisBroker(client *redis.Client) *RedisBroker {
+	return &RedisBroker{
+		client: client,
+		nodeID: uuid.New().String(),
+	}
+}
+
+// Name implements Broker
+func (b *RedisBroker) Name() string {
+	if b.client.UsesStreams() {
+		return "redis-streams"
+	}
+	return "redis"
+}
+
+// Publish implements Broker
+func (b *RedisBroker) Publish(topic string, data []byte) error {
+	if b.client.UsesStreams() {
+		return b.client.AppendToStream(streamKey(topic), b.nodeID, data)
+	}
+	payload, err := json.Marshal(redisEnvelope{Origin: b.nodeID, Data: data})
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(topic, payload)
+}
+
+// Subscribe implements Broker
+func (b *RedisBroker) Subscribe(ctx context.Context, topic string, handler Handler) error {
+	if b.client.UsesStreams() {
+		go b.readStream(ctx, topic, handler)
+		return nil
+	}
+
+	pubsub := b.client.Subscribe(topic)
+	if pubsub == nil {
+		return fmt.Errorf("redis subscription to %s unavailable", topic)
+	}
+
+	go func() {
+		defer pubsub.Close()
+		ch := pubsub.Channel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case msg, ok := <-ch:
+				if !ok {
+					log.Printf("Redis channel %s closed", topic)
+					return
+				}
+				if data, ok := b.unwrap(msg.Channel, msg.Payload); ok {
+					handler(data)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Close implements Broker. The Redis client is shared and closed by its owner.
+func (b *RedisBroker) Close() error {
+	return nil
+}
+
+// unwrap returns the data of a pub/sub payload, or false for messages this
+// node published, whose local clients already have them, and malformed ones
+func (b *RedisBroker) unwrap(channel, payload string) ([]byte, bool) {
+	var envelope redisEnvelope
+	if err := json.Unmarshal([]byte(payload), &envelope); err != nil {
+		log.Printf("Dropping malformed message on Redis channel %s: %v", channel, err)
+		return nil, false
+	}
+	if envelope.Origin == b.nodeID {
+		return nil, false
+	}
+	return envelope.Data, true
+}
+
+// streamKey returns the Redis stream carrying a topic's messages
+func streamKey(topic string) string {
+	return topic + ":stream"
+}
+
+// readStream consumes a topic's Redis stream until ctx is cancelled. The last
+// seen ID survives read errors, so a dropped connection resumes without losing events.
+func (b *RedisBroker) readStream(ctx context.Context, topic string, handler Handler) {
+	stream := streamKey(topic)
+	lastID := b.client.StreamStartID(stream)
+
+	for {
+		entries, err := b.client.ReadStream(ctx, stream, lastID, streamBlockTimeout)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to read Redis stream %s: %v", stream, err)
+			select {
+			case <-time.After(streamRetryDelay):
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+
+		for _, entry := range entries {
+			lastID = entry.ID
+
+			// Skip messages this node published; local clients already have them
+			if entry.Origin == b.nodeID {
+				continue
+			}
+			handler(entry.Data)
+		}
+	}
+}
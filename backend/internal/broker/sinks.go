@@ -0,0 +1,50 @@
+package broker
+
+import (
+	"log"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+)
+
+// Supported event sink types, listed in EVENT_SINKS
+const (
+	SinkKafka  = "kafka"
+	SinkSNS    = "sns"
+	SinkPubSub = "pubsub"
+)
+
+// NewSinks creates the event sinks selected in the config. Sinks publish the
+// same schema-change messages as the broker, for consumers outside EzModel
+// such as data platforms. A sink that cannot be created is left out.
+func NewSinks(cfg *config.Config) []Publisher {
+	var sinks []Publisher
+	for _, sinkType := range cfg.EventSinks.Types {
+		switch sinkType {
+		case SinkKafka:
+			log.Printf("Kafka event sink using topic %s on %v", cfg.EventSinks.KafkaTopic, cfg.EventSinks.KafkaBrokers)
+			sinks = append(sinks, NewKafkaSink(cfg.EventSinks.KafkaBrokers, cfg.EventSinks.KafkaTopic))
+
+		case SinkSNS:
+			sink, err := NewSNSSink(cfg.EventSinks.SNSTopicARN)
+			if err != nil {
+				log.Printf("WARNING: SNS event sink disabled: %v", err)
+				continue
+			}
+			log.Printf("SNS event sink using topic %s", cfg.EventSinks.SNSTopicARN)
+			sinks = append(sinks, sink)
+
+		case SinkPubSub:
+			sink, err := NewPubSubSink(cfg.EventSinks.PubSubProject, cfg.EventSinks.PubSubTopic)
+			if err != nil {
+				log.Printf("WARNING: Pub/Sub event sink disabled: %v", err)
+				continue
+			}
+			log.Printf("Pub/Sub event sink using topic %s in project %s", cfg.EventSinks.PubSubTopic, cfg.EventSinks.PubSubProject)
+			sinks = append(sinks, sink)
+
+		default:
+			log.Printf("WARNING: Unknown event sink type %q", sinkType)
+		}
+	}
+	return sinks
+}
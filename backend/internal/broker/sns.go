@@ -0,0 +1,77 @@
+package broker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+// topicAttribute names the message attribute holding the broker topic, which
+// subscribers can filter on
+const topicAttribute = "topic"
+
+// SNSSink publishes events to an Amazon SNS topic. On FIFO topics the events
+// of a project are ordered by using the broker topic as message group.
+type SNSSink struct {
+	client   *sns.Client
+	topicARN string
+	fifo     bool
+}
+
+// NewSNSSink creates a sink with the region and credentials of the AWS
+// environment, e.g. AWS_REGION and an IAM role
+func NewSNSSink(topicARN string) (*SNSSink, error) {
+	if topicARN == "" {
+		return nil, errors.New("no topic ARN configured")
+	}
+
+	cfg, err := awsConfig.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return &SNSSink{
+		client:   sns.NewFromConfig(cfg),
+		topicARN: topicARN,
+		fifo:     strings.HasSuffix(topicARN, ".fifo"),
+	}, nil
+}
+
+// Name implements Publisher
+func (s *SNSSink) Name() string {
+	return "sns sink"
+}
+
+// Publish implements Publisher
+func (s *SNSSink) Publish(topic string, data []byte) error {
+	_, err := s.client.Publish(context.Background(), s.publishInput(topic, data))
+	return err
+}
+
+func (s *SNSSink) publishInput(topic string, data []byte) *sns.PublishInput {
+	input := &sns.PublishInput{
+		TopicArn: aws.String(s.topicARN),
+		Message:  aws.String(string(data)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			topicAttribute: {DataType: aws.String("String"), StringValue: aws.String(topic)},
+		},
+	}
+	if s.fifo {
+		sum := sha256.Sum256(data)
+		input.MessageGroupId = aws.String(topic)
+		input.MessageDeduplicationId = aws.String(hex.EncodeToString(sum[:]))
+	}
+	return input
+}
+
+// Close implements Publisher
+func (s *SNSSink) Close() error {
+	return nil
+}
@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"log"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/redis"
+)
+
+// Store keeps values by key until they expire. A cache miss is not an error.
+type Store interface {
+	// Get returns the value of key, or nil when it is missing or expired
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(keys ...string) error
+}
+
+// New returns a Redis-backed store shared by every node when Redis is
+// available and useRedis is set, and a node-local store otherwise
+func New(redisClient *redis.Client, useRedis bool) Store {
+	memory := NewMemoryStore()
+	if !useRedis || redisClient == nil || !redisClient.IsEnabled() {
+		log.Println("Cache is kept in memory; entries changed on other nodes stay cached until they expire")
+		return memory
+	}
+
+	return NewRedisStore(redisClient, memory)
+}
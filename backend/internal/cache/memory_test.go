@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	value, err := s.Get("project:a")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	assert.NoError(t, s.Set("project:a", []byte("a"), time.Minute))
+	assert.NoError(t, s.Set("project:b", []byte("b"), time.Hour))
+	value, _ = s.Get("project:a")
+	assert.Equal(t, []byte("a"), value)
+
+	// Values are gone once expired or deleted
+	now = now.Add(2 * time.Minute)
+	value, _ = s.Get("project:a")
+	assert.Nil(t, value)
+
+	assert.NoError(t, s.Delete("project:b"))
+	value, _ = s.Get("project:b")
+	assert.Nil(t, value)
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	s.Set("a", []byte("a"), time.Second)
+	s.Set("b", []byte("b"), time.Hour)
+
+	now = now.Add(2 * sweepInterval)
+	s.Set("c", []byte("c"), time.Second)
+
+	assert.NotContains(t, s.entries, "a")
+	assert.Contains(t, s.entries, "b")
+	assert.Contains(t, s.entries, "c")
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"log"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/redis"
+)
+
+// RedisStore keeps values in Redis so every node sees the same entries and
+// invalidations. When Redis is unreachable it falls back to node-local values.
+type RedisStore struct {
+	client   *redis.Client
+	fallback Store
+}
+
+func NewRedisStore(client *redis.Client, fallback Store) *RedisStore {
+	return &RedisStore{
+		client:   client,
+		fallback: fallback,
+	}
+}
+
+func (s *RedisStore) Get(key string) ([]byte, error) {
+	value, err := s.client.Get(key)
+	if err != nil {
+		log.Printf("Cache unavailable in Redis, using local cache: %v", err)
+		return s.fallback.Get(key)
+	}
+	return value, nil
+}
+
+func (s *RedisStore) Set(key string, value []byte, ttl time.Duration) error {
+	if err := s.client.Set(key, value, ttl); err != nil {
+		log.Printf("Cache unavailable in Redis, using local cache: %v", err)
+		return s.fallback.Set(key, value, ttl)
+	}
+	return nil
+}
+
+// Delete removes keys from Redis and from the local values written while it was
+// unreachable. It fails when Redis does, as other nodes may still see the values.
+func (s *RedisStore) Delete(keys ...string) error {
+	s.fallback.Delete(keys...)
+	return s.client.Del(keys...)
+}
@@ -0,0 +1,249 @@
+// Package canvas checks the layout a project's canvas saves: the viewport, where
+// each table node sits and how relationship edges are routed between them.
+package canvas
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultMaxSize limits canvas data in bytes when no limit is configured
+	DefaultMaxSize = 10 * 1024 * 1024
+
+	// MinZoom and MaxZoom bound the viewport zoom
+	MinZoom = 0.01
+	MaxZoom = 10
+
+	// MaxCoordinate bounds positions and offsets in either direction
+	MaxCoordinate = 1e7
+
+	// maxNodes, maxEdges and maxWaypoints bound the canvas beyond its size
+	maxNodes     = 10000
+	maxEdges     = 20000
+	maxWaypoints = 100
+
+	// maxErrors bounds how many problems one ValidationError reports
+	maxErrors = 20
+)
+
+// Empty is the canvas of a project nobody has laid out yet
+const Empty = "{}"
+
+// ErrTooLarge is returned for canvas data over the size limit
+var ErrTooLarge = errors.New("canvas data is too large")
+
+// ValidationError lists what is wrong with canvas data, by the path of the
+// offending value, e.g. "nodes[2].position.x"
+type ValidationError struct {
+	Errors map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	paths := make([]string, 0, len(e.Errors))
+	for path := range e.Errors {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for i, path := range paths {
+		paths[i] = path + ": " + e.Errors[path]
+	}
+	return "invalid canvas data: " + strings.Join(paths, "; ")
+}
+
+// Validate checks canvas data and returns it with surrounding whitespace
+// removed, or Empty when there is none. maxSize is in bytes, DefaultMaxSize
+// when not positive. Properties the canvas does not know are kept unchecked.
+func Validate(data string, maxSize int) (string, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	data = strings.TrimSpace(data)
+	if data == "" {
+		return Empty, nil
+	}
+	if len(data) > maxSize {
+		return "", fmt.Errorf("%w: %d bytes, at most %d are allowed", ErrTooLarge, len(data), maxSize)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.UseNumber()
+	var root any
+	if err := decoder.Decode(&root); err != nil || decoder.More() {
+		return "", &ValidationError{Errors: map[string]string{"": "Must be valid JSON"}}
+	}
+
+	v := &validator{errors: make(map[string]string)}
+	v.canvas(root)
+	if len(v.errors) > 0 {
+		return "", &ValidationError{Errors: v.errors}
+	}
+	return data, nil
+}
+
+// validator collects the problems found while walking decoded canvas data
+type validator struct {
+	errors map[string]string
+}
+
+func (v *validator) fail(path, message string) {
+	if len(v.errors) < maxErrors {
+		v.errors[path] = message
+	}
+}
+
+func (v *validator) canvas(value any) {
+	root, ok := value.(map[string]any)
+	if !ok {
+		v.fail("", "Must be an object")
+		return
+	}
+
+	if viewport, ok := root["viewport"]; ok {
+		v.viewport(viewport)
+	}
+
+	nodeIDs := make(map[string]bool)
+	if nodes, ok := root["nodes"]; ok {
+		for i, node := range v.array("nodes", nodes, maxNodes) {
+			v.node(fmt.Sprintf("nodes[%d]", i), node, nodeIDs)
+		}
+	}
+
+	if edges, ok := root["edges"]; ok {
+		edgeIDs := make(map[string]bool)
+		for i, edge := range v.array("edges", edges, maxEdges) {
+			v.edge(fmt.Sprintf("edges[%d]", i), edge, nodeIDs, edgeIDs)
+		}
+	}
+}
+
+func (v *validator) viewport(value any) {
+	viewport, ok := v.object("viewport", value)
+	if !ok {
+		return
+	}
+	v.coordinate("viewport.x", viewport["x"])
+	v.coordinate("viewport.y", viewport["y"])
+	if zoom, ok := v.number("viewport.zoom", viewport["zoom"]); ok && (zoom < MinZoom || zoom > MaxZoom) {
+		v.fail("viewport.zoom", fmt.Sprintf("Must be between %g and %g", float64(MinZoom), float64(MaxZoom)))
+	}
+}
+
+func (v *validator) node(path string, value any, ids map[string]bool) {
+	node, ok := v.object(path, value)
+	if !ok {
+		return
+	}
+	if id, ok := v.id(path+".id", node["id"]); ok {
+		if ids[id] {
+			v.fail(path+".id", "Must be unique")
+		}
+		ids[id] = true
+	}
+	v.optionalString(path+".type", node)
+	v.position(path+".position", node["position"])
+}
+
+func (v *validator) edge(path string, value any, nodeIDs, ids map[string]bool) {
+	edge, ok := v.object(path, value)
+	if !ok {
+		return
+	}
+	if id, ok := v.id(path+".id", edge["id"]); ok {
+		if ids[id] {
+			v.fail(path+".id", "Must be unique")
+		}
+		ids[id] = true
+	}
+	for _, end := range []string{"source", "target"} {
+		if id, ok := v.id(path+"."+end, edge[end]); ok && !nodeIDs[id] {
+			v.fail(path+"."+end, "Must be the id of a node")
+		}
+	}
+	v.optionalString(path+".type", edge)
+	v.optionalString(path+".sourceHandle", edge)
+	v.optionalString(path+".targetHandle", edge)
+
+	if waypoints, ok := edge["waypoints"]; ok {
+		for i, waypoint := range v.array(path+".waypoints", waypoints, maxWaypoints) {
+			v.position(fmt.Sprintf("%s.waypoints[%d]", path, i), waypoint)
+		}
+	}
+}
+
+// position checks an object with x and y coordinates
+func (v *validator) position(path string, value any) {
+	position, ok := v.object(path, value)
+	if !ok {
+		return
+	}
+	v.coordinate(path+".x", position["x"])
+	v.coordinate(path+".y", position["y"])
+}
+
+func (v *validator) coordinate(path string, value any) {
+	if coordinate, ok := v.number(path, value); ok && math.Abs(coordinate) > MaxCoordinate {
+		v.fail(path, fmt.Sprintf("Must be between %g and %g", -MaxCoordinate, MaxCoordinate))
+	}
+}
+
+func (v *validator) object(path string, value any) (map[string]any, bool) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		v.fail(path, "Must be an object")
+	}
+	return object, ok
+}
+
+// array returns the elements of an array of at most max of them
+func (v *validator) array(path string, value any, max int) []any {
+	array, ok := value.([]any)
+	if !ok {
+		v.fail(path, "Must be an array")
+		return nil
+	}
+	if len(array) > max {
+		v.fail(path, fmt.Sprintf("Must have at most %d elements", max))
+		return nil
+	}
+	return array
+}
+
+func (v *validator) number(path string, value any) (float64, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		v.fail(path, "Must be a number")
+		return 0, false
+	}
+	f, err := number.Float64()
+	if err != nil || math.IsInf(f, 0) {
+		v.fail(path, "Must be a finite number")
+		return 0, false
+	}
+	return f, true
+}
+
+func (v *validator) id(path string, value any) (string, bool) {
+	id, ok := value.(string)
+	if !ok || id == "" {
+		v.fail(path, "Must be a non-empty string")
+		return "", false
+	}
+	return id, true
+}
+
+// optionalString checks that the property of the path's last element is a string when present
+func (v *validator) optionalString(path string, object map[string]any) {
+	key := path[strings.LastIndexByte(path, '.')+1:]
+	if value, ok := object[key]; ok {
+		if _, ok := value.(string); !ok && value != nil {
+			v.fail(path, "Must be a string")
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package canvas
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validCanvas = `{
+	"viewport": {"x": -120.5, "y": 40, "zoom": 1.25},
+	"nodes": [
+		{"id": "users", "type": "table", "position": {"x": 0, "y": 0}},
+		{"id": "posts", "position": {"x": 320, "y": 80}, "selected": true}
+	],
+	"edges": [
+		{"id": "e1", "source": "posts", "target": "users", "sourceHandle": null, "waypoints": [{"x": 160, "y": 40}]}
+	]
+}`
+
+func TestValidate_AcceptsCanvas(t *testing.T) {
+	data, err := Validate("  "+validCanvas+"\n", 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, validCanvas, data)
+}
+
+func TestValidate_EmptyCanvas(t *testing.T) {
+	for _, data := range []string{"", "  ", "{}"} {
+		result, err := Validate(data, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, Empty, result)
+	}
+}
+
+func TestValidate_ReportsProblemsByPath(t *testing.T) {
+	data := `{
+		"viewport": {"x": 0, "y": "top", "zoom": 50},
+		"nodes": [
+			{"id": "a", "position": {"x": 0, "y": 0}},
+			{"id": "a", "position": {"x": 1e9, "y": 0}},
+			{"position": {"x": 0}}
+		],
+		"edges": [
+			{"id": "e1", "source": "a", "target": "missing", "type": 3, "waypoints": {}}
+		]
+	}`
+
+	_, err := Validate(data, 0)
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, map[string]string{
+		"viewport.y":          "Must be a number",
+		"viewport.zoom":       "Must be between 0.01 and 10",
+		"nodes[1].id":         "Must be unique",
+		"nodes[1].position.x": "Must be between -1e+07 and 1e+07",
+		"nodes[2].id":         "Must be a non-empty string",
+		"nodes[2].position.y": "Must be a number",
+		"edges[0].target":     "Must be the id of a node",
+		"edges[0].type":       "Must be a string",
+		"edges[0].waypoints":  "Must be an array",
+	}, validationErr.Errors)
+}
+
+func TestValidate_RejectsInvalidJSON(t *testing.T) {
+	for _, data := range []string{`{"nodes": [`, `{} {}`, `[]`, `"canvas"`} {
+		_, err := Validate(data, 0)
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr, data)
+		assert.Contains(t, validationErr.Errors, "", data)
+	}
+}
+
+func TestValidate_EnforcesSizeLimit(t *testing.T) {
+	data := `{"padding": "` + strings.Repeat("x", 100) + `"}`
+
+	_, err := Validate(data, 64)
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	_, err = Validate(data, 1024)
+	assert.NoError(t, err)
+}
+
+func TestValidate_LimitsReportedErrors(t *testing.T) {
+	nodes := make([]string, 2*maxErrors)
+	for i := range nodes {
+		nodes[i] = `{"id": ""}`
+	}
+
+	_, err := Validate(`{"nodes": [`+strings.Join(nodes, ",")+`]}`, 0)
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Len(t, validationErr.Errors, maxErrors)
+}
@@ -1,23 +1,53 @@
 package config
 
 import (
+	"errors"
+	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/joho/godotenv"
 )
 
+// OAuthClient holds the credentials of an application registered with an OAuth provider
+type OAuthClient struct {
+	ClientID     string
+	ClientSecret string
+}
+
+// BillingPlan is a paid plan sold as a Stripe subscription, with its quotas; 0 means unlimited
+type BillingPlan struct {
+	PriceID             string // Stripe price of the subscription; the plan is not offered when empty
+	MaxProjectsPerUser  int
+	MaxTablesPerProject int
+	MaxCollaborators    int
+}
+
 type Config struct {
-	Port           string
-	Env            string
-	Region         string
-	AllowedOrigins []string
-	Database       struct {
+	Port            string
+	Env             string
+	Region          string
+	AllowedOrigins  []string
+	AdminUserIDs    []string
+	ShutdownTimeout time.Duration // Deadline for draining connections on exit
+	Database        struct {
 		Host     string
 		Port     string
 		User     string
 		Password string
 		DBName   string
 		SSLMode  string
+		// Apply pending migrations at startup; otherwise startup fails until
+		// they are applied with ezmodel migrate
+		MigrateOnStart bool
+		// Connection pool of the primary and of the read replica
+		MaxOpenConns    int
+		MaxIdleConns    int
+		ConnMaxLifetime time.Duration
+		// Statements slower than this are logged and counted; zero disables it
+		SlowQueryThreshold time.Duration
 	}
 	DatabaseReplica struct {
 		Enabled  bool
@@ -27,20 +57,194 @@ type Config struct {
 		Password string
 		DBName   string
 		SSLMode  string
+		// Connection string used instead of the fields above when set
+		DSN string
 	}
 	Redis struct {
+		Enabled      bool
+		Host         string
+		Port         string
+		Password     string
+		DB           int
+		TLS          bool
+		Mode         string        // "pubsub" or "streams"
+		StreamMaxLen int64         // Approximate number of events kept per project stream
+		StreamReplay time.Duration // How far back a newly subscribed node replays stream events
+	}
+	Broker struct {
+		Type         string // "redis", "nats" or "kafka"
+		NATSURL      string
+		KafkaBrokers []string
+		KafkaTopic   string
+	}
+	// EventSinks receive schema-change events for consumers outside EzModel
+	EventSinks struct {
+		Types         []string // Any of "kafka", "sns" and "pubsub"
+		KafkaBrokers  []string
+		KafkaTopic    string
+		SNSTopicARN   string // Region and credentials come from the AWS environment
+		PubSubProject string // Credentials come from Google application default credentials
+		PubSubTopic   string
+	}
+	OAuth struct {
+		RedirectBaseURL string // Public base URL of this API, used to build provider callback URLs
+		FrontendURL     string // Where the browser is sent after an OAuth login
+		Google          OAuthClient
+		GitHub          OAuthClient
+	}
+	SAML struct {
+		Enabled           bool
+		RootURL           string // Public base URL of this API, used for metadata and ACS URLs
+		EntityID          string // Defaults to the metadata URL when empty
+		CertFile          string // PEM certificate of the service provider
+		KeyFile           string // PEM private key of the service provider
+		IDPMetadataURL    string
+		JITProvisioning   bool // Create users on first SAML login instead of requiring an existing account
+		EmailAttribute    string
+		UsernameAttribute string
+	}
+	RateLimit struct {
+		Enabled          bool
+		UseRedis         bool // Share counters across nodes through Redis when it is available
+		TrustProxy       bool // Take the client IP from X-Forwarded-For, only behind a proxy that sets it
+		AuthRequests     int  // Requests per IP per AuthWindow to login, registration and token refresh
+		AuthWindow       time.Duration
+		MutationRequests int // Write requests per user per MutationWindow
+		MutationWindow   time.Duration
+	}
+	ProjectCache struct {
 		Enabled  bool
-		Host     string
-		Port     string
-		Password string
-		DB       int
-		TLS      bool
+		UseRedis bool          // Share entries and invalidations across nodes through Redis when it is available
+		TTL      time.Duration // Upper bound on how long a project stays cached
+		// How long permission checks are cached; changes to the collaborators
+		// of a project invalidate its entries sooner
+		AccessTTL time.Duration
+	}
+	Idempotency struct {
+		Enabled  bool
+		UseRedis bool          // Share keys across nodes through Redis when it is available
+		TTL      time.Duration // How long the response to a key is kept for retries
+		// Requests with a larger body are refused, and larger responses are not
+		// kept, so keys cannot fill the store
+		MaxRequestBytes  int64
+		MaxResponseBytes int
+	}
+	// Outbox delivers the collaboration messages saved with schema changes
+	Outbox struct {
+		PollInterval time.Duration // How often pending messages are looked for, besides right after each commit
+		Retention    time.Duration // How long delivered messages are kept
+	}
+	// EventHistory keeps the latest schema changes of each project for the API
+	EventHistory struct {
+		Size int // Events kept per project
+	}
+	// Canvas limits the layout saved with each project
+	Canvas struct {
+		MaxSize int // Limit for a project's canvas data in bytes
+	}
+	// Quotas limit what users and projects may hold; 0 means unlimited
+	Quotas struct {
+		MaxProjectsPerUser  int
+		MaxTablesPerProject int
+		MaxCollaborators    int // Per project, not counting the owner or service accounts
+	}
+	// Billing sells plans with larger quotas through Stripe; the quotas above are those of the free plan
+	Billing struct {
+		StripeSecretKey     string // Billing is disabled when empty
+		StripeWebhookSecret string // Signing secret of the webhook endpoint
+		StripeAPIURL        string
+		SuccessURL          string // Where Stripe Checkout sends the browser after paying
+		CancelURL           string // Where Stripe Checkout sends the browser when the buyer goes back
+		Pro                 BillingPlan
+		Team                BillingPlan
+	}
+	// Review has schemas reviewed by a language model; built-in rules review them when URL is empty
+	Review struct {
+		URL     string // Base URL of an OpenAI compatible API, e.g. https://api.openai.com/v1
+		APIKey  string
+		Model   string
+		Timeout time.Duration // For one review, which the model may take a while to write
+	}
+	LoginLockout struct {
+		Threshold     int           // Consecutive failures for an account before it is locked
+		BaseDuration  time.Duration // First lockout; doubles with every further failure
+		MaxDuration   time.Duration
+		FailureWindow time.Duration // Failures older than this are forgotten
+		IPThreshold   int           // Failures from one IP, across accounts, before the IP is locked out
+		IPWindow      time.Duration
+	}
+	Impersonation struct {
+		SessionTTL time.Duration // Lifetime of a support session an admin starts as another user
+	}
+	CSRF struct {
+		Enabled bool // Require the double-submit token on cookie-authenticated writes
+	}
+	// Storage keeps uploaded files such as avatars
+	Storage struct {
+		Driver      string // "local" or "s3"
+		LocalDir    string // Directory of the local driver, served under /uploads
+		PublicURL   string // Base URL files are linked from, e.g. a CDN; /uploads of this API for the local driver when empty
+		S3Bucket    string
+		S3Region    string // Credentials come from the AWS environment
+		S3Endpoint  string // For S3-compatible services such as MinIO; AWS when empty
+		S3PathStyle bool
+	}
+	Avatars struct {
+		MaxBytes int64 // Largest accepted upload
+		Size     int   // Width and height stored avatars are resized to
+	}
+	DataExports struct {
+		TTL time.Duration // How long a finished export can be downloaded
+	}
+	// Snapshots are copies of project schemas taken nightly for projects that changed
+	Snapshots struct {
+		Enabled     bool
+		Hour        int // UTC hour the nightly snapshots are taken at
+		KeepDaily   int // Latest snapshot of each of this many days is kept, unless a project overrides it
+		KeepMonthly int // Latest snapshot of each of this many months is kept, unless a project overrides it
+	}
+	// Drift checks compare the live databases of projects with a connection profile to their models
+	Drift struct {
+		Enabled bool
+		Hour    int           // UTC hour the daily checks run at
+		Timeout time.Duration // Longest a check may spend reading a live database
+	}
+	// Jobs run slow work, such as building data exports, outside of requests
+	Jobs struct {
+		UseRedis     bool          // Queue jobs in Redis when it is available, so any node or a worker process can run them
+		InProcess    bool          // The API process runs jobs too; always the case when they are queued in memory
+		Concurrency  int           // Jobs one process runs at once
+		PollInterval time.Duration // How often an idle worker looks for due jobs
+		Lease        time.Duration // How long a job may run before it is assumed lost and run again
+	}
+	// Metrics are served to Prometheus at /metrics
+	Metrics struct {
+		Token string // Bearer token scrapers must send; /metrics is not served when empty
+	}
+	// ErrorReporting sends panics and unexpected errors to a Sentry-compatible service
+	ErrorReporting struct {
+		DSN         string // Reporting is disabled when empty
+		Environment string
+		Release     string
+		SampleRate  float64 // Share of errors reported, from 0 to 1
 	}
 	JWT struct {
 		Secret          string
 		AccessTokenExp  time.Duration
 		RefreshTokenExp time.Duration
 	}
+	WebSocket struct {
+		MaxMessageSize  int64 // Read limit for a single frame in bytes
+		ReadBufferSize  int
+		WriteBufferSize int
+		WriteWait       time.Duration
+		PongWait        time.Duration
+		PingPeriod      time.Duration
+		AuthTimeout     time.Duration
+		IdleTimeout     time.Duration // How long a collaborator does nothing before they are idle
+		WriteWorkers    int           // Database writes from client messages that run at once
+		WriteQueueSize  int           // Writes that may wait for a worker before more are rejected
+	}
 }
 
 func New() *Config {
@@ -51,9 +255,10 @@ func New() *Config {
 	}
 
 	cfg := &Config{
-		Port:   port,
-		Env:    getEnv("ENV", "development"),
-		Region: getEnv("REGION", "region1"),
+		Port:            port,
+		Env:             getEnv("ENV", "development"),
+		Region:          getEnv("REGION", "region1"),
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
 	}
 
 	// CORS Configuration - parse comma-separated origins
@@ -64,6 +269,14 @@ func New() *Config {
 		cfg.AllowedOrigins[i] = strings.TrimSpace(origin)
 	}
 
+	// Admin accounts - comma-separated user IDs allowed to use /api/admin routes
+	if adminIDs := getEnv("ADMIN_USER_IDS", ""); adminIDs != "" {
+		for _, id := range strings.Split(adminIDs, ",") {
+			cfg.AdminUserIDs = append(cfg.AdminUserIDs, strings.TrimSpace(id))
+		}
+	}
+	cfg.Impersonation.SessionTTL = getEnvDuration("ADMIN_IMPERSONATION_TTL", time.Hour)
+
 	// Primary Database Configuration
 	cfg.Database.Host = getEnv("DB_HOST", "localhost")
 	cfg.Database.Port = getEnv("DB_PORT", "5432")
@@ -71,6 +284,11 @@ func New() *Config {
 	cfg.Database.Password = getEnv("DB_PASSWORD", "")
 	cfg.Database.DBName = getEnv("DB_NAME", "ezmodel_backend")
 	cfg.Database.SSLMode = getEnv("DB_SSL_MODE", "disable")
+	cfg.Database.MigrateOnStart = getEnv("DB_MIGRATE_ON_START", strconv.FormatBool(cfg.Env == "development")) == "true"
+	cfg.Database.MaxOpenConns = getEnvInt("DB_MAX_OPEN_CONNS", 100)
+	cfg.Database.MaxIdleConns = getEnvInt("DB_MAX_IDLE_CONNS", 10)
+	cfg.Database.ConnMaxLifetime = getEnvDuration("DB_CONN_MAX_LIFETIME", 24*time.Hour)
+	cfg.Database.SlowQueryThreshold = getEnvDuration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond)
 
 	// Read Replica Configuration
 	cfg.DatabaseReplica.Enabled = getEnv("DB_REPLICA_ENABLED", "false") == "true"
@@ -80,6 +298,7 @@ func New() *Config {
 	cfg.DatabaseReplica.Password = getEnv("DB_REPLICA_PASSWORD", cfg.Database.Password)
 	cfg.DatabaseReplica.DBName = getEnv("DB_REPLICA_NAME", cfg.Database.DBName)
 	cfg.DatabaseReplica.SSLMode = getEnv("DB_REPLICA_SSL_MODE", cfg.Database.SSLMode)
+	cfg.DatabaseReplica.DSN = getEnv("DB_REPLICA_DSN", "")
 
 	// Redis Configuration
 	cfg.Redis.Enabled = getEnv("REDIS_ENABLED", "false") == "true"
@@ -87,6 +306,9 @@ func New() *Config {
 	cfg.Redis.Port = getEnv("REDIS_PORT", "6379")
 	cfg.Redis.Password = getEnv("REDIS_PASSWORD", "")
 	cfg.Redis.TLS = getEnv("REDIS_TLS", "false") == "true"
+	cfg.Redis.Mode = getEnv("REDIS_MODE", "pubsub")
+	cfg.Redis.StreamMaxLen = int64(getEnvInt("REDIS_STREAM_MAXLEN", 1000))
+	cfg.Redis.StreamReplay = getEnvDuration("REDIS_STREAM_REPLAY", 0)
 	cfg.Redis.DB = 0
 	if dbStr := getEnv("REDIS_DB", "0"); dbStr != "0" {
 		if db, err := time.ParseDuration(dbStr + "s"); err == nil {
@@ -94,6 +316,104 @@ func New() *Config {
 		}
 	}
 
+	// Message broker for cross-node WebSocket sync
+	cfg.Broker.Type = getEnv("BROKER_TYPE", "redis")
+	cfg.Broker.NATSURL = getEnv("NATS_URL", "nats://localhost:4222")
+	for _, addr := range strings.Split(getEnv("KAFKA_BROKERS", "localhost:9092"), ",") {
+		cfg.Broker.KafkaBrokers = append(cfg.Broker.KafkaBrokers, strings.TrimSpace(addr))
+	}
+	cfg.Broker.KafkaTopic = getEnv("KAFKA_TOPIC", "ezmodel-ws")
+
+	// Outbound schema-change events
+	for _, sinkType := range strings.Split(getEnv("EVENT_SINKS", ""), ",") {
+		if sinkType = strings.TrimSpace(sinkType); sinkType != "" {
+			cfg.EventSinks.Types = append(cfg.EventSinks.Types, sinkType)
+		}
+	}
+	for _, addr := range strings.Split(getEnv("EVENT_SINK_KAFKA_BROKERS", getEnv("KAFKA_BROKERS", "localhost:9092")), ",") {
+		cfg.EventSinks.KafkaBrokers = append(cfg.EventSinks.KafkaBrokers, strings.TrimSpace(addr))
+	}
+	cfg.EventSinks.KafkaTopic = getEnv("EVENT_SINK_KAFKA_TOPIC", "ezmodel-events")
+	cfg.EventSinks.SNSTopicARN = getEnv("EVENT_SINK_SNS_TOPIC_ARN", "")
+	cfg.EventSinks.PubSubProject = getEnv("EVENT_SINK_PUBSUB_PROJECT", "")
+	cfg.EventSinks.PubSubTopic = getEnv("EVENT_SINK_PUBSUB_TOPIC", "ezmodel-events")
+
+	// HTTP rate limiting
+	cfg.RateLimit.Enabled = getEnv("RATE_LIMIT_ENABLED", "true") == "true"
+	cfg.RateLimit.UseRedis = getEnv("RATE_LIMIT_REDIS", "true") == "true"
+	cfg.RateLimit.TrustProxy = getEnv("RATE_LIMIT_TRUST_PROXY", "false") == "true"
+	cfg.RateLimit.AuthRequests = getEnvInt("RATE_LIMIT_AUTH_REQUESTS", 10)
+	cfg.RateLimit.AuthWindow = getEnvDuration("RATE_LIMIT_AUTH_WINDOW", time.Minute)
+	cfg.RateLimit.MutationRequests = getEnvInt("RATE_LIMIT_MUTATION_REQUESTS", 300)
+	cfg.RateLimit.MutationWindow = getEnvDuration("RATE_LIMIT_MUTATION_WINDOW", time.Minute)
+
+	// Cache of projects with their schema
+	cfg.ProjectCache.Enabled = getEnv("PROJECT_CACHE_ENABLED", "true") == "true"
+	cfg.ProjectCache.UseRedis = getEnv("PROJECT_CACHE_REDIS", "true") == "true"
+	cfg.ProjectCache.TTL = getEnvDuration("PROJECT_CACHE_TTL", time.Minute)
+	cfg.ProjectCache.AccessTTL = getEnvDuration("PROJECT_CACHE_ACCESS_TTL", 15*time.Second)
+
+	// Idempotency-Key replay for retried POST requests
+	cfg.Idempotency.Enabled = getEnv("IDEMPOTENCY_ENABLED", "true") == "true"
+	cfg.Idempotency.UseRedis = getEnv("IDEMPOTENCY_REDIS", "true") == "true"
+	cfg.Idempotency.TTL = getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour)
+	cfg.Idempotency.MaxRequestBytes = int64(getEnvInt("IDEMPOTENCY_MAX_REQUEST_BYTES", 1<<20))
+	cfg.Idempotency.MaxResponseBytes = getEnvInt("IDEMPOTENCY_MAX_RESPONSE_BYTES", 1<<20)
+
+	// Outbox of collaboration messages
+	cfg.Outbox.PollInterval = getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second)
+	cfg.Outbox.Retention = getEnvDuration("OUTBOX_RETENTION", 24*time.Hour)
+
+	// History of schema-change events
+	cfg.EventHistory.Size = getEnvInt("EVENT_HISTORY_SIZE", 1000)
+
+	// Canvas data
+	cfg.Canvas.MaxSize = getEnvInt("CANVAS_MAX_SIZE", 10*1024*1024)
+
+	// Usage quotas
+	cfg.Quotas.MaxProjectsPerUser = getEnvInt("QUOTA_MAX_PROJECTS_PER_USER", 0)
+	cfg.Quotas.MaxTablesPerProject = getEnvInt("QUOTA_MAX_TABLES_PER_PROJECT", 0)
+	cfg.Quotas.MaxCollaborators = getEnvInt("QUOTA_MAX_COLLABORATORS", 0)
+
+	// CSRF protection for cookie-authenticated requests
+	cfg.CSRF.Enabled = getEnv("CSRF_ENABLED", "true") == "true"
+
+	// Login lockout after repeated failures
+	cfg.LoginLockout.Threshold = getEnvInt("LOGIN_LOCKOUT_THRESHOLD", 5)
+	cfg.LoginLockout.BaseDuration = getEnvDuration("LOGIN_LOCKOUT_BASE", time.Minute)
+	cfg.LoginLockout.MaxDuration = getEnvDuration("LOGIN_LOCKOUT_MAX", time.Hour)
+	cfg.LoginLockout.FailureWindow = getEnvDuration("LOGIN_FAILURE_WINDOW", 24*time.Hour)
+	cfg.LoginLockout.IPThreshold = getEnvInt("LOGIN_IP_THRESHOLD", 50)
+	cfg.LoginLockout.IPWindow = getEnvDuration("LOGIN_IP_WINDOW", 15*time.Minute)
+
+	// Uploaded file storage
+	cfg.Storage.Driver = getEnv("STORAGE_DRIVER", "local")
+	cfg.Storage.LocalDir = getEnv("STORAGE_LOCAL_DIR", "./uploads")
+	cfg.Storage.PublicURL = strings.TrimSuffix(getEnv("STORAGE_PUBLIC_URL", ""), "/")
+	cfg.Storage.S3Bucket = getEnv("STORAGE_S3_BUCKET", "")
+	cfg.Storage.S3Region = getEnv("STORAGE_S3_REGION", "")
+	cfg.Storage.S3Endpoint = getEnv("STORAGE_S3_ENDPOINT", "")
+	cfg.Storage.S3PathStyle = getEnv("STORAGE_S3_PATH_STYLE", "false") == "true"
+	cfg.Avatars.MaxBytes = int64(getEnvInt("AVATAR_MAX_BYTES", 5*1024*1024))
+	cfg.Avatars.Size = getEnvInt("AVATAR_SIZE", 256)
+
+	cfg.DataExports.TTL = getEnvDuration("DATA_EXPORT_TTL", 7*24*time.Hour)
+
+	cfg.Snapshots.Enabled = getEnv("SNAPSHOTS_ENABLED", "true") == "true"
+	cfg.Snapshots.Hour = getEnvInt("SNAPSHOT_HOUR", 2)
+	cfg.Snapshots.KeepDaily = getEnvInt("SNAPSHOT_KEEP_DAILY", 30)
+	cfg.Snapshots.KeepMonthly = getEnvInt("SNAPSHOT_KEEP_MONTHLY", 12)
+
+	cfg.Drift.Enabled = getEnv("DRIFT_CHECKS_ENABLED", "true") == "true"
+	cfg.Drift.Hour = getEnvInt("DRIFT_CHECK_HOUR", 4)
+	cfg.Drift.Timeout = getEnvDuration("DRIFT_CHECK_TIMEOUT", time.Minute)
+
+	cfg.Jobs.UseRedis = getEnv("JOBS_REDIS", "true") == "true"
+	cfg.Jobs.InProcess = getEnv("JOBS_IN_PROCESS", "true") == "true"
+	cfg.Jobs.Concurrency = getEnvInt("JOBS_CONCURRENCY", 4)
+	cfg.Jobs.PollInterval = getEnvDuration("JOBS_POLL_INTERVAL", time.Second)
+	cfg.Jobs.Lease = getEnvDuration("JOBS_LEASE", 10*time.Minute)
+
 	// JWT Configuration
 	cfg.JWT.Secret = getEnv("JWT_SECRET", "")
 	accessExp, _ := time.ParseDuration(getEnv("JWT_ACCESS_TOKEN_EXP", "15m"))
@@ -101,12 +421,134 @@ func New() *Config {
 	cfg.JWT.AccessTokenExp = accessExp
 	cfg.JWT.RefreshTokenExp = refreshExp
 
+	// OAuth login providers; a provider is enabled when its client ID is set
+	cfg.OAuth.RedirectBaseURL = strings.TrimSuffix(getEnv("OAUTH_REDIRECT_BASE_URL", "http://localhost:8080"), "/")
+	cfg.OAuth.FrontendURL = getEnv("OAUTH_FRONTEND_URL", "http://localhost:5173/")
+	cfg.OAuth.Google.ClientID = getEnv("OAUTH_GOOGLE_CLIENT_ID", "")
+	cfg.OAuth.Google.ClientSecret = getEnv("OAUTH_GOOGLE_CLIENT_SECRET", "")
+	cfg.OAuth.GitHub.ClientID = getEnv("OAUTH_GITHUB_CLIENT_ID", "")
+	cfg.OAuth.GitHub.ClientSecret = getEnv("OAUTH_GITHUB_CLIENT_SECRET", "")
+
+	// SAML single sign-on
+	cfg.SAML.Enabled = getEnv("SAML_ENABLED", "false") == "true"
+	cfg.SAML.RootURL = strings.TrimSuffix(getEnv("SAML_ROOT_URL", cfg.OAuth.RedirectBaseURL), "/")
+	cfg.SAML.EntityID = getEnv("SAML_ENTITY_ID", "")
+	cfg.SAML.CertFile = getEnv("SAML_CERT_FILE", "")
+	cfg.SAML.KeyFile = getEnv("SAML_KEY_FILE", "")
+	cfg.SAML.IDPMetadataURL = getEnv("SAML_IDP_METADATA_URL", "")
+	cfg.SAML.JITProvisioning = getEnv("SAML_JIT_PROVISIONING", "true") == "true"
+	cfg.SAML.EmailAttribute = getEnv("SAML_EMAIL_ATTRIBUTE", "email")
+	cfg.SAML.UsernameAttribute = getEnv("SAML_USERNAME_ATTRIBUTE", "username")
+
+	// Billing through Stripe
+	cfg.Billing.StripeSecretKey = getEnv("STRIPE_SECRET_KEY", "")
+	cfg.Billing.StripeWebhookSecret = getEnv("STRIPE_WEBHOOK_SECRET", "")
+	cfg.Billing.StripeAPIURL = getEnv("STRIPE_API_URL", "https://api.stripe.com")
+	cfg.Billing.SuccessURL = getEnv("BILLING_SUCCESS_URL", cfg.OAuth.FrontendURL)
+	cfg.Billing.CancelURL = getEnv("BILLING_CANCEL_URL", cfg.OAuth.FrontendURL)
+	cfg.Billing.Pro = getBillingPlan("BILLING_PRO")
+	cfg.Billing.Team = getBillingPlan("BILLING_TEAM")
+
+	// Schema reviews
+	cfg.Review.URL = getEnv("REVIEW_API_URL", "")
+	cfg.Review.APIKey = getEnv("REVIEW_API_KEY", "")
+	cfg.Review.Model = getEnv("REVIEW_MODEL", "gpt-4o-mini")
+	cfg.Review.Timeout = getEnvDuration("REVIEW_TIMEOUT", time.Minute)
+
+	// Prometheus metrics
+	cfg.Metrics.Token = getEnv("METRICS_TOKEN", "")
+
+	// Error reporting
+	cfg.ErrorReporting.DSN = getEnv("SENTRY_DSN", "")
+	cfg.ErrorReporting.Environment = getEnv("SENTRY_ENVIRONMENT", cfg.Env)
+	cfg.ErrorReporting.Release = getEnv("SENTRY_RELEASE", "")
+	cfg.ErrorReporting.SampleRate = getEnvFloat("SENTRY_SAMPLE_RATE", 1)
+
+	// WebSocket Configuration
+	cfg.WebSocket.MaxMessageSize = int64(getEnvInt("WS_MAX_MESSAGE_SIZE", 1024*1024))
+	cfg.WebSocket.ReadBufferSize = getEnvInt("WS_READ_BUFFER_SIZE", 1024)
+	cfg.WebSocket.WriteBufferSize = getEnvInt("WS_WRITE_BUFFER_SIZE", 1024)
+	cfg.WebSocket.WriteWait = getEnvDuration("WS_WRITE_WAIT", 10*time.Second)
+	cfg.WebSocket.PongWait = getEnvDuration("WS_PONG_WAIT", 60*time.Second)
+	cfg.WebSocket.PingPeriod = getEnvDuration("WS_PING_PERIOD", (cfg.WebSocket.PongWait*9)/10)
+	cfg.WebSocket.AuthTimeout = getEnvDuration("WS_AUTH_TIMEOUT", 10*time.Second)
+	cfg.WebSocket.IdleTimeout = getEnvDuration("WS_IDLE_TIMEOUT", 5*time.Minute)
+	cfg.WebSocket.WriteWorkers = getEnvInt("WS_WRITE_WORKERS", 16)
+	cfg.WebSocket.WriteQueueSize = getEnvInt("WS_WRITE_QUEUE_SIZE", 1024)
+
 	return cfg
 }
 
+// Load reads the .env file of the environment named by ENV from the project
+// root, when there is one, then the configuration. Variables already set are
+// kept over the file.
+func Load() *Config {
+	envFile := "../.env.dev"
+	if getEnv("ENV", "development") == "production" {
+		envFile = "../.env.prod"
+	}
+	if err := godotenv.Load(envFile); err != nil {
+		log.Printf("Warning: No %s file found or error loading it. Using default values or environment variables.", envFile)
+	}
+	return New()
+}
+
+// Validate reports settings the server cannot safely start with
+func (c *Config) Validate() error {
+	if c.Billing.StripeSecretKey != "" && c.Billing.StripeWebhookSecret == "" {
+		return errors.New("STRIPE_WEBHOOK_SECRET is required when STRIPE_SECRET_KEY is set, or anyone could forge billing webhooks")
+	}
+	// The WebSocket handlers use these as they are, with no fallback
+	ws := c.WebSocket
+	if ws.MaxMessageSize <= 0 || ws.ReadBufferSize <= 0 || ws.WriteBufferSize <= 0 {
+		return errors.New("WS_MAX_MESSAGE_SIZE, WS_READ_BUFFER_SIZE and WS_WRITE_BUFFER_SIZE must be positive")
+	}
+	if ws.WriteWait <= 0 || ws.PongWait <= 0 || ws.PingPeriod <= 0 || ws.AuthTimeout <= 0 {
+		return errors.New("WS_WRITE_WAIT, WS_PONG_WAIT, WS_PING_PERIOD and WS_AUTH_TIMEOUT must be positive")
+	}
+	return nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
 	}
 	return defaultValue
 }
+
+// getBillingPlan reads a plan from the variables starting with prefix, e.g. BILLING_PRO_PRICE_ID
+func getBillingPlan(prefix string) BillingPlan {
+	return BillingPlan{
+		PriceID:             getEnv(prefix+"_PRICE_ID", ""),
+		MaxProjectsPerUser:  getEnvInt(prefix+"_MAX_PROJECTS_PER_USER", 0),
+		MaxTablesPerProject: getEnvInt(prefix+"_MAX_TABLES_PER_PROJECT", 0),
+		MaxCollaborators:    getEnvInt(prefix+"_MAX_COLLABORATORS", 0),
+	}
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
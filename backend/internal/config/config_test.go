@@ -0,0 +1,26 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	cfg := New()
+	assert.NoError(t, cfg.Validate())
+
+	// Billing cannot be enabled without verifying its webhooks
+	cfg.Billing.StripeSecretKey = "sk_test"
+	assert.Error(t, cfg.Validate())
+
+	cfg.Billing.StripeWebhookSecret = "whsec_test"
+	assert.NoError(t, cfg.Validate())
+
+	// WebSocket limits and timeouts have no fallback in the handlers
+	cfg.WebSocket.MaxMessageSize = 0
+	assert.Error(t, cfg.Validate())
+	cfg = New()
+	cfg.WebSocket.PingPeriod = 0
+	assert.Error(t, cfg.Validate())
+}
@@ -3,45 +3,62 @@ package db
 import (
 	"fmt"
 	"log"
-	"strings"
+	"os"
 	"time"
 
 	"github.com/Bug-Bugger/ezmodel/internal/config"
-	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 	"gorm.io/plugin/dbresolver"
 )
 
-// Connect establishes a connection to the database and performs migrations
+// Connect establishes a connection to the database and applies or checks the migrations
 func Connect(cfg *config.Config) (*gorm.DB, error) {
 	// Primary database connection
 	primaryDSN := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
 		cfg.Database.User, cfg.Database.Password, cfg.Database.Host,
 		cfg.Database.Port, cfg.Database.DBName, cfg.Database.SSLMode)
 
-	db, err := gorm.Open(postgres.Open(primaryDSN), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(primaryDSN), &gorm.Config{Logger: newLogger()})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to primary database: %w", err)
 	}
 
-	// Configure read replica if enabled
-	if cfg.DatabaseReplica.Enabled && cfg.DatabaseReplica.Host != "" {
-		log.Printf("Configuring read replica: %s:%s", cfg.DatabaseReplica.Host, cfg.DatabaseReplica.Port)
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure primary database pool: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
 
-		replicaDSN := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
-			cfg.DatabaseReplica.User, cfg.DatabaseReplica.Password, cfg.DatabaseReplica.Host,
-			cfg.DatabaseReplica.Port, cfg.DatabaseReplica.DBName, cfg.DatabaseReplica.SSLMode)
+	// Configure read replica if enabled
+	replica := cfg.DatabaseReplica
+	if replica.Enabled && (replica.DSN != "" || replica.Host != "") {
+		replicaDSN := replica.DSN
+		if replicaDSN == "" {
+			log.Printf("Configuring read replica: %s:%s", replica.Host, replica.Port)
+			replicaDSN = fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
+				replica.User, replica.Password, replica.Host,
+				replica.Port, replica.DBName, replica.SSLMode)
+		} else {
+			log.Println("Configuring read replica from DB_REPLICA_DSN")
+		}
 
 		err = db.Use(dbresolver.Register(dbresolver.Config{
 			Replicas: []gorm.Dialector{postgres.Open(replicaDSN)},
 			Policy:   dbresolver.RandomPolicy{}, // Load balance across replicas
 		}).
 			SetConnMaxIdleTime(time.Hour).
-			SetConnMaxLifetime(24 * time.Hour).
-			SetMaxIdleConns(10).
-			SetMaxOpenConns(100))
+			SetConnMaxLifetime(cfg.Database.ConnMaxLifetime).
+			SetMaxIdleConns(cfg.Database.MaxIdleConns).
+			SetMaxOpenConns(cfg.Database.MaxOpenConns))
 
+		if err == nil {
+			// Only reads marked with ReplicaRead leave the primary
+			err = db.Use(newReplicaRouter())
+		}
 		if err != nil {
 			log.Printf("WARNING: Failed to configure read replica: %v. Continuing with primary only.", err)
 		} else {
@@ -53,31 +70,28 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 		log.Println("Read replica not enabled, using primary database only")
 	}
 
-	// Ensure pgcrypto extension exists for UUID generation defaults
-	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS "pgcrypto"`).Error; err != nil {
-		return nil, fmt.Errorf("failed to enable pgcrypto extension: %w", err)
-	}
-
-	// Auto Migrate the schema (safe migration that handles existing tables)
-	err = db.AutoMigrate(
-		&models.User{},
-		&models.Project{},
-		&models.Table{},
-		&models.Field{},
-		&models.Relationship{},
-		&models.CollaborationSession{},
-	)
-	if err != nil {
-		// Check if the error is about tables already existing
-		if !isTableExistsError(err.Error()) {
+	// Bring the schema to the version of this build, or make sure it already is
+	if cfg.Database.MigrateOnStart {
+		if err := Migrate(cfg); err != nil {
 			return nil, err
 		}
-		// If it's just table exists errors, we can continue safely
+	} else if err := CheckMigrations(cfg); err != nil {
+		return nil, err
 	}
 
 	return db, nil
 }
 
+// newLogger logs failed statements without their bound parameters, which may
+// hold user data. Slow statements are logged by the metrics plugin instead.
+func newLogger() logger.Interface {
+	return logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), logger.Config{
+		LogLevel:             logger.Warn,
+		Colorful:             true,
+		ParameterizedQueries: true,
+	})
+}
+
 // startReplicaHealthCheck monitors replica health and logs issues
 func startReplicaHealthCheck(db *gorm.DB) {
 	ticker := time.NewTicker(30 * time.Second)
@@ -92,10 +106,3 @@ func startReplicaHealthCheck(db *gorm.DB) {
 		}
 	}
 }
-
-// isTableExistsError checks if the error is related to tables already existing
-func isTableExistsError(errorMsg string) bool {
-	errorMsg = strings.ToLower(errorMsg)
-	return strings.Contains(errorMsg, "already exists") ||
-		strings.Contains(errorMsg, "relation") && strings.Contains(errorMsg, "already exists")
-}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5" // pgx5:// database URLs
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// migrationFiles are the versioned schema changes, NNNNNN_name.up.sql with a
+// matching .down.sql reverting it. Existing files must never be edited once
+// released; change the schema by adding the next version.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// ErrSchemaOutdated is returned when the database lags behind the migrations of this build
+var ErrSchemaOutdated = errors.New("database schema is not up to date")
+
+// NewMigrator returns a runner for the embedded migrations on the primary
+// database. It holds its own connection; Close it when done.
+func NewMigrator(cfg *config.Config) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", source, migrationURL(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect for migrations: %w", err)
+	}
+	return m, nil
+}
+
+// LatestVersion returns the version of the newest embedded migration
+func LatestVersion() (uint, error) {
+	source, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return 0, err
+	}
+	defer source.Close()
+
+	version, err := source.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := source.Next(version)
+		if err != nil {
+			return version, nil
+		}
+		version = next
+	}
+}
+
+// Migrate applies every pending migration
+func Migrate(cfg *config.Config) error {
+	m, err := NewMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return nil
+}
+
+// CheckMigrations returns ErrSchemaOutdated unless every embedded migration
+// has been applied cleanly, so the server never runs against a schema it was
+// not built for
+func CheckMigrations(cfg *config.Config) error {
+	latest, err := LatestVersion()
+	if err != nil {
+		return err
+	}
+
+	m, err := NewMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	version, dirty, err := m.Version()
+	switch {
+	case errors.Is(err, migrate.ErrNilVersion):
+		return fmt.Errorf("%w: no migrations applied, expected version %d; run ezmodel migrate up", ErrSchemaOutdated, latest)
+	case err != nil:
+		return err
+	case dirty:
+		return fmt.Errorf("%w: migration %d failed part way; fix the schema and run ezmodel migrate force", ErrSchemaOutdated, version)
+	case version < latest:
+		return fmt.Errorf("%w: at version %d, expected %d; run ezmodel migrate up", ErrSchemaOutdated, version, latest)
+	}
+	return nil
+}
+
+// migrationURL is the primary database as a pgx5:// URL, escaping the credentials
+func migrationURL(cfg *config.Config) string {
+	u := url.URL{
+		Scheme:   "pgx5",
+		User:     url.UserPassword(cfg.Database.User, cfg.Database.Password),
+		Host:     net.JoinHostPort(cfg.Database.Host, cfg.Database.Port),
+		Path:     "/" + cfg.Database.DBName,
+		RawQuery: url.Values{"sslmode": {cfg.Database.SSLMode}}.Encode(),
+	}
+	return u.String()
+}
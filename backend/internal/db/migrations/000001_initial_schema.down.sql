@@ -0,0 +1,18 @@
+DROP TABLE IF EXISTS "data_exports";
+DROP TABLE IF EXISTS "user_preferences";
+DROP TABLE IF EXISTS "project_views";
+DROP TABLE IF EXISTS "project_stars";
+DROP TABLE IF EXISTS "project_tags";
+DROP TABLE IF EXISTS "admin_audit_logs";
+DROP TABLE IF EXISTS "user_sessions";
+DROP TABLE IF EXISTS "login_events";
+DROP TABLE IF EXISTS "service_accounts";
+DROP TABLE IF EXISTS "api_tokens";
+DROP TABLE IF EXISTS "user_identities";
+DROP TABLE IF EXISTS "collaboration_sessions";
+DROP TABLE IF EXISTS "relationships";
+DROP TABLE IF EXISTS "fields";
+DROP TABLE IF EXISTS "tables";
+DROP TABLE IF EXISTS "project_collaborators";
+DROP TABLE IF EXISTS "projects";
+DROP TABLE IF EXISTS "users";
@@ -0,0 +1,280 @@
+-- Baseline schema, as previously created by GORM AutoMigrate. Every statement
+-- is idempotent so databases created by AutoMigrate can adopt migrations by
+-- running this one.
+
+CREATE EXTENSION IF NOT EXISTS "pgcrypto";
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "email" text NOT NULL,
+    "username" text NOT NULL,
+    "password_hash" text NOT NULL,
+    "avatar_url" text NOT NULL DEFAULT '',
+    "avatar_key" text NOT NULL DEFAULT '',
+    "is_service_account" boolean NOT NULL DEFAULT false,
+    "role" text NOT NULL DEFAULT 'user',
+    "disabled_at" timestamptz,
+    "password_reset_required" boolean NOT NULL DEFAULT false,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_role" ON "users" ("role");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_username" ON "users" ("username");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+
+CREATE TABLE IF NOT EXISTS "projects" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "name" text NOT NULL,
+    "description" text,
+    "owner_id" uuid NOT NULL,
+    "database_type" text DEFAULT 'postgresql',
+    "canvas_data" jsonb,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "version" bigint NOT NULL DEFAULT 1,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_owned_projects" FOREIGN KEY ("owner_id") REFERENCES "users"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "project_collaborators" (
+    "project_id" uuid,
+    "user_id" uuid,
+    PRIMARY KEY ("project_id", "user_id"),
+    CONSTRAINT "fk_project_collaborators_project" FOREIGN KEY ("project_id") REFERENCES "projects"("id"),
+    CONSTRAINT "fk_project_collaborators_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "tables" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "name" text NOT NULL,
+    "pos_x" decimal,
+    "pos_y" decimal,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "version" bigint NOT NULL DEFAULT 1,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_projects_tables" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "fields" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "table_id" uuid NOT NULL,
+    "name" text NOT NULL,
+    "data_type" text NOT NULL,
+    "is_primary_key" boolean DEFAULT false,
+    "is_nullable" boolean DEFAULT true,
+    "default_value" text,
+    "position" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "version" bigint NOT NULL DEFAULT 1,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_tables_fields" FOREIGN KEY ("table_id") REFERENCES "tables"("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "relationships" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "source_table_id" uuid NOT NULL,
+    "source_field_id" uuid NOT NULL,
+    "target_table_id" uuid NOT NULL,
+    "target_field_id" uuid NOT NULL,
+    "relation_type" text DEFAULT 'one_to_many',
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "version" bigint NOT NULL DEFAULT 1,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_projects_relationships" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "collaboration_sessions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "cursor_x" decimal,
+    "cursor_y" decimal,
+    "user_color" text,
+    "is_active" boolean DEFAULT true,
+    "last_ping_at" timestamptz,
+    "joined_at" timestamptz,
+    "left_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "user_identities" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "provider" text NOT NULL,
+    "subject" text NOT NULL,
+    "email" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_identities_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_identity_provider_subject" ON "user_identities" ("provider", "subject");
+CREATE INDEX IF NOT EXISTS "idx_user_identities_user_id" ON "user_identities" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "api_tokens" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "name" text NOT NULL,
+    "prefix" text NOT NULL,
+    "token_hash" text NOT NULL,
+    "scopes" text NOT NULL,
+    "project_id" uuid,
+    "last_used_at" timestamptz,
+    "expires_at" timestamptz,
+    "revoked_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_api_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_api_tokens_project_id" ON "api_tokens" ("project_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_api_tokens_token_hash" ON "api_tokens" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_api_tokens_user_id" ON "api_tokens" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "service_accounts" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "name" text NOT NULL,
+    "scopes" text NOT NULL,
+    "created_by_id" uuid NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_service_accounts_project" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_service_accounts_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_service_accounts_user_id" ON "service_accounts" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_service_accounts_project_id" ON "service_accounts" ("project_id");
+
+CREATE TABLE IF NOT EXISTS "login_events" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid,
+    "email" text NOT NULL,
+    "ip_address" text NOT NULL,
+    "user_agent" text,
+    "success" boolean NOT NULL,
+    "failure_reason" text,
+    "suspicious" boolean NOT NULL DEFAULT false,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_login_events_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_login_event_ip_created" ON "login_events" ("ip_address", "created_at");
+CREATE INDEX IF NOT EXISTS "idx_login_event_email_created" ON "login_events" ("email", "created_at");
+CREATE INDEX IF NOT EXISTS "idx_login_events_user_id" ON "login_events" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "user_sessions" (
+    "id" uuid,
+    "user_id" uuid NOT NULL,
+    "refresh_token_hash" text NOT NULL,
+    "ip_address" text,
+    "user_agent" text,
+    "last_seen_at" timestamptz NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "revoked_at" timestamptz,
+    "impersonator_id" uuid,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_sessions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_user_sessions_user_id" ON "user_sessions" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "admin_audit_logs" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "actor_id" uuid NOT NULL,
+    "action" text NOT NULL,
+    "target_user_id" uuid NOT NULL,
+    "details" text,
+    "ip_address" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_admin_audit_logs_created_at" ON "admin_audit_logs" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_admin_audit_logs_target_user_id" ON "admin_audit_logs" ("target_user_id");
+CREATE INDEX IF NOT EXISTS "idx_admin_audit_logs_action" ON "admin_audit_logs" ("action");
+CREATE INDEX IF NOT EXISTS "idx_admin_audit_logs_actor_id" ON "admin_audit_logs" ("actor_id");
+
+CREATE TABLE IF NOT EXISTS "project_tags" (
+    "project_id" uuid,
+    "name" varchar(50),
+    "created_at" timestamptz,
+    PRIMARY KEY ("project_id", "name"),
+    CONSTRAINT "fk_projects_tags" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_project_tags_name" ON "project_tags" ("name");
+
+CREATE TABLE IF NOT EXISTS "project_stars" (
+    "user_id" uuid,
+    "project_id" uuid,
+    "created_at" timestamptz,
+    PRIMARY KEY ("user_id", "project_id"),
+    CONSTRAINT "fk_project_stars_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_project_stars_project" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_project_stars_project_id" ON "project_stars" ("project_id");
+
+CREATE TABLE IF NOT EXISTS "project_views" (
+    "user_id" uuid,
+    "project_id" uuid,
+    "viewed_at" timestamptz NOT NULL,
+    PRIMARY KEY ("user_id", "project_id"),
+    CONSTRAINT "fk_project_views_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_project_views_project" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_project_views_viewed_at" ON "project_views" ("viewed_at");
+CREATE INDEX IF NOT EXISTS "idx_project_views_project_id" ON "project_views" ("project_id");
+
+CREATE TABLE IF NOT EXISTS "user_preferences" (
+    "user_id" uuid,
+    "theme" text NOT NULL,
+    "default_database_type" text NOT NULL,
+    "canvas_show_grid" boolean NOT NULL,
+    "canvas_snap_to_grid" boolean NOT NULL,
+    "canvas_grid_size" bigint NOT NULL,
+    "notify_collaborator_joined" boolean NOT NULL,
+    "notify_project_shared" boolean NOT NULL,
+    "email_notifications" boolean NOT NULL,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("user_id"),
+    CONSTRAINT "fk_user_preferences_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "data_exports" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "user_id" uuid NOT NULL,
+    "status" text NOT NULL,
+    "storage_key" text NOT NULL DEFAULT '',
+    "size_bytes" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    "completed_at" timestamptz,
+    "expires_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_data_exports_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_data_exports_user_id" ON "data_exports" ("user_id");
+
+-- Full-text search columns. The simple configuration keeps identifiers
+-- unstemmed, and the parser splits snake_case names into words so "customer"
+-- matches customer_id.
+ALTER TABLE "projects" ADD COLUMN IF NOT EXISTS "search_vector" tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(name, '')), 'A') || setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+) STORED;
+CREATE INDEX IF NOT EXISTS "idx_projects_search_vector" ON "projects" USING GIN ("search_vector");
+
+ALTER TABLE "tables" ADD COLUMN IF NOT EXISTS "search_vector" tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', coalesce(name, ''))
+) STORED;
+CREATE INDEX IF NOT EXISTS "idx_tables_search_vector" ON "tables" USING GIN ("search_vector");
+
+ALTER TABLE "fields" ADD COLUMN IF NOT EXISTS "search_vector" tsvector GENERATED ALWAYS AS (
+    to_tsvector('simple', coalesce(name, ''))
+) STORED;
+CREATE INDEX IF NOT EXISTS "idx_fields_search_vector" ON "fields" USING GIN ("search_vector");
@@ -0,0 +1,13 @@
+-- Collaboration messages saved with the schema change they describe. There is
+-- no foreign key to projects so a project's deletion can still be announced.
+CREATE TABLE IF NOT EXISTS "outbox_events" (
+    "id" bigserial,
+    "project_id" uuid NOT NULL,
+    "message" jsonb NOT NULL,
+    "attempts" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz,
+    "dispatched_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_outbox_events_pending" ON "outbox_events" ("id") WHERE "dispatched_at" IS NULL;
+CREATE INDEX IF NOT EXISTS "idx_outbox_events_dispatched_at" ON "outbox_events" ("dispatched_at") WHERE "dispatched_at" IS NOT NULL;
@@ -0,0 +1,3 @@
+-- The last sequence number given to a change of the project, so clients can
+-- order its events and notice the ones they missed
+ALTER TABLE "projects" ADD COLUMN IF NOT EXISTS "sequence" bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+ALTER TABLE "relationships" DROP COLUMN IF EXISTS "label_position";
+ALTER TABLE "relationships" DROP COLUMN IF EXISTS "waypoints";
+ALTER TABLE "relationships" DROP COLUMN IF EXISTS "target_anchor";
+ALTER TABLE "relationships" DROP COLUMN IF EXISTS "source_anchor";
@@ -0,0 +1,5 @@
+-- How each relationship's edge is drawn, so every collaborator sees the same route
+ALTER TABLE "relationships" ADD COLUMN IF NOT EXISTS "source_anchor" text NOT NULL DEFAULT 'auto';
+ALTER TABLE "relationships" ADD COLUMN IF NOT EXISTS "target_anchor" text NOT NULL DEFAULT 'auto';
+ALTER TABLE "relationships" ADD COLUMN IF NOT EXISTS "waypoints" jsonb NOT NULL DEFAULT '[]';
+ALTER TABLE "relationships" ADD COLUMN IF NOT EXISTS "label_position" double precision NOT NULL DEFAULT 0.5;
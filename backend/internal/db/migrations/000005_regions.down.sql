@@ -0,0 +1,2 @@
+ALTER TABLE "tables" DROP COLUMN IF EXISTS "region_id";
+DROP TABLE IF EXISTS "regions";
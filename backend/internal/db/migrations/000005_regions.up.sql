@@ -0,0 +1,22 @@
+-- Named, colored areas of the canvas clustering related tables
+CREATE TABLE IF NOT EXISTS "regions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "name" text NOT NULL,
+    "color" text NOT NULL,
+    "pos_x" decimal,
+    "pos_y" decimal,
+    "width" decimal NOT NULL,
+    "height" decimal NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "version" bigint NOT NULL DEFAULT 1,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_projects_regions" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_regions_project_id" ON "regions" ("project_id");
+
+-- A table is in at most one region; deleting the region keeps its tables
+ALTER TABLE "tables" ADD COLUMN IF NOT EXISTS "region_id" uuid;
+ALTER TABLE "tables" ADD CONSTRAINT "fk_regions_tables" FOREIGN KEY ("region_id") REFERENCES "regions"("id") ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS "idx_tables_region_id" ON "tables" ("region_id");
@@ -0,0 +1,2 @@
+ALTER TABLE "tables" DROP COLUMN IF EXISTS "icon";
+ALTER TABLE "tables" DROP COLUMN IF EXISTS "color";
@@ -0,0 +1,3 @@
+-- Colors and icons distinguishing the tables of different domains
+ALTER TABLE "tables" ADD COLUMN IF NOT EXISTS "color" text NOT NULL DEFAULT '';
+ALTER TABLE "tables" ADD COLUMN IF NOT EXISTS "icon" text NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE "tables" DROP COLUMN IF EXISTS "field_order_sequence";
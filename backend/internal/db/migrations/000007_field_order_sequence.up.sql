@@ -0,0 +1,3 @@
+-- Project sequence of the last confirmed reorder of each table's fields, so a
+-- reorder made without seeing it can be told apart and rejected
+ALTER TABLE "tables" ADD COLUMN IF NOT EXISTS "field_order_sequence" bigint NOT NULL DEFAULT 0;
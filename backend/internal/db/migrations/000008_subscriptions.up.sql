@@ -0,0 +1,15 @@
+-- Paid plans of users, kept in step with Stripe
+CREATE TABLE IF NOT EXISTS "subscriptions" (
+    "user_id" uuid,
+    "plan" text NOT NULL,
+    "status" text NOT NULL,
+    "stripe_customer_id" text NOT NULL,
+    "stripe_subscription_id" text NOT NULL,
+    "current_period_end" timestamptz,
+    "event_created_at" timestamptz NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("user_id"),
+    CONSTRAINT "fk_subscriptions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_subscriptions_stripe_customer_id" ON "subscriptions" ("stripe_customer_id");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "snapshot_policies";
+DROP TABLE IF EXISTS "project_snapshots";
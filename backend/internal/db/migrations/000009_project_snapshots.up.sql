@@ -0,0 +1,23 @@
+-- Copies of project schemas taken nightly by a background job
+CREATE TABLE IF NOT EXISTS "project_snapshots" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "project_version" bigint NOT NULL,
+    "project_sequence" bigint NOT NULL,
+    "data" jsonb NOT NULL,
+    "size_bytes" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_projects_snapshots" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_project_snapshots_project_id_created_at" ON "project_snapshots" ("project_id", "created_at" DESC);
+
+-- How many snapshots a project keeps, where it differs from the instance defaults
+CREATE TABLE IF NOT EXISTS "snapshot_policies" (
+    "project_id" uuid,
+    "keep_daily" integer,
+    "keep_monthly" integer,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("project_id"),
+    CONSTRAINT "fk_projects_snapshot_policy" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "drift_checks";
+DROP TABLE IF EXISTS "connection_profiles";
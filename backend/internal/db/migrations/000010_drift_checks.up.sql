@@ -0,0 +1,29 @@
+-- How projects' live databases are reached, read-only, to check them for drift
+CREATE TABLE IF NOT EXISTS "connection_profiles" (
+    "project_id" uuid,
+    "dsn" text NOT NULL,
+    "schema_name" text NOT NULL DEFAULT 'public',
+    "scheduled_checks" boolean NOT NULL DEFAULT true,
+    "webhook_url" text NOT NULL DEFAULT '',
+    "webhook_secret" text NOT NULL DEFAULT '',
+    "created_by" uuid NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("project_id"),
+    CONSTRAINT "fk_projects_connection_profile" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+
+-- Comparisons of live databases to their models
+CREATE TABLE IF NOT EXISTS "drift_checks" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "status" text NOT NULL,
+    "trigger" text NOT NULL,
+    "diff" text NOT NULL DEFAULT '',
+    "error" text NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL,
+    "completed_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_projects_drift_checks" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_drift_checks_project_id_created_at" ON "drift_checks" ("project_id", "created_at" DESC);
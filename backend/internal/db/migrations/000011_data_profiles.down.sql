@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "field_profiles";
+DROP TABLE IF EXISTS "table_profiles";
+ALTER TABLE "connection_profiles" DROP COLUMN IF EXISTS "data_profiled_at";
+ALTER TABLE "connection_profiles" DROP COLUMN IF EXISTS "data_profile_error";
+ALTER TABLE "connection_profiles" DROP COLUMN IF EXISTS "data_profile_status";
@@ -0,0 +1,27 @@
+-- State of the latest profiling of a project's live database
+ALTER TABLE "connection_profiles" ADD COLUMN IF NOT EXISTS "data_profile_status" text NOT NULL DEFAULT '';
+ALTER TABLE "connection_profiles" ADD COLUMN IF NOT EXISTS "data_profile_error" text NOT NULL DEFAULT '';
+ALTER TABLE "connection_profiles" ADD COLUMN IF NOT EXISTS "data_profiled_at" timestamptz;
+
+-- Statistics of the live counterparts of tables and fields, from the latest profiling
+CREATE TABLE IF NOT EXISTS "table_profiles" (
+    "table_id" uuid,
+    "project_id" uuid NOT NULL,
+    "row_count" bigint,
+    "profiled_at" timestamptz NOT NULL,
+    PRIMARY KEY ("table_id"),
+    CONSTRAINT "fk_tables_profile" FOREIGN KEY ("table_id") REFERENCES "tables"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_table_profiles_project_id" ON "table_profiles" ("project_id");
+
+CREATE TABLE IF NOT EXISTS "field_profiles" (
+    "field_id" uuid,
+    "table_id" uuid NOT NULL,
+    "project_id" uuid NOT NULL,
+    "null_ratio" double precision NOT NULL,
+    "distinct_count" bigint NOT NULL,
+    "profiled_at" timestamptz NOT NULL,
+    PRIMARY KEY ("field_id"),
+    CONSTRAINT "fk_fields_profile" FOREIGN KEY ("field_id") REFERENCES "fields"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_field_profiles_project_id" ON "field_profiles" ("project_id");
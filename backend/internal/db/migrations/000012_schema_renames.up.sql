@@ -0,0 +1,15 @@
+-- Tables and fields renamed by refactoring, replayed by migrations as renames
+CREATE TABLE IF NOT EXISTS "schema_renames" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "table_id" uuid NOT NULL,
+    "field_id" uuid,
+    "table_name" text NOT NULL,
+    "old_name" text NOT NULL,
+    "new_name" text NOT NULL,
+    "renamed_by" uuid NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_projects_schema_renames" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_schema_renames_project_id_created_at" ON "schema_renames" ("project_id", "created_at");
@@ -0,0 +1,14 @@
+-- Named SQL kept with a project and carried along by its exports
+CREATE TABLE IF NOT EXISTS "snippets" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "name" text NOT NULL,
+    "description" text NOT NULL DEFAULT '',
+    "sql" text NOT NULL,
+    "created_by" uuid NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    "updated_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_projects_snippets" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_snippets_project_id_name" ON "snippets" ("project_id", "name");
@@ -0,0 +1,4 @@
+ALTER TABLE "relationships" DROP COLUMN IF EXISTS "metadata";
+ALTER TABLE "fields" DROP COLUMN IF EXISTS "metadata";
+ALTER TABLE "tables" DROP COLUMN IF EXISTS "metadata";
+ALTER TABLE "projects" DROP COLUMN IF EXISTS "metadata";
@@ -0,0 +1,5 @@
+-- Metadata organizations record about schema objects, e.g. a data owner or a retention class
+ALTER TABLE "projects" ADD COLUMN IF NOT EXISTS "metadata" jsonb NOT NULL DEFAULT '{}';
+ALTER TABLE "tables" ADD COLUMN IF NOT EXISTS "metadata" jsonb NOT NULL DEFAULT '{}';
+ALTER TABLE "fields" ADD COLUMN IF NOT EXISTS "metadata" jsonb NOT NULL DEFAULT '{}';
+ALTER TABLE "relationships" ADD COLUMN IF NOT EXISTS "metadata" jsonb NOT NULL DEFAULT '{}';
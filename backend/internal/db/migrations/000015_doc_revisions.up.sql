@@ -0,0 +1,12 @@
+-- Edits of the Markdown document of a project, the latest being the document
+CREATE TABLE IF NOT EXISTS "doc_revisions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "version" bigint NOT NULL,
+    "content" text NOT NULL,
+    "edited_by" uuid NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_projects_doc_revisions" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_doc_revisions_project_id_version" ON "doc_revisions" ("project_id", "version");
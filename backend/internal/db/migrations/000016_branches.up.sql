@@ -0,0 +1,17 @@
+-- Copies of a project's schema edited apart from it and merged back
+CREATE TABLE IF NOT EXISTS "branches" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "name" text NOT NULL,
+    "base" jsonb NOT NULL,
+    "schema" jsonb NOT NULL,
+    "version" bigint NOT NULL DEFAULT 1,
+    "created_by" uuid NOT NULL,
+    "merged_by" uuid,
+    "merged_at" timestamptz,
+    "created_at" timestamptz NOT NULL,
+    "updated_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_projects_branches" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_branches_project_id_name" ON "branches" ("project_id", "name");
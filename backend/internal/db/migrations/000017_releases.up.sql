@@ -0,0 +1,17 @@
+-- Named snapshots of a project, pinned so that pruning keeps them
+CREATE TABLE IF NOT EXISTS "releases" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "name" text NOT NULL,
+    "notes" text NOT NULL DEFAULT '',
+    "snapshot_id" uuid NOT NULL,
+    "locked" boolean NOT NULL DEFAULT false,
+    "created_by" uuid NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    "updated_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_projects_releases" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_project_snapshots_releases" FOREIGN KEY ("snapshot_id") REFERENCES "project_snapshots"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_releases_project_id_name" ON "releases" ("project_id", "name");
+CREATE INDEX IF NOT EXISTS "idx_releases_snapshot_id" ON "releases" ("snapshot_id");
@@ -0,0 +1,12 @@
+-- Messages collaborators on a project send each other
+CREATE TABLE IF NOT EXISTS "chat_messages" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "user_id" uuid NOT NULL,
+    "content" text NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_projects_chat_messages" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_users_chat_messages" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_chat_messages_project_id_created_at" ON "chat_messages" ("project_id", "created_at" DESC);
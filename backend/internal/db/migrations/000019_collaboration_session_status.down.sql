@@ -0,0 +1 @@
+ALTER TABLE "collaboration_sessions" DROP COLUMN IF EXISTS "status";
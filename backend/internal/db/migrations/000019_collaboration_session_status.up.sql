@@ -0,0 +1,2 @@
+-- Whether a collaborator is active, idle or away, as seen by the WebSocket hub
+ALTER TABLE "collaboration_sessions" ADD COLUMN IF NOT EXISTS "status" text NOT NULL DEFAULT 'active';
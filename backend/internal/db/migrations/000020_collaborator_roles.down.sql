@@ -0,0 +1 @@
+ALTER TABLE "project_collaborators" DROP COLUMN IF EXISTS "role";
@@ -0,0 +1,2 @@
+-- Whether a collaborator may change the project (editor) or only look at it (viewer)
+ALTER TABLE "project_collaborators" ADD COLUMN IF NOT EXISTS "role" text NOT NULL DEFAULT 'editor';
@@ -0,0 +1,11 @@
+-- The latest schema-change messages of each project, kept for clients that
+-- catch up over the API. Older ones are removed as new ones arrive.
+CREATE TABLE IF NOT EXISTS "project_events" (
+    "project_id" uuid NOT NULL,
+    "sequence" bigint NOT NULL,
+    "type" text NOT NULL,
+    "message" jsonb NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("project_id", "sequence"),
+    CONSTRAINT "fk_projects_project_events" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
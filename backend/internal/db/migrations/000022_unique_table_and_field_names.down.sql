@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_fields_table_name";
+DROP INDEX IF EXISTS "idx_tables_project_name";
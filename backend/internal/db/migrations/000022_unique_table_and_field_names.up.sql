@@ -0,0 +1,19 @@
+-- A table whose name an earlier table of its project has, or a field whose
+-- name an earlier field of its table has, gets the start of its ID appended,
+-- so the names can be made unique below.
+UPDATE "tables" SET "name" = "tables"."name" || '_' || left("tables"."id"::text, 8)
+FROM (
+    SELECT "id", row_number() OVER (PARTITION BY "project_id", "name" ORDER BY "created_at", "id") AS "rank"
+    FROM "tables"
+) AS "ranked"
+WHERE "tables"."id" = "ranked"."id" AND "ranked"."rank" > 1;
+
+UPDATE "fields" SET "name" = "fields"."name" || '_' || left("fields"."id"::text, 8)
+FROM (
+    SELECT "id", row_number() OVER (PARTITION BY "table_id", "name" ORDER BY "created_at", "id") AS "rank"
+    FROM "fields"
+) AS "ranked"
+WHERE "fields"."id" = "ranked"."id" AND "ranked"."rank" > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tables_project_name" ON "tables" ("project_id", "name");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_fields_table_name" ON "fields" ("table_id", "name");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_fields_table_lower_name";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_fields_table_name" ON "fields" ("table_id", "name");
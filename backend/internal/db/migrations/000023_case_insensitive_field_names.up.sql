@@ -0,0 +1,11 @@
+-- A field whose name an earlier field of its table has in another case gets
+-- the start of its ID appended, so the names can be made unique ignoring case.
+UPDATE "fields" SET "name" = "fields"."name" || '_' || left("fields"."id"::text, 8)
+FROM (
+    SELECT "id", row_number() OVER (PARTITION BY "table_id", lower("name") ORDER BY "created_at", "id") AS "rank"
+    FROM "fields"
+) AS "ranked"
+WHERE "fields"."id" = "ranked"."id" AND "ranked"."rank" > 1;
+
+DROP INDEX IF EXISTS "idx_fields_table_name";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_fields_table_lower_name" ON "fields" ("table_id", lower("name"));
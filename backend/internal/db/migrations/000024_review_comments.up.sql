@@ -0,0 +1,22 @@
+-- Remarks of schema reviews, kept until resolved
+CREATE TABLE IF NOT EXISTS "review_comments" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "table_id" uuid,
+    "field_id" uuid,
+    "table_name" text NOT NULL,
+    "field_name" text NOT NULL DEFAULT '',
+    "kind" text NOT NULL,
+    "message" text NOT NULL,
+    "suggestion" text NOT NULL DEFAULT '',
+    "provider" text NOT NULL,
+    "created_by" uuid NOT NULL,
+    "resolved_by" uuid,
+    "resolved_at" timestamptz,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_projects_review_comments" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_tables_review_comments" FOREIGN KEY ("table_id") REFERENCES "tables"("id") ON DELETE SET NULL,
+    CONSTRAINT "fk_fields_review_comments" FOREIGN KEY ("field_id") REFERENCES "fields"("id") ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS "idx_review_comments_project_id" ON "review_comments" ("project_id");
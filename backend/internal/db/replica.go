@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+const (
+	// replicaReadKey marks a query that may be served by a read replica
+	replicaReadKey = "ezmodel:replica_read"
+	// routedKey marks a query already routed, so its preloads follow the same database
+	routedKey = "ezmodel:replica_routed"
+	// Settings dbresolver stores for its Read and Write clauses, which are
+	// honored as given
+	resolverReadKey  = "gorm:db_resolver:read"
+	resolverWriteKey = "gorm:db_resolver:write"
+)
+
+// writesKey is the context key of a request's writes
+type writesKey struct{}
+
+// writes records whether a request has written
+type writes struct {
+	written atomic.Bool
+}
+
+// TrackWrites returns a context for a request, or any unit of work, whose
+// reads marked with ReplicaRead may be served by a read replica until it
+// first writes. From then on they stay on the primary, so it reads its own
+// writes while the replica catches up.
+func TrackWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, writesKey{}, &writes{})
+}
+
+// ReplicaRead lets a query be served by a read replica when made with a
+// context from TrackWrites, through WithContext, that has not written yet.
+// Use it for reads that tolerate replication lag, never for those authorizing
+// access; every other query goes to the primary.
+func ReplicaRead(tx *gorm.DB) *gorm.DB {
+	return tx.Set(replicaReadKey, true)
+}
+
+// replicaRouter keeps queries on the primary unless marked with ReplicaRead
+// and made for a request that has not written yet
+type replicaRouter struct{}
+
+func newReplicaRouter() *replicaRouter {
+	return &replicaRouter{}
+}
+
+// Name implements gorm.Plugin
+func (r *replicaRouter) Name() string {
+	return "ezmodel:replica_router"
+}
+
+// Initialize implements gorm.Plugin. dbresolver runs first in every chain, so
+// the router overrides its choice through dbresolver's own Read and Write
+// operations just before the statement is executed.
+func (r *replicaRouter) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	if err := callback.Query().Before("gorm:query").Register("ezmodel:route_query", r.route); err != nil {
+		return err
+	}
+	if err := callback.Row().Before("gorm:row").Register("ezmodel:route_row", r.route); err != nil {
+		return err
+	}
+	if err := callback.Raw().Before("gorm:raw").Register("ezmodel:route_raw", r.route); err != nil {
+		return err
+	}
+	if err := callback.Create().After("gorm:create").Register("ezmodel:record_create", r.recordWrite); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:update").Register("ezmodel:record_update", r.recordWrite); err != nil {
+		return err
+	}
+	if err := callback.Delete().After("gorm:delete").Register("ezmodel:record_delete", r.recordWrite); err != nil {
+		return err
+	}
+	return callback.Raw().After("gorm:raw").Register("ezmodel:record_raw", r.recordRawWrite)
+}
+
+func (r *replicaRouter) route(db *gorm.DB) {
+	settings := &db.Statement.Settings
+	if _, ok := settings.Load(routedKey); ok {
+		return
+	}
+	if _, ok := settings.Load(resolverReadKey); ok {
+		return
+	}
+	if _, ok := settings.Load(resolverWriteKey); ok {
+		return
+	}
+
+	settings.Store(routedKey, true)
+	if _, ok := settings.Load(replicaReadKey); ok && !written(db.Statement.Context) {
+		dbresolver.Read.ModifyStatement(db.Statement)
+		return
+	}
+	dbresolver.Write.ModifyStatement(db.Statement)
+}
+
+// written reports whether the request of ctx has written, or is unknown as
+// ctx does not come from TrackWrites
+func written(ctx context.Context) bool {
+	if ctx == nil {
+		return true
+	}
+	w, ok := ctx.Value(writesKey{}).(*writes)
+	return !ok || w.written.Load()
+}
+
+func (r *replicaRouter) recordWrite(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	record(db.Statement.Context)
+}
+
+// recordRawWrite records raw statements other than SELECT as writes
+func (r *replicaRouter) recordRawWrite(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	sql := strings.TrimSpace(db.Statement.SQL.String())
+	if len(sql) >= 6 && strings.EqualFold(sql[:6], "select") {
+		return
+	}
+	record(db.Statement.Context)
+}
+
+// record pins the rest of the request of ctx to the primary
+func record(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	if w, ok := ctx.Value(writesKey{}).(*writes); ok {
+		w.written.Store(true)
+	}
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestReplicaRouterPinsRequestAfterWrite(t *testing.T) {
+	router := newReplicaRouter()
+	ctx := TrackWrites(context.Background())
+	other := TrackWrites(context.Background())
+
+	assert.False(t, written(ctx))
+
+	failed := statement(ctx, "")
+	failed.Error = errors.New("duplicate key")
+	router.recordWrite(failed)
+	assert.False(t, written(ctx), "failed writes change nothing")
+
+	router.recordWrite(statement(ctx, ""))
+	assert.True(t, written(ctx))
+	assert.False(t, written(other), "other requests are not pinned")
+}
+
+func TestReplicaRouterPinsRequestAfterRawWrite(t *testing.T) {
+	router := newReplicaRouter()
+	ctx := TrackWrites(context.Background())
+
+	router.recordRawWrite(statement(ctx, "SELECT 1"))
+	assert.False(t, written(ctx))
+
+	router.recordRawWrite(statement(ctx, "UPDATE projects SET name = 'x'"))
+	assert.True(t, written(ctx))
+}
+
+func TestReplicaRouterKeepsUntrackedQueriesOnPrimary(t *testing.T) {
+	assert.True(t, written(context.Background()))
+	assert.True(t, written(nil))
+}
+
+func statement(ctx context.Context, sql string) *gorm.DB {
+	stmt := &gorm.Statement{Context: ctx}
+	stmt.SQL.WriteString(sql)
+	return &gorm.DB{Config: &gorm.Config{}, Statement: stmt}
+}
@@ -0,0 +1,212 @@
+// Package dbt scaffolds the staging layer of a dbt project from a schema: a
+// source declaring its tables, with tests following from their keys and
+// foreign keys, and a staging model selecting the columns of each table.
+// Files follow the dbt Labs layout, under models/staging/<source>.
+package dbt
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/archive"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"gopkg.in/yaml.v3"
+)
+
+// Tests dbt runs on columns
+const (
+	testUnique        = "unique"
+	testNotNull       = "not_null"
+	testRelationships = "relationships"
+)
+
+type properties struct {
+	Version int      `yaml:"version"`
+	Sources []source `yaml:"sources,omitempty"`
+	Models  []model  `yaml:"models,omitempty"`
+}
+
+type source struct {
+	Name        string         `yaml:"name"`
+	Description string         `yaml:"description,omitempty"`
+	Meta        map[string]any `yaml:"meta,omitempty"`
+	Tables      []sourceTable  `yaml:"tables"`
+}
+
+type sourceTable struct {
+	Name    string         `yaml:"name"`
+	Meta    map[string]any `yaml:"meta,omitempty"`
+	Columns []column       `yaml:"columns,omitempty"`
+}
+
+type model struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Columns     []column `yaml:"columns,omitempty"`
+}
+
+type column struct {
+	Name     string         `yaml:"name"`
+	DataType string         `yaml:"data_type,omitempty"`
+	Meta     map[string]any `yaml:"meta,omitempty"`
+	Tests    []any          `yaml:"data_tests,omitempty"` // Test names, or maps of a test name to its arguments
+}
+
+// nonWord matches what cannot be part of a dbt name
+var nonWord = regexp.MustCompile(`[^a-z0-9]+`)
+
+// identifier matches column names selected without quotes
+var identifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// Name turns a project name into a source name, e.g. "Shop DB" into shop_db
+func Name(name string) string {
+	name = strings.Trim(nonWord.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "ezmodel"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// Scaffold writes the source of a schema, named after the project, with a
+// staging model per table. Primary keys are tested unique and not null in
+// both, required columns not null and foreign keys for their relationships
+// in the source. Tables without fields are left out. The metadata of the
+// project, tables and fields is the meta of the source, its tables and columns.
+func Scaffold(projectName, description string, meta map[string]any, schema schemadiff.Schema) ([]archive.File, error) {
+	sourceName := Name(projectName)
+	dir := "models/staging/" + sourceName + "/"
+
+	references := make(map[[2]string]schemadiff.ForeignKey)
+	for _, foreignKey := range schema.ForeignKeys() {
+		references[[2]string{foreignKey.Table, foreignKey.Field}] = foreignKey
+	}
+
+	sources := properties{Version: 2, Sources: []source{{Name: sourceName, Description: description, Meta: meta}}}
+	models := properties{Version: 2}
+	var files []archive.File
+	for _, table := range schema.Tables {
+		if len(table.Fields) == 0 {
+			continue
+		}
+		modelName := "stg_" + sourceName + "__" + Name(table.Name)
+		sourceTable := sourceTable{Name: table.Name, Meta: table.Metadata}
+		stagingModel := model{Name: modelName, Description: fmt.Sprintf("%s from the %s source, one row per row of the table.", table.Name, sourceName)}
+		var selected []string
+		for _, field := range table.Fields {
+			sourceColumn := column{Name: field.Name, DataType: field.DataType, Meta: field.Metadata}
+			modelColumn := column{Name: field.Name}
+			switch {
+			case field.IsPrimaryKey:
+				sourceColumn.Tests = []any{testUnique, testNotNull}
+				modelColumn.Tests = []any{testUnique, testNotNull}
+			case !field.IsNullable:
+				sourceColumn.Tests = []any{testNotNull}
+			}
+			if foreignKey, ok := references[[2]string{table.Name, field.Name}]; ok {
+				sourceColumn.Tests = append(sourceColumn.Tests, map[string]any{testRelationships: map[string]string{
+					"to":    fmt.Sprintf("source('%s', '%s')", sourceName, foreignKey.RefTable),
+					"field": foreignKey.RefField,
+				}})
+			}
+			sourceTable.Columns = append(sourceTable.Columns, sourceColumn)
+			if len(modelColumn.Tests) > 0 {
+				stagingModel.Columns = append(stagingModel.Columns, modelColumn)
+			}
+			selected = append(selected, selectColumn(field.Name))
+		}
+		sources.Sources[0].Tables = append(sources.Sources[0].Tables, sourceTable)
+		models.Models = append(models.Models, stagingModel)
+		files = append(files, archive.File{Name: dir + modelName + ".sql", Data: stagingSQL(sourceName, table.Name, selected)})
+	}
+
+	sourcesYAML, err := marshal(sources)
+	if err != nil {
+		return nil, err
+	}
+	modelsYAML, err := marshal(models)
+	if err != nil {
+		return nil, err
+	}
+	return append([]archive.File{
+		{Name: dir + "_" + sourceName + "__sources.yml", Data: sourcesYAML},
+		{Name: dir + "_" + sourceName + "__models.yml", Data: modelsYAML},
+	}, files...), nil
+}
+
+// Analysis is SQL kept with the project, e.g. an example query
+type Analysis struct {
+	Name        string
+	Description string
+	SQL         string
+}
+
+// Analyses writes each analysis to analyses/, which dbt compiles but never
+// runs, named after it. Names made alike are numbered, e.g. top_customers_2.
+func Analyses(analyses []Analysis) []archive.File {
+	files := make([]archive.File, 0, len(analyses))
+	taken := make(map[string]bool, len(analyses))
+	for _, analysis := range analyses {
+		name := Name(analysis.Name)
+		for i := 2; taken[name]; i++ {
+			name = fmt.Sprintf("%s_%d", Name(analysis.Name), i)
+		}
+		taken[name] = true
+
+		var buf bytes.Buffer
+		for _, line := range strings.Split(analysis.Description, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				buf.WriteString("-- " + line + "\n")
+			}
+		}
+		buf.WriteString(strings.TrimRight(analysis.SQL, "\n") + "\n")
+		files = append(files, archive.File{Name: "analyses/" + name + ".sql", Data: buf.Bytes()})
+	}
+	return files
+}
+
+// stagingSQL writes a staging model selecting the columns of a source table
+func stagingSQL(sourceName, table string, columns []string) []byte {
+	return fmt.Appendf(nil, `with source as (
+
+    select * from {{ source('%s', '%s') }}
+
+),
+
+renamed as (
+
+    select
+        %s
+
+    from source
+
+)
+
+select * from renamed
+`, sourceName, table, strings.Join(columns, ",\n        "))
+}
+
+// selectColumn names a column in a select list, quoted unless a plain identifier
+func selectColumn(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
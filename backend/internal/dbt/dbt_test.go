@@ -0,0 +1,128 @@
+package dbt
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffold(t *testing.T) {
+	schema := schemadiff.Schema{
+		Tables: []schemadiff.Table{
+			{Name: "users", Fields: []schemadiff.Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "email", DataType: "VARCHAR(255)"},
+			}},
+			{Name: "Orders", Fields: []schemadiff.Field{
+				{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+				{Name: "user_id", DataType: "UUID", IsNullable: true},
+				{Name: "Placed At", DataType: "TIMESTAMP", IsNullable: true},
+			}},
+			{Name: "empty"},
+		},
+		Relationships: []schemadiff.Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "Orders", TargetField: "user_id", RelationType: "one_to_many"},
+		},
+	}
+
+	files, err := Scaffold("Shop DB", "The shop", nil, schema)
+
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+	assert.Equal(t, "models/staging/shop_db/_shop_db__sources.yml", files[0].Name)
+	assert.Equal(t, `version: 2
+sources:
+  - name: shop_db
+    description: The shop
+    tables:
+      - name: users
+        columns:
+          - name: id
+            data_type: UUID
+            data_tests:
+              - unique
+              - not_null
+          - name: email
+            data_type: VARCHAR(255)
+            data_tests:
+              - not_null
+      - name: Orders
+        columns:
+          - name: id
+            data_type: INTEGER
+            data_tests:
+              - unique
+              - not_null
+          - name: user_id
+            data_type: UUID
+            data_tests:
+              - relationships:
+                  field: id
+                  to: source('shop_db', 'users')
+          - name: Placed At
+            data_type: TIMESTAMP
+`, string(files[0].Data))
+
+	assert.Equal(t, "models/staging/shop_db/_shop_db__models.yml", files[1].Name)
+	assert.Contains(t, string(files[1].Data), `  - name: stg_shop_db__orders
+    description: Orders from the shop_db source, one row per row of the table.
+    columns:
+      - name: id
+        data_tests:
+          - unique
+          - not_null
+`)
+
+	assert.Equal(t, "models/staging/shop_db/stg_shop_db__users.sql", files[2].Name)
+	assert.Equal(t, "models/staging/shop_db/stg_shop_db__orders.sql", files[3].Name)
+	assert.Contains(t, string(files[3].Data), "select * from {{ source('shop_db', 'Orders') }}")
+	assert.Contains(t, string(files[3].Data), "        id,\n        user_id,\n        \"Placed At\"\n")
+}
+
+func TestScaffoldMeta(t *testing.T) {
+	schema := schemadiff.Schema{Tables: []schemadiff.Table{
+		{Name: "users", Metadata: map[string]any{"data_owner": "crm"}, Fields: []schemadiff.Field{
+			{Name: "email", DataType: "TEXT", IsNullable: true, Metadata: map[string]any{"pii": true}},
+		}},
+	}}
+
+	files, err := Scaffold("Shop", "", map[string]any{"domain": "sales"}, schema)
+
+	require.NoError(t, err)
+	assert.Equal(t, `version: 2
+sources:
+  - name: shop
+    meta:
+      domain: sales
+    tables:
+      - name: users
+        meta:
+          data_owner: crm
+        columns:
+          - name: email
+            data_type: TEXT
+            meta:
+              pii: true
+`, string(files[0].Data))
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "shop_db", Name("  Shop DB! "))
+	assert.Equal(t, "_2024_sales", Name("2024 Sales"))
+	assert.Equal(t, "ezmodel", Name("???"))
+}
+
+func TestAnalyses(t *testing.T) {
+	files := Analyses([]Analysis{
+		{Name: "Top customers", Description: "Who spends most\nlast year", SQL: "select user_id, sum(total) from orders group by 1\n\n"},
+		{Name: "top-customers", SQL: "select 1"},
+	})
+
+	require.Len(t, files, 2)
+	assert.Equal(t, "analyses/top_customers.sql", files[0].Name)
+	assert.Equal(t, "-- Who spends most\n-- last year\nselect user_id, sum(total) from orders group by 1\n", string(files[0].Data))
+	assert.Equal(t, "analyses/top_customers_2.sql", files[1].Name)
+	assert.Equal(t, "select 1\n", string(files[1].Data))
+}
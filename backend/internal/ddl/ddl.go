@@ -0,0 +1,146 @@
+// Package ddl writes the CREATE TABLE statements of a schema in the SQL
+// dialect of a project's database type. Data types and default values are
+// written as the project declares them. Foreign keys are added once every
+// table exists, so tables may refer to each other in any order, except with
+// SQLite, which only declares them within CREATE TABLE. It also plans the
+// PostgreSQL statements migrating a live database to a model.
+package ddl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+)
+
+// Database types of projects, naming their dialect
+const (
+	PostgreSQL = "postgresql"
+	MySQL      = "mysql"
+	SQLite     = "sqlite"
+	SQLServer  = "sqlserver"
+)
+
+// maxIdentifierLength is the longest constraint name every dialect accepts,
+// that of PostgreSQL
+const maxIdentifierLength = 63
+
+// ErrUnsupportedDialect is returned for a database type without a dialect
+var ErrUnsupportedDialect = errors.New("unsupported database type")
+
+type dialect struct {
+	open, close string // Quotes around identifiers
+	// Foreign keys are declared by CREATE TABLE rather than added after
+	inlineForeignKeys bool
+}
+
+var dialects = map[string]dialect{
+	PostgreSQL: {open: `"`, close: `"`},
+	MySQL:      {open: "`", close: "`"},
+	SQLite:     {open: `"`, close: `"`, inlineForeignKeys: true},
+	SQLServer:  {open: "[", close: "]"},
+}
+
+// Generate writes the statements creating the tables of schema, in the
+// dialect of databaseType, PostgreSQL when empty. Tables without fields are
+// left out with a comment, as most dialects reject them.
+func Generate(schema schemadiff.Schema, databaseType string) ([]byte, error) {
+	if databaseType == "" {
+		databaseType = PostgreSQL
+	}
+	d, ok := dialects[databaseType]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedDialect, databaseType)
+	}
+
+	foreignKeys := make(map[string][]schemadiff.ForeignKey)
+	for _, foreignKey := range schema.ForeignKeys() {
+		foreignKeys[foreignKey.Table] = append(foreignKeys[foreignKey.Table], foreignKey)
+	}
+
+	var buf bytes.Buffer
+	var later []string // ALTER TABLE statements adding foreign keys
+	for i, table := range schema.Tables {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		if len(table.Fields) == 0 {
+			fmt.Fprintf(&buf, "-- %s has no fields\n", table.Name)
+			continue
+		}
+
+		var inline []string
+		for _, foreignKey := range foreignKeys[table.Name] {
+			if foreignKey.Unique {
+				inline = append(inline, d.unique(foreignKey))
+			}
+			if d.inlineForeignKeys {
+				inline = append(inline, d.foreignKey(foreignKey))
+			} else {
+				later = append(later, d.addForeignKey(foreignKey))
+			}
+		}
+		buf.WriteString(d.createTable(table, inline))
+	}
+
+	if len(later) > 0 {
+		buf.WriteString("\n" + strings.Join(later, "\n") + "\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// createTable writes the CREATE TABLE statement of a table with fields,
+// followed by the constraints given
+func (d dialect) createTable(table schemadiff.Table, constraints []string) string {
+	var lines, keys []string
+	for _, field := range table.Fields {
+		lines = append(lines, d.column(field))
+		if field.IsPrimaryKey {
+			keys = append(keys, d.quote(field.Name))
+		}
+	}
+	if len(keys) > 0 {
+		lines = append(lines, "PRIMARY KEY ("+strings.Join(keys, ", ")+")")
+	}
+	lines = append(lines, constraints...)
+	return fmt.Sprintf("CREATE TABLE %s (\n  %s\n);\n", d.quote(table.Name), strings.Join(lines, ",\n  "))
+}
+
+// column declares a column of a table
+func (d dialect) column(field schemadiff.Field) string {
+	line := d.quote(field.Name) + " " + field.DataType
+	if !field.IsNullable || field.IsPrimaryKey {
+		line += " NOT NULL"
+	}
+	if field.DefaultValue != "" {
+		line += " DEFAULT " + field.DefaultValue
+	}
+	return line
+}
+
+// unique declares the unique constraint of a one-to-one foreign key
+func (d dialect) unique(foreignKey schemadiff.ForeignKey) string {
+	return "UNIQUE (" + d.quote(foreignKey.Field) + ")"
+}
+
+// addForeignKey adds a foreign key constraint to an existing table
+func (d dialect) addForeignKey(foreignKey schemadiff.ForeignKey) string {
+	return "ALTER TABLE " + d.quote(foreignKey.Table) + " ADD " + d.foreignKey(foreignKey) + ";"
+}
+
+// foreignKey declares a foreign key constraint, named after its column
+func (d dialect) foreignKey(foreignKey schemadiff.ForeignKey) string {
+	name := "fk_" + foreignKey.Table + "_" + foreignKey.Field
+	if len(name) > maxIdentifierLength {
+		name = name[:maxIdentifierLength]
+	}
+	return fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		d.quote(name), d.quote(foreignKey.Field), d.quote(foreignKey.RefTable), d.quote(foreignKey.RefField))
+}
+
+// quote quotes an identifier, doubling the closing quote within it
+func (d dialect) quote(name string) string {
+	return d.open + strings.ReplaceAll(name, d.close, d.close+d.close) + d.close
+}
@@ -0,0 +1,69 @@
+package ddl
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var schema = schemadiff.Schema{
+	Tables: []schemadiff.Table{
+		{Name: "orders", Fields: []schemadiff.Field{
+			{Name: "id", DataType: "SERIAL", IsPrimaryKey: true},
+			{Name: "user_id", DataType: "INTEGER"},
+			{Name: "note", DataType: "TEXT", IsNullable: true},
+			{Name: "created_at", DataType: "TIMESTAMP", DefaultValue: "CURRENT_TIMESTAMP"},
+		}},
+		{Name: "users", Fields: []schemadiff.Field{{Name: "id", DataType: "SERIAL", IsPrimaryKey: true}}},
+		{Name: "empty"},
+	},
+	Relationships: []schemadiff.Relationship{
+		{SourceTable: "users", SourceField: "id", TargetTable: "orders", TargetField: "user_id", RelationType: "one_to_one"},
+	},
+}
+
+func TestGeneratePostgres(t *testing.T) {
+	sql, err := Generate(schema, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, `CREATE TABLE "orders" (
+  "id" SERIAL NOT NULL,
+  "user_id" INTEGER NOT NULL,
+  "note" TEXT,
+  "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id"),
+  UNIQUE ("user_id")
+);
+
+CREATE TABLE "users" (
+  "id" SERIAL NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+-- empty has no fields
+
+ALTER TABLE "orders" ADD CONSTRAINT "fk_orders_user_id" FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+`, string(sql))
+}
+
+func TestGenerateDialects(t *testing.T) {
+	sql, err := Generate(schema, SQLite)
+	require.NoError(t, err)
+	assert.Contains(t, string(sql), `  UNIQUE ("user_id"),
+  CONSTRAINT "fk_orders_user_id" FOREIGN KEY ("user_id") REFERENCES "users" ("id")
+);`)
+	assert.NotContains(t, string(sql), "ALTER TABLE")
+
+	sql, err = Generate(schema, MySQL)
+	require.NoError(t, err)
+	assert.Contains(t, string(sql), "ALTER TABLE `orders` ADD CONSTRAINT `fk_orders_user_id` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);")
+
+	sql, err = Generate(schemadiff.Schema{Tables: []schemadiff.Table{{Name: "odd]name", Fields: []schemadiff.Field{{Name: "id", DataType: "INT"}}}}}, SQLServer)
+	require.NoError(t, err)
+	assert.Contains(t, string(sql), "CREATE TABLE [odd]]name] (")
+
+	_, err = Generate(schema, "oracle")
+	assert.ErrorIs(t, err, ErrUnsupportedDialect)
+}
@@ -0,0 +1,159 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+)
+
+// Statement is a statement of a migration
+type Statement struct {
+	SQL         string
+	Destructive bool // Drops a table or column, or converts a column, so data may be lost
+}
+
+// Migration is how to bring a PostgreSQL database to a model
+type Migration struct {
+	Statements  []Statement
+	Unsupported []string // Changes it cannot make, to be made by hand
+}
+
+// Migrate plans the PostgreSQL statements bringing a database to a model.
+// diff is what changes from the database to the model, both prepared for
+// comparing as the caller sees fit, e.g. with normalized data types; tables
+// and fields are written as model declares them. Foreign keys are dropped
+// first, by the constraint names the database's relationships carry, and
+// added last, so each statement can run after the previous ones. Primary keys
+// and relationship types are not changed. renames, made to the database's
+// schema before diff was taken, come first, so renamed tables and fields
+// keep their data.
+func Migrate(diff *schemadiff.Diff, model schemadiff.Schema, renames []schemadiff.Rename) *Migration {
+	d := dialects[PostgreSQL]
+	tables := make(map[string]schemadiff.Table, len(model.Tables))
+	fields := make(map[[2]string]schemadiff.Field)
+	for _, table := range model.Tables {
+		tables[table.Name] = table
+		for _, field := range table.Fields {
+			fields[[2]string{table.Name, field.Name}] = field
+		}
+	}
+	foreignKeys := make(map[[4]string]schemadiff.ForeignKey)
+	for _, foreignKey := range model.ForeignKeys() {
+		foreignKeys[[4]string{foreignKey.RefTable, foreignKey.RefField, foreignKey.Table, foreignKey.Field}] = foreignKey
+	}
+
+	migration := &Migration{}
+	add := func(sql string, destructive bool) {
+		migration.Statements = append(migration.Statements, Statement{SQL: sql, Destructive: destructive})
+	}
+	unsupported := func(format string, args ...any) {
+		migration.Unsupported = append(migration.Unsupported, fmt.Sprintf(format, args...))
+	}
+
+	for _, rename := range renames {
+		if rename.Field == "" {
+			add("ALTER TABLE "+d.quote(rename.Table)+" RENAME TO "+d.quote(rename.To)+";", false)
+		} else {
+			add("ALTER TABLE "+d.quote(rename.Table)+" RENAME COLUMN "+d.quote(rename.Field)+" TO "+d.quote(rename.To)+";", false)
+		}
+	}
+
+	for _, relationship := range diff.RemovedRelationships {
+		if relationship.RelationType == "many_to_many" {
+			continue
+		}
+		if relationship.Constraint == "" {
+			unsupported("%s: foreign key to be dropped has no constraint name", relationshipName(relationship))
+			continue
+		}
+		add("ALTER TABLE "+d.quote(relationship.TargetTable)+" DROP CONSTRAINT "+d.quote(relationship.Constraint)+";", false)
+	}
+	for _, change := range diff.ChangedRelationships {
+		for _, c := range change.Changes {
+			unsupported("%s: relationship changes from %s to %s", relationshipName(change.Relationship), c.From, c.To)
+		}
+	}
+
+	for _, table := range diff.RemovedTables {
+		add("DROP TABLE "+d.quote(table.Name)+";", true)
+	}
+
+	for _, table := range diff.AddedTables {
+		if declared, ok := tables[table.Name]; ok {
+			table = declared
+		}
+		if len(table.Fields) == 0 {
+			add("CREATE TABLE "+d.quote(table.Name)+" ();", false)
+			continue
+		}
+		add(strings.TrimSuffix(d.createTable(table, nil), "\n"), false)
+	}
+
+	for _, table := range diff.ChangedTables {
+		alter := "ALTER TABLE " + d.quote(table.Name) + " "
+		for _, field := range table.AddedFields {
+			if declared, ok := fields[[2]string{table.Name, field.Name}]; ok {
+				field = declared
+			}
+			add(alter+"ADD COLUMN "+d.column(field)+";", false)
+			if field.IsPrimaryKey {
+				unsupported("%s.%s: added to the primary key", table.Name, field.Name)
+			}
+		}
+		for _, field := range table.ChangedFields {
+			declared := fields[[2]string{table.Name, field.Name}]
+			column := alter + "ALTER COLUMN " + d.quote(field.Name) + " "
+			for _, c := range field.Changes {
+				switch c.Attribute {
+				case schemadiff.AttributeDataType:
+					add(column+"TYPE "+declared.DataType+" USING "+d.quote(field.Name)+"::"+declared.DataType+";", true)
+				case schemadiff.AttributeNullable:
+					if declared.IsNullable {
+						add(column+"DROP NOT NULL;", false)
+					} else {
+						add(column+"SET NOT NULL;", false)
+					}
+				case schemadiff.AttributeDefaultValue:
+					if declared.DefaultValue == "" {
+						add(column+"DROP DEFAULT;", false)
+					} else {
+						add(column+"SET DEFAULT "+declared.DefaultValue+";", false)
+					}
+				case schemadiff.AttributePrimaryKey:
+					unsupported("%s.%s: primary key changes from %s to %s", table.Name, field.Name, c.From, c.To)
+				}
+			}
+		}
+		for _, field := range table.RemovedFields {
+			add(alter+"DROP COLUMN "+d.quote(field.Name)+";", true)
+		}
+	}
+
+	for _, relationship := range diff.AddedRelationships {
+		foreignKey, ok := foreignKeys[[4]string{relationship.SourceTable, relationship.SourceField, relationship.TargetTable, relationship.TargetField}]
+		if !ok {
+			continue // Many to many, or linking a column the model lacks
+		}
+		if foreignKey.Unique {
+			add("ALTER TABLE "+d.quote(foreignKey.Table)+" ADD "+d.unique(foreignKey)+";", false)
+		}
+		add(d.addForeignKey(foreignKey), false)
+	}
+	return migration
+}
+
+// Destructive reports whether a statement of the migration may lose data
+func (m *Migration) Destructive() bool {
+	for _, statement := range m.Statements {
+		if statement.Destructive {
+			return true
+		}
+	}
+	return false
+}
+
+// relationshipName names a relationship by the columns it links, the foreign key last
+func relationshipName(relationship schemadiff.Relationship) string {
+	return relationship.SourceTable + "." + relationship.SourceField + " to " + relationship.TargetTable + "." + relationship.TargetField
+}
@@ -0,0 +1,123 @@
+package ddl
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrate(t *testing.T) {
+	live := schemadiff.Schema{
+		Tables: []schemadiff.Table{
+			{Name: "orders", Fields: []schemadiff.Field{
+				{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+				{Name: "user_id", DataType: "INTEGER"},
+				{Name: "legacy", DataType: "TEXT", IsNullable: true},
+				{Name: "total", DataType: "INTEGER"},
+			}},
+			{Name: "users", Fields: []schemadiff.Field{{Name: "id", DataType: "INTEGER", IsPrimaryKey: true}}},
+			{Name: "audit", Fields: []schemadiff.Field{{Name: "id", DataType: "INTEGER"}}},
+		},
+		Relationships: []schemadiff.Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "orders", TargetField: "user_id", RelationType: "one_to_many", Constraint: "orders_user_id_fkey"},
+		},
+	}
+	model := schemadiff.Schema{
+		Tables: []schemadiff.Table{
+			{Name: "orders", Fields: []schemadiff.Field{
+				{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+				{Name: "user_id", DataType: "INTEGER", IsNullable: true},
+				{Name: "total", DataType: "numeric(10,2)"},
+				{Name: "note", DataType: "TEXT", IsNullable: true, DefaultValue: "''"},
+			}},
+			{Name: "users", Fields: []schemadiff.Field{{Name: "id", DataType: "INTEGER", IsPrimaryKey: true}}},
+			{Name: "profiles", Fields: []schemadiff.Field{
+				{Name: "user_id", DataType: "INTEGER", IsPrimaryKey: true},
+			}},
+		},
+		Relationships: []schemadiff.Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "profiles", TargetField: "user_id", RelationType: "one_to_one"},
+		},
+	}
+
+	migration := Migrate(schemadiff.Compare(live, model), model, nil)
+
+	assert.Equal(t, []Statement{
+		{SQL: `ALTER TABLE "orders" DROP CONSTRAINT "orders_user_id_fkey";`},
+		{SQL: `DROP TABLE "audit";`, Destructive: true},
+		{SQL: "CREATE TABLE \"profiles\" (\n  \"user_id\" INTEGER NOT NULL,\n  PRIMARY KEY (\"user_id\")\n);"},
+		{SQL: `ALTER TABLE "orders" ADD COLUMN "note" TEXT DEFAULT '';`},
+		{SQL: `ALTER TABLE "orders" ALTER COLUMN "total" TYPE numeric(10,2) USING "total"::numeric(10,2);`, Destructive: true},
+		{SQL: `ALTER TABLE "orders" ALTER COLUMN "user_id" DROP NOT NULL;`},
+		{SQL: `ALTER TABLE "orders" DROP COLUMN "legacy";`, Destructive: true},
+		{SQL: `ALTER TABLE "profiles" ADD UNIQUE ("user_id");`},
+		{SQL: `ALTER TABLE "profiles" ADD CONSTRAINT "fk_profiles_user_id" FOREIGN KEY ("user_id") REFERENCES "users" ("id");`},
+	}, migration.Statements)
+	assert.Empty(t, migration.Unsupported)
+	assert.True(t, migration.Destructive())
+}
+
+func TestMigrateUnsupported(t *testing.T) {
+	live := schemadiff.Schema{
+		Tables: []schemadiff.Table{
+			{Name: "users", Fields: []schemadiff.Field{{Name: "id", DataType: "INTEGER", IsPrimaryKey: true}, {Name: "email", DataType: "TEXT"}}},
+			{Name: "profiles", Fields: []schemadiff.Field{{Name: "user_id", DataType: "INTEGER"}}},
+		},
+		Relationships: []schemadiff.Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "profiles", TargetField: "user_id", RelationType: "one_to_many"},
+		},
+	}
+	model := schemadiff.Schema{
+		Tables: []schemadiff.Table{
+			{Name: "users", Fields: []schemadiff.Field{{Name: "id", DataType: "INTEGER", IsPrimaryKey: true}, {Name: "email", DataType: "TEXT", IsPrimaryKey: true}}},
+			{Name: "profiles", Fields: []schemadiff.Field{{Name: "user_id", DataType: "INTEGER"}}},
+		},
+		Relationships: []schemadiff.Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "profiles", TargetField: "user_id", RelationType: "one_to_one"},
+		},
+	}
+
+	migration := Migrate(schemadiff.Compare(live, model), model, nil)
+
+	assert.Empty(t, migration.Statements)
+	assert.Equal(t, []string{
+		"users.id to profiles.user_id: relationship changes from one_to_many to one_to_one",
+		"users.email: primary key changes from false to true",
+	}, migration.Unsupported)
+	assert.False(t, migration.Destructive())
+}
+
+func TestMigrateRenames(t *testing.T) {
+	live := schemadiff.Schema{
+		Tables: []schemadiff.Table{
+			{Name: "users", Fields: []schemadiff.Field{{Name: "id", DataType: "INTEGER", IsPrimaryKey: true}}},
+			{Name: "posts", Fields: []schemadiff.Field{{Name: "user_id", DataType: "INTEGER"}}},
+		},
+		Relationships: []schemadiff.Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "user_id", RelationType: "one_to_many", Constraint: "posts_user_id_fkey"},
+		},
+	}
+	model := schemadiff.Schema{
+		Tables: []schemadiff.Table{
+			{Name: "accounts", Fields: []schemadiff.Field{{Name: "id", DataType: "INTEGER", IsPrimaryKey: true}}},
+			{Name: "posts", Fields: []schemadiff.Field{{Name: "account_id", DataType: "INTEGER"}}},
+		},
+		Relationships: []schemadiff.Relationship{
+			{SourceTable: "accounts", SourceField: "id", TargetTable: "posts", TargetField: "account_id", RelationType: "one_to_many"},
+		},
+	}
+
+	renamed, renames := live.Rename([]schemadiff.Rename{
+		{Table: "users", To: "accounts"},
+		{Table: "posts", Field: "user_id", To: "account_id"},
+		{Table: "comments", To: "replies"}, // Not in the database
+	})
+	migration := Migrate(schemadiff.Compare(renamed, model), model, renames)
+
+	assert.Equal(t, []Statement{
+		{SQL: `ALTER TABLE "users" RENAME TO "accounts";`},
+		{SQL: `ALTER TABLE "posts" RENAME COLUMN "user_id" TO "account_id";`},
+	}, migration.Statements)
+	assert.False(t, migration.Destructive())
+}
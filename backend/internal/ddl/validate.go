@@ -0,0 +1,193 @@
+package ddl
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+)
+
+// Data types and default values are written into statements as declared, so
+// they are held to a grammar admitting nothing but a type or a literal
+
+// ErrInvalidDataType is returned for a data type outside the grammar
+var ErrInvalidDataType = errors.New("invalid data type")
+
+// ErrInvalidDefaultValue is returned for a default value that is neither a
+// literal nor a call of an allowed function
+var ErrInvalidDefaultValue = errors.New("invalid default value")
+
+// maxDataTypeLength bounds a data type, the longest standard ones being
+// around "TIMESTAMP(6) WITHOUT TIME ZONE"
+const maxDataTypeLength = 64
+
+// maxDefaultValueLength bounds a default value
+const maxDefaultValueLength = 255
+
+var (
+	typeToken       = regexp.MustCompile(`^\s*(?:([A-Za-z_][A-Za-z0-9_]*)|(\(\s*(?:\d+|(?i:max))\s*(?:,\s*\d+\s*)?\))|(\[\d*\]))`)
+	numberLiteral   = regexp.MustCompile(`^[+-]?(?:\d+(?:\.\d*)?|\.\d+)(?:[eE][+-]?\d+)?$`)
+	stringLiteral   = regexp.MustCompile(`^'(?:[^'\\]|'')*'$`)
+	functionCall    = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*\((.*)\)$`)
+	castSuffix      = regexp.MustCompile(`::\s*([^:']+)$`)
+	precisionSuffix = regexp.MustCompile(`^\(\s*\d+\s*\)$`)
+)
+
+// typeModifiers are the words that may follow the first word of a data type,
+// as in DOUBLE PRECISION, CHARACTER VARYING or TIMESTAMP WITH TIME ZONE
+var typeModifiers = map[string]bool{
+	"PRECISION": true, "VARYING": true, "WITH": true, "WITHOUT": true, "TIME": true, "ZONE": true,
+	"LOCAL": true, "UNSIGNED": true, "SIGNED": true, "ZEROFILL": true, "CHARACTER": true, "TO": true,
+	"YEAR": true, "MONTH": true, "DAY": true, "HOUR": true, "MINUTE": true, "SECOND": true,
+}
+
+// defaultKeywords are the defaults written as a bare keyword
+var defaultKeywords = map[string]bool{
+	"NULL": true, "TRUE": true, "FALSE": true, "CURRENT_TIMESTAMP": true, "CURRENT_DATE": true,
+	"CURRENT_TIME": true, "LOCALTIMESTAMP": true, "LOCALTIME": true, "CURRENT_USER": true,
+}
+
+// defaultFunctions are the functions a default may call, with literal arguments
+var defaultFunctions = map[string]bool{
+	"now": true, "gen_random_uuid": true, "uuid_generate_v1": true, "uuid_generate_v4": true,
+	"uuid": true, "nextval": true, "current_timestamp": true, "localtimestamp": true,
+	"clock_timestamp": true, "statement_timestamp": true, "transaction_timestamp": true,
+	"getdate": true, "getutcdate": true, "sysdatetime": true, "sysutcdatetime": true,
+	"newid": true, "newsequentialid": true, "utc_timestamp": true, "unix_timestamp": true,
+	"datetime": true, "date": true, "time": true, "strftime": true,
+}
+
+// ValidateDataType fails with ErrInvalidDataType unless dataType is a type
+// name, optionally followed by modifier words, a length or precision in
+// parentheses and array brackets, such as VARCHAR(255), NUMERIC(10, 2),
+// DOUBLE PRECISION, TIMESTAMP(3) WITH TIME ZONE or INT[]
+func ValidateDataType(dataType string) error {
+	if len(dataType) > maxDataTypeLength || strings.TrimSpace(dataType) == "" {
+		return fmt.Errorf("%w %q", ErrInvalidDataType, dataType)
+	}
+
+	rest := dataType
+	words, arrays := 0, false
+	for strings.TrimSpace(rest) != "" {
+		match := typeToken.FindStringSubmatch(rest)
+		if match == nil {
+			return fmt.Errorf("%w %q", ErrInvalidDataType, dataType)
+		}
+		switch {
+		case match[1] != "":
+			if arrays || (words > 0 && !typeModifiers[strings.ToUpper(match[1])]) {
+				return fmt.Errorf("%w %q", ErrInvalidDataType, dataType)
+			}
+			words++
+		case match[2] != "":
+			if words == 0 || arrays {
+				return fmt.Errorf("%w %q", ErrInvalidDataType, dataType)
+			}
+		case match[3] != "":
+			if words == 0 {
+				return fmt.Errorf("%w %q", ErrInvalidDataType, dataType)
+			}
+			arrays = true
+		}
+		rest = rest[len(match[0]):]
+	}
+	return nil
+}
+
+// ValidateDefaultValue fails with ErrInvalidDefaultValue unless value is
+// empty, a number, a string, a keyword such as NULL or CURRENT_TIMESTAMP, or
+// a call of an allowed function such as now() or nextval('seq'), each
+// optionally cast to a valid type and wrapped in parentheses as SQL Server
+// reports defaults. Strings may not hold backslashes, which MySQL takes for
+// escapes.
+func ValidateDefaultValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > maxDefaultValueLength || !validDefault(strings.TrimSpace(value), true) {
+		return fmt.Errorf("%w %q", ErrInvalidDefaultValue, value)
+	}
+	return nil
+}
+
+// validDefault reports whether expr is a default value, calls allowed when
+// call is set
+func validDefault(expr string, call bool) bool {
+	for len(expr) > 1 && expr[0] == '(' && expr[len(expr)-1] == ')' {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	// A cast to a valid type, as in 'x'::text; nextval('s'::regclass) ends in none
+	if match := castSuffix.FindStringSubmatchIndex(expr); match != nil && ValidateDataType(strings.TrimSpace(expr[match[2]:match[3]])) == nil {
+		expr = strings.TrimSpace(expr[:match[0]])
+	}
+
+	switch {
+	case numberLiteral.MatchString(expr), stringLiteral.MatchString(expr):
+		return true
+	case defaultKeywords[strings.ToUpper(expr)]:
+		return true
+	}
+
+	match := functionCall.FindStringSubmatch(expr)
+	if match == nil || !call {
+		return false
+	}
+	name := strings.ToLower(match[1])
+	if defaultKeywords[strings.ToUpper(name)] && precisionSuffix.MatchString(expr[len(match[1]):]) {
+		return true // CURRENT_TIMESTAMP(3)
+	}
+	if !defaultFunctions[name] {
+		return false
+	}
+	args := splitArguments(match[2])
+	if args == nil {
+		return false
+	}
+	for _, arg := range args {
+		if !validDefault(arg, false) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitArguments splits the arguments of a call at commas outside strings,
+// returning nil when a string is left open. No arguments give an empty slice.
+func splitArguments(list string) []string {
+	args := []string{}
+	if strings.TrimSpace(list) == "" {
+		return args
+	}
+	inString, start := false, 0
+	for i := 0; i < len(list); i++ {
+		switch {
+		case list[i] == '\'':
+			inString = !inString
+		case list[i] == ',' && !inString:
+			args = append(args, strings.TrimSpace(list[start:i]))
+			start = i + 1
+		}
+	}
+	if inString {
+		return nil
+	}
+	return append(args, strings.TrimSpace(list[start:]))
+}
+
+// ValidateSchema checks the data type and default value of every field of
+// schema, so fields stored before either was validated cannot reach a database
+func ValidateSchema(schema schemadiff.Schema) error {
+	for _, table := range schema.Tables {
+		for _, field := range table.Fields {
+			if err := ValidateDataType(field.DataType); err != nil {
+				return fmt.Errorf("%s.%s: %w", table.Name, field.Name, err)
+			}
+			if err := ValidateDefaultValue(field.DefaultValue); err != nil {
+				return fmt.Errorf("%s.%s: %w", table.Name, field.Name, err)
+			}
+		}
+	}
+	return nil
+}
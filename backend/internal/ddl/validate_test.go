@@ -0,0 +1,54 @@
+package ddl
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDataType(t *testing.T) {
+	for _, dataType := range []string{
+		"INTEGER", "uuid", "VARCHAR(255)", "NUMERIC(10, 2)", "DOUBLE PRECISION", "character varying",
+		"TIMESTAMP(3) WITH TIME ZONE", "INT UNSIGNED", "INT[]", "text[][]", "NVARCHAR(MAX)", "INTERVAL DAY TO SECOND",
+	} {
+		assert.NoError(t, ValidateDataType(dataType), dataType)
+	}
+
+	for _, dataType := range []string{
+		"", "  ", "int; DROP TABLE users; --", "INT REFERENCES users", "INT CHECK (id > 0)",
+		"VARCHAR(255", "VARCHAR(a)", "INT[] NOT", "(10)", "ENUM('a', 'b')", "INT -- comment", "public.citext",
+		"VARCHAR(255) COLLATE \"C\"", "INT GENERATED ALWAYS AS IDENTITY",
+	} {
+		assert.ErrorIs(t, ValidateDataType(dataType), ErrInvalidDataType, dataType)
+	}
+}
+
+func TestValidateDefaultValue(t *testing.T) {
+	for _, value := range []string{
+		"", "0", "-1.5", "'active'", "'it''s'", "TRUE", "null", "CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP(3)",
+		"now()", "gen_random_uuid()", "nextval('posts_id_seq'::regclass)", "'{}'::jsonb",
+		"'active'::character varying", "((0))", "(getdate())", "datetime('now')",
+	} {
+		assert.NoError(t, ValidateDefaultValue(value), value)
+	}
+
+	for _, value := range []string{
+		"0; DROP TABLE users; --", "'a'; DROP TABLE users; --", "pg_sleep(10)", "now(); DROP TABLE users",
+		"'a\\'; DROP TABLE users; -- '", "'open", "nextval(now())", "1 + 1", "(SELECT 1)",
+		"now(1) ; DROP TABLE users; SELECT (1)", "'x'::int; DROP TABLE users",
+	} {
+		assert.ErrorIs(t, ValidateDefaultValue(value), ErrInvalidDefaultValue, value)
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	assert.NoError(t, ValidateSchema(schema))
+
+	invalid := schemadiff.Schema{Tables: []schemadiff.Table{{Name: "users", Fields: []schemadiff.Field{
+		{Name: "id", DataType: "int; DROP TABLE users; --"},
+	}}}}
+	err := ValidateSchema(invalid)
+	assert.ErrorIs(t, err, ErrInvalidDataType)
+	assert.ErrorContains(t, err, "users.id")
+}
@@ -0,0 +1,146 @@
+// Package errorreport sends panics and unexpected errors to a Sentry-compatible
+// error tracking service. Reports only ever identify users and projects by ID:
+// emails, usernames, IP addresses, cookies, auth headers and query strings are
+// stripped before anything leaves the process.
+package errorreport
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/getsentry/sentry-go"
+	"github.com/google/uuid"
+)
+
+// Reporter sends errors to an error tracking service
+type Reporter interface {
+	Capture(scope *Scope, err error)
+	CapturePanic(scope *Scope, value any)
+	Flush(timeout time.Duration) bool
+}
+
+var (
+	reporterMu sync.RWMutex
+	reporter   Reporter = noopReporter{}
+)
+
+// Init starts reporting to the service of cfg.ErrorReporting.DSN. Reporting
+// stays disabled when no DSN is set.
+func Init(cfg *config.Config) error {
+	if cfg.ErrorReporting.DSN == "" {
+		return nil
+	}
+
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:              cfg.ErrorReporting.DSN,
+		Environment:      cfg.ErrorReporting.Environment,
+		Release:          cfg.ErrorReporting.Release,
+		SampleRate:       cfg.ErrorReporting.SampleRate,
+		AttachStacktrace: true,
+		SendDefaultPII:   false,
+		BeforeSend: func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+			return scrubEvent(event)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create error reporter: %w", err)
+	}
+
+	SetReporter(&sentryReporter{client: client})
+	return nil
+}
+
+// SetReporter replaces the reporter errors are sent to, e.g. in tests. Nil
+// disables reporting.
+func SetReporter(r Reporter) {
+	if r == nil {
+		r = noopReporter{}
+	}
+	reporterMu.Lock()
+	defer reporterMu.Unlock()
+	reporter = r
+}
+
+func current() Reporter {
+	reporterMu.RLock()
+	defer reporterMu.RUnlock()
+	return reporter
+}
+
+// Capture reports an unexpected error, tagged with the scope of the request
+// in ctx and any further tags
+func Capture(ctx context.Context, err error, tags ...Tag) {
+	if err == nil {
+		return
+	}
+	current().Capture(scopeWith(ctx, tags), err)
+}
+
+// CapturePanic reports a recovered panic value with the scope of the request in ctx
+func CapturePanic(ctx context.Context, value any) {
+	current().CapturePanic(scopeWith(ctx, nil), value)
+}
+
+// Flush waits up to timeout for queued reports to be sent, e.g. before exiting
+func Flush(timeout time.Duration) bool {
+	return current().Flush(timeout)
+}
+
+type noopReporter struct{}
+
+func (noopReporter) Capture(*Scope, error)    {}
+func (noopReporter) CapturePanic(*Scope, any) {}
+func (noopReporter) Flush(time.Duration) bool { return true }
+
+type sentryReporter struct {
+	client *sentry.Client
+}
+
+func (r *sentryReporter) Capture(scope *Scope, err error) {
+	r.client.CaptureException(err, nil, r.sentryScope(scope))
+}
+
+func (r *sentryReporter) CapturePanic(scope *Scope, value any) {
+	r.client.Recover(value, nil, r.sentryScope(scope))
+}
+
+func (r *sentryReporter) Flush(timeout time.Duration) bool {
+	return r.client.Flush(timeout)
+}
+
+func (r *sentryReporter) sentryScope(scope *Scope) *sentry.Scope {
+	s := sentry.NewScope()
+	tags := scope.Tags()
+	if userID, ok := tags[TagUserID]; ok {
+		s.SetUser(sentry.User{ID: userID})
+	}
+	s.SetTags(tags)
+	return s
+}
+
+// Tag keys of the request scope
+const (
+	TagUserID    = "user_id"
+	TagProjectID = "project_id"
+	TagRoute     = "route" // Route pattern, e.g. /api/projects/{project_id}
+	TagMethod    = "method"
+)
+
+// Tag is a key and value attached to a report
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// UserTag identifies the user affected by an error by ID only
+func UserTag(userID uuid.UUID) Tag {
+	return Tag{Key: TagUserID, Value: userID.String()}
+}
+
+// ProjectTag identifies the project affected by an error by ID only
+func ProjectTag(projectID uuid.UUID) Tag {
+	return Tag{Key: TagProjectID, Value: projectID.String()}
+}
@@ -0,0 +1,70 @@
+package errorreport
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingReporter struct {
+	scopes []*Scope
+	errs   []error
+}
+
+func (r *recordingReporter) Capture(scope *Scope, err error) {
+	r.scopes = append(r.scopes, scope)
+	r.errs = append(r.errs, err)
+}
+func (r *recordingReporter) CapturePanic(scope *Scope, value any) {}
+func (r *recordingReporter) Flush(time.Duration) bool             { return true }
+
+func TestCapture_MergesScopeAndTags(t *testing.T) {
+	reporter := &recordingReporter{}
+	SetReporter(reporter)
+	defer SetReporter(nil)
+
+	userID, projectID := uuid.New(), uuid.New()
+	ctx, scope := NewContext(context.Background())
+	scope.SetTag(TagRoute, "/api/projects/{project_id}")
+	SetUser(ctx, userID.String())
+
+	Capture(ctx, errors.New("boom"), ProjectTag(projectID))
+	Capture(ctx, nil)
+
+	assert.Len(t, reporter.errs, 1)
+	assert.Equal(t, map[string]string{
+		TagRoute:     "/api/projects/{project_id}",
+		TagUserID:    userID.String(),
+		TagProjectID: projectID.String(),
+	}, reporter.scopes[0].Tags())
+	// The request scope itself is not changed by per-call tags
+	assert.NotContains(t, scope.Tags(), TagProjectID)
+}
+
+func TestScrubEvent(t *testing.T) {
+	event := &sentry.Event{
+		Message:   "duplicate key for alice@example.com",
+		User:      sentry.User{ID: "user-1", Email: "alice@example.com", IPAddress: "10.0.0.1", Username: "alice"},
+		Exception: []sentry.Exception{{Value: "no user bob@example.org"}},
+		Request: &sentry.Request{
+			QueryString: "signature=abc",
+			Cookies:     "access_token=secret",
+			Headers:     map[string]string{"Authorization": "Bearer x", "User-Agent": "curl"},
+		},
+		Tags: map[string]string{"route": "/api/users/me"},
+	}
+
+	event = scrubEvent(event)
+
+	assert.Equal(t, sentry.User{ID: "user-1"}, event.User)
+	assert.Equal(t, "duplicate key for [email]", event.Message)
+	assert.Equal(t, "no user [email]", event.Exception[0].Value)
+	assert.Empty(t, event.Request.QueryString)
+	assert.Empty(t, event.Request.Cookies)
+	assert.Equal(t, map[string]string{"User-Agent": "curl"}, event.Request.Headers)
+}
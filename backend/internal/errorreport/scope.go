@@ -0,0 +1,63 @@
+package errorreport
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// Scope holds the tags of a request. It lives in the request context and is
+// filled in as the request passes through middleware, e.g. with the user once
+// authenticated.
+type Scope struct {
+	mu   sync.Mutex
+	tags map[string]string
+}
+
+type scopeKey struct{}
+
+// NewContext returns a context carrying a new, empty scope
+func NewContext(ctx context.Context) (context.Context, *Scope) {
+	scope := &Scope{tags: make(map[string]string)}
+	return context.WithValue(ctx, scopeKey{}, scope), scope
+}
+
+// FromContext returns the scope of the request in ctx, or nil
+func FromContext(ctx context.Context) *Scope {
+	scope, _ := ctx.Value(scopeKey{}).(*Scope)
+	return scope
+}
+
+// SetUser records the ID of the authenticated user in the scope of ctx
+func SetUser(ctx context.Context, userID string) {
+	FromContext(ctx).SetTag(TagUserID, userID)
+}
+
+// SetTag sets a tag. Values must not hold personal data such as emails.
+func (s *Scope) SetTag(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tags[key] = value
+}
+
+// Tags returns a copy of the tags
+func (s *Scope) Tags() map[string]string {
+	if s == nil {
+		return map[string]string{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.tags)
+}
+
+// scopeWith copies the scope in ctx and adds tags to the copy
+func scopeWith(ctx context.Context, tags []Tag) *Scope {
+	scope := &Scope{tags: FromContext(ctx).Tags()}
+	for _, tag := range tags {
+		scope.tags[tag.Key] = tag.Value
+	}
+	return scope
+}
@@ -0,0 +1,48 @@
+package errorreport
+
+import (
+	"regexp"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// emailPattern finds email addresses, e.g. in messages of database errors
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// safeHeaders are the request headers kept in reports; the rest may carry
+// credentials or identify the client
+var safeHeaders = []string{"Accept", "Content-Type", "User-Agent"}
+
+// scrubEvent strips personal data and credentials from an event before it is sent
+func scrubEvent(event *sentry.Event) *sentry.Event {
+	event.User = sentry.User{ID: event.User.ID}
+	event.Message = redact(event.Message)
+	for i := range event.Exception {
+		event.Exception[i].Value = redact(event.Exception[i].Value)
+	}
+	for _, breadcrumb := range event.Breadcrumbs {
+		breadcrumb.Message = redact(breadcrumb.Message)
+		breadcrumb.Data = nil
+	}
+	if event.Request != nil {
+		headers := make(map[string]string, len(safeHeaders))
+		for _, name := range safeHeaders {
+			if value, ok := event.Request.Headers[name]; ok {
+				headers[name] = value
+			}
+		}
+		event.Request.Headers = headers
+		event.Request.Cookies = ""
+		event.Request.QueryString = ""
+		event.Request.Data = ""
+		event.Request.Env = nil
+	}
+	for key, value := range event.Tags {
+		event.Tags[key] = redact(value)
+	}
+	return event
+}
+
+func redact(s string) string {
+	return emailPattern.ReplaceAllString(s, "[email]")
+}
@@ -0,0 +1,99 @@
+package eventschema
+
+import (
+	"encoding/json"
+
+	"github.com/Bug-Bugger/ezmodel/internal/archive"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+)
+
+type avroRecord struct {
+	Type      string      `json:"type"`
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace"`
+	Doc       string      `json:"doc"`
+	Fields    []avroField `json:"fields"`
+}
+
+type avroField struct {
+	Name     string `json:"name"`
+	Type     any    `json:"type"`
+	Doc      string `json:"doc,omitempty"`
+	optional bool
+}
+
+// MarshalJSON gives optional fields their null default
+func (f avroField) MarshalJSON() ([]byte, error) {
+	type plain avroField
+	if !f.optional {
+		return json.Marshal(plain(f))
+	}
+	return json.Marshal(struct {
+		plain
+		Default *struct{} `json:"default"`
+	}{plain: plain(f)})
+}
+
+// Avro writes a record schema per table, in a .avsc file named after the
+// record, all in the namespace of the project. Optional fields are unions
+// with null, null by default. Foreign keys say what they refer to in their doc.
+func Avro(projectName string, schema schemadiff.Schema) ([]archive.File, error) {
+	namespace := PackageName(projectName)
+	references := referencesOf(schema)
+
+	var files []archive.File
+	for _, table := range schema.Tables {
+		record := avroRecord{Type: "record", Name: typeName(table.Name), Namespace: namespace, Doc: "A row of the " + table.Name + " table", Fields: []avroField{}}
+		for _, field := range table.Fields {
+			f := avroField{Name: fieldName(field.Name), Type: avroType(parseColumn(field.DataType)), Doc: references[[2]string{table.Name, field.Name}]}
+			if field.IsNullable && !field.IsPrimaryKey {
+				f.Type = []any{"null", f.Type}
+				f.optional = true
+			}
+			record.Fields = append(record.Fields, f)
+		}
+		data, err := json.MarshalIndent(record, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, archive.File{Name: namespace + "/" + record.Name + ".avsc", Data: append(data, '\n')})
+	}
+	return files, nil
+}
+
+// avroType maps a data type to an Avro type, with a logical type where one fits
+func avroType(c column) any {
+	switch c.kind {
+	case "UUID":
+		return map[string]any{"type": "string", "logicalType": "uuid"}
+	case "INTEGER":
+		if c.wide {
+			return "long"
+		}
+		return "int"
+	case "FLOAT":
+		if c.wide {
+			return "double"
+		}
+		return "float"
+	case "BOOLEAN":
+		return "boolean"
+	case "TIMESTAMP":
+		return map[string]any{"type": "long", "logicalType": "timestamp-micros"}
+	case "DATE":
+		return map[string]any{"type": "int", "logicalType": "date"}
+	case "DECIMAL":
+		return map[string]any{"type": "bytes", "logicalType": "decimal", "precision": c.precision, "scale": c.scale}
+	default: // Strings, text, JSON and types without a counterpart
+		return "string"
+	}
+}
+
+// referencesOf describes the foreign keys of a schema, by table and field
+func referencesOf(schema schemadiff.Schema) map[[2]string]string {
+	references := make(map[[2]string]string)
+	for _, foreignKey := range schema.ForeignKeys() {
+		references[[2]string{foreignKey.Table, foreignKey.Field}] = "References " + foreignKey.RefTable + "." + foreignKey.RefField
+	}
+	return references
+}
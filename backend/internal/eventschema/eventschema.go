@@ -0,0 +1,92 @@
+// Package eventschema writes the tables of a schema as Avro record schemas
+// and Protobuf messages, for event streams whose contract is the model. Data
+// types are mapped by kind, as introspect normalizes them, so VARCHAR(255)
+// and TEXT are both strings. Nullable fields that are not part of the
+// primary key are optional. Names are made valid identifiers: records and
+// messages in PascalCase, fields with anything but letters, digits and
+// underscores replaced.
+package eventschema
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/introspect"
+)
+
+// Decimals declared without a precision are given the widest Avro accepts
+// from most producers, keeping cents and finer fractions
+const (
+	defaultPrecision = 38
+	defaultScale     = 10
+)
+
+// column is a data type as both formats map it
+type column struct {
+	kind      string // Data type of the modeler, e.g. STRING
+	wide      bool   // 64-bit integer or double precision float
+	precision int    // Of decimals
+	scale     int
+}
+
+// narrowTypes are the integer and float types that fit 32 bits
+var narrowTypes = map[string]bool{
+	"smallint": true, "int": true, "integer": true, "int2": true, "int4": true, "serial": true, "smallserial": true,
+	"real": true, "float4": true,
+}
+
+// parseColumn classifies a data type of the model
+func parseColumn(dataType string) column {
+	c := column{kind: introspect.NormalizeType(dataType), precision: defaultPrecision, scale: defaultScale}
+	base, args, hasArgs := strings.Cut(strings.ToLower(strings.TrimSpace(dataType)), "(")
+	c.wide = !narrowTypes[strings.TrimSpace(base)]
+	if c.kind == "DECIMAL" && hasArgs {
+		args, _, _ = strings.Cut(args, ")")
+		precision, scale, _ := strings.Cut(args, ",")
+		if p, err := strconv.Atoi(strings.TrimSpace(precision)); err == nil && p > 0 {
+			c.precision = p
+			c.scale, _ = strconv.Atoi(strings.TrimSpace(scale))
+		}
+	}
+	return c
+}
+
+var nonWord = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// fieldName makes a field name a valid identifier, e.g. "Placed At" placed_at
+func fieldName(name string) string {
+	name = strings.Trim(nonWord.ReplaceAllString(name, "_"), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return strings.ToLower(name)
+}
+
+// typeName makes a table name a record or message name, e.g. order_items OrderItems
+func typeName(name string) string {
+	var b strings.Builder
+	for _, word := range nonWord.Split(name, -1) {
+		if word == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	typeName := b.String()
+	if typeName == "" || (typeName[0] >= '0' && typeName[0] <= '9') {
+		typeName = "T" + typeName
+	}
+	return typeName
+}
+
+// PackageName turns a project name into a namespace or package, e.g. "Shop DB" shop_db
+func PackageName(name string) string {
+	name = strings.ToLower(strings.Trim(nonWord.ReplaceAllString(name, "_"), "_"))
+	if name == "" {
+		return "ezmodel"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
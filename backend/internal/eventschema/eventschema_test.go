@@ -0,0 +1,80 @@
+package eventschema
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var shop = schemadiff.Schema{
+	Tables: []schemadiff.Table{
+		{Name: "users", Fields: []schemadiff.Field{
+			{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+			{Name: "email", DataType: "VARCHAR(255)"},
+		}},
+		{Name: "order_items", Fields: []schemadiff.Field{
+			{Name: "id", DataType: "BIGINT", IsPrimaryKey: true},
+			{Name: "user_id", DataType: "UUID", IsNullable: true},
+			{Name: "quantity", DataType: "INT"},
+			{Name: "price", DataType: "NUMERIC(10,2)"},
+			{Name: "Placed At", DataType: "TIMESTAMPTZ", IsNullable: true},
+		}},
+	},
+	Relationships: []schemadiff.Relationship{
+		{SourceTable: "users", SourceField: "id", TargetTable: "order_items", TargetField: "user_id", RelationType: "one_to_many"},
+	},
+}
+
+func TestAvro(t *testing.T) {
+	files, err := Avro("Shop DB", shop)
+
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, "shop_db/Users.avsc", files[0].Name)
+	assert.Equal(t, "shop_db/OrderItems.avsc", files[1].Name)
+	assert.JSONEq(t, `{
+		"type": "record", "name": "OrderItems", "namespace": "shop_db", "doc": "A row of the order_items table",
+		"fields": [
+			{"name": "id", "type": "long"},
+			{"name": "user_id", "type": ["null", {"type": "string", "logicalType": "uuid"}], "doc": "References users.id", "default": null},
+			{"name": "quantity", "type": "int"},
+			{"name": "price", "type": {"type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2}},
+			{"name": "placed_at", "type": ["null", {"type": "long", "logicalType": "timestamp-micros"}], "default": null}
+		]
+	}`, string(files[1].Data))
+}
+
+func TestProtobuf(t *testing.T) {
+	proto := Protobuf("Shop DB", shop)
+
+	assert.Equal(t, `syntax = "proto3";
+
+package shop_db;
+
+import "google/protobuf/timestamp.proto";
+
+// A row of the users table
+message Users {
+  string id = 1; // UUID
+  string email = 2;
+}
+
+// A row of the order_items table
+message OrderItems {
+  int64 id = 1;
+  optional string user_id = 2; // References users.id
+  int32 quantity = 3;
+  string price = 4; // Decimal of precision 10 and scale 2
+  optional google.protobuf.Timestamp placed_at = 5;
+}
+`, string(proto))
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "OrderItems", typeName("order items"))
+	assert.Equal(t, "T2024Sales", typeName("2024_sales"))
+	assert.Equal(t, "_1st_place", fieldName("1st place"))
+	assert.Equal(t, "ezmodel", PackageName("!!"))
+}
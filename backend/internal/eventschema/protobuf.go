@@ -0,0 +1,84 @@
+package eventschema
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+)
+
+const timestampImport = "google/protobuf/timestamp.proto"
+
+// Protobuf writes a proto3 file with a message per table, in the package of
+// the project. Fields are numbered in the order of the table, so reserve the
+// numbers of removed fields rather than exporting again over a published
+// contract. Optional fields are marked so presence is tracked; decimals are
+// strings, so no precision is lost, and dates ISO 8601 strings.
+func Protobuf(projectName string, schema schemadiff.Schema) []byte {
+	references := referencesOf(schema)
+
+	var messages bytes.Buffer
+	timestamps := false
+	for _, table := range schema.Tables {
+		fmt.Fprintf(&messages, "\n// A row of the %s table\nmessage %s {\n", table.Name, typeName(table.Name))
+		for i, field := range table.Fields {
+			c := parseColumn(field.DataType)
+			protoType, comment := protobufType(c)
+			if c.kind == "TIMESTAMP" {
+				timestamps = true
+			}
+			if reference, ok := references[[2]string{table.Name, field.Name}]; ok {
+				comment = reference
+			}
+			label := ""
+			if field.IsNullable && !field.IsPrimaryKey {
+				label = "optional "
+			}
+			fmt.Fprintf(&messages, "  %s%s %s = %d;", label, protoType, fieldName(field.Name), i+1)
+			if comment != "" {
+				messages.WriteString(" // " + comment)
+			}
+			messages.WriteString("\n")
+		}
+		messages.WriteString("}\n")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "syntax = \"proto3\";\n\npackage %s;\n", PackageName(projectName))
+	if timestamps {
+		fmt.Fprintf(&buf, "\nimport \"%s\";\n", timestampImport)
+	}
+	buf.Write(messages.Bytes())
+	return buf.Bytes()
+}
+
+// protobufType maps a data type to a Protobuf type, with a comment on how
+// values are written when the type alone does not say
+func protobufType(c column) (string, string) {
+	switch c.kind {
+	case "INTEGER":
+		if c.wide {
+			return "int64", ""
+		}
+		return "int32", ""
+	case "FLOAT":
+		if c.wide {
+			return "double", ""
+		}
+		return "float", ""
+	case "BOOLEAN":
+		return "bool", ""
+	case "TIMESTAMP":
+		return "google.protobuf.Timestamp", ""
+	case "DATE":
+		return "string", "ISO 8601 date"
+	case "DECIMAL":
+		return "string", fmt.Sprintf("Decimal of precision %d and scale %d", c.precision, c.scale)
+	case "UUID":
+		return "string", "UUID"
+	case "JSON":
+		return "string", "JSON"
+	default:
+		return "string", ""
+	}
+}
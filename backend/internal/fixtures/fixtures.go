@@ -0,0 +1,252 @@
+// Package fixtures generates fake but plausible rows for the tables of a
+// schema, e.g. to seed a development database. Tables are filled in foreign
+// key order, so every foreign key refers to a row generated before it.
+// Primary keys, the foreign keys of one-to-one relationships and columns
+// named like identifiers, such as email or username, are unique. Values
+// follow the data type of their column and, for common names such as
+// first_name, city or price, its meaning.
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+)
+
+// MaxRows bounds the rows generated for each table
+const MaxRows = 1000
+
+// nullShare is the share of values left null in nullable columns that are
+// neither keys nor foreign keys
+const nullShare = 0.1
+
+// ErrCycle is returned when tables reference each other through required
+// foreign keys, so none of them can be filled first
+var ErrCycle = errors.New("tables reference each other through required foreign keys")
+
+// Table is the rows generated for a table, in the order of its columns
+type Table struct {
+	Name    string
+	Columns []string
+	Rows    [][]Value
+}
+
+// Value is a generated value as text, e.g. 42, true or 2024-05-01
+type Value struct {
+	Text   string
+	Null   bool
+	Quoted bool // A string literal in SQL rather than a number or boolean
+}
+
+// reference is a foreign key column and the column it refers to
+type reference struct {
+	table  string
+	field  string
+	unique bool // One-to-one, so no two rows refer to the same row
+}
+
+// Generate fills every table of schema with rows rows, or fewer for a table
+// whose required one-to-one foreign key runs out of rows to refer to. The
+// same seed generates the same rows. Tables come in the order they can be
+// inserted in. Foreign keys that would refer to a table filled later, which
+// only nullable ones in a cycle do, are left null.
+func Generate(schema schemadiff.Schema, rows int, seed uint64) ([]Table, error) {
+	if rows < 1 || rows > MaxRows {
+		return nil, fmt.Errorf("rows must be between 1 and %d", MaxRows)
+	}
+
+	references := foreignKeys(schema)
+	order, deferred, err := insertOrder(schema.Tables, references)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		rng:        rand.New(rand.NewPCG(seed, seed)),
+		references: references,
+		deferred:   deferred,
+		tables:     make(map[string]*generated, len(order)),
+	}
+	tables := make([]Table, len(order))
+	for i, table := range order {
+		tables[i] = g.fill(table, rows)
+	}
+	return tables, nil
+}
+
+// foreignKeys maps the table and field names of foreign key columns to what
+// they refer to
+func foreignKeys(schema schemadiff.Schema) map[[2]string]reference {
+	references := make(map[[2]string]reference)
+	for _, foreignKey := range schema.ForeignKeys() {
+		references[[2]string{foreignKey.Table, foreignKey.Field}] = reference{
+			table:  foreignKey.RefTable,
+			field:  foreignKey.RefField,
+			unique: foreignKey.Unique,
+		}
+	}
+	return references
+}
+
+// insertOrder sorts tables so each comes after the tables it refers to,
+// keeping their order otherwise. When only tables in a cycle are left, their
+// nullable foreign keys to each other are deferred, i.e. left null, to break
+// it. Self references do not count, as a row can refer to an earlier row.
+func insertOrder(tables []schemadiff.Table, references map[[2]string]reference) ([]schemadiff.Table, map[[2]string]bool, error) {
+	deferred := make(map[[2]string]bool)
+	done := make(map[string]bool, len(tables))
+	order := make([]schemadiff.Table, 0, len(tables))
+	remaining := slices.Clone(tables)
+
+	ready := func(table schemadiff.Table) bool {
+		for _, field := range table.Fields {
+			key := [2]string{table.Name, field.Name}
+			ref, ok := references[key]
+			if ok && !deferred[key] && ref.table != table.Name && !done[ref.table] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for len(remaining) > 0 {
+		var left []schemadiff.Table
+		for _, table := range remaining {
+			if ready(table) {
+				order = append(order, table)
+				done[table.Name] = true
+			} else {
+				left = append(left, table)
+			}
+		}
+		if len(left) < len(remaining) {
+			remaining = left
+			continue
+		}
+
+		progress := false
+		for _, table := range remaining {
+			for _, field := range table.Fields {
+				key := [2]string{table.Name, field.Name}
+				if ref, ok := references[key]; ok && field.IsNullable && !deferred[key] && !done[ref.table] && ref.table != table.Name {
+					deferred[key] = true
+					progress = true
+				}
+			}
+		}
+		if !progress {
+			names := make([]string, len(remaining))
+			for i, table := range remaining {
+				names[i] = table.Name
+			}
+			return nil, nil, fmt.Errorf("%w: %s", ErrCycle, strings.Join(names, ", "))
+		}
+	}
+	return order, deferred, nil
+}
+
+// generated is a filled table, whose values foreign keys pick from
+type generated struct {
+	columns map[string]int
+	rows    [][]Value
+}
+
+type generator struct {
+	rng        *rand.Rand
+	references map[[2]string]reference
+	deferred   map[[2]string]bool
+	tables     map[string]*generated
+}
+
+func (g *generator) fill(table schemadiff.Table, rows int) Table {
+	result := Table{Name: table.Name, Columns: make([]string, len(table.Fields))}
+	filled := &generated{columns: make(map[string]int, len(table.Fields))}
+	for i, field := range table.Fields {
+		result.Columns[i] = field.Name
+		filled.columns[field.Name] = i
+	}
+	g.tables[table.Name] = filled
+
+	// Rows referred to one-to-one are handed out in a random order, each once
+	unused := make(map[string][]Value)
+	for _, field := range table.Fields {
+		key := [2]string{table.Name, field.Name}
+		ref, ok := g.references[key]
+		if !ok || !ref.unique || g.deferred[key] || ref.table == table.Name {
+			continue
+		}
+		values := g.candidates(ref)
+		g.rng.Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+		unused[field.Name] = values
+		if !field.IsNullable && len(values) < rows {
+			rows = len(values)
+		}
+	}
+
+	for i := range rows {
+		row := make([]Value, len(table.Fields))
+		for j := range row {
+			row[j] = Value{Null: true} // Until generated, so nothing refers to it
+		}
+		filled.rows = append(filled.rows, row) // Self references can pick the row itself
+
+		// Plain values first, so self references find the row's own key
+		for j, field := range table.Fields {
+			if _, ok := g.references[[2]string{table.Name, field.Name}]; !ok {
+				row[j] = g.value(table.Name, field, i)
+			}
+		}
+		for j, field := range table.Fields {
+			key := [2]string{table.Name, field.Name}
+			ref, ok := g.references[key]
+			if !ok {
+				continue
+			}
+			switch {
+			case g.deferred[key]:
+				row[j] = Value{Null: true}
+			case ref.unique && ref.table != table.Name:
+				if values := unused[field.Name]; len(values) > 0 {
+					row[j] = values[0]
+					unused[field.Name] = values[1:]
+				} else {
+					row[j] = Value{Null: true}
+				}
+			default:
+				row[j] = g.pick(ref, field.IsNullable)
+			}
+		}
+	}
+	result.Rows = filled.rows
+	return result
+}
+
+// candidates lists the non-null values of the column ref refers to
+func (g *generator) candidates(ref reference) []Value {
+	referenced, ok := g.tables[ref.table]
+	if !ok {
+		return nil
+	}
+	column := referenced.columns[ref.field]
+	var values []Value
+	for _, row := range referenced.rows {
+		if !row[column].Null {
+			values = append(values, row[column])
+		}
+	}
+	return values
+}
+
+// pick refers to a random row of the referenced table, or to none when it
+// has no rows or, for nullable columns, now and then
+func (g *generator) pick(ref reference, nullable bool) Value {
+	values := g.candidates(ref)
+	if len(values) == 0 || nullable && g.rng.Float64() < nullShare {
+		return Value{Null: true}
+	}
+	return values[g.rng.IntN(len(values))]
+}
@@ -0,0 +1,178 @@
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shop has orders referring to users and users, one-to-one, to profiles
+// declared after them
+var shop = schemadiff.Schema{
+	Tables: []schemadiff.Table{
+		{Name: "orders", Fields: []schemadiff.Field{
+			{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+			{Name: "user_id", DataType: "UUID"},
+			{Name: "total", DataType: "DECIMAL(10,2)"},
+			{Name: "placed_on", DataType: "DATE"},
+		}},
+		{Name: "users", Fields: []schemadiff.Field{
+			{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+			{Name: "email", DataType: "VARCHAR(255)"},
+			{Name: "profile_id", DataType: "INTEGER"},
+		}},
+		{Name: "profiles", Fields: []schemadiff.Field{
+			{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+			{Name: "bio", DataType: "TEXT", IsNullable: true},
+		}},
+	},
+	Relationships: []schemadiff.Relationship{
+		{SourceTable: "users", SourceField: "id", TargetTable: "orders", TargetField: "user_id", RelationType: "one_to_many"},
+		{SourceTable: "profiles", SourceField: "id", TargetTable: "users", TargetField: "profile_id", RelationType: "one_to_one"},
+	},
+}
+
+func column(table Table, name string) []Value {
+	for i, column := range table.Columns {
+		if column == name {
+			values := make([]Value, len(table.Rows))
+			for j, row := range table.Rows {
+				values[j] = row[i]
+			}
+			return values
+		}
+	}
+	return nil
+}
+
+func texts(values []Value) []string {
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = value.Text
+	}
+	return result
+}
+
+func TestGenerateOrdersByForeignKeys(t *testing.T) {
+	tables, err := Generate(shop, 20, 1)
+
+	require.NoError(t, err)
+	require.Len(t, tables, 3)
+	assert.Equal(t, "profiles", tables[0].Name)
+	assert.Equal(t, "users", tables[1].Name)
+	assert.Equal(t, "orders", tables[2].Name)
+
+	userIDs := texts(column(tables[1], "id"))
+	for _, value := range column(tables[2], "user_id") {
+		assert.False(t, value.Null)
+		assert.Contains(t, userIDs, value.Text)
+	}
+	assert.Len(t, tables[2].Rows, 20)
+}
+
+func TestGenerateUniqueValues(t *testing.T) {
+	tables, err := Generate(shop, 50, 7)
+
+	require.NoError(t, err)
+	users := tables[1]
+	for _, name := range []string{"id", "email", "profile_id"} {
+		values := texts(column(users, name))
+		seen := make(map[string]bool)
+		for _, value := range values {
+			assert.False(t, seen[value], "%s %s repeats", name, value)
+			seen[value] = true
+		}
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, texts(column(tables[0], "id"))[:3])
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, users.Rows[0][0].Text)
+	assert.Regexp(t, `@example\.com$`, users.Rows[0][1].Text)
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}$`, tables[2].Rows[0][3].Text)
+}
+
+func TestGenerateIsRepeatable(t *testing.T) {
+	first, err := Generate(shop, 10, 42)
+	require.NoError(t, err)
+	second, err := Generate(shop, 10, 42)
+	require.NoError(t, err)
+	other, err := Generate(shop, 10, 43)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, other)
+}
+
+func TestGenerateCycles(t *testing.T) {
+	schema := schemadiff.Schema{
+		Tables: []schemadiff.Table{
+			{Name: "teams", Fields: []schemadiff.Field{
+				{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+				{Name: "captain_id", DataType: "INTEGER", IsNullable: true},
+			}},
+			{Name: "players", Fields: []schemadiff.Field{
+				{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+				{Name: "team_id", DataType: "INTEGER"},
+				{Name: "mentor_id", DataType: "INTEGER", IsNullable: true},
+			}},
+		},
+		Relationships: []schemadiff.Relationship{
+			{SourceTable: "players", SourceField: "id", TargetTable: "teams", TargetField: "captain_id", RelationType: "one_to_one"},
+			{SourceTable: "teams", SourceField: "id", TargetTable: "players", TargetField: "team_id", RelationType: "one_to_many"},
+			{SourceTable: "players", SourceField: "id", TargetTable: "players", TargetField: "mentor_id", RelationType: "one_to_many"},
+		},
+	}
+
+	tables, err := Generate(schema, 5, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "teams", tables[0].Name)
+	for _, value := range column(tables[0], "captain_id") {
+		assert.True(t, value.Null) // Deferred to break the cycle
+	}
+	playerIDs := texts(column(tables[1], "id"))
+	for _, value := range column(tables[1], "mentor_id") {
+		if !value.Null {
+			assert.Contains(t, playerIDs, value.Text)
+		}
+	}
+
+	schema.Tables[0].Fields[1].IsNullable = false
+	_, err = Generate(schema, 5, 1)
+	assert.ErrorIs(t, err, ErrCycle)
+}
+
+func TestGenerateRows(t *testing.T) {
+	_, err := Generate(shop, 0, 1)
+	assert.Error(t, err)
+	_, err = Generate(shop, MaxRows+1, 1)
+	assert.Error(t, err)
+}
+
+func TestSQL(t *testing.T) {
+	tables := []Table{{
+		Name:    "users",
+		Columns: []string{"id", "name", "active", "bio"},
+		Rows: [][]Value{
+			{{Text: "1"}, {Text: "O'Brien", Quoted: true}, {Text: "true"}, {Null: true}},
+			{{Text: "2"}, {Text: "Ada", Quoted: true}, {Text: "false"}, {Text: "Hi", Quoted: true}},
+		},
+	}}
+
+	assert.Equal(t, `-- users: 2 rows
+INSERT INTO "users" ("id", "name", "active", "bio") VALUES
+  (1, 'O''Brien', true, NULL),
+  (2, 'Ada', false, 'Hi');
+`, string(SQL(tables)))
+}
+
+func TestCSV(t *testing.T) {
+	data, err := CSV(Table{
+		Name:    "users",
+		Columns: []string{"id", "name", "bio"},
+		Rows:    [][]Value{{{Text: "1"}, {Text: "Lovelace, Ada", Quoted: true}, {Null: true}}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "id,name,bio\n1,\"Lovelace, Ada\",\n", string(data))
+}
@@ -0,0 +1,78 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// insertBatch is the most rows inserted by one INSERT statement
+const insertBatch = 100
+
+// SQL renders tables as INSERT statements in their order, for PostgreSQL
+func SQL(tables []Table) []byte {
+	var buf bytes.Buffer
+	for i, table := range tables {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "-- %s: %d rows\n", table.Name, len(table.Rows))
+
+		columns := make([]string, len(table.Columns))
+		for j, column := range table.Columns {
+			columns[j] = quoteIdentifier(column)
+		}
+		for start := 0; start < len(table.Rows); start += insertBatch {
+			batch := table.Rows[start:min(start+insertBatch, len(table.Rows))]
+			fmt.Fprintf(&buf, "INSERT INTO %s (%s) VALUES\n", quoteIdentifier(table.Name), strings.Join(columns, ", "))
+			for j, row := range batch {
+				values := make([]string, len(row))
+				for k, value := range row {
+					values[k] = literal(value)
+				}
+				separator := ","
+				if j == len(batch)-1 {
+					separator = ";"
+				}
+				fmt.Fprintf(&buf, "  (%s)%s\n", strings.Join(values, ", "), separator)
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// CSV renders a table as CSV with a header row; nulls are empty
+func CSV(table Table) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(table.Columns); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i, value := range row {
+			record[i] = value.Text
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+func literal(value Value) string {
+	switch {
+	case value.Null:
+		return "NULL"
+	case value.Quoted:
+		return "'" + strings.ReplaceAll(value.Text, "'", "''") + "'"
+	default:
+		return value.Text
+	}
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
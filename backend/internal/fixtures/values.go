@@ -0,0 +1,204 @@
+package fixtures
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+)
+
+// Generated dates and timestamps fall between epoch and epoch plus span
+var (
+	epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	span  = 6 * 365 * 24 * time.Hour
+)
+
+var (
+	firstNames = []string{"Ada", "Alan", "Grace", "Linus", "Margaret", "Dennis", "Barbara", "Ken", "Frances", "Edsger", "Katherine", "Donald"}
+	lastNames  = []string{"Lovelace", "Turing", "Hopper", "Torvalds", "Hamilton", "Ritchie", "Liskov", "Thompson", "Allen", "Dijkstra", "Johnson", "Knuth"}
+	cities     = []string{"London", "Paris", "Berlin", "Madrid", "Lisbon", "Amsterdam", "Vienna", "Prague", "Dublin", "Oslo"}
+	countries  = []string{"United Kingdom", "France", "Germany", "Spain", "Portugal", "Netherlands", "Austria", "Czechia", "Ireland", "Norway"}
+	streets    = []string{"High Street", "Station Road", "Church Lane", "Park Avenue", "Mill Road", "King Street", "Victoria Road", "Green Lane"}
+	companies  = []string{"Acme", "Globex", "Initech", "Umbrella", "Hooli", "Stark Industries", "Wayne Enterprises", "Wonka"}
+	statuses   = []string{"active", "inactive", "pending"}
+	colors     = []string{"red", "green", "blue", "yellow", "purple", "orange"}
+	words      = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "do", "eiusmod", "tempor"}
+)
+
+// value generates the value of a column that is not a foreign key for the
+// i-th row. Primary keys and identifier-like columns include i to be unique.
+func (g *generator) value(table string, field schemadiff.Field, i int) Value {
+	name := strings.ToLower(field.Name)
+	if field.IsNullable && !field.IsPrimaryKey && g.rng.Float64() < nullShare {
+		return Value{Null: true}
+	}
+
+	dataType, length := parseType(field.DataType)
+	switch {
+	case dataType == "UUID":
+		return quoted(g.uuid())
+	case isOneOf(dataType, "INT", "INTEGER", "BIGINT", "SMALLINT", "TINYINT", "SERIAL", "BIGSERIAL", "SMALLSERIAL", "INT2", "INT4", "INT8"):
+		return Value{Text: strconv.Itoa(g.integer(name, field.IsPrimaryKey, i))}
+	case isOneOf(dataType, "DECIMAL", "NUMERIC", "MONEY", "FLOAT", "DOUBLE", "DOUBLE PRECISION", "REAL", "FLOAT4", "FLOAT8"):
+		return Value{Text: strconv.FormatFloat(g.number(name), 'f', 2, 64)}
+	case isOneOf(dataType, "BOOL", "BOOLEAN"):
+		return Value{Text: strconv.FormatBool(g.rng.IntN(2) == 0)}
+	case dataType == "DATE":
+		return quoted(g.instant().Format(time.DateOnly))
+	case dataType == "TIME":
+		return quoted(g.instant().Format(time.TimeOnly))
+	case strings.HasPrefix(dataType, "TIMESTAMP") || dataType == "DATETIME" || dataType == "TIMESTAMPTZ":
+		return quoted(g.instant().Format("2006-01-02 15:04:05"))
+	case isOneOf(dataType, "JSON", "JSONB"):
+		return quoted(fmt.Sprintf(`{"%s": %d}`, name, i+1))
+	}
+
+	text := g.text(table, name, i)
+	if field.IsPrimaryKey && !strings.Contains(text, strconv.Itoa(i+1)) {
+		text = fmt.Sprintf("%s_%d", text, i+1)
+	}
+	if length > 0 && len(text) > length {
+		if field.IsPrimaryKey {
+			text = text[len(text)-length:] // Keeps the row number that makes it unique
+		} else {
+			text = text[:length]
+		}
+	}
+	return quoted(text)
+}
+
+func (g *generator) integer(name string, primaryKey bool, i int) int {
+	switch {
+	case primaryKey:
+		return i + 1
+	case name == "age":
+		return 18 + g.rng.IntN(72)
+	case strings.Contains(name, "year"):
+		return 1990 + g.rng.IntN(36)
+	case strings.Contains(name, "quantity") || strings.Contains(name, "count") || name == "qty":
+		return 1 + g.rng.IntN(20)
+	default:
+		return 1 + g.rng.IntN(1000)
+	}
+}
+
+func (g *generator) number(name string) float64 {
+	switch {
+	case name == "rate" || strings.HasSuffix(name, "_rate") || strings.Contains(name, "percent"):
+		return g.rng.Float64() * 100
+	case isOneOf(name, "lat", "latitude"):
+		return g.rng.Float64()*180 - 90
+	case isOneOf(name, "lng", "lon", "longitude"):
+		return g.rng.Float64()*360 - 180
+	default:
+		return 1 + g.rng.Float64()*999 // Prices, amounts and totals alike
+	}
+}
+
+// text generates a string for a column, by what its name suggests it holds
+func (g *generator) text(table, name string, i int) string {
+	first := firstNames[g.rng.IntN(len(firstNames))]
+	last := lastNames[g.rng.IntN(len(lastNames))]
+	switch {
+	case strings.Contains(name, "email"):
+		return fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(first), strings.ToLower(last), i+1)
+	case strings.Contains(name, "username") || name == "login" || name == "handle":
+		return fmt.Sprintf("%s%d", strings.ToLower(first), i+1)
+	case strings.Contains(name, "first_name") || name == "firstname" || name == "given_name":
+		return first
+	case strings.Contains(name, "last_name") || name == "lastname" || name == "surname" || name == "family_name":
+		return last
+	case isOneOf(name, "full_name", "fullname", "display_name"),
+		name == "name" && isOneOf(table, "users", "customers", "people", "authors", "employees"):
+		return first + " " + last
+	case strings.Contains(name, "phone") || strings.Contains(name, "mobile"):
+		return fmt.Sprintf("+44 20 %04d %04d", g.rng.IntN(10000), g.rng.IntN(10000))
+	case strings.Contains(name, "city") || name == "town":
+		return cities[g.rng.IntN(len(cities))]
+	case strings.Contains(name, "country"):
+		return countries[g.rng.IntN(len(countries))]
+	case strings.Contains(name, "address") || strings.Contains(name, "street"):
+		return fmt.Sprintf("%d %s", 1+g.rng.IntN(200), streets[g.rng.IntN(len(streets))])
+	case strings.Contains(name, "zip") || strings.Contains(name, "postal") || strings.Contains(name, "postcode"):
+		return fmt.Sprintf("%05d", g.rng.IntN(100000))
+	case strings.Contains(name, "url") || strings.Contains(name, "website") || strings.Contains(name, "link"):
+		return fmt.Sprintf("https://example.com/%s/%d", table, i+1)
+	case strings.Contains(name, "company") || strings.Contains(name, "organization"):
+		return companies[g.rng.IntN(len(companies))]
+	case name == "status":
+		return statuses[g.rng.IntN(len(statuses))]
+	case strings.Contains(name, "color") || strings.Contains(name, "colour"):
+		return colors[g.rng.IntN(len(colors))]
+	case name == "slug":
+		return fmt.Sprintf("%s-%d", singular(table), i+1)
+	case name == "code" || name == "sku" || strings.HasSuffix(name, "_code"):
+		prefix := singular(table)
+		return fmt.Sprintf("%s-%04d", strings.ToUpper(prefix[:min(3, len(prefix))]), i+1)
+	case strings.Contains(name, "password") || strings.Contains(name, "hash") || strings.Contains(name, "token"):
+		return fmt.Sprintf("%016x%016x", g.rng.Uint64(), g.rng.Uint64())
+	case isOneOf(name, "description", "body", "content", "bio", "notes", "comment", "summary", "text", "message"):
+		return g.sentence(8 + g.rng.IntN(8))
+	case isOneOf(name, "title", "subject", "headline"):
+		return strings.TrimSuffix(g.sentence(3+g.rng.IntN(3)), ".")
+	default:
+		return fmt.Sprintf("%s %s %d", singular(table), strings.ReplaceAll(name, "_", " "), i+1)
+	}
+}
+
+// sentence strings count lorem ipsum words into a capitalized sentence
+func (g *generator) sentence(count int) string {
+	chosen := make([]string, count)
+	for i := range chosen {
+		chosen[i] = words[g.rng.IntN(len(words))]
+	}
+	text := strings.Join(chosen, " ")
+	return strings.ToUpper(text[:1]) + text[1:] + "."
+}
+
+func (g *generator) instant() time.Time {
+	return epoch.Add(time.Duration(g.rng.Int64N(int64(span)))).Truncate(time.Second)
+}
+
+// uuid generates a version 4 UUID from the generator, so seeds repeat it
+func (g *generator) uuid() string {
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(g.rng.UintN(256))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// parseType splits a data type such as VARCHAR(255) into VARCHAR and its
+// length, or 0 when it has none
+func parseType(dataType string) (string, int) {
+	base, args, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(dataType)), "(")
+	base = strings.TrimSpace(base)
+	if !ok || isOneOf(base, "DECIMAL", "NUMERIC") { // Their arguments are precision and scale
+		return base, 0
+	}
+	args, _, _ = strings.Cut(args, ")")
+	first, _, _ := strings.Cut(args, ",")
+	length, _ := strconv.Atoi(strings.TrimSpace(first))
+	return base, length
+}
+
+// singular drops the plural s of a table name, e.g. products to product
+func singular(table string) string {
+	if len(table) > 1 && strings.HasSuffix(table, "s") && !strings.HasSuffix(table, "ss") {
+		return table[:len(table)-1]
+	}
+	return table
+}
+
+func quoted(text string) Value {
+	return Value{Text: text, Quoted: true}
+}
+
+func isOneOf(value string, options ...string) bool {
+	return slices.Contains(options, value)
+}
@@ -0,0 +1,40 @@
+package idempotency
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/redis"
+)
+
+// Record is a request made with an idempotency key and, once it finished, its response
+type Record struct {
+	Fingerprint string      `json:"fingerprint"`      // Hash of the request, so a key cannot be reused for another one
+	Status      int         `json:"status,omitempty"` // Zero while the request is in progress
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// Store keeps records by key until they expire
+type Store interface {
+	// Claim stores the record of a request that is starting, unless key already
+	// has one. The existing record is returned then, and nil when key was claimed.
+	Claim(key string, record *Record, ttl time.Duration) (*Record, error)
+	// Save replaces the record of a claimed key once its request finished
+	Save(key string, record *Record, ttl time.Duration) error
+	// Delete forgets a key so its request can be retried
+	Delete(key string) error
+}
+
+// New returns a Redis-backed store shared by every node when Redis is
+// available and useRedis is set, and a node-local store otherwise
+func New(redisClient *redis.Client, useRedis bool) Store {
+	memory := NewMemoryStore()
+	if !useRedis || redisClient == nil || !redisClient.IsEnabled() {
+		log.Println("Idempotency keys are kept in memory; retries are only recognized by the same node")
+		return memory
+	}
+
+	return NewRedisStore(redisClient, memory)
+}
@@ -0,0 +1,53 @@
+package idempotency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	existing, err := s.Claim("user:key", &Record{Fingerprint: "a"}, time.Minute)
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	// A second claim sees the request in progress
+	existing, _ = s.Claim("user:key", &Record{Fingerprint: "b"}, time.Minute)
+	assert.Equal(t, &Record{Fingerprint: "a"}, existing)
+
+	// and then its response
+	assert.NoError(t, s.Save("user:key", &Record{Fingerprint: "a", Status: 201, Body: []byte("{}")}, time.Hour))
+	now = now.Add(30 * time.Minute)
+	existing, _ = s.Claim("user:key", &Record{Fingerprint: "a"}, time.Minute)
+	assert.Equal(t, 201, existing.Status)
+
+	// The key can be used again once the record expired or was deleted
+	now = now.Add(time.Hour)
+	existing, _ = s.Claim("user:key", &Record{Fingerprint: "c"}, time.Minute)
+	assert.Nil(t, existing)
+
+	assert.NoError(t, s.Delete("user:key"))
+	existing, _ = s.Claim("user:key", &Record{Fingerprint: "d"}, time.Minute)
+	assert.Nil(t, existing)
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	s.Claim("a", &Record{}, time.Second)
+	s.Claim("b", &Record{}, time.Hour)
+
+	now = now.Add(2 * sweepInterval)
+	s.Claim("c", &Record{}, time.Second)
+
+	assert.NotContains(t, s.entries, "a")
+	assert.Contains(t, s.entries, "b")
+	assert.Contains(t, s.entries, "c")
+}
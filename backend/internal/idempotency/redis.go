@@ -0,0 +1,73 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/redis"
+)
+
+// RedisStore keeps records in Redis so a retry is recognized by any node. When
+// Redis is unreachable it falls back to node-local records rather than failing requests.
+type RedisStore struct {
+	client   *redis.Client
+	fallback Store
+}
+
+func NewRedisStore(client *redis.Client, fallback Store) *RedisStore {
+	return &RedisStore{
+		client:   client,
+		fallback: fallback,
+	}
+}
+
+func (s *RedisStore) Claim(key string, record *Record, ttl time.Duration) (*Record, error) {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.client.SetNX(key, value, ttl)
+	if err != nil {
+		log.Printf("Idempotency record unavailable in Redis, using local record: %v", err)
+		return s.fallback.Claim(key, record, ttl)
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	value, err = s.client.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		// Expired since SetNX; try again rather than run the request unclaimed
+		return s.Claim(key, record, ttl)
+	}
+	var existing Record
+	if err := json.Unmarshal(value, &existing); err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+func (s *RedisStore) Save(key string, record *Record, ttl time.Duration) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	if err := s.client.Set(key, value, ttl); err != nil {
+		log.Printf("Idempotency record unavailable in Redis, using local record: %v", err)
+		return s.fallback.Save(key, record, ttl)
+	}
+	return nil
+}
+
+func (s *RedisStore) Delete(key string) error {
+	if err := s.client.Del(key); err != nil {
+		log.Printf("Idempotency record unavailable in Redis, using local record: %v", err)
+	}
+	return s.fallback.Delete(key)
+}
@@ -0,0 +1,686 @@
+// Package inference proposes relational tables for MongoDB collections, from
+// sample documents or from Mongoose schemas. Embedded documents are flattened
+// into prefixed columns, arrays of documents become child tables and
+// references between collections become relationships.
+package inference
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Data types of the proposed columns
+const (
+	TypeString    = "STRING"
+	TypeText      = "TEXT"
+	TypeInteger   = "INTEGER"
+	TypeFloat     = "FLOAT"
+	TypeDecimal   = "DECIMAL"
+	TypeBoolean   = "BOOLEAN"
+	TypeTimestamp = "TIMESTAMP"
+	TypeDate      = "DATE"
+	TypeUUID      = "UUID"
+	TypeJSON      = "JSON"
+
+	// typeObjectID marks MongoDB ObjectIds, proposed as strings; references
+	// between collections are recognized by them
+	typeObjectID = "OBJECTID"
+)
+
+const (
+	// maxDepth bounds how deep embedded documents are flattened or split
+	// into child tables; deeper ones are kept as JSON
+	maxDepth = 4
+
+	// maxStringLength is the longest string proposed as STRING rather than TEXT
+	maxStringLength = 255
+
+	// versionKey is the document version Mongoose adds, which is left out
+	versionKey = "__v"
+)
+
+var (
+	objectIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{24}$`)
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// ErrNotObject is returned for a document or Mongoose schema that is not a JSON object
+var ErrNotObject = errors.New("documents and Mongoose schemas must be JSON objects")
+
+// Collection is a MongoDB collection described by sample documents, as
+// exported by mongoexport or mongosh with their extended JSON types, or by
+// the definition of a Mongoose schema as JSON, e.g. {"title": "String",
+// "author": {"type": "ObjectId", "ref": "User", "required": true}}
+type Collection struct {
+	Name      string
+	Documents []json.RawMessage
+	Mongoose  json.RawMessage
+}
+
+// Schema is the proposed tables and relationships, with notes on what could
+// not be inferred with confidence
+type Schema struct {
+	Tables        []Table
+	Relationships []Relationship
+	Notes         []string
+}
+
+type Table struct {
+	Name    string
+	Columns []Column
+}
+
+type Column struct {
+	Name         string
+	DataType     string
+	IsPrimaryKey bool
+	IsNullable   bool
+	DefaultValue string
+}
+
+// Relationship links a table's id to the column of another table referring
+// to it, one to many
+type Relationship struct {
+	SourceTable string
+	SourceField string
+	TargetTable string
+	TargetField string
+}
+
+// shape is what the documents of a collection, or the elements of an array of
+// documents, hold
+type shape struct {
+	fields     map[string]*field
+	order      []string // Keys in the order they were first seen
+	count      int      // Documents seen
+	definition bool     // Read from a Mongoose schema rather than documents
+}
+
+// field is what a key of a shape holds across documents
+type field struct {
+	types    map[string]bool // Scalar types seen
+	present  int             // Documents in which it is set and not null
+	object   *shape          // Embedded documents seen
+	array    *shape          // Elements of arrays of documents seen
+	scalars  bool            // Arrays of scalars seen
+	required bool            // Required by a Mongoose schema
+	ref      string          // Model referred to by a Mongoose schema
+	def      string          // Default value of a Mongoose schema, as SQL
+}
+
+func newShape() *shape {
+	return &shape{fields: make(map[string]*field)}
+}
+
+func (s *shape) field(key string) *field {
+	f, ok := s.fields[key]
+	if !ok {
+		f = &field{types: make(map[string]bool)}
+		s.fields[key] = f
+		s.order = append(s.order, key)
+	}
+	return f
+}
+
+// nullable reports whether a field may be missing or null
+func (s *shape) nullable(f *field) bool {
+	if s.definition {
+		return !f.required
+	}
+	return f.present < s.count
+}
+
+// Infer proposes tables and relationships for the collections, in their order
+func Infer(collections []Collection) (*Schema, error) {
+	b := &builder{schema: &Schema{}, tables: make(map[string]bool), collections: make(map[string]string)}
+	names := make([]string, len(collections))
+	for i, collection := range collections {
+		names[i] = b.reserveTable(strings.TrimSpace(collection.Name))
+		b.collections[strings.ToLower(names[i])] = names[i]
+	}
+
+	shapes := make([]*shape, len(collections))
+	for i, collection := range collections {
+		if collection.Mongoose != nil {
+			definition, err := decodeObject(collection.Mongoose)
+			if err != nil {
+				return nil, err
+			}
+			shapes[i] = b.mongooseShape(definition, names[i], 0)
+			continue
+		}
+		shapes[i] = newShape()
+		for _, data := range collection.Documents {
+			document, err := decodeObject(data)
+			if err != nil {
+				return nil, err
+			}
+			b.observeDocument(shapes[i], document, names[i], 0)
+		}
+	}
+
+	for i, s := range shapes {
+		b.addCollection(names[i], s)
+	}
+	return b.schema, nil
+}
+
+func decodeObject(data json.RawMessage) (*object, error) {
+	value, err := decode(data)
+	if err != nil {
+		return nil, ErrNotObject
+	}
+	o, ok := value.(*object)
+	if !ok {
+		return nil, ErrNotObject
+	}
+	return o, nil
+}
+
+type builder struct {
+	schema      *Schema
+	tables      map[string]bool   // Names of the proposed tables
+	collections map[string]string // Table names of the collections by their lowercase
+}
+
+func (b *builder) note(format string, args ...any) {
+	b.schema.Notes = append(b.schema.Notes, fmt.Sprintf(format, args...))
+}
+
+// reserveTable returns a table name not proposed yet, numbering the name when it is
+func (b *builder) reserveTable(name string) string {
+	unique := name
+	for i := 2; b.tables[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	b.tables[unique] = true
+	return unique
+}
+
+// observeDocument adds what a document holds to a shape
+func (b *builder) observeDocument(s *shape, document *object, path string, depth int) {
+	s.count++
+	for _, key := range document.keys {
+		if key == versionKey {
+			continue
+		}
+		value, _ := document.get(key)
+		b.observeValue(s.field(key), value, path+"."+key, depth)
+	}
+}
+
+func (b *builder) observeValue(f *field, value any, path string, depth int) {
+	if value == nil {
+		return
+	}
+	f.present++
+
+	switch value := value.(type) {
+	case *object:
+		if scalarType, ok := extendedJSONType(value); ok {
+			f.types[scalarType] = true
+			return
+		}
+		if depth >= maxDepth {
+			f.types[TypeJSON] = true
+			return
+		}
+		if f.object == nil {
+			f.object = newShape()
+		}
+		b.observeDocument(f.object, value, path, depth+1)
+	case []any:
+		if len(value) == 0 && f.array == nil {
+			f.scalars = true
+		}
+		for _, element := range value {
+			document, ok := element.(*object)
+			if !ok || depth >= maxDepth {
+				f.scalars = true
+				continue
+			}
+			if _, ok := extendedJSONType(document); ok {
+				f.scalars = true
+				continue
+			}
+			if f.array == nil {
+				f.array = newShape()
+			}
+			b.observeDocument(f.array, document, path, depth+1)
+		}
+	default:
+		f.types[scalarType(value)] = true
+	}
+}
+
+// mongooseShape reads the paths of a Mongoose schema definition
+func (b *builder) mongooseShape(definition *object, path string, depth int) *shape {
+	s := newShape()
+	s.count = 1
+	s.definition = true
+	for _, key := range definition.keys {
+		value, _ := definition.get(key)
+		if key == versionKey || (key == "_id" && value == false) {
+			continue
+		}
+		f := s.field(key)
+		f.present = 1
+		b.mongooseField(f, value, path+"."+key, depth)
+	}
+	return s
+}
+
+func (b *builder) mongooseField(f *field, value any, path string, depth int) {
+	switch value := value.(type) {
+	case string:
+		f.types[b.mongooseType(value, path)] = true
+	case []any:
+		f.scalars = true
+		if len(value) != 1 {
+			return
+		}
+		element, ok := value[0].(*object)
+		if !ok {
+			return
+		}
+		if !isMongooseDefinition(element) && depth < maxDepth {
+			f.scalars = false
+			f.array = b.mongooseShape(element, path, depth+1)
+			return
+		}
+		if ref, ok := element.values["ref"].(string); ok {
+			b.note("%s: an array of references to %s, proposed as JSON; a join table may suit it better", path, ref)
+		}
+	case *object:
+		if !isMongooseDefinition(value) {
+			if depth >= maxDepth {
+				f.types[TypeJSON] = true
+				return
+			}
+			f.object = b.mongooseShape(value, path, depth+1)
+			f.required = true // Mongoose always sets nested paths
+			return
+		}
+		b.mongooseField(f, value.values["type"], path, depth)
+		switch required := value.values["required"].(type) {
+		case bool:
+			f.required = required
+		case []any:
+			f.required = len(required) > 0 && required[0] == true // [true, "message"]
+		}
+		if ref, ok := value.values["ref"].(string); ok {
+			f.ref = ref
+		}
+		if def, ok := value.get("default"); ok {
+			f.def = defaultValue(def)
+		}
+	default:
+		f.types[TypeJSON] = true
+		b.note("%s: unknown Mongoose type, proposed as JSON", path)
+	}
+}
+
+// isMongooseDefinition reports whether an object defines one path, with its
+// type and options, rather than a nested document
+func isMongooseDefinition(value *object) bool {
+	switch value.values["type"].(type) {
+	case string, []any:
+		return true
+	}
+	return false
+}
+
+func (b *builder) mongooseType(name, path string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:] // Schema.Types.ObjectId
+	}
+	switch strings.ToLower(name) {
+	case "string":
+		return TypeString
+	case "number", "double":
+		return TypeFloat
+	case "bigint", "int32":
+		return TypeInteger
+	case "decimal128":
+		return TypeDecimal
+	case "boolean":
+		return TypeBoolean
+	case "date":
+		return TypeTimestamp
+	case "objectid":
+		return typeObjectID
+	case "uuid":
+		return TypeUUID
+	case "buffer":
+		return TypeText
+	case "mixed", "map", "array", "object":
+		return TypeJSON
+	default:
+		b.note("%s: unknown Mongoose type %s, proposed as JSON", path, name)
+		return TypeJSON
+	}
+}
+
+// addCollection proposes the table of a collection and those of its arrays of
+// documents. The id column stands for _id, an ObjectId unless documents or
+// the schema say otherwise.
+func (b *builder) addCollection(name string, s *shape) {
+	idType := typeObjectID
+	if id, ok := s.fields["_id"]; ok {
+		idType = b.resolveType(id, name+"._id")
+	} else if !s.definition {
+		b.note("%s: documents have no _id, so an id column of ObjectIds is proposed", name)
+	}
+
+	b.schema.Tables = append(b.schema.Tables, Table{
+		Name:    name,
+		Columns: []Column{{Name: "id", DataType: columnType(idType), IsPrimaryKey: true}},
+	})
+	b.addColumns(len(b.schema.Tables)-1, map[string]bool{"id": true}, s, "", name, false)
+}
+
+// addColumns adds the fields of a shape to a table, flattening embedded
+// documents under prefix and splitting arrays of documents into child tables
+func (b *builder) addColumns(index int, columns map[string]bool, s *shape, prefix, path string, nullable bool) {
+	for _, key := range s.order {
+		if key == "_id" && prefix == "" {
+			continue // The table's id
+		}
+		f := s.fields[key]
+		fieldPath := path + "." + key
+		name := prefix + snakeCase(key)
+		isNullable := nullable || s.nullable(f)
+
+		kinds := 0
+		for _, holds := range []bool{len(f.types) > 0, f.object != nil, f.array != nil, f.scalars} {
+			if holds {
+				kinds++
+			}
+		}
+		switch {
+		case kinds > 1:
+			b.note("%s: holds both documents and other values, proposed as JSON", fieldPath)
+			b.addColumn(index, columns, Column{Name: name, DataType: TypeJSON, IsNullable: isNullable}, fieldPath)
+		case f.object != nil:
+			b.addColumns(index, columns, f.object, name+"_", fieldPath, isNullable)
+		case f.array != nil:
+			b.addChildTable(index, name, f.array, fieldPath)
+		case f.scalars:
+			b.addColumn(index, columns, Column{Name: name, DataType: TypeJSON, IsNullable: isNullable}, fieldPath)
+		default:
+			dataType := b.resolveType(f, fieldPath)
+			column := b.addColumn(index, columns, Column{Name: name, DataType: columnType(dataType), IsNullable: isNullable, DefaultValue: f.def}, fieldPath)
+			if dataType == typeObjectID {
+				b.addReference(index, column, f.ref, name, fieldPath)
+			}
+		}
+	}
+}
+
+// addChildTable proposes a table for an array of documents, referring to the
+// table holding the array. Ids the elements have of their own are kept in an
+// object_id column.
+func (b *builder) addChildTable(parent int, name string, s *shape, path string) {
+	parentTable := b.schema.Tables[parent]
+	foreignKey := singular(parentTable.Name) + "_id"
+	b.schema.Tables = append(b.schema.Tables, Table{
+		Name: b.reserveTable(parentTable.Name + "_" + name),
+		Columns: []Column{
+			{Name: "id", DataType: TypeInteger, IsPrimaryKey: true},
+			{Name: foreignKey, DataType: parentTable.Columns[0].DataType},
+		},
+	})
+	index := len(b.schema.Tables) - 1
+	b.schema.Relationships = append(b.schema.Relationships, Relationship{
+		SourceTable: parentTable.Name,
+		SourceField: "id",
+		TargetTable: b.schema.Tables[index].Name,
+		TargetField: foreignKey,
+	})
+
+	columns := map[string]bool{"id": true, foreignKey: true}
+	if id, ok := s.fields["_id"]; ok {
+		b.addColumn(index, columns, Column{Name: "object_id", DataType: columnType(b.resolveType(id, path+"._id"))}, path+"._id")
+	}
+	b.addColumns(index, columns, s, "", path, false)
+}
+
+// addColumn adds a column to a table, renaming it when the table has one of
+// that name already, and returns its name
+func (b *builder) addColumn(index int, columns map[string]bool, column Column, path string) string {
+	name := column.Name
+	for i := 2; columns[column.Name]; i++ {
+		column.Name = fmt.Sprintf("%s_%d", name, i)
+	}
+	if column.Name != name {
+		b.note("%s: proposed as %s, as %s is taken", path, column.Name, name)
+	}
+	columns[column.Name] = true
+	b.schema.Tables[index].Columns = append(b.schema.Tables[index].Columns, column)
+	return column.Name
+}
+
+// addReference relates a column of ObjectIds to the collection it refers to:
+// the one a Mongoose ref names, or the one the column is named after, e.g.
+// authors for author_id
+func (b *builder) addReference(index int, column, ref, name, path string) {
+	target := ref
+	if target == "" {
+		target = strings.TrimSuffix(name, "_id")
+	}
+	collection, ok := b.collection(target)
+	if !ok {
+		if ref != "" {
+			b.note("%s: refers to %s, which is not among the collections", path, ref)
+		}
+		return
+	}
+	b.schema.Relationships = append(b.schema.Relationships, Relationship{
+		SourceTable: collection,
+		SourceField: "id",
+		TargetTable: b.schema.Tables[index].Name,
+		TargetField: column,
+	})
+}
+
+// collection finds the table of a collection by a model or field name, as
+// Mongoose names collections after the lowercase plural of their model
+func (b *builder) collection(name string) (string, bool) {
+	name = strings.ToLower(name)
+	candidates := []string{name, name + "s", name + "es", strings.ReplaceAll(name, "_", "")}
+	if strings.HasSuffix(name, "y") {
+		candidates = append(candidates, strings.TrimSuffix(name, "y")+"ies")
+	}
+	for _, candidate := range candidates {
+		if table, ok := b.collections[candidate]; ok {
+			return table, true
+		}
+	}
+	return "", false
+}
+
+// resolveType picks one type for the values of a field, widening mixed
+// numbers, dates and strings, and falling back to JSON for anything else
+func (b *builder) resolveType(f *field, path string) string {
+	types := make([]string, 0, len(f.types))
+	for t := range f.types {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	switch {
+	case len(types) == 0:
+		b.note("%s: only ever null, proposed as %s", path, TypeString)
+		return TypeString
+	case len(types) == 1:
+		return types[0]
+	case only(f.types, TypeInteger, TypeFloat, TypeDecimal):
+		if f.types[TypeDecimal] {
+			return TypeDecimal
+		}
+		return TypeFloat
+	case only(f.types, TypeDate, TypeTimestamp):
+		return TypeTimestamp
+	case only(f.types, TypeString, TypeText, TypeUUID, TypeDate, TypeTimestamp, typeObjectID):
+		if f.types[TypeText] {
+			return TypeText
+		}
+		return TypeString
+	default:
+		b.note("%s: values of types %s, proposed as JSON", path, strings.Join(types, ", "))
+		return TypeJSON
+	}
+}
+
+func only(types map[string]bool, allowed ...string) bool {
+	for t := range types {
+		found := false
+		for _, a := range allowed {
+			found = found || t == a
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func columnType(dataType string) string {
+	if dataType == typeObjectID {
+		return TypeString
+	}
+	return dataType
+}
+
+// scalarType infers the type of a JSON scalar
+func scalarType(value any) string {
+	switch value := value.(type) {
+	case bool:
+		return TypeBoolean
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return TypeInteger
+		}
+		return TypeFloat
+	case string:
+		return stringType(value)
+	default:
+		return TypeJSON
+	}
+}
+
+func stringType(value string) string {
+	switch {
+	case objectIDPattern.MatchString(value):
+		return typeObjectID
+	case uuidPattern.MatchString(value):
+		return TypeUUID
+	case isTimestamp(value):
+		return TypeTimestamp
+	case isDate(value):
+		return TypeDate
+	case len(value) > maxStringLength:
+		return TypeText
+	default:
+		return TypeString
+	}
+}
+
+func isTimestamp(value string) bool {
+	_, err := time.Parse(time.RFC3339Nano, value)
+	return err == nil
+}
+
+func isDate(value string) bool {
+	_, err := time.Parse(time.DateOnly, value)
+	return err == nil
+}
+
+// extendedJSONType returns the type of a value in MongoDB extended JSON,
+// e.g. {"$oid": "..."}, reporting false for other documents
+func extendedJSONType(document *object) (string, bool) {
+	if len(document.keys) != 1 {
+		return "", false
+	}
+	for _, key := range document.keys {
+		switch key {
+		case "$oid":
+			return typeObjectID, true
+		case "$date":
+			return TypeTimestamp, true
+		case "$numberInt", "$numberLong":
+			return TypeInteger, true
+		case "$numberDouble":
+			return TypeFloat, true
+		case "$numberDecimal":
+			return TypeDecimal, true
+		case "$uuid":
+			return TypeUUID, true
+		case "$binary":
+			return TypeText, true
+		case "$timestamp":
+			return TypeTimestamp, true
+		}
+	}
+	return "", false
+}
+
+// defaultValue renders the default of a Mongoose path as SQL
+func defaultValue(value any) string {
+	switch value := value.(type) {
+	case string:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case bool, json.Number:
+		return fmt.Sprint(value)
+	default:
+		return ""
+	}
+}
+
+// snakeCase turns a key such as createdAt or userID into created_at or user_id
+func snakeCase(key string) string {
+	if key == "_id" {
+		return "id"
+	}
+	runes := []rune(key)
+	var out []rune
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				out = append(out, '_')
+			}
+			out = append(out, unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			out = append(out, r)
+		default:
+			out = append(out, '_')
+		}
+	}
+	name := strings.Trim(strings.Join(strings.FieldsFunc(string(out), func(r rune) bool { return r == '_' }), "_"), "_")
+	if name == "" {
+		return "field"
+	}
+	return name
+}
+
+// singular strips the plural of a table name, e.g. orders or categories
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "ss"):
+		return name
+	case strings.HasSuffix(name, "s"):
+		return strings.TrimSuffix(name, "s")
+	default:
+		return name
+	}
+}
@@ -0,0 +1,139 @@
+package inference
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func documents(values ...string) []json.RawMessage {
+	raw := make([]json.RawMessage, len(values))
+	for i, value := range values {
+		raw[i] = json.RawMessage(value)
+	}
+	return raw
+}
+
+func TestInferFromDocuments(t *testing.T) {
+	schema, err := Infer([]Collection{
+		{Name: "users", Documents: documents(
+			`{"_id": {"$oid": "64b7f0c2a1b2c3d4e5f60718"}, "email": "ada@example.com", "age": 36, "createdAt": {"$date": "2024-01-02T03:04:05Z"},
+			  "address": {"city": "London", "zip": "N1"}, "__v": 0}`,
+			`{"_id": {"$oid": "64b7f0c2a1b2c3d4e5f60719"}, "email": "alan@example.com", "age": 41.5, "createdAt": {"$date": "2024-02-02T03:04:05Z"},
+			  "tags": ["admin"]}`,
+		)},
+		{Name: "orders", Documents: documents(
+			`{"_id": "64b7f0c2a1b2c3d4e5f6071a", "userId": "64b7f0c2a1b2c3d4e5f60718", "placedOn": "2024-03-01", "total": {"$numberDecimal": "9.99"},
+			  "items": [{"sku": "A-1", "qty": 2}, {"sku": "B-2", "qty": 1, "note": "gift"}]}`,
+		)},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []Table{
+		{Name: "users", Columns: []Column{
+			{Name: "id", DataType: TypeString, IsPrimaryKey: true},
+			{Name: "email", DataType: TypeString},
+			{Name: "age", DataType: TypeFloat},
+			{Name: "created_at", DataType: TypeTimestamp},
+			{Name: "address_city", DataType: TypeString, IsNullable: true},
+			{Name: "address_zip", DataType: TypeString, IsNullable: true},
+			{Name: "tags", DataType: TypeJSON, IsNullable: true},
+		}},
+		{Name: "orders", Columns: []Column{
+			{Name: "id", DataType: TypeString, IsPrimaryKey: true},
+			{Name: "user_id", DataType: TypeString},
+			{Name: "placed_on", DataType: TypeDate},
+			{Name: "total", DataType: TypeDecimal},
+		}},
+		{Name: "orders_items", Columns: []Column{
+			{Name: "id", DataType: TypeInteger, IsPrimaryKey: true},
+			{Name: "order_id", DataType: TypeString},
+			{Name: "sku", DataType: TypeString},
+			{Name: "qty", DataType: TypeInteger},
+			{Name: "note", DataType: TypeString, IsNullable: true},
+		}},
+	}, schema.Tables)
+	assert.Equal(t, []Relationship{
+		{SourceTable: "users", SourceField: "id", TargetTable: "orders", TargetField: "user_id"},
+		{SourceTable: "orders", SourceField: "id", TargetTable: "orders_items", TargetField: "order_id"},
+	}, schema.Relationships)
+	assert.Empty(t, schema.Notes)
+}
+
+func TestInferFromMongoose(t *testing.T) {
+	schema, err := Infer([]Collection{
+		{Name: "posts", Mongoose: json.RawMessage(`{
+			"title": {"type": "String", "required": true},
+			"status": {"type": "String", "default": "draft"},
+			"author": {"type": "Schema.Types.ObjectId", "ref": "User", "required": [true, "An author is required"]},
+			"meta": {"votes": "Number", "type": {"type": "String"}},
+			"comments": [{"body": "String", "postedAt": "Date"}],
+			"reviewers": [{"type": "ObjectId", "ref": "User"}],
+			"extra": "Mixed"
+		}`)},
+		{Name: "users", Mongoose: json.RawMessage(`{"name": "String"}`)},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, Table{Name: "posts", Columns: []Column{
+		{Name: "id", DataType: TypeString, IsPrimaryKey: true},
+		{Name: "title", DataType: TypeString},
+		{Name: "status", DataType: TypeString, IsNullable: true, DefaultValue: "'draft'"},
+		{Name: "author", DataType: TypeString},
+		{Name: "meta_votes", DataType: TypeFloat, IsNullable: true},
+		{Name: "meta_type", DataType: TypeString, IsNullable: true},
+		{Name: "reviewers", DataType: TypeJSON, IsNullable: true},
+		{Name: "extra", DataType: TypeJSON, IsNullable: true},
+	}}, schema.Tables[0])
+	assert.Equal(t, "posts_comments", schema.Tables[1].Name)
+	assert.Equal(t, []Column{
+		{Name: "id", DataType: TypeInteger, IsPrimaryKey: true},
+		{Name: "post_id", DataType: TypeString},
+		{Name: "body", DataType: TypeString, IsNullable: true},
+		{Name: "posted_at", DataType: TypeTimestamp, IsNullable: true},
+	}, schema.Tables[1].Columns)
+	assert.Contains(t, schema.Relationships, Relationship{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author"})
+	assert.Equal(t, []string{"posts.reviewers: an array of references to User, proposed as JSON; a join table may suit it better"}, schema.Notes)
+}
+
+func TestInferMixedTypes(t *testing.T) {
+	schema, err := Infer([]Collection{{Name: "events", Documents: documents(
+		`{"_id": 1, "value": 1, "at": "2024-01-01", "payload": {"a": 1}, "nothing": null}`,
+		`{"_id": 2, "value": "high", "at": "2024-01-01T10:00:00Z", "payload": 5}`,
+	)}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []Column{
+		{Name: "id", DataType: TypeInteger, IsPrimaryKey: true},
+		{Name: "value", DataType: TypeJSON},
+		{Name: "at", DataType: TypeTimestamp},
+		{Name: "payload", DataType: TypeJSON},
+		{Name: "nothing", DataType: TypeString, IsNullable: true},
+	}, schema.Tables[0].Columns)
+	assert.Equal(t, []string{
+		"events.value: values of types INTEGER, STRING, proposed as JSON",
+		"events.payload: holds both documents and other values, proposed as JSON",
+		"events.nothing: only ever null, proposed as STRING",
+	}, schema.Notes)
+}
+
+func TestInferRejectsNonObjects(t *testing.T) {
+	_, err := Infer([]Collection{{Name: "events", Documents: documents(`[1, 2]`)}})
+	assert.ErrorIs(t, err, ErrNotObject)
+}
+
+func TestSnakeCase(t *testing.T) {
+	for key, expected := range map[string]string{
+		"createdAt":  "created_at",
+		"userID":     "user_id",
+		"HTTPServer": "http_server",
+		"first name": "first_name",
+		"_id":        "id",
+		"__private":  "private",
+		"$$":         "field",
+	} {
+		assert.Equal(t, expected, snakeCase(key), key)
+	}
+}
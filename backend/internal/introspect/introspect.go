@@ -0,0 +1,344 @@
+// Package introspect reads the structure of a live database, so it can be
+// compared to a project's model. It only reads: every query runs in a
+// read-only transaction.
+package introspect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/netguard"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrInvalidDSN is returned for connection strings that are not postgres:// URLs
+// with a host that may be public
+var ErrInvalidDSN = errors.New("invalid connection string")
+
+const connectTimeout = 10 * time.Second
+
+// ValidateDSN checks that dsn is a PostgreSQL URL, the only form accepted so
+// its password can be redacted, and that none of its hosts is a socket, a
+// loopback name or an address that is not public. Other names are checked when
+// dialed.
+func ValidateDSN(dsn string) error {
+	u, err := url.Parse(dsn)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") || u.Hostname() == "" {
+		return ErrInvalidDSN
+	}
+	cfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return ErrInvalidDSN
+	}
+	hosts := []string{cfg.Host}
+	for _, fallback := range cfg.Fallbacks {
+		hosts = append(hosts, fallback.Host)
+	}
+	for _, host := range hosts {
+		if !publicHost(host) {
+			return ErrInvalidDSN
+		}
+	}
+	return nil
+}
+
+// publicHost reports whether host, from a parsed DSN, may name a public address
+func publicHost(host string) bool {
+	if strings.HasPrefix(host, "/") || strings.EqualFold(strings.TrimSuffix(host, "."), "localhost") {
+		return false
+	}
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return netguard.Public(ip)
+	}
+	return true
+}
+
+// ConnConfig parses dsn, once validated, into the configuration every
+// connection to a user's database is made with: it times out and dials public
+// addresses only, so the server's own network cannot be reached through it
+func ConnConfig(dsn string) (*pgx.ConnConfig, error) {
+	if err := ValidateDSN(dsn); err != nil {
+		return nil, err
+	}
+	cfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, ErrInvalidDSN
+	}
+	cfg.ConnectTimeout = connectTimeout
+	dialer := &net.Dialer{Timeout: connectTimeout, KeepAlive: 5 * time.Minute, Control: netguard.Control}
+	cfg.DialFunc = dialer.DialContext
+	return cfg, nil
+}
+
+// RedactDSN returns dsn with its password hidden, whether given with the user
+// or as the password query parameter
+func RedactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return ""
+	}
+	query := u.Query()
+	if query.Has("password") {
+		query.Set("password", "xxxxx")
+		u.RawQuery = query.Encode()
+	}
+	return u.Redacted()
+}
+
+// column is a column of a base table
+type column struct {
+	Table    string
+	Name     string
+	DataType string // information_schema data_type, e.g. "character varying" or "ARRAY"
+	Nullable bool
+	Default  string
+}
+
+// keyColumn is a column of a primary key or unique constraint
+type keyColumn struct {
+	Table      string
+	Constraint string
+	Type       string // "PRIMARY KEY" or "UNIQUE"
+	Column     string
+}
+
+// foreignKey is one column pair of a foreign key constraint
+type foreignKey struct {
+	Table           string
+	Column          string
+	ReferencedTable string
+	ReferencedField string
+	Constraint      string
+}
+
+// Postgres reads the tables, columns, keys and foreign keys of a schema of a
+// PostgreSQL database
+func Postgres(ctx context.Context, dsn, schema string) (schemadiff.Schema, error) {
+	conn, tx, err := begin(ctx, dsn)
+	if err != nil {
+		return schemadiff.Schema{}, err
+	}
+	defer conn.Close(context.Background())
+	defer tx.Rollback(context.Background())
+
+	columns, err := readColumns(ctx, tx, schema)
+	if err != nil {
+		return schemadiff.Schema{}, fmt.Errorf("reading columns: %w", err)
+	}
+	keys, err := readKeyColumns(ctx, tx, schema)
+	if err != nil {
+		return schemadiff.Schema{}, fmt.Errorf("reading keys: %w", err)
+	}
+	foreignKeys, err := readForeignKeys(ctx, tx, schema)
+	if err != nil {
+		return schemadiff.Schema{}, fmt.Errorf("reading foreign keys: %w", err)
+	}
+	return build(columns, keys, foreignKeys), nil
+}
+
+// begin connects to the database and starts a read-only transaction
+func begin(ctx context.Context, dsn string) (*pgx.Conn, pgx.Tx, error) {
+	cfg, err := ConnConfig(dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg.RuntimeParams["default_transaction_read_only"] = "on"
+	cfg.RuntimeParams["application_name"] = "ezmodel-introspect"
+
+	conn, err := pgx.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting: %w", err)
+	}
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		conn.Close(context.Background())
+		return nil, nil, err
+	}
+	return conn, tx, nil
+}
+
+func readColumns(ctx context.Context, tx pgx.Tx, schema string) ([]column, error) {
+	rows, err := tx.Query(ctx, `SELECT c.table_name, c.column_name, c.data_type, c.is_nullable = 'YES', COALESCE(c.column_default, '')
+		FROM information_schema.columns c
+		JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		WHERE c.table_schema = $1 AND t.table_type = 'BASE TABLE'
+		ORDER BY c.table_name, c.ordinal_position`, schema)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (column, error) {
+		var c column
+		err := row.Scan(&c.Table, &c.Name, &c.DataType, &c.Nullable, &c.Default)
+		return c, err
+	})
+}
+
+func readKeyColumns(ctx context.Context, tx pgx.Tx, schema string) ([]keyColumn, error) {
+	rows, err := tx.Query(ctx, `SELECT tc.table_name, tc.constraint_name, tc.constraint_type, kcu.column_name
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.key_column_usage kcu
+			ON kcu.constraint_schema = tc.constraint_schema AND kcu.constraint_name = tc.constraint_name AND kcu.table_name = tc.table_name
+		WHERE tc.table_schema = $1 AND tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE')
+		ORDER BY tc.table_name, tc.constraint_name, kcu.ordinal_position`, schema)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (keyColumn, error) {
+		var k keyColumn
+		err := row.Scan(&k.Table, &k.Constraint, &k.Type, &k.Column)
+		return k, err
+	})
+}
+
+// readForeignKeys reads pg_constraint rather than information_schema, which
+// cannot pair the columns of composite foreign keys reliably
+func readForeignKeys(ctx context.Context, tx pgx.Tx, schema string) ([]foreignKey, error) {
+	rows, err := tx.Query(ctx, `SELECT src.relname, a.attname, ref.relname, ra.attname, c.conname
+		FROM pg_constraint c
+		JOIN pg_class src ON src.oid = c.conrelid
+		JOIN pg_namespace n ON n.oid = src.relnamespace
+		JOIN pg_class ref ON ref.oid = c.confrelid
+		CROSS JOIN LATERAL unnest(c.conkey, c.confkey) AS k(col, ref_col)
+		JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.col
+		JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = k.ref_col
+		WHERE c.contype = 'f' AND n.nspname = $1
+		ORDER BY src.relname, c.conname, a.attname`, schema)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (foreignKey, error) {
+		var fk foreignKey
+		err := row.Scan(&fk.Table, &fk.Column, &fk.ReferencedTable, &fk.ReferencedField, &fk.Constraint)
+		return fk, err
+	})
+}
+
+// build assembles what was read into a schema. A foreign key becomes a
+// relationship from the referenced column to the referencing one, one to one
+// when the referencing column alone is a key of its table.
+func build(columns []column, keys []keyColumn, foreignKeys []foreignKey) schemadiff.Schema {
+	type tableKey struct{ table, column string }
+	primary := make(map[tableKey]bool)
+	unique := make(map[tableKey]bool)
+	constraintColumns := make(map[string][]keyColumn)
+	for _, key := range keys {
+		name := key.Table + "\x00" + key.Constraint
+		constraintColumns[name] = append(constraintColumns[name], key)
+	}
+	for _, columns := range constraintColumns {
+		for _, key := range columns {
+			if key.Type == "PRIMARY KEY" {
+				primary[tableKey{key.Table, key.Column}] = true
+			}
+		}
+		if len(columns) == 1 {
+			unique[tableKey{columns[0].Table, columns[0].Column}] = true
+		}
+	}
+
+	var schema schemadiff.Schema
+	tableIndex := make(map[string]int)
+	for _, c := range columns {
+		i, ok := tableIndex[c.Table]
+		if !ok {
+			i = len(schema.Tables)
+			tableIndex[c.Table] = i
+			schema.Tables = append(schema.Tables, schemadiff.Table{Name: c.Table})
+		}
+		schema.Tables[i].Fields = append(schema.Tables[i].Fields, schemadiff.Field{
+			Name:         c.Name,
+			DataType:     NormalizeType(c.DataType),
+			IsPrimaryKey: primary[tableKey{c.Table, c.Name}],
+			IsNullable:   c.Nullable,
+			DefaultValue: c.Default,
+		})
+	}
+
+	for _, fk := range foreignKeys {
+		relationType := "one_to_many"
+		if unique[tableKey{fk.Table, fk.Column}] {
+			relationType = "one_to_one"
+		}
+		schema.Relationships = append(schema.Relationships, schemadiff.Relationship{
+			SourceTable:  fk.ReferencedTable,
+			SourceField:  fk.ReferencedField,
+			TargetTable:  fk.Table,
+			TargetField:  fk.Column,
+			RelationType: relationType,
+			Constraint:   fk.Constraint,
+		})
+	}
+	sort.SliceStable(schema.Relationships, func(i, j int) bool {
+		return schema.Relationships[i].TargetTable < schema.Relationships[j].TargetTable
+	})
+	return schema
+}
+
+// typeNames maps SQL type names, without length or precision, to the data
+// types of the modeler
+var typeNames = map[string]string{
+	"uuid":                        "UUID",
+	"character varying":           "STRING",
+	"varchar":                     "STRING",
+	"character":                   "STRING",
+	"char":                        "STRING",
+	"bpchar":                      "STRING",
+	"citext":                      "STRING",
+	"string":                      "STRING",
+	"text":                        "TEXT",
+	"smallint":                    "INTEGER",
+	"integer":                     "INTEGER",
+	"int":                         "INTEGER",
+	"int2":                        "INTEGER",
+	"int4":                        "INTEGER",
+	"int8":                        "INTEGER",
+	"bigint":                      "INTEGER",
+	"serial":                      "INTEGER",
+	"bigserial":                   "INTEGER",
+	"smallserial":                 "INTEGER",
+	"boolean":                     "BOOLEAN",
+	"bool":                        "BOOLEAN",
+	"timestamp":                   "TIMESTAMP",
+	"timestamptz":                 "TIMESTAMP",
+	"timestamp without time zone": "TIMESTAMP",
+	"timestamp with time zone":    "TIMESTAMP",
+	"datetime":                    "TIMESTAMP",
+	"date":                        "DATE",
+	"numeric":                     "DECIMAL",
+	"decimal":                     "DECIMAL",
+	"money":                       "DECIMAL",
+	"real":                        "FLOAT",
+	"float":                       "FLOAT",
+	"float4":                      "FLOAT",
+	"float8":                      "FLOAT",
+	"double":                      "FLOAT",
+	"double precision":            "FLOAT",
+	"json":                        "JSON",
+	"jsonb":                       "JSON",
+	"array":                       "JSON", // The modeler has no arrays
+}
+
+// NormalizeType maps a SQL type, as PostgreSQL reports it or as typed in a
+// model, to a data type of the modeler, so equivalent spellings compare equal.
+// Types it does not know are returned upper-cased.
+func NormalizeType(dataType string) string {
+	name := strings.ToLower(strings.TrimSpace(dataType))
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = strings.TrimSpace(name[:i])
+	}
+	if strings.HasSuffix(name, "[]") {
+		name = "array"
+	}
+	if normalized, ok := typeNames[name]; ok {
+		return normalized
+	}
+	return strings.ToUpper(strings.TrimSpace(dataType))
+}
@@ -0,0 +1,117 @@
+package introspect
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TableStats are the statistics PostgreSQL keeps of a table, as refreshed by
+// ANALYZE. They are estimates, read without scanning any table.
+type TableStats struct {
+	Name     string
+	RowCount *int64 // Nil when the table was never analyzed
+	Columns  []ColumnStats
+}
+
+// ColumnStats are the statistics of a column. Only columns the database has
+// statistics of, and that the connecting user may read, are listed.
+type ColumnStats struct {
+	Name          string
+	NullRatio     float64 // Share of rows that are null, 0 to 1
+	DistinctCount int64
+}
+
+// tableRows is a row of pg_class
+type tableRows struct {
+	Table     string
+	RelTuples float64 // -1 when never analyzed, on PostgreSQL 14 and later
+}
+
+// columnStats is a row of pg_stats
+type columnStats struct {
+	Table     string
+	Column    string
+	NullFrac  float64
+	NDistinct float64 // Negative when a share of the rows rather than a count
+}
+
+// PostgresStats reads the planner statistics of the tables of a schema of a
+// PostgreSQL database
+func PostgresStats(ctx context.Context, dsn, schema string) ([]TableStats, error) {
+	conn, tx, err := begin(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close(context.Background())
+	defer tx.Rollback(context.Background())
+
+	rows, err := tx.Query(ctx, `SELECT c.relname, c.reltuples::float8
+		FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p')
+		ORDER BY c.relname`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("reading row counts: %w", err)
+	}
+	tables, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (tableRows, error) {
+		var t tableRows
+		err := row.Scan(&t.Table, &t.RelTuples)
+		return t, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading row counts: %w", err)
+	}
+
+	// Inherited statistics of partitioned tables are left out for those of the table itself
+	rows, err = tx.Query(ctx, `SELECT tablename, attname, null_frac::float8, n_distinct::float8
+		FROM pg_stats WHERE schemaname = $1 AND NOT inherited
+		ORDER BY tablename, attname`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("reading column statistics: %w", err)
+	}
+	columns, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (columnStats, error) {
+		var c columnStats
+		err := row.Scan(&c.Table, &c.Column, &c.NullFrac, &c.NDistinct)
+		return c, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading column statistics: %w", err)
+	}
+	return buildStats(tables, columns), nil
+}
+
+// buildStats assembles the statistics of each table and its columns
+func buildStats(tables []tableRows, columns []columnStats) []TableStats {
+	stats := make([]TableStats, len(tables))
+	index := make(map[string]int, len(tables))
+	for i, table := range tables {
+		index[table.Table] = i
+		stats[i] = TableStats{Name: table.Table}
+		if table.RelTuples >= 0 {
+			rowCount := int64(math.Round(table.RelTuples))
+			stats[i].RowCount = &rowCount
+		}
+	}
+	for _, column := range columns {
+		i, ok := index[column.Table]
+		if !ok {
+			continue
+		}
+		distinct := column.NDistinct
+		if distinct < 0 {
+			rows := 0.0
+			if stats[i].RowCount != nil {
+				rows = float64(*stats[i].RowCount)
+			}
+			distinct = -distinct * rows
+		}
+		stats[i].Columns = append(stats[i].Columns, ColumnStats{
+			Name:          column.Column,
+			NullRatio:     column.NullFrac,
+			DistinctCount: int64(math.Round(distinct)),
+		})
+	}
+	return stats
+}
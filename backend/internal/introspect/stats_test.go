@@ -0,0 +1,28 @@
+package introspect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildStats(t *testing.T) {
+	tables := []tableRows{{Table: "users", RelTuples: 1200}, {Table: "drafts", RelTuples: -1}}
+	columns := []columnStats{
+		{Table: "users", Column: "email", NullFrac: 0, NDistinct: -1},
+		{Table: "users", Column: "country", NullFrac: 0.25, NDistinct: 42},
+		{Table: "drafts", Column: "body", NullFrac: 0.5, NDistinct: -0.5},
+		{Table: "views", Column: "id", NDistinct: -1}, // Not a table of the schema
+	}
+
+	stats := buildStats(tables, columns)
+
+	rowCount := int64(1200)
+	assert.Equal(t, []TableStats{
+		{Name: "users", RowCount: &rowCount, Columns: []ColumnStats{
+			{Name: "email", NullRatio: 0, DistinctCount: 1200},
+			{Name: "country", NullRatio: 0.25, DistinctCount: 42},
+		}},
+		{Name: "drafts", Columns: []ColumnStats{{Name: "body", NullRatio: 0.5, DistinctCount: 0}}},
+	}, stats)
+}
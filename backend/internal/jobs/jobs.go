@@ -0,0 +1,162 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/redis"
+	"github.com/google/uuid"
+)
+
+// defaultMaxAttempts is how often a job runs before it is given up, unless enqueued with MaxAttempts
+const defaultMaxAttempts = 3
+
+// ErrDuplicate is returned for a unique job that was enqueued already
+var ErrDuplicate = errors.New("job already enqueued")
+
+// Job is a unit of slow work run by a worker, possibly in another process
+type Job struct {
+	ID          uuid.UUID       `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempt     int             `json:"attempt"` // Runs so far; a running job counts its own run
+	MaxAttempts int             `json:"max_attempts"`
+	EnqueuedAt  time.Time       `json:"enqueued_at"`
+	LastError   string          `json:"last_error,omitempty"`
+
+	raw string // The job as claimed, which identifies it to the backend until it is done
+}
+
+// Decode unmarshals the payload into v
+func (j *Job) Decode(v any) error {
+	return json.Unmarshal(j.Payload, v)
+}
+
+// LastAttempt reports whether the job is not run again if this run fails
+func (j *Job) LastAttempt() bool {
+	return j.Attempt >= j.MaxAttempts
+}
+
+// Handler runs a job. A job whose handler fails is run again later, until it
+// runs out of attempts. The context ends when the job's lease does.
+type Handler func(ctx context.Context, job *Job) error
+
+// Queue accepts jobs for workers to run
+type Queue interface {
+	Enqueue(ctx context.Context, jobType string, payload any, opts ...Option) (*Job, error)
+}
+
+// Option changes how a job is enqueued
+type Option func(*enqueueOptions)
+
+type enqueueOptions struct {
+	maxAttempts int
+	delay       time.Duration
+	uniqueKey   string
+	uniqueTTL   time.Duration
+}
+
+// MaxAttempts sets how often the job may run before it is given up
+func MaxAttempts(attempts int) Option {
+	return func(o *enqueueOptions) { o.maxAttempts = attempts }
+}
+
+// Delay makes the job due only after the delay
+func Delay(delay time.Duration) Option {
+	return func(o *enqueueOptions) { o.delay = delay }
+}
+
+// Unique enqueues the job only once per key within ttl, e.g. when every node
+// schedules the same job; later attempts fail with ErrDuplicate
+func Unique(key string, ttl time.Duration) Option {
+	return func(o *enqueueOptions) {
+		o.uniqueKey = key
+		o.uniqueTTL = ttl
+	}
+}
+
+// Backend stores jobs until a worker has run them. A claimed job is leased to
+// one worker; when the lease ends before the worker is done, e.g. because its
+// process stopped, Recover makes the job due again.
+type Backend interface {
+	Push(ctx context.Context, job *Job, runAt time.Time) error
+	// Claim leases the next job due by now, or returns nil when none is due
+	Claim(ctx context.Context, now time.Time, lease time.Duration) (*Job, error)
+	// Complete drops a claimed job that ran
+	Complete(ctx context.Context, job *Job) error
+	// Retry queues a claimed job again to run at runAt
+	Retry(ctx context.Context, job *Job, runAt time.Time) error
+	// Bury keeps a claimed job that ran out of attempts aside for inspection
+	Bury(ctx context.Context, job *Job) error
+	// Recover makes the jobs whose lease ended by now due again, returning how many there were
+	Recover(ctx context.Context, now time.Time) (int, error)
+	// Reserve claims a key for ttl, reporting false when it is claimed already
+	Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// New returns a backend in Redis, shared by every node and worker process,
+// when Redis is available and useRedis is set, and a process-local one otherwise
+func New(redisClient *redis.Client, useRedis bool) Backend {
+	if !useRedis || redisClient == nil || !redisClient.IsEnabled() {
+		log.Println("Jobs are queued in memory; they only run in this process and are lost when it stops")
+		return NewMemoryBackend()
+	}
+
+	return NewRedisBackend(redisClient)
+}
+
+// IsShared reports whether jobs in the backend can be run by other processes
+func IsShared(backend Backend) bool {
+	_, ok := backend.(*RedisBackend)
+	return ok
+}
+
+// Client enqueues jobs in a backend
+type Client struct {
+	backend Backend
+	now     func() time.Time
+}
+
+func NewClient(backend Backend) *Client {
+	return &Client{
+		backend: backend,
+		now:     time.Now,
+	}
+}
+
+// Enqueue implements Queue, marshaling the payload to JSON
+func (c *Client) Enqueue(ctx context.Context, jobType string, payload any, opts ...Option) (*Job, error) {
+	options := enqueueOptions{maxAttempts: defaultMaxAttempts}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if options.uniqueKey != "" {
+		reserved, err := c.backend.Reserve(ctx, options.uniqueKey, options.uniqueTTL)
+		if err != nil {
+			return nil, err
+		}
+		if !reserved {
+			return nil, ErrDuplicate
+		}
+	}
+	now := c.now()
+	job := &Job{
+		ID:          uuid.New(),
+		Type:        jobType,
+		Payload:     data,
+		MaxAttempts: max(options.maxAttempts, 1),
+		EnqueuedAt:  now,
+	}
+	if err := c.backend.Push(ctx, job, now.Add(options.delay)); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
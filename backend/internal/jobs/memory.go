@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type memoryEntry struct {
+	job   Job
+	runAt time.Time // When it is due, or when its lease ends once claimed
+}
+
+// MemoryBackend keeps jobs in process memory, for single-node setups without
+// Redis. Jobs are lost when the process stops.
+type MemoryBackend struct {
+	mu     sync.Mutex
+	queue  []*memoryEntry // Sorted by when they are due
+	active map[uuid.UUID]*memoryEntry
+	dead   []Job                // Newest first
+	unique map[string]time.Time // Reserved keys by when they expire
+	now    func() time.Time
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		active: make(map[uuid.UUID]*memoryEntry),
+		unique: make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+func (b *MemoryBackend) Push(ctx context.Context, job *Job, runAt time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.push(&memoryEntry{job: *job, runAt: runAt})
+	return nil
+}
+
+func (b *MemoryBackend) Claim(ctx context.Context, now time.Time, lease time.Duration) (*Job, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.queue) == 0 || b.queue[0].runAt.After(now) {
+		return nil, nil
+	}
+	entry := b.queue[0]
+	b.queue = b.queue[1:]
+	entry.runAt = now.Add(lease)
+	b.active[entry.job.ID] = entry
+
+	job := entry.job
+	return &job, nil
+}
+
+func (b *MemoryBackend) Complete(ctx context.Context, job *Job) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.active, job.ID)
+	return nil
+}
+
+func (b *MemoryBackend) Retry(ctx context.Context, job *Job, runAt time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.active, job.ID)
+	b.push(&memoryEntry{job: *job, runAt: runAt})
+	return nil
+}
+
+func (b *MemoryBackend) Bury(ctx context.Context, job *Job) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.active, job.ID)
+	b.dead = append([]Job{*job}, b.dead...)
+	if len(b.dead) > maxDeadJobs {
+		b.dead = b.dead[:maxDeadJobs]
+	}
+	return nil
+}
+
+func (b *MemoryBackend) Recover(ctx context.Context, now time.Time) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	recovered := 0
+	for id, entry := range b.active {
+		if entry.runAt.After(now) {
+			continue
+		}
+		delete(b.active, id)
+		entry.runAt = now
+		b.push(entry)
+		recovered++
+	}
+	return recovered, nil
+}
+
+func (b *MemoryBackend) Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	for reserved, expiresAt := range b.unique {
+		if !expiresAt.After(now) {
+			delete(b.unique, reserved)
+		}
+	}
+	if _, ok := b.unique[key]; ok {
+		return false, nil
+	}
+	b.unique[key] = now.Add(ttl)
+	return true, nil
+}
+
+// push inserts an entry after those due no later than it
+func (b *MemoryBackend) push(entry *memoryEntry) {
+	i := sort.Search(len(b.queue), func(i int) bool { return b.queue[i].runAt.After(entry.runAt) })
+	b.queue = append(b.queue, nil)
+	copy(b.queue[i+1:], b.queue[i:])
+	b.queue[i] = entry
+}
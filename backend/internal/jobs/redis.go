@@ -0,0 +1,83 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/redis"
+)
+
+// Keys of the jobs in Redis: due and scheduled jobs sorted by when they are
+// due, claimed ones by the end of their lease, and the failed ones
+const (
+	queueKey  = "jobs:queue"
+	activeKey = "jobs:active"
+	deadKey   = "jobs:dead"
+	// uniqueKeyPrefix prefixes the keys reserved by unique jobs
+	uniqueKeyPrefix = "jobs:unique:"
+)
+
+// maxDeadJobs bounds how many failed jobs are kept for inspection
+const maxDeadJobs = 1000
+
+// RedisBackend keeps jobs in Redis, so they survive restarts and any node or
+// worker process can run them
+type RedisBackend struct {
+	client *redis.Client
+}
+
+func NewRedisBackend(client *redis.Client) *RedisBackend {
+	return &RedisBackend{client: client}
+}
+
+func (b *RedisBackend) Push(ctx context.Context, job *Job, runAt time.Time) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return b.client.EnqueueJob(queueKey, string(data), runAt)
+}
+
+func (b *RedisBackend) Claim(ctx context.Context, now time.Time, lease time.Duration) (*Job, error) {
+	raw, err := b.client.ClaimJob(queueKey, activeKey, now, now.Add(lease))
+	if err != nil || raw == "" {
+		return nil, err
+	}
+
+	var job Job
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		// Drop what cannot be run rather than claiming it forever
+		return nil, b.client.CompleteJob(activeKey, raw)
+	}
+	job.raw = raw
+	return &job, nil
+}
+
+func (b *RedisBackend) Complete(ctx context.Context, job *Job) error {
+	return b.client.CompleteJob(activeKey, job.raw)
+}
+
+func (b *RedisBackend) Retry(ctx context.Context, job *Job, runAt time.Time) error {
+	next, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return b.client.RequeueJob(queueKey, activeKey, job.raw, string(next), runAt)
+}
+
+func (b *RedisBackend) Bury(ctx context.Context, job *Job) error {
+	final, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return b.client.BuryJob(activeKey, deadKey, job.raw, string(final), maxDeadJobs)
+}
+
+func (b *RedisBackend) Recover(ctx context.Context, now time.Time) (int, error) {
+	return b.client.RecoverJobs(queueKey, activeKey, now)
+}
+
+func (b *RedisBackend) Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return b.client.SetNX(uniqueKeyPrefix+key, []byte("1"), ttl)
+}
@@ -0,0 +1,93 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// schedulerInterval is how often the scheduler checks for due runs
+const schedulerInterval = time.Minute
+
+// scheduledRun is the payload of a scheduled job: the time it was due
+type scheduledRun struct {
+	DueAt time.Time `json:"due_at"`
+}
+
+type dailyEntry struct {
+	jobType string
+	hour    int // UTC
+}
+
+// Scheduler enqueues jobs at fixed times. Every process may run one: each run
+// is enqueued once, through a unique key. A run missed while no scheduler was
+// running is enqueued when one starts.
+type Scheduler struct {
+	queue   Queue
+	entries []dailyEntry
+	now     func() time.Time
+
+	stop    chan struct{}
+	stopped chan struct{}
+	running atomic.Bool
+}
+
+func NewScheduler(queue Queue) *Scheduler {
+	return &Scheduler{
+		queue:   queue,
+		now:     time.Now,
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Daily enqueues a job of the type every day at the hour, in UTC. Call it before Run.
+func (s *Scheduler) Daily(jobType string, hour int) {
+	s.entries = append(s.entries, dailyEntry{jobType: jobType, hour: hour})
+}
+
+// Run enqueues due jobs until Stop is called
+func (s *Scheduler) Run() {
+	s.running.Store(true)
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		s.EnqueueDue(context.Background())
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop ends Run, if it was started
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	if s.running.Load() {
+		<-s.stopped
+	}
+}
+
+// EnqueueDue enqueues the latest run of each job that is due, unless it was enqueued already
+func (s *Scheduler) EnqueueDue(ctx context.Context) {
+	now := s.now().UTC()
+	for _, entry := range s.entries {
+		dueAt := time.Date(now.Year(), now.Month(), now.Day(), entry.hour, 0, 0, 0, time.UTC)
+		if dueAt.After(now) {
+			dueAt = dueAt.AddDate(0, 0, -1)
+		}
+
+		// The key outlives the day, so a run is never enqueued twice
+		key := entry.jobType + ":" + dueAt.Format(time.RFC3339)
+		_, err := s.queue.Enqueue(ctx, entry.jobType, scheduledRun{DueAt: dueAt}, Unique(key, 48*time.Hour))
+		if err != nil && !errors.Is(err, ErrDuplicate) {
+			log.Printf("Failed to schedule %s job: %v", entry.jobType, err)
+		}
+	}
+}
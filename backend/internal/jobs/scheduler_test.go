@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerEnqueuesEachRunOnce(t *testing.T) {
+	now := time.Date(2026, 3, 15, 1, 30, 0, 0, time.UTC)
+	worker, client, _ := newTestWorker(&now)
+	var due []time.Time
+	worker.Register("nightly", func(ctx context.Context, job *Job) error {
+		var run scheduledRun
+		require.NoError(t, job.Decode(&run))
+		due = append(due, run.DueAt)
+		return nil
+	})
+
+	// Two processes scheduling the same job
+	schedulers := []*Scheduler{NewScheduler(client), NewScheduler(client)}
+	for _, scheduler := range schedulers {
+		scheduler.now = func() time.Time { return now }
+		scheduler.Daily("nightly", 2)
+	}
+	runAll := func() {
+		for _, scheduler := range schedulers {
+			scheduler.EnqueueDue(context.Background())
+		}
+		for {
+			ok, err := worker.RunNext(context.Background())
+			require.NoError(t, err)
+			if !ok {
+				return
+			}
+		}
+	}
+
+	// Before 02:00 the run of the day before is due, as when a process starts after a missed run
+	runAll()
+	now = now.Add(time.Minute)
+	runAll()
+	now = time.Date(2026, 3, 15, 2, 0, 0, 0, time.UTC)
+	runAll()
+
+	assert.Equal(t, []time.Time{
+		time.Date(2026, 3, 14, 2, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 15, 2, 0, 0, 0, time.UTC),
+	}, due)
+}
@@ -0,0 +1,181 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+)
+
+const (
+	// retryBaseDelay is how long a failed job waits before its second run; each later run waits twice as long
+	retryBaseDelay = 10 * time.Second
+	// maxRetryDelay bounds the wait between two runs of a job
+	maxRetryDelay = 10 * time.Minute
+)
+
+// errUnknownJobType is recorded on jobs no handler is registered for
+var errUnknownJobType = errors.New("no handler for job type")
+
+// Worker claims due jobs from a backend and runs them with the handler
+// registered for their type, a number of them at once
+type Worker struct {
+	backend      Backend
+	handlers     map[string]Handler
+	concurrency  int
+	pollInterval time.Duration
+	lease        time.Duration
+	now          func() time.Time
+
+	stop    chan struct{}
+	cancel  context.CancelFunc // Ends the jobs still running when Stop gives up waiting
+	running sync.WaitGroup
+}
+
+func NewWorker(backend Backend, concurrency int, pollInterval, lease time.Duration) *Worker {
+	return &Worker{
+		backend:      backend,
+		handlers:     make(map[string]Handler),
+		concurrency:  max(concurrency, 1),
+		pollInterval: pollInterval,
+		lease:        lease,
+		now:          time.Now,
+		stop:         make(chan struct{}),
+	}
+}
+
+// Register sets the handler of a job type. Call it before Run.
+func (w *Worker) Register(jobType string, handler Handler) {
+	w.handlers[jobType] = handler
+}
+
+// Run claims and runs jobs until Stop is called. It returns at once.
+func (w *Worker) Run() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+
+	for range w.concurrency {
+		w.running.Add(1)
+		go func() {
+			defer w.running.Done()
+			w.loop(ctx)
+		}()
+	}
+
+	// Jobs of workers that stopped mid-run become due again once their lease ends
+	w.running.Add(1)
+	go func() {
+		defer w.running.Done()
+		ticker := time.NewTicker(w.lease / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				if recovered, err := w.backend.Recover(ctx, w.now()); err != nil {
+					log.Printf("Failed to recover abandoned jobs: %v", err)
+				} else if recovered > 0 {
+					log.Printf("Recovered %d abandoned jobs", recovered)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops claiming jobs and waits for the running ones to finish. Jobs
+// still running when ctx ends are canceled and run again after their lease.
+func (w *Worker) Stop(ctx context.Context) {
+	close(w.stop)
+
+	done := make(chan struct{})
+	go func() {
+		w.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if w.cancel != nil {
+			w.cancel()
+		}
+		<-done
+	}
+}
+
+func (w *Worker) loop(ctx context.Context) {
+	for {
+		ran, err := w.RunNext(ctx)
+		if err != nil {
+			log.Printf("Failed to run jobs: %v", err)
+		}
+		if ran && err == nil {
+			select {
+			case <-w.stop:
+				return
+			default:
+				continue // More jobs may be due
+			}
+		}
+		select {
+		case <-w.stop:
+			return
+		case <-time.After(w.pollInterval):
+		}
+	}
+}
+
+// RunNext claims the next due job and runs it, reporting whether there was one
+func (w *Worker) RunNext(ctx context.Context) (bool, error) {
+	job, err := w.backend.Claim(ctx, w.now(), w.lease)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	job.Attempt++
+	if err := w.run(ctx, job); err != nil {
+		job.LastError = err.Error()
+		if job.LastAttempt() || errors.Is(err, errUnknownJobType) {
+			log.Printf("Job %s of type %s failed for good after %d attempts: %v", job.ID, job.Type, job.Attempt, err)
+			errorreport.Capture(ctx, fmt.Errorf("job %s: %w", job.Type, err))
+			return true, w.backend.Bury(ctx, job)
+		}
+		log.Printf("Job %s of type %s failed, retrying: %v", job.ID, job.Type, err)
+		return true, w.backend.Retry(ctx, job, w.now().Add(retryDelay(job.Attempt)))
+	}
+	return true, w.backend.Complete(ctx, job)
+}
+
+// run calls the handler of the job, turning a panic into an error
+func (w *Worker) run(ctx context.Context, job *Job) (err error) {
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("%w %q", errUnknownJobType, job.Type)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.lease)
+	defer cancel()
+	defer func() {
+		if value := recover(); value != nil {
+			errorreport.CapturePanic(ctx, value)
+			err = fmt.Errorf("panic: %v", value)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// retryDelay is how long a job waits after its attempt-th run failed
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for range attempt - 1 {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,151 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWorker(now *time.Time) (*Worker, *Client, *MemoryBackend) {
+	backend := NewMemoryBackend()
+	client := NewClient(backend)
+	client.now = func() time.Time { return *now }
+	worker := NewWorker(backend, 1, time.Second, time.Minute)
+	worker.now = func() time.Time { return *now }
+	return worker, client, backend
+}
+
+func TestWorkerRunsDueJobs(t *testing.T) {
+	now := time.Now()
+	worker, client, backend := newTestWorker(&now)
+	var ran []string
+	worker.Register("greet", func(ctx context.Context, job *Job) error {
+		var payload struct{ Name string }
+		require.NoError(t, job.Decode(&payload))
+		ran = append(ran, payload.Name)
+		return nil
+	})
+
+	_, err := client.Enqueue(context.Background(), "greet", map[string]string{"name": "later"}, Delay(time.Hour))
+	require.NoError(t, err)
+	_, err = client.Enqueue(context.Background(), "greet", map[string]string{"name": "now"})
+	require.NoError(t, err)
+
+	ok, err := worker.RunNext(context.Background())
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	ok, _ = worker.RunNext(context.Background())
+	assert.False(t, ok, "the delayed job is not due yet")
+	assert.Equal(t, []string{"now"}, ran)
+
+	now = now.Add(time.Hour)
+	ok, _ = worker.RunNext(context.Background())
+	assert.True(t, ok)
+	assert.Equal(t, []string{"now", "later"}, ran)
+	assert.Empty(t, backend.active)
+}
+
+func TestWorkerRetriesThenBuries(t *testing.T) {
+	now := time.Now()
+	worker, client, backend := newTestWorker(&now)
+	attempts := 0
+	worker.Register("flaky", func(ctx context.Context, job *Job) error {
+		attempts++
+		return errors.New("unavailable")
+	})
+	_, err := client.Enqueue(context.Background(), "flaky", nil, MaxAttempts(2))
+	require.NoError(t, err)
+
+	ok, err := worker.RunNext(context.Background())
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	ok, _ = worker.RunNext(context.Background())
+	assert.False(t, ok, "the retry waits for its delay")
+
+	now = now.Add(retryDelay(1))
+	ok, _ = worker.RunNext(context.Background())
+	assert.True(t, ok)
+	assert.Equal(t, 2, attempts)
+	require.Len(t, backend.dead, 1)
+	assert.Equal(t, "unavailable", backend.dead[0].LastError)
+	assert.Empty(t, backend.queue)
+}
+
+func TestWorkerTurnsPanicsIntoFailures(t *testing.T) {
+	now := time.Now()
+	worker, client, backend := newTestWorker(&now)
+	worker.Register("broken", func(ctx context.Context, job *Job) error {
+		panic("nil map")
+	})
+	_, err := client.Enqueue(context.Background(), "broken", nil, MaxAttempts(1))
+	require.NoError(t, err)
+
+	ok, err := worker.RunNext(context.Background())
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	require.Len(t, backend.dead, 1)
+	assert.Equal(t, "panic: nil map", backend.dead[0].LastError)
+}
+
+func TestWorkerBuriesUnknownTypes(t *testing.T) {
+	now := time.Now()
+	worker, client, backend := newTestWorker(&now)
+	_, err := client.Enqueue(context.Background(), "unknown", nil)
+	require.NoError(t, err)
+
+	worker.RunNext(context.Background())
+
+	assert.Len(t, backend.dead, 1)
+	assert.Empty(t, backend.queue)
+}
+
+func TestMemoryBackendRecoversExpiredLeases(t *testing.T) {
+	now := time.Now()
+	backend := NewMemoryBackend()
+	require.NoError(t, backend.Push(context.Background(), &Job{Type: "export"}, now))
+
+	job, err := backend.Claim(context.Background(), now, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+
+	recovered, _ := backend.Recover(context.Background(), now.Add(30*time.Second))
+	assert.Equal(t, 0, recovered)
+	recovered, _ = backend.Recover(context.Background(), now.Add(time.Minute))
+	assert.Equal(t, 1, recovered)
+
+	again, _ := backend.Claim(context.Background(), now.Add(time.Minute), time.Minute)
+	require.NotNil(t, again)
+	assert.Equal(t, job.ID, again.ID)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, retryBaseDelay, retryDelay(1))
+	assert.Equal(t, 4*retryBaseDelay, retryDelay(3))
+	assert.Equal(t, maxRetryDelay, retryDelay(20))
+}
+
+func TestWorkerStopWaitsForRunningJobs(t *testing.T) {
+	backend := NewMemoryBackend()
+	worker := NewWorker(backend, 2, 10*time.Millisecond, time.Minute)
+	started := make(chan struct{})
+	finished := false
+	worker.Register("slow", func(ctx context.Context, job *Job) error {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		finished = true
+		return nil
+	})
+	_, err := NewClient(backend).Enqueue(context.Background(), "slow", nil)
+	require.NoError(t, err)
+
+	worker.Run()
+	<-started
+	worker.Stop(context.Background())
+
+	assert.True(t, finished)
+	assert.Empty(t, backend.active)
+}
@@ -0,0 +1,268 @@
+// Package lint checks a schema for likely design problems, deterministically,
+// from the names, types and keys of its fields. Each finding names the rule
+// it breaks, the table and fields concerned and explains what to change.
+// Findings are hints: a heuristic cannot know what the data means.
+package lint
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/Bug-Bugger/ezmodel/internal/introspect"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+)
+
+// Rules
+const (
+	RuleRepeatingGroup       = "1nf-repeating-group"       // Numbered fields holding the same kind of value, e.g. phone1, phone2
+	RuleMultiValuedField     = "1nf-multi-valued-field"    // A text field named for a list, e.g. tag_ids
+	RulePartialDependency    = "2nf-partial-dependency"    // A field describing part of a composite key
+	RuleTransitiveDependency = "3nf-transitive-dependency" // Fields describing what a foreign key refers to
+)
+
+// Finding is a likely problem of a table
+type Finding struct {
+	Rule        string
+	Table       string
+	Fields      []string // In the order of the table
+	Message     string   // What was found
+	Explanation string   // Why it is a problem and how to fix it
+}
+
+// Check runs every rule on every table of schema, returning the findings by
+// table in the order of the schema, then by rule
+func Check(schema schemadiff.Schema) []Finding {
+	findings := []Finding{}
+	for _, table := range schema.Tables {
+		findings = append(findings, repeatingGroups(table)...)
+		findings = append(findings, multiValuedFields(table)...)
+		findings = append(findings, partialDependencies(table)...)
+		findings = append(findings, transitiveDependencies(table)...)
+	}
+	return findings
+}
+
+// repeatingGroups finds fields named alike but for a number, e.g. phone1 and
+// phone_2, which hold one value of a list each
+func repeatingGroups(table schemadiff.Table) []Finding {
+	groups := make(map[string][]string)
+	var stems []string
+	for _, field := range table.Fields {
+		stem := strings.TrimRight(field.Name, "0123456789")
+		if stem == field.Name {
+			continue
+		}
+		stem = strings.ToLower(strings.TrimRight(stem, "_- "))
+		if stem == "" {
+			continue
+		}
+		if _, ok := groups[stem]; !ok {
+			stems = append(stems, stem)
+		}
+		groups[stem] = append(groups[stem], field.Name)
+	}
+
+	var findings []Finding
+	for _, stem := range stems {
+		fields := groups[stem]
+		if len(fields) < 2 {
+			continue
+		}
+		findings = append(findings, Finding{
+			Rule:    RuleRepeatingGroup,
+			Table:   table.Name,
+			Fields:  fields,
+			Message: strings.Join(fields, ", ") + " look like a repeating group of " + stem,
+			Explanation: "Numbered fields cap how many values a row can have and make every query look in each of them. " +
+				"Move " + stem + " to a table of its own, a row per value, with a foreign key to " + table.Name + ".",
+		})
+	}
+	return findings
+}
+
+// listWords end the names of fields holding several values
+var listWords = map[string]bool{"ids": true, "list": true, "csv": true, "array": true}
+
+// multiValuedFields finds text fields named for lists, e.g. tag_ids or
+// emailList, whose values are likely separated by commas
+func multiValuedFields(table schemadiff.Table) []Finding {
+	var findings []Finding
+	for _, field := range table.Fields {
+		if kind := introspect.NormalizeType(field.DataType); kind != "STRING" && kind != "TEXT" {
+			continue
+		}
+		w := words(field.Name)
+		if len(w) < 2 || !listWords[w[len(w)-1]] {
+			continue
+		}
+		findings = append(findings, Finding{
+			Rule:    RuleMultiValuedField,
+			Table:   table.Name,
+			Fields:  []string{field.Name},
+			Message: field.Name + " looks like a list stored as " + field.DataType,
+			Explanation: "Several values in one field cannot be constrained, indexed or joined on, and must be parsed by every reader. " +
+				"Store them in a table of their own, a row per value, with a foreign key to " + table.Name + ".",
+		})
+	}
+	return findings
+}
+
+// partialDependencies finds, in tables keyed by several fields, fields named
+// after one key field only, e.g. product_name in a table keyed by order_id
+// and product_id, which depend on part of the key
+func partialDependencies(table schemadiff.Table) []Finding {
+	var keys []schemadiff.Field
+	for _, field := range table.Fields {
+		if field.IsPrimaryKey {
+			keys = append(keys, field)
+		}
+	}
+	if len(keys) < 2 {
+		return nil
+	}
+
+	var findings []Finding
+	for _, key := range keys {
+		subject, ok := subjectOf(key.Name)
+		if !ok {
+			continue
+		}
+		fields := describing(table, subject)
+		if len(fields) == 0 {
+			continue
+		}
+		findings = append(findings, Finding{
+			Rule:    RulePartialDependency,
+			Table:   table.Name,
+			Fields:  fields,
+			Message: strings.Join(fields, ", ") + seem(fields) + " to depend on " + key.Name + " alone, part of the primary key",
+			Explanation: "A field that depends on part of a composite key is repeated for every row sharing that part, and can disagree between them. " +
+				"Move it to the table " + key.Name + " refers to, or to a new one keyed by " + key.Name + ".",
+		})
+	}
+	return findings
+}
+
+// transitiveDependencies finds fields named after a foreign key outside the
+// primary key, e.g. customer_name next to customer_id, which depend on the
+// row it refers to rather than on the key of the table
+func transitiveDependencies(table schemadiff.Table) []Finding {
+	own := words(table.Name)
+	var findings []Finding
+	for _, field := range table.Fields {
+		if field.IsPrimaryKey {
+			continue
+		}
+		subject, ok := subjectOf(field.Name)
+		if !ok || singular(strings.Join(subject, "_")) == singular(strings.Join(own, "_")) {
+			continue
+		}
+		fields := describing(table, subject)
+		if len(fields) == 0 {
+			continue
+		}
+		findings = append(findings, Finding{
+			Rule:    RuleTransitiveDependency,
+			Table:   table.Name,
+			Fields:  fields,
+			Message: strings.Join(fields, ", ") + seem(fields) + " to depend on " + field.Name + " rather than on the primary key",
+			Explanation: "Copies of what a foreign key refers to go stale when the referenced row changes. " +
+				"Keep " + field.Name + " and read the others from the table it refers to, or move them to a new table keyed by " + field.Name + ".",
+		})
+	}
+	return findings
+}
+
+// subjectOf returns the words a key field is named after, e.g. order_item
+// for order_item_id or orderItemId
+func subjectOf(name string) ([]string, bool) {
+	w := words(name)
+	if len(w) < 2 || (w[len(w)-1] != "id" && w[len(w)-1] != "fk") {
+		return nil, false
+	}
+	return w[:len(w)-1], true
+}
+
+// describing returns the fields of table, other than keys, whose names start
+// with subject, e.g. product_name and productPrice for product
+func describing(table schemadiff.Table, subject []string) []string {
+	var fields []string
+	for _, field := range table.Fields {
+		if field.IsPrimaryKey {
+			continue
+		}
+		w := words(field.Name)
+		if len(w) <= len(subject) || !hasPrefix(w, subject) {
+			continue
+		}
+		if _, ok := subjectOf(field.Name); ok {
+			continue // Another key, e.g. product_variant_id
+		}
+		fields = append(fields, field.Name)
+	}
+	return fields
+}
+
+// seem agrees with fields
+func seem(fields []string) string {
+	if len(fields) == 1 {
+		return " seems"
+	}
+	return " seem"
+}
+
+// words splits a name into lower-case words at separators and at the humps
+// of camelCase, e.g. orderItemID order, item, id
+func words(name string) []string {
+	var w []string
+	var current []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				w = append(w, strings.ToLower(string(current)))
+				current = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextLower) {
+				w = append(w, strings.ToLower(string(current)))
+				current = nil
+			}
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		w = append(w, strings.ToLower(string(current)))
+	}
+	return w
+}
+
+func hasPrefix(w, prefix []string) bool {
+	if len(w) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if w[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// singular strips the plural of a name, e.g. orders or categories
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "ss"):
+		return name
+	default:
+		return strings.TrimSuffix(name, "s")
+	}
+}
@@ -0,0 +1,72 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	schema := schemadiff.Schema{Tables: []schemadiff.Table{
+		{Name: "customers", Fields: []schemadiff.Field{
+			{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+			{Name: "phone1", DataType: "VARCHAR(20)"},
+			{Name: "phone_2", DataType: "VARCHAR(20)"},
+			{Name: "tagIds", DataType: "TEXT"},
+			{Name: "role_ids", DataType: "UUID[]"}, // An array, not a list in text
+		}},
+		{Name: "order_items", Fields: []schemadiff.Field{
+			{Name: "order_id", DataType: "UUID", IsPrimaryKey: true},
+			{Name: "product_id", DataType: "UUID", IsPrimaryKey: true},
+			{Name: "productName", DataType: "TEXT"},
+			{Name: "quantity", DataType: "INT"},
+		}},
+		{Name: "orders", Fields: []schemadiff.Field{
+			{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+			{Name: "customer_id", DataType: "UUID"},
+			{Name: "customer_name", DataType: "TEXT"},
+			{Name: "customer_email", DataType: "TEXT"},
+			{Name: "order_number", DataType: "TEXT"}, // Named after the table itself
+		}},
+	}}
+
+	findings := Check(schema)
+
+	require.Len(t, findings, 4)
+	assert.Equal(t, RuleRepeatingGroup, findings[0].Rule)
+	assert.Equal(t, []string{"phone1", "phone_2"}, findings[0].Fields)
+	assert.Equal(t, "phone1, phone_2 look like a repeating group of phone", findings[0].Message)
+	assert.Equal(t, RuleMultiValuedField, findings[1].Rule)
+	assert.Equal(t, []string{"tagIds"}, findings[1].Fields)
+	assert.Equal(t, Finding{
+		Rule:        RulePartialDependency,
+		Table:       "order_items",
+		Fields:      []string{"productName"},
+		Message:     "productName seems to depend on product_id alone, part of the primary key",
+		Explanation: findings[2].Explanation,
+	}, findings[2])
+	assert.Equal(t, RuleTransitiveDependency, findings[3].Rule)
+	assert.Equal(t, "orders", findings[3].Table)
+	assert.Equal(t, []string{"customer_name", "customer_email"}, findings[3].Fields)
+}
+
+func TestCheck_Clean(t *testing.T) {
+	schema := schemadiff.Schema{Tables: []schemadiff.Table{
+		{Name: "users", Fields: []schemadiff.Field{
+			{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+			{Name: "sha256", DataType: "TEXT"},
+			{Name: "team_id", DataType: "UUID"},
+			{Name: "team_role_id", DataType: "UUID"},
+		}},
+	}}
+
+	assert.Empty(t, Check(schema))
+}
+
+func TestWords(t *testing.T) {
+	assert.Equal(t, []string{"order", "item", "id"}, words("orderItemID"))
+	assert.Equal(t, []string{"http", "status", "2"}, words("HTTPStatus_2"))
+	assert.Equal(t, []string{"placed", "at"}, words("Placed At"))
+}
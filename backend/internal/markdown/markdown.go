@@ -0,0 +1,28 @@
+// Package markdown renders the Markdown documents kept with projects as
+// HTML, with the GitHub extensions: tables, strikethrough, autolinks and
+// task lists. Raw HTML in a document is left out and links to dangerous
+// schemes such as javascript: are dropped, so the HTML is safe to show.
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+)
+
+// renderer is safe for concurrent use
+var renderer = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()), // Headings can be linked to
+)
+
+// Render converts a Markdown document to an HTML fragment
+func Render(source string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
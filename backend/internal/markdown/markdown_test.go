@@ -0,0 +1,39 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	html, err := Render("# Why orders\n\nOrders are ~~never~~ *rarely* deleted.\n\n| Table | Owner |\n| --- | --- |\n| orders | sales |\n")
+
+	require.NoError(t, err)
+	assert.Equal(t, `<h1 id="why-orders">Why orders</h1>
+<p>Orders are <del>never</del> <em>rarely</em> deleted.</p>
+<table>
+<thead>
+<tr>
+<th>Table</th>
+<th>Owner</th>
+</tr>
+</thead>
+<tbody>
+<tr>
+<td>orders</td>
+<td>sales</td>
+</tr>
+</tbody>
+</table>
+`, string(html))
+}
+
+func TestRender_Unsafe(t *testing.T) {
+	html, err := Render("<script>alert(1)</script>\n\n[click](javascript:alert(1))\n")
+
+	require.NoError(t, err)
+	assert.NotContains(t, string(html), "<script>")
+	assert.NotContains(t, string(html), "javascript:")
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+// queryStartKey holds the start time of a statement in its gorm instance
+const queryStartKey = "ezmodel:metrics_query_start"
+
+// GormPlugin records the duration of every GORM statement by operation and
+// table, and logs and counts the statements slower than slowThreshold
+type GormPlugin struct {
+	duration      *prometheus.HistogramVec
+	slow          *prometheus.CounterVec
+	slowThreshold time.Duration // Zero disables the slow query log
+}
+
+// RegisterDB instruments the queries made through db and exports the stats of
+// its primary connection pool, e.g. open and in-use connections. Statements
+// taking at least slowThreshold are logged without their bound parameters.
+func RegisterDB(registry prometheus.Registerer, db *gorm.DB, slowThreshold time.Duration) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	plugin := &GormPlugin{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "ezmodel_db_query_duration_seconds",
+			Help:    "Duration of database statements by operation and table.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+		slow: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ezmodel_db_slow_queries_total",
+			Help: "Database statements slower than the slow query threshold by operation and table.",
+		}, []string{"operation", "table"}),
+		slowThreshold: slowThreshold,
+	}
+	if err := registry.Register(plugin.duration); err != nil {
+		return err
+	}
+	if err := registry.Register(plugin.slow); err != nil {
+		return err
+	}
+	if err := registry.Register(collectors.NewDBStatsCollector(sqlDB, "ezmodel")); err != nil {
+		return err
+	}
+	return db.Use(plugin)
+}
+
+// Name implements gorm.Plugin
+func (p *GormPlugin) Name() string {
+	return "ezmodel:metrics"
+}
+
+// Initialize implements gorm.Plugin by timing each kind of statement
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	processors := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callback.Create().Before("gorm:create").Register, callback.Create().After("gorm:create").Register},
+		{"query", callback.Query().Before("gorm:query").Register, callback.Query().After("gorm:query").Register},
+		{"update", callback.Update().Before("gorm:update").Register, callback.Update().After("gorm:update").Register},
+		{"delete", callback.Delete().Before("gorm:delete").Register, callback.Delete().After("gorm:delete").Register},
+		{"row", callback.Row().Before("gorm:row").Register, callback.Row().After("gorm:row").Register},
+		{"raw", callback.Raw().Before("gorm:raw").Register, callback.Raw().After("gorm:raw").Register},
+	}
+
+	for _, processor := range processors {
+		if err := processor.before("ezmodel:metrics_before_"+processor.operation, p.start); err != nil {
+			return err
+		}
+		if err := processor.after("ezmodel:metrics_after_"+processor.operation, p.observe(processor.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *GormPlugin) start(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func (p *GormPlugin) observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(start)
+		p.duration.WithLabelValues(operation, db.Statement.Table).Observe(elapsed.Seconds())
+		if p.slowThreshold > 0 && elapsed >= p.slowThreshold {
+			p.slow.WithLabelValues(operation, db.Statement.Table).Inc()
+			// The SQL of the statement holds placeholders, its values are in Vars
+			log.Printf("Slow query (%s, %s on %q, %d rows): %s",
+				elapsed, operation, db.Statement.Table, db.RowsAffected, db.Statement.SQL.String())
+		}
+	}
+}
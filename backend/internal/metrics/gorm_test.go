@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type account struct {
+	ID    int
+	Email string
+}
+
+// newDryRunDB builds statements without a database server to run them
+func newDryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.Open("postgres://test@127.0.0.1:1/test"), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestRegisterDB_LogsSlowQueriesWithoutParameters(t *testing.T) {
+	var output bytes.Buffer
+	log.SetOutput(&output)
+	defer log.SetOutput(os.Stderr)
+
+	registry := prometheus.NewRegistry()
+	db := newDryRunDB(t)
+	require.NoError(t, RegisterDB(registry, db, time.Nanosecond))
+
+	var found account
+	db.Where("email = ?", "alice@example.com").Find(&found)
+
+	count, err := testutil.GatherAndCount(registry, "ezmodel_db_slow_queries_total")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Contains(t, output.String(), "email = $1")
+	assert.NotContains(t, output.String(), "alice@example.com")
+}
+
+func TestRegisterDB_SlowQueryLogDisabled(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	db := newDryRunDB(t)
+	require.NoError(t, RegisterDB(registry, db, 0))
+
+	var found account
+	db.Where("email = ?", "alice@example.com").Find(&found)
+
+	count, err := testutil.GatherAndCount(registry, "ezmodel_db_slow_queries_total")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	count, err = testutil.GatherAndCount(registry, "ezmodel_db_query_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
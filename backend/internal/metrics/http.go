@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute labels requests no route matched, so unknown paths cannot
+// create new series
+const unmatchedRoute = "unmatched"
+
+// HTTPMetrics records the latency and status of HTTP requests per route
+type HTTPMetrics struct {
+	duration *prometheus.HistogramVec
+	requests *prometheus.CounterVec
+	inFlight prometheus.Gauge
+}
+
+// NewHTTPMetrics creates the HTTP metrics and registers them with the registry
+func NewHTTPMetrics(registry prometheus.Registerer) *HTTPMetrics {
+	m := &HTTPMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "ezmodel_http_request_duration_seconds",
+			Help:    "Latency of HTTP requests by route pattern.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ezmodel_http_requests_total",
+			Help: "Total HTTP requests by route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ezmodel_http_requests_in_flight",
+			Help: "Number of HTTP requests being served.",
+		}),
+	}
+	registry.MustRegister(m.duration, m.requests, m.inFlight)
+	return m
+}
+
+// Middleware records every request under the chi route pattern it matched,
+// e.g. /api/projects/{project_id}, rather than its path. WebSocket connections
+// are counted when they close.
+func (m *HTTPMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		start := time.Now()
+		ww := chiMiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := unmatchedRoute
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				route = pattern
+			}
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK // Nothing was written
+		}
+
+		m.duration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+		m.requests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+	})
+}
@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HubCollector exports WebSocket hub statistics to Prometheus. Connections
+// are aggregated over projects rather than labelled by project, so series do
+// not grow with the number of projects; the admin stats endpoint breaks them
+// down.
+type HubCollector struct {
+	hub *websocketPkg.Hub
+
+	connections           *prometheus.Desc
+	projects              *prometheus.Desc
+	maxProjectConnections *prometheus.Desc
+	observers             *prometheus.Desc
+	messagesBroadcast     *prometheus.Desc
+	messagesDelivered     *prometheus.Desc
+	messagesPerSecond     *prometheus.Desc
+	droppedSends          *prometheus.Desc
+	brokerPublishFailures *prometheus.Desc
+	eventSinkFailures     *prometheus.Desc
+	queuedWrites          *prometheus.Desc
+	activeWrites          *prometheus.Desc
+	rejectedWrites        *prometheus.Desc
+	droppedSessionEvents  *prometheus.Desc
+}
+
+// NewHubCollector creates a collector that reads stats from the given hub on every scrape
+func NewHubCollector(hub *websocketPkg.Hub) *HubCollector {
+	return &HubCollector{
+		hub: hub,
+		connections: prometheus.NewDesc(
+			"ezmodel_ws_connections",
+			"Number of open WebSocket connections.",
+			nil, nil,
+		),
+		projects: prometheus.NewDesc(
+			"ezmodel_ws_projects",
+			"Number of projects with open WebSocket connections.",
+			nil, nil,
+		),
+		maxProjectConnections: prometheus.NewDesc(
+			"ezmodel_ws_max_project_connections",
+			"Largest number of open WebSocket connections to a single project.",
+			nil, nil,
+		),
+		observers: prometheus.NewDesc(
+			"ezmodel_ws_observers",
+			"Number of non-client consumers of project broadcasts, e.g. GraphQL subscriptions.",
+			nil, nil,
+		),
+		messagesBroadcast: prometheus.NewDesc(
+			"ezmodel_ws_messages_broadcast_total",
+			"Total messages broadcast by the hub.",
+			nil, nil,
+		),
+		messagesDelivered: prometheus.NewDesc(
+			"ezmodel_ws_messages_delivered_total",
+			"Total messages queued to client send channels.",
+			nil, nil,
+		),
+		messagesPerSecond: prometheus.NewDesc(
+			"ezmodel_ws_messages_per_second",
+			"Broadcast rate over the last heartbeat interval.",
+			nil, nil,
+		),
+		droppedSends: prometheus.NewDesc(
+			"ezmodel_ws_dropped_sends_total",
+			"Total messages dropped because a client send channel was full.",
+			nil, nil,
+		),
+		brokerPublishFailures: prometheus.NewDesc(
+			"ezmodel_ws_broker_publish_failures_total",
+			"Total failed publishes to the message broker for cross-node sync.",
+			nil, nil,
+		),
+		eventSinkFailures: prometheus.NewDesc(
+			"ezmodel_event_sink_failures_total",
+			"Total schema-change events dropped or rejected by an outbound event sink.",
+			nil, nil,
+		),
+		queuedWrites: prometheus.NewDesc(
+			"ezmodel_ws_queued_writes",
+			"Number of database writes from client messages waiting for a worker.",
+			nil, nil,
+		),
+		activeWrites: prometheus.NewDesc(
+			"ezmodel_ws_active_writes",
+			"Number of database writes from client messages being run.",
+			nil, nil,
+		),
+		rejectedWrites: prometheus.NewDesc(
+			"ezmodel_ws_rejected_writes_total",
+			"Total database writes from client messages rejected because the write queue was full.",
+			nil, nil,
+		),
+		droppedSessionEvents: prometheus.NewDesc(
+			"ezmodel_ws_dropped_session_events_total",
+			"Total cursor and status updates not saved because the collaboration session queue was full.",
+			nil, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *HubCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.connections
+	ch <- c.projects
+	ch <- c.maxProjectConnections
+	ch <- c.observers
+	ch <- c.messagesBroadcast
+	ch <- c.messagesDelivered
+	ch <- c.messagesPerSecond
+	ch <- c.droppedSends
+	ch <- c.brokerPublishFailures
+	ch <- c.eventSinkFailures
+	ch <- c.queuedWrites
+	ch <- c.activeWrites
+	ch <- c.rejectedWrites
+	ch <- c.droppedSessionEvents
+}
+
+// Collect implements prometheus.Collector
+func (c *HubCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.hub.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.connections, prometheus.GaugeValue, float64(stats.TotalConnections))
+	maxProjectConnections := 0
+	for _, count := range stats.ConnectionsByProject {
+		maxProjectConnections = max(maxProjectConnections, count)
+	}
+	ch <- prometheus.MustNewConstMetric(c.projects, prometheus.GaugeValue, float64(len(stats.ConnectionsByProject)))
+	ch <- prometheus.MustNewConstMetric(c.maxProjectConnections, prometheus.GaugeValue, float64(maxProjectConnections))
+	ch <- prometheus.MustNewConstMetric(c.observers, prometheus.GaugeValue, float64(stats.Observers))
+	ch <- prometheus.MustNewConstMetric(c.messagesBroadcast, prometheus.CounterValue, float64(stats.MessagesBroadcast))
+	ch <- prometheus.MustNewConstMetric(c.messagesDelivered, prometheus.CounterValue, float64(stats.MessagesDelivered))
+	ch <- prometheus.MustNewConstMetric(c.messagesPerSecond, prometheus.GaugeValue, stats.MessagesPerSecond)
+	ch <- prometheus.MustNewConstMetric(c.droppedSends, prometheus.CounterValue, float64(stats.DroppedSends))
+	ch <- prometheus.MustNewConstMetric(c.brokerPublishFailures, prometheus.CounterValue, float64(stats.BrokerPublishFailures))
+	ch <- prometheus.MustNewConstMetric(c.eventSinkFailures, prometheus.CounterValue, float64(stats.EventSinkFailures))
+	ch <- prometheus.MustNewConstMetric(c.queuedWrites, prometheus.GaugeValue, float64(stats.QueuedWrites))
+	ch <- prometheus.MustNewConstMetric(c.activeWrites, prometheus.GaugeValue, float64(stats.ActiveWrites))
+	ch <- prometheus.MustNewConstMetric(c.rejectedWrites, prometheus.CounterValue, float64(stats.RejectedWrites))
+	ch <- prometheus.MustNewConstMetric(c.droppedSessionEvents, prometheus.CounterValue, float64(stats.DroppedSessionEvents))
+}
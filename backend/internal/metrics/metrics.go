@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewRegistry creates a Prometheus registry with runtime collectors and the WebSocket hub collector
+func NewRegistry(hub *websocketPkg.Hub) *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		NewHubCollector(hub),
+	)
+	return registry
+}
+
+// Handler serves the metrics in a registry in the Prometheus exposition format
+// to scrapers that send token as a bearer token
+func Handler(registry *prometheus.Registry, token string) http.Handler {
+	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_RequiresToken(t *testing.T) {
+	hub := websocketPkg.NewHub()
+	defer hub.Shutdown()
+	handler := Handler(NewRegistry(hub), "scrape-token")
+
+	for _, authorization := range []string{"", "Bearer wrong", "scrape-token"} {
+		request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, authorization)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	request.Header.Set("Authorization", "Bearer scrape-token")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "ezmodel_ws_projects 0")
+	assert.NotContains(t, recorder.Body.String(), "project_id=", "connections are not labelled by project")
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	repo "github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAdminAuditLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockAdminAuditLogRepository) Create(entry *models.AdminAuditLog) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAdminAuditLogRepository) List(filter repo.AuditLogFilter, page repo.PageQuery) ([]*models.AdminAuditLog, string, error) {
+	args := m.Called(filter, page)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).([]*models.AdminAuditLog), args.String(1), args.Error(2)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBranchRepository struct {
+	mock.Mock
+}
+
+func (m *MockBranchRepository) Create(branch *models.Branch) (uuid.UUID, error) {
+	args := m.Called(branch)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockBranchRepository) GetByID(id uuid.UUID) (*models.Branch, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Branch), args.Error(1)
+}
+
+func (m *MockBranchRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Branch, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Branch), args.Error(1)
+}
+
+func (m *MockBranchRepository) Update(branch *models.Branch) error {
+	args := m.Called(branch)
+	return args.Error(0)
+}
+
+func (m *MockBranchRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	repo "github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockChatRepository struct {
+	mock.Mock
+}
+
+func (m *MockChatRepository) Create(message *models.ChatMessage) (uuid.UUID, error) {
+	args := m.Called(message)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockChatRepository) ListByProjectID(projectID uuid.UUID, page repo.PageQuery) ([]*models.ChatMessage, string, error) {
+	args := m.Called(projectID, page)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).([]*models.ChatMessage), args.String(1), args.Error(2)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDataExportRepository struct {
+	mock.Mock
+}
+
+func (m *MockDataExportRepository) Create(export *models.DataExport) (uuid.UUID, error) {
+	args := m.Called(export)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockDataExportRepository) GetByID(id uuid.UUID) (*models.DataExport, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportRepository) GetByUserID(userID uuid.UUID) ([]*models.DataExport, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportRepository) Update(export *models.DataExport) error {
+	args := m.Called(export)
+	return args.Error(0)
+}
+
+func (m *MockDataExportRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDocRepository struct {
+	mock.Mock
+}
+
+func (m *MockDocRepository) Create(revision *models.DocRevision) (uuid.UUID, error) {
+	args := m.Called(revision)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockDocRepository) GetLatest(projectID uuid.UUID) (*models.DocRevision, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DocRevision), args.Error(1)
+}
+
+func (m *MockDocRepository) GetByVersion(projectID uuid.UUID, version int64) (*models.DocRevision, error) {
+	args := m.Called(projectID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DocRevision), args.Error(1)
+}
+
+func (m *MockDocRepository) GetByProjectID(projectID uuid.UUID) ([]*models.DocRevision, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DocRevision), args.Error(1)
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDriftRepository struct {
+	mock.Mock
+}
+
+func (m *MockDriftRepository) GetProfile(projectID uuid.UUID) (*models.ConnectionProfile, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ConnectionProfile), args.Error(1)
+}
+
+func (m *MockDriftRepository) SaveProfile(profile *models.ConnectionProfile) error {
+	args := m.Called(profile)
+	return args.Error(0)
+}
+
+func (m *MockDriftRepository) DeleteProfile(projectID uuid.UUID) error {
+	args := m.Called(projectID)
+	return args.Error(0)
+}
+
+func (m *MockDriftRepository) GetProjectIDsToCheck() ([]uuid.UUID, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockDriftRepository) CreateCheck(check *models.DriftCheck) (uuid.UUID, error) {
+	args := m.Called(check)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockDriftRepository) GetCheck(id uuid.UUID) (*models.DriftCheck, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DriftCheck), args.Error(1)
+}
+
+func (m *MockDriftRepository) GetChecksByProjectID(projectID uuid.UUID, limit int) ([]*models.DriftCheck, error) {
+	args := m.Called(projectID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DriftCheck), args.Error(1)
+}
+
+func (m *MockDriftRepository) GetLatestCompletedCheck(projectID uuid.UUID) (*models.DriftCheck, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DriftCheck), args.Error(1)
+}
+
+func (m *MockDriftRepository) UpdateCheck(check *models.DriftCheck) error {
+	args := m.Called(check)
+	return args.Error(0)
+}
+
+func (m *MockDriftRepository) PruneChecks(projectID uuid.UUID, keep int) error {
+	args := m.Called(projectID, keep)
+	return args.Error(0)
+}
+
+func (m *MockDriftRepository) SetDataProfileStatus(projectID uuid.UUID, status, reason string) error {
+	args := m.Called(projectID, status, reason)
+	return args.Error(0)
+}
+
+func (m *MockDriftRepository) ReplaceDataProfile(projectID uuid.UUID, tables []*models.TableProfile, fields []*models.FieldProfile, profiledAt time.Time) error {
+	args := m.Called(projectID, tables, fields, profiledAt)
+	return args.Error(0)
+}
+
+func (m *MockDriftRepository) GetDataProfile(projectID uuid.UUID) ([]*models.TableProfile, []*models.FieldProfile, error) {
+	args := m.Called(projectID)
+	var tables []*models.TableProfile
+	if args.Get(0) != nil {
+		tables = args.Get(0).([]*models.TableProfile)
+	}
+	var fields []*models.FieldProfile
+	if args.Get(1) != nil {
+		fields = args.Get(1).([]*models.FieldProfile)
+	}
+	return tables, fields, args.Error(2)
+}
@@ -15,6 +15,11 @@ func (m *MockFieldRepository) Create(field *models.Field) (uuid.UUID, error) {
 	return args.Get(0).(uuid.UUID), args.Error(1)
 }
 
+func (m *MockFieldRepository) CreateBatch(fields []*models.Field) error {
+	args := m.Called(fields)
+	return args.Error(0)
+}
+
 func (m *MockFieldRepository) GetByID(id uuid.UUID) (*models.Field, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -23,6 +28,14 @@ func (m *MockFieldRepository) GetByID(id uuid.UUID) (*models.Field, error) {
 	return args.Get(0).(*models.Field), args.Error(1)
 }
 
+func (m *MockFieldRepository) GetByIDs(ids []uuid.UUID) ([]*models.Field, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Field), args.Error(1)
+}
+
 func (m *MockFieldRepository) GetByTableID(tableID uuid.UUID) ([]*models.Field, error) {
 	args := m.Called(tableID)
 	if args.Get(0) == nil {
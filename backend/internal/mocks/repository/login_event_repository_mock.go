@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoginEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginEventRepository) Create(event *models.LoginEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockLoginEventRepository) GetByUserID(userID uuid.UUID, limit int) ([]*models.LoginEvent, error) {
+	args := m.Called(userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.LoginEvent), args.Error(1)
+}
+
+func (m *MockLoginEventRepository) GetLastSuccessAt(email string) (*time.Time, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockLoginEventRepository) CountFailuresByEmail(email string, since time.Time) (int64, *time.Time, error) {
+	args := m.Called(email, since)
+	if args.Get(1) == nil {
+		return args.Get(0).(int64), nil, args.Error(2)
+	}
+	return args.Get(0).(int64), args.Get(1).(*time.Time), args.Error(2)
+}
+
+func (m *MockLoginEventRepository) CountFailuresByIP(ipAddress string, since time.Time) (int64, error) {
+	args := m.Called(ipAddress, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockLoginEventRepository) CountSuccessesByUser(userID uuid.UUID, ipAddress string) (int64, int64, error) {
+	args := m.Called(userID, ipAddress)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
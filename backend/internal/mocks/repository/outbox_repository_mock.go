@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOutboxRepository hands the events given to DispatchPending's Return to
+// the deliver function, counting those delivered without error
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) Create(event *models.OutboxEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) DispatchPending(limit, maxAttempts int, deliver func(event *models.OutboxEvent) error) (int, error) {
+	args := m.Called(limit, maxAttempts)
+	if err := args.Error(1); err != nil {
+		return 0, err
+	}
+	dispatched := 0
+	for _, event := range args.Get(0).([]*models.OutboxEvent) {
+		if deliver(event) == nil {
+			dispatched++
+		}
+	}
+	return dispatched, nil
+}
+
+func (m *MockOutboxRepository) DeleteDispatchedBefore(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
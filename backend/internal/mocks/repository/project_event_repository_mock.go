@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockProjectEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectEventRepository) Create(event *models.ProjectEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockProjectEventRepository) GetSince(projectID uuid.UUID, sequence int64, limit int) ([]*models.ProjectEvent, error) {
+	args := m.Called(projectID, sequence, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ProjectEvent), args.Error(1)
+}
+
+func (m *MockProjectEventRepository) DeleteUpTo(projectID uuid.UUID, sequence int64) error {
+	args := m.Called(projectID, sequence)
+	return args.Error(0)
+}
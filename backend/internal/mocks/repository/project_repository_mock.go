@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	repo "github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -23,28 +26,33 @@ func (m *MockProjectRepository) GetByID(id uuid.UUID) (*models.Project, error) {
 	return args.Get(0).(*models.Project), args.Error(1)
 }
 
-func (m *MockProjectRepository) GetByOwnerID(ownerID uuid.UUID) ([]*models.Project, error) {
-	args := m.Called(ownerID)
+func (m *MockProjectRepository) GetByOwnerID(ownerID uuid.UUID, includes repo.ProjectIncludes) ([]*models.Project, error) {
+	args := m.Called(ownerID, includes)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*models.Project), args.Error(1)
 }
 
-func (m *MockProjectRepository) GetByCollaboratorID(collaboratorID uuid.UUID) ([]*models.Project, error) {
-	args := m.Called(collaboratorID)
+func (m *MockProjectRepository) CountByOwnerID(ownerID uuid.UUID) (int, error) {
+	args := m.Called(ownerID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetByCollaboratorID(collaboratorID uuid.UUID, includes repo.ProjectIncludes) ([]*models.Project, error) {
+	args := m.Called(collaboratorID, includes)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*models.Project), args.Error(1)
 }
 
-func (m *MockProjectRepository) GetAll() ([]*models.Project, error) {
-	args := m.Called()
+func (m *MockProjectRepository) List(filter repo.ProjectFilter, page repo.PageQuery) ([]*models.Project, string, error) {
+	args := m.Called(filter, page)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, "", args.Error(2)
 	}
-	return args.Get(0).([]*models.Project), args.Error(1)
+	return args.Get(0).([]*models.Project), args.String(1), args.Error(2)
 }
 
 func (m *MockProjectRepository) Update(project *models.Project) error {
@@ -52,6 +60,11 @@ func (m *MockProjectRepository) Update(project *models.Project) error {
 	return args.Error(0)
 }
 
+func (m *MockProjectRepository) NextSequence(id uuid.UUID) (int64, error) {
+	args := m.Called(id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockProjectRepository) Delete(id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -66,3 +79,57 @@ func (m *MockProjectRepository) RemoveCollaborator(projectID, collaboratorID uui
 	args := m.Called(projectID, collaboratorID)
 	return args.Error(0)
 }
+
+func (m *MockProjectRepository) AddTag(projectID uuid.UUID, name string) error {
+	args := m.Called(projectID, name)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) RemoveTag(projectID uuid.UUID, name string) error {
+	args := m.Called(projectID, name)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) Star(projectID, userID uuid.UUID) error {
+	args := m.Called(projectID, userID)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) Unstar(projectID, userID uuid.UUID) error {
+	args := m.Called(projectID, userID)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) SetCollaboratorRole(projectID, userID uuid.UUID, role string) error {
+	args := m.Called(projectID, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) GetViewerIDs(projectID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetStarredProjectIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockProjectRepository) RecordView(projectID, userID uuid.UUID, viewedAt time.Time) error {
+	args := m.Called(projectID, userID, viewedAt)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) GetRecentlyViewed(userID uuid.UUID, limit int) ([]*models.ProjectView, error) {
+	args := m.Called(userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ProjectView), args.Error(1)
+}
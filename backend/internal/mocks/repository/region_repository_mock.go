@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRegionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRegionRepository) Create(region *models.Region) (uuid.UUID, error) {
+	args := m.Called(region)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockRegionRepository) GetByID(id uuid.UUID) (*models.Region, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Region), args.Error(1)
+}
+
+func (m *MockRegionRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Region, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Region), args.Error(1)
+}
+
+func (m *MockRegionRepository) Update(region *models.Region) error {
+	args := m.Called(region)
+	return args.Error(0)
+}
+
+func (m *MockRegionRepository) SetTables(regionID uuid.UUID, tableIDs []uuid.UUID) error {
+	args := m.Called(regionID, tableIDs)
+	return args.Error(0)
+}
+
+func (m *MockRegionRepository) MoveTables(regionID uuid.UUID, dx, dy float64) ([]repository.TablePosition, error) {
+	args := m.Called(regionID, dx, dy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repository.TablePosition), args.Error(1)
+}
+
+func (m *MockRegionRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
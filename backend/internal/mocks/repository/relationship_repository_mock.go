@@ -23,6 +23,14 @@ func (m *MockRelationshipRepository) GetByID(id uuid.UUID) (*models.Relationship
 	return args.Get(0).(*models.Relationship), args.Error(1)
 }
 
+func (m *MockRelationshipRepository) GetByIDs(ids []uuid.UUID) ([]*models.Relationship, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Relationship), args.Error(1)
+}
+
 func (m *MockRelationshipRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Relationship, error) {
 	args := m.Called(projectID)
 	if args.Get(0) == nil {
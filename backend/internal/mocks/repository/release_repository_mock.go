@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReleaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockReleaseRepository) Create(release *models.Release) (uuid.UUID, error) {
+	args := m.Called(release)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockReleaseRepository) GetByID(id uuid.UUID) (*models.Release, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Release), args.Error(1)
+}
+
+func (m *MockReleaseRepository) GetByName(projectID uuid.UUID, name string) (*models.Release, error) {
+	args := m.Called(projectID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Release), args.Error(1)
+}
+
+func (m *MockReleaseRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Release, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Release), args.Error(1)
+}
+
+func (m *MockReleaseRepository) Update(release *models.Release) error {
+	args := m.Called(release)
+	return args.Error(0)
+}
+
+func (m *MockReleaseRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRenameRepository struct {
+	mock.Mock
+}
+
+func (m *MockRenameRepository) Create(rename *models.SchemaRename) (uuid.UUID, error) {
+	args := m.Called(rename)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockRenameRepository) GetByProjectID(projectID uuid.UUID) ([]*models.SchemaRename, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SchemaRename), args.Error(1)
+}
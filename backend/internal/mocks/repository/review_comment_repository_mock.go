@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReviewCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockReviewCommentRepository) Create(comment *models.ReviewComment) (uuid.UUID, error) {
+	args := m.Called(comment)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockReviewCommentRepository) GetByID(id uuid.UUID) (*models.ReviewComment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReviewComment), args.Error(1)
+}
+
+func (m *MockReviewCommentRepository) GetByProjectID(projectID uuid.UUID) ([]*models.ReviewComment, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ReviewComment), args.Error(1)
+}
+
+func (m *MockReviewCommentRepository) Update(comment *models.ReviewComment) error {
+	args := m.Called(comment)
+	return args.Error(0)
+}
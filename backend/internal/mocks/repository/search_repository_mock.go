@@ -0,0 +1,18 @@
+package repository
+
+import (
+	repo "github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepository) Search(query repo.SearchQuery) ([]*repo.SearchHit, error) {
+	args := m.Called(query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repo.SearchHit), args.Error(1)
+}
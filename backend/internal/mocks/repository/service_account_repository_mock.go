@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockServiceAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockServiceAccountRepository) Create(account *models.ServiceAccount) (uuid.UUID, error) {
+	args := m.Called(account)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockServiceAccountRepository) GetByID(id uuid.UUID) (*models.ServiceAccount, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ServiceAccount), args.Error(1)
+}
+
+func (m *MockServiceAccountRepository) GetByProjectID(projectID uuid.UUID) ([]*models.ServiceAccount, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ServiceAccount), args.Error(1)
+}
+
+func (m *MockServiceAccountRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSnapshotRepository struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotRepository) Create(snapshot *models.ProjectSnapshot) (uuid.UUID, error) {
+	args := m.Called(snapshot)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockSnapshotRepository) GetByID(id uuid.UUID) (*models.ProjectSnapshot, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProjectSnapshot), args.Error(1)
+}
+
+func (m *MockSnapshotRepository) GetByProjectID(projectID uuid.UUID) ([]*models.ProjectSnapshot, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ProjectSnapshot), args.Error(1)
+}
+
+func (m *MockSnapshotRepository) DeleteByIDs(ids []uuid.UUID) error {
+	args := m.Called(ids)
+	return args.Error(0)
+}
+
+func (m *MockSnapshotRepository) GetProjectIDsToSnapshot() ([]uuid.UUID, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockSnapshotRepository) GetPolicy(projectID uuid.UUID) (*models.SnapshotPolicy, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SnapshotPolicy), args.Error(1)
+}
+
+func (m *MockSnapshotRepository) SavePolicy(policy *models.SnapshotPolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
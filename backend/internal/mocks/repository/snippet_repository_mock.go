@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSnippetRepository struct {
+	mock.Mock
+}
+
+func (m *MockSnippetRepository) Create(snippet *models.Snippet) (uuid.UUID, error) {
+	args := m.Called(snippet)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockSnippetRepository) GetByID(id uuid.UUID) (*models.Snippet, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Snippet), args.Error(1)
+}
+
+func (m *MockSnippetRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Snippet, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Snippet), args.Error(1)
+}
+
+func (m *MockSnippetRepository) Update(snippet *models.Snippet) error {
+	args := m.Called(snippet)
+	return args.Error(0)
+}
+
+func (m *MockSnippetRepository) Delete(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
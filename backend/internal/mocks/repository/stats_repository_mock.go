@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	repo "github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockStatsRepository) GetTotals(ctx context.Context) (*repo.InstanceTotals, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repo.InstanceTotals), args.Error(1)
+}
+
+func (m *MockStatsRepository) CountSignupsByDay(ctx context.Context, since time.Time) ([]*repo.DailyCount, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repo.DailyCount), args.Error(1)
+}
+
+func (m *MockStatsRepository) ListLargestOwners(ctx context.Context, limit int) ([]*repo.OwnerStorage, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repo.OwnerStorage), args.Error(1)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	repo "github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -23,6 +24,14 @@ func (m *MockTableRepository) GetByID(id uuid.UUID) (*models.Table, error) {
 	return args.Get(0).(*models.Table), args.Error(1)
 }
 
+func (m *MockTableRepository) GetByIDs(ids []uuid.UUID) ([]*models.Table, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Table), args.Error(1)
+}
+
 func (m *MockTableRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Table, error) {
 	args := m.Called(projectID)
 	if args.Get(0) == nil {
@@ -31,6 +40,14 @@ func (m *MockTableRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Tab
 	return args.Get(0).([]*models.Table), args.Error(1)
 }
 
+func (m *MockTableRepository) ListByProjectID(projectID uuid.UUID, filter repo.TableFilter, page repo.PageQuery) ([]*models.Table, string, error) {
+	args := m.Called(projectID, filter, page)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).([]*models.Table), args.String(1), args.Error(2)
+}
+
 func (m *MockTableRepository) Update(table *models.Table) error {
 	args := m.Called(table)
 	return args.Error(0)
@@ -41,6 +58,19 @@ func (m *MockTableRepository) UpdatePosition(id uuid.UUID, posX, posY float64) e
 	return args.Error(0)
 }
 
+func (m *MockTableRepository) SetFieldOrderSequence(id uuid.UUID, sequence, since int64) error {
+	args := m.Called(id, sequence, since)
+	return args.Error(0)
+}
+
+func (m *MockTableRepository) CountByProjectIDs(projectIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	args := m.Called(projectIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]int), args.Error(1)
+}
+
 func (m *MockTableRepository) Delete(id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
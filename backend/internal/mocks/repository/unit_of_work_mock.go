@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUnitOfWork runs the work against Repos, which are usually the other
+// repository mocks. Nothing is rolled back when the work fails.
+type MockUnitOfWork struct {
+	mock.Mock
+	Repos repository.Repositories
+}
+
+func (m *MockUnitOfWork) Do(fn func(repos repository.Repositories) error) error {
+	args := m.Called()
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(m.Repos)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserIdentityRepository) Create(identity *models.UserIdentity) (uuid.UUID, error) {
+	args := m.Called(identity)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockUserIdentityRepository) GetByProviderSubject(provider, subject string) (*models.UserIdentity, error) {
+	args := m.Called(provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserIdentity), args.Error(1)
+}
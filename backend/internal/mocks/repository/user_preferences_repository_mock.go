@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserPreferencesRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserPreferencesRepository) GetByUserID(userID uuid.UUID) (*models.UserPreferences, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserPreferences), args.Error(1)
+}
+
+func (m *MockUserPreferencesRepository) Save(preferences *models.UserPreferences) error {
+	args := m.Called(preferences)
+	return args.Error(0)
+}
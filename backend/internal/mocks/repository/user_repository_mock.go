@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	repo "github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -31,12 +32,25 @@ func (m *MockUserRepository) GetByEmail(email string) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetAll() ([]*models.User, error) {
-	args := m.Called()
+func (m *MockUserRepository) GetByUsername(username string) (*models.User, error) {
+	args := m.Called(username)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*models.User), args.Error(1)
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) List(filter repo.UserFilter, page repo.PageQuery) ([]*models.User, string, error) {
+	args := m.Called(filter, page)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).([]*models.User), args.String(1), args.Error(2)
+}
+
+func (m *MockUserRepository) Count(filter repo.UserFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
 }
 
 func (m *MockUserRepository) Update(user *models.User) error {
@@ -48,3 +62,8 @@ func (m *MockUserRepository) Delete(id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
+
+func (m *MockUserRepository) DeleteAccount(id uuid.UUID, transfers map[uuid.UUID]uuid.UUID) error {
+	args := m.Called(id, transfers)
+	return args.Error(0)
+}
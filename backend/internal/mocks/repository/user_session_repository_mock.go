@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserSessionRepository) Create(session *models.UserSession) (uuid.UUID, error) {
+	args := m.Called(session)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockUserSessionRepository) GetByID(id uuid.UUID) (*models.UserSession, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSession), args.Error(1)
+}
+
+func (m *MockUserSessionRepository) GetActiveByUserID(userID uuid.UUID, now time.Time) ([]*models.UserSession, error) {
+	args := m.Called(userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.UserSession), args.Error(1)
+}
+
+func (m *MockUserSessionRepository) Rotate(session *models.UserSession, previousHash string) (bool, error) {
+	args := m.Called(session, previousHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserSessionRepository) Revoke(id uuid.UUID, revokedAt time.Time) error {
+	args := m.Called(id, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserSessionRepository) RevokeAllByUserID(userID, exceptID uuid.UUID, revokedAt time.Time) error {
+	args := m.Called(userID, exceptID, revokedAt)
+	return args.Error(0)
+}
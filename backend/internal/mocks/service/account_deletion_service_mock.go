@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAccountDeletionService struct {
+	mock.Mock
+}
+
+func (m *MockAccountDeletionService) DeleteAccount(ctx context.Context, userID, sessionID uuid.UUID, req *dto.DeleteAccountRequest) ([]*models.Project, error) {
+	args := m.Called(ctx, userID, sessionID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Project), args.Error(1)
+}
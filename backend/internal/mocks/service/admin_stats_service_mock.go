@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAdminStatsService struct {
+	mock.Mock
+}
+
+func (m *MockAdminStatsService) GetStats(ctx context.Context, days int) (*services.InstanceStats, error) {
+	args := m.Called(ctx, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.InstanceStats), args.Error(1)
+}
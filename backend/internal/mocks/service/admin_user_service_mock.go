@@ -0,0 +1,71 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAdminUserService struct {
+	mock.Mock
+}
+
+func (m *MockAdminUserService) IsAdmin(userID uuid.UUID) (bool, error) {
+	args := m.Called(userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAdminUserService) ListUsers(filter repository.UserFilter, page repository.PageQuery) ([]*models.User, string, int64, error) {
+	args := m.Called(filter, page)
+	if args.Get(0) == nil {
+		return nil, "", 0, args.Error(3)
+	}
+	return args.Get(0).([]*models.User), args.String(1), args.Get(2).(int64), args.Error(3)
+}
+
+func (m *MockAdminUserService) SetUserDisabled(actorID, userID uuid.UUID, disabled bool, reason, ipAddress string) (*models.User, error) {
+	args := m.Called(actorID, userID, disabled, reason, ipAddress)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAdminUserService) SetUserRole(actorID, userID uuid.UUID, role, ipAddress string) (*models.User, error) {
+	args := m.Called(actorID, userID, role, ipAddress)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAdminUserService) ForcePasswordReset(actorID, userID uuid.UUID, ipAddress string) (*models.User, error) {
+	args := m.Called(actorID, userID, ipAddress)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAdminUserService) Impersonate(actorID, userID uuid.UUID, reason, ipAddress, userAgent string) (*models.User, *services.TokenPair, error) {
+	args := m.Called(actorID, userID, reason, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*models.User), args.Get(1).(*services.TokenPair), args.Error(2)
+}
+
+func (m *MockAdminUserService) RecordImpersonatedRequest(actorID, userID uuid.UUID, request, ipAddress string) error {
+	args := m.Called(actorID, userID, request, ipAddress)
+	return args.Error(0)
+}
+
+func (m *MockAdminUserService) GetAuditLogs(filter repository.AuditLogFilter, page repository.PageQuery) ([]*models.AdminAuditLog, string, error) {
+	args := m.Called(filter, page)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).([]*models.AdminAuditLog), args.String(1), args.Error(2)
+}
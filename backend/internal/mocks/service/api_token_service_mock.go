@@ -0,0 +1,55 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAPITokenService struct {
+	mock.Mock
+}
+
+func (m *MockAPITokenService) CreateToken(userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*models.APIToken, string, error) {
+	args := m.Called(userID, name, scopes, expiresAt)
+	if args.Get(0) == nil {
+		return nil, args.String(1), args.Error(2)
+	}
+	return args.Get(0).(*models.APIToken), args.String(1), args.Error(2)
+}
+
+func (m *MockAPITokenService) CreateProjectToken(userID, projectID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*models.APIToken, string, error) {
+	args := m.Called(userID, projectID, name, scopes, expiresAt)
+	if args.Get(0) == nil {
+		return nil, args.String(1), args.Error(2)
+	}
+	return args.Get(0).(*models.APIToken), args.String(1), args.Error(2)
+}
+
+func (m *MockAPITokenService) GetTokensByUserID(userID uuid.UUID) ([]*models.APIToken, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.APIToken), args.Error(1)
+}
+
+func (m *MockAPITokenService) RevokeToken(id, userID uuid.UUID) error {
+	args := m.Called(id, userID)
+	return args.Error(0)
+}
+
+func (m *MockAPITokenService) AdminRevokeToken(id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAPITokenService) AuthenticateToken(plaintext string) (*models.APIToken, error) {
+	args := m.Called(plaintext)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIToken), args.Error(1)
+}
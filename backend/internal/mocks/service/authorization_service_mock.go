@@ -0,0 +1,40 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuthorizationService struct {
+	mock.Mock
+}
+
+func (m *MockAuthorizationService) CanUserAccessProject(userID, projectID uuid.UUID) (bool, error) {
+	args := m.Called(userID, projectID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthorizationService) CanUserModifyProject(userID, projectID uuid.UUID) (bool, error) {
+	args := m.Called(userID, projectID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthorizationService) CanUserDeleteCollaborationSession(userID, sessionID uuid.UUID) (bool, error) {
+	args := m.Called(userID, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAuthorizationService) GetProjectIDFromTable(tableID uuid.UUID) (uuid.UUID, error) {
+	args := m.Called(tableID)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockAuthorizationService) GetProjectIDFromRelationship(relationshipID uuid.UUID) (uuid.UUID, error) {
+	args := m.Called(relationshipID)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (m *MockAuthorizationService) GetProjectIDFromField(fieldID uuid.UUID) (uuid.UUID, error) {
+	args := m.Called(fieldID)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAvatarService struct {
+	mock.Mock
+}
+
+func (m *MockAvatarService) MaxBytes() int64 {
+	args := m.Called()
+	return args.Get(0).(int64)
+}
+
+func (m *MockAvatarService) UploadAvatar(ctx context.Context, userID uuid.UUID, data []byte) (*models.User, error) {
+	args := m.Called(ctx, userID, data)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockAvatarService) DeleteAvatar(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBillingService struct {
+	mock.Mock
+}
+
+func (m *MockBillingService) Plans() []services.Plan {
+	args := m.Called()
+	return args.Get(0).([]services.Plan)
+}
+
+func (m *MockBillingService) GetCurrentPlan(userID uuid.UUID) (*services.CurrentPlan, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.CurrentPlan), args.Error(1)
+}
+
+func (m *MockBillingService) CreateCheckout(ctx context.Context, userID uuid.UUID, planID string) (string, error) {
+	args := m.Called(ctx, userID, planID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockBillingService) HandleWebhook(payload []byte, signature string) error {
+	args := m.Called(payload, signature)
+	return args.Error(0)
+}
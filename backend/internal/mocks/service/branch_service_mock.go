@@ -0,0 +1,66 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBranchService struct {
+	mock.Mock
+}
+
+func (m *MockBranchService) CreateBranch(projectID uuid.UUID, req *dto.CreateBranchRequest, userID uuid.UUID) (*models.Branch, error) {
+	args := m.Called(projectID, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Branch), args.Error(1)
+}
+
+func (m *MockBranchService) GetBranches(projectID, userID uuid.UUID) ([]*models.Branch, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Branch), args.Error(1)
+}
+
+func (m *MockBranchService) GetBranch(projectID, id, userID uuid.UUID) (*models.Branch, error) {
+	args := m.Called(projectID, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Branch), args.Error(1)
+}
+
+func (m *MockBranchService) UpdateBranch(projectID, id uuid.UUID, req *dto.UpdateBranchRequest, userID uuid.UUID) (*models.Branch, error) {
+	args := m.Called(projectID, id, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Branch), args.Error(1)
+}
+
+func (m *MockBranchService) DeleteBranch(projectID, id, userID uuid.UUID) error {
+	args := m.Called(projectID, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockBranchService) DiffBranch(projectID, id, userID uuid.UUID) (*schemadiff.Diff, error) {
+	args := m.Called(projectID, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemadiff.Diff), args.Error(1)
+}
+
+func (m *MockBranchService) MergeBranch(projectID, id, userID uuid.UUID) (*models.Branch, *schemadiff.Diff, error) {
+	args := m.Called(projectID, id, userID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*models.Branch), args.Get(1).(*schemadiff.Diff), args.Error(2)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockChatService struct {
+	mock.Mock
+}
+
+func (m *MockChatService) SendMessage(projectID uuid.UUID, content string, userID uuid.UUID) (*models.ChatMessage, error) {
+	args := m.Called(projectID, content, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChatMessage), args.Error(1)
+}
+
+func (m *MockChatService) ListMessages(projectID, userID uuid.UUID, page repository.PageQuery) ([]*models.ChatMessage, string, error) {
+	args := m.Called(projectID, userID, page)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).([]*models.ChatMessage), args.String(1), args.Error(2)
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDataExportService struct {
+	mock.Mock
+}
+
+func (m *MockDataExportService) StartExport(userID uuid.UUID) (*models.DataExport, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportService) GetExport(userID uuid.UUID) (*models.DataExport, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DataExport), args.Error(1)
+}
+
+func (m *MockDataExportService) DownloadURL(export *models.DataExport) string {
+	args := m.Called(export)
+	return args.String(0)
+}
+
+func (m *MockDataExportService) OpenDownload(ctx context.Context, exportID uuid.UUID, expires, signature string) (io.ReadCloser, *models.DataExport, error) {
+	args := m.Called(ctx, exportID, expires, signature)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(io.ReadCloser), args.Get(1).(*models.DataExport), args.Error(2)
+}
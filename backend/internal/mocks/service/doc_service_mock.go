@@ -0,0 +1,44 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDocService struct {
+	mock.Mock
+}
+
+func (m *MockDocService) GetDoc(projectID uuid.UUID, version int64, userID uuid.UUID) (*models.DocRevision, error) {
+	args := m.Called(projectID, version, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DocRevision), args.Error(1)
+}
+
+func (m *MockDocService) GetDocRevisions(projectID, userID uuid.UUID) ([]*models.DocRevision, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DocRevision), args.Error(1)
+}
+
+func (m *MockDocService) UpdateDoc(projectID uuid.UUID, req *dto.UpdateDocRequest, userID uuid.UUID) (*models.DocRevision, error) {
+	args := m.Called(projectID, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DocRevision), args.Error(1)
+}
+
+func (m *MockDocService) RenderDoc(projectID uuid.UUID, version int64, userID uuid.UUID) ([]byte, error) {
+	args := m.Called(projectID, version, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
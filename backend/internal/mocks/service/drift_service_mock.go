@@ -0,0 +1,80 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDriftService struct {
+	mock.Mock
+}
+
+func (m *MockDriftService) GetProfile(projectID, userID uuid.UUID) (*services.ConnectionProfile, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ConnectionProfile), args.Error(1)
+}
+
+func (m *MockDriftService) SetProfile(projectID, userID uuid.UUID, req *dto.SetConnectionProfileRequest) (*services.ConnectionProfile, error) {
+	args := m.Called(projectID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ConnectionProfile), args.Error(1)
+}
+
+func (m *MockDriftService) DeleteProfile(projectID, userID uuid.UUID) error {
+	args := m.Called(projectID, userID)
+	return args.Error(0)
+}
+
+func (m *MockDriftService) StartCheck(projectID, userID uuid.UUID) (*models.DriftCheck, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DriftCheck), args.Error(1)
+}
+
+func (m *MockDriftService) GetChecks(projectID, userID uuid.UUID) ([]*models.DriftCheck, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DriftCheck), args.Error(1)
+}
+
+func (m *MockDriftService) GetCheck(projectID, checkID, userID uuid.UUID) (*models.DriftCheck, *schemadiff.Diff, error) {
+	args := m.Called(projectID, checkID, userID)
+	var check *models.DriftCheck
+	if args.Get(0) != nil {
+		check = args.Get(0).(*models.DriftCheck)
+	}
+	var diff *schemadiff.Diff
+	if args.Get(1) != nil {
+		diff = args.Get(1).(*schemadiff.Diff)
+	}
+	return check, diff, args.Error(2)
+}
+
+func (m *MockDriftService) StartDataProfile(projectID, userID uuid.UUID) (*services.DataProfile, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.DataProfile), args.Error(1)
+}
+
+func (m *MockDriftService) GetDataProfile(projectID, userID uuid.UUID) (*services.DataProfile, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.DataProfile), args.Error(1)
+}
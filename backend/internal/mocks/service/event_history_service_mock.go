@@ -0,0 +1,19 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEventHistoryService struct {
+	mock.Mock
+}
+
+func (m *MockEventHistoryService) ListEvents(projectID, userID uuid.UUID, sinceSequence int64, limit int) (*services.ProjectEvents, error) {
+	args := m.Called(projectID, userID, sinceSequence, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ProjectEvents), args.Error(1)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -19,6 +20,14 @@ func (m *MockFieldService) CreateField(tableID uuid.UUID, req *dto.CreateFieldRe
 	return args.Get(0).(*models.Field), args.Error(1)
 }
 
+func (m *MockFieldService) CreateFields(tableID uuid.UUID, reqs []dto.CreateFieldRequest, userID uuid.UUID) ([]*models.Field, error) {
+	args := m.Called(tableID, reqs, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Field), args.Error(1)
+}
+
 func (m *MockFieldService) GetFieldByID(id uuid.UUID) (*models.Field, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -43,12 +52,15 @@ func (m *MockFieldService) UpdateField(id uuid.UUID, req *dto.UpdateFieldRequest
 	return args.Get(0).(*models.Field), args.Error(1)
 }
 
-func (m *MockFieldService) DeleteField(id uuid.UUID, userID uuid.UUID) error {
+func (m *MockFieldService) DeleteField(id uuid.UUID, userID uuid.UUID) (int64, error) {
 	args := m.Called(id, userID)
-	return args.Error(0)
+	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockFieldService) ReorderFields(tableID uuid.UUID, fieldPositions map[uuid.UUID]int) error {
-	args := m.Called(tableID, fieldPositions)
-	return args.Error(0)
+func (m *MockFieldService) ReorderFields(tableID uuid.UUID, fieldPositions map[uuid.UUID]int, baseSequence *int64, userID uuid.UUID) (*services.FieldOrder, error) {
+	args := m.Called(tableID, fieldPositions, baseSequence, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.FieldOrder), args.Error(1)
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -20,6 +21,14 @@ func (m *MockJWTService) GenerateTokenPair(user *models.User) (*services.TokenPa
 	return args.Get(0).(*services.TokenPair), args.Error(1)
 }
 
+func (m *MockJWTService) GenerateSessionTokenPair(user *models.User, sessionID uuid.UUID) (*services.TokenPair, error) {
+	args := m.Called(user, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.TokenPair), args.Error(1)
+}
+
 func (m *MockJWTService) RefreshTokens(refreshToken string) (*services.TokenPair, error) {
 	args := m.Called(refreshToken)
 	if args.Get(0) == nil {
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLoginSecurityService struct {
+	mock.Mock
+}
+
+func (m *MockLoginSecurityService) Authenticate(email, password, ipAddress, userAgent string) (*models.User, error) {
+	args := m.Called(email, password, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockLoginSecurityService) GetLoginHistory(userID uuid.UUID) ([]*models.LoginEvent, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.LoginEvent), args.Error(1)
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOAuthService struct {
+	mock.Mock
+}
+
+func (m *MockOAuthService) AuthCodeURL(provider, state, verifier string) (string, error) {
+	args := m.Called(provider, state, verifier)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockOAuthService) Authenticate(ctx context.Context, provider, code, verifier string) (*models.User, error) {
+	args := m.Called(ctx, provider, code, verifier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -27,28 +28,28 @@ func (m *MockProjectService) GetProjectByID(id uuid.UUID) (*models.Project, erro
 	return args.Get(0).(*models.Project), args.Error(1)
 }
 
-func (m *MockProjectService) GetProjectsByOwnerID(ownerID uuid.UUID) ([]*models.Project, error) {
-	args := m.Called(ownerID)
+func (m *MockProjectService) GetProjectsByOwnerID(ownerID uuid.UUID, includes repository.ProjectIncludes) ([]*models.Project, error) {
+	args := m.Called(ownerID, includes)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*models.Project), args.Error(1)
 }
 
-func (m *MockProjectService) GetProjectsByCollaboratorID(collaboratorID uuid.UUID) ([]*models.Project, error) {
-	args := m.Called(collaboratorID)
+func (m *MockProjectService) GetProjectsByCollaboratorID(collaboratorID uuid.UUID, includes repository.ProjectIncludes) ([]*models.Project, error) {
+	args := m.Called(collaboratorID, includes)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*models.Project), args.Error(1)
 }
 
-func (m *MockProjectService) GetAllProjects() ([]*models.Project, error) {
-	args := m.Called()
+func (m *MockProjectService) ListProjects(filter repository.ProjectFilter, page repository.PageQuery) ([]*models.Project, string, error) {
+	args := m.Called(filter, page)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, "", args.Error(2)
 	}
-	return args.Get(0).([]*models.Project), args.Error(1)
+	return args.Get(0).([]*models.Project), args.String(1), args.Error(2)
 }
 
 func (m *MockProjectService) UpdateProject(id uuid.UUID, req *dto.UpdateProjectRequest, userID uuid.UUID) (*models.Project, error) {
@@ -73,3 +74,59 @@ func (m *MockProjectService) RemoveCollaborator(projectID, collaboratorID uuid.U
 	args := m.Called(projectID, collaboratorID)
 	return args.Error(0)
 }
+
+func (m *MockProjectService) KickCollaborator(projectID, collaboratorID, userID uuid.UUID) error {
+	args := m.Called(projectID, collaboratorID, userID)
+	return args.Error(0)
+}
+
+func (m *MockProjectService) SetCollaboratorRole(projectID, collaboratorID uuid.UUID, role string, userID uuid.UUID) error {
+	args := m.Called(projectID, collaboratorID, role, userID)
+	return args.Error(0)
+}
+
+func (m *MockProjectService) GetMemberRole(projectID, userID uuid.UUID) (string, error) {
+	args := m.Called(projectID, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockProjectService) AddProjectTag(projectID uuid.UUID, tag string, userID uuid.UUID) error {
+	args := m.Called(projectID, tag, userID)
+	return args.Error(0)
+}
+
+func (m *MockProjectService) RemoveProjectTag(projectID uuid.UUID, tag string, userID uuid.UUID) error {
+	args := m.Called(projectID, tag, userID)
+	return args.Error(0)
+}
+
+func (m *MockProjectService) StarProject(projectID, userID uuid.UUID) error {
+	args := m.Called(projectID, userID)
+	return args.Error(0)
+}
+
+func (m *MockProjectService) UnstarProject(projectID, userID uuid.UUID) error {
+	args := m.Called(projectID, userID)
+	return args.Error(0)
+}
+
+func (m *MockProjectService) GetStarredProjectIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockProjectService) RecordProjectView(projectID, userID uuid.UUID) error {
+	args := m.Called(projectID, userID)
+	return args.Error(0)
+}
+
+func (m *MockProjectService) GetRecentProjects(userID uuid.UUID, limit int) ([]*models.ProjectView, error) {
+	args := m.Called(userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ProjectView), args.Error(1)
+}
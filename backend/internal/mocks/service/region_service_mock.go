@@ -0,0 +1,58 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRegionService struct {
+	mock.Mock
+}
+
+func (m *MockRegionService) CreateRegion(projectID uuid.UUID, req *dto.CreateRegionRequest, userID uuid.UUID) (*models.Region, error) {
+	args := m.Called(projectID, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Region), args.Error(1)
+}
+
+func (m *MockRegionService) GetRegionsByProjectID(projectID uuid.UUID) ([]*models.Region, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Region), args.Error(1)
+}
+
+func (m *MockRegionService) GetRegionByID(projectID, id uuid.UUID) (*models.Region, error) {
+	args := m.Called(projectID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Region), args.Error(1)
+}
+
+func (m *MockRegionService) UpdateRegion(projectID, id uuid.UUID, req *dto.UpdateRegionRequest, userID uuid.UUID) (*models.Region, error) {
+	args := m.Called(projectID, id, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Region), args.Error(1)
+}
+
+func (m *MockRegionService) MoveRegion(projectID, id uuid.UUID, req *dto.MoveRegionRequest, userID uuid.UUID) (*services.RegionMove, error) {
+	args := m.Called(projectID, id, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.RegionMove), args.Error(1)
+}
+
+func (m *MockRegionService) DeleteRegion(projectID, id uuid.UUID, userID uuid.UUID) (int64, error) {
+	args := m.Called(projectID, id, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
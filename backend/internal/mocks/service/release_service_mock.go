@@ -0,0 +1,49 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReleaseService struct {
+	mock.Mock
+}
+
+func (m *MockReleaseService) CreateRelease(projectID uuid.UUID, req *dto.CreateReleaseRequest, userID uuid.UUID) (*models.Release, error) {
+	args := m.Called(projectID, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Release), args.Error(1)
+}
+
+func (m *MockReleaseService) GetReleases(projectID, userID uuid.UUID) ([]*models.Release, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Release), args.Error(1)
+}
+
+func (m *MockReleaseService) GetRelease(projectID, id, userID uuid.UUID) (*models.Release, error) {
+	args := m.Called(projectID, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Release), args.Error(1)
+}
+
+func (m *MockReleaseService) UpdateRelease(projectID, id uuid.UUID, req *dto.UpdateReleaseRequest, userID uuid.UUID) (*models.Release, error) {
+	args := m.Called(projectID, id, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Release), args.Error(1)
+}
+
+func (m *MockReleaseService) DeleteRelease(projectID, id, userID uuid.UUID) error {
+	args := m.Called(projectID, id, userID)
+	return args.Error(0)
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReviewService struct {
+	mock.Mock
+}
+
+func (m *MockReviewService) ReviewSchema(ctx context.Context, projectID, userID uuid.UUID) ([]*models.ReviewComment, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ReviewComment), args.Error(1)
+}
+
+func (m *MockReviewService) GetComments(projectID, userID uuid.UUID) ([]*models.ReviewComment, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ReviewComment), args.Error(1)
+}
+
+func (m *MockReviewService) ResolveComment(projectID, id, userID uuid.UUID) (*models.ReviewComment, error) {
+	args := m.Called(projectID, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReviewComment), args.Error(1)
+}
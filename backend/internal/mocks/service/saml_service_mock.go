@@ -0,0 +1,33 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSAMLService struct {
+	mock.Mock
+}
+
+func (m *MockSAMLService) Metadata() ([]byte, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockSAMLService) LoginURL(relayState string) (string, string, error) {
+	args := m.Called(relayState)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockSAMLService) Authenticate(r *http.Request, requestIDs []string) (*models.User, error) {
+	args := m.Called(r, requestIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/lint"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSchemaService struct {
+	mock.Mock
+}
+
+func (m *MockSchemaService) CreateSchema(projectID uuid.UUID, req *dto.CreateSchemaRequest, userID uuid.UUID) (*services.Schema, error) {
+	args := m.Called(projectID, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.Schema), args.Error(1)
+}
+
+func (m *MockSchemaService) PlanSchema(projectID uuid.UUID, req *dto.CreateSchemaRequest, userID uuid.UUID) (*services.Schema, error) {
+	args := m.Called(projectID, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.Schema), args.Error(1)
+}
+
+func (m *MockSchemaService) ImportSpreadsheet(projectID uuid.UUID, filename string, data []byte, conflicts []dto.SchemaConflictResolution, userID uuid.UUID) (*services.Schema, error) {
+	args := m.Called(projectID, filename, data, conflicts, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.Schema), args.Error(1)
+}
+
+func (m *MockSchemaService) PlanSpreadsheet(projectID uuid.UUID, filename string, data []byte, conflicts []dto.SchemaConflictResolution, userID uuid.UUID) (*services.Schema, error) {
+	args := m.Called(projectID, filename, data, conflicts, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.Schema), args.Error(1)
+}
+
+func (m *MockSchemaService) InferSchema(projectID uuid.UUID, req *dto.InferSchemaRequest, userID uuid.UUID) (*services.SchemaProposal, error) {
+	args := m.Called(projectID, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.SchemaProposal), args.Error(1)
+}
+
+func (m *MockSchemaService) CompareProjects(projectID, otherProjectID, userID uuid.UUID) (*schemadiff.Diff, error) {
+	args := m.Called(projectID, otherProjectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schemadiff.Diff), args.Error(1)
+}
+
+func (m *MockSchemaService) GenerateData(projectID uuid.UUID, req *dto.GenerateDataRequest, userID uuid.UUID) ([]byte, error) {
+	args := m.Called(projectID, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockSchemaService) ExportSchema(projectID uuid.UUID, format string, userID uuid.UUID) (*services.SchemaExport, error) {
+	args := m.Called(projectID, format, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.SchemaExport), args.Error(1)
+}
+
+func (m *MockSchemaService) ExportRelease(projectID uuid.UUID, release, format string, userID uuid.UUID) (*services.SchemaExport, error) {
+	args := m.Called(projectID, release, format, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.SchemaExport), args.Error(1)
+}
+
+func (m *MockSchemaService) PlanApply(projectID uuid.UUID, req *dto.PlanApplyRequest, userID uuid.UUID) (*services.ApplyPlan, error) {
+	args := m.Called(projectID, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ApplyPlan), args.Error(1)
+}
+
+func (m *MockSchemaService) ExecuteApply(projectID uuid.UUID, req *dto.ExecuteApplyRequest, userID uuid.UUID) (*services.ApplyLog, error) {
+	args := m.Called(projectID, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.ApplyLog), args.Error(1)
+}
+
+func (m *MockSchemaService) SuggestRelationships(projectID, userID uuid.UUID) ([]services.RelationshipSuggestion, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]services.RelationshipSuggestion), args.Error(1)
+}
+
+func (m *MockSchemaService) AcceptRelationshipSuggestions(projectID uuid.UUID, reqs []dto.SchemaRelationshipRequest, userID uuid.UUID) (*services.Schema, error) {
+	args := m.Called(projectID, reqs, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.Schema), args.Error(1)
+}
+
+func (m *MockSchemaService) LintSchema(projectID, userID uuid.UUID) ([]lint.Finding, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]lint.Finding), args.Error(1)
+}
+
+func (m *MockSchemaService) Rename(projectID uuid.UUID, req *dto.RenameRequest, userID uuid.UUID) (*models.SchemaRename, error) {
+	args := m.Called(projectID, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SchemaRename), args.Error(1)
+}
+
+func (m *MockSchemaService) ListRenames(projectID, userID uuid.UUID) ([]*models.SchemaRename, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SchemaRename), args.Error(1)
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSearchService) Search(userID uuid.UUID, text string, projectID *uuid.UUID, types []string, limit int) ([]*repository.SearchHit, error) {
+	args := m.Called(userID, text, projectID, types, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repository.SearchHit), args.Error(1)
+}
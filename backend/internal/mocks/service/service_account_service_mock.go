@@ -0,0 +1,55 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockServiceAccountService struct {
+	mock.Mock
+}
+
+func (m *MockServiceAccountService) CreateServiceAccount(projectID uuid.UUID, name string, scopes []string, userID uuid.UUID) (*models.ServiceAccount, error) {
+	args := m.Called(projectID, name, scopes, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ServiceAccount), args.Error(1)
+}
+
+func (m *MockServiceAccountService) GetServiceAccountsByProjectID(projectID, userID uuid.UUID) ([]*models.ServiceAccount, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ServiceAccount), args.Error(1)
+}
+
+func (m *MockServiceAccountService) DeleteServiceAccount(projectID, id, userID uuid.UUID) error {
+	args := m.Called(projectID, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockServiceAccountService) CreateToken(projectID, id uuid.UUID, name string, scopes []string, expiresAt *time.Time, userID uuid.UUID) (*models.APIToken, string, error) {
+	args := m.Called(projectID, id, name, scopes, expiresAt, userID)
+	if args.Get(0) == nil {
+		return nil, args.String(1), args.Error(2)
+	}
+	return args.Get(0).(*models.APIToken), args.String(1), args.Error(2)
+}
+
+func (m *MockServiceAccountService) GetTokens(projectID, id, userID uuid.UUID) ([]*models.APIToken, error) {
+	args := m.Called(projectID, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.APIToken), args.Error(1)
+}
+
+func (m *MockServiceAccountService) RevokeToken(projectID, id, tokenID, userID uuid.UUID) error {
+	args := m.Called(projectID, id, tokenID, userID)
+	return args.Error(0)
+}
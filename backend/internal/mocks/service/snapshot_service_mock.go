@@ -0,0 +1,36 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSnapshotService struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotService) GetSnapshots(projectID, userID uuid.UUID) ([]*models.ProjectSnapshot, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ProjectSnapshot), args.Error(1)
+}
+
+func (m *MockSnapshotService) GetRetention(projectID, userID uuid.UUID) (*services.SnapshotRetention, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.SnapshotRetention), args.Error(1)
+}
+
+func (m *MockSnapshotService) SetRetention(projectID, userID uuid.UUID, keepDaily, keepMonthly *int) (*services.SnapshotRetention, error) {
+	args := m.Called(projectID, userID, keepDaily, keepMonthly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.SnapshotRetention), args.Error(1)
+}
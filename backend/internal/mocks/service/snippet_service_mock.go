@@ -0,0 +1,49 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSnippetService struct {
+	mock.Mock
+}
+
+func (m *MockSnippetService) CreateSnippet(projectID uuid.UUID, req *dto.CreateSnippetRequest, userID uuid.UUID) (*models.Snippet, error) {
+	args := m.Called(projectID, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Snippet), args.Error(1)
+}
+
+func (m *MockSnippetService) GetSnippets(projectID, userID uuid.UUID) ([]*models.Snippet, error) {
+	args := m.Called(projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Snippet), args.Error(1)
+}
+
+func (m *MockSnippetService) GetSnippet(projectID, id, userID uuid.UUID) (*models.Snippet, error) {
+	args := m.Called(projectID, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Snippet), args.Error(1)
+}
+
+func (m *MockSnippetService) UpdateSnippet(projectID, id uuid.UUID, req *dto.UpdateSnippetRequest, userID uuid.UUID) (*models.Snippet, error) {
+	args := m.Called(projectID, id, req, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Snippet), args.Error(1)
+}
+
+func (m *MockSnippetService) DeleteSnippet(projectID, id, userID uuid.UUID) error {
+	args := m.Called(projectID, id, userID)
+	return args.Error(0)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -11,8 +12,8 @@ type MockTableService struct {
 	mock.Mock
 }
 
-func (m *MockTableService) CreateTable(projectID uuid.UUID, name string, posX, posY float64, userID uuid.UUID) (*models.Table, error) {
-	args := m.Called(projectID, name, posX, posY, userID)
+func (m *MockTableService) CreateTable(projectID uuid.UUID, req *dto.CreateTableRequest, userID uuid.UUID) (*models.Table, error) {
+	args := m.Called(projectID, req, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -35,6 +36,14 @@ func (m *MockTableService) GetTablesByProjectID(projectID uuid.UUID) ([]*models.
 	return args.Get(0).([]*models.Table), args.Error(1)
 }
 
+func (m *MockTableService) ListTables(projectID uuid.UUID, filter repository.TableFilter, page repository.PageQuery) ([]*models.Table, string, error) {
+	args := m.Called(projectID, filter, page)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).([]*models.Table), args.String(1), args.Error(2)
+}
+
 func (m *MockTableService) UpdateTable(id uuid.UUID, req *dto.UpdateTableRequest, userID uuid.UUID) (*models.Table, error) {
 	args := m.Called(id, req, userID)
 	if args.Get(0) == nil {
@@ -48,7 +57,7 @@ func (m *MockTableService) UpdateTablePosition(id uuid.UUID, posX, posY float64,
 	return args.Error(0)
 }
 
-func (m *MockTableService) DeleteTable(id uuid.UUID, userID uuid.UUID) error {
+func (m *MockTableService) DeleteTable(id uuid.UUID, userID uuid.UUID) (int64, error) {
 	args := m.Called(id, userID)
-	return args.Error(0)
+	return args.Get(0).(int64), args.Error(1)
 }
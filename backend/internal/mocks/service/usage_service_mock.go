@@ -0,0 +1,19 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUsageService struct {
+	mock.Mock
+}
+
+func (m *MockUsageService) GetUsage(userID uuid.UUID) (*services.Usage, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.Usage), args.Error(1)
+}
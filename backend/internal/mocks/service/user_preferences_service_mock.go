@@ -0,0 +1,28 @@
+package service
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserPreferencesService struct {
+	mock.Mock
+}
+
+func (m *MockUserPreferencesService) GetPreferences(userID uuid.UUID) (*models.UserPreferences, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserPreferences), args.Error(1)
+}
+
+func (m *MockUserPreferencesService) UpdatePreferences(userID uuid.UUID, req *dto.UpdateUserPreferencesRequest) (*models.UserPreferences, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserPreferences), args.Error(1)
+}
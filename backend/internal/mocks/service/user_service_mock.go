@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -35,12 +36,12 @@ func (m *MockUserService) GetUserByEmail(email string) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *MockUserService) GetAllUsers() ([]*models.User, error) {
-	args := m.Called()
+func (m *MockUserService) ListUsers(filter repository.UserFilter, page repository.PageQuery) ([]*models.User, string, error) {
+	args := m.Called(filter, page)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, "", args.Error(2)
 	}
-	return args.Get(0).([]*models.User), args.Error(1)
+	return args.Get(0).([]*models.User), args.String(1), args.Error(2)
 }
 
 func (m *MockUserService) UpdateUser(id uuid.UUID, req *dto.UpdateUserRequest) (*models.User, error) {
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/services"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockUserSessionService struct {
+	mock.Mock
+}
+
+func (m *MockUserSessionService) StartSession(user *models.User, ipAddress, userAgent string) (*services.TokenPair, error) {
+	args := m.Called(user, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.TokenPair), args.Error(1)
+}
+
+func (m *MockUserSessionService) StartImpersonationSession(user *models.User, impersonatorID uuid.UUID, ttl time.Duration, ipAddress, userAgent string) (*services.TokenPair, error) {
+	args := m.Called(user, impersonatorID, ttl, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.TokenPair), args.Error(1)
+}
+
+func (m *MockUserSessionService) RefreshSession(refreshToken, ipAddress, userAgent string) (*services.TokenPair, error) {
+	args := m.Called(refreshToken, ipAddress, userAgent)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.TokenPair), args.Error(1)
+}
+
+func (m *MockUserSessionService) EndSession(refreshToken string) error {
+	args := m.Called(refreshToken)
+	return args.Error(0)
+}
+
+func (m *MockUserSessionService) ValidateSession(sessionID uuid.UUID) (*models.UserSession, error) {
+	args := m.Called(sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSession), args.Error(1)
+}
+
+func (m *MockUserSessionService) GetActiveSessions(userID uuid.UUID) ([]*models.UserSession, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.UserSession), args.Error(1)
+}
+
+func (m *MockUserSessionService) RevokeSession(userID, sessionID uuid.UUID) error {
+	args := m.Called(userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockUserSessionService) RevokeOtherSessions(userID, currentSessionID uuid.UUID) error {
+	args := m.Called(userID, currentSessionID)
+	return args.Error(0)
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Admin audit actions
+const (
+	AuditActionUserDisabled      = "user.disabled"
+	AuditActionUserEnabled       = "user.enabled"
+	AuditActionUserRoleChanged   = "user.role_changed"
+	AuditActionUserPasswordReset = "user.password_reset_forced"
+	AuditActionUserImpersonated  = "user.impersonated"
+	AuditActionImpersonatedWrite = "user.impersonated_write" // A change made in an impersonation session
+)
+
+// AdminAuditLog records an action an admin took on another account. Entries are never updated.
+type AdminAuditLog struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ActorID      uuid.UUID `gorm:"type:uuid;not null;index" json:"actor_id"`
+	Action       string    `gorm:"not null;index" json:"action"`
+	TargetUserID uuid.UUID `gorm:"type:uuid;not null;index" json:"target_user_id"`
+	Details      string    `json:"details,omitempty"` // Free-form context, e.g. the reason given or the new role
+	IPAddress    string    `json:"ip_address"`
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Branch is a copy of a project's schema edited apart from it, to try changes
+// out or propose them for review, then merged back. Schemas are kept as
+// createSchema requests, layout included. A merged branch can no longer change.
+type Branch struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID uuid.UUID  `gorm:"type:uuid;not null" json:"project_id"`
+	Name      string     `gorm:"not null" json:"name"`              // Unique within the project
+	Base      string     `gorm:"type:jsonb;not null" json:"-"`      // The project's schema when branched, merges compare against it
+	Schema    string     `gorm:"type:jsonb;not null" json:"-"`      // The branch's schema
+	Version   int64      `gorm:"not null;default:1" json:"version"` // Advances on every change, for optimistic concurrency
+	CreatedBy uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	MergedBy  *uuid.UUID `gorm:"type:uuid" json:"merged_by"`
+	MergedAt  *time.Time `json:"merged_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Sequence is the project sequence of the last notification about the
+	// merge, or 0. It is not stored with the branch.
+	Sequence int64 `gorm:"-" json:"-"`
+}
@@ -14,6 +14,7 @@ type CollaborationSession struct {
 	CursorX    *float64   `json:"cursor_x"`
 	CursorY    *float64   `json:"cursor_y"`
 	UserColor  string     `json:"user_color"`
+	Status     string     `gorm:"default:active" json:"status"` // active, idle or away
 	IsActive   bool       `gorm:"default:true" json:"is_active"`
 	LastPingAt time.Time  `json:"last_ping_at"`
 	JoinedAt   time.Time  `json:"joined_at"`
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DocRevision is one edit of a project's doc, the Markdown document keeping
+// the reasons behind the model next to it. Every edit saves the whole
+// document as the next version; the latest revision is the doc.
+type DocRevision struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID uuid.UUID `gorm:"type:uuid;not null" json:"project_id"`
+	Version   int64     `gorm:"not null" json:"version"` // 1 for the first edit, unique within the project
+	Content   string    `gorm:"not null" json:"content"` // Markdown
+	EditedBy  uuid.UUID `gorm:"type:uuid;not null" json:"edited_by"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Sequence is the project sequence of the notification about the edit,
+	// or 0. It is not stored with the revision.
+	Sequence int64 `gorm:"-" json:"-"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Drift check statuses
+const (
+	DriftCheckPending = "pending"
+	DriftCheckClean   = "clean"   // The database matches the model
+	DriftCheckDrifted = "drifted" // The database deviates from the model
+	DriftCheckFailed  = "failed"
+)
+
+// Data profile statuses
+const (
+	DataProfilePending = "pending"
+	DataProfileReady   = "ready"
+	DataProfileFailed  = "failed"
+)
+
+// What started a drift check
+const (
+	DriftTriggerManual    = "manual"
+	DriftTriggerScheduled = "scheduled"
+)
+
+// ConnectionProfile is how a project's live database is reached to check it
+// for drift from the model. The database is only ever read.
+type ConnectionProfile struct {
+	ProjectID       uuid.UUID `gorm:"type:uuid;primary_key" json:"project_id"`
+	DSN             string    `gorm:"column:dsn;not null" json:"-"` // Encrypted connection string
+	SchemaName      string    `gorm:"not null;default:'public'" json:"schema_name"`
+	ScheduledChecks bool      `gorm:"not null;default:true" json:"scheduled_checks"` // Checked daily as well as on demand
+	WebhookURL      string    `gorm:"not null;default:''" json:"webhook_url"`        // Notified when drift is found; none when empty
+	WebhookSecret   string    `gorm:"not null;default:''" json:"-"`                  // Encrypted key signing webhook deliveries
+	CreatedBy       uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+
+	// The latest profiling of the database, see TableProfile and FieldProfile
+	DataProfileStatus string     `gorm:"not null;default:''" json:"data_profile_status"` // Empty when never profiled
+	DataProfileError  string     `gorm:"not null;default:''" json:"data_profile_error"`
+	DataProfiledAt    *time.Time `json:"data_profiled_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DriftCheck is one comparison of a project's live database to its model
+type DriftCheck struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID   uuid.UUID  `gorm:"type:uuid;not null" json:"project_id"`
+	Status      string     `gorm:"not null" json:"status"`
+	Trigger     string     `gorm:"not null" json:"trigger"`
+	Diff        string     `gorm:"not null;default:''" json:"-"`     // JSON of what changes from the model to the database, once completed
+	Error       string     `gorm:"not null;default:''" json:"error"` // Why the check failed
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+// TableProfile is the row count of a table's counterpart in the project's
+// live database, as estimated by the database at the latest profiling
+type TableProfile struct {
+	TableID    uuid.UUID `gorm:"type:uuid;primary_key" json:"table_id"`
+	ProjectID  uuid.UUID `gorm:"type:uuid;not null" json:"project_id"`
+	RowCount   *int64    `json:"row_count"` // Nil when the database never analyzed the table
+	ProfiledAt time.Time `gorm:"not null" json:"profiled_at"`
+}
+
+// FieldProfile is how a field's counterpart in the project's live database
+// is filled, as estimated by the database at the latest profiling
+type FieldProfile struct {
+	FieldID       uuid.UUID `gorm:"type:uuid;primary_key" json:"field_id"`
+	TableID       uuid.UUID `gorm:"type:uuid;not null" json:"table_id"`
+	ProjectID     uuid.UUID `gorm:"type:uuid;not null" json:"project_id"`
+	NullRatio     float64   `gorm:"not null" json:"null_ratio"` // Share of rows that are null, 0 to 1
+	DistinctCount int64     `gorm:"not null" json:"distinct_count"`
+	ProfiledAt    time.Time `gorm:"not null" json:"profiled_at"`
+}
@@ -9,13 +9,19 @@ import (
 // Field represents a column in a database table
 type Field struct {
 	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	TableID      uuid.UUID `gorm:"type:uuid;not null" json:"table_id"`
-	Name         string    `gorm:"not null" json:"name"`
+	TableID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_fields_table_lower_name" json:"table_id"`
+	Name         string    `gorm:"not null;uniqueIndex:idx_fields_table_lower_name,expression:lower(name)" json:"name"`
 	DataType     string    `gorm:"not null" json:"data_type"` // VARCHAR, INT, TEXT, etc.
 	IsPrimaryKey bool      `gorm:"default:false" json:"is_primary_key"`
 	IsNullable   bool      `gorm:"default:true" json:"is_nullable"`
 	DefaultValue string    `json:"default_value"`
 	Position     int       `json:"position"` // Field order in table
+	Metadata     Metadata  `gorm:"type:jsonb;not null" json:"metadata"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+	Version      int64     `gorm:"not null;default:1" json:"version"` // Advances on every change, for optimistic concurrency
+
+	// Sequence is the project sequence of the notification about the last change
+	// made through the service, or 0. It is not stored with the field.
+	Sequence int64 `gorm:"-" json:"-"`
 }
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Login failure reasons
+const (
+	LoginFailureInvalidCredentials = "invalid_credentials"
+	LoginFailureLocked             = "locked"
+)
+
+// LoginEvent records a password login attempt. Attempts for unknown emails
+// are recorded too so lockout behaves the same whether or not an account exists.
+type LoginEvent struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID        *uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
+	Email         string     `gorm:"not null;index:idx_login_event_email_created" json:"-"` // Normalized email the attempt was made for
+	IPAddress     string     `gorm:"not null;index:idx_login_event_ip_created" json:"ip_address"`
+	UserAgent     string     `json:"user_agent"`
+	Success       bool       `gorm:"not null" json:"success"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	Suspicious    bool       `gorm:"not null;default:false" json:"suspicious"` // Successful login from an IP not seen before for the user
+	CreatedAt     time.Time  `gorm:"index:idx_login_event_email_created;index:idx_login_event_ip_created" json:"created_at"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Limits of the metadata of one object
+const (
+	MaxMetadataKeys      = 50
+	MaxMetadataKeyLength = 100
+	MaxMetadataSize      = 8192 // Bytes of JSON
+)
+
+// Metadata is what an organization records about a schema object for its
+// own purposes, e.g. a data owner or a retention class. It is a JSON object
+// EzModel stores and exports without interpreting.
+type Metadata map[string]any
+
+// Value implements driver.Valuer, storing no metadata as an empty object
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(map[string]any(m))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (m *Metadata) Scan(value any) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Metadata", value)
+	}
+	return json.Unmarshal(data, (*map[string]any)(m))
+}
+
+// Merge returns m with patch applied as a JSON Merge Patch (RFC 7396):
+// members set to null are removed, objects are merged member by member and
+// other values replace what was there. m is left as it was.
+func (m Metadata) Merge(patch map[string]any) Metadata {
+	return Metadata(mergeObject(m, patch))
+}
+
+func mergeObject(target, patch map[string]any) map[string]any {
+	merged := make(map[string]any, len(target)+len(patch))
+	for key, value := range target {
+		merged[key] = value
+	}
+	for key, value := range patch {
+		switch value := value.(type) {
+		case nil:
+			delete(merged, key)
+		case map[string]any:
+			current, _ := merged[key].(map[string]any)
+			merged[key] = mergeObject(current, value)
+		default:
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// Valid reports whether m is within the limits of metadata
+func (m Metadata) Valid() bool {
+	if len(m) > MaxMetadataKeys {
+		return false
+	}
+	for key := range m {
+		if key == "" || len(key) > MaxMetadataKeyLength {
+			return false
+		}
+	}
+	data, err := json.Marshal(map[string]any(m))
+	return err == nil && len(data) <= MaxMetadataSize
+}
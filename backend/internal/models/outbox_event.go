@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is a collaboration message saved in the transaction of the
+// change it describes, and delivered to the project's clients once that
+// change has committed
+type OutboxEvent struct {
+	ID           int64      `gorm:"primaryKey;autoIncrement" json:"id"` // Delivery order
+	ProjectID    uuid.UUID  `gorm:"type:uuid;not null" json:"project_id"`
+	Message      []byte     `gorm:"type:jsonb;not null" json:"message"` // Encoded WebSocket message
+	Attempts     int        `gorm:"not null;default:0" json:"attempts"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DispatchedAt *time.Time `json:"dispatched_at"`
+}
@@ -6,6 +6,13 @@ import (
 	"github.com/google/uuid"
 )
 
+// Roles of a project's members. Collaborators are editors unless made viewers.
+const (
+	ProjectRoleOwner  = "owner"
+	ProjectRoleEditor = "editor"
+	ProjectRoleViewer = "viewer" // Can open the project but not change it
+)
+
 // Project represents a database schema design project
 type Project struct {
 	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
@@ -14,12 +21,19 @@ type Project struct {
 	OwnerID      uuid.UUID `gorm:"type:uuid;not null" json:"owner_id"`
 	DatabaseType string    `gorm:"default:'postgresql'" json:"database_type"` // postgresql, mysql, sqlite, sqlserver
 	CanvasData   string    `gorm:"type:jsonb" json:"canvas_data"`             // Visual layout/positioning data
+	Metadata     Metadata  `gorm:"type:jsonb;not null" json:"metadata"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+	Version      int64     `gorm:"not null;default:1" json:"version"`           // Advances on every change, for optimistic concurrency
+	Sequence     int64     `gorm:"not null;default:0;<-:false" json:"sequence"` // Numbers the project's changes; see ProjectRepository.NextSequence
+
+	// Number of tables, only counted by listings that include it
+	TablesCount int64 `gorm:"->;-:migration" json:"-"`
 
 	// Relationships
 	Owner         User           `gorm:"foreignKey:OwnerID" json:"owner,omitempty"`
 	Collaborators []User         `gorm:"many2many:project_collaborators;" json:"collaborators,omitempty"`
 	Tables        []Table        `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"tables,omitempty"`
 	Relationships []Relationship `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"relationships,omitempty"`
+	Tags          []ProjectTag   `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"tags,omitempty"`
 }
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectEvent is a schema-change message broadcast to a project's
+// collaborators, kept so clients can catch up on the changes they missed
+type ProjectEvent struct {
+	ProjectID uuid.UUID `gorm:"type:uuid;primaryKey" json:"project_id"`
+	Sequence  int64     `gorm:"primaryKey;autoIncrement:false" json:"sequence"`
+	Type      string    `gorm:"not null" json:"type"`
+	Message   []byte    `gorm:"type:jsonb;not null" json:"message"` // Encoded WebSocket message
+	CreatedAt time.Time `json:"created_at"`
+}
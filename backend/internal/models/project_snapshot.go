@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectSnapshot is a copy of a project's schema at one point in time, taken
+// by the nightly snapshot job
+type ProjectSnapshot struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID       uuid.UUID `gorm:"type:uuid;not null" json:"project_id"`
+	ProjectVersion  int64     `gorm:"not null" json:"project_version"`  // Version of the project when taken
+	ProjectSequence int64     `gorm:"not null" json:"project_sequence"` // Sequence of the project's changes when taken
+	Data            string    `gorm:"type:jsonb;not null" json:"-"`     // The project with its tables, fields, relationships and regions
+	SizeBytes       int64     `gorm:"not null;default:0" json:"size_bytes"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// SnapshotPolicy overrides how many snapshots of a project are kept. A nil
+// count uses the instance default.
+type SnapshotPolicy struct {
+	ProjectID   uuid.UUID `gorm:"type:uuid;primary_key" json:"project_id"`
+	KeepDaily   *int      `json:"keep_daily"`   // Latest snapshot of each of this many days
+	KeepMonthly *int      `json:"keep_monthly"` // Latest snapshot of each of this many months
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectTag is a label organizing a project, shared by everyone on the project
+type ProjectTag struct {
+	ProjectID uuid.UUID `gorm:"type:uuid;primaryKey" json:"project_id"`
+	Name      string    `gorm:"primaryKey;size:50;index" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ProjectStar marks a project as a favorite of one user
+type ProjectStar struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	ProjectID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"project_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	User    User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectView records when a user last opened a project
+type ProjectView struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	ProjectID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"project_id"`
+	ViewedAt  time.Time `gorm:"not null;index" json:"viewed_at"`
+
+	// Relationships
+	User    User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"project"`
+}
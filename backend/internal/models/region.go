@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Region is a named, colored area of the canvas clustering related tables,
+// e.g. "Billing domain". A table is in at most one region.
+type Region struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID uuid.UUID `gorm:"type:uuid;not null;index" json:"project_id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Color     string    `gorm:"not null" json:"color"`  // Hex color, e.g. #3b82f6
+	PosX      float64   `json:"pos_x"`                  // Canvas position of the top left corner
+	PosY      float64   `json:"pos_y"`                  // Canvas position of the top left corner
+	Width     float64   `gorm:"not null" json:"width"`  // In canvas units
+	Height    float64   `gorm:"not null" json:"height"` // In canvas units
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int64     `gorm:"not null;default:1" json:"version"` // Advances on every change, for optimistic concurrency
+
+	// TableIDs are the tables in the region. They are stored with the tables
+	// and filled in by the repository.
+	TableIDs []uuid.UUID `gorm:"-" json:"table_ids"`
+
+	// Sequence is the project sequence of the notification about the last change
+	// made through the service, or 0. It is not stored with the region.
+	Sequence int64 `gorm:"-" json:"-"`
+}
@@ -1,11 +1,22 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	// AnchorAuto leaves the side an edge leaves or enters a table by to the client
+	AnchorAuto = "auto"
+
+	// DefaultLabelPosition puts a relationship's label halfway along its edge
+	DefaultLabelPosition = 0.5
+)
+
 // Relationship represents a foreign key relationship between tables
 type Relationship struct {
 	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
@@ -15,6 +26,58 @@ type Relationship struct {
 	TargetTableID uuid.UUID `gorm:"type:uuid;not null" json:"target_table_id"`
 	TargetFieldID uuid.UUID `gorm:"type:uuid;not null" json:"target_field_id"`
 	RelationType  string    `gorm:"default:'one_to_many'" json:"relation_type"` // one_to_one, one_to_many, many_to_many
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+
+	// How the edge is drawn, so every client renders the same route
+	SourceAnchor  string    `gorm:"not null;default:auto" json:"source_anchor"` // auto, top, right, bottom or left
+	TargetAnchor  string    `gorm:"not null;default:auto" json:"target_anchor"` // auto, top, right, bottom or left
+	Waypoints     Waypoints `gorm:"type:jsonb;not null" json:"waypoints"`       // Canvas points the edge passes through, in order
+	LabelPosition float64   `gorm:"not null" json:"label_position"`             // Fraction of the edge's length from the source
+
+	Metadata Metadata `gorm:"type:jsonb;not null" json:"metadata"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Version   int64     `gorm:"not null;default:1" json:"version"` // Advances on every change, for optimistic concurrency
+
+	// Sequence is the project sequence of the notification about the last change
+	// made through the service, or 0. It is not stored with the relationship.
+	Sequence int64 `gorm:"-" json:"-"`
+}
+
+// Point is a position on the canvas
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Waypoints are stored as a JSON array of points
+type Waypoints []Point
+
+// Value implements driver.Valuer, storing no waypoints as an empty array
+func (w Waypoints) Value() (driver.Value, error) {
+	if w == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (w *Waypoints) Scan(value any) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*w = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Waypoints", value)
+	}
+	return json.Unmarshal(data, w)
 }
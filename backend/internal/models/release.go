@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Release names a snapshot of a project, such as v1.0, so that the schema
+// as it was released can be fetched again however the project changed since.
+// A locked release can no longer be renamed, moved to another snapshot or
+// deleted until it is unlocked.
+type Release struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID  uuid.UUID `gorm:"type:uuid;not null" json:"project_id"`
+	Name       string    `gorm:"not null" json:"name"` // Unique within the project
+	Notes      string    `gorm:"not null;default:''" json:"notes"`
+	SnapshotID uuid.UUID `gorm:"type:uuid;not null" json:"snapshot_id"` // Pinned, so never pruned
+	Locked     bool      `gorm:"not null;default:false" json:"locked"`
+	CreatedBy  uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewComment is a remark a schema review made about a table, or one of its
+// fields, kept until someone resolves it. The names are those at the time of
+// the review; the IDs are cleared when the table or field is deleted.
+type ReviewComment struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID  uuid.UUID  `gorm:"type:uuid;not null" json:"project_id"`
+	TableID    *uuid.UUID `gorm:"type:uuid" json:"table_id"`
+	FieldID    *uuid.UUID `gorm:"type:uuid" json:"field_id"`
+	TableName  string     `gorm:"not null" json:"table_name"`
+	FieldName  string     `gorm:"not null;default:''" json:"field_name"` // Empty for the whole table
+	Kind       string     `gorm:"not null" json:"kind"`                  // One of review.Kinds
+	Message    string     `gorm:"not null" json:"message"`
+	Suggestion string     `gorm:"not null;default:''" json:"suggestion"`
+	Provider   string     `gorm:"not null" json:"provider"` // What made the review, "rules" or a model
+	CreatedBy  uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	ResolvedBy *uuid.UUID `gorm:"type:uuid" json:"resolved_by"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
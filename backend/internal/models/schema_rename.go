@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchemaRename records a table or field renamed by refactoring, so
+// migrations of a database built before rename it rather than drop and
+// create it. Names are as they were at the time, so a project's renames
+// replay in order.
+type SchemaRename struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID uuid.UUID  `gorm:"type:uuid;not null" json:"project_id"`
+	TableID   uuid.UUID  `gorm:"type:uuid;not null" json:"table_id"`
+	FieldID   *uuid.UUID `gorm:"type:uuid" json:"field_id"`  // Nil when the table was renamed
+	TableName string     `gorm:"not null" json:"table_name"` // Of the table at the time, before the rename when it was the table renamed
+	OldName   string     `gorm:"not null" json:"old_name"`   // Of the table or field renamed
+	NewName   string     `gorm:"not null" json:"new_name"`
+	RenamedBy uuid.UUID  `gorm:"type:uuid;not null" json:"renamed_by"`
+	CreatedAt time.Time  `json:"created_at"`
+
+	// Sequence is the project sequence of the notification about the rename,
+	// or 0. It is not stored with the rename.
+	Sequence int64 `gorm:"-" json:"-"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceAccount is a non-human principal for automation such as CI pipelines.
+// It acts through a backing user that collaborates on a single project and
+// can only authenticate with API tokens restricted to that project.
+type ServiceAccount struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID   uuid.UUID `gorm:"type:uuid;not null;index" json:"project_id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"` // Backing user the account acts as
+	Name        string    `gorm:"not null" json:"name"`
+	Scopes      string    `gorm:"not null" json:"scopes"` // Comma-separated scopes its tokens may be granted
+	CreatedByID uuid.UUID `gorm:"type:uuid;not null" json:"created_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relationships
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+	User    User    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Snippet is named SQL kept with a project, e.g. a seed script or a query
+// that motivated the design. Exports of the schema carry the snippets along.
+type Snippet struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ProjectID   uuid.UUID `gorm:"type:uuid;not null" json:"project_id"`
+	Name        string    `gorm:"not null" json:"name"` // Unique within the project
+	Description string    `gorm:"not null;default:''" json:"description"`
+	SQL         string    `gorm:"column:sql;not null" json:"sql"`
+	CreatedBy   uuid.UUID `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
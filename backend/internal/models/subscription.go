@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Subscription is the paid plan of a user, kept in step with the payment
+// provider through its webhooks. Users without one are on the free plan.
+type Subscription struct {
+	UserID               uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Plan                 string     `gorm:"not null" json:"plan"`
+	Status               string     `gorm:"not null" json:"status"` // As reported by Stripe, e.g. active or past_due
+	StripeCustomerID     string     `gorm:"not null;index" json:"-"`
+	StripeSubscriptionID string     `gorm:"not null" json:"-"`
+	CurrentPeriodEnd     *time.Time `json:"current_period_end"`
+	// EventCreatedAt is when the last event applied was created, so events
+	// arriving out of order do not undo newer ones
+	EventCreatedAt time.Time `gorm:"not null" json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// IsActive reports whether the subscription grants its plan. Past due
+// subscriptions keep it while the provider retries the payment.
+func (s *Subscription) IsActive() bool {
+	switch s.Status {
+	case "active", "trialing", "past_due":
+		return true
+	default:
+		return false
+	}
+}
@@ -6,15 +6,33 @@ import (
 	"github.com/google/uuid"
 )
 
+// TableIcons are the icons a table can show next to its name
+var TableIcons = []string{
+	"database", "users", "user", "credit-card", "shopping-cart", "package", "file-text",
+	"settings", "lock", "globe", "calendar", "mail", "tag", "activity",
+}
+
 // Table represents a database table in the schema
 type Table struct {
 	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	ProjectID uuid.UUID `gorm:"type:uuid;not null" json:"project_id"`
-	Name      string    `gorm:"not null" json:"name"`
-	PosX      float64   `json:"pos_x"` // Canvas position
-	PosY      float64   `json:"pos_y"` // Canvas position
+	ProjectID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_tables_project_name" json:"project_id"`
+	Name      string    `gorm:"not null;uniqueIndex:idx_tables_project_name" json:"name"`
+	PosX      float64   `json:"pos_x"`                            // Canvas position
+	PosY      float64   `json:"pos_y"`                            // Canvas position
+	Color     string    `gorm:"not null;default:''" json:"color"` // Hex color of the header, or empty for the default
+	Icon      string    `gorm:"not null;default:''" json:"icon"`  // One of TableIcons, or empty for none
+	Metadata  Metadata  `gorm:"type:jsonb;not null" json:"metadata"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	Version   int64     `gorm:"not null;default:1" json:"version"` // Advances on every change, for optimistic concurrency
+
+	// FieldOrderSequence is the project sequence of the last confirmed reorder
+	// of the table's fields, or 0. See TableRepository.SetFieldOrderSequence.
+	FieldOrderSequence int64 `gorm:"not null;default:0;<-:false" json:"-"`
+
+	// Sequence is the project sequence of the notification about the last change
+	// made through the service, or 0. It is not stored with the table.
+	Sequence int64 `gorm:"-" json:"-"`
 
 	// Relationships
 	Fields []Field `gorm:"foreignKey:TableID;constraint:OnDelete:CASCADE" json:"fields,omitempty"`
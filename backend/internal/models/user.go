@@ -6,16 +6,33 @@ import (
 	"github.com/google/uuid"
 )
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin" // Can use the /api/admin routes
+)
+
 // User represents a user in the system
 type User struct {
-	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	Email        string    `gorm:"uniqueIndex;not null" json:"email"`
-	Username     string    `gorm:"uniqueIndex;not null" json:"username"`
-	PasswordHash string    `gorm:"not null" json:"-"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                    uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Email                 string     `gorm:"uniqueIndex;not null" json:"email"`
+	Username              string     `gorm:"uniqueIndex;not null" json:"username"`
+	PasswordHash          string     `gorm:"not null" json:"-"`
+	AvatarURL             string     `gorm:"not null;default:''" json:"avatar_url"`
+	AvatarKey             string     `gorm:"not null;default:''" json:"-"`                     // Storage key of the avatar, to delete it when replaced
+	IsServiceAccount      bool       `gorm:"not null;default:false" json:"is_service_account"` // Backing user of a service account; has no password
+	Role                  string     `gorm:"not null;default:user;index" json:"role"`
+	DisabledAt            *time.Time `json:"disabled_at"`                                           // Disabled accounts cannot sign in
+	PasswordResetRequired bool       `gorm:"not null;default:false" json:"password_reset_required"` // Set by an admin, cleared on password change
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
 
 	// Relationships
 	OwnedProjects        []Project `gorm:"foreignKey:OwnerID" json:"owned_projects,omitempty"`
 	CollaboratedProjects []Project `gorm:"many2many:project_collaborators;" json:"collaborated_projects,omitempty"`
 }
+
+// IsDisabled reports whether an admin has disabled the account
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
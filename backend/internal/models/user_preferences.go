@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserPreferences holds a user's settings so they follow the user across devices
+type UserPreferences struct {
+	UserID                   uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Theme                    string    `gorm:"not null" json:"theme"`                 // light, dark or system
+	DefaultDatabaseType      string    `gorm:"not null" json:"default_database_type"` // For new projects
+	CanvasShowGrid           bool      `gorm:"not null" json:"canvas_show_grid"`
+	CanvasSnapToGrid         bool      `gorm:"not null" json:"canvas_snap_to_grid"`
+	CanvasGridSize           int       `gorm:"not null" json:"canvas_grid_size"` // Pixels between grid lines
+	NotifyCollaboratorJoined bool      `gorm:"not null" json:"notify_collaborator_joined"`
+	NotifyProjectShared      bool      `gorm:"not null" json:"notify_project_shared"`
+	EmailNotifications       bool      `gorm:"not null" json:"email_notifications"`
+	UpdatedAt                time.Time `json:"updated_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// DefaultUserPreferences returns the preferences of a user who has not changed any.
+// Columns have no database defaults because GORM would apply them to false values.
+func DefaultUserPreferences(userID uuid.UUID) *UserPreferences {
+	return &UserPreferences{
+		UserID:                   userID,
+		Theme:                    "system",
+		DefaultDatabaseType:      "postgresql",
+		CanvasShowGrid:           true,
+		CanvasSnapToGrid:         false,
+		CanvasGridSize:           20,
+		NotifyCollaboratorJoined: true,
+		NotifyProjectShared:      true,
+		EmailNotifications:       true,
+	}
+}
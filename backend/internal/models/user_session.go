@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserSession is a signed-in device. Refresh tokens carry the session ID and
+// are rotated on every refresh; only a hash of the current one is stored.
+type UserSession struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	RefreshTokenHash string     `gorm:"not null" json:"-"`
+	IPAddress        string     `json:"ip_address"` // Address of the most recent sign-in or refresh
+	UserAgent        string     `json:"user_agent"`
+	LastSeenAt       time.Time  `gorm:"not null" json:"last_seen_at"`
+	ExpiresAt        time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at"`
+	ImpersonatorID   *uuid.UUID `gorm:"type:uuid" json:"impersonator_id"` // Admin who started the session to act as the user
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// IsActive reports whether the session is neither revoked nor expired at the given time
+func (s *UserSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
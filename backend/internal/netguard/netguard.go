@@ -0,0 +1,49 @@
+// Package netguard keeps the connections made on behalf of users, to webhooks
+// or to their databases, off the server's own network.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"syscall"
+)
+
+// ErrNotPublic is returned for addresses that are not public
+var ErrNotPublic = errors.New("not a public address")
+
+// blockedPrefixes are the non-public ranges netip does not classify: "this
+// network" and the shared address space of carrier-grade NAT
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// Public reports whether ip may be reached from the server, excluding
+// loopback, private, link-local, multicast and unspecified addresses
+func Public(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Control is a net.Dialer Control refusing connections to addresses that are
+// not public. It checks the address actually dialed, so a name resolving to a
+// private, loopback or link-local one is refused too.
+func Control(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !Public(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrNotPublic, addrPort.Addr())
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package netguard
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublic(t *testing.T) {
+	for _, address := range []string{"93.184.216.34", "2606:2800:220:1::1"} {
+		assert.True(t, Public(netip.MustParseAddr(address)), address)
+	}
+	for _, address := range []string{"127.0.0.1", "10.0.0.5", "192.168.1.1", "169.254.169.254",
+		"0.0.0.0", "100.64.0.1", "::1", "fe80::1", "::ffff:127.0.0.1", "224.0.0.1"} {
+		assert.False(t, Public(netip.MustParseAddr(address)), address)
+	}
+}
+
+func TestControl(t *testing.T) {
+	assert.NoError(t, Control("tcp4", "93.184.216.34:5432", nil))
+	assert.ErrorIs(t, Control("tcp4", "127.0.0.1:5432", nil), ErrNotPublic)
+	assert.ErrorIs(t, Control("tcp6", "[::1]:5432", nil), ErrNotPublic)
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired windows are dropped from memory
+const sweepInterval = time.Minute
+
+type window struct {
+	count   int64
+	resetAt time.Time
+}
+
+// MemoryLimiter keeps counters in process memory. Limits are per node.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	windows   map[string]*window
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		windows: make(map[string]*window),
+		now:     time.Now,
+	}
+}
+
+func (l *MemoryLimiter) Allow(key string, limit int, period time.Duration) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	w, exists := l.windows[key]
+	if !exists || !now.Before(w.resetAt) {
+		w = &window{resetAt: now.Add(period)}
+		l.windows[key] = w
+	}
+	w.count++
+
+	return result(w.count, limit, w.resetAt.Sub(now)), nil
+}
+
+// sweep drops expired windows so idle keys do not accumulate.
+// MUST be called with l.mu held.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Before(l.nextSweep) {
+		return
+	}
+	l.nextSweep = now.Add(sweepInterval)
+
+	for key, w := range l.windows {
+		if !now.Before(w.resetAt) {
+			delete(l.windows, key)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	now := time.Now()
+	l := NewMemoryLimiter()
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		result, err := l.Allow("login:1.2.3.4", 3, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2-i, result.Remaining)
+	}
+
+	now = now.Add(20 * time.Second)
+	result, err := l.Allow("login:1.2.3.4", 3, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 40*time.Second, result.RetryAfter)
+
+	// Other keys have their own budget
+	result, _ = l.Allow("login:5.6.7.8", 3, time.Minute)
+	assert.True(t, result.Allowed)
+
+	// A new window starts once the old one has passed
+	now = now.Add(40 * time.Second)
+	result, _ = l.Allow("login:1.2.3.4", 3, time.Minute)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+}
+
+func TestMemoryLimiterSweep(t *testing.T) {
+	now := time.Now()
+	l := NewMemoryLimiter()
+	l.now = func() time.Time { return now }
+
+	l.Allow("a", 1, time.Second)
+	l.Allow("b", 1, time.Hour)
+
+	now = now.Add(2 * sweepInterval)
+	l.Allow("c", 1, time.Second)
+
+	assert.NotContains(t, l.windows, "a")
+	assert.Contains(t, l.windows, "b")
+	assert.Contains(t, l.windows, "c")
+}
@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"log"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/redis"
+)
+
+// Result is the outcome of counting a request against a limit
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // Time until the current window resets; only set when not allowed
+}
+
+// Limiter counts requests per key in fixed windows
+type Limiter interface {
+	// Allow counts a request for key and reports whether it fits within limit requests per window
+	Allow(key string, limit int, window time.Duration) (Result, error)
+}
+
+// New returns a Redis-backed limiter shared by every node when Redis is
+// available and useRedis is set, and a node-local limiter otherwise
+func New(redisClient *redis.Client, useRedis bool) Limiter {
+	memory := NewMemoryLimiter()
+	if !useRedis || redisClient == nil || !redisClient.IsEnabled() {
+		log.Println("Rate limiting uses in-memory counters; limits apply per node")
+		return memory
+	}
+
+	return NewRedisLimiter(redisClient, memory)
+}
+
+// result builds a Result from the count within the current window
+func result(count int64, limit int, resetIn time.Duration) Result {
+	if count > int64(limit) {
+		return Result{RetryAfter: resetIn}
+	}
+	return Result{Allowed: true, Remaining: limit - int(count)}
+}
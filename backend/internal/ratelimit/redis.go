@@ -0,0 +1,32 @@
+package ratelimit
+
+import (
+	"log"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/redis"
+)
+
+// RedisLimiter keeps counters in Redis so limits hold across nodes. When Redis
+// is unreachable it falls back to node-local counters rather than failing requests.
+type RedisLimiter struct {
+	client   *redis.Client
+	fallback Limiter
+}
+
+func NewRedisLimiter(client *redis.Client, fallback Limiter) *RedisLimiter {
+	return &RedisLimiter{
+		client:   client,
+		fallback: fallback,
+	}
+}
+
+func (l *RedisLimiter) Allow(key string, limit int, period time.Duration) (Result, error) {
+	count, resetIn, err := l.client.IncrWindow(key, period)
+	if err != nil {
+		log.Printf("Rate limit counter unavailable in Redis, using local counter: %v", err)
+		return l.fallback.Allow(key, limit, period)
+	}
+
+	return result(count, limit, resetIn), nil
+}
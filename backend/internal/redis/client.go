@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -11,17 +12,32 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// ModeStreams selects Redis Streams instead of pub/sub for cross-region messages
+const ModeStreams = "streams"
+
 // Client wraps Redis client functionality
 type Client struct {
 	client  *redis.Client
 	enabled bool
 	ctx     context.Context
+
+	// Stream settings, only used in streams mode
+	useStreams   bool
+	streamMaxLen int64
+	streamReplay time.Duration
+}
+
+// StreamEntry is a message read from a Redis stream
+type StreamEntry struct {
+	ID     string
+	Origin string
+	Data   []byte
 }
 
 // NewClient creates a new Redis client
 func NewClient(cfg *config.Config) *Client {
 	if !cfg.Redis.Enabled {
-		log.Println("Redis is disabled, WebSocket messages will not sync across regions")
+		log.Println("Redis is disabled, WebSocket presence will not sync across nodes")
 		return &Client{
 			enabled: false,
 			ctx:     context.Background(),
@@ -61,11 +77,19 @@ func NewClient(cfg *config.Config) *Client {
 		}
 	}
 
+	useStreams := cfg.Redis.Mode == ModeStreams
+	if useStreams {
+		log.Println("Redis Streams mode enabled for WebSocket sync")
+	}
+
 	log.Printf("Redis connected successfully at %s", addr)
 	return &Client{
-		client:  rdb,
-		enabled: true,
-		ctx:     ctx,
+		client:       rdb,
+		enabled:      true,
+		ctx:          ctx,
+		useStreams:   useStreams,
+		streamMaxLen: cfg.Redis.StreamMaxLen,
+		streamReplay: cfg.Redis.StreamReplay,
 	}
 }
 
@@ -92,6 +116,179 @@ func (c *Client) Subscribe(channel string) *redis.PubSub {
 	return c.client.Subscribe(c.ctx, channel)
 }
 
+// UsesStreams returns whether cross-region messages go through Redis Streams
+func (c *Client) UsesStreams() bool {
+	return c.enabled && c.useStreams
+}
+
+// AppendToStream adds a message to a stream, trimming it to roughly the configured length
+func (c *Client) AppendToStream(stream, origin string, data []byte) error {
+	if !c.enabled {
+		return nil
+	}
+
+	return c.client.XAdd(c.ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: c.streamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"origin": origin,
+			"data":   data,
+		},
+	}).Err()
+}
+
+// StreamStartID returns the ID a new reader should start after. With a replay
+// window it points that far into the past, otherwise at the latest entry.
+func (c *Client) StreamStartID(stream string) string {
+	if c.streamReplay > 0 {
+		return fmt.Sprintf("%d-0", time.Now().Add(-c.streamReplay).UnixMilli())
+	}
+
+	// Resolve the latest ID now; re-reading from "$" after a timeout would skip
+	// entries added between two reads
+	entries, err := c.client.XRevRangeN(c.ctx, stream, "+", "-", 1).Result()
+	if err != nil {
+		return "$"
+	}
+	if len(entries) == 0 {
+		return "0-0"
+	}
+	return entries[0].ID
+}
+
+// ReadStream blocks until entries newer than lastID arrive or the block time
+// elapses. Returns no entries and no error on timeout.
+func (c *Client) ReadStream(ctx context.Context, stream, lastID string, block time.Duration) ([]StreamEntry, error) {
+	if !c.enabled {
+		return nil, nil
+	}
+
+	streams, err := c.client.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{stream, lastID},
+		Block:   block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []StreamEntry
+	for _, s := range streams {
+		for _, message := range s.Messages {
+			origin, _ := message.Values["origin"].(string)
+			data, _ := message.Values["data"].(string)
+			entries = append(entries, StreamEntry{
+				ID:     message.ID,
+				Origin: origin,
+				Data:   []byte(data),
+			})
+		}
+	}
+	return entries, nil
+}
+
+// HSetWithExpiry sets a hash field and refreshes the expiry of the whole hash
+func (c *Client) HSetWithExpiry(key, field string, value []byte, ttl time.Duration) error {
+	if !c.enabled {
+		return nil
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.HSet(c.ctx, key, field, value)
+	pipe.Expire(c.ctx, key, ttl)
+	_, err := pipe.Exec(c.ctx)
+	return err
+}
+
+// HGetAll returns every field of a hash
+func (c *Client) HGetAll(key string) (map[string]string, error) {
+	if !c.enabled {
+		return nil, nil
+	}
+
+	return c.client.HGetAll(c.ctx, key).Result()
+}
+
+// HDel removes fields from a hash
+func (c *Client) HDel(key string, fields ...string) error {
+	if !c.enabled {
+		return nil
+	}
+
+	return c.client.HDel(c.ctx, key, fields...).Err()
+}
+
+// SetNX sets a key that expires after ttl unless it exists, reporting whether it was set
+func (c *Client) SetNX(key string, value []byte, ttl time.Duration) (bool, error) {
+	if !c.enabled {
+		return false, fmt.Errorf("redis is disabled")
+	}
+
+	return c.client.SetNX(c.ctx, key, value, ttl).Result()
+}
+
+// Set sets a key that expires after ttl
+func (c *Client) Set(key string, value []byte, ttl time.Duration) error {
+	if !c.enabled {
+		return fmt.Errorf("redis is disabled")
+	}
+
+	return c.client.Set(c.ctx, key, value, ttl).Err()
+}
+
+// Get returns the value of a key, or nil when it does not exist
+func (c *Client) Get(key string) ([]byte, error) {
+	if !c.enabled {
+		return nil, fmt.Errorf("redis is disabled")
+	}
+
+	value, err := c.client.Get(c.ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// Del removes keys
+func (c *Client) Del(keys ...string) error {
+	if !c.enabled {
+		return nil
+	}
+
+	return c.client.Del(c.ctx, keys...).Err()
+}
+
+// incrWindowScript increments a counter and starts its expiry on the first increment,
+// returning the count and the remaining time to live in milliseconds
+var incrWindowScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}
+`)
+
+// IncrWindow increments a fixed-window counter that expires after window.
+// It returns the count within the window and the time until the window resets.
+func (c *Client) IncrWindow(key string, window time.Duration) (int64, time.Duration, error) {
+	if !c.enabled {
+		return 0, 0, fmt.Errorf("redis is disabled")
+	}
+
+	values, err := incrWindowScript.Run(c.ctx, c.client, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(values) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	return values[0], time.Duration(values[1]) * time.Millisecond, nil
+}
+
 // Close closes the Redis connection
 func (c *Client) Close() error {
 	if !c.enabled || c.client == nil {
@@ -102,10 +299,10 @@ func (c *Client) Close() error {
 }
 
 // Ping checks if Redis is still connected
-func (c *Client) Ping() error {
+func (c *Client) Ping(ctx context.Context) error {
 	if !c.enabled {
 		return nil
 	}
 
-	return c.client.Ping(c.ctx).Err()
+	return c.client.Ping(ctx).Err()
 }
@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// claimJobScript moves the first member of the queue due by ARGV[1] to the
+// active set, scored by its lease deadline ARGV[2], and returns it
+var claimJobScript = redis.NewScript(`
+local due = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1], "LIMIT", 0, 1)
+if #due == 0 then
+	return false
+end
+redis.call("ZREM", KEYS[1], due[1])
+redis.call("ZADD", KEYS[2], ARGV[2], due[1])
+return due[1]
+`)
+
+// recoverJobsScript moves the members of the active set whose lease ended by
+// ARGV[1] back to the queue, due at once
+var recoverJobsScript = redis.NewScript(`
+local expired = redis.call("ZRANGEBYSCORE", KEYS[2], "-inf", ARGV[1])
+for _, member in ipairs(expired) do
+	redis.call("ZREM", KEYS[2], member)
+	redis.call("ZADD", KEYS[1], ARGV[1], member)
+end
+return #expired
+`)
+
+// EnqueueJob adds a job to a queue sorted by when it is due
+func (c *Client) EnqueueJob(queueKey, job string, runAt time.Time) error {
+	if !c.enabled {
+		return fmt.Errorf("redis is disabled")
+	}
+
+	return c.client.ZAdd(c.ctx, queueKey, redis.Z{Score: float64(runAt.UnixMilli()), Member: job}).Err()
+}
+
+// ClaimJob takes the next job due by now off the queue and leases it until
+// leaseUntil. Returns an empty string when no job is due.
+func (c *Client) ClaimJob(queueKey, activeKey string, now, leaseUntil time.Time) (string, error) {
+	if !c.enabled {
+		return "", fmt.Errorf("redis is disabled")
+	}
+
+	job, err := claimJobScript.Run(c.ctx, c.client, []string{queueKey, activeKey}, now.UnixMilli(), leaseUntil.UnixMilli()).Text()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return job, err
+}
+
+// CompleteJob drops a leased job
+func (c *Client) CompleteJob(activeKey, job string) error {
+	if !c.enabled {
+		return fmt.Errorf("redis is disabled")
+	}
+
+	return c.client.ZRem(c.ctx, activeKey, job).Err()
+}
+
+// RequeueJob replaces a leased job with its next run, due at runAt
+func (c *Client) RequeueJob(queueKey, activeKey, job, next string, runAt time.Time) error {
+	if !c.enabled {
+		return fmt.Errorf("redis is disabled")
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.ZRem(c.ctx, activeKey, job)
+	pipe.ZAdd(c.ctx, queueKey, redis.Z{Score: float64(runAt.UnixMilli()), Member: next})
+	_, err := pipe.Exec(c.ctx)
+	return err
+}
+
+// BuryJob replaces a leased job with its final state in a list of at most maxLen failed jobs
+func (c *Client) BuryJob(activeKey, deadKey, job, final string, maxLen int64) error {
+	if !c.enabled {
+		return fmt.Errorf("redis is disabled")
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.ZRem(c.ctx, activeKey, job)
+	pipe.LPush(c.ctx, deadKey, final)
+	pipe.LTrim(c.ctx, deadKey, 0, maxLen-1)
+	_, err := pipe.Exec(c.ctx)
+	return err
+}
+
+// RecoverJobs returns the jobs whose lease ended by now to the queue and reports how many there were
+func (c *Client) RecoverJobs(queueKey, activeKey string, now time.Time) (int, error) {
+	if !c.enabled {
+		return 0, fmt.Errorf("redis is disabled")
+	}
+
+	return recoverJobsScript.Run(c.ctx, c.client, []string{queueKey, activeKey}, now.UnixMilli()).Int()
+}
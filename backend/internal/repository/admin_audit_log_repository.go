@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AdminAuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAdminAuditLogRepository(db *gorm.DB) AdminAuditLogRepositoryInterface {
+	return &AdminAuditLogRepository{
+		db: db,
+	}
+}
+
+func (r *AdminAuditLogRepository) Create(entry *models.AdminAuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// AuditLogFilter narrows the entries returned by List. Zero values do not filter.
+type AuditLogFilter struct {
+	ActorID      *uuid.UUID
+	TargetUserID *uuid.UUID
+	Action       string
+}
+
+var auditLogSorts = map[string]sortField[models.AdminAuditLog]{
+	"created_at": {"created_at", func(e *models.AdminAuditLog) any { return e.CreatedAt }},
+}
+
+// List returns a page of entries matching the filter, most recent first by default
+func (r *AdminAuditLogRepository) List(filter AuditLogFilter, page PageQuery) ([]*models.AdminAuditLog, string, error) {
+	query := r.db.Model(&models.AdminAuditLog{})
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.TargetUserID != nil {
+		query = query.Where("target_user_id = ?", *filter.TargetUserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	return paginate(query, page, auditLogSorts, "-created_at", func(e *models.AdminAuditLog) uuid.UUID { return e.ID })
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type APITokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) APITokenRepositoryInterface {
+	return &APITokenRepository{
+		db: db,
+	}
+}
+
+func (r *APITokenRepository) Create(token *models.APIToken) (uuid.UUID, error) {
+	result := r.db.Create(token)
+	if result.Error != nil {
+		return uuid.Nil, result.Error
+	}
+	return token.ID, nil
+}
+
+func (r *APITokenRepository) GetByID(id uuid.UUID) (*models.APIToken, error) {
+	var token models.APIToken
+	result := r.db.First(&token, "id = ?", id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+// GetByHash also loads the owner so authentication can reject disabled accounts
+func (r *APITokenRepository) GetByHash(tokenHash string) (*models.APIToken, error) {
+	var token models.APIToken
+	result := r.db.Joins("User").First(&token, "api_tokens.token_hash = ?", tokenHash)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+func (r *APITokenRepository) GetByUserID(userID uuid.UUID) ([]*models.APIToken, error) {
+	var tokens []*models.APIToken
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tokens, nil
+}
+
+func (r *APITokenRepository) Revoke(id uuid.UUID, revokedAt time.Time) error {
+	result := r.db.Model(&models.APIToken{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", revokedAt)
+	return result.Error
+}
+
+func (r *APITokenRepository) UpdateLastUsed(id uuid.UUID, lastUsedAt time.Time) error {
+	result := r.db.Model(&models.APIToken{}).Where("id = ?", id).Update("last_used_at", lastUsedAt)
+	return result.Error
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BranchRepository struct {
+	db *gorm.DB
+}
+
+func NewBranchRepository(db *gorm.DB) BranchRepositoryInterface {
+	return &BranchRepository{db: db}
+}
+
+func (r *BranchRepository) Create(branch *models.Branch) (uuid.UUID, error) {
+	if err := r.db.Create(branch).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return branch.ID, nil
+}
+
+func (r *BranchRepository) GetByID(id uuid.UUID) (*models.Branch, error) {
+	var branch models.Branch
+	if err := r.db.Scopes(db.ReplicaRead).First(&branch, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &branch, nil
+}
+
+// GetByProjectID returns the project's branches by name, without their schemas
+func (r *BranchRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Branch, error) {
+	var branches []*models.Branch
+	err := r.db.Scopes(db.ReplicaRead).Omit("base", "schema").Where("project_id = ?", projectID).Order("name").Find(&branches).Error
+	return branches, err
+}
+
+// Update saves a branch read at its version, failing with ErrVersionConflict
+// when it changed since
+func (r *BranchRepository) Update(branch *models.Branch) error {
+	return saveVersioned(r.db, branch, &branch.Version)
+}
+
+func (r *BranchRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Branch{}, "id = ?", id).Error
+}
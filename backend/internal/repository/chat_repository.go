@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ChatRepository struct {
+	db *gorm.DB
+}
+
+func NewChatRepository(db *gorm.DB) ChatRepositoryInterface {
+	return &ChatRepository{db: db}
+}
+
+func (r *ChatRepository) Create(message *models.ChatMessage) (uuid.UUID, error) {
+	if err := r.db.Create(message).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return message.ID, nil
+}
+
+var chatMessageSorts = map[string]sortField[models.ChatMessage]{
+	"created_at": {"created_at", func(m *models.ChatMessage) any { return m.CreatedAt }},
+}
+
+// ListByProjectID returns a page of a project's chat with their senders, newest first by default
+func (r *ChatRepository) ListByProjectID(projectID uuid.UUID, page PageQuery) ([]*models.ChatMessage, string, error) {
+	query := r.db.Scopes(db.ReplicaRead).Preload("User").Where("project_id = ?", projectID)
+	return paginate(query, page, chatMessageSorts, "-created_at", func(m *models.ChatMessage) uuid.UUID { return m.ID })
+}
@@ -59,6 +59,16 @@ func (r *CollaborationSessionRepository) GetByUserID(userID uuid.UUID) ([]*model
 	return sessions, nil
 }
 
+// GetByProjectAndUser returns the most recently joined session of a user in a project
+func (r *CollaborationSessionRepository) GetByProjectAndUser(projectID, userID uuid.UUID) (*models.CollaborationSession, error) {
+	var session models.CollaborationSession
+	err := r.db.Where("project_id = ? AND user_id = ?", projectID, userID).Order("joined_at DESC").First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
 func (r *CollaborationSessionRepository) Update(session *models.CollaborationSession) error {
 	return r.db.Save(session).Error
 }
@@ -78,6 +88,10 @@ func (r *CollaborationSessionRepository) UpdateCursor(id uuid.UUID, cursorX, cur
 	return r.db.Model(&models.CollaborationSession{}).Where("id = ?", id).Updates(updates).Error
 }
 
+func (r *CollaborationSessionRepository) UpdateStatus(id uuid.UUID, status string) error {
+	return r.db.Model(&models.CollaborationSession{}).Where("id = ?", id).Update("status", status).Error
+}
+
 func (r *CollaborationSessionRepository) SetInactive(id uuid.UUID) error {
 	now := time.Now()
 	return r.db.Model(&models.CollaborationSession{}).Where("id = ?", id).Updates(map[string]interface{}{
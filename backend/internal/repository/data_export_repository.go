@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DataExportRepository struct {
+	db *gorm.DB
+}
+
+func NewDataExportRepository(db *gorm.DB) DataExportRepositoryInterface {
+	return &DataExportRepository{db: db}
+}
+
+func (r *DataExportRepository) Create(export *models.DataExport) (uuid.UUID, error) {
+	if err := r.db.Omit(clause.Associations).Create(export).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return export.ID, nil
+}
+
+func (r *DataExportRepository) GetByID(id uuid.UUID) (*models.DataExport, error) {
+	var export models.DataExport
+	if err := r.db.First(&export, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// GetByUserID returns the user's exports, newest first
+func (r *DataExportRepository) GetByUserID(userID uuid.UUID) ([]*models.DataExport, error) {
+	var exports []*models.DataExport
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&exports).Error
+	return exports, err
+}
+
+func (r *DataExportRepository) Update(export *models.DataExport) error {
+	return r.db.Omit(clause.Associations).Save(export).Error
+}
+
+func (r *DataExportRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.DataExport{}, "id = ?", id).Error
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type DocRepository struct {
+	db *gorm.DB
+}
+
+func NewDocRepository(db *gorm.DB) DocRepositoryInterface {
+	return &DocRepository{db: db}
+}
+
+// Create saves a revision of a project's doc. It fails with
+// ErrVersionConflict when the project already has a revision of its version,
+// saved by a concurrent edit.
+func (r *DocRepository) Create(revision *models.DocRevision) (uuid.UUID, error) {
+	if err := r.db.Create(revision).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return uuid.Nil, ErrVersionConflict
+		}
+		return uuid.Nil, err
+	}
+	return revision.ID, nil
+}
+
+// GetLatest returns the latest revision of a project's doc, or
+// gorm.ErrRecordNotFound when it was never edited
+func (r *DocRepository) GetLatest(projectID uuid.UUID) (*models.DocRevision, error) {
+	var revision models.DocRevision
+	if err := r.db.Where("project_id = ?", projectID).Order("version DESC").First(&revision).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *DocRepository) GetByVersion(projectID uuid.UUID, version int64) (*models.DocRevision, error) {
+	var revision models.DocRevision
+	if err := r.db.Scopes(db.ReplicaRead).First(&revision, "project_id = ? AND version = ?", projectID, version).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// GetByProjectID returns the revisions of a project's doc newest first,
+// without their content
+func (r *DocRepository) GetByProjectID(projectID uuid.UUID) ([]*models.DocRevision, error) {
+	var revisions []*models.DocRevision
+	err := r.db.Scopes(db.ReplicaRead).Omit("content").Where("project_id = ?", projectID).Order("version DESC").Find(&revisions).Error
+	return revisions, err
+}
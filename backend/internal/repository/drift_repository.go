@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DriftRepository struct {
+	db *gorm.DB
+}
+
+func NewDriftRepository(db *gorm.DB) DriftRepositoryInterface {
+	return &DriftRepository{db: db}
+}
+
+// GetProfile returns gorm.ErrRecordNotFound for projects without a connection profile
+func (r *DriftRepository) GetProfile(projectID uuid.UUID) (*models.ConnectionProfile, error) {
+	var profile models.ConnectionProfile
+	if err := r.db.First(&profile, "project_id = ?", projectID).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// SaveProfile creates or replaces the project's connection profile
+func (r *DriftRepository) SaveProfile(profile *models.ConnectionProfile) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"dsn", "schema_name", "scheduled_checks", "webhook_url", "webhook_secret", "updated_at"}),
+	}).Create(profile).Error
+}
+
+func (r *DriftRepository) DeleteProfile(projectID uuid.UUID) error {
+	return r.db.Delete(&models.ConnectionProfile{}, "project_id = ?", projectID).Error
+}
+
+// GetProjectIDsToCheck returns the projects whose connection profile asks for daily checks
+func (r *DriftRepository) GetProjectIDsToCheck() ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&models.ConnectionProfile{}).Where("scheduled_checks").
+		Order("project_id").Pluck("project_id", &ids).Error
+	return ids, err
+}
+
+func (r *DriftRepository) CreateCheck(check *models.DriftCheck) (uuid.UUID, error) {
+	if err := r.db.Create(check).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return check.ID, nil
+}
+
+func (r *DriftRepository) GetCheck(id uuid.UUID) (*models.DriftCheck, error) {
+	var check models.DriftCheck
+	if err := r.db.First(&check, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &check, nil
+}
+
+// GetChecksByProjectID returns the project's latest checks newest first, without their diffs
+func (r *DriftRepository) GetChecksByProjectID(projectID uuid.UUID, limit int) ([]*models.DriftCheck, error) {
+	var checks []*models.DriftCheck
+	err := r.db.Scopes(db.ReplicaRead).Omit("diff").Where("project_id = ?", projectID).
+		Order("created_at DESC, id").Limit(limit).Find(&checks).Error
+	return checks, err
+}
+
+// GetLatestCompletedCheck returns the project's newest check that is clean or
+// drifted, or gorm.ErrRecordNotFound when there is none
+func (r *DriftRepository) GetLatestCompletedCheck(projectID uuid.UUID) (*models.DriftCheck, error) {
+	var check models.DriftCheck
+	err := r.db.Where("project_id = ? AND status IN ?", projectID, []string{models.DriftCheckClean, models.DriftCheckDrifted}).
+		Order("created_at DESC, id").First(&check).Error
+	if err != nil {
+		return nil, err
+	}
+	return &check, nil
+}
+
+func (r *DriftRepository) UpdateCheck(check *models.DriftCheck) error {
+	return r.db.Save(check).Error
+}
+
+// PruneChecks deletes the project's checks beyond the newest keep
+func (r *DriftRepository) PruneChecks(projectID uuid.UUID, keep int) error {
+	return r.db.Exec(`DELETE FROM drift_checks WHERE project_id = ? AND id NOT IN (
+		SELECT id FROM drift_checks WHERE project_id = ? ORDER BY created_at DESC, id LIMIT ?
+	)`, projectID, projectID, keep).Error
+}
+
+// SetDataProfileStatus records the state of the project's profiling
+func (r *DriftRepository) SetDataProfileStatus(projectID uuid.UUID, status, reason string) error {
+	return r.db.Model(&models.ConnectionProfile{}).Where("project_id = ?", projectID).
+		UpdateColumns(map[string]any{"data_profile_status": status, "data_profile_error": reason}).Error
+}
+
+// ReplaceDataProfile replaces the project's table and field profiles and
+// marks its profiling ready, in one transaction
+func (r *DriftRepository) ReplaceDataProfile(projectID uuid.UUID, tables []*models.TableProfile, fields []*models.FieldProfile, profiledAt time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.FieldProfile{}, "project_id = ?", projectID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.TableProfile{}, "project_id = ?", projectID).Error; err != nil {
+			return err
+		}
+		if len(tables) > 0 {
+			if err := tx.Create(tables).Error; err != nil {
+				return err
+			}
+		}
+		if len(fields) > 0 {
+			if err := tx.Create(fields).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.ConnectionProfile{}).Where("project_id = ?", projectID).UpdateColumns(map[string]any{
+			"data_profile_status": models.DataProfileReady,
+			"data_profile_error":  "",
+			"data_profiled_at":    profiledAt,
+		}).Error
+	})
+}
+
+// GetDataProfile returns the project's table and field profiles
+func (r *DriftRepository) GetDataProfile(projectID uuid.UUID) ([]*models.TableProfile, []*models.FieldProfile, error) {
+	var tables []*models.TableProfile
+	if err := r.db.Scopes(db.ReplicaRead).Where("project_id = ?", projectID).Order("table_id").Find(&tables).Error; err != nil {
+		return nil, nil, err
+	}
+	var fields []*models.FieldProfile
+	if err := r.db.Scopes(db.ReplicaRead).Where("project_id = ?", projectID).Order("table_id, field_id").Find(&fields).Error; err != nil {
+		return nil, nil, err
+	}
+	return tables, fields, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation is the SQLSTATE of an insert duplicating a unique key
+const uniqueViolation = "23505"
+
+// The unique indexes a write can fail on with a DuplicateError
+const (
+	IndexUserEmail    = "idx_users_email"
+	IndexUserUsername = "idx_users_username"
+	IndexTableName    = "idx_tables_project_name"     // Table names within a project
+	IndexFieldName    = "idx_fields_table_lower_name" // Field names within a table, ignoring case
+)
+
+// ErrDuplicate is returned when a write would give a record a unique key
+// another record has, such as a taken email
+var ErrDuplicate = errors.New("duplicate key")
+
+// DuplicateError is an ErrDuplicate naming the unique index the write broke
+type DuplicateError struct {
+	Index string
+}
+
+func (e *DuplicateError) Error() string {
+	return "duplicate key in " + e.Index
+}
+
+func (e *DuplicateError) Is(target error) bool {
+	return target == ErrDuplicate
+}
+
+// translateDuplicate turns a unique violation into a DuplicateError, so
+// concurrent writes that both passed a check for a taken key fail as if the
+// check had caught them
+func translateDuplicate(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return &DuplicateError{Index: pgErr.ConstraintName}
+	}
+	return err
+}
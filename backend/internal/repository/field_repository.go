@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"github.com/Bug-Bugger/ezmodel/internal/db"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -16,20 +17,37 @@ func NewFieldRepository(db *gorm.DB) FieldRepositoryInterface {
 
 func (r *FieldRepository) Create(field *models.Field) (uuid.UUID, error) {
 	if err := r.db.Create(field).Error; err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, translateDuplicate(err)
 	}
 	return field.ID, nil
 }
 
+// CreateBatch inserts the fields with a single statement, so either all of them
+// are created or none is
+func (r *FieldRepository) CreateBatch(fields []*models.Field) error {
+	return translateDuplicate(r.db.Create(&fields).Error)
+}
+
 func (r *FieldRepository) GetByID(id uuid.UUID) (*models.Field, error) {
 	var field models.Field
-	err := r.db.First(&field, "id = ?", id).Error
+	err := r.db.Scopes(db.ReplicaRead).First(&field, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &field, nil
 }
 
+// GetByIDs returns the fields with the given IDs in one query. Fields that do
+// not exist are left out.
+func (r *FieldRepository) GetByIDs(ids []uuid.UUID) ([]*models.Field, error) {
+	fields := make([]*models.Field, 0, len(ids))
+	if len(ids) == 0 {
+		return fields, nil
+	}
+	err := r.db.Scopes(db.ReplicaRead).Where("id IN ?", ids).Find(&fields).Error
+	return fields, err
+}
+
 func (r *FieldRepository) GetByTableID(tableID uuid.UUID) ([]*models.Field, error) {
 	var fields []*models.Field
 	err := r.db.Where("table_id = ?", tableID).Order("position ASC").Find(&fields).Error
@@ -39,8 +57,10 @@ func (r *FieldRepository) GetByTableID(tableID uuid.UUID) ([]*models.Field, erro
 	return fields, nil
 }
 
+// Update saves a field read at field.Version and advances the version. It fails
+// with ErrVersionConflict when the field has changed since.
 func (r *FieldRepository) Update(field *models.Field) error {
-	return r.db.Save(field).Error
+	return translateDuplicate(saveVersioned(r.db, field, &field.Version))
 }
 
 func (r *FieldRepository) Delete(id uuid.UUID) error {
@@ -50,7 +70,10 @@ func (r *FieldRepository) Delete(id uuid.UUID) error {
 func (r *FieldRepository) ReorderFields(tableID uuid.UUID, fieldPositions map[uuid.UUID]int) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		for fieldID, position := range fieldPositions {
-			if err := tx.Model(&models.Field{}).Where("id = ? AND table_id = ?", fieldID, tableID).Update("position", position).Error; err != nil {
+			if err := tx.Model(&models.Field{}).Where("id = ? AND table_id = ?", fieldID, tableID).Updates(map[string]any{
+				"position": position,
+				"version":  nextVersion,
+			}).Error; err != nil {
 				return err
 			}
 		}
@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/google/uuid"
 )
@@ -9,35 +12,99 @@ type UserRepositoryInterface interface {
 	Create(user *models.User) (uuid.UUID, error)
 	GetByID(id uuid.UUID) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
-	GetAll() ([]*models.User, error)
+	GetByUsername(username string) (*models.User, error)
+	List(filter UserFilter, page PageQuery) ([]*models.User, string, error)
+	Count(filter UserFilter) (int64, error)
 	Update(user *models.User) error
 	Delete(id uuid.UUID) error
+	DeleteAccount(id uuid.UUID, transfers map[uuid.UUID]uuid.UUID) error
+}
+
+type UserIdentityRepositoryInterface interface {
+	Create(identity *models.UserIdentity) (uuid.UUID, error)
+	GetByProviderSubject(provider, subject string) (*models.UserIdentity, error)
+}
+
+type APITokenRepositoryInterface interface {
+	Create(token *models.APIToken) (uuid.UUID, error)
+	GetByID(id uuid.UUID) (*models.APIToken, error)
+	GetByHash(tokenHash string) (*models.APIToken, error)
+	GetByUserID(userID uuid.UUID) ([]*models.APIToken, error)
+	Revoke(id uuid.UUID, revokedAt time.Time) error
+	UpdateLastUsed(id uuid.UUID, lastUsedAt time.Time) error
+}
+
+type UserSessionRepositoryInterface interface {
+	Create(session *models.UserSession) (uuid.UUID, error)
+	GetByID(id uuid.UUID) (*models.UserSession, error)
+	GetActiveByUserID(userID uuid.UUID, now time.Time) ([]*models.UserSession, error)
+	Rotate(session *models.UserSession, previousHash string) (bool, error)
+	Revoke(id uuid.UUID, revokedAt time.Time) error
+	RevokeAllByUserID(userID, exceptID uuid.UUID, revokedAt time.Time) error
+}
+
+type AdminAuditLogRepositoryInterface interface {
+	Create(entry *models.AdminAuditLog) error
+	List(filter AuditLogFilter, page PageQuery) ([]*models.AdminAuditLog, string, error)
+}
+
+type ServiceAccountRepositoryInterface interface {
+	Create(account *models.ServiceAccount) (uuid.UUID, error)
+	GetByID(id uuid.UUID) (*models.ServiceAccount, error)
+	GetByProjectID(projectID uuid.UUID) ([]*models.ServiceAccount, error)
+	Delete(id uuid.UUID) error
+}
+
+type LoginEventRepositoryInterface interface {
+	Create(event *models.LoginEvent) error
+	GetByUserID(userID uuid.UUID, limit int) ([]*models.LoginEvent, error)
+	GetLastSuccessAt(email string) (*time.Time, error)
+	CountFailuresByEmail(email string, since time.Time) (int64, *time.Time, error)
+	CountFailuresByIP(ipAddress string, since time.Time) (int64, error)
+	CountSuccessesByUser(userID uuid.UUID, ipAddress string) (total int64, fromIP int64, err error)
 }
 
 type ProjectRepositoryInterface interface {
 	Create(project *models.Project) (uuid.UUID, error)
 	GetByID(id uuid.UUID) (*models.Project, error)
-	GetByOwnerID(ownerID uuid.UUID) ([]*models.Project, error)
-	GetByCollaboratorID(collaboratorID uuid.UUID) ([]*models.Project, error)
-	GetAll() ([]*models.Project, error)
+	GetByOwnerID(ownerID uuid.UUID, includes ProjectIncludes) ([]*models.Project, error)
+	CountByOwnerID(ownerID uuid.UUID) (int, error)
+	GetByCollaboratorID(collaboratorID uuid.UUID, includes ProjectIncludes) ([]*models.Project, error)
+	List(filter ProjectFilter, page PageQuery) ([]*models.Project, string, error)
 	Update(project *models.Project) error
+	NextSequence(id uuid.UUID) (int64, error)
 	Delete(id uuid.UUID) error
 	AddCollaborator(projectID, userID uuid.UUID) error
 	RemoveCollaborator(projectID, userID uuid.UUID) error
+	SetCollaboratorRole(projectID, userID uuid.UUID, role string) error
+	GetViewerIDs(projectID uuid.UUID) ([]uuid.UUID, error)
+	AddTag(projectID uuid.UUID, name string) error
+	RemoveTag(projectID uuid.UUID, name string) error
+	Star(projectID, userID uuid.UUID) error
+	Unstar(projectID, userID uuid.UUID) error
+	GetStarredProjectIDs(userID uuid.UUID) ([]uuid.UUID, error)
+	RecordView(projectID, userID uuid.UUID, viewedAt time.Time) error
+	GetRecentlyViewed(userID uuid.UUID, limit int) ([]*models.ProjectView, error)
 }
 
 type TableRepositoryInterface interface {
 	Create(table *models.Table) (uuid.UUID, error)
 	GetByID(id uuid.UUID) (*models.Table, error)
+	GetByIDs(ids []uuid.UUID) ([]*models.Table, error)
 	GetByProjectID(projectID uuid.UUID) ([]*models.Table, error)
+	ListByProjectID(projectID uuid.UUID, filter TableFilter, page PageQuery) ([]*models.Table, string, error)
 	Update(table *models.Table) error
 	Delete(id uuid.UUID) error
 	UpdatePosition(id uuid.UUID, posX, posY float64) error
+	SetFieldOrderSequence(id uuid.UUID, sequence, since int64) error
+	CountByProjectIDs(projectIDs []uuid.UUID) (map[uuid.UUID]int, error)
 }
 
 type FieldRepositoryInterface interface {
 	Create(field *models.Field) (uuid.UUID, error)
+	CreateBatch(fields []*models.Field) error
 	GetByID(id uuid.UUID) (*models.Field, error)
+	GetByIDs(ids []uuid.UUID) ([]*models.Field, error)
 	GetByTableID(tableID uuid.UUID) ([]*models.Field, error)
 	Update(field *models.Field) error
 	Delete(id uuid.UUID) error
@@ -47,20 +114,154 @@ type FieldRepositoryInterface interface {
 type RelationshipRepositoryInterface interface {
 	Create(relationship *models.Relationship) (uuid.UUID, error)
 	GetByID(id uuid.UUID) (*models.Relationship, error)
+	GetByIDs(ids []uuid.UUID) ([]*models.Relationship, error)
 	GetByProjectID(projectID uuid.UUID) ([]*models.Relationship, error)
 	GetByTableID(tableID uuid.UUID) ([]*models.Relationship, error)
 	Update(relationship *models.Relationship) error
 	Delete(id uuid.UUID) error
 }
 
+type RegionRepositoryInterface interface {
+	Create(region *models.Region) (uuid.UUID, error)
+	GetByID(id uuid.UUID) (*models.Region, error)
+	GetByProjectID(projectID uuid.UUID) ([]*models.Region, error)
+	Update(region *models.Region) error
+	SetTables(regionID uuid.UUID, tableIDs []uuid.UUID) error
+	MoveTables(regionID uuid.UUID, dx, dy float64) ([]TablePosition, error)
+	Delete(id uuid.UUID) error
+}
+
+type RenameRepositoryInterface interface {
+	Create(rename *models.SchemaRename) (uuid.UUID, error)
+	GetByProjectID(projectID uuid.UUID) ([]*models.SchemaRename, error)
+}
+
+type SnippetRepositoryInterface interface {
+	Create(snippet *models.Snippet) (uuid.UUID, error)
+	GetByID(id uuid.UUID) (*models.Snippet, error)
+	GetByProjectID(projectID uuid.UUID) ([]*models.Snippet, error)
+	Update(snippet *models.Snippet) error
+	Delete(id uuid.UUID) error
+}
+
+type ReviewCommentRepositoryInterface interface {
+	Create(comment *models.ReviewComment) (uuid.UUID, error)
+	GetByID(id uuid.UUID) (*models.ReviewComment, error)
+	GetByProjectID(projectID uuid.UUID) ([]*models.ReviewComment, error)
+	Update(comment *models.ReviewComment) error
+}
+
+type DocRepositoryInterface interface {
+	Create(revision *models.DocRevision) (uuid.UUID, error)
+	GetLatest(projectID uuid.UUID) (*models.DocRevision, error)
+	GetByVersion(projectID uuid.UUID, version int64) (*models.DocRevision, error)
+	GetByProjectID(projectID uuid.UUID) ([]*models.DocRevision, error)
+}
+
+type BranchRepositoryInterface interface {
+	Create(branch *models.Branch) (uuid.UUID, error)
+	GetByID(id uuid.UUID) (*models.Branch, error)
+	GetByProjectID(projectID uuid.UUID) ([]*models.Branch, error)
+	Update(branch *models.Branch) error
+	Delete(id uuid.UUID) error
+}
+
+type ReleaseRepositoryInterface interface {
+	Create(release *models.Release) (uuid.UUID, error)
+	GetByID(id uuid.UUID) (*models.Release, error)
+	GetByName(projectID uuid.UUID, name string) (*models.Release, error)
+	GetByProjectID(projectID uuid.UUID) ([]*models.Release, error)
+	Update(release *models.Release) error
+	Delete(id uuid.UUID) error
+}
+
+type ChatRepositoryInterface interface {
+	Create(message *models.ChatMessage) (uuid.UUID, error)
+	ListByProjectID(projectID uuid.UUID, page PageQuery) ([]*models.ChatMessage, string, error)
+}
+
+type ProjectEventRepositoryInterface interface {
+	Create(event *models.ProjectEvent) error
+	GetSince(projectID uuid.UUID, sequence int64, limit int) ([]*models.ProjectEvent, error)
+	DeleteUpTo(projectID uuid.UUID, sequence int64) error
+}
+
+type OutboxRepositoryInterface interface {
+	Create(event *models.OutboxEvent) error
+	DispatchPending(limit, maxAttempts int, deliver func(event *models.OutboxEvent) error) (int, error)
+	DeleteDispatchedBefore(before time.Time) (int64, error)
+}
+
+// UnitOfWorkInterface runs changes spanning several repositories in one transaction
+type UnitOfWorkInterface interface {
+	Do(fn func(repos Repositories) error) error
+}
+
 type CollaborationSessionRepositoryInterface interface {
 	Create(session *models.CollaborationSession) (uuid.UUID, error)
 	GetByID(id uuid.UUID) (*models.CollaborationSession, error)
 	GetByProjectID(projectID uuid.UUID) ([]*models.CollaborationSession, error)
 	GetActiveByProjectID(projectID uuid.UUID) ([]*models.CollaborationSession, error)
 	GetByUserID(userID uuid.UUID) ([]*models.CollaborationSession, error)
+	GetByProjectAndUser(projectID, userID uuid.UUID) (*models.CollaborationSession, error)
 	Update(session *models.CollaborationSession) error
 	UpdateCursor(id uuid.UUID, cursorX, cursorY *float64) error
+	UpdateStatus(id uuid.UUID, status string) error
 	SetInactive(id uuid.UUID) error
 	Delete(id uuid.UUID) error
 }
+
+type SearchRepositoryInterface interface {
+	Search(query SearchQuery) ([]*SearchHit, error)
+}
+
+type SnapshotRepositoryInterface interface {
+	Create(snapshot *models.ProjectSnapshot) (uuid.UUID, error)
+	GetByID(id uuid.UUID) (*models.ProjectSnapshot, error)
+	GetByProjectID(projectID uuid.UUID) ([]*models.ProjectSnapshot, error)
+	DeleteByIDs(ids []uuid.UUID) error
+	GetProjectIDsToSnapshot() ([]uuid.UUID, error)
+	GetPolicy(projectID uuid.UUID) (*models.SnapshotPolicy, error)
+	SavePolicy(policy *models.SnapshotPolicy) error
+}
+
+type DriftRepositoryInterface interface {
+	GetProfile(projectID uuid.UUID) (*models.ConnectionProfile, error)
+	SaveProfile(profile *models.ConnectionProfile) error
+	DeleteProfile(projectID uuid.UUID) error
+	GetProjectIDsToCheck() ([]uuid.UUID, error)
+	CreateCheck(check *models.DriftCheck) (uuid.UUID, error)
+	GetCheck(id uuid.UUID) (*models.DriftCheck, error)
+	GetChecksByProjectID(projectID uuid.UUID, limit int) ([]*models.DriftCheck, error)
+	GetLatestCompletedCheck(projectID uuid.UUID) (*models.DriftCheck, error)
+	UpdateCheck(check *models.DriftCheck) error
+	PruneChecks(projectID uuid.UUID, keep int) error
+	SetDataProfileStatus(projectID uuid.UUID, status, reason string) error
+	ReplaceDataProfile(projectID uuid.UUID, tables []*models.TableProfile, fields []*models.FieldProfile, profiledAt time.Time) error
+	GetDataProfile(projectID uuid.UUID) ([]*models.TableProfile, []*models.FieldProfile, error)
+}
+
+type StatsRepositoryInterface interface {
+	GetTotals(ctx context.Context) (*InstanceTotals, error)
+	CountSignupsByDay(ctx context.Context, since time.Time) ([]*DailyCount, error)
+	ListLargestOwners(ctx context.Context, limit int) ([]*OwnerStorage, error)
+}
+
+type UserPreferencesRepositoryInterface interface {
+	GetByUserID(userID uuid.UUID) (*models.UserPreferences, error)
+	Save(preferences *models.UserPreferences) error
+}
+
+type SubscriptionRepositoryInterface interface {
+	GetByUserID(userID uuid.UUID) (*models.Subscription, error)
+	GetByStripeCustomerID(customerID string) (*models.Subscription, error)
+	Save(subscription *models.Subscription) error
+}
+
+type DataExportRepositoryInterface interface {
+	Create(export *models.DataExport) (uuid.UUID, error)
+	GetByID(id uuid.UUID) (*models.DataExport, error)
+	GetByUserID(userID uuid.UUID) ([]*models.DataExport, error)
+	Update(export *models.DataExport) error
+	Delete(id uuid.UUID) error
+}
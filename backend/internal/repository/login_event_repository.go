@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LoginEventRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginEventRepository(db *gorm.DB) LoginEventRepositoryInterface {
+	return &LoginEventRepository{
+		db: db,
+	}
+}
+
+func (r *LoginEventRepository) Create(event *models.LoginEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *LoginEventRepository) GetByUserID(userID uuid.UUID, limit int) ([]*models.LoginEvent, error) {
+	var events []*models.LoginEvent
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").Limit(limit).Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return events, nil
+}
+
+// GetLastSuccessAt returns when the email last logged in successfully, or nil if it never did
+func (r *LoginEventRepository) GetLastSuccessAt(email string) (*time.Time, error) {
+	var event models.LoginEvent
+	result := r.db.Select("created_at").Where("email = ? AND success = ?", email, true).Order("created_at DESC").Limit(1).Find(&event)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &event.CreatedAt, nil
+}
+
+// CountFailuresByEmail counts wrong-password attempts for the email since the given
+// time and returns the time of the latest one
+func (r *LoginEventRepository) CountFailuresByEmail(email string, since time.Time) (int64, *time.Time, error) {
+	var stats struct {
+		Count  int64
+		Latest *time.Time
+	}
+	result := r.db.Model(&models.LoginEvent{}).
+		Select("COUNT(*) AS count, MAX(created_at) AS latest").
+		Where("email = ? AND failure_reason = ? AND created_at > ?", email, models.LoginFailureInvalidCredentials, since).
+		Scan(&stats)
+	if result.Error != nil {
+		return 0, nil, result.Error
+	}
+	return stats.Count, stats.Latest, nil
+}
+
+// CountFailuresByIP counts wrong-password attempts from the IP address since the given time
+func (r *LoginEventRepository) CountFailuresByIP(ipAddress string, since time.Time) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.LoginEvent{}).
+		Where("ip_address = ? AND failure_reason = ? AND created_at > ?", ipAddress, models.LoginFailureInvalidCredentials, since).
+		Count(&count)
+	return count, result.Error
+}
+
+// CountSuccessesByUser counts the user's successful logins overall and from the IP address
+func (r *LoginEventRepository) CountSuccessesByUser(userID uuid.UUID, ipAddress string) (int64, int64, error) {
+	var stats struct {
+		Total  int64
+		FromIP int64
+	}
+	result := r.db.Model(&models.LoginEvent{}).
+		Select("COUNT(*) AS total, COUNT(*) FILTER (WHERE ip_address = ?) AS from_ip", ipAddress).
+		Where("user_id = ? AND success = ?", userID, true).
+		Scan(&stats)
+	if result.Error != nil {
+		return 0, 0, result.Error
+	}
+	return stats.Total, stats.FromIP, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepositoryInterface {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) Create(event *models.OutboxEvent) error {
+	return r.db.Create(event).Error
+}
+
+// DispatchPending hands up to limit undelivered events to deliver, oldest
+// first, skipping those that already failed maxAttempts times. Events are
+// locked while delivered so several nodes can dispatch at once without
+// delivering an event twice. Returns how many events were delivered.
+func (r *OutboxRepository) DispatchPending(limit, maxAttempts int, deliver func(event *models.OutboxEvent) error) (int, error) {
+	dispatched := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var events []*models.OutboxEvent
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("dispatched_at IS NULL AND attempts < ?", maxAttempts).
+			Order("id ASC").Limit(limit).Find(&events).Error
+		if err != nil {
+			return err
+		}
+
+		var delivered, failed []int64
+		for _, event := range events {
+			if err := deliver(event); err != nil {
+				failed = append(failed, event.ID)
+				continue
+			}
+			delivered = append(delivered, event.ID)
+		}
+
+		if len(delivered) > 0 {
+			err := tx.Model(&models.OutboxEvent{}).Where("id IN ?", delivered).
+				Update("dispatched_at", time.Now()).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(failed) > 0 {
+			err := tx.Model(&models.OutboxEvent{}).Where("id IN ?", failed).
+				Update("attempts", gorm.Expr("attempts + 1")).Error
+			if err != nil {
+				return err
+			}
+		}
+		dispatched = len(delivered)
+		return nil
+	})
+	return dispatched, err
+}
+
+// DeleteDispatchedBefore removes events delivered before the given time
+func (r *OutboxRepository) DeleteDispatchedBefore(before time.Time) (int64, error) {
+	result := r.db.Where("dispatched_at < ?", before).Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidSort   = errors.New("invalid sort")
+)
+
+// PageQuery selects one page of a list. The zero value is the first page in
+// the list's default order.
+type PageQuery struct {
+	Limit  int    // At most MaxPageLimit, DefaultPageLimit when zero
+	Cursor string // NextCursor of the previous page
+	Sort   string // Sort field, prefixed with - for descending order
+}
+
+// sortField is a column a list can be ordered by. value reads it from a record
+// so the next page can start after it.
+type sortField[M any] struct {
+	column string
+	value  func(*M) any
+}
+
+// cursor marks the last record of a page. Ties on the sort column are broken
+// by ID, so every record is returned exactly once.
+type cursor struct {
+	Sort  string          `json:"s"`
+	Value json.RawMessage `json:"v"`
+	ID    uuid.UUID       `json:"id"`
+}
+
+// paginate returns a page of the records the query selects, ordered by a
+// field of sorts, and the cursor of the next page, empty on the last page.
+// Unlike offsets, cursors stay valid while records are added.
+func paginate[M any](query *gorm.DB, page PageQuery, sorts map[string]sortField[M], defaultSort string, id func(*M) uuid.UUID) ([]*M, string, error) {
+	sort := page.Sort
+	if sort == "" {
+		sort = defaultSort
+	}
+	field, ok := sorts[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		return nil, "", ErrInvalidSort
+	}
+	direction, after := "ASC", ">"
+	if strings.HasPrefix(sort, "-") {
+		direction, after = "DESC", "<"
+	}
+
+	if page.Cursor != "" {
+		value, lastID, err := decodeCursor(page.Cursor, sort, field)
+		if err != nil {
+			return nil, "", err
+		}
+		query = query.Where(fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", field.column, after), value, value, lastID)
+	}
+
+	limit := page.Limit
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+	limit = min(limit, MaxPageLimit)
+
+	var records []*M
+	err := query.Order(field.column + " " + direction).Order("id " + direction).Limit(limit + 1).Find(&records).Error
+	if err != nil {
+		return nil, "", err
+	}
+	if len(records) <= limit {
+		return records, "", nil
+	}
+
+	records = records[:limit]
+	last := records[limit-1]
+	next, err := encodeCursor(sort, field.value(last), id(last))
+	if err != nil {
+		return nil, "", err
+	}
+	return records, next, nil
+}
+
+func encodeCursor(sort string, value any, id uuid.UUID) (string, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	encoded, err := json.Marshal(cursor{Sort: sort, Value: raw, ID: id})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+// decodeCursor reads a cursor made for the same sort, decoding its value to
+// the Go type of the sort column so the database compares like with like
+func decodeCursor[M any](encoded, sort string, field sortField[M]) (any, uuid.UUID, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, uuid.Nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(decoded, &c); err != nil || c.Sort != sort {
+		return nil, uuid.Nil, ErrInvalidCursor
+	}
+
+	value := reflect.New(reflect.TypeOf(field.value(new(M))))
+	if err := json.Unmarshal(c.Value, value.Interface()); err != nil {
+		return nil, uuid.Nil, ErrInvalidCursor
+	}
+	return value.Elem().Interface(), c.ID, nil
+}
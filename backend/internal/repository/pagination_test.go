@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type PaginationTestSuite struct {
+	suite.Suite
+}
+
+func TestPaginationSuite(t *testing.T) {
+	suite.Run(t, new(PaginationTestSuite))
+}
+
+// Test cursor - values come back as the Go type of their column
+func (suite *PaginationTestSuite) TestCursorRoundTrip() {
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC)
+	id := uuid.New()
+
+	encoded, err := encodeCursor("-created_at", createdAt, id)
+	suite.Require().NoError(err)
+
+	value, lastID, err := decodeCursor(encoded, "-created_at", projectSorts["created_at"])
+	suite.Require().NoError(err)
+	suite.Equal(id, lastID)
+	suite.IsType(time.Time{}, value)
+	suite.True(createdAt.Equal(value.(time.Time)))
+
+	encoded, err = encodeCursor("name", "orders", id)
+	suite.Require().NoError(err)
+
+	value, _, err = decodeCursor(encoded, "name", projectSorts["name"])
+	suite.Require().NoError(err)
+	suite.Equal("orders", value)
+}
+
+// Test cursor - garbage and cursors made for another order are rejected
+func (suite *PaginationTestSuite) TestCursorInvalid() {
+	_, _, err := decodeCursor("not a cursor", "name", projectSorts["name"])
+	suite.ErrorIs(err, ErrInvalidCursor)
+
+	encoded, err := encodeCursor("name", "orders", uuid.New())
+	suite.Require().NoError(err)
+
+	_, _, err = decodeCursor(encoded, "-name", projectSorts["name"])
+	suite.ErrorIs(err, ErrInvalidCursor)
+
+	// A name where a time is expected
+	encoded, err = encodeCursor("created_at", "orders", uuid.New())
+	suite.Require().NoError(err)
+
+	_, _, err = decodeCursor(encoded, "created_at", projectSorts["created_at"])
+	suite.ErrorIs(err, ErrInvalidCursor)
+}
+
+// Test paginate - unknown sort fields are rejected before querying
+func (suite *PaginationTestSuite) TestPaginateInvalidSort() {
+	idOf := func(p *models.Project) uuid.UUID { return p.ID }
+
+	for _, sort := range []string{"owner_id", "-password", "name; DROP TABLE projects"} {
+		_, _, err := paginate(nil, PageQuery{Sort: sort}, projectSorts, "created_at", idOf)
+		suite.ErrorIs(err, ErrInvalidSort, sort)
+	}
+}
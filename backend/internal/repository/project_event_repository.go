@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProjectEventRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectEventRepository(db *gorm.DB) ProjectEventRepositoryInterface {
+	return &ProjectEventRepository{db: db}
+}
+
+// Create saves an event unless the project already has one with its
+// sequence, as when an event is delivered twice
+func (r *ProjectEventRepository) Create(event *models.ProjectEvent) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(event).Error
+}
+
+// GetSince returns up to limit of the project's events after the given
+// sequence, oldest first
+func (r *ProjectEventRepository) GetSince(projectID uuid.UUID, sequence int64, limit int) ([]*models.ProjectEvent, error) {
+	var events []*models.ProjectEvent
+	err := r.db.Where("project_id = ? AND sequence > ?", projectID, sequence).
+		Order("sequence ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// DeleteUpTo removes the project's events up to and including the given sequence
+func (r *ProjectEventRepository) DeleteUpTo(projectID uuid.UUID, sequence int64) error {
+	return r.db.Where("project_id = ? AND sequence <= ?", projectID, sequence).Delete(&models.ProjectEvent{}).Error
+}
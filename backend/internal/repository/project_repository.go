@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProjectRepository struct {
@@ -24,36 +28,102 @@ func (r *ProjectRepository) Create(project *models.Project) (uuid.UUID, error) {
 
 func (r *ProjectRepository) GetByID(id uuid.UUID) (*models.Project, error) {
 	var project models.Project
-	err := r.db.Preload("Owner").Preload("Collaborators").Preload("Tables.Fields").Preload("Relationships").First(&project, "id = ?", id).Error
+	// Ordered so the schema, and the ETag of the full project response, is stable.
+	// Authorization reads the members from it, so it stays on the primary.
+	err := r.db.Preload("Owner").Preload("Collaborators", orderBy("username")).
+		Preload("Tables", orderBy("created_at, id")).Preload("Tables.Fields", orderBy("position, created_at, id")).
+		Preload("Relationships", orderBy("created_at, id")).Preload("Tags", orderBy("name")).
+		First(&project, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &project, nil
 }
 
-func (r *ProjectRepository) GetByOwnerID(ownerID uuid.UUID) ([]*models.Project, error) {
+// ProjectIncludes selects what a listing of projects loads besides the
+// projects and their tags
+type ProjectIncludes struct {
+	Collaborators bool
+	TablesCount   bool // Counted by a subquery of the listing into Project.TablesCount
+}
+
+// listProjects starts a query listing projects with the includes
+func (r *ProjectRepository) listProjects(includes ProjectIncludes) *gorm.DB {
+	query := r.db.Model(&models.Project{}).Preload("Tags", orderBy("name"))
+	if includes.Collaborators {
+		query = query.Preload("Collaborators", orderBy("username"))
+	}
+	if includes.TablesCount {
+		query = query.Select("projects.*, (SELECT COUNT(*) FROM tables WHERE tables.project_id = projects.id) AS tables_count")
+	}
+	return query
+}
+
+func (r *ProjectRepository) GetByOwnerID(ownerID uuid.UUID, includes ProjectIncludes) ([]*models.Project, error) {
 	var projects []*models.Project
-	err := r.db.Preload("Owner").Preload("Collaborators").Where("owner_id = ?", ownerID).Find(&projects).Error
+	err := r.listProjects(includes).Where("owner_id = ?", ownerID).Find(&projects).Error
 	return projects, err
 }
 
-func (r *ProjectRepository) GetByCollaboratorID(collaboratorID uuid.UUID) ([]*models.Project, error) {
+// CountByOwnerID returns how many projects the user owns
+func (r *ProjectRepository) CountByOwnerID(ownerID uuid.UUID) (int, error) {
+	var count int64
+	err := r.db.Model(&models.Project{}).Where("owner_id = ?", ownerID).Count(&count).Error
+	return int(count), err
+}
+
+func (r *ProjectRepository) GetByCollaboratorID(collaboratorID uuid.UUID, includes ProjectIncludes) ([]*models.Project, error) {
 	var projects []*models.Project
-	err := r.db.Preload("Owner").Preload("Collaborators").
+	err := r.listProjects(includes).
 		Joins("JOIN project_collaborators ON projects.id = project_collaborators.project_id").
 		Where("project_collaborators.user_id = ?", collaboratorID).
 		Find(&projects).Error
 	return projects, err
 }
 
-func (r *ProjectRepository) GetAll() ([]*models.Project, error) {
-	var projects []*models.Project
-	err := r.db.Preload("Owner").Preload("Collaborators").Find(&projects).Error
-	return projects, err
+// ProjectFilter narrows the projects returned by List. Zero values do not filter.
+type ProjectFilter struct {
+	Name    string // Case-insensitive substring of the name
+	OwnerID *uuid.UUID
 }
 
+var projectSorts = map[string]sortField[models.Project]{
+	"created_at": {"created_at", func(p *models.Project) any { return p.CreatedAt }},
+	"updated_at": {"updated_at", func(p *models.Project) any { return p.UpdatedAt }},
+	"name":       {"name", func(p *models.Project) any { return p.Name }},
+}
+
+// List returns a page of projects matching the filter, oldest first by default
+func (r *ProjectRepository) List(filter ProjectFilter, page PageQuery) ([]*models.Project, string, error) {
+	query := r.db.Preload("Owner").Preload("Collaborators").Preload("Tags", orderBy("name"))
+	if filter.Name != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filter.Name)+"%")
+	}
+	if filter.OwnerID != nil {
+		query = query.Where("owner_id = ?", *filter.OwnerID)
+	}
+	return paginate(query, page, projectSorts, "created_at", func(p *models.Project) uuid.UUID { return p.ID })
+}
+
+// Update saves a project read at project.Version and advances the version. It fails
+// with ErrVersionConflict when the project has changed since.
 func (r *ProjectRepository) Update(project *models.Project) error {
-	return r.db.Save(project).Error
+	return saveVersioned(r.db, project, &project.Version)
+}
+
+// NextSequence advances the project's sequence and returns it. The row stays
+// locked until the surrounding transaction ends, so changes to one project are
+// numbered in the order they commit.
+func (r *ProjectRepository) NextSequence(id uuid.UUID) (int64, error) {
+	var sequence int64
+	result := r.db.Raw(`UPDATE projects SET sequence = sequence + 1 WHERE id = ? RETURNING sequence`, id).Scan(&sequence)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return sequence, nil
 }
 
 func (r *ProjectRepository) Delete(id uuid.UUID) error {
@@ -88,3 +158,80 @@ func (r *ProjectRepository) RemoveCollaborator(projectID, userID uuid.UUID) erro
 
 	return r.db.Model(&project).Association("Collaborators").Delete(&user)
 }
+
+// SetCollaboratorRole makes a collaborator an editor or a viewer of the project
+func (r *ProjectRepository) SetCollaboratorRole(projectID, userID uuid.UUID, role string) error {
+	result := r.db.Exec(`UPDATE project_collaborators SET role = ? WHERE project_id = ? AND user_id = ?`, role, projectID, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetViewerIDs returns the collaborators who may not change the project
+func (r *ProjectRepository) GetViewerIDs(projectID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.Table("project_collaborators").
+		Where("project_id = ? AND role = ?", projectID, models.ProjectRoleViewer).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// AddTag labels a project; adding a tag it already has does nothing
+func (r *ProjectRepository) AddTag(projectID uuid.UUID, name string) error {
+	tag := &models.ProjectTag{ProjectID: projectID, Name: name}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(tag).Error
+}
+
+func (r *ProjectRepository) RemoveTag(projectID uuid.UUID, name string) error {
+	return r.db.Delete(&models.ProjectTag{}, "project_id = ? AND name = ?", projectID, name).Error
+}
+
+// Star adds a project to the user's favorites; starring it again does nothing
+func (r *ProjectRepository) Star(projectID, userID uuid.UUID) error {
+	star := &models.ProjectStar{UserID: userID, ProjectID: projectID}
+	return r.db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(star).Error
+}
+
+func (r *ProjectRepository) Unstar(projectID, userID uuid.UUID) error {
+	return r.db.Delete(&models.ProjectStar{}, "user_id = ? AND project_id = ?", userID, projectID).Error
+}
+
+// GetStarredProjectIDs returns the user's favorite projects
+func (r *ProjectRepository) GetStarredProjectIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	var projectIDs []uuid.UUID
+	err := r.db.Model(&models.ProjectStar{}).Where("user_id = ?", userID).Pluck("project_id", &projectIDs).Error
+	return projectIDs, err
+}
+
+// RecordView remembers that the user opened the project, keeping one row per project
+func (r *ProjectRepository) RecordView(projectID, userID uuid.UUID, viewedAt time.Time) error {
+	view := &models.ProjectView{UserID: userID, ProjectID: projectID, ViewedAt: viewedAt}
+	return r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "project_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"viewed_at"}),
+	}).Create(view).Error
+}
+
+// GetRecentlyViewed returns the projects the user opened last, most recent first,
+// leaving out those the user can no longer access
+func (r *ProjectRepository) GetRecentlyViewed(userID uuid.UUID, limit int) ([]*models.ProjectView, error) {
+	var views []*models.ProjectView
+	err := r.db.Preload("Project").Preload("Project.Tags", orderBy("name")).
+		Joins("JOIN projects ON projects.id = project_views.project_id").
+		Where("project_views.user_id = ?", userID).
+		Where("projects.owner_id = ? OR projects.id IN (SELECT project_id FROM project_collaborators WHERE user_id = ?)", userID, userID).
+		Order("project_views.viewed_at DESC").Limit(limit).
+		Find(&views).Error
+	return views, err
+}
+
+// orderBy sorts the records of a preload
+func orderBy(order string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Order(order)
+	}
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TablePosition is where a table sits on the canvas after a move
+type TablePosition struct {
+	ID      uuid.UUID `json:"id"`
+	PosX    float64   `json:"pos_x"`
+	PosY    float64   `json:"pos_y"`
+	Version int64     `json:"version"`
+}
+
+type RegionRepository struct {
+	db *gorm.DB
+}
+
+func NewRegionRepository(db *gorm.DB) RegionRepositoryInterface {
+	return &RegionRepository{db: db}
+}
+
+func (r *RegionRepository) Create(region *models.Region) (uuid.UUID, error) {
+	if err := r.db.Create(region).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return region.ID, nil
+}
+
+func (r *RegionRepository) GetByID(id uuid.UUID) (*models.Region, error) {
+	var region models.Region
+	if err := r.db.Scopes(db.ReplicaRead).First(&region, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if err := r.loadTableIDs([]*models.Region{&region}); err != nil {
+		return nil, err
+	}
+	return &region, nil
+}
+
+func (r *RegionRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Region, error) {
+	var regions []*models.Region
+	err := r.db.Scopes(db.ReplicaRead).Where("project_id = ?", projectID).Order("created_at").Find(&regions).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadTableIDs(regions); err != nil {
+		return nil, err
+	}
+	return regions, nil
+}
+
+// loadTableIDs fills in the tables of each region with a single query
+func (r *RegionRepository) loadTableIDs(regions []*models.Region) error {
+	if len(regions) == 0 {
+		return nil
+	}
+	byID := make(map[uuid.UUID]*models.Region, len(regions))
+	ids := make([]uuid.UUID, len(regions))
+	for i, region := range regions {
+		region.TableIDs = []uuid.UUID{}
+		byID[region.ID] = region
+		ids[i] = region.ID
+	}
+
+	var members []struct {
+		ID       uuid.UUID
+		RegionID uuid.UUID
+	}
+	err := r.db.Scopes(db.ReplicaRead).Table("tables").Select("id", "region_id").
+		Where("region_id IN ?", ids).Order("created_at").Scan(&members).Error
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		byID[member.RegionID].TableIDs = append(byID[member.RegionID].TableIDs, member.ID)
+	}
+	return nil
+}
+
+// Update saves a region read at region.Version and advances the version. It fails
+// with ErrVersionConflict when the region has changed since. Its tables are
+// changed with SetTables.
+func (r *RegionRepository) Update(region *models.Region) error {
+	return saveVersioned(r.db, region, &region.Version)
+}
+
+// SetTables makes tableIDs the tables of a region, taking the others out of it.
+// A table in another region moves to this one.
+func (r *RegionRepository) SetTables(regionID uuid.UUID, tableIDs []uuid.UUID) error {
+	leaving := r.db.Model(&models.Table{}).Where("region_id = ?", regionID)
+	if len(tableIDs) > 0 {
+		leaving = leaving.Where("id NOT IN ?", tableIDs)
+	}
+	if err := leaving.Updates(map[string]any{"region_id": nil, "version": nextVersion}).Error; err != nil {
+		return err
+	}
+	if len(tableIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&models.Table{}).Where("id IN ? AND region_id IS DISTINCT FROM ?", tableIDs, regionID).
+		Updates(map[string]any{"region_id": regionID, "version": nextVersion}).Error
+}
+
+// MoveTables moves every table of a region by dx and dy with a single
+// statement and returns where they are now
+func (r *RegionRepository) MoveTables(regionID uuid.UUID, dx, dy float64) ([]TablePosition, error) {
+	var positions []TablePosition
+	err := r.db.Raw(`UPDATE tables SET pos_x = pos_x + ?, pos_y = pos_y + ?, version = version + 1, updated_at = NOW()
+		WHERE region_id = ? RETURNING id, pos_x, pos_y, version`, dx, dy, regionID).Scan(&positions).Error
+	if err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+// Delete removes a region. Its tables stay where they are, outside any region.
+func (r *RegionRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Region{}, "id = ?", id).Error
+}
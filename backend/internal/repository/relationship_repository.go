@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"github.com/Bug-Bugger/ezmodel/internal/db"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -23,16 +24,27 @@ func (r *RelationshipRepository) Create(relationship *models.Relationship) (uuid
 
 func (r *RelationshipRepository) GetByID(id uuid.UUID) (*models.Relationship, error) {
 	var relationship models.Relationship
-	err := r.db.First(&relationship, "id = ?", id).Error
+	err := r.db.Scopes(db.ReplicaRead).First(&relationship, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &relationship, nil
 }
 
+// GetByIDs returns the relationships with the given IDs in one query.
+// Relationships that do not exist are left out.
+func (r *RelationshipRepository) GetByIDs(ids []uuid.UUID) ([]*models.Relationship, error) {
+	relationships := make([]*models.Relationship, 0, len(ids))
+	if len(ids) == 0 {
+		return relationships, nil
+	}
+	err := r.db.Scopes(db.ReplicaRead).Where("id IN ?", ids).Find(&relationships).Error
+	return relationships, err
+}
+
 func (r *RelationshipRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Relationship, error) {
 	var relationships []*models.Relationship
-	err := r.db.Where("project_id = ?", projectID).Find(&relationships).Error
+	err := r.db.Scopes(db.ReplicaRead).Where("project_id = ?", projectID).Find(&relationships).Error
 	if err != nil {
 		return nil, err
 	}
@@ -48,8 +60,10 @@ func (r *RelationshipRepository) GetByTableID(tableID uuid.UUID) ([]*models.Rela
 	return relationships, nil
 }
 
+// Update saves a relationship read at relationship.Version and advances the version. It fails
+// with ErrVersionConflict when the relationship has changed since.
 func (r *RelationshipRepository) Update(relationship *models.Relationship) error {
-	return r.db.Save(relationship).Error
+	return saveVersioned(r.db, relationship, &relationship.Version)
 }
 
 func (r *RelationshipRepository) Delete(id uuid.UUID) error {
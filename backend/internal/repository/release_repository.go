@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReleaseRepository struct {
+	db *gorm.DB
+}
+
+func NewReleaseRepository(db *gorm.DB) ReleaseRepositoryInterface {
+	return &ReleaseRepository{db: db}
+}
+
+func (r *ReleaseRepository) Create(release *models.Release) (uuid.UUID, error) {
+	if err := r.db.Create(release).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return release.ID, nil
+}
+
+func (r *ReleaseRepository) GetByID(id uuid.UUID) (*models.Release, error) {
+	var release models.Release
+	if err := r.db.Scopes(db.ReplicaRead).First(&release, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// GetByName returns the project's release of that name, or gorm.ErrRecordNotFound
+func (r *ReleaseRepository) GetByName(projectID uuid.UUID, name string) (*models.Release, error) {
+	var release models.Release
+	if err := r.db.Scopes(db.ReplicaRead).First(&release, "project_id = ? AND name = ?", projectID, name).Error; err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// GetByProjectID returns the project's releases newest first
+func (r *ReleaseRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Release, error) {
+	var releases []*models.Release
+	err := r.db.Scopes(db.ReplicaRead).Where("project_id = ?", projectID).Order("created_at DESC, id").Find(&releases).Error
+	return releases, err
+}
+
+func (r *ReleaseRepository) Update(release *models.Release) error {
+	return r.db.Save(release).Error
+}
+
+func (r *ReleaseRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Release{}, "id = ?", id).Error
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RenameRepository struct {
+	db *gorm.DB
+}
+
+func NewRenameRepository(db *gorm.DB) RenameRepositoryInterface {
+	return &RenameRepository{db: db}
+}
+
+func (r *RenameRepository) Create(rename *models.SchemaRename) (uuid.UUID, error) {
+	if err := r.db.Create(rename).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return rename.ID, nil
+}
+
+// GetByProjectID returns the project's renames oldest first, the order they replay in
+func (r *RenameRepository) GetByProjectID(projectID uuid.UUID) ([]*models.SchemaRename, error) {
+	var renames []*models.SchemaRename
+	err := r.db.Scopes(db.ReplicaRead).Where("project_id = ?", projectID).Order("created_at, id").Find(&renames).Error
+	return renames, err
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReviewCommentRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewCommentRepository(db *gorm.DB) ReviewCommentRepositoryInterface {
+	return &ReviewCommentRepository{db: db}
+}
+
+func (r *ReviewCommentRepository) Create(comment *models.ReviewComment) (uuid.UUID, error) {
+	if err := r.db.Create(comment).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return comment.ID, nil
+}
+
+func (r *ReviewCommentRepository) GetByID(id uuid.UUID) (*models.ReviewComment, error) {
+	var comment models.ReviewComment
+	if err := r.db.Scopes(db.ReplicaRead).First(&comment, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetByProjectID returns the project's review comments, oldest first
+func (r *ReviewCommentRepository) GetByProjectID(projectID uuid.UUID) ([]*models.ReviewComment, error) {
+	var comments []*models.ReviewComment
+	err := r.db.Scopes(db.ReplicaRead).Where("project_id = ?", projectID).Order("created_at, id").Find(&comments).Error
+	return comments, err
+}
+
+func (r *ReviewCommentRepository) Update(comment *models.ReviewComment) error {
+	return r.db.Save(comment).Error
+}
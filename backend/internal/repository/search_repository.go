@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Kinds of record a search can match
+const (
+	SearchTypeProject = "project"
+	SearchTypeTable   = "table"
+	SearchTypeField   = "field"
+)
+
+// SearchTypes lists every kind of record a search can match
+var SearchTypes = []string{SearchTypeProject, SearchTypeTable, SearchTypeField}
+
+// SearchQuery describes a full-text search over the projects a user can access
+type SearchQuery struct {
+	Terms     []string   // Lowercase letters and digits only; each matches as a word prefix
+	UserID    uuid.UUID  // Only projects the user owns or collaborates on
+	ProjectID *uuid.UUID // Only this project when set
+	Types     []string   // Kinds of record to match, all when empty
+	Limit     int
+}
+
+// SearchHit is a project, table or field matching a search
+type SearchHit struct {
+	Type        string
+	ID          uuid.UUID
+	ProjectID   uuid.UUID
+	ProjectName string
+	TableID     *uuid.UUID
+	TableName   *string
+	Name        string
+	Detail      string // Project description or field data type
+	Rank        float64
+}
+
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepositoryInterface {
+	return &SearchRepository{db: db}
+}
+
+// searchSelects holds the query matching each kind of record. Each selects from
+// the accessible projects a and the tsquery q defined in Search.
+var searchSelects = map[string]string{
+	SearchTypeProject: `SELECT 'project' AS type, p.id, p.id AS project_id, a.name AS project_name,
+			NULL::uuid AS table_id, NULL AS table_name, p.name, COALESCE(p.description, '') AS detail,
+			ts_rank(p.search_vector, q.query) AS rank
+		FROM projects p JOIN accessible a ON a.id = p.id, q
+		WHERE p.search_vector @@ q.query`,
+	SearchTypeTable: `SELECT 'table' AS type, t.id, t.project_id, a.name AS project_name,
+			NULL::uuid AS table_id, NULL AS table_name, t.name, '' AS detail,
+			ts_rank(t.search_vector, q.query) AS rank
+		FROM tables t JOIN accessible a ON a.id = t.project_id, q
+		WHERE t.search_vector @@ q.query`,
+	SearchTypeField: `SELECT 'field' AS type, f.id, t.project_id, a.name AS project_name,
+			t.id AS table_id, t.name AS table_name, f.name, f.data_type AS detail,
+			ts_rank(f.search_vector, q.query) AS rank
+		FROM fields f JOIN tables t ON t.id = f.table_id JOIN accessible a ON a.id = t.project_id, q
+		WHERE f.search_vector @@ q.query`,
+}
+
+// Search returns the best matches first, using the search_vector columns and
+// their GIN indexes created by the initial migration
+func (r *SearchRepository) Search(query SearchQuery) ([]*SearchHit, error) {
+	if len(query.Terms) == 0 {
+		return []*SearchHit{}, nil
+	}
+
+	types := query.Types
+	if len(types) == 0 {
+		types = SearchTypes
+	}
+
+	accessible := `SELECT id, name FROM projects
+		WHERE (owner_id = @user OR id IN (SELECT project_id FROM project_collaborators WHERE user_id = @user))`
+	if query.ProjectID != nil {
+		accessible += ` AND id = @project`
+	}
+
+	selects := make([]string, 0, len(types))
+	for _, searchType := range types {
+		if sql, ok := searchSelects[searchType]; ok {
+			selects = append(selects, sql)
+		}
+	}
+
+	sql := `WITH q AS (SELECT to_tsquery('simple', @tsquery) AS query), accessible AS (` + accessible + `) ` +
+		strings.Join(selects, " UNION ALL ") +
+		` ORDER BY rank DESC, name, id LIMIT @limit`
+
+	args := map[string]any{
+		"tsquery": prefixTSQuery(query.Terms),
+		"user":    query.UserID,
+		"limit":   query.Limit,
+	}
+	if query.ProjectID != nil {
+		args["project"] = *query.ProjectID
+	}
+
+	var hits []*SearchHit
+	if err := r.db.Raw(sql, args).Scan(&hits).Error; err != nil {
+		return nil, err
+	}
+	if hits == nil {
+		hits = []*SearchHit{}
+	}
+	return hits, nil
+}
+
+// prefixTSQuery matches every term as the prefix of a word, so "cust" finds customer_id
+func prefixTSQuery(terms []string) string {
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = term + ":*"
+	}
+	return strings.Join(prefixes, " & ")
+}
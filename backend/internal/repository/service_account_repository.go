@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ServiceAccountRepository struct {
+	db *gorm.DB
+}
+
+func NewServiceAccountRepository(db *gorm.DB) ServiceAccountRepositoryInterface {
+	return &ServiceAccountRepository{
+		db: db,
+	}
+}
+
+func (r *ServiceAccountRepository) Create(account *models.ServiceAccount) (uuid.UUID, error) {
+	result := r.db.Create(account)
+	if result.Error != nil {
+		return uuid.Nil, result.Error
+	}
+	return account.ID, nil
+}
+
+func (r *ServiceAccountRepository) GetByID(id uuid.UUID) (*models.ServiceAccount, error) {
+	var account models.ServiceAccount
+	result := r.db.First(&account, "id = ?", id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &account, nil
+}
+
+func (r *ServiceAccountRepository) GetByProjectID(projectID uuid.UUID) ([]*models.ServiceAccount, error) {
+	var accounts []*models.ServiceAccount
+	result := r.db.Where("project_id = ?", projectID).Order("created_at").Find(&accounts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return accounts, nil
+}
+
+func (r *ServiceAccountRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.ServiceAccount{}, "id = ?", id).Error
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SnapshotRepository struct {
+	db *gorm.DB
+}
+
+func NewSnapshotRepository(db *gorm.DB) SnapshotRepositoryInterface {
+	return &SnapshotRepository{db: db}
+}
+
+func (r *SnapshotRepository) Create(snapshot *models.ProjectSnapshot) (uuid.UUID, error) {
+	if err := r.db.Create(snapshot).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return snapshot.ID, nil
+}
+
+// GetByProjectID returns the project's snapshots newest first, without their data
+func (r *SnapshotRepository) GetByProjectID(projectID uuid.UUID) ([]*models.ProjectSnapshot, error) {
+	var snapshots []*models.ProjectSnapshot
+	err := r.db.Scopes(db.ReplicaRead).Omit("data").Where("project_id = ?", projectID).
+		Order("created_at DESC, id").Find(&snapshots).Error
+	return snapshots, err
+}
+
+func (r *SnapshotRepository) GetByID(id uuid.UUID) (*models.ProjectSnapshot, error) {
+	var snapshot models.ProjectSnapshot
+	if err := r.db.Scopes(db.ReplicaRead).First(&snapshot, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// DeleteByIDs deletes the snapshots but those pinned by a release
+func (r *SnapshotRepository) DeleteByIDs(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Delete(&models.ProjectSnapshot{}, "id IN ? AND id NOT IN (SELECT snapshot_id FROM releases)", ids).Error
+}
+
+// GetProjectIDsToSnapshot returns the projects that changed since their
+// latest snapshot, including those without any
+func (r *SnapshotRepository) GetProjectIDsToSnapshot() ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Raw(`SELECT p.id FROM projects p
+		LEFT JOIN LATERAL (
+			SELECT project_version, project_sequence FROM project_snapshots s
+			WHERE s.project_id = p.id ORDER BY created_at DESC LIMIT 1
+		) latest ON true
+		WHERE latest.project_version IS NULL
+			OR latest.project_version <> p.version OR latest.project_sequence <> p.sequence
+		ORDER BY p.id`).Scan(&ids).Error
+	return ids, err
+}
+
+// GetPolicy returns gorm.ErrRecordNotFound for projects using the default retention
+func (r *SnapshotRepository) GetPolicy(projectID uuid.UUID) (*models.SnapshotPolicy, error) {
+	var policy models.SnapshotPolicy
+	if err := r.db.First(&policy, "project_id = ?", projectID).Error; err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SavePolicy creates or replaces the project's retention overrides
+func (r *SnapshotRepository) SavePolicy(policy *models.SnapshotPolicy) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"keep_daily", "keep_monthly", "updated_at"}),
+	}).Create(policy).Error
+}
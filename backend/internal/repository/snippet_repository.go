@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SnippetRepository struct {
+	db *gorm.DB
+}
+
+func NewSnippetRepository(db *gorm.DB) SnippetRepositoryInterface {
+	return &SnippetRepository{db: db}
+}
+
+func (r *SnippetRepository) Create(snippet *models.Snippet) (uuid.UUID, error) {
+	if err := r.db.Create(snippet).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return snippet.ID, nil
+}
+
+func (r *SnippetRepository) GetByID(id uuid.UUID) (*models.Snippet, error) {
+	var snippet models.Snippet
+	if err := r.db.Scopes(db.ReplicaRead).First(&snippet, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &snippet, nil
+}
+
+// GetByProjectID returns the project's snippets by name
+func (r *SnippetRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Snippet, error) {
+	var snippets []*models.Snippet
+	err := r.db.Scopes(db.ReplicaRead).Where("project_id = ?", projectID).Order("name").Find(&snippets).Error
+	return snippets, err
+}
+
+func (r *SnippetRepository) Update(snippet *models.Snippet) error {
+	return r.db.Save(snippet).Error
+}
+
+func (r *SnippetRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Snippet{}, "id = ?", id).Error
+}
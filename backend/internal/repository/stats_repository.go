@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/db"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InstanceTotals are counts over the whole instance
+type InstanceTotals struct {
+	Users         int64
+	Projects      int64
+	DatabaseBytes int64 // On disk, including indexes and other applications' tables
+}
+
+// DailyCount is the number of records created on a day
+type DailyCount struct {
+	Day   time.Time // Midnight UTC
+	Count int64
+}
+
+// OwnerStorage is how much the projects of one owner take up
+type OwnerStorage struct {
+	OwnerID  uuid.UUID
+	Username string
+	Projects int64
+	Tables   int64
+	Fields   int64
+	Bytes    int64 // Size of the rows, without indexes or storage overhead
+}
+
+type StatsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) StatsRepositoryInterface {
+	return &StatsRepository{db: db}
+}
+
+func (r *StatsRepository) GetTotals(ctx context.Context) (*InstanceTotals, error) {
+	var totals InstanceTotals
+	err := r.db.WithContext(ctx).Scopes(db.ReplicaRead).Raw(`SELECT
+			(SELECT COUNT(*) FROM users) AS users,
+			(SELECT COUNT(*) FROM projects) AS projects,
+			pg_database_size(current_database()) AS database_bytes`).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
+
+// CountSignupsByDay returns the users created on each day since the time,
+// leaving out days without any
+func (r *StatsRepository) CountSignupsByDay(ctx context.Context, since time.Time) ([]*DailyCount, error) {
+	var counts []*DailyCount
+	err := r.db.WithContext(ctx).Scopes(db.ReplicaRead).Raw(`SELECT date_trunc('day', created_at AT TIME ZONE 'UTC') AS day, COUNT(*) AS count
+		FROM users WHERE created_at >= ?
+		GROUP BY day ORDER BY day`, since).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, count := range counts {
+		count.Day = time.Date(count.Day.Year(), count.Day.Month(), count.Day.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return counts, nil
+}
+
+// ListLargestOwners returns the owners whose projects take up the most space
+// first. Sizes are summed with pg_column_size over the rows of the projects
+// and their tables, fields, relationships and regions, which scans them all.
+func (r *StatsRepository) ListLargestOwners(ctx context.Context, limit int) ([]*OwnerStorage, error) {
+	var owners []*OwnerStorage
+	err := r.db.WithContext(ctx).Scopes(db.ReplicaRead).Raw(`WITH project_sizes AS (
+			SELECT p.id, p.owner_id,
+				pg_column_size(p.*)
+					+ COALESCE((SELECT SUM(pg_column_size(t.*)) FROM tables t WHERE t.project_id = p.id), 0)
+					+ COALESCE((SELECT SUM(pg_column_size(f.*)) FROM fields f JOIN tables t ON t.id = f.table_id WHERE t.project_id = p.id), 0)
+					+ COALESCE((SELECT SUM(pg_column_size(rel.*)) FROM relationships rel WHERE rel.project_id = p.id), 0)
+					+ COALESCE((SELECT SUM(pg_column_size(reg.*)) FROM regions reg WHERE reg.project_id = p.id), 0) AS bytes,
+				(SELECT COUNT(*) FROM tables t WHERE t.project_id = p.id) AS tables,
+				(SELECT COUNT(*) FROM fields f JOIN tables t ON t.id = f.table_id WHERE t.project_id = p.id) AS fields
+			FROM projects p
+		)
+		SELECT s.owner_id, u.username, COUNT(*) AS projects, SUM(s.tables) AS tables, SUM(s.fields) AS fields, SUM(s.bytes) AS bytes
+		FROM project_sizes s JOIN users u ON u.id = s.owner_id
+		GROUP BY s.owner_id, u.username
+		ORDER BY bytes DESC, s.owner_id
+		LIMIT ?`, limit).
+		Scan(&owners).Error
+	if err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
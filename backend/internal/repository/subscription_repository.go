@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepositoryInterface {
+	return &SubscriptionRepository{db: db}
+}
+
+// GetByUserID returns gorm.ErrRecordNotFound for users who never subscribed
+func (r *SubscriptionRepository) GetByUserID(userID uuid.UUID) (*models.Subscription, error) {
+	var subscription models.Subscription
+	if err := r.db.First(&subscription, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *SubscriptionRepository) GetByStripeCustomerID(customerID string) (*models.Subscription, error) {
+	var subscription models.Subscription
+	if err := r.db.First(&subscription, "stripe_customer_id = ?", customerID).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// Save creates or replaces the user's subscription
+func (r *SubscriptionRepository) Save(subscription *models.Subscription) error {
+	return r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"plan", "status", "stripe_customer_id", "stripe_subscription_id", "current_period_end", "event_created_at", "updated_at"}),
+	}).Create(subscription).Error
+}
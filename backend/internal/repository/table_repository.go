@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/db"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -16,31 +19,64 @@ func NewTableRepository(db *gorm.DB) TableRepositoryInterface {
 
 func (r *TableRepository) Create(table *models.Table) (uuid.UUID, error) {
 	if err := r.db.Create(table).Error; err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, translateDuplicate(err)
 	}
 	return table.ID, nil
 }
 
 func (r *TableRepository) GetByID(id uuid.UUID) (*models.Table, error) {
 	var table models.Table
-	err := r.db.Preload("Fields").First(&table, "id = ?", id).Error
+	err := r.db.Scopes(db.ReplicaRead).Preload("Fields").First(&table, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &table, nil
 }
 
+// GetByIDs returns the tables with the given IDs, without their fields, in one
+// query. Tables that do not exist are left out.
+func (r *TableRepository) GetByIDs(ids []uuid.UUID) ([]*models.Table, error) {
+	tables := make([]*models.Table, 0, len(ids))
+	if len(ids) == 0 {
+		return tables, nil
+	}
+	err := r.db.Scopes(db.ReplicaRead).Where("id IN ?", ids).Find(&tables).Error
+	return tables, err
+}
+
 func (r *TableRepository) GetByProjectID(projectID uuid.UUID) ([]*models.Table, error) {
 	var tables []*models.Table
-	err := r.db.Preload("Fields").Where("project_id = ?", projectID).Find(&tables).Error
+	err := r.db.Scopes(db.ReplicaRead).Preload("Fields").Where("project_id = ?", projectID).Find(&tables).Error
 	if err != nil {
 		return nil, err
 	}
 	return tables, nil
 }
 
+// TableFilter narrows the tables returned by ListByProjectID. Zero values do not filter.
+type TableFilter struct {
+	Name string // Case-insensitive substring of the name
+}
+
+var tableSorts = map[string]sortField[models.Table]{
+	"created_at": {"created_at", func(t *models.Table) any { return t.CreatedAt }},
+	"updated_at": {"updated_at", func(t *models.Table) any { return t.UpdatedAt }},
+	"name":       {"name", func(t *models.Table) any { return t.Name }},
+}
+
+// ListByProjectID returns a page of a project's tables, oldest first by default
+func (r *TableRepository) ListByProjectID(projectID uuid.UUID, filter TableFilter, page PageQuery) ([]*models.Table, string, error) {
+	query := r.db.Where("project_id = ?", projectID)
+	if filter.Name != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(filter.Name)+"%")
+	}
+	return paginate(query, page, tableSorts, "created_at", func(t *models.Table) uuid.UUID { return t.ID })
+}
+
+// Update saves a table read at table.Version and advances the version. It fails
+// with ErrVersionConflict when the table has changed since.
 func (r *TableRepository) Update(table *models.Table) error {
-	return r.db.Save(table).Error
+	return translateDuplicate(saveVersioned(r.db, table, &table.Version))
 }
 
 func (r *TableRepository) Delete(id uuid.UUID) error {
@@ -48,8 +84,47 @@ func (r *TableRepository) Delete(id uuid.UUID) error {
 }
 
 func (r *TableRepository) UpdatePosition(id uuid.UUID, posX, posY float64) error {
-	return r.db.Model(&models.Table{}).Where("id = ?", id).Select("pos_x", "pos_y").Updates(map[string]any{
-		"pos_x": posX,
-		"pos_y": posY,
+	return r.db.Model(&models.Table{}).Where("id = ?", id).Select("pos_x", "pos_y", "version").Updates(map[string]any{
+		"pos_x":   posX,
+		"pos_y":   posY,
+		"version": nextVersion,
 	}).Error
 }
+
+// SetFieldOrderSequence records that the table's fields were reordered at
+// sequence, unless a reorder after since was recorded already, in which case it
+// fails with ErrVersionConflict. The row lock it takes makes concurrent
+// reorders of a table wait for each other, so only one of them can win.
+func (r *TableRepository) SetFieldOrderSequence(id uuid.UUID, sequence, since int64) error {
+	result := r.db.Exec(`UPDATE tables SET field_order_sequence = ? WHERE id = ? AND field_order_sequence <= ?`, sequence, id, since)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// CountByProjectIDs returns how many tables each of the projects has. Projects
+// without tables are left out.
+func (r *TableRepository) CountByProjectIDs(projectIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int, len(projectIDs))
+	if len(projectIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		ProjectID uuid.UUID
+		Count     int
+	}
+	err := r.db.Scopes(db.ReplicaRead).Model(&models.Table{}).Select("project_id, COUNT(*) AS count").
+		Where("project_id IN ?", projectIDs).Group("project_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ProjectID] = row.Count
+	}
+	return counts, nil
+}
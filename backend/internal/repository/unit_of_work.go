@@ -0,0 +1,49 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Repositories are the schema repositories bound to one transaction
+type Repositories struct {
+	Projects      ProjectRepositoryInterface
+	Tables        TableRepositoryInterface
+	Fields        FieldRepositoryInterface
+	Relationships RelationshipRepositoryInterface
+	Regions       RegionRepositoryInterface
+	Renames       RenameRepositoryInterface
+	Snippets      SnippetRepositoryInterface
+	Docs          DocRepositoryInterface
+	Branches      BranchRepositoryInterface
+	Releases      ReleaseRepositoryInterface
+	Snapshots     SnapshotRepositoryInterface
+	Outbox        OutboxRepositoryInterface
+}
+
+type UnitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWorkInterface {
+	return &UnitOfWork{db: db}
+}
+
+// Do runs fn in a transaction with repositories bound to it. The transaction
+// commits when fn returns nil and rolls back otherwise, so fn's changes are
+// either all saved or none is.
+func (u *UnitOfWork) Do(fn func(repos Repositories) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Projects:      NewProjectRepository(tx),
+			Tables:        NewTableRepository(tx),
+			Fields:        NewFieldRepository(tx),
+			Relationships: NewRelationshipRepository(tx),
+			Regions:       NewRegionRepository(tx),
+			Renames:       NewRenameRepository(tx),
+			Snippets:      NewSnippetRepository(tx),
+			Docs:          NewDocRepository(tx),
+			Branches:      NewBranchRepository(tx),
+			Releases:      NewReleaseRepository(tx),
+			Snapshots:     NewSnapshotRepository(tx),
+			Outbox:        NewOutboxRepository(tx),
+		})
+	})
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UserIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepositoryInterface {
+	return &UserIdentityRepository{
+		db: db,
+	}
+}
+
+func (r *UserIdentityRepository) Create(identity *models.UserIdentity) (uuid.UUID, error) {
+	result := r.db.Create(identity)
+	if result.Error != nil {
+		return uuid.Nil, result.Error
+	}
+	return identity.ID, nil
+}
+
+func (r *UserIdentityRepository) GetByProviderSubject(provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	result := r.db.First(&identity, "provider = ? AND subject = ?", provider, subject)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &identity, nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserPreferencesRepository struct {
+	db *gorm.DB
+}
+
+func NewUserPreferencesRepository(db *gorm.DB) UserPreferencesRepositoryInterface {
+	return &UserPreferencesRepository{db: db}
+}
+
+// GetByUserID returns gorm.ErrRecordNotFound for users who never saved preferences
+func (r *UserPreferencesRepository) GetByUserID(userID uuid.UUID) (*models.UserPreferences, error) {
+	var preferences models.UserPreferences
+	if err := r.db.First(&preferences, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &preferences, nil
+}
+
+// Save creates or replaces the user's preferences
+func (r *UserPreferencesRepository) Save(preferences *models.UserPreferences) error {
+	return r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		UpdateAll: true,
+	}).Create(preferences).Error
+}
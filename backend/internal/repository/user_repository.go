@@ -1,11 +1,20 @@
 package repository
 
 import (
+	"strings"
+
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// UserFilter narrows the users returned by List. Zero values do not filter.
+type UserFilter struct {
+	Query    string // Case-insensitive substring of the email or username
+	Role     string
+	Disabled *bool
+}
+
 type UserRepository struct {
 	db *gorm.DB
 }
@@ -19,7 +28,7 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 func (r *UserRepository) Create(user *models.User) (uuid.UUID, error) {
 	result := r.db.Create(user)
 	if result.Error != nil {
-		return uuid.Nil, result.Error
+		return uuid.Nil, translateDuplicate(result.Error)
 	}
 	return user.ID, nil
 }
@@ -42,21 +51,127 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepository) GetAll() ([]*models.User, error) {
-	var users []*models.User
-	result := r.db.Find(&users)
+func (r *UserRepository) GetByUsername(username string) (*models.User, error) {
+	var user models.User
+	result := r.db.First(&user, "username = ?", username)
 	if result.Error != nil {
 		return nil, result.Error
 	}
-	return users, nil
+	return &user, nil
+}
+
+var userSorts = map[string]sortField[models.User]{
+	"created_at": {"created_at", func(u *models.User) any { return u.CreatedAt }},
+	"username":   {"username", func(u *models.User) any { return u.Username }},
+	"email":      {"email", func(u *models.User) any { return u.Email }},
+}
+
+// List returns a page of users matching the filter, oldest first by default
+func (r *UserRepository) List(filter UserFilter, page PageQuery) ([]*models.User, string, error) {
+	return paginate(r.filtered(filter), page, userSorts, "created_at", func(u *models.User) uuid.UUID { return u.ID })
+}
+
+// Count returns the number of users matching the filter
+func (r *UserRepository) Count(filter UserFilter) (int64, error) {
+	var total int64
+	err := r.filtered(filter).Count(&total).Error
+	return total, err
+}
+
+func (r *UserRepository) filtered(filter UserFilter) *gorm.DB {
+	query := r.db.Model(&models.User{})
+	if filter.Query != "" {
+		pattern := "%" + strings.ToLower(filter.Query) + "%"
+		query = query.Where("LOWER(email) LIKE ? OR LOWER(username) LIKE ?", pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Disabled != nil {
+		if *filter.Disabled {
+			query = query.Where("disabled_at IS NOT NULL")
+		} else {
+			query = query.Where("disabled_at IS NULL")
+		}
+	}
+	return query
 }
 
 func (r *UserRepository) Update(user *models.User) error {
 	result := r.db.Save(user)
-	return result.Error
+	return translateDuplicate(result.Error)
 }
 
 func (r *UserRepository) Delete(id uuid.UUID) error {
 	result := r.db.Delete(&models.User{}, "id = ?", id)
 	return result.Error
 }
+
+// DeleteAccount removes a user and their personal data in one transaction.
+// Projects in transfers (project ID to new owner ID) move to the new owner,
+// who stops being a collaborator; the other owned projects are deleted.
+// Admin audit entries are kept for accountability but no longer name the user.
+// Sessions, API tokens and linked identities go with the user through their
+// cascading foreign keys.
+func (r *UserRepository) DeleteAccount(id uuid.UUID, transfers map[uuid.UUID]uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.First(&user, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		for projectID, newOwnerID := range transfers {
+			if err := tx.Model(&models.Project{}).Where("id = ? AND owner_id = ?", projectID, id).Updates(map[string]any{
+				"owner_id": newOwnerID,
+				"version":  nextVersion,
+			}).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM project_collaborators WHERE project_id = ? AND user_id = ?", projectID, newOwnerID).Error; err != nil {
+				return err
+			}
+		}
+
+		var owned []uuid.UUID
+		if err := tx.Model(&models.Project{}).Where("owner_id = ?", id).Pluck("id", &owned).Error; err != nil {
+			return err
+		}
+		if len(owned) > 0 {
+			if err := tx.Exec("DELETE FROM project_collaborators WHERE project_id IN ?", owned).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("project_id IN ?", owned).Delete(&models.CollaborationSession{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", owned).Delete(&models.Project{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec("DELETE FROM project_collaborators WHERE user_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.CollaborationSession{}).Error; err != nil {
+			return err
+		}
+		// Attempts for the email made while signed out are not linked to the user
+		if err := tx.Where("user_id = ? OR email = ?", id, strings.ToLower(strings.TrimSpace(user.Email))).Delete(&models.LoginEvent{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.AdminAuditLog{}).Where("target_user_id = ?", id).Updates(map[string]any{
+			"target_user_id": uuid.Nil,
+			"details":        "",
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.AdminAuditLog{}).Where("actor_id = ?", id).Updates(map[string]any{
+			"actor_id":   uuid.Nil,
+			"ip_address": "",
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.User{}, "id = ?", id).Error
+	})
+}
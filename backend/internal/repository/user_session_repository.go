@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UserSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewUserSessionRepository(db *gorm.DB) UserSessionRepositoryInterface {
+	return &UserSessionRepository{
+		db: db,
+	}
+}
+
+func (r *UserSessionRepository) Create(session *models.UserSession) (uuid.UUID, error) {
+	result := r.db.Create(session)
+	if result.Error != nil {
+		return uuid.Nil, result.Error
+	}
+	return session.ID, nil
+}
+
+func (r *UserSessionRepository) GetByID(id uuid.UUID) (*models.UserSession, error) {
+	var session models.UserSession
+	result := r.db.First(&session, "id = ?", id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+func (r *UserSessionRepository) GetActiveByUserID(userID uuid.UUID, now time.Time) ([]*models.UserSession, error) {
+	var sessions []*models.UserSession
+	result := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return sessions, nil
+}
+
+// Rotate replaces the refresh token of an active session, but only if the caller
+// presented the current one. It reports whether the session was updated.
+func (r *UserSessionRepository) Rotate(session *models.UserSession, previousHash string) (bool, error) {
+	result := r.db.Model(&models.UserSession{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", session.ID, previousHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash": session.RefreshTokenHash,
+			"ip_address":         session.IPAddress,
+			"user_agent":         session.UserAgent,
+			"last_seen_at":       session.LastSeenAt,
+			"expires_at":         session.ExpiresAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *UserSessionRepository) Revoke(id uuid.UUID, revokedAt time.Time) error {
+	result := r.db.Model(&models.UserSession{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", revokedAt)
+	return result.Error
+}
+
+func (r *UserSessionRepository) RevokeAllByUserID(userID, exceptID uuid.UUID, revokedAt time.Time) error {
+	result := r.db.Model(&models.UserSession{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, exceptID).
+		Update("revoked_at", revokedAt)
+	return result.Error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVersionConflict is returned when a record changed after it was read
+var ErrVersionConflict = errors.New("version conflict")
+
+// nextVersion advances a record's version column in an update
+var nextVersion = gorm.Expr("version + 1")
+
+// saveVersioned writes every column of a record read at *version and advances
+// the version. A writer that read the same version and saved first wins; the
+// other gets ErrVersionConflict and the record is left as it was.
+func saveVersioned(db *gorm.DB, record any, version *int64) error {
+	expected := *version
+	*version = expected + 1
+
+	result := db.Model(record).Where("version = ?", expected).
+		Select("*").Omit(clause.Associations, "created_at").Updates(record)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		*version = expected
+	}
+	return result.Error
+}
@@ -0,0 +1,162 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+)
+
+// instructions tell the model what to review and how to answer
+const instructions = `You review relational database schemas. The user sends a schema as JSON: tables with their fields, and relationships from a referenced field to the foreign key field referring to it.
+Point out normalization issues, foreign keys that need an index, and names inconsistent with the rest of the schema. Only point out what you are confident about.
+Answer with a JSON object {"annotations": [...]} where each annotation has:
+- "kind": "normalization", "missing_index" or "naming"
+- "table": the name of the table concerned, exactly as given
+- "field": the name of the field concerned, exactly as given, or "" for the whole table
+- "message": one sentence saying what is wrong
+- "suggestion": one or two sentences saying what to change`
+
+// ChatClient reviews schemas with a language model served through an OpenAI
+// compatible chat completions API
+type ChatClient struct {
+	client *http.Client
+	apiURL string
+	apiKey string
+	model  string
+}
+
+// NewChatClient creates a client asking model through the API at apiURL, e.g.
+// https://api.openai.com/v1, authenticated with apiKey when set
+func NewChatClient(apiURL, apiKey, model string, timeout time.Duration) *ChatClient {
+	return &ChatClient{
+		client: &http.Client{Timeout: timeout},
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		apiKey: apiKey,
+		model:  model,
+	}
+}
+
+// Name implements Provider
+func (c *ChatClient) Name() string {
+	return c.model
+}
+
+// chatSchema is the schema as the model is shown it
+type chatSchema struct {
+	Tables        []chatTable        `json:"tables"`
+	Relationships []chatRelationship `json:"relationships"`
+}
+
+type chatTable struct {
+	Name   string      `json:"name"`
+	Fields []chatField `json:"fields"`
+}
+
+type chatField struct {
+	Name         string `json:"name"`
+	DataType     string `json:"data_type"`
+	PrimaryKey   bool   `json:"primary_key,omitempty"`
+	Nullable     bool   `json:"nullable,omitempty"`
+	DefaultValue string `json:"default_value,omitempty"`
+}
+
+type chatRelationship struct {
+	From string `json:"from"` // table.field referred to
+	To   string `json:"to"`   // table.field referring to it
+	Type string `json:"type"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Review implements Provider. Annotations about tables or fields the schema
+// does not have, or of another kind, are left out.
+func (c *ChatClient) Review(ctx context.Context, schema schemadiff.Schema) ([]Annotation, error) {
+	shown := chatSchema{Tables: make([]chatTable, len(schema.Tables)), Relationships: make([]chatRelationship, len(schema.Relationships))}
+	for i, table := range schema.Tables {
+		shown.Tables[i] = chatTable{Name: table.Name, Fields: make([]chatField, len(table.Fields))}
+		for j, field := range table.Fields {
+			shown.Tables[i].Fields[j] = chatField{
+				Name:         field.Name,
+				DataType:     field.DataType,
+				PrimaryKey:   field.IsPrimaryKey,
+				Nullable:     field.IsNullable,
+				DefaultValue: field.DefaultValue,
+			}
+		}
+	}
+	for i, relationship := range schema.Relationships {
+		shown.Relationships[i] = chatRelationship{
+			From: relationship.SourceTable + "." + relationship.SourceField,
+			To:   relationship.TargetTable + "." + relationship.TargetField,
+			Type: relationship.RelationType,
+		}
+	}
+	content, err := json.Marshal(shown)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"model":           c.model,
+		"temperature":     0,
+		"response_format": map[string]string{"type": "json_object"},
+		"messages": []chatMessage{
+			{Role: "system", Content: instructions},
+			{Role: "user", Content: string(content)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("chat completion failed with status %d", resp.StatusCode)
+	}
+
+	var completion struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(respBody, &completion); err != nil {
+		return nil, err
+	}
+	if len(completion.Choices) == 0 {
+		return nil, errors.New("chat completion has no choices")
+	}
+	var answer struct {
+		Annotations []Annotation `json:"annotations"`
+	}
+	if err := json.Unmarshal([]byte(completion.Choices[0].Message.Content), &answer); err != nil {
+		return nil, fmt.Errorf("reading the review: %w", err)
+	}
+	return known(schema, answer.Annotations), nil
+}
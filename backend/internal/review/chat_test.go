@@ -0,0 +1,68 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatClientReview(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+
+		var request struct {
+			Model    string        `json:"model"`
+			Messages []chatMessage `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "reviewer", request.Model)
+		require.Len(t, request.Messages, 2)
+		assert.Contains(t, request.Messages[1].Content, `"from":"customers.id","to":"orders.customer_id"`)
+
+		content := `{"annotations":[` +
+			`{"kind":"missing_index","table":"orders","field":"customer_id","message":"Not indexed","suggestion":"Index it."},` +
+			`{"kind":"naming","table":"invoices","message":"Not in the schema"}]}`
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"message": map[string]string{"role": "assistant", "content": content}}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewChatClient(server.URL+"/v1/", "key", "reviewer", time.Second)
+	annotations, err := client.Review(context.Background(), testSchema())
+
+	require.NoError(t, err)
+	assert.Equal(t, "reviewer", client.Name())
+	assert.Equal(t, []Annotation{
+		{Kind: KindMissingIndex, Table: "orders", Field: "customer_id", Message: "Not indexed", Suggestion: "Index it."},
+	}, annotations)
+}
+
+func TestChatClientReviewFailures(t *testing.T) {
+	for name, respond := range map[string]http.HandlerFunc{
+		"error status": func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+		},
+		"no choices": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"choices":[]}`))
+		},
+		"not json": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"choices":[{"message":{"content":"Looks good to me"}}]}`))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(respond)
+			defer server.Close()
+
+			_, err := NewChatClient(server.URL, "", "reviewer", time.Second).Review(context.Background(), testSchema())
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+// Package review annotates a schema with what a reviewer would point out:
+// normalization issues, foreign keys without an index and names breaking the
+// conventions of the rest of the schema. A Provider does the reviewing; Rules
+// reviews deterministically and ChatClient asks a language model.
+package review
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+)
+
+// Kinds of annotations
+const (
+	KindNormalization = "normalization" // Data repeated or stored where it does not belong
+	KindMissingIndex  = "missing_index" // A foreign key without an index
+	KindNaming        = "naming"        // A name unlike those of the rest of the schema
+)
+
+// Kinds lists every kind of annotation
+var Kinds = []string{KindNormalization, KindMissingIndex, KindNaming}
+
+// Annotation is a remark about a table, or about one of its fields when Field is set
+type Annotation struct {
+	Kind       string `json:"kind"`
+	Table      string `json:"table"`
+	Field      string `json:"field,omitempty"`
+	Message    string `json:"message"`              // What was found
+	Suggestion string `json:"suggestion,omitempty"` // What to change
+}
+
+// Provider reviews schemas
+type Provider interface {
+	// Name identifies the provider, and the model it uses if any, on the
+	// annotations it makes
+	Name() string
+	Review(ctx context.Context, schema schemadiff.Schema) ([]Annotation, error)
+}
+
+// known keeps the annotations of a known kind about tables, and fields, of the
+// schema, so each can be linked to what it is about
+func known(schema schemadiff.Schema, annotations []Annotation) []Annotation {
+	fields := make(map[string]map[string]bool, len(schema.Tables))
+	for _, table := range schema.Tables {
+		fields[table.Name] = make(map[string]bool, len(table.Fields))
+		for _, field := range table.Fields {
+			fields[table.Name][field.Name] = true
+		}
+	}
+
+	kept := []Annotation{}
+	for _, annotation := range annotations {
+		annotation.Message = strings.TrimSpace(annotation.Message)
+		annotation.Suggestion = strings.TrimSpace(annotation.Suggestion)
+		tableFields, ok := fields[annotation.Table]
+		if !ok || !slices.Contains(Kinds, annotation.Kind) || annotation.Message == "" {
+			continue
+		}
+		if annotation.Field != "" && !tableFields[annotation.Field] {
+			continue
+		}
+		kept = append(kept, annotation)
+	}
+	return kept
+}
@@ -0,0 +1,192 @@
+package review
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/Bug-Bugger/ezmodel/internal/lint"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+)
+
+// Cases names are written in
+const (
+	snakeCase = "snake_case"
+	camelCase = "camelCase"
+)
+
+// Rules reviews schemas without a model: normalization issues are the
+// findings of lint, foreign keys are expected to be indexed, and names to be
+// written in the case most names of their kind are written in
+type Rules struct{}
+
+// Name implements Provider
+func (Rules) Name() string {
+	return "rules"
+}
+
+// Review implements Provider, annotating normalization issues, then missing
+// indexes, then names, each in the order of the schema
+func (Rules) Review(ctx context.Context, schema schemadiff.Schema) ([]Annotation, error) {
+	annotations := []Annotation{}
+	for _, finding := range lint.Check(schema) {
+		annotation := Annotation{
+			Kind:       KindNormalization,
+			Table:      finding.Table,
+			Message:    finding.Message,
+			Suggestion: finding.Explanation,
+		}
+		// Linked to the first field concerned; the message names them all
+		if len(finding.Fields) > 0 {
+			annotation.Field = finding.Fields[0]
+		}
+		annotations = append(annotations, annotation)
+	}
+	annotations = append(annotations, missingIndexes(schema)...)
+	annotations = append(annotations, naming(schema)...)
+	return annotations, nil
+}
+
+// missingIndexes annotates the foreign keys PostgreSQL does not index: those
+// that are neither unique nor the first field of their table's primary key
+func missingIndexes(schema schemadiff.Schema) []Annotation {
+	firstKeys := make(map[string]string, len(schema.Tables))
+	for _, table := range schema.Tables {
+		for _, field := range table.Fields {
+			if field.IsPrimaryKey {
+				firstKeys[table.Name] = field.Name
+				break
+			}
+		}
+	}
+
+	var annotations []Annotation
+	for _, foreignKey := range schema.ForeignKeys() {
+		if foreignKey.Unique || firstKeys[foreignKey.Table] == foreignKey.Field {
+			continue
+		}
+		annotations = append(annotations, Annotation{
+			Kind:    KindMissingIndex,
+			Table:   foreignKey.Table,
+			Field:   foreignKey.Field,
+			Message: foreignKey.Field + " refers to " + foreignKey.RefTable + "." + foreignKey.RefField + " but is not indexed",
+			Suggestion: "Index " + foreignKey.Table + "." + foreignKey.Field + ", so joins on it and deletes from " +
+				foreignKey.RefTable + " do not scan all of " + foreignKey.Table + ".",
+		})
+	}
+	return annotations
+}
+
+// naming annotates the tables and fields written in another case than most
+// tables, or most fields, of the schema. Names of a single lowercase word fit
+// either case and are left out of the count.
+func naming(schema schemadiff.Schema) []Annotation {
+	tableNames := make([]string, len(schema.Tables))
+	var fieldNames []string
+	for i, table := range schema.Tables {
+		tableNames[i] = table.Name
+		for _, field := range table.Fields {
+			fieldNames = append(fieldNames, field.Name)
+		}
+	}
+	tableCase := usualCase(tableNames)
+	fieldCase := usualCase(fieldNames)
+
+	var annotations []Annotation
+	for _, table := range schema.Tables {
+		if tableCase != "" && caseOf(table.Name) != "" && caseOf(table.Name) != tableCase {
+			annotations = append(annotations, Annotation{
+				Kind:       KindNaming,
+				Table:      table.Name,
+				Message:    table.Name + " is not in " + tableCase + ", unlike most tables",
+				Suggestion: "Rename it " + inCase(table.Name, tableCase) + ".",
+			})
+		}
+		for _, field := range table.Fields {
+			if fieldCase != "" && caseOf(field.Name) != "" && caseOf(field.Name) != fieldCase {
+				annotations = append(annotations, Annotation{
+					Kind:       KindNaming,
+					Table:      table.Name,
+					Field:      field.Name,
+					Message:    field.Name + " is not in " + fieldCase + ", unlike most fields",
+					Suggestion: "Rename it " + inCase(field.Name, fieldCase) + ".",
+				})
+			}
+		}
+	}
+	return annotations
+}
+
+// usualCase returns the case most of names are in, or "" when there is no
+// majority
+func usualCase(names []string) string {
+	counts := make(map[string]int)
+	for _, name := range names {
+		counts[caseOf(name)]++
+	}
+	switch {
+	case counts[snakeCase] > counts[camelCase]:
+		return snakeCase
+	case counts[camelCase] > counts[snakeCase]:
+		return camelCase
+	}
+	return ""
+}
+
+// caseOf returns the case of a name: snake_case for lowercase words joined by
+// underscores, camelCase for words starting with a capital joined without,
+// "mixed" for both, and "" for a single lowercase word, which could be either
+func caseOf(name string) string {
+	underscore := strings.Contains(name, "_")
+	upper := strings.IndexFunc(name, unicode.IsUpper) >= 0
+	switch {
+	case underscore && upper:
+		return "mixed"
+	case underscore:
+		return snakeCase
+	case upper:
+		return camelCase
+	}
+	return ""
+}
+
+// inCase writes name in the case given
+func inCase(name, nameCase string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if nameCase == camelCase && i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		words[i] = word
+	}
+	if nameCase == camelCase {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words, "_")
+}
+
+// splitWords splits a name at underscores and before capitals starting a word,
+// e.g. userID_Created into user, ID and Created
+func splitWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i, r := range runes {
+		switch {
+		case r == '_':
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+		case i > start && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
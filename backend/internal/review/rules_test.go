@@ -0,0 +1,100 @@
+package review
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSchema() schemadiff.Schema {
+	return schemadiff.Schema{
+		Tables: []schemadiff.Table{
+			{Name: "customers", Fields: []schemadiff.Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "full_name", DataType: "TEXT"},
+				{Name: "createdAt", DataType: "TIMESTAMP"},
+			}},
+			{Name: "orders", Fields: []schemadiff.Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "customer_id", DataType: "UUID"},
+				{Name: "customer_name", DataType: "TEXT"},
+				{Name: "placed_at", DataType: "TIMESTAMP"},
+			}},
+			{Name: "order_items", Fields: []schemadiff.Field{
+				{Name: "order_id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "line", DataType: "INT", IsPrimaryKey: true},
+			}},
+			{Name: "shipping_addresses", Fields: []schemadiff.Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+			}},
+			{Name: "OrderNotes", Fields: []schemadiff.Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "order_id", DataType: "UUID"},
+			}},
+		},
+		Relationships: []schemadiff.Relationship{
+			{SourceTable: "customers", SourceField: "id", TargetTable: "orders", TargetField: "customer_id", RelationType: "one_to_many"},
+			{SourceTable: "orders", SourceField: "id", TargetTable: "order_items", TargetField: "order_id", RelationType: "one_to_many"},
+			{SourceTable: "orders", SourceField: "id", TargetTable: "OrderNotes", TargetField: "order_id", RelationType: "one_to_one"},
+		},
+	}
+}
+
+func TestRulesReview(t *testing.T) {
+	annotations, err := Rules{}.Review(context.Background(), testSchema())
+	require.NoError(t, err)
+
+	require.Len(t, annotations, 4)
+	assert.Equal(t, KindNormalization, annotations[0].Kind)
+	assert.Equal(t, "orders", annotations[0].Table)
+	assert.Equal(t, "customer_name", annotations[0].Field)
+	assert.NotEmpty(t, annotations[0].Suggestion)
+
+	// order_items.order_id leads the primary key and OrderNotes.order_id is unique
+	assert.Equal(t, Annotation{
+		Kind:       KindMissingIndex,
+		Table:      "orders",
+		Field:      "customer_id",
+		Message:    "customer_id refers to customers.id but is not indexed",
+		Suggestion: "Index orders.customer_id, so joins on it and deletes from customers do not scan all of orders.",
+	}, annotations[1])
+
+	assert.Equal(t, Annotation{
+		Kind:       KindNaming,
+		Table:      "customers",
+		Field:      "createdAt",
+		Message:    "createdAt is not in snake_case, unlike most fields",
+		Suggestion: "Rename it created_at.",
+	}, annotations[2])
+	assert.Equal(t, Annotation{
+		Kind:       KindNaming,
+		Table:      "OrderNotes",
+		Message:    "OrderNotes is not in snake_case, unlike most tables",
+		Suggestion: "Rename it order_notes.",
+	}, annotations[3])
+}
+
+func TestInCase(t *testing.T) {
+	assert.Equal(t, "user_id_created", inCase("userID_Created", snakeCase))
+	assert.Equal(t, "userIdCreated", inCase("user_id_created", camelCase))
+	assert.Equal(t, "html_page", inCase("HTMLPage", snakeCase))
+}
+
+func TestKnown(t *testing.T) {
+	annotations := known(testSchema(), []Annotation{
+		{Kind: KindNaming, Table: "customers", Field: "createdAt", Message: " Use snake_case "},
+		{Kind: KindNaming, Table: "customers", Message: "Plural"},
+		{Kind: "style", Table: "customers", Message: "Unknown kind"},
+		{Kind: KindNaming, Table: "invoices", Message: "Unknown table"},
+		{Kind: KindNaming, Table: "customers", Field: "email", Message: "Unknown field"},
+		{Kind: KindNaming, Table: "customers", Message: "  "},
+	})
+
+	assert.Equal(t, []Annotation{
+		{Kind: KindNaming, Table: "customers", Field: "createdAt", Message: "Use snake_case"},
+		{Kind: KindNaming, Table: "customers", Message: "Plural"},
+	}, annotations)
+}
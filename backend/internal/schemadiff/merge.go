@@ -0,0 +1,167 @@
+package schemadiff
+
+// Reasons two sides of a merge conflict
+const (
+	ConflictModified = "modified_on_both_sides" // Both sides changed it differently
+	ConflictRemoved  = "removed_and_modified"   // One side removed what the other changed
+	ConflictAdded    = "added_on_both_sides"    // Both sides added it differently
+	ConflictDangling = "links_removed_field"    // A relationship links a table or field the merge removes
+)
+
+// Conflict is a part of the schema a merge cannot decide on
+type Conflict struct {
+	Table        string        // Name of the table, or of the table of the field
+	Field        string        // Name of the field, or empty
+	Relationship *Relationship // The relationship, for conflicts about one
+	Reason       string
+}
+
+// Merge applies the changes theirs made since base to ours, e.g. those of a
+// branch to the schema it was branched from. Tables, fields and relationships
+// changed on one side only take that side's change; those changed alike on
+// both sides are merged as they are. Anything else is a conflict, and the
+// schema returned is only meaningful without conflicts. Like Compare, Merge
+// looks at structure only: the metadata of ours is kept.
+func Merge(base, ours, theirs Schema) (Schema, []Conflict) {
+	var merged Schema
+	var conflicts []Conflict
+
+	baseTables := tablesByName(base.Tables)
+	ourTables := tablesByName(ours.Tables)
+	theirTables := tablesByName(theirs.Tables)
+	for _, name := range mergeOrder(ours.Tables, theirs.Tables, tableName) {
+		baseTable, inBase := baseTables[name]
+		ourTable, inOurs := ourTables[name]
+		theirTable, inTheirs := theirTables[name]
+		switch {
+		case !inBase && inOurs && inTheirs:
+			table, tableConflicts := mergeTables(Table{Name: name}, ourTable, theirTable)
+			merged.Tables = append(merged.Tables, table)
+			conflicts = append(conflicts, tableConflicts...)
+		case !inBase && inOurs:
+			merged.Tables = append(merged.Tables, ourTable)
+		case !inBase:
+			merged.Tables = append(merged.Tables, theirTable)
+		case inOurs && inTheirs:
+			table, tableConflicts := mergeTables(baseTable, ourTable, theirTable)
+			merged.Tables = append(merged.Tables, table)
+			conflicts = append(conflicts, tableConflicts...)
+		case inOurs && compareTables(baseTable, ourTable) != nil:
+			conflicts = append(conflicts, Conflict{Table: name, Reason: ConflictRemoved})
+		case inTheirs && compareTables(baseTable, theirTable) != nil:
+			conflicts = append(conflicts, Conflict{Table: name, Reason: ConflictRemoved})
+		}
+	}
+
+	baseRelationships := relationshipsByKey(base.Relationships)
+	ourRelationships := relationshipsByKey(ours.Relationships)
+	theirRelationships := relationshipsByKey(theirs.Relationships)
+	for _, key := range mergeOrder(ours.Relationships, theirs.Relationships, relationshipKey) {
+		baseRelationship, inBase := baseRelationships[key]
+		ourRelationship, inOurs := ourRelationships[key]
+		theirRelationship, inTheirs := theirRelationships[key]
+		switch {
+		case inOurs && inTheirs:
+			switch {
+			case ourRelationship.RelationType == theirRelationship.RelationType,
+				inBase && theirRelationship.RelationType == baseRelationship.RelationType:
+				merged.Relationships = append(merged.Relationships, ourRelationship)
+			case inBase && ourRelationship.RelationType == baseRelationship.RelationType:
+				merged.Relationships = append(merged.Relationships, theirRelationship)
+			default:
+				reason := ConflictModified
+				if !inBase {
+					reason = ConflictAdded
+				}
+				conflicts = append(conflicts, relationshipConflict(theirRelationship, reason))
+			}
+		case !inBase && inOurs:
+			merged.Relationships = append(merged.Relationships, ourRelationship)
+		case !inBase:
+			merged.Relationships = append(merged.Relationships, theirRelationship)
+		case inOurs && ourRelationship.RelationType != baseRelationship.RelationType:
+			conflicts = append(conflicts, relationshipConflict(ourRelationship, ConflictRemoved))
+		case inTheirs && theirRelationship.RelationType != baseRelationship.RelationType:
+			conflicts = append(conflicts, relationshipConflict(theirRelationship, ConflictRemoved))
+		}
+	}
+
+	// A relationship kept by one side may link what the other removed
+	columns := make(map[[2]string]bool)
+	for _, table := range merged.Tables {
+		for _, field := range table.Fields {
+			columns[[2]string{table.Name, field.Name}] = true
+		}
+	}
+	for _, relationship := range merged.Relationships {
+		if !columns[[2]string{relationship.SourceTable, relationship.SourceField}] ||
+			!columns[[2]string{relationship.TargetTable, relationship.TargetField}] {
+			conflicts = append(conflicts, relationshipConflict(relationship, ConflictDangling))
+		}
+	}
+	return merged, conflicts
+}
+
+// mergeTables merges the fields of a table both sides kept, or added alike
+// when base has no fields
+func mergeTables(base, ours, theirs Table) (Table, []Conflict) {
+	merged := Table{Name: ours.Name, Metadata: ours.Metadata}
+	var conflicts []Conflict
+
+	baseFields := fieldsByName(base.Fields)
+	ourFields := fieldsByName(ours.Fields)
+	theirFields := fieldsByName(theirs.Fields)
+	for _, name := range mergeOrder(ours.Fields, theirs.Fields, fieldName) {
+		baseField, inBase := baseFields[name]
+		ourField, inOurs := ourFields[name]
+		theirField, inTheirs := theirFields[name]
+		switch {
+		case inOurs && inTheirs:
+			switch {
+			case len(compareFields(ourField, theirField)) == 0,
+				inBase && len(compareFields(baseField, theirField)) == 0:
+				merged.Fields = append(merged.Fields, ourField)
+			case inBase && len(compareFields(baseField, ourField)) == 0:
+				merged.Fields = append(merged.Fields, theirField)
+			default:
+				reason := ConflictModified
+				if !inBase {
+					reason = ConflictAdded
+				}
+				conflicts = append(conflicts, Conflict{Table: ours.Name, Field: name, Reason: reason})
+			}
+		case !inBase && inOurs:
+			merged.Fields = append(merged.Fields, ourField)
+		case !inBase:
+			merged.Fields = append(merged.Fields, theirField)
+		case inOurs && len(compareFields(baseField, ourField)) > 0:
+			conflicts = append(conflicts, Conflict{Table: ours.Name, Field: name, Reason: ConflictRemoved})
+		case inTheirs && len(compareFields(baseField, theirField)) > 0:
+			conflicts = append(conflicts, Conflict{Table: ours.Name, Field: name, Reason: ConflictRemoved})
+		}
+	}
+	return merged, conflicts
+}
+
+// mergeOrder lists the keys of ours in order, then those only theirs has
+func mergeOrder[T any](ours, theirs []T, key func(T) string) []string {
+	seen := make(map[string]bool, len(ours)+len(theirs))
+	var keys []string
+	for _, items := range [][]T{ours, theirs} {
+		for _, item := range items {
+			if k := key(item); !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+func tableName(table Table) string { return table.Name }
+
+func fieldName(field Field) string { return field.Name }
+
+func relationshipConflict(relationship Relationship, reason string) Conflict {
+	return Conflict{Table: relationship.TargetTable, Field: relationship.TargetField, Relationship: &relationship, Reason: reason}
+}
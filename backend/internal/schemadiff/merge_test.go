@@ -0,0 +1,156 @@
+package schemadiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	base := Schema{
+		Tables: []Table{
+			{Name: "users", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "email", DataType: "VARCHAR(255)"},
+				{Name: "nickname", DataType: "TEXT", IsNullable: true},
+			}},
+			{Name: "posts", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "author_id", DataType: "UUID"},
+			}},
+			{Name: "sessions", Fields: []Field{{Name: "id", DataType: "UUID", IsPrimaryKey: true}}},
+		},
+		Relationships: []Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_many"},
+		},
+	}
+	ours := Schema{
+		Tables: []Table{
+			{Name: "users", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "email", DataType: "TEXT"}, // Changed alike on both sides
+				{Name: "nickname", DataType: "TEXT", IsNullable: true},
+				{Name: "created_at", DataType: "TIMESTAMP"},
+			}, Metadata: map[string]any{"owner": "identity"}},
+			{Name: "posts", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "author_id", DataType: "UUID"},
+			}},
+			{Name: "sessions", Fields: []Field{{Name: "id", DataType: "UUID", IsPrimaryKey: true}}},
+		},
+		Relationships: base.Relationships,
+	}
+	theirs := Schema{
+		Tables: []Table{
+			{Name: "comments", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "post_id", DataType: "UUID"},
+			}},
+			{Name: "users", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "email", DataType: "text"},
+				{Name: "nickname", DataType: "TEXT"},
+			}},
+			{Name: "posts", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "author_id", DataType: "UUID"},
+			}},
+		},
+		Relationships: []Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_one"},
+			{SourceTable: "posts", SourceField: "id", TargetTable: "comments", TargetField: "post_id", RelationType: "one_to_many"},
+		},
+	}
+
+	merged, conflicts := Merge(base, ours, theirs)
+
+	assert.Empty(t, conflicts)
+	assert.Equal(t, Schema{
+		Tables: []Table{
+			{Name: "users", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "email", DataType: "TEXT"},
+				{Name: "nickname", DataType: "TEXT"},
+				{Name: "created_at", DataType: "TIMESTAMP"},
+			}, Metadata: map[string]any{"owner": "identity"}},
+			{Name: "posts", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "author_id", DataType: "UUID"},
+			}},
+			{Name: "comments", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "post_id", DataType: "UUID"},
+			}},
+		},
+		Relationships: []Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_one"},
+			{SourceTable: "posts", SourceField: "id", TargetTable: "comments", TargetField: "post_id", RelationType: "one_to_many"},
+		},
+	}, merged)
+}
+
+func TestMerge_Conflicts(t *testing.T) {
+	base := Schema{
+		Tables: []Table{
+			{Name: "users", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "email", DataType: "VARCHAR(255)"},
+				{Name: "nickname", DataType: "TEXT"},
+			}},
+			{Name: "posts", Fields: []Field{{Name: "author_id", DataType: "UUID"}}},
+		},
+		Relationships: []Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_many"},
+		},
+	}
+	ours := Schema{
+		Tables: []Table{
+			{Name: "users", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "email", DataType: "TEXT"},
+				{Name: "nickname", DataType: "TEXT", IsNullable: true},
+				{Name: "age", DataType: "INTEGER"},
+			}},
+		},
+	}
+	theirs := Schema{
+		Tables: []Table{
+			{Name: "users", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "email", DataType: "CITEXT"},
+				{Name: "age", DataType: "SMALLINT"},
+			}},
+			{Name: "posts", Fields: []Field{{Name: "author_id", DataType: "BIGINT"}}},
+		},
+		Relationships: base.Relationships,
+	}
+
+	_, conflicts := Merge(base, ours, theirs)
+
+	assert.Equal(t, []Conflict{
+		{Table: "users", Field: "email", Reason: ConflictModified},
+		{Table: "users", Field: "nickname", Reason: ConflictRemoved},
+		{Table: "users", Field: "age", Reason: ConflictAdded},
+		{Table: "posts", Reason: ConflictRemoved},
+	}, conflicts)
+}
+
+func TestMerge_DanglingRelationship(t *testing.T) {
+	base := Schema{
+		Tables: []Table{
+			{Name: "users", Fields: []Field{{Name: "id", DataType: "UUID"}}},
+			{Name: "posts", Fields: []Field{{Name: "author_id", DataType: "UUID"}}},
+		},
+	}
+	ours := Schema{
+		Tables: base.Tables,
+		Relationships: []Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_many"},
+		},
+	}
+	theirs := Schema{Tables: base.Tables[:1]}
+
+	_, conflicts := Merge(base, ours, theirs)
+
+	assert.Equal(t, []Conflict{{Table: "posts", Field: "author_id", Relationship: &ours.Relationships[0], Reason: ConflictDangling}}, conflicts)
+}
@@ -0,0 +1,356 @@
+// Package schemadiff compares and merges schemas structurally. Tables are matched by
+// name, fields by name within their table and relationships by the table and
+// field names they link; layout, such as positions and colors, is ignored.
+package schemadiff
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Schema is the structure of a project
+type Schema struct {
+	Tables        []Table
+	Relationships []Relationship
+}
+
+type Table struct {
+	Name     string
+	Fields   []Field
+	Metadata map[string]any // Of the organization, carried to exports; ignored when comparing
+}
+
+type Field struct {
+	Name         string
+	DataType     string
+	IsPrimaryKey bool
+	IsNullable   bool
+	DefaultValue string
+	Metadata     map[string]any // Of the organization, carried to exports; ignored when comparing
+}
+
+type Relationship struct {
+	SourceTable  string
+	SourceField  string
+	TargetTable  string
+	TargetField  string
+	RelationType string
+	Constraint   string // Name of the foreign key in a live database; ignored when comparing
+}
+
+// ForeignKey is a column referring to a column of another table, or of its own
+type ForeignKey struct {
+	Table    string
+	Field    string
+	RefTable string
+	RefField string
+	Unique   bool // One-to-one, so no two rows refer to the same row
+}
+
+// ForeignKeys lists the foreign keys the relationships of the schema stand
+// for. Relationships link a referenced column as their source to a foreign
+// key column as their target; many-to-many ones, and those linking a column
+// the schema lacks, have none. A column keeps its first relationship.
+func (s Schema) ForeignKeys() []ForeignKey {
+	columns := make(map[[2]string]bool)
+	for _, table := range s.Tables {
+		for _, field := range table.Fields {
+			columns[[2]string{table.Name, field.Name}] = true
+		}
+	}
+
+	var foreignKeys []ForeignKey
+	seen := make(map[[2]string]bool)
+	for _, relationship := range s.Relationships {
+		if relationship.RelationType == "many_to_many" {
+			continue
+		}
+		source := [2]string{relationship.SourceTable, relationship.SourceField}
+		target := [2]string{relationship.TargetTable, relationship.TargetField}
+		if !columns[source] || !columns[target] || seen[target] {
+			continue
+		}
+		seen[target] = true
+		foreignKeys = append(foreignKeys, ForeignKey{
+			Table:    relationship.TargetTable,
+			Field:    relationship.TargetField,
+			RefTable: relationship.SourceTable,
+			RefField: relationship.SourceField,
+			Unique:   relationship.RelationType == "one_to_one",
+		})
+	}
+	return foreignKeys
+}
+
+// Rename is a table renamed, or a field of one when Field is set
+type Rename struct {
+	Table string // Name of the table before, or of the table of the field
+	Field string // Name of the field before, or empty
+	To    string
+}
+
+// Rename returns a copy of the schema with the renames made in order, its
+// relationships following the tables and fields they link, and the renames
+// made. A rename is skipped unless what it renames exists under its old name
+// and nothing under its new one, e.g. in a database created after it.
+func (s Schema) Rename(renames []Rename) (Schema, []Rename) {
+	renamed := Schema{Tables: make([]Table, len(s.Tables)), Relationships: append([]Relationship{}, s.Relationships...)}
+	for i, table := range s.Tables {
+		renamed.Tables[i] = Table{Name: table.Name, Fields: append([]Field{}, table.Fields...)}
+	}
+	find := func(name string) *Table {
+		for i := range renamed.Tables {
+			if renamed.Tables[i].Name == name {
+				return &renamed.Tables[i]
+			}
+		}
+		return nil
+	}
+
+	var made []Rename
+	for _, rename := range renames {
+		table := find(rename.Table)
+		if table == nil || rename.To == "" {
+			continue
+		}
+		if rename.Field == "" {
+			if find(rename.To) != nil {
+				continue
+			}
+			table.Name = rename.To
+			for i := range renamed.Relationships {
+				relationship := &renamed.Relationships[i]
+				if relationship.SourceTable == rename.Table {
+					relationship.SourceTable = rename.To
+				}
+				if relationship.TargetTable == rename.Table {
+					relationship.TargetTable = rename.To
+				}
+			}
+			made = append(made, rename)
+			continue
+		}
+
+		from, taken := -1, false
+		for i, field := range table.Fields {
+			switch field.Name {
+			case rename.Field:
+				from = i
+			case rename.To:
+				taken = true
+			}
+		}
+		if from < 0 || taken {
+			continue
+		}
+		table.Fields[from].Name = rename.To
+		for i := range renamed.Relationships {
+			relationship := &renamed.Relationships[i]
+			if relationship.SourceTable == rename.Table && relationship.SourceField == rename.Field {
+				relationship.SourceField = rename.To
+			}
+			if relationship.TargetTable == rename.Table && relationship.TargetField == rename.Field {
+				relationship.TargetField = rename.To
+			}
+		}
+		made = append(made, rename)
+	}
+	return renamed, made
+}
+
+// Attributes that can change between two fields or relationships of the same name
+const (
+	AttributeDataType     = "data_type"
+	AttributePrimaryKey   = "is_primary_key"
+	AttributeNullable     = "is_nullable"
+	AttributeDefaultValue = "default_value"
+	AttributeRelationType = "relation_type"
+)
+
+// Change is an attribute whose value differs, as text
+type Change struct {
+	Attribute string
+	From      string
+	To        string
+}
+
+// FieldDiff is a field of both schemas that differs
+type FieldDiff struct {
+	Name    string
+	Changes []Change
+}
+
+// TableDiff is a table of both schemas whose fields differ
+type TableDiff struct {
+	Name          string
+	AddedFields   []Field
+	RemovedFields []Field
+	ChangedFields []FieldDiff
+}
+
+// RelationshipDiff is a relationship of both schemas that differs
+type RelationshipDiff struct {
+	Relationship Relationship // As it is in the schema compared to
+	Changes      []Change
+}
+
+// Diff is what changes from one schema to another. Everything is sorted by
+// name, so equal schemas always give the same diff.
+type Diff struct {
+	AddedTables          []Table
+	RemovedTables        []Table
+	ChangedTables        []TableDiff
+	AddedRelationships   []Relationship
+	RemovedRelationships []Relationship
+	ChangedRelationships []RelationshipDiff
+}
+
+// Empty reports whether the schemas compared have the same structure
+func (d *Diff) Empty() bool {
+	return len(d.AddedTables) == 0 && len(d.RemovedTables) == 0 && len(d.ChangedTables) == 0 &&
+		len(d.AddedRelationships) == 0 && len(d.RemovedRelationships) == 0 && len(d.ChangedRelationships) == 0
+}
+
+// Compare returns what changes from the schema from to the schema to
+func Compare(from, to Schema) *Diff {
+	diff := &Diff{}
+
+	fromTables := tablesByName(from.Tables)
+	toTables := tablesByName(to.Tables)
+	for _, name := range sortedKeys(toTables) {
+		toTable := toTables[name]
+		fromTable, ok := fromTables[name]
+		if !ok {
+			diff.AddedTables = append(diff.AddedTables, sortedTable(toTable))
+			continue
+		}
+		if tableDiff := compareTables(fromTable, toTable); tableDiff != nil {
+			diff.ChangedTables = append(diff.ChangedTables, *tableDiff)
+		}
+	}
+	for _, name := range sortedKeys(fromTables) {
+		if _, ok := toTables[name]; !ok {
+			diff.RemovedTables = append(diff.RemovedTables, sortedTable(fromTables[name]))
+		}
+	}
+
+	fromRelationships := relationshipsByKey(from.Relationships)
+	toRelationships := relationshipsByKey(to.Relationships)
+	for _, key := range sortedKeys(toRelationships) {
+		toRelationship := toRelationships[key]
+		fromRelationship, ok := fromRelationships[key]
+		if !ok {
+			diff.AddedRelationships = append(diff.AddedRelationships, toRelationship)
+			continue
+		}
+		if fromRelationship.RelationType != toRelationship.RelationType {
+			diff.ChangedRelationships = append(diff.ChangedRelationships, RelationshipDiff{
+				Relationship: fromRelationship,
+				Changes:      []Change{{Attribute: AttributeRelationType, From: fromRelationship.RelationType, To: toRelationship.RelationType}},
+			})
+		}
+	}
+	for _, key := range sortedKeys(fromRelationships) {
+		if _, ok := toRelationships[key]; !ok {
+			diff.RemovedRelationships = append(diff.RemovedRelationships, fromRelationships[key])
+		}
+	}
+	return diff
+}
+
+// compareTables returns how the fields of two tables of the same name differ, or nil when they do not
+func compareTables(from, to Table) *TableDiff {
+	diff := &TableDiff{Name: to.Name}
+	fromFields := fieldsByName(from.Fields)
+	toFields := fieldsByName(to.Fields)
+	for _, name := range sortedKeys(toFields) {
+		toField := toFields[name]
+		fromField, ok := fromFields[name]
+		if !ok {
+			diff.AddedFields = append(diff.AddedFields, toField)
+			continue
+		}
+		if changes := compareFields(fromField, toField); len(changes) > 0 {
+			diff.ChangedFields = append(diff.ChangedFields, FieldDiff{Name: name, Changes: changes})
+		}
+	}
+	for _, name := range sortedKeys(fromFields) {
+		if _, ok := toFields[name]; !ok {
+			diff.RemovedFields = append(diff.RemovedFields, fromFields[name])
+		}
+	}
+
+	if len(diff.AddedFields) == 0 && len(diff.RemovedFields) == 0 && len(diff.ChangedFields) == 0 {
+		return nil
+	}
+	return diff
+}
+
+// compareFields lists the attributes that differ between two fields. Data
+// types differing only in case are the same.
+func compareFields(from, to Field) []Change {
+	var changes []Change
+	if !strings.EqualFold(from.DataType, to.DataType) {
+		changes = append(changes, Change{Attribute: AttributeDataType, From: from.DataType, To: to.DataType})
+	}
+	if from.IsPrimaryKey != to.IsPrimaryKey {
+		changes = append(changes, Change{Attribute: AttributePrimaryKey, From: strconv.FormatBool(from.IsPrimaryKey), To: strconv.FormatBool(to.IsPrimaryKey)})
+	}
+	if from.IsNullable != to.IsNullable {
+		changes = append(changes, Change{Attribute: AttributeNullable, From: strconv.FormatBool(from.IsNullable), To: strconv.FormatBool(to.IsNullable)})
+	}
+	if from.DefaultValue != to.DefaultValue {
+		changes = append(changes, Change{Attribute: AttributeDefaultValue, From: from.DefaultValue, To: to.DefaultValue})
+	}
+	return changes
+}
+
+func tablesByName(tables []Table) map[string]Table {
+	byName := make(map[string]Table, len(tables))
+	for _, table := range tables {
+		byName[table.Name] = table
+	}
+	return byName
+}
+
+func fieldsByName(fields []Field) map[string]Field {
+	byName := make(map[string]Field, len(fields))
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+	return byName
+}
+
+// relationshipsByKey keys relationships by the fields they link, so one
+// between the same fields of both schemas is matched whatever its type
+func relationshipsByKey(relationships []Relationship) map[string]Relationship {
+	byKey := make(map[string]Relationship, len(relationships))
+	for _, relationship := range relationships {
+		byKey[relationshipKey(relationship)] = relationship
+	}
+	return byKey
+}
+
+func relationshipKey(relationship Relationship) string {
+	return strings.Join([]string{relationship.SourceTable, relationship.SourceField, relationship.TargetTable, relationship.TargetField}, "\x00")
+}
+
+// sortedTable copies a table with its fields sorted by name
+func sortedTable(table Table) Table {
+	fields := fieldsByName(table.Fields)
+	sorted := Table{Name: table.Name, Fields: make([]Field, 0, len(fields))}
+	for _, name := range sortedKeys(fields) {
+		sorted.Fields = append(sorted.Fields, fields[name])
+	}
+	return sorted
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,148 @@
+package schemadiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	from := Schema{
+		Tables: []Table{
+			{Name: "users", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "email", DataType: "VARCHAR(255)"},
+				{Name: "nickname", DataType: "TEXT", IsNullable: true},
+			}},
+			{Name: "sessions", Fields: []Field{{Name: "id", DataType: "UUID", IsPrimaryKey: true}}},
+			{Name: "posts", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "author_id", DataType: "UUID"},
+			}},
+		},
+		Relationships: []Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_many"},
+			{SourceTable: "users", SourceField: "id", TargetTable: "sessions", TargetField: "id", RelationType: "one_to_one"},
+		},
+	}
+	to := Schema{
+		Tables: []Table{
+			{Name: "users", Fields: []Field{
+				{Name: "email", DataType: "TEXT", IsNullable: true},
+				{Name: "id", DataType: "uuid", IsPrimaryKey: true},
+				{Name: "created_at", DataType: "TIMESTAMP", DefaultValue: "now()"},
+			}},
+			{Name: "posts", Fields: []Field{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "author_id", DataType: "UUID"},
+			}},
+			{Name: "comments", Fields: []Field{
+				{Name: "post_id", DataType: "UUID"},
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+			}},
+		},
+		Relationships: []Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_one"},
+			{SourceTable: "posts", SourceField: "id", TargetTable: "comments", TargetField: "post_id", RelationType: "one_to_many"},
+		},
+	}
+
+	diff := Compare(from, to)
+
+	assert.Equal(t, &Diff{
+		AddedTables: []Table{{Name: "comments", Fields: []Field{
+			{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+			{Name: "post_id", DataType: "UUID"},
+		}}},
+		RemovedTables: []Table{{Name: "sessions", Fields: []Field{{Name: "id", DataType: "UUID", IsPrimaryKey: true}}}},
+		ChangedTables: []TableDiff{{
+			Name:          "users",
+			AddedFields:   []Field{{Name: "created_at", DataType: "TIMESTAMP", DefaultValue: "now()"}},
+			RemovedFields: []Field{{Name: "nickname", DataType: "TEXT", IsNullable: true}},
+			ChangedFields: []FieldDiff{{Name: "email", Changes: []Change{
+				{Attribute: AttributeDataType, From: "VARCHAR(255)", To: "TEXT"},
+				{Attribute: AttributeNullable, From: "false", To: "true"},
+			}}},
+		}},
+		AddedRelationships: []Relationship{
+			{SourceTable: "posts", SourceField: "id", TargetTable: "comments", TargetField: "post_id", RelationType: "one_to_many"},
+		},
+		RemovedRelationships: []Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "sessions", TargetField: "id", RelationType: "one_to_one"},
+		},
+		ChangedRelationships: []RelationshipDiff{{
+			Relationship: Relationship{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_many"},
+			Changes:      []Change{{Attribute: AttributeRelationType, From: "one_to_many", To: "one_to_one"}},
+		}},
+	}, diff)
+	assert.False(t, diff.Empty())
+}
+
+func TestCompare_Identical(t *testing.T) {
+	schema := Schema{
+		Tables: []Table{{Name: "users", Fields: []Field{{Name: "id", DataType: "UUID", IsPrimaryKey: true}}}},
+		Relationships: []Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "users", TargetField: "id", RelationType: "one_to_one"},
+		},
+	}
+
+	diff := Compare(schema, schema)
+
+	assert.True(t, diff.Empty())
+	assert.Equal(t, &Diff{}, diff)
+}
+
+func TestForeignKeys(t *testing.T) {
+	schema := Schema{
+		Tables: []Table{
+			{Name: "users", Fields: []Field{{Name: "id", DataType: "UUID", IsPrimaryKey: true}}},
+			{Name: "profiles", Fields: []Field{{Name: "user_id", DataType: "UUID"}}},
+			{Name: "posts", Fields: []Field{{Name: "author_id", DataType: "UUID"}}},
+		},
+		Relationships: []Relationship{
+			{SourceTable: "users", SourceField: "id", TargetTable: "profiles", TargetField: "user_id", RelationType: "one_to_one"},
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_many"},
+			{SourceTable: "profiles", SourceField: "user_id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_many"}, // Second for the column
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "editor_id", RelationType: "one_to_many"},         // Missing column
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "many_to_many"},
+		},
+	}
+
+	assert.Equal(t, []ForeignKey{
+		{Table: "profiles", Field: "user_id", RefTable: "users", RefField: "id", Unique: true},
+		{Table: "posts", Field: "author_id", RefTable: "users", RefField: "id"},
+	}, schema.ForeignKeys())
+}
+
+func TestRename(t *testing.T) {
+	schema := Schema{
+		Tables: []Table{
+			{Name: "users", Fields: []Field{{Name: "id"}, {Name: "name"}, {Name: "full_name"}}},
+			{Name: "posts", Fields: []Field{{Name: "user_id"}}},
+		},
+		Relationships: []Relationship{{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "user_id"}},
+	}
+
+	renamed, made := schema.Rename([]Rename{
+		{Table: "users", To: "people"},
+		{Table: "people", Field: "id", To: "person_id"},
+		{Table: "people", Field: "name", To: "full_name"}, // Taken
+		{Table: "posts", To: "people"},                    // Taken
+		{Table: "posts", Field: "user_id", To: "author_id"},
+	})
+
+	assert.Equal(t, []Rename{
+		{Table: "users", To: "people"},
+		{Table: "people", Field: "id", To: "person_id"},
+		{Table: "posts", Field: "user_id", To: "author_id"},
+	}, made)
+	assert.Equal(t, Schema{
+		Tables: []Table{
+			{Name: "people", Fields: []Field{{Name: "person_id"}, {Name: "name"}, {Name: "full_name"}}},
+			{Name: "posts", Fields: []Field{{Name: "author_id"}}},
+		},
+		Relationships: []Relationship{{SourceTable: "people", SourceField: "person_id", TargetTable: "posts", TargetField: "author_id"}},
+	}, renamed)
+	assert.Equal(t, "users", schema.Tables[0].Name)
+	assert.Equal(t, "id", schema.Tables[0].Fields[0].Name)
+}
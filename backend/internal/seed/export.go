@@ -0,0 +1,60 @@
+package seed
+
+import (
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+)
+
+// ExportProject reads a project with its schema and collaborators as a
+// fixture, to be imported in another database
+func (s *Seeder) ExportProject(projectID uuid.UUID) (*Fixture, error) {
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return ProjectFixture(project), nil
+}
+
+// ProjectFixture describes a project, loaded with its owner, collaborators
+// and schema, as a fixture. Its users are listed without their passwords, so
+// they must exist wherever the fixture is imported.
+func ProjectFixture(project *models.Project) *Fixture {
+	fixtureProject := FixtureProject{
+		Name:         project.Name,
+		Description:  project.Description,
+		Owner:        project.Owner.Username,
+		DatabaseType: project.DatabaseType,
+		CanvasData:   project.CanvasData,
+	}
+	users := []FixtureUser{{Username: project.Owner.Username, Email: project.Owner.Email}}
+	for _, collaborator := range project.Collaborators {
+		fixtureProject.Collaborators = append(fixtureProject.Collaborators, collaborator.Username)
+		users = append(users, FixtureUser{Username: collaborator.Username, Email: collaborator.Email})
+	}
+
+	// Relationships refer to fields as table.field
+	references := make(map[uuid.UUID]string)
+	for _, table := range project.Tables {
+		fixtureTable := FixtureTable{Name: table.Name, PosX: table.PosX, PosY: table.PosY}
+		for _, field := range table.Fields {
+			fixtureTable.Fields = append(fixtureTable.Fields, FixtureField{
+				Name:         field.Name,
+				DataType:     field.DataType,
+				PrimaryKey:   field.IsPrimaryKey,
+				Nullable:     field.IsNullable,
+				DefaultValue: field.DefaultValue,
+			})
+			references[field.ID] = table.Name + "." + field.Name
+		}
+		fixtureProject.Tables = append(fixtureProject.Tables, fixtureTable)
+	}
+	for _, relationship := range project.Relationships {
+		fixtureProject.Relationships = append(fixtureProject.Relationships, FixtureRelationship{
+			Source: references[relationship.SourceFieldID],
+			Target: references[relationship.TargetFieldID],
+			Type:   relationship.RelationType,
+		})
+	}
+
+	return &Fixture{Users: users, Projects: []FixtureProject{fixtureProject}}
+}
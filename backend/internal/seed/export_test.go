@@ -0,0 +1,38 @@
+package seed
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestProjectFixtureRoundTrip(t *testing.T) {
+	userID, orderID := uuid.New(), uuid.New()
+	project := &models.Project{
+		Name:          "Shop",
+		Owner:         models.User{Username: "ada", Email: "ada@example.com"},
+		Collaborators: []models.User{{Username: "alan", Email: "alan@example.com"}},
+		DatabaseType:  "postgresql",
+		Tables: []models.Table{
+			{Name: "users", PosX: 10, Fields: []models.Field{{ID: userID, Name: "id", DataType: "UUID", IsPrimaryKey: true}}},
+			{Name: "orders", PosY: 20, Fields: []models.Field{{ID: orderID, Name: "user_id", DataType: "UUID", IsNullable: true}}},
+		},
+		Relationships: []models.Relationship{{SourceFieldID: userID, TargetFieldID: orderID, RelationType: "one_to_many"}},
+	}
+
+	data, err := yaml.Marshal(ProjectFixture(project))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "password")
+
+	fixture, err := ParseFixture(data) // Valid without passwords
+	require.NoError(t, err)
+	assert.Equal(t, []FixtureUser{{Username: "ada", Email: "ada@example.com"}, {Username: "alan", Email: "alan@example.com"}}, fixture.Users)
+	require.Len(t, fixture.Projects, 1)
+	assert.Equal(t, []string{"alan"}, fixture.Projects[0].Collaborators)
+	assert.Equal(t, FixtureTable{Name: "users", PosX: 10, Fields: []FixtureField{{Name: "id", DataType: "UUID", PrimaryKey: true}}}, fixture.Projects[0].Tables[0])
+	assert.Equal(t, []FixtureRelationship{{Source: "users.id", Target: "orders.user_id", Type: "one_to_many"}}, fixture.Projects[0].Relationships)
+}
@@ -0,0 +1,151 @@
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/brianvoe/gofakeit/v7"
+)
+
+// FakePassword is the password of every generated user
+const FakePassword = "123321"
+
+// Generated tables are laid out on a grid of fakeGridColumns columns, each
+// cell fakeCellWidth by fakeCellHeight
+const (
+	fakeGridColumns = 4
+	fakeCellWidth   = 320
+	fakeCellHeight  = 400
+)
+
+// maxCollaborators bounds the collaborators of a generated project
+const maxCollaborators = 3
+
+// Volume is how much data faker mode generates
+type Volume struct {
+	Users            int
+	Projects         int
+	TablesPerProject int
+}
+
+// fakeColumns are the columns generated tables pick from besides their key
+// and foreign keys
+var fakeColumns = []FixtureField{
+	{Name: "name", DataType: "VARCHAR(255)"},
+	{Name: "title", DataType: "VARCHAR(255)"},
+	{Name: "description", DataType: "TEXT", Nullable: true},
+	{Name: "email", DataType: "VARCHAR(255)"},
+	{Name: "status", DataType: "VARCHAR(20)", DefaultValue: "'active'"},
+	{Name: "price", DataType: "DECIMAL(10,2)"},
+	{Name: "quantity", DataType: "INTEGER", DefaultValue: "0"},
+	{Name: "is_active", DataType: "BOOLEAN", DefaultValue: "true"},
+	{Name: "published_at", DataType: "TIMESTAMP", Nullable: true},
+	{Name: "metadata", DataType: "JSONB", Nullable: true},
+	{Name: "created_at", DataType: "TIMESTAMP", DefaultValue: "CURRENT_TIMESTAMP"},
+	{Name: "updated_at", DataType: "TIMESTAMP", DefaultValue: "CURRENT_TIMESTAMP"},
+}
+
+var fakeDatabaseTypes = []string{"postgresql", "postgresql", "mysql", "sqlite", "sqlserver"}
+
+// FakeFixture generates a random but coherent dataset of volume, the same
+// for the same seed, or a random one when seed is 0. Projects belong to
+// random users and are shared with a few others. Each table has an id key
+// and may refer to tables generated before it in its project, so the
+// relationships never form a cycle.
+func FakeFixture(volume Volume, seed uint64) (*Fixture, error) {
+	if volume.Users < 0 || volume.Projects < 0 || volume.TablesPerProject < 0 {
+		return nil, errors.New("volumes cannot be negative")
+	}
+	if volume.Projects > 0 && volume.Users == 0 {
+		return nil, errors.New("projects need users to own them")
+	}
+
+	faker := gofakeit.New(seed)
+	fixture := &Fixture{
+		Users:    make([]FixtureUser, volume.Users),
+		Projects: make([]FixtureProject, volume.Projects),
+	}
+	for i := range fixture.Users {
+		// The index keeps usernames and emails unique at any volume
+		username := fmt.Sprintf("%s.%s%d", strings.ToLower(faker.FirstName()), strings.ToLower(faker.LastName()), i+1)
+		fixture.Users[i] = FixtureUser{
+			Username: username,
+			Email:    username + "@example.com",
+			Password: FakePassword,
+		}
+	}
+
+	for i := range fixture.Projects {
+		owner := faker.IntRange(0, volume.Users-1)
+		project := FixtureProject{
+			Name:         fmt.Sprintf("%s %s %d", faker.Company(), faker.AppName(), i+1),
+			Description:  faker.Sentence(),
+			Owner:        fixture.Users[owner].Username,
+			DatabaseType: faker.RandomString(fakeDatabaseTypes),
+			CanvasData:   `{"zoom": 1, "position": {"x": 0, "y": 0}}`,
+		}
+
+		collaborators := faker.IntRange(0, min(maxCollaborators, volume.Users-1))
+		for _, j := range perm(faker, volume.Users) {
+			if len(project.Collaborators) == collaborators {
+				break
+			}
+			if j != owner {
+				project.Collaborators = append(project.Collaborators, fixture.Users[j].Username)
+			}
+		}
+
+		fakeSchema(faker, &project, volume.TablesPerProject)
+		fixture.Projects[i] = project
+	}
+	return fixture, nil
+}
+
+// fakeSchema adds count tables to project, named after random nouns
+func fakeSchema(faker *gofakeit.Faker, project *FixtureProject, count int) {
+	taken := make(map[string]bool, count)
+	for i := range count {
+		name := strings.ToLower(strings.ReplaceAll(faker.Noun(), " ", "_")) + "s"
+		if taken[name] {
+			name = fmt.Sprintf("%s_%d", name, i+1)
+		}
+		taken[name] = true
+
+		table := FixtureTable{
+			Name:   name,
+			PosX:   float64(i%fakeGridColumns) * fakeCellWidth,
+			PosY:   float64(i/fakeGridColumns) * fakeCellHeight,
+			Fields: []FixtureField{{Name: "id", DataType: "SERIAL", PrimaryKey: true}},
+		}
+
+		// Up to two references to earlier tables, one-to-many
+		if i > 0 {
+			for _, j := range perm(faker, i)[:faker.IntRange(0, min(2, i))] {
+				referenced := project.Tables[j].Name
+				column := strings.TrimSuffix(referenced, "s") + "_id"
+				table.Fields = append(table.Fields, FixtureField{Name: column, DataType: "INTEGER", Nullable: faker.Bool()})
+				project.Relationships = append(project.Relationships, FixtureRelationship{
+					Source: referenced + ".id",
+					Target: name + "." + column,
+					Type:   "one_to_many",
+				})
+			}
+		}
+
+		for _, j := range perm(faker, len(fakeColumns))[:faker.IntRange(2, 6)] {
+			table.Fields = append(table.Fields, fakeColumns[j])
+		}
+		project.Tables = append(project.Tables, table)
+	}
+}
+
+// perm returns 0 to n-1 in a random order
+func perm(faker *gofakeit.Faker, n int) []int {
+	indexes := make([]int, n)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	faker.ShuffleInts(indexes)
+	return indexes
+}
@@ -0,0 +1,55 @@
+package seed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeFixture(t *testing.T) {
+	fixture, err := FakeFixture(Volume{Users: 40, Projects: 25, TablesPerProject: 8}, 42)
+
+	require.NoError(t, err)
+	require.NoError(t, fixture.validate()) // Every reference resolves
+	assert.Len(t, fixture.Users, 40)
+	require.Len(t, fixture.Projects, 25)
+
+	emails := make(map[string]bool)
+	for _, user := range fixture.Users {
+		assert.False(t, emails[user.Email], "%s repeats", user.Email)
+		emails[user.Email] = true
+	}
+	for _, project := range fixture.Projects {
+		assert.Len(t, project.Tables, 8)
+		assert.LessOrEqual(t, len(project.Collaborators), maxCollaborators)
+		assert.NotContains(t, project.Collaborators, project.Owner)
+
+		// Tables only refer to tables before them
+		position := make(map[string]int)
+		for i, table := range project.Tables {
+			position[table.Name] = i
+			assert.Equal(t, "id", table.Fields[0].Name)
+		}
+		for _, relationship := range project.Relationships {
+			assert.Less(t, position[tableOf(relationship.Source)], position[tableOf(relationship.Target)])
+		}
+	}
+
+	again, err := FakeFixture(Volume{Users: 40, Projects: 25, TablesPerProject: 8}, 42)
+	require.NoError(t, err)
+	assert.Equal(t, fixture, again)
+}
+
+func TestFakeFixtureVolumes(t *testing.T) {
+	_, err := FakeFixture(Volume{Projects: 3}, 1)
+	assert.Error(t, err)
+
+	_, err = FakeFixture(Volume{Users: -1}, 1)
+	assert.Error(t, err)
+
+	fixture, err := FakeFixture(Volume{Users: 1, Projects: 2}, 1)
+	require.NoError(t, err)
+	assert.Empty(t, fixture.Projects[0].Tables)
+	assert.Empty(t, fixture.Projects[0].Collaborators)
+}
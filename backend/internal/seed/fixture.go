@@ -0,0 +1,209 @@
+package seed
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// datasets holds the fixture files --fixture selects by name
+//
+//go:embed fixtures/*.yaml
+var datasets embed.FS
+
+// Fixture is a dataset of a fixture file: users, and projects with their
+// collaborators and schema. Users are referred to by username and fields by
+// table and field name, e.g. orders.user_id. JSON files are read as YAML.
+// Users without a password must exist already, like those of exported
+// projects.
+type Fixture struct {
+	Users    []FixtureUser    `yaml:"users"`
+	Projects []FixtureProject `yaml:"projects"`
+}
+
+type FixtureUser struct {
+	Username string `yaml:"username"`
+	Email    string `yaml:"email"`
+	Password string `yaml:"password,omitempty"`
+}
+
+type FixtureProject struct {
+	Name          string                `yaml:"name"`
+	Description   string                `yaml:"description,omitempty"`
+	Owner         string                `yaml:"owner"`                   // Username
+	Collaborators []string              `yaml:"collaborators,omitempty"` // Usernames
+	DatabaseType  string                `yaml:"database_type,omitempty"`
+	CanvasData    string                `yaml:"canvas_data,omitempty"`
+	Tables        []FixtureTable        `yaml:"tables,omitempty"`
+	Relationships []FixtureRelationship `yaml:"relationships,omitempty"`
+}
+
+type FixtureTable struct {
+	Name   string         `yaml:"name"`
+	PosX   float64        `yaml:"pos_x"`
+	PosY   float64        `yaml:"pos_y"`
+	Fields []FixtureField `yaml:"fields,omitempty"`
+}
+
+// FixtureField is positioned by its order in the table, from 1
+type FixtureField struct {
+	Name         string `yaml:"name"`
+	DataType     string `yaml:"data_type"`
+	PrimaryKey   bool   `yaml:"primary_key,omitempty"`
+	Nullable     bool   `yaml:"nullable,omitempty"`
+	DefaultValue string `yaml:"default_value,omitempty"`
+}
+
+type FixtureRelationship struct {
+	Source string `yaml:"source"` // table.field
+	Target string `yaml:"target"` // table.field
+	Type   string `yaml:"type"`
+}
+
+// AvailableDatasets lists the names --fixture accepts besides file paths
+func AvailableDatasets() []string {
+	entries, _ := fs.ReadDir(datasets, "fixtures")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".yaml"))
+	}
+	return names
+}
+
+// LoadFixtures reads the datasets named by selection, a comma separated list
+// of embedded dataset names or paths to YAML or JSON files, and merges them.
+// A user may be declared by several datasets, identically.
+func LoadFixtures(selection string) (*Fixture, error) {
+	merged := &Fixture{}
+	users := make(map[string]FixtureUser)
+	projects := make(map[string]bool)
+
+	for _, name := range strings.Split(selection, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		data, err := readDataset(name)
+		if err != nil {
+			return nil, err
+		}
+		fixture, err := ParseFixture(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		for _, user := range fixture.Users {
+			if existing, ok := users[user.Username]; ok {
+				if existing != user {
+					return nil, fmt.Errorf("%s: user %s is declared differently by another dataset", name, user.Username)
+				}
+				continue
+			}
+			users[user.Username] = user
+			merged.Users = append(merged.Users, user)
+		}
+		for _, project := range fixture.Projects {
+			if projects[project.Name] {
+				return nil, fmt.Errorf("%s: project %q is declared by another dataset", name, project.Name)
+			}
+			projects[project.Name] = true
+			merged.Projects = append(merged.Projects, project)
+		}
+	}
+
+	if len(merged.Users) == 0 && len(merged.Projects) == 0 {
+		return nil, errors.New("no dataset selected")
+	}
+	return merged, nil
+}
+
+// readDataset reads an embedded dataset by name, or else a file by path
+func readDataset(name string) ([]byte, error) {
+	if filepath.Ext(name) == "" {
+		data, err := datasets.ReadFile("fixtures/" + name + ".yaml")
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unknown dataset %q, available: %s", name, strings.Join(AvailableDatasets(), ", "))
+	}
+	return os.ReadFile(name)
+}
+
+// ParseFixture decodes a fixture file, rejecting unknown keys, and checks that
+// what it refers to is declared in it
+func ParseFixture(data []byte) (*Fixture, error) {
+	var fixture Fixture
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fixture); err != nil {
+		return nil, err
+	}
+	if err := fixture.validate(); err != nil {
+		return nil, err
+	}
+	return &fixture, nil
+}
+
+func (f *Fixture) validate() error {
+	users := make(map[string]bool, len(f.Users))
+	for _, user := range f.Users {
+		if user.Username == "" || user.Email == "" {
+			return errors.New("users need a username and an email")
+		}
+		if users[user.Username] {
+			return fmt.Errorf("user %s is declared twice", user.Username)
+		}
+		users[user.Username] = true
+	}
+
+	for _, project := range f.Projects {
+		if project.Name == "" {
+			return errors.New("projects need a name")
+		}
+		for _, username := range append([]string{project.Owner}, project.Collaborators...) {
+			if !users[username] {
+				return fmt.Errorf("project %q: unknown user %q", project.Name, username)
+			}
+		}
+
+		fields := make(map[string]bool)
+		for _, table := range project.Tables {
+			if table.Name == "" {
+				return fmt.Errorf("project %q: tables need a name", project.Name)
+			}
+			for _, field := range table.Fields {
+				key := table.Name + "." + field.Name
+				if field.Name == "" || field.DataType == "" {
+					return fmt.Errorf("project %q: fields of %s need a name and a data type", project.Name, table.Name)
+				}
+				if fields[key] {
+					return fmt.Errorf("project %q: field %s is declared twice", project.Name, key)
+				}
+				fields[key] = true
+			}
+		}
+		for _, relationship := range project.Relationships {
+			for _, end := range []string{relationship.Source, relationship.Target} {
+				if !fields[end] {
+					return fmt.Errorf("project %q: relationship refers to unknown field %q", project.Name, end)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// tableOf returns the table of a table.field reference
+func tableOf(reference string) string {
+	return reference[:strings.LastIndex(reference, ".")]
+}
@@ -0,0 +1,66 @@
+package seed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFixturesBundled(t *testing.T) {
+	fixture, err := LoadFixtures("ecommerce, blog,tasks")
+
+	require.NoError(t, err)
+	assert.Len(t, fixture.Users, 3) // Declared again by blog and tasks, identically
+	require.Len(t, fixture.Projects, 3)
+	assert.Equal(t, "Collaborative E-commerce Platform", fixture.Projects[0].Name)
+	assert.Equal(t, []string{"test2", "test3"}, fixture.Projects[0].Collaborators)
+	assert.Len(t, fixture.Projects[0].Tables, 4)
+	assert.Equal(t, FixtureRelationship{Source: "orders.user_id", Target: "users.id", Type: "many_to_one"}, fixture.Projects[0].Relationships[0])
+}
+
+func TestLoadFixturesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mine.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"users": [{"username": "ada", "email": "ada@example.com", "password": "secret"}],
+		"projects": [{"name": "Notes", "owner": "ada", "tables": [{"name": "notes", "fields": [{"name": "id", "data_type": "UUID", "primary_key": true}]}]}]
+	}`), 0o600))
+
+	fixture, err := LoadFixtures(path)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ada", fixture.Projects[0].Owner)
+	assert.True(t, fixture.Projects[0].Tables[0].Fields[0].PrimaryKey)
+
+	_, err = LoadFixtures("nonexistent")
+	assert.ErrorContains(t, err, "available: blog, ecommerce, tasks")
+}
+
+func TestParseFixtureReferences(t *testing.T) {
+	tests := map[string]string{
+		"unknown owner": `
+users: [{username: ada, email: ada@example.com, password: x}]
+projects: [{name: P, owner: alan}]`,
+		"unknown collaborator": `
+users: [{username: ada, email: ada@example.com, password: x}]
+projects: [{name: P, owner: ada, collaborators: [alan]}]`,
+		"unknown field": `
+users: [{username: ada, email: ada@example.com, password: x}]
+projects:
+  - name: P
+    owner: ada
+    tables: [{name: posts, fields: [{name: id, data_type: INT}]}]
+    relationships: [{source: posts.author_id, target: users.id, type: many_to_one}]`,
+		"unknown key": `
+users: [{username: ada, email: ada@example.com, password: x, admin: true}]`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseFixture([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
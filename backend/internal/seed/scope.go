@@ -0,0 +1,75 @@
+package seed
+
+import (
+	"errors"
+	"fmt"
+)
+
+// productionEnv is the ENV of production deployments, whose database is
+// never cleared without --i-know-what-im-doing
+const productionEnv = "production"
+
+// Scope narrows what is cleared and seeded
+type Scope struct {
+	Project    string // Name of the only project to clear and seed; all when empty
+	OnlySchema bool   // Only tables, fields and relationships, of existing projects
+}
+
+// Describe says what clearing the scope removes, for the confirmation prompt
+func (scope Scope) Describe() string {
+	what := "all data"
+	if scope.OnlySchema {
+		what = "the tables, fields and relationships of all projects"
+	}
+	if scope.Project != "" {
+		what = fmt.Sprintf("project %q with its schema and collaborators", scope.Project)
+		if scope.OnlySchema {
+			what = fmt.Sprintf("the tables, fields and relationships of project %q", scope.Project)
+		}
+	}
+	return what
+}
+
+// Select narrows a fixture to the scope: the targeted project and the users
+// it needs, or no users at all when only the schema is seeded
+func (scope Scope) Select(fixture *Fixture) (*Fixture, error) {
+	selected := &Fixture{Users: fixture.Users, Projects: fixture.Projects}
+	if scope.Project != "" {
+		selected.Projects = nil
+		for _, project := range fixture.Projects {
+			if project.Name == scope.Project {
+				selected.Projects = append(selected.Projects, project)
+			}
+		}
+		if len(selected.Projects) == 0 {
+			return nil, fmt.Errorf("no project named %q in the selected data", scope.Project)
+		}
+
+		needed := make(map[string]bool)
+		for _, project := range selected.Projects {
+			needed[project.Owner] = true
+			for _, username := range project.Collaborators {
+				needed[username] = true
+			}
+		}
+		selected.Users = nil
+		for _, user := range fixture.Users {
+			if needed[user.Username] {
+				selected.Users = append(selected.Users, user)
+			}
+		}
+	}
+	if scope.OnlySchema {
+		selected.Users = nil
+	}
+	return selected, nil
+}
+
+// GuardClear refuses to clear the database of a production deployment unless
+// the operator confirmed they mean it, whatever --force says
+func GuardClear(env string, confirmed bool) error {
+	if env == productionEnv && !confirmed {
+		return errors.New("refusing to clear a production database (ENV=production) without --i-know-what-im-doing")
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package seed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeSelect(t *testing.T) {
+	fixture, err := LoadFixtures("ecommerce,blog,tasks")
+	require.NoError(t, err)
+	blog := fixture.Projects[1].Name
+
+	all, err := Scope{}.Select(fixture)
+	require.NoError(t, err)
+	assert.Equal(t, fixture, all)
+
+	selected, err := Scope{Project: blog}.Select(fixture)
+	require.NoError(t, err)
+	require.Len(t, selected.Projects, 1)
+	assert.Equal(t, blog, selected.Projects[0].Name)
+	for _, user := range selected.Users { // Only the owner and collaborators of the project
+		assert.Contains(t, append([]string{selected.Projects[0].Owner}, selected.Projects[0].Collaborators...), user.Username)
+	}
+	assert.Len(t, fixture.Projects, 3) // Left as it was
+
+	schema, err := Scope{Project: blog, OnlySchema: true}.Select(fixture)
+	require.NoError(t, err)
+	assert.Empty(t, schema.Users)
+	assert.Len(t, schema.Projects, 1)
+
+	_, err = Scope{Project: "Nonexistent"}.Select(fixture)
+	assert.ErrorContains(t, err, `no project named "Nonexistent"`)
+}
+
+func TestGuardClear(t *testing.T) {
+	assert.NoError(t, GuardClear("development", false))
+	assert.NoError(t, GuardClear(productionEnv, true))
+	assert.ErrorContains(t, GuardClear(productionEnv, false), "--i-know-what-im-doing")
+}
@@ -0,0 +1,360 @@
+// Package seed fills a database with users and projects described by fixture
+// files or generated at random, and clears them again
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Seeder handles database clearing and seeding operations
+type Seeder struct {
+	db                *gorm.DB
+	userRepo          repository.UserRepositoryInterface
+	projectRepo       repository.ProjectRepositoryInterface
+	tableRepo         repository.TableRepositoryInterface
+	fieldRepo         repository.FieldRepositoryInterface
+	relationshipRepo  repository.RelationshipRepositoryInterface
+	collaborationRepo repository.CollaborationSessionRepositoryInterface
+}
+
+// NewSeeder creates a new seeder instance
+func NewSeeder(db *gorm.DB) *Seeder {
+	return &Seeder{
+		db:                db,
+		userRepo:          repository.NewUserRepository(db),
+		projectRepo:       repository.NewProjectRepository(db),
+		tableRepo:         repository.NewTableRepository(db),
+		fieldRepo:         repository.NewFieldRepository(db),
+		relationshipRepo:  repository.NewRelationshipRepository(db),
+		collaborationRepo: repository.NewCollaborationSessionRepository(db),
+	}
+}
+
+// ClearData removes the data of scope from the database in the correct order
+func (s *Seeder) ClearData(scope Scope) error {
+	if scope.Project != "" || scope.OnlySchema {
+		return s.db.Transaction(func(tx *gorm.DB) error {
+			return clearScope(tx, scope)
+		})
+	}
+
+	// Use a transaction for consistency
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// Clear in dependency order to avoid foreign key conflicts
+
+		// 1. Clear collaboration sessions
+		if err := tx.Exec("DELETE FROM collaboration_sessions").Error; err != nil {
+			return fmt.Errorf("failed to clear collaboration sessions: %w", err)
+		}
+		log.Println("✓ Cleared collaboration sessions")
+
+		// 2. Clear relationships
+		if err := tx.Exec("DELETE FROM relationships").Error; err != nil {
+			return fmt.Errorf("failed to clear relationships: %w", err)
+		}
+		log.Println("✓ Cleared relationships")
+
+		// 3. Clear fields
+		if err := tx.Exec("DELETE FROM fields").Error; err != nil {
+			return fmt.Errorf("failed to clear fields: %w", err)
+		}
+		log.Println("✓ Cleared fields")
+
+		// 4. Clear tables
+		if err := tx.Exec("DELETE FROM tables").Error; err != nil {
+			return fmt.Errorf("failed to clear tables: %w", err)
+		}
+		log.Println("✓ Cleared tables")
+
+		// 5. Clear project collaborators (many-to-many relationship)
+		if err := tx.Exec("DELETE FROM project_collaborators").Error; err != nil {
+			return fmt.Errorf("failed to clear project collaborators: %w", err)
+		}
+		log.Println("✓ Cleared project collaborators")
+
+		// 6. Clear projects
+		if err := tx.Exec("DELETE FROM projects").Error; err != nil {
+			return fmt.Errorf("failed to clear projects: %w", err)
+		}
+		log.Println("✓ Cleared projects")
+
+		// 7. Clear users
+		if err := tx.Exec("DELETE FROM users").Error; err != nil {
+			return fmt.Errorf("failed to clear users: %w", err)
+		}
+		log.Println("✓ Cleared users")
+
+		return nil
+	})
+}
+
+// clearScope removes the schema of the projects of a narrowed scope, and the
+// projects themselves unless only the schema is cleared. Users are kept.
+func clearScope(tx *gorm.DB, scope Scope) error {
+	projects := tx.Model(&models.Project{})
+	if scope.Project != "" {
+		projects = projects.Where("name = ?", scope.Project)
+	}
+	var projectIDs []uuid.UUID
+	if err := projects.Pluck("id", &projectIDs).Error; err != nil {
+		return fmt.Errorf("failed to find projects: %w", err)
+	}
+	if len(projectIDs) == 0 {
+		log.Println("✓ No project to clear")
+		return nil
+	}
+
+	// In dependency order, like ClearData
+	type clearStep struct{ what, query string }
+	var steps []clearStep
+	if !scope.OnlySchema {
+		steps = append(steps, clearStep{"collaboration sessions", "DELETE FROM collaboration_sessions WHERE project_id IN ?"})
+	}
+	steps = append(steps,
+		clearStep{"relationships", "DELETE FROM relationships WHERE project_id IN ?"},
+		clearStep{"fields", "DELETE FROM fields WHERE table_id IN (SELECT id FROM tables WHERE project_id IN ?)"},
+		clearStep{"tables", "DELETE FROM tables WHERE project_id IN ?"},
+	)
+	if !scope.OnlySchema {
+		steps = append(steps,
+			clearStep{"project collaborators", "DELETE FROM project_collaborators WHERE project_id IN ?"},
+			clearStep{"projects", "DELETE FROM projects WHERE id IN ?"},
+		)
+	}
+
+	for _, step := range steps {
+		if err := tx.Exec(step.query, projectIDs).Error; err != nil {
+			return fmt.Errorf("failed to clear %s: %w", step.what, err)
+		}
+		log.Printf("✓ Cleared %s of %d projects", step.what, len(projectIDs))
+	}
+	return nil
+}
+
+// Seeded counts what SeedData wrote
+type Seeded struct {
+	Users         int         `json:"users"`    // Created; existing users are reused
+	Projects      []uuid.UUID `json:"projects"` // Created, or whose schema was seeded
+	Tables        int         `json:"tables"`
+	Fields        int         `json:"fields"`
+	Relationships int         `json:"relationships"`
+}
+
+// SeedData populates the database with the users and projects of fixture, or
+// only with the schema of its projects, which must then already exist
+func (s *Seeder) SeedData(fixture *Fixture, scope Scope) (*Seeded, error) {
+	seeded := &Seeded{Projects: []uuid.UUID{}}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Create temporary seeder with transaction DB
+		txSeeder := &Seeder{
+			db:                tx,
+			userRepo:          repository.NewUserRepository(tx),
+			projectRepo:       repository.NewProjectRepository(tx),
+			tableRepo:         repository.NewTableRepository(tx),
+			fieldRepo:         repository.NewFieldRepository(tx),
+			relationshipRepo:  repository.NewRelationshipRepository(tx),
+			collaborationRepo: repository.NewCollaborationSessionRepository(tx),
+		}
+
+		if scope.OnlySchema {
+			for _, project := range fixture.Projects {
+				projectID, err := txSeeder.existingProject(project.Name)
+				if err != nil {
+					return err
+				}
+				seeded.Projects = append(seeded.Projects, projectID)
+				if err := txSeeder.seedSchema(projectID, project, seeded); err != nil {
+					return fmt.Errorf("failed to seed the schema of project %s: %w", project.Name, err)
+				}
+			}
+			log.Printf("✓ Created the tables, fields and relationships of %d projects", len(fixture.Projects))
+			return nil
+		}
+
+		// Seed users
+		users, err := txSeeder.seedUsers(fixture.Users, seeded)
+		if err != nil {
+			return fmt.Errorf("failed to seed users: %w", err)
+		}
+		log.Printf("✓ Created %d users, reused %d", seeded.Users, len(users)-seeded.Users)
+
+		// Seed projects with their schema and collaborators
+		for _, project := range fixture.Projects {
+			if err := txSeeder.seedProject(project, users, seeded); err != nil {
+				return fmt.Errorf("failed to seed project %s: %w", project.Name, err)
+			}
+		}
+		log.Printf("✓ Created %d projects with their tables, fields, relationships and collaborators", len(fixture.Projects))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return seeded, nil
+}
+
+// existingProject finds the only project named name, whose schema is seeded
+// with --only-schema
+func (s *Seeder) existingProject(name string) (uuid.UUID, error) {
+	var projectIDs []uuid.UUID
+	if err := s.db.Model(&models.Project{}).Where("name = ?", name).Pluck("id", &projectIDs).Error; err != nil {
+		return uuid.Nil, fmt.Errorf("failed to find project %s: %w", name, err)
+	}
+	switch len(projectIDs) {
+	case 0:
+		return uuid.Nil, fmt.Errorf("project %s does not exist, seed it without --only-schema first", name)
+	case 1:
+		return projectIDs[0], nil
+	default:
+		return uuid.Nil, fmt.Errorf("%d projects are named %s", len(projectIDs), name)
+	}
+}
+
+// seedUsers creates the users of a fixture, by username. Users that already
+// exist, kept by a scoped clear, are reused as they are.
+func (s *Seeder) seedUsers(fixtureUsers []FixtureUser, seeded *Seeded) (map[string]*models.User, error) {
+	users := make(map[string]*models.User, len(fixtureUsers))
+	hashes := make(map[string]string) // Hashing is slow, so users sharing a password share its hash
+	for _, fixtureUser := range fixtureUsers {
+		existing, err := s.userRepo.GetByUsername(fixtureUser.Username)
+		if err == nil {
+			users[existing.Username] = existing
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to find user %s: %w", fixtureUser.Username, err)
+		}
+		if fixtureUser.Password == "" { // Exported users have none
+			return nil, fmt.Errorf("user %s does not exist and has no password to create it with", fixtureUser.Username)
+		}
+
+		hashedPassword, ok := hashes[fixtureUser.Password]
+		if !ok {
+			if hashedPassword, err = hashPassword(fixtureUser.Password); err != nil {
+				return nil, fmt.Errorf("failed to hash password for user %s: %w", fixtureUser.Username, err)
+			}
+			hashes[fixtureUser.Password] = hashedPassword
+		}
+
+		user := &models.User{
+			Email:        fixtureUser.Email,
+			Username:     fixtureUser.Username,
+			PasswordHash: hashedPassword,
+		}
+		userID, err := s.userRepo.Create(user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create user %s: %w", user.Username, err)
+		}
+		user.ID = userID
+		users[user.Username] = user
+		seeded.Users++
+	}
+
+	return users, nil
+}
+
+// seedProject creates a project of a fixture with its tables, fields,
+// relationships and collaborators
+func (s *Seeder) seedProject(fixtureProject FixtureProject, users map[string]*models.User, seeded *Seeded) error {
+	project := &models.Project{
+		Name:         fixtureProject.Name,
+		Description:  fixtureProject.Description,
+		OwnerID:      users[fixtureProject.Owner].ID,
+		DatabaseType: fixtureProject.DatabaseType,
+		CanvasData:   fixtureProject.CanvasData,
+	}
+	projectID, err := s.projectRepo.Create(project)
+	if err != nil {
+		return err
+	}
+
+	seeded.Projects = append(seeded.Projects, projectID)
+
+	if err := s.seedSchema(projectID, fixtureProject, seeded); err != nil {
+		return err
+	}
+
+	for _, username := range fixtureProject.Collaborators {
+		if err := s.projectRepo.AddCollaborator(projectID, users[username].ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// seedSchema creates the tables, fields and relationships of a fixture
+// project in the project of projectID
+func (s *Seeder) seedSchema(projectID uuid.UUID, fixtureProject FixtureProject, seeded *Seeded) error {
+	// Tables and fields by name, for relationships to refer to
+	tables := make(map[string]*models.Table, len(fixtureProject.Tables))
+	fields := make(map[string]*models.Field)
+	for _, fixtureTable := range fixtureProject.Tables {
+		table := &models.Table{
+			ProjectID: projectID,
+			Name:      fixtureTable.Name,
+			PosX:      fixtureTable.PosX,
+			PosY:      fixtureTable.PosY,
+		}
+		tableID, err := s.tableRepo.Create(table)
+		if err != nil {
+			return err
+		}
+		table.ID = tableID
+		tables[table.Name] = table
+		seeded.Tables++
+
+		tableFields := make([]*models.Field, len(fixtureTable.Fields))
+		for i, fixtureField := range fixtureTable.Fields {
+			tableFields[i] = &models.Field{
+				TableID:      tableID,
+				Name:         fixtureField.Name,
+				DataType:     fixtureField.DataType,
+				IsPrimaryKey: fixtureField.PrimaryKey,
+				IsNullable:   fixtureField.Nullable,
+				DefaultValue: fixtureField.DefaultValue,
+				Position:     i + 1,
+			}
+			fields[table.Name+"."+fixtureField.Name] = tableFields[i]
+		}
+		if len(tableFields) > 0 {
+			if err := s.fieldRepo.CreateBatch(tableFields); err != nil {
+				return err
+			}
+			seeded.Fields += len(tableFields)
+		}
+	}
+
+	for _, fixtureRelationship := range fixtureProject.Relationships {
+		relationship := &models.Relationship{
+			ProjectID:     projectID,
+			SourceTableID: tables[tableOf(fixtureRelationship.Source)].ID,
+			SourceFieldID: fields[fixtureRelationship.Source].ID,
+			TargetTableID: tables[tableOf(fixtureRelationship.Target)].ID,
+			TargetFieldID: fields[fixtureRelationship.Target].ID,
+			RelationType:  fixtureRelationship.Type,
+			LabelPosition: models.DefaultLabelPosition,
+		}
+		if _, err := s.relationshipRepo.Create(relationship); err != nil {
+			return err
+		}
+		seeded.Relationships++
+	}
+
+	return nil
+}
+
+// hashPassword creates a bcrypt hash of the password
+func hashPassword(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	return string(bytes), err
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/cache"
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+	"github.com/google/uuid"
+)
+
+// Kinds of entity whose project AccessCache remembers
+const (
+	accessKindTable        = "table"
+	accessKindField        = "field"
+	accessKindRelationship = "relationship"
+)
+
+// projectMembers are the users who may access a project. All but its viewers
+// may modify it.
+type projectMembers struct {
+	OwnerID         uuid.UUID   `json:"owner_id"`
+	CollaboratorIDs []uuid.UUID `json:"collaborator_ids"`
+	ViewerIDs       []uuid.UUID `json:"viewer_ids,omitempty"`
+}
+
+func (m *projectMembers) includes(userID uuid.UUID) bool {
+	return m.OwnerID == userID || slices.Contains(m.CollaboratorIDs, userID)
+}
+
+func (m *projectMembers) canModify(userID uuid.UUID) bool {
+	return m.includes(userID) && !slices.Contains(m.ViewerIDs, userID)
+}
+
+// AccessCache holds what the AuthorizationService checks: the members of each
+// project, invalidated whenever they change, and the project of tables, fields
+// and relationships, which never changes. Entries of deleted entities linger
+// until the short TTL, but the entities are not found past the check.
+// A nil *AccessCache caches nothing.
+type AccessCache struct {
+	store cache.Store
+	ttl   time.Duration
+}
+
+func NewAccessCache(store cache.Store, ttl time.Duration) *AccessCache {
+	return &AccessCache{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+func membersCacheKey(projectID uuid.UUID) string {
+	return "authz:members:" + projectID.String()
+}
+
+func projectOfCacheKey(kind string, id uuid.UUID) string {
+	return "authz:" + kind + ":" + id.String()
+}
+
+// Members returns the cached members of a project, or nil on a miss
+func (c *AccessCache) Members(projectID uuid.UUID) *projectMembers {
+	if c == nil {
+		return nil
+	}
+
+	value, err := c.store.Get(membersCacheKey(projectID))
+	if err != nil || value == nil {
+		return nil
+	}
+	var members projectMembers
+	if err := json.Unmarshal(value, &members); err != nil {
+		log.Printf("Failed to decode cached members of project %s: %v", projectID, err)
+		return nil
+	}
+	return &members
+}
+
+func (c *AccessCache) SetMembers(projectID uuid.UUID, members *projectMembers) {
+	if c == nil {
+		return
+	}
+
+	value, err := json.Marshal(members)
+	if err != nil {
+		log.Printf("Failed to encode members of project %s for the cache: %v", projectID, err)
+		return
+	}
+	if err := c.store.Set(membersCacheKey(projectID), value, c.ttl); err != nil {
+		log.Printf("Failed to cache members of project %s: %v", projectID, err)
+	}
+}
+
+// ProjectOf returns the cached project of an entity, or uuid.Nil on a miss
+func (c *AccessCache) ProjectOf(kind string, id uuid.UUID) uuid.UUID {
+	if c == nil {
+		return uuid.Nil
+	}
+
+	value, err := c.store.Get(projectOfCacheKey(kind, id))
+	if err != nil || value == nil {
+		return uuid.Nil
+	}
+	projectID, err := uuid.ParseBytes(value)
+	if err != nil {
+		return uuid.Nil
+	}
+	return projectID
+}
+
+func (c *AccessCache) SetProjectOf(kind string, id, projectID uuid.UUID) {
+	if c == nil {
+		return
+	}
+
+	if err := c.store.Set(projectOfCacheKey(kind, id), []byte(projectID.String()), c.ttl); err != nil {
+		log.Printf("Failed to cache project of %s %s: %v", kind, id, err)
+	}
+}
+
+// InvalidateMembers drops the cached members of projects whose owner or
+// collaborators changed
+func (c *AccessCache) InvalidateMembers(projectIDs ...uuid.UUID) {
+	if c == nil || len(projectIDs) == 0 {
+		return
+	}
+
+	keys := make([]string, len(projectIDs))
+	for i, projectID := range projectIDs {
+		keys[i] = membersCacheKey(projectID)
+	}
+	if err := c.store.Delete(keys...); err != nil {
+		log.Printf("Failed to invalidate cached members of projects %v: %v", projectIDs, err)
+		errorreport.Capture(context.Background(), err, errorreport.ProjectTag(projectIDs[0]))
+	}
+}
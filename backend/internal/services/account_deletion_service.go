@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/storage"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// AccountDeletionService lets users delete their own account along with their
+// personal data
+type AccountDeletionService struct {
+	userRepo     repository.UserRepositoryInterface
+	projectRepo  repository.ProjectRepositoryInterface
+	sessionRepo  repository.UserSessionRepositoryInterface
+	exportRepo   repository.DataExportRepositoryInterface
+	store        storage.Store // Holds avatars and data exports; nil when uploads are disabled
+	projectCache *ProjectCache
+	accessCache  *AccessCache
+}
+
+func NewAccountDeletionService(userRepo repository.UserRepositoryInterface, projectRepo repository.ProjectRepositoryInterface, sessionRepo repository.UserSessionRepositoryInterface, exportRepo repository.DataExportRepositoryInterface, store storage.Store, projectCache *ProjectCache, accessCache *AccessCache) *AccountDeletionService {
+	return &AccountDeletionService{
+		userRepo:     userRepo,
+		projectRepo:  projectRepo,
+		sessionRepo:  sessionRepo,
+		exportRepo:   exportRepo,
+		store:        store,
+		projectCache: projectCache,
+		accessCache:  accessCache,
+	}
+}
+
+// DeleteAccount deletes the user after checking the confirmation. When owned
+// projects with collaborators have no new owner and are not to be deleted, it
+// returns them with ErrTransferRequired so the user can choose. Service
+// accounts neither make a project shared nor can own one. Admins
+// impersonating the user in the session cannot delete the account.
+func (s *AccountDeletionService) DeleteAccount(ctx context.Context, userID, sessionID uuid.UUID, req *dto.DeleteAccountRequest) ([]*models.Project, error) {
+	if sessionID != uuid.Nil {
+		session, err := s.sessionRepo.GetByID(sessionID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if session != nil && session.ImpersonatorID != nil {
+			return nil, ErrForbidden
+		}
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if !deletionConfirmed(user, req) {
+		return nil, ErrInvalidCredentials
+	}
+
+	owned, err := s.projectRepo.GetByOwnerID(userID, repository.ProjectIncludes{Collaborators: true})
+	if err != nil {
+		return nil, err
+	}
+
+	transfers := make(map[uuid.UUID]uuid.UUID, len(req.ProjectTransfers))
+	for _, transfer := range req.ProjectTransfers {
+		transfers[transfer.ProjectID] = transfer.NewOwnerID
+	}
+
+	var undecided []*models.Project
+	transferred := 0
+	for _, project := range owned {
+		if newOwnerID, ok := transfers[project.ID]; ok {
+			if !hasCollaborator(project, newOwnerID) {
+				return nil, ErrInvalidInput
+			}
+			transferred++
+			continue
+		}
+		if countCollaborators(project.Collaborators) > 0 && !req.DeleteSharedProjects {
+			undecided = append(undecided, project)
+		}
+	}
+	if transferred != len(transfers) {
+		// A transfer names a project the user does not own
+		return nil, ErrInvalidInput
+	}
+	if len(undecided) > 0 {
+		return undecided, ErrTransferRequired
+	}
+
+	// The export rows go with the user, so look up their files first
+	var exports []*models.DataExport
+	if s.store != nil {
+		if exports, err = s.exportRepo.GetByUserID(userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The user leaves the projects they collaborate on, and owned projects are
+	// deleted or change owner
+	var joined []*models.Project
+	if s.projectCache != nil || s.accessCache != nil {
+		if joined, err = s.projectRepo.GetByCollaboratorID(userID, repository.ProjectIncludes{}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.userRepo.DeleteAccount(userID, transfers); err != nil {
+		return nil, err
+	}
+
+	changed := make([]uuid.UUID, 0, len(owned)+len(joined))
+	for _, project := range append(owned, joined...) {
+		changed = append(changed, project.ID)
+	}
+	s.projectCache.Invalidate(changed...)
+	s.accessCache.InvalidateMembers(changed...)
+
+	if s.store == nil {
+		return nil, nil
+	}
+	if user.AvatarKey != "" {
+		if err := s.store.Delete(ctx, user.AvatarKey); err != nil {
+			log.Printf("Failed to delete avatar %s of deleted user: %v", user.AvatarKey, err)
+			errorreport.Capture(ctx, err, errorreport.UserTag(userID))
+		}
+	}
+	for _, export := range exports {
+		if export.StorageKey == "" {
+			continue
+		}
+		if err := s.store.Delete(ctx, export.StorageKey); err != nil {
+			log.Printf("Failed to delete data export %s of deleted user: %v", export.StorageKey, err)
+			errorreport.Capture(ctx, err, errorreport.UserTag(userID))
+		}
+	}
+	return nil, nil
+}
+
+// deletionConfirmed checks the password, or the email for accounts that only
+// sign in through SSO and so have none
+func deletionConfirmed(user *models.User, req *dto.DeleteAccountRequest) bool {
+	if user.PasswordHash == "" {
+		return req.ConfirmEmail != "" && strings.EqualFold(strings.TrimSpace(req.ConfirmEmail), user.Email)
+	}
+	return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) == nil
+}
+
+// hasCollaborator reports whether the user, who is not a service account,
+// collaborates on the project
+func hasCollaborator(project *models.Project, userID uuid.UUID) bool {
+	for _, collaborator := range project.Collaborators {
+		if collaborator.ID == userID && !collaborator.IsServiceAccount {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AccountDeletionServiceTestSuite struct {
+	suite.Suite
+	mockUserRepo    *mockRepo.MockUserRepository
+	mockProjectRepo *mockRepo.MockProjectRepository
+	mockSessionRepo *mockRepo.MockUserSessionRepository
+	service         *AccountDeletionService
+	user            *models.User
+	collaborator    models.User
+}
+
+func (suite *AccountDeletionServiceTestSuite) SetupTest() {
+	suite.mockUserRepo = new(mockRepo.MockUserRepository)
+	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
+	suite.mockSessionRepo = new(mockRepo.MockUserSessionRepository)
+	suite.service = NewAccountDeletionService(suite.mockUserRepo, suite.mockProjectRepo, suite.mockSessionRepo, nil, nil, nil, nil)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	suite.user = &models.User{ID: uuid.New(), Email: "alice@example.com", PasswordHash: string(hash)}
+	suite.collaborator = models.User{ID: uuid.New(), Username: "bob"}
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+}
+
+func TestAccountDeletionServiceSuite(t *testing.T) {
+	suite.Run(t, new(AccountDeletionServiceTestSuite))
+}
+
+// Test DeleteAccount - Projects nobody else uses go with the account
+func (suite *AccountDeletionServiceTestSuite) TestDeleteAccount_Success() {
+	solo := &models.Project{ID: uuid.New(), OwnerID: suite.user.ID}
+	suite.mockProjectRepo.On("GetByOwnerID", suite.user.ID, repository.ProjectIncludes{Collaborators: true}).Return([]*models.Project{solo}, nil)
+	suite.mockUserRepo.On("DeleteAccount", suite.user.ID, map[uuid.UUID]uuid.UUID{}).Return(nil)
+
+	shared, err := suite.service.DeleteAccount(context.Background(), suite.user.ID, uuid.Nil, &dto.DeleteAccountRequest{Password: "secret123"})
+
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), shared)
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+// Test DeleteAccount - A wrong password deletes nothing
+func (suite *AccountDeletionServiceTestSuite) TestDeleteAccount_WrongPassword() {
+	_, err := suite.service.DeleteAccount(context.Background(), suite.user.ID, uuid.Nil, &dto.DeleteAccountRequest{Password: "guess"})
+
+	assert.ErrorIs(suite.T(), err, ErrInvalidCredentials)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "DeleteAccount", mock.Anything, mock.Anything)
+}
+
+// Test DeleteAccount - Accounts without a password confirm with their email
+func (suite *AccountDeletionServiceTestSuite) TestDeleteAccount_SSOConfirmEmail() {
+	suite.user.PasswordHash = ""
+	suite.mockProjectRepo.On("GetByOwnerID", suite.user.ID, repository.ProjectIncludes{Collaborators: true}).Return([]*models.Project{}, nil)
+	suite.mockUserRepo.On("DeleteAccount", suite.user.ID, mock.Anything).Return(nil)
+
+	_, err := suite.service.DeleteAccount(context.Background(), suite.user.ID, uuid.Nil, &dto.DeleteAccountRequest{Password: ""})
+	assert.ErrorIs(suite.T(), err, ErrInvalidCredentials)
+
+	_, err = suite.service.DeleteAccount(context.Background(), suite.user.ID, uuid.Nil, &dto.DeleteAccountRequest{ConfirmEmail: "Alice@example.com"})
+	assert.NoError(suite.T(), err)
+}
+
+// Test DeleteAccount - Shared projects without a decision are returned
+func (suite *AccountDeletionServiceTestSuite) TestDeleteAccount_TransferRequired() {
+	project := &models.Project{ID: uuid.New(), OwnerID: suite.user.ID, Collaborators: []models.User{suite.collaborator}}
+	suite.mockProjectRepo.On("GetByOwnerID", suite.user.ID, repository.ProjectIncludes{Collaborators: true}).Return([]*models.Project{project}, nil)
+
+	shared, err := suite.service.DeleteAccount(context.Background(), suite.user.ID, uuid.Nil, &dto.DeleteAccountRequest{Password: "secret123"})
+
+	assert.ErrorIs(suite.T(), err, ErrTransferRequired)
+	assert.Equal(suite.T(), []*models.Project{project}, shared)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "DeleteAccount", mock.Anything, mock.Anything)
+}
+
+// Test DeleteAccount - Shared projects move to the chosen collaborator
+func (suite *AccountDeletionServiceTestSuite) TestDeleteAccount_Transfer() {
+	project := &models.Project{ID: uuid.New(), OwnerID: suite.user.ID, Collaborators: []models.User{suite.collaborator}}
+	suite.mockProjectRepo.On("GetByOwnerID", suite.user.ID, repository.ProjectIncludes{Collaborators: true}).Return([]*models.Project{project}, nil)
+	suite.mockUserRepo.On("DeleteAccount", suite.user.ID, map[uuid.UUID]uuid.UUID{project.ID: suite.collaborator.ID}).Return(nil)
+
+	_, err := suite.service.DeleteAccount(context.Background(), suite.user.ID, uuid.Nil, &dto.DeleteAccountRequest{
+		Password:         "secret123",
+		ProjectTransfers: []dto.ProjectTransferRequest{{ProjectID: project.ID, NewOwnerID: suite.collaborator.ID}},
+	})
+
+	assert.NoError(suite.T(), err)
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+// Test DeleteAccount - Projects can only go to one of their collaborators
+func (suite *AccountDeletionServiceTestSuite) TestDeleteAccount_TransferToStranger() {
+	project := &models.Project{ID: uuid.New(), OwnerID: suite.user.ID, Collaborators: []models.User{suite.collaborator}}
+	suite.mockProjectRepo.On("GetByOwnerID", suite.user.ID, repository.ProjectIncludes{Collaborators: true}).Return([]*models.Project{project}, nil)
+
+	_, err := suite.service.DeleteAccount(context.Background(), suite.user.ID, uuid.Nil, &dto.DeleteAccountRequest{
+		Password:         "secret123",
+		ProjectTransfers: []dto.ProjectTransferRequest{{ProjectID: project.ID, NewOwnerID: uuid.New()}},
+	})
+
+	assert.ErrorIs(suite.T(), err, ErrInvalidInput)
+}
+
+// Test DeleteAccount - Service accounts do not make a project shared and cannot own it
+func (suite *AccountDeletionServiceTestSuite) TestDeleteAccount_ServiceAccountCollaborator() {
+	bot := models.User{ID: uuid.New(), Username: "ci-bot", IsServiceAccount: true}
+	project := &models.Project{ID: uuid.New(), OwnerID: suite.user.ID, Collaborators: []models.User{bot}}
+	suite.mockProjectRepo.On("GetByOwnerID", suite.user.ID, repository.ProjectIncludes{Collaborators: true}).Return([]*models.Project{project}, nil)
+
+	_, err := suite.service.DeleteAccount(context.Background(), suite.user.ID, uuid.Nil, &dto.DeleteAccountRequest{
+		Password:         "secret123",
+		ProjectTransfers: []dto.ProjectTransferRequest{{ProjectID: project.ID, NewOwnerID: bot.ID}},
+	})
+	assert.ErrorIs(suite.T(), err, ErrInvalidInput)
+
+	suite.mockUserRepo.On("DeleteAccount", suite.user.ID, map[uuid.UUID]uuid.UUID{}).Return(nil)
+	shared, err := suite.service.DeleteAccount(context.Background(), suite.user.ID, uuid.Nil, &dto.DeleteAccountRequest{Password: "secret123"})
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), shared)
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+// Test DeleteAccount - Impersonation sessions cannot delete the account
+func (suite *AccountDeletionServiceTestSuite) TestDeleteAccount_Impersonated() {
+	adminID := uuid.New()
+	session := &models.UserSession{ID: uuid.New(), UserID: suite.user.ID, ImpersonatorID: &adminID}
+	suite.mockSessionRepo.On("GetByID", session.ID).Return(session, nil)
+
+	_, err := suite.service.DeleteAccount(context.Background(), suite.user.ID, session.ID, &dto.DeleteAccountRequest{Password: "secret123"})
+
+	assert.ErrorIs(suite.T(), err, ErrForbidden)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "DeleteAccount", mock.Anything, mock.Anything)
+}
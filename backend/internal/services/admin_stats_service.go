@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+)
+
+const (
+	// MaxStatsDays bounds how far back signups are reported
+	MaxStatsDays = 365
+	// statsOwners is how many of the largest owners are reported
+	statsOwners = 20
+	// statsCacheTTL spares the database the scans behind the stats when a dashboard polls them
+	statsCacheTTL = time.Minute
+)
+
+// InstanceStats are aggregates over the whole instance. Each owner of
+// projects is reported as a tenant.
+type InstanceStats struct {
+	Users         int64
+	Projects      int64
+	DatabaseBytes int64
+	Tenants       []*repository.OwnerStorage // Largest first
+	Signups       []repository.DailyCount    // One per day, oldest first
+	GeneratedAt   time.Time
+}
+
+// AdminStatsService computes the instance stats shown to administrators
+type AdminStatsService struct {
+	statsRepo repository.StatsRepositoryInterface
+	now       func() time.Time
+
+	mu     sync.Mutex
+	cached map[int]*InstanceStats // By number of days
+}
+
+func NewAdminStatsService(statsRepo repository.StatsRepositoryInterface) *AdminStatsService {
+	return &AdminStatsService{
+		statsRepo: statsRepo,
+		now:       time.Now,
+		cached:    make(map[int]*InstanceStats),
+	}
+}
+
+// GetStats returns the stats with the signups of the last days, today
+// included. Results are reused for up to a minute.
+func (s *AdminStatsService) GetStats(ctx context.Context, days int) (*InstanceStats, error) {
+	if days < 1 || days > MaxStatsDays {
+		return nil, ErrInvalidInput
+	}
+
+	now := s.now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stats, ok := s.cached[days]; ok && now.Sub(stats.GeneratedAt) < statsCacheTTL {
+		return stats, nil
+	}
+
+	totals, err := s.statsRepo.GetTotals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tenants, err := s.statsRepo.ListLargestOwners(ctx, statsOwners)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	since := today.AddDate(0, 0, 1-days)
+	counts, err := s.statsRepo.CountSignupsByDay(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	// Days without signups are filled in, so the series can be charted as is
+	byDay := make(map[time.Time]int64, len(counts))
+	for _, count := range counts {
+		byDay[count.Day] = count.Count
+	}
+	signups := make([]repository.DailyCount, days)
+	for i := range signups {
+		day := since.AddDate(0, 0, i)
+		signups[i] = repository.DailyCount{Day: day, Count: byDay[day]}
+	}
+
+	stats := &InstanceStats{
+		Users:         totals.Users,
+		Projects:      totals.Projects,
+		DatabaseBytes: totals.DatabaseBytes,
+		Tenants:       tenants,
+		Signups:       signups,
+		GeneratedAt:   now,
+	}
+	s.cached[days] = stats
+	return stats, nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AdminStatsServiceTestSuite struct {
+	suite.Suite
+	mockStatsRepo *mockRepo.MockStatsRepository
+	service       *AdminStatsService
+	now           time.Time
+}
+
+func (suite *AdminStatsServiceTestSuite) SetupTest() {
+	suite.mockStatsRepo = new(mockRepo.MockStatsRepository)
+	suite.service = NewAdminStatsService(suite.mockStatsRepo)
+	suite.now = time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+	suite.service.now = func() time.Time { return suite.now }
+}
+
+func TestAdminStatsServiceSuite(t *testing.T) {
+	suite.Run(t, new(AdminStatsServiceTestSuite))
+}
+
+func (suite *AdminStatsServiceTestSuite) expectStats() {
+	suite.mockStatsRepo.On("GetTotals", mock.Anything).Return(&repository.InstanceTotals{Users: 12, Projects: 30, DatabaseBytes: 1 << 20}, nil)
+	suite.mockStatsRepo.On("ListLargestOwners", mock.Anything, statsOwners).Return([]*repository.OwnerStorage{{OwnerID: uuid.New(), Username: "ada", Projects: 3, Bytes: 4096}}, nil)
+}
+
+// Test GetStats - Days without signups are filled in
+func (suite *AdminStatsServiceTestSuite) TestGetStats_Success() {
+	suite.expectStats()
+	since := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	suite.mockStatsRepo.On("CountSignupsByDay", mock.Anything, since).Return([]*repository.DailyCount{
+		{Day: since, Count: 2},
+		{Day: since.AddDate(0, 0, 2), Count: 5},
+	}, nil)
+
+	stats, err := suite.service.GetStats(context.Background(), 3)
+
+	suite.NoError(err)
+	suite.Equal(int64(12), stats.Users)
+	suite.Equal(int64(30), stats.Projects)
+	suite.Equal(int64(1<<20), stats.DatabaseBytes)
+	suite.Len(stats.Tenants, 1)
+	suite.Equal([]repository.DailyCount{
+		{Day: since, Count: 2},
+		{Day: since.AddDate(0, 0, 1), Count: 0},
+		{Day: since.AddDate(0, 0, 2), Count: 5},
+	}, stats.Signups)
+	suite.Equal(suite.now, stats.GeneratedAt)
+}
+
+// Test GetStats - Results are reused for a minute
+func (suite *AdminStatsServiceTestSuite) TestGetStats_Cached() {
+	suite.expectStats()
+	suite.mockStatsRepo.On("CountSignupsByDay", mock.Anything, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)).Return([]*repository.DailyCount{}, nil)
+
+	first, err := suite.service.GetStats(context.Background(), 1)
+	suite.NoError(err)
+	suite.now = suite.now.Add(30 * time.Second)
+	second, err := suite.service.GetStats(context.Background(), 1)
+	suite.NoError(err)
+	suite.Same(first, second)
+	suite.mockStatsRepo.AssertNumberOfCalls(suite.T(), "GetTotals", 1)
+
+	suite.now = suite.now.Add(time.Minute)
+	_, err = suite.service.GetStats(context.Background(), 1)
+	suite.NoError(err)
+	suite.mockStatsRepo.AssertNumberOfCalls(suite.T(), "GetTotals", 2)
+}
+
+// Test GetStats - Days out of range
+func (suite *AdminStatsServiceTestSuite) TestGetStats_InvalidDays() {
+	for _, days := range []int{0, MaxStatsDays + 1} {
+		stats, err := suite.service.GetStats(context.Background(), days)
+
+		suite.ErrorIs(err, ErrInvalidInput)
+		suite.Nil(stats)
+	}
+	suite.mockStatsRepo.AssertNotCalled(suite.T(), "GetTotals")
+}
+
+// Test GetStats - Failures are not cached
+func (suite *AdminStatsServiceTestSuite) TestGetStats_RepositoryError() {
+	suite.mockStatsRepo.On("GetTotals", mock.Anything).Return(nil, errors.New("database error"))
+
+	for range 2 {
+		stats, err := suite.service.GetStats(context.Background(), 30)
+
+		suite.Error(err)
+		suite.Nil(stats)
+	}
+	suite.mockStatsRepo.AssertNumberOfCalls(suite.T(), "GetTotals", 2)
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AdminUserService implements account management for administrators. Every
+// change it makes to another account is written to the admin audit log.
+type AdminUserService struct {
+	userRepo           repository.UserRepositoryInterface
+	auditRepo          repository.AdminAuditLogRepositoryInterface
+	userSessionService UserSessionServiceInterface
+
+	// Accounts configured as admins through ADMIN_USER_IDS, in addition to the admin role
+	configuredAdmins map[uuid.UUID]bool
+	impersonationTTL time.Duration
+}
+
+func NewAdminUserService(
+	cfg *config.Config,
+	userRepo repository.UserRepositoryInterface,
+	auditRepo repository.AdminAuditLogRepositoryInterface,
+	userSessionService UserSessionServiceInterface,
+) *AdminUserService {
+	configuredAdmins := make(map[uuid.UUID]bool)
+	for _, id := range cfg.AdminUserIDs {
+		if adminID, err := uuid.Parse(id); err == nil {
+			configuredAdmins[adminID] = true
+		}
+	}
+
+	return &AdminUserService{
+		userRepo:           userRepo,
+		auditRepo:          auditRepo,
+		userSessionService: userSessionService,
+		configuredAdmins:   configuredAdmins,
+		impersonationTTL:   cfg.Impersonation.SessionTTL,
+	}
+}
+
+// IsAdmin reports whether the user may use the admin routes
+func (s *AdminUserService) IsAdmin(userID uuid.UUID) (bool, error) {
+	if s.configuredAdmins[userID] {
+		return true, nil
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return s.isAdmin(user), nil
+}
+
+// ListUsers returns a page of users matching the filter, the cursor of the
+// next page and the total number of matches
+func (s *AdminUserService) ListUsers(filter repository.UserFilter, page repository.PageQuery) ([]*models.User, string, int64, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	if filter.Role != "" && !isValidRole(filter.Role) {
+		return nil, "", 0, ErrInvalidRole
+	}
+
+	users, next, err := s.userRepo.List(filter, page)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	total, err := s.userRepo.Count(filter)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	return users, next, total, nil
+}
+
+// SetUserDisabled disables or re-enables an account. Disabling signs the user out everywhere.
+func (s *AdminUserService) SetUserDisabled(actorID, userID uuid.UUID, disabled bool, reason, ipAddress string) (*models.User, error) {
+	if actorID == userID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	action := models.AuditActionUserEnabled
+	if disabled {
+		action = models.AuditActionUserDisabled
+		if !user.IsDisabled() {
+			now := time.Now()
+			user.DisabledAt = &now
+		}
+	} else {
+		user.DisabledAt = nil
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	if disabled {
+		if err := s.userSessionService.RevokeOtherSessions(user.ID, uuid.Nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.audit(actorID, action, user.ID, reason, ipAddress); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// SetUserRole grants or removes the admin role
+func (s *AdminUserService) SetUserRole(actorID, userID uuid.UUID, role, ipAddress string) (*models.User, error) {
+	if !isValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	// Keeps admins from locking themselves out
+	if actorID == userID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = role
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.audit(actorID, models.AuditActionUserRoleChanged, user.ID, role, ipAddress); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ForcePasswordReset signs the user out everywhere and requires a new password
+// before the account is used again
+func (s *AdminUserService) ForcePasswordReset(actorID, userID uuid.UUID, ipAddress string) (*models.User, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsServiceAccount {
+		return nil, ErrInvalidInput
+	}
+
+	user.PasswordResetRequired = true
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.userSessionService.RevokeOtherSessions(user.ID, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.audit(actorID, models.AuditActionUserPasswordReset, user.ID, "", ipAddress); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Impersonate starts a short support session as the user. The audit entry is
+// written before any token is issued, so no impersonation goes unrecorded.
+func (s *AdminUserService) Impersonate(actorID, userID uuid.UUID, reason, ipAddress, userAgent string) (*models.User, *TokenPair, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, nil, ErrInvalidInput
+	}
+	if actorID == userID {
+		return nil, nil, ErrCannotModifySelf
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.isAdmin(user) {
+		return nil, nil, ErrCannotImpersonateAdmin
+	}
+	if user.IsDisabled() {
+		return nil, nil, ErrAccountDisabled
+	}
+
+	if err := s.audit(actorID, models.AuditActionUserImpersonated, user.ID, reason, ipAddress); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.userSessionService.StartImpersonationSession(user, actorID, s.impersonationTTL, ipAddress, userAgent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// RecordImpersonatedRequest audits a change the admin makes while impersonating
+// the user, request naming the method and path
+func (s *AdminUserService) RecordImpersonatedRequest(actorID, userID uuid.UUID, request, ipAddress string) error {
+	return s.audit(actorID, models.AuditActionImpersonatedWrite, userID, request, ipAddress)
+}
+
+// GetAuditLogs returns a page of admin actions matching the filter, most recent first by default
+func (s *AdminUserService) GetAuditLogs(filter repository.AuditLogFilter, page repository.PageQuery) ([]*models.AdminAuditLog, string, error) {
+	return s.auditRepo.List(filter, page)
+}
+
+func (s *AdminUserService) getUser(userID uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *AdminUserService) isAdmin(user *models.User) bool {
+	if user.IsDisabled() {
+		return false
+	}
+	return s.configuredAdmins[user.ID] || user.Role == models.RoleAdmin
+}
+
+func (s *AdminUserService) audit(actorID uuid.UUID, action string, targetUserID uuid.UUID, details, ipAddress string) error {
+	return s.auditRepo.Create(&models.AdminAuditLog{
+		ActorID:      actorID,
+		Action:       action,
+		TargetUserID: targetUserID,
+		Details:      details,
+		IPAddress:    ipAddress,
+	})
+}
+
+func isValidRole(role string) bool {
+	return role == models.RoleUser || role == models.RoleAdmin
+}
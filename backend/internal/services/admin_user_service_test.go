@@ -0,0 +1,265 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type AdminUserServiceTestSuite struct {
+	suite.Suite
+	mockUserRepo    *mockRepo.MockUserRepository
+	mockAuditRepo   *mockRepo.MockAdminAuditLogRepository
+	mockSessionRepo *mockRepo.MockUserSessionRepository
+	jwtService      *JWTService
+	service         *AdminUserService
+	configuredAdmin uuid.UUID
+	actorID         uuid.UUID
+	user            *models.User
+}
+
+func (suite *AdminUserServiceTestSuite) SetupTest() {
+	suite.configuredAdmin = uuid.New()
+	suite.actorID = uuid.New()
+
+	cfg := &config.Config{}
+	cfg.JWT.Secret = "test-secret-key-for-admin-testing"
+	cfg.JWT.AccessTokenExp = 15 * time.Minute
+	cfg.JWT.RefreshTokenExp = 7 * 24 * time.Hour
+	cfg.AdminUserIDs = []string{suite.configuredAdmin.String(), "not-a-uuid"}
+	cfg.Impersonation.SessionTTL = 30 * time.Minute
+
+	suite.mockUserRepo = new(mockRepo.MockUserRepository)
+	suite.mockAuditRepo = new(mockRepo.MockAdminAuditLogRepository)
+	suite.mockSessionRepo = new(mockRepo.MockUserSessionRepository)
+	suite.jwtService = NewJWTService(cfg)
+	userSessionService := NewUserSessionService(cfg, suite.mockSessionRepo, suite.mockUserRepo, suite.jwtService)
+	suite.service = NewAdminUserService(cfg, suite.mockUserRepo, suite.mockAuditRepo, userSessionService)
+	suite.user = createTestUser()
+	suite.user.Role = models.RoleUser
+}
+
+func TestAdminUserServiceSuite(t *testing.T) {
+	suite.Run(t, new(AdminUserServiceTestSuite))
+}
+
+// expectAudit expects one audit entry for the action against the test user
+func (suite *AdminUserServiceTestSuite) expectAudit(action, details string) {
+	suite.mockAuditRepo.On("Create", mock.MatchedBy(func(entry *models.AdminAuditLog) bool {
+		return entry.ActorID == suite.actorID && entry.Action == action &&
+			entry.TargetUserID == suite.user.ID && entry.Details == details && entry.IPAddress == "192.0.2.1"
+	})).Return(nil).Once()
+}
+
+// Test IsAdmin - configured IDs and the admin role grant access, disabled admins do not
+func (suite *AdminUserServiceTestSuite) TestIsAdmin() {
+	disabledAt := time.Now()
+	roleAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	disabledAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin, DisabledAt: &disabledAt}
+	missingID := uuid.New()
+
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockUserRepo.On("GetByID", roleAdmin.ID).Return(roleAdmin, nil)
+	suite.mockUserRepo.On("GetByID", disabledAdmin.ID).Return(disabledAdmin, nil)
+	suite.mockUserRepo.On("GetByID", missingID).Return(nil, gorm.ErrRecordNotFound)
+
+	cases := map[uuid.UUID]bool{
+		suite.configuredAdmin: true,
+		roleAdmin.ID:          true,
+		suite.user.ID:         false,
+		disabledAdmin.ID:      false,
+		missingID:             false,
+	}
+	for userID, expected := range cases {
+		isAdmin, err := suite.service.IsAdmin(userID)
+		suite.NoError(err)
+		suite.Equal(expected, isAdmin, userID.String())
+	}
+}
+
+// Test ListUsers - passes the trimmed filter through and rejects unknown roles
+func (suite *AdminUserServiceTestSuite) TestListUsers() {
+	users := []*models.User{suite.user}
+	filter := repository.UserFilter{Query: "test", Role: models.RoleUser}
+	page := repository.PageQuery{Limit: 1}
+	suite.mockUserRepo.On("List", filter, page).Return(users, "next", nil)
+	suite.mockUserRepo.On("Count", filter).Return(int64(2), nil)
+
+	result, next, total, err := suite.service.ListUsers(repository.UserFilter{Query: " test ", Role: models.RoleUser}, page)
+
+	suite.NoError(err)
+	suite.Equal(users, result)
+	suite.Equal("next", next)
+	suite.Equal(int64(2), total)
+
+	_, _, _, err = suite.service.ListUsers(repository.UserFilter{Role: "owner"}, repository.PageQuery{})
+	suite.ErrorIs(err, ErrInvalidRole)
+}
+
+// Test SetUserDisabled - disabling revokes every session and is audited
+func (suite *AdminUserServiceTestSuite) TestSetUserDisabled_Disable() {
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockUserRepo.On("Update", suite.user).Return(nil)
+	suite.mockSessionRepo.On("RevokeAllByUserID", suite.user.ID, uuid.Nil, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.expectAudit(models.AuditActionUserDisabled, "abuse report")
+
+	user, err := suite.service.SetUserDisabled(suite.actorID, suite.user.ID, true, "abuse report", "192.0.2.1")
+
+	suite.Require().NoError(err)
+	suite.True(user.IsDisabled())
+	suite.mockSessionRepo.AssertExpectations(suite.T())
+	suite.mockAuditRepo.AssertExpectations(suite.T())
+}
+
+// Test SetUserDisabled - enabling clears the flag without touching sessions
+func (suite *AdminUserServiceTestSuite) TestSetUserDisabled_Enable() {
+	disabledAt := time.Now()
+	suite.user.DisabledAt = &disabledAt
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockUserRepo.On("Update", suite.user).Return(nil)
+	suite.expectAudit(models.AuditActionUserEnabled, "")
+
+	user, err := suite.service.SetUserDisabled(suite.actorID, suite.user.ID, false, "", "192.0.2.1")
+
+	suite.Require().NoError(err)
+	suite.False(user.IsDisabled())
+	suite.mockSessionRepo.AssertNotCalled(suite.T(), "RevokeAllByUserID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test SetUserDisabled - admins cannot disable themselves and unknown users are reported
+func (suite *AdminUserServiceTestSuite) TestSetUserDisabled_Errors() {
+	_, err := suite.service.SetUserDisabled(suite.actorID, suite.actorID, true, "", "192.0.2.1")
+	suite.ErrorIs(err, ErrCannotModifySelf)
+
+	missingID := uuid.New()
+	suite.mockUserRepo.On("GetByID", missingID).Return(nil, gorm.ErrRecordNotFound)
+	_, err = suite.service.SetUserDisabled(suite.actorID, missingID, true, "", "192.0.2.1")
+	suite.ErrorIs(err, ErrUserNotFound)
+
+	suite.mockAuditRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test SetUserRole - updates the role and records it
+func (suite *AdminUserServiceTestSuite) TestSetUserRole() {
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockUserRepo.On("Update", suite.user).Return(nil)
+	suite.expectAudit(models.AuditActionUserRoleChanged, models.RoleAdmin)
+
+	user, err := suite.service.SetUserRole(suite.actorID, suite.user.ID, models.RoleAdmin, "192.0.2.1")
+
+	suite.Require().NoError(err)
+	suite.Equal(models.RoleAdmin, user.Role)
+
+	_, err = suite.service.SetUserRole(suite.actorID, suite.user.ID, "owner", "192.0.2.1")
+	suite.ErrorIs(err, ErrInvalidRole)
+	_, err = suite.service.SetUserRole(suite.actorID, suite.actorID, models.RoleUser, "192.0.2.1")
+	suite.ErrorIs(err, ErrCannotModifySelf)
+}
+
+// Test ForcePasswordReset - flags the account and signs it out
+func (suite *AdminUserServiceTestSuite) TestForcePasswordReset() {
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockUserRepo.On("Update", suite.user).Return(nil)
+	suite.mockSessionRepo.On("RevokeAllByUserID", suite.user.ID, uuid.Nil, mock.AnythingOfType("time.Time")).Return(nil)
+	suite.expectAudit(models.AuditActionUserPasswordReset, "")
+
+	user, err := suite.service.ForcePasswordReset(suite.actorID, suite.user.ID, "192.0.2.1")
+
+	suite.Require().NoError(err)
+	suite.True(user.PasswordResetRequired)
+	suite.mockSessionRepo.AssertExpectations(suite.T())
+}
+
+// Test ForcePasswordReset - service accounts have no password to reset
+func (suite *AdminUserServiceTestSuite) TestForcePasswordReset_ServiceAccount() {
+	suite.user.IsServiceAccount = true
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+
+	_, err := suite.service.ForcePasswordReset(suite.actorID, suite.user.ID, "192.0.2.1")
+
+	suite.ErrorIs(err, ErrInvalidInput)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+// Test Impersonate - audits the reason and issues a session bound to the admin
+func (suite *AdminUserServiceTestSuite) TestImpersonate_Success() {
+	var stored *models.UserSession
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+	suite.expectAudit(models.AuditActionUserImpersonated, "ticket 42")
+	suite.mockSessionRepo.On("Create", mock.AnythingOfType("*models.UserSession")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*models.UserSession) }).
+		Return(uuid.New(), nil)
+
+	user, tokens, err := suite.service.Impersonate(suite.actorID, suite.user.ID, " ticket 42 ", "192.0.2.1", "test-agent")
+
+	suite.Require().NoError(err)
+	suite.Equal(suite.user, user)
+	suite.Require().NotNil(stored.ImpersonatorID)
+	suite.Equal(suite.actorID, *stored.ImpersonatorID)
+	suite.WithinDuration(time.Now().Add(30*time.Minute), stored.ExpiresAt, time.Minute)
+
+	claims, err := suite.jwtService.ValidateToken(tokens.AccessToken)
+	suite.NoError(err)
+	suite.Equal(suite.user.ID, claims.UserID)
+	suite.mockAuditRepo.AssertExpectations(suite.T())
+}
+
+// Test Impersonate - no session is issued when the audit entry cannot be written
+func (suite *AdminUserServiceTestSuite) TestImpersonate_AuditFailure() {
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockAuditRepo.On("Create", mock.AnythingOfType("*models.AdminAuditLog")).Return(assert.AnError)
+
+	_, tokens, err := suite.service.Impersonate(suite.actorID, suite.user.ID, "ticket 42", "192.0.2.1", "test-agent")
+
+	suite.Nil(tokens)
+	suite.ErrorIs(err, assert.AnError)
+	suite.mockSessionRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test Impersonate - a reason is required and admins or disabled accounts are refused
+func (suite *AdminUserServiceTestSuite) TestImpersonate_Refused() {
+	disabledAt := time.Now()
+	roleAdmin := &models.User{ID: uuid.New(), Role: models.RoleAdmin}
+	disabled := &models.User{ID: uuid.New(), Role: models.RoleUser, DisabledAt: &disabledAt}
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockUserRepo.On("GetByID", suite.configuredAdmin).Return(&models.User{ID: suite.configuredAdmin, Role: models.RoleUser}, nil)
+	suite.mockUserRepo.On("GetByID", roleAdmin.ID).Return(roleAdmin, nil)
+	suite.mockUserRepo.On("GetByID", disabled.ID).Return(disabled, nil)
+
+	_, _, err := suite.service.Impersonate(suite.actorID, suite.user.ID, "  ", "192.0.2.1", "test-agent")
+	suite.ErrorIs(err, ErrInvalidInput)
+
+	_, _, err = suite.service.Impersonate(suite.actorID, suite.configuredAdmin, "ticket", "192.0.2.1", "test-agent")
+	suite.ErrorIs(err, ErrCannotImpersonateAdmin)
+
+	_, _, err = suite.service.Impersonate(suite.actorID, roleAdmin.ID, "ticket", "192.0.2.1", "test-agent")
+	suite.ErrorIs(err, ErrCannotImpersonateAdmin)
+
+	_, _, err = suite.service.Impersonate(suite.actorID, disabled.ID, "ticket", "192.0.2.1", "test-agent")
+	suite.ErrorIs(err, ErrAccountDisabled)
+
+	suite.mockAuditRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+	suite.mockSessionRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test GetAuditLogs - passes the filter and page through
+func (suite *AdminUserServiceTestSuite) TestGetAuditLogs() {
+	entries := []*models.AdminAuditLog{{ID: uuid.New(), Action: models.AuditActionUserDisabled}}
+	filter := repository.AuditLogFilter{TargetUserID: &suite.user.ID}
+	suite.mockAuditRepo.On("List", filter, repository.PageQuery{}).Return(entries, "", nil)
+
+	result, next, err := suite.service.GetAuditLogs(filter, repository.PageQuery{})
+
+	suite.NoError(err)
+	suite.Equal(entries, result)
+	suite.Empty(next)
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// API token scopes
+const (
+	ScopeReadProjects = "read:projects" // Read projects and their schema
+	ScopeWriteSchema  = "write:schema"  // Create, update and delete projects, tables, fields and relationships
+)
+
+// ValidAPITokenScopes lists every scope a token can be granted
+var ValidAPITokenScopes = []string{ScopeReadProjects, ScopeWriteSchema}
+
+const (
+	// APITokenPrefix marks API tokens so they can be told apart from JWTs
+	APITokenPrefix = "ezm_"
+
+	apiTokenBytes         = 32
+	apiTokenDisplayLength = len(APITokenPrefix) + 8
+
+	// lastUsedInterval limits how often last-used tracking writes to the database
+	lastUsedInterval = time.Minute
+)
+
+type APITokenService struct {
+	tokenRepo repository.APITokenRepositoryInterface
+	userRepo  repository.UserRepositoryInterface
+}
+
+func NewAPITokenService(tokenRepo repository.APITokenRepositoryInterface, userRepo repository.UserRepositoryInterface) *APITokenService {
+	return &APITokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// CreateToken mints a token for the user and returns it with its plaintext value,
+// which is not stored and cannot be retrieved again
+func (s *APITokenService) CreateToken(userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*models.APIToken, string, error) {
+	return s.createToken(userID, nil, name, scopes, expiresAt)
+}
+
+// CreateProjectToken mints a token that is only accepted for the given project
+func (s *APITokenService) CreateProjectToken(userID, projectID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*models.APIToken, string, error) {
+	return s.createToken(userID, &projectID, name, scopes, expiresAt)
+}
+
+func (s *APITokenService) createToken(userID uuid.UUID, projectID *uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*models.APIToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", ErrInvalidInput
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", ErrInvalidInput
+	}
+
+	granted, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrUserNotFound
+		}
+		return nil, "", err
+	}
+
+	plaintext, err := generateAPIToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := &models.APIToken{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    plaintext[:apiTokenDisplayLength],
+		TokenHash: hashToken(plaintext),
+		Scopes:    strings.Join(granted, ","),
+		ProjectID: projectID,
+		ExpiresAt: expiresAt,
+	}
+
+	id, err := s.tokenRepo.Create(token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token.ID = id
+	return token, plaintext, nil
+}
+
+func (s *APITokenService) GetTokensByUserID(userID uuid.UUID) ([]*models.APIToken, error) {
+	return s.tokenRepo.GetByUserID(userID)
+}
+
+// RevokeToken revokes one of the user's own tokens
+func (s *APITokenService) RevokeToken(id, userID uuid.UUID) error {
+	token, err := s.getToken(id)
+	if err != nil {
+		return err
+	}
+
+	// Other users' tokens are reported as missing so their IDs are not disclosed
+	if token.UserID != userID {
+		return ErrAPITokenNotFound
+	}
+
+	return s.tokenRepo.Revoke(id, time.Now())
+}
+
+// AdminRevokeToken revokes any user's token
+func (s *APITokenService) AdminRevokeToken(id uuid.UUID) error {
+	if _, err := s.getToken(id); err != nil {
+		return err
+	}
+
+	return s.tokenRepo.Revoke(id, time.Now())
+}
+
+// AuthenticateToken resolves a plaintext token to an active token record and records its use
+func (s *APITokenService) AuthenticateToken(plaintext string) (*models.APIToken, error) {
+	if !strings.HasPrefix(plaintext, APITokenPrefix) {
+		return nil, ErrInvalidAPIToken
+	}
+
+	token, err := s.tokenRepo.GetByHash(hashToken(plaintext))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !token.IsActive(now) || token.User.IsDisabled() {
+		return nil, ErrInvalidAPIToken
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedInterval {
+		// Usage tracking is best effort and must not fail the request
+		if err := s.tokenRepo.UpdateLastUsed(token.ID, now); err != nil {
+			log.Printf("Failed to record API token use: %v", err)
+			errorreport.Capture(context.Background(), err, errorreport.UserTag(token.UserID))
+		} else {
+			token.LastUsedAt = &now
+		}
+	}
+
+	return token, nil
+}
+
+func (s *APITokenService) getToken(id uuid.UUID) (*models.APIToken, error) {
+	token, err := s.tokenRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPITokenNotFound
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// normalizeScopes validates scopes and removes duplicates. At least one scope is required.
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, ErrInvalidInput
+	}
+
+	var granted []string
+	for _, scope := range scopes {
+		if !slices.Contains(ValidAPITokenScopes, scope) {
+			return nil, ErrInvalidScope
+		}
+		if !slices.Contains(granted, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	return granted, nil
+}
+
+// generateAPIToken returns a new random token carrying APITokenPrefix
+func generateAPIToken() (string, error) {
+	buf := make([]byte, apiTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return APITokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken returns the hex SHA-256 of a token. Tokens are high-entropy,
+// so a fast unsalted hash is enough to make a leaked table useless.
+func hashToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
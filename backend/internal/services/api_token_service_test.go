@@ -0,0 +1,149 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type APITokenServiceTestSuite struct {
+	suite.Suite
+	mockTokenRepo *mockRepo.MockAPITokenRepository
+	mockUserRepo  *mockRepo.MockUserRepository
+	service       *APITokenService
+}
+
+func (suite *APITokenServiceTestSuite) SetupTest() {
+	suite.mockTokenRepo = new(mockRepo.MockAPITokenRepository)
+	suite.mockUserRepo = new(mockRepo.MockUserRepository)
+	suite.service = NewAPITokenService(suite.mockTokenRepo, suite.mockUserRepo)
+}
+
+func TestAPITokenServiceSuite(t *testing.T) {
+	suite.Run(t, new(APITokenServiceTestSuite))
+}
+
+// Test CreateToken - stores only the hash and returns the plaintext once
+func (suite *APITokenServiceTestSuite) TestCreateToken_Success() {
+	user := createTestUser()
+	tokenID := uuid.New()
+
+	suite.mockUserRepo.On("GetByID", user.ID).Return(user, nil)
+	suite.mockTokenRepo.On("Create", mock.AnythingOfType("*models.APIToken")).Return(tokenID, nil)
+
+	token, plaintext, err := suite.service.CreateToken(user.ID, " CI ", []string{ScopeReadProjects, ScopeWriteSchema, ScopeReadProjects}, nil)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(plaintext, APITokenPrefix))
+	assert.Equal(suite.T(), tokenID, token.ID)
+	assert.Equal(suite.T(), "CI", token.Name)
+	assert.Equal(suite.T(), hashToken(plaintext), token.TokenHash)
+	assert.NotContains(suite.T(), token.TokenHash, plaintext)
+	assert.True(suite.T(), strings.HasPrefix(plaintext, token.Prefix))
+	assert.Equal(suite.T(), []string{ScopeReadProjects, ScopeWriteSchema}, token.ScopeList())
+}
+
+// Test CreateToken - rejects unknown scopes, missing scopes and past expiry
+func (suite *APITokenServiceTestSuite) TestCreateToken_InvalidInput() {
+	past := time.Now().Add(-time.Hour)
+
+	_, _, err := suite.service.CreateToken(uuid.New(), "CI", []string{"admin:everything"}, nil)
+	assert.ErrorIs(suite.T(), err, ErrInvalidScope)
+
+	_, _, err = suite.service.CreateToken(uuid.New(), "CI", nil, nil)
+	assert.ErrorIs(suite.T(), err, ErrInvalidInput)
+
+	_, _, err = suite.service.CreateToken(uuid.New(), "CI", []string{ScopeReadProjects}, &past)
+	assert.ErrorIs(suite.T(), err, ErrInvalidInput)
+
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test AuthenticateToken - active token records its use
+func (suite *APITokenServiceTestSuite) TestAuthenticateToken_Success() {
+	token := &models.APIToken{ID: uuid.New(), UserID: uuid.New(), Scopes: ScopeReadProjects}
+
+	suite.mockTokenRepo.On("GetByHash", hashToken("ezm_valid")).Return(token, nil)
+	suite.mockTokenRepo.On("UpdateLastUsed", token.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	result, err := suite.service.AuthenticateToken("ezm_valid")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), token, result)
+	assert.NotNil(suite.T(), result.LastUsedAt)
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+}
+
+// Test AuthenticateToken - recent use is not written again
+func (suite *APITokenServiceTestSuite) TestAuthenticateToken_ThrottlesLastUsed() {
+	recently := time.Now().Add(-10 * time.Second)
+	token := &models.APIToken{ID: uuid.New(), LastUsedAt: &recently}
+
+	suite.mockTokenRepo.On("GetByHash", hashToken("ezm_valid")).Return(token, nil)
+
+	_, err := suite.service.AuthenticateToken("ezm_valid")
+
+	assert.NoError(suite.T(), err)
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "UpdateLastUsed", mock.Anything, mock.Anything)
+}
+
+// Test AuthenticateToken - revoked, expired, unknown and disabled owners' tokens are rejected
+func (suite *APITokenServiceTestSuite) TestAuthenticateToken_Invalid() {
+	past := time.Now().Add(-time.Minute)
+
+	suite.mockTokenRepo.On("GetByHash", hashToken("ezm_revoked")).Return(&models.APIToken{RevokedAt: &past}, nil)
+	suite.mockTokenRepo.On("GetByHash", hashToken("ezm_expired")).Return(&models.APIToken{ExpiresAt: &past}, nil)
+	suite.mockTokenRepo.On("GetByHash", hashToken("ezm_disabled")).Return(&models.APIToken{User: models.User{DisabledAt: &past}}, nil)
+	suite.mockTokenRepo.On("GetByHash", hashToken("ezm_unknown")).Return(nil, gorm.ErrRecordNotFound)
+
+	for _, plaintext := range []string{"ezm_revoked", "ezm_expired", "ezm_disabled", "ezm_unknown", "not-a-token"} {
+		_, err := suite.service.AuthenticateToken(plaintext)
+		assert.ErrorIs(suite.T(), err, ErrInvalidAPIToken, plaintext)
+	}
+}
+
+// Test RevokeToken - users cannot revoke other users' tokens
+func (suite *APITokenServiceTestSuite) TestRevokeToken_OtherUser() {
+	token := &models.APIToken{ID: uuid.New(), UserID: uuid.New()}
+
+	suite.mockTokenRepo.On("GetByID", token.ID).Return(token, nil)
+
+	err := suite.service.RevokeToken(token.ID, uuid.New())
+
+	assert.ErrorIs(suite.T(), err, ErrAPITokenNotFound)
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "Revoke", mock.Anything, mock.Anything)
+}
+
+// Test RevokeToken - owner revokes their token
+func (suite *APITokenServiceTestSuite) TestRevokeToken_Success() {
+	token := &models.APIToken{ID: uuid.New(), UserID: uuid.New()}
+
+	suite.mockTokenRepo.On("GetByID", token.ID).Return(token, nil)
+	suite.mockTokenRepo.On("Revoke", token.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	err := suite.service.RevokeToken(token.ID, token.UserID)
+
+	assert.NoError(suite.T(), err)
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+}
+
+// Test AdminRevokeToken - missing token and repository errors
+func (suite *APITokenServiceTestSuite) TestAdminRevokeToken_Errors() {
+	missingID := uuid.New()
+	failingID := uuid.New()
+
+	suite.mockTokenRepo.On("GetByID", missingID).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockTokenRepo.On("GetByID", failingID).Return(nil, errors.New("database error"))
+
+	assert.ErrorIs(suite.T(), suite.service.AdminRevokeToken(missingID), ErrAPITokenNotFound)
+	assert.EqualError(suite.T(), suite.service.AdminRevokeToken(failingID), "database error")
+}
@@ -23,6 +23,7 @@ type AuthorizationService struct {
 	fieldRepo        repository.FieldRepositoryInterface
 	relationshipRepo repository.RelationshipRepositoryInterface
 	sessionRepo      repository.CollaborationSessionRepositoryInterface
+	accessCache      *AccessCache
 }
 
 func NewAuthorizationService(
@@ -31,6 +32,7 @@ func NewAuthorizationService(
 	fieldRepo repository.FieldRepositoryInterface,
 	relationshipRepo repository.RelationshipRepositoryInterface,
 	sessionRepo repository.CollaborationSessionRepositoryInterface,
+	accessCache *AccessCache,
 ) *AuthorizationService {
 	return &AuthorizationService{
 		projectRepo:      projectRepo,
@@ -38,65 +40,52 @@ func NewAuthorizationService(
 		fieldRepo:        fieldRepo,
 		relationshipRepo: relationshipRepo,
 		sessionRepo:      sessionRepo,
+		accessCache:      accessCache,
 	}
 }
 
 func (s *AuthorizationService) CanUserAccessProject(userID, projectID uuid.UUID) (bool, error) {
-	project, err := s.projectRepo.GetByID(projectID)
+	members, err := s.members(projectID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return false, ErrProjectNotFound
-		}
 		return false, err
 	}
+	return members.includes(userID), nil
+}
 
-	// Check if user is owner
-	if project.OwnerID == userID {
-		return true, nil
-	}
-
-	// Check if user is collaborator
-	collaboratorProjects, err := s.projectRepo.GetByCollaboratorID(userID)
+// CanUserModifyProject reports whether the user owns the project or
+// collaborates on it as an editor
+func (s *AuthorizationService) CanUserModifyProject(userID, projectID uuid.UUID) (bool, error) {
+	members, err := s.members(projectID)
 	if err != nil {
 		return false, err
 	}
+	return members.canModify(userID), nil
+}
 
-	for _, collabProject := range collaboratorProjects {
-		if collabProject.ID == projectID {
-			return true, nil
-		}
+// members returns who owns and collaborates on the project, from the cache when it can
+func (s *AuthorizationService) members(projectID uuid.UUID) (*projectMembers, error) {
+	if members := s.accessCache.Members(projectID); members != nil {
+		return members, nil
 	}
 
-	return false, nil
-}
-
-func (s *AuthorizationService) CanUserModifyProject(userID, projectID uuid.UUID) (bool, error) {
 	project, err := s.projectRepo.GetByID(projectID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return false, ErrProjectNotFound
+			return nil, ErrProjectNotFound
 		}
-		return false, err
-	}
-
-	// Check if user is owner
-	if project.OwnerID == userID {
-		return true, nil
+		return nil, err
 	}
-
-	// Check if user is collaborator
-	collaboratorProjects, err := s.projectRepo.GetByCollaboratorID(userID)
+	viewerIDs, err := s.projectRepo.GetViewerIDs(projectID)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	for _, collabProject := range collaboratorProjects {
-		if collabProject.ID == projectID {
-			return true, nil
-		}
+	members := &projectMembers{OwnerID: project.OwnerID, ViewerIDs: viewerIDs}
+	for _, collaborator := range project.Collaborators {
+		members.CollaboratorIDs = append(members.CollaboratorIDs, collaborator.ID)
 	}
-
-	return false, nil
+	s.accessCache.SetMembers(projectID, members)
+	return members, nil
 }
 
 func (s *AuthorizationService) CanUserDeleteCollaborationSession(userID, sessionID uuid.UUID) (bool, error) {
@@ -123,6 +112,10 @@ func (s *AuthorizationService) CanUserDeleteCollaborationSession(userID, session
 }
 
 func (s *AuthorizationService) GetProjectIDFromTable(tableID uuid.UUID) (uuid.UUID, error) {
+	if projectID := s.accessCache.ProjectOf(accessKindTable, tableID); projectID != uuid.Nil {
+		return projectID, nil
+	}
+
 	table, err := s.tableRepo.GetByID(tableID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -130,10 +123,15 @@ func (s *AuthorizationService) GetProjectIDFromTable(tableID uuid.UUID) (uuid.UU
 		}
 		return uuid.Nil, err
 	}
+	s.accessCache.SetProjectOf(accessKindTable, tableID, table.ProjectID)
 	return table.ProjectID, nil
 }
 
 func (s *AuthorizationService) GetProjectIDFromRelationship(relationshipID uuid.UUID) (uuid.UUID, error) {
+	if projectID := s.accessCache.ProjectOf(accessKindRelationship, relationshipID); projectID != uuid.Nil {
+		return projectID, nil
+	}
+
 	relationship, err := s.relationshipRepo.GetByID(relationshipID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -141,10 +139,15 @@ func (s *AuthorizationService) GetProjectIDFromRelationship(relationshipID uuid.
 		}
 		return uuid.Nil, err
 	}
+	s.accessCache.SetProjectOf(accessKindRelationship, relationshipID, relationship.ProjectID)
 	return relationship.ProjectID, nil
 }
 
 func (s *AuthorizationService) GetProjectIDFromField(fieldID uuid.UUID) (uuid.UUID, error) {
+	if projectID := s.accessCache.ProjectOf(accessKindField, fieldID); projectID != uuid.Nil {
+		return projectID, nil
+	}
+
 	field, err := s.fieldRepo.GetByID(fieldID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -154,5 +157,10 @@ func (s *AuthorizationService) GetProjectIDFromField(fieldID uuid.UUID) (uuid.UU
 	}
 
 	// Get table to find project ID
-	return s.GetProjectIDFromTable(field.TableID)
+	projectID, err := s.GetProjectIDFromTable(field.TableID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	s.accessCache.SetProjectOf(accessKindField, fieldID, projectID)
+	return projectID, nil
 }
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/cache"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type AuthorizationServiceTestSuite struct {
+	suite.Suite
+	mockProjectRepo *mockRepo.MockProjectRepository
+	mockTableRepo   *mockRepo.MockTableRepository
+	mockFieldRepo   *mockRepo.MockFieldRepository
+	accessCache     *AccessCache
+	service         *AuthorizationService
+}
+
+func (suite *AuthorizationServiceTestSuite) SetupTest() {
+	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
+	suite.mockTableRepo = new(mockRepo.MockTableRepository)
+	suite.mockFieldRepo = new(mockRepo.MockFieldRepository)
+	suite.accessCache = NewAccessCache(cache.NewMemoryStore(), time.Minute)
+	suite.service = NewAuthorizationService(suite.mockProjectRepo, suite.mockTableRepo, suite.mockFieldRepo, nil, nil, suite.accessCache)
+}
+
+func TestAuthorizationServiceSuite(t *testing.T) {
+	suite.Run(t, new(AuthorizationServiceTestSuite))
+}
+
+func (suite *AuthorizationServiceTestSuite) TestCanUserAccessProject_Members() {
+	ownerID, collaboratorID := uuid.New(), uuid.New()
+	project := &models.Project{ID: uuid.New(), OwnerID: ownerID, Collaborators: []models.User{{ID: collaboratorID}}}
+
+	// Loaded once, then checked against the cached members
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil).Once()
+	suite.mockProjectRepo.On("GetViewerIDs", project.ID).Return(nil, nil).Once()
+
+	for userID, expected := range map[uuid.UUID]bool{ownerID: true, collaboratorID: true, uuid.New(): false} {
+		canAccess, err := suite.service.CanUserAccessProject(userID, project.ID)
+		suite.NoError(err)
+		suite.Equal(expected, canAccess)
+
+		canModify, err := suite.service.CanUserModifyProject(userID, project.ID)
+		suite.NoError(err)
+		suite.Equal(expected, canModify)
+	}
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *AuthorizationServiceTestSuite) TestCanUserAccessProject_InvalidatedMembers() {
+	collaboratorID := uuid.New()
+	project := &models.Project{ID: uuid.New(), OwnerID: uuid.New(), Collaborators: []models.User{{ID: collaboratorID}}}
+	removed := &models.Project{ID: project.ID, OwnerID: project.OwnerID}
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil).Once()
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(removed, nil).Once()
+	suite.mockProjectRepo.On("GetViewerIDs", project.ID).Return(nil, nil).Twice()
+
+	canAccess, err := suite.service.CanUserAccessProject(collaboratorID, project.ID)
+	suite.NoError(err)
+	suite.True(canAccess)
+
+	suite.accessCache.InvalidateMembers(project.ID)
+
+	canAccess, err = suite.service.CanUserAccessProject(collaboratorID, project.ID)
+	suite.NoError(err)
+	suite.False(canAccess)
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *AuthorizationServiceTestSuite) TestCanUserModifyProject_Viewer() {
+	viewerID := uuid.New()
+	project := &models.Project{ID: uuid.New(), OwnerID: uuid.New(), Collaborators: []models.User{{ID: viewerID}}}
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil).Once()
+	suite.mockProjectRepo.On("GetViewerIDs", project.ID).Return([]uuid.UUID{viewerID}, nil).Once()
+
+	canAccess, err := suite.service.CanUserAccessProject(viewerID, project.ID)
+	suite.NoError(err)
+	suite.True(canAccess)
+
+	canModify, err := suite.service.CanUserModifyProject(viewerID, project.ID)
+	suite.NoError(err)
+	suite.False(canModify)
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+func (suite *AuthorizationServiceTestSuite) TestCanUserAccessProject_NotFound() {
+	projectID := uuid.New()
+	suite.mockProjectRepo.On("GetByID", projectID).Return(nil, gorm.ErrRecordNotFound)
+
+	canAccess, err := suite.service.CanUserAccessProject(uuid.New(), projectID)
+
+	suite.Equal(ErrProjectNotFound, err)
+	suite.False(canAccess)
+}
+
+func (suite *AuthorizationServiceTestSuite) TestGetProjectIDFromField_Cached() {
+	projectID := uuid.New()
+	table := &models.Table{ID: uuid.New(), ProjectID: projectID}
+	field := &models.Field{ID: uuid.New(), TableID: table.ID}
+
+	suite.mockFieldRepo.On("GetByID", field.ID).Return(field, nil).Once()
+	suite.mockTableRepo.On("GetByID", table.ID).Return(table, nil).Once()
+
+	for range 2 {
+		result, err := suite.service.GetProjectIDFromField(field.ID)
+		suite.NoError(err)
+		suite.Equal(projectID, result)
+	}
+
+	// The table lookup was cached on the way
+	result, err := suite.service.GetProjectIDFromTable(table.ID)
+	suite.NoError(err)
+	suite.Equal(projectID, result)
+
+	suite.mockFieldRepo.AssertExpectations(suite.T())
+	suite.mockTableRepo.AssertExpectations(suite.T())
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"log"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/storage"
+	"github.com/google/uuid"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"gorm.io/gorm"
+)
+
+// maxAvatarPixels bounds the decoded size of an upload, so a small file
+// cannot expand into a huge image in memory
+const maxAvatarPixels = 4096 * 4096
+
+// AvatarService stores profile pictures. Uploads are cropped to a square and
+// resized, so collaborator cursors and lists can show them at a fixed size.
+type AvatarService struct {
+	userRepo repository.UserRepositoryInterface
+	store    storage.Store
+	maxBytes int64
+	size     int
+}
+
+func NewAvatarService(cfg *config.Config, userRepo repository.UserRepositoryInterface, store storage.Store) *AvatarService {
+	return &AvatarService{
+		userRepo: userRepo,
+		store:    store,
+		maxBytes: cfg.Avatars.MaxBytes,
+		size:     cfg.Avatars.Size,
+	}
+}
+
+// MaxBytes is the largest upload UploadAvatar accepts
+func (s *AvatarService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// UploadAvatar replaces the avatar of the user with a PNG, JPEG, GIF or WebP image.
+// Every upload gets a new key, so its URL can be cached forever.
+func (s *AvatarService) UploadAvatar(ctx context.Context, userID uuid.UUID, data []byte) (*models.User, error) {
+	if int64(len(data)) > s.maxBytes {
+		return nil, ErrImageTooLarge
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	avatar, err := s.resize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s%s/%s.png", storage.PublicPrefix, userID, uuid.New())
+	if err := s.store.Put(ctx, key, "image/png", avatar); err != nil {
+		return nil, err
+	}
+
+	return s.setAvatar(ctx, user, key, s.store.URL(key))
+}
+
+// DeleteAvatar removes the avatar of the user
+func (s *AvatarService) DeleteAvatar(ctx context.Context, userID uuid.UUID) (*models.User, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.AvatarKey == "" && user.AvatarURL == "" {
+		return user, nil
+	}
+	return s.setAvatar(ctx, user, "", "")
+}
+
+func (s *AvatarService) getUser(userID uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// setAvatar saves the new avatar and then deletes the file of the old one. A
+// file that cannot be deleted is only logged, as the user no longer links to it.
+func (s *AvatarService) setAvatar(ctx context.Context, user *models.User, key, url string) (*models.User, error) {
+	oldKey := user.AvatarKey
+	user.AvatarKey = key
+	user.AvatarURL = url
+	if err := s.userRepo.Update(user); err != nil {
+		if key != "" {
+			_ = s.store.Delete(ctx, key)
+		}
+		return nil, err
+	}
+
+	if oldKey != "" {
+		if err := s.store.Delete(ctx, oldKey); err != nil {
+			log.Printf("Failed to delete old avatar %s: %v", oldKey, err)
+			errorreport.Capture(ctx, err, errorreport.UserTag(user.ID))
+		}
+	}
+	return user, nil
+}
+
+// resize decodes the upload, crops the largest centered square and scales it
+// to the configured size
+func (s *AvatarService) resize(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxAvatarPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+	crop := image.Rect(x, y, x+side, y+side)
+
+	size := min(s.size, side)
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AvatarServiceTestSuite struct {
+	suite.Suite
+	mockUserRepo *mockRepo.MockUserRepository
+	dir          string
+	service      *AvatarService
+	user         *models.User
+}
+
+func (suite *AvatarServiceTestSuite) SetupTest() {
+	suite.mockUserRepo = new(mockRepo.MockUserRepository)
+	suite.dir = suite.T().TempDir()
+	store, err := storage.NewLocalStore(suite.dir, "https://cdn.example.com")
+	suite.Require().NoError(err)
+
+	cfg := &config.Config{}
+	cfg.Avatars.MaxBytes = 1024 * 1024
+	cfg.Avatars.Size = 64
+	suite.service = NewAvatarService(cfg, suite.mockUserRepo, store)
+	suite.user = &models.User{ID: uuid.New(), Username: "alice"}
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+}
+
+func TestAvatarServiceSuite(t *testing.T) {
+	suite.Run(t, new(AvatarServiceTestSuite))
+}
+
+func testImage(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// Test UploadAvatar - Crops to a square, resizes and replaces the old avatar
+func (suite *AvatarServiceTestSuite) TestUploadAvatar_Success() {
+	suite.mockUserRepo.On("Update", mock.AnythingOfType("*models.User")).Return(nil)
+	old := filepath.Join(suite.dir, "avatars", "old.png")
+	suite.Require().NoError(os.MkdirAll(filepath.Dir(old), 0o755))
+	suite.Require().NoError(os.WriteFile(old, []byte("old"), 0o644))
+	suite.user.AvatarKey = "avatars/old.png"
+
+	user, err := suite.service.UploadAvatar(context.Background(), suite.user.ID, testImage(300, 150))
+
+	suite.Require().NoError(err)
+	assert.True(suite.T(), strings.HasPrefix(user.AvatarKey, "avatars/"+suite.user.ID.String()+"/"))
+	assert.Equal(suite.T(), "https://cdn.example.com/"+user.AvatarKey, user.AvatarURL)
+
+	data, err := os.ReadFile(filepath.Join(suite.dir, filepath.FromSlash(user.AvatarKey)))
+	suite.Require().NoError(err)
+	stored, err := png.DecodeConfig(bytes.NewReader(data))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 64, stored.Width)
+	assert.Equal(suite.T(), 64, stored.Height)
+
+	_, err = os.Stat(old)
+	assert.True(suite.T(), os.IsNotExist(err))
+}
+
+// Test UploadAvatar - Files that are not images are rejected
+func (suite *AvatarServiceTestSuite) TestUploadAvatar_InvalidImage() {
+	user, err := suite.service.UploadAvatar(context.Background(), suite.user.ID, []byte("<svg></svg>"))
+
+	assert.ErrorIs(suite.T(), err, ErrInvalidImage)
+	assert.Nil(suite.T(), user)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+// Test UploadAvatar - Uploads over the configured size are rejected
+func (suite *AvatarServiceTestSuite) TestUploadAvatar_TooLarge() {
+	_, err := suite.service.UploadAvatar(context.Background(), suite.user.ID, make([]byte, 1024*1024+1))
+
+	assert.ErrorIs(suite.T(), err, ErrImageTooLarge)
+}
+
+// Test DeleteAvatar - Clears the URL and deletes the file
+func (suite *AvatarServiceTestSuite) TestDeleteAvatar_Success() {
+	suite.mockUserRepo.On("Update", mock.MatchedBy(func(u *models.User) bool {
+		return u.AvatarURL == "" && u.AvatarKey == ""
+	})).Return(nil)
+	file := filepath.Join(suite.dir, "avatars", "current.png")
+	suite.Require().NoError(os.MkdirAll(filepath.Dir(file), 0o755))
+	suite.Require().NoError(os.WriteFile(file, []byte("avatar"), 0o644))
+	suite.user.AvatarKey = "avatars/current.png"
+	suite.user.AvatarURL = "https://cdn.example.com/avatars/current.png"
+
+	user, err := suite.service.DeleteAvatar(context.Background(), suite.user.ID)
+
+	suite.Require().NoError(err)
+	assert.Empty(suite.T(), user.AvatarURL)
+	_, err = os.Stat(file)
+	assert.True(suite.T(), os.IsNotExist(err))
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/billing"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IDs of the plans
+const (
+	PlanFree = "free"
+	PlanPro  = "pro"
+	PlanTeam = "team"
+)
+
+// Plan is a set of quotas users can subscribe to
+type Plan struct {
+	ID      string
+	Name    string
+	PriceID string // Price of the subscription with the payment provider, empty for the free plan
+	Quotas  Quotas
+}
+
+// CurrentPlan is the plan a user is on and the state of their subscription to it
+type CurrentPlan struct {
+	Plan             Plan
+	Status           string // Of the subscription, empty on the free plan
+	CurrentPeriodEnd *time.Time
+}
+
+// BillingService sells plans with larger quotas through Stripe and applies the
+// quotas of the plan a user pays for. Lowering the quotas, e.g. when a
+// subscription ends, keeps what is already there but blocks adding more.
+type BillingService struct {
+	subscriptionRepo repository.SubscriptionRepositoryInterface
+	userRepo         repository.UserRepositoryInterface
+	provider         billing.Provider
+	plans            []Plan // The free plan first
+	successURL       string
+	cancelURL        string
+}
+
+// NewBillingService offers the free plan with freeQuotas, and the paid plans of the config that have a price
+func NewBillingService(cfg *config.Config, subscriptionRepo repository.SubscriptionRepositoryInterface, userRepo repository.UserRepositoryInterface, provider billing.Provider, freeQuotas Quotas) *BillingService {
+	plans := []Plan{{ID: PlanFree, Name: "Free", Quotas: freeQuotas}}
+	for _, paid := range []struct {
+		id, name string
+		plan     config.BillingPlan
+	}{
+		{PlanPro, "Pro", cfg.Billing.Pro},
+		{PlanTeam, "Team", cfg.Billing.Team},
+	} {
+		if paid.plan.PriceID == "" {
+			continue
+		}
+		plans = append(plans, Plan{ID: paid.id, Name: paid.name, PriceID: paid.plan.PriceID, Quotas: Quotas{
+			MaxProjectsPerUser:  paid.plan.MaxProjectsPerUser,
+			MaxTablesPerProject: paid.plan.MaxTablesPerProject,
+			MaxCollaborators:    paid.plan.MaxCollaborators,
+		}})
+	}
+
+	return &BillingService{
+		subscriptionRepo: subscriptionRepo,
+		userRepo:         userRepo,
+		provider:         provider,
+		plans:            plans,
+		successURL:       cfg.Billing.SuccessURL,
+		cancelURL:        cfg.Billing.CancelURL,
+	}
+}
+
+// Plans returns the plans on offer, the free plan first
+func (s *BillingService) Plans() []Plan {
+	return s.plans
+}
+
+// GetCurrentPlan returns the plan the user is on, the free plan when they do not pay for one
+func (s *BillingService) GetCurrentPlan(userID uuid.UUID) (*CurrentPlan, error) {
+	subscription, err := s.getSubscription(userID)
+	if err != nil {
+		return nil, err
+	}
+	current := &CurrentPlan{Plan: s.planOf(subscription)}
+	if subscription != nil {
+		current.Status = subscription.Status
+		current.CurrentPeriodEnd = subscription.CurrentPeriodEnd
+	}
+	return current, nil
+}
+
+// QuotasFor implements QuotaPolicy with the quotas of the owner's plan
+func (s *BillingService) QuotasFor(ownerID uuid.UUID) (Quotas, error) {
+	subscription, err := s.getSubscription(ownerID)
+	if err != nil {
+		return Quotas{}, err
+	}
+	return s.planOf(subscription).Quotas, nil
+}
+
+// CreateCheckout starts buying a paid plan and returns the URL of the payment page
+func (s *BillingService) CreateCheckout(ctx context.Context, userID uuid.UUID, planID string) (string, error) {
+	plan, ok := s.plan(planID)
+	if !ok || plan.PriceID == "" {
+		return "", ErrPlanNotFound
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+	subscription, err := s.getSubscription(userID)
+	if err != nil {
+		return "", err
+	}
+	// Changing a running subscription is left to the provider's customer portal
+	if subscription != nil && subscription.IsActive() {
+		return "", ErrAlreadySubscribed
+	}
+
+	params := &billing.CheckoutParams{
+		PriceID:           plan.PriceID,
+		CustomerEmail:     user.Email,
+		ClientReferenceID: userID.String(),
+		SuccessURL:        s.successURL,
+		CancelURL:         s.cancelURL,
+		Metadata:          map[string]string{"user_id": userID.String(), "plan": plan.ID},
+	}
+	if subscription != nil {
+		params.CustomerID = subscription.StripeCustomerID
+	}
+	session, err := s.provider.CreateCheckoutSession(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	return session.URL, nil
+}
+
+// HandleWebhook applies an event of the payment provider to the subscription
+// it is about. Events of other types are ignored.
+func (s *BillingService) HandleWebhook(payload []byte, signature string) error {
+	event, err := s.provider.ParseWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, billing.ErrInvalidSignature) {
+			return ErrInvalidWebhook
+		}
+		return err
+	}
+
+	switch event.Type {
+	case billing.EventCheckoutCompleted:
+		session, err := event.CheckoutSession()
+		if err != nil {
+			return errors.Join(ErrInvalidWebhook, err)
+		}
+		return s.applyEvent(event, session.ClientReferenceID, session.Customer, func(subscription *models.Subscription) bool {
+			if subscription.StripeSubscriptionID != session.Subscription {
+				subscription.Status = "active" // A new subscription, until its own events tell more
+			}
+			subscription.StripeSubscriptionID = session.Subscription
+			if plan, ok := s.plan(session.Metadata["plan"]); ok {
+				subscription.Plan = plan.ID
+			}
+			return true
+		})
+	case billing.EventSubscriptionCreated, billing.EventSubscriptionUpdated, billing.EventSubscriptionDeleted:
+		stripeSubscription, err := event.Subscription()
+		if err != nil {
+			return errors.Join(ErrInvalidWebhook, err)
+		}
+		return s.applyEvent(event, stripeSubscription.Metadata["user_id"], stripeSubscription.Customer, func(subscription *models.Subscription) bool {
+			// Late events of a replaced subscription must not end the one running now
+			if subscription.StripeSubscriptionID != stripeSubscription.ID && subscription.StripeSubscriptionID != "" && subscription.IsActive() {
+				return false
+			}
+			subscription.StripeSubscriptionID = stripeSubscription.ID
+			subscription.Status = stripeSubscription.Status
+			if plan, ok := s.planByPrice(stripeSubscription.PriceID()); ok {
+				subscription.Plan = plan.ID
+			}
+			if end := stripeSubscription.PeriodEnd(); end != 0 {
+				periodEnd := time.Unix(end, 0).UTC()
+				subscription.CurrentPeriodEnd = &periodEnd
+			}
+			return true
+		})
+	default:
+		return nil
+	}
+}
+
+// applyEvent updates the subscription of the user with the ID, or else of the
+// customer, unless a newer event has been applied to it already. The
+// subscription is saved when apply returns true.
+func (s *BillingService) applyEvent(event *billing.Event, userID, customerID string, apply func(*models.Subscription) bool) error {
+	var subscription *models.Subscription
+	if id, err := uuid.Parse(userID); err == nil {
+		subscription, err = s.getSubscription(id)
+		if err != nil {
+			return err
+		}
+		if subscription == nil {
+			subscription = &models.Subscription{UserID: id, Plan: PlanFree}
+		}
+	} else if customerID != "" {
+		subscription, err = s.subscriptionRepo.GetByStripeCustomerID(customerID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	if subscription == nil {
+		log.Printf("Billing: ignoring %s event %s for an unknown customer", event.Type, event.ID)
+		return nil
+	}
+
+	createdAt := time.Unix(event.Created, 0).UTC()
+	if createdAt.Before(subscription.EventCreatedAt) {
+		return nil
+	}
+	if !apply(subscription) {
+		return nil
+	}
+	subscription.EventCreatedAt = createdAt
+	if customerID != "" {
+		subscription.StripeCustomerID = customerID
+	}
+	return s.subscriptionRepo.Save(subscription)
+}
+
+// getSubscription returns nil for users who never subscribed
+func (s *BillingService) getSubscription(userID uuid.UUID) (*models.Subscription, error) {
+	subscription, err := s.subscriptionRepo.GetByUserID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// planOf returns the plan a subscription grants, the free plan when it is not
+// active or its plan is no longer offered
+func (s *BillingService) planOf(subscription *models.Subscription) Plan {
+	if subscription != nil && subscription.IsActive() {
+		if plan, ok := s.plan(subscription.Plan); ok {
+			return plan
+		}
+	}
+	return s.plans[0]
+}
+
+func (s *BillingService) plan(id string) (Plan, bool) {
+	for _, plan := range s.plans {
+		if plan.ID == id {
+			return plan, true
+		}
+	}
+	return Plan{}, false
+}
+
+func (s *BillingService) planByPrice(priceID string) (Plan, bool) {
+	for _, plan := range s.plans {
+		if priceID != "" && plan.PriceID == priceID {
+			return plan, true
+		}
+	}
+	return Plan{}, false
+}
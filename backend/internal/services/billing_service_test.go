@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/billing"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// mockBillingProvider stands in for Stripe
+type mockBillingProvider struct {
+	mock.Mock
+}
+
+func (m *mockBillingProvider) CreateCheckoutSession(ctx context.Context, params *billing.CheckoutParams) (*billing.CheckoutSession, error) {
+	args := m.Called(params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*billing.CheckoutSession), args.Error(1)
+}
+
+func (m *mockBillingProvider) ParseWebhook(payload []byte, signature string) (*billing.Event, error) {
+	args := m.Called(payload, signature)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*billing.Event), args.Error(1)
+}
+
+type BillingServiceTestSuite struct {
+	suite.Suite
+	mockSubscriptionRepo *mockRepo.MockSubscriptionRepository
+	mockUserRepo         *mockRepo.MockUserRepository
+	mockProvider         *mockBillingProvider
+	service              *BillingService
+	userID               uuid.UUID
+}
+
+func (suite *BillingServiceTestSuite) SetupTest() {
+	suite.mockSubscriptionRepo = new(mockRepo.MockSubscriptionRepository)
+	suite.mockUserRepo = new(mockRepo.MockUserRepository)
+	suite.mockProvider = new(mockBillingProvider)
+
+	cfg := &config.Config{}
+	cfg.Billing.Pro = config.BillingPlan{PriceID: "price_pro", MaxProjectsPerUser: 50}
+	suite.service = NewBillingService(cfg, suite.mockSubscriptionRepo, suite.mockUserRepo, suite.mockProvider, Quotas{MaxProjectsPerUser: 3})
+	suite.userID = uuid.New()
+}
+
+func TestBillingServiceSuite(t *testing.T) {
+	suite.Run(t, new(BillingServiceTestSuite))
+}
+
+// webhookEvent returns an event about object, created at the Unix time
+func webhookEvent(eventType string, created int64, object any) *billing.Event {
+	event := &billing.Event{ID: "evt_" + eventType, Type: eventType, Created: created}
+	event.Data.Object, _ = json.Marshal(object)
+	return event
+}
+
+// Test Plans - Paid plans without a price are not offered
+func (suite *BillingServiceTestSuite) TestPlans() {
+	plans := suite.service.Plans()
+
+	suite.Len(plans, 2)
+	suite.Equal(PlanFree, plans[0].ID)
+	suite.Equal(PlanPro, plans[1].ID)
+}
+
+// Test QuotasFor - Free plan without a subscription
+func (suite *BillingServiceTestSuite) TestQuotasFor_Free() {
+	suite.mockSubscriptionRepo.On("GetByUserID", suite.userID).Return(nil, gorm.ErrRecordNotFound)
+
+	quotas, err := suite.service.QuotasFor(suite.userID)
+
+	suite.NoError(err)
+	suite.Equal(3, quotas.MaxProjectsPerUser)
+}
+
+// Test QuotasFor - Quotas of the plan while the subscription is active
+func (suite *BillingServiceTestSuite) TestQuotasFor_Subscribed() {
+	suite.mockSubscriptionRepo.On("GetByUserID", suite.userID).Return(&models.Subscription{UserID: suite.userID, Plan: PlanPro, Status: "past_due"}, nil)
+
+	quotas, err := suite.service.QuotasFor(suite.userID)
+
+	suite.NoError(err)
+	suite.Equal(50, quotas.MaxProjectsPerUser)
+}
+
+// Test QuotasFor - Back to the free plan once the subscription is canceled
+func (suite *BillingServiceTestSuite) TestQuotasFor_Canceled() {
+	suite.mockSubscriptionRepo.On("GetByUserID", suite.userID).Return(&models.Subscription{UserID: suite.userID, Plan: PlanPro, Status: "canceled"}, nil)
+
+	quotas, err := suite.service.QuotasFor(suite.userID)
+
+	suite.NoError(err)
+	suite.Equal(3, quotas.MaxProjectsPerUser)
+}
+
+// Test CreateCheckout - Returning customers keep their Stripe customer
+func (suite *BillingServiceTestSuite) TestCreateCheckout_Success() {
+	user := &models.User{ID: suite.userID, Email: "ada@example.com"}
+	suite.mockUserRepo.On("GetByID", suite.userID).Return(user, nil)
+	suite.mockSubscriptionRepo.On("GetByUserID", suite.userID).Return(&models.Subscription{
+		UserID: suite.userID, Plan: PlanPro, Status: "canceled", StripeCustomerID: "cus_1",
+	}, nil)
+	suite.mockProvider.On("CreateCheckoutSession", mock.MatchedBy(func(params *billing.CheckoutParams) bool {
+		return params.PriceID == "price_pro" && params.CustomerID == "cus_1" &&
+			params.ClientReferenceID == suite.userID.String() && params.Metadata["plan"] == PlanPro
+	})).Return(&billing.CheckoutSession{URL: "https://checkout.stripe.com/c/pay/cs_1"}, nil)
+
+	url, err := suite.service.CreateCheckout(context.Background(), suite.userID, PlanPro)
+
+	suite.NoError(err)
+	suite.Equal("https://checkout.stripe.com/c/pay/cs_1", url)
+}
+
+// Test CreateCheckout - The free plan cannot be bought
+func (suite *BillingServiceTestSuite) TestCreateCheckout_FreePlan() {
+	_, err := suite.service.CreateCheckout(context.Background(), suite.userID, PlanFree)
+
+	suite.ErrorIs(err, ErrPlanNotFound)
+}
+
+// Test CreateCheckout - No second subscription while one is running
+func (suite *BillingServiceTestSuite) TestCreateCheckout_AlreadySubscribed() {
+	suite.mockUserRepo.On("GetByID", suite.userID).Return(&models.User{ID: suite.userID}, nil)
+	suite.mockSubscriptionRepo.On("GetByUserID", suite.userID).Return(&models.Subscription{UserID: suite.userID, Plan: PlanPro, Status: "active"}, nil)
+
+	_, err := suite.service.CreateCheckout(context.Background(), suite.userID, PlanPro)
+
+	suite.ErrorIs(err, ErrAlreadySubscribed)
+	suite.mockProvider.AssertNotCalled(suite.T(), "CreateCheckoutSession", mock.Anything)
+}
+
+// Test HandleWebhook - A completed checkout subscribes the buyer
+func (suite *BillingServiceTestSuite) TestHandleWebhook_CheckoutCompleted() {
+	event := webhookEvent(billing.EventCheckoutCompleted, 1_700_000_000, billing.CheckoutSession{
+		Customer: "cus_1", Subscription: "sub_1", ClientReferenceID: suite.userID.String(), Metadata: map[string]string{"plan": PlanPro},
+	})
+	suite.mockProvider.On("ParseWebhook", []byte("payload"), "signature").Return(event, nil)
+	suite.mockSubscriptionRepo.On("GetByUserID", suite.userID).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockSubscriptionRepo.On("Save", mock.MatchedBy(func(subscription *models.Subscription) bool {
+		return subscription.UserID == suite.userID && subscription.Plan == PlanPro && subscription.Status == "active" &&
+			subscription.StripeCustomerID == "cus_1" && subscription.StripeSubscriptionID == "sub_1"
+	})).Return(nil)
+
+	err := suite.service.HandleWebhook([]byte("payload"), "signature")
+
+	suite.NoError(err)
+	suite.mockSubscriptionRepo.AssertExpectations(suite.T())
+}
+
+// Test HandleWebhook - Subscription changes are found by customer without metadata
+func (suite *BillingServiceTestSuite) TestHandleWebhook_SubscriptionDeleted() {
+	stored := &models.Subscription{UserID: suite.userID, Plan: PlanPro, Status: "active", StripeCustomerID: "cus_1",
+		StripeSubscriptionID: "sub_1", EventCreatedAt: time.Unix(1_700_000_000, 0)}
+	event := webhookEvent(billing.EventSubscriptionDeleted, 1_700_000_100, map[string]any{
+		"id": "sub_1", "customer": "cus_1", "status": "canceled",
+	})
+	suite.mockProvider.On("ParseWebhook", mock.Anything, mock.Anything).Return(event, nil)
+	suite.mockSubscriptionRepo.On("GetByStripeCustomerID", "cus_1").Return(stored, nil)
+	suite.mockSubscriptionRepo.On("Save", mock.MatchedBy(func(subscription *models.Subscription) bool {
+		return subscription.Status == "canceled"
+	})).Return(nil)
+
+	err := suite.service.HandleWebhook([]byte("payload"), "signature")
+
+	suite.NoError(err)
+	suite.mockSubscriptionRepo.AssertExpectations(suite.T())
+}
+
+// Test HandleWebhook - Events older than the last one applied are ignored
+func (suite *BillingServiceTestSuite) TestHandleWebhook_StaleEvent() {
+	stored := &models.Subscription{UserID: suite.userID, Plan: PlanPro, Status: "canceled", StripeCustomerID: "cus_1",
+		StripeSubscriptionID: "sub_1", EventCreatedAt: time.Unix(1_700_000_100, 0)}
+	event := webhookEvent(billing.EventSubscriptionUpdated, 1_700_000_000, map[string]any{
+		"id": "sub_1", "customer": "cus_1", "status": "active", "metadata": map[string]string{"user_id": suite.userID.String()},
+	})
+	suite.mockProvider.On("ParseWebhook", mock.Anything, mock.Anything).Return(event, nil)
+	suite.mockSubscriptionRepo.On("GetByUserID", suite.userID).Return(stored, nil)
+
+	err := suite.service.HandleWebhook([]byte("payload"), "signature")
+
+	suite.NoError(err)
+	suite.mockSubscriptionRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
+
+// Test HandleWebhook - Bad signatures are reported as invalid webhooks
+func (suite *BillingServiceTestSuite) TestHandleWebhook_InvalidSignature() {
+	suite.mockProvider.On("ParseWebhook", mock.Anything, mock.Anything).Return(nil, billing.ErrInvalidSignature)
+
+	err := suite.service.HandleWebhook([]byte("payload"), "signature")
+
+	suite.ErrorIs(err, ErrInvalidWebhook)
+}
@@ -0,0 +1,646 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MergeConflictError lists what a project and a branch both changed
+// differently since the branch was made
+type MergeConflictError struct {
+	Conflicts []schemadiff.Conflict
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("%d conflicts with the project", len(e.Conflicts))
+}
+
+func (e *MergeConflictError) Is(target error) bool {
+	return target == ErrMergeConflict
+}
+
+// BranchService keeps branches of a project's schema: copies edited apart
+// from the project, e.g. to try a change out, then merged back. A merge
+// applies the changes made on the branch since it was made to the project as
+// it is now, failing with a *MergeConflictError when the project changed the
+// same tables, fields or relationships differently. Like a diff, a merge
+// looks at structure; layout and metadata are only taken for what the branch
+// adds. Anyone with access to a project reads its branches; those who may
+// modify it make, edit and merge them.
+type BranchService struct {
+	branchRepo           repository.BranchRepositoryInterface
+	authService          AuthorizationServiceInterface
+	collaborationService CollaborationSessionServiceInterface
+	unitOfWork           *UnitOfWork
+	quotas               QuotaPolicy
+}
+
+func NewBranchService(branchRepo repository.BranchRepositoryInterface, authService AuthorizationServiceInterface, collaborationService CollaborationSessionServiceInterface, unitOfWork *UnitOfWork, quotas QuotaPolicy) *BranchService {
+	return &BranchService{
+		branchRepo:           branchRepo,
+		authService:          authService,
+		collaborationService: collaborationService,
+		unitOfWork:           unitOfWork,
+		quotas:               quotas,
+	}
+}
+
+// CreateBranch copies the project's schema as it is now into a new branch
+func (s *BranchService) CreateBranch(projectID uuid.UUID, req *dto.CreateBranchRequest, userID uuid.UUID) (*models.Branch, error) {
+	name := strings.TrimSpace(req.Name)
+	if len(name) < 1 || len(name) > 255 {
+		return nil, ErrInvalidInput
+	}
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	branch := &models.Branch{ProjectID: projectID, Name: name, CreatedBy: userID}
+	err := s.unitOfWork.Run(func(tx *Tx) error {
+		project, err := tx.Projects.GetByID(projectID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProjectNotFound
+			}
+			return err
+		}
+		if err := checkBranchNameFree(tx.Branches, projectID, uuid.Nil, name); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(schemaRequestOf(project))
+		if err != nil {
+			return err
+		}
+		branch.Base = string(data)
+		branch.Schema = string(data)
+		branch.ID, err = tx.Branches.Create(branch)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return branch, nil
+}
+
+// GetBranches returns the project's branches by name, without their schemas
+func (s *BranchService) GetBranches(projectID, userID uuid.UUID) ([]*models.Branch, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.branchRepo.GetByProjectID(projectID)
+}
+
+// GetBranch returns a branch of the project, ErrBranchNotFound for a branch of another
+func (s *BranchService) GetBranch(projectID, id, userID uuid.UUID) (*models.Branch, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	return projectBranch(s.branchRepo, projectID, id)
+}
+
+// UpdateBranch renames a branch or replaces its schema, checked like a
+// createSchema request. Given a version other than the branch's, or when
+// another change is saved first, it returns the branch with
+// ErrVersionConflict. Merged branches fail with ErrBranchMerged.
+func (s *BranchService) UpdateBranch(projectID, id uuid.UUID, req *dto.UpdateBranchRequest, userID uuid.UUID) (*models.Branch, error) {
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return nil, err
+	}
+	branch, err := projectBranch(s.branchRepo, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	if branch.MergedAt != nil {
+		return nil, ErrBranchMerged
+	}
+	if req.Version != nil && *req.Version != branch.Version {
+		return branch, ErrVersionConflict
+	}
+
+	// Only update fields that were provided
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if len(name) < 1 || len(name) > 255 {
+			return nil, ErrInvalidInput
+		}
+		if name != branch.Name {
+			if err := checkBranchNameFree(s.branchRepo, projectID, id, name); err != nil {
+				return nil, err
+			}
+		}
+		branch.Name = name
+	}
+	if req.Schema != nil {
+		schema, err := normalizeBranchSchema(projectID, req.Schema)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(schema)
+		if err != nil {
+			return nil, err
+		}
+		branch.Schema = string(data)
+	}
+
+	if err := s.branchRepo.Update(branch); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			current, err := projectBranch(s.branchRepo, projectID, id)
+			if err != nil {
+				return nil, err
+			}
+			return current, ErrVersionConflict
+		}
+		return nil, err
+	}
+	return branch, nil
+}
+
+func (s *BranchService) DeleteBranch(projectID, id, userID uuid.UUID) error {
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return err
+	}
+	if _, err := projectBranch(s.branchRepo, projectID, id); err != nil {
+		return err
+	}
+	return s.branchRepo.Delete(id)
+}
+
+// DiffBranch returns what changes from the project's schema as it is now to
+// the branch's
+func (s *BranchService) DiffBranch(projectID, id, userID uuid.UUID) (*schemadiff.Diff, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	var diff *schemadiff.Diff
+	err := s.unitOfWork.Run(func(tx *Tx) error {
+		branch, err := projectBranch(tx.Branches, projectID, id)
+		if err != nil {
+			return err
+		}
+		theirs, err := branchSchema(branch.Schema)
+		if err != nil {
+			return err
+		}
+		ours, err := readDiffSchema(tx, projectID)
+		if err != nil {
+			return err
+		}
+		diff = schemadiff.Compare(ours, requestDiffSchema(theirs))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// MergeBranch applies the changes made on the branch since it was made to the
+// project in one transaction, notifying collaborators of each, and marks the
+// branch merged. It returns the merged branch and what changed in the project.
+func (s *BranchService) MergeBranch(projectID, id, userID uuid.UUID) (*models.Branch, *schemadiff.Diff, error) {
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return nil, nil, err
+	}
+
+	var branch *models.Branch
+	var diff *schemadiff.Diff
+	err := s.unitOfWork.Run(func(tx *Tx) error {
+		var err error
+		branch, err = projectBranch(tx.Branches, projectID, id)
+		if err != nil {
+			return err
+		}
+		if branch.MergedAt != nil {
+			return ErrBranchMerged
+		}
+		base, err := branchSchema(branch.Base)
+		if err != nil {
+			return err
+		}
+		theirs, err := branchSchema(branch.Schema)
+		if err != nil {
+			return err
+		}
+		ours, err := readDiffSchema(tx, projectID)
+		if err != nil {
+			return err
+		}
+
+		merged, conflicts := schemadiff.Merge(requestDiffSchema(base), ours, requestDiffSchema(theirs))
+		if len(conflicts) > 0 {
+			return &MergeConflictError{Conflicts: conflicts}
+		}
+		diff = schemadiff.Compare(ours, merged)
+		if len(diff.AddedTables) > 0 {
+			project, err := tx.Projects.GetByID(projectID)
+			if err != nil {
+				return err
+			}
+			quotas, err := s.quotas.QuotasFor(project.OwnerID)
+			if err != nil {
+				return err
+			}
+			if err := quotas.CheckTables(len(merged.Tables)); err != nil {
+				return err
+			}
+		}
+		if err := s.applyMerge(tx, projectID, diff, merged, theirs, userID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		branch.MergedBy = &userID
+		branch.MergedAt = &now
+		if err := tx.Branches.Update(branch); err != nil {
+			return err
+		}
+		branch.Sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, nil, ErrVersionConflict
+		}
+		return nil, nil, err
+	}
+	return branch, diff, nil
+}
+
+// applyMerge changes the project's schema as diff says, taking the layout and
+// metadata of what it adds from the branch's schema
+func (s *BranchService) applyMerge(tx *Tx, projectID uuid.UUID, diff *schemadiff.Diff, merged schemadiff.Schema, branch dto.CreateSchemaRequest, userID uuid.UUID) error {
+	tables, err := tx.Tables.GetByProjectID(projectID)
+	if err != nil {
+		return err
+	}
+	relationships, err := tx.Relationships.GetByProjectID(projectID)
+	if err != nil {
+		return err
+	}
+	tablesByName := make(map[string]*models.Table, len(tables))
+	tableNames := make(map[uuid.UUID]string, len(tables))
+	fieldNames := make(map[uuid.UUID]string)
+	for _, table := range tables {
+		tablesByName[table.Name] = table
+		tableNames[table.ID] = table.Name
+		for _, field := range table.Fields {
+			fieldNames[field.ID] = field.Name
+		}
+	}
+
+	// Relationships go first, as they do not go with the tables they link
+	schema := &Schema{}
+	removed := make(map[[4]string]bool, len(diff.RemovedRelationships))
+	for _, relationship := range diff.RemovedRelationships {
+		removed[relationshipNames(relationship)] = true
+	}
+	changed := make(map[[4]string]string, len(diff.ChangedRelationships))
+	for _, relationship := range diff.ChangedRelationships {
+		changed[relationshipNames(relationship.Relationship)] = relationship.Changes[0].To
+	}
+	var updatedRelationships []*models.Relationship
+	for _, relationship := range relationships {
+		names := [4]string{tableNames[relationship.SourceTableID], fieldNames[relationship.SourceFieldID], tableNames[relationship.TargetTableID], fieldNames[relationship.TargetFieldID]}
+		if removed[names] {
+			if err := tx.Relationships.Delete(relationship.ID); err != nil {
+				return err
+			}
+			schema.dropped = append(schema.dropped, relationship)
+		} else if relationType, ok := changed[names]; ok {
+			relationship.RelationType = relationType
+			if err := tx.Relationships.Update(relationship); err != nil {
+				return err
+			}
+			updatedRelationships = append(updatedRelationships, relationship)
+		}
+	}
+
+	for _, removedTable := range diff.RemovedTables {
+		table := tablesByName[removedTable.Name]
+		if err := tx.Tables.Delete(table.ID); err != nil {
+			return err
+		}
+		delete(tablesByName, table.Name)
+		schema.replaced = append(schema.replaced, table)
+	}
+
+	var deletedFields, updatedFields []*models.Field
+	mergedTables := make(map[string]schemadiff.Table, len(merged.Tables))
+	for _, table := range merged.Tables {
+		mergedTables[table.Name] = table
+	}
+	for _, tableDiff := range diff.ChangedTables {
+		table := tablesByName[tableDiff.Name]
+		for _, removedField := range tableDiff.RemovedFields {
+			field := findField(table, removedField.Name)
+			if err := tx.Fields.Delete(field.ID); err != nil {
+				return err
+			}
+			deletedFields = append(deletedFields, field)
+		}
+		for _, fieldDiff := range tableDiff.ChangedFields {
+			field := findField(table, fieldDiff.Name)
+			for _, mergedField := range mergedTables[table.Name].Fields {
+				if mergedField.Name == field.Name {
+					field.DataType = mergedField.DataType
+					field.IsPrimaryKey = mergedField.IsPrimaryKey
+					field.IsNullable = mergedField.IsNullable
+					field.DefaultValue = mergedField.DefaultValue
+				}
+			}
+			if err := tx.Fields.Update(field); err != nil {
+				return err
+			}
+			updatedFields = append(updatedFields, field)
+		}
+
+		// Added fields follow the table's own, in the order of the merged table.
+		// One differing only in case from a field kept, such as one added to the
+		// project since the branch was made, cannot be.
+		added := make(map[string]bool, len(tableDiff.AddedFields))
+		for _, field := range tableDiff.AddedFields {
+			added[field.Name] = true
+		}
+		kept := slices.DeleteFunc(slices.Clone(table.Fields), func(field models.Field) bool {
+			return slices.ContainsFunc(tableDiff.RemovedFields, func(removed schemadiff.Field) bool { return removed.Name == field.Name })
+		})
+		position := -1
+		for _, field := range table.Fields {
+			position = max(position, field.Position)
+		}
+		var fields []models.Field
+		for _, mergedField := range mergedTables[table.Name].Fields {
+			if !added[mergedField.Name] {
+				continue
+			}
+			if fieldNameTaken(kept, mergedField.Name, uuid.Nil) {
+				return ErrFieldNameTaken
+			}
+			metadata, err := newMetadata(branchFieldMetadata(branch, table.Name, mergedField.Name))
+			if err != nil {
+				return err
+			}
+			position++
+			fields = append(fields, models.Field{
+				ID:           uuid.New(),
+				TableID:      table.ID,
+				Name:         mergedField.Name,
+				DataType:     mergedField.DataType,
+				IsPrimaryKey: mergedField.IsPrimaryKey,
+				IsNullable:   mergedField.IsNullable,
+				DefaultValue: mergedField.DefaultValue,
+				Position:     position,
+				Metadata:     metadata,
+			})
+		}
+		if len(fields) > 0 {
+			table.Fields = append(table.Fields, fields...)
+			created := fieldPointers(table.Fields[len(table.Fields)-len(fields):])
+			if err := tx.Fields.CreateBatch(created); err != nil {
+				return err
+			}
+			schema.Fields = append(schema.Fields, created...)
+		}
+	}
+
+	// Added tables are as the branch has them, layout included
+	if len(diff.AddedTables) > 0 {
+		req := &dto.CreateSchemaRequest{}
+		for _, addedTable := range diff.AddedTables {
+			for _, table := range branch.Tables {
+				if table.Name == addedTable.Name {
+					req.Tables = append(req.Tables, table)
+				}
+			}
+		}
+		added, err := newSchema(projectID, req)
+		if err != nil {
+			return err
+		}
+		for _, table := range added.Tables {
+			fields := table.Fields
+			table.Fields = nil // Inserted below with a single statement
+			if _, err := tx.Tables.Create(table); err != nil {
+				return err
+			}
+			table.Fields = fields
+			if len(fields) > 0 {
+				if err := tx.Fields.CreateBatch(fieldPointers(fields)); err != nil {
+					return err
+				}
+			}
+			tablesByName[table.Name] = table
+		}
+		schema.Tables = added.Tables
+	}
+
+	reqs := make([]dto.SchemaRelationshipRequest, len(diff.AddedRelationships))
+	for i, relationship := range diff.AddedRelationships {
+		reqs[i] = dto.SchemaRelationshipRequest{
+			SourceTable:  relationship.SourceTable,
+			SourceField:  relationship.SourceField,
+			TargetTable:  relationship.TargetTable,
+			TargetField:  relationship.TargetField,
+			RelationType: relationship.RelationType,
+		}
+		for _, branchRelationship := range branch.Relationships {
+			if relationshipNames(requestRelationship(branchRelationship)) == relationshipNames(relationship) {
+				reqs[i].Metadata = branchRelationship.Metadata
+			}
+		}
+	}
+	if err := resolveRelationships(schema, reqs, tablesByName); err != nil {
+		return err
+	}
+	for _, relationship := range schema.Relationships {
+		if _, err := tx.Relationships.Create(relationship); err != nil {
+			return err
+		}
+	}
+
+	if s.collaborationService == nil {
+		return nil
+	}
+	collaborationService := s.collaborationService.InTx(tx)
+	if err := notifySchemaCreated(collaborationService, projectID, schema, userID); err != nil {
+		return err
+	}
+	for _, field := range deletedFields {
+		if err := collaborationService.NotifyFieldDeleted(projectID, field.TableID, field.ID, field.Name, userID); err != nil {
+			return err
+		}
+	}
+	for _, field := range updatedFields {
+		if err := collaborationService.NotifyFieldUpdated(projectID, field, userID); err != nil {
+			return err
+		}
+	}
+	for _, relationship := range updatedRelationships {
+		if err := collaborationService.NotifyRelationshipUpdated(projectID, relationship, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeBranchSchema checks a schema like newSchema does and returns it as
+// schemaRequestOf describes it, so it is kept in one form
+func normalizeBranchSchema(projectID uuid.UUID, req *dto.CreateSchemaRequest) (dto.CreateSchemaRequest, error) {
+	schema, err := newSchema(projectID, req)
+	if err != nil {
+		return dto.CreateSchemaRequest{}, err
+	}
+	tables := make(map[string]*models.Table, len(schema.Tables))
+	for _, table := range schema.Tables {
+		tables[table.Name] = table
+	}
+	if err := resolveRelationships(schema, req.Relationships, tables); err != nil {
+		return dto.CreateSchemaRequest{}, err
+	}
+
+	project := &models.Project{
+		Tables:        make([]models.Table, len(schema.Tables)),
+		Relationships: make([]models.Relationship, len(schema.Relationships)),
+	}
+	for i, table := range schema.Tables {
+		project.Tables[i] = *table
+	}
+	for i, relationship := range schema.Relationships {
+		project.Relationships[i] = *relationship
+	}
+	return schemaRequestOf(project), nil
+}
+
+// branchSchema reads a schema kept with a branch
+func branchSchema(data string) (dto.CreateSchemaRequest, error) {
+	var schema dto.CreateSchemaRequest
+	err := json.Unmarshal([]byte(data), &schema)
+	return schema, err
+}
+
+// requestDiffSchema reads the structure of a schema kept with a branch
+func requestDiffSchema(req dto.CreateSchemaRequest) schemadiff.Schema {
+	schema := schemadiff.Schema{Tables: make([]schemadiff.Table, len(req.Tables))}
+	for i, table := range req.Tables {
+		diffTable := schemadiff.Table{Name: table.Name, Fields: make([]schemadiff.Field, len(table.Fields)), Metadata: table.Metadata}
+		for j, field := range table.Fields {
+			diffTable.Fields[j] = schemadiff.Field{
+				Name:         field.Name,
+				DataType:     field.DataType,
+				IsPrimaryKey: field.IsPrimaryKey,
+				IsNullable:   field.IsNullable,
+				DefaultValue: field.DefaultValue,
+				Metadata:     field.Metadata,
+			}
+		}
+		schema.Tables[i] = diffTable
+	}
+	for _, relationship := range req.Relationships {
+		schema.Relationships = append(schema.Relationships, requestRelationship(relationship))
+	}
+	return schema
+}
+
+func requestRelationship(req dto.SchemaRelationshipRequest) schemadiff.Relationship {
+	return schemadiff.Relationship{
+		SourceTable:  req.SourceTable,
+		SourceField:  req.SourceField,
+		TargetTable:  req.TargetTable,
+		TargetField:  req.TargetField,
+		RelationType: req.RelationType,
+	}
+}
+
+// relationshipNames names the tables and fields a relationship links
+func relationshipNames(relationship schemadiff.Relationship) [4]string {
+	return [4]string{relationship.SourceTable, relationship.SourceField, relationship.TargetTable, relationship.TargetField}
+}
+
+// branchFieldMetadata returns the metadata of a field of the branch's schema
+func branchFieldMetadata(branch dto.CreateSchemaRequest, tableName, fieldName string) map[string]any {
+	for _, table := range branch.Tables {
+		if table.Name != tableName {
+			continue
+		}
+		for _, field := range table.Fields {
+			if field.Name == fieldName {
+				return field.Metadata
+			}
+		}
+	}
+	return nil
+}
+
+func findField(table *models.Table, name string) *models.Field {
+	for i := range table.Fields {
+		if table.Fields[i].Name == name {
+			return &table.Fields[i]
+		}
+	}
+	return nil
+}
+
+// projectBranch returns a branch, ErrBranchNotFound unless it is of the project
+func projectBranch(branchRepo repository.BranchRepositoryInterface, projectID, id uuid.UUID) (*models.Branch, error) {
+	branch, err := branchRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBranchNotFound
+		}
+		return nil, err
+	}
+	if branch.ProjectID != projectID {
+		return nil, ErrBranchNotFound
+	}
+	return branch, nil
+}
+
+// checkBranchNameFree fails with ErrNameTaken when a branch of the project
+// other than id is named name
+func checkBranchNameFree(branchRepo repository.BranchRepositoryInterface, projectID, id uuid.UUID, name string) error {
+	branches, err := branchRepo.GetByProjectID(projectID)
+	if err != nil {
+		return err
+	}
+	for _, branch := range branches {
+		if branch.ID != id && branch.Name == name {
+			return ErrNameTaken
+		}
+	}
+	return nil
+}
+
+func (s *BranchService) checkAccess(projectID, userID uuid.UUID) error {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !canAccess {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *BranchService) checkCanModify(projectID, userID uuid.UUID) error {
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !canModify {
+		return ErrForbidden
+	}
+	return nil
+}
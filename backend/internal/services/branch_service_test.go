@@ -0,0 +1,313 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type BranchServiceTestSuite struct {
+	suite.Suite
+	mockBranchRepo           *mockRepo.MockBranchRepository
+	mockProjectRepo          *mockRepo.MockProjectRepository
+	mockTableRepo            *mockRepo.MockTableRepository
+	mockFieldRepo            *mockRepo.MockFieldRepository
+	mockRelRepo              *mockRepo.MockRelationshipRepository
+	mockAuthService          *mockAuthorizationService
+	mockCollaborationService *mockCollaborationService
+	service                  *BranchService
+	projectID                uuid.UUID
+	userID                   uuid.UUID
+}
+
+func (suite *BranchServiceTestSuite) SetupTest() {
+	suite.mockBranchRepo = new(mockRepo.MockBranchRepository)
+	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
+	suite.mockTableRepo = new(mockRepo.MockTableRepository)
+	suite.mockFieldRepo = new(mockRepo.MockFieldRepository)
+	suite.mockRelRepo = new(mockRepo.MockRelationshipRepository)
+	suite.mockAuthService = new(mockAuthorizationService)
+	suite.mockCollaborationService = new(mockCollaborationService)
+	suite.service = NewBranchService(suite.mockBranchRepo, suite.mockAuthService, suite.mockCollaborationService,
+		newTestUnitOfWork(repository.Repositories{
+			Projects:      suite.mockProjectRepo,
+			Tables:        suite.mockTableRepo,
+			Fields:        suite.mockFieldRepo,
+			Relationships: suite.mockRelRepo,
+			Branches:      suite.mockBranchRepo,
+		}), Quotas{})
+	suite.projectID = uuid.New()
+	suite.userID = uuid.New()
+}
+
+func TestBranchServiceSuite(t *testing.T) {
+	suite.Run(t, new(BranchServiceTestSuite))
+}
+
+// newBranch returns a branch of the suite's project made from base and edited to schema
+func (suite *BranchServiceTestSuite) newBranch(base, schema dto.CreateSchemaRequest) *models.Branch {
+	baseData, err := json.Marshal(base)
+	suite.Require().NoError(err)
+	schemaData, err := json.Marshal(schema)
+	suite.Require().NoError(err)
+	return &models.Branch{ID: uuid.New(), ProjectID: suite.projectID, Name: "audit", Base: string(baseData), Schema: string(schemaData), Version: 1}
+}
+
+// usersSchema is the users table of the suite's tests with email typed as given
+func usersSchema(emailType string) dto.CreateSchemaRequest {
+	return dto.CreateSchemaRequest{Tables: []dto.SchemaTableRequest{{Name: "users", Fields: []dto.CreateFieldRequest{
+		{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+		{Name: "email", DataType: emailType, Position: 1},
+	}}}}
+}
+
+// Test CreateBranch - The branch starts as a copy of the project's schema
+func (suite *BranchServiceTestSuite) TestCreateBranch_Success() {
+	users := models.Table{ID: uuid.New(), Name: "users", PosX: 40, Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true}}}
+	branchID := uuid.New()
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockProjectRepo.On("GetByID", suite.projectID).Return(&models.Project{ID: suite.projectID, Tables: []models.Table{users}}, nil)
+	suite.mockBranchRepo.On("GetByProjectID", suite.projectID).Return([]*models.Branch{}, nil)
+	suite.mockBranchRepo.On("Create", mock.MatchedBy(func(branch *models.Branch) bool {
+		return branch.Name == "audit" && branch.CreatedBy == suite.userID && branch.Base == branch.Schema
+	})).Return(branchID, nil)
+
+	branch, err := suite.service.CreateBranch(suite.projectID, &dto.CreateBranchRequest{Name: " audit "}, suite.userID)
+
+	suite.NoError(err)
+	suite.Equal(branchID, branch.ID)
+	schema, err := branchSchema(branch.Schema)
+	suite.NoError(err)
+	suite.Require().Len(schema.Tables, 1)
+	suite.Equal("users", schema.Tables[0].Name)
+	suite.Equal(float64(40), schema.Tables[0].PosX)
+}
+
+// Test CreateBranch - Branch names are unique within the project
+func (suite *BranchServiceTestSuite) TestCreateBranch_NameTaken() {
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockProjectRepo.On("GetByID", suite.projectID).Return(&models.Project{ID: suite.projectID}, nil)
+	suite.mockBranchRepo.On("GetByProjectID", suite.projectID).Return([]*models.Branch{{ID: uuid.New(), Name: "audit"}}, nil)
+
+	branch, err := suite.service.CreateBranch(suite.projectID, &dto.CreateBranchRequest{Name: "audit"}, suite.userID)
+
+	suite.ErrorIs(err, ErrNameTaken)
+	suite.Nil(branch)
+	suite.mockBranchRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test UpdateBranch - The schema is checked and kept in one form
+func (suite *BranchServiceTestSuite) TestUpdateBranch_Schema() {
+	branch := suite.newBranch(usersSchema("TEXT"), usersSchema("TEXT"))
+	schema := usersSchema("CITEXT")
+	schema.Tables[0].Name = " users "
+	schema.Relationships = []dto.SchemaRelationshipRequest{{SourceTable: "users", SourceField: "id", TargetTable: "users", TargetField: "email"}}
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockBranchRepo.On("GetByID", branch.ID).Return(branch, nil)
+	suite.mockBranchRepo.On("Update", branch).Return(nil)
+
+	updated, err := suite.service.UpdateBranch(suite.projectID, branch.ID, &dto.UpdateBranchRequest{Schema: &schema}, suite.userID)
+
+	suite.NoError(err)
+	saved, err := branchSchema(updated.Schema)
+	suite.NoError(err)
+	suite.Equal("users", saved.Tables[0].Name)
+	suite.Equal("CITEXT", saved.Tables[0].Fields[1].DataType)
+	suite.Equal("one_to_many", saved.Relationships[0].RelationType)
+}
+
+// Test UpdateBranch - Relationships must link fields of the schema
+func (suite *BranchServiceTestSuite) TestUpdateBranch_UnknownField() {
+	branch := suite.newBranch(usersSchema("TEXT"), usersSchema("TEXT"))
+	schema := usersSchema("TEXT")
+	schema.Relationships = []dto.SchemaRelationshipRequest{{SourceTable: "users", SourceField: "id", TargetTable: "users", TargetField: "manager_id"}}
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockBranchRepo.On("GetByID", branch.ID).Return(branch, nil)
+
+	_, err := suite.service.UpdateBranch(suite.projectID, branch.ID, &dto.UpdateBranchRequest{Schema: &schema}, suite.userID)
+
+	suite.ErrorIs(err, ErrFieldNotFound)
+	suite.mockBranchRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+// Test UpdateBranch - An edit of an earlier version returns the current branch
+func (suite *BranchServiceTestSuite) TestUpdateBranch_StaleVersion() {
+	branch := suite.newBranch(usersSchema("TEXT"), usersSchema("TEXT"))
+	branch.Version = 3
+	version := int64(2)
+	name := "renamed"
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockBranchRepo.On("GetByID", branch.ID).Return(branch, nil)
+
+	updated, err := suite.service.UpdateBranch(suite.projectID, branch.ID, &dto.UpdateBranchRequest{Name: &name, Version: &version}, suite.userID)
+
+	suite.ErrorIs(err, ErrVersionConflict)
+	suite.Equal("audit", updated.Name)
+	suite.mockBranchRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+// Test UpdateBranch - Merged branches no longer change
+func (suite *BranchServiceTestSuite) TestUpdateBranch_Merged() {
+	branch := suite.newBranch(usersSchema("TEXT"), usersSchema("TEXT"))
+	branch.MergedBy = &suite.userID
+	branch.MergedAt = &branch.CreatedAt
+	name := "renamed"
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockBranchRepo.On("GetByID", branch.ID).Return(branch, nil)
+
+	_, err := suite.service.UpdateBranch(suite.projectID, branch.ID, &dto.UpdateBranchRequest{Name: &name}, suite.userID)
+
+	suite.ErrorIs(err, ErrBranchMerged)
+}
+
+// Test GetBranch - Branches of other projects are not found
+func (suite *BranchServiceTestSuite) TestGetBranch_OtherProject() {
+	branchID := uuid.New()
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockBranchRepo.On("GetByID", branchID).Return(&models.Branch{ID: branchID, ProjectID: uuid.New()}, nil)
+
+	branch, err := suite.service.GetBranch(suite.projectID, branchID, suite.userID)
+
+	suite.ErrorIs(err, ErrBranchNotFound)
+	suite.Nil(branch)
+}
+
+// Test DiffBranch - The diff goes from the project as it is now to the branch
+func (suite *BranchServiceTestSuite) TestDiffBranch() {
+	branch := suite.newBranch(usersSchema("TEXT"), usersSchema("CITEXT"))
+	users := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{
+		{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true},
+		{ID: uuid.New(), Name: "email", DataType: "VARCHAR(255)"},
+	}}
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockBranchRepo.On("GetByID", branch.ID).Return(branch, nil)
+	suite.mockTableRepo.On("GetByProjectID", suite.projectID).Return([]*models.Table{users}, nil)
+	suite.mockRelRepo.On("GetByProjectID", suite.projectID).Return([]*models.Relationship{}, nil)
+
+	diff, err := suite.service.DiffBranch(suite.projectID, branch.ID, suite.userID)
+
+	suite.NoError(err)
+	suite.Require().Len(diff.ChangedTables, 1)
+	suite.Equal([]schemadiff.Change{{Attribute: schemadiff.AttributeDataType, From: "VARCHAR(255)", To: "CITEXT"}}, diff.ChangedTables[0].ChangedFields[0].Changes)
+}
+
+// Test MergeBranch - The branch's changes are applied to the project and collaborators notified
+func (suite *BranchServiceTestSuite) TestMergeBranch_Success() {
+	theirs := usersSchema("CITEXT")
+	theirs.Tables = append(theirs.Tables, dto.SchemaTableRequest{Name: "posts", PosX: 320, Fields: []dto.CreateFieldRequest{
+		{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+		{Name: "author_id", DataType: "UUID", Position: 1, Metadata: map[string]any{"pii": false}},
+	}})
+	theirs.Relationships = []dto.SchemaRelationshipRequest{{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_many"}}
+	branch := suite.newBranch(usersSchema("TEXT"), theirs)
+
+	// The project added a field since, which the merge keeps
+	users := &models.Table{ID: uuid.New(), ProjectID: suite.projectID, Name: "users", Fields: []models.Field{
+		{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true},
+		{ID: uuid.New(), Name: "email", DataType: "TEXT", Position: 1, Version: 4},
+		{ID: uuid.New(), Name: "created_at", DataType: "TIMESTAMP", Position: 2},
+	}}
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockBranchRepo.On("GetByID", branch.ID).Return(branch, nil)
+	suite.mockProjectRepo.On("GetByID", suite.projectID).Return(&models.Project{ID: suite.projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", suite.projectID).Return([]*models.Table{users}, nil)
+	suite.mockRelRepo.On("GetByProjectID", suite.projectID).Return([]*models.Relationship{}, nil)
+	suite.mockFieldRepo.On("Update", mock.MatchedBy(func(field *models.Field) bool {
+		return field.Name == "email" && field.DataType == "CITEXT"
+	})).Return(nil)
+	suite.mockTableRepo.On("Create", mock.MatchedBy(func(table *models.Table) bool {
+		return table.Name == "posts" && table.PosX == 320
+	})).Return(uuid.New(), nil)
+	suite.mockFieldRepo.On("CreateBatch", mock.MatchedBy(func(fields []*models.Field) bool {
+		return len(fields) == 2 && fields[1].Name == "author_id" && fields[1].Metadata["pii"] == false
+	})).Return(nil)
+	suite.mockRelRepo.On("Create", mock.MatchedBy(func(relationship *models.Relationship) bool {
+		return relationship.SourceFieldID == users.Fields[0].ID && relationship.ProjectID == suite.projectID
+	})).Return(uuid.New(), nil)
+	suite.mockBranchRepo.On("Update", mock.MatchedBy(func(branch *models.Branch) bool {
+		return branch.MergedAt != nil && *branch.MergedBy == suite.userID
+	})).Return(nil)
+	suite.mockCollaborationService.On("NotifyTableCreated", suite.projectID, mock.AnythingOfType("*models.Table"), suite.userID).Return(nil)
+	suite.mockCollaborationService.On("NotifyFieldsCreated", suite.projectID, mock.Anything, mock.Anything, suite.userID).Return(nil)
+	suite.mockCollaborationService.On("NotifyRelationshipCreated", suite.projectID, mock.AnythingOfType("*models.Relationship"), suite.userID).Return(nil)
+	suite.mockCollaborationService.On("NotifyFieldUpdated", suite.projectID, mock.AnythingOfType("*models.Field"), suite.userID).Return(nil)
+
+	merged, diff, err := suite.service.MergeBranch(suite.projectID, branch.ID, suite.userID)
+
+	suite.NoError(err)
+	suite.NotNil(merged.MergedAt)
+	suite.Require().Len(diff.AddedTables, 1)
+	suite.Equal("posts", diff.AddedTables[0].Name)
+	suite.Empty(diff.RemovedTables)
+	suite.Require().Len(diff.ChangedTables, 1)
+	suite.Empty(diff.ChangedTables[0].RemovedFields, "The field the project added is kept")
+	suite.mockFieldRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+	suite.mockTableRepo.AssertExpectations(suite.T())
+	suite.mockFieldRepo.AssertExpectations(suite.T())
+	suite.mockRelRepo.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertExpectations(suite.T())
+}
+
+// Test MergeBranch - A field the branch adds cannot take the name of one the
+// project added since in another case
+func (suite *BranchServiceTestSuite) TestMergeBranch_FieldNameTaken() {
+	theirs := usersSchema("TEXT")
+	theirs.Tables[0].Fields = append(theirs.Tables[0].Fields, dto.CreateFieldRequest{Name: "Created_At", DataType: "TIMESTAMP", Position: 2})
+	branch := suite.newBranch(usersSchema("TEXT"), theirs)
+	users := &models.Table{ID: uuid.New(), ProjectID: suite.projectID, Name: "users", Fields: []models.Field{
+		{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true},
+		{ID: uuid.New(), Name: "email", DataType: "TEXT", Position: 1},
+		{ID: uuid.New(), Name: "created_at", DataType: "TIMESTAMP", Position: 2},
+	}}
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockBranchRepo.On("GetByID", branch.ID).Return(branch, nil)
+	suite.mockProjectRepo.On("GetByID", suite.projectID).Return(&models.Project{ID: suite.projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", suite.projectID).Return([]*models.Table{users}, nil)
+	suite.mockRelRepo.On("GetByProjectID", suite.projectID).Return([]*models.Relationship{}, nil)
+
+	merged, _, err := suite.service.MergeBranch(suite.projectID, branch.ID, suite.userID)
+
+	suite.ErrorIs(err, ErrFieldNameTaken)
+	suite.Nil(merged)
+	suite.mockFieldRepo.AssertNotCalled(suite.T(), "CreateBatch", mock.Anything)
+	suite.mockBranchRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+// Test MergeBranch - A field changed differently on both sides is a conflict
+func (suite *BranchServiceTestSuite) TestMergeBranch_Conflict() {
+	branch := suite.newBranch(usersSchema("TEXT"), usersSchema("CITEXT"))
+	users := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{
+		{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true},
+		{ID: uuid.New(), Name: "email", DataType: "VARCHAR(320)", Position: 1},
+	}}
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockBranchRepo.On("GetByID", branch.ID).Return(branch, nil)
+	suite.mockTableRepo.On("GetByProjectID", suite.projectID).Return([]*models.Table{users}, nil)
+	suite.mockRelRepo.On("GetByProjectID", suite.projectID).Return([]*models.Relationship{}, nil)
+
+	merged, _, err := suite.service.MergeBranch(suite.projectID, branch.ID, suite.userID)
+
+	suite.ErrorIs(err, ErrMergeConflict)
+	suite.Equal(&MergeConflictError{Conflicts: []schemadiff.Conflict{{Table: "users", Field: "email", Reason: schemadiff.ConflictModified}}}, err)
+	suite.Nil(merged)
+	suite.mockFieldRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+	suite.mockBranchRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+// Test MergeBranch - Viewers cannot merge
+func (suite *BranchServiceTestSuite) TestMergeBranch_Forbidden() {
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(false, nil)
+
+	_, _, err := suite.service.MergeBranch(suite.projectID, uuid.New(), suite.userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.mockBranchRepo.AssertNotCalled(suite.T(), "GetByID", mock.Anything)
+}
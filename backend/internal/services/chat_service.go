@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxChatMessageLength is how many characters a chat message holds at most
+const MaxChatMessageLength = 4000
+
+// ChatService keeps the chat of a project's collaborators, sent through the
+// API or a WebSocket and delivered to the WebSockets of the project. Anyone
+// with access to a project reads and writes its chat.
+type ChatService struct {
+	chatRepo             repository.ChatRepositoryInterface
+	userRepo             repository.UserRepositoryInterface
+	authService          AuthorizationServiceInterface
+	collaborationService CollaborationSessionServiceInterface
+}
+
+func NewChatService(chatRepo repository.ChatRepositoryInterface, userRepo repository.UserRepositoryInterface, authService AuthorizationServiceInterface, collaborationService CollaborationSessionServiceInterface) *ChatService {
+	return &ChatService{
+		chatRepo:             chatRepo,
+		userRepo:             userRepo,
+		authService:          authService,
+		collaborationService: collaborationService,
+	}
+}
+
+// SendMessage saves a chat message and delivers it to the project's
+// WebSockets. A message that is saved but not delivered is not an error;
+// clients find it in the history.
+func (s *ChatService) SendMessage(projectID uuid.UUID, content string, userID uuid.UUID) (*models.ChatMessage, error) {
+	content = strings.TrimSpace(content)
+	if content == "" || utf8.RuneCountInString(content) > MaxChatMessageLength {
+		return nil, ErrInvalidInput
+	}
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	message := &models.ChatMessage{ProjectID: projectID, UserID: userID, Content: content}
+	if message.ID, err = s.chatRepo.Create(message); err != nil {
+		return nil, err
+	}
+	message.User = *user
+
+	if err := s.collaborationService.NotifyChatMessage(projectID, message); err != nil {
+		log.Printf("Error delivering chat message %s: %v", message.ID, err)
+	}
+	return message, nil
+}
+
+// ListMessages returns a page of the project's chat, newest first by default
+func (s *ChatService) ListMessages(projectID, userID uuid.UUID, page repository.PageQuery) ([]*models.ChatMessage, string, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, "", err
+	}
+	return s.chatRepo.ListByProjectID(projectID, page)
+}
+
+func (s *ChatService) checkAccess(projectID, userID uuid.UUID) error {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !canAccess {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChatServiceTestSuite struct {
+	suite.Suite
+	mockChatRepo             *mockRepo.MockChatRepository
+	mockUserRepo             *mockRepo.MockUserRepository
+	mockAuthService          *mockAuthorizationService
+	mockCollaborationService *mockCollaborationService
+	service                  *ChatService
+	projectID                uuid.UUID
+	user                     *models.User
+}
+
+func (suite *ChatServiceTestSuite) SetupTest() {
+	suite.mockChatRepo = new(mockRepo.MockChatRepository)
+	suite.mockUserRepo = new(mockRepo.MockUserRepository)
+	suite.mockAuthService = new(mockAuthorizationService)
+	suite.mockCollaborationService = new(mockCollaborationService)
+	suite.service = NewChatService(suite.mockChatRepo, suite.mockUserRepo, suite.mockAuthService, suite.mockCollaborationService)
+	suite.projectID = uuid.New()
+	suite.user = &models.User{ID: uuid.New(), Username: "ada"}
+}
+
+func TestChatServiceSuite(t *testing.T) {
+	suite.Run(t, new(ChatServiceTestSuite))
+}
+
+// Test SendMessage - The message is saved and delivered with its sender
+func (suite *ChatServiceTestSuite) TestSendMessage_Success() {
+	messageID := uuid.New()
+	suite.mockAuthService.On("CanUserAccessProject", suite.user.ID, suite.projectID).Return(true, nil)
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockChatRepo.On("Create", mock.MatchedBy(func(message *models.ChatMessage) bool {
+		return message.ProjectID == suite.projectID && message.UserID == suite.user.ID && message.Content == "Shall we split users?"
+	})).Return(messageID, nil)
+	suite.mockCollaborationService.On("NotifyChatMessage", suite.projectID, mock.MatchedBy(func(message *models.ChatMessage) bool {
+		return message.ID == messageID && message.User.Username == "ada"
+	})).Return(nil)
+
+	message, err := suite.service.SendMessage(suite.projectID, "  Shall we split users?\n", suite.user.ID)
+
+	suite.NoError(err)
+	suite.Equal(messageID, message.ID)
+	suite.mockCollaborationService.AssertExpectations(suite.T())
+}
+
+// Test SendMessage - A message that could not be delivered is still sent
+func (suite *ChatServiceTestSuite) TestSendMessage_DeliveryFails() {
+	suite.mockAuthService.On("CanUserAccessProject", suite.user.ID, suite.projectID).Return(true, nil)
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockChatRepo.On("Create", mock.AnythingOfType("*models.ChatMessage")).Return(uuid.New(), nil)
+	suite.mockCollaborationService.On("NotifyChatMessage", suite.projectID, mock.AnythingOfType("*models.ChatMessage")).Return(errors.New("hub down"))
+
+	message, err := suite.service.SendMessage(suite.projectID, "Hi", suite.user.ID)
+
+	suite.NoError(err)
+	suite.Equal("Hi", message.Content)
+}
+
+// Test SendMessage - Blank and overlong messages are rejected
+func (suite *ChatServiceTestSuite) TestSendMessage_InvalidContent() {
+	for _, content := range []string{" \n ", strings.Repeat("é", MaxChatMessageLength+1)} {
+		message, err := suite.service.SendMessage(suite.projectID, content, suite.user.ID)
+
+		suite.ErrorIs(err, ErrInvalidInput)
+		suite.Nil(message)
+	}
+	suite.mockChatRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test SendMessage - Only people with access to the project chat in it
+func (suite *ChatServiceTestSuite) TestSendMessage_Forbidden() {
+	suite.mockAuthService.On("CanUserAccessProject", suite.user.ID, suite.projectID).Return(false, nil)
+
+	message, err := suite.service.SendMessage(suite.projectID, "Hi", suite.user.ID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(message)
+	suite.mockChatRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test ListMessages - Pages come from the repository as they are
+func (suite *ChatServiceTestSuite) TestListMessages() {
+	page := repository.PageQuery{Limit: 20, Cursor: "abc"}
+	messages := []*models.ChatMessage{{ID: uuid.New(), ProjectID: suite.projectID, Content: "Hi"}}
+	suite.mockAuthService.On("CanUserAccessProject", suite.user.ID, suite.projectID).Return(true, nil)
+	suite.mockChatRepo.On("ListByProjectID", suite.projectID, page).Return(messages, "next", nil)
+
+	listed, next, err := suite.service.ListMessages(suite.projectID, suite.user.ID, page)
+
+	suite.NoError(err)
+	suite.Equal(messages, listed)
+	suite.Equal("next", next)
+}
@@ -1,11 +1,15 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
@@ -21,6 +25,9 @@ type CollaborationSessionService struct {
 	relationshipRepo repository.RelationshipRepositoryInterface
 	authService      AuthorizationServiceInterface
 	hub              *websocketPkg.Hub
+	projectCache     *ProjectCache
+	outbox           *OutboxDispatcher // Delivers notifications saved in a transaction
+	tx               *Tx               // Set on the copies returned by InTx
 }
 
 func NewCollaborationSessionService(
@@ -31,6 +38,7 @@ func NewCollaborationSessionService(
 	relationshipRepo repository.RelationshipRepositoryInterface,
 	authService AuthorizationServiceInterface,
 	hub *websocketPkg.Hub,
+	projectCache *ProjectCache,
 ) *CollaborationSessionService {
 	return &CollaborationSessionService{
 		sessionRepo:      sessionRepo,
@@ -40,9 +48,28 @@ func NewCollaborationSessionService(
 		relationshipRepo: relationshipRepo,
 		authService:      authService,
 		hub:              hub,
+		projectCache:     projectCache,
 	}
 }
 
+// SetOutbox makes notifications sent within a transaction go through the
+// outbox, so they are saved with the change and survive a crash before delivery
+func (s *CollaborationSessionService) SetOutbox(outbox *OutboxDispatcher) {
+	s.outbox = outbox
+}
+
+// InTx returns a copy of the service whose notifications belong to tx. They
+// are saved to the outbox with the change, or without an outbox broadcast
+// once tx has committed; nobody hears of a change that is rolled back.
+func (s *CollaborationSessionService) InTx(tx *Tx) CollaborationSessionServiceInterface {
+	inTx := *s
+	inTx.tx = tx
+	// Payloads may name tables created in tx
+	inTx.tableRepo = tx.Tables
+	inTx.relationshipRepo = tx.Relationships
+	return &inTx
+}
+
 func (s *CollaborationSessionService) CreateSession(projectID, userID uuid.UUID, userColor string) (*models.CollaborationSession, error) {
 	// Verify project exists
 	_, err := s.projectRepo.GetByID(projectID)
@@ -104,9 +131,11 @@ func (s *CollaborationSessionService) GetActiveSessionsByProjectID(projectID uui
 	return s.sessionRepo.GetActiveByProjectID(projectID)
 }
 
+// UpdateCursor saves a session's cursor and, given both coordinates, sends it
+// to the project's WebSockets like a cursor moved over a WebSocket
 func (s *CollaborationSessionService) UpdateCursor(sessionID uuid.UUID, cursorX, cursorY *float64) error {
 	// Verify session exists
-	_, err := s.sessionRepo.GetByID(sessionID)
+	session, err := s.sessionRepo.GetByID(sessionID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrSessionNotFound
@@ -114,7 +143,35 @@ func (s *CollaborationSessionService) UpdateCursor(sessionID uuid.UUID, cursorX,
 		return err
 	}
 
-	return s.sessionRepo.UpdateCursor(sessionID, cursorX, cursorY)
+	if err := s.sessionRepo.UpdateCursor(sessionID, cursorX, cursorY); err != nil {
+		return err
+	}
+	if s.hub != nil && cursorX != nil && cursorY != nil {
+		s.broadcastCursor(session, *cursorX, *cursorY)
+	}
+	return nil
+}
+
+// broadcastCursor sends a session's cursor to the project's WebSockets
+func (s *CollaborationSessionService) broadcastCursor(session *models.CollaborationSession, cursorX, cursorY float64) {
+	user, err := s.userRepo.GetByID(session.UserID)
+	if err != nil {
+		log.Printf("Failed to load user %s for cursor broadcast: %v", session.UserID, err)
+		return
+	}
+
+	message, err := websocketPkg.NewWebSocketMessage(websocketPkg.MessageTypeUserCursor, websocketPkg.UserCursorPayload{
+		UserID:    session.UserID,
+		Username:  user.Username,
+		UserColor: session.UserColor,
+		CursorX:   cursorX,
+		CursorY:   cursorY,
+	}, session.UserID, session.ProjectID)
+	if err != nil {
+		log.Printf("Error creating cursor message: %v", err)
+		return
+	}
+	s.hub.BroadcastToProject(session.ProjectID, message, nil)
 }
 
 func (s *CollaborationSessionService) UpdateSession(id uuid.UUID, req *dto.UpdateSessionRequest) (*models.CollaborationSession, error) {
@@ -194,8 +251,105 @@ func (s *CollaborationSessionService) DeleteSession(sessionID uuid.UUID, userID
 
 // WebSocket integration methods
 
-// BroadcastSchemaChange broadcasts schema changes to all collaborators
+// UserConnected creates or reactivates the user's session when their first
+// WebSocket connection to a project opens. Called by the hub.
+func (s *CollaborationSessionService) UserConnected(projectID, userID uuid.UUID, userColor string) {
+	session, err := s.sessionRepo.GetByProjectAndUser(projectID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Failed to load collaboration session for user %s in project %s: %v", userID, projectID, err)
+		errorreport.Capture(context.Background(), err, errorreport.UserTag(userID), errorreport.ProjectTag(projectID))
+		return
+	}
+
+	now := time.Now()
+	if session == nil {
+		session = &models.CollaborationSession{
+			ProjectID: projectID,
+			UserID:    userID,
+			UserColor: userColor,
+		}
+	}
+	session.UserColor = userColor
+	session.Status = websocketPkg.PresenceActive
+	session.IsActive = true
+	session.LastPingAt = now
+	session.JoinedAt = now
+	session.LeftAt = nil
+
+	if session.ID == uuid.Nil {
+		_, err = s.sessionRepo.Create(session)
+	} else {
+		err = s.sessionRepo.Update(session)
+	}
+	if err != nil {
+		log.Printf("Failed to activate collaboration session for user %s in project %s: %v", userID, projectID, err)
+		errorreport.Capture(context.Background(), err, errorreport.UserTag(userID), errorreport.ProjectTag(projectID))
+	}
+}
+
+// UserDisconnected marks the user's session inactive when their last WebSocket
+// connection to a project closes. Called by the hub.
+func (s *CollaborationSessionService) UserDisconnected(projectID, userID uuid.UUID) {
+	session, err := s.sessionRepo.GetByProjectAndUser(projectID, userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Failed to load collaboration session for user %s in project %s: %v", userID, projectID, err)
+			errorreport.Capture(context.Background(), err, errorreport.UserTag(userID), errorreport.ProjectTag(projectID))
+		}
+		return
+	}
+
+	if err := s.sessionRepo.SetInactive(session.ID); err != nil {
+		log.Printf("Failed to deactivate collaboration session %s: %v", session.ID, err)
+		errorreport.Capture(context.Background(), err, errorreport.UserTag(userID), errorreport.ProjectTag(projectID))
+	}
+}
+
+// CursorMoved saves where the user's cursor is to their session, so that
+// sessions listed through the API show it. Called by the hub, debounced.
+func (s *CollaborationSessionService) CursorMoved(projectID, userID uuid.UUID, cursorX, cursorY float64) {
+	session, err := s.sessionRepo.GetByProjectAndUser(projectID, userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Failed to load collaboration session for user %s in project %s: %v", userID, projectID, err)
+			errorreport.Capture(context.Background(), err, errorreport.UserTag(userID), errorreport.ProjectTag(projectID))
+		}
+		return
+	}
+
+	if err := s.sessionRepo.UpdateCursor(session.ID, &cursorX, &cursorY); err != nil {
+		log.Printf("Failed to save cursor of collaboration session %s: %v", session.ID, err)
+		errorreport.Capture(context.Background(), err, errorreport.UserTag(userID), errorreport.ProjectTag(projectID))
+	}
+}
+
+// StatusChanged saves whether the user is active, idle or away to their
+// session. Called by the hub.
+func (s *CollaborationSessionService) StatusChanged(projectID, userID uuid.UUID, status string) {
+	session, err := s.sessionRepo.GetByProjectAndUser(projectID, userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Failed to load collaboration session for user %s in project %s: %v", userID, projectID, err)
+			errorreport.Capture(context.Background(), err, errorreport.UserTag(userID), errorreport.ProjectTag(projectID))
+		}
+		return
+	}
+
+	if err := s.sessionRepo.UpdateStatus(session.ID, status); err != nil {
+		log.Printf("Failed to save status of collaboration session %s: %v", session.ID, err)
+		errorreport.Capture(context.Background(), err, errorreport.UserTag(userID), errorreport.ProjectTag(projectID))
+	}
+}
+
+// BroadcastSchemaChange broadcasts schema changes to all collaborators. The
+// project is dropped from the project cache first, as its schema changed.
 func (s *CollaborationSessionService) BroadcastSchemaChange(projectID uuid.UUID, messageType websocketPkg.MessageType, payload interface{}, senderUserID uuid.UUID) error {
+	if s.tx != nil {
+		return s.queueSchemaChange(projectID, messageType, payload, senderUserID)
+	}
+
+	s.projectCache.Invalidate(projectID)
+
 	if s.hub == nil {
 		return fmt.Errorf("WebSocket hub not initialized")
 	}
@@ -210,6 +364,46 @@ func (s *CollaborationSessionService) BroadcastSchemaChange(projectID uuid.UUID,
 	return nil
 }
 
+// queueSchemaChange holds a schema-change message back until the transaction
+// of the service commits. The message takes the project's next sequence in the
+// transaction, which is recorded on it. With an outbox the message is saved in
+// the transaction too, so an error means the change must not commit either.
+func (s *CollaborationSessionService) queueSchemaChange(projectID uuid.UUID, messageType websocketPkg.MessageType, payload interface{}, senderUserID uuid.UUID) error {
+	message, err := websocketPkg.NewWebSocketMessage(messageType, payload, senderUserID, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to create WebSocket message: %w", err)
+	}
+	sequence, err := s.tx.Projects.NextSequence(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to number WebSocket message: %w", err)
+	}
+	message.Sequence = sequence
+	s.tx.Sequence = sequence
+
+	if s.outbox == nil {
+		s.tx.AfterCommit(func() {
+			s.projectCache.Invalidate(projectID)
+			if s.hub != nil {
+				s.hub.BroadcastToProject(projectID, message, nil)
+			}
+		})
+		return nil
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode WebSocket message: %w", err)
+	}
+	if err := s.tx.Outbox.Create(&models.OutboxEvent{ProjectID: projectID, Message: data}); err != nil {
+		return fmt.Errorf("failed to save WebSocket message: %w", err)
+	}
+	s.tx.AfterCommit(func() {
+		s.projectCache.Invalidate(projectID)
+		s.outbox.Wake()
+	})
+	return nil
+}
+
 // BroadcastCanvasUpdate broadcasts canvas updates to all collaborators
 func (s *CollaborationSessionService) BroadcastCanvasUpdate(projectID uuid.UUID, canvasData string, senderUserID uuid.UUID) error {
 	payload := websocketPkg.CanvasUpdatedPayload{
@@ -219,13 +413,64 @@ func (s *CollaborationSessionService) BroadcastCanvasUpdate(projectID uuid.UUID,
 	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeCanvasUpdated, payload, senderUserID)
 }
 
+// NotifyDocUpdated notifies collaborators about a new version of the project's doc
+func (s *CollaborationSessionService) NotifyDocUpdated(projectID uuid.UUID, revision *models.DocRevision, senderUserID uuid.UUID) error {
+	payload := websocketPkg.DocUpdatedPayload{
+		Version: revision.Version,
+	}
+
+	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeDocUpdated, payload, senderUserID)
+}
+
+// NotifyChatMessage delivers a chat message to the project's collaborators,
+// its sender included. Chat is not a schema change: it takes no sequence and
+// is not kept for clients to catch up on, who read the history instead.
+func (s *CollaborationSessionService) NotifyChatMessage(projectID uuid.UUID, message *models.ChatMessage) error {
+	if s.hub == nil {
+		return fmt.Errorf("WebSocket hub not initialized")
+	}
+
+	payload := websocketPkg.ChatMessagePayload{
+		MessageID: message.ID,
+		UserID:    message.UserID,
+		Username:  message.User.Username,
+		Content:   message.Content,
+		CreatedAt: message.CreatedAt,
+	}
+	wsMessage, err := websocketPkg.NewWebSocketMessage(websocketPkg.MessageTypeChatMessage, payload, message.UserID, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to create WebSocket message: %w", err)
+	}
+	s.hub.BroadcastToProject(projectID, wsMessage, nil)
+	return nil
+}
+
+// DisconnectCollaborator closes a user's WebSockets to the project on every
+// node, telling them why first
+func (s *CollaborationSessionService) DisconnectCollaborator(projectID, userID uuid.UUID, reason string) error {
+	if s.hub == nil {
+		return fmt.Errorf("WebSocket hub not initialized")
+	}
+	return s.hub.DisconnectUser(projectID, userID, reason)
+}
+
+// NotifyPermissionsUpdated tells a user's WebSockets to the project about
+// their new role; those of a viewer may no longer send changes
+func (s *CollaborationSessionService) NotifyPermissionsUpdated(projectID, userID uuid.UUID, role string) error {
+	if s.hub == nil {
+		return fmt.Errorf("WebSocket hub not initialized")
+	}
+	return s.hub.UpdatePermissions(projectID, userID, role, role != models.ProjectRoleViewer)
+}
+
 // NotifyTableCreated notifies collaborators about a new table
 func (s *CollaborationSessionService) NotifyTableCreated(projectID uuid.UUID, table *models.Table, senderUserID uuid.UUID) error {
 	payload := websocketPkg.TablePayload{
-		TableID: table.ID,
-		Name:    table.Name,
-		X:       table.PosX,
-		Y:       table.PosY,
+		TableID:    table.ID,
+		Name:       table.Name,
+		X:          table.PosX,
+		Y:          table.PosY,
+		Appearance: &websocketPkg.TableAppearancePayload{Color: table.Color, Icon: table.Icon},
 	}
 
 	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeTableCreated, payload, senderUserID)
@@ -234,10 +479,11 @@ func (s *CollaborationSessionService) NotifyTableCreated(projectID uuid.UUID, ta
 // NotifyTableUpdated notifies collaborators about a table update
 func (s *CollaborationSessionService) NotifyTableUpdated(projectID uuid.UUID, table *models.Table, senderUserID uuid.UUID) error {
 	payload := websocketPkg.TablePayload{
-		TableID: table.ID,
-		Name:    table.Name,
-		X:       table.PosX,
-		Y:       table.PosY,
+		TableID:    table.ID,
+		Name:       table.Name,
+		X:          table.PosX,
+		Y:          table.PosY,
+		Appearance: &websocketPkg.TableAppearancePayload{Color: table.Color, Icon: table.Icon},
 	}
 
 	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeTableUpdated, payload, senderUserID)
@@ -269,6 +515,29 @@ func (s *CollaborationSessionService) NotifyFieldCreated(projectID uuid.UUID, fi
 	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeFieldCreated, payload, senderUserID)
 }
 
+// NotifyFieldsCreated notifies collaborators about fields added to a table
+// together, with a single message
+func (s *CollaborationSessionService) NotifyFieldsCreated(projectID, tableID uuid.UUID, fields []*models.Field, senderUserID uuid.UUID) error {
+	payload := websocketPkg.FieldsCreatedPayload{
+		TableID: tableID,
+		Fields:  make([]websocketPkg.FieldPayload, len(fields)),
+	}
+	for i, field := range fields {
+		payload.Fields[i] = websocketPkg.FieldPayload{
+			FieldID:      field.ID,
+			TableID:      field.TableID,
+			Name:         field.Name,
+			DataType:     field.DataType,
+			IsPrimaryKey: field.IsPrimaryKey,
+			IsNullable:   field.IsNullable,
+			DefaultValue: &field.DefaultValue,
+			Position:     field.Position,
+		}
+	}
+
+	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeFieldsCreated, payload, senderUserID)
+}
+
 // NotifyFieldUpdated notifies collaborators about a field update
 func (s *CollaborationSessionService) NotifyFieldUpdated(projectID uuid.UUID, field *models.Field, senderUserID uuid.UUID) error {
 	payload := websocketPkg.FieldPayload{
@@ -296,20 +565,24 @@ func (s *CollaborationSessionService) NotifyFieldDeleted(projectID, tableID, fie
 	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeFieldDeleted, payload, senderUserID)
 }
 
+// NotifyFieldsReordered notifies collaborators about the order of a table's
+// fields, giving the position of each of them
+func (s *CollaborationSessionService) NotifyFieldsReordered(projectID, tableID uuid.UUID, fields []*models.Field, senderUserID uuid.UUID) error {
+	payload := websocketPkg.FieldsReorderedPayload{
+		TableID: tableID,
+		Fields:  make([]websocketPkg.FieldPositionPayload, len(fields)),
+	}
+	for i, field := range fields {
+		payload.Fields[i] = websocketPkg.FieldPositionPayload{FieldID: field.ID, Position: field.Position}
+	}
+
+	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeFieldsReordered, payload, senderUserID)
+}
+
 // NotifyRelationshipCreated notifies collaborators about a new relationship
 func (s *CollaborationSessionService) NotifyRelationshipCreated(projectID uuid.UUID, relationship *models.Relationship, senderUserID uuid.UUID) error {
 	// Get table names for activity messages
-	sourceTable, err := s.tableRepo.GetByID(relationship.SourceTableID)
-	sourceTableName := "Unknown Table"
-	if err == nil {
-		sourceTableName = sourceTable.Name
-	}
-
-	targetTable, err := s.tableRepo.GetByID(relationship.TargetTableID)
-	targetTableName := "Unknown Table"
-	if err == nil {
-		targetTableName = targetTable.Name
-	}
+	sourceTableName, targetTableName := s.relationshipTableNames(relationship)
 
 	payload := websocketPkg.RelationshipPayload{
 		RelationshipID: relationship.ID,
@@ -320,6 +593,7 @@ func (s *CollaborationSessionService) NotifyRelationshipCreated(projectID uuid.U
 		Type:           relationship.RelationType,
 		FromTableName:  sourceTableName,
 		ToTableName:    targetTableName,
+		Route:          edgeRoutePayload(relationship),
 	}
 
 	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeRelationshipCreated, payload, senderUserID)
@@ -328,17 +602,7 @@ func (s *CollaborationSessionService) NotifyRelationshipCreated(projectID uuid.U
 // NotifyRelationshipUpdated notifies collaborators about a relationship update
 func (s *CollaborationSessionService) NotifyRelationshipUpdated(projectID uuid.UUID, relationship *models.Relationship, senderUserID uuid.UUID) error {
 	// Get table names for activity messages
-	sourceTable, err := s.tableRepo.GetByID(relationship.SourceTableID)
-	sourceTableName := "Unknown Table"
-	if err == nil {
-		sourceTableName = sourceTable.Name
-	}
-
-	targetTable, err := s.tableRepo.GetByID(relationship.TargetTableID)
-	targetTableName := "Unknown Table"
-	if err == nil {
-		targetTableName = targetTable.Name
-	}
+	sourceTableName, targetTableName := s.relationshipTableNames(relationship)
 
 	payload := websocketPkg.RelationshipPayload{
 		RelationshipID: relationship.ID,
@@ -349,11 +613,33 @@ func (s *CollaborationSessionService) NotifyRelationshipUpdated(projectID uuid.U
 		Type:           relationship.RelationType,
 		FromTableName:  sourceTableName,
 		ToTableName:    targetTableName,
+		Route:          edgeRoutePayload(relationship),
 	}
 
 	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeRelationshipUpdated, payload, senderUserID)
 }
 
+// relationshipTableNames looks up the names of a relationship's source and
+// target tables in one query, using "Unknown Table" for any it cannot find
+func (s *CollaborationSessionService) relationshipTableNames(relationship *models.Relationship) (string, string) {
+	sourceTableName := "Unknown Table"
+	targetTableName := "Unknown Table"
+
+	tables, err := s.tableRepo.GetByIDs([]uuid.UUID{relationship.SourceTableID, relationship.TargetTableID})
+	if err != nil {
+		return sourceTableName, targetTableName
+	}
+	for _, table := range tables {
+		if table.ID == relationship.SourceTableID {
+			sourceTableName = table.Name
+		}
+		if table.ID == relationship.TargetTableID {
+			targetTableName = table.Name
+		}
+	}
+	return sourceTableName, targetTableName
+}
+
 // NotifyRelationshipDeleted notifies collaborators about a relationship deletion
 func (s *CollaborationSessionService) NotifyRelationshipDeleted(projectID, relationshipID uuid.UUID, senderUserID uuid.UUID) error {
 	// Get relationship to fetch table names for activity messages
@@ -362,16 +648,7 @@ func (s *CollaborationSessionService) NotifyRelationshipDeleted(projectID, relat
 	targetTableName := "Unknown Table"
 
 	if err == nil {
-		// Get table names
-		sourceTable, err := s.tableRepo.GetByID(relationship.SourceTableID)
-		if err == nil {
-			sourceTableName = sourceTable.Name
-		}
-
-		targetTable, err := s.tableRepo.GetByID(relationship.TargetTableID)
-		if err == nil {
-			targetTableName = targetTable.Name
-		}
+		sourceTableName, targetTableName = s.relationshipTableNames(relationship)
 	}
 
 	payload := websocketPkg.RelationshipPayload{
@@ -383,6 +660,78 @@ func (s *CollaborationSessionService) NotifyRelationshipDeleted(projectID, relat
 	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeRelationshipDeleted, payload, senderUserID)
 }
 
+// NotifyRegionCreated notifies collaborators about a new region
+func (s *CollaborationSessionService) NotifyRegionCreated(projectID uuid.UUID, region *models.Region, senderUserID uuid.UUID) error {
+	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeRegionCreated, regionPayload(region), senderUserID)
+}
+
+// NotifyRegionUpdated notifies collaborators about a region update, including
+// a change of its tables
+func (s *CollaborationSessionService) NotifyRegionUpdated(projectID uuid.UUID, region *models.Region, senderUserID uuid.UUID) error {
+	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeRegionUpdated, regionPayload(region), senderUserID)
+}
+
+// NotifyRegionMoved notifies collaborators about a region moved with its tables,
+// with where each table ended up
+func (s *CollaborationSessionService) NotifyRegionMoved(projectID uuid.UUID, region *models.Region, tables []repository.TablePosition, senderUserID uuid.UUID) error {
+	payload := websocketPkg.RegionMovedPayload{
+		RegionID: region.ID,
+		X:        region.PosX,
+		Y:        region.PosY,
+		Tables:   make([]websocketPkg.TablePositionPayload, len(tables)),
+	}
+	for i, table := range tables {
+		payload.Tables[i] = websocketPkg.TablePositionPayload{
+			TableID: table.ID,
+			X:       table.PosX,
+			Y:       table.PosY,
+		}
+	}
+
+	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeRegionMoved, payload, senderUserID)
+}
+
+// NotifyRegionDeleted notifies collaborators about a region deletion
+func (s *CollaborationSessionService) NotifyRegionDeleted(projectID, regionID uuid.UUID, regionName string, senderUserID uuid.UUID) error {
+	payload := websocketPkg.RegionPayload{
+		RegionID: regionID,
+		Name:     regionName,
+	}
+
+	return s.BroadcastSchemaChange(projectID, websocketPkg.MessageTypeRegionDeleted, payload, senderUserID)
+}
+
+func regionPayload(region *models.Region) websocketPkg.RegionPayload {
+	tableIDs := region.TableIDs
+	if tableIDs == nil {
+		tableIDs = []uuid.UUID{}
+	}
+	return websocketPkg.RegionPayload{
+		RegionID: region.ID,
+		Name:     region.Name,
+		Color:    region.Color,
+		X:        region.PosX,
+		Y:        region.PosY,
+		Width:    region.Width,
+		Height:   region.Height,
+		TableIDs: tableIDs,
+	}
+}
+
+// edgeRoutePayload describes how a relationship's edge is drawn
+func edgeRoutePayload(relationship *models.Relationship) *websocketPkg.EdgeRoutePayload {
+	waypoints := make([]websocketPkg.Point, len(relationship.Waypoints))
+	for i, point := range relationship.Waypoints {
+		waypoints[i] = websocketPkg.Point{X: point.X, Y: point.Y}
+	}
+	return &websocketPkg.EdgeRoutePayload{
+		SourceAnchor:  relationship.SourceAnchor,
+		TargetAnchor:  relationship.TargetAnchor,
+		Waypoints:     waypoints,
+		LabelPosition: relationship.LabelPosition,
+	}
+}
+
 // GetActiveClientCount returns the number of active clients for a project
 func (s *CollaborationSessionService) GetActiveClientCount(projectID uuid.UUID) int {
 	if s.hub == nil {
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+	"github.com/Bug-Bugger/ezmodel/internal/jobs"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/storage"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// staleExportAge is how long an export may stay pending before it is assumed
+// lost, e.g. to a restart, and a new one may be started
+const staleExportAge = time.Hour
+
+// maxExportedLogins bounds the login history included in an export
+const maxExportedLogins = 1000
+
+// JobBuildDataExport is the type of the jobs building data exports
+const JobBuildDataExport = "data_export.build"
+
+// buildDataExportJob is the payload of a JobBuildDataExport job
+type buildDataExportJob struct {
+	ExportID uuid.UUID `json:"export_id"`
+}
+
+// DataExportService builds archives of everything stored about a user, for
+// data portability requests. Archives are built by background jobs and are
+// downloaded through links signed with a key derived from the JWT secret, so
+// they work without a session, e.g. when opened from another device.
+type DataExportService struct {
+	exportRepo      repository.DataExportRepositoryInterface
+	userRepo        repository.UserRepositoryInterface
+	preferencesRepo repository.UserPreferencesRepositoryInterface
+	projectRepo     repository.ProjectRepositoryInterface
+	loginEventRepo  repository.LoginEventRepositoryInterface
+	sessionRepo     repository.UserSessionRepositoryInterface
+	apiTokenRepo    repository.APITokenRepositoryInterface
+	store           storage.Store
+	jobs            jobs.Queue
+	signingKey      []byte
+	baseURL         string
+	ttl             time.Duration
+	now             func() time.Time
+}
+
+func NewDataExportService(
+	cfg *config.Config,
+	exportRepo repository.DataExportRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	preferencesRepo repository.UserPreferencesRepositoryInterface,
+	projectRepo repository.ProjectRepositoryInterface,
+	loginEventRepo repository.LoginEventRepositoryInterface,
+	sessionRepo repository.UserSessionRepositoryInterface,
+	apiTokenRepo repository.APITokenRepositoryInterface,
+	store storage.Store,
+	jobQueue jobs.Queue,
+) *DataExportService {
+	key := sha256.Sum256([]byte("data-export:" + cfg.JWT.Secret))
+	return &DataExportService{
+		exportRepo:      exportRepo,
+		userRepo:        userRepo,
+		preferencesRepo: preferencesRepo,
+		projectRepo:     projectRepo,
+		loginEventRepo:  loginEventRepo,
+		sessionRepo:     sessionRepo,
+		apiTokenRepo:    apiTokenRepo,
+		store:           store,
+		jobs:            jobQueue,
+		signingKey:      key[:],
+		baseURL:         cfg.OAuth.RedirectBaseURL,
+		ttl:             cfg.DataExports.TTL,
+		now:             time.Now,
+	}
+}
+
+// StartExport starts building a new export, unless one is already being built
+func (s *DataExportService) StartExport(userID uuid.UUID) (*models.DataExport, error) {
+	exports, err := s.currentExports(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(exports) > 0 && exports[0].Status == models.DataExportPending {
+		return exports[0], nil
+	}
+	return s.start(userID)
+}
+
+// GetExport returns the user's latest export that is being built or can be
+// downloaded, and starts one when there is none
+func (s *DataExportService) GetExport(userID uuid.UUID) (*models.DataExport, error) {
+	exports, err := s.currentExports(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(exports) > 0 && exports[0].Status != models.DataExportFailed {
+		return exports[0], nil
+	}
+	return s.start(userID)
+}
+
+// DownloadURL returns the signed link to a ready export, valid until it expires
+func (s *DataExportService) DownloadURL(export *models.DataExport) string {
+	if export.Status != models.DataExportReady || export.ExpiresAt == nil {
+		return ""
+	}
+	expires := strconv.FormatInt(export.ExpiresAt.Unix(), 10)
+	query := url.Values{"expires": {expires}, "signature": {s.sign(export.ID, expires)}}
+	return fmt.Sprintf("%s/api/exports/%s/download?%s", s.baseURL, export.ID, query.Encode())
+}
+
+// OpenDownload checks a signed link and returns the archive it points to
+func (s *DataExportService) OpenDownload(ctx context.Context, exportID uuid.UUID, expires, signature string) (io.ReadCloser, *models.DataExport, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(s.sign(exportID, expires))) || s.now().Unix() >= expiresAt {
+		return nil, nil, ErrInvalidDownloadLink
+	}
+
+	export, err := s.exportRepo.GetByID(exportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrDataExportNotFound
+		}
+		return nil, nil, err
+	}
+	if export.Status != models.DataExportReady || export.IsExpired(s.now()) {
+		return nil, nil, ErrDataExportNotFound
+	}
+
+	file, err := s.store.Open(ctx, export.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, export, nil
+}
+
+func (s *DataExportService) sign(exportID uuid.UUID, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(exportID.String() + "." + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// currentExports returns the user's exports, newest first, after deleting the
+// expired ones and failing those left pending for too long
+func (s *DataExportService) currentExports(userID uuid.UUID) ([]*models.DataExport, error) {
+	exports, err := s.exportRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	current := make([]*models.DataExport, 0, len(exports))
+	for _, export := range exports {
+		switch {
+		case export.IsExpired(now):
+			s.delete(export)
+			continue
+		case export.Status == models.DataExportPending && now.Sub(export.CreatedAt) > staleExportAge:
+			export.Status = models.DataExportFailed
+			if err := s.exportRepo.Update(export); err != nil {
+				return nil, err
+			}
+		}
+		current = append(current, export)
+	}
+	return current, nil
+}
+
+func (s *DataExportService) delete(export *models.DataExport) {
+	if export.StorageKey != "" {
+		if err := s.store.Delete(context.Background(), export.StorageKey); err != nil {
+			log.Printf("Failed to delete expired data export %s: %v", export.ID, err)
+			return
+		}
+	}
+	if err := s.exportRepo.Delete(export.ID); err != nil {
+		log.Printf("Failed to delete expired data export %s: %v", export.ID, err)
+	}
+}
+
+func (s *DataExportService) start(userID uuid.UUID) (*models.DataExport, error) {
+	export := &models.DataExport{UserID: userID, Status: models.DataExportPending, CreatedAt: s.now()}
+	id, err := s.exportRepo.Create(export)
+	if err != nil {
+		return nil, err
+	}
+	export.ID = id
+
+	if _, err := s.jobs.Enqueue(context.Background(), JobBuildDataExport, buildDataExportJob{ExportID: id}); err != nil {
+		export.Status = models.DataExportFailed
+		if updateErr := s.exportRepo.Update(export); updateErr != nil {
+			log.Printf("Failed to save data export %s: %v", export.ID, updateErr)
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// BuildExport is the handler of JobBuildDataExport jobs. It writes the archive
+// and marks the export ready, or failed once the job runs out of attempts.
+// Exports no longer pending, e.g. as an earlier run finished after its lease
+// ended, are left alone.
+func (s *DataExportService) BuildExport(ctx context.Context, job *jobs.Job) error {
+	var payload buildDataExportJob
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+	export, err := s.exportRepo.GetByID(payload.ExportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if export.Status != models.DataExportPending {
+		return nil
+	}
+
+	archive, err := s.buildArchive(export.UserID)
+	if err == nil {
+		key := fmt.Sprintf("%s%s/%s.zip", storage.PrivatePrefix, export.UserID, export.ID)
+		if err = s.store.Put(ctx, key, "application/zip", archive); err == nil {
+			now := s.now()
+			expiresAt := now.Add(s.ttl)
+			export.Status = models.DataExportReady
+			export.StorageKey = key
+			export.SizeBytes = int64(len(archive))
+			export.CompletedAt = &now
+			export.ExpiresAt = &expiresAt
+		}
+	}
+	if err != nil {
+		if !job.LastAttempt() {
+			return err
+		}
+		log.Printf("Failed to build data export %s: %v", export.ID, err)
+		errorreport.Capture(ctx, err, errorreport.UserTag(export.UserID))
+		export.Status = models.DataExportFailed
+	}
+
+	return s.exportRepo.Update(export)
+}
+
+// exportedProject is an owned project with its schema. Other users are named
+// by username only, as their emails are not part of this user's data.
+type exportedProject struct {
+	*models.Project
+	Owner         *models.User `json:"owner,omitempty"`
+	Collaborators []string     `json:"collaborators"`
+}
+
+// exportedProjectRef names a project the user collaborates on or viewed
+type exportedProjectRef struct {
+	ID       uuid.UUID  `json:"id"`
+	Name     string     `json:"name"`
+	ViewedAt *time.Time `json:"viewed_at,omitempty"`
+}
+
+// buildArchive collects the user's data into a zip of JSON files
+func (s *DataExportService) buildArchive(userID uuid.UUID) ([]byte, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferences, err := s.preferencesRepo.GetByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		preferences, err = models.DefaultUserPreferences(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	owned, err := s.projectRepo.GetByOwnerID(userID, repository.ProjectIncludes{})
+	if err != nil {
+		return nil, err
+	}
+	projects := make([]exportedProject, 0, len(owned))
+	for _, summary := range owned {
+		// GetByOwnerID leaves out the schema
+		project, err := s.projectRepo.GetByID(summary.ID)
+		if err != nil {
+			return nil, err
+		}
+		collaborators := make([]string, len(project.Collaborators))
+		for i, collaborator := range project.Collaborators {
+			collaborators[i] = collaborator.Username
+		}
+		projects = append(projects, exportedProject{Project: project, Collaborators: collaborators})
+	}
+
+	shared, err := s.projectRepo.GetByCollaboratorID(userID, repository.ProjectIncludes{})
+	if err != nil {
+		return nil, err
+	}
+	collaborations := make([]exportedProjectRef, len(shared))
+	for i, project := range shared {
+		collaborations[i] = exportedProjectRef{ID: project.ID, Name: project.Name}
+	}
+
+	views, err := s.projectRepo.GetRecentlyViewed(userID, 50)
+	if err != nil {
+		return nil, err
+	}
+	recent := make([]exportedProjectRef, len(views))
+	for i, view := range views {
+		recent[i] = exportedProjectRef{ID: view.ProjectID, Name: view.Project.Name, ViewedAt: &view.ViewedAt}
+	}
+
+	logins, err := s.loginEventRepo.GetByUserID(userID, maxExportedLogins)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.sessionRepo.GetActiveByUserID(userID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.apiTokenRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", user},
+		{"preferences.json", preferences},
+		{"projects.json", projects},
+		{"collaborations.json", collaborations},
+		{"api_tokens.json", tokens},
+		{"activity/logins.json", logins},
+		{"activity/sessions.json", sessions},
+		{"activity/recent_projects.json", recent},
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		data, err := json.MarshalIndent(file.data, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
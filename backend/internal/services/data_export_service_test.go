@@ -0,0 +1,276 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/jobs"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// recordingQueue keeps the jobs enqueued so tests can run them
+type recordingQueue struct {
+	jobs []*jobs.Job
+	err  error
+}
+
+func (q *recordingQueue) Enqueue(ctx context.Context, jobType string, payload any, opts ...jobs.Option) (*jobs.Job, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	job := &jobs.Job{ID: uuid.New(), Type: jobType, Payload: data, Attempt: 1, MaxAttempts: 3}
+	q.jobs = append(q.jobs, job)
+	return job, nil
+}
+
+type DataExportServiceTestSuite struct {
+	suite.Suite
+	mockExportRepo      *mockRepo.MockDataExportRepository
+	mockUserRepo        *mockRepo.MockUserRepository
+	mockPreferencesRepo *mockRepo.MockUserPreferencesRepository
+	mockProjectRepo     *mockRepo.MockProjectRepository
+	mockLoginEventRepo  *mockRepo.MockLoginEventRepository
+	mockSessionRepo     *mockRepo.MockUserSessionRepository
+	mockAPITokenRepo    *mockRepo.MockAPITokenRepository
+	store               *storage.LocalStore
+	queue               *recordingQueue
+	service             *DataExportService
+	now                 time.Time
+	user                *models.User
+}
+
+func (suite *DataExportServiceTestSuite) SetupTest() {
+	suite.mockExportRepo = new(mockRepo.MockDataExportRepository)
+	suite.mockUserRepo = new(mockRepo.MockUserRepository)
+	suite.mockPreferencesRepo = new(mockRepo.MockUserPreferencesRepository)
+	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
+	suite.mockLoginEventRepo = new(mockRepo.MockLoginEventRepository)
+	suite.mockSessionRepo = new(mockRepo.MockUserSessionRepository)
+	suite.mockAPITokenRepo = new(mockRepo.MockAPITokenRepository)
+
+	store, err := storage.NewLocalStore(suite.T().TempDir(), "https://cdn.example.com")
+	suite.Require().NoError(err)
+	suite.store = store
+
+	cfg := &config.Config{}
+	cfg.JWT.Secret = "test-secret"
+	cfg.OAuth.RedirectBaseURL = "https://ezmodel.example.com"
+	cfg.DataExports.TTL = 24 * time.Hour
+	suite.queue = &recordingQueue{}
+	suite.service = NewDataExportService(cfg, suite.mockExportRepo, suite.mockUserRepo, suite.mockPreferencesRepo,
+		suite.mockProjectRepo, suite.mockLoginEventRepo, suite.mockSessionRepo, suite.mockAPITokenRepo, store, suite.queue)
+	suite.now = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	suite.service.now = func() time.Time { return suite.now }
+
+	suite.user = &models.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com"}
+}
+
+func TestDataExportServiceSuite(t *testing.T) {
+	suite.Run(t, new(DataExportServiceTestSuite))
+}
+
+func (suite *DataExportServiceTestSuite) expectUserData() {
+	bob := models.User{ID: uuid.New(), Username: "bob", Email: "bob@example.com"}
+	owned := &models.Project{ID: uuid.New(), Name: "Shop", OwnerID: suite.user.ID}
+	full := &models.Project{ID: owned.ID, Name: "Shop", OwnerID: suite.user.ID, Collaborators: []models.User{bob},
+		Tables: []models.Table{{ID: uuid.New(), Name: "orders"}}}
+
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockPreferencesRepo.On("GetByUserID", suite.user.ID).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockProjectRepo.On("GetByOwnerID", suite.user.ID, repository.ProjectIncludes{}).Return([]*models.Project{owned}, nil)
+	suite.mockProjectRepo.On("GetByID", owned.ID).Return(full, nil)
+	suite.mockProjectRepo.On("GetByCollaboratorID", suite.user.ID, repository.ProjectIncludes{}).Return([]*models.Project{}, nil)
+	suite.mockProjectRepo.On("GetRecentlyViewed", suite.user.ID, 50).Return([]*models.ProjectView{}, nil)
+	suite.mockLoginEventRepo.On("GetByUserID", suite.user.ID, maxExportedLogins).Return([]*models.LoginEvent{}, nil)
+	suite.mockSessionRepo.On("GetActiveByUserID", suite.user.ID, suite.now).Return([]*models.UserSession{}, nil)
+	suite.mockAPITokenRepo.On("GetByUserID", suite.user.ID).Return([]*models.APIToken{}, nil)
+}
+
+// Test GetExport - Without an export a job builds one with the user's data
+func (suite *DataExportServiceTestSuite) TestGetExport_BuildsArchive() {
+	exportID := uuid.New()
+	suite.mockExportRepo.On("GetByUserID", suite.user.ID).Return([]*models.DataExport{}, nil)
+	suite.mockExportRepo.On("Create", mock.AnythingOfType("*models.DataExport")).Return(exportID, nil)
+	suite.mockExportRepo.On("GetByID", exportID).Return(&models.DataExport{ID: exportID, UserID: suite.user.ID, Status: models.DataExportPending}, nil)
+	var built *models.DataExport
+	suite.mockExportRepo.On("Update", mock.AnythingOfType("*models.DataExport")).Run(func(args mock.Arguments) {
+		built = args.Get(0).(*models.DataExport)
+	}).Return(nil)
+	suite.expectUserData()
+
+	export, err := suite.service.GetExport(suite.user.ID)
+
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), models.DataExportPending, export.Status)
+	suite.Require().Len(suite.queue.jobs, 1)
+	assert.Equal(suite.T(), JobBuildDataExport, suite.queue.jobs[0].Type)
+	suite.Require().NoError(suite.service.BuildExport(context.Background(), suite.queue.jobs[0]))
+	suite.Require().NotNil(built)
+	assert.Equal(suite.T(), models.DataExportReady, built.Status)
+	assert.Equal(suite.T(), suite.now.Add(24*time.Hour), *built.ExpiresAt)
+
+	file, err := suite.store.Open(context.Background(), built.StorageKey)
+	suite.Require().NoError(err)
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	suite.Require().NoError(err)
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	suite.Require().NoError(err)
+
+	contents := map[string]string{}
+	for _, f := range archive.File {
+		r, err := f.Open()
+		suite.Require().NoError(err)
+		body, _ := io.ReadAll(r)
+		r.Close()
+		contents[f.Name] = string(body)
+	}
+	assert.Contains(suite.T(), contents["profile.json"], "alice@example.com")
+	assert.Contains(suite.T(), contents["projects.json"], "orders")
+	assert.Contains(suite.T(), contents["projects.json"], `"bob"`)
+	assert.NotContains(suite.T(), contents["projects.json"], "bob@example.com")
+	assert.Contains(suite.T(), contents, "activity/logins.json")
+}
+
+// Test BuildExport - A failed build is retried, and marks the export failed on the last attempt
+func (suite *DataExportServiceTestSuite) TestBuildExport_Failure() {
+	export := &models.DataExport{ID: uuid.New(), UserID: suite.user.ID, Status: models.DataExportPending}
+	suite.mockExportRepo.On("GetByID", export.ID).Return(export, nil)
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(nil, errors.New("database error"))
+	suite.mockExportRepo.On("Update", mock.AnythingOfType("*models.DataExport")).Return(nil)
+	payload, _ := json.Marshal(buildDataExportJob{ExportID: export.ID})
+	job := &jobs.Job{Type: JobBuildDataExport, Payload: payload, Attempt: 1, MaxAttempts: 2}
+
+	suite.Error(suite.service.BuildExport(context.Background(), job))
+	suite.mockExportRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+
+	job.Attempt = 2
+	suite.NoError(suite.service.BuildExport(context.Background(), job))
+	assert.Equal(suite.T(), models.DataExportFailed, export.Status)
+	suite.mockExportRepo.AssertCalled(suite.T(), "Update", export)
+}
+
+// Test BuildExport - An export no longer pending is not built again
+func (suite *DataExportServiceTestSuite) TestBuildExport_NotPending() {
+	export := suite.readyExport()
+	suite.mockExportRepo.On("GetByID", export.ID).Return(export, nil)
+	payload, _ := json.Marshal(buildDataExportJob{ExportID: export.ID})
+
+	err := suite.service.BuildExport(context.Background(), &jobs.Job{Type: JobBuildDataExport, Payload: payload, Attempt: 1, MaxAttempts: 3})
+
+	suite.NoError(err)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "GetByID", mock.Anything)
+}
+
+// Test StartExport - A job that cannot be queued fails the export
+func (suite *DataExportServiceTestSuite) TestStartExport_EnqueueFails() {
+	suite.queue.err = errors.New("redis unavailable")
+	suite.mockExportRepo.On("GetByUserID", suite.user.ID).Return([]*models.DataExport{}, nil)
+	suite.mockExportRepo.On("Create", mock.AnythingOfType("*models.DataExport")).Return(uuid.New(), nil)
+	suite.mockExportRepo.On("Update", mock.MatchedBy(func(export *models.DataExport) bool {
+		return export.Status == models.DataExportFailed
+	})).Return(nil).Once()
+
+	export, err := suite.service.StartExport(suite.user.ID)
+
+	suite.Error(err)
+	suite.Nil(export)
+	suite.mockExportRepo.AssertExpectations(suite.T())
+}
+
+// Test StartExport - An export being built is returned instead of starting another
+func (suite *DataExportServiceTestSuite) TestStartExport_AlreadyPending() {
+	pending := &models.DataExport{ID: uuid.New(), UserID: suite.user.ID, Status: models.DataExportPending, CreatedAt: suite.now.Add(-time.Minute)}
+	suite.mockExportRepo.On("GetByUserID", suite.user.ID).Return([]*models.DataExport{pending}, nil)
+
+	export, err := suite.service.StartExport(suite.user.ID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), pending, export)
+	suite.mockExportRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test GetExport - Expired exports are deleted with their files
+func (suite *DataExportServiceTestSuite) TestGetExport_DeletesExpired() {
+	key := storage.PrivatePrefix + suite.user.ID.String() + "/old.zip"
+	suite.Require().NoError(suite.store.Put(context.Background(), key, "application/zip", []byte("zip")))
+	expiresAt := suite.now.Add(-time.Hour)
+	expired := &models.DataExport{ID: uuid.New(), UserID: suite.user.ID, Status: models.DataExportReady, StorageKey: key, ExpiresAt: &expiresAt}
+	suite.mockExportRepo.On("GetByUserID", suite.user.ID).Return([]*models.DataExport{expired}, nil)
+	suite.mockExportRepo.On("Delete", expired.ID).Return(nil)
+	suite.mockExportRepo.On("Create", mock.AnythingOfType("*models.DataExport")).Return(uuid.New(), nil)
+	suite.mockExportRepo.On("Update", mock.AnythingOfType("*models.DataExport")).Return(nil)
+	suite.expectUserData()
+
+	_, err := suite.service.GetExport(suite.user.ID)
+
+	assert.NoError(suite.T(), err)
+	suite.mockExportRepo.AssertCalled(suite.T(), "Delete", expired.ID)
+	_, err = suite.store.Open(context.Background(), key)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *DataExportServiceTestSuite) readyExport() *models.DataExport {
+	key := storage.PrivatePrefix + suite.user.ID.String() + "/export.zip"
+	suite.Require().NoError(suite.store.Put(context.Background(), key, "application/zip", []byte("zip")))
+	expiresAt := suite.now.Add(time.Hour)
+	return &models.DataExport{ID: uuid.New(), UserID: suite.user.ID, Status: models.DataExportReady, StorageKey: key, SizeBytes: 3, ExpiresAt: &expiresAt}
+}
+
+// Test OpenDownload - The signed link of a ready export opens its archive
+func (suite *DataExportServiceTestSuite) TestOpenDownload_Success() {
+	export := suite.readyExport()
+	suite.mockExportRepo.On("GetByID", export.ID).Return(export, nil)
+	link, err := url.Parse(suite.service.DownloadURL(export))
+	suite.Require().NoError(err)
+	assert.True(suite.T(), strings.HasPrefix(link.String(), "https://ezmodel.example.com/api/exports/"+export.ID.String()+"/download?"))
+
+	file, opened, err := suite.service.OpenDownload(context.Background(), export.ID, link.Query().Get("expires"), link.Query().Get("signature"))
+
+	suite.Require().NoError(err)
+	defer file.Close()
+	data, _ := io.ReadAll(file)
+	assert.Equal(suite.T(), "zip", string(data))
+	assert.Equal(suite.T(), export, opened)
+}
+
+// Test OpenDownload - Links for another export, with a changed expiry, or past
+// their expiry are rejected
+func (suite *DataExportServiceTestSuite) TestOpenDownload_InvalidLink() {
+	export := suite.readyExport()
+	link, err := url.Parse(suite.service.DownloadURL(export))
+	suite.Require().NoError(err)
+	expires, signature := link.Query().Get("expires"), link.Query().Get("signature")
+
+	_, _, err = suite.service.OpenDownload(context.Background(), uuid.New(), expires, signature)
+	assert.ErrorIs(suite.T(), err, ErrInvalidDownloadLink)
+
+	_, _, err = suite.service.OpenDownload(context.Background(), export.ID, expires+"0", signature)
+	assert.ErrorIs(suite.T(), err, ErrInvalidDownloadLink)
+
+	suite.now = suite.now.Add(2 * time.Hour)
+	_, _, err = suite.service.OpenDownload(context.Background(), export.ID, expires, signature)
+	assert.ErrorIs(suite.T(), err, ErrInvalidDownloadLink)
+	suite.mockExportRepo.AssertNotCalled(suite.T(), "GetByID", mock.Anything)
+}
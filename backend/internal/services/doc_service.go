@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/markdown"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DocService keeps a Markdown document with each project, for the reasons
+// behind the model. Every edit is saved as a revision holding the whole
+// document, so earlier versions stay readable. Anyone with access to a
+// project reads its doc; those who may modify it edit it, and collaborators
+// hear of each edit.
+type DocService struct {
+	docRepo              repository.DocRepositoryInterface
+	authService          AuthorizationServiceInterface
+	collaborationService CollaborationSessionServiceInterface
+	unitOfWork           *UnitOfWork
+}
+
+func NewDocService(docRepo repository.DocRepositoryInterface, authService AuthorizationServiceInterface, collaborationService CollaborationSessionServiceInterface, unitOfWork *UnitOfWork) *DocService {
+	return &DocService{
+		docRepo:              docRepo,
+		authService:          authService,
+		collaborationService: collaborationService,
+		unitOfWork:           unitOfWork,
+	}
+}
+
+// GetDoc returns a version of the project's doc, the current one for version
+// 0. A doc never edited is empty, at version 0.
+func (s *DocService) GetDoc(projectID uuid.UUID, version int64, userID uuid.UUID) (*models.DocRevision, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	if version == 0 {
+		return s.currentDoc(projectID)
+	}
+
+	revision, err := s.docRepo.GetByVersion(projectID, version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDocRevisionNotFound
+		}
+		return nil, err
+	}
+	return revision, nil
+}
+
+// GetDocRevisions returns the edits of the project's doc newest first,
+// without their content
+func (s *DocService) GetDocRevisions(projectID, userID uuid.UUID) ([]*models.DocRevision, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.docRepo.GetByProjectID(projectID)
+}
+
+// UpdateDoc saves the content as the next version of the project's doc.
+// Given a version other than the current one, or when another edit is saved
+// first, it returns the current doc with ErrVersionConflict. Content equal to
+// the current doc's is not saved again.
+func (s *DocService) UpdateDoc(projectID uuid.UUID, req *dto.UpdateDocRequest, userID uuid.UUID) (*models.DocRevision, error) {
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModify {
+		return nil, ErrForbidden
+	}
+
+	current, err := s.currentDoc(projectID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Version != nil && *req.Version != current.Version {
+		return current, ErrVersionConflict
+	}
+	if req.Content == current.Content {
+		return current, nil
+	}
+
+	revision := &models.DocRevision{
+		ProjectID: projectID,
+		Version:   current.Version + 1,
+		Content:   req.Content,
+		EditedBy:  userID,
+	}
+
+	// Save with the notification, so collaborators fetch a version that exists
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		id, err := tx.Docs.Create(revision)
+		if err != nil {
+			return err
+		}
+		revision.ID = id
+
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyDocUpdated(projectID, revision, userID); err != nil {
+				return err
+			}
+		}
+		revision.Sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			current, err := s.currentDoc(projectID)
+			if err != nil {
+				return nil, err
+			}
+			return current, ErrVersionConflict
+		}
+		return nil, err
+	}
+	return revision, nil
+}
+
+// RenderDoc returns a version of the project's doc as HTML, the current one
+// for version 0
+func (s *DocService) RenderDoc(projectID uuid.UUID, version int64, userID uuid.UUID) ([]byte, error) {
+	doc, err := s.GetDoc(projectID, version, userID)
+	if err != nil {
+		return nil, err
+	}
+	return markdown.Render(doc.Content)
+}
+
+// currentDoc returns the latest revision of the project's doc, or an empty
+// doc at version 0 when it was never edited
+func (s *DocService) currentDoc(projectID uuid.UUID) (*models.DocRevision, error) {
+	revision, err := s.docRepo.GetLatest(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &models.DocRevision{ProjectID: projectID}, nil
+		}
+		return nil, err
+	}
+	return revision, nil
+}
+
+func (s *DocService) checkAccess(projectID, userID uuid.UUID) error {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !canAccess {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type DocServiceTestSuite struct {
+	suite.Suite
+	mockDocRepo              *mockRepo.MockDocRepository
+	mockAuthService          *mockAuthorizationService
+	mockCollaborationService *mockCollaborationService
+	service                  *DocService
+	projectID                uuid.UUID
+	userID                   uuid.UUID
+}
+
+func (suite *DocServiceTestSuite) SetupTest() {
+	suite.mockDocRepo = new(mockRepo.MockDocRepository)
+	suite.mockAuthService = new(mockAuthorizationService)
+	suite.mockCollaborationService = new(mockCollaborationService)
+	suite.service = NewDocService(suite.mockDocRepo, suite.mockAuthService, suite.mockCollaborationService,
+		newTestUnitOfWork(repository.Repositories{Docs: suite.mockDocRepo}))
+	suite.projectID = uuid.New()
+	suite.userID = uuid.New()
+}
+
+func TestDocServiceSuite(t *testing.T) {
+	suite.Run(t, new(DocServiceTestSuite))
+}
+
+// Test GetDoc - A doc never edited is empty, at version 0
+func (suite *DocServiceTestSuite) TestGetDoc_NeverEdited() {
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockDocRepo.On("GetLatest", suite.projectID).Return(nil, gorm.ErrRecordNotFound)
+
+	doc, err := suite.service.GetDoc(suite.projectID, 0, suite.userID)
+
+	suite.NoError(err)
+	suite.Equal(&models.DocRevision{ProjectID: suite.projectID}, doc)
+}
+
+// Test GetDoc - Earlier versions are read by number
+func (suite *DocServiceTestSuite) TestGetDoc_Version() {
+	revision := &models.DocRevision{ID: uuid.New(), ProjectID: suite.projectID, Version: 2, Content: "# Orders"}
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockDocRepo.On("GetByVersion", suite.projectID, int64(2)).Return(revision, nil)
+	suite.mockDocRepo.On("GetByVersion", suite.projectID, int64(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	doc, err := suite.service.GetDoc(suite.projectID, 2, suite.userID)
+	suite.NoError(err)
+	suite.Equal(revision, doc)
+
+	doc, err = suite.service.GetDoc(suite.projectID, 9, suite.userID)
+	suite.ErrorIs(err, ErrDocRevisionNotFound)
+	suite.Nil(doc)
+}
+
+// Test GetDoc - Users without access to the project are refused
+func (suite *DocServiceTestSuite) TestGetDoc_Forbidden() {
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, suite.projectID).Return(false, nil)
+
+	doc, err := suite.service.GetDoc(suite.projectID, 0, suite.userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(doc)
+	suite.mockDocRepo.AssertNotCalled(suite.T(), "GetLatest", mock.Anything)
+}
+
+// Test UpdateDoc - The edit is saved as the next version and collaborators notified
+func (suite *DocServiceTestSuite) TestUpdateDoc_Success() {
+	current := &models.DocRevision{ID: uuid.New(), ProjectID: suite.projectID, Version: 3, Content: "Old"}
+	revisionID := uuid.New()
+	version := int64(3)
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockDocRepo.On("GetLatest", suite.projectID).Return(current, nil)
+	suite.mockDocRepo.On("Create", mock.MatchedBy(func(revision *models.DocRevision) bool {
+		return revision.ProjectID == suite.projectID && revision.Version == 4 && revision.Content == "New" && revision.EditedBy == suite.userID
+	})).Return(revisionID, nil)
+	suite.mockCollaborationService.On("NotifyDocUpdated", suite.projectID, mock.AnythingOfType("*models.DocRevision"), suite.userID).Return(nil)
+
+	doc, err := suite.service.UpdateDoc(suite.projectID, &dto.UpdateDocRequest{Content: "New", Version: &version}, suite.userID)
+
+	suite.NoError(err)
+	suite.Equal(revisionID, doc.ID)
+	suite.Equal(int64(4), doc.Version)
+	suite.mockDocRepo.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertExpectations(suite.T())
+}
+
+// Test UpdateDoc - An edit of an earlier version returns the current doc
+func (suite *DocServiceTestSuite) TestUpdateDoc_StaleVersion() {
+	current := &models.DocRevision{ID: uuid.New(), ProjectID: suite.projectID, Version: 3, Content: "Current"}
+	version := int64(2)
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockDocRepo.On("GetLatest", suite.projectID).Return(current, nil)
+
+	doc, err := suite.service.UpdateDoc(suite.projectID, &dto.UpdateDocRequest{Content: "New", Version: &version}, suite.userID)
+
+	suite.ErrorIs(err, ErrVersionConflict)
+	suite.Equal(current, doc)
+	suite.mockDocRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test UpdateDoc - Losing to a concurrent edit returns the doc it saved
+func (suite *DocServiceTestSuite) TestUpdateDoc_ConcurrentEdit() {
+	read := &models.DocRevision{ID: uuid.New(), ProjectID: suite.projectID, Version: 1, Content: "Old"}
+	saved := &models.DocRevision{ID: uuid.New(), ProjectID: suite.projectID, Version: 2, Content: "Theirs"}
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockDocRepo.On("GetLatest", suite.projectID).Return(read, nil).Once()
+	suite.mockDocRepo.On("GetLatest", suite.projectID).Return(saved, nil).Once()
+	suite.mockDocRepo.On("Create", mock.AnythingOfType("*models.DocRevision")).Return(uuid.Nil, repository.ErrVersionConflict)
+
+	doc, err := suite.service.UpdateDoc(suite.projectID, &dto.UpdateDocRequest{Content: "Mine"}, suite.userID)
+
+	suite.ErrorIs(err, ErrVersionConflict)
+	suite.Equal(saved, doc)
+	suite.mockCollaborationService.AssertNotCalled(suite.T(), "NotifyDocUpdated", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test UpdateDoc - Content equal to the current doc's saves no version
+func (suite *DocServiceTestSuite) TestUpdateDoc_Unchanged() {
+	current := &models.DocRevision{ID: uuid.New(), ProjectID: suite.projectID, Version: 3, Content: "Same"}
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockDocRepo.On("GetLatest", suite.projectID).Return(current, nil)
+
+	doc, err := suite.service.UpdateDoc(suite.projectID, &dto.UpdateDocRequest{Content: "Same"}, suite.userID)
+
+	suite.NoError(err)
+	suite.Equal(current, doc)
+	suite.mockDocRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test UpdateDoc - Viewers cannot edit the doc
+func (suite *DocServiceTestSuite) TestUpdateDoc_Forbidden() {
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(false, nil)
+
+	doc, err := suite.service.UpdateDoc(suite.projectID, &dto.UpdateDocRequest{Content: "New"}, suite.userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(doc)
+	suite.mockDocRepo.AssertNotCalled(suite.T(), "GetLatest", mock.Anything)
+}
+
+// Test RenderDoc - The current doc is rendered as HTML
+func (suite *DocServiceTestSuite) TestRenderDoc() {
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockDocRepo.On("GetLatest", suite.projectID).Return(&models.DocRevision{ProjectID: suite.projectID, Version: 1, Content: "Orders are **never** deleted"}, nil)
+
+	html, err := suite.service.RenderDoc(suite.projectID, 0, suite.userID)
+
+	suite.NoError(err)
+	suite.Equal("<p>Orders are <strong>never</strong> deleted</p>\n", string(html))
+}
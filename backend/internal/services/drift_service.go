@@ -0,0 +1,797 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+	"github.com/Bug-Bugger/ezmodel/internal/introspect"
+	"github.com/Bug-Bugger/ezmodel/internal/jobs"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/netguard"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Types of the drift jobs: the daily run, which starts a check of every
+// project whose connection profile asks for it, a single check and the
+// profiling of a project's data
+const (
+	JobScheduleDriftChecks = "drift.schedule"
+	JobCheckDrift          = "drift.check"
+	JobProfileData         = "drift.profile_data"
+)
+
+const (
+	driftChecksKept = 50 // Checks kept per project, newest first
+	webhookTimeout  = 10 * time.Second
+
+	// DriftSignatureHeader carries the HMAC-SHA256 of a webhook delivery's body
+	// under the profile's webhook secret, as sha256=<hex>
+	DriftSignatureHeader = "X-EzModel-Signature"
+)
+
+// checkDriftJob is the payload of a JobCheckDrift job
+type checkDriftJob struct {
+	CheckID uuid.UUID `json:"check_id"`
+}
+
+// profileDataJob is the payload of a JobProfileData job
+type profileDataJob struct {
+	ProjectID uuid.UUID `json:"project_id"`
+}
+
+// DataProfile is the latest profiling of a project's live database: how many
+// rows its tables have and how their columns are filled, attached to the
+// tables and fields of the model of the same names
+type DataProfile struct {
+	Status     string // Empty when never profiled
+	Error      string
+	ProfiledAt *time.Time
+	Tables     []*models.TableProfile
+	Fields     []*models.FieldProfile
+}
+
+// ConnectionProfile is a project's connection profile as shown, its secrets hidden
+type ConnectionProfile struct {
+	ProjectID       uuid.UUID
+	DSN             string // Password redacted
+	SchemaName      string
+	ScheduledChecks bool
+	WebhookURL      string
+	WebhookSigned   bool
+	UpdatedAt       time.Time
+}
+
+// driftWebhook is the body POSTed to a profile's webhook when drift is found
+type driftWebhook struct {
+	Event     string       `json:"event"`
+	ProjectID uuid.UUID    `json:"project_id"`
+	CheckID   uuid.UUID    `json:"check_id"`
+	CheckedAt time.Time    `json:"checked_at"`
+	Summary   driftSummary `json:"summary"`
+}
+
+// driftSummary counts what differs; the check itself has the details
+type driftSummary struct {
+	AddedTables          int `json:"added_tables"`
+	RemovedTables        int `json:"removed_tables"`
+	ChangedTables        int `json:"changed_tables"`
+	AddedRelationships   int `json:"added_relationships"`
+	RemovedRelationships int `json:"removed_relationships"`
+	ChangedRelationships int `json:"changed_relationships"`
+}
+
+// DriftService checks where projects' live databases deviate from their
+// models. A project's connection profile says how its database is reached;
+// the connection string is stored encrypted and the database only read.
+// Checks run as jobs, on demand or daily, and the profile's webhook is called
+// when a check finds drift that differs from the previous check's. The
+// database's statistics can be profiled too, on demand, to show how much data
+// each table and field holds.
+type DriftService struct {
+	driftRepo   repository.DriftRepositoryInterface
+	projectRepo repository.ProjectRepositoryInterface
+	unitOfWork  *UnitOfWork
+	authService AuthorizationServiceInterface
+	jobs        jobs.Queue
+	aead        cipher.AEAD
+	introspect  func(ctx context.Context, dsn, schema string) (schemadiff.Schema, error)
+	stats       func(ctx context.Context, dsn, schema string) ([]introspect.TableStats, error)
+	httpClient  *http.Client
+	timeout     time.Duration
+	now         func() time.Time
+}
+
+func NewDriftService(
+	cfg *config.Config,
+	driftRepo repository.DriftRepositoryInterface,
+	projectRepo repository.ProjectRepositoryInterface,
+	unitOfWork *UnitOfWork,
+	authService AuthorizationServiceInterface,
+	jobQueue jobs.Queue,
+) *DriftService {
+	key := sha256.Sum256([]byte("connection-profile:" + cfg.JWT.Secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		panic(err) // A 32-byte key is always valid
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic(err)
+	}
+	return &DriftService{
+		driftRepo:   driftRepo,
+		projectRepo: projectRepo,
+		unitOfWork:  unitOfWork,
+		authService: authService,
+		jobs:        jobQueue,
+		aead:        aead,
+		introspect:  introspect.Postgres,
+		stats:       introspect.PostgresStats,
+		httpClient:  newWebhookClient(),
+		timeout:     cfg.Drift.Timeout,
+		now:         time.Now,
+	}
+}
+
+// GetProfile returns the project's connection profile to those who may modify the project
+func (s *DriftService) GetProfile(projectID, userID uuid.UUID) (*ConnectionProfile, error) {
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModify {
+		return nil, ErrForbidden
+	}
+
+	profile, err := s.profile(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return s.connectionProfile(profile)
+}
+
+// SetProfile creates or replaces the project's connection profile; only the
+// owner may. The connection string is kept when none is given for a project
+// that has one, and so is the webhook secret when nil.
+func (s *DriftService) SetProfile(projectID, userID uuid.UUID, req *dto.SetConnectionProfileRequest) (*ConnectionProfile, error) {
+	if err := s.checkOwner(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.profile(projectID)
+	if err != nil && !errors.Is(err, ErrConnectionProfileNotFound) {
+		return nil, err
+	}
+	now := s.now()
+	if profile == nil {
+		if req.DSN == "" {
+			return nil, ErrInvalidInput
+		}
+		profile = &models.ConnectionProfile{ProjectID: projectID, CreatedBy: userID, CreatedAt: now}
+	}
+
+	if req.DSN != "" {
+		if err := introspect.ValidateDSN(req.DSN); err != nil {
+			return nil, ErrInvalidInput
+		}
+		if profile.DSN, err = s.seal(req.DSN); err != nil {
+			return nil, err
+		}
+	}
+	profile.SchemaName = req.SchemaName
+	if profile.SchemaName == "" {
+		profile.SchemaName = "public"
+	}
+	profile.ScheduledChecks = req.ScheduledChecks == nil || *req.ScheduledChecks
+	if req.WebhookURL != "" {
+		if err := validateWebhookURL(req.WebhookURL); err != nil {
+			return nil, err
+		}
+	}
+	profile.WebhookURL = req.WebhookURL
+	if req.WebhookSecret != nil {
+		if profile.WebhookSecret, err = s.seal(*req.WebhookSecret); err != nil {
+			return nil, err
+		}
+	}
+	profile.UpdatedAt = now
+
+	if err := s.driftRepo.SaveProfile(profile); err != nil {
+		return nil, err
+	}
+	return s.connectionProfile(profile)
+}
+
+// DeleteProfile removes the project's connection profile; only the owner may.
+// Past checks are kept.
+func (s *DriftService) DeleteProfile(projectID, userID uuid.UUID) error {
+	if err := s.checkOwner(projectID, userID); err != nil {
+		return err
+	}
+	if _, err := s.profile(projectID); err != nil {
+		return err
+	}
+	return s.driftRepo.DeleteProfile(projectID)
+}
+
+// StartCheck starts checking the project's database for drift, unless a
+// check is already running
+func (s *DriftService) StartCheck(projectID, userID uuid.UUID) (*models.DriftCheck, error) {
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModify {
+		return nil, ErrForbidden
+	}
+	if _, err := s.profile(projectID); err != nil {
+		return nil, err
+	}
+
+	checks, err := s.driftRepo.GetChecksByProjectID(projectID, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(checks) > 0 && checks[0].Status == models.DriftCheckPending {
+		return checks[0], nil
+	}
+	return s.start(context.Background(), projectID, models.DriftTriggerManual)
+}
+
+// GetChecks returns the project's latest checks newest first, without their diffs
+func (s *DriftService) GetChecks(projectID, userID uuid.UUID) ([]*models.DriftCheck, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.driftRepo.GetChecksByProjectID(projectID, driftChecksKept)
+}
+
+// GetCheck returns a check of the project with its diff, which is nil unless the check completed
+func (s *DriftService) GetCheck(projectID, checkID, userID uuid.UUID) (*models.DriftCheck, *schemadiff.Diff, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, nil, err
+	}
+	check, err := s.driftRepo.GetCheck(checkID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrDriftCheckNotFound
+		}
+		return nil, nil, err
+	}
+	if check.ProjectID != projectID {
+		return nil, nil, ErrDriftCheckNotFound
+	}
+	if check.Diff == "" {
+		return check, nil, nil
+	}
+	var diff schemadiff.Diff
+	if err := json.Unmarshal([]byte(check.Diff), &diff); err != nil {
+		return nil, nil, err
+	}
+	return check, &diff, nil
+}
+
+// ScheduleChecks is the handler of JobScheduleDriftChecks jobs. It starts a
+// check of every project whose profile asks for daily checks, skipping those
+// already checked by schedule today, e.g. as this run is retried.
+func (s *DriftService) ScheduleChecks(ctx context.Context, job *jobs.Job) error {
+	projectIDs, err := s.driftRepo.GetProjectIDsToCheck()
+	if err != nil {
+		return err
+	}
+
+	day := s.now().UTC().Format("2006-01-02")
+	started := 0
+	for _, projectID := range projectIDs {
+		checks, err := s.driftRepo.GetChecksByProjectID(projectID, 1)
+		if err != nil {
+			return err
+		}
+		if len(checks) > 0 && (checks[0].Status == models.DriftCheckPending ||
+			(checks[0].Trigger == models.DriftTriggerScheduled && checks[0].CreatedAt.UTC().Format("2006-01-02") == day)) {
+			continue
+		}
+		if _, err := s.start(ctx, projectID, models.DriftTriggerScheduled); err != nil {
+			return err
+		}
+		started++
+	}
+	log.Printf("Scheduled drift checks of %d projects", started)
+	return nil
+}
+
+// RunCheck is the handler of JobCheckDrift jobs. It reads the live database
+// and the model, saves what differs and calls the webhook when the drift is
+// new. The check is marked failed once the job runs out of attempts. Checks
+// no longer pending are left alone.
+func (s *DriftService) RunCheck(ctx context.Context, job *jobs.Job) error {
+	var payload checkDriftJob
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+	check, err := s.driftRepo.GetCheck(payload.CheckID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if check.Status != models.DriftCheckPending {
+		return nil
+	}
+
+	profile, err := s.profile(check.ProjectID)
+	if err != nil {
+		if !errors.Is(err, ErrConnectionProfileNotFound) {
+			return err
+		}
+		return s.fail(check, "The connection profile was removed")
+	}
+	previous, err := s.driftRepo.GetLatestCompletedCheck(check.ProjectID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	diff, err := s.compare(ctx, profile)
+	if err != nil {
+		if !job.LastAttempt() {
+			return err
+		}
+		log.Printf("Failed to check project %s for drift: %v", check.ProjectID, err)
+		return s.fail(check, err.Error())
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	check.Status = models.DriftCheckClean
+	if !diff.Empty() {
+		check.Status = models.DriftCheckDrifted
+	}
+	check.Diff = string(data)
+	check.CompletedAt = &now
+	if err := s.driftRepo.UpdateCheck(check); err != nil {
+		return err
+	}
+
+	if check.Status == models.DriftCheckDrifted && (previous == nil || previous.Status != models.DriftCheckDrifted || previous.Diff != check.Diff) {
+		if err := s.notify(ctx, profile, check, diff); err != nil {
+			log.Printf("Failed to deliver the drift webhook of project %s: %v", check.ProjectID, err)
+			errorreport.Capture(ctx, err, errorreport.ProjectTag(check.ProjectID))
+		}
+	}
+	return s.driftRepo.PruneChecks(check.ProjectID, driftChecksKept)
+}
+
+// StartDataProfile starts profiling the project's live database, unless it
+// is already being profiled
+func (s *DriftService) StartDataProfile(projectID, userID uuid.UUID) (*DataProfile, error) {
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModify {
+		return nil, ErrForbidden
+	}
+	profile, err := s.profile(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile.DataProfileStatus != models.DataProfilePending {
+		if err := s.driftRepo.SetDataProfileStatus(projectID, models.DataProfilePending, ""); err != nil {
+			return nil, err
+		}
+		if _, err := s.jobs.Enqueue(context.Background(), JobProfileData, profileDataJob{ProjectID: projectID}); err != nil {
+			if failErr := s.driftRepo.SetDataProfileStatus(projectID, models.DataProfileFailed, "The profiling could not be started"); failErr != nil {
+				log.Printf("Failed to save the data profile status of project %s: %v", projectID, failErr)
+			}
+			return nil, err
+		}
+		profile.DataProfileStatus = models.DataProfilePending
+		profile.DataProfileError = ""
+	}
+	return s.dataProfile(profile)
+}
+
+// GetDataProfile returns the latest profiling of the project's live database
+func (s *DriftService) GetDataProfile(projectID, userID uuid.UUID) (*DataProfile, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	profile, err := s.profile(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return s.dataProfile(profile)
+}
+
+// ProfileData is the handler of JobProfileData jobs. It reads the statistics
+// the live database keeps and attaches them to the tables and fields of the
+// model with the same names, replacing those of the previous profiling. The
+// profiling is marked failed once the job runs out of attempts.
+func (s *DriftService) ProfileData(ctx context.Context, job *jobs.Job) error {
+	var payload profileDataJob
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+	profile, err := s.profile(payload.ProjectID)
+	if err != nil {
+		if errors.Is(err, ErrConnectionProfileNotFound) {
+			return nil // Removed since the profiling started
+		}
+		return err
+	}
+	if profile.DataProfileStatus != models.DataProfilePending {
+		return nil
+	}
+
+	tables, fields, err := s.readDataProfile(ctx, profile)
+	if err != nil {
+		if !job.LastAttempt() {
+			return err
+		}
+		log.Printf("Failed to profile the data of project %s: %v", profile.ProjectID, err)
+		return s.driftRepo.SetDataProfileStatus(profile.ProjectID, models.DataProfileFailed, err.Error())
+	}
+	return s.driftRepo.ReplaceDataProfile(profile.ProjectID, tables, fields, s.now())
+}
+
+// readDataProfile reads the live database's statistics and matches them to the model by name
+func (s *DriftService) readDataProfile(ctx context.Context, profile *models.ConnectionProfile) ([]*models.TableProfile, []*models.FieldProfile, error) {
+	dsn, err := s.open(profile.DSN)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	stats, err := s.stats(ctx, dsn, profile.SchemaName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var modelTables []*models.Table
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		modelTables, err = tx.Tables.GetByProjectID(profile.ProjectID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	statsByTable := make(map[string]introspect.TableStats, len(stats))
+	for _, table := range stats {
+		statsByTable[table.Name] = table
+	}
+	now := s.now()
+	var tables []*models.TableProfile
+	var fields []*models.FieldProfile
+	for _, table := range modelTables {
+		tableStats, ok := statsByTable[table.Name]
+		if !ok {
+			continue
+		}
+		tables = append(tables, &models.TableProfile{TableID: table.ID, ProjectID: profile.ProjectID, RowCount: tableStats.RowCount, ProfiledAt: now})
+
+		columns := make(map[string]introspect.ColumnStats, len(tableStats.Columns))
+		for _, column := range tableStats.Columns {
+			columns[column.Name] = column
+		}
+		for _, field := range table.Fields {
+			column, ok := columns[field.Name]
+			if !ok {
+				continue
+			}
+			fields = append(fields, &models.FieldProfile{
+				FieldID:       field.ID,
+				TableID:       table.ID,
+				ProjectID:     profile.ProjectID,
+				NullRatio:     column.NullRatio,
+				DistinctCount: column.DistinctCount,
+				ProfiledAt:    now,
+			})
+		}
+	}
+	return tables, fields, nil
+}
+
+func (s *DriftService) dataProfile(profile *models.ConnectionProfile) (*DataProfile, error) {
+	tables, fields, err := s.driftRepo.GetDataProfile(profile.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	return &DataProfile{
+		Status:     profile.DataProfileStatus,
+		Error:      profile.DataProfileError,
+		ProfiledAt: profile.DataProfiledAt,
+		Tables:     tables,
+		Fields:     fields,
+	}, nil
+}
+
+// start saves a pending check and enqueues the job running it
+func (s *DriftService) start(ctx context.Context, projectID uuid.UUID, trigger string) (*models.DriftCheck, error) {
+	check := &models.DriftCheck{
+		ProjectID: projectID,
+		Status:    models.DriftCheckPending,
+		Trigger:   trigger,
+		CreatedAt: s.now(),
+	}
+	id, err := s.driftRepo.CreateCheck(check)
+	if err != nil {
+		return nil, err
+	}
+	check.ID = id
+
+	if _, err := s.jobs.Enqueue(ctx, JobCheckDrift, checkDriftJob{CheckID: id}); err != nil {
+		if failErr := s.fail(check, "The check could not be started"); failErr != nil {
+			log.Printf("Failed to save drift check %s: %v", check.ID, failErr)
+		}
+		return nil, err
+	}
+	return check, nil
+}
+
+func (s *DriftService) fail(check *models.DriftCheck, reason string) error {
+	now := s.now()
+	check.Status = models.DriftCheckFailed
+	check.Error = reason
+	check.CompletedAt = &now
+	return s.driftRepo.UpdateCheck(check)
+}
+
+// compare returns what changes from the project's model to its live database
+func (s *DriftService) compare(ctx context.Context, profile *models.ConnectionProfile) (*schemadiff.Diff, error) {
+	dsn, err := s.open(profile.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	live, err := s.introspect(ctx, dsn, profile.SchemaName)
+	if err != nil {
+		return nil, err
+	}
+
+	var model schemadiff.Schema
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		model, err = readDiffSchema(tx, profile.ProjectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schemadiff.Compare(driftSchema(model), driftSchema(live)), nil
+}
+
+// driftSchema prepares a schema for comparing a model to a database. Data
+// types are normalized, as a model may spell them as either side does, and
+// defaults are left out, as databases rewrite them, e.g. 'a' as 'a'::text.
+// Many to many relationships are left out too: a database has join tables
+// instead.
+func driftSchema(schema schemadiff.Schema) schemadiff.Schema {
+	prepared := schemadiff.Schema{Tables: make([]schemadiff.Table, len(schema.Tables))}
+	for i, table := range schema.Tables {
+		prepared.Tables[i] = schemadiff.Table{Name: table.Name, Fields: make([]schemadiff.Field, len(table.Fields))}
+		for j, field := range table.Fields {
+			field.DataType = introspect.NormalizeType(field.DataType)
+			field.DefaultValue = ""
+			prepared.Tables[i].Fields[j] = field
+		}
+	}
+	for _, relationship := range schema.Relationships {
+		if relationship.RelationType != "many_to_many" {
+			prepared.Relationships = append(prepared.Relationships, relationship)
+		}
+	}
+	return prepared
+}
+
+// notify POSTs a summary of the drift to the profile's webhook, if any
+func (s *DriftService) notify(ctx context.Context, profile *models.ConnectionProfile, check *models.DriftCheck, diff *schemadiff.Diff) error {
+	if profile.WebhookURL == "" {
+		return nil
+	}
+	// Profiles saved before webhooks had to be https are not called
+	if err := validateWebhookURL(profile.WebhookURL); err != nil {
+		return err
+	}
+	body, err := json.Marshal(driftWebhook{
+		Event:     "drift.detected",
+		ProjectID: check.ProjectID,
+		CheckID:   check.ID,
+		CheckedAt: *check.CompletedAt,
+		Summary: driftSummary{
+			AddedTables:          len(diff.AddedTables),
+			RemovedTables:        len(diff.RemovedTables),
+			ChangedTables:        len(diff.ChangedTables),
+			AddedRelationships:   len(diff.AddedRelationships),
+			RemovedRelationships: len(diff.RemovedRelationships),
+			ChangedRelationships: len(diff.ChangedRelationships),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, profile.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if profile.WebhookSecret != "" {
+		secret, err := s.open(profile.WebhookSecret)
+		if err != nil {
+			return err
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		req.Header.Set(DriftSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// validateWebhookURL returns ErrInvalidWebhookURL unless rawURL is an https URL
+// whose host, when an IP address, is public. Names are checked when dialed.
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return ErrInvalidWebhookURL
+	}
+	if ip, err := netip.ParseAddr(u.Hostname()); err == nil && !netguard.Public(ip) {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// newWebhookClient returns the client webhooks are delivered with. It dials
+// public addresses only, checking the address actually dialed so a name
+// resolving to a private, loopback or link-local one is refused too, uses no
+// proxy, which would be dialed instead, and follows no redirects.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout, Control: dialPublicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// dialPublicOnly is a net.Dialer Control refusing connections to addresses
+// that are not public
+func dialPublicOnly(network, address string, conn syscall.RawConn) error {
+	if err := netguard.Control(network, address, conn); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookURL, err)
+	}
+	return nil
+}
+
+// profile returns ErrConnectionProfileNotFound for projects without one
+func (s *DriftService) profile(projectID uuid.UUID) (*models.ConnectionProfile, error) {
+	profile, err := s.driftRepo.GetProfile(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrConnectionProfileNotFound
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s *DriftService) connectionProfile(profile *models.ConnectionProfile) (*ConnectionProfile, error) {
+	dsn, err := s.open(profile.DSN)
+	if err != nil {
+		return nil, err
+	}
+	return &ConnectionProfile{
+		ProjectID:       profile.ProjectID,
+		DSN:             introspect.RedactDSN(dsn),
+		SchemaName:      profile.SchemaName,
+		ScheduledChecks: profile.ScheduledChecks,
+		WebhookURL:      profile.WebhookURL,
+		WebhookSigned:   profile.WebhookSecret != "",
+		UpdatedAt:       profile.UpdatedAt,
+	}, nil
+}
+
+func (s *DriftService) checkOwner(projectID, userID uuid.UUID) error {
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProjectNotFound
+		}
+		return err
+	}
+	if project.OwnerID != userID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *DriftService) checkAccess(projectID, userID uuid.UUID) error {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return ErrProjectNotFound
+		}
+		return err
+	}
+	if !canAccess {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// seal encrypts a secret for storage as base64 of the nonce and ciphertext;
+// an empty secret stays empty
+func (s *DriftService) seal(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(s.aead.Seal(nonce, nonce, []byte(plaintext), nil)), nil
+}
+
+// open decrypts a secret sealed by seal
+func (s *DriftService) open(sealed string) (string, error) {
+	if sealed == "" {
+		return "", nil
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < s.aead.NonceSize() {
+		return "", errors.New("malformed sealed secret")
+	}
+	plaintext, err := s.aead.Open(nil, data[:s.aead.NonceSize()], data[s.aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
@@ -1,15 +1,61 @@
 package services
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+)
 
 var (
 	// Shared errors
 	ErrInvalidInput       = errors.New("invalid input")
 	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrAccountLocked      = errors.New("too many failed login attempts")
+	ErrVersionConflict    = errors.New("modified since the given version")
+	ErrQuotaExceeded      = errors.New("quota exceeded")
 
 	// User errors
 	ErrUserNotFound      = errors.New("user not found")
 	ErrUserAlreadyExists = errors.New("user already exists")
+	ErrAccountDisabled   = errors.New("account is disabled")
+	ErrInvalidImage      = errors.New("invalid image")
+	ErrImageTooLarge     = errors.New("image is too large")
+	ErrTransferRequired  = errors.New("owned projects with collaborators need a new owner")
+
+	// Data export errors
+	ErrDataExportNotFound  = errors.New("data export not found")
+	ErrInvalidDownloadLink = errors.New("download link is invalid or expired")
+
+	// Sign-in session errors
+	ErrUserSessionNotFound = errors.New("user session not found")
+	ErrUserSessionRevoked  = errors.New("user session has been revoked")
+
+	// OAuth errors
+	ErrUnknownOAuthProvider  = errors.New("unknown oauth provider")
+	ErrOAuthEmailNotVerified = errors.New("oauth account email is not verified")
+	ErrOAuthExchangeFailed   = errors.New("oauth code exchange failed")
+
+	// SAML errors
+	ErrSAMLAssertionInvalid = errors.New("invalid saml assertion")
+
+	// API token errors
+	ErrAPITokenNotFound = errors.New("api token not found")
+	ErrInvalidAPIToken  = errors.New("invalid api token")
+	ErrInvalidScope     = errors.New("invalid api token scope")
+
+	// Service account errors
+	ErrServiceAccountNotFound = errors.New("service account not found")
+
+	// Admin errors
+	ErrCannotModifySelf       = errors.New("admins cannot perform this action on their own account")
+	ErrCannotImpersonateAdmin = errors.New("admins cannot be impersonated")
+	ErrInvalidRole            = errors.New("invalid role")
+
+	// Billing errors
+	ErrPlanNotFound      = errors.New("plan not found")
+	ErrAlreadySubscribed = errors.New("already subscribed to a plan")
+	ErrInvalidWebhook    = errors.New("invalid webhook")
 
 	// Project errors
 	ErrProjectNotFound      = errors.New("project not found")
@@ -17,16 +63,96 @@ var (
 	ErrUnauthorized         = errors.New("unauthorized")
 	ErrForbidden            = errors.New("forbidden")
 	ErrCollaboratorNotFound = errors.New("collaborator not found")
+	ErrTooManyTags          = errors.New("project has too many tags")
+	ErrUnknownExportFormat  = errors.New("unknown export format")
 
 	// Table errors
-	ErrTableNotFound = errors.New("table not found")
+	ErrTableNotFound  = errors.New("table not found")
+	ErrSchemaConflict = errors.New("tables of the schema already exist")
 
 	// Field errors
 	ErrFieldNotFound = errors.New("field not found")
 
+	// ErrFieldNameTaken is the ErrNameTaken of a field named as another field of
+	// its table, ignoring case
+	ErrFieldNameTaken = fmt.Errorf("field %w", ErrNameTaken)
+
 	// Relationship errors
 	ErrRelationshipNotFound = errors.New("relationship not found")
 
+	// Region errors
+	ErrRegionNotFound = errors.New("region not found")
+
+	// Snippet errors
+	ErrSnippetNotFound = errors.New("snippet not found")
+
+	// Review errors
+	ErrReviewCommentNotFound = errors.New("review comment not found")
+	ErrReviewFailed          = errors.New("schema review failed")
+
+	// Doc errors
+	ErrDocRevisionNotFound = errors.New("doc revision not found")
+
+	// Branch errors
+	ErrBranchNotFound = errors.New("branch not found")
+	ErrBranchMerged   = errors.New("branch was merged")
+	ErrMergeConflict  = errors.New("branch conflicts with the project")
+
+	// Release errors
+	ErrReleaseNotFound  = errors.New("release not found")
+	ErrReleaseLocked    = errors.New("release is locked")
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+
+	// Drift errors
+	ErrConnectionProfileNotFound = errors.New("connection profile not found")
+	ErrDriftCheckNotFound        = errors.New("drift check not found")
+	ErrInvalidWebhookURL         = errors.New("webhook URL must be https and public")
+
+	// Apply errors
+	ErrTargetDatabase     = errors.New("target database failed")
+	ErrApplyPlanChanged   = errors.New("plan changed since reviewed")
+	ErrDestructiveChanges = errors.New("plan drops or converts data")
+
+	// Refactoring errors
+	ErrNameTaken = errors.New("name is taken")
+
 	// Collaboration session errors
 	ErrSessionNotFound = errors.New("collaboration session not found")
 )
+
+// TakenError is a conflict with another record over a value that must be
+// unique, such as an email or a table's name within its project. It is
+// ErrUserAlreadyExists or ErrNameTaken, naming the attribute whose value is
+// taken in Field.
+type TakenError struct {
+	Field string
+	Err   error
+}
+
+func (e *TakenError) Error() string {
+	return e.Field + " is taken: " + e.Err.Error()
+}
+
+func (e *TakenError) Unwrap() error {
+	return e.Err
+}
+
+// takenError turns a write that broke a unique index, having raced past the
+// check for a taken value, into the TakenError the check would have returned
+func takenError(err error) error {
+	var duplicate *repository.DuplicateError
+	if !errors.As(err, &duplicate) {
+		return err
+	}
+	switch duplicate.Index {
+	case repository.IndexUserEmail:
+		return &TakenError{Field: "email", Err: ErrUserAlreadyExists}
+	case repository.IndexUserUsername:
+		return &TakenError{Field: "username", Err: ErrUserAlreadyExists}
+	case repository.IndexTableName:
+		return &TakenError{Field: "name", Err: ErrNameTaken}
+	case repository.IndexFieldName:
+		return &TakenError{Field: "name", Err: ErrFieldNameTaken}
+	}
+	return err
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultEventPageSize is how many events a page of history holds unless asked otherwise
+	DefaultEventPageSize = 100
+
+	// MaxEventPageSize is how many events a page of history holds at most
+	MaxEventPageSize = 1000
+)
+
+// ProjectEvents is a page of a project's event history
+type ProjectEvents struct {
+	Events         []*models.ProjectEvent
+	LatestSequence int64 // The project's sequence when the page was read
+	Truncated      bool  // Events right after the requested sequence are no longer kept
+}
+
+// EventHistoryService keeps the latest schema-change events of each project so
+// clients and integrations can catch up over the API, as WebSocket clients do
+// from the sequence of the messages they receive. It is one of the hub's event
+// sinks, so it records each event once, on the node that broadcast it.
+type EventHistoryService struct {
+	eventRepo   repository.ProjectEventRepositoryInterface
+	projectRepo repository.ProjectRepositoryInterface
+	authService AuthorizationServiceInterface
+	size        int // Events kept per project
+}
+
+func NewEventHistoryService(eventRepo repository.ProjectEventRepositoryInterface, projectRepo repository.ProjectRepositoryInterface, authService AuthorizationServiceInterface, size int) *EventHistoryService {
+	return &EventHistoryService{
+		eventRepo:   eventRepo,
+		projectRepo: projectRepo,
+		authService: authService,
+		size:        size,
+	}
+}
+
+// Name identifies the history in the hub's logs
+func (s *EventHistoryService) Name() string {
+	return "event history"
+}
+
+// Publish records an event exported by the hub and forgets the project's
+// events that no longer fit the history. Events without a sequence cannot be
+// caught up on and are not kept.
+func (s *EventHistoryService) Publish(topic string, data []byte) error {
+	var message websocketPkg.WebSocketMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return fmt.Errorf("failed to decode event: %w", err)
+	}
+	if message.Sequence == 0 {
+		return nil
+	}
+
+	event := &models.ProjectEvent{
+		ProjectID: message.ProjectID,
+		Sequence:  message.Sequence,
+		Type:      string(message.Type),
+		Message:   data,
+		CreatedAt: message.Timestamp,
+	}
+	if err := s.eventRepo.Create(event); err != nil {
+		return err
+	}
+	if oldest := message.Sequence - int64(s.size); oldest > 0 {
+		return s.eventRepo.DeleteUpTo(message.ProjectID, oldest)
+	}
+	return nil
+}
+
+// Close is a no-op; the history has no connection of its own
+func (s *EventHistoryService) Close() error {
+	return nil
+}
+
+// ListEvents returns up to limit of the project's events after sinceSequence,
+// oldest first. When the event right after sinceSequence is no longer kept the
+// page is marked truncated, and the client should reload the project instead.
+func (s *EventHistoryService) ListEvents(projectID, userID uuid.UUID, sinceSequence int64, limit int) (*ProjectEvents, error) {
+	if sinceSequence < 0 || limit < 1 || limit > MaxEventPageSize {
+		return nil, ErrInvalidInput
+	}
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccess {
+		return nil, ErrForbidden
+	}
+
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	events, err := s.eventRepo.GetSince(projectID, sinceSequence, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Events are recorded shortly after they are broadcast, so the latest may
+	// be missing for a moment without having been dropped
+	truncated := sinceSequence < project.Sequence-int64(s.size) ||
+		(len(events) > 0 && events[0].Sequence > sinceSequence+1)
+	return &ProjectEvents{
+		Events:         events,
+		LatestSequence: project.Sequence,
+		Truncated:      truncated,
+	}, nil
+}
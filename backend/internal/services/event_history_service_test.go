@@ -0,0 +1,105 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type EventHistoryServiceTestSuite struct {
+	suite.Suite
+	mockEventRepo   *mockRepo.MockProjectEventRepository
+	mockProjectRepo *mockRepo.MockProjectRepository
+	mockAuthService *mockAuthorizationService
+	service         *EventHistoryService
+	projectID       uuid.UUID
+	userID          uuid.UUID
+}
+
+func (suite *EventHistoryServiceTestSuite) SetupTest() {
+	suite.mockEventRepo = new(mockRepo.MockProjectEventRepository)
+	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
+	suite.mockAuthService = new(mockAuthorizationService)
+	suite.service = NewEventHistoryService(suite.mockEventRepo, suite.mockProjectRepo, suite.mockAuthService, 10)
+	suite.projectID = uuid.New()
+	suite.userID = uuid.New()
+}
+
+func TestEventHistoryServiceSuite(t *testing.T) {
+	suite.Run(t, new(EventHistoryServiceTestSuite))
+}
+
+func (suite *EventHistoryServiceTestSuite) encodedEvent(sequence int64) []byte {
+	message, err := websocketPkg.NewWebSocketMessage(websocketPkg.MessageTypeTableCreated, websocketPkg.TablePayload{Name: "users"}, suite.userID, suite.projectID)
+	suite.Require().NoError(err)
+	message.Sequence = sequence
+	data, err := json.Marshal(message)
+	suite.Require().NoError(err)
+	return data
+}
+
+// Test Publish - Events are recorded and those past the history size forgotten
+func (suite *EventHistoryServiceTestSuite) TestPublish_RecordsAndTrims() {
+	data := suite.encodedEvent(25)
+	suite.mockEventRepo.On("Create", mock.MatchedBy(func(event *models.ProjectEvent) bool {
+		return event.ProjectID == suite.projectID && event.Sequence == 25 && event.Type == "table_created" && string(event.Message) == string(data)
+	})).Return(nil)
+	suite.mockEventRepo.On("DeleteUpTo", suite.projectID, int64(15)).Return(nil)
+
+	suite.NoError(suite.service.Publish("project:"+suite.projectID.String(), data))
+	suite.mockEventRepo.AssertExpectations(suite.T())
+}
+
+// Test Publish - Events without a sequence cannot be caught up on
+func (suite *EventHistoryServiceTestSuite) TestPublish_SkipsUnsequenced() {
+	suite.NoError(suite.service.Publish("project:"+suite.projectID.String(), suite.encodedEvent(0)))
+	suite.mockEventRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test ListEvents - A page continuing from the client's sequence is complete
+func (suite *EventHistoryServiceTestSuite) TestListEvents_Success() {
+	events := []*models.ProjectEvent{{ProjectID: suite.projectID, Sequence: 6, CreatedAt: time.Now()}, {ProjectID: suite.projectID, Sequence: 7}}
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockProjectRepo.On("GetByID", suite.projectID).Return(&models.Project{ID: suite.projectID, Sequence: 7}, nil)
+	suite.mockEventRepo.On("GetSince", suite.projectID, int64(5), 100).Return(events, nil)
+
+	history, err := suite.service.ListEvents(suite.projectID, suite.userID, 5, 100)
+
+	suite.NoError(err)
+	suite.Equal(events, history.Events)
+	suite.Equal(int64(7), history.LatestSequence)
+	suite.False(history.Truncated)
+}
+
+// Test ListEvents - Events the history no longer holds make the page truncated
+func (suite *EventHistoryServiceTestSuite) TestListEvents_Truncated() {
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockProjectRepo.On("GetByID", suite.projectID).Return(&models.Project{ID: suite.projectID, Sequence: 30}, nil)
+	suite.mockEventRepo.On("GetSince", suite.projectID, int64(5), 100).Return([]*models.ProjectEvent{{Sequence: 21}}, nil)
+	suite.mockEventRepo.On("GetSince", suite.projectID, int64(25), 100).Return([]*models.ProjectEvent{{Sequence: 26}}, nil)
+
+	history, err := suite.service.ListEvents(suite.projectID, suite.userID, 5, 100)
+	suite.NoError(err)
+	suite.True(history.Truncated)
+
+	history, err = suite.service.ListEvents(suite.projectID, suite.userID, 25, 100)
+	suite.NoError(err)
+	suite.False(history.Truncated)
+}
+
+// Test ListEvents - Only people with access to the project read its events
+func (suite *EventHistoryServiceTestSuite) TestListEvents_Forbidden() {
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, suite.projectID).Return(false, nil)
+
+	_, err := suite.service.ListEvents(suite.projectID, suite.userID, 0, 100)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.mockEventRepo.AssertNotCalled(suite.T(), "GetSince", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -2,28 +2,37 @@ package services
 
 import (
 	"errors"
+	"math"
 	"strings"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/ddl"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// maxBulkFields bounds the fields created by one CreateFields call
+const maxBulkFields = 200
+
 type FieldService struct {
 	fieldRepo            repository.FieldRepositoryInterface
 	tableRepo            repository.TableRepositoryInterface
 	authService          AuthorizationServiceInterface
 	collaborationService CollaborationSessionServiceInterface
+	projectCache         *ProjectCache
+	unitOfWork           *UnitOfWork
 }
 
-func NewFieldService(fieldRepo repository.FieldRepositoryInterface, tableRepo repository.TableRepositoryInterface, authService AuthorizationServiceInterface, collaborationService CollaborationSessionServiceInterface) *FieldService {
+func NewFieldService(fieldRepo repository.FieldRepositoryInterface, tableRepo repository.TableRepositoryInterface, authService AuthorizationServiceInterface, collaborationService CollaborationSessionServiceInterface, projectCache *ProjectCache, unitOfWork *UnitOfWork) *FieldService {
 	return &FieldService{
 		fieldRepo:            fieldRepo,
 		tableRepo:            tableRepo,
 		authService:          authService,
 		collaborationService: collaborationService,
+		projectCache:         projectCache,
+		unitOfWork:           unitOfWork,
 	}
 }
 
@@ -35,8 +44,13 @@ func (s *FieldService) CreateField(tableID uuid.UUID, req *dto.CreateFieldReques
 		return nil, ErrInvalidInput
 	}
 
-	if len(dataType) < 1 {
-		return nil, ErrInvalidInput
+	if err := validateFieldType(dataType, req.DefaultValue); err != nil {
+		return nil, err
+	}
+
+	metadata, err := newMetadata(req.Metadata)
+	if err != nil {
+		return nil, err
 	}
 
 	// Verify table exists and get project ID for authorization
@@ -57,6 +71,10 @@ func (s *FieldService) CreateField(tableID uuid.UUID, req *dto.CreateFieldReques
 		return nil, ErrForbidden
 	}
 
+	if fieldNameTaken(table.Fields, name, uuid.Nil) {
+		return nil, &TakenError{Field: "name", Err: ErrFieldNameTaken}
+	}
+
 	field := &models.Field{
 		TableID:      tableID,
 		Name:         name,
@@ -65,28 +83,117 @@ func (s *FieldService) CreateField(tableID uuid.UUID, req *dto.CreateFieldReques
 		IsNullable:   req.IsNullable,
 		DefaultValue: req.DefaultValue,
 		Position:     req.Position,
+		Metadata:     metadata,
+	}
+
+	// Persist with the notification, so collaborators hear of the field once it is saved
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		id, err := tx.Fields.Create(field)
+		if err != nil {
+			return err
+		}
+		field.ID = id
+
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyFieldCreated(table.ProjectID, field, userID); err != nil {
+				return err
+			}
+		}
+		field.Sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		return nil, takenError(err)
 	}
 
-	// Generate UUID for the field before broadcasting
-	field.ID = uuid.New()
+	return field, nil
+}
 
-	// Broadcast field creation to collaborators FIRST
-	if s.collaborationService != nil {
-		if err := s.collaborationService.NotifyFieldCreated(table.ProjectID, field, userID); err != nil {
-			// Log error but don't fail the operation
-			// TODO: Add proper logging
+// CreateFields adds fields to a table in the given order, all or none of them.
+// Collaborators get a single message for the whole batch.
+func (s *FieldService) CreateFields(tableID uuid.UUID, reqs []dto.CreateFieldRequest, userID uuid.UUID) ([]*models.Field, error) {
+	if len(reqs) < 1 || len(reqs) > maxBulkFields {
+		return nil, ErrInvalidInput
+	}
+
+	fields := make([]*models.Field, len(reqs))
+	for i, req := range reqs {
+		name := strings.TrimSpace(req.Name)
+		dataType := strings.TrimSpace(req.DataType)
+		if len(name) < 1 || len(name) > 255 {
+			return nil, ErrInvalidInput
+		}
+		if err := validateFieldType(dataType, req.DefaultValue); err != nil {
+			return nil, err
+		}
+		metadata, err := newMetadata(req.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		fields[i] = &models.Field{
+			TableID:      tableID,
+			Name:         name,
+			DataType:     dataType,
+			IsPrimaryKey: req.IsPrimaryKey,
+			IsNullable:   req.IsNullable,
+			DefaultValue: req.DefaultValue,
+			Position:     req.Position,
+			Metadata:     metadata,
+		}
+	}
+
+	// Verify table exists and get project ID for authorization
+	table, err := s.tableRepo.GetByID(tableID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTableNotFound
 		}
+		return nil, err
 	}
 
-	// Then persist to database
-	id, err := s.fieldRepo.Create(field)
+	canModify, err := s.authService.CanUserModifyProject(userID, table.ProjectID)
 	if err != nil {
 		return nil, err
 	}
+	if !canModify {
+		return nil, ErrForbidden
+	}
 
-	field.ID = id
+	// Names must differ from those of the table's fields and of each other
+	taken := make(map[string]bool, len(table.Fields)+len(fields))
+	for _, field := range table.Fields {
+		taken[strings.ToLower(field.Name)] = true
+	}
+	for _, field := range fields {
+		name := strings.ToLower(field.Name)
+		if taken[name] {
+			return nil, &TakenError{Field: "name", Err: ErrFieldNameTaken}
+		}
+		taken[name] = true
+	}
 
-	return field, nil
+	// Persist with the notification, so collaborators never see fields that failed to insert
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := tx.Fields.CreateBatch(fields); err != nil {
+			return err
+		}
+
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyFieldsCreated(table.ProjectID, tableID, fields, userID); err != nil {
+				return err
+			}
+		}
+		for _, field := range fields {
+			field.Sequence = tx.Sequence
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, takenError(err)
+	}
+
+	return fields, nil
 }
 
 func (s *FieldService) GetFieldByID(id uuid.UUID) (*models.Field, error) {
@@ -112,6 +219,9 @@ func (s *FieldService) UpdateField(id uuid.UUID, req *dto.UpdateFieldRequest, us
 		}
 		return nil, err
 	}
+	if req.Version != nil && *req.Version != field.Version {
+		return field, ErrVersionConflict
+	}
 
 	// Only update fields that were provided
 	if req.Name != nil {
@@ -119,12 +229,21 @@ func (s *FieldService) UpdateField(id uuid.UUID, req *dto.UpdateFieldRequest, us
 		if len(name) < 1 || len(name) > 255 {
 			return nil, ErrInvalidInput
 		}
+		if name != field.Name {
+			table, err := s.tableRepo.GetByID(field.TableID)
+			if err != nil {
+				return nil, err
+			}
+			if fieldNameTaken(table.Fields, name, field.ID) {
+				return nil, &TakenError{Field: "name", Err: ErrFieldNameTaken}
+			}
+		}
 		field.Name = name
 	}
 
 	if req.DataType != nil {
 		dataType := strings.TrimSpace(*req.DataType)
-		if len(dataType) < 1 {
+		if ddl.ValidateDataType(dataType) != nil {
 			return nil, ErrInvalidInput
 		}
 		field.DataType = dataType
@@ -139,6 +258,9 @@ func (s *FieldService) UpdateField(id uuid.UUID, req *dto.UpdateFieldRequest, us
 	}
 
 	if req.DefaultValue != nil {
+		if ddl.ValidateDefaultValue(*req.DefaultValue) != nil {
+			return nil, ErrInvalidInput
+		}
 		field.DefaultValue = *req.DefaultValue
 	}
 
@@ -146,88 +268,209 @@ func (s *FieldService) UpdateField(id uuid.UUID, req *dto.UpdateFieldRequest, us
 		field.Position = *req.Position
 	}
 
-	// Get table and project ID for collaboration notification
-	table, err := s.tableRepo.GetByID(field.TableID)
-	if err == nil && s.collaborationService != nil {
-		// Broadcast field update to collaborators FIRST
-		if err := s.collaborationService.NotifyFieldUpdated(table.ProjectID, field, userID); err != nil {
-			// Log error but don't fail the operation
-			// TODO: Add proper logging
+	if req.Metadata != nil {
+		if field.Metadata, err = patchMetadata(field.Metadata, req.Metadata); err != nil {
+			return nil, err
 		}
 	}
 
-	// Then persist to database
-	if err := s.fieldRepo.Update(field); err != nil {
-		return nil, err
+	// Persist with the notification, so a change that loses a concurrent update is never broadcast
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := tx.Fields.Update(field); err != nil {
+			return err
+		}
+
+		// Get table and project ID for collaboration notification
+		table, err := tx.Tables.GetByID(field.TableID)
+		if err == nil && s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyFieldUpdated(table.ProjectID, field, userID); err != nil {
+				return err
+			}
+		}
+		field.Sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return s.currentField(id)
+		}
+		return nil, takenError(err)
 	}
 
 	return field, nil
 }
 
-func (s *FieldService) DeleteField(id uuid.UUID, userID uuid.UUID) error {
+// currentField returns the field as another writer left it, with ErrVersionConflict
+func (s *FieldService) currentField(id uuid.UUID) (*models.Field, error) {
+	field, err := s.fieldRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return field, ErrVersionConflict
+}
+
+func (s *FieldService) DeleteField(id uuid.UUID, userID uuid.UUID) (int64, error) {
 	// Get project ID from field
 	projectID, err := s.authService.GetProjectIDFromField(id)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// Check authorization
 	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if !canModify {
-		return ErrForbidden
+		return 0, ErrForbidden
 	}
 
 	// Verify field exists and get its table_id and name
 	field, err := s.fieldRepo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrFieldNotFound
+			return 0, ErrFieldNotFound
 		}
-		return err
+		return 0, err
 	}
 
-	// Notify collaborators about field deletion FIRST
-	if s.collaborationService != nil {
-		if err := s.collaborationService.NotifyFieldDeleted(projectID, field.TableID, id, field.Name, userID); err != nil {
-			// Log error but don't fail the operation
-			// TODO: Add proper logging
+	// Delete with the notification, so collaborators never drop a field that is still there
+	var sequence int64
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := tx.Fields.Delete(id); err != nil {
+			return err
 		}
-	}
 
-	// Then delete from database
-	if err := s.fieldRepo.Delete(id); err != nil {
-		return err
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyFieldDeleted(projectID, field.TableID, id, field.Name, userID); err != nil {
+				return err
+			}
+		}
+		sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
+	return sequence, nil
+}
 
-	return nil
+// FieldOrder is the order of a table's fields after a reorder
+type FieldOrder struct {
+	TableID  uuid.UUID
+	Fields   []*models.Field // Every field of the table, in position order
+	Sequence int64           // Project sequence of the reorder, or 0
 }
 
-func (s *FieldService) ReorderFields(tableID uuid.UUID, fieldPositions map[uuid.UUID]int) error {
+// ReorderFields moves fields of a table to new positions and tells
+// collaborators the resulting order. With baseSequence, the reorder is made
+// against the project as of that sequence: when another reorder of the table
+// was confirmed after it, nothing changes and the order that reorder left is
+// returned with ErrVersionConflict, so the last confirmed reorder wins.
+func (s *FieldService) ReorderFields(tableID uuid.UUID, fieldPositions map[uuid.UUID]int, baseSequence *int64, userID uuid.UUID) (*FieldOrder, error) {
 	// Verify table exists
-	_, err := s.tableRepo.GetByID(tableID)
+	table, err := s.tableRepo.GetByID(tableID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrTableNotFound
+			return nil, ErrTableNotFound
 		}
-		return err
+		return nil, err
+	}
+
+	canModify, err := s.authService.CanUserModifyProject(userID, table.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModify {
+		return nil, ErrForbidden
 	}
 
-	// Verify all fields belong to the table
+	// Verify all fields exist and belong to the table
+	fieldIDs := make([]uuid.UUID, 0, len(fieldPositions))
 	for fieldID := range fieldPositions {
-		field, err := s.fieldRepo.GetByID(fieldID)
+		fieldIDs = append(fieldIDs, fieldID)
+	}
+	fields, err := s.fieldRepo.GetByIDs(fieldIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != len(fieldIDs) {
+		return nil, ErrFieldNotFound
+	}
+	for _, field := range fields {
+		if field.TableID != tableID {
+			return nil, ErrInvalidInput
+		}
+	}
+
+	order := &FieldOrder{TableID: tableID}
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := tx.Fields.ReorderFields(tableID, fieldPositions); err != nil {
+			return err
+		}
+		fields, err := tx.Fields.GetByTableID(tableID)
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return ErrFieldNotFound
-			}
 			return err
 		}
-		if field.TableID != tableID {
-			return ErrInvalidInput
+		order.Fields = fields
+
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyFieldsReordered(table.ProjectID, tableID, fields, userID); err != nil {
+				return err
+			}
 		}
+		order.Sequence = tx.Sequence
+
+		since := int64(math.MaxInt64) // Without a base sequence, any earlier reorder is overridden
+		if baseSequence != nil {
+			since = *baseSequence
+		}
+		return tx.Tables.SetFieldOrderSequence(tableID, tx.Sequence, since)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return s.currentFieldOrder(tableID)
+		}
+		return nil, err
 	}
+	s.projectCache.Invalidate(table.ProjectID)
 
-	return s.fieldRepo.ReorderFields(tableID, fieldPositions)
+	return order, nil
+}
+
+// currentFieldOrder returns the order of a table's fields as the last confirmed
+// reorder left it, with ErrVersionConflict
+func (s *FieldService) currentFieldOrder(tableID uuid.UUID) (*FieldOrder, error) {
+	table, err := s.tableRepo.GetByID(tableID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTableNotFound
+		}
+		return nil, err
+	}
+	fields, err := s.fieldRepo.GetByTableID(tableID)
+	if err != nil {
+		return nil, err
+	}
+	return &FieldOrder{TableID: tableID, Fields: fields, Sequence: table.FieldOrderSequence}, ErrVersionConflict
+}
+
+// validateFieldType fails with ErrInvalidInput unless a data type and default
+// value may be written into the statements applied to databases as they are
+func validateFieldType(dataType, defaultValue string) error {
+	if ddl.ValidateDataType(dataType) != nil || ddl.ValidateDefaultValue(defaultValue) != nil {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// fieldNameTaken reports whether a field other than the one with the given ID
+// has name, ignoring case as databases do for unquoted identifiers
+func fieldNameTaken(fields []models.Field, name string, except uuid.UUID) bool {
+	for _, field := range fields {
+		if field.ID != except && strings.EqualFold(field.Name, name) {
+			return true
+		}
+	}
+	return false
 }
@@ -1,11 +1,13 @@
 package services
 
 import (
+	"math"
 	"testing"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
 	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -113,6 +115,16 @@ func (m *mockCollaborationService) NotifyFieldCreated(projectID uuid.UUID, field
 	return args.Error(0)
 }
 
+// InTx returns the mock itself, so expectations hold inside transactions too
+func (m *mockCollaborationService) InTx(tx *Tx) CollaborationSessionServiceInterface {
+	return m
+}
+
+func (m *mockCollaborationService) NotifyFieldsCreated(projectID, tableID uuid.UUID, fields []*models.Field, senderUserID uuid.UUID) error {
+	args := m.Called(projectID, tableID, fields, senderUserID)
+	return args.Error(0)
+}
+
 func (m *mockCollaborationService) NotifyFieldUpdated(projectID uuid.UUID, field *models.Field, senderUserID uuid.UUID) error {
 	args := m.Called(projectID, field, senderUserID)
 	return args.Error(0)
@@ -123,6 +135,11 @@ func (m *mockCollaborationService) NotifyFieldDeleted(projectID, tableID, fieldI
 	return args.Error(0)
 }
 
+func (m *mockCollaborationService) NotifyFieldsReordered(projectID, tableID uuid.UUID, fields []*models.Field, senderUserID uuid.UUID) error {
+	args := m.Called(projectID, tableID, fields, senderUserID)
+	return args.Error(0)
+}
+
 // Table collaboration methods
 func (m *mockCollaborationService) NotifyTableCreated(projectID uuid.UUID, table *models.Table, senderUserID uuid.UUID) error {
 	args := m.Called(projectID, table, senderUserID)
@@ -155,12 +172,53 @@ func (m *mockCollaborationService) NotifyRelationshipDeleted(projectID, relation
 	return args.Error(0)
 }
 
+// Region collaboration methods
+func (m *mockCollaborationService) NotifyRegionCreated(projectID uuid.UUID, region *models.Region, senderUserID uuid.UUID) error {
+	args := m.Called(projectID, region, senderUserID)
+	return args.Error(0)
+}
+
+func (m *mockCollaborationService) NotifyRegionUpdated(projectID uuid.UUID, region *models.Region, senderUserID uuid.UUID) error {
+	args := m.Called(projectID, region, senderUserID)
+	return args.Error(0)
+}
+
+func (m *mockCollaborationService) NotifyRegionMoved(projectID uuid.UUID, region *models.Region, tables []repository.TablePosition, senderUserID uuid.UUID) error {
+	args := m.Called(projectID, region, tables, senderUserID)
+	return args.Error(0)
+}
+
+func (m *mockCollaborationService) NotifyRegionDeleted(projectID, regionID uuid.UUID, regionName string, senderUserID uuid.UUID) error {
+	args := m.Called(projectID, regionID, regionName, senderUserID)
+	return args.Error(0)
+}
+
 // Canvas collaboration methods
 func (m *mockCollaborationService) BroadcastCanvasUpdate(projectID uuid.UUID, canvasData string, senderUserID uuid.UUID) error {
 	args := m.Called(projectID, canvasData, senderUserID)
 	return args.Error(0)
 }
 
+func (m *mockCollaborationService) NotifyDocUpdated(projectID uuid.UUID, revision *models.DocRevision, senderUserID uuid.UUID) error {
+	args := m.Called(projectID, revision, senderUserID)
+	return args.Error(0)
+}
+
+func (m *mockCollaborationService) NotifyChatMessage(projectID uuid.UUID, message *models.ChatMessage) error {
+	args := m.Called(projectID, message)
+	return args.Error(0)
+}
+
+func (m *mockCollaborationService) DisconnectCollaborator(projectID, userID uuid.UUID, reason string) error {
+	args := m.Called(projectID, userID, reason)
+	return args.Error(0)
+}
+
+func (m *mockCollaborationService) NotifyPermissionsUpdated(projectID, userID uuid.UUID, role string) error {
+	args := m.Called(projectID, userID, role)
+	return args.Error(0)
+}
+
 // Test helper functions
 func createTestField(tableID uuid.UUID) *models.Field {
 	return &models.Field{
@@ -193,7 +251,8 @@ func (suite *FieldServiceTestSuite) SetupTest() {
 	suite.mockTableRepo = new(mockRepo.MockTableRepository)
 	suite.mockAuthService = new(mockAuthorizationService)
 	suite.mockCollabService = new(mockCollaborationService)
-	suite.service = NewFieldService(suite.mockFieldRepo, suite.mockTableRepo, suite.mockAuthService, suite.mockCollabService)
+	suite.service = NewFieldService(suite.mockFieldRepo, suite.mockTableRepo, suite.mockAuthService, suite.mockCollabService, nil,
+		newTestUnitOfWork(repository.Repositories{Tables: suite.mockTableRepo, Fields: suite.mockFieldRepo}))
 }
 
 func TestFieldServiceSuite(t *testing.T) {
@@ -209,7 +268,7 @@ func (suite *FieldServiceTestSuite) TestCreateField_Success() {
 		DataType:     "VARCHAR(255)",
 		IsPrimaryKey: true,
 		IsNullable:   false,
-		DefaultValue: "default_value",
+		DefaultValue: "'default_value'",
 		Position:     1,
 	}
 
@@ -227,7 +286,7 @@ func (suite *FieldServiceTestSuite) TestCreateField_Success() {
 			field.DataType == "VARCHAR(255)" &&
 			field.IsPrimaryKey == true &&
 			field.IsNullable == false &&
-			field.DefaultValue == "default_value" &&
+			field.DefaultValue == "'default_value'" &&
 			field.Position == 1
 	})).Return(fieldID, nil)
 
@@ -243,7 +302,7 @@ func (suite *FieldServiceTestSuite) TestCreateField_Success() {
 	suite.Equal("VARCHAR(255)", result.DataType)
 	suite.Equal(true, result.IsPrimaryKey)
 	suite.Equal(false, result.IsNullable)
-	suite.Equal("default_value", result.DefaultValue)
+	suite.Equal("'default_value'", result.DefaultValue)
 	suite.Equal(1, result.Position)
 
 	suite.mockTableRepo.AssertExpectations(suite.T())
@@ -252,6 +311,100 @@ func (suite *FieldServiceTestSuite) TestCreateField_Success() {
 	suite.mockCollabService.AssertExpectations(suite.T())
 }
 
+// Test CreateFields - Success
+func (suite *FieldServiceTestSuite) TestCreateFields_Success() {
+	tableID := uuid.New()
+	userID := uuid.New()
+	table := &models.Table{ID: tableID, Name: "Test Table", ProjectID: uuid.New()}
+	reqs := []dto.CreateFieldRequest{
+		{Name: "id", DataType: "UUID", IsPrimaryKey: true, Position: 0},
+		{Name: " email ", DataType: "VARCHAR(255)", Position: 1},
+	}
+
+	suite.mockTableRepo.On("GetByID", tableID).Return(table, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, table.ProjectID).Return(true, nil)
+	suite.mockFieldRepo.On("CreateBatch", mock.MatchedBy(func(fields []*models.Field) bool {
+		return len(fields) == 2 && fields[0].Name == "id" && fields[1].Name == "email" && fields[1].TableID == tableID
+	})).Return(nil)
+	suite.mockCollabService.On("NotifyFieldsCreated", table.ProjectID, tableID, mock.AnythingOfType("[]*models.Field"), userID).Return(nil).Once()
+
+	result, err := suite.service.CreateFields(tableID, reqs, userID)
+
+	suite.NoError(err)
+	suite.Len(result, 2)
+	suite.Equal("email", result[1].Name)
+	suite.mockFieldRepo.AssertExpectations(suite.T())
+	suite.mockCollabService.AssertExpectations(suite.T())
+}
+
+// Test CreateField - Name of another field of the table, in other case
+func (suite *FieldServiceTestSuite) TestCreateField_NameTaken() {
+	tableID := uuid.New()
+	userID := uuid.New()
+	table := &models.Table{ID: tableID, ProjectID: uuid.New(), Fields: []models.Field{{ID: uuid.New(), TableID: tableID, Name: "Email"}}}
+
+	suite.mockTableRepo.On("GetByID", tableID).Return(table, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, table.ProjectID).Return(true, nil)
+
+	result, err := suite.service.CreateField(tableID, &dto.CreateFieldRequest{Name: "email", DataType: "TEXT"}, userID)
+
+	suite.Nil(result)
+	suite.ErrorIs(err, ErrFieldNameTaken)
+	suite.ErrorIs(err, ErrNameTaken)
+	suite.mockFieldRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test CreateFields - Two fields of the batch with one name, ignoring case
+func (suite *FieldServiceTestSuite) TestCreateFields_DuplicateNames() {
+	tableID := uuid.New()
+	userID := uuid.New()
+	table := &models.Table{ID: tableID, ProjectID: uuid.New()}
+	reqs := []dto.CreateFieldRequest{
+		{Name: "email", DataType: "TEXT"},
+		{Name: "EMAIL", DataType: "TEXT"},
+	}
+
+	suite.mockTableRepo.On("GetByID", tableID).Return(table, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, table.ProjectID).Return(true, nil)
+
+	result, err := suite.service.CreateFields(tableID, reqs, userID)
+
+	suite.Nil(result)
+	suite.ErrorIs(err, ErrFieldNameTaken)
+	suite.mockFieldRepo.AssertNotCalled(suite.T(), "CreateBatch", mock.Anything)
+}
+
+// Test CreateFields - One invalid field rejects the whole batch
+func (suite *FieldServiceTestSuite) TestCreateFields_InvalidField() {
+	reqs := []dto.CreateFieldRequest{
+		{Name: "id", DataType: "UUID"},
+		{Name: "missing_type"},
+	}
+
+	result, err := suite.service.CreateFields(uuid.New(), reqs, uuid.New())
+
+	suite.Equal(ErrInvalidInput, err)
+	suite.Nil(result)
+	suite.mockFieldRepo.AssertNotCalled(suite.T(), "CreateBatch", mock.Anything)
+}
+
+// Test CreateFields - Nothing is broadcast when the insert fails
+func (suite *FieldServiceTestSuite) TestCreateFields_RepositoryError() {
+	tableID := uuid.New()
+	userID := uuid.New()
+	table := &models.Table{ID: tableID, ProjectID: uuid.New()}
+
+	suite.mockTableRepo.On("GetByID", tableID).Return(table, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, table.ProjectID).Return(true, nil)
+	suite.mockFieldRepo.On("CreateBatch", mock.Anything).Return(gorm.ErrInvalidDB)
+
+	result, err := suite.service.CreateFields(tableID, []dto.CreateFieldRequest{{Name: "id", DataType: "UUID"}}, userID)
+
+	suite.Error(err)
+	suite.Nil(result)
+	suite.mockCollabService.AssertNotCalled(suite.T(), "NotifyFieldsCreated", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Test CreateField - Invalid Name (empty)
 func (suite *FieldServiceTestSuite) TestCreateField_InvalidNameEmpty() {
 	tableID := uuid.New()
@@ -301,6 +454,24 @@ func (suite *FieldServiceTestSuite) TestCreateField_InvalidDataType() {
 	suite.Equal(ErrInvalidInput, err)
 }
 
+// Test CreateField - Types and defaults other than a type or a literal are
+// refused, as both are written into DDL
+func (suite *FieldServiceTestSuite) TestCreateField_InvalidTypeOrDefault() {
+	tableID := uuid.New()
+	userID := uuid.New()
+
+	for _, req := range []*dto.CreateFieldRequest{
+		{Name: "test_field", DataType: "INT); DROP TABLE users; --"},
+		{Name: "test_field", DataType: "INT", DefaultValue: "(SELECT max(id) FROM users)"},
+	} {
+		result, err := suite.service.CreateField(tableID, req, userID)
+
+		suite.Equal(ErrInvalidInput, err)
+		suite.Nil(result)
+	}
+	suite.mockFieldRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
 // Test CreateField - Table Not Found
 func (suite *FieldServiceTestSuite) TestCreateField_TableNotFound() {
 	tableID := uuid.New()
@@ -337,7 +508,6 @@ func (suite *FieldServiceTestSuite) TestCreateField_RepositoryError() {
 
 	suite.mockTableRepo.On("GetByID", tableID).Return(table, nil)
 	suite.mockAuthService.On("CanUserModifyProject", mock.AnythingOfType("uuid.UUID"), table.ProjectID).Return(true, nil)
-	suite.mockCollabService.On("NotifyFieldCreated", table.ProjectID, mock.AnythingOfType("*models.Field"), mock.AnythingOfType("uuid.UUID")).Return(nil)
 	suite.mockFieldRepo.On("Create", mock.AnythingOfType("*models.Field")).Return(uuid.Nil, assert.AnError)
 
 	userID := uuid.New()
@@ -349,7 +519,7 @@ func (suite *FieldServiceTestSuite) TestCreateField_RepositoryError() {
 
 	suite.mockTableRepo.AssertExpectations(suite.T())
 	suite.mockAuthService.AssertExpectations(suite.T())
-	suite.mockCollabService.AssertExpectations(suite.T())
+	suite.mockCollabService.AssertNotCalled(suite.T(), "NotifyFieldCreated", mock.Anything, mock.Anything, mock.Anything)
 	suite.mockFieldRepo.AssertExpectations(suite.T())
 }
 
@@ -451,6 +621,22 @@ func (suite *FieldServiceTestSuite) TestUpdateField_Success() {
 	suite.mockCollabService.AssertExpectations(suite.T())
 }
 
+// Test UpdateField - Defaults other than a literal are refused
+func (suite *FieldServiceTestSuite) TestUpdateField_InvalidDefault() {
+	fieldID := uuid.New()
+	existingField := createTestField(uuid.New())
+	existingField.ID = fieldID
+	updateRequest := &dto.UpdateFieldRequest{DefaultValue: fieldStringPtr("0; DROP TABLE users")}
+
+	suite.mockFieldRepo.On("GetByID", fieldID).Return(existingField, nil)
+
+	result, err := suite.service.UpdateField(fieldID, updateRequest, uuid.New())
+
+	suite.Equal(ErrInvalidInput, err)
+	suite.Nil(result)
+	suite.mockFieldRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
 // Test UpdateField - Not Found
 func (suite *FieldServiceTestSuite) TestUpdateField_NotFound() {
 	fieldID := uuid.New()
@@ -493,6 +679,44 @@ func (suite *FieldServiceTestSuite) TestUpdateField_InvalidName() {
 	suite.mockFieldRepo.AssertExpectations(suite.T())
 }
 
+// Test UpdateField - Renamed to the name of another field of the table
+func (suite *FieldServiceTestSuite) TestUpdateField_NameTaken() {
+	existingField := createTestField(uuid.New())
+	other := models.Field{ID: uuid.New(), TableID: existingField.TableID, Name: "created_at"}
+	table := &models.Table{ID: existingField.TableID, Fields: []models.Field{*existingField, other}}
+
+	suite.mockFieldRepo.On("GetByID", existingField.ID).Return(existingField, nil)
+	suite.mockTableRepo.On("GetByID", existingField.TableID).Return(table, nil)
+
+	result, err := suite.service.UpdateField(existingField.ID, &dto.UpdateFieldRequest{Name: fieldStringPtr("Created_At")}, uuid.New())
+
+	suite.Nil(result)
+	suite.ErrorIs(err, ErrFieldNameTaken)
+	var takenErr *TakenError
+	suite.Require().ErrorAs(err, &takenErr)
+	suite.Equal("name", takenErr.Field)
+	suite.mockFieldRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+// Test UpdateField - Changing only the case of the field's own name
+func (suite *FieldServiceTestSuite) TestUpdateField_RenameCase() {
+	existingField := createTestField(uuid.New())
+	table := &models.Table{ID: existingField.TableID, ProjectID: uuid.New(), Fields: []models.Field{*existingField}}
+	userID := uuid.New()
+
+	suite.mockFieldRepo.On("GetByID", existingField.ID).Return(existingField, nil)
+	suite.mockTableRepo.On("GetByID", existingField.TableID).Return(table, nil)
+	suite.mockFieldRepo.On("Update", mock.MatchedBy(func(field *models.Field) bool {
+		return field.Name == "TEST FIELD"
+	})).Return(nil)
+	suite.mockCollabService.On("NotifyFieldUpdated", table.ProjectID, mock.AnythingOfType("*models.Field"), userID).Return(nil)
+
+	result, err := suite.service.UpdateField(existingField.ID, &dto.UpdateFieldRequest{Name: fieldStringPtr("TEST FIELD")}, userID)
+
+	suite.NoError(err)
+	suite.Equal("TEST FIELD", result.Name)
+}
+
 // Test UpdateField - Invalid DataType
 func (suite *FieldServiceTestSuite) TestUpdateField_InvalidDataType() {
 	fieldID := uuid.New()
@@ -530,7 +754,7 @@ func (suite *FieldServiceTestSuite) TestDeleteField_Success() {
 	suite.mockFieldRepo.On("Delete", fieldID).Return(nil)
 	suite.mockCollabService.On("NotifyFieldDeleted", projectID, existingField.TableID, fieldID, existingField.Name, userID).Return(nil)
 
-	err := suite.service.DeleteField(fieldID, userID)
+	_, err := suite.service.DeleteField(fieldID, userID)
 
 	suite.NoError(err)
 	suite.mockAuthService.AssertExpectations(suite.T())
@@ -545,7 +769,7 @@ func (suite *FieldServiceTestSuite) TestDeleteField_NotFound() {
 
 	suite.mockAuthService.On("GetProjectIDFromField", fieldID).Return(uuid.Nil, ErrFieldNotFound)
 
-	err := suite.service.DeleteField(fieldID, userID)
+	_, err := suite.service.DeleteField(fieldID, userID)
 
 	suite.Error(err)
 	suite.Equal(ErrFieldNotFound, err)
@@ -562,7 +786,7 @@ func (suite *FieldServiceTestSuite) TestDeleteField_Forbidden() {
 	suite.mockAuthService.On("GetProjectIDFromField", fieldID).Return(projectID, nil)
 	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(false, nil)
 
-	err := suite.service.DeleteField(fieldID, userID)
+	_, err := suite.service.DeleteField(fieldID, userID)
 
 	suite.Error(err)
 	suite.Equal(ErrForbidden, err)
@@ -581,22 +805,63 @@ func (suite *FieldServiceTestSuite) TestDeleteField_RepositoryError() {
 	suite.mockAuthService.On("GetProjectIDFromField", fieldID).Return(projectID, nil)
 	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
 	suite.mockFieldRepo.On("GetByID", fieldID).Return(existingField, nil)
-	suite.mockCollabService.On("NotifyFieldDeleted", projectID, existingField.TableID, fieldID, existingField.Name, userID).Return(nil)
 	suite.mockFieldRepo.On("Delete", fieldID).Return(assert.AnError)
 
-	err := suite.service.DeleteField(fieldID, userID)
+	_, err := suite.service.DeleteField(fieldID, userID)
 
 	suite.Error(err)
 	suite.Equal(assert.AnError, err)
 
 	suite.mockAuthService.AssertExpectations(suite.T())
-	suite.mockCollabService.AssertExpectations(suite.T())
+	suite.mockCollabService.AssertNotCalled(suite.T(), "NotifyFieldDeleted", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	suite.mockFieldRepo.AssertExpectations(suite.T())
 }
 
+// Test DeleteField - Collaborators hear of the deletion only after it
+func (suite *FieldServiceTestSuite) TestDeleteField_NotifiesAfterDelete() {
+	fieldID := uuid.New()
+	userID := uuid.New()
+	projectID := uuid.New()
+	existingField := createTestField(uuid.New())
+	existingField.ID = fieldID
+
+	var calls []string
+	suite.mockAuthService.On("GetProjectIDFromField", fieldID).Return(projectID, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockFieldRepo.On("GetByID", fieldID).Return(existingField, nil)
+	suite.mockFieldRepo.On("Delete", fieldID).Return(nil).Run(func(mock.Arguments) { calls = append(calls, "delete") })
+	suite.mockCollabService.On("NotifyFieldDeleted", projectID, existingField.TableID, fieldID, existingField.Name, userID).
+		Return(nil).Run(func(mock.Arguments) { calls = append(calls, "notify") })
+
+	_, err := suite.service.DeleteField(fieldID, userID)
+
+	suite.NoError(err)
+	suite.Equal([]string{"delete", "notify"}, calls)
+}
+
+// Test DeleteField - A notification that cannot be saved fails the deletion
+func (suite *FieldServiceTestSuite) TestDeleteField_NotifyError() {
+	fieldID := uuid.New()
+	userID := uuid.New()
+	projectID := uuid.New()
+	existingField := createTestField(uuid.New())
+	existingField.ID = fieldID
+
+	suite.mockAuthService.On("GetProjectIDFromField", fieldID).Return(projectID, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockFieldRepo.On("GetByID", fieldID).Return(existingField, nil)
+	suite.mockFieldRepo.On("Delete", fieldID).Return(nil)
+	suite.mockCollabService.On("NotifyFieldDeleted", projectID, existingField.TableID, fieldID, existingField.Name, userID).Return(assert.AnError)
+
+	_, err := suite.service.DeleteField(fieldID, userID)
+
+	suite.Equal(assert.AnError, err)
+}
+
 // Test ReorderFields - Success
 func (suite *FieldServiceTestSuite) TestReorderFields_Success() {
 	tableID := uuid.New()
+	userID := uuid.New()
 	fieldID1 := uuid.New()
 	fieldID2 := uuid.New()
 
@@ -616,19 +881,74 @@ func (suite *FieldServiceTestSuite) TestReorderFields_Success() {
 		fieldID1: 1,
 		fieldID2: 2,
 	}
+	ordered := []*models.Field{field1, field2}
 
 	suite.mockTableRepo.On("GetByID", tableID).Return(table, nil)
-	suite.mockFieldRepo.On("GetByID", fieldID1).Return(field1, nil)
-	suite.mockFieldRepo.On("GetByID", fieldID2).Return(field2, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, table.ProjectID).Return(true, nil)
+	suite.mockFieldRepo.On("GetByIDs", mock.MatchedBy(func(ids []uuid.UUID) bool {
+		return suite.ElementsMatch([]uuid.UUID{fieldID1, fieldID2}, ids)
+	})).Return([]*models.Field{field1, field2}, nil)
 	suite.mockFieldRepo.On("ReorderFields", tableID, fieldPositions).Return(nil)
+	suite.mockFieldRepo.On("GetByTableID", tableID).Return(ordered, nil)
+	suite.mockCollabService.On("NotifyFieldsReordered", table.ProjectID, tableID, ordered, userID).Return(nil)
+	suite.mockTableRepo.On("SetFieldOrderSequence", tableID, int64(0), int64(math.MaxInt64)).Return(nil)
 
-	err := suite.service.ReorderFields(tableID, fieldPositions)
+	order, err := suite.service.ReorderFields(tableID, fieldPositions, nil, userID)
 
 	suite.NoError(err)
+	suite.Equal(tableID, order.TableID)
+	suite.Equal(ordered, order.Fields)
 
 	suite.mockTableRepo.AssertExpectations(suite.T())
 	suite.mockAuthService.AssertExpectations(suite.T())
 	suite.mockFieldRepo.AssertExpectations(suite.T())
+	suite.mockCollabService.AssertExpectations(suite.T())
+}
+
+// Test ReorderFields - A reorder confirmed after the base sequence wins
+func (suite *FieldServiceTestSuite) TestReorderFields_LaterReorderWins() {
+	tableID := uuid.New()
+	userID := uuid.New()
+	field := createTestField(tableID)
+	baseSequence := int64(4)
+
+	table := &models.Table{ID: tableID, ProjectID: uuid.New()}
+	confirmed := &models.Table{ID: tableID, ProjectID: table.ProjectID, FieldOrderSequence: 6}
+	fieldPositions := map[uuid.UUID]int{field.ID: 3}
+	current := []*models.Field{field}
+
+	suite.mockTableRepo.On("GetByID", tableID).Return(table, nil).Once()
+	suite.mockAuthService.On("CanUserModifyProject", userID, table.ProjectID).Return(true, nil)
+	suite.mockFieldRepo.On("GetByIDs", []uuid.UUID{field.ID}).Return([]*models.Field{field}, nil)
+	suite.mockFieldRepo.On("ReorderFields", tableID, fieldPositions).Return(nil)
+	suite.mockFieldRepo.On("GetByTableID", tableID).Return(current, nil)
+	suite.mockCollabService.On("NotifyFieldsReordered", table.ProjectID, tableID, current, userID).Return(nil)
+	suite.mockTableRepo.On("SetFieldOrderSequence", tableID, int64(0), baseSequence).Return(repository.ErrVersionConflict)
+	suite.mockTableRepo.On("GetByID", tableID).Return(confirmed, nil).Once()
+
+	order, err := suite.service.ReorderFields(tableID, fieldPositions, &baseSequence, userID)
+
+	suite.ErrorIs(err, ErrVersionConflict)
+	suite.Equal(int64(6), order.Sequence)
+	suite.Equal(current, order.Fields)
+
+	suite.mockTableRepo.AssertExpectations(suite.T())
+	suite.mockFieldRepo.AssertExpectations(suite.T())
+}
+
+// Test ReorderFields - Forbidden
+func (suite *FieldServiceTestSuite) TestReorderFields_Forbidden() {
+	tableID := uuid.New()
+	userID := uuid.New()
+	table := &models.Table{ID: tableID, ProjectID: uuid.New()}
+
+	suite.mockTableRepo.On("GetByID", tableID).Return(table, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, table.ProjectID).Return(false, nil)
+
+	_, err := suite.service.ReorderFields(tableID, map[uuid.UUID]int{uuid.New(): 1}, nil, userID)
+
+	suite.Equal(ErrForbidden, err)
+	suite.mockFieldRepo.AssertNotCalled(suite.T(), "ReorderFields", mock.Anything, mock.Anything)
 }
 
 // Test ReorderFields - Table Not Found
@@ -640,7 +960,7 @@ func (suite *FieldServiceTestSuite) TestReorderFields_TableNotFound() {
 
 	suite.mockTableRepo.On("GetByID", tableID).Return(nil, gorm.ErrRecordNotFound)
 
-	err := suite.service.ReorderFields(tableID, fieldPositions)
+	_, err := suite.service.ReorderFields(tableID, fieldPositions, nil, uuid.New())
 
 	suite.Error(err)
 	suite.Equal(ErrTableNotFound, err)
@@ -651,6 +971,7 @@ func (suite *FieldServiceTestSuite) TestReorderFields_TableNotFound() {
 // Test ReorderFields - Field Not Found
 func (suite *FieldServiceTestSuite) TestReorderFields_FieldNotFound() {
 	tableID := uuid.New()
+	userID := uuid.New()
 	fieldID := uuid.New()
 
 	table := &models.Table{
@@ -664,9 +985,10 @@ func (suite *FieldServiceTestSuite) TestReorderFields_FieldNotFound() {
 	}
 
 	suite.mockTableRepo.On("GetByID", tableID).Return(table, nil)
-	suite.mockFieldRepo.On("GetByID", fieldID).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockAuthService.On("CanUserModifyProject", userID, table.ProjectID).Return(true, nil)
+	suite.mockFieldRepo.On("GetByIDs", []uuid.UUID{fieldID}).Return([]*models.Field{}, nil)
 
-	err := suite.service.ReorderFields(tableID, fieldPositions)
+	_, err := suite.service.ReorderFields(tableID, fieldPositions, nil, userID)
 
 	suite.Error(err)
 	suite.Equal(ErrFieldNotFound, err)
@@ -679,6 +1001,7 @@ func (suite *FieldServiceTestSuite) TestReorderFields_FieldNotFound() {
 // Test ReorderFields - Field Belongs To Different Table
 func (suite *FieldServiceTestSuite) TestReorderFields_FieldBelongsToDifferentTable() {
 	tableID := uuid.New()
+	userID := uuid.New()
 	fieldID := uuid.New()
 	differentTableID := uuid.New()
 
@@ -696,9 +1019,10 @@ func (suite *FieldServiceTestSuite) TestReorderFields_FieldBelongsToDifferentTab
 	}
 
 	suite.mockTableRepo.On("GetByID", tableID).Return(table, nil)
-	suite.mockFieldRepo.On("GetByID", fieldID).Return(field, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, table.ProjectID).Return(true, nil)
+	suite.mockFieldRepo.On("GetByIDs", []uuid.UUID{fieldID}).Return([]*models.Field{field}, nil)
 
-	err := suite.service.ReorderFields(tableID, fieldPositions)
+	_, err := suite.service.ReorderFields(tableID, fieldPositions, nil, userID)
 
 	suite.Error(err)
 	suite.Equal(ErrInvalidInput, err)
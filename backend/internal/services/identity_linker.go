@@ -0,0 +1,130 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"gorm.io/gorm"
+)
+
+// usernameDisallowed matches characters not allowed in generated usernames
+var usernameDisallowed = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// ExternalProfile is the account information an external login provider returns for the signed-in user
+type ExternalProfile struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+}
+
+// identityLinker maps accounts at external login providers (OAuth, SAML) to local users
+type identityLinker struct {
+	userRepo     repository.UserRepositoryInterface
+	identityRepo repository.UserIdentityRepositoryInterface
+}
+
+func newIdentityLinker(userRepo repository.UserRepositoryInterface, identityRepo repository.UserIdentityRepositoryInterface) *identityLinker {
+	return &identityLinker{
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+	}
+}
+
+// resolve finds the user linked to a provider account. Unknown accounts are
+// linked to the user with the same email, or to a new user when provisionNew
+// is set. Callers must only pass emails the provider has verified.
+func (l *identityLinker) resolve(provider string, profile *ExternalProfile, provisionNew bool) (*models.User, error) {
+	identity, err := l.identityRepo.GetByProviderSubject(provider, profile.Subject)
+	if err == nil {
+		return l.userRepo.GetByID(identity.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if profile.Email == "" {
+		return nil, ErrInvalidInput
+	}
+
+	user, err := l.userRepo.GetByEmail(profile.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if !provisionNew {
+			return nil, ErrUserNotFound
+		}
+		user, err = l.createUser(profile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := l.identityRepo.Create(&models.UserIdentity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  profile.Subject,
+		Email:    profile.Email,
+	}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createUser creates a passwordless user for a provider profile
+func (l *identityLinker) createUser(profile *ExternalProfile) (*models.User, error) {
+	username, err := l.uniqueUsername(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Email:    profile.Email,
+		Username: username,
+	}
+
+	id, err := l.userRepo.Create(user)
+	if err != nil {
+		return nil, err
+	}
+
+	user.ID = id
+	return user, nil
+}
+
+// uniqueUsername derives an unused username from the profile, adding a random
+// numeric suffix when the preferred one is taken
+func (l *identityLinker) uniqueUsername(profile *ExternalProfile) (string, error) {
+	base := profile.Username
+	if base == "" {
+		base, _, _ = strings.Cut(profile.Email, "@")
+	}
+	base = usernameDisallowed.ReplaceAllString(base, "")
+	if len(base) < 3 {
+		base = "user" + base
+	}
+
+	candidate := base
+	for attempt := 0; attempt < 5; attempt++ {
+		_, err := l.userRepo.GetByUsername(candidate)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		n, err := rand.Int(rand.Reader, big.NewInt(10000))
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s-%04d", base, n.Int64())
+	}
+
+	return "", ErrUserAlreadyExists
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type IdentityLinkerTestSuite struct {
+	suite.Suite
+	mockUserRepo     *mockRepo.MockUserRepository
+	mockIdentityRepo *mockRepo.MockUserIdentityRepository
+	linker           *identityLinker
+}
+
+func (suite *IdentityLinkerTestSuite) SetupTest() {
+	suite.mockUserRepo = new(mockRepo.MockUserRepository)
+	suite.mockIdentityRepo = new(mockRepo.MockUserIdentityRepository)
+	suite.linker = newIdentityLinker(suite.mockUserRepo, suite.mockIdentityRepo)
+}
+
+func TestIdentityLinkerSuite(t *testing.T) {
+	suite.Run(t, new(IdentityLinkerTestSuite))
+}
+
+// Test resolve - existing identity returns the linked user
+func (suite *IdentityLinkerTestSuite) TestResolve_ExistingIdentity() {
+	user := createTestUser()
+	identity := &models.UserIdentity{UserID: user.ID, Provider: OAuthProviderGitHub, Subject: "42"}
+
+	suite.mockIdentityRepo.On("GetByProviderSubject", OAuthProviderGitHub, "42").Return(identity, nil)
+	suite.mockUserRepo.On("GetByID", user.ID).Return(user, nil)
+
+	result, err := suite.linker.resolve(OAuthProviderGitHub, &ExternalProfile{Subject: "42"}, true)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), user, result)
+	suite.mockIdentityRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test resolve - verified email links to the existing account
+func (suite *IdentityLinkerTestSuite) TestResolve_LinksByVerifiedEmail() {
+	user := createTestUser()
+	profile := &ExternalProfile{Subject: "42", Email: user.Email, EmailVerified: true}
+
+	suite.mockIdentityRepo.On("GetByProviderSubject", OAuthProviderGitHub, "42").Return(nil, gorm.ErrRecordNotFound)
+	suite.mockUserRepo.On("GetByEmail", user.Email).Return(user, nil)
+	suite.mockIdentityRepo.On("Create", mock.MatchedBy(func(identity *models.UserIdentity) bool {
+		return identity.UserID == user.ID && identity.Provider == OAuthProviderGitHub && identity.Subject == "42"
+	})).Return(uuid.New(), nil)
+
+	result, err := suite.linker.resolve(OAuthProviderGitHub, profile, true)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), user, result)
+	suite.mockIdentityRepo.AssertExpectations(suite.T())
+}
+
+// Test resolve - unknown accounts are rejected when provisioning is off
+func (suite *IdentityLinkerTestSuite) TestResolve_NoProvisioning() {
+	profile := &ExternalProfile{Subject: "42", Email: "new@example.com", EmailVerified: true}
+
+	suite.mockIdentityRepo.On("GetByProviderSubject", OAuthProviderGitHub, "42").Return(nil, gorm.ErrRecordNotFound)
+	suite.mockUserRepo.On("GetByEmail", profile.Email).Return(nil, gorm.ErrRecordNotFound)
+
+	result, err := suite.linker.resolve(OAuthProviderGitHub, profile, false)
+
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, ErrUserNotFound)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test resolve - new account gets a unique username
+func (suite *IdentityLinkerTestSuite) TestResolve_CreatesUserWithUniqueUsername() {
+	profile := &ExternalProfile{Subject: "42", Email: "octo@example.com", EmailVerified: true, Username: "octocat"}
+	newID := uuid.New()
+
+	suite.mockIdentityRepo.On("GetByProviderSubject", OAuthProviderGitHub, "42").Return(nil, gorm.ErrRecordNotFound)
+	suite.mockUserRepo.On("GetByEmail", profile.Email).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockUserRepo.On("GetByUsername", "octocat").Return(createTestUser(), nil)
+	suite.mockUserRepo.On("GetByUsername", mock.MatchedBy(func(username string) bool {
+		return strings.HasPrefix(username, "octocat-")
+	})).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockUserRepo.On("Create", mock.MatchedBy(func(user *models.User) bool {
+		return user.Email == profile.Email && strings.HasPrefix(user.Username, "octocat-") && user.PasswordHash == ""
+	})).Return(newID, nil)
+	suite.mockIdentityRepo.On("Create", mock.AnythingOfType("*models.UserIdentity")).Return(uuid.New(), nil)
+
+	result, err := suite.linker.resolve(OAuthProviderGitHub, profile, true)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), newID, result.ID)
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
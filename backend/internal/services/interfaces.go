@@ -1,10 +1,16 @@
 package services
 
 import (
+	"context"
+	"io"
+	"net/http"
 	"time"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/lint"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
 	"github.com/google/uuid"
 )
 
@@ -12,41 +18,167 @@ type UserServiceInterface interface {
 	CreateUser(email, username, password string) (*models.User, error)
 	GetUserByID(id uuid.UUID) (*models.User, error)
 	GetUserByEmail(email string) (*models.User, error)
-	GetAllUsers() ([]*models.User, error)
+	ListUsers(filter repository.UserFilter, page repository.PageQuery) ([]*models.User, string, error)
 	UpdateUser(id uuid.UUID, req *dto.UpdateUserRequest) (*models.User, error)
 	UpdatePassword(id uuid.UUID, currentPassword, newPassword string) error
 	DeleteUser(id uuid.UUID) error
 	AuthenticateUser(email, password string) (*models.User, error)
 }
 
+type UserPreferencesServiceInterface interface {
+	GetPreferences(userID uuid.UUID) (*models.UserPreferences, error)
+	UpdatePreferences(userID uuid.UUID, req *dto.UpdateUserPreferencesRequest) (*models.UserPreferences, error)
+}
+
+type UsageServiceInterface interface {
+	GetUsage(userID uuid.UUID) (*Usage, error)
+}
+
+type BillingServiceInterface interface {
+	Plans() []Plan
+	GetCurrentPlan(userID uuid.UUID) (*CurrentPlan, error)
+	CreateCheckout(ctx context.Context, userID uuid.UUID, planID string) (string, error)
+	HandleWebhook(payload []byte, signature string) error
+}
+
+type AvatarServiceInterface interface {
+	MaxBytes() int64
+	UploadAvatar(ctx context.Context, userID uuid.UUID, data []byte) (*models.User, error)
+	DeleteAvatar(ctx context.Context, userID uuid.UUID) (*models.User, error)
+}
+
+type AccountDeletionServiceInterface interface {
+	DeleteAccount(ctx context.Context, userID, sessionID uuid.UUID, req *dto.DeleteAccountRequest) ([]*models.Project, error)
+}
+
+type DataExportServiceInterface interface {
+	StartExport(userID uuid.UUID) (*models.DataExport, error)
+	GetExport(userID uuid.UUID) (*models.DataExport, error)
+	DownloadURL(export *models.DataExport) string
+	OpenDownload(ctx context.Context, exportID uuid.UUID, expires, signature string) (io.ReadCloser, *models.DataExport, error)
+}
+
+type AdminUserServiceInterface interface {
+	IsAdmin(userID uuid.UUID) (bool, error)
+	ListUsers(filter repository.UserFilter, page repository.PageQuery) ([]*models.User, string, int64, error)
+	SetUserDisabled(actorID, userID uuid.UUID, disabled bool, reason, ipAddress string) (*models.User, error)
+	SetUserRole(actorID, userID uuid.UUID, role, ipAddress string) (*models.User, error)
+	ForcePasswordReset(actorID, userID uuid.UUID, ipAddress string) (*models.User, error)
+	Impersonate(actorID, userID uuid.UUID, reason, ipAddress, userAgent string) (*models.User, *TokenPair, error)
+	RecordImpersonatedRequest(actorID, userID uuid.UUID, request, ipAddress string) error
+	GetAuditLogs(filter repository.AuditLogFilter, page repository.PageQuery) ([]*models.AdminAuditLog, string, error)
+}
+
+type SnapshotServiceInterface interface {
+	GetSnapshots(projectID, userID uuid.UUID) ([]*models.ProjectSnapshot, error)
+	GetRetention(projectID, userID uuid.UUID) (*SnapshotRetention, error)
+	SetRetention(projectID, userID uuid.UUID, keepDaily, keepMonthly *int) (*SnapshotRetention, error)
+}
+
+type DriftServiceInterface interface {
+	GetProfile(projectID, userID uuid.UUID) (*ConnectionProfile, error)
+	SetProfile(projectID, userID uuid.UUID, req *dto.SetConnectionProfileRequest) (*ConnectionProfile, error)
+	DeleteProfile(projectID, userID uuid.UUID) error
+	StartCheck(projectID, userID uuid.UUID) (*models.DriftCheck, error)
+	GetChecks(projectID, userID uuid.UUID) ([]*models.DriftCheck, error)
+	GetCheck(projectID, checkID, userID uuid.UUID) (*models.DriftCheck, *schemadiff.Diff, error)
+	StartDataProfile(projectID, userID uuid.UUID) (*DataProfile, error)
+	GetDataProfile(projectID, userID uuid.UUID) (*DataProfile, error)
+}
+
+type AdminStatsServiceInterface interface {
+	GetStats(ctx context.Context, days int) (*InstanceStats, error)
+}
+
+type UserSessionServiceInterface interface {
+	StartSession(user *models.User, ipAddress, userAgent string) (*TokenPair, error)
+	StartImpersonationSession(user *models.User, impersonatorID uuid.UUID, ttl time.Duration, ipAddress, userAgent string) (*TokenPair, error)
+	RefreshSession(refreshToken, ipAddress, userAgent string) (*TokenPair, error)
+	EndSession(refreshToken string) error
+	ValidateSession(sessionID uuid.UUID) (*models.UserSession, error)
+	GetActiveSessions(userID uuid.UUID) ([]*models.UserSession, error)
+	RevokeSession(userID, sessionID uuid.UUID) error
+	RevokeOtherSessions(userID, currentSessionID uuid.UUID) error
+}
+
+type LoginSecurityServiceInterface interface {
+	Authenticate(email, password, ipAddress, userAgent string) (*models.User, error)
+	GetLoginHistory(userID uuid.UUID) ([]*models.LoginEvent, error)
+}
+
+type OAuthServiceInterface interface {
+	AuthCodeURL(provider, state, verifier string) (string, error)
+	Authenticate(ctx context.Context, provider, code, verifier string) (*models.User, error)
+}
+
+type SAMLServiceInterface interface {
+	Metadata() ([]byte, error)
+	LoginURL(relayState string) (redirectURL, requestID string, err error)
+	Authenticate(r *http.Request, requestIDs []string) (*models.User, error)
+}
+
+type APITokenServiceInterface interface {
+	CreateToken(userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*models.APIToken, string, error)
+	CreateProjectToken(userID, projectID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*models.APIToken, string, error)
+	GetTokensByUserID(userID uuid.UUID) ([]*models.APIToken, error)
+	RevokeToken(id, userID uuid.UUID) error
+	AdminRevokeToken(id uuid.UUID) error
+	AuthenticateToken(plaintext string) (*models.APIToken, error)
+}
+
+type ServiceAccountServiceInterface interface {
+	CreateServiceAccount(projectID uuid.UUID, name string, scopes []string, userID uuid.UUID) (*models.ServiceAccount, error)
+	GetServiceAccountsByProjectID(projectID, userID uuid.UUID) ([]*models.ServiceAccount, error)
+	DeleteServiceAccount(projectID, id, userID uuid.UUID) error
+	CreateToken(projectID, id uuid.UUID, name string, scopes []string, expiresAt *time.Time, userID uuid.UUID) (*models.APIToken, string, error)
+	GetTokens(projectID, id, userID uuid.UUID) ([]*models.APIToken, error)
+	RevokeToken(projectID, id, tokenID, userID uuid.UUID) error
+}
+
+type SearchServiceInterface interface {
+	Search(userID uuid.UUID, text string, projectID *uuid.UUID, types []string, limit int) ([]*repository.SearchHit, error)
+}
+
 type ProjectServiceInterface interface {
 	CreateProject(name, description string, ownerID uuid.UUID) (*models.Project, error)
 	GetProjectByID(id uuid.UUID) (*models.Project, error)
-	GetProjectsByOwnerID(ownerID uuid.UUID) ([]*models.Project, error)
-	GetProjectsByCollaboratorID(collaboratorID uuid.UUID) ([]*models.Project, error)
-	GetAllProjects() ([]*models.Project, error)
+	GetProjectsByOwnerID(ownerID uuid.UUID, includes repository.ProjectIncludes) ([]*models.Project, error)
+	GetProjectsByCollaboratorID(collaboratorID uuid.UUID, includes repository.ProjectIncludes) ([]*models.Project, error)
+	ListProjects(filter repository.ProjectFilter, page repository.PageQuery) ([]*models.Project, string, error)
 	UpdateProject(id uuid.UUID, req *dto.UpdateProjectRequest, userID uuid.UUID) (*models.Project, error)
 	DeleteProject(id uuid.UUID) error
 	AddCollaborator(projectID, collaboratorID uuid.UUID) error
 	RemoveCollaborator(projectID, collaboratorID uuid.UUID) error
+	KickCollaborator(projectID, collaboratorID, userID uuid.UUID) error
+	SetCollaboratorRole(projectID, collaboratorID uuid.UUID, role string, userID uuid.UUID) error
+	GetMemberRole(projectID, userID uuid.UUID) (string, error)
+	AddProjectTag(projectID uuid.UUID, tag string, userID uuid.UUID) error
+	RemoveProjectTag(projectID uuid.UUID, tag string, userID uuid.UUID) error
+	StarProject(projectID, userID uuid.UUID) error
+	UnstarProject(projectID, userID uuid.UUID) error
+	GetStarredProjectIDs(userID uuid.UUID) ([]uuid.UUID, error)
+	RecordProjectView(projectID, userID uuid.UUID) error
+	GetRecentProjects(userID uuid.UUID, limit int) ([]*models.ProjectView, error)
 }
 
 type TableServiceInterface interface {
-	CreateTable(projectID uuid.UUID, name string, posX, posY float64, userID uuid.UUID) (*models.Table, error)
+	CreateTable(projectID uuid.UUID, req *dto.CreateTableRequest, userID uuid.UUID) (*models.Table, error)
 	GetTableByID(id uuid.UUID) (*models.Table, error)
 	GetTablesByProjectID(projectID uuid.UUID) ([]*models.Table, error)
+	ListTables(projectID uuid.UUID, filter repository.TableFilter, page repository.PageQuery) ([]*models.Table, string, error)
 	UpdateTable(id uuid.UUID, req *dto.UpdateTableRequest, userID uuid.UUID) (*models.Table, error)
 	UpdateTablePosition(id uuid.UUID, posX, posY float64, userID uuid.UUID) error
-	DeleteTable(id uuid.UUID, userID uuid.UUID) error
+	DeleteTable(id uuid.UUID, userID uuid.UUID) (int64, error) // Returns the project sequence of the deletion
 }
 
 type FieldServiceInterface interface {
 	CreateField(tableID uuid.UUID, req *dto.CreateFieldRequest, userID uuid.UUID) (*models.Field, error)
+	CreateFields(tableID uuid.UUID, reqs []dto.CreateFieldRequest, userID uuid.UUID) ([]*models.Field, error)
 	GetFieldByID(id uuid.UUID) (*models.Field, error)
 	GetFieldsByTableID(tableID uuid.UUID) ([]*models.Field, error)
 	UpdateField(id uuid.UUID, req *dto.UpdateFieldRequest, userID uuid.UUID) (*models.Field, error)
-	DeleteField(id uuid.UUID, userID uuid.UUID) error
-	ReorderFields(tableID uuid.UUID, fieldPositions map[uuid.UUID]int) error
+	DeleteField(id uuid.UUID, userID uuid.UUID) (int64, error) // Returns the project sequence of the deletion
+	ReorderFields(tableID uuid.UUID, fieldPositions map[uuid.UUID]int, baseSequence *int64, userID uuid.UUID) (*FieldOrder, error)
 }
 
 type RelationshipServiceInterface interface {
@@ -55,7 +187,83 @@ type RelationshipServiceInterface interface {
 	GetRelationshipsByProjectID(projectID uuid.UUID) ([]*models.Relationship, error)
 	GetRelationshipsByTableID(tableID uuid.UUID) ([]*models.Relationship, error)
 	UpdateRelationship(id uuid.UUID, req *dto.UpdateRelationshipRequest, userID uuid.UUID) (*models.Relationship, error)
-	DeleteRelationship(id uuid.UUID, userID uuid.UUID) error
+	DeleteRelationship(id uuid.UUID, userID uuid.UUID) (int64, error) // Returns the project sequence of the deletion
+}
+
+type RegionServiceInterface interface {
+	CreateRegion(projectID uuid.UUID, req *dto.CreateRegionRequest, userID uuid.UUID) (*models.Region, error)
+	GetRegionsByProjectID(projectID uuid.UUID) ([]*models.Region, error)
+	GetRegionByID(projectID, id uuid.UUID) (*models.Region, error)
+	UpdateRegion(projectID, id uuid.UUID, req *dto.UpdateRegionRequest, userID uuid.UUID) (*models.Region, error)
+	MoveRegion(projectID, id uuid.UUID, req *dto.MoveRegionRequest, userID uuid.UUID) (*RegionMove, error)
+	DeleteRegion(projectID, id uuid.UUID, userID uuid.UUID) (int64, error) // Returns the project sequence of the deletion
+}
+
+type DocServiceInterface interface {
+	GetDoc(projectID uuid.UUID, version int64, userID uuid.UUID) (*models.DocRevision, error)
+	GetDocRevisions(projectID, userID uuid.UUID) ([]*models.DocRevision, error)
+	UpdateDoc(projectID uuid.UUID, req *dto.UpdateDocRequest, userID uuid.UUID) (*models.DocRevision, error)
+	RenderDoc(projectID uuid.UUID, version int64, userID uuid.UUID) ([]byte, error)
+}
+
+type BranchServiceInterface interface {
+	CreateBranch(projectID uuid.UUID, req *dto.CreateBranchRequest, userID uuid.UUID) (*models.Branch, error)
+	GetBranches(projectID, userID uuid.UUID) ([]*models.Branch, error)
+	GetBranch(projectID, id, userID uuid.UUID) (*models.Branch, error)
+	UpdateBranch(projectID, id uuid.UUID, req *dto.UpdateBranchRequest, userID uuid.UUID) (*models.Branch, error)
+	DeleteBranch(projectID, id, userID uuid.UUID) error
+	DiffBranch(projectID, id, userID uuid.UUID) (*schemadiff.Diff, error)
+	MergeBranch(projectID, id, userID uuid.UUID) (*models.Branch, *schemadiff.Diff, error)
+}
+
+type ReleaseServiceInterface interface {
+	CreateRelease(projectID uuid.UUID, req *dto.CreateReleaseRequest, userID uuid.UUID) (*models.Release, error)
+	GetReleases(projectID, userID uuid.UUID) ([]*models.Release, error)
+	GetRelease(projectID, id, userID uuid.UUID) (*models.Release, error)
+	UpdateRelease(projectID, id uuid.UUID, req *dto.UpdateReleaseRequest, userID uuid.UUID) (*models.Release, error)
+	DeleteRelease(projectID, id, userID uuid.UUID) error
+}
+
+type ChatServiceInterface interface {
+	SendMessage(projectID uuid.UUID, content string, userID uuid.UUID) (*models.ChatMessage, error)
+	ListMessages(projectID, userID uuid.UUID, page repository.PageQuery) ([]*models.ChatMessage, string, error)
+}
+
+type EventHistoryServiceInterface interface {
+	ListEvents(projectID, userID uuid.UUID, sinceSequence int64, limit int) (*ProjectEvents, error)
+}
+
+type SnippetServiceInterface interface {
+	CreateSnippet(projectID uuid.UUID, req *dto.CreateSnippetRequest, userID uuid.UUID) (*models.Snippet, error)
+	GetSnippets(projectID, userID uuid.UUID) ([]*models.Snippet, error)
+	GetSnippet(projectID, id, userID uuid.UUID) (*models.Snippet, error)
+	UpdateSnippet(projectID, id uuid.UUID, req *dto.UpdateSnippetRequest, userID uuid.UUID) (*models.Snippet, error)
+	DeleteSnippet(projectID, id, userID uuid.UUID) error
+}
+
+type ReviewServiceInterface interface {
+	ReviewSchema(ctx context.Context, projectID, userID uuid.UUID) ([]*models.ReviewComment, error)
+	GetComments(projectID, userID uuid.UUID) ([]*models.ReviewComment, error)
+	ResolveComment(projectID, id, userID uuid.UUID) (*models.ReviewComment, error)
+}
+
+type SchemaServiceInterface interface {
+	CreateSchema(projectID uuid.UUID, req *dto.CreateSchemaRequest, userID uuid.UUID) (*Schema, error)
+	PlanSchema(projectID uuid.UUID, req *dto.CreateSchemaRequest, userID uuid.UUID) (*Schema, error)
+	ImportSpreadsheet(projectID uuid.UUID, filename string, data []byte, conflicts []dto.SchemaConflictResolution, userID uuid.UUID) (*Schema, error)
+	PlanSpreadsheet(projectID uuid.UUID, filename string, data []byte, conflicts []dto.SchemaConflictResolution, userID uuid.UUID) (*Schema, error)
+	InferSchema(projectID uuid.UUID, req *dto.InferSchemaRequest, userID uuid.UUID) (*SchemaProposal, error)
+	CompareProjects(projectID, otherProjectID, userID uuid.UUID) (*schemadiff.Diff, error)
+	GenerateData(projectID uuid.UUID, req *dto.GenerateDataRequest, userID uuid.UUID) ([]byte, error)
+	ExportSchema(projectID uuid.UUID, format string, userID uuid.UUID) (*SchemaExport, error)
+	ExportRelease(projectID uuid.UUID, release, format string, userID uuid.UUID) (*SchemaExport, error)
+	PlanApply(projectID uuid.UUID, req *dto.PlanApplyRequest, userID uuid.UUID) (*ApplyPlan, error)
+	ExecuteApply(projectID uuid.UUID, req *dto.ExecuteApplyRequest, userID uuid.UUID) (*ApplyLog, error)
+	Rename(projectID uuid.UUID, req *dto.RenameRequest, userID uuid.UUID) (*models.SchemaRename, error)
+	ListRenames(projectID, userID uuid.UUID) ([]*models.SchemaRename, error)
+	LintSchema(projectID, userID uuid.UUID) ([]lint.Finding, error)
+	SuggestRelationships(projectID, userID uuid.UUID) ([]RelationshipSuggestion, error)
+	AcceptRelationshipSuggestions(projectID uuid.UUID, reqs []dto.SchemaRelationshipRequest, userID uuid.UUID) (*Schema, error)
 }
 
 type CollaborationSessionServiceInterface interface {
@@ -68,10 +276,15 @@ type CollaborationSessionServiceInterface interface {
 	SetSessionInactive(sessionID uuid.UUID) error
 	DeleteSession(sessionID uuid.UUID, userID uuid.UUID) error
 
+	// InTx returns the service for notifications delivered once tx commits
+	InTx(tx *Tx) CollaborationSessionServiceInterface
+
 	// Field collaboration methods
 	NotifyFieldCreated(projectID uuid.UUID, field *models.Field, senderUserID uuid.UUID) error
+	NotifyFieldsCreated(projectID, tableID uuid.UUID, fields []*models.Field, senderUserID uuid.UUID) error
 	NotifyFieldUpdated(projectID uuid.UUID, field *models.Field, senderUserID uuid.UUID) error
 	NotifyFieldDeleted(projectID, tableID, fieldID uuid.UUID, fieldName string, senderUserID uuid.UUID) error
+	NotifyFieldsReordered(projectID, tableID uuid.UUID, fields []*models.Field, senderUserID uuid.UUID) error
 
 	// Table collaboration methods
 	NotifyTableCreated(projectID uuid.UUID, table *models.Table, senderUserID uuid.UUID) error
@@ -83,12 +296,27 @@ type CollaborationSessionServiceInterface interface {
 	NotifyRelationshipUpdated(projectID uuid.UUID, relationship *models.Relationship, senderUserID uuid.UUID) error
 	NotifyRelationshipDeleted(projectID, relationshipID uuid.UUID, senderUserID uuid.UUID) error
 
+	// Region collaboration methods
+	NotifyRegionCreated(projectID uuid.UUID, region *models.Region, senderUserID uuid.UUID) error
+	NotifyRegionUpdated(projectID uuid.UUID, region *models.Region, senderUserID uuid.UUID) error
+	NotifyRegionMoved(projectID uuid.UUID, region *models.Region, tables []repository.TablePosition, senderUserID uuid.UUID) error
+	NotifyRegionDeleted(projectID, regionID uuid.UUID, regionName string, senderUserID uuid.UUID) error
+
 	// Canvas collaboration methods
 	BroadcastCanvasUpdate(projectID uuid.UUID, canvasData string, senderUserID uuid.UUID) error
+
+	// Doc collaboration methods
+	NotifyDocUpdated(projectID uuid.UUID, revision *models.DocRevision, senderUserID uuid.UUID) error
+
+	// Chat collaboration methods
+	NotifyChatMessage(projectID uuid.UUID, message *models.ChatMessage) error
+	DisconnectCollaborator(projectID, userID uuid.UUID, reason string) error
+	NotifyPermissionsUpdated(projectID, userID uuid.UUID, role string) error
 }
 
 type JWTServiceInterface interface {
 	GenerateTokenPair(user *models.User) (*TokenPair, error)
+	GenerateSessionTokenPair(user *models.User, sessionID uuid.UUID) (*TokenPair, error)
 	RefreshTokens(refreshToken string) (*TokenPair, error)
 	GetAccessTokenExpiration() time.Duration
 	GetRefreshTokenExpiration() time.Duration
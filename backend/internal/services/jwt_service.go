@@ -21,8 +21,9 @@ type TokenPair struct {
 }
 
 type CustomClaims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	SessionID uuid.UUID `json:"sid"` // Nil for tokens issued before sessions were tracked
 	jwt.RegisteredClaims
 }
 
@@ -37,12 +38,17 @@ func NewJWTService(cfg *config.Config) *JWTService {
 }
 
 func (s *JWTService) GenerateTokenPair(user *models.User) (*TokenPair, error) {
-	accessToken, err := s.generateToken(user, s.config.JWT.AccessTokenExp)
+	return s.GenerateSessionTokenPair(user, uuid.Nil)
+}
+
+// GenerateSessionTokenPair issues tokens bound to a session so they can be revoked with it
+func (s *JWTService) GenerateSessionTokenPair(user *models.User, sessionID uuid.UUID) (*TokenPair, error) {
+	accessToken, err := s.generateToken(user, sessionID, s.config.JWT.AccessTokenExp)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := s.generateToken(user, s.config.JWT.RefreshTokenExp)
+	refreshToken, err := s.generateToken(user, sessionID, s.config.JWT.RefreshTokenExp)
 	if err != nil {
 		return nil, err
 	}
@@ -53,11 +59,13 @@ func (s *JWTService) GenerateTokenPair(user *models.User) (*TokenPair, error) {
 	}, nil
 }
 
-func (s *JWTService) generateToken(user *models.User, expiration time.Duration) (string, error) {
+func (s *JWTService) generateToken(user *models.User, sessionID uuid.UUID, expiration time.Duration) (string, error) {
 	claims := CustomClaims{
-		UserID: user.ID,
-		Email:  user.Email,
+		UserID:    user.ID,
+		Email:     user.Email,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(), // Makes every token unique, even when issued in the same second
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -106,7 +114,7 @@ func (s *JWTService) RefreshTokens(refreshToken string) (*TokenPair, error) {
 		Email: claims.Email,
 	}
 
-	return s.GenerateTokenPair(user)
+	return s.GenerateSessionTokenPair(user, claims.SessionID)
 }
 
 func (s *JWTService) GetAccessTokenExpiration() time.Duration {
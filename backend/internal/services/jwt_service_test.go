@@ -178,6 +178,30 @@ func (suite *JWTServiceTestSuite) TestRefreshTokens_Success() {
 	suite.Equal(suite.testUser.Email, refreshClaims.Email)
 }
 
+// Test GenerateSessionTokenPair - both tokens carry the session and are unique
+func (suite *JWTServiceTestSuite) TestGenerateSessionTokenPair_CarriesSessionID() {
+	sessionID := uuid.New()
+
+	pair, err := suite.service.GenerateSessionTokenPair(suite.testUser, sessionID)
+	suite.NoError(err)
+	suite.NotEqual(pair.AccessToken, pair.RefreshToken)
+
+	accessClaims, err := suite.service.ValidateToken(pair.AccessToken)
+	suite.NoError(err)
+	suite.Equal(sessionID, accessClaims.SessionID)
+
+	refreshClaims, err := suite.service.ValidateToken(pair.RefreshToken)
+	suite.NoError(err)
+	suite.Equal(sessionID, refreshClaims.SessionID)
+
+	// Refreshing keeps the session
+	newPair, err := suite.service.RefreshTokens(pair.RefreshToken)
+	suite.NoError(err)
+	newClaims, err := suite.service.ValidateToken(newPair.AccessToken)
+	suite.NoError(err)
+	suite.Equal(sessionID, newClaims.SessionID)
+}
+
 // Test RefreshTokens - Invalid Refresh Token
 func (suite *JWTServiceTestSuite) TestRefreshTokens_InvalidToken() {
 	newPair, err := suite.service.RefreshTokens("invalid-token")
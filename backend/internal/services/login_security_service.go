@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+)
+
+// loginHistoryLimit is the number of login events returned to a user
+const loginHistoryLimit = 50
+
+// LockoutError reports that a login was refused because of earlier failures
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%v, retry after %s", ErrAccountLocked, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LockoutError) Unwrap() error {
+	return ErrAccountLocked
+}
+
+// LoginSecurityService wraps password login with lockout after repeated
+// failures and records every attempt for the user's login history
+type LoginSecurityService struct {
+	userService UserServiceInterface
+	userRepo    repository.UserRepositoryInterface
+	eventRepo   repository.LoginEventRepositoryInterface
+
+	threshold     int
+	baseDuration  time.Duration
+	maxDuration   time.Duration
+	failureWindow time.Duration
+	ipThreshold   int
+	ipWindow      time.Duration
+
+	now func() time.Time
+}
+
+func NewLoginSecurityService(cfg *config.Config, userService UserServiceInterface, userRepo repository.UserRepositoryInterface, eventRepo repository.LoginEventRepositoryInterface) *LoginSecurityService {
+	return &LoginSecurityService{
+		userService:   userService,
+		userRepo:      userRepo,
+		eventRepo:     eventRepo,
+		threshold:     cfg.LoginLockout.Threshold,
+		baseDuration:  cfg.LoginLockout.BaseDuration,
+		maxDuration:   cfg.LoginLockout.MaxDuration,
+		failureWindow: cfg.LoginLockout.FailureWindow,
+		ipThreshold:   cfg.LoginLockout.IPThreshold,
+		ipWindow:      cfg.LoginLockout.IPWindow,
+		now:           time.Now,
+	}
+}
+
+// Authenticate checks the password unless the account or IP is locked out, and records the attempt
+func (s *LoginSecurityService) Authenticate(email, password, ipAddress, userAgent string) (*models.User, error) {
+	event := &models.LoginEvent{
+		Email:     normalizeLoginEmail(email),
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	}
+
+	retryAfter, err := s.lockout(event.Email, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+	if retryAfter > 0 {
+		event.FailureReason = models.LoginFailureLocked
+		s.attachUser(event, email)
+		s.record(event)
+		return nil, &LockoutError{RetryAfter: retryAfter}
+	}
+
+	user, err := s.userService.AuthenticateUser(email, password)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			event.FailureReason = models.LoginFailureInvalidCredentials
+			s.attachUser(event, email)
+			s.record(event)
+		}
+		return nil, err
+	}
+
+	event.UserID = &user.ID
+	event.Success = true
+	event.Suspicious = s.isNewLocation(user.ID, ipAddress)
+	if event.Suspicious {
+		log.Printf("Login for user %s from new IP address %s", user.ID, ipAddress)
+	}
+	s.record(event)
+
+	return user, nil
+}
+
+// GetLoginHistory returns the user's most recent login events
+func (s *LoginSecurityService) GetLoginHistory(userID uuid.UUID) ([]*models.LoginEvent, error) {
+	return s.eventRepo.GetByUserID(userID, loginHistoryLimit)
+}
+
+// lockout returns how long logins for the email from the IP are refused, or zero if allowed.
+// Each failure past the threshold doubles the account lockout, up to maxDuration.
+func (s *LoginSecurityService) lockout(email, ipAddress string) (time.Duration, error) {
+	now := s.now()
+
+	since := now.Add(-s.failureWindow)
+	lastSuccess, err := s.eventRepo.GetLastSuccessAt(email)
+	if err != nil {
+		return 0, err
+	}
+	if lastSuccess != nil && lastSuccess.After(since) {
+		since = *lastSuccess
+	}
+
+	failures, lastFailure, err := s.eventRepo.CountFailuresByEmail(email, since)
+	if err != nil {
+		return 0, err
+	}
+	if s.threshold > 0 && failures >= int64(s.threshold) && lastFailure != nil {
+		lockedUntil := lastFailure.Add(s.lockoutDuration(failures))
+		if now.Before(lockedUntil) {
+			return lockedUntil.Sub(now), nil
+		}
+	}
+
+	if s.ipThreshold > 0 {
+		ipFailures, err := s.eventRepo.CountFailuresByIP(ipAddress, now.Add(-s.ipWindow))
+		if err != nil {
+			return 0, err
+		}
+		if ipFailures >= int64(s.ipThreshold) {
+			return s.ipWindow, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// lockoutDuration returns the lockout after the given number of consecutive failures
+func (s *LoginSecurityService) lockoutDuration(failures int64) time.Duration {
+	duration := s.baseDuration
+	for i := int64(s.threshold); i < failures && duration < s.maxDuration; i++ {
+		duration *= 2
+	}
+	return min(duration, s.maxDuration)
+}
+
+// isNewLocation reports whether the user has logged in before, but never from this IP
+func (s *LoginSecurityService) isNewLocation(userID uuid.UUID, ipAddress string) bool {
+	total, fromIP, err := s.eventRepo.CountSuccessesByUser(userID, ipAddress)
+	if err != nil {
+		log.Printf("Failed to check login history of user %s: %v", userID, err)
+		errorreport.Capture(context.Background(), err, errorreport.UserTag(userID))
+		return false
+	}
+	return total > 0 && fromIP == 0
+}
+
+// attachUser links a failed attempt to the account it targeted so it shows in the user's history
+func (s *LoginSecurityService) attachUser(event *models.LoginEvent, email string) {
+	if user, err := s.userRepo.GetByEmail(email); err == nil && user != nil {
+		event.UserID = &user.ID
+	}
+}
+
+// record stores a login event. Failures are logged but never fail the login.
+func (s *LoginSecurityService) record(event *models.LoginEvent) {
+	if err := s.eventRepo.Create(event); err != nil {
+		log.Printf("Failed to record login event: %v", err)
+		errorreport.Capture(context.Background(), err)
+	}
+}
+
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type LoginSecurityServiceTestSuite struct {
+	suite.Suite
+	mockUserRepo  *mockRepo.MockUserRepository
+	mockEventRepo *mockRepo.MockLoginEventRepository
+	service       *LoginSecurityService
+	now           time.Time
+	user          *models.User
+}
+
+func (suite *LoginSecurityServiceTestSuite) SetupTest() {
+	suite.mockUserRepo = new(mockRepo.MockUserRepository)
+	suite.mockEventRepo = new(mockRepo.MockLoginEventRepository)
+
+	cfg := config.New()
+	cfg.LoginLockout.Threshold = 5
+	cfg.LoginLockout.BaseDuration = time.Minute
+	cfg.LoginLockout.MaxDuration = time.Hour
+	cfg.LoginLockout.FailureWindow = 24 * time.Hour
+	cfg.LoginLockout.IPThreshold = 50
+	cfg.LoginLockout.IPWindow = 15 * time.Minute
+
+	suite.service = NewLoginSecurityService(cfg, NewUserService(suite.mockUserRepo), suite.mockUserRepo, suite.mockEventRepo)
+	suite.now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	suite.service.now = func() time.Time { return suite.now }
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.user = createTestUser()
+	suite.user.PasswordHash = string(hash)
+}
+
+func TestLoginSecurityServiceSuite(t *testing.T) {
+	suite.Run(t, new(LoginSecurityServiceTestSuite))
+}
+
+// expectNoLockout sets up a clean failure history for the test user's email
+func (suite *LoginSecurityServiceTestSuite) expectNoLockout(ipAddress string) {
+	suite.mockEventRepo.On("GetLastSuccessAt", suite.user.Email).Return(nil, nil)
+	suite.mockEventRepo.On("CountFailuresByEmail", suite.user.Email, suite.now.Add(-24*time.Hour)).Return(int64(0), nil, nil)
+	suite.mockEventRepo.On("CountFailuresByIP", ipAddress, suite.now.Add(-15*time.Minute)).Return(int64(0), nil)
+}
+
+// Test Authenticate - success from a known IP is recorded and not suspicious
+func (suite *LoginSecurityServiceTestSuite) TestAuthenticate_Success() {
+	suite.expectNoLockout("192.0.2.1")
+	suite.mockUserRepo.On("GetByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockEventRepo.On("CountSuccessesByUser", suite.user.ID, "192.0.2.1").Return(int64(3), int64(2), nil)
+	suite.mockEventRepo.On("Create", mock.MatchedBy(func(event *models.LoginEvent) bool {
+		return event.Success && !event.Suspicious && *event.UserID == suite.user.ID &&
+			event.IPAddress == "192.0.2.1" && event.UserAgent == "test-agent"
+	})).Return(nil)
+
+	user, err := suite.service.Authenticate(suite.user.Email, "password123", "192.0.2.1", "test-agent")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), suite.user, user)
+	suite.mockEventRepo.AssertExpectations(suite.T())
+}
+
+// Test Authenticate - success from an IP never used before is flagged
+func (suite *LoginSecurityServiceTestSuite) TestAuthenticate_NewIPIsSuspicious() {
+	suite.expectNoLockout("198.51.100.9")
+	suite.mockUserRepo.On("GetByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockEventRepo.On("CountSuccessesByUser", suite.user.ID, "198.51.100.9").Return(int64(3), int64(0), nil)
+	suite.mockEventRepo.On("Create", mock.MatchedBy(func(event *models.LoginEvent) bool {
+		return event.Success && event.Suspicious
+	})).Return(nil)
+
+	_, err := suite.service.Authenticate(suite.user.Email, "password123", "198.51.100.9", "test-agent")
+
+	assert.NoError(suite.T(), err)
+	suite.mockEventRepo.AssertExpectations(suite.T())
+}
+
+// Test Authenticate - the first ever login is not suspicious
+func (suite *LoginSecurityServiceTestSuite) TestAuthenticate_FirstLoginNotSuspicious() {
+	suite.expectNoLockout("192.0.2.1")
+	suite.mockUserRepo.On("GetByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockEventRepo.On("CountSuccessesByUser", suite.user.ID, "192.0.2.1").Return(int64(0), int64(0), nil)
+	suite.mockEventRepo.On("Create", mock.MatchedBy(func(event *models.LoginEvent) bool {
+		return event.Success && !event.Suspicious
+	})).Return(nil)
+
+	_, err := suite.service.Authenticate(suite.user.Email, "password123", "192.0.2.1", "test-agent")
+
+	assert.NoError(suite.T(), err)
+	suite.mockEventRepo.AssertExpectations(suite.T())
+}
+
+// Test Authenticate - a wrong password records a failure linked to the account
+func (suite *LoginSecurityServiceTestSuite) TestAuthenticate_WrongPassword() {
+	suite.expectNoLockout("192.0.2.1")
+	suite.mockUserRepo.On("GetByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockEventRepo.On("Create", mock.MatchedBy(func(event *models.LoginEvent) bool {
+		return !event.Success && event.FailureReason == models.LoginFailureInvalidCredentials &&
+			event.UserID != nil && *event.UserID == suite.user.ID
+	})).Return(nil)
+
+	user, err := suite.service.Authenticate(suite.user.Email, "wrong", "192.0.2.1", "test-agent")
+
+	assert.Nil(suite.T(), user)
+	assert.ErrorIs(suite.T(), err, ErrInvalidCredentials)
+	suite.mockEventRepo.AssertExpectations(suite.T())
+}
+
+// Test Authenticate - unknown emails are recorded without a user
+func (suite *LoginSecurityServiceTestSuite) TestAuthenticate_UnknownEmail() {
+	suite.mockEventRepo.On("GetLastSuccessAt", "nobody@example.com").Return(nil, nil)
+	suite.mockEventRepo.On("CountFailuresByEmail", "nobody@example.com", mock.Anything).Return(int64(0), nil, nil)
+	suite.mockEventRepo.On("CountFailuresByIP", "192.0.2.1", mock.Anything).Return(int64(0), nil)
+	suite.mockUserRepo.On("GetByEmail", " Nobody@Example.com").Return(nil, gorm.ErrRecordNotFound)
+	suite.mockEventRepo.On("Create", mock.MatchedBy(func(event *models.LoginEvent) bool {
+		return event.UserID == nil && event.Email == "nobody@example.com"
+	})).Return(nil)
+
+	_, err := suite.service.Authenticate(" Nobody@Example.com", "password123", "192.0.2.1", "test-agent")
+
+	assert.ErrorIs(suite.T(), err, ErrInvalidCredentials)
+	suite.mockEventRepo.AssertExpectations(suite.T())
+}
+
+// Test Authenticate - reaching the threshold locks the account without checking the password
+func (suite *LoginSecurityServiceTestSuite) TestAuthenticate_AccountLocked() {
+	lastFailure := suite.now.Add(-30 * time.Second)
+	suite.mockEventRepo.On("GetLastSuccessAt", suite.user.Email).Return(nil, nil)
+	suite.mockEventRepo.On("CountFailuresByEmail", suite.user.Email, mock.Anything).Return(int64(5), &lastFailure, nil)
+	suite.mockUserRepo.On("GetByEmail", suite.user.Email).Return(suite.user, nil).Once()
+	suite.mockEventRepo.On("Create", mock.MatchedBy(func(event *models.LoginEvent) bool {
+		return event.FailureReason == models.LoginFailureLocked
+	})).Return(nil)
+
+	user, err := suite.service.Authenticate(suite.user.Email, "password123", "192.0.2.1", "test-agent")
+
+	assert.Nil(suite.T(), user)
+	assert.ErrorIs(suite.T(), err, ErrAccountLocked)
+	var lockoutErr *LockoutError
+	suite.Require().True(errors.As(err, &lockoutErr))
+	assert.Equal(suite.T(), 30*time.Second, lockoutErr.RetryAfter)
+	suite.mockEventRepo.AssertExpectations(suite.T())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+// Test Authenticate - the lockout expires after the backoff
+func (suite *LoginSecurityServiceTestSuite) TestAuthenticate_LockoutExpired() {
+	lastFailure := suite.now.Add(-2 * time.Minute)
+	suite.mockEventRepo.On("GetLastSuccessAt", suite.user.Email).Return(nil, nil)
+	suite.mockEventRepo.On("CountFailuresByEmail", suite.user.Email, mock.Anything).Return(int64(5), &lastFailure, nil)
+	suite.mockEventRepo.On("CountFailuresByIP", "192.0.2.1", mock.Anything).Return(int64(0), nil)
+	suite.mockUserRepo.On("GetByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockEventRepo.On("CountSuccessesByUser", suite.user.ID, "192.0.2.1").Return(int64(1), int64(1), nil)
+	suite.mockEventRepo.On("Create", mock.AnythingOfType("*models.LoginEvent")).Return(nil)
+
+	_, err := suite.service.Authenticate(suite.user.Email, "password123", "192.0.2.1", "test-agent")
+
+	assert.NoError(suite.T(), err)
+}
+
+// Test Authenticate - failures before the last successful login are ignored
+func (suite *LoginSecurityServiceTestSuite) TestAuthenticate_CountsFailuresSinceLastSuccess() {
+	lastSuccess := suite.now.Add(-time.Hour)
+	suite.mockEventRepo.On("GetLastSuccessAt", suite.user.Email).Return(&lastSuccess, nil)
+	suite.mockEventRepo.On("CountFailuresByEmail", suite.user.Email, lastSuccess).Return(int64(0), nil, nil)
+	suite.mockEventRepo.On("CountFailuresByIP", "192.0.2.1", mock.Anything).Return(int64(0), nil)
+	suite.mockUserRepo.On("GetByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockEventRepo.On("CountSuccessesByUser", suite.user.ID, "192.0.2.1").Return(int64(1), int64(1), nil)
+	suite.mockEventRepo.On("Create", mock.AnythingOfType("*models.LoginEvent")).Return(nil)
+
+	_, err := suite.service.Authenticate(suite.user.Email, "password123", "192.0.2.1", "test-agent")
+
+	assert.NoError(suite.T(), err)
+	suite.mockEventRepo.AssertExpectations(suite.T())
+}
+
+// Test Authenticate - too many failures from one IP lock that IP out
+func (suite *LoginSecurityServiceTestSuite) TestAuthenticate_IPLocked() {
+	suite.mockEventRepo.On("GetLastSuccessAt", suite.user.Email).Return(nil, nil)
+	suite.mockEventRepo.On("CountFailuresByEmail", suite.user.Email, mock.Anything).Return(int64(0), nil, nil)
+	suite.mockEventRepo.On("CountFailuresByIP", "192.0.2.1", suite.now.Add(-15*time.Minute)).Return(int64(50), nil)
+	suite.mockUserRepo.On("GetByEmail", suite.user.Email).Return(suite.user, nil)
+	suite.mockEventRepo.On("Create", mock.AnythingOfType("*models.LoginEvent")).Return(nil)
+
+	_, err := suite.service.Authenticate(suite.user.Email, "password123", "192.0.2.1", "test-agent")
+
+	var lockoutErr *LockoutError
+	suite.Require().True(errors.As(err, &lockoutErr))
+	assert.Equal(suite.T(), 15*time.Minute, lockoutErr.RetryAfter)
+}
+
+// Test Authenticate - repository errors are returned
+func (suite *LoginSecurityServiceTestSuite) TestAuthenticate_RepositoryError() {
+	suite.mockEventRepo.On("GetLastSuccessAt", suite.user.Email).Return(nil, assert.AnError)
+
+	_, err := suite.service.Authenticate(suite.user.Email, "password123", "192.0.2.1", "test-agent")
+
+	assert.ErrorIs(suite.T(), err, assert.AnError)
+}
+
+// Test lockoutDuration - doubles per failure past the threshold and is capped
+func (suite *LoginSecurityServiceTestSuite) TestLockoutDuration() {
+	assert.Equal(suite.T(), time.Minute, suite.service.lockoutDuration(5))
+	assert.Equal(suite.T(), 2*time.Minute, suite.service.lockoutDuration(6))
+	assert.Equal(suite.T(), 32*time.Minute, suite.service.lockoutDuration(10))
+	assert.Equal(suite.T(), time.Hour, suite.service.lockoutDuration(11))
+	assert.Equal(suite.T(), time.Hour, suite.service.lockoutDuration(1000))
+}
+
+// Test GetLoginHistory - returns the user's recent events
+func (suite *LoginSecurityServiceTestSuite) TestGetLoginHistory() {
+	userID := uuid.New()
+	events := []*models.LoginEvent{{ID: uuid.New(), UserID: &userID}}
+	suite.mockEventRepo.On("GetByUserID", userID, loginHistoryLimit).Return(events, nil)
+
+	result, err := suite.service.GetLoginHistory(userID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), events, result)
+}
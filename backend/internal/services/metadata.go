@@ -0,0 +1,29 @@
+package services
+
+import "github.com/Bug-Bugger/ezmodel/internal/models"
+
+// newMetadata is the metadata an object is created with, without the
+// members set to null. It fails with ErrInvalidInput beyond the limits.
+func newMetadata(metadata map[string]any) (models.Metadata, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	return patchMetadata(nil, &metadata)
+}
+
+// patchMetadata applies the metadata of an update to an object's: none
+// leaves it as it is, null clears it and an object is merged in as a JSON
+// Merge Patch. It fails with ErrInvalidInput beyond the limits.
+func patchMetadata(current models.Metadata, patch *map[string]any) (models.Metadata, error) {
+	if patch == nil {
+		return current, nil
+	}
+	if *patch == nil {
+		return models.Metadata{}, nil
+	}
+	merged := current.Merge(*patch)
+	if !merged.Valid() {
+		return nil, ErrInvalidInput
+	}
+	return merged, nil
+}
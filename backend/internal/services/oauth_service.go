@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/endpoints"
+)
+
+// Supported OAuth login providers
+const (
+	OAuthProviderGoogle = "google"
+	OAuthProviderGitHub = "github"
+)
+
+const (
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+	githubUserURL     = "https://api.github.com/user"
+	githubEmailsURL   = "https://api.github.com/user/emails"
+)
+
+// oauthProvider pairs a provider's OAuth2 client config with the call that loads the user's profile
+type oauthProvider struct {
+	config       *oauth2.Config
+	fetchProfile func(ctx context.Context, client *http.Client) (*ExternalProfile, error)
+}
+
+type OAuthService struct {
+	identities *identityLinker
+	providers  map[string]*oauthProvider
+}
+
+func NewOAuthService(cfg *config.Config, userRepo repository.UserRepositoryInterface, identityRepo repository.UserIdentityRepositoryInterface) *OAuthService {
+	s := &OAuthService{
+		identities: newIdentityLinker(userRepo, identityRepo),
+		providers:  make(map[string]*oauthProvider),
+	}
+
+	if cfg.OAuth.Google.ClientID != "" {
+		s.providers[OAuthProviderGoogle] = &oauthProvider{
+			config:       newOAuthConfig(cfg, OAuthProviderGoogle, cfg.OAuth.Google, endpoints.Google, "openid", "email", "profile"),
+			fetchProfile: fetchGoogleProfile,
+		}
+	}
+	if cfg.OAuth.GitHub.ClientID != "" {
+		s.providers[OAuthProviderGitHub] = &oauthProvider{
+			config:       newOAuthConfig(cfg, OAuthProviderGitHub, cfg.OAuth.GitHub, endpoints.GitHub, "read:user", "user:email"),
+			fetchProfile: fetchGitHubProfile,
+		}
+	}
+
+	return s
+}
+
+func newOAuthConfig(cfg *config.Config, provider string, client config.OAuthClient, endpoint oauth2.Endpoint, scopes ...string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     client.ClientID,
+		ClientSecret: client.ClientSecret,
+		Endpoint:     endpoint,
+		RedirectURL:  fmt.Sprintf("%s/api/auth/oauth/%s/callback", cfg.OAuth.RedirectBaseURL, provider),
+		Scopes:       scopes,
+	}
+}
+
+// AuthCodeURL returns the provider's consent page URL for the given state and PKCE verifier
+func (s *OAuthService) AuthCodeURL(provider, state, verifier string) (string, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", ErrUnknownOAuthProvider
+	}
+
+	return p.config.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Authenticate exchanges an authorization code for the provider profile and
+// returns the linked user, linking or creating an account on first login
+func (s *OAuthService) Authenticate(ctx context.Context, provider, code, verifier string) (*models.User, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrUnknownOAuthProvider
+	}
+
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOAuthExchangeFailed, err)
+	}
+
+	profile, err := p.fetchProfile(ctx, p.config.Client(ctx, token))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOAuthExchangeFailed, err)
+	}
+
+	// Only link by email when the provider vouches for it
+	if !profile.EmailVerified {
+		return nil, ErrOAuthEmailNotVerified
+	}
+
+	return s.identities.resolve(provider, profile, true)
+}
+
+// getJSON fetches a URL with the authorized client and decodes the JSON response into target
+func getJSON(ctx context.Context, client *http.Client, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func fetchGoogleProfile(ctx context.Context, client *http.Client) (*ExternalProfile, error) {
+	var info struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	if err := getJSON(ctx, client, googleUserInfoURL, &info); err != nil {
+		return nil, err
+	}
+
+	return &ExternalProfile{
+		Subject:       info.Sub,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified,
+	}, nil
+}
+
+func fetchGitHubProfile(ctx context.Context, client *http.Client) (*ExternalProfile, error) {
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+	}
+	if err := getJSON(ctx, client, githubUserURL, &user); err != nil {
+		return nil, err
+	}
+
+	// The profile email may be hidden or unverified; use the primary verified address
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, client, githubEmailsURL, &emails); err != nil {
+		return nil, err
+	}
+
+	profile := &ExternalProfile{
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Username: user.Login,
+	}
+	for _, email := range emails {
+		if email.Primary {
+			profile.Email = email.Email
+			profile.EmailVerified = email.Verified
+			break
+		}
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type OAuthServiceTestSuite struct {
+	suite.Suite
+	mockUserRepo     *mockRepo.MockUserRepository
+	mockIdentityRepo *mockRepo.MockUserIdentityRepository
+	service          *OAuthService
+}
+
+func (suite *OAuthServiceTestSuite) SetupTest() {
+	cfg := &config.Config{}
+	cfg.OAuth.RedirectBaseURL = "http://api.example.com"
+	cfg.OAuth.GitHub.ClientID = "github-client"
+
+	suite.mockUserRepo = new(mockRepo.MockUserRepository)
+	suite.mockIdentityRepo = new(mockRepo.MockUserIdentityRepository)
+	suite.service = NewOAuthService(cfg, suite.mockUserRepo, suite.mockIdentityRepo)
+}
+
+func TestOAuthServiceSuite(t *testing.T) {
+	suite.Run(t, new(OAuthServiceTestSuite))
+}
+
+// Test AuthCodeURL - configured provider includes state, PKCE challenge and callback
+func (suite *OAuthServiceTestSuite) TestAuthCodeURL_Success() {
+	authURL, err := suite.service.AuthCodeURL(OAuthProviderGitHub, "state123", "verifier123")
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(authURL, "https://github.com/login/oauth/authorize"))
+	assert.Contains(suite.T(), authURL, "state=state123")
+	assert.Contains(suite.T(), authURL, "code_challenge_method=S256")
+	assert.Contains(suite.T(), authURL, "api.example.com%2Fapi%2Fauth%2Foauth%2Fgithub%2Fcallback")
+}
+
+// Test AuthCodeURL - providers without a client ID are disabled
+func (suite *OAuthServiceTestSuite) TestAuthCodeURL_UnknownProvider() {
+	_, err := suite.service.AuthCodeURL(OAuthProviderGoogle, "state", "verifier")
+
+	assert.ErrorIs(suite.T(), err, ErrUnknownOAuthProvider)
+}
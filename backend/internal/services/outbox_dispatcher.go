@@ -0,0 +1,133 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+)
+
+const (
+	// outboxBatchSize is how many events one dispatch transaction delivers
+	outboxBatchSize = 100
+
+	// outboxMaxAttempts is how often delivering an event may fail before it is given up
+	outboxMaxAttempts = 5
+
+	// outboxPurgeInterval is how often delivered events past retention are removed
+	outboxPurgeInterval = time.Hour
+)
+
+// OutboxDispatcher delivers the collaboration messages saved to the outbox
+// through the hub, which hands them to local clients, the broker and the event
+// sinks. It runs right after each commit and polls for events committed by
+// nodes that stopped before delivering them.
+type OutboxDispatcher struct {
+	outboxRepo   repository.OutboxRepositoryInterface
+	hub          *websocketPkg.Hub
+	pollInterval time.Duration
+	retention    time.Duration
+	now          func() time.Time
+
+	wake    chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+	running atomic.Bool
+}
+
+func NewOutboxDispatcher(outboxRepo repository.OutboxRepositoryInterface, hub *websocketPkg.Hub, pollInterval, retention time.Duration) *OutboxDispatcher {
+	return &OutboxDispatcher{
+		outboxRepo:   outboxRepo,
+		hub:          hub,
+		pollInterval: pollInterval,
+		retention:    retention,
+		now:          time.Now,
+		wake:         make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}
+}
+
+// Wake makes Run dispatch now rather than at the next poll. It never blocks.
+func (d *OutboxDispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run dispatches pending events until Stop is called
+func (d *OutboxDispatcher) Run() {
+	d.running.Store(true)
+	defer close(d.stopped)
+
+	poll := time.NewTicker(d.pollInterval)
+	defer poll.Stop()
+	purge := time.NewTicker(outboxPurgeInterval)
+	defer purge.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			d.dispatchAll() // Deliver what was committed before the shutdown
+			return
+		case <-d.wake:
+			d.dispatchAll()
+		case <-poll.C:
+			d.dispatchAll()
+		case <-purge.C:
+			d.purge()
+		}
+	}
+}
+
+// Stop delivers the events still pending and ends Run, if it was started
+func (d *OutboxDispatcher) Stop() {
+	close(d.stop)
+	if d.running.Load() {
+		<-d.stopped
+	}
+}
+
+// DispatchPending delivers every pending event, in batches, and returns how many were delivered
+func (d *OutboxDispatcher) DispatchPending() (int, error) {
+	total := 0
+	for {
+		dispatched, err := d.outboxRepo.DispatchPending(outboxBatchSize, outboxMaxAttempts, d.deliver)
+		total += dispatched
+		if err != nil || dispatched < outboxBatchSize {
+			return total, err
+		}
+	}
+}
+
+func (d *OutboxDispatcher) dispatchAll() {
+	if _, err := d.DispatchPending(); err != nil {
+		log.Printf("Failed to dispatch outbox events: %v", err)
+	}
+}
+
+func (d *OutboxDispatcher) deliver(event *models.OutboxEvent) error {
+	var message websocketPkg.WebSocketMessage
+	if err := json.Unmarshal(event.Message, &message); err != nil {
+		log.Printf("Failed to decode outbox event %d: %v", event.ID, err)
+		return err
+	}
+	d.hub.BroadcastToProject(event.ProjectID, &message, nil)
+	return nil
+}
+
+func (d *OutboxDispatcher) purge() {
+	deleted, err := d.outboxRepo.DeleteDispatchedBefore(d.now().Add(-d.retention))
+	if err != nil {
+		log.Printf("Failed to purge delivered outbox events: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Purged %d delivered outbox events", deleted)
+	}
+}
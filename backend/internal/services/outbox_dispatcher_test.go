@@ -0,0 +1,125 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	websocketPkg "github.com/Bug-Bugger/ezmodel/internal/websocket"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestOutboxDispatcherDeliversPendingEvents(t *testing.T) {
+	outboxRepo := new(mockRepo.MockOutboxRepository)
+	message, err := websocketPkg.NewWebSocketMessage(websocketPkg.MessageTypeTableCreated, websocketPkg.TablePayload{Name: "users"}, uuid.New(), uuid.New())
+	require.NoError(t, err)
+	data, err := json.Marshal(message)
+	require.NoError(t, err)
+	events := []*models.OutboxEvent{
+		{ID: 1, ProjectID: message.ProjectID, Message: data},
+		{ID: 2, ProjectID: message.ProjectID, Message: []byte("not json")},
+	}
+	outboxRepo.On("DispatchPending", outboxBatchSize, outboxMaxAttempts).Return(events, nil).Once()
+
+	dispatcher := NewOutboxDispatcher(outboxRepo, websocketPkg.NewHub(), time.Second, time.Hour)
+	dispatched, err := dispatcher.DispatchPending()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dispatched, "An event that cannot be decoded is not delivered")
+	outboxRepo.AssertExpectations(t)
+}
+
+func TestOutboxDispatcherPurgesPastRetention(t *testing.T) {
+	outboxRepo := new(mockRepo.MockOutboxRepository)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	outboxRepo.On("DeleteDispatchedBefore", now.Add(-24*time.Hour)).Return(int64(3), nil).Once()
+
+	dispatcher := NewOutboxDispatcher(outboxRepo, websocketPkg.NewHub(), time.Second, 24*time.Hour)
+	dispatcher.now = func() time.Time { return now }
+	dispatcher.purge()
+
+	outboxRepo.AssertExpectations(t)
+}
+
+func TestOutboxDispatcherStopWithoutRun(t *testing.T) {
+	dispatcher := NewOutboxDispatcher(new(mockRepo.MockOutboxRepository), websocketPkg.NewHub(), time.Second, time.Hour)
+
+	dispatcher.Stop() // Must not wait for a Run that never started
+}
+
+func TestCollaborationInTxSavesToOutbox(t *testing.T) {
+	outboxRepo := new(mockRepo.MockOutboxRepository)
+	projectRepo := new(mockRepo.MockProjectRepository)
+	projectID := uuid.New()
+	projectRepo.On("NextSequence", projectID).Return(int64(7), nil).Once()
+	outboxRepo.On("Create", mock.MatchedBy(func(event *models.OutboxEvent) bool {
+		var message websocketPkg.WebSocketMessage
+		return event.ProjectID == projectID && json.Unmarshal(event.Message, &message) == nil &&
+			message.Type == websocketPkg.MessageTypeTableCreated && message.Sequence == 7
+	})).Return(nil).Once()
+
+	dispatcher := NewOutboxDispatcher(outboxRepo, websocketPkg.NewHub(), time.Second, time.Hour)
+	collaborationService := NewCollaborationSessionService(nil, nil, nil, nil, nil, nil, websocketPkg.NewHub(), nil)
+	collaborationService.SetOutbox(dispatcher)
+	mockUnitOfWork := &mockRepo.MockUnitOfWork{Repos: repository.Repositories{Projects: projectRepo, Outbox: outboxRepo}}
+	mockUnitOfWork.On("Do").Return(nil)
+
+	err := NewUnitOfWork(mockUnitOfWork).Run(func(tx *Tx) error {
+		err := collaborationService.InTx(tx).NotifyTableCreated(projectID, &models.Table{ID: uuid.New(), Name: "users"}, uuid.New())
+		assert.Empty(t, dispatcher.wake, "The dispatcher should only wake after the commit")
+		assert.Equal(t, int64(7), tx.Sequence)
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, dispatcher.wake, 1)
+	outboxRepo.AssertExpectations(t)
+	projectRepo.AssertExpectations(t)
+}
+
+func TestCollaborationInTxFailsWhenOutboxFails(t *testing.T) {
+	outboxRepo := new(mockRepo.MockOutboxRepository)
+	outboxRepo.On("Create", mock.AnythingOfType("*models.OutboxEvent")).Return(gorm.ErrInvalidDB)
+	projectRepo := new(mockRepo.MockProjectRepository)
+	projectRepo.On("NextSequence", mock.Anything).Return(int64(1), nil)
+
+	dispatcher := NewOutboxDispatcher(outboxRepo, websocketPkg.NewHub(), time.Second, time.Hour)
+	collaborationService := NewCollaborationSessionService(nil, nil, nil, nil, nil, nil, websocketPkg.NewHub(), nil)
+	collaborationService.SetOutbox(dispatcher)
+	mockUnitOfWork := &mockRepo.MockUnitOfWork{Repos: repository.Repositories{Projects: projectRepo, Outbox: outboxRepo}}
+	mockUnitOfWork.On("Do").Return(nil)
+
+	err := NewUnitOfWork(mockUnitOfWork).Run(func(tx *Tx) error {
+		return collaborationService.InTx(tx).NotifyTableDeleted(uuid.New(), uuid.New(), "users", uuid.New())
+	})
+
+	assert.ErrorIs(t, err, gorm.ErrInvalidDB)
+	assert.Empty(t, dispatcher.wake)
+}
+
+func TestCollaborationInTxFailsWhenSequenceFails(t *testing.T) {
+	outboxRepo := new(mockRepo.MockOutboxRepository)
+	projectRepo := new(mockRepo.MockProjectRepository)
+	projectRepo.On("NextSequence", mock.Anything).Return(int64(0), gorm.ErrRecordNotFound)
+
+	dispatcher := NewOutboxDispatcher(outboxRepo, websocketPkg.NewHub(), time.Second, time.Hour)
+	collaborationService := NewCollaborationSessionService(nil, nil, nil, nil, nil, nil, websocketPkg.NewHub(), nil)
+	collaborationService.SetOutbox(dispatcher)
+	mockUnitOfWork := &mockRepo.MockUnitOfWork{Repos: repository.Repositories{Projects: projectRepo, Outbox: outboxRepo}}
+	mockUnitOfWork.On("Do").Return(nil)
+
+	err := NewUnitOfWork(mockUnitOfWork).Run(func(tx *Tx) error {
+		return collaborationService.InTx(tx).NotifyTableDeleted(uuid.New(), uuid.New(), "users", uuid.New())
+	})
+
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	outboxRepo.AssertNotCalled(t, "Create", mock.Anything)
+	assert.Empty(t, dispatcher.wake)
+}
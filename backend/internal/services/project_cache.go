@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/cache"
+	"github.com/Bug-Bugger/ezmodel/internal/errorreport"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+)
+
+// ProjectCache holds projects with their schema as loaded by GetProjectByID.
+// Entries are invalidated by every service change to a project, its schema or
+// its collaborators; the TTL bounds how stale an entry can get otherwise.
+// A nil *ProjectCache caches nothing.
+type ProjectCache struct {
+	store cache.Store
+	ttl   time.Duration
+}
+
+func NewProjectCache(store cache.Store, ttl time.Duration) *ProjectCache {
+	return &ProjectCache{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+func projectCacheKey(projectID uuid.UUID) string {
+	return "project:" + projectID.String()
+}
+
+// Get returns the cached project, or nil on a miss. Each call returns a new
+// copy, so callers may modify it.
+func (c *ProjectCache) Get(projectID uuid.UUID) *models.Project {
+	if c == nil {
+		return nil
+	}
+
+	value, err := c.store.Get(projectCacheKey(projectID))
+	if err != nil || value == nil {
+		return nil
+	}
+	var project models.Project
+	if err := json.Unmarshal(value, &project); err != nil {
+		log.Printf("Failed to decode cached project %s: %v", projectID, err)
+		return nil
+	}
+	return &project
+}
+
+func (c *ProjectCache) Set(project *models.Project) {
+	if c == nil {
+		return
+	}
+
+	value, err := json.Marshal(project)
+	if err != nil {
+		log.Printf("Failed to encode project %s for the cache: %v", project.ID, err)
+		return
+	}
+	if err := c.store.Set(projectCacheKey(project.ID), value, c.ttl); err != nil {
+		log.Printf("Failed to cache project %s: %v", project.ID, err)
+	}
+}
+
+// Invalidate drops the cached projects so the next read loads them again
+func (c *ProjectCache) Invalidate(projectIDs ...uuid.UUID) {
+	if c == nil || len(projectIDs) == 0 {
+		return
+	}
+
+	keys := make([]string, len(projectIDs))
+	for i, projectID := range projectIDs {
+		keys[i] = projectCacheKey(projectID)
+	}
+	if err := c.store.Delete(keys...); err != nil {
+		log.Printf("Failed to invalidate cached projects %v: %v", projectIDs, err)
+		errorreport.Capture(context.Background(), err, errorreport.ProjectTag(projectIDs[0]))
+	}
+}
@@ -3,26 +3,51 @@ package services
 import (
 	"errors"
 	"log"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/canvas"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+const (
+	// maxProjectTags bounds the labels on one project
+	maxProjectTags = 20
+
+	defaultRecentProjects = 10
+	maxRecentProjects     = 50
+)
+
+// projectTagPattern allows lowercase labels such as "billing" or "q3-launch"
+var projectTagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
 type ProjectService struct {
 	projectRepo          repository.ProjectRepositoryInterface
 	userRepo             repository.UserRepositoryInterface
 	collaborationService CollaborationSessionServiceInterface
+	projectCache         *ProjectCache
+	accessCache          *AccessCache
+	unitOfWork           *UnitOfWork
+	maxCanvasSize        int // In bytes, canvas.DefaultMaxSize when not positive
+	quotas               QuotaPolicy
 }
 
-func NewProjectService(projectRepo repository.ProjectRepositoryInterface, userRepo repository.UserRepositoryInterface, collaborationService CollaborationSessionServiceInterface) *ProjectService {
+func NewProjectService(projectRepo repository.ProjectRepositoryInterface, userRepo repository.UserRepositoryInterface, collaborationService CollaborationSessionServiceInterface, projectCache *ProjectCache, accessCache *AccessCache, unitOfWork *UnitOfWork, maxCanvasSize int, quotas QuotaPolicy) *ProjectService {
 	return &ProjectService{
 		projectRepo:          projectRepo,
 		userRepo:             userRepo,
 		collaborationService: collaborationService,
+		projectCache:         projectCache,
+		accessCache:          accessCache,
+		unitOfWork:           unitOfWork,
+		maxCanvasSize:        maxCanvasSize,
+		quotas:               quotas,
 	}
 }
 
@@ -47,12 +72,24 @@ func (s *ProjectService) CreateProject(name, description string, ownerID uuid.UU
 		return nil, err
 	}
 
+	quotas, err := s.quotas.QuotasFor(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	owned, err := s.projectRepo.CountByOwnerID(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := quotas.CheckProjects(owned + 1); err != nil {
+		return nil, err
+	}
+
 	project := &models.Project{
 		Name:         name,
 		Description:  description,
 		OwnerID:      ownerID,
 		DatabaseType: "postgresql", // Default to PostgreSQL
-		CanvasData:   "{}",         // Initialize with empty JSON object
+		CanvasData:   canvas.Empty,
 	}
 
 	id, err := s.projectRepo.Create(project)
@@ -64,7 +101,12 @@ func (s *ProjectService) CreateProject(name, description string, ownerID uuid.UU
 	return project, nil
 }
 
+// GetProjectByID returns the project with its schema, from the project cache when it is there
 func (s *ProjectService) GetProjectByID(id uuid.UUID) (*models.Project, error) {
+	if project := s.projectCache.Get(id); project != nil {
+		return project, nil
+	}
+
 	project, err := s.projectRepo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -72,19 +114,24 @@ func (s *ProjectService) GetProjectByID(id uuid.UUID) (*models.Project, error) {
 		}
 		return nil, err
 	}
+	s.projectCache.Set(project)
 	return project, nil
 }
 
-func (s *ProjectService) GetProjectsByOwnerID(ownerID uuid.UUID) ([]*models.Project, error) {
-	return s.projectRepo.GetByOwnerID(ownerID)
+// GetProjectsByOwnerID lists the user's projects with their tags and the includes
+func (s *ProjectService) GetProjectsByOwnerID(ownerID uuid.UUID, includes repository.ProjectIncludes) ([]*models.Project, error) {
+	return s.projectRepo.GetByOwnerID(ownerID, includes)
 }
 
-func (s *ProjectService) GetProjectsByCollaboratorID(collaboratorID uuid.UUID) ([]*models.Project, error) {
-	return s.projectRepo.GetByCollaboratorID(collaboratorID)
+// GetProjectsByCollaboratorID lists the projects the user collaborates on with
+// their tags and the includes
+func (s *ProjectService) GetProjectsByCollaboratorID(collaboratorID uuid.UUID, includes repository.ProjectIncludes) ([]*models.Project, error) {
+	return s.projectRepo.GetByCollaboratorID(collaboratorID, includes)
 }
 
-func (s *ProjectService) GetAllProjects() ([]*models.Project, error) {
-	return s.projectRepo.GetAll()
+func (s *ProjectService) ListProjects(filter repository.ProjectFilter, page repository.PageQuery) ([]*models.Project, string, error) {
+	filter.Name = strings.TrimSpace(filter.Name)
+	return s.projectRepo.List(filter, page)
 }
 
 func (s *ProjectService) UpdateProject(id uuid.UUID, req *dto.UpdateProjectRequest, userID uuid.UUID) (*models.Project, error) {
@@ -95,6 +142,9 @@ func (s *ProjectService) UpdateProject(id uuid.UUID, req *dto.UpdateProjectReque
 		}
 		return nil, err
 	}
+	if req.Version != nil && *req.Version != project.Version {
+		return project, ErrVersionConflict
+	}
 
 	// Only update fields that were provided
 	if req.Name != nil {
@@ -114,35 +164,58 @@ func (s *ProjectService) UpdateProject(id uuid.UUID, req *dto.UpdateProjectReque
 	}
 
 	if req.CanvasData != nil {
-		canvasData := strings.TrimSpace(*req.CanvasData)
-		// Validate that it's valid JSON (basic check)
-		if canvasData == "" {
-			canvasData = "{}"
+		// Checked here so nothing that breaks rendering or export is saved or broadcast
+		canvasData, err := canvas.Validate(*req.CanvasData, s.maxCanvasSize)
+		if err != nil {
+			return nil, err
 		}
-		// Note: For production, you might want to use json.Valid() for proper validation
 		project.CanvasData = canvasData
 
 		// Debug logging for canvas data updates
 		log.Printf("CANVAS DEBUG: Updating canvas data for project %s, data length: %d",
 			project.ID.String(), len(canvasData))
+	}
 
-		// Broadcast canvas update to collaborators FIRST if canvas data was changed
-		if s.collaborationService != nil {
-			if err := s.collaborationService.BroadcastCanvasUpdate(id, project.CanvasData, userID); err != nil {
-				// Log error but don't fail the operation
-				// TODO: Add proper logging
-			}
+	if req.Metadata != nil {
+		if project.Metadata, err = patchMetadata(project.Metadata, req.Metadata); err != nil {
+			return nil, err
 		}
 	}
 
-	// Then persist to database
-	if err := s.projectRepo.Update(project); err != nil {
+	// Persist with the canvas broadcast, so a change that loses a concurrent update is never broadcast
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := tx.Projects.Update(project); err != nil {
+			return err
+		}
+
+		if req.CanvasData != nil && s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).BroadcastCanvasUpdate(id, project.CanvasData, userID); err != nil {
+				return err
+			}
+			project.Sequence = tx.Sequence
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return s.currentProject(id)
+		}
 		return nil, err
 	}
+	s.projectCache.Invalidate(id)
 
 	return project, nil
 }
 
+// currentProject returns the project as another writer left it, with ErrVersionConflict
+func (s *ProjectService) currentProject(id uuid.UUID) (*models.Project, error) {
+	project, err := s.projectRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return project, ErrVersionConflict
+}
+
 func (s *ProjectService) DeleteProject(id uuid.UUID) error {
 	_, err := s.projectRepo.GetByID(id)
 	if err != nil {
@@ -152,12 +225,17 @@ func (s *ProjectService) DeleteProject(id uuid.UUID) error {
 		return err
 	}
 
-	return s.projectRepo.Delete(id)
+	if err := s.projectRepo.Delete(id); err != nil {
+		return err
+	}
+	s.projectCache.Invalidate(id)
+	s.accessCache.InvalidateMembers(id)
+	return nil
 }
 
 func (s *ProjectService) AddCollaborator(projectID, collaboratorID uuid.UUID) error {
 	// Verify project exists
-	_, err := s.projectRepo.GetByID(projectID)
+	project, err := s.projectRepo.GetByID(projectID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrProjectNotFound
@@ -166,7 +244,7 @@ func (s *ProjectService) AddCollaborator(projectID, collaboratorID uuid.UUID) er
 	}
 
 	// Verify collaborator exists
-	_, err = s.userRepo.GetByID(collaboratorID)
+	collaborator, err := s.userRepo.GetByID(collaboratorID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrCollaboratorNotFound
@@ -174,7 +252,23 @@ func (s *ProjectService) AddCollaborator(projectID, collaboratorID uuid.UUID) er
 		return err
 	}
 
-	return s.projectRepo.AddCollaborator(projectID, collaboratorID)
+	isCollaborator := slices.ContainsFunc(project.Collaborators, func(u models.User) bool { return u.ID == collaboratorID })
+	if !isCollaborator && !collaborator.IsServiceAccount {
+		quotas, err := s.quotas.QuotasFor(project.OwnerID)
+		if err != nil {
+			return err
+		}
+		if err := quotas.CheckCollaborators(countCollaborators(project.Collaborators) + 1); err != nil {
+			return err
+		}
+	}
+
+	if err := s.projectRepo.AddCollaborator(projectID, collaboratorID); err != nil {
+		return err
+	}
+	s.projectCache.Invalidate(projectID)
+	s.accessCache.InvalidateMembers(projectID)
+	return nil
 }
 
 func (s *ProjectService) RemoveCollaborator(projectID, collaboratorID uuid.UUID) error {
@@ -196,5 +290,185 @@ func (s *ProjectService) RemoveCollaborator(projectID, collaboratorID uuid.UUID)
 		return err
 	}
 
-	return s.projectRepo.RemoveCollaborator(projectID, collaboratorID)
+	if err := s.projectRepo.RemoveCollaborator(projectID, collaboratorID); err != nil {
+		return err
+	}
+	s.projectCache.Invalidate(projectID)
+	s.accessCache.InvalidateMembers(projectID)
+
+	// Removed collaborators stop editing now rather than when they reconnect
+	if err := s.collaborationService.DisconnectCollaborator(projectID, collaboratorID, "You no longer have access to this project"); err != nil {
+		log.Printf("Error disconnecting removed collaborator %s from project %s: %v", collaboratorID, projectID, err)
+	}
+	return nil
+}
+
+// KickCollaborator closes a collaborator's WebSockets to the project without
+// removing them from it; they may reconnect. Only the owner may kick.
+func (s *ProjectService) KickCollaborator(projectID, collaboratorID, userID uuid.UUID) error {
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProjectNotFound
+		}
+		return err
+	}
+	if project.OwnerID != userID {
+		return ErrForbidden
+	}
+	if !slices.ContainsFunc(project.Collaborators, func(u models.User) bool { return u.ID == collaboratorID }) {
+		return ErrCollaboratorNotFound
+	}
+
+	return s.collaborationService.DisconnectCollaborator(projectID, collaboratorID, "You were disconnected by the project owner")
+}
+
+// SetCollaboratorRole makes a collaborator an editor or a viewer. Only the
+// owner may change roles. The collaborator's WebSockets learn of it at once.
+func (s *ProjectService) SetCollaboratorRole(projectID, collaboratorID uuid.UUID, role string, userID uuid.UUID) error {
+	if role != models.ProjectRoleEditor && role != models.ProjectRoleViewer {
+		return ErrInvalidInput
+	}
+
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProjectNotFound
+		}
+		return err
+	}
+	if project.OwnerID != userID {
+		return ErrForbidden
+	}
+
+	if err := s.projectRepo.SetCollaboratorRole(projectID, collaboratorID, role); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCollaboratorNotFound
+		}
+		return err
+	}
+	s.accessCache.InvalidateMembers(projectID)
+
+	if err := s.collaborationService.NotifyPermissionsUpdated(projectID, collaboratorID, role); err != nil {
+		log.Printf("Error notifying %s of their role in project %s: %v", collaboratorID, projectID, err)
+	}
+	return nil
+}
+
+// GetMemberRole returns the role of a user in a project: owner, editor or
+// viewer. Users who are not members are forbidden.
+func (s *ProjectService) GetMemberRole(projectID, userID uuid.UUID) (string, error) {
+	project, err := s.requireMember(projectID, userID)
+	if err != nil {
+		return "", err
+	}
+	if project.OwnerID == userID {
+		return models.ProjectRoleOwner, nil
+	}
+
+	viewerIDs, err := s.projectRepo.GetViewerIDs(projectID)
+	if err != nil {
+		return "", err
+	}
+	if slices.Contains(viewerIDs, userID) {
+		return models.ProjectRoleViewer, nil
+	}
+	return models.ProjectRoleEditor, nil
+}
+
+// AddProjectTag labels a project. Tags are lowercased and shared by everyone on the project.
+func (s *ProjectService) AddProjectTag(projectID uuid.UUID, tag string, userID uuid.UUID) error {
+	tag, err := normalizeProjectTag(tag)
+	if err != nil {
+		return err
+	}
+
+	project, err := s.requireMember(projectID, userID)
+	if err != nil {
+		return err
+	}
+
+	hasTag := slices.ContainsFunc(project.Tags, func(t models.ProjectTag) bool { return t.Name == tag })
+	if !hasTag && len(project.Tags) >= maxProjectTags {
+		return ErrTooManyTags
+	}
+
+	if err := s.projectRepo.AddTag(projectID, tag); err != nil {
+		return err
+	}
+	s.projectCache.Invalidate(projectID)
+	return nil
+}
+
+func (s *ProjectService) RemoveProjectTag(projectID uuid.UUID, tag string, userID uuid.UUID) error {
+	tag, err := normalizeProjectTag(tag)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.requireMember(projectID, userID); err != nil {
+		return err
+	}
+
+	if err := s.projectRepo.RemoveTag(projectID, tag); err != nil {
+		return err
+	}
+	s.projectCache.Invalidate(projectID)
+	return nil
+}
+
+// StarProject adds a project to the user's favorites
+func (s *ProjectService) StarProject(projectID, userID uuid.UUID) error {
+	if _, err := s.requireMember(projectID, userID); err != nil {
+		return err
+	}
+	return s.projectRepo.Star(projectID, userID)
+}
+
+// UnstarProject removes a project from the user's favorites. It needs no access,
+// so users can clear stars of projects they were removed from.
+func (s *ProjectService) UnstarProject(projectID, userID uuid.UUID) error {
+	return s.projectRepo.Unstar(projectID, userID)
+}
+
+func (s *ProjectService) GetStarredProjectIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	return s.projectRepo.GetStarredProjectIDs(userID)
+}
+
+// RecordProjectView remembers that the user opened the project
+func (s *ProjectService) RecordProjectView(projectID, userID uuid.UUID) error {
+	return s.projectRepo.RecordView(projectID, userID, time.Now())
+}
+
+// GetRecentProjects returns the projects the user opened last, most recent first.
+// A limit of zero uses the default.
+func (s *ProjectService) GetRecentProjects(userID uuid.UUID, limit int) ([]*models.ProjectView, error) {
+	if limit == 0 {
+		limit = defaultRecentProjects
+	}
+	if limit < 0 || limit > maxRecentProjects {
+		return nil, ErrInvalidInput
+	}
+	return s.projectRepo.GetRecentlyViewed(userID, limit)
+}
+
+// requireMember loads a project the user owns or collaborates on
+func (s *ProjectService) requireMember(projectID, userID uuid.UUID) (*models.Project, error) {
+	project, err := s.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if project.OwnerID != userID && !slices.ContainsFunc(project.Collaborators, func(u models.User) bool { return u.ID == userID }) {
+		return nil, ErrForbidden
+	}
+	return project, nil
+}
+
+func normalizeProjectTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if !projectTagPattern.MatchString(tag) {
+		return "", ErrInvalidInput
+	}
+	return tag, nil
 }
@@ -1,9 +1,15 @@
 package services
 
 import (
+	"fmt"
 	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/cache"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/canvas"
 	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
 	"github.com/google/uuid"
@@ -50,7 +56,8 @@ func (suite *ProjectServiceTestSuite) SetupTest() {
 	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
 	suite.mockUserRepo = new(mockRepo.MockUserRepository)
 	suite.mockCollaborationService = new(mockCollaborationService)
-	suite.service = NewProjectService(suite.mockProjectRepo, suite.mockUserRepo, suite.mockCollaborationService)
+	suite.service = NewProjectService(suite.mockProjectRepo, suite.mockUserRepo, suite.mockCollaborationService, nil, nil,
+		newTestUnitOfWork(repository.Repositories{Projects: suite.mockProjectRepo}), 0, Quotas{})
 }
 
 func TestProjectServiceSuite(t *testing.T) {
@@ -69,6 +76,7 @@ func (suite *ProjectServiceTestSuite) TestCreateProject_Success() {
 
 	// Mock that owner exists
 	suite.mockUserRepo.On("GetByID", ownerID).Return(owner, nil)
+	suite.mockProjectRepo.On("CountByOwnerID", ownerID).Return(0, nil)
 
 	// Mock successful creation
 	suite.mockProjectRepo.On("Create", mock.MatchedBy(func(project *models.Project) bool {
@@ -163,6 +171,7 @@ func (suite *ProjectServiceTestSuite) TestCreateProject_ProjectRepoError() {
 	owner.ID = ownerID
 
 	suite.mockUserRepo.On("GetByID", ownerID).Return(owner, nil)
+	suite.mockProjectRepo.On("CountByOwnerID", ownerID).Return(0, nil)
 	suite.mockProjectRepo.On("Create", mock.AnythingOfType("*models.Project")).Return(uuid.Nil, assert.AnError)
 
 	result, err := suite.service.CreateProject(name, description, ownerID)
@@ -175,6 +184,23 @@ func (suite *ProjectServiceTestSuite) TestCreateProject_ProjectRepoError() {
 	suite.mockProjectRepo.AssertExpectations(suite.T())
 }
 
+func (suite *ProjectServiceTestSuite) TestCreateProject_QuotaExceeded() {
+	ownerID := uuid.New()
+	owner := createTestProjectUser()
+	owner.ID = ownerID
+	suite.service.quotas = Quotas{MaxProjectsPerUser: 3}
+
+	suite.mockUserRepo.On("GetByID", ownerID).Return(owner, nil)
+	suite.mockProjectRepo.On("CountByOwnerID", ownerID).Return(3, nil)
+
+	result, err := suite.service.CreateProject("Test Project", "", ownerID)
+
+	suite.ErrorIs(err, ErrQuotaExceeded)
+	suite.Equal(&QuotaExceededError{Quota: QuotaProjects, Limit: 3}, err)
+	suite.Nil(result)
+	suite.mockProjectRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
 // Test GetProjectByID - Success
 func (suite *ProjectServiceTestSuite) TestGetProjectByID_Success() {
 	projectID := uuid.New()
@@ -208,6 +234,30 @@ func (suite *ProjectServiceTestSuite) TestGetProjectByID_NotFound() {
 	suite.mockProjectRepo.AssertExpectations(suite.T())
 }
 
+// Test GetProjectByID - Served from the project cache until a change invalidates it
+func (suite *ProjectServiceTestSuite) TestGetProjectByID_Cached() {
+	suite.service.projectCache = NewProjectCache(cache.NewMemoryStore(), time.Minute)
+	project := createTestProject(uuid.New())
+	collaboratorID := uuid.New()
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil).Twice()
+	suite.mockUserRepo.On("GetByID", collaboratorID).Return(createTestProjectUser(), nil)
+	suite.mockProjectRepo.On("AddCollaborator", project.ID, collaboratorID).Return(nil)
+
+	first, err := suite.service.GetProjectByID(project.ID)
+	suite.NoError(err)
+	second, err := suite.service.GetProjectByID(project.ID)
+	suite.NoError(err)
+	suite.Equal(first, second)
+	suite.NotSame(first, second)
+
+	// AddCollaborator loads the project itself, then invalidates the cached copy
+	suite.NoError(suite.service.AddCollaborator(project.ID, collaboratorID))
+	suite.Nil(suite.service.projectCache.Get(project.ID))
+
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
 // Test GetProjectsByOwnerID - Success
 func (suite *ProjectServiceTestSuite) TestGetProjectsByOwnerID_Success() {
 	ownerID := uuid.New()
@@ -216,9 +266,9 @@ func (suite *ProjectServiceTestSuite) TestGetProjectsByOwnerID_Success() {
 		createTestProject(ownerID),
 	}
 
-	suite.mockProjectRepo.On("GetByOwnerID", ownerID).Return(expectedProjects, nil)
+	suite.mockProjectRepo.On("GetByOwnerID", ownerID, repository.ProjectIncludes{TablesCount: true}).Return(expectedProjects, nil)
 
-	result, err := suite.service.GetProjectsByOwnerID(ownerID)
+	result, err := suite.service.GetProjectsByOwnerID(ownerID, repository.ProjectIncludes{TablesCount: true})
 
 	suite.NoError(err)
 	suite.NotNil(result)
@@ -296,6 +346,48 @@ func (suite *ProjectServiceTestSuite) TestUpdateProject_InvalidName() {
 	suite.mockProjectRepo.AssertExpectations(suite.T())
 }
 
+// Test UpdateProject - Canvas changes that lose to a concurrent update are not broadcast
+func (suite *ProjectServiceTestSuite) TestUpdateProject_ConcurrentCanvasUpdate() {
+	projectID := uuid.New()
+	readProject := createTestProject(uuid.New())
+	readProject.ID = projectID
+	currentProject := createTestProject(readProject.OwnerID)
+	currentProject.ID = projectID
+	currentProject.CanvasData = `{"zoom":2}`
+	currentProject.Version = 2
+
+	suite.mockProjectRepo.On("GetByID", projectID).Return(readProject, nil).Once()
+	suite.mockProjectRepo.On("Update", mock.AnythingOfType("*models.Project")).Return(repository.ErrVersionConflict)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(currentProject, nil).Once()
+
+	canvasData := `{"zoom":1}`
+	result, err := suite.service.UpdateProject(projectID, &dto.UpdateProjectRequest{CanvasData: &canvasData}, uuid.New())
+
+	suite.ErrorIs(err, ErrVersionConflict)
+	suite.Equal(currentProject, result)
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertNotCalled(suite.T(), "BroadcastCanvasUpdate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test UpdateProject - Invalid canvas data is neither saved nor broadcast
+func (suite *ProjectServiceTestSuite) TestUpdateProject_InvalidCanvasData() {
+	projectID := uuid.New()
+	existingProject := createTestProject(uuid.New())
+	existingProject.ID = projectID
+
+	suite.mockProjectRepo.On("GetByID", projectID).Return(existingProject, nil)
+
+	canvasData := `{"viewport":{"x":0,"y":0,"zoom":0}}`
+	result, err := suite.service.UpdateProject(projectID, &dto.UpdateProjectRequest{CanvasData: &canvasData}, uuid.New())
+
+	var validationErr *canvas.ValidationError
+	suite.ErrorAs(err, &validationErr)
+	suite.Contains(validationErr.Errors, "viewport.zoom")
+	suite.Nil(result)
+	suite.mockProjectRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+	suite.mockCollaborationService.AssertNotCalled(suite.T(), "BroadcastCanvasUpdate", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Test DeleteProject - Success
 func (suite *ProjectServiceTestSuite) TestDeleteProject_Success() {
 	projectID := uuid.New()
@@ -347,6 +439,49 @@ func (suite *ProjectServiceTestSuite) TestAddCollaborator_Success() {
 	suite.mockUserRepo.AssertExpectations(suite.T())
 }
 
+func (suite *ProjectServiceTestSuite) TestAddCollaborator_QuotaExceeded() {
+	projectID := uuid.New()
+	collaboratorID := uuid.New()
+	suite.service.quotas = Quotas{MaxCollaborators: 1}
+
+	existingProject := createTestProject(uuid.New())
+	existingProject.ID = projectID
+	existingProject.Collaborators = []models.User{{ID: uuid.New()}}
+
+	collaborator := createTestProjectUser()
+	collaborator.ID = collaboratorID
+
+	suite.mockProjectRepo.On("GetByID", projectID).Return(existingProject, nil)
+	suite.mockUserRepo.On("GetByID", collaboratorID).Return(collaborator, nil)
+
+	err := suite.service.AddCollaborator(projectID, collaboratorID)
+
+	suite.Equal(&QuotaExceededError{Quota: QuotaCollaborators, Limit: 1}, err)
+	suite.mockProjectRepo.AssertNotCalled(suite.T(), "AddCollaborator", mock.Anything, mock.Anything)
+}
+
+func (suite *ProjectServiceTestSuite) TestAddCollaborator_ServiceAccountsOutsideQuota() {
+	projectID := uuid.New()
+	collaboratorID := uuid.New()
+	suite.service.quotas = Quotas{MaxCollaborators: 1}
+
+	existingProject := createTestProject(uuid.New())
+	existingProject.ID = projectID
+	existingProject.Collaborators = []models.User{{ID: uuid.New(), IsServiceAccount: true}}
+
+	collaborator := createTestProjectUser()
+	collaborator.ID = collaboratorID
+
+	suite.mockProjectRepo.On("GetByID", projectID).Return(existingProject, nil)
+	suite.mockUserRepo.On("GetByID", collaboratorID).Return(collaborator, nil)
+	suite.mockProjectRepo.On("AddCollaborator", projectID, collaboratorID).Return(nil)
+
+	err := suite.service.AddCollaborator(projectID, collaboratorID)
+
+	suite.NoError(err)
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
 // Test RemoveCollaborator - Success
 func (suite *ProjectServiceTestSuite) TestRemoveCollaborator_Success() {
 	projectID := uuid.New()
@@ -361,10 +496,156 @@ func (suite *ProjectServiceTestSuite) TestRemoveCollaborator_Success() {
 	suite.mockProjectRepo.On("GetByID", projectID).Return(existingProject, nil)
 	suite.mockUserRepo.On("GetByID", collaboratorID).Return(collaborator, nil)
 	suite.mockProjectRepo.On("RemoveCollaborator", projectID, collaboratorID).Return(nil)
+	suite.mockCollaborationService.On("DisconnectCollaborator", projectID, collaboratorID, mock.Anything).Return(nil)
 
 	err := suite.service.RemoveCollaborator(projectID, collaboratorID)
 
 	suite.NoError(err)
 	suite.mockProjectRepo.AssertExpectations(suite.T())
 	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertExpectations(suite.T())
+}
+
+// Test SetCollaboratorRole - The owner makes a collaborator a viewer, who is told at once
+func (suite *ProjectServiceTestSuite) TestSetCollaboratorRole_Success() {
+	ownerID := uuid.New()
+	collaboratorID := uuid.New()
+	project := createTestProject(ownerID)
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil)
+	suite.mockProjectRepo.On("SetCollaboratorRole", project.ID, collaboratorID, models.ProjectRoleViewer).Return(nil)
+	suite.mockCollaborationService.On("NotifyPermissionsUpdated", project.ID, collaboratorID, models.ProjectRoleViewer).Return(nil)
+
+	err := suite.service.SetCollaboratorRole(project.ID, collaboratorID, models.ProjectRoleViewer, ownerID)
+
+	suite.NoError(err)
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertExpectations(suite.T())
+}
+
+// Test SetCollaboratorRole - Unknown roles, other users and non-collaborators are rejected
+func (suite *ProjectServiceTestSuite) TestSetCollaboratorRole_Rejected() {
+	ownerID := uuid.New()
+	collaboratorID := uuid.New()
+	project := createTestProject(ownerID)
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil)
+	suite.mockProjectRepo.On("SetCollaboratorRole", project.ID, collaboratorID, models.ProjectRoleEditor).Return(gorm.ErrRecordNotFound)
+
+	suite.ErrorIs(suite.service.SetCollaboratorRole(project.ID, collaboratorID, models.ProjectRoleOwner, ownerID), ErrInvalidInput)
+	suite.ErrorIs(suite.service.SetCollaboratorRole(project.ID, collaboratorID, models.ProjectRoleViewer, collaboratorID), ErrForbidden)
+	suite.ErrorIs(suite.service.SetCollaboratorRole(project.ID, collaboratorID, models.ProjectRoleEditor, ownerID), ErrCollaboratorNotFound)
+	suite.mockCollaborationService.AssertNotCalled(suite.T(), "NotifyPermissionsUpdated", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test GetMemberRole - Owners, editors and viewers
+func (suite *ProjectServiceTestSuite) TestGetMemberRole() {
+	ownerID := uuid.New()
+	editor := createTestProjectUser()
+	viewer := createTestProjectUser()
+	project := createTestProject(ownerID)
+	project.Collaborators = []models.User{*editor, *viewer}
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil)
+	suite.mockProjectRepo.On("GetViewerIDs", project.ID).Return([]uuid.UUID{viewer.ID}, nil)
+
+	for userID, expected := range map[uuid.UUID]string{ownerID: models.ProjectRoleOwner, editor.ID: models.ProjectRoleEditor, viewer.ID: models.ProjectRoleViewer} {
+		role, err := suite.service.GetMemberRole(project.ID, userID)
+		suite.NoError(err)
+		suite.Equal(expected, role)
+	}
+
+	_, err := suite.service.GetMemberRole(project.ID, uuid.New())
+	suite.ErrorIs(err, ErrForbidden)
+}
+
+// Test KickCollaborator - The owner disconnects a collaborator
+func (suite *ProjectServiceTestSuite) TestKickCollaborator_Success() {
+	ownerID := uuid.New()
+	collaborator := createTestProjectUser()
+	project := createTestProject(ownerID)
+	project.Collaborators = []models.User{*collaborator}
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil)
+	suite.mockCollaborationService.On("DisconnectCollaborator", project.ID, collaborator.ID, mock.Anything).Return(nil)
+
+	err := suite.service.KickCollaborator(project.ID, collaborator.ID, ownerID)
+
+	suite.NoError(err)
+	suite.mockCollaborationService.AssertExpectations(suite.T())
+}
+
+// Test KickCollaborator - Only the owner kicks, and only collaborators
+func (suite *ProjectServiceTestSuite) TestKickCollaborator_Rejected() {
+	ownerID := uuid.New()
+	collaborator := createTestProjectUser()
+	project := createTestProject(ownerID)
+	project.Collaborators = []models.User{*collaborator}
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil)
+
+	suite.ErrorIs(suite.service.KickCollaborator(project.ID, ownerID, collaborator.ID), ErrForbidden)
+	suite.ErrorIs(suite.service.KickCollaborator(project.ID, uuid.New(), ownerID), ErrCollaboratorNotFound)
+	suite.mockCollaborationService.AssertNotCalled(suite.T(), "DisconnectCollaborator", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test AddProjectTag - Tags are normalized before they are stored
+func (suite *ProjectServiceTestSuite) TestAddProjectTag_Success() {
+	ownerID := uuid.New()
+	project := createTestProject(ownerID)
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil)
+	suite.mockProjectRepo.On("AddTag", project.ID, "q3-launch").Return(nil)
+
+	err := suite.service.AddProjectTag(project.ID, "  Q3-Launch ", ownerID)
+
+	assert.NoError(suite.T(), err)
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+// Test AddProjectTag - Invalid tags, too many tags and non-members are rejected
+func (suite *ProjectServiceTestSuite) TestAddProjectTag_Rejected() {
+	ownerID := uuid.New()
+	project := createTestProject(ownerID)
+	for i := range maxProjectTags {
+		project.Tags = append(project.Tags, models.ProjectTag{ProjectID: project.ID, Name: fmt.Sprintf("tag-%d", i)})
+	}
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil)
+
+	assert.ErrorIs(suite.T(), suite.service.AddProjectTag(project.ID, "two words", ownerID), ErrInvalidInput)
+	assert.ErrorIs(suite.T(), suite.service.AddProjectTag(project.ID, "one-more", ownerID), ErrTooManyTags)
+	assert.ErrorIs(suite.T(), suite.service.AddProjectTag(project.ID, "billing", uuid.New()), ErrForbidden)
+	suite.mockProjectRepo.AssertNotCalled(suite.T(), "AddTag", mock.Anything, mock.Anything)
+}
+
+// Test StarProject - Collaborators can star a project
+func (suite *ProjectServiceTestSuite) TestStarProject_Collaborator() {
+	collaborator := createTestProjectUser()
+	project := createTestProject(uuid.New())
+	project.Collaborators = []models.User{*collaborator}
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil)
+	suite.mockProjectRepo.On("Star", project.ID, collaborator.ID).Return(nil)
+
+	err := suite.service.StarProject(project.ID, collaborator.ID)
+
+	assert.NoError(suite.T(), err)
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+}
+
+// Test GetRecentProjects - Default and maximum limits
+func (suite *ProjectServiceTestSuite) TestGetRecentProjects_Limit() {
+	userID := uuid.New()
+	views := []*models.ProjectView{{UserID: userID, ProjectID: uuid.New()}}
+
+	suite.mockProjectRepo.On("GetRecentlyViewed", userID, defaultRecentProjects).Return(views, nil)
+
+	result, err := suite.service.GetRecentProjects(userID, 0)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), views, result)
+
+	_, err = suite.service.GetRecentProjects(userID, maxRecentProjects+1)
+	assert.ErrorIs(suite.T(), err, ErrInvalidInput)
+	suite.mockProjectRepo.AssertNumberOfCalls(suite.T(), "GetRecentlyViewed", 1)
 }
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RegionMove is a region after a move together with where its tables ended up
+type RegionMove struct {
+	Region *models.Region
+	Tables []repository.TablePosition
+}
+
+type RegionService struct {
+	regionRepo           repository.RegionRepositoryInterface
+	projectRepo          repository.ProjectRepositoryInterface
+	authService          AuthorizationServiceInterface
+	collaborationService CollaborationSessionServiceInterface
+	unitOfWork           *UnitOfWork
+}
+
+func NewRegionService(regionRepo repository.RegionRepositoryInterface, projectRepo repository.ProjectRepositoryInterface, authService AuthorizationServiceInterface, collaborationService CollaborationSessionServiceInterface, unitOfWork *UnitOfWork) *RegionService {
+	return &RegionService{
+		regionRepo:           regionRepo,
+		projectRepo:          projectRepo,
+		authService:          authService,
+		collaborationService: collaborationService,
+		unitOfWork:           unitOfWork,
+	}
+}
+
+func (s *RegionService) CreateRegion(projectID uuid.UUID, req *dto.CreateRegionRequest, userID uuid.UUID) (*models.Region, error) {
+	name := strings.TrimSpace(req.Name)
+	if len(name) < 1 || len(name) > 255 {
+		return nil, ErrInvalidInput
+	}
+
+	// Verify project exists
+	_, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	region := &models.Region{
+		ProjectID: projectID,
+		Name:      name,
+		Color:     req.Color,
+		PosX:      req.PosX,
+		PosY:      req.PosY,
+		Width:     req.Width,
+		Height:    req.Height,
+	}
+	tableIDs := uniqueIDs(req.TableIDs)
+
+	// Persist with the notification, so collaborators hear of the region once it is saved
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := checkProjectTables(tx, projectID, tableIDs); err != nil {
+			return err
+		}
+		id, err := tx.Regions.Create(region)
+		if err != nil {
+			return err
+		}
+		region.ID = id
+		if err := tx.Regions.SetTables(id, tableIDs); err != nil {
+			return err
+		}
+		region.TableIDs = tableIDs
+
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyRegionCreated(projectID, region, userID); err != nil {
+				return err
+			}
+		}
+		region.Sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return region, nil
+}
+
+func (s *RegionService) GetRegionsByProjectID(projectID uuid.UUID) ([]*models.Region, error) {
+	return s.regionRepo.GetByProjectID(projectID)
+}
+
+// GetRegionByID returns a region of the project, ErrRegionNotFound for a region of another
+func (s *RegionService) GetRegionByID(projectID, id uuid.UUID) (*models.Region, error) {
+	region, err := s.regionRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRegionNotFound
+		}
+		return nil, err
+	}
+	if region.ProjectID != projectID {
+		return nil, ErrRegionNotFound
+	}
+	return region, nil
+}
+
+func (s *RegionService) UpdateRegion(projectID, id uuid.UUID, req *dto.UpdateRegionRequest, userID uuid.UUID) (*models.Region, error) {
+	region, err := s.GetRegionByID(projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return nil, err
+	}
+	if req.Version != nil && *req.Version != region.Version {
+		return region, ErrVersionConflict
+	}
+
+	// Only update fields that were provided
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if len(name) < 1 || len(name) > 255 {
+			return nil, ErrInvalidInput
+		}
+		region.Name = name
+	}
+	if req.Color != nil {
+		region.Color = *req.Color
+	}
+	if req.PosX != nil {
+		region.PosX = *req.PosX
+	}
+	if req.PosY != nil {
+		region.PosY = *req.PosY
+	}
+	if req.Width != nil {
+		region.Width = *req.Width
+	}
+	if req.Height != nil {
+		region.Height = *req.Height
+	}
+
+	// Persist with the notification, so a change that loses a concurrent update is never broadcast
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := tx.Regions.Update(region); err != nil {
+			return err
+		}
+		if req.TableIDs != nil {
+			tableIDs := uniqueIDs(*req.TableIDs)
+			if err := checkProjectTables(tx, projectID, tableIDs); err != nil {
+				return err
+			}
+			if err := tx.Regions.SetTables(id, tableIDs); err != nil {
+				return err
+			}
+			region.TableIDs = tableIDs
+		}
+
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyRegionUpdated(projectID, region, userID); err != nil {
+				return err
+			}
+		}
+		region.Sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return s.currentRegion(id)
+		}
+		return nil, err
+	}
+
+	return region, nil
+}
+
+// MoveRegion moves a region and every table in it by the same offset in one
+// transaction, so the tables never end up split between old and new positions
+func (s *RegionService) MoveRegion(projectID, id uuid.UUID, req *dto.MoveRegionRequest, userID uuid.UUID) (*RegionMove, error) {
+	region, err := s.GetRegionByID(projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return nil, err
+	}
+	if req.Version != nil && *req.Version != region.Version {
+		return &RegionMove{Region: region}, ErrVersionConflict
+	}
+
+	region.PosX += req.DX
+	region.PosY += req.DY
+	move := &RegionMove{Region: region}
+
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := tx.Regions.Update(region); err != nil {
+			return err
+		}
+		tables, err := tx.Regions.MoveTables(id, req.DX, req.DY)
+		if err != nil {
+			return err
+		}
+		move.Tables = tables
+
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyRegionMoved(projectID, region, tables, userID); err != nil {
+				return err
+			}
+		}
+		region.Sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			current, err := s.currentRegion(id)
+			return &RegionMove{Region: current}, err
+		}
+		return nil, err
+	}
+
+	return move, nil
+}
+
+func (s *RegionService) DeleteRegion(projectID, id uuid.UUID, userID uuid.UUID) (int64, error) {
+	region, err := s.GetRegionByID(projectID, id)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return 0, err
+	}
+
+	// Delete with the notification, so collaborators never drop a region that is still there
+	var sequence int64
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := tx.Regions.Delete(id); err != nil {
+			return err
+		}
+
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyRegionDeleted(projectID, id, region.Name, userID); err != nil {
+				return err
+			}
+		}
+		sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sequence, nil
+}
+
+func (s *RegionService) checkCanModify(projectID, userID uuid.UUID) error {
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !canModify {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// currentRegion returns the region as another writer left it, with ErrVersionConflict
+func (s *RegionService) currentRegion(id uuid.UUID) (*models.Region, error) {
+	region, err := s.regionRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return region, ErrVersionConflict
+}
+
+// checkProjectTables fails with ErrTableNotFound unless every table of ids is in the project
+func checkProjectTables(tx *Tx, projectID uuid.UUID, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	tables, err := tx.Tables.GetByProjectID(projectID)
+	if err != nil {
+		return err
+	}
+	inProject := make(map[uuid.UUID]bool, len(tables))
+	for _, table := range tables {
+		inProject[table.ID] = true
+	}
+	for _, id := range ids {
+		if !inProject[id] {
+			return ErrTableNotFound
+		}
+	}
+	return nil
+}
+
+// uniqueIDs returns ids without repeats, in their first order
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type RegionServiceTestSuite struct {
+	suite.Suite
+	mockRegionRepo           *mockRepo.MockRegionRepository
+	mockProjectRepo          *mockRepo.MockProjectRepository
+	mockTableRepo            *mockRepo.MockTableRepository
+	mockAuthService          *mockTableAuthService
+	mockCollaborationService *mockCollaborationService
+	service                  *RegionService
+}
+
+func (suite *RegionServiceTestSuite) SetupTest() {
+	suite.mockRegionRepo = new(mockRepo.MockRegionRepository)
+	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
+	suite.mockTableRepo = new(mockRepo.MockTableRepository)
+	suite.mockAuthService = new(mockTableAuthService)
+	suite.mockCollaborationService = new(mockCollaborationService)
+	suite.service = NewRegionService(suite.mockRegionRepo, suite.mockProjectRepo, suite.mockAuthService, suite.mockCollaborationService,
+		newTestUnitOfWork(repository.Repositories{Projects: suite.mockProjectRepo, Tables: suite.mockTableRepo, Regions: suite.mockRegionRepo}))
+}
+
+func TestRegionServiceSuite(t *testing.T) {
+	suite.Run(t, new(RegionServiceTestSuite))
+}
+
+func createTestRegion(projectID uuid.UUID) *models.Region {
+	return &models.Region{
+		ID:        uuid.New(),
+		ProjectID: projectID,
+		Name:      "Billing domain",
+		Color:     "#3b82f6",
+		PosX:      10,
+		PosY:      20,
+		Width:     400,
+		Height:    300,
+		Version:   3,
+		TableIDs:  []uuid.UUID{},
+	}
+}
+
+func (suite *RegionServiceTestSuite) TestCreateRegion_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	regionID := uuid.New()
+	table := createTestTable(projectID)
+	req := &dto.CreateRegionRequest{
+		Name:     "  Billing domain ",
+		Color:    "#3b82f6",
+		Width:    400,
+		Height:   300,
+		TableIDs: []uuid.UUID{table.ID, table.ID},
+	}
+
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{table}, nil)
+	suite.mockRegionRepo.On("Create", mock.MatchedBy(func(region *models.Region) bool {
+		return region.Name == "Billing domain" && region.ProjectID == projectID && region.Width == 400
+	})).Return(regionID, nil)
+	suite.mockRegionRepo.On("SetTables", regionID, []uuid.UUID{table.ID}).Return(nil)
+	suite.mockCollaborationService.On("NotifyRegionCreated", projectID, mock.AnythingOfType("*models.Region"), userID).Return(nil)
+
+	region, err := suite.service.CreateRegion(projectID, req, userID)
+
+	suite.NoError(err)
+	suite.Equal(regionID, region.ID)
+	suite.Equal([]uuid.UUID{table.ID}, region.TableIDs)
+	suite.mockRegionRepo.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertExpectations(suite.T())
+}
+
+func (suite *RegionServiceTestSuite) TestCreateRegion_TableOfAnotherProject() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	req := &dto.CreateRegionRequest{Name: "Billing", Color: "#fff", Width: 1, Height: 1, TableIDs: []uuid.UUID{uuid.New()}}
+
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{createTestTable(projectID)}, nil)
+
+	region, err := suite.service.CreateRegion(projectID, req, userID)
+
+	suite.ErrorIs(err, ErrTableNotFound)
+	suite.Nil(region)
+	suite.mockRegionRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *RegionServiceTestSuite) TestCreateRegion_Forbidden() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	req := &dto.CreateRegionRequest{Name: "Billing", Color: "#fff", Width: 1, Height: 1}
+
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(false, nil)
+
+	region, err := suite.service.CreateRegion(projectID, req, userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(region)
+}
+
+func (suite *RegionServiceTestSuite) TestGetRegionByID_OtherProject() {
+	region := createTestRegion(uuid.New())
+	suite.mockRegionRepo.On("GetByID", region.ID).Return(region, nil)
+
+	result, err := suite.service.GetRegionByID(uuid.New(), region.ID)
+
+	suite.ErrorIs(err, ErrRegionNotFound)
+	suite.Nil(result)
+}
+
+func (suite *RegionServiceTestSuite) TestGetRegionByID_NotFound() {
+	regionID := uuid.New()
+	suite.mockRegionRepo.On("GetByID", regionID).Return(nil, gorm.ErrRecordNotFound)
+
+	result, err := suite.service.GetRegionByID(uuid.New(), regionID)
+
+	suite.ErrorIs(err, ErrRegionNotFound)
+	suite.Nil(result)
+}
+
+func (suite *RegionServiceTestSuite) TestUpdateRegion_ReplacesTables() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	region := createTestRegion(projectID)
+	table := createTestTable(projectID)
+	name := "Payments"
+	tableIDs := []uuid.UUID{table.ID}
+
+	suite.mockRegionRepo.On("GetByID", region.ID).Return(region, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockRegionRepo.On("Update", region).Return(nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{table}, nil)
+	suite.mockRegionRepo.On("SetTables", region.ID, tableIDs).Return(nil)
+	suite.mockCollaborationService.On("NotifyRegionUpdated", projectID, region, userID).Return(nil)
+
+	result, err := suite.service.UpdateRegion(projectID, region.ID, &dto.UpdateRegionRequest{Name: &name, TableIDs: &tableIDs}, userID)
+
+	suite.NoError(err)
+	suite.Equal("Payments", result.Name)
+	suite.Equal(tableIDs, result.TableIDs)
+	suite.mockRegionRepo.AssertExpectations(suite.T())
+}
+
+func (suite *RegionServiceTestSuite) TestUpdateRegion_StaleVersion() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	region := createTestRegion(projectID)
+	version := region.Version - 1
+
+	suite.mockRegionRepo.On("GetByID", region.ID).Return(region, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+
+	result, err := suite.service.UpdateRegion(projectID, region.ID, &dto.UpdateRegionRequest{Version: &version}, userID)
+
+	suite.ErrorIs(err, ErrVersionConflict)
+	suite.Equal(region, result)
+	suite.mockRegionRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+func (suite *RegionServiceTestSuite) TestMoveRegion_MovesTables() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	region := createTestRegion(projectID)
+	positions := []repository.TablePosition{{ID: uuid.New(), PosX: 125, PosY: 190, Version: 4}}
+
+	suite.mockRegionRepo.On("GetByID", region.ID).Return(region, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockRegionRepo.On("Update", mock.MatchedBy(func(r *models.Region) bool {
+		return r.PosX == 35 && r.PosY == 10
+	})).Return(nil)
+	suite.mockRegionRepo.On("MoveTables", region.ID, 25.0, -10.0).Return(positions, nil)
+	suite.mockCollaborationService.On("NotifyRegionMoved", projectID, region, positions, userID).Return(nil)
+
+	move, err := suite.service.MoveRegion(projectID, region.ID, &dto.MoveRegionRequest{DX: 25, DY: -10}, userID)
+
+	suite.NoError(err)
+	suite.Equal(35.0, move.Region.PosX)
+	suite.Equal(positions, move.Tables)
+	suite.mockRegionRepo.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertExpectations(suite.T())
+}
+
+func (suite *RegionServiceTestSuite) TestMoveRegion_ConcurrentChange() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	region := createTestRegion(projectID)
+	current := createTestRegion(projectID)
+	current.ID = region.ID
+	current.Version = region.Version + 1
+
+	suite.mockRegionRepo.On("GetByID", region.ID).Return(region, nil).Once()
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockRegionRepo.On("Update", region).Return(repository.ErrVersionConflict)
+	suite.mockRegionRepo.On("GetByID", region.ID).Return(current, nil).Once()
+
+	move, err := suite.service.MoveRegion(projectID, region.ID, &dto.MoveRegionRequest{DX: 1, DY: 1}, userID)
+
+	suite.ErrorIs(err, ErrVersionConflict)
+	suite.Equal(current, move.Region)
+	suite.mockRegionRepo.AssertNotCalled(suite.T(), "MoveTables", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockCollaborationService.AssertNotCalled(suite.T(), "NotifyRegionMoved", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *RegionServiceTestSuite) TestDeleteRegion_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	region := createTestRegion(projectID)
+
+	suite.mockRegionRepo.On("GetByID", region.ID).Return(region, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockRegionRepo.On("Delete", region.ID).Return(nil)
+	suite.mockCollaborationService.On("NotifyRegionDeleted", projectID, region.ID, region.Name, userID).Return(nil)
+
+	_, err := suite.service.DeleteRegion(projectID, region.ID, userID)
+
+	suite.NoError(err)
+	suite.mockRegionRepo.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertExpectations(suite.T())
+}
@@ -17,6 +17,7 @@ type RelationshipService struct {
 	fieldRepo            repository.FieldRepositoryInterface
 	authService          AuthorizationServiceInterface
 	collaborationService CollaborationSessionServiceInterface
+	unitOfWork           *UnitOfWork
 }
 
 func NewRelationshipService(
@@ -26,6 +27,7 @@ func NewRelationshipService(
 	fieldRepo repository.FieldRepositoryInterface,
 	authService AuthorizationServiceInterface,
 	collaborationService CollaborationSessionServiceInterface,
+	unitOfWork *UnitOfWork,
 ) *RelationshipService {
 	return &RelationshipService{
 		relationshipRepo:     relationshipRepo,
@@ -34,6 +36,7 @@ func NewRelationshipService(
 		fieldRepo:            fieldRepo,
 		authService:          authService,
 		collaborationService: collaborationService,
+		unitOfWork:           unitOfWork,
 	}
 }
 
@@ -47,47 +50,24 @@ func (s *RelationshipService) CreateRelationship(projectID uuid.UUID, req *dto.C
 		return nil, err
 	}
 
-	// Verify source table exists
-	_, err = s.tableRepo.GetByID(req.SourceTableID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrTableNotFound
-		}
+	// Verify source and target tables and fields exist
+	if err := s.verifyTablesExist(req.SourceTableID, req.TargetTableID); err != nil {
 		return nil, err
 	}
-
-	// Verify target table exists
-	_, err = s.tableRepo.GetByID(req.TargetTableID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrTableNotFound
-		}
+	if err := s.verifyFieldsExist(req.SourceFieldID, req.TargetFieldID); err != nil {
 		return nil, err
 	}
 
-	// Verify source field exists
-	_, err = s.fieldRepo.GetByID(req.SourceFieldID)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrFieldNotFound
-		}
-		return nil, err
+	relationType := req.RelationType
+	if relationType == "" {
+		relationType = "one_to_many"
 	}
 
-	// Verify target field exists
-	_, err = s.fieldRepo.GetByID(req.TargetFieldID)
+	metadata, err := newMetadata(req.Metadata)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrFieldNotFound
-		}
 		return nil, err
 	}
 
-	relationType := req.RelationType
-	if relationType == "" {
-		relationType = "one_to_many"
-	}
-
 	relationship := &models.Relationship{
 		ProjectID:     projectID,
 		SourceTableID: req.SourceTableID,
@@ -95,27 +75,36 @@ func (s *RelationshipService) CreateRelationship(projectID uuid.UUID, req *dto.C
 		TargetTableID: req.TargetTableID,
 		TargetFieldID: req.TargetFieldID,
 		RelationType:  relationType,
+		SourceAnchor:  anchorOrAuto(req.SourceAnchor),
+		TargetAnchor:  anchorOrAuto(req.TargetAnchor),
+		Waypoints:     toWaypoints(req.Waypoints),
+		LabelPosition: models.DefaultLabelPosition,
+		Metadata:      metadata,
+	}
+	if req.LabelPosition != nil {
+		relationship.LabelPosition = *req.LabelPosition
 	}
 
-	// Generate UUID for the relationship before broadcasting
-	relationship.ID = uuid.New()
-
-	// Broadcast relationship creation to collaborators FIRST
-	if s.collaborationService != nil {
-		if err := s.collaborationService.NotifyRelationshipCreated(projectID, relationship, userID); err != nil {
-			// Log error but don't fail the operation
-			// TODO: Add proper logging
+	// Persist with the notification, so collaborators hear of the relationship once it is saved
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		id, err := tx.Relationships.Create(relationship)
+		if err != nil {
+			return err
 		}
-	}
+		relationship.ID = id
 
-	// Then persist to database
-	id, err := s.relationshipRepo.Create(relationship)
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyRelationshipCreated(projectID, relationship, userID); err != nil {
+				return err
+			}
+		}
+		relationship.Sequence = tx.Sequence
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	relationship.ID = id
-
 	return relationship, nil
 }
 
@@ -146,53 +135,43 @@ func (s *RelationshipService) UpdateRelationship(id uuid.UUID, req *dto.UpdateRe
 		}
 		return nil, err
 	}
+	if req.Version != nil && *req.Version != relationship.Version {
+		return relationship, ErrVersionConflict
+	}
+
+	// Verify any new tables and fields exist
+	var tableIDs, fieldIDs []uuid.UUID
+	for _, id := range []*uuid.UUID{req.SourceTableID, req.TargetTableID} {
+		if id != nil {
+			tableIDs = append(tableIDs, *id)
+		}
+	}
+	for _, id := range []*uuid.UUID{req.SourceFieldID, req.TargetFieldID} {
+		if id != nil {
+			fieldIDs = append(fieldIDs, *id)
+		}
+	}
+	if err := s.verifyTablesExist(tableIDs...); err != nil {
+		return nil, err
+	}
+	if err := s.verifyFieldsExist(fieldIDs...); err != nil {
+		return nil, err
+	}
 
 	// Only update fields that were provided
 	if req.SourceTableID != nil {
-		// Verify source table exists
-		_, err = s.tableRepo.GetByID(*req.SourceTableID)
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, ErrTableNotFound
-			}
-			return nil, err
-		}
 		relationship.SourceTableID = *req.SourceTableID
 	}
 
 	if req.TargetTableID != nil {
-		// Verify target table exists
-		_, err = s.tableRepo.GetByID(*req.TargetTableID)
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, ErrTableNotFound
-			}
-			return nil, err
-		}
 		relationship.TargetTableID = *req.TargetTableID
 	}
 
 	if req.SourceFieldID != nil {
-		// Verify source field exists
-		_, err = s.fieldRepo.GetByID(*req.SourceFieldID)
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, ErrFieldNotFound
-			}
-			return nil, err
-		}
 		relationship.SourceFieldID = *req.SourceFieldID
 	}
 
 	if req.TargetFieldID != nil {
-		// Verify target field exists
-		_, err = s.fieldRepo.GetByID(*req.TargetFieldID)
-		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, ErrFieldNotFound
-			}
-			return nil, err
-		}
 		relationship.TargetFieldID = *req.TargetFieldID
 	}
 
@@ -200,59 +179,148 @@ func (s *RelationshipService) UpdateRelationship(id uuid.UUID, req *dto.UpdateRe
 		relationship.RelationType = *req.RelationType
 	}
 
-	// Broadcast relationship update to collaborators FIRST
-	if s.collaborationService != nil {
-		if err := s.collaborationService.NotifyRelationshipUpdated(relationship.ProjectID, relationship, userID); err != nil {
-			// Log error but don't fail the operation
-			// TODO: Add proper logging
+	if req.SourceAnchor != nil {
+		relationship.SourceAnchor = anchorOrAuto(*req.SourceAnchor)
+	}
+	if req.TargetAnchor != nil {
+		relationship.TargetAnchor = anchorOrAuto(*req.TargetAnchor)
+	}
+	if req.Waypoints != nil {
+		relationship.Waypoints = toWaypoints(*req.Waypoints)
+	}
+	if req.LabelPosition != nil {
+		relationship.LabelPosition = *req.LabelPosition
+	}
+	if req.Metadata != nil {
+		if relationship.Metadata, err = patchMetadata(relationship.Metadata, req.Metadata); err != nil {
+			return nil, err
 		}
 	}
 
-	// Then persist to database
-	if err := s.relationshipRepo.Update(relationship); err != nil {
+	// Persist with the notification, so a change that loses a concurrent update is never broadcast
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := tx.Relationships.Update(relationship); err != nil {
+			return err
+		}
+
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyRelationshipUpdated(relationship.ProjectID, relationship, userID); err != nil {
+				return err
+			}
+		}
+		relationship.Sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return s.currentRelationship(id)
+		}
 		return nil, err
 	}
 
 	return relationship, nil
 }
 
-func (s *RelationshipService) DeleteRelationship(id uuid.UUID, userID uuid.UUID) error {
+// currentRelationship returns the relationship as another writer left it, with ErrVersionConflict
+func (s *RelationshipService) currentRelationship(id uuid.UUID) (*models.Relationship, error) {
+	relationship, err := s.relationshipRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return relationship, ErrVersionConflict
+}
+
+func (s *RelationshipService) DeleteRelationship(id uuid.UUID, userID uuid.UUID) (int64, error) {
 	// Get project ID from relationship
 	projectID, err := s.authService.GetProjectIDFromRelationship(id)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// Check authorization
 	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if !canModify {
-		return ErrForbidden
+		return 0, ErrForbidden
 	}
 
 	// Verify relationship exists
 	_, err = s.relationshipRepo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrRelationshipNotFound
+			return 0, ErrRelationshipNotFound
 		}
-		return err
+		return 0, err
 	}
 
-	// Notify collaborators about relationship deletion FIRST
-	if s.collaborationService != nil {
-		if err := s.collaborationService.NotifyRelationshipDeleted(projectID, id, userID); err != nil {
-			// Log error but don't fail the operation
-			// TODO: Add proper logging
+	// Delete with the notification, so collaborators never drop a relationship that is still there
+	var sequence int64
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := tx.Relationships.Delete(id); err != nil {
+			return err
+		}
+
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyRelationshipDeleted(projectID, id, userID); err != nil {
+				return err
+			}
 		}
+		sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
+	return sequence, nil
+}
+
+// anchorOrAuto returns the side an edge is anchored to, auto when none is given
+func anchorOrAuto(anchor string) string {
+	if anchor == "" {
+		return models.AnchorAuto
+	}
+	return anchor
+}
 
-	// Then delete from database
-	if err := s.relationshipRepo.Delete(id); err != nil {
+// toWaypoints converts requested points to the stored form
+func toWaypoints(points []dto.Point) models.Waypoints {
+	waypoints := make(models.Waypoints, len(points))
+	for i, point := range points {
+		waypoints[i] = models.Point{X: point.X, Y: point.Y}
+	}
+	return waypoints
+}
+
+// verifyTablesExist returns ErrTableNotFound unless every given table exists
+func (s *RelationshipService) verifyTablesExist(ids ...uuid.UUID) error {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return nil
+	}
+	tables, err := s.tableRepo.GetByIDs(ids)
+	if err != nil {
 		return err
 	}
+	if len(tables) != len(ids) {
+		return ErrTableNotFound
+	}
+	return nil
+}
 
+// verifyFieldsExist returns ErrFieldNotFound unless every given field exists
+func (s *RelationshipService) verifyFieldsExist(ids ...uuid.UUID) error {
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return nil
+	}
+	fields, err := s.fieldRepo.GetByIDs(ids)
+	if err != nil {
+		return err
+	}
+	if len(fields) != len(ids) {
+		return ErrFieldNotFound
+	}
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
 	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -94,6 +95,12 @@ func (suite *RelationshipServiceTestSuite) SetupTest() {
 		suite.mockFieldRepo,
 		suite.mockAuthService,
 		suite.mockCollaborationService,
+		newTestUnitOfWork(repository.Repositories{
+			Projects:      suite.mockProjectRepo,
+			Tables:        suite.mockTableRepo,
+			Fields:        suite.mockFieldRepo,
+			Relationships: suite.mockRelationshipRepo,
+		}),
 	)
 }
 
@@ -125,10 +132,8 @@ func (suite *RelationshipServiceTestSuite) TestCreateRelationship_Success() {
 	targetField := &models.Field{ID: targetFieldID, Name: "Target Field"}
 
 	suite.mockProjectRepo.On("GetByID", projectID).Return(project, nil)
-	suite.mockTableRepo.On("GetByID", sourceTableID).Return(sourceTable, nil)
-	suite.mockTableRepo.On("GetByID", targetTableID).Return(targetTable, nil)
-	suite.mockFieldRepo.On("GetByID", sourceFieldID).Return(sourceField, nil)
-	suite.mockFieldRepo.On("GetByID", targetFieldID).Return(targetField, nil)
+	suite.mockTableRepo.On("GetByIDs", []uuid.UUID{sourceTableID, targetTableID}).Return([]*models.Table{sourceTable, targetTable}, nil)
+	suite.mockFieldRepo.On("GetByIDs", []uuid.UUID{sourceFieldID, targetFieldID}).Return([]*models.Field{sourceField, targetField}, nil)
 	suite.mockRelationshipRepo.On("Create", mock.MatchedBy(func(rel *models.Relationship) bool {
 		return rel.ProjectID == projectID &&
 			rel.SourceTableID == sourceTableID &&
@@ -182,10 +187,8 @@ func (suite *RelationshipServiceTestSuite) TestCreateRelationship_DefaultRelatio
 	targetField := &models.Field{ID: targetFieldID, Name: "Target Field"}
 
 	suite.mockProjectRepo.On("GetByID", projectID).Return(project, nil)
-	suite.mockTableRepo.On("GetByID", sourceTableID).Return(sourceTable, nil)
-	suite.mockTableRepo.On("GetByID", targetTableID).Return(targetTable, nil)
-	suite.mockFieldRepo.On("GetByID", sourceFieldID).Return(sourceField, nil)
-	suite.mockFieldRepo.On("GetByID", targetFieldID).Return(targetField, nil)
+	suite.mockTableRepo.On("GetByIDs", []uuid.UUID{sourceTableID, targetTableID}).Return([]*models.Table{sourceTable, targetTable}, nil)
+	suite.mockFieldRepo.On("GetByIDs", []uuid.UUID{sourceFieldID, targetFieldID}).Return([]*models.Field{sourceField, targetField}, nil)
 	suite.mockRelationshipRepo.On("Create", mock.MatchedBy(func(rel *models.Relationship) bool {
 		return rel.RelationType == "one_to_many"
 	})).Return(relationshipID, nil)
@@ -205,6 +208,96 @@ func (suite *RelationshipServiceTestSuite) TestCreateRelationship_DefaultRelatio
 }
 
 // Test CreateRelationship - Project Not Found
+func (suite *RelationshipServiceTestSuite) TestCreateRelationship_Route() {
+	projectID := uuid.New()
+	sourceTableID := uuid.New()
+	targetTableID := uuid.New()
+	sourceFieldID := uuid.New()
+	targetFieldID := uuid.New()
+	labelPosition := 0.0
+
+	req := &dto.CreateRelationshipRequest{
+		SourceTableID: sourceTableID,
+		SourceFieldID: sourceFieldID,
+		TargetTableID: targetTableID,
+		TargetFieldID: targetFieldID,
+		SourceAnchor:  "right",
+		Waypoints:     []dto.Point{{X: 100, Y: 50}, {X: 100, Y: 200}},
+		LabelPosition: &labelPosition,
+	}
+
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByIDs", []uuid.UUID{sourceTableID, targetTableID}).Return([]*models.Table{{ID: sourceTableID}, {ID: targetTableID}}, nil)
+	suite.mockFieldRepo.On("GetByIDs", []uuid.UUID{sourceFieldID, targetFieldID}).Return([]*models.Field{{ID: sourceFieldID}, {ID: targetFieldID}}, nil)
+	suite.mockRelationshipRepo.On("Create", mock.AnythingOfType("*models.Relationship")).Return(uuid.New(), nil)
+	suite.mockCollaborationService.On("NotifyRelationshipCreated", projectID, mock.AnythingOfType("*models.Relationship"), mock.AnythingOfType("uuid.UUID")).Return(nil)
+
+	result, err := suite.service.CreateRelationship(projectID, req, uuid.New())
+
+	suite.NoError(err)
+	suite.Equal("right", result.SourceAnchor)
+	suite.Equal(models.AnchorAuto, result.TargetAnchor)
+	suite.Equal(models.Waypoints{{X: 100, Y: 50}, {X: 100, Y: 200}}, result.Waypoints)
+	suite.Equal(0.0, result.LabelPosition)
+}
+
+func (suite *RelationshipServiceTestSuite) TestCreateRelationship_DefaultRoute() {
+	projectID := uuid.New()
+	sourceTableID := uuid.New()
+	targetTableID := uuid.New()
+	sourceFieldID := uuid.New()
+	targetFieldID := uuid.New()
+
+	req := &dto.CreateRelationshipRequest{
+		SourceTableID: sourceTableID,
+		SourceFieldID: sourceFieldID,
+		TargetTableID: targetTableID,
+		TargetFieldID: targetFieldID,
+	}
+
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByIDs", []uuid.UUID{sourceTableID, targetTableID}).Return([]*models.Table{{ID: sourceTableID}, {ID: targetTableID}}, nil)
+	suite.mockFieldRepo.On("GetByIDs", []uuid.UUID{sourceFieldID, targetFieldID}).Return([]*models.Field{{ID: sourceFieldID}, {ID: targetFieldID}}, nil)
+	suite.mockRelationshipRepo.On("Create", mock.AnythingOfType("*models.Relationship")).Return(uuid.New(), nil)
+	suite.mockCollaborationService.On("NotifyRelationshipCreated", projectID, mock.AnythingOfType("*models.Relationship"), mock.AnythingOfType("uuid.UUID")).Return(nil)
+
+	result, err := suite.service.CreateRelationship(projectID, req, uuid.New())
+
+	suite.NoError(err)
+	suite.Equal(models.AnchorAuto, result.SourceAnchor)
+	suite.Equal(models.AnchorAuto, result.TargetAnchor)
+	suite.Empty(result.Waypoints)
+	suite.Equal(models.DefaultLabelPosition, result.LabelPosition)
+}
+
+// Test CreateRelationship - Self Relation looks its table up once
+func (suite *RelationshipServiceTestSuite) TestCreateRelationship_SelfRelation() {
+	projectID := uuid.New()
+	tableID := uuid.New()
+	sourceFieldID := uuid.New()
+	targetFieldID := uuid.New()
+
+	req := &dto.CreateRelationshipRequest{
+		SourceTableID: tableID,
+		SourceFieldID: sourceFieldID,
+		TargetTableID: tableID,
+		TargetFieldID: targetFieldID,
+	}
+
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByIDs", []uuid.UUID{tableID}).Return([]*models.Table{{ID: tableID}}, nil)
+	suite.mockFieldRepo.On("GetByIDs", []uuid.UUID{sourceFieldID, targetFieldID}).Return([]*models.Field{{ID: sourceFieldID}, {ID: targetFieldID}}, nil)
+	suite.mockRelationshipRepo.On("Create", mock.AnythingOfType("*models.Relationship")).Return(uuid.New(), nil)
+	suite.mockCollaborationService.On("NotifyRelationshipCreated", projectID, mock.AnythingOfType("*models.Relationship"), mock.AnythingOfType("uuid.UUID")).Return(nil)
+
+	result, err := suite.service.CreateRelationship(projectID, req, uuid.New())
+
+	suite.NoError(err)
+	suite.Equal(tableID, result.SourceTableID)
+	suite.Equal(tableID, result.TargetTableID)
+	suite.mockTableRepo.AssertExpectations(suite.T())
+}
+
 func (suite *RelationshipServiceTestSuite) TestCreateRelationship_ProjectNotFound() {
 	projectID := uuid.New()
 	req := &dto.CreateRelationshipRequest{
@@ -241,7 +334,7 @@ func (suite *RelationshipServiceTestSuite) TestCreateRelationship_SourceTableNot
 	project := &models.Project{ID: projectID, Name: "Test Project"}
 
 	suite.mockProjectRepo.On("GetByID", projectID).Return(project, nil)
-	suite.mockTableRepo.On("GetByID", sourceTableID).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockTableRepo.On("GetByIDs", []uuid.UUID{sourceTableID, req.TargetTableID}).Return([]*models.Table{}, nil)
 
 	result, err := suite.service.CreateRelationship(projectID, req, uuid.New())
 
@@ -272,9 +365,8 @@ func (suite *RelationshipServiceTestSuite) TestCreateRelationship_SourceFieldNot
 	targetTable := &models.Table{ID: targetTableID, Name: "Target Table"}
 
 	suite.mockProjectRepo.On("GetByID", projectID).Return(project, nil)
-	suite.mockTableRepo.On("GetByID", sourceTableID).Return(sourceTable, nil)
-	suite.mockTableRepo.On("GetByID", targetTableID).Return(targetTable, nil)
-	suite.mockFieldRepo.On("GetByID", sourceFieldID).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockTableRepo.On("GetByIDs", []uuid.UUID{sourceTableID, targetTableID}).Return([]*models.Table{sourceTable, targetTable}, nil)
+	suite.mockFieldRepo.On("GetByIDs", []uuid.UUID{sourceFieldID, req.TargetFieldID}).Return([]*models.Field{{ID: req.TargetFieldID}}, nil)
 
 	result, err := suite.service.CreateRelationship(projectID, req, uuid.New())
 
@@ -374,7 +466,7 @@ func (suite *RelationshipServiceTestSuite) TestUpdateRelationship_Success() {
 	newTable := &models.Table{ID: newSourceTableID, Name: "New Source Table"}
 
 	suite.mockRelationshipRepo.On("GetByID", relationshipID).Return(existingRelationship, nil)
-	suite.mockTableRepo.On("GetByID", newSourceTableID).Return(newTable, nil)
+	suite.mockTableRepo.On("GetByIDs", []uuid.UUID{newSourceTableID}).Return([]*models.Table{newTable}, nil)
 	suite.mockRelationshipRepo.On("Update", mock.MatchedBy(func(rel *models.Relationship) bool {
 		return rel.ID == relationshipID &&
 			rel.SourceTableID == newSourceTableID &&
@@ -396,6 +488,38 @@ func (suite *RelationshipServiceTestSuite) TestUpdateRelationship_Success() {
 }
 
 // Test UpdateRelationship - Not Found
+func (suite *RelationshipServiceTestSuite) TestUpdateRelationship_Route() {
+	relationshipID := uuid.New()
+	existingRelationship := createTestRelationship(uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New())
+	existingRelationship.ID = relationshipID
+	existingRelationship.TargetAnchor = "left"
+	existingRelationship.Waypoints = models.Waypoints{{X: 10, Y: 10}}
+
+	sourceAnchor := "bottom"
+	noWaypoints := []dto.Point{}
+	labelPosition := 0.25
+	updateRequest := &dto.UpdateRelationshipRequest{
+		SourceAnchor:  &sourceAnchor,
+		Waypoints:     &noWaypoints,
+		LabelPosition: &labelPosition,
+	}
+
+	suite.mockRelationshipRepo.On("GetByID", relationshipID).Return(existingRelationship, nil)
+	suite.mockRelationshipRepo.On("Update", mock.MatchedBy(func(rel *models.Relationship) bool {
+		return rel.SourceAnchor == "bottom" &&
+			rel.TargetAnchor == "left" &&
+			len(rel.Waypoints) == 0 &&
+			rel.LabelPosition == 0.25
+	})).Return(nil)
+	suite.mockCollaborationService.On("NotifyRelationshipUpdated", existingRelationship.ProjectID, mock.AnythingOfType("*models.Relationship"), mock.AnythingOfType("uuid.UUID")).Return(nil)
+
+	_, err := suite.service.UpdateRelationship(relationshipID, updateRequest, uuid.New())
+
+	suite.NoError(err)
+	suite.mockRelationshipRepo.AssertExpectations(suite.T())
+	suite.mockTableRepo.AssertNotCalled(suite.T(), "GetByID", mock.Anything)
+}
+
 func (suite *RelationshipServiceTestSuite) TestUpdateRelationship_NotFound() {
 	relationshipID := uuid.New()
 	updateRequest := &dto.UpdateRelationshipRequest{
@@ -425,7 +549,7 @@ func (suite *RelationshipServiceTestSuite) TestUpdateRelationship_SourceTableNot
 	}
 
 	suite.mockRelationshipRepo.On("GetByID", relationshipID).Return(existingRelationship, nil)
-	suite.mockTableRepo.On("GetByID", newSourceTableID).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockTableRepo.On("GetByIDs", []uuid.UUID{newSourceTableID}).Return([]*models.Table{}, nil)
 
 	result, err := suite.service.UpdateRelationship(relationshipID, updateRequest, uuid.New())
 
@@ -451,7 +575,7 @@ func (suite *RelationshipServiceTestSuite) TestDeleteRelationship_Success() {
 	suite.mockRelationshipRepo.On("Delete", relationshipID).Return(nil)
 	suite.mockCollaborationService.On("NotifyRelationshipDeleted", projectID, relationshipID, userID).Return(nil)
 
-	err := suite.service.DeleteRelationship(relationshipID, userID)
+	_, err := suite.service.DeleteRelationship(relationshipID, userID)
 
 	suite.NoError(err)
 	suite.mockAuthService.AssertExpectations(suite.T())
@@ -466,7 +590,7 @@ func (suite *RelationshipServiceTestSuite) TestDeleteRelationship_NotFound() {
 
 	suite.mockAuthService.On("GetProjectIDFromRelationship", relationshipID).Return(uuid.Nil, ErrRelationshipNotFound)
 
-	err := suite.service.DeleteRelationship(relationshipID, userID)
+	_, err := suite.service.DeleteRelationship(relationshipID, userID)
 
 	suite.Error(err)
 	suite.Equal(ErrRelationshipNotFound, err)
@@ -483,7 +607,7 @@ func (suite *RelationshipServiceTestSuite) TestDeleteRelationship_Forbidden() {
 	suite.mockAuthService.On("GetProjectIDFromRelationship", relationshipID).Return(projectID, nil)
 	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(false, nil)
 
-	err := suite.service.DeleteRelationship(relationshipID, userID)
+	_, err := suite.service.DeleteRelationship(relationshipID, userID)
 
 	suite.Error(err)
 	suite.Equal(ErrForbidden, err)
@@ -502,15 +626,55 @@ func (suite *RelationshipServiceTestSuite) TestDeleteRelationship_RepositoryErro
 	suite.mockAuthService.On("GetProjectIDFromRelationship", relationshipID).Return(projectID, nil)
 	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
 	suite.mockRelationshipRepo.On("GetByID", relationshipID).Return(existingRelationship, nil)
-	suite.mockCollaborationService.On("NotifyRelationshipDeleted", projectID, relationshipID, userID).Return(nil)
 	suite.mockRelationshipRepo.On("Delete", relationshipID).Return(assert.AnError)
 
-	err := suite.service.DeleteRelationship(relationshipID, userID)
+	_, err := suite.service.DeleteRelationship(relationshipID, userID)
 
 	suite.Error(err)
 	suite.Equal(assert.AnError, err)
 
 	suite.mockAuthService.AssertExpectations(suite.T())
-	suite.mockCollaborationService.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertNotCalled(suite.T(), "NotifyRelationshipDeleted", mock.Anything, mock.Anything, mock.Anything)
 	suite.mockRelationshipRepo.AssertExpectations(suite.T())
 }
+
+// Test DeleteRelationship - Collaborators hear of the deletion only after it
+func (suite *RelationshipServiceTestSuite) TestDeleteRelationship_NotifiesAfterDelete() {
+	relationshipID := uuid.New()
+	userID := uuid.New()
+	projectID := uuid.New()
+	existingRelationship := createTestRelationship(projectID, uuid.New(), uuid.New(), uuid.New(), uuid.New())
+	existingRelationship.ID = relationshipID
+
+	var calls []string
+	suite.mockAuthService.On("GetProjectIDFromRelationship", relationshipID).Return(projectID, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockRelationshipRepo.On("GetByID", relationshipID).Return(existingRelationship, nil)
+	suite.mockRelationshipRepo.On("Delete", relationshipID).Return(nil).Run(func(mock.Arguments) { calls = append(calls, "delete") })
+	suite.mockCollaborationService.On("NotifyRelationshipDeleted", projectID, relationshipID, userID).
+		Return(nil).Run(func(mock.Arguments) { calls = append(calls, "notify") })
+
+	_, err := suite.service.DeleteRelationship(relationshipID, userID)
+
+	suite.NoError(err)
+	suite.Equal([]string{"delete", "notify"}, calls)
+}
+
+// Test DeleteRelationship - A notification that cannot be saved fails the deletion
+func (suite *RelationshipServiceTestSuite) TestDeleteRelationship_NotifyError() {
+	relationshipID := uuid.New()
+	userID := uuid.New()
+	projectID := uuid.New()
+	existingRelationship := createTestRelationship(projectID, uuid.New(), uuid.New(), uuid.New(), uuid.New())
+	existingRelationship.ID = relationshipID
+
+	suite.mockAuthService.On("GetProjectIDFromRelationship", relationshipID).Return(projectID, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockRelationshipRepo.On("GetByID", relationshipID).Return(existingRelationship, nil)
+	suite.mockRelationshipRepo.On("Delete", relationshipID).Return(nil)
+	suite.mockCollaborationService.On("NotifyRelationshipDeleted", projectID, relationshipID, userID).Return(assert.AnError)
+
+	_, err := suite.service.DeleteRelationship(relationshipID, userID)
+
+	suite.Equal(assert.AnError, err)
+}
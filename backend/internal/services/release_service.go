@@ -0,0 +1,260 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReleaseService names snapshots of a project, such as v1.0 and v2.0, so that
+// consumers can fetch a stable schema by release. A release pins its snapshot:
+// pruning keeps it however old it gets. Locking a release freezes it, failing
+// any change to it but unlocking with ErrReleaseLocked. Anyone with access to
+// a project reads its releases; those who may modify it make, edit and lock
+// them, and only the owner unlocks them.
+type ReleaseService struct {
+	releaseRepo repository.ReleaseRepositoryInterface
+	authService AuthorizationServiceInterface
+	unitOfWork  *UnitOfWork
+	now         func() time.Time
+}
+
+func NewReleaseService(releaseRepo repository.ReleaseRepositoryInterface, authService AuthorizationServiceInterface, unitOfWork *UnitOfWork) *ReleaseService {
+	return &ReleaseService{
+		releaseRepo: releaseRepo,
+		authService: authService,
+		unitOfWork:  unitOfWork,
+		now:         time.Now,
+	}
+}
+
+// CreateRelease names the snapshot of req.SnapshotID, or without one a
+// snapshot of the project taken now
+func (s *ReleaseService) CreateRelease(projectID uuid.UUID, req *dto.CreateReleaseRequest, userID uuid.UUID) (*models.Release, error) {
+	name := strings.TrimSpace(req.Name)
+	if len(name) < 1 || len(name) > 100 {
+		return nil, ErrInvalidInput
+	}
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	release := &models.Release{
+		ProjectID: projectID,
+		Name:      name,
+		Notes:     strings.TrimSpace(req.Notes),
+		Locked:    req.Locked,
+		CreatedBy: userID,
+	}
+	err := s.unitOfWork.Run(func(tx *Tx) error {
+		if err := checkReleaseNameFree(tx.Releases, projectID, uuid.Nil, name); err != nil {
+			return err
+		}
+		if req.SnapshotID != nil {
+			if _, err := projectSnapshot(tx.Snapshots, projectID, *req.SnapshotID); err != nil {
+				return err
+			}
+			release.SnapshotID = *req.SnapshotID
+		} else {
+			project, err := tx.Projects.GetByID(projectID)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrProjectNotFound
+				}
+				return err
+			}
+			regions, err := tx.Regions.GetByProjectID(projectID)
+			if err != nil {
+				return err
+			}
+			snapshot, err := newSnapshot(project, regions, s.now())
+			if err != nil {
+				return err
+			}
+			if release.SnapshotID, err = tx.Snapshots.Create(snapshot); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		release.ID, err = tx.Releases.Create(release)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return release, nil
+}
+
+// GetReleases returns the project's releases newest first
+func (s *ReleaseService) GetReleases(projectID, userID uuid.UUID) ([]*models.Release, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.releaseRepo.GetByProjectID(projectID)
+}
+
+// GetRelease returns a release of the project, ErrReleaseNotFound for a release of another
+func (s *ReleaseService) GetRelease(projectID, id, userID uuid.UUID) (*models.Release, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	return projectRelease(s.releaseRepo, projectID, id)
+}
+
+// UpdateRelease renames a release, edits its notes, moves it to another
+// snapshot of the project, or locks or unlocks it. A locked release fails
+// with ErrReleaseLocked unless the request unlocks it, which only the
+// project's owner may do; the other changes of that request apply after.
+func (s *ReleaseService) UpdateRelease(projectID, id uuid.UUID, req *dto.UpdateReleaseRequest, userID uuid.UUID) (*models.Release, error) {
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	var release *models.Release
+	err := s.unitOfWork.Run(func(tx *Tx) error {
+		var err error
+		if release, err = projectRelease(tx.Releases, projectID, id); err != nil {
+			return err
+		}
+
+		if req.Locked != nil && !*req.Locked && release.Locked {
+			project, err := tx.Projects.GetByID(projectID)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrProjectNotFound
+				}
+				return err
+			}
+			if project.OwnerID != userID {
+				return ErrForbidden
+			}
+			release.Locked = false
+		}
+		if release.Locked && (req.Name != nil || req.Notes != nil || req.SnapshotID != nil) {
+			return ErrReleaseLocked
+		}
+
+		// Only update fields that were provided
+		if req.Name != nil {
+			name := strings.TrimSpace(*req.Name)
+			if len(name) < 1 || len(name) > 100 {
+				return ErrInvalidInput
+			}
+			if name != release.Name {
+				if err := checkReleaseNameFree(tx.Releases, projectID, id, name); err != nil {
+					return err
+				}
+			}
+			release.Name = name
+		}
+		if req.Notes != nil {
+			release.Notes = strings.TrimSpace(*req.Notes)
+		}
+		if req.SnapshotID != nil {
+			if _, err := projectSnapshot(tx.Snapshots, projectID, *req.SnapshotID); err != nil {
+				return err
+			}
+			release.SnapshotID = *req.SnapshotID
+		}
+		if req.Locked != nil && *req.Locked {
+			release.Locked = true
+		}
+		return tx.Releases.Update(release)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return release, nil
+}
+
+// DeleteRelease deletes a release, leaving its snapshot to pruning. Locked
+// releases fail with ErrReleaseLocked.
+func (s *ReleaseService) DeleteRelease(projectID, id, userID uuid.UUID) error {
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return err
+	}
+	release, err := projectRelease(s.releaseRepo, projectID, id)
+	if err != nil {
+		return err
+	}
+	if release.Locked {
+		return ErrReleaseLocked
+	}
+	return s.releaseRepo.Delete(id)
+}
+
+// projectRelease returns a release, ErrReleaseNotFound unless it is of the project
+func projectRelease(releaseRepo repository.ReleaseRepositoryInterface, projectID, id uuid.UUID) (*models.Release, error) {
+	release, err := releaseRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReleaseNotFound
+		}
+		return nil, err
+	}
+	if release.ProjectID != projectID {
+		return nil, ErrReleaseNotFound
+	}
+	return release, nil
+}
+
+// projectSnapshot returns a snapshot with its data, ErrSnapshotNotFound
+// unless it is of the project
+func projectSnapshot(snapshotRepo repository.SnapshotRepositoryInterface, projectID, id uuid.UUID) (*models.ProjectSnapshot, error) {
+	snapshot, err := snapshotRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSnapshotNotFound
+		}
+		return nil, err
+	}
+	if snapshot.ProjectID != projectID {
+		return nil, ErrSnapshotNotFound
+	}
+	return snapshot, nil
+}
+
+// checkReleaseNameFree fails with ErrNameTaken when a release of the project
+// other than id is named name
+func checkReleaseNameFree(releaseRepo repository.ReleaseRepositoryInterface, projectID, id uuid.UUID, name string) error {
+	release, err := releaseRepo.GetByName(projectID, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if release.ID != id {
+		return ErrNameTaken
+	}
+	return nil
+}
+
+func (s *ReleaseService) checkAccess(projectID, userID uuid.UUID) error {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !canAccess {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *ReleaseService) checkCanModify(projectID, userID uuid.UUID) error {
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !canModify {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type ReleaseServiceTestSuite struct {
+	suite.Suite
+	mockReleaseRepo  *mockRepo.MockReleaseRepository
+	mockSnapshotRepo *mockRepo.MockSnapshotRepository
+	mockProjectRepo  *mockRepo.MockProjectRepository
+	mockRegionRepo   *mockRepo.MockRegionRepository
+	mockAuthService  *mockAuthorizationService
+	service          *ReleaseService
+	projectID        uuid.UUID
+	userID           uuid.UUID
+	now              time.Time
+}
+
+func (suite *ReleaseServiceTestSuite) SetupTest() {
+	suite.mockReleaseRepo = new(mockRepo.MockReleaseRepository)
+	suite.mockSnapshotRepo = new(mockRepo.MockSnapshotRepository)
+	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
+	suite.mockRegionRepo = new(mockRepo.MockRegionRepository)
+	suite.mockAuthService = new(mockAuthorizationService)
+	suite.service = NewReleaseService(suite.mockReleaseRepo, suite.mockAuthService, newTestUnitOfWork(repository.Repositories{
+		Projects:  suite.mockProjectRepo,
+		Regions:   suite.mockRegionRepo,
+		Releases:  suite.mockReleaseRepo,
+		Snapshots: suite.mockSnapshotRepo,
+	}))
+	suite.now = time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	suite.service.now = func() time.Time { return suite.now }
+	suite.projectID = uuid.New()
+	suite.userID = uuid.New()
+}
+
+func TestReleaseServiceSuite(t *testing.T) {
+	suite.Run(t, new(ReleaseServiceTestSuite))
+}
+
+// newRelease returns a release of the suite's project
+func (suite *ReleaseServiceTestSuite) newRelease(locked bool) *models.Release {
+	return &models.Release{ID: uuid.New(), ProjectID: suite.projectID, Name: "v1.0", SnapshotID: uuid.New(), Locked: locked}
+}
+
+// Test CreateRelease - Without a snapshot, one of the project as it is now is taken
+func (suite *ReleaseServiceTestSuite) TestCreateRelease_TakesSnapshot() {
+	snapshotID := uuid.New()
+	releaseID := uuid.New()
+	project := &models.Project{ID: suite.projectID, Version: 7, Sequence: 12, Owner: models.User{Username: "owner"}}
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockReleaseRepo.On("GetByName", suite.projectID, "v1.0").Return(nil, gorm.ErrRecordNotFound)
+	suite.mockProjectRepo.On("GetByID", suite.projectID).Return(project, nil)
+	suite.mockRegionRepo.On("GetByProjectID", suite.projectID).Return([]*models.Region{}, nil)
+	suite.mockSnapshotRepo.On("Create", mock.MatchedBy(func(snapshot *models.ProjectSnapshot) bool {
+		return snapshot.ProjectVersion == 7 && snapshot.ProjectSequence == 12 && snapshot.CreatedAt.Equal(suite.now)
+	})).Return(snapshotID, nil)
+	suite.mockReleaseRepo.On("Create", mock.MatchedBy(func(release *models.Release) bool {
+		return release.Name == "v1.0" && release.SnapshotID == snapshotID && release.Locked && release.CreatedBy == suite.userID
+	})).Return(releaseID, nil)
+
+	release, err := suite.service.CreateRelease(suite.projectID, &dto.CreateReleaseRequest{Name: " v1.0 ", Locked: true}, suite.userID)
+
+	suite.NoError(err)
+	suite.Equal(releaseID, release.ID)
+	suite.Empty(project.Owner.Username)
+}
+
+// Test CreateRelease - A snapshot of another project cannot be released
+func (suite *ReleaseServiceTestSuite) TestCreateRelease_SnapshotOfAnotherProject() {
+	snapshotID := uuid.New()
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockReleaseRepo.On("GetByName", suite.projectID, "v1.0").Return(nil, gorm.ErrRecordNotFound)
+	suite.mockSnapshotRepo.On("GetByID", snapshotID).Return(&models.ProjectSnapshot{ID: snapshotID, ProjectID: uuid.New()}, nil)
+
+	release, err := suite.service.CreateRelease(suite.projectID, &dto.CreateReleaseRequest{Name: "v1.0", SnapshotID: &snapshotID}, suite.userID)
+
+	suite.ErrorIs(err, ErrSnapshotNotFound)
+	suite.Nil(release)
+	suite.mockReleaseRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test CreateRelease - Release names are unique within the project
+func (suite *ReleaseServiceTestSuite) TestCreateRelease_NameTaken() {
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockReleaseRepo.On("GetByName", suite.projectID, "v1.0").Return(suite.newRelease(false), nil)
+
+	release, err := suite.service.CreateRelease(suite.projectID, &dto.CreateReleaseRequest{Name: "v1.0"}, suite.userID)
+
+	suite.ErrorIs(err, ErrNameTaken)
+	suite.Nil(release)
+	suite.mockSnapshotRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test UpdateRelease - Locked releases do not change
+func (suite *ReleaseServiceTestSuite) TestUpdateRelease_Locked() {
+	release := suite.newRelease(true)
+	snapshotID := uuid.New()
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockReleaseRepo.On("GetByID", release.ID).Return(release, nil)
+
+	updated, err := suite.service.UpdateRelease(suite.projectID, release.ID, &dto.UpdateReleaseRequest{SnapshotID: &snapshotID}, suite.userID)
+
+	suite.ErrorIs(err, ErrReleaseLocked)
+	suite.Nil(updated)
+	suite.mockReleaseRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+// Test UpdateRelease - Only the owner unlocks a release
+func (suite *ReleaseServiceTestSuite) TestUpdateRelease_UnlockNotOwner() {
+	release := suite.newRelease(true)
+	unlocked := false
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockReleaseRepo.On("GetByID", release.ID).Return(release, nil)
+	suite.mockProjectRepo.On("GetByID", suite.projectID).Return(&models.Project{ID: suite.projectID, OwnerID: uuid.New()}, nil)
+
+	updated, err := suite.service.UpdateRelease(suite.projectID, release.ID, &dto.UpdateReleaseRequest{Locked: &unlocked}, suite.userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(updated)
+	suite.mockReleaseRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+// Test UpdateRelease - Unlocking applies before the other changes of the request
+func (suite *ReleaseServiceTestSuite) TestUpdateRelease_UnlockAndRename() {
+	release := suite.newRelease(true)
+	unlocked := false
+	name := "v1.0.1"
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockReleaseRepo.On("GetByID", release.ID).Return(release, nil)
+	suite.mockProjectRepo.On("GetByID", suite.projectID).Return(&models.Project{ID: suite.projectID, OwnerID: suite.userID}, nil)
+	suite.mockReleaseRepo.On("GetByName", suite.projectID, name).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockReleaseRepo.On("Update", release).Return(nil)
+
+	updated, err := suite.service.UpdateRelease(suite.projectID, release.ID, &dto.UpdateReleaseRequest{Name: &name, Locked: &unlocked}, suite.userID)
+
+	suite.NoError(err)
+	suite.Equal(name, updated.Name)
+	suite.False(updated.Locked)
+}
+
+// Test DeleteRelease - Locked releases are kept
+func (suite *ReleaseServiceTestSuite) TestDeleteRelease_Locked() {
+	release := suite.newRelease(true)
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockReleaseRepo.On("GetByID", release.ID).Return(release, nil)
+
+	err := suite.service.DeleteRelease(suite.projectID, release.ID, suite.userID)
+
+	suite.ErrorIs(err, ErrReleaseLocked)
+	suite.mockReleaseRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+}
+
+// Test GetRelease - Releases of other projects are not found
+func (suite *ReleaseServiceTestSuite) TestGetRelease_OtherProject() {
+	release := suite.newRelease(false)
+	release.ProjectID = uuid.New()
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockReleaseRepo.On("GetByID", release.ID).Return(release, nil)
+
+	found, err := suite.service.GetRelease(suite.projectID, release.ID, suite.userID)
+
+	suite.ErrorIs(err, ErrReleaseNotFound)
+	suite.Nil(found)
+}
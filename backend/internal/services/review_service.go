@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/review"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReviewService reviews project schemas with a review.Provider and keeps what
+// it points out as comments on the tables and fields concerned, until someone
+// resolves them. Those who may modify a project review it and resolve its
+// comments; anyone with access reads them.
+type ReviewService struct {
+	reviewCommentRepo repository.ReviewCommentRepositoryInterface
+	tableRepo         repository.TableRepositoryInterface
+	relationshipRepo  repository.RelationshipRepositoryInterface
+	authService       AuthorizationServiceInterface
+	provider          review.Provider
+}
+
+func NewReviewService(reviewCommentRepo repository.ReviewCommentRepositoryInterface, tableRepo repository.TableRepositoryInterface, relationshipRepo repository.RelationshipRepositoryInterface, authService AuthorizationServiceInterface, provider review.Provider) *ReviewService {
+	return &ReviewService{
+		reviewCommentRepo: reviewCommentRepo,
+		tableRepo:         tableRepo,
+		relationshipRepo:  relationshipRepo,
+		authService:       authService,
+		provider:          provider,
+	}
+}
+
+// ReviewSchema reviews the project's schema and returns the open comments
+// about what the review found. Findings already commented on are not
+// commented on again, and those resolved are not reopened. ErrReviewFailed
+// wraps the error of a provider that failed.
+func (s *ReviewService) ReviewSchema(ctx context.Context, projectID, userID uuid.UUID) ([]*models.ReviewComment, error) {
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return nil, err
+	}
+	tables, err := s.tableRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	relationships, err := s.relationshipRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	annotations, err := s.provider.Review(ctx, newDiffSchema(tables, relationships))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReviewFailed, err)
+	}
+
+	existing, err := s.reviewCommentRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	commented := make(map[review.Annotation]*models.ReviewComment, len(existing))
+	for _, comment := range existing {
+		commented[annotationKey(comment.Kind, comment.TableName, comment.FieldName, comment.Message)] = comment
+	}
+	byName := make(map[string]*models.Table, len(tables))
+	for _, table := range tables {
+		byName[table.Name] = table
+	}
+
+	comments := []*models.ReviewComment{}
+	for _, annotation := range annotations {
+		key := annotationKey(annotation.Kind, annotation.Table, annotation.Field, annotation.Message)
+		if comment, ok := commented[key]; ok {
+			if comment.ResolvedAt == nil {
+				comments = append(comments, comment)
+			}
+			continue
+		}
+
+		comment := &models.ReviewComment{
+			ProjectID:  projectID,
+			TableName:  annotation.Table,
+			FieldName:  annotation.Field,
+			Kind:       annotation.Kind,
+			Message:    annotation.Message,
+			Suggestion: annotation.Suggestion,
+			Provider:   s.provider.Name(),
+			CreatedBy:  userID,
+		}
+		if table, ok := byName[annotation.Table]; ok {
+			comment.TableID = &table.ID
+			for _, field := range table.Fields {
+				if annotation.Field != "" && field.Name == annotation.Field {
+					comment.FieldID = &field.ID
+				}
+			}
+		}
+		id, err := s.reviewCommentRepo.Create(comment)
+		if err != nil {
+			return nil, err
+		}
+		comment.ID = id
+		commented[key] = comment
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+// GetComments returns the project's review comments, resolved or not, oldest first
+func (s *ReviewService) GetComments(projectID, userID uuid.UUID) ([]*models.ReviewComment, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.reviewCommentRepo.GetByProjectID(projectID)
+}
+
+// ResolveComment marks a comment of the project resolved by userID. Resolving
+// it again changes nothing.
+func (s *ReviewService) ResolveComment(projectID, id, userID uuid.UUID) (*models.ReviewComment, error) {
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return nil, err
+	}
+	comment, err := s.reviewCommentRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewCommentNotFound
+		}
+		return nil, err
+	}
+	if comment.ProjectID != projectID {
+		return nil, ErrReviewCommentNotFound
+	}
+	if comment.ResolvedAt != nil {
+		return comment, nil
+	}
+
+	now := time.Now()
+	comment.ResolvedBy = &userID
+	comment.ResolvedAt = &now
+	if err := s.reviewCommentRepo.Update(comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// annotationKey identifies a finding, so a review repeating it is recognized
+func annotationKey(kind, table, field, message string) review.Annotation {
+	return review.Annotation{Kind: kind, Table: table, Field: field, Message: message}
+}
+
+func (s *ReviewService) checkAccess(projectID, userID uuid.UUID) error {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !canAccess {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *ReviewService) checkCanModify(projectID, userID uuid.UUID) error {
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !canModify {
+		return ErrForbidden
+	}
+	return nil
+}
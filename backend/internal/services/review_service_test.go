@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/review"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// fakeReviewProvider answers every review with its annotations, or its error
+type fakeReviewProvider struct {
+	annotations []review.Annotation
+	err         error
+}
+
+func (p *fakeReviewProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeReviewProvider) Review(ctx context.Context, schema schemadiff.Schema) ([]review.Annotation, error) {
+	return p.annotations, p.err
+}
+
+type ReviewServiceTestSuite struct {
+	suite.Suite
+	mockReviewCommentRepo *mockRepo.MockReviewCommentRepository
+	mockTableRepo         *mockRepo.MockTableRepository
+	mockRelationshipRepo  *mockRepo.MockRelationshipRepository
+	mockAuthService       *mockAuthorizationService
+	provider              *fakeReviewProvider
+	service               *ReviewService
+}
+
+func (suite *ReviewServiceTestSuite) SetupTest() {
+	suite.mockReviewCommentRepo = new(mockRepo.MockReviewCommentRepository)
+	suite.mockTableRepo = new(mockRepo.MockTableRepository)
+	suite.mockRelationshipRepo = new(mockRepo.MockRelationshipRepository)
+	suite.mockAuthService = new(mockAuthorizationService)
+	suite.provider = &fakeReviewProvider{}
+	suite.service = NewReviewService(suite.mockReviewCommentRepo, suite.mockTableRepo, suite.mockRelationshipRepo, suite.mockAuthService, suite.provider)
+}
+
+func TestReviewServiceSuite(t *testing.T) {
+	suite.Run(t, new(ReviewServiceTestSuite))
+}
+
+func (suite *ReviewServiceTestSuite) expectSchema(projectID uuid.UUID) *models.Table {
+	table := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "orders", Fields: []models.Field{
+		{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true},
+		{ID: uuid.New(), Name: "customerId", DataType: "UUID"},
+	}}
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{table}, nil)
+	suite.mockRelationshipRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{}, nil)
+	return table
+}
+
+// Test ReviewSchema - New findings become comments linked to their table and
+// field, open ones are returned again and resolved ones stay resolved
+func (suite *ReviewServiceTestSuite) TestReviewSchema_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	table := suite.expectSchema(projectID)
+	resolvedAt := time.Now()
+	open := &models.ReviewComment{ID: uuid.New(), ProjectID: projectID, TableName: "orders", Kind: review.KindNaming, Message: "Plural"}
+	resolved := &models.ReviewComment{ID: uuid.New(), ProjectID: projectID, TableName: "orders", Kind: review.KindNaming, Message: "Vague", ResolvedAt: &resolvedAt}
+	suite.provider.annotations = []review.Annotation{
+		{Kind: review.KindNaming, Table: "orders", Field: "customerId", Message: "Not snake_case", Suggestion: "Rename it customer_id."},
+		{Kind: review.KindNaming, Table: "orders", Message: "Plural"},
+		{Kind: review.KindNaming, Table: "orders", Message: "Vague"},
+	}
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockReviewCommentRepo.On("GetByProjectID", projectID).Return([]*models.ReviewComment{open, resolved}, nil)
+	commentID := uuid.New()
+	suite.mockReviewCommentRepo.On("Create", mock.MatchedBy(func(comment *models.ReviewComment) bool {
+		return comment.ProjectID == projectID && *comment.TableID == table.ID && *comment.FieldID == table.Fields[1].ID &&
+			comment.Suggestion == "Rename it customer_id." && comment.Provider == "fake" && comment.CreatedBy == userID
+	})).Return(commentID, nil).Once()
+
+	comments, err := suite.service.ReviewSchema(context.Background(), projectID, userID)
+
+	suite.NoError(err)
+	suite.Len(comments, 2)
+	suite.Equal(commentID, comments[0].ID)
+	suite.Equal(open, comments[1])
+	suite.mockReviewCommentRepo.AssertExpectations(suite.T())
+}
+
+// Test ReviewSchema - A failing provider saves nothing
+func (suite *ReviewServiceTestSuite) TestReviewSchema_ProviderFailed() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.expectSchema(projectID)
+	suite.provider.err = errors.New("timeout")
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+
+	comments, err := suite.service.ReviewSchema(context.Background(), projectID, userID)
+
+	suite.ErrorIs(err, ErrReviewFailed)
+	suite.Nil(comments)
+	suite.mockReviewCommentRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test ReviewSchema - Viewers without modify rights cannot review
+func (suite *ReviewServiceTestSuite) TestReviewSchema_Forbidden() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(false, nil)
+
+	comments, err := suite.service.ReviewSchema(context.Background(), projectID, userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(comments)
+	suite.mockTableRepo.AssertNotCalled(suite.T(), "GetByProjectID", mock.Anything)
+}
+
+// Test ResolveComment - The resolver and time are recorded
+func (suite *ReviewServiceTestSuite) TestResolveComment_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	comment := &models.ReviewComment{ID: uuid.New(), ProjectID: projectID}
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockReviewCommentRepo.On("GetByID", comment.ID).Return(comment, nil)
+	suite.mockReviewCommentRepo.On("Update", comment).Return(nil)
+
+	resolved, err := suite.service.ResolveComment(projectID, comment.ID, userID)
+
+	suite.NoError(err)
+	suite.Equal(userID, *resolved.ResolvedBy)
+	suite.NotNil(resolved.ResolvedAt)
+}
+
+// Test ResolveComment - A comment of another project is not found
+func (suite *ReviewServiceTestSuite) TestResolveComment_OtherProject() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	comment := &models.ReviewComment{ID: uuid.New(), ProjectID: uuid.New()}
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockReviewCommentRepo.On("GetByID", comment.ID).Return(comment, nil)
+
+	resolved, err := suite.service.ResolveComment(projectID, comment.ID, userID)
+
+	suite.ErrorIs(err, ErrReviewCommentNotFound)
+	suite.Nil(resolved)
+	suite.mockReviewCommentRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+// Test ResolveComment - A missing comment is not found
+func (suite *ReviewServiceTestSuite) TestResolveComment_NotFound() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	id := uuid.New()
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockReviewCommentRepo.On("GetByID", id).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := suite.service.ResolveComment(projectID, id, userID)
+
+	suite.ErrorIs(err, ErrReviewCommentNotFound)
+}
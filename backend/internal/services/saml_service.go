@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
+)
+
+// SAMLProvider is the provider name SAML identities are stored under
+const SAMLProvider = "saml"
+
+// samlMetadataTimeout bounds fetching the identity provider metadata at startup
+const samlMetadataTimeout = 10 * time.Second
+
+// SAMLService implements a SAML 2.0 service provider for enterprise single sign-on
+type SAMLService struct {
+	sp                *saml.ServiceProvider
+	identities        *identityLinker
+	jitProvisioning   bool
+	emailAttribute    string
+	usernameAttribute string
+}
+
+// NewSAMLService loads the service provider key pair and the identity provider metadata
+func NewSAMLService(cfg *config.Config, userRepo repository.UserRepositoryInterface, identityRepo repository.UserIdentityRepositoryInterface) (*SAMLService, error) {
+	keyPair, err := tls.LoadX509KeyPair(cfg.SAML.CertFile, cfg.SAML.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SAML key pair: %w", err)
+	}
+	certificate, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SAML certificate: %w", err)
+	}
+	key, ok := keyPair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("SAML private key cannot sign")
+	}
+
+	idpMetadataURL, err := url.Parse(cfg.SAML.IDPMetadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SAML IdP metadata URL: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), samlMetadataTimeout)
+	defer cancel()
+	idpMetadata, err := samlsp.FetchMetadata(ctx, http.DefaultClient, *idpMetadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch SAML IdP metadata: %w", err)
+	}
+
+	rootURL, err := url.Parse(cfg.SAML.RootURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SAML root URL: %w", err)
+	}
+
+	sp := &saml.ServiceProvider{
+		EntityID:          cfg.SAML.EntityID,
+		Key:               key,
+		Certificate:       certificate,
+		MetadataURL:       *rootURL.JoinPath("/api/auth/saml/metadata"),
+		AcsURL:            *rootURL.JoinPath("/api/auth/saml/acs"),
+		IDPMetadata:       idpMetadata,
+		AllowIDPInitiated: true,
+	}
+
+	return newSAMLService(sp, cfg, userRepo, identityRepo), nil
+}
+
+func newSAMLService(sp *saml.ServiceProvider, cfg *config.Config, userRepo repository.UserRepositoryInterface, identityRepo repository.UserIdentityRepositoryInterface) *SAMLService {
+	return &SAMLService{
+		sp:                sp,
+		identities:        newIdentityLinker(userRepo, identityRepo),
+		jitProvisioning:   cfg.SAML.JITProvisioning,
+		emailAttribute:    cfg.SAML.EmailAttribute,
+		usernameAttribute: cfg.SAML.UsernameAttribute,
+	}
+}
+
+// Metadata returns the service provider metadata XML to register with the identity provider
+func (s *SAMLService) Metadata() ([]byte, error) {
+	metadata, err := xml.MarshalIndent(s.sp.Metadata(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), metadata...), nil
+}
+
+// LoginURL builds a redirect-binding authentication request. The request ID
+// must be passed back to Authenticate to match the response to it.
+func (s *SAMLService) LoginURL(relayState string) (string, string, error) {
+	req, err := s.sp.MakeAuthenticationRequest(s.sp.GetSSOBindingLocation(saml.HTTPRedirectBinding), saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return "", "", err
+	}
+
+	redirectURL, err := req.Redirect(relayState, s.sp)
+	if err != nil {
+		return "", "", err
+	}
+
+	return redirectURL.String(), req.ID, nil
+}
+
+// Authenticate validates the SAML response posted to the ACS endpoint and
+// returns the matching user, provisioning one if JIT provisioning is enabled
+func (s *SAMLService) Authenticate(r *http.Request, requestIDs []string) (*models.User, error) {
+	assertion, err := s.sp.ParseResponse(r, requestIDs)
+	if err != nil {
+		// The detailed reason is only available on the wrapped error
+		if invalid, ok := err.(*saml.InvalidResponseError); ok {
+			log.Printf("SAML response rejected: %v", invalid.PrivateErr)
+		}
+		return nil, ErrSAMLAssertionInvalid
+	}
+
+	profile := s.profileFromAssertion(assertion)
+	if profile.Subject == "" || profile.Email == "" {
+		return nil, ErrSAMLAssertionInvalid
+	}
+
+	return s.identities.resolve(SAMLProvider, profile, s.jitProvisioning)
+}
+
+// profileFromAssertion maps the NameID and configured attributes of an assertion
+// onto a profile. The IdP is trusted to have verified the email.
+func (s *SAMLService) profileFromAssertion(assertion *saml.Assertion) *ExternalProfile {
+	profile := &ExternalProfile{EmailVerified: true}
+
+	if assertion.Subject != nil && assertion.Subject.NameID != nil {
+		profile.Subject = assertion.Subject.NameID.Value
+	}
+
+	for _, statement := range assertion.AttributeStatements {
+		for _, attribute := range statement.Attributes {
+			if len(attribute.Values) == 0 {
+				continue
+			}
+			value := strings.TrimSpace(attribute.Values[0].Value)
+
+			switch {
+			case attributeMatches(attribute, s.emailAttribute):
+				profile.Email = value
+			case attributeMatches(attribute, s.usernameAttribute):
+				profile.Username = value
+			}
+		}
+	}
+
+	// Many IdPs send the email address as the NameID
+	if profile.Email == "" && strings.Contains(profile.Subject, "@") {
+		profile.Email = profile.Subject
+	}
+
+	return profile
+}
+
+// attributeMatches reports whether an attribute has the configured name or friendly name
+func attributeMatches(attribute saml.Attribute, name string) bool {
+	return name != "" && (attribute.Name == name || attribute.FriendlyName == name)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/crewjam/saml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SAMLServiceTestSuite struct {
+	suite.Suite
+	service *SAMLService
+}
+
+func (suite *SAMLServiceTestSuite) SetupTest() {
+	cfg := config.New()
+	cfg.SAML.EmailAttribute = "http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress"
+	cfg.SAML.UsernameAttribute = "username"
+	suite.service = newSAMLService(&saml.ServiceProvider{}, cfg, nil, nil)
+}
+
+func TestSAMLServiceSuite(t *testing.T) {
+	suite.Run(t, new(SAMLServiceTestSuite))
+}
+
+func samlAttribute(name, friendlyName, value string) saml.Attribute {
+	return saml.Attribute{
+		Name:         name,
+		FriendlyName: friendlyName,
+		Values:       []saml.AttributeValue{{Value: value}},
+	}
+}
+
+// Test profileFromAssertion - maps NameID and attributes by name or friendly name
+func (suite *SAMLServiceTestSuite) TestProfileFromAssertion_MapsAttributes() {
+	assertion := &saml.Assertion{
+		Subject: &saml.Subject{NameID: &saml.NameID{Value: "00u1abcd"}},
+		AttributeStatements: []saml.AttributeStatement{{
+			Attributes: []saml.Attribute{
+				samlAttribute("http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress", "", " jane@example.com "),
+				samlAttribute("urn:oid:0.9.2342.19200300.100.1.1", "username", "jane"),
+				samlAttribute("department", "", "engineering"),
+			},
+		}},
+	}
+
+	profile := suite.service.profileFromAssertion(assertion)
+
+	assert.Equal(suite.T(), "00u1abcd", profile.Subject)
+	assert.Equal(suite.T(), "jane@example.com", profile.Email)
+	assert.Equal(suite.T(), "jane", profile.Username)
+	assert.True(suite.T(), profile.EmailVerified)
+}
+
+// Test profileFromAssertion - falls back to an email-formatted NameID
+func (suite *SAMLServiceTestSuite) TestProfileFromAssertion_EmailNameID() {
+	assertion := &saml.Assertion{
+		Subject: &saml.Subject{NameID: &saml.NameID{Value: "jane@example.com"}},
+	}
+
+	profile := suite.service.profileFromAssertion(assertion)
+
+	assert.Equal(suite.T(), "jane@example.com", profile.Subject)
+	assert.Equal(suite.T(), "jane@example.com", profile.Email)
+	assert.Empty(suite.T(), profile.Username)
+}
+
+// Test profileFromAssertion - missing subject leaves the profile empty
+func (suite *SAMLServiceTestSuite) TestProfileFromAssertion_NoSubject() {
+	profile := suite.service.profileFromAssertion(&saml.Assertion{})
+
+	assert.Empty(suite.T(), profile.Subject)
+	assert.Empty(suite.T(), profile.Email)
+}
@@ -0,0 +1,1466 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/apply"
+	"github.com/Bug-Bugger/ezmodel/internal/archive"
+	"github.com/Bug-Bugger/ezmodel/internal/dbt"
+	"github.com/Bug-Bugger/ezmodel/internal/ddl"
+	"github.com/Bug-Bugger/ezmodel/internal/eventschema"
+	"github.com/Bug-Bugger/ezmodel/internal/fixtures"
+	"github.com/Bug-Bugger/ezmodel/internal/inference"
+	"github.com/Bug-Bugger/ezmodel/internal/introspect"
+	"github.com/Bug-Bugger/ezmodel/internal/lint"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/Bug-Bugger/ezmodel/internal/spreadsheet"
+	"github.com/Bug-Bugger/ezmodel/internal/suggest"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxSchemaTables bounds the tables created by one CreateSchema call
+const maxSchemaTables = 100
+
+// maxSchemaRelationships bounds the relationships created by one CreateSchema call
+const maxSchemaRelationships = 500
+
+// Imported tables are laid out on a grid of importGridColumns columns, each
+// cell importCellWidth by importCellHeight
+const (
+	importGridColumns = 4
+	importCellWidth   = 320
+	importCellHeight  = 400
+)
+
+// SchemaProposal is a schema proposed for a project without creating it
+type SchemaProposal struct {
+	Schema dto.CreateSchemaRequest
+	Notes  []string // What could not be inferred with confidence
+}
+
+// Strategies resolving a conflict between a table of a schema and an existing
+// table of the same name
+const (
+	ConflictSkip      = "skip"      // Keep the existing table; relationships refer to it
+	ConflictRename    = "rename"    // Create the table under another name
+	ConflictOverwrite = "overwrite" // Replace the existing table, dropping its relationships
+	ConflictMerge     = "merge"     // Add the fields the existing table lacks to it
+)
+
+// Schema is a set of tables with their fields and the relationships between them
+type Schema struct {
+	Tables        []*models.Table
+	Fields        []*models.Field // Added to existing tables by ConflictMerge
+	Relationships []*models.Relationship
+	Conflicts     []SchemaConflict // Tables named like tables the project has already
+	Sequence      int64            // Project sequence of the last notification about the schema, or 0
+
+	replaced []*models.Table        // Deleted by ConflictOverwrite
+	dropped  []*models.Relationship // Relationships of the replaced tables
+}
+
+// SchemaConflict is a table of a schema named like one the project has already
+type SchemaConflict struct {
+	Table           string
+	ExistingTableID uuid.UUID
+	Strategy        string // How it is resolved, or empty when it is not
+	NewName         string // What the table is created as, for ConflictRename
+}
+
+// SchemaConflictError lists the conflicts a schema cannot be created with
+// until a strategy is chosen for each
+type SchemaConflictError struct {
+	Conflicts []SchemaConflict
+}
+
+func (e *SchemaConflictError) Error() string {
+	return fmt.Sprintf("%d tables of the schema already exist", len(e.Conflicts))
+}
+
+func (e *SchemaConflictError) Is(target error) bool {
+	return target == ErrSchemaConflict
+}
+
+// SchemaService changes several parts of a project's schema at once
+type SchemaService struct {
+	unitOfWork           *UnitOfWork
+	authService          AuthorizationServiceInterface
+	collaborationService CollaborationSessionServiceInterface
+	quotas               QuotaPolicy
+	introspect           func(ctx context.Context, dsn, schema string) (schemadiff.Schema, error)
+	execute              func(ctx context.Context, dsn string, statements []string, mode string) ([]apply.Result, error)
+}
+
+func NewSchemaService(unitOfWork *UnitOfWork, authService AuthorizationServiceInterface, collaborationService CollaborationSessionServiceInterface, quotas QuotaPolicy) *SchemaService {
+	return &SchemaService{
+		unitOfWork:           unitOfWork,
+		authService:          authService,
+		collaborationService: collaborationService,
+		quotas:               quotas,
+		introspect:           introspect.Postgres,
+		execute:              apply.Execute,
+	}
+}
+
+// CreateSchema creates the tables, fields and relationships of req in one
+// transaction, so a failure part way leaves the project as it was.
+// Collaborators are notified once everything is saved. Tables named like
+// existing ones fail with a *SchemaConflictError unless req resolves them.
+func (s *SchemaService) CreateSchema(projectID uuid.UUID, req *dto.CreateSchemaRequest, userID uuid.UUID) (*Schema, error) {
+	return s.createSchema(projectID, req, userID, false)
+}
+
+// PlanSchema checks req like CreateSchema does and returns what it would
+// create, with IDs that are never saved, without changing the project.
+// Unresolved conflicts are listed rather than failing.
+func (s *SchemaService) PlanSchema(projectID uuid.UUID, req *dto.CreateSchemaRequest, userID uuid.UUID) (*Schema, error) {
+	return s.createSchema(projectID, req, userID, true)
+}
+
+func (s *SchemaService) createSchema(projectID uuid.UUID, req *dto.CreateSchemaRequest, userID uuid.UUID, dryRun bool) (*Schema, error) {
+	schema, err := newSchema(projectID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModify {
+		return nil, ErrForbidden
+	}
+
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		project, err := tx.Projects.GetByID(projectID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProjectNotFound
+			}
+			return err
+		}
+		quotas, err := s.quotas.QuotasFor(project.OwnerID)
+		if err != nil {
+			return err
+		}
+		existing, err := tx.Tables.GetByProjectID(projectID)
+		if err != nil {
+			return err
+		}
+		tables, err := resolveConflicts(schema, req.Conflicts, existing)
+		if err != nil {
+			return err
+		}
+		if !dryRun {
+			var unresolved []SchemaConflict
+			for _, conflict := range schema.Conflicts {
+				if conflict.Strategy == "" {
+					unresolved = append(unresolved, conflict)
+				}
+			}
+			if len(unresolved) > 0 {
+				return &SchemaConflictError{Conflicts: unresolved}
+			}
+		}
+		if err := quotas.CheckTables(len(existing) - len(schema.replaced) + len(schema.Tables)); err != nil {
+			return err
+		}
+		if err := resolveRelationships(schema, req.Relationships, tables); err != nil {
+			return err
+		}
+
+		dropped := make(map[uuid.UUID]bool)
+		for _, table := range schema.replaced {
+			relationships, err := tx.Relationships.GetByTableID(table.ID)
+			if err != nil {
+				return err
+			}
+			for _, relationship := range relationships {
+				if !dropped[relationship.ID] {
+					dropped[relationship.ID] = true
+					schema.dropped = append(schema.dropped, relationship)
+				}
+			}
+		}
+		if dryRun {
+			return nil
+		}
+
+		for _, relationship := range schema.dropped {
+			if err := tx.Relationships.Delete(relationship.ID); err != nil {
+				return err
+			}
+		}
+		for _, table := range schema.replaced {
+			if err := tx.Tables.Delete(table.ID); err != nil {
+				return err
+			}
+		}
+
+		for _, table := range schema.Tables {
+			fields := table.Fields
+			table.Fields = nil // Inserted below with a single statement
+			if _, err := tx.Tables.Create(table); err != nil {
+				return err
+			}
+			table.Fields = fields
+			if len(fields) > 0 {
+				if err := tx.Fields.CreateBatch(fieldPointers(fields)); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(schema.Fields) > 0 {
+			if err := tx.Fields.CreateBatch(schema.Fields); err != nil {
+				return err
+			}
+		}
+
+		for _, relationship := range schema.Relationships {
+			if _, err := tx.Relationships.Create(relationship); err != nil {
+				return err
+			}
+		}
+
+		if s.collaborationService != nil {
+			if err := notifySchemaCreated(s.collaborationService.InTx(tx), projectID, schema, userID); err != nil {
+				return err
+			}
+		}
+		schema.Sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// ImportSpreadsheet creates the tables defined in an Excel workbook or CSV
+// file, one per sheet, like CreateSchema does with the conflicts resolved. Problems with the file are
+// reported as a *spreadsheet.ValidationError.
+func (s *SchemaService) ImportSpreadsheet(projectID uuid.UUID, filename string, data []byte, conflicts []dto.SchemaConflictResolution, userID uuid.UUID) (*Schema, error) {
+	req, err := spreadsheetSchema(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	req.Conflicts = conflicts
+	return s.CreateSchema(projectID, req, userID)
+}
+
+// PlanSpreadsheet returns what ImportSpreadsheet would create, like PlanSchema
+func (s *SchemaService) PlanSpreadsheet(projectID uuid.UUID, filename string, data []byte, conflicts []dto.SchemaConflictResolution, userID uuid.UUID) (*Schema, error) {
+	req, err := spreadsheetSchema(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	req.Conflicts = conflicts
+	return s.PlanSchema(projectID, req, userID)
+}
+
+// spreadsheetSchema reads the tables of a spreadsheet into a request laying them out on the grid
+func spreadsheetSchema(filename string, data []byte) (*dto.CreateSchemaRequest, error) {
+	tables, err := spreadsheet.Read(filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &dto.CreateSchemaRequest{Tables: make([]dto.SchemaTableRequest, len(tables))}
+	for i, table := range tables {
+		tableReq := dto.SchemaTableRequest{
+			Name:   table.Name,
+			PosX:   importPosX(i),
+			PosY:   importPosY(i),
+			Fields: make([]dto.CreateFieldRequest, len(table.Columns)),
+		}
+		for j, column := range table.Columns {
+			tableReq.Fields[j] = dto.CreateFieldRequest{
+				Name:         column.Name,
+				DataType:     column.DataType,
+				IsPrimaryKey: column.IsPrimaryKey,
+				IsNullable:   column.IsNullable,
+				DefaultValue: column.DefaultValue,
+				Position:     j,
+			}
+		}
+		req.Tables[i] = tableReq
+	}
+	return req, nil
+}
+
+// InferSchema proposes tables, fields and relationships for MongoDB
+// collections described by sample documents or Mongoose schemas, without
+// creating anything. The proposal can be edited, then passed to CreateSchema.
+// Documents that are not JSON objects fail with inference.ErrNotObject.
+func (s *SchemaService) InferSchema(projectID uuid.UUID, req *dto.InferSchemaRequest, userID uuid.UUID) (*SchemaProposal, error) {
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModify {
+		return nil, ErrForbidden
+	}
+
+	collections := make([]inference.Collection, len(req.Collections))
+	for i, collection := range req.Collections {
+		collections[i] = inference.Collection{
+			Name:      collection.Name,
+			Documents: collection.Documents,
+			Mongoose:  collection.MongooseSchema,
+		}
+	}
+	inferred, err := inference.Infer(collections)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal := &SchemaProposal{
+		Schema: dto.CreateSchemaRequest{
+			Tables:        make([]dto.SchemaTableRequest, len(inferred.Tables)),
+			Relationships: make([]dto.SchemaRelationshipRequest, len(inferred.Relationships)),
+		},
+		Notes: inferred.Notes,
+	}
+	for i, table := range inferred.Tables {
+		tableReq := dto.SchemaTableRequest{
+			Name:   table.Name,
+			PosX:   importPosX(i),
+			PosY:   importPosY(i),
+			Fields: make([]dto.CreateFieldRequest, len(table.Columns)),
+		}
+		for j, column := range table.Columns {
+			tableReq.Fields[j] = dto.CreateFieldRequest{
+				Name:         column.Name,
+				DataType:     column.DataType,
+				IsPrimaryKey: column.IsPrimaryKey,
+				IsNullable:   column.IsNullable,
+				DefaultValue: column.DefaultValue,
+				Position:     j,
+			}
+		}
+		proposal.Schema.Tables[i] = tableReq
+	}
+	for i, relationship := range inferred.Relationships {
+		proposal.Schema.Relationships[i] = dto.SchemaRelationshipRequest{
+			SourceTable:  relationship.SourceTable,
+			SourceField:  relationship.SourceField,
+			TargetTable:  relationship.TargetTable,
+			TargetField:  relationship.TargetField,
+			RelationType: "one_to_many",
+		}
+	}
+	if proposal.Notes == nil {
+		proposal.Notes = []string{}
+	}
+	return proposal, nil
+}
+
+// CompareProjects returns what changes from the schema of a project to the
+// schema of another, e.g. from a staging model to an import of production.
+// The user needs access to both.
+func (s *SchemaService) CompareProjects(projectID, otherProjectID, userID uuid.UUID) (*schemadiff.Diff, error) {
+	for _, id := range []uuid.UUID{projectID, otherProjectID} {
+		canAccess, err := s.authService.CanUserAccessProject(userID, id)
+		if err != nil {
+			return nil, err
+		}
+		if !canAccess {
+			return nil, ErrForbidden
+		}
+	}
+
+	// Both are read in one transaction, so neither changes part way
+	var from, to schemadiff.Schema
+	err := s.unitOfWork.Run(func(tx *Tx) error {
+		var err error
+		if from, err = readDiffSchema(tx, projectID); err != nil {
+			return err
+		}
+		to, err = readDiffSchema(tx, otherProjectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schemadiff.Compare(from, to), nil
+}
+
+// Rename renames a table, or one of its fields when req names one, and
+// records the rename, so applying the model to a database renames it there
+// too rather than dropping it with its data. Relationships link tables and
+// fields by ID and need no change. Names already taken, by another table of
+// the project or by another field of the table ignoring case, fail with
+// ErrNameTaken.
+func (s *SchemaService) Rename(projectID uuid.UUID, req *dto.RenameRequest, userID uuid.UUID) (*models.SchemaRename, error) {
+	name := strings.TrimSpace(req.Name)
+	if len(name) < 1 || len(name) > 255 {
+		return nil, ErrInvalidInput
+	}
+
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModify {
+		return nil, ErrForbidden
+	}
+
+	rename := &models.SchemaRename{ProjectID: projectID, TableID: req.TableID, FieldID: req.FieldID, NewName: name, RenamedBy: userID}
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		table, err := tx.Tables.GetByID(req.TableID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTableNotFound
+			}
+			return err
+		}
+		if table.ProjectID != projectID {
+			return ErrTableNotFound
+		}
+		rename.TableName = table.Name
+
+		if req.FieldID == nil {
+			if table.Name == name {
+				return ErrInvalidInput
+			}
+			tables, err := tx.Tables.GetByProjectID(projectID)
+			if err != nil {
+				return err
+			}
+			if findTable(tables, name) != nil {
+				return ErrNameTaken
+			}
+			rename.OldName = table.Name
+			table.Name = name
+			if err := tx.Tables.Update(table); err != nil {
+				return err
+			}
+			if s.collaborationService != nil {
+				if err := s.collaborationService.InTx(tx).NotifyTableUpdated(projectID, table, userID); err != nil {
+					return err
+				}
+			}
+		} else {
+			var field *models.Field
+			for i := range table.Fields {
+				if table.Fields[i].ID == *req.FieldID {
+					field = &table.Fields[i]
+				}
+			}
+			if field == nil {
+				return ErrFieldNotFound
+			}
+			if fieldNameTaken(table.Fields, name, field.ID) {
+				return ErrFieldNameTaken
+			}
+			if field.Name == name {
+				return ErrInvalidInput
+			}
+			rename.OldName = field.Name
+			field.Name = name
+			if err := tx.Fields.Update(field); err != nil {
+				return err
+			}
+			if s.collaborationService != nil {
+				if err := s.collaborationService.InTx(tx).NotifyFieldUpdated(projectID, field, userID); err != nil {
+					return err
+				}
+			}
+		}
+
+		if _, err := tx.Renames.Create(rename); err != nil {
+			return err
+		}
+		rename.Sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, ErrVersionConflict
+		}
+		return nil, takenError(err)
+	}
+	return rename, nil
+}
+
+// ListRenames returns the tables and fields of a project renamed by
+// refactoring, oldest first
+func (s *SchemaService) ListRenames(projectID, userID uuid.UUID) ([]*models.SchemaRename, error) {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccess {
+		return nil, ErrForbidden
+	}
+
+	var renames []*models.SchemaRename
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if _, err := tx.Projects.GetByID(projectID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProjectNotFound
+			}
+			return err
+		}
+		renames, err = tx.Renames.GetByProjectID(projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return renames, nil
+}
+
+// LintSchema checks a project's schema for likely normalization problems,
+// such as repeating groups or fields depending on part of a composite key
+func (s *SchemaService) LintSchema(projectID, userID uuid.UUID) ([]lint.Finding, error) {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccess {
+		return nil, ErrForbidden
+	}
+
+	var schema schemadiff.Schema
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if _, err := tx.Projects.GetByID(projectID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProjectNotFound
+			}
+			return err
+		}
+		schema, err = readDiffSchema(tx, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lint.Check(schema), nil
+}
+
+// RelationshipSuggestion is a relationship a project's schema may be missing,
+// with the IDs of the tables and fields it links
+type RelationshipSuggestion struct {
+	suggest.Suggestion
+	SourceTableID uuid.UUID
+	SourceFieldID uuid.UUID
+	TargetTableID uuid.UUID
+	TargetFieldID uuid.UUID
+}
+
+// SuggestRelationships proposes the foreign keys a project's schema seems to
+// miss, from fields named after tables, such as user_id for users, with the
+// type of their key. Nothing is created; see AcceptRelationshipSuggestions.
+func (s *SchemaService) SuggestRelationships(projectID, userID uuid.UUID) ([]RelationshipSuggestion, error) {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccess {
+		return nil, ErrForbidden
+	}
+
+	var suggestions []RelationshipSuggestion
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if _, err := tx.Projects.GetByID(projectID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProjectNotFound
+			}
+			return err
+		}
+		tables, err := tx.Tables.GetByProjectID(projectID)
+		if err != nil {
+			return err
+		}
+		relationships, err := tx.Relationships.GetByProjectID(projectID)
+		if err != nil {
+			return err
+		}
+
+		byName := make(map[string]*models.Table, len(tables))
+		for _, table := range tables {
+			byName[table.Name] = table
+		}
+		suggestions = []RelationshipSuggestion{}
+		for _, suggestion := range suggest.Relationships(newDiffSchema(tables, relationships)) {
+			sourceTable, sourceField, err := lookupField(byName, suggestion.SourceTable, suggestion.SourceField)
+			if err != nil {
+				return err
+			}
+			targetTable, targetField, err := lookupField(byName, suggestion.TargetTable, suggestion.TargetField)
+			if err != nil {
+				return err
+			}
+			suggestions = append(suggestions, RelationshipSuggestion{
+				Suggestion:    suggestion,
+				SourceTableID: sourceTable.ID,
+				SourceFieldID: sourceField.ID,
+				TargetTableID: targetTable.ID,
+				TargetFieldID: targetField.ID,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+// AcceptRelationshipSuggestions creates the relationships of reqs, named like
+// suggestions are, in one transaction. Those the project has already, linking
+// the same fields, are skipped, so accepting twice creates nothing more.
+// Collaborators are notified once everything is saved.
+func (s *SchemaService) AcceptRelationshipSuggestions(projectID uuid.UUID, reqs []dto.SchemaRelationshipRequest, userID uuid.UUID) (*Schema, error) {
+	if len(reqs) < 1 || len(reqs) > maxSchemaRelationships {
+		return nil, ErrInvalidInput
+	}
+
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModify {
+		return nil, ErrForbidden
+	}
+
+	schema := &Schema{}
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if _, err := tx.Projects.GetByID(projectID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProjectNotFound
+			}
+			return err
+		}
+		tables, err := tx.Tables.GetByProjectID(projectID)
+		if err != nil {
+			return err
+		}
+		existing, err := tx.Relationships.GetByProjectID(projectID)
+		if err != nil {
+			return err
+		}
+
+		byName := make(map[string]*models.Table, len(tables))
+		for _, table := range tables {
+			byName[table.Name] = table
+		}
+		if err := resolveRelationships(schema, reqs, byName); err != nil {
+			return err
+		}
+
+		linked := make(map[[2]uuid.UUID]bool, len(existing))
+		for _, relationship := range existing {
+			linked[[2]uuid.UUID{relationship.SourceFieldID, relationship.TargetFieldID}] = true
+		}
+		created := schema.Relationships[:0]
+		for _, relationship := range schema.Relationships {
+			key := [2]uuid.UUID{relationship.SourceFieldID, relationship.TargetFieldID}
+			if linked[key] {
+				continue
+			}
+			linked[key] = true
+			if _, err := tx.Relationships.Create(relationship); err != nil {
+				return err
+			}
+			created = append(created, relationship)
+		}
+		schema.Relationships = created
+
+		if s.collaborationService != nil {
+			if err := notifySchemaCreated(s.collaborationService.InTx(tx), projectID, schema, userID); err != nil {
+				return err
+			}
+		}
+		schema.Sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Formats of generated data
+const (
+	DataFormatSQL = "sql" // INSERT statements in seed.sql
+	DataFormatCSV = "csv" // A CSV file per table
+)
+
+// defaultDataRows is how many rows GenerateData makes for each table unless asked otherwise
+const defaultDataRows = 10
+
+// GenerateData generates fake rows for every table of a project, for seeding
+// a development database, as a zip archive. It holds seed.sql, with INSERT
+// statements in foreign key order, or a CSV file per table numbered in the
+// order they can be loaded in. The same seed generates the same rows for the
+// same schema. Tables referencing each other through required foreign keys
+// fail with fixtures.ErrCycle.
+func (s *SchemaService) GenerateData(projectID uuid.UUID, req *dto.GenerateDataRequest, userID uuid.UUID) ([]byte, error) {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccess {
+		return nil, ErrForbidden
+	}
+
+	var schema schemadiff.Schema
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		var err error
+		schema, err = readDiffSchema(tx, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := req.Rows
+	if rows == 0 {
+		rows = defaultDataRows
+	}
+	seed := rand.Uint64()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+	tables, err := fixtures.Generate(schema, rows, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []archive.File
+	if req.Format == DataFormatCSV {
+		for i, table := range tables {
+			data, err := fixtures.CSV(table)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, archive.File{Name: fmt.Sprintf("%02d_%s.csv", i+1, table.Name), Data: data})
+		}
+	} else {
+		files = append(files, archive.File{Name: "seed.sql", Data: fixtures.SQL(tables)})
+	}
+	return archive.Zip(files)
+}
+
+// Formats a project's schema is exported in
+const (
+	ExportFormatSQL      = "sql"      // CREATE TABLE statements in the dialect of the project's database type
+	ExportFormatJSON     = "json"     // A request to createSchema creating the schema again
+	ExportFormatDBT      = "dbt"      // A zip of the dbt source and staging models of the schema
+	ExportFormatAvro     = "avro"     // A zip of an Avro record schema per table
+	ExportFormatProtobuf = "protobuf" // A proto3 file with a message per table
+)
+
+// SchemaExport is a project's schema written in an export format
+type SchemaExport struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// ExportSchema writes the schema of a project in format, for tools and
+// pipelines outside EzModel. Projects of a database type without an SQL
+// dialect cannot be exported as SQL. The project's snippets go along as an
+// appendix of SQL exports and as analyses of dbt ones.
+func (s *SchemaService) ExportSchema(projectID uuid.UUID, format string, userID uuid.UUID) (*SchemaExport, error) {
+	if !slices.Contains([]string{ExportFormatSQL, ExportFormatJSON, ExportFormatDBT, ExportFormatAvro, ExportFormatProtobuf}, format) {
+		return nil, ErrUnknownExportFormat
+	}
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccess {
+		return nil, ErrForbidden
+	}
+
+	var project *models.Project
+	var schema schemadiff.Schema
+	var snippets []*models.Snippet
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		var err error
+		if project, err = tx.Projects.GetByID(projectID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProjectNotFound
+			}
+			return err
+		}
+		if schema, err = readDiffSchema(tx, projectID); err != nil {
+			return err
+		}
+		// Only the SQL and dbt formats have a place for SQL
+		if format == ExportFormatSQL || format == ExportFormatDBT {
+			snippets, err = tx.Snippets.GetByProjectID(projectID)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return exportSchema(format, project, schema, snippets)
+}
+
+// ExportRelease writes the schema of a project as of its release of that
+// name in format, like ExportSchema. The schema is the release's snapshot,
+// so it stays the same however the project changes; snippets are not
+// snapshotted and are left out.
+func (s *SchemaService) ExportRelease(projectID uuid.UUID, release, format string, userID uuid.UUID) (*SchemaExport, error) {
+	if !slices.Contains([]string{ExportFormatSQL, ExportFormatJSON, ExportFormatDBT, ExportFormatAvro, ExportFormatProtobuf}, format) {
+		return nil, ErrUnknownExportFormat
+	}
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccess {
+		return nil, ErrForbidden
+	}
+
+	var snapshot *models.ProjectSnapshot
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		named, err := tx.Releases.GetByName(projectID, release)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrReleaseNotFound
+			}
+			return err
+		}
+		snapshot, err = projectSnapshot(tx.Snapshots, projectID, named.SnapshotID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := snapshotProject(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]*models.Table, len(project.Tables))
+	for i := range project.Tables {
+		tables[i] = &project.Tables[i]
+	}
+	relationships := make([]*models.Relationship, len(project.Relationships))
+	for i := range project.Relationships {
+		relationships[i] = &project.Relationships[i]
+	}
+	return exportSchema(format, project, newDiffSchema(tables, relationships), nil)
+}
+
+// exportSchema writes a project's schema in format, with the snippets where
+// the format has a place for SQL
+func exportSchema(format string, project *models.Project, schema schemadiff.Schema, snippets []*models.Snippet) (*SchemaExport, error) {
+	switch format {
+	case ExportFormatJSON:
+		data, err := json.MarshalIndent(schemaRequestOf(project), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &SchemaExport{Filename: "schema.json", ContentType: "application/json", Data: data}, nil
+	case ExportFormatDBT:
+		files, err := dbt.Scaffold(project.Name, project.Description, project.Metadata, schema)
+		if err != nil {
+			return nil, err
+		}
+		analyses := make([]dbt.Analysis, len(snippets))
+		for i, snippet := range snippets {
+			analyses[i] = dbt.Analysis{Name: snippet.Name, Description: snippet.Description, SQL: snippet.SQL}
+		}
+		files = append(files, dbt.Analyses(analyses)...)
+		data, err := archive.Zip(files)
+		if err != nil {
+			return nil, err
+		}
+		return &SchemaExport{Filename: dbt.Name(project.Name) + "_dbt.zip", ContentType: "application/zip", Data: data}, nil
+	case ExportFormatAvro:
+		files, err := eventschema.Avro(project.Name, schema)
+		if err != nil {
+			return nil, err
+		}
+		data, err := archive.Zip(files)
+		if err != nil {
+			return nil, err
+		}
+		return &SchemaExport{Filename: eventschema.PackageName(project.Name) + "_avro.zip", ContentType: "application/zip", Data: data}, nil
+	case ExportFormatProtobuf:
+		return &SchemaExport{Filename: eventschema.PackageName(project.Name) + ".proto", ContentType: "text/plain; charset=utf-8", Data: eventschema.Protobuf(project.Name, schema)}, nil
+	}
+	data, err := ddl.Generate(schema, project.DatabaseType)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaExport{Filename: "schema.sql", ContentType: "application/sql", Data: append(data, snippetAppendix(snippets)...)}, nil
+}
+
+// snippetAppendix writes snippets after the statements of a schema, each
+// under a comment naming and describing it, or nothing without snippets
+func snippetAppendix(snippets []*models.Snippet) []byte {
+	if len(snippets) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.WriteString("\n-- Appendix: snippets saved with the project\n")
+	for _, snippet := range snippets {
+		buf.WriteString("\n-- " + strings.ReplaceAll(snippet.Name, "\n", " ") + "\n")
+		for _, line := range strings.Split(snippet.Description, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				buf.WriteString("-- " + line + "\n")
+			}
+		}
+		buf.WriteString(strings.TrimRight(snippet.SQL, "\n") + "\n")
+	}
+	return buf.Bytes()
+}
+
+// applyTimeout bounds reading a target database and running a plan on it
+const applyTimeout = 5 * time.Minute
+
+// ApplyPlan is how to bring a target database to a project's model
+type ApplyPlan struct {
+	*ddl.Migration
+	Checksum string // Of the statements, so execution can require the plan reviewed
+}
+
+// ApplyLog is what became of every statement of an executed plan
+type ApplyLog struct {
+	Plan        *ApplyPlan
+	Transaction string
+	Results     []apply.Result // Empty when the database matched the model
+}
+
+// PlanApply compares a PostgreSQL database to the project's model and plans
+// the statements bringing it to the model, without changing it. Those who
+// may modify the project may; the connection string is not kept.
+func (s *SchemaService) PlanApply(projectID uuid.UUID, req *dto.PlanApplyRequest, userID uuid.UUID) (*ApplyPlan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), applyTimeout)
+	defer cancel()
+	return s.planApply(ctx, projectID, req.DSN, req.SchemaName, userID)
+}
+
+// ExecuteApply plans again and runs the plan on the database, in one
+// transaction or one per statement. It is refused unless req names the
+// checksum of the plan, so only a reviewed plan runs and not once the database
+// or the model changed since, and when the plan may lose data unless req
+// allows it.
+func (s *SchemaService) ExecuteApply(projectID uuid.UUID, req *dto.ExecuteApplyRequest, userID uuid.UUID) (*ApplyLog, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), applyTimeout)
+	defer cancel()
+	plan, err := s.planApply(ctx, projectID, req.DSN, req.SchemaName, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Checksum != plan.Checksum {
+		return nil, ErrApplyPlanChanged
+	}
+	if plan.Destructive() && !req.AllowDestructive {
+		return nil, ErrDestructiveChanges
+	}
+
+	applyLog := &ApplyLog{Plan: plan, Transaction: req.Transaction}
+	if applyLog.Transaction == "" {
+		applyLog.Transaction = apply.ModeSingle
+	}
+	if len(plan.Statements) == 0 {
+		return applyLog, nil
+	}
+	statements := make([]string, len(plan.Statements))
+	for i, statement := range plan.Statements {
+		statements[i] = statement.SQL
+	}
+	if applyLog.Results, err = s.execute(ctx, req.DSN, statements, applyLog.Transaction); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTargetDatabase, err)
+	}
+	return applyLog, nil
+}
+
+// planApply plans the migration of the database of dsn to the project's
+// model, comparing them as drift checks do
+func (s *SchemaService) planApply(ctx context.Context, projectID uuid.UUID, dsn, schemaName string, userID uuid.UUID) (*ApplyPlan, error) {
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModify {
+		return nil, ErrForbidden
+	}
+	if err := introspect.ValidateDSN(dsn); err != nil {
+		return nil, ErrInvalidInput
+	}
+	if schemaName == "" {
+		schemaName = "public"
+	}
+
+	var model schemadiff.Schema
+	var renames []schemadiff.Rename
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		project, err := tx.Projects.GetByID(projectID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProjectNotFound
+			}
+			return err
+		}
+		if project.DatabaseType != "" && project.DatabaseType != ddl.PostgreSQL {
+			return fmt.Errorf("%w %q", ddl.ErrUnsupportedDialect, project.DatabaseType)
+		}
+		if model, err = readDiffSchema(tx, projectID); err != nil {
+			return err
+		}
+		// Fields saved before their types and defaults were validated
+		if err := ddl.ValidateSchema(model); err != nil {
+			return err
+		}
+		recorded, err := tx.Renames.GetByProjectID(projectID)
+		if err != nil {
+			return err
+		}
+		renames = make([]schemadiff.Rename, len(recorded))
+		for i, rename := range recorded {
+			renames[i] = schemadiff.Rename{Table: rename.TableName, To: rename.NewName}
+			if rename.FieldID != nil {
+				renames[i].Field = rename.OldName
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := s.introspect(ctx, dsn, schemaName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTargetDatabase, err)
+	}
+	// Tables and fields renamed in the model are renamed in the database too,
+	// where they still have their old names, rather than dropped and created
+	live, renames = live.Rename(renames)
+	migration := ddl.Migrate(schemadiff.Compare(driftSchema(live), driftSchema(model)), model, renames)
+
+	hash := sha256.New()
+	for _, statement := range migration.Statements {
+		hash.Write([]byte(statement.SQL + "\n"))
+	}
+	return &ApplyPlan{Migration: migration, Checksum: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// schemaRequestOf describes the schema of a project, loaded with its tables,
+// fields and relationships, as a request creating it again, layout included
+func schemaRequestOf(project *models.Project) dto.CreateSchemaRequest {
+	req := dto.CreateSchemaRequest{Tables: make([]dto.SchemaTableRequest, len(project.Tables))}
+	tableNames := make(map[uuid.UUID]string, len(project.Tables))
+	fieldNames := make(map[uuid.UUID]string)
+	for i, table := range project.Tables {
+		tableNames[table.ID] = table.Name
+		req.Tables[i] = dto.SchemaTableRequest{
+			Name:     table.Name,
+			PosX:     table.PosX,
+			PosY:     table.PosY,
+			Color:    table.Color,
+			Icon:     table.Icon,
+			Fields:   make([]dto.CreateFieldRequest, len(table.Fields)),
+			Metadata: table.Metadata,
+		}
+		for j, field := range table.Fields {
+			fieldNames[field.ID] = field.Name
+			req.Tables[i].Fields[j] = dto.CreateFieldRequest{
+				Name:         field.Name,
+				DataType:     field.DataType,
+				IsPrimaryKey: field.IsPrimaryKey,
+				IsNullable:   field.IsNullable,
+				DefaultValue: field.DefaultValue,
+				Position:     field.Position,
+				Metadata:     field.Metadata,
+			}
+		}
+	}
+	for _, relationship := range project.Relationships {
+		sourceTable, ok1 := tableNames[relationship.SourceTableID]
+		sourceField, ok2 := fieldNames[relationship.SourceFieldID]
+		targetTable, ok3 := tableNames[relationship.TargetTableID]
+		targetField, ok4 := fieldNames[relationship.TargetFieldID]
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			continue // Links a deleted table or field
+		}
+		req.Relationships = append(req.Relationships, dto.SchemaRelationshipRequest{
+			SourceTable:  sourceTable,
+			SourceField:  sourceField,
+			TargetTable:  targetTable,
+			TargetField:  targetField,
+			RelationType: relationship.RelationType,
+			Metadata:     relationship.Metadata,
+		})
+	}
+	return req
+}
+
+// readDiffSchema reads the structure of a project's schema, naming the tables
+// and fields relationships link
+func readDiffSchema(tx *Tx, projectID uuid.UUID) (schemadiff.Schema, error) {
+	tables, err := tx.Tables.GetByProjectID(projectID)
+	if err != nil {
+		return schemadiff.Schema{}, err
+	}
+	relationships, err := tx.Relationships.GetByProjectID(projectID)
+	if err != nil {
+		return schemadiff.Schema{}, err
+	}
+	return newDiffSchema(tables, relationships), nil
+}
+
+// newDiffSchema names the tables and fields relationships link
+func newDiffSchema(tables []*models.Table, relationships []*models.Relationship) schemadiff.Schema {
+	schema := schemadiff.Schema{Tables: make([]schemadiff.Table, len(tables))}
+	tableNames := make(map[uuid.UUID]string, len(tables))
+	fieldNames := make(map[uuid.UUID]string)
+	for i, table := range tables {
+		tableNames[table.ID] = table.Name
+		diffTable := schemadiff.Table{Name: table.Name, Fields: make([]schemadiff.Field, len(table.Fields)), Metadata: table.Metadata}
+		for j, field := range table.Fields {
+			fieldNames[field.ID] = field.Name
+			diffTable.Fields[j] = schemadiff.Field{
+				Name:         field.Name,
+				DataType:     field.DataType,
+				IsPrimaryKey: field.IsPrimaryKey,
+				IsNullable:   field.IsNullable,
+				DefaultValue: field.DefaultValue,
+				Metadata:     field.Metadata,
+			}
+		}
+		schema.Tables[i] = diffTable
+	}
+	for _, relationship := range relationships {
+		sourceTable, ok1 := tableNames[relationship.SourceTableID]
+		sourceField, ok2 := fieldNames[relationship.SourceFieldID]
+		targetTable, ok3 := tableNames[relationship.TargetTableID]
+		targetField, ok4 := fieldNames[relationship.TargetFieldID]
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			continue // Links a deleted table or field
+		}
+		schema.Relationships = append(schema.Relationships, schemadiff.Relationship{
+			SourceTable:  sourceTable,
+			SourceField:  sourceField,
+			TargetTable:  targetTable,
+			TargetField:  targetField,
+			RelationType: relationship.RelationType,
+		})
+	}
+	return schema
+}
+
+// importPosX and importPosY place the i-th imported table on the grid
+func importPosX(i int) float64 {
+	return float64(i%importGridColumns) * importCellWidth
+}
+
+func importPosY(i int) float64 {
+	return float64(i/importGridColumns) * importCellHeight
+}
+
+// notifySchemaCreated tells collaborators about every part of a created schema,
+// replaced tables first, then tables before the fields and relationships
+// referring to them
+func notifySchemaCreated(collaborationService CollaborationSessionServiceInterface, projectID uuid.UUID, schema *Schema, userID uuid.UUID) error {
+	for _, relationship := range schema.dropped {
+		if err := collaborationService.NotifyRelationshipDeleted(projectID, relationship.ID, userID); err != nil {
+			return err
+		}
+	}
+	for _, table := range schema.replaced {
+		if err := collaborationService.NotifyTableDeleted(projectID, table.ID, table.Name, userID); err != nil {
+			return err
+		}
+	}
+	for _, table := range schema.Tables {
+		if err := collaborationService.NotifyTableCreated(projectID, table, userID); err != nil {
+			return err
+		}
+		if len(table.Fields) == 0 {
+			continue
+		}
+		if err := collaborationService.NotifyFieldsCreated(projectID, table.ID, fieldPointers(table.Fields), userID); err != nil {
+			return err
+		}
+	}
+	// Merged fields come grouped by table
+	for start := 0; start < len(schema.Fields); {
+		end := start + 1
+		for end < len(schema.Fields) && schema.Fields[end].TableID == schema.Fields[start].TableID {
+			end++
+		}
+		if err := collaborationService.NotifyFieldsCreated(projectID, schema.Fields[start].TableID, schema.Fields[start:end], userID); err != nil {
+			return err
+		}
+		start = end
+	}
+	for _, relationship := range schema.Relationships {
+		if err := collaborationService.NotifyRelationshipCreated(projectID, relationship, userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newSchema checks the tables and fields of req and builds them. IDs are
+// generated up front so relationships can refer to fields not saved yet.
+// Table names must be unique within the request, and field names within their table.
+func newSchema(projectID uuid.UUID, req *dto.CreateSchemaRequest) (*Schema, error) {
+	if len(req.Tables) < 1 || len(req.Tables) > maxSchemaTables || len(req.Relationships) > maxSchemaRelationships {
+		return nil, ErrInvalidInput
+	}
+
+	schema := &Schema{Tables: make([]*models.Table, len(req.Tables))}
+	tableNames := make(map[string]bool, len(req.Tables))
+	for i, tableReq := range req.Tables {
+		name := strings.TrimSpace(tableReq.Name)
+		if len(name) < 1 || len(name) > 255 || tableNames[name] || len(tableReq.Fields) > maxBulkFields {
+			return nil, ErrInvalidInput
+		}
+		tableNames[name] = true
+		metadata, err := newMetadata(tableReq.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		table := &models.Table{
+			ID:        uuid.New(),
+			ProjectID: projectID,
+			Name:      name,
+			PosX:      tableReq.PosX,
+			PosY:      tableReq.PosY,
+			Color:     tableReq.Color,
+			Icon:      tableReq.Icon,
+			Metadata:  metadata,
+			Fields:    make([]models.Field, len(tableReq.Fields)),
+		}
+		fieldNames := make(map[string]bool, len(tableReq.Fields))
+		for j, fieldReq := range tableReq.Fields {
+			fieldName := strings.TrimSpace(fieldReq.Name)
+			dataType := strings.TrimSpace(fieldReq.DataType)
+			if len(fieldName) < 1 || len(fieldName) > 255 || fieldNames[strings.ToLower(fieldName)] {
+				return nil, ErrInvalidInput
+			}
+			if err := validateFieldType(dataType, fieldReq.DefaultValue); err != nil {
+				return nil, err
+			}
+			fieldNames[strings.ToLower(fieldName)] = true
+			fieldMetadata, err := newMetadata(fieldReq.Metadata)
+			if err != nil {
+				return nil, err
+			}
+
+			table.Fields[j] = models.Field{
+				ID:           uuid.New(),
+				TableID:      table.ID,
+				Name:         fieldName,
+				DataType:     dataType,
+				IsPrimaryKey: fieldReq.IsPrimaryKey,
+				IsNullable:   fieldReq.IsNullable,
+				DefaultValue: fieldReq.DefaultValue,
+				Position:     fieldReq.Position,
+				Metadata:     fieldMetadata,
+			}
+		}
+		schema.Tables[i] = table
+	}
+	return schema, nil
+}
+
+// resolveRelationships adds the relationships of reqs to schema, looking their
+// tables up by name in tables
+func resolveRelationships(schema *Schema, reqs []dto.SchemaRelationshipRequest, tables map[string]*models.Table) error {
+	schema.Relationships = make([]*models.Relationship, len(reqs))
+	for i, req := range reqs {
+		relationType := req.RelationType
+		switch relationType {
+		case "":
+			relationType = "one_to_many"
+		case "one_to_one", "one_to_many", "many_to_many":
+		default:
+			return ErrInvalidInput
+		}
+
+		sourceTable, sourceField, err := lookupField(tables, req.SourceTable, req.SourceField)
+		if err != nil {
+			return err
+		}
+		targetTable, targetField, err := lookupField(tables, req.TargetTable, req.TargetField)
+		if err != nil {
+			return err
+		}
+		metadata, err := newMetadata(req.Metadata)
+		if err != nil {
+			return err
+		}
+
+		schema.Relationships[i] = &models.Relationship{
+			ID:            uuid.New(),
+			ProjectID:     sourceTable.ProjectID,
+			SourceTableID: sourceTable.ID,
+			SourceFieldID: sourceField.ID,
+			TargetTableID: targetTable.ID,
+			TargetFieldID: targetField.ID,
+			RelationType:  relationType,
+			SourceAnchor:  models.AnchorAuto,
+			TargetAnchor:  models.AnchorAuto,
+			LabelPosition: models.DefaultLabelPosition,
+			Metadata:      metadata,
+		}
+	}
+	return nil
+}
+
+// resolveConflicts applies the resolutions to the tables of schema named like
+// existing ones, recording every conflict, and returns the tables that
+// relationships refer to by name: the schema's tables first, under their
+// names in the request, then the existing ones. Unresolved conflicts leave
+// the table as it is.
+func resolveConflicts(schema *Schema, resolutions []dto.SchemaConflictResolution, existing []*models.Table) (map[string]*models.Table, error) {
+	tables := make(map[string]*models.Table, len(schema.Tables)+len(existing))
+	taken := make(map[string]bool, len(schema.Tables)+len(existing))
+	for _, table := range existing {
+		tables[table.Name] = table
+		taken[table.Name] = true
+	}
+	for _, table := range schema.Tables {
+		taken[table.Name] = true
+	}
+
+	byTable := make(map[string]dto.SchemaConflictResolution, len(resolutions))
+	for _, resolution := range resolutions {
+		name := strings.TrimSpace(resolution.Table)
+		if _, ok := byTable[name]; ok || !slices.ContainsFunc(schema.Tables, func(t *models.Table) bool { return t.Name == name }) {
+			return nil, ErrInvalidInput
+		}
+		byTable[name] = resolution
+	}
+
+	created := make([]*models.Table, 0, len(schema.Tables))
+	for _, table := range schema.Tables {
+		name := table.Name
+		target := table
+		existingTable := findTable(existing, name)
+		if existingTable == nil {
+			created = append(created, table)
+			tables[name] = target
+			continue
+		}
+
+		// Resolutions of tables that no longer conflict are moot
+		resolution := byTable[name]
+		conflict := SchemaConflict{Table: name, ExistingTableID: existingTable.ID, Strategy: resolution.Strategy}
+		switch resolution.Strategy {
+		case "":
+			created = append(created, table)
+		case ConflictSkip:
+			target = existingTable
+		case ConflictRename:
+			newName := strings.TrimSpace(resolution.NewName)
+			if newName == "" {
+				newName = freeTableName(name, taken)
+			}
+			if len(newName) > 255 || taken[newName] {
+				return nil, ErrInvalidInput
+			}
+			taken[newName] = true
+			table.Name = newName
+			conflict.NewName = newName
+			tables[newName] = table
+			created = append(created, table)
+		case ConflictOverwrite:
+			schema.replaced = append(schema.replaced, existingTable)
+			created = append(created, table)
+		case ConflictMerge:
+			schema.Fields = append(schema.Fields, mergeFields(existingTable, table.Fields)...)
+			target = existingTable
+		default:
+			return nil, ErrInvalidInput
+		}
+		tables[name] = target
+		schema.Conflicts = append(schema.Conflicts, conflict)
+	}
+	schema.Tables = created
+	return tables, nil
+}
+
+// mergeFields adds the fields existing lacks by name, ignoring case, to it,
+// after its own, and returns them. Fields it has already are kept as they are.
+func mergeFields(existing *models.Table, fields []models.Field) []*models.Field {
+	position := -1
+	for _, field := range existing.Fields {
+		position = max(position, field.Position)
+	}
+
+	var added []models.Field
+	for _, field := range fields {
+		if fieldNameTaken(existing.Fields, field.Name, uuid.Nil) {
+			continue
+		}
+		position++
+		field.TableID = existing.ID
+		field.Position = position
+		added = append(added, field)
+	}
+	existing.Fields = append(existing.Fields, added...)
+	return fieldPointers(existing.Fields[len(existing.Fields)-len(added):])
+}
+
+// freeTableName returns name_2, name_3, ..., the first of them not taken
+func freeTableName(name string, taken map[string]bool) string {
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s_%d", name, i)
+		if !taken[candidate] {
+			return candidate
+		}
+	}
+}
+
+func findTable(tables []*models.Table, name string) *models.Table {
+	for _, table := range tables {
+		if table.Name == name {
+			return table
+		}
+	}
+	return nil
+}
+
+// lookupField finds a table by name and one of its fields by name
+func lookupField(tables map[string]*models.Table, tableName, fieldName string) (*models.Table, *models.Field, error) {
+	table, ok := tables[strings.TrimSpace(tableName)]
+	if !ok {
+		return nil, nil, ErrTableNotFound
+	}
+	fieldName = strings.TrimSpace(fieldName)
+	for i := range table.Fields {
+		if table.Fields[i].Name == fieldName {
+			return table, &table.Fields[i], nil
+		}
+	}
+	return nil, nil, ErrFieldNotFound
+}
+
+// fieldPointers points into fields, so what the repository fills in is kept
+func fieldPointers(fields []models.Field) []*models.Field {
+	pointers := make([]*models.Field, len(fields))
+	for i := range fields {
+		pointers[i] = &fields[i]
+	}
+	return pointers
+}
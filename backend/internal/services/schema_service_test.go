@@ -0,0 +1,1162 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/apply"
+	"github.com/Bug-Bugger/ezmodel/internal/ddl"
+	"github.com/Bug-Bugger/ezmodel/internal/lint"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/Bug-Bugger/ezmodel/internal/spreadsheet"
+	"github.com/Bug-Bugger/ezmodel/internal/suggest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type SchemaServiceTestSuite struct {
+	suite.Suite
+	mockUnitOfWork    *mockRepo.MockUnitOfWork
+	mockProjectRepo   *mockRepo.MockProjectRepository
+	mockTableRepo     *mockRepo.MockTableRepository
+	mockFieldRepo     *mockRepo.MockFieldRepository
+	mockRelRepo       *mockRepo.MockRelationshipRepository
+	mockRenameRepo    *mockRepo.MockRenameRepository
+	mockSnippetRepo   *mockRepo.MockSnippetRepository
+	mockReleaseRepo   *mockRepo.MockReleaseRepository
+	mockSnapshotRepo  *mockRepo.MockSnapshotRepository
+	mockAuthService   *mockAuthorizationService
+	mockCollabService *mockCollaborationService
+	service           *SchemaService
+}
+
+func (suite *SchemaServiceTestSuite) SetupTest() {
+	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
+	suite.mockTableRepo = new(mockRepo.MockTableRepository)
+	suite.mockFieldRepo = new(mockRepo.MockFieldRepository)
+	suite.mockRelRepo = new(mockRepo.MockRelationshipRepository)
+	suite.mockRenameRepo = new(mockRepo.MockRenameRepository)
+	suite.mockSnippetRepo = new(mockRepo.MockSnippetRepository)
+	suite.mockReleaseRepo = new(mockRepo.MockReleaseRepository)
+	suite.mockSnapshotRepo = new(mockRepo.MockSnapshotRepository)
+	suite.mockUnitOfWork = &mockRepo.MockUnitOfWork{Repos: repository.Repositories{
+		Projects:      suite.mockProjectRepo,
+		Tables:        suite.mockTableRepo,
+		Fields:        suite.mockFieldRepo,
+		Relationships: suite.mockRelRepo,
+		Renames:       suite.mockRenameRepo,
+		Snippets:      suite.mockSnippetRepo,
+		Releases:      suite.mockReleaseRepo,
+		Snapshots:     suite.mockSnapshotRepo,
+	}}
+	suite.mockAuthService = new(mockAuthorizationService)
+	suite.mockCollabService = new(mockCollaborationService)
+	suite.service = NewSchemaService(NewUnitOfWork(suite.mockUnitOfWork), suite.mockAuthService, suite.mockCollabService, Quotas{})
+}
+
+func TestSchemaServiceSuite(t *testing.T) {
+	suite.Run(t, new(SchemaServiceTestSuite))
+}
+
+func createTestSchemaRequest() *dto.CreateSchemaRequest {
+	return &dto.CreateSchemaRequest{
+		Tables: []dto.SchemaTableRequest{
+			{Name: "users", Fields: []dto.CreateFieldRequest{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+			}},
+			{Name: "posts", PosX: 300, Fields: []dto.CreateFieldRequest{
+				{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+				{Name: "author_id", DataType: "UUID", Position: 1},
+			}},
+		},
+		Relationships: []dto.SchemaRelationshipRequest{
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id"},
+		},
+	}
+}
+
+// Test CreateSchema - Success
+func (suite *SchemaServiceTestSuite) TestCreateSchema_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{}, nil)
+	suite.mockTableRepo.On("Create", mock.MatchedBy(func(table *models.Table) bool {
+		return table.ProjectID == projectID && table.Fields == nil
+	})).Return(uuid.New(), nil).Twice()
+	suite.mockFieldRepo.On("CreateBatch", mock.AnythingOfType("[]*models.Field")).Return(nil).Twice()
+	suite.mockRelRepo.On("Create", mock.AnythingOfType("*models.Relationship")).Return(uuid.New(), nil).Once()
+	suite.mockCollabService.On("NotifyTableCreated", projectID, mock.AnythingOfType("*models.Table"), userID).Return(nil).Twice()
+	suite.mockCollabService.On("NotifyFieldsCreated", projectID, mock.Anything, mock.AnythingOfType("[]*models.Field"), userID).Return(nil).Twice()
+	suite.mockCollabService.On("NotifyRelationshipCreated", projectID, mock.AnythingOfType("*models.Relationship"), userID).Return(nil).Once()
+
+	schema, err := suite.service.CreateSchema(projectID, createTestSchemaRequest(), userID)
+
+	suite.NoError(err)
+	suite.Len(schema.Tables, 2)
+	users, posts := schema.Tables[0], schema.Tables[1]
+	suite.Len(posts.Fields, 2)
+	suite.Equal(posts.ID, posts.Fields[1].TableID)
+
+	suite.Len(schema.Relationships, 1)
+	relationship := schema.Relationships[0]
+	suite.Equal(users.ID, relationship.SourceTableID)
+	suite.Equal(users.Fields[0].ID, relationship.SourceFieldID)
+	suite.Equal(posts.ID, relationship.TargetTableID)
+	suite.Equal(posts.Fields[1].ID, relationship.TargetFieldID)
+	suite.Equal("one_to_many", relationship.RelationType)
+
+	suite.mockTableRepo.AssertExpectations(suite.T())
+	suite.mockFieldRepo.AssertExpectations(suite.T())
+	suite.mockRelRepo.AssertExpectations(suite.T())
+	suite.mockCollabService.AssertExpectations(suite.T())
+}
+
+// Test CreateSchema - Relationship to an existing table
+func (suite *SchemaServiceTestSuite) TestCreateSchema_ExistingTable() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	existing := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "accounts",
+		Fields: []models.Field{{ID: uuid.New(), Name: "id"}}}
+	req := &dto.CreateSchemaRequest{
+		Tables: []dto.SchemaTableRequest{{Name: "orders", Fields: []dto.CreateFieldRequest{{Name: "account_id", DataType: "UUID"}}}},
+		Relationships: []dto.SchemaRelationshipRequest{
+			{SourceTable: "accounts", SourceField: "id", TargetTable: "orders", TargetField: "account_id", RelationType: "one_to_one"},
+		},
+	}
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{existing}, nil)
+	suite.mockTableRepo.On("Create", mock.AnythingOfType("*models.Table")).Return(uuid.New(), nil)
+	suite.mockFieldRepo.On("CreateBatch", mock.AnythingOfType("[]*models.Field")).Return(nil)
+	suite.mockRelRepo.On("Create", mock.MatchedBy(func(relationship *models.Relationship) bool {
+		return relationship.SourceTableID == existing.ID && relationship.SourceFieldID == existing.Fields[0].ID &&
+			relationship.RelationType == "one_to_one"
+	})).Return(uuid.New(), nil).Once()
+	suite.mockCollabService.On("NotifyTableCreated", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.mockCollabService.On("NotifyFieldsCreated", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.mockCollabService.On("NotifyRelationshipCreated", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	_, err := suite.service.CreateSchema(projectID, req, userID)
+
+	suite.NoError(err)
+	suite.mockRelRepo.AssertExpectations(suite.T())
+}
+
+// Test CreateSchema - A failing insert notifies nobody
+func (suite *SchemaServiceTestSuite) TestCreateSchema_FailureNotifiesNobody() {
+	projectID := uuid.New()
+	userID := uuid.New()
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{}, nil)
+	suite.mockTableRepo.On("Create", mock.AnythingOfType("*models.Table")).Return(uuid.New(), nil)
+	suite.mockFieldRepo.On("CreateBatch", mock.AnythingOfType("[]*models.Field")).Return(nil)
+	suite.mockRelRepo.On("Create", mock.AnythingOfType("*models.Relationship")).Return(uuid.Nil, gorm.ErrInvalidDB)
+
+	schema, err := suite.service.CreateSchema(projectID, createTestSchemaRequest(), userID)
+
+	suite.ErrorIs(err, gorm.ErrInvalidDB)
+	suite.Nil(schema)
+	suite.mockCollabService.AssertNotCalled(suite.T(), "NotifyTableCreated", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockCollabService.AssertNotCalled(suite.T(), "NotifyFieldsCreated", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *SchemaServiceTestSuite) TestCreateSchema_QuotaExceeded() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.service.quotas = Quotas{MaxTablesPerProject: 2}
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{{ID: uuid.New(), Name: "accounts"}}, nil)
+
+	schema, err := suite.service.CreateSchema(projectID, createTestSchemaRequest(), userID)
+
+	suite.Equal(&QuotaExceededError{Quota: QuotaTables, Limit: 2}, err)
+	suite.Nil(schema)
+	suite.mockTableRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test CreateSchema - Unknown field in a relationship
+func (suite *SchemaServiceTestSuite) TestCreateSchema_UnknownField() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	req := createTestSchemaRequest()
+	req.Relationships[0].TargetField = "missing"
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{}, nil)
+
+	schema, err := suite.service.CreateSchema(projectID, req, userID)
+
+	suite.ErrorIs(err, ErrFieldNotFound)
+	suite.Nil(schema)
+	suite.mockTableRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test CreateSchema - Duplicate table names
+func (suite *SchemaServiceTestSuite) TestCreateSchema_DuplicateTable() {
+	req := createTestSchemaRequest()
+	req.Tables[1].Name = " users "
+
+	schema, err := suite.service.CreateSchema(uuid.New(), req, uuid.New())
+
+	suite.ErrorIs(err, ErrInvalidInput)
+	suite.Nil(schema)
+	suite.mockUnitOfWork.AssertNotCalled(suite.T(), "Do")
+}
+
+// Test CreateSchema - Field names of a table differing only in case
+func (suite *SchemaServiceTestSuite) TestCreateSchema_DuplicateField() {
+	req := createTestSchemaRequest()
+	req.Tables[0].Fields = append(req.Tables[0].Fields, dto.CreateFieldRequest{Name: strings.ToUpper(req.Tables[0].Fields[0].Name), DataType: "TEXT", Position: 1})
+
+	schema, err := suite.service.CreateSchema(uuid.New(), req, uuid.New())
+
+	suite.ErrorIs(err, ErrInvalidInput)
+	suite.Nil(schema)
+	suite.mockUnitOfWork.AssertNotCalled(suite.T(), "Do")
+}
+
+// Test mergeFields - Fields the table has in another case are not added again
+func (suite *SchemaServiceTestSuite) TestMergeFields_IgnoresCase() {
+	users := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id", Position: 3}}}
+
+	added := mergeFields(users, []models.Field{{Name: "ID"}, {Name: "Email"}})
+
+	suite.Require().Len(added, 1)
+	suite.Equal("Email", added[0].Name)
+	suite.Equal(4, added[0].Position)
+	suite.Len(users.Fields, 2)
+}
+
+// Test CreateSchema - Forbidden
+func (suite *SchemaServiceTestSuite) TestCreateSchema_Forbidden() {
+	projectID := uuid.New()
+	userID := uuid.New()
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(false, nil)
+
+	schema, err := suite.service.CreateSchema(projectID, createTestSchemaRequest(), userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(schema)
+	suite.mockUnitOfWork.AssertNotCalled(suite.T(), "Do")
+}
+
+// Test CreateSchema - Tables named like existing ones need a strategy
+func (suite *SchemaServiceTestSuite) TestCreateSchema_UnresolvedConflict() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	existing := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "users"}
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{existing}, nil)
+
+	schema, err := suite.service.CreateSchema(projectID, createTestSchemaRequest(), userID)
+
+	suite.ErrorIs(err, ErrSchemaConflict)
+	var conflictErr *SchemaConflictError
+	suite.Require().ErrorAs(err, &conflictErr)
+	suite.Equal([]SchemaConflict{{Table: "users", ExistingTableID: existing.ID}}, conflictErr.Conflicts)
+	suite.Nil(schema)
+	suite.mockTableRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test CreateSchema - Merged fields go to the existing table and renamed tables keep their request name for relationships
+func (suite *SchemaServiceTestSuite) TestCreateSchema_MergeAndRename() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "users",
+		Fields: []models.Field{{ID: uuid.New(), Name: "id", Position: 0}}}
+	posts := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "posts"}
+	req := createTestSchemaRequest()
+	req.Tables[0].Fields = append(req.Tables[0].Fields, dto.CreateFieldRequest{Name: "email", DataType: "TEXT", Position: 1})
+	req.Conflicts = []dto.SchemaConflictResolution{
+		{Table: "users", Strategy: ConflictMerge},
+		{Table: "posts", Strategy: ConflictRename},
+	}
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users, posts}, nil)
+	suite.mockTableRepo.On("Create", mock.MatchedBy(func(table *models.Table) bool { return table.Name == "posts_2" })).Return(uuid.New(), nil).Once()
+	suite.mockFieldRepo.On("CreateBatch", mock.AnythingOfType("[]*models.Field")).Return(nil).Twice()
+	suite.mockRelRepo.On("Create", mock.AnythingOfType("*models.Relationship")).Return(uuid.New(), nil).Once()
+	suite.mockCollabService.On("NotifyTableCreated", projectID, mock.AnythingOfType("*models.Table"), userID).Return(nil).Once()
+	suite.mockCollabService.On("NotifyFieldsCreated", projectID, mock.Anything, mock.AnythingOfType("[]*models.Field"), userID).Return(nil).Twice()
+	suite.mockCollabService.On("NotifyRelationshipCreated", projectID, mock.AnythingOfType("*models.Relationship"), userID).Return(nil).Once()
+
+	schema, err := suite.service.CreateSchema(projectID, req, userID)
+
+	suite.NoError(err)
+	suite.Require().Len(schema.Tables, 1)
+	renamed := schema.Tables[0]
+	suite.Equal("posts_2", renamed.Name)
+
+	suite.Require().Len(schema.Fields, 1)
+	suite.Equal("email", schema.Fields[0].Name)
+	suite.Equal(users.ID, schema.Fields[0].TableID)
+	suite.Equal(1, schema.Fields[0].Position)
+
+	relationship := schema.Relationships[0]
+	suite.Equal(users.Fields[0].ID, relationship.SourceFieldID)
+	suite.Equal(renamed.ID, relationship.TargetTableID)
+
+	suite.Equal([]SchemaConflict{
+		{Table: "users", ExistingTableID: users.ID, Strategy: ConflictMerge},
+		{Table: "posts", ExistingTableID: posts.ID, Strategy: ConflictRename, NewName: "posts_2"},
+	}, schema.Conflicts)
+	suite.mockCollabService.AssertExpectations(suite.T())
+}
+
+// Test CreateSchema - Overwriting replaces the existing table and its relationships
+func (suite *SchemaServiceTestSuite) TestCreateSchema_Overwrite() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "users"}
+	oldRelationship := &models.Relationship{ID: uuid.New(), SourceTableID: users.ID}
+	req := createTestSchemaRequest()
+	req.Conflicts = []dto.SchemaConflictResolution{{Table: "users", Strategy: ConflictOverwrite}}
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users}, nil)
+	suite.mockRelRepo.On("GetByTableID", users.ID).Return([]*models.Relationship{oldRelationship}, nil)
+	suite.mockRelRepo.On("Delete", oldRelationship.ID).Return(nil).Once()
+	suite.mockTableRepo.On("Delete", users.ID).Return(nil).Once()
+	suite.mockTableRepo.On("Create", mock.AnythingOfType("*models.Table")).Return(uuid.New(), nil).Twice()
+	suite.mockFieldRepo.On("CreateBatch", mock.AnythingOfType("[]*models.Field")).Return(nil).Twice()
+	suite.mockRelRepo.On("Create", mock.AnythingOfType("*models.Relationship")).Return(uuid.New(), nil).Once()
+	suite.mockCollabService.On("NotifyRelationshipDeleted", projectID, oldRelationship.ID, userID).Return(nil).Once()
+	suite.mockCollabService.On("NotifyTableDeleted", projectID, users.ID, "users", userID).Return(nil).Once()
+	suite.mockCollabService.On("NotifyTableCreated", projectID, mock.AnythingOfType("*models.Table"), userID).Return(nil).Twice()
+	suite.mockCollabService.On("NotifyFieldsCreated", projectID, mock.Anything, mock.AnythingOfType("[]*models.Field"), userID).Return(nil).Twice()
+	suite.mockCollabService.On("NotifyRelationshipCreated", projectID, mock.AnythingOfType("*models.Relationship"), userID).Return(nil).Once()
+
+	schema, err := suite.service.CreateSchema(projectID, req, userID)
+
+	suite.NoError(err)
+	suite.Len(schema.Tables, 2)
+	suite.Equal(schema.Tables[0].ID, schema.Relationships[0].SourceTableID)
+	suite.mockRelRepo.AssertExpectations(suite.T())
+	suite.mockTableRepo.AssertExpectations(suite.T())
+	suite.mockCollabService.AssertExpectations(suite.T())
+}
+
+// Test CreateSchema - Skipped tables are not created and relationships refer to the existing one
+func (suite *SchemaServiceTestSuite) TestCreateSchema_Skip() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "users",
+		Fields: []models.Field{{ID: uuid.New(), Name: "id"}}}
+	req := createTestSchemaRequest()
+	req.Conflicts = []dto.SchemaConflictResolution{{Table: "users", Strategy: ConflictSkip}}
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users}, nil)
+
+	schema, err := suite.service.PlanSchema(projectID, req, userID)
+
+	suite.NoError(err)
+	suite.Require().Len(schema.Tables, 1)
+	suite.Equal("posts", schema.Tables[0].Name)
+	suite.Equal(users.ID, schema.Relationships[0].SourceTableID)
+	suite.Equal(users.Fields[0].ID, schema.Relationships[0].SourceFieldID)
+}
+
+// Test CreateSchema - Invalid resolutions
+func (suite *SchemaServiceTestSuite) TestCreateSchema_InvalidResolution() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	existing := []*models.Table{{ID: uuid.New(), Name: "users"}, {ID: uuid.New(), Name: "accounts"}}
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return(existing, nil)
+
+	tests := map[string]dto.SchemaConflictResolution{
+		"unknown table":  {Table: "orders", Strategy: ConflictSkip},
+		"taken new name": {Table: "users", Strategy: ConflictRename, NewName: "accounts"},
+	}
+	for name, resolution := range tests {
+		req := createTestSchemaRequest()
+		req.Conflicts = []dto.SchemaConflictResolution{resolution}
+
+		_, err := suite.service.CreateSchema(projectID, req, userID)
+
+		suite.ErrorIs(err, ErrInvalidInput, name)
+	}
+}
+
+// Test PlanSchema - Everything is checked, nothing saved, and existing names reported
+func (suite *SchemaServiceTestSuite) TestPlanSchema_SavesNothing() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	existing := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "users"}
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{existing}, nil)
+
+	schema, err := suite.service.PlanSchema(projectID, createTestSchemaRequest(), userID)
+
+	suite.NoError(err)
+	suite.Len(schema.Tables, 2)
+	suite.Len(schema.Relationships, 1)
+	suite.Equal([]SchemaConflict{{Table: "users", ExistingTableID: existing.ID}}, schema.Conflicts)
+	suite.Zero(schema.Sequence)
+	suite.mockTableRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+	suite.mockFieldRepo.AssertNotCalled(suite.T(), "CreateBatch", mock.Anything)
+	suite.mockRelRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+	suite.mockCollabService.AssertNotCalled(suite.T(), "NotifyTableCreated", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test PlanSchema - Fails like CreateSchema would
+func (suite *SchemaServiceTestSuite) TestPlanSchema_UnknownField() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	req := createTestSchemaRequest()
+	req.Relationships[0].TargetField = "missing"
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{}, nil)
+
+	schema, err := suite.service.PlanSchema(projectID, req, userID)
+
+	suite.ErrorIs(err, ErrFieldNotFound)
+	suite.Nil(schema)
+}
+
+// Test ImportSpreadsheet - Each table of the file is created, laid out on a grid
+func (suite *SchemaServiceTestSuite) TestImportSpreadsheet_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	data := "table,name,type,pk,default\n" +
+		",id,uuid,yes,\n" +
+		",status,text,,'new'\n" +
+		"items,id,bigint,yes,\n"
+
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{}, nil)
+	suite.mockTableRepo.On("Create", mock.AnythingOfType("*models.Table")).Return(uuid.New(), nil).Twice()
+	suite.mockFieldRepo.On("CreateBatch", mock.AnythingOfType("[]*models.Field")).Return(nil).Twice()
+	suite.mockCollabService.On("NotifyTableCreated", projectID, mock.AnythingOfType("*models.Table"), userID).Return(nil).Twice()
+	suite.mockCollabService.On("NotifyFieldsCreated", projectID, mock.Anything, mock.AnythingOfType("[]*models.Field"), userID).Return(nil).Twice()
+
+	schema, err := suite.service.ImportSpreadsheet(projectID, "orders.csv", []byte(data), nil, userID)
+
+	suite.NoError(err)
+	suite.Require().Len(schema.Tables, 2)
+	orders, items := schema.Tables[0], schema.Tables[1]
+	suite.Equal("orders", orders.Name)
+	suite.Equal("items", items.Name)
+	suite.Equal(float64(importCellWidth), items.PosX)
+	suite.Require().Len(orders.Fields, 2)
+	suite.True(orders.Fields[0].IsPrimaryKey)
+	suite.False(orders.Fields[0].IsNullable)
+	suite.Equal("'new'", orders.Fields[1].DefaultValue)
+	suite.Equal(1, orders.Fields[1].Position)
+	suite.Empty(schema.Relationships)
+}
+
+// Test ImportSpreadsheet - Problems with the file are reported before anything is checked or saved
+func (suite *SchemaServiceTestSuite) TestImportSpreadsheet_InvalidFile() {
+	schema, err := suite.service.ImportSpreadsheet(uuid.New(), "orders.csv", []byte("name,type\nid,\n"), nil, uuid.New())
+
+	var spreadsheetErr *spreadsheet.ValidationError
+	suite.ErrorAs(err, &spreadsheetErr)
+	suite.Equal(map[string]string{"orders!B2": "Data type is required"}, spreadsheetErr.Errors)
+	suite.Nil(schema)
+	suite.mockAuthService.AssertNotCalled(suite.T(), "CanUserModifyProject", mock.Anything, mock.Anything)
+}
+
+// Test InferSchema - Collections are proposed as tables without saving anything
+func (suite *SchemaServiceTestSuite) TestInferSchema_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	req := &dto.InferSchemaRequest{Collections: []dto.InferCollectionRequest{
+		{Name: "users", MongooseSchema: json.RawMessage(`{"email": {"type": "String", "required": true}}`)},
+		{Name: "posts", Documents: []json.RawMessage{json.RawMessage(`{"_id": {"$oid": "64b7f0c2a1b2c3d4e5f60718"}, "userId": {"$oid": "64b7f0c2a1b2c3d4e5f60719"}}`)}},
+	}}
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+
+	proposal, err := suite.service.InferSchema(projectID, req, userID)
+
+	suite.NoError(err)
+	suite.Equal(dto.CreateSchemaRequest{
+		Tables: []dto.SchemaTableRequest{
+			{Name: "users", Fields: []dto.CreateFieldRequest{
+				{Name: "id", DataType: "STRING", IsPrimaryKey: true},
+				{Name: "email", DataType: "STRING", Position: 1},
+			}},
+			{Name: "posts", PosX: importCellWidth, Fields: []dto.CreateFieldRequest{
+				{Name: "id", DataType: "STRING", IsPrimaryKey: true},
+				{Name: "user_id", DataType: "STRING", Position: 1},
+			}},
+		},
+		Relationships: []dto.SchemaRelationshipRequest{
+			{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "user_id", RelationType: "one_to_many"},
+		},
+	}, proposal.Schema)
+	suite.Empty(proposal.Notes)
+	suite.mockUnitOfWork.AssertNotCalled(suite.T(), "Do")
+}
+
+// Test InferSchema - Forbidden
+func (suite *SchemaServiceTestSuite) TestInferSchema_Forbidden() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(false, nil)
+
+	proposal, err := suite.service.InferSchema(projectID, &dto.InferSchemaRequest{}, userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(proposal)
+}
+
+// Test CompareProjects - Relationships are compared by the names of what they link
+func (suite *SchemaServiceTestSuite) TestCompareProjects_Success() {
+	projectID := uuid.New()
+	otherID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "UUID"}}}
+	posts := &models.Table{ID: uuid.New(), Name: "posts", Fields: []models.Field{{ID: uuid.New(), Name: "author_id", DataType: "UUID"}}}
+	otherUsers := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "BIGINT"}}}
+	otherPosts := &models.Table{ID: uuid.New(), Name: "posts", Fields: []models.Field{{ID: uuid.New(), Name: "author_id", DataType: "UUID"}}}
+
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockAuthService.On("CanUserAccessProject", userID, otherID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users, posts}, nil)
+	suite.mockTableRepo.On("GetByProjectID", otherID).Return([]*models.Table{otherUsers, otherPosts}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{{
+		SourceTableID: users.ID, SourceFieldID: users.Fields[0].ID, TargetTableID: posts.ID, TargetFieldID: posts.Fields[0].ID, RelationType: "one_to_many",
+	}}, nil)
+	suite.mockRelRepo.On("GetByProjectID", otherID).Return([]*models.Relationship{
+		{SourceTableID: otherUsers.ID, SourceFieldID: otherUsers.Fields[0].ID, TargetTableID: otherPosts.ID, TargetFieldID: otherPosts.Fields[0].ID, RelationType: "one_to_many"},
+		{SourceTableID: uuid.New(), SourceFieldID: uuid.New(), TargetTableID: otherPosts.ID, TargetFieldID: otherPosts.Fields[0].ID}, // Links a deleted table
+	}, nil)
+
+	diff, err := suite.service.CompareProjects(projectID, otherID, userID)
+
+	suite.NoError(err)
+	suite.Empty(diff.AddedRelationships)
+	suite.Empty(diff.RemovedRelationships)
+	suite.Require().Len(diff.ChangedTables, 1)
+	suite.Equal("users", diff.ChangedTables[0].Name)
+	suite.Equal([]schemadiff.Change{{Attribute: schemadiff.AttributeDataType, From: "UUID", To: "BIGINT"}}, diff.ChangedTables[0].ChangedFields[0].Changes)
+}
+
+// Test CompareProjects - Both projects must be accessible
+func (suite *SchemaServiceTestSuite) TestCompareProjects_Forbidden() {
+	projectID := uuid.New()
+	otherID := uuid.New()
+	userID := uuid.New()
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockAuthService.On("CanUserAccessProject", userID, otherID).Return(false, nil)
+
+	diff, err := suite.service.CompareProjects(projectID, otherID, userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(diff)
+	suite.mockUnitOfWork.AssertNotCalled(suite.T(), "Do")
+}
+
+// Test GenerateData - CSV files are numbered in the order they load in
+func (suite *SchemaServiceTestSuite) TestGenerateData_CSV() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true}}}
+	posts := &models.Table{ID: uuid.New(), Name: "posts", Fields: []models.Field{
+		{ID: uuid.New(), Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+		{ID: uuid.New(), Name: "author_id", DataType: "UUID"},
+	}}
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{posts, users}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{{
+		SourceTableID: users.ID, SourceFieldID: users.Fields[0].ID, TargetTableID: posts.ID, TargetFieldID: posts.Fields[1].ID, RelationType: "one_to_many",
+	}}, nil)
+	seed := uint64(3)
+
+	data, err := suite.service.GenerateData(projectID, &dto.GenerateDataRequest{Rows: 4, Format: DataFormatCSV, Seed: &seed}, userID)
+
+	suite.Require().NoError(err)
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	suite.Require().NoError(err)
+	suite.Require().Len(archive.File, 2)
+	suite.Equal("01_users.csv", archive.File[0].Name)
+	suite.Equal("02_posts.csv", archive.File[1].Name)
+	file, err := archive.File[1].Open()
+	suite.Require().NoError(err)
+	records, err := csv.NewReader(file).ReadAll()
+	suite.Require().NoError(err)
+	suite.Len(records, 5)
+	suite.Equal([]string{"id", "author_id"}, records[0])
+
+	again, err := suite.service.GenerateData(projectID, &dto.GenerateDataRequest{Rows: 4, Format: DataFormatCSV, Seed: &seed}, userID)
+	suite.NoError(err)
+	suite.Equal(data, again)
+}
+
+// Test GenerateData - Viewers need access to the project
+func (suite *SchemaServiceTestSuite) TestGenerateData_Forbidden() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(false, nil)
+
+	data, err := suite.service.GenerateData(projectID, &dto.GenerateDataRequest{}, userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(data)
+	suite.mockUnitOfWork.AssertNotCalled(suite.T(), "Do")
+}
+
+// Test ExportSchema - SQL is written in the dialect of the project
+func (suite *SchemaServiceTestSuite) TestExportSchema_SQL() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "INT", IsPrimaryKey: true}}}
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID, DatabaseType: "mysql"}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{}, nil)
+	suite.mockSnippetRepo.On("GetByProjectID", projectID).Return([]*models.Snippet{}, nil)
+
+	export, err := suite.service.ExportSchema(projectID, ExportFormatSQL, userID)
+
+	suite.Require().NoError(err)
+	suite.Equal("schema.sql", export.Filename)
+	suite.Equal("CREATE TABLE `users` (\n  `id` INT NOT NULL,\n  PRIMARY KEY (`id`)\n);\n", string(export.Data))
+}
+
+// Test ExportSchema - Snippets follow the statements as an appendix
+func (suite *SchemaServiceTestSuite) TestExportSchema_SQLWithSnippets() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "INT", IsPrimaryKey: true}}}
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID, DatabaseType: "mysql"}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{}, nil)
+	suite.mockSnippetRepo.On("GetByProjectID", projectID).Return([]*models.Snippet{
+		{Name: "Seed", Description: "Two users", SQL: "INSERT INTO users VALUES (1), (2);\n"},
+		{Name: "Count", SQL: "SELECT COUNT(*) FROM users;"},
+	}, nil)
+
+	export, err := suite.service.ExportSchema(projectID, ExportFormatSQL, userID)
+
+	suite.Require().NoError(err)
+	suite.Equal("CREATE TABLE `users` (\n  `id` INT NOT NULL,\n  PRIMARY KEY (`id`)\n);\n"+
+		"\n-- Appendix: snippets saved with the project\n"+
+		"\n-- Seed\n-- Two users\nINSERT INTO users VALUES (1), (2);\n"+
+		"\n-- Count\nSELECT COUNT(*) FROM users;\n", string(export.Data))
+}
+
+// Test ExportSchema - JSON is a request creating the schema again
+func (suite *SchemaServiceTestSuite) TestExportSchema_JSON() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := models.Table{ID: uuid.New(), Name: "users", PosX: 40, Color: "#ff0000", Metadata: models.Metadata{"data_owner": "crm"}, Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true, Position: 1}}}
+	posts := models.Table{ID: uuid.New(), Name: "posts", Fields: []models.Field{{ID: uuid.New(), Name: "author_id", DataType: "UUID", Position: 1}}}
+	project := &models.Project{ID: projectID, Tables: []models.Table{users, posts}, Relationships: []models.Relationship{{
+		SourceTableID: users.ID, SourceFieldID: users.Fields[0].ID, TargetTableID: posts.ID, TargetFieldID: posts.Fields[0].ID, RelationType: "one_to_many",
+	}}}
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(project, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{&users, &posts}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{}, nil)
+
+	export, err := suite.service.ExportSchema(projectID, ExportFormatJSON, userID)
+
+	suite.Require().NoError(err)
+	var req dto.CreateSchemaRequest
+	suite.Require().NoError(json.Unmarshal(export.Data, &req))
+	suite.Equal(dto.SchemaTableRequest{Name: "users", PosX: 40, Color: "#ff0000", Metadata: map[string]any{"data_owner": "crm"}, Fields: []dto.CreateFieldRequest{{Name: "id", DataType: "UUID", IsPrimaryKey: true, Position: 1}}}, req.Tables[0])
+	suite.Equal([]dto.SchemaRelationshipRequest{{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "author_id", RelationType: "one_to_many"}}, req.Relationships)
+}
+
+// Test ExportRelease - The schema is the release's snapshot, not the project as it is now
+func (suite *SchemaServiceTestSuite) TestExportRelease_Snapshot() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "INT", IsPrimaryKey: true}}}
+	snapshot, err := newSnapshot(&models.Project{ID: projectID, DatabaseType: "mysql", Tables: []models.Table{users}}, nil, time.Now())
+	suite.Require().NoError(err)
+	snapshot.ID = uuid.New()
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockReleaseRepo.On("GetByName", projectID, "v1.0").Return(&models.Release{ID: uuid.New(), ProjectID: projectID, Name: "v1.0", SnapshotID: snapshot.ID}, nil)
+	suite.mockSnapshotRepo.On("GetByID", snapshot.ID).Return(snapshot, nil)
+
+	export, err := suite.service.ExportRelease(projectID, "v1.0", ExportFormatSQL, userID)
+
+	suite.Require().NoError(err)
+	suite.Equal("CREATE TABLE `users` (\n  `id` INT NOT NULL,\n  PRIMARY KEY (`id`)\n);\n", string(export.Data))
+	suite.mockProjectRepo.AssertNotCalled(suite.T(), "GetByID", mock.Anything)
+	suite.mockSnippetRepo.AssertNotCalled(suite.T(), "GetByProjectID", mock.Anything)
+}
+
+// Test ExportRelease - Releases are looked up by name within the project
+func (suite *SchemaServiceTestSuite) TestExportRelease_NotFound() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockReleaseRepo.On("GetByName", projectID, "v9").Return(nil, gorm.ErrRecordNotFound)
+
+	export, err := suite.service.ExportRelease(projectID, "v9", ExportFormatJSON, userID)
+
+	suite.ErrorIs(err, ErrReleaseNotFound)
+	suite.Nil(export)
+}
+
+// Test ExportSchema - dbt is a zip of the source and staging models
+func (suite *SchemaServiceTestSuite) TestExportSchema_DBT() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true}}}
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID, Name: "Shop"}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{}, nil)
+	suite.mockSnippetRepo.On("GetByProjectID", projectID).Return([]*models.Snippet{{Name: "Active users", SQL: "select * from users"}}, nil)
+
+	export, err := suite.service.ExportSchema(projectID, ExportFormatDBT, userID)
+
+	suite.Require().NoError(err)
+	suite.Equal("shop_dbt.zip", export.Filename)
+	archive, err := zip.NewReader(bytes.NewReader(export.Data), int64(len(export.Data)))
+	suite.Require().NoError(err)
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	suite.Equal([]string{
+		"models/staging/shop/_shop__sources.yml",
+		"models/staging/shop/_shop__models.yml",
+		"models/staging/shop/stg_shop__users.sql",
+		"analyses/active_users.sql",
+	}, names)
+}
+
+// Test ExportSchema - Protobuf is a proto file named after the project
+func (suite *SchemaServiceTestSuite) TestExportSchema_Protobuf() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "BIGINT", IsPrimaryKey: true}}}
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID, Name: "Shop"}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{}, nil)
+
+	export, err := suite.service.ExportSchema(projectID, ExportFormatProtobuf, userID)
+
+	suite.Require().NoError(err)
+	suite.Equal("shop.proto", export.Filename)
+	suite.Contains(string(export.Data), "message Users {\n  int64 id = 1;\n}\n")
+	suite.mockSnippetRepo.AssertNotCalled(suite.T(), "GetByProjectID", mock.Anything)
+}
+
+// Test ExportSchema - Unknown formats are rejected before reading anything
+func (suite *SchemaServiceTestSuite) TestExportSchema_UnknownFormat() {
+	export, err := suite.service.ExportSchema(uuid.New(), "xml", uuid.New())
+
+	suite.ErrorIs(err, ErrUnknownExportFormat)
+	suite.Nil(export)
+	suite.mockUnitOfWork.AssertNotCalled(suite.T(), "Do")
+}
+
+// expectApplyModel has the project model a users table with an id and an email
+func (suite *SchemaServiceTestSuite) expectApplyModel(projectID, userID uuid.UUID, renames ...*models.SchemaRename) {
+	users := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{
+		{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true},
+		{ID: uuid.New(), Name: "email", DataType: "VARCHAR(255)"},
+	}}
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID, DatabaseType: "postgresql"}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{}, nil)
+	suite.mockRenameRepo.On("GetByProjectID", projectID).Return(append([]*models.SchemaRename{}, renames...), nil)
+}
+
+// liveDatabase stubs introspection with a database having the tables given
+func (suite *SchemaServiceTestSuite) liveDatabase(tables ...schemadiff.Table) {
+	suite.service.introspect = func(ctx context.Context, dsn, schema string) (schemadiff.Schema, error) {
+		suite.Equal("postgres://app@db.example.com/app", dsn)
+		suite.Equal("public", schema)
+		return schemadiff.Schema{Tables: tables}, nil
+	}
+}
+
+var liveUsers = schemadiff.Table{Name: "users", Fields: []schemadiff.Field{{Name: "id", DataType: "UUID", IsPrimaryKey: true}}}
+
+// Test PlanApply - The statements bringing the database to the model, as declared
+func (suite *SchemaServiceTestSuite) TestPlanApply() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.expectApplyModel(projectID, userID)
+	suite.liveDatabase(liveUsers)
+
+	plan, err := suite.service.PlanApply(projectID, &dto.PlanApplyRequest{DSN: "postgres://app@db.example.com/app"}, userID)
+
+	suite.Require().NoError(err)
+	suite.Equal([]ddl.Statement{{SQL: `ALTER TABLE "users" ADD COLUMN "email" VARCHAR(255) NOT NULL;`}}, plan.Statements)
+	suite.Len(plan.Checksum, 64)
+}
+
+// Test PlanApply - Tables and fields renamed in the model are renamed in the
+// database, where they have their old names
+func (suite *SchemaServiceTestSuite) TestPlanApply_Renames() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	fieldID := uuid.New()
+	suite.expectApplyModel(projectID, userID,
+		&models.SchemaRename{TableName: "people", OldName: "people", NewName: "users"},
+		&models.SchemaRename{TableName: "users", FieldID: &fieldID, OldName: "mail", NewName: "email"},
+	)
+	suite.liveDatabase(schemadiff.Table{Name: "people", Fields: []schemadiff.Field{
+		{Name: "id", DataType: "UUID", IsPrimaryKey: true},
+		{Name: "mail", DataType: "TEXT"},
+	}})
+
+	plan, err := suite.service.PlanApply(projectID, &dto.PlanApplyRequest{DSN: "postgres://app@db.example.com/app"}, userID)
+
+	suite.Require().NoError(err)
+	suite.Equal([]ddl.Statement{
+		{SQL: `ALTER TABLE "people" RENAME TO "users";`},
+		{SQL: `ALTER TABLE "users" RENAME COLUMN "mail" TO "email";`},
+		{SQL: `ALTER TABLE "users" ALTER COLUMN "email" TYPE VARCHAR(255) USING "email"::VARCHAR(255);`, Destructive: true},
+	}, plan.Statements)
+}
+
+// Test PlanApply - Only those who may modify the project may, before connecting
+func (suite *SchemaServiceTestSuite) TestPlanApply_Forbidden() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(false, nil)
+	suite.service.introspect = func(ctx context.Context, dsn, schema string) (schemadiff.Schema, error) {
+		suite.Fail("introspected")
+		return schemadiff.Schema{}, nil
+	}
+
+	plan, err := suite.service.PlanApply(projectID, &dto.PlanApplyRequest{DSN: "postgres://app@db.example.com/app"}, userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(plan)
+}
+
+// Test ExecuteApply - Runs the plan in the transaction mode asked for
+func (suite *SchemaServiceTestSuite) TestExecuteApply() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.expectApplyModel(projectID, userID)
+	suite.liveDatabase(liveUsers)
+	suite.service.execute = func(ctx context.Context, dsn string, statements []string, mode string) ([]apply.Result, error) {
+		suite.Equal(apply.ModePerStatement, mode)
+		return []apply.Result{{SQL: statements[0], Status: apply.StatusApplied}}, nil
+	}
+
+	plan, err := suite.service.PlanApply(projectID, &dto.PlanApplyRequest{DSN: "postgres://app@db.example.com/app"}, userID)
+	suite.Require().NoError(err)
+
+	applyLog, err := suite.service.ExecuteApply(projectID, &dto.ExecuteApplyRequest{DSN: "postgres://app@db.example.com/app", Transaction: apply.ModePerStatement, Checksum: plan.Checksum}, userID)
+
+	suite.Require().NoError(err)
+	suite.Equal(apply.ModePerStatement, applyLog.Transaction)
+	suite.Equal([]apply.Result{{SQL: `ALTER TABLE "users" ADD COLUMN "email" VARCHAR(255) NOT NULL;`, Status: apply.StatusApplied}}, applyLog.Results)
+}
+
+// Test ExecuteApply - Plans that drop data are refused unless allowed, and
+// plans other than the one reviewed, or none, always are
+func (suite *SchemaServiceTestSuite) TestExecuteApply_Refused() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.expectApplyModel(projectID, userID)
+	suite.liveDatabase(liveUsers, schemadiff.Table{Name: "audit", Fields: []schemadiff.Field{{Name: "id", DataType: "UUID"}}})
+	suite.service.execute = func(ctx context.Context, dsn string, statements []string, mode string) ([]apply.Result, error) {
+		suite.Fail("executed")
+		return nil, nil
+	}
+	plan, err := suite.service.PlanApply(projectID, &dto.PlanApplyRequest{DSN: "postgres://app@db.example.com/app"}, userID)
+	suite.Require().NoError(err)
+	req := &dto.ExecuteApplyRequest{DSN: "postgres://app@db.example.com/app", Checksum: plan.Checksum}
+
+	_, err = suite.service.ExecuteApply(projectID, req, userID)
+	suite.ErrorIs(err, ErrDestructiveChanges)
+
+	req.AllowDestructive = true
+	req.Checksum = strings.Repeat("0", 64)
+	_, err = suite.service.ExecuteApply(projectID, req, userID)
+	suite.ErrorIs(err, ErrApplyPlanChanged)
+
+	req.Checksum = ""
+	_, err = suite.service.ExecuteApply(projectID, req, userID)
+	suite.ErrorIs(err, ErrApplyPlanChanged)
+}
+
+// Test PlanApply - Fields saved before types and defaults were validated are
+// refused before any statement is written
+func (suite *SchemaServiceTestSuite) TestPlanApply_InvalidField() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{
+		{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true, DefaultValue: "0; DROP TABLE users"},
+	}}
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID, DatabaseType: "postgresql"}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{}, nil)
+	suite.liveDatabase(liveUsers)
+
+	plan, err := suite.service.PlanApply(projectID, &dto.PlanApplyRequest{DSN: "postgres://app@db.example.com/app"}, userID)
+
+	suite.ErrorIs(err, ddl.ErrInvalidDefaultValue)
+	suite.Nil(plan)
+}
+
+// Test LintSchema - The project's schema is checked as read
+func (suite *SchemaServiceTestSuite) TestLintSchema() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	contacts := &models.Table{ID: uuid.New(), Name: "contacts", Fields: []models.Field{
+		{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true},
+		{ID: uuid.New(), Name: "email1", DataType: "TEXT"},
+		{ID: uuid.New(), Name: "email2", DataType: "TEXT"},
+	}}
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{contacts}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{}, nil)
+
+	findings, err := suite.service.LintSchema(projectID, userID)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(findings, 1)
+	suite.Equal(lint.RuleRepeatingGroup, findings[0].Rule)
+	suite.Equal([]string{"email1", "email2"}, findings[0].Fields)
+}
+
+// Test LintSchema - Missing project
+func (suite *SchemaServiceTestSuite) TestLintSchema_ProjectNotFound() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(nil, gorm.ErrRecordNotFound)
+
+	findings, err := suite.service.LintSchema(projectID, userID)
+
+	suite.ErrorIs(err, ErrProjectNotFound)
+	suite.Nil(findings)
+}
+
+// Test SuggestRelationships - Suggestions carry the IDs of what they link,
+// and fields already related are left out
+func (suite *SchemaServiceTestSuite) TestSuggestRelationships() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true}}}
+	posts := &models.Table{ID: uuid.New(), Name: "posts", Fields: []models.Field{
+		{ID: uuid.New(), Name: "id", DataType: "BIGINT", IsPrimaryKey: true},
+		{ID: uuid.New(), Name: "user_id", DataType: "UUID"},
+		{ID: uuid.New(), Name: "editor_id", DataType: "UUID"},
+	}}
+	comments := &models.Table{ID: uuid.New(), Name: "comments", Fields: []models.Field{
+		{ID: uuid.New(), Name: "id", DataType: "BIGINT", IsPrimaryKey: true},
+		{ID: uuid.New(), Name: "postId", DataType: "INT8"},
+		{ID: uuid.New(), Name: "user_id", DataType: "UUID"},
+	}}
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users, posts, comments}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{{
+		SourceTableID: users.ID, SourceFieldID: users.Fields[0].ID, TargetTableID: comments.ID, TargetFieldID: comments.Fields[2].ID, RelationType: "one_to_many",
+	}}, nil)
+
+	suggestions, err := suite.service.SuggestRelationships(projectID, userID)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(suggestions, 2)
+	suite.Equal(RelationshipSuggestion{
+		Suggestion: suggest.Suggestion{
+			Relationship: schemadiff.Relationship{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "user_id", RelationType: "one_to_many"},
+			Reason:       "user_id is named after users and has the type of users.id",
+		},
+		SourceTableID: users.ID, SourceFieldID: users.Fields[0].ID, TargetTableID: posts.ID, TargetFieldID: posts.Fields[1].ID,
+	}, suggestions[0])
+	suite.Equal("comments", suggestions[1].TargetTable)
+	suite.Equal(comments.Fields[1].ID, suggestions[1].TargetFieldID)
+	suite.Equal(posts.Fields[0].ID, suggestions[1].SourceFieldID)
+}
+
+// Test SuggestRelationships - The user needs access to the project
+func (suite *SchemaServiceTestSuite) TestSuggestRelationships_Forbidden() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(false, nil)
+
+	suggestions, err := suite.service.SuggestRelationships(projectID, userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(suggestions)
+	suite.mockUnitOfWork.AssertNotCalled(suite.T(), "Do")
+}
+
+// Test AcceptRelationshipSuggestions - Relationships the project has already
+// are skipped, as are repeats within the request
+func (suite *SchemaServiceTestSuite) TestAcceptRelationshipSuggestions() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "UUID", IsPrimaryKey: true}}}
+	posts := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "posts", Fields: []models.Field{
+		{ID: uuid.New(), Name: "user_id", DataType: "UUID"},
+		{ID: uuid.New(), Name: "editor_id", DataType: "UUID"},
+	}}
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users, posts}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{{
+		SourceTableID: users.ID, SourceFieldID: users.Fields[0].ID, TargetTableID: posts.ID, TargetFieldID: posts.Fields[0].ID, RelationType: "one_to_many",
+	}}, nil)
+	suite.mockRelRepo.On("Create", mock.MatchedBy(func(relationship *models.Relationship) bool {
+		return relationship.TargetFieldID == posts.Fields[1].ID && relationship.ProjectID == projectID
+	})).Return(uuid.New(), nil).Once()
+	suite.mockCollabService.On("NotifyRelationshipCreated", projectID, mock.AnythingOfType("*models.Relationship"), userID).Return(nil).Once()
+	editor := dto.SchemaRelationshipRequest{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "editor_id"}
+
+	schema, err := suite.service.AcceptRelationshipSuggestions(projectID, []dto.SchemaRelationshipRequest{
+		{SourceTable: "users", SourceField: "id", TargetTable: "posts", TargetField: "user_id"},
+		editor,
+		editor,
+	}, userID)
+
+	suite.Require().NoError(err)
+	suite.Require().Len(schema.Relationships, 1)
+	suite.Equal(posts.Fields[1].ID, schema.Relationships[0].TargetFieldID)
+	suite.Equal("one_to_many", schema.Relationships[0].RelationType)
+	suite.mockRelRepo.AssertExpectations(suite.T())
+	suite.mockCollabService.AssertExpectations(suite.T())
+}
+
+// Test AcceptRelationshipSuggestions - Unknown fields create nothing
+func (suite *SchemaServiceTestSuite) TestAcceptRelationshipSuggestions_UnknownField() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "users", Fields: []models.Field{{ID: uuid.New(), Name: "id", DataType: "UUID"}}}
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockProjectRepo.On("GetByID", projectID).Return(&models.Project{ID: projectID}, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{users}, nil)
+	suite.mockRelRepo.On("GetByProjectID", projectID).Return([]*models.Relationship{}, nil)
+
+	schema, err := suite.service.AcceptRelationshipSuggestions(projectID, []dto.SchemaRelationshipRequest{
+		{SourceTable: "users", SourceField: "id", TargetTable: "users", TargetField: "manager_id"},
+	}, userID)
+
+	suite.ErrorIs(err, ErrFieldNotFound)
+	suite.Nil(schema)
+	suite.mockRelRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test Rename - A table is renamed and the rename recorded with its old name
+func (suite *SchemaServiceTestSuite) TestRename_Table() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	people := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "people"}
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockTableRepo.On("GetByID", people.ID).Return(people, nil)
+	suite.mockTableRepo.On("GetByProjectID", projectID).Return([]*models.Table{people, {Name: "posts"}}, nil)
+	suite.mockTableRepo.On("Update", mock.MatchedBy(func(table *models.Table) bool { return table.Name == "users" })).Return(nil).Once()
+	suite.mockCollabService.On("NotifyTableUpdated", projectID, people, userID).Return(nil).Once()
+	suite.mockRenameRepo.On("Create", mock.AnythingOfType("*models.SchemaRename")).Return(uuid.New(), nil).Once()
+
+	rename, err := suite.service.Rename(projectID, &dto.RenameRequest{TableID: people.ID, Name: " users "}, userID)
+
+	suite.Require().NoError(err)
+	suite.Equal(models.SchemaRename{ProjectID: projectID, TableID: people.ID, TableName: "people", OldName: "people", NewName: "users", RenamedBy: userID}, *rename)
+	suite.mockTableRepo.AssertExpectations(suite.T())
+	suite.mockRenameRepo.AssertExpectations(suite.T())
+	suite.mockCollabService.AssertExpectations(suite.T())
+}
+
+// Test Rename - A field cannot take the name of another of its table
+func (suite *SchemaServiceTestSuite) TestRename_FieldNameTaken() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	users := &models.Table{ID: uuid.New(), ProjectID: projectID, Name: "users", Fields: []models.Field{
+		{ID: uuid.New(), Name: "mail"},
+		{ID: uuid.New(), Name: "email"},
+	}}
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockTableRepo.On("GetByID", users.ID).Return(users, nil)
+
+	rename, err := suite.service.Rename(projectID, &dto.RenameRequest{TableID: users.ID, FieldID: &users.Fields[0].ID, Name: "email"}, userID)
+
+	suite.ErrorIs(err, ErrNameTaken)
+	suite.Nil(rename)
+	suite.mockFieldRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+	suite.mockRenameRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test Rename - Tables of other projects are not found
+func (suite *SchemaServiceTestSuite) TestRename_OtherProject() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	table := &models.Table{ID: uuid.New(), ProjectID: uuid.New(), Name: "users"}
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockUnitOfWork.On("Do").Return(nil)
+	suite.mockTableRepo.On("GetByID", table.ID).Return(table, nil)
+
+	_, err := suite.service.Rename(projectID, &dto.RenameRequest{TableID: table.ID, Name: "accounts"}, userID)
+
+	suite.ErrorIs(err, ErrTableNotFound)
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	maxSearchQueryLength = 200
+	maxSearchTerms       = 10
+	defaultSearchLimit   = 20
+	maxSearchLimit       = 100
+)
+
+type SearchService struct {
+	searchRepo  repository.SearchRepositoryInterface
+	authService AuthorizationServiceInterface
+}
+
+func NewSearchService(searchRepo repository.SearchRepositoryInterface, authService AuthorizationServiceInterface) *SearchService {
+	return &SearchService{
+		searchRepo:  searchRepo,
+		authService: authService,
+	}
+}
+
+// Search finds the projects, tables and fields matching every word of text among
+// the projects the user can access, or within projectID when it is set. A limit
+// of zero uses the default.
+func (s *SearchService) Search(userID uuid.UUID, text string, projectID *uuid.UUID, types []string, limit int) ([]*repository.SearchHit, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || len(text) > maxSearchQueryLength {
+		return nil, ErrInvalidInput
+	}
+
+	terms := searchTerms(text)
+	if len(terms) == 0 || len(terms) > maxSearchTerms {
+		return nil, ErrInvalidInput
+	}
+
+	for _, searchType := range types {
+		if !slices.Contains(repository.SearchTypes, searchType) {
+			return nil, ErrInvalidInput
+		}
+	}
+
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if limit < 0 || limit > maxSearchLimit {
+		return nil, ErrInvalidInput
+	}
+
+	if projectID != nil {
+		canAccess, err := s.authService.CanUserAccessProject(userID, *projectID)
+		if err != nil {
+			return nil, err
+		}
+		if !canAccess {
+			return nil, ErrForbidden
+		}
+	}
+
+	return s.searchRepo.Search(repository.SearchQuery{
+		Terms:     terms,
+		UserID:    userID,
+		ProjectID: projectID,
+		Types:     types,
+		Limit:     limit,
+	})
+}
+
+// searchTerms splits text into lowercase words of letters and digits, the way
+// Postgres splits names like customer_id
+func searchTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	slices.Sort(words)
+	return slices.Compact(words)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"testing"
+
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SearchServiceTestSuite struct {
+	suite.Suite
+	mockSearchRepo  *mockRepo.MockSearchRepository
+	mockAuthService *mockAuthorizationService
+	service         *SearchService
+	userID          uuid.UUID
+}
+
+func (suite *SearchServiceTestSuite) SetupTest() {
+	suite.mockSearchRepo = new(mockRepo.MockSearchRepository)
+	suite.mockAuthService = new(mockAuthorizationService)
+	suite.service = NewSearchService(suite.mockSearchRepo, suite.mockAuthService)
+	suite.userID = uuid.New()
+}
+
+func TestSearchServiceSuite(t *testing.T) {
+	suite.Run(t, new(SearchServiceTestSuite))
+}
+
+// Test Search - Identifiers are split into lowercase words like Postgres does
+func (suite *SearchServiceTestSuite) TestSearch_Terms() {
+	hits := []*repository.SearchHit{{Type: repository.SearchTypeTable, ID: uuid.New(), Name: "customers"}}
+	expected := repository.SearchQuery{Terms: []string{"cust", "id"}, UserID: suite.userID, Limit: defaultSearchLimit}
+
+	suite.mockSearchRepo.On("Search", expected).Return(hits, nil)
+
+	result, err := suite.service.Search(suite.userID, "  Cust_ID id ", nil, nil, 0)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), hits, result)
+	suite.mockSearchRepo.AssertExpectations(suite.T())
+}
+
+// Test Search - Invalid queries are rejected before searching
+func (suite *SearchServiceTestSuite) TestSearch_InvalidInput() {
+	cases := []struct {
+		text  string
+		types []string
+		limit int
+	}{
+		{text: "--", limit: 0},
+		{text: "orders", types: []string{"relationship"}},
+		{text: "orders", limit: maxSearchLimit + 1},
+	}
+
+	for _, c := range cases {
+		_, err := suite.service.Search(suite.userID, c.text, nil, c.types, c.limit)
+		assert.ErrorIs(suite.T(), err, ErrInvalidInput)
+	}
+	suite.mockSearchRepo.AssertNotCalled(suite.T(), "Search", mock.Anything)
+}
+
+// Test Search - A project the user cannot access is forbidden
+func (suite *SearchServiceTestSuite) TestSearch_ProjectForbidden() {
+	projectID := uuid.New()
+
+	suite.mockAuthService.On("CanUserAccessProject", suite.userID, projectID).Return(false, nil)
+
+	_, err := suite.service.Search(suite.userID, "orders", &projectID, nil, 0)
+
+	assert.ErrorIs(suite.T(), err, ErrForbidden)
+	suite.mockSearchRepo.AssertNotCalled(suite.T(), "Search", mock.Anything)
+}
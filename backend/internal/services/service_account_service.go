@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// serviceAccountEmailDomain is a reserved, non-routable domain so backing users
+// can never receive mail or be matched by an OAuth or SAML email
+const serviceAccountEmailDomain = "service-accounts.invalid"
+
+type ServiceAccountService struct {
+	accountRepo     repository.ServiceAccountRepositoryInterface
+	userRepo        repository.UserRepositoryInterface
+	projectRepo     repository.ProjectRepositoryInterface
+	authService     AuthorizationServiceInterface
+	apiTokenService APITokenServiceInterface
+	projectCache    *ProjectCache
+	accessCache     *AccessCache
+}
+
+func NewServiceAccountService(
+	accountRepo repository.ServiceAccountRepositoryInterface,
+	userRepo repository.UserRepositoryInterface,
+	projectRepo repository.ProjectRepositoryInterface,
+	authService AuthorizationServiceInterface,
+	apiTokenService APITokenServiceInterface,
+	projectCache *ProjectCache,
+	accessCache *AccessCache,
+) *ServiceAccountService {
+	return &ServiceAccountService{
+		accountRepo:     accountRepo,
+		userRepo:        userRepo,
+		projectRepo:     projectRepo,
+		authService:     authService,
+		apiTokenService: apiTokenService,
+		projectCache:    projectCache,
+		accessCache:     accessCache,
+	}
+}
+
+// CreateServiceAccount creates a service account and its backing user, and adds
+// the user as a collaborator so it can reach the project and nothing else
+func (s *ServiceAccountService) CreateServiceAccount(projectID uuid.UUID, name string, scopes []string, userID uuid.UUID) (*models.ServiceAccount, error) {
+	name = strings.TrimSpace(name)
+	if len(name) < 1 || len(name) > 100 {
+		return nil, ErrInvalidInput
+	}
+
+	granted, err := normalizeScopes(scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireModify(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	handle := uuid.New()
+	principal := &models.User{
+		Email:            fmt.Sprintf("%s@%s", handle, serviceAccountEmailDomain),
+		Username:         "sa-" + strings.ReplaceAll(handle.String(), "-", "")[:12],
+		IsServiceAccount: true,
+	}
+
+	principalID, err := s.userRepo.Create(principal)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.projectRepo.AddCollaborator(projectID, principalID); err != nil {
+		s.userRepo.Delete(principalID)
+		return nil, err
+	}
+	s.projectCache.Invalidate(projectID)
+	s.accessCache.InvalidateMembers(projectID)
+
+	account := &models.ServiceAccount{
+		ProjectID:   projectID,
+		UserID:      principalID,
+		Name:        name,
+		Scopes:      strings.Join(granted, ","),
+		CreatedByID: userID,
+	}
+
+	id, err := s.accountRepo.Create(account)
+	if err != nil {
+		s.userRepo.Delete(principalID)
+		return nil, err
+	}
+
+	account.ID = id
+	return account, nil
+}
+
+func (s *ServiceAccountService) GetServiceAccountsByProjectID(projectID, userID uuid.UUID) ([]*models.ServiceAccount, error) {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAccess {
+		return nil, ErrForbidden
+	}
+
+	return s.accountRepo.GetByProjectID(projectID)
+}
+
+// DeleteServiceAccount removes the account and its backing user, which also deletes its tokens
+func (s *ServiceAccountService) DeleteServiceAccount(projectID, id, userID uuid.UUID) error {
+	account, err := s.getAccount(projectID, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.accountRepo.Delete(account.ID); err != nil {
+		return err
+	}
+	if err := s.projectRepo.RemoveCollaborator(projectID, account.UserID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	s.projectCache.Invalidate(projectID)
+	s.accessCache.InvalidateMembers(projectID)
+	return s.userRepo.Delete(account.UserID)
+}
+
+// CreateToken mints a token for the service account. The token is restricted to
+// the account's project and may not exceed the account's scopes.
+func (s *ServiceAccountService) CreateToken(projectID, id uuid.UUID, name string, scopes []string, expiresAt *time.Time, userID uuid.UUID) (*models.APIToken, string, error) {
+	account, err := s.getAccount(projectID, id, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	allowed := strings.Split(account.Scopes, ",")
+	for _, scope := range scopes {
+		if !slices.Contains(allowed, scope) {
+			return nil, "", ErrInvalidScope
+		}
+	}
+
+	return s.apiTokenService.CreateProjectToken(account.UserID, account.ProjectID, name, scopes, expiresAt)
+}
+
+func (s *ServiceAccountService) GetTokens(projectID, id, userID uuid.UUID) ([]*models.APIToken, error) {
+	account, err := s.getAccount(projectID, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.apiTokenService.GetTokensByUserID(account.UserID)
+}
+
+func (s *ServiceAccountService) RevokeToken(projectID, id, tokenID, userID uuid.UUID) error {
+	account, err := s.getAccount(projectID, id, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.apiTokenService.RevokeToken(tokenID, account.UserID)
+}
+
+// getAccount loads a service account of the project after checking the user may manage it
+func (s *ServiceAccountService) getAccount(projectID, id, userID uuid.UUID) (*models.ServiceAccount, error) {
+	if err := s.requireModify(projectID, userID); err != nil {
+		return nil, err
+	}
+
+	account, err := s.accountRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrServiceAccountNotFound
+		}
+		return nil, err
+	}
+	if account.ProjectID != projectID {
+		return nil, ErrServiceAccountNotFound
+	}
+
+	return account, nil
+}
+
+func (s *ServiceAccountService) requireModify(projectID, userID uuid.UUID) error {
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !canModify {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ServiceAccountServiceTestSuite struct {
+	suite.Suite
+	mockAccountRepo *mockRepo.MockServiceAccountRepository
+	mockUserRepo    *mockRepo.MockUserRepository
+	mockProjectRepo *mockRepo.MockProjectRepository
+	mockTokenRepo   *mockRepo.MockAPITokenRepository
+	mockAuthService *mockAuthorizationService
+	service         *ServiceAccountService
+	projectID       uuid.UUID
+	userID          uuid.UUID
+}
+
+func (suite *ServiceAccountServiceTestSuite) SetupTest() {
+	suite.mockAccountRepo = new(mockRepo.MockServiceAccountRepository)
+	suite.mockUserRepo = new(mockRepo.MockUserRepository)
+	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
+	suite.mockTokenRepo = new(mockRepo.MockAPITokenRepository)
+	suite.mockAuthService = new(mockAuthorizationService)
+	apiTokenService := NewAPITokenService(suite.mockTokenRepo, suite.mockUserRepo)
+	suite.service = NewServiceAccountService(suite.mockAccountRepo, suite.mockUserRepo, suite.mockProjectRepo, suite.mockAuthService, apiTokenService, nil, nil)
+	suite.projectID = uuid.New()
+	suite.userID = uuid.New()
+}
+
+func TestServiceAccountServiceSuite(t *testing.T) {
+	suite.Run(t, new(ServiceAccountServiceTestSuite))
+}
+
+func (suite *ServiceAccountServiceTestSuite) testAccount(scopes string) *models.ServiceAccount {
+	return &models.ServiceAccount{ID: uuid.New(), ProjectID: suite.projectID, UserID: uuid.New(), Name: "CI", Scopes: scopes}
+}
+
+// Test CreateServiceAccount - creates a passwordless backing user that collaborates on the project
+func (suite *ServiceAccountServiceTestSuite) TestCreateServiceAccount_Success() {
+	principalID := uuid.New()
+	accountID := uuid.New()
+
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockUserRepo.On("Create", mock.MatchedBy(func(user *models.User) bool {
+		return user.IsServiceAccount && user.PasswordHash == "" &&
+			strings.HasSuffix(user.Email, "@"+serviceAccountEmailDomain) && strings.HasPrefix(user.Username, "sa-")
+	})).Return(principalID, nil)
+	suite.mockProjectRepo.On("AddCollaborator", suite.projectID, principalID).Return(nil)
+	suite.mockAccountRepo.On("Create", mock.AnythingOfType("*models.ServiceAccount")).Return(accountID, nil)
+
+	account, err := suite.service.CreateServiceAccount(suite.projectID, "Export pipeline", []string{ScopeReadProjects}, suite.userID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), accountID, account.ID)
+	assert.Equal(suite.T(), principalID, account.UserID)
+	assert.Equal(suite.T(), ScopeReadProjects, account.Scopes)
+	assert.Equal(suite.T(), suite.userID, account.CreatedByID)
+}
+
+// Test CreateServiceAccount - users who cannot modify the project are rejected
+func (suite *ServiceAccountServiceTestSuite) TestCreateServiceAccount_Forbidden() {
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(false, nil)
+
+	_, err := suite.service.CreateServiceAccount(suite.projectID, "CI", []string{ScopeReadProjects}, suite.userID)
+
+	assert.ErrorIs(suite.T(), err, ErrForbidden)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test CreateToken - token is restricted to the project and capped at the account scopes
+func (suite *ServiceAccountServiceTestSuite) TestCreateToken_Success() {
+	account := suite.testAccount(ScopeReadProjects)
+
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockAccountRepo.On("GetByID", account.ID).Return(account, nil)
+	suite.mockUserRepo.On("GetByID", account.UserID).Return(&models.User{ID: account.UserID}, nil)
+	suite.mockTokenRepo.On("Create", mock.MatchedBy(func(token *models.APIToken) bool {
+		return token.UserID == account.UserID && token.ProjectID != nil && *token.ProjectID == suite.projectID
+	})).Return(uuid.New(), nil)
+
+	token, plaintext, err := suite.service.CreateToken(suite.projectID, account.ID, "export", []string{ScopeReadProjects}, nil, suite.userID)
+
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), plaintext)
+	assert.Equal(suite.T(), ScopeReadProjects, token.Scopes)
+}
+
+// Test CreateToken - scopes beyond the account's are rejected
+func (suite *ServiceAccountServiceTestSuite) TestCreateToken_ExceedsAccountScopes() {
+	account := suite.testAccount(ScopeReadProjects)
+
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockAccountRepo.On("GetByID", account.ID).Return(account, nil)
+
+	_, _, err := suite.service.CreateToken(suite.projectID, account.ID, "deploy", []string{ScopeWriteSchema}, nil, suite.userID)
+
+	assert.ErrorIs(suite.T(), err, ErrInvalidScope)
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test DeleteServiceAccount - account of another project is not found
+func (suite *ServiceAccountServiceTestSuite) TestDeleteServiceAccount_OtherProject() {
+	account := suite.testAccount(ScopeReadProjects)
+	account.ProjectID = uuid.New()
+
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockAccountRepo.On("GetByID", account.ID).Return(account, nil)
+
+	err := suite.service.DeleteServiceAccount(suite.projectID, account.ID, suite.userID)
+
+	assert.ErrorIs(suite.T(), err, ErrServiceAccountNotFound)
+	suite.mockAccountRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+}
+
+// Test DeleteServiceAccount - removes the account, its collaboration and its backing user
+func (suite *ServiceAccountServiceTestSuite) TestDeleteServiceAccount_Success() {
+	account := suite.testAccount(ScopeReadProjects)
+
+	suite.mockAuthService.On("CanUserModifyProject", suite.userID, suite.projectID).Return(true, nil)
+	suite.mockAccountRepo.On("GetByID", account.ID).Return(account, nil)
+	suite.mockAccountRepo.On("Delete", account.ID).Return(nil)
+	suite.mockProjectRepo.On("RemoveCollaborator", suite.projectID, account.UserID).Return(nil)
+	suite.mockUserRepo.On("Delete", account.UserID).Return(nil)
+
+	err := suite.service.DeleteServiceAccount(suite.projectID, account.ID, suite.userID)
+
+	assert.NoError(suite.T(), err)
+	suite.mockAccountRepo.AssertExpectations(suite.T())
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
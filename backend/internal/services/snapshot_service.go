@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/jobs"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Types of the snapshot jobs: the nightly run, which enqueues one job per
+// project that changed since its latest snapshot
+const (
+	JobScheduleSnapshots = "snapshots.schedule"
+	JobTakeSnapshot      = "snapshots.take"
+)
+
+// takeSnapshotJob is the payload of a JobTakeSnapshot job
+type takeSnapshotJob struct {
+	ProjectID uuid.UUID `json:"project_id"`
+}
+
+// snapshotData is what a snapshot holds
+type snapshotData struct {
+	Project *models.Project  `json:"project"`
+	Regions []*models.Region `json:"regions"`
+}
+
+// SnapshotRetention is how many snapshots a project keeps
+type SnapshotRetention struct {
+	KeepDaily   int
+	KeepMonthly int
+	Overridden  bool // Differs from the instance defaults through the project's policy
+}
+
+// SnapshotService takes nightly snapshots of the projects that changed and
+// prunes those beyond the retention: the latest snapshot of each of the last
+// KeepDaily days and KeepMonthly months with snapshots are kept, and always
+// the latest one.
+type SnapshotService struct {
+	snapshotRepo repository.SnapshotRepositoryInterface
+	projectRepo  repository.ProjectRepositoryInterface
+	regionRepo   repository.RegionRepositoryInterface
+	authService  AuthorizationServiceInterface
+	jobs         jobs.Queue
+	keepDaily    int
+	keepMonthly  int
+	now          func() time.Time
+}
+
+func NewSnapshotService(
+	cfg *config.Config,
+	snapshotRepo repository.SnapshotRepositoryInterface,
+	projectRepo repository.ProjectRepositoryInterface,
+	regionRepo repository.RegionRepositoryInterface,
+	authService AuthorizationServiceInterface,
+	jobQueue jobs.Queue,
+) *SnapshotService {
+	return &SnapshotService{
+		snapshotRepo: snapshotRepo,
+		projectRepo:  projectRepo,
+		regionRepo:   regionRepo,
+		authService:  authService,
+		jobs:         jobQueue,
+		keepDaily:    cfg.Snapshots.KeepDaily,
+		keepMonthly:  cfg.Snapshots.KeepMonthly,
+		now:          time.Now,
+	}
+}
+
+// GetSnapshots returns the project's snapshots newest first, without their data
+func (s *SnapshotService) GetSnapshots(projectID, userID uuid.UUID) ([]*models.ProjectSnapshot, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.snapshotRepo.GetByProjectID(projectID)
+}
+
+// GetRetention returns how many snapshots the project keeps
+func (s *SnapshotService) GetRetention(projectID, userID uuid.UUID) (*SnapshotRetention, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.retention(projectID)
+}
+
+// SetRetention overrides how many snapshots the project keeps; a nil count
+// goes back to the instance default. Only the owner may change it. Snapshots
+// beyond the new retention are pruned at once.
+func (s *SnapshotService) SetRetention(projectID, userID uuid.UUID, keepDaily, keepMonthly *int) (*SnapshotRetention, error) {
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+	if project.OwnerID != userID {
+		return nil, ErrForbidden
+	}
+	if (keepDaily != nil && *keepDaily < 0) || (keepMonthly != nil && *keepMonthly < 0) {
+		return nil, ErrInvalidInput
+	}
+
+	policy := &models.SnapshotPolicy{ProjectID: projectID, KeepDaily: keepDaily, KeepMonthly: keepMonthly, UpdatedAt: s.now()}
+	if err := s.snapshotRepo.SavePolicy(policy); err != nil {
+		return nil, err
+	}
+	retention := s.retentionOf(policy)
+	if err := s.prune(projectID, retention); err != nil {
+		return nil, err
+	}
+	return retention, nil
+}
+
+// ScheduleSnapshots is the handler of JobScheduleSnapshots jobs. It enqueues
+// a snapshot of every project that changed since its latest one.
+func (s *SnapshotService) ScheduleSnapshots(ctx context.Context, job *jobs.Job) error {
+	projectIDs, err := s.snapshotRepo.GetProjectIDsToSnapshot()
+	if err != nil {
+		return err
+	}
+
+	// A retried run skips the projects enqueued before it failed
+	day := s.now().UTC().Format("2006-01-02")
+	for _, projectID := range projectIDs {
+		_, err := s.jobs.Enqueue(ctx, JobTakeSnapshot, takeSnapshotJob{ProjectID: projectID},
+			jobs.Unique(JobTakeSnapshot+":"+projectID.String()+":"+day, 24*time.Hour))
+		if err != nil && !errors.Is(err, jobs.ErrDuplicate) {
+			return err
+		}
+	}
+	log.Printf("Scheduled snapshots of %d projects", len(projectIDs))
+	return nil
+}
+
+// TakeSnapshot is the handler of JobTakeSnapshot jobs. It snapshots the
+// project unless it is unchanged since its latest snapshot, then prunes.
+func (s *SnapshotService) TakeSnapshot(ctx context.Context, job *jobs.Job) error {
+	var payload takeSnapshotJob
+	if err := job.Decode(&payload); err != nil {
+		return err
+	}
+
+	project, err := s.projectRepo.GetByID(payload.ProjectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil // Deleted since it was scheduled
+		}
+		return err
+	}
+	snapshots, err := s.snapshotRepo.GetByProjectID(project.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) == 0 || snapshots[0].ProjectVersion != project.Version || snapshots[0].ProjectSequence != project.Sequence {
+		regions, err := s.regionRepo.GetByProjectID(project.ID)
+		if err != nil {
+			return err
+		}
+		snapshot, err := newSnapshot(project, regions, s.now())
+		if err != nil {
+			return err
+		}
+		if _, err := s.snapshotRepo.Create(snapshot); err != nil {
+			return err
+		}
+		snapshots = append([]*models.ProjectSnapshot{snapshot}, snapshots...)
+	}
+
+	retention, err := s.retention(project.ID)
+	if err != nil {
+		return err
+	}
+	return s.snapshotRepo.DeleteByIDs(snapshotsToPrune(snapshots, retention))
+}
+
+func (s *SnapshotService) prune(projectID uuid.UUID, retention *SnapshotRetention) error {
+	snapshots, err := s.snapshotRepo.GetByProjectID(projectID)
+	if err != nil {
+		return err
+	}
+	return s.snapshotRepo.DeleteByIDs(snapshotsToPrune(snapshots, retention))
+}
+
+func (s *SnapshotService) checkAccess(projectID, userID uuid.UUID) error {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return ErrProjectNotFound
+		}
+		return err
+	}
+	if !canAccess {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// retention returns the project's policy applied over the instance defaults
+func (s *SnapshotService) retention(projectID uuid.UUID) (*SnapshotRetention, error) {
+	policy, err := s.snapshotRepo.GetPolicy(projectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.retentionOf(nil), nil
+		}
+		return nil, err
+	}
+	return s.retentionOf(policy), nil
+}
+
+func (s *SnapshotService) retentionOf(policy *models.SnapshotPolicy) *SnapshotRetention {
+	retention := &SnapshotRetention{KeepDaily: s.keepDaily, KeepMonthly: s.keepMonthly}
+	if policy == nil {
+		return retention
+	}
+	if policy.KeepDaily != nil {
+		retention.KeepDaily = *policy.KeepDaily
+		retention.Overridden = true
+	}
+	if policy.KeepMonthly != nil {
+		retention.KeepMonthly = *policy.KeepMonthly
+		retention.Overridden = true
+	}
+	return retention
+}
+
+// newSnapshot snapshots the project with its regions, leaving out the
+// people; the snapshot is of the schema
+func newSnapshot(project *models.Project, regions []*models.Region, now time.Time) (*models.ProjectSnapshot, error) {
+	project.Owner = models.User{}
+	project.Collaborators = nil
+	data, err := json.Marshal(snapshotData{Project: project, Regions: regions})
+	if err != nil {
+		return nil, err
+	}
+	return &models.ProjectSnapshot{
+		ProjectID:       project.ID,
+		ProjectVersion:  project.Version,
+		ProjectSequence: project.Sequence,
+		Data:            string(data),
+		SizeBytes:       int64(len(data)),
+		CreatedAt:       now,
+	}, nil
+}
+
+// snapshotProject returns the project as a snapshot holds it, with its
+// tables, fields and relationships
+func snapshotProject(snapshot *models.ProjectSnapshot) (*models.Project, error) {
+	var data snapshotData
+	if err := json.Unmarshal([]byte(snapshot.Data), &data); err != nil {
+		return nil, err
+	}
+	if data.Project == nil {
+		return nil, errors.New("snapshot without a project")
+	}
+	return data.Project, nil
+}
+
+// snapshotsToPrune returns the IDs of the snapshots, given newest first, that
+// the retention does not keep
+func snapshotsToPrune(snapshots []*models.ProjectSnapshot, retention *SnapshotRetention) []uuid.UUID {
+	days := make(map[string]bool)
+	months := make(map[string]bool)
+	var pruned []uuid.UUID
+	for i, snapshot := range snapshots {
+		// The first snapshot seen of a day or month is its latest
+		day := snapshot.CreatedAt.UTC().Format("2006-01-02")
+		month := snapshot.CreatedAt.UTC().Format("2006-01")
+		keep := i == 0
+		if !days[day] && len(days) < retention.KeepDaily {
+			days[day] = true
+			keep = true
+		}
+		if !months[month] && len(months) < retention.KeepMonthly {
+			months[month] = true
+			keep = true
+		}
+		if !keep {
+			pruned = append(pruned, snapshot.ID)
+		}
+	}
+	return pruned
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/jobs"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type SnapshotServiceTestSuite struct {
+	suite.Suite
+	mockSnapshotRepo *mockRepo.MockSnapshotRepository
+	mockProjectRepo  *mockRepo.MockProjectRepository
+	mockRegionRepo   *mockRepo.MockRegionRepository
+	mockAuthService  *mockTableAuthService
+	queue            *recordingQueue
+	service          *SnapshotService
+	now              time.Time
+}
+
+func (suite *SnapshotServiceTestSuite) SetupTest() {
+	suite.mockSnapshotRepo = new(mockRepo.MockSnapshotRepository)
+	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
+	suite.mockRegionRepo = new(mockRepo.MockRegionRepository)
+	suite.mockAuthService = new(mockTableAuthService)
+	suite.queue = &recordingQueue{}
+
+	cfg := &config.Config{}
+	cfg.Snapshots.KeepDaily = 7
+	cfg.Snapshots.KeepMonthly = 3
+	suite.service = NewSnapshotService(cfg, suite.mockSnapshotRepo, suite.mockProjectRepo, suite.mockRegionRepo, suite.mockAuthService, suite.queue)
+	suite.now = time.Date(2026, 3, 15, 2, 0, 0, 0, time.UTC)
+	suite.service.now = func() time.Time { return suite.now }
+}
+
+func (suite *SnapshotServiceTestSuite) TearDownTest() {
+	suite.mockSnapshotRepo.AssertExpectations(suite.T())
+	suite.mockProjectRepo.AssertExpectations(suite.T())
+	suite.mockRegionRepo.AssertExpectations(suite.T())
+	suite.mockAuthService.AssertExpectations(suite.T())
+}
+
+func TestSnapshotServiceSuite(t *testing.T) {
+	suite.Run(t, new(SnapshotServiceTestSuite))
+}
+
+func (suite *SnapshotServiceTestSuite) takeJob(projectID uuid.UUID) *jobs.Job {
+	payload, err := json.Marshal(takeSnapshotJob{ProjectID: projectID})
+	suite.Require().NoError(err)
+	return &jobs.Job{ID: uuid.New(), Type: JobTakeSnapshot, Payload: payload, Attempt: 1, MaxAttempts: 3}
+}
+
+// Test snapshotsToPrune - The latest of each kept day and month survive
+func (suite *SnapshotServiceTestSuite) TestSnapshotsToPrune() {
+	at := func(s string) *models.ProjectSnapshot {
+		createdAt, err := time.Parse(time.RFC3339, s)
+		suite.Require().NoError(err)
+		return &models.ProjectSnapshot{ID: uuid.New(), CreatedAt: createdAt}
+	}
+	snapshots := []*models.ProjectSnapshot{
+		at("2026-03-15T02:00:00Z"), // Day 1, month 1
+		at("2026-03-14T12:00:00Z"), // Day 2
+		at("2026-03-14T02:00:00Z"), // Second of day 2
+		at("2026-03-10T02:00:00Z"), // Day 3, beyond the kept days
+		at("2026-02-20T02:00:00Z"), // Month 2
+		at("2026-02-01T02:00:00Z"), // Second of month 2
+		at("2026-01-05T02:00:00Z"), // Month 3, beyond the kept months
+	}
+
+	pruned := snapshotsToPrune(snapshots, &SnapshotRetention{KeepDaily: 2, KeepMonthly: 2})
+
+	suite.ElementsMatch([]uuid.UUID{snapshots[2].ID, snapshots[3].ID, snapshots[5].ID, snapshots[6].ID}, pruned)
+}
+
+// Test snapshotsToPrune - The latest snapshot is kept even with nothing retained
+func (suite *SnapshotServiceTestSuite) TestSnapshotsToPrune_KeepsLatest() {
+	latest := &models.ProjectSnapshot{ID: uuid.New(), CreatedAt: suite.now}
+	older := &models.ProjectSnapshot{ID: uuid.New(), CreatedAt: suite.now.AddDate(0, 0, -1)}
+
+	pruned := snapshotsToPrune([]*models.ProjectSnapshot{latest, older}, &SnapshotRetention{})
+
+	suite.Equal([]uuid.UUID{older.ID}, pruned)
+}
+
+// Test ScheduleSnapshots - A snapshot job is enqueued per changed project
+func (suite *SnapshotServiceTestSuite) TestScheduleSnapshots() {
+	projectIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	suite.mockSnapshotRepo.On("GetProjectIDsToSnapshot").Return(projectIDs, nil)
+
+	err := suite.service.ScheduleSnapshots(context.Background(), &jobs.Job{Type: JobScheduleSnapshots})
+
+	suite.NoError(err)
+	suite.Require().Len(suite.queue.jobs, 2)
+	for i, job := range suite.queue.jobs {
+		var payload takeSnapshotJob
+		suite.Require().NoError(job.Decode(&payload))
+		suite.Equal(JobTakeSnapshot, job.Type)
+		suite.Equal(projectIDs[i], payload.ProjectID)
+	}
+}
+
+// Test TakeSnapshot - A changed project is snapshotted without its people
+func (suite *SnapshotServiceTestSuite) TestTakeSnapshot_Changed() {
+	project := &models.Project{ID: uuid.New(), Name: "Shop", OwnerID: uuid.New()}
+	project.Version = 4
+	project.Sequence = 9
+	project.Owner = models.User{ID: project.OwnerID, Email: "owner@example.com"}
+	previous := &models.ProjectSnapshot{ID: uuid.New(), ProjectVersion: 3, ProjectSequence: 7, CreatedAt: suite.now.AddDate(0, 0, -1)}
+	regions := []*models.Region{{ID: uuid.New(), ProjectID: project.ID, Name: "Billing"}}
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil)
+	suite.mockSnapshotRepo.On("GetByProjectID", project.ID).Return([]*models.ProjectSnapshot{previous}, nil)
+	suite.mockRegionRepo.On("GetByProjectID", project.ID).Return(regions, nil)
+	var created *models.ProjectSnapshot
+	suite.mockSnapshotRepo.On("Create", mock.AnythingOfType("*models.ProjectSnapshot")).
+		Run(func(args mock.Arguments) { created = args.Get(0).(*models.ProjectSnapshot) }).
+		Return(uuid.New(), nil)
+	suite.mockSnapshotRepo.On("GetPolicy", project.ID).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockSnapshotRepo.On("DeleteByIDs", []uuid.UUID(nil)).Return(nil)
+
+	err := suite.service.TakeSnapshot(context.Background(), suite.takeJob(project.ID))
+
+	suite.NoError(err)
+	suite.Require().NotNil(created)
+	suite.Equal(int64(4), created.ProjectVersion)
+	suite.Equal(int64(9), created.ProjectSequence)
+	suite.Equal(int64(len(created.Data)), created.SizeBytes)
+	suite.Contains(created.Data, `"Billing"`)
+	suite.NotContains(created.Data, "owner@example.com")
+}
+
+// Test TakeSnapshot - A project unchanged since its latest snapshot is not snapshotted again
+func (suite *SnapshotServiceTestSuite) TestTakeSnapshot_Unchanged() {
+	project := &models.Project{ID: uuid.New(), Name: "Shop", OwnerID: uuid.New()}
+	project.Version = 4
+	project.Sequence = 9
+	latest := &models.ProjectSnapshot{ID: uuid.New(), ProjectVersion: 4, ProjectSequence: 9, CreatedAt: suite.now.AddDate(0, 0, -1)}
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil)
+	suite.mockSnapshotRepo.On("GetByProjectID", project.ID).Return([]*models.ProjectSnapshot{latest}, nil)
+	suite.mockSnapshotRepo.On("GetPolicy", project.ID).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockSnapshotRepo.On("DeleteByIDs", []uuid.UUID(nil)).Return(nil)
+
+	err := suite.service.TakeSnapshot(context.Background(), suite.takeJob(project.ID))
+
+	suite.NoError(err)
+	suite.mockSnapshotRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test TakeSnapshot - A project deleted since it was scheduled is skipped
+func (suite *SnapshotServiceTestSuite) TestTakeSnapshot_ProjectDeleted() {
+	projectID := uuid.New()
+	suite.mockProjectRepo.On("GetByID", projectID).Return(nil, gorm.ErrRecordNotFound)
+
+	err := suite.service.TakeSnapshot(context.Background(), suite.takeJob(projectID))
+
+	suite.NoError(err)
+}
+
+// Test GetRetention - The project's policy overrides the defaults it sets
+func (suite *SnapshotServiceTestSuite) TestGetRetention_Overridden() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	keepDaily := 2
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockSnapshotRepo.On("GetPolicy", projectID).Return(&models.SnapshotPolicy{ProjectID: projectID, KeepDaily: &keepDaily}, nil)
+
+	retention, err := suite.service.GetRetention(projectID, userID)
+
+	suite.NoError(err)
+	suite.Equal(&SnapshotRetention{KeepDaily: 2, KeepMonthly: 3, Overridden: true}, retention)
+}
+
+// Test SetRetention - The policy is saved and older snapshots pruned at once
+func (suite *SnapshotServiceTestSuite) TestSetRetention_Success() {
+	project := &models.Project{ID: uuid.New(), Name: "Shop", OwnerID: uuid.New()}
+	keepDaily, keepMonthly := 1, 0
+	latest := &models.ProjectSnapshot{ID: uuid.New(), CreatedAt: suite.now}
+	older := &models.ProjectSnapshot{ID: uuid.New(), CreatedAt: suite.now.AddDate(0, -1, 0)}
+
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil)
+	suite.mockSnapshotRepo.On("SavePolicy", mock.MatchedBy(func(policy *models.SnapshotPolicy) bool {
+		return policy.ProjectID == project.ID && *policy.KeepDaily == 1 && *policy.KeepMonthly == 0
+	})).Return(nil)
+	suite.mockSnapshotRepo.On("GetByProjectID", project.ID).Return([]*models.ProjectSnapshot{latest, older}, nil)
+	suite.mockSnapshotRepo.On("DeleteByIDs", []uuid.UUID{older.ID}).Return(nil)
+
+	retention, err := suite.service.SetRetention(project.ID, project.OwnerID, &keepDaily, &keepMonthly)
+
+	suite.NoError(err)
+	suite.Equal(&SnapshotRetention{KeepDaily: 1, KeepMonthly: 0, Overridden: true}, retention)
+}
+
+// Test SetRetention - Only the owner may change it
+func (suite *SnapshotServiceTestSuite) TestSetRetention_NotOwner() {
+	project := &models.Project{ID: uuid.New(), Name: "Shop", OwnerID: uuid.New()}
+	suite.mockProjectRepo.On("GetByID", project.ID).Return(project, nil)
+
+	retention, err := suite.service.SetRetention(project.ID, uuid.New(), nil, nil)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(retention)
+	suite.mockSnapshotRepo.AssertNotCalled(suite.T(), "SavePolicy", mock.Anything)
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SnippetService keeps named SQL with projects, such as seed scripts and
+// example queries. Anyone with access to a project reads its snippets; those
+// who may modify it write them.
+type SnippetService struct {
+	snippetRepo repository.SnippetRepositoryInterface
+	authService AuthorizationServiceInterface
+}
+
+func NewSnippetService(snippetRepo repository.SnippetRepositoryInterface, authService AuthorizationServiceInterface) *SnippetService {
+	return &SnippetService{
+		snippetRepo: snippetRepo,
+		authService: authService,
+	}
+}
+
+func (s *SnippetService) CreateSnippet(projectID uuid.UUID, req *dto.CreateSnippetRequest, userID uuid.UUID) (*models.Snippet, error) {
+	name := strings.TrimSpace(req.Name)
+	if len(name) < 1 || len(name) > 255 || strings.TrimSpace(req.SQL) == "" {
+		return nil, ErrInvalidInput
+	}
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.checkNameFree(projectID, uuid.Nil, name); err != nil {
+		return nil, err
+	}
+
+	snippet := &models.Snippet{
+		ProjectID:   projectID,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		SQL:         req.SQL,
+		CreatedBy:   userID,
+	}
+	id, err := s.snippetRepo.Create(snippet)
+	if err != nil {
+		return nil, err
+	}
+	snippet.ID = id
+	return snippet, nil
+}
+
+// GetSnippets returns the project's snippets by name
+func (s *SnippetService) GetSnippets(projectID, userID uuid.UUID) ([]*models.Snippet, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.snippetRepo.GetByProjectID(projectID)
+}
+
+// GetSnippet returns a snippet of the project, ErrSnippetNotFound for a snippet of another
+func (s *SnippetService) GetSnippet(projectID, id, userID uuid.UUID) (*models.Snippet, error) {
+	if err := s.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	return s.projectSnippet(projectID, id)
+}
+
+func (s *SnippetService) UpdateSnippet(projectID, id uuid.UUID, req *dto.UpdateSnippetRequest, userID uuid.UUID) (*models.Snippet, error) {
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return nil, err
+	}
+	snippet, err := s.projectSnippet(projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only update fields that were provided
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if len(name) < 1 || len(name) > 255 {
+			return nil, ErrInvalidInput
+		}
+		if name != snippet.Name {
+			if err := s.checkNameFree(projectID, id, name); err != nil {
+				return nil, err
+			}
+		}
+		snippet.Name = name
+	}
+	if req.Description != nil {
+		snippet.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.SQL != nil {
+		if strings.TrimSpace(*req.SQL) == "" {
+			return nil, ErrInvalidInput
+		}
+		snippet.SQL = *req.SQL
+	}
+
+	if err := s.snippetRepo.Update(snippet); err != nil {
+		return nil, err
+	}
+	return snippet, nil
+}
+
+func (s *SnippetService) DeleteSnippet(projectID, id, userID uuid.UUID) error {
+	if err := s.checkCanModify(projectID, userID); err != nil {
+		return err
+	}
+	if _, err := s.projectSnippet(projectID, id); err != nil {
+		return err
+	}
+	return s.snippetRepo.Delete(id)
+}
+
+// projectSnippet returns a snippet, ErrSnippetNotFound unless it is of the project
+func (s *SnippetService) projectSnippet(projectID, id uuid.UUID) (*models.Snippet, error) {
+	snippet, err := s.snippetRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSnippetNotFound
+		}
+		return nil, err
+	}
+	if snippet.ProjectID != projectID {
+		return nil, ErrSnippetNotFound
+	}
+	return snippet, nil
+}
+
+// checkNameFree fails with ErrNameTaken when a snippet of the project other
+// than id is named name
+func (s *SnippetService) checkNameFree(projectID, id uuid.UUID, name string) error {
+	snippets, err := s.snippetRepo.GetByProjectID(projectID)
+	if err != nil {
+		return err
+	}
+	for _, snippet := range snippets {
+		if snippet.ID != id && snippet.Name == name {
+			return ErrNameTaken
+		}
+	}
+	return nil
+}
+
+func (s *SnippetService) checkAccess(projectID, userID uuid.UUID) error {
+	canAccess, err := s.authService.CanUserAccessProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !canAccess {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *SnippetService) checkCanModify(projectID, userID uuid.UUID) error {
+	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !canModify {
+		return ErrForbidden
+	}
+	return nil
+}
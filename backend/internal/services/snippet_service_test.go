@@ -0,0 +1,122 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type SnippetServiceTestSuite struct {
+	suite.Suite
+	mockSnippetRepo *mockRepo.MockSnippetRepository
+	mockAuthService *mockAuthorizationService
+	service         *SnippetService
+}
+
+func (suite *SnippetServiceTestSuite) SetupTest() {
+	suite.mockSnippetRepo = new(mockRepo.MockSnippetRepository)
+	suite.mockAuthService = new(mockAuthorizationService)
+	suite.service = NewSnippetService(suite.mockSnippetRepo, suite.mockAuthService)
+}
+
+func TestSnippetServiceSuite(t *testing.T) {
+	suite.Run(t, new(SnippetServiceTestSuite))
+}
+
+// Test CreateSnippet - The name is trimmed and the author recorded
+func (suite *SnippetServiceTestSuite) TestCreateSnippet_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	snippetID := uuid.New()
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockSnippetRepo.On("GetByProjectID", projectID).Return([]*models.Snippet{{ID: uuid.New(), Name: "Other"}}, nil)
+	suite.mockSnippetRepo.On("Create", mock.MatchedBy(func(snippet *models.Snippet) bool {
+		return snippet.ProjectID == projectID && snippet.Name == "Seed" && snippet.CreatedBy == userID
+	})).Return(snippetID, nil)
+
+	snippet, err := suite.service.CreateSnippet(projectID, &dto.CreateSnippetRequest{Name: " Seed ", SQL: "INSERT INTO users VALUES (1);"}, userID)
+
+	suite.NoError(err)
+	suite.Equal(snippetID, snippet.ID)
+	suite.mockSnippetRepo.AssertExpectations(suite.T())
+}
+
+// Test CreateSnippet - Names are unique within the project
+func (suite *SnippetServiceTestSuite) TestCreateSnippet_NameTaken() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockSnippetRepo.On("GetByProjectID", projectID).Return([]*models.Snippet{{ID: uuid.New(), Name: "Seed"}}, nil)
+
+	snippet, err := suite.service.CreateSnippet(projectID, &dto.CreateSnippetRequest{Name: "Seed", SQL: "SELECT 1;"}, userID)
+
+	suite.ErrorIs(err, ErrNameTaken)
+	suite.Nil(snippet)
+	suite.mockSnippetRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test CreateSnippet - Viewers without modify rights cannot save snippets
+func (suite *SnippetServiceTestSuite) TestCreateSnippet_Forbidden() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(false, nil)
+
+	snippet, err := suite.service.CreateSnippet(projectID, &dto.CreateSnippetRequest{Name: "Seed", SQL: "SELECT 1;"}, userID)
+
+	suite.ErrorIs(err, ErrForbidden)
+	suite.Nil(snippet)
+}
+
+// Test GetSnippet - A snippet of another project is not found
+func (suite *SnippetServiceTestSuite) TestGetSnippet_OtherProject() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	snippetID := uuid.New()
+	suite.mockAuthService.On("CanUserAccessProject", userID, projectID).Return(true, nil)
+	suite.mockSnippetRepo.On("GetByID", snippetID).Return(&models.Snippet{ID: snippetID, ProjectID: uuid.New()}, nil)
+
+	snippet, err := suite.service.GetSnippet(projectID, snippetID, userID)
+
+	suite.ErrorIs(err, ErrSnippetNotFound)
+	suite.Nil(snippet)
+}
+
+// Test UpdateSnippet - Only the fields provided change, and renaming checks the name
+func (suite *SnippetServiceTestSuite) TestUpdateSnippet_Success() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	snippetID := uuid.New()
+	name := "Seed users"
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockSnippetRepo.On("GetByID", snippetID).Return(&models.Snippet{ID: snippetID, ProjectID: projectID, Name: "Seed", SQL: "SELECT 1;"}, nil)
+	suite.mockSnippetRepo.On("GetByProjectID", projectID).Return([]*models.Snippet{{ID: snippetID, Name: "Seed"}}, nil)
+	suite.mockSnippetRepo.On("Update", mock.MatchedBy(func(snippet *models.Snippet) bool {
+		return snippet.Name == "Seed users" && snippet.SQL == "SELECT 1;"
+	})).Return(nil)
+
+	snippet, err := suite.service.UpdateSnippet(projectID, snippetID, &dto.UpdateSnippetRequest{Name: &name}, userID)
+
+	suite.NoError(err)
+	suite.Equal("Seed users", snippet.Name)
+	suite.mockSnippetRepo.AssertExpectations(suite.T())
+}
+
+// Test DeleteSnippet - Deleting a missing snippet fails without deleting
+func (suite *SnippetServiceTestSuite) TestDeleteSnippet_NotFound() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	snippetID := uuid.New()
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockSnippetRepo.On("GetByID", snippetID).Return(nil, gorm.ErrRecordNotFound)
+
+	err := suite.service.DeleteSnippet(projectID, snippetID, userID)
+
+	suite.ErrorIs(err, ErrSnippetNotFound)
+	suite.mockSnippetRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+}
@@ -16,26 +16,37 @@ type TableService struct {
 	projectRepo          repository.ProjectRepositoryInterface
 	authService          AuthorizationServiceInterface
 	collaborationService CollaborationSessionServiceInterface
+	projectCache         *ProjectCache
+	unitOfWork           *UnitOfWork
+	quotas               QuotaPolicy
 }
 
-func NewTableService(tableRepo repository.TableRepositoryInterface, projectRepo repository.ProjectRepositoryInterface, authService AuthorizationServiceInterface, collaborationService CollaborationSessionServiceInterface) *TableService {
+func NewTableService(tableRepo repository.TableRepositoryInterface, projectRepo repository.ProjectRepositoryInterface, authService AuthorizationServiceInterface, collaborationService CollaborationSessionServiceInterface, projectCache *ProjectCache, unitOfWork *UnitOfWork, quotas QuotaPolicy) *TableService {
 	return &TableService{
 		tableRepo:            tableRepo,
 		projectRepo:          projectRepo,
 		authService:          authService,
 		collaborationService: collaborationService,
+		projectCache:         projectCache,
+		unitOfWork:           unitOfWork,
+		quotas:               quotas,
 	}
 }
 
-func (s *TableService) CreateTable(projectID uuid.UUID, name string, posX, posY float64, userID uuid.UUID) (*models.Table, error) {
-	name = strings.TrimSpace(name)
+func (s *TableService) CreateTable(projectID uuid.UUID, req *dto.CreateTableRequest, userID uuid.UUID) (*models.Table, error) {
+	name := strings.TrimSpace(req.Name)
 
 	if len(name) < 1 || len(name) > 255 {
 		return nil, ErrInvalidInput
 	}
 
+	metadata, err := newMetadata(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
 	// Verify project exists
-	_, err := s.projectRepo.GetByID(projectID)
+	project, err := s.projectRepo.GetByID(projectID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrProjectNotFound
@@ -52,32 +63,44 @@ func (s *TableService) CreateTable(projectID uuid.UUID, name string, posX, posY
 		return nil, ErrForbidden
 	}
 
+	quotas, err := s.quotas.QuotasFor(project.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := quotas.CheckTables(len(project.Tables) + 1); err != nil {
+		return nil, err
+	}
+
 	table := &models.Table{
 		ProjectID: projectID,
 		Name:      name,
-		PosX:      posX,
-		PosY:      posY,
+		PosX:      req.PosX,
+		PosY:      req.PosY,
+		Color:     req.Color,
+		Icon:      req.Icon,
+		Metadata:  metadata,
 	}
 
-	// Generate UUID for the table before broadcasting
-	table.ID = uuid.New()
-
-	// Broadcast table creation to collaborators FIRST
-	if s.collaborationService != nil {
-		if err := s.collaborationService.NotifyTableCreated(projectID, table, userID); err != nil {
-			// Log error but don't fail the operation
-			// TODO: Add proper logging
+	// Persist with the notification, so collaborators hear of the table once it is saved
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		id, err := tx.Tables.Create(table)
+		if err != nil {
+			return err
 		}
-	}
+		table.ID = id
 
-	// Then persist to database
-	id, err := s.tableRepo.Create(table)
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyTableCreated(projectID, table, userID); err != nil {
+				return err
+			}
+		}
+		table.Sequence = tx.Sequence
+		return nil
+	})
 	if err != nil {
-		return nil, err
+		return nil, takenError(err)
 	}
 
-	table.ID = id
-
 	return table, nil
 }
 
@@ -96,6 +119,11 @@ func (s *TableService) GetTablesByProjectID(projectID uuid.UUID) ([]*models.Tabl
 	return s.tableRepo.GetByProjectID(projectID)
 }
 
+func (s *TableService) ListTables(projectID uuid.UUID, filter repository.TableFilter, page repository.PageQuery) ([]*models.Table, string, error) {
+	filter.Name = strings.TrimSpace(filter.Name)
+	return s.tableRepo.ListByProjectID(projectID, filter, page)
+}
+
 func (s *TableService) UpdateTable(id uuid.UUID, req *dto.UpdateTableRequest, userID uuid.UUID) (*models.Table, error) {
 	table, err := s.tableRepo.GetByID(id)
 	if err != nil {
@@ -104,6 +132,9 @@ func (s *TableService) UpdateTable(id uuid.UUID, req *dto.UpdateTableRequest, us
 		}
 		return nil, err
 	}
+	if req.Version != nil && *req.Version != table.Version {
+		return table, ErrVersionConflict
+	}
 
 	// Only update fields that were provided
 	if req.Name != nil {
@@ -122,25 +153,56 @@ func (s *TableService) UpdateTable(id uuid.UUID, req *dto.UpdateTableRequest, us
 		table.PosY = *req.PosY
 	}
 
-	// Broadcast table update to collaborators FIRST
-	if s.collaborationService != nil {
-		if err := s.collaborationService.NotifyTableUpdated(table.ProjectID, table, userID); err != nil {
-			// Log error but don't fail the operation
-			// TODO: Add proper logging
+	if req.Color != nil {
+		table.Color = *req.Color
+	}
+
+	if req.Icon != nil {
+		table.Icon = *req.Icon
+	}
+
+	if req.Metadata != nil {
+		if table.Metadata, err = patchMetadata(table.Metadata, req.Metadata); err != nil {
+			return nil, err
 		}
 	}
 
-	// Then persist to database
-	if err := s.tableRepo.Update(table); err != nil {
-		return nil, err
+	// Persist with the notification, so a change that loses a concurrent update is never broadcast
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := tx.Tables.Update(table); err != nil {
+			return err
+		}
+
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyTableUpdated(table.ProjectID, table, userID); err != nil {
+				return err
+			}
+		}
+		table.Sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return s.currentTable(id)
+		}
+		return nil, takenError(err)
 	}
 
 	return table, nil
 }
 
+// currentTable returns the table as another writer left it, with ErrVersionConflict
+func (s *TableService) currentTable(id uuid.UUID) (*models.Table, error) {
+	table, err := s.tableRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return table, ErrVersionConflict
+}
+
 func (s *TableService) UpdateTablePosition(id uuid.UUID, posX, posY float64, userID uuid.UUID) error {
 	// Verify table exists
-	_, err := s.tableRepo.GetByID(id)
+	table, err := s.tableRepo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrTableNotFound
@@ -148,10 +210,20 @@ func (s *TableService) UpdateTablePosition(id uuid.UUID, posX, posY float64, use
 		return err
 	}
 
+	// Positions arrive over WebSockets too, naming any table
+	canModify, err := s.authService.CanUserModifyProject(userID, table.ProjectID)
+	if err != nil {
+		return err
+	}
+	if !canModify {
+		return ErrForbidden
+	}
+
 	err = s.tableRepo.UpdatePosition(id, posX, posY)
 	if err != nil {
 		return err
 	}
+	s.projectCache.Invalidate(table.ProjectID)
 
 	// Note: Position updates are handled via WebSocket MessageTypeTableMoved
 	// and do not create activity feed entries to avoid spam from drag operations
@@ -159,43 +231,48 @@ func (s *TableService) UpdateTablePosition(id uuid.UUID, posX, posY float64, use
 	return nil
 }
 
-func (s *TableService) DeleteTable(id uuid.UUID, userID uuid.UUID) error {
+func (s *TableService) DeleteTable(id uuid.UUID, userID uuid.UUID) (int64, error) {
 	// Get project ID from table
 	projectID, err := s.authService.GetProjectIDFromTable(id)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// Check authorization
 	canModify, err := s.authService.CanUserModifyProject(userID, projectID)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if !canModify {
-		return ErrForbidden
+		return 0, ErrForbidden
 	}
 
 	// Verify table exists and get table name
 	table, err := s.tableRepo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrTableNotFound
+			return 0, ErrTableNotFound
 		}
-		return err
+		return 0, err
 	}
 
-	// Notify collaborators about table deletion FIRST
-	if s.collaborationService != nil {
-		if err := s.collaborationService.NotifyTableDeleted(projectID, id, table.Name, userID); err != nil {
-			// Log error but don't fail the operation
-			// TODO: Add proper logging
+	// Delete with the notification, so collaborators never drop a table that is still there
+	var sequence int64
+	err = s.unitOfWork.Run(func(tx *Tx) error {
+		if err := tx.Tables.Delete(id); err != nil {
+			return err
 		}
-	}
 
-	// Then delete from database
-	if err := s.tableRepo.Delete(id); err != nil {
-		return err
+		if s.collaborationService != nil {
+			if err := s.collaborationService.InTx(tx).NotifyTableDeleted(projectID, id, table.Name, userID); err != nil {
+				return err
+			}
+		}
+		sequence = tx.Sequence
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
-
-	return nil
+	return sequence, nil
 }
@@ -1,11 +1,13 @@
 package services
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
 	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -77,7 +79,8 @@ func (suite *TableServiceTestSuite) SetupTest() {
 	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
 	suite.mockAuthService = new(mockTableAuthService)
 	suite.mockCollaborationService = new(mockCollaborationService)
-	suite.service = NewTableService(suite.mockTableRepo, suite.mockProjectRepo, suite.mockAuthService, suite.mockCollaborationService)
+	suite.service = NewTableService(suite.mockTableRepo, suite.mockProjectRepo, suite.mockAuthService, suite.mockCollaborationService, nil,
+		newTestUnitOfWork(repository.Repositories{Projects: suite.mockProjectRepo, Tables: suite.mockTableRepo}), Quotas{})
 }
 
 func TestTableServiceSuite(t *testing.T) {
@@ -107,7 +110,7 @@ func (suite *TableServiceTestSuite) TestCreateTable_Success() {
 	})).Return(tableID, nil)
 	suite.mockCollaborationService.On("NotifyTableCreated", projectID, mock.AnythingOfType("*models.Table"), userID).Return(nil)
 
-	result, err := suite.service.CreateTable(projectID, name, posX, posY, userID)
+	result, err := suite.service.CreateTable(projectID, &dto.CreateTableRequest{Name: name, PosX: posX, PosY: posY}, userID)
 
 	suite.NoError(err)
 	suite.NotNil(result)
@@ -122,12 +125,29 @@ func (suite *TableServiceTestSuite) TestCreateTable_Success() {
 	suite.mockTableRepo.AssertExpectations(suite.T())
 }
 
+func (suite *TableServiceTestSuite) TestCreateTable_QuotaExceeded() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.service.quotas = Quotas{MaxTablesPerProject: 2}
+
+	project := &models.Project{ID: projectID, Tables: []models.Table{{ID: uuid.New()}, {ID: uuid.New()}}}
+
+	suite.mockProjectRepo.On("GetByID", projectID).Return(project, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+
+	result, err := suite.service.CreateTable(projectID, &dto.CreateTableRequest{Name: "users"}, userID)
+
+	suite.Equal(&QuotaExceededError{Quota: QuotaTables, Limit: 2}, err)
+	suite.Nil(result)
+	suite.mockTableRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
 // Test CreateTable - Invalid Input (empty name)
 func (suite *TableServiceTestSuite) TestCreateTable_InvalidName() {
 	projectID := uuid.New()
 	userID := uuid.New()
 
-	result, err := suite.service.CreateTable(projectID, "", 100.0, 200.0, userID)
+	result, err := suite.service.CreateTable(projectID, &dto.CreateTableRequest{Name: "", PosX: 100.0, PosY: 200.0}, userID)
 
 	suite.Error(err)
 	suite.Nil(result)
@@ -140,7 +160,7 @@ func (suite *TableServiceTestSuite) TestCreateTable_NameTooLong() {
 	userID := uuid.New()
 	longName := string(make([]byte, 256))
 
-	result, err := suite.service.CreateTable(projectID, longName, 100.0, 200.0, userID)
+	result, err := suite.service.CreateTable(projectID, &dto.CreateTableRequest{Name: longName, PosX: 100.0, PosY: 200.0}, userID)
 
 	suite.Error(err)
 	suite.Nil(result)
@@ -155,7 +175,7 @@ func (suite *TableServiceTestSuite) TestCreateTable_ProjectNotFound() {
 
 	suite.mockProjectRepo.On("GetByID", projectID).Return(nil, gorm.ErrRecordNotFound)
 
-	result, err := suite.service.CreateTable(projectID, name, 100.0, 200.0, userID)
+	result, err := suite.service.CreateTable(projectID, &dto.CreateTableRequest{Name: name, PosX: 100.0, PosY: 200.0}, userID)
 
 	suite.Error(err)
 	suite.Nil(result)
@@ -178,10 +198,9 @@ func (suite *TableServiceTestSuite) TestCreateTable_RepositoryError() {
 
 	suite.mockProjectRepo.On("GetByID", projectID).Return(project, nil)
 	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
-	suite.mockCollaborationService.On("NotifyTableCreated", projectID, mock.AnythingOfType("*models.Table"), userID).Return(nil)
 	suite.mockTableRepo.On("Create", mock.AnythingOfType("*models.Table")).Return(uuid.Nil, assert.AnError)
 
-	result, err := suite.service.CreateTable(projectID, name, 100.0, 200.0, userID)
+	result, err := suite.service.CreateTable(projectID, &dto.CreateTableRequest{Name: name, PosX: 100.0, PosY: 200.0}, userID)
 
 	suite.Error(err)
 	suite.Nil(result)
@@ -189,10 +208,31 @@ func (suite *TableServiceTestSuite) TestCreateTable_RepositoryError() {
 
 	suite.mockProjectRepo.AssertExpectations(suite.T())
 	suite.mockAuthService.AssertExpectations(suite.T())
-	suite.mockCollaborationService.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertNotCalled(suite.T(), "NotifyTableCreated", mock.Anything, mock.Anything, mock.Anything)
 	suite.mockTableRepo.AssertExpectations(suite.T())
 }
 
+// Test CreateTable - Name taken by a table created concurrently
+func (suite *TableServiceTestSuite) TestCreateTable_NameTaken() {
+	projectID := uuid.New()
+	userID := uuid.New()
+	project := &models.Project{ID: projectID, OwnerID: uuid.New()}
+
+	suite.mockProjectRepo.On("GetByID", projectID).Return(project, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockTableRepo.On("Create", mock.AnythingOfType("*models.Table")).
+		Return(uuid.Nil, &repository.DuplicateError{Index: repository.IndexTableName})
+
+	result, err := suite.service.CreateTable(projectID, &dto.CreateTableRequest{Name: "users"}, userID)
+
+	suite.Nil(result)
+	suite.ErrorIs(err, ErrNameTaken)
+	var takenErr *TakenError
+	suite.Require().ErrorAs(err, &takenErr)
+	suite.Equal("name", takenErr.Field)
+	suite.mockCollaborationService.AssertNotCalled(suite.T(), "NotifyTableCreated", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Test GetTableByID - Success
 func (suite *TableServiceTestSuite) TestGetTableByID_Success() {
 	tableID := uuid.New()
@@ -272,6 +312,59 @@ func (suite *TableServiceTestSuite) TestUpdateTable_Success() {
 	suite.mockTableRepo.AssertExpectations(suite.T())
 }
 
+// Test UpdateTable - Color and icon are changed and sent to collaborators
+func (suite *TableServiceTestSuite) TestUpdateTable_Appearance() {
+	existingTable := createTestTable(uuid.New())
+	existingTable.Color = "#ef4444"
+	existingTable.Icon = "users"
+	color := "#22c55e"
+	icon := ""
+
+	suite.mockTableRepo.On("GetByID", existingTable.ID).Return(existingTable, nil)
+	suite.mockTableRepo.On("Update", mock.MatchedBy(func(table *models.Table) bool {
+		return table.Color == color && table.Icon == "" && table.Name == "Test Table"
+	})).Return(nil)
+	suite.mockCollaborationService.On("NotifyTableUpdated", existingTable.ProjectID, existingTable, mock.AnythingOfType("uuid.UUID")).Return(nil)
+
+	result, err := suite.service.UpdateTable(existingTable.ID, &dto.UpdateTableRequest{Color: &color, Icon: &icon}, uuid.New())
+
+	suite.NoError(err)
+	suite.Equal(color, result.Color)
+	suite.Empty(result.Icon)
+	suite.mockTableRepo.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertExpectations(suite.T())
+}
+
+// Test UpdateTable - Metadata is merged: null members are removed, others set
+func (suite *TableServiceTestSuite) TestUpdateTable_Metadata() {
+	existingTable := createTestTable(uuid.New())
+	existingTable.Metadata = models.Metadata{"data_owner": "crm", "retention": map[string]any{"days": 30.0, "legal_hold": true}}
+	patch := map[string]any{"data_owner": nil, "pii": true, "retention": map[string]any{"legal_hold": nil}}
+
+	suite.mockTableRepo.On("GetByID", existingTable.ID).Return(existingTable, nil)
+	suite.mockTableRepo.On("Update", mock.AnythingOfType("*models.Table")).Return(nil)
+	suite.mockCollaborationService.On("NotifyTableUpdated", existingTable.ProjectID, existingTable, mock.AnythingOfType("uuid.UUID")).Return(nil)
+
+	result, err := suite.service.UpdateTable(existingTable.ID, &dto.UpdateTableRequest{Metadata: &patch}, uuid.New())
+
+	suite.NoError(err)
+	suite.Equal(models.Metadata{"pii": true, "retention": map[string]any{"days": 30.0}}, result.Metadata)
+}
+
+// Test UpdateTable - Metadata over the limits is rejected
+func (suite *TableServiceTestSuite) TestUpdateTable_MetadataTooLarge() {
+	existingTable := createTestTable(uuid.New())
+	patch := map[string]any{strings.Repeat("k", models.MaxMetadataKeyLength+1): "v"}
+
+	suite.mockTableRepo.On("GetByID", existingTable.ID).Return(existingTable, nil)
+
+	result, err := suite.service.UpdateTable(existingTable.ID, &dto.UpdateTableRequest{Metadata: &patch}, uuid.New())
+
+	suite.Equal(ErrInvalidInput, err)
+	suite.Nil(result)
+	suite.mockTableRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
 // Test UpdateTable - Not Found
 func (suite *TableServiceTestSuite) TestUpdateTable_NotFound() {
 	tableID := uuid.New()
@@ -312,6 +405,53 @@ func (suite *TableServiceTestSuite) TestUpdateTable_InvalidName() {
 	suite.mockTableRepo.AssertExpectations(suite.T())
 }
 
+// Test UpdateTable - A version other than the current one is rejected with the current table
+func (suite *TableServiceTestSuite) TestUpdateTable_StaleVersion() {
+	tableID := uuid.New()
+	existingTable := createTestTable(uuid.New())
+	existingTable.ID = tableID
+	existingTable.Version = 3
+
+	staleVersion := int64(2)
+	updateRequest := &dto.UpdateTableRequest{
+		Name:    tableStringPtr("Renamed"),
+		Version: &staleVersion,
+	}
+
+	suite.mockTableRepo.On("GetByID", tableID).Return(existingTable, nil)
+
+	result, err := suite.service.UpdateTable(tableID, updateRequest, uuid.New())
+
+	suite.ErrorIs(err, ErrVersionConflict)
+	suite.Equal(existingTable, result)
+	suite.Equal("Test Table", result.Name)
+	suite.mockTableRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+	suite.mockCollaborationService.AssertNotCalled(suite.T(), "NotifyTableUpdated", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test UpdateTable - A writer that saved in between wins, and nothing is broadcast
+func (suite *TableServiceTestSuite) TestUpdateTable_ConcurrentUpdate() {
+	tableID := uuid.New()
+	projectID := uuid.New()
+	readTable := createTestTable(projectID)
+	readTable.ID = tableID
+	currentTable := createTestTable(projectID)
+	currentTable.ID = tableID
+	currentTable.Name = "Saved first"
+	currentTable.Version = 2
+
+	suite.mockTableRepo.On("GetByID", tableID).Return(readTable, nil).Once()
+	suite.mockTableRepo.On("Update", mock.AnythingOfType("*models.Table")).Return(repository.ErrVersionConflict)
+	suite.mockTableRepo.On("GetByID", tableID).Return(currentTable, nil).Once()
+
+	result, err := suite.service.UpdateTable(tableID, &dto.UpdateTableRequest{Name: tableStringPtr("Saved second")}, uuid.New())
+
+	suite.ErrorIs(err, ErrVersionConflict)
+	suite.Equal("Saved first", result.Name)
+	suite.mockTableRepo.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertNotCalled(suite.T(), "NotifyTableUpdated", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Test UpdateTablePosition - Success
 func (suite *TableServiceTestSuite) TestUpdateTablePosition_Success() {
 	tableID := uuid.New()
@@ -321,16 +461,33 @@ func (suite *TableServiceTestSuite) TestUpdateTablePosition_Success() {
 	existingTable := createTestTable(uuid.New())
 	existingTable.ID = tableID
 
+	userID := uuid.New()
+
 	suite.mockTableRepo.On("GetByID", tableID).Return(existingTable, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, existingTable.ProjectID).Return(true, nil)
 	suite.mockTableRepo.On("UpdatePosition", tableID, newPosX, newPosY).Return(nil)
 	suite.mockCollaborationService.On("NotifyTableUpdated", existingTable.ProjectID, mock.AnythingOfType("*models.Table"), mock.AnythingOfType("uuid.UUID")).Return(nil)
 
-	err := suite.service.UpdateTablePosition(tableID, newPosX, newPosY, uuid.New())
+	err := suite.service.UpdateTablePosition(tableID, newPosX, newPosY, userID)
 
 	suite.NoError(err)
 	suite.mockTableRepo.AssertExpectations(suite.T())
 }
 
+// Test UpdateTablePosition - Users who cannot modify the table's project are rejected
+func (suite *TableServiceTestSuite) TestUpdateTablePosition_Forbidden() {
+	existingTable := createTestTable(uuid.New())
+	userID := uuid.New()
+
+	suite.mockTableRepo.On("GetByID", existingTable.ID).Return(existingTable, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, existingTable.ProjectID).Return(false, nil)
+
+	err := suite.service.UpdateTablePosition(existingTable.ID, 1, 2, userID)
+
+	suite.Equal(ErrForbidden, err)
+	suite.mockTableRepo.AssertNotCalled(suite.T(), "UpdatePosition", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Test UpdateTablePosition - Table Not Found
 func (suite *TableServiceTestSuite) TestUpdateTablePosition_NotFound() {
 	tableID := uuid.New()
@@ -355,10 +512,13 @@ func (suite *TableServiceTestSuite) TestUpdateTablePosition_RepositoryError() {
 	existingTable := createTestTable(uuid.New())
 	existingTable.ID = tableID
 
+	userID := uuid.New()
+
 	suite.mockTableRepo.On("GetByID", tableID).Return(existingTable, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, existingTable.ProjectID).Return(true, nil)
 	suite.mockTableRepo.On("UpdatePosition", tableID, newPosX, newPosY).Return(assert.AnError)
 
-	err := suite.service.UpdateTablePosition(tableID, newPosX, newPosY, uuid.New())
+	err := suite.service.UpdateTablePosition(tableID, newPosX, newPosY, userID)
 
 	suite.Error(err)
 	suite.Equal(assert.AnError, err)
@@ -379,7 +539,7 @@ func (suite *TableServiceTestSuite) TestDeleteTable_Success() {
 	suite.mockTableRepo.On("Delete", tableID).Return(nil)
 	suite.mockCollaborationService.On("NotifyTableDeleted", projectID, tableID, existingTable.Name, userID).Return(nil)
 
-	err := suite.service.DeleteTable(tableID, userID)
+	_, err := suite.service.DeleteTable(tableID, userID)
 
 	suite.NoError(err)
 	suite.mockAuthService.AssertExpectations(suite.T())
@@ -393,7 +553,7 @@ func (suite *TableServiceTestSuite) TestDeleteTable_NotFound() {
 
 	suite.mockAuthService.On("GetProjectIDFromTable", tableID).Return(uuid.Nil, ErrTableNotFound)
 
-	err := suite.service.DeleteTable(tableID, userID)
+	_, err := suite.service.DeleteTable(tableID, userID)
 
 	suite.Error(err)
 	suite.Equal(ErrTableNotFound, err)
@@ -410,7 +570,7 @@ func (suite *TableServiceTestSuite) TestDeleteTable_Forbidden() {
 	suite.mockAuthService.On("GetProjectIDFromTable", tableID).Return(projectID, nil)
 	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(false, nil)
 
-	err := suite.service.DeleteTable(tableID, userID)
+	_, err := suite.service.DeleteTable(tableID, userID)
 
 	suite.Error(err)
 	suite.Equal(ErrForbidden, err)
@@ -429,15 +589,55 @@ func (suite *TableServiceTestSuite) TestDeleteTable_RepositoryError() {
 	suite.mockAuthService.On("GetProjectIDFromTable", tableID).Return(projectID, nil)
 	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
 	suite.mockTableRepo.On("GetByID", tableID).Return(existingTable, nil)
-	suite.mockCollaborationService.On("NotifyTableDeleted", projectID, tableID, existingTable.Name, userID).Return(nil)
 	suite.mockTableRepo.On("Delete", tableID).Return(assert.AnError)
 
-	err := suite.service.DeleteTable(tableID, userID)
+	_, err := suite.service.DeleteTable(tableID, userID)
 
 	suite.Error(err)
 	suite.Equal(assert.AnError, err)
 
 	suite.mockAuthService.AssertExpectations(suite.T())
-	suite.mockCollaborationService.AssertExpectations(suite.T())
+	suite.mockCollaborationService.AssertNotCalled(suite.T(), "NotifyTableDeleted", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	suite.mockTableRepo.AssertExpectations(suite.T())
 }
+
+// Test DeleteTable - Collaborators hear of the deletion only after it
+func (suite *TableServiceTestSuite) TestDeleteTable_NotifiesAfterDelete() {
+	tableID := uuid.New()
+	userID := uuid.New()
+	projectID := uuid.New()
+	existingTable := createTestTable(projectID)
+	existingTable.ID = tableID
+
+	var calls []string
+	suite.mockAuthService.On("GetProjectIDFromTable", tableID).Return(projectID, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockTableRepo.On("GetByID", tableID).Return(existingTable, nil)
+	suite.mockTableRepo.On("Delete", tableID).Return(nil).Run(func(mock.Arguments) { calls = append(calls, "delete") })
+	suite.mockCollaborationService.On("NotifyTableDeleted", projectID, tableID, existingTable.Name, userID).
+		Return(nil).Run(func(mock.Arguments) { calls = append(calls, "notify") })
+
+	_, err := suite.service.DeleteTable(tableID, userID)
+
+	suite.NoError(err)
+	suite.Equal([]string{"delete", "notify"}, calls)
+}
+
+// Test DeleteTable - A notification that cannot be saved fails the deletion
+func (suite *TableServiceTestSuite) TestDeleteTable_NotifyError() {
+	tableID := uuid.New()
+	userID := uuid.New()
+	projectID := uuid.New()
+	existingTable := createTestTable(projectID)
+	existingTable.ID = tableID
+
+	suite.mockAuthService.On("GetProjectIDFromTable", tableID).Return(projectID, nil)
+	suite.mockAuthService.On("CanUserModifyProject", userID, projectID).Return(true, nil)
+	suite.mockTableRepo.On("GetByID", tableID).Return(existingTable, nil)
+	suite.mockTableRepo.On("Delete", tableID).Return(nil)
+	suite.mockCollaborationService.On("NotifyTableDeleted", projectID, tableID, existingTable.Name, userID).Return(assert.AnError)
+
+	_, err := suite.service.DeleteTable(tableID, userID)
+
+	suite.Equal(assert.AnError, err)
+}
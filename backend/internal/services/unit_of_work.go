@@ -0,0 +1,50 @@
+package services
+
+import "github.com/Bug-Bugger/ezmodel/internal/repository"
+
+// UnitOfWork runs changes spanning several entities in one transaction and
+// holds back their side effects, such as notifying collaborators, until the
+// transaction commits
+type UnitOfWork struct {
+	unitOfWork repository.UnitOfWorkInterface
+}
+
+func NewUnitOfWork(unitOfWork repository.UnitOfWorkInterface) *UnitOfWork {
+	return &UnitOfWork{unitOfWork: unitOfWork}
+}
+
+// Tx gives the work its transactional repositories
+type Tx struct {
+	repository.Repositories
+	afterCommit []func()
+
+	// Sequence is the project sequence given to the last notification sent in
+	// the transaction, or 0 when none was
+	Sequence int64
+}
+
+// AfterCommit queues fn to run once the transaction has committed. Nothing
+// queued runs when the work fails.
+func (tx *Tx) AfterCommit(fn func()) {
+	tx.afterCommit = append(tx.afterCommit, fn)
+}
+
+// Run runs fn in a transaction, then the functions it queued with AfterCommit
+// in order. An error from fn rolls every change back and is returned as is.
+func (u *UnitOfWork) Run(fn func(tx *Tx) error) error {
+	tx := &Tx{}
+	err := u.unitOfWork.Do(func(repos repository.Repositories) error {
+		tx.Repositories = repos
+		tx.afterCommit = nil
+		tx.Sequence = 0
+		return fn(tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, fn := range tx.afterCommit {
+		fn()
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// newTestUnitOfWork runs work against the given repositories, usually the
+// suite's mocks, and is not required to be used
+func newTestUnitOfWork(repos repository.Repositories) *UnitOfWork {
+	mockUnitOfWork := &mockRepo.MockUnitOfWork{Repos: repos}
+	mockUnitOfWork.On("Do").Return(nil).Maybe()
+	return NewUnitOfWork(mockUnitOfWork)
+}
+
+func TestUnitOfWorkRunsAfterCommitOnSuccess(t *testing.T) {
+	mockUnitOfWork := new(mockRepo.MockUnitOfWork)
+	mockUnitOfWork.On("Do").Return(nil)
+
+	var ran []int
+	err := NewUnitOfWork(mockUnitOfWork).Run(func(tx *Tx) error {
+		tx.AfterCommit(func() { ran = append(ran, 1) })
+		tx.AfterCommit(func() { ran = append(ran, 2) })
+		assert.Empty(t, ran, "Nothing should run before the commit")
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, ran)
+}
+
+func TestUnitOfWorkSkipsAfterCommitOnFailure(t *testing.T) {
+	mockUnitOfWork := new(mockRepo.MockUnitOfWork)
+	mockUnitOfWork.On("Do").Return(nil)
+
+	ran := false
+	err := NewUnitOfWork(mockUnitOfWork).Run(func(tx *Tx) error {
+		tx.AfterCommit(func() { ran = true })
+		return gorm.ErrInvalidDB
+	})
+
+	assert.ErrorIs(t, err, gorm.ErrInvalidDB)
+	assert.False(t, ran)
+}
+
+func TestUnitOfWorkReturnsTransactionError(t *testing.T) {
+	mockUnitOfWork := new(mockRepo.MockUnitOfWork)
+	mockUnitOfWork.On("Do").Return(gorm.ErrInvalidTransaction)
+
+	err := NewUnitOfWork(mockUnitOfWork).Run(func(tx *Tx) error {
+		t.Fatal("Work should not run when the transaction cannot start")
+		return nil
+	})
+
+	assert.ErrorIs(t, err, gorm.ErrInvalidTransaction)
+}
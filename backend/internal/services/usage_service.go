@@ -0,0 +1,162 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+)
+
+// Names of the quotas, as reported by QuotaExceededError
+const (
+	QuotaProjects      = "projects"
+	QuotaTables        = "tables"
+	QuotaCollaborators = "collaborators"
+)
+
+// Quotas limit what users and projects may hold, e.g. for the plans of a
+// hosted service. A limit that is not positive does not limit anything.
+type Quotas struct {
+	MaxProjectsPerUser  int // Projects a user owns
+	MaxTablesPerProject int
+	MaxCollaborators    int // Collaborators of one project besides its owner; service accounts do not count
+}
+
+// QuotaPolicy decides the quotas of a user and the projects they own, e.g. by
+// the plan they pay for. Quotas is a policy applying the same ones to everyone.
+type QuotaPolicy interface {
+	QuotasFor(ownerID uuid.UUID) (Quotas, error)
+}
+
+// QuotasFor implements QuotaPolicy
+func (q Quotas) QuotasFor(uuid.UUID) (Quotas, error) {
+	return q, nil
+}
+
+// QuotaExceededError is returned for a change that would take a user or
+// project over one of its quotas. It matches ErrQuotaExceeded.
+type QuotaExceededError struct {
+	Quota string // QuotaProjects, QuotaTables or QuotaCollaborators
+	Limit int
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s quota of %d exceeded", e.Quota, e.Limit)
+}
+
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// CheckProjects fails when a user would own more than count projects
+func (q Quotas) CheckProjects(count int) error {
+	return checkQuota(QuotaProjects, q.MaxProjectsPerUser, count)
+}
+
+// CheckTables fails when a project would have more than count tables
+func (q Quotas) CheckTables(count int) error {
+	return checkQuota(QuotaTables, q.MaxTablesPerProject, count)
+}
+
+// CheckCollaborators fails when a project would have more than count collaborators
+func (q Quotas) CheckCollaborators(count int) error {
+	return checkQuota(QuotaCollaborators, q.MaxCollaborators, count)
+}
+
+func checkQuota(quota string, limit, count int) error {
+	if limit > 0 && count > limit {
+		return &QuotaExceededError{Quota: quota, Limit: limit}
+	}
+	return nil
+}
+
+// countCollaborators counts the collaborators the quota applies to
+func countCollaborators(collaborators []models.User) int {
+	count := 0
+	for _, collaborator := range collaborators {
+		if !collaborator.IsServiceAccount {
+			count++
+		}
+	}
+	return count
+}
+
+// QuotaUsage is how much of a quota is used. Limit is 0 when there is none.
+type QuotaUsage struct {
+	Used  int
+	Limit int
+}
+
+// ProjectUsage is how much of the per-project quotas a project uses
+type ProjectUsage struct {
+	ProjectID     uuid.UUID
+	Name          string
+	Tables        QuotaUsage
+	Collaborators QuotaUsage
+}
+
+// Usage is how much of the quotas a user and the projects they own use
+type Usage struct {
+	Projects      QuotaUsage
+	OwnedProjects []ProjectUsage
+}
+
+// UsageService reports usage against the quotas the other services enforce
+type UsageService struct {
+	projectRepo repository.ProjectRepositoryInterface
+	tableRepo   repository.TableRepositoryInterface
+	quotas      QuotaPolicy
+}
+
+func NewUsageService(projectRepo repository.ProjectRepositoryInterface, tableRepo repository.TableRepositoryInterface, quotas QuotaPolicy) *UsageService {
+	return &UsageService{
+		projectRepo: projectRepo,
+		tableRepo:   tableRepo,
+		quotas:      quotas,
+	}
+}
+
+// GetUsage returns the usage of the user and of each project they own
+func (s *UsageService) GetUsage(userID uuid.UUID) (*Usage, error) {
+	quotas, err := s.quotas.QuotasFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := s.projectRepo.GetByOwnerID(userID, repository.ProjectIncludes{})
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs := make([]uuid.UUID, len(projects))
+	for i, project := range projects {
+		projectIDs[i] = project.ID
+	}
+	tableCounts, err := s.tableRepo.CountByProjectIDs(projectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &Usage{
+		Projects:      QuotaUsage{Used: len(projects), Limit: limitOrZero(quotas.MaxProjectsPerUser)},
+		OwnedProjects: make([]ProjectUsage, len(projects)),
+	}
+	for i, project := range projects {
+		usage.OwnedProjects[i] = ProjectUsage{
+			ProjectID:     project.ID,
+			Name:          project.Name,
+			Tables:        QuotaUsage{Used: tableCounts[project.ID], Limit: limitOrZero(quotas.MaxTablesPerProject)},
+			Collaborators: QuotaUsage{Used: countCollaborators(project.Collaborators), Limit: limitOrZero(quotas.MaxCollaborators)},
+		}
+	}
+	return usage, nil
+}
+
+// limitOrZero reports a limit that does not limit anything as 0
+func limitOrZero(limit int) int {
+	if limit > 0 {
+		return limit
+	}
+	return 0
+}
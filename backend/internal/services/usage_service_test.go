@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type UsageServiceTestSuite struct {
+	suite.Suite
+	mockProjectRepo *mockRepo.MockProjectRepository
+	mockTableRepo   *mockRepo.MockTableRepository
+	service         *UsageService
+	userID          uuid.UUID
+}
+
+func (suite *UsageServiceTestSuite) SetupTest() {
+	suite.mockProjectRepo = new(mockRepo.MockProjectRepository)
+	suite.mockTableRepo = new(mockRepo.MockTableRepository)
+	suite.service = NewUsageService(suite.mockProjectRepo, suite.mockTableRepo, Quotas{MaxProjectsPerUser: 5, MaxTablesPerProject: 50})
+	suite.userID = uuid.New()
+}
+
+func TestUsageServiceSuite(t *testing.T) {
+	suite.Run(t, new(UsageServiceTestSuite))
+}
+
+// Test GetUsage - Counts per owned project, without service accounts
+func (suite *UsageServiceTestSuite) TestGetUsage_Success() {
+	shop := &models.Project{ID: uuid.New(), Name: "Shop", Collaborators: []models.User{
+		{ID: uuid.New()}, {ID: uuid.New(), IsServiceAccount: true},
+	}}
+	blog := &models.Project{ID: uuid.New(), Name: "Blog"}
+
+	suite.mockProjectRepo.On("GetByOwnerID", suite.userID, repository.ProjectIncludes{}).Return([]*models.Project{shop, blog}, nil)
+	suite.mockTableRepo.On("CountByProjectIDs", []uuid.UUID{shop.ID, blog.ID}).Return(map[uuid.UUID]int{shop.ID: 12}, nil)
+
+	usage, err := suite.service.GetUsage(suite.userID)
+
+	suite.NoError(err)
+	suite.Equal(QuotaUsage{Used: 2, Limit: 5}, usage.Projects)
+	suite.Equal([]ProjectUsage{
+		{ProjectID: shop.ID, Name: "Shop", Tables: QuotaUsage{Used: 12, Limit: 50}, Collaborators: QuotaUsage{Used: 1}},
+		{ProjectID: blog.ID, Name: "Blog", Tables: QuotaUsage{Used: 0, Limit: 50}},
+	}, usage.OwnedProjects)
+}
+
+// Test Quotas - Limits that are not positive do not limit anything
+func (suite *UsageServiceTestSuite) TestQuotas_Check() {
+	quotas := Quotas{MaxTablesPerProject: 2, MaxCollaborators: -1}
+
+	suite.NoError(quotas.CheckTables(2))
+	suite.ErrorIs(quotas.CheckTables(3), ErrQuotaExceeded)
+	suite.NoError(quotas.CheckProjects(1000))
+	suite.NoError(quotas.CheckCollaborators(1000))
+}
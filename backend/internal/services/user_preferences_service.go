@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	preferenceThemes        = []string{"light", "dark", "system"}
+	preferenceDatabaseTypes = []string{"postgresql", "mysql", "sqlite", "sqlserver"}
+)
+
+const (
+	minCanvasGridSize = 5
+	maxCanvasGridSize = 100
+)
+
+type UserPreferencesService struct {
+	preferencesRepo repository.UserPreferencesRepositoryInterface
+}
+
+func NewUserPreferencesService(preferencesRepo repository.UserPreferencesRepositoryInterface) *UserPreferencesService {
+	return &UserPreferencesService{
+		preferencesRepo: preferencesRepo,
+	}
+}
+
+// GetPreferences returns the user's preferences, or the defaults when the user
+// has never changed one
+func (s *UserPreferencesService) GetPreferences(userID uuid.UUID) (*models.UserPreferences, error) {
+	preferences, err := s.preferencesRepo.GetByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.DefaultUserPreferences(userID), nil
+	}
+	return preferences, err
+}
+
+// UpdatePreferences changes the preferences that were provided and keeps the rest
+func (s *UserPreferencesService) UpdatePreferences(userID uuid.UUID, req *dto.UpdateUserPreferencesRequest) (*models.UserPreferences, error) {
+	preferences, err := s.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Theme != nil {
+		if !slices.Contains(preferenceThemes, *req.Theme) {
+			return nil, ErrInvalidInput
+		}
+		preferences.Theme = *req.Theme
+	}
+	if req.DefaultDatabaseType != nil {
+		if !slices.Contains(preferenceDatabaseTypes, *req.DefaultDatabaseType) {
+			return nil, ErrInvalidInput
+		}
+		preferences.DefaultDatabaseType = *req.DefaultDatabaseType
+	}
+	if req.CanvasGridSize != nil {
+		if *req.CanvasGridSize < minCanvasGridSize || *req.CanvasGridSize > maxCanvasGridSize {
+			return nil, ErrInvalidInput
+		}
+		preferences.CanvasGridSize = *req.CanvasGridSize
+	}
+	if req.CanvasShowGrid != nil {
+		preferences.CanvasShowGrid = *req.CanvasShowGrid
+	}
+	if req.CanvasSnapToGrid != nil {
+		preferences.CanvasSnapToGrid = *req.CanvasSnapToGrid
+	}
+	if req.NotifyCollaboratorJoined != nil {
+		preferences.NotifyCollaboratorJoined = *req.NotifyCollaboratorJoined
+	}
+	if req.NotifyProjectShared != nil {
+		preferences.NotifyProjectShared = *req.NotifyProjectShared
+	}
+	if req.EmailNotifications != nil {
+		preferences.EmailNotifications = *req.EmailNotifications
+	}
+
+	if err := s.preferencesRepo.Save(preferences); err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type UserPreferencesServiceTestSuite struct {
+	suite.Suite
+	mockPreferencesRepo *mockRepo.MockUserPreferencesRepository
+	service             *UserPreferencesService
+	userID              uuid.UUID
+}
+
+func (suite *UserPreferencesServiceTestSuite) SetupTest() {
+	suite.mockPreferencesRepo = new(mockRepo.MockUserPreferencesRepository)
+	suite.service = NewUserPreferencesService(suite.mockPreferencesRepo)
+	suite.userID = uuid.New()
+}
+
+func TestUserPreferencesServiceSuite(t *testing.T) {
+	suite.Run(t, new(UserPreferencesServiceTestSuite))
+}
+
+// Test GetPreferences - Defaults for users without saved preferences
+func (suite *UserPreferencesServiceTestSuite) TestGetPreferences_Defaults() {
+	suite.mockPreferencesRepo.On("GetByUserID", suite.userID).Return(nil, gorm.ErrRecordNotFound)
+
+	preferences, err := suite.service.GetPreferences(suite.userID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), models.DefaultUserPreferences(suite.userID), preferences)
+}
+
+// Test UpdatePreferences - Only provided preferences change
+func (suite *UserPreferencesServiceTestSuite) TestUpdatePreferences_Partial() {
+	stored := models.DefaultUserPreferences(suite.userID)
+	stored.Theme = "dark"
+	theme := "light"
+	showGrid := false
+
+	suite.mockPreferencesRepo.On("GetByUserID", suite.userID).Return(stored, nil)
+	suite.mockPreferencesRepo.On("Save", mock.MatchedBy(func(p *models.UserPreferences) bool {
+		return p.Theme == "light" && !p.CanvasShowGrid && p.CanvasGridSize == 20 && p.EmailNotifications
+	})).Return(nil)
+
+	preferences, err := suite.service.UpdatePreferences(suite.userID, &dto.UpdateUserPreferencesRequest{Theme: &theme, CanvasShowGrid: &showGrid})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "light", preferences.Theme)
+	suite.mockPreferencesRepo.AssertExpectations(suite.T())
+}
+
+// Test UpdatePreferences - Invalid values are rejected
+func (suite *UserPreferencesServiceTestSuite) TestUpdatePreferences_InvalidInput() {
+	theme := "neon"
+	gridSize := 1
+
+	suite.mockPreferencesRepo.On("GetByUserID", suite.userID).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := suite.service.UpdatePreferences(suite.userID, &dto.UpdateUserPreferencesRequest{Theme: &theme})
+	assert.ErrorIs(suite.T(), err, ErrInvalidInput)
+
+	_, err = suite.service.UpdatePreferences(suite.userID, &dto.UpdateUserPreferencesRequest{CanvasGridSize: &gridSize})
+	assert.ErrorIs(suite.T(), err, ErrInvalidInput)
+
+	suite.mockPreferencesRepo.AssertNotCalled(suite.T(), "Save", mock.Anything)
+}
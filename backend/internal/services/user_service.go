@@ -33,7 +33,7 @@ func (s *UserService) CreateUser(email, username, password string) (*models.User
 	// Check if email already exists
 	existingUser, err := s.userRepo.GetByEmail(email)
 	if err == nil && existingUser != nil {
-		return nil, ErrUserAlreadyExists
+		return nil, &TakenError{Field: "email", Err: ErrUserAlreadyExists}
 	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	}
@@ -48,11 +48,12 @@ func (s *UserService) CreateUser(email, username, password string) (*models.User
 		Email:        email,
 		Username:     username,
 		PasswordHash: string(hashedPassword),
+		Role:         models.RoleUser,
 	}
 
 	id, err := s.userRepo.Create(user)
 	if err != nil {
-		return nil, err
+		return nil, takenError(err)
 	}
 
 	user.ID = id
@@ -81,8 +82,9 @@ func (s *UserService) GetUserByEmail(email string) (*models.User, error) {
 	return user, nil
 }
 
-func (s *UserService) GetAllUsers() ([]*models.User, error) {
-	return s.userRepo.GetAll()
+func (s *UserService) ListUsers(filter repository.UserFilter, page repository.PageQuery) ([]*models.User, string, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	return s.userRepo.List(filter, page)
 }
 
 func (s *UserService) UpdateUser(id uuid.UUID, req *dto.UpdateUserRequest) (*models.User, error) {
@@ -113,7 +115,7 @@ func (s *UserService) UpdateUser(id uuid.UUID, req *dto.UpdateUserRequest) (*mod
 		if email != user.Email {
 			existingUser, err := s.userRepo.GetByEmail(email)
 			if err == nil && existingUser != nil && existingUser.ID != id {
-				return nil, ErrUserAlreadyExists
+				return nil, &TakenError{Field: "email", Err: ErrUserAlreadyExists}
 			} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, err
 			}
@@ -123,7 +125,7 @@ func (s *UserService) UpdateUser(id uuid.UUID, req *dto.UpdateUserRequest) (*mod
 	}
 
 	if err := s.userRepo.Update(user); err != nil {
-		return nil, err
+		return nil, takenError(err)
 	}
 
 	return user, nil
@@ -155,6 +157,7 @@ func (s *UserService) UpdatePassword(id uuid.UUID, currentPassword, newPassword
 	}
 
 	user.PasswordHash = string(hashedPassword)
+	user.PasswordResetRequired = false
 	return s.userRepo.Update(user)
 }
 
@@ -6,6 +6,7 @@ import (
 	"github.com/Bug-Bugger/ezmodel/internal/api/dto"
 	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
 	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -125,11 +126,31 @@ func (suite *UserServiceTestSuite) TestCreateUser_UserAlreadyExists() {
 
 	suite.Error(err)
 	suite.Nil(result)
-	suite.Equal(ErrUserAlreadyExists, err)
+	suite.ErrorIs(err, ErrUserAlreadyExists)
+	var takenErr *TakenError
+	suite.Require().ErrorAs(err, &takenErr)
+	suite.Equal("email", takenErr.Field)
 
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
+// Test CreateUser - Username taken by a concurrent sign-up
+func (suite *UserServiceTestSuite) TestCreateUser_UsernameTaken() {
+	email := "test@example.com"
+
+	suite.mockRepo.On("GetByEmail", email).Return(nil, gorm.ErrRecordNotFound)
+	suite.mockRepo.On("Create", mock.AnythingOfType("*models.User")).
+		Return(uuid.Nil, &repository.DuplicateError{Index: repository.IndexUserUsername})
+
+	result, err := suite.service.CreateUser(email, "testuser", "password123")
+
+	suite.Nil(result)
+	suite.ErrorIs(err, ErrUserAlreadyExists)
+	var takenErr *TakenError
+	suite.Require().ErrorAs(err, &takenErr)
+	suite.Equal("username", takenErr.Field)
+}
+
 // Test CreateUser - Repository Error on Email Check
 func (suite *UserServiceTestSuite) TestCreateUser_RepositoryErrorOnEmailCheck() {
 	email := "test@example.com"
@@ -284,7 +305,10 @@ func (suite *UserServiceTestSuite) TestUpdateUser_EmailAlreadyExists() {
 
 	suite.Error(err)
 	suite.Nil(result)
-	suite.Equal(ErrUserAlreadyExists, err)
+	suite.ErrorIs(err, ErrUserAlreadyExists)
+	var takenErr *TakenError
+	suite.Require().ErrorAs(err, &takenErr)
+	suite.Equal("email", takenErr.Field)
 
 	suite.mockRepo.AssertExpectations(suite.T())
 }
@@ -433,29 +457,31 @@ func (suite *UserServiceTestSuite) TestAuthenticateUser_UserNotFound() {
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
-// Test GetAllUsers - Success
-func (suite *UserServiceTestSuite) TestGetAllUsers_Success() {
+// Test ListUsers - Success
+func (suite *UserServiceTestSuite) TestListUsers_Success() {
 	expectedUsers := []*models.User{
 		createTestUser(),
 		createTestUser(),
 	}
+	page := repository.PageQuery{Limit: 2}
 
-	suite.mockRepo.On("GetAll").Return(expectedUsers, nil)
+	suite.mockRepo.On("List", repository.UserFilter{Query: "test"}, page).Return(expectedUsers, "next", nil)
 
-	result, err := suite.service.GetAllUsers()
+	result, next, err := suite.service.ListUsers(repository.UserFilter{Query: " test "}, page)
 
 	suite.NoError(err)
 	suite.NotNil(result)
 	suite.Len(result, 2)
+	suite.Equal("next", next)
 
 	suite.mockRepo.AssertExpectations(suite.T())
 }
 
-// Test GetAllUsers - Repository Error
-func (suite *UserServiceTestSuite) TestGetAllUsers_RepositoryError() {
-	suite.mockRepo.On("GetAll").Return(nil, assert.AnError)
+// Test ListUsers - Repository Error
+func (suite *UserServiceTestSuite) TestListUsers_RepositoryError() {
+	suite.mockRepo.On("List", repository.UserFilter{}, repository.PageQuery{}).Return(nil, "", assert.AnError)
 
-	result, err := suite.service.GetAllUsers()
+	result, _, err := suite.service.ListUsers(repository.UserFilter{}, repository.PageQuery{})
 
 	suite.Error(err)
 	suite.Nil(result)
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/Bug-Bugger/ezmodel/internal/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserSessionService tracks signed-in devices. Each login starts a session
+// whose ID is embedded in the issued tokens, so a user can list where they
+// are signed in and revoke a device.
+type UserSessionService struct {
+	sessionRepo     repository.UserSessionRepositoryInterface
+	userRepo        repository.UserRepositoryInterface
+	jwtService      JWTServiceInterface
+	refreshTokenExp time.Duration
+	now             func() time.Time
+}
+
+func NewUserSessionService(cfg *config.Config, sessionRepo repository.UserSessionRepositoryInterface, userRepo repository.UserRepositoryInterface, jwtService JWTServiceInterface) *UserSessionService {
+	return &UserSessionService{
+		sessionRepo:     sessionRepo,
+		userRepo:        userRepo,
+		jwtService:      jwtService,
+		refreshTokenExp: cfg.JWT.RefreshTokenExp,
+		now:             time.Now,
+	}
+}
+
+// StartSession records a new session for the user and issues tokens bound to it.
+// Every sign-in method goes through here, so disabled accounts are refused here.
+func (s *UserSessionService) StartSession(user *models.User, ipAddress, userAgent string) (*TokenPair, error) {
+	return s.startSession(user, nil, s.refreshTokenExp, ipAddress, userAgent)
+}
+
+// StartImpersonationSession starts a session as the user on behalf of an admin.
+// It lasts ttl and is not extended by refreshing.
+func (s *UserSessionService) StartImpersonationSession(user *models.User, impersonatorID uuid.UUID, ttl time.Duration, ipAddress, userAgent string) (*TokenPair, error) {
+	return s.startSession(user, &impersonatorID, ttl, ipAddress, userAgent)
+}
+
+func (s *UserSessionService) startSession(user *models.User, impersonatorID *uuid.UUID, ttl time.Duration, ipAddress, userAgent string) (*TokenPair, error) {
+	if user.IsDisabled() {
+		return nil, ErrAccountDisabled
+	}
+
+	now := s.now()
+	session := &models.UserSession{
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		IPAddress:      ipAddress,
+		UserAgent:      userAgent,
+		LastSeenAt:     now,
+		ExpiresAt:      now.Add(ttl),
+		ImpersonatorID: impersonatorID,
+	}
+
+	tokens, err := s.jwtService.GenerateSessionTokenPair(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	session.RefreshTokenHash = hashToken(tokens.RefreshToken)
+
+	if _, err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// RefreshSession rotates the session's refresh token and issues a new token pair.
+// A refresh token that has already been rotated away is rejected.
+func (s *UserSessionService) RefreshSession(refreshToken, ipAddress, userAgent string) (*TokenPair, error) {
+	claims, err := s.jwtService.ValidateToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tokens issued before sessions were tracked move onto a new session
+	if claims.SessionID == uuid.Nil {
+		user, err := s.userRepo.GetByID(claims.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrInvalidToken
+			}
+			return nil, err
+		}
+		return s.StartSession(user, ipAddress, userAgent)
+	}
+
+	user := &models.User{
+		ID:    claims.UserID,
+		Email: claims.Email,
+	}
+
+	session, err := s.getActiveSession(claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != claims.UserID {
+		return nil, ErrInvalidToken
+	}
+
+	tokens, err := s.jwtService.GenerateSessionTokenPair(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	previousHash := hashToken(refreshToken)
+	session.RefreshTokenHash = hashToken(tokens.RefreshToken)
+	session.IPAddress = ipAddress
+	session.UserAgent = userAgent
+	session.LastSeenAt = now
+	if session.ImpersonatorID == nil {
+		session.ExpiresAt = now.Add(s.refreshTokenExp)
+	}
+
+	rotated, err := s.sessionRepo.Rotate(session, previousHash)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, ErrInvalidToken
+	}
+
+	return tokens, nil
+}
+
+// EndSession revokes the session a refresh token belongs to. Invalid tokens are ignored
+// since there is nothing to sign out of.
+func (s *UserSessionService) EndSession(refreshToken string) error {
+	claims, err := s.jwtService.ValidateToken(refreshToken)
+	if err != nil || claims.SessionID == uuid.Nil {
+		return nil
+	}
+
+	return s.sessionRepo.Revoke(claims.SessionID, s.now())
+}
+
+// ValidateSession returns the session, or ErrUserSessionRevoked unless it is still active
+func (s *UserSessionService) ValidateSession(sessionID uuid.UUID) (*models.UserSession, error) {
+	return s.getActiveSession(sessionID)
+}
+
+// GetActiveSessions returns the user's signed-in devices, most recently used first
+func (s *UserSessionService) GetActiveSessions(userID uuid.UUID) ([]*models.UserSession, error) {
+	return s.sessionRepo.GetActiveByUserID(userID, s.now())
+}
+
+// RevokeSession signs one of the user's devices out
+func (s *UserSessionService) RevokeSession(userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserSessionNotFound
+		}
+		return err
+	}
+	if session.UserID != userID {
+		return ErrUserSessionNotFound
+	}
+
+	return s.sessionRepo.Revoke(sessionID, s.now())
+}
+
+// RevokeOtherSessions signs the user out everywhere except the current session
+func (s *UserSessionService) RevokeOtherSessions(userID, currentSessionID uuid.UUID) error {
+	return s.sessionRepo.RevokeAllByUserID(userID, currentSessionID, s.now())
+}
+
+func (s *UserSessionService) getActiveSession(sessionID uuid.UUID) (*models.UserSession, error) {
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserSessionRevoked
+		}
+		return nil, err
+	}
+	if !session.IsActive(s.now()) {
+		return nil, ErrUserSessionRevoked
+	}
+	return session, nil
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+	mockRepo "github.com/Bug-Bugger/ezmodel/internal/mocks/repository"
+	"github.com/Bug-Bugger/ezmodel/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type UserSessionServiceTestSuite struct {
+	suite.Suite
+	mockSessionRepo *mockRepo.MockUserSessionRepository
+	mockUserRepo    *mockRepo.MockUserRepository
+	jwtService      *JWTService
+	service         *UserSessionService
+	now             time.Time
+	user            *models.User
+}
+
+func (suite *UserSessionServiceTestSuite) SetupTest() {
+	cfg := &config.Config{}
+	cfg.JWT.Secret = "test-secret-key-for-session-testing"
+	cfg.JWT.AccessTokenExp = 15 * time.Minute
+	cfg.JWT.RefreshTokenExp = 7 * 24 * time.Hour
+
+	suite.mockSessionRepo = new(mockRepo.MockUserSessionRepository)
+	suite.mockUserRepo = new(mockRepo.MockUserRepository)
+	suite.jwtService = NewJWTService(cfg)
+	suite.service = NewUserSessionService(cfg, suite.mockSessionRepo, suite.mockUserRepo, suite.jwtService)
+	suite.now = time.Now().Truncate(time.Second)
+	suite.service.now = func() time.Time { return suite.now }
+	suite.user = createTestUser()
+}
+
+func TestUserSessionServiceSuite(t *testing.T) {
+	suite.Run(t, new(UserSessionServiceTestSuite))
+}
+
+// activeSession returns a stored session whose current refresh token is refreshToken
+func (suite *UserSessionServiceTestSuite) activeSession(sessionID uuid.UUID, refreshToken string) *models.UserSession {
+	return &models.UserSession{
+		ID:               sessionID,
+		UserID:           suite.user.ID,
+		RefreshTokenHash: hashToken(refreshToken),
+		LastSeenAt:       suite.now.Add(-time.Hour),
+		ExpiresAt:        suite.now.Add(time.Hour),
+	}
+}
+
+// Test StartSession - stores the session with the hash of the refresh token it issued
+func (suite *UserSessionServiceTestSuite) TestStartSession_Success() {
+	var stored *models.UserSession
+	suite.mockSessionRepo.On("Create", mock.AnythingOfType("*models.UserSession")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*models.UserSession) }).
+		Return(uuid.New(), nil)
+
+	tokens, err := suite.service.StartSession(suite.user, "192.0.2.1", "test-agent")
+
+	suite.Require().NoError(err)
+	suite.Equal(suite.user.ID, stored.UserID)
+	suite.Equal("192.0.2.1", stored.IPAddress)
+	suite.Equal("test-agent", stored.UserAgent)
+	suite.Equal(hashToken(tokens.RefreshToken), stored.RefreshTokenHash)
+	suite.Equal(suite.now.Add(7*24*time.Hour), stored.ExpiresAt)
+
+	claims, err := suite.jwtService.ValidateToken(tokens.AccessToken)
+	suite.NoError(err)
+	suite.Equal(stored.ID, claims.SessionID)
+}
+
+// Test StartSession - repository errors are returned
+func (suite *UserSessionServiceTestSuite) TestStartSession_RepositoryError() {
+	suite.mockSessionRepo.On("Create", mock.AnythingOfType("*models.UserSession")).Return(uuid.Nil, assert.AnError)
+
+	tokens, err := suite.service.StartSession(suite.user, "192.0.2.1", "test-agent")
+
+	suite.Nil(tokens)
+	suite.ErrorIs(err, assert.AnError)
+}
+
+// Test StartSession - disabled accounts cannot sign in
+func (suite *UserSessionServiceTestSuite) TestStartSession_DisabledAccount() {
+	disabledAt := suite.now.Add(-time.Hour)
+	suite.user.DisabledAt = &disabledAt
+
+	tokens, err := suite.service.StartSession(suite.user, "192.0.2.1", "test-agent")
+
+	suite.Nil(tokens)
+	suite.ErrorIs(err, ErrAccountDisabled)
+	suite.mockSessionRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// Test StartImpersonationSession - records the admin and uses the shorter lifetime
+func (suite *UserSessionServiceTestSuite) TestStartImpersonationSession() {
+	adminID := uuid.New()
+	var stored *models.UserSession
+	suite.mockSessionRepo.On("Create", mock.AnythingOfType("*models.UserSession")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*models.UserSession) }).
+		Return(uuid.New(), nil)
+
+	_, err := suite.service.StartImpersonationSession(suite.user, adminID, time.Hour, "192.0.2.1", "test-agent")
+
+	suite.Require().NoError(err)
+	suite.Require().NotNil(stored.ImpersonatorID)
+	suite.Equal(adminID, *stored.ImpersonatorID)
+	suite.Equal(suite.now.Add(time.Hour), stored.ExpiresAt)
+}
+
+// Test RefreshSession - impersonation sessions keep their original expiry
+func (suite *UserSessionServiceTestSuite) TestRefreshSession_ImpersonationNotExtended() {
+	sessionID := uuid.New()
+	original, err := suite.jwtService.GenerateSessionTokenPair(suite.user, sessionID)
+	suite.Require().NoError(err)
+
+	session := suite.activeSession(sessionID, original.RefreshToken)
+	adminID := uuid.New()
+	session.ImpersonatorID = &adminID
+	expiresAt := session.ExpiresAt
+	suite.mockSessionRepo.On("GetByID", sessionID).Return(session, nil)
+	suite.mockSessionRepo.On("Rotate", mock.MatchedBy(func(s *models.UserSession) bool {
+		return s.ExpiresAt.Equal(expiresAt)
+	}), hashToken(original.RefreshToken)).Return(true, nil)
+
+	_, err = suite.service.RefreshSession(original.RefreshToken, "192.0.2.1", "test-agent")
+
+	suite.NoError(err)
+	suite.mockSessionRepo.AssertExpectations(suite.T())
+}
+
+// Test RefreshSession - rotates the refresh token and updates the device metadata
+func (suite *UserSessionServiceTestSuite) TestRefreshSession_Success() {
+	sessionID := uuid.New()
+	original, err := suite.jwtService.GenerateSessionTokenPair(suite.user, sessionID)
+	suite.Require().NoError(err)
+
+	suite.mockSessionRepo.On("GetByID", sessionID).Return(suite.activeSession(sessionID, original.RefreshToken), nil)
+	suite.mockSessionRepo.On("Rotate", mock.MatchedBy(func(session *models.UserSession) bool {
+		return session.IPAddress == "198.51.100.2" && session.UserAgent == "new-agent" &&
+			session.LastSeenAt.Equal(suite.now) && session.RefreshTokenHash != hashToken(original.RefreshToken)
+	}), hashToken(original.RefreshToken)).Return(true, nil)
+
+	tokens, err := suite.service.RefreshSession(original.RefreshToken, "198.51.100.2", "new-agent")
+
+	suite.Require().NoError(err)
+	suite.NotEqual(original.RefreshToken, tokens.RefreshToken)
+	claims, err := suite.jwtService.ValidateToken(tokens.RefreshToken)
+	suite.NoError(err)
+	suite.Equal(sessionID, claims.SessionID)
+	suite.mockSessionRepo.AssertExpectations(suite.T())
+}
+
+// Test RefreshSession - a refresh token that was already rotated is rejected
+func (suite *UserSessionServiceTestSuite) TestRefreshSession_ReusedToken() {
+	sessionID := uuid.New()
+	original, err := suite.jwtService.GenerateSessionTokenPair(suite.user, sessionID)
+	suite.Require().NoError(err)
+
+	suite.mockSessionRepo.On("GetByID", sessionID).Return(suite.activeSession(sessionID, "newer-token"), nil)
+	suite.mockSessionRepo.On("Rotate", mock.AnythingOfType("*models.UserSession"), hashToken(original.RefreshToken)).Return(false, nil)
+
+	tokens, err := suite.service.RefreshSession(original.RefreshToken, "192.0.2.1", "test-agent")
+
+	suite.Nil(tokens)
+	suite.ErrorIs(err, ErrInvalidToken)
+}
+
+// Test RefreshSession - revoked sessions cannot be refreshed
+func (suite *UserSessionServiceTestSuite) TestRefreshSession_Revoked() {
+	sessionID := uuid.New()
+	original, err := suite.jwtService.GenerateSessionTokenPair(suite.user, sessionID)
+	suite.Require().NoError(err)
+
+	session := suite.activeSession(sessionID, original.RefreshToken)
+	revokedAt := suite.now.Add(-time.Minute)
+	session.RevokedAt = &revokedAt
+	suite.mockSessionRepo.On("GetByID", sessionID).Return(session, nil)
+
+	tokens, err := suite.service.RefreshSession(original.RefreshToken, "192.0.2.1", "test-agent")
+
+	suite.Nil(tokens)
+	suite.ErrorIs(err, ErrUserSessionRevoked)
+	suite.mockSessionRepo.AssertNotCalled(suite.T(), "Rotate", mock.Anything, mock.Anything)
+}
+
+// Test RefreshSession - tokens issued before sessions existed start a new session
+func (suite *UserSessionServiceTestSuite) TestRefreshSession_LegacyToken() {
+	legacy, err := suite.jwtService.GenerateTokenPair(suite.user)
+	suite.Require().NoError(err)
+
+	suite.mockUserRepo.On("GetByID", suite.user.ID).Return(suite.user, nil)
+	suite.mockSessionRepo.On("Create", mock.AnythingOfType("*models.UserSession")).Return(uuid.New(), nil)
+
+	tokens, err := suite.service.RefreshSession(legacy.RefreshToken, "192.0.2.1", "test-agent")
+
+	suite.Require().NoError(err)
+	claims, err := suite.jwtService.ValidateToken(tokens.AccessToken)
+	suite.NoError(err)
+	suite.NotEqual(uuid.Nil, claims.SessionID)
+}
+
+// Test RefreshSession - invalid tokens are rejected before touching the store
+func (suite *UserSessionServiceTestSuite) TestRefreshSession_InvalidToken() {
+	tokens, err := suite.service.RefreshSession("not-a-token", "192.0.2.1", "test-agent")
+
+	suite.Nil(tokens)
+	suite.ErrorIs(err, ErrInvalidToken)
+	suite.mockSessionRepo.AssertNotCalled(suite.T(), "GetByID", mock.Anything)
+}
+
+// Test EndSession - revokes the session of the refresh token
+func (suite *UserSessionServiceTestSuite) TestEndSession() {
+	sessionID := uuid.New()
+	tokens, err := suite.jwtService.GenerateSessionTokenPair(suite.user, sessionID)
+	suite.Require().NoError(err)
+
+	suite.mockSessionRepo.On("Revoke", sessionID, suite.now).Return(nil)
+
+	suite.NoError(suite.service.EndSession(tokens.RefreshToken))
+	suite.NoError(suite.service.EndSession("not-a-token"))
+	suite.mockSessionRepo.AssertNumberOfCalls(suite.T(), "Revoke", 1)
+}
+
+// Test ValidateSession - missing and expired sessions are reported as revoked
+func (suite *UserSessionServiceTestSuite) TestValidateSession() {
+	activeID, expiredID, missingID := uuid.New(), uuid.New(), uuid.New()
+	expired := suite.activeSession(expiredID, "token")
+	expired.ExpiresAt = suite.now.Add(-time.Second)
+
+	suite.mockSessionRepo.On("GetByID", activeID).Return(suite.activeSession(activeID, "token"), nil)
+	suite.mockSessionRepo.On("GetByID", expiredID).Return(expired, nil)
+	suite.mockSessionRepo.On("GetByID", missingID).Return(nil, gorm.ErrRecordNotFound)
+
+	session, err := suite.service.ValidateSession(activeID)
+	suite.NoError(err)
+	suite.Equal(activeID, session.ID)
+	_, err = suite.service.ValidateSession(expiredID)
+	suite.ErrorIs(err, ErrUserSessionRevoked)
+	_, err = suite.service.ValidateSession(missingID)
+	suite.ErrorIs(err, ErrUserSessionRevoked)
+}
+
+// Test RevokeSession - users can only revoke their own sessions
+func (suite *UserSessionServiceTestSuite) TestRevokeSession() {
+	ownID, otherID := uuid.New(), uuid.New()
+	other := suite.activeSession(otherID, "token")
+	other.UserID = uuid.New()
+
+	suite.mockSessionRepo.On("GetByID", ownID).Return(suite.activeSession(ownID, "token"), nil)
+	suite.mockSessionRepo.On("GetByID", otherID).Return(other, nil)
+	suite.mockSessionRepo.On("Revoke", ownID, suite.now).Return(nil)
+
+	suite.NoError(suite.service.RevokeSession(suite.user.ID, ownID))
+	suite.ErrorIs(suite.service.RevokeSession(suite.user.ID, otherID), ErrUserSessionNotFound)
+	suite.mockSessionRepo.AssertNotCalled(suite.T(), "Revoke", otherID, mock.Anything)
+}
+
+// Test RevokeSession - unknown sessions are not found
+func (suite *UserSessionServiceTestSuite) TestRevokeSession_NotFound() {
+	sessionID := uuid.New()
+	suite.mockSessionRepo.On("GetByID", sessionID).Return(nil, gorm.ErrRecordNotFound)
+
+	suite.ErrorIs(suite.service.RevokeSession(suite.user.ID, sessionID), ErrUserSessionNotFound)
+}
+
+// Test RevokeOtherSessions - keeps the current session
+func (suite *UserSessionServiceTestSuite) TestRevokeOtherSessions() {
+	currentID := uuid.New()
+	suite.mockSessionRepo.On("RevokeAllByUserID", suite.user.ID, currentID, suite.now).Return(nil)
+
+	suite.NoError(suite.service.RevokeOtherSessions(suite.user.ID, currentID))
+	suite.mockSessionRepo.AssertExpectations(suite.T())
+}
+
+// Test GetActiveSessions - returns the repository result
+func (suite *UserSessionServiceTestSuite) TestGetActiveSessions() {
+	sessions := []*models.UserSession{suite.activeSession(uuid.New(), "token")}
+	suite.mockSessionRepo.On("GetActiveByUserID", suite.user.ID, suite.now).Return(sessions, nil)
+
+	result, err := suite.service.GetActiveSessions(suite.user.ID)
+
+	suite.NoError(err)
+	suite.Equal(sessions, result)
+}
@@ -0,0 +1,343 @@
+// Package spreadsheet reads table definitions from Excel workbooks and CSV
+// files, as analysts often sketch a data model in a spreadsheet first. Each
+// sheet is a table and each row below the header defines one of its columns.
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// MaxSize limits the files read, in bytes
+	MaxSize = 5 * 1024 * 1024
+
+	// maxUnzipSize and maxUnzipXMLSize bound what a workbook may inflate to
+	maxUnzipSize    = 64 * 1024 * 1024
+	maxUnzipXMLSize = 16 * 1024 * 1024
+
+	// maxNameLength bounds the names of tables and columns
+	maxNameLength = 255
+
+	// maxErrors bounds how many problems one ValidationError reports
+	maxErrors = 20
+)
+
+var (
+	// ErrUnsupportedFormat is returned for files that are neither .xlsx nor .csv
+	ErrUnsupportedFormat = errors.New("unsupported spreadsheet format")
+	// ErrTooLarge is returned for files over MaxSize
+	ErrTooLarge = errors.New("spreadsheet is too large")
+)
+
+// ValidationError lists what is wrong with a spreadsheet, by the cell of the
+// offending value, e.g. "Users!B4", or by sheet name for a whole sheet. The
+// empty key is about the file itself.
+type ValidationError struct {
+	Errors map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	cells := make([]string, 0, len(e.Errors))
+	for cell := range e.Errors {
+		cells = append(cells, cell)
+	}
+	sort.Strings(cells)
+	for i, cell := range cells {
+		cells[i] = cell + ": " + e.Errors[cell]
+	}
+	return "invalid spreadsheet: " + strings.Join(cells, "; ")
+}
+
+// Table is a table defined by a sheet
+type Table struct {
+	Name    string
+	Columns []Column
+}
+
+// Column is a column defined by a row
+type Column struct {
+	Name         string
+	DataType     string
+	IsPrimaryKey bool
+	IsNullable   bool
+	DefaultValue string
+}
+
+// Headers name the columns of a sheet. Their case, spaces and underscores
+// do not matter, and the columns may come in any order.
+var headers = map[string]string{
+	"name":         "name",
+	"column":       "name",
+	"columnname":   "name",
+	"field":        "name",
+	"fieldname":    "name",
+	"type":         "type",
+	"datatype":     "type",
+	"nullable":     "nullable",
+	"null":         "nullable",
+	"isnullable":   "nullable",
+	"default":      "default",
+	"defaultvalue": "default",
+	"primarykey":   "primary_key",
+	"pk":           "primary_key",
+	"isprimarykey": "primary_key",
+	"table":        "table",
+	"tablename":    "table",
+}
+
+// Read reads the tables defined in an .xlsx workbook or a .csv file, telling
+// them apart by the file name. The tables of a workbook are named after its
+// visible sheets, skipping empty ones; the table of a CSV file after the file.
+// A "table" column puts rows in other tables instead, so one CSV file can
+// define several. Tables are returned in the order they first appear.
+func Read(filename string, data []byte) ([]Table, error) {
+	if len(data) > MaxSize {
+		return nil, fmt.Errorf("%w: %d bytes, at most %d are allowed", ErrTooLarge, len(data), MaxSize)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".xlsx", ".xlsm":
+		return readWorkbook(data)
+	case ".csv":
+		return readCSV(name, data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+func readWorkbook(data []byte) ([]Table, error) {
+	file, err := excelize.OpenReader(bytes.NewReader(data), excelize.Options{
+		UnzipSizeLimit:    maxUnzipSize,
+		UnzipXMLSizeLimit: maxUnzipXMLSize,
+	})
+	if err != nil {
+		return nil, &ValidationError{Errors: map[string]string{"": "Must be an Excel workbook"}}
+	}
+	defer file.Close()
+
+	reader := newReader()
+	for _, sheet := range file.GetSheetList() {
+		if visible, err := file.GetSheetVisible(sheet); err == nil && !visible {
+			continue
+		}
+		rows, err := file.GetRows(sheet)
+		if err != nil {
+			return nil, err
+		}
+		reader.readSheet(sheet, rows)
+	}
+	return reader.result()
+}
+
+func readCSV(name string, data []byte) ([]Table, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff")) // Excel saves CSV with a byte order mark
+	csvReader := csv.NewReader(bytes.NewReader(data))
+	csvReader.FieldsPerRecord = -1
+	var rows [][]string
+	for {
+		row, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &ValidationError{Errors: map[string]string{"": "Must be a valid CSV file"}}
+		}
+		rows = append(rows, row)
+	}
+
+	reader := newReader()
+	reader.readSheet(name, rows)
+	return reader.result()
+}
+
+// reader collects tables from sheets along with what is wrong with them
+type reader struct {
+	tables  []*Table
+	byName  map[string]*Table
+	columns map[string]map[string]bool // Column names by table, to report duplicates
+	errors  map[string]string
+}
+
+func newReader() *reader {
+	return &reader{
+		byName:  make(map[string]*Table),
+		columns: make(map[string]map[string]bool),
+		errors:  make(map[string]string),
+	}
+}
+
+func (r *reader) addError(cell, message string) {
+	if len(r.errors) < maxErrors {
+		r.errors[cell] = message
+	}
+}
+
+func (r *reader) table(name string) *Table {
+	table, ok := r.byName[name]
+	if !ok {
+		table = &Table{Name: name}
+		r.byName[name] = table
+		r.columns[name] = make(map[string]bool)
+		r.tables = append(r.tables, table)
+	}
+	return table
+}
+
+// readSheet reads the columns defined by the rows of a sheet; the first row
+// with any value is the header
+func (r *reader) readSheet(sheet string, rows [][]string) {
+	header := -1
+	for i, row := range rows {
+		if !isEmpty(row) {
+			header = i
+			break
+		}
+	}
+	if header < 0 {
+		return
+	}
+
+	indexes := make(map[string]int) // Of the known headers
+	for i, cell := range rows[header] {
+		key := strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(strings.TrimSpace(cell)))
+		if known, ok := headers[key]; ok {
+			if _, seen := indexes[known]; !seen {
+				indexes[known] = i
+			}
+		}
+	}
+	_, hasName := indexes["name"]
+	_, hasType := indexes["type"]
+	if !hasName || !hasType {
+		r.addError(sheet, "Needs a header row with name and type columns")
+		return
+	}
+
+	for i := header + 1; i < len(rows); i++ {
+		row := rows[i]
+		if isEmpty(row) {
+			continue
+		}
+		// value returns the trimmed value under a header and the name of its cell
+		value := func(key string) (string, string) {
+			column, ok := indexes[key]
+			if !ok {
+				return "", ""
+			}
+			cell := cellName(sheet, column, i+1)
+			if column >= len(row) {
+				return "", cell
+			}
+			return strings.TrimSpace(row[column]), cell
+		}
+
+		tableName, tableCell := value("table")
+		if tableName == "" {
+			tableName, tableCell = sheet, sheet
+		}
+		if len(tableName) > maxNameLength {
+			r.addError(tableCell, "Table names must be at most 255 characters")
+			continue
+		}
+		name, nameCell := value("name")
+		dataType, typeCell := value("type")
+		switch {
+		case name == "":
+			r.addError(nameCell, "Column name is required")
+			continue
+		case len(name) > maxNameLength:
+			r.addError(nameCell, "Column names must be at most 255 characters")
+			continue
+		case dataType == "":
+			r.addError(typeCell, "Data type is required")
+			continue
+		}
+		if r.columns[tableName][name] {
+			r.addError(nameCell, fmt.Sprintf("Column %q is defined twice in table %q", name, tableName))
+			continue
+		}
+
+		primaryKeyValue, primaryKeyCell := value("primary_key")
+		isPrimaryKey, ok := parseBool(primaryKeyValue, false)
+		if !ok {
+			r.addError(primaryKeyCell, "Must be yes or no")
+			continue
+		}
+		nullableValue, nullableCell := value("nullable")
+		isNullable, ok := parseBool(nullableValue, !isPrimaryKey)
+		if !ok {
+			r.addError(nullableCell, "Must be yes or no")
+			continue
+		}
+		defaultValue, _ := value("default")
+
+		table := r.table(tableName)
+		r.columns[tableName][name] = true
+		table.Columns = append(table.Columns, Column{
+			Name:         name,
+			DataType:     dataType,
+			IsPrimaryKey: isPrimaryKey,
+			IsNullable:   isNullable,
+			DefaultValue: defaultValue,
+		})
+	}
+}
+
+func (r *reader) result() ([]Table, error) {
+	if len(r.errors) > 0 {
+		return nil, &ValidationError{Errors: r.errors}
+	}
+	if len(r.tables) == 0 {
+		return nil, &ValidationError{Errors: map[string]string{"": "Defines no tables"}}
+	}
+
+	tables := make([]Table, len(r.tables))
+	for i, table := range r.tables {
+		tables[i] = *table
+	}
+	return tables, nil
+}
+
+// parseBool reads the yes or no of a cell, or fallback for a blank one. SQL
+// spellings are understood too, e.g. "NOT NULL" in a nullable column.
+func parseBool(value string, fallback bool) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "":
+		return fallback, true
+	case "yes", "y", "true", "t", "1", "x", "null":
+		return true, true
+	case "no", "n", "false", "f", "0", "not null":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// cellName returns the name of a cell, e.g. "Users!B4", from its zero-based
+// column and one-based row
+func cellName(sheet string, column, row int) string {
+	name, err := excelize.CoordinatesToCellName(column+1, row)
+	if err != nil {
+		return sheet
+	}
+	return sheet + "!" + name
+}
+
+func isEmpty(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package spreadsheet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// workbook builds an .xlsx file from rows by sheet name
+func workbook(t *testing.T, sheets map[string][][]any, order ...string) []byte {
+	t.Helper()
+	file := excelize.NewFile()
+	defer file.Close()
+	for i, name := range order {
+		if i == 0 {
+			require.NoError(t, file.SetSheetName("Sheet1", name))
+		} else {
+			_, err := file.NewSheet(name)
+			require.NoError(t, err)
+		}
+		for j, row := range sheets[name] {
+			cell, err := excelize.CoordinatesToCellName(1, j+1)
+			require.NoError(t, err)
+			require.NoError(t, file.SetSheetRow(name, cell, &row))
+		}
+	}
+	buffer, err := file.WriteToBuffer()
+	require.NoError(t, err)
+	return buffer.Bytes()
+}
+
+func TestReadWorkbook(t *testing.T) {
+	data := workbook(t, map[string][][]any{
+		"users": {
+			{"Column Name", "Data Type", "Nullable", "Default", "PK"},
+			{"id", "uuid", "", "", "yes"},
+			{"email", "varchar(255)", "no"},
+			{},
+			{"created_at", "timestamp", "", "now()"},
+		},
+		"Notes": {},
+		"orders": {
+			{"type", "name"},
+			{"bigint", "id"},
+		},
+	}, "users", "Notes", "orders")
+
+	tables, err := Read("model.xlsx", data)
+
+	require.NoError(t, err)
+	assert.Equal(t, []Table{
+		{Name: "users", Columns: []Column{
+			{Name: "id", DataType: "uuid", IsPrimaryKey: true},
+			{Name: "email", DataType: "varchar(255)"},
+			{Name: "created_at", DataType: "timestamp", IsNullable: true, DefaultValue: "now()"},
+		}},
+		{Name: "orders", Columns: []Column{
+			{Name: "id", DataType: "bigint", IsNullable: true},
+		}},
+	}, tables)
+}
+
+func TestReadCSVWithTableColumn(t *testing.T) {
+	data := "\ufefftable,name,type,nullable\n" +
+		",id,uuid,NOT NULL\n" +
+		"orders,id,bigint,no\n" +
+		"orders,customer_id,uuid,\n"
+
+	tables, err := Read("customers.csv", []byte(data))
+
+	require.NoError(t, err)
+	assert.Equal(t, []Table{
+		{Name: "customers", Columns: []Column{{Name: "id", DataType: "uuid"}}},
+		{Name: "orders", Columns: []Column{
+			{Name: "id", DataType: "bigint"},
+			{Name: "customer_id", DataType: "uuid", IsNullable: true},
+		}},
+	}, tables)
+}
+
+func TestReadReportsCells(t *testing.T) {
+	data := workbook(t, map[string][][]any{
+		"users": {
+			{"name", "type", "nullable"},
+			{"id", "uuid"},
+			{"email", ""},
+			{"id", "text"},
+			{"age", "int", "maybe"},
+		},
+		"Notes": {{"Remember to add indexes"}},
+	}, "users", "Notes")
+
+	_, err := Read("model.xlsx", data)
+
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, map[string]string{
+		"users!B3": "Data type is required",
+		"users!A4": `Column "id" is defined twice in table "users"`,
+		"users!C5": "Must be yes or no",
+		"Notes":    "Needs a header row with name and type columns",
+	}, validationErr.Errors)
+}
+
+func TestReadRejectsOtherFiles(t *testing.T) {
+	_, err := Read("model.ods", []byte("data"))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	_, err = Read("model.xlsx", []byte("not a workbook"))
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+
+	_, err = Read("model.csv", []byte(strings.Repeat("a", MaxSize+1)))
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	_, err = Read("model.csv", []byte("name,type\n"))
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, map[string]string{"": "Defines no tables"}, validationErr.Errors)
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore_PutServeDelete(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "http://localhost:8080/uploads/")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(context.Background(), "avatars/a/b.png", "image/png", []byte("png")))
+	assert.Equal(t, "http://localhost:8080/uploads/avatars/a/b.png", store.URL("avatars/a/b.png"))
+
+	w := httptest.NewRecorder()
+	store.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/avatars/a/b.png", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "png", w.Body.String())
+
+	require.NoError(t, store.Delete(context.Background(), "avatars/a/b.png"))
+	require.NoError(t, store.Delete(context.Background(), "avatars/a/b.png"))
+
+	w = httptest.NewRecorder()
+	store.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/avatars/a/b.png", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLocalStore_NoDirectoryListing(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "/uploads")
+	require.NoError(t, err)
+	require.NoError(t, store.Put(context.Background(), "avatars/a/b.png", "image/png", []byte("png")))
+
+	w := httptest.NewRecorder()
+	store.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/avatars/a/", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestLocalStore_PrivateFilesNotServed(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "/uploads")
+	require.NoError(t, err)
+	require.NoError(t, store.Put(context.Background(), "exports/a/b.zip", "application/zip", []byte("zip")))
+
+	w := httptest.NewRecorder()
+	store.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/exports/a/b.zip", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	file, err := store.Open(context.Background(), "exports/a/b.zip")
+	require.NoError(t, err)
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Equal(t, "zip", string(data))
+}
+
+func TestLocalStore_RejectsEscapingKeys(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "/uploads")
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "avatars/../../secret", ".."} {
+		assert.ErrorIs(t, store.Put(context.Background(), key, "image/png", []byte("x")), errInvalidKey, key)
+	}
+}
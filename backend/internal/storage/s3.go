@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsConfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Store keeps files in an S3 bucket or an S3-compatible service. Files are
+// linked from the public URL, which is usually a CDN in front of the bucket.
+type S3Store struct {
+	client    *s3.Client
+	bucket    string
+	publicURL string
+}
+
+// NewS3Store creates a store with the credentials of the AWS environment. The
+// region falls back to the environment too when empty.
+func NewS3Store(bucket, region, endpoint string, pathStyle bool, publicURL string) (*S3Store, error) {
+	if bucket == "" {
+		return nil, errors.New("no S3 bucket configured")
+	}
+	if publicURL == "" {
+		return nil, errors.New("no public URL configured for the S3 bucket")
+	}
+
+	var opts []func(*awsConfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsConfig.WithRegion(region))
+	}
+	cfg, err := awsConfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+		o.UsePathStyle = pathStyle
+	})
+
+	return &S3Store{client: client, bucket: bucket, publicURL: strings.TrimSuffix(publicURL, "/")}, nil
+}
+
+// Put implements Store. Keys are never reused, so public objects can be cached forever.
+func (s *S3Store) Put(ctx context.Context, key, contentType string, data []byte) error {
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	}
+	if strings.HasPrefix(key, PublicPrefix) {
+		input.CacheControl = aws.String("public, max-age=31536000, immutable")
+	}
+	_, err := s.client.PutObject(ctx, input)
+	return err
+}
+
+// Open implements Store
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+// Delete implements Store
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// URL implements Store
+func (s *S3Store) URL(key string) string {
+	return s.publicURL + "/" + key
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Bug-Bugger/ezmodel/internal/config"
+)
+
+// Supported storage drivers, selected with STORAGE_DRIVER
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// LocalPath is where the server serves the files of the local driver
+const LocalPath = "/uploads"
+
+// Key prefixes. Only files under PublicPrefix may be served to anyone with the
+// URL; with S3, publish only that prefix of the bucket, e.g. through a CDN.
+const (
+	PublicPrefix  = "avatars/"
+	PrivatePrefix = "exports/" // Downloaded through the API after checking access
+)
+
+// Store keeps uploaded files under slash-separated keys such as
+// avatars/<user id>/<name>.png
+type Store interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Open returns the content of a file; the caller closes it
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL is where clients fetch the file, under the configured public URL
+	URL(key string) string
+}
+
+// New creates the store selected in the config
+func New(cfg *config.Config) (Store, error) {
+	switch cfg.Storage.Driver {
+	case DriverLocal:
+		publicURL := cfg.Storage.PublicURL
+		if publicURL == "" {
+			// Served by this API, so absolute for a frontend on another origin
+			publicURL = cfg.OAuth.RedirectBaseURL + LocalPath
+		}
+		store, err := NewLocalStore(cfg.Storage.LocalDir, publicURL)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case DriverS3:
+		store, err := NewS3Store(cfg.Storage.S3Bucket, cfg.Storage.S3Region, cfg.Storage.S3Endpoint, cfg.Storage.S3PathStyle, cfg.Storage.PublicURL)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
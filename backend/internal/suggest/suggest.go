@@ -0,0 +1,131 @@
+// Package suggest proposes the relationships a schema is likely missing. A
+// field named after a table, such as user_id or userId for users, is taken
+// for a foreign key to that table's key when their data types are of the
+// same kind. Fields that already take part in a relationship are left alone.
+package suggest
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/Bug-Bugger/ezmodel/internal/introspect"
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+)
+
+// Suggestion is a relationship the schema may be missing, with why it is proposed
+type Suggestion struct {
+	schemadiff.Relationship
+	Reason string
+}
+
+// Relationships proposes a relationship for every field that looks like a
+// foreign key without being one, in the order of the schema. The key it
+// refers to is the sole primary key of the table it names, or its id field.
+// Foreign keys that are their table's sole primary key make one to one
+// relationships, others one to many.
+func Relationships(schema schemadiff.Schema) []Suggestion {
+	linked := make(map[[2]string]bool)
+	for _, relationship := range schema.Relationships {
+		linked[[2]string{relationship.SourceTable, relationship.SourceField}] = true
+		linked[[2]string{relationship.TargetTable, relationship.TargetField}] = true
+	}
+	tables := make(map[string]schemadiff.Table)
+	for _, table := range schema.Tables {
+		name := singular(strings.ToLower(table.Name))
+		if _, ok := tables[name]; !ok {
+			tables[name] = table
+		}
+	}
+
+	var suggestions []Suggestion
+	for _, table := range schema.Tables {
+		for _, field := range table.Fields {
+			if linked[[2]string{table.Name, field.Name}] {
+				continue
+			}
+			prefix, ok := referencePrefix(field.Name)
+			if !ok {
+				continue
+			}
+			referenced, ok := tables[singular(prefix)]
+			if !ok {
+				continue
+			}
+			key, ok := keyOf(referenced)
+			if !ok || (referenced.Name == table.Name && key.Name == field.Name) {
+				continue
+			}
+			if introspect.NormalizeType(key.DataType) != introspect.NormalizeType(field.DataType) {
+				continue
+			}
+
+			relationType := "one_to_many"
+			if key, ok := keyOf(table); ok && key.Name == field.Name && key.IsPrimaryKey {
+				relationType = "one_to_one"
+			}
+			suggestions = append(suggestions, Suggestion{
+				Relationship: schemadiff.Relationship{
+					SourceTable:  referenced.Name,
+					SourceField:  key.Name,
+					TargetTable:  table.Name,
+					TargetField:  field.Name,
+					RelationType: relationType,
+				},
+				Reason: field.Name + " is named after " + referenced.Name + " and has the type of " + referenced.Name + "." + key.Name,
+			})
+		}
+	}
+	return suggestions
+}
+
+// referencePrefix returns the name a foreign key field refers to, e.g. user
+// for user_id, userId or user_fk, lower-cased
+func referencePrefix(name string) (string, bool) {
+	lower := strings.ToLower(name)
+	for _, suffix := range []string{"_id", "_fk"} {
+		if prefix, ok := strings.CutSuffix(lower, suffix); ok && prefix != "" {
+			return prefix, true
+		}
+	}
+	// camelCase, e.g. userId, but not paid or uuid
+	if len(name) > 2 && strings.HasSuffix(name, "Id") && unicode.IsLower(rune(name[len(name)-3])) {
+		return strings.ToLower(name[:len(name)-2]), true
+	}
+	return "", false
+}
+
+// keyOf returns the field rows of table are referred to by: its sole
+// primary key, or else a field named id
+func keyOf(table schemadiff.Table) (schemadiff.Field, bool) {
+	var keys []schemadiff.Field
+	for _, field := range table.Fields {
+		if field.IsPrimaryKey {
+			keys = append(keys, field)
+		}
+	}
+	if len(keys) == 1 {
+		return keys[0], true
+	}
+	for _, field := range table.Fields {
+		if strings.EqualFold(field.Name, "id") {
+			return field, true
+		}
+	}
+	return schemadiff.Field{}, false
+}
+
+// singular strips the plural of a table name, e.g. orders or categories
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "ss"):
+		return name
+	case strings.HasSuffix(name, "s"):
+		return strings.TrimSuffix(name, "s")
+	default:
+		return name
+	}
+}
@@ -0,0 +1,62 @@
+package suggest
+
+import (
+	"testing"
+
+	"github.com/Bug-Bugger/ezmodel/internal/schemadiff"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelationships(t *testing.T) {
+	schema := schemadiff.Schema{
+		Tables: []schemadiff.Table{
+			{Name: "categories", Fields: []schemadiff.Field{
+				{Name: "id", DataType: "INTEGER", IsPrimaryKey: true},
+				{Name: "parent_id", DataType: "INTEGER"},
+			}},
+			{Name: "Users", Fields: []schemadiff.Field{
+				{Name: "uuid", DataType: "UUID", IsPrimaryKey: true},
+			}},
+			{Name: "profiles", Fields: []schemadiff.Field{
+				{Name: "user_id", DataType: "UUID", IsPrimaryKey: true},
+			}},
+			{Name: "products", Fields: []schemadiff.Field{
+				{Name: "id", DataType: "BIGINT", IsPrimaryKey: true},
+				{Name: "categoryId", DataType: "INT"},   // Integers both
+				{Name: "userId", DataType: "TEXT"},      // Not a UUID
+				{Name: "paid", DataType: "BOOLEAN"},     // Not a key
+				{Name: "warehouse_id", DataType: "INT"}, // No such table
+				{Name: "owner_id", DataType: "UUID"},    // Already related
+			}},
+		},
+		Relationships: []schemadiff.Relationship{
+			{SourceTable: "Users", SourceField: "uuid", TargetTable: "products", TargetField: "owner_id", RelationType: "one_to_many"},
+		},
+	}
+
+	assert.Equal(t, []Suggestion{
+		{
+			Relationship: schemadiff.Relationship{SourceTable: "Users", SourceField: "uuid", TargetTable: "profiles", TargetField: "user_id", RelationType: "one_to_one"},
+			Reason:       "user_id is named after Users and has the type of Users.uuid",
+		},
+		{
+			Relationship: schemadiff.Relationship{SourceTable: "categories", SourceField: "id", TargetTable: "products", TargetField: "categoryId", RelationType: "one_to_many"},
+			Reason:       "categoryId is named after categories and has the type of categories.id",
+		},
+	}, Relationships(schema))
+}
+
+func TestReferencePrefix(t *testing.T) {
+	for name, want := range map[string]string{"user_id": "user", "order_item_id": "order_item", "authorId": "author", "Team_FK": "team", "id": "", "uuid": "", "paid": ""} {
+		prefix, ok := referencePrefix(name)
+		assert.Equal(t, want, prefix, name)
+		assert.Equal(t, want != "", ok, name)
+	}
+}
+
+func TestSingular(t *testing.T) {
+	assert.Equal(t, "category", singular("categories"))
+	assert.Equal(t, "address", singular("addresses"))
+	assert.Equal(t, "class", singular("class"))
+	assert.Equal(t, "user", singular("user"))
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -63,6 +64,13 @@ func MakeJSONRequest(t *testing.T, method, url string, body any) *http.Request {
 	return req
 }
 
+// MakeMergePatchRequest creates a PATCH request carrying a raw JSON Merge Patch
+func MakeMergePatchRequest(url, patch string) *http.Request {
+	req := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(patch))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	return req
+}
+
 // ParseJSONResponse parses the JSON response from a ResponseRecorder
 func ParseJSONResponse(t *testing.T, w *httptest.ResponseRecorder, v any) {
 	err := json.Unmarshal(w.Body.Bytes(), v)
@@ -126,6 +134,12 @@ func WithUserContext(req *http.Request, userID uuid.UUID) *http.Request {
 	return req.WithContext(ctx)
 }
 
+// WithSessionContext adds the sign-in session ID to request context (for auth middleware simulation)
+func WithSessionContext(req *http.Request, sessionID uuid.UUID) *http.Request {
+	ctx := context.WithValue(req.Context(), "sessionID", sessionID)
+	return req.WithContext(ctx)
+}
+
 // CreateValidProjectRequest creates a valid project creation request
 func CreateValidProjectRequest() dto.CreateProjectRequest {
 	return dto.CreateProjectRequest{
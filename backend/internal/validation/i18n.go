@@ -0,0 +1,95 @@
+package validation
+
+import (
+	"embed"
+	"encoding/json"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is used when the client accepts none of the supported languages
+const DefaultLanguage = "en"
+
+// Catalog maps a validation tag to its message template. "{param}" is replaced by
+// the tag parameter, and the "default" entry covers tags without their own message.
+type Catalog map[string]string
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// supportedLanguages lists the catalogs in matching preference; the first is the fallback
+var supportedLanguages = []language.Tag{language.English, language.Chinese, language.Spanish}
+
+var (
+	catalogs = make(map[string]Catalog)
+	matcher  = language.NewMatcher(supportedLanguages)
+)
+
+func init() {
+	for _, tag := range supportedLanguages {
+		lang := tag.String()
+		data, err := localeFiles.ReadFile("locales/" + lang + ".json")
+		if err != nil {
+			panic("validation: missing catalog for " + lang)
+		}
+
+		var catalog Catalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic("validation: invalid catalog for " + lang + ": " + err.Error())
+		}
+		if catalog["default"] == "" {
+			panic("validation: catalog for " + lang + " has no default message")
+		}
+		catalogs[lang] = catalog
+	}
+}
+
+// Language picks the supported language that best matches an Accept-Language header
+func Language(acceptLanguage string) string {
+	_, index := language.MatchStrings(matcher, acceptLanguage)
+	return supportedLanguages[index].String()
+}
+
+// LocalizedValidationErrors is ValidationErrors with messages in the given language
+func LocalizedValidationErrors(err error, lang string) map[string]string {
+	if err == nil {
+		return nil
+	}
+
+	catalog, ok := catalogs[lang]
+	if !ok {
+		catalog = catalogs[DefaultLanguage]
+	}
+
+	errs := make(map[string]string)
+
+	validationErrors := err.(validator.ValidationErrors)
+	for _, e := range validationErrors {
+		errs[e.Field()] = catalog.message(e)
+	}
+
+	return errs
+}
+
+// LocalizedMessage returns the catalog message for a key outside the validator's
+// tags, such as "unknown_field", in the given language
+func LocalizedMessage(key, lang string) string {
+	catalog, ok := catalogs[lang]
+	if !ok {
+		catalog = catalogs[DefaultLanguage]
+	}
+	if message, ok := catalog[key]; ok {
+		return message
+	}
+	return catalog["default"]
+}
+
+func (c Catalog) message(e validator.FieldError) string {
+	template, ok := c[e.Tag()]
+	if !ok {
+		template = c["default"]
+	}
+	return strings.ReplaceAll(template, "{param}", e.Param())
+}
@@ -0,0 +1,59 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type localizedRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"omitempty,email"`
+	Role  string `json:"role" validate:"omitempty,oneof=user admin"`
+	Bio   string `json:"bio" validate:"max=3"`
+}
+
+func TestLanguage(t *testing.T) {
+	tests := map[string]string{
+		"":                          "en",
+		"zh-CN,zh;q=0.9,en;q=0.8":   "zh",
+		"es-MX":                     "es",
+		"fr-FR,es;q=0.5,en;q=0.4":   "es",
+		"de":                        "en",
+		"en-US,en;q=0.9":            "en",
+		"not a language header;;;;": "en",
+	}
+	for header, expected := range tests {
+		assert.Equal(t, expected, Language(header), header)
+	}
+}
+
+func TestLocalizedValidationErrors(t *testing.T) {
+	err := Validate(localizedRequest{Email: "nope", Role: "owner", Bio: "long"})
+
+	en := LocalizedValidationErrors(err, "en")
+	assert.Equal(t, "This field is required", en["name"])
+	assert.Equal(t, "Must be a valid email address", en["email"])
+	assert.Equal(t, "Value must be one of: user admin", en["role"])
+	assert.Equal(t, "Value cannot be longer than 3", en["bio"])
+
+	zh := LocalizedValidationErrors(err, "zh")
+	assert.Equal(t, "此字段为必填项", zh["name"])
+	assert.Equal(t, "值不能超过 3", zh["bio"])
+
+	es := LocalizedValidationErrors(err, "es")
+	assert.Equal(t, "Este campo es obligatorio", es["name"])
+
+	// Unknown languages fall back to English
+	assert.Equal(t, en, LocalizedValidationErrors(err, "fr"))
+	assert.Equal(t, en, ValidationErrors(err))
+	assert.Nil(t, LocalizedValidationErrors(nil, "en"))
+}
+
+func TestCatalogsCoverSameTags(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for tag := range catalogs[DefaultLanguage] {
+			assert.NotEmpty(t, catalog[tag], "%s is missing %q", lang, tag)
+		}
+	}
+}
@@ -26,30 +26,7 @@ func Validate(s interface{}) error {
 	return validate.Struct(s)
 }
 
+// ValidationErrors returns a message per invalid field, in DefaultLanguage
 func ValidationErrors(err error) map[string]string {
-	if err == nil {
-		return nil
-	}
-
-	errs := make(map[string]string)
-
-	validationErrors := err.(validator.ValidationErrors)
-	for _, e := range validationErrors {
-		errs[e.Field()] = validationErrorMsg(e)
-	}
-
-	return errs
-}
-
-func validationErrorMsg(e validator.FieldError) string {
-	switch e.Tag() {
-	case "required":
-		return "This field is required"
-	case "min":
-		return "Value must be at least " + e.Param()
-	case "max":
-		return "Value cannot be longer than " + e.Param()
-	default:
-		return "Invalid value"
-	}
+	return LocalizedValidationErrors(err, DefaultLanguage)
 }
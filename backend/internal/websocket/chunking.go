@@ -0,0 +1,111 @@
+package websocket
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// maxPendingCanvasChunks limits how many chunked canvas updates a client can have in flight
+	maxPendingCanvasChunks = 4
+
+	// maxCanvasChunkCount limits how many chunks a single canvas update may be split into
+	maxCanvasChunkCount = 1024
+
+	// canvasChunkTimeout discards partially received canvas updates after this long
+	canvasChunkTimeout = 30 * time.Second
+)
+
+var (
+	ErrInvalidChunk     = errors.New("invalid canvas chunk")
+	ErrChunkTooLarge    = errors.New("canvas update exceeds size limit")
+	ErrTooManyChunkSets = errors.New("too many canvas updates in progress")
+)
+
+type canvasChunkBuffer struct {
+	parts     []string
+	have      []bool
+	received  int
+	size      int
+	startedAt time.Time
+}
+
+// CanvasChunkAssembler reassembles canvas updates that clients split across
+// several canvas_chunk messages. It is not safe for concurrent use; each client
+// owns one and only touches it from its read loop.
+type CanvasChunkAssembler struct {
+	maxSize int
+	buffers map[string]*canvasChunkBuffer
+}
+
+// NewCanvasChunkAssembler creates an assembler that rejects canvas updates larger than maxSize bytes
+func NewCanvasChunkAssembler(maxSize int) *CanvasChunkAssembler {
+	return &CanvasChunkAssembler{
+		maxSize: maxSize,
+		buffers: make(map[string]*canvasChunkBuffer),
+	}
+}
+
+// Add stores a chunk and returns the full canvas data once every chunk of the
+// update has arrived. The boolean is false while chunks are still missing.
+func (a *CanvasChunkAssembler) Add(chunk CanvasChunkPayload) (string, bool, error) {
+	if chunk.ChunkID == "" || chunk.Total < 1 || chunk.Total > maxCanvasChunkCount || chunk.Index < 0 || chunk.Index >= chunk.Total {
+		return "", false, ErrInvalidChunk
+	}
+
+	a.expire(time.Now())
+
+	buffer, exists := a.buffers[chunk.ChunkID]
+	if !exists {
+		if len(a.buffers) >= maxPendingCanvasChunks {
+			return "", false, ErrTooManyChunkSets
+		}
+		buffer = &canvasChunkBuffer{
+			parts:     make([]string, chunk.Total),
+			have:      make([]bool, chunk.Total),
+			startedAt: time.Now(),
+		}
+		a.buffers[chunk.ChunkID] = buffer
+	}
+
+	if len(buffer.parts) != chunk.Total {
+		delete(a.buffers, chunk.ChunkID)
+		return "", false, ErrInvalidChunk
+	}
+
+	// Ignore duplicate chunks
+	if buffer.have[chunk.Index] {
+		return "", false, nil
+	}
+
+	buffer.size += len(chunk.Data)
+	if a.maxSize > 0 && buffer.size > a.maxSize {
+		delete(a.buffers, chunk.ChunkID)
+		return "", false, ErrChunkTooLarge
+	}
+
+	buffer.parts[chunk.Index] = chunk.Data
+	buffer.have[chunk.Index] = true
+	buffer.received++
+
+	if buffer.received < chunk.Total {
+		return "", false, nil
+	}
+
+	delete(a.buffers, chunk.ChunkID)
+
+	data := make([]byte, 0, buffer.size)
+	for _, part := range buffer.parts {
+		data = append(data, part...)
+	}
+	return string(data), true, nil
+}
+
+// expire drops chunk sets that have not completed within canvasChunkTimeout
+func (a *CanvasChunkAssembler) expire(now time.Time) {
+	for id, buffer := range a.buffers {
+		if now.Sub(buffer.startedAt) > canvasChunkTimeout {
+			delete(a.buffers, id)
+		}
+	}
+}
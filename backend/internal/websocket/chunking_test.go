@@ -0,0 +1,67 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanvasChunkAssembler_ReassemblesOutOfOrderChunks(t *testing.T) {
+	assembler := NewCanvasChunkAssembler(1024)
+
+	data, complete, err := assembler.Add(CanvasChunkPayload{ChunkID: "a", Index: 1, Total: 3, Data: "def"})
+	assert.NoError(t, err)
+	assert.False(t, complete)
+	assert.Empty(t, data)
+
+	_, complete, err = assembler.Add(CanvasChunkPayload{ChunkID: "a", Index: 0, Total: 3, Data: "abc"})
+	assert.NoError(t, err)
+	assert.False(t, complete)
+
+	// Duplicate chunks are ignored
+	_, complete, err = assembler.Add(CanvasChunkPayload{ChunkID: "a", Index: 0, Total: 3, Data: "abc"})
+	assert.NoError(t, err)
+	assert.False(t, complete)
+
+	data, complete, err = assembler.Add(CanvasChunkPayload{ChunkID: "a", Index: 2, Total: 3, Data: "ghi"})
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	assert.Equal(t, "abcdefghi", data)
+	assert.Empty(t, assembler.buffers)
+}
+
+func TestCanvasChunkAssembler_RejectsInvalidChunks(t *testing.T) {
+	assembler := NewCanvasChunkAssembler(1024)
+
+	_, _, err := assembler.Add(CanvasChunkPayload{ChunkID: "", Index: 0, Total: 1})
+	assert.ErrorIs(t, err, ErrInvalidChunk)
+
+	_, _, err = assembler.Add(CanvasChunkPayload{ChunkID: "a", Index: 2, Total: 2})
+	assert.ErrorIs(t, err, ErrInvalidChunk)
+
+	_, _, err = assembler.Add(CanvasChunkPayload{ChunkID: "a", Index: 0, Total: maxCanvasChunkCount + 1})
+	assert.ErrorIs(t, err, ErrInvalidChunk)
+
+	// Total must stay the same for every chunk of an update
+	_, _, err = assembler.Add(CanvasChunkPayload{ChunkID: "b", Index: 0, Total: 2, Data: "x"})
+	assert.NoError(t, err)
+	_, _, err = assembler.Add(CanvasChunkPayload{ChunkID: "b", Index: 1, Total: 3, Data: "y"})
+	assert.ErrorIs(t, err, ErrInvalidChunk)
+}
+
+func TestCanvasChunkAssembler_EnforcesLimits(t *testing.T) {
+	assembler := NewCanvasChunkAssembler(4)
+
+	_, _, err := assembler.Add(CanvasChunkPayload{ChunkID: "a", Index: 0, Total: 2, Data: "abc"})
+	assert.NoError(t, err)
+	_, _, err = assembler.Add(CanvasChunkPayload{ChunkID: "a", Index: 1, Total: 2, Data: "def"})
+	assert.ErrorIs(t, err, ErrChunkTooLarge)
+	assert.Empty(t, assembler.buffers)
+
+	for i := 0; i < maxPendingCanvasChunks; i++ {
+		_, _, err = assembler.Add(CanvasChunkPayload{ChunkID: string(rune('a' + i)), Index: 0, Total: 2, Data: "x"})
+		assert.NoError(t, err)
+	}
+	_, _, err = assembler.Add(CanvasChunkPayload{ChunkID: "z", Index: 0, Total: 2, Data: "x"})
+	assert.ErrorIs(t, err, ErrTooManyChunkSets)
+}
@@ -0,0 +1,98 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TableDragCommitDelay is how long a dragged table may rest before its
+// position is saved, for clients that never send the table_moved commit
+const TableDragCommitDelay = 2 * time.Second
+
+// tableDragFlushInterval is how often the latest position of each dragged
+// table is relayed to the project (~20Hz)
+const tableDragFlushInterval = time.Second / 20
+
+type pendingDragCommit struct {
+	timer  *time.Timer
+	commit func()
+}
+
+// TableDragDebouncer saves the position a client dragged a table to once the
+// drag ends, instead of on every move. A drag ends with a table_moved commit,
+// after a rest of the debouncer's delay, or when the client leaves.
+type TableDragDebouncer struct {
+	delay   time.Duration
+	mu      sync.Mutex
+	pending map[uuid.UUID]*pendingDragCommit
+}
+
+// NewTableDragDebouncer creates a debouncer that commits drags resting for delay
+func NewTableDragDebouncer(delay time.Duration) *TableDragDebouncer {
+	return &TableDragDebouncer{
+		delay:   delay,
+		pending: make(map[uuid.UUID]*pendingDragCommit),
+	}
+}
+
+// Schedule makes commit the pending commit of a table's drag, replacing the
+// previous one, and runs it once the table has rested for the delay
+func (d *TableDragDebouncer) Schedule(tableID uuid.UUID, commit func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if previous, exists := d.pending[tableID]; exists {
+		previous.timer.Stop()
+	}
+	pending := &pendingDragCommit{commit: commit}
+	pending.timer = time.AfterFunc(d.delay, func() {
+		if d.take(tableID, pending) {
+			commit()
+		}
+	})
+	d.pending[tableID] = pending
+}
+
+// Cancel drops the pending commit of a table's drag, once the client has
+// committed the drag itself
+func (d *TableDragDebouncer) Cancel(tableID uuid.UUID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if pending, exists := d.pending[tableID]; exists {
+		pending.timer.Stop()
+		delete(d.pending, tableID)
+	}
+}
+
+// Flush runs every pending commit at once
+func (d *TableDragDebouncer) Flush() {
+	var commits []func()
+	d.mu.Lock()
+	for tableID, pending := range d.pending {
+		// Commits whose timer already fired run on their own
+		if pending.timer.Stop() {
+			commits = append(commits, pending.commit)
+			delete(d.pending, tableID)
+		}
+	}
+	d.mu.Unlock()
+
+	for _, commit := range commits {
+		commit()
+	}
+}
+
+// take removes a pending commit that is still the table's latest
+func (d *TableDragDebouncer) take(tableID uuid.UUID, pending *pendingDragCommit) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.pending[tableID] != pending {
+		return false
+	}
+	delete(d.pending, tableID)
+	return true
+}
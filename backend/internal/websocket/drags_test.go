@@ -0,0 +1,42 @@
+package websocket
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableDragDebouncer_CommitsLatestAfterRest(t *testing.T) {
+	debouncer := NewTableDragDebouncer(20 * time.Millisecond)
+	tableID := uuid.New()
+
+	var committed atomic.Int64
+	for i := 1; i <= 3; i++ {
+		debouncer.Schedule(tableID, func() { committed.Store(int64(i)) })
+	}
+
+	assert.Eventually(t, func() bool { return committed.Load() == 3 }, time.Second, 5*time.Millisecond)
+	assert.Empty(t, debouncer.pending)
+}
+
+func TestTableDragDebouncer_CancelAndFlush(t *testing.T) {
+	debouncer := NewTableDragDebouncer(time.Minute)
+	cancelled, flushed := uuid.New(), uuid.New()
+
+	var commits atomic.Int32
+	debouncer.Schedule(cancelled, func() { t.Error("a cancelled drag was committed") })
+	debouncer.Schedule(flushed, func() { commits.Add(1) })
+
+	debouncer.Cancel(cancelled)
+	debouncer.Flush()
+
+	assert.Equal(t, int32(1), commits.Load())
+	assert.Empty(t, debouncer.pending)
+
+	// Nothing is left to flush
+	debouncer.Flush()
+	assert.Equal(t, int32(1), commits.Load())
+}
@@ -0,0 +1,58 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"github.com/gorilla/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Payload encodings a client can request during the auth handshake
+const (
+	EncodingJSON        = "json"
+	EncodingMessagePack = "msgpack"
+)
+
+// NegotiateEncoding returns the encoding to use for a client, falling back to JSON
+// when the requested encoding is empty or unsupported
+func NegotiateEncoding(requested string) string {
+	if requested == EncodingMessagePack {
+		return EncodingMessagePack
+	}
+	return EncodingJSON
+}
+
+// EncodeFrame converts a JSON-encoded message into the wire format of the given
+// encoding and returns the WebSocket frame type to send it with
+func EncodeFrame(encoding string, jsonBytes []byte) (int, []byte, error) {
+	if encoding != EncodingMessagePack {
+		return websocket.TextMessage, jsonBytes, nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(jsonBytes, &value); err != nil {
+		return 0, nil, err
+	}
+
+	packed, err := msgpack.Marshal(value)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return websocket.BinaryMessage, packed, nil
+}
+
+// DecodeFrame converts an incoming frame into JSON bytes. Binary frames are
+// treated as MessagePack, text frames are returned unchanged
+func DecodeFrame(frameType int, data []byte) ([]byte, error) {
+	if frameType != websocket.BinaryMessage {
+		return data, nil
+	}
+
+	var value interface{}
+	if err := msgpack.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(value)
+}
@@ -9,11 +9,41 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/Bug-Bugger/ezmodel/internal/broker"
 	"github.com/Bug-Bugger/ezmodel/internal/redis"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
+// cursorFlushInterval is how often coalesced cursor updates are fanned out (~30Hz)
+const cursorFlushInterval = time.Second / 30
+
+// viewportFlushInterval is how often a user's latest viewport is relayed to
+// their followers (~10Hz)
+const viewportFlushInterval = time.Second / 10
+
+// cursorPersistInterval is how often the latest cursor of each user is handed
+// to the session listener and shared with other nodes' presence
+const cursorPersistInterval = 2 * time.Second
+
+// A client is slow when its send queue is at least half full or its pings
+// take slowRTT or longer to come back. Slow clients get cursor updates
+// slowCursorDivisor times less often, coalesced, rather than missing them.
+const (
+	slowRTT           = time.Second
+	slowCursorDivisor = 4
+)
+
+// sessionEventQueueSize is how many session events may wait for the listener
+const sessionEventQueueSize = 256
+
+// defaultIdleTimeout is how long a user does nothing before they are idle
+const defaultIdleTimeout = 5 * time.Minute
+
+// ephemeralMaxAge is how late a cursor or viewport update from another node
+// may arrive and still be delivered. Pings last their own TTL.
+const ephemeralMaxAge = time.Second
+
 // Client represents a WebSocket client connection
 type Client struct {
 	ID        uuid.UUID
@@ -21,27 +51,64 @@ type Client struct {
 	ProjectID uuid.UUID
 	Username  string
 	UserColor string
+	AvatarURL string
+	Encoding  string // Payload encoding negotiated during auth (json or msgpack)
 	Conn      *websocket.Conn
 	Send      chan []byte
 	Hub       *Hub
 	LastPing  time.Time
+
+	// CanvasChunks reassembles chunked canvas updates sent by this client
+	CanvasChunks *CanvasChunkAssembler
+
+	// TableDrags saves the tables this client drags once each drag ends
+	TableDrags *TableDragDebouncer
+
+	// Activity of the client, for its user's presence status
+	lastActivity atomic.Int64 // Unix nanoseconds
+	away         atomic.Bool
+
+	// Round trip of the client's last ping in nanoseconds
+	rtt atomic.Int64
+
+	// Whether the client's user may only view the project
+	readOnly atomic.Bool
 }
 
-// Hub maintains the set of active clients and broadcasts messages to them
-type Hub struct {
-	// Registered clients by project ID
-	projects map[uuid.UUID]map[*Client]bool
+// ReadOnly reports whether the client's user may not change the project
+func (c *Client) ReadOnly() bool {
+	return c.readOnly.Load()
+}
 
-	// Register requests from the clients
-	register chan *Client
+// SetReadOnly sets whether the client's user may not change the project, as
+// found when it connected. Later changes come through Hub.UpdatePermissions.
+func (c *Client) SetReadOnly(readOnly bool) {
+	c.readOnly.Store(readOnly)
+}
 
-	// Unregister requests from clients
-	unregister chan *Client
+// connectionQuality measures how well the client keeps up
+func (c *Client) connectionQuality() ConnectionQuality {
+	rtt := time.Duration(c.rtt.Load())
+	queueDepth := len(c.Send)
+	return ConnectionQuality{
+		RTTMs:      rtt.Milliseconds(),
+		QueueDepth: queueDepth,
+		Slow:       rtt >= slowRTT || queueDepth >= cap(c.Send)/2,
+	}
+}
+
+// Hub routes clients and messages to per-project shards. Each shard runs its
+// own goroutine, so the hub itself only guards the shard lookup table.
+type Hub struct {
+	// Shards by project ID, created on first registration and removed when empty
+	shards map[uuid.UUID]*projectShard
 
-	// Inbound messages from the clients
-	broadcast chan *BroadcastMessage
+	// Non-client consumers of project broadcasts, guarded by mu
+	observers observerSet
 
-	// Mutex for thread-safe operations
+	// Guards shards and observers. Lock order: subMu, then mu, then a shard's
+	// lock. Broker calls, which may block on the network, are never made
+	// while holding mu.
 	mu sync.RWMutex
 
 	// Ticker for ping/pong heartbeat
@@ -50,15 +117,113 @@ type Hub struct {
 	// Done channel for graceful shutdown
 	done chan struct{}
 
-	// Redis client for cross-region synchronization
+	// Message broker for cross-node synchronization
+	broker broker.Broker
+
+	// Sinks exporting this node's schema-change events, set before Run
+	eventSinks       []*eventSink
+	eventSinksWG     sync.WaitGroup
+	eventSinksMu     sync.RWMutex
+	eventSinksClosed bool
+
+	// Database writes made for clients, run by a bounded pool of workers
+	writes writePool
+
+	// Redis client for cross-node presence
 	redisClient *redis.Client
 
-	// Active Redis subscriptions by project ID
+	// Active broker subscriptions by project ID, guarded by subMu
 	subscriptions map[uuid.UUID]context.CancelFunc
 	subMu         sync.Mutex
 
-	// Atomic flag for shutdown state
+	// Identifies this node's presence entries in Redis
+	nodeID string
+
+	// Projects whose local presence must be written to Redis
+	presenceUpdates chan uuid.UUID
+
+	// Atomic flags for shutdown state. While draining, existing clients are
+	// still served but new ones are refused.
+	isDraining     atomic.Bool
 	isShuttingDown atomic.Bool
+
+	// Numbers schema changes broadcast without a sequence, set before Run
+	sequencer Sequencer
+
+	// How long a user does nothing before they are idle, set before Run
+	idleTimeout time.Duration
+
+	// Listener for session lifecycle events, fed in order by a single goroutine
+	sessionListener SessionListener
+	sessionEvents   chan sessionEvent
+
+	// Counters for hub statistics
+	messagesBroadcast     atomic.Uint64
+	messagesDelivered     atomic.Uint64
+	droppedSends          atomic.Uint64
+	brokerPublishFailures atomic.Uint64
+	eventSinkFailures     atomic.Uint64
+	rejectedWrites        atomic.Uint64
+	activeWrites          atomic.Int64
+	droppedSessionEvents  atomic.Uint64
+
+	// Broadcast rate sampled on every heartbeat tick
+	rateMu            sync.Mutex
+	rateSampledAt     time.Time
+	rateSampledCount  uint64
+	messagesPerSecond float64
+}
+
+// SessionListener is notified when a user's first connection to a project opens
+// and when their last connection to it closes, and of where the user's cursor
+// is, at most every cursorPersistInterval and before the user disconnects
+type SessionListener interface {
+	UserConnected(projectID, userID uuid.UUID, userColor string)
+	UserDisconnected(projectID, userID uuid.UUID)
+	CursorMoved(projectID, userID uuid.UUID, cursorX, cursorY float64)
+	StatusChanged(projectID, userID uuid.UUID, status string)
+}
+
+// Sequencer hands out a project's sequence numbers, one per change
+type Sequencer interface {
+	NextSequence(projectID uuid.UUID) (int64, error)
+}
+
+// sessionEventKind selects the SessionListener method a sessionEvent calls
+type sessionEventKind int
+
+const (
+	sessionConnected sessionEventKind = iota
+	sessionDisconnected
+	sessionCursorMoved
+	sessionStatusChanged
+)
+
+// sessionEvent is a queued call to the SessionListener
+type sessionEvent struct {
+	kind      sessionEventKind
+	projectID uuid.UUID
+	userID    uuid.UUID
+	userColor string
+	cursor    Point
+	status    string
+}
+
+// HubStats is a point-in-time snapshot of hub activity
+type HubStats struct {
+	TotalConnections      int               `json:"total_connections"`
+	ConnectionsByProject  map[uuid.UUID]int `json:"connections_by_project"`
+	Observers             int               `json:"observers"` // GraphQL subscriptions and other observers
+	MessagesBroadcast     uint64            `json:"messages_broadcast"`
+	MessagesDelivered     uint64            `json:"messages_delivered"`
+	MessagesPerSecond     float64           `json:"messages_per_second"`
+	DroppedSends          uint64            `json:"dropped_sends"`
+	BrokerPublishFailures uint64            `json:"broker_publish_failures"`
+	EventSinkFailures     uint64            `json:"event_sink_failures"`    // Events dropped or not accepted by a sink
+	QueuedWrites          int               `json:"queued_writes"`          // Client writes waiting for a worker
+	ActiveWrites          int64             `json:"active_writes"`          // Client writes being run
+	RejectedWrites        uint64            `json:"rejected_writes"`        // Client writes refused because the queue was full
+	DroppedSessionEvents  uint64            `json:"dropped_session_events"` // Cursor and status updates dropped while the session listener was behind
 }
 
 // BroadcastMessage represents a message to be broadcasted
@@ -71,25 +236,104 @@ type BroadcastMessage struct {
 // NewHub creates a new WebSocket hub
 func NewHub() *Hub {
 	return &Hub{
-		projects:      make(map[uuid.UUID]map[*Client]bool),
-		register:      make(chan *Client),
-		unregister:    make(chan *Client),
-		broadcast:     make(chan *BroadcastMessage),
-		ticker:        time.NewTicker(30 * time.Second),
-		done:          make(chan struct{}),
-		subscriptions: make(map[uuid.UUID]context.CancelFunc),
+		shards: make(map[uuid.UUID]*projectShard),
+		observers: observerSet{
+			byProject: make(map[uuid.UUID]map[*observer]struct{}),
+		},
+		ticker:          time.NewTicker(30 * time.Second),
+		done:            make(chan struct{}),
+		subscriptions:   make(map[uuid.UUID]context.CancelFunc),
+		nodeID:          uuid.New().String(),
+		presenceUpdates: make(chan uuid.UUID, 1024),
+		idleTimeout:     defaultIdleTimeout,
+		writes:          writePool{workers: defaultWriteWorkers, queueSize: defaultWriteQueueSize},
+		rateSampledAt:   time.Now(),
 	}
 }
 
-// SetRedisClient sets the Redis client for cross-region synchronization
+// SetRedisClient sets the Redis client used to share presence across nodes
 func (h *Hub) SetRedisClient(client *redis.Client) {
 	h.redisClient = client
 	if client != nil && client.IsEnabled() {
-		log.Println("Redis client enabled for WebSocket hub - cross-region sync active")
+		log.Println("Redis client enabled for WebSocket hub - cross-node presence active")
+		go h.runPresenceWriter()
+	}
+}
+
+// SetBroker sets the message broker for cross-node synchronization
+func (h *Hub) SetBroker(b broker.Broker) {
+	h.broker = b
+	if b != nil {
+		log.Printf("%s broker enabled for WebSocket hub - cross-node sync active", b.Name())
 	}
 }
 
-// Run starts the hub and handles all client connections
+// SetSequencer makes the hub number the schema changes it broadcasts that do
+// not have a sequence yet, such as those relayed from clients
+func (h *Hub) SetSequencer(sequencer Sequencer) {
+	h.sequencer = sequencer
+}
+
+// SetIdleTimeout sets how long a user does nothing before they are idle
+func (h *Hub) SetIdleTimeout(timeout time.Duration) {
+	h.idleTimeout = timeout
+}
+
+// SetSessionListener registers a listener for user connect and disconnect events.
+// Events are delivered in order on a dedicated goroutine through a queue of
+// sessionEventQueueSize, so a slow listener only holds up shards once the
+// queue is full. Must be called before clients connect.
+func (h *Hub) SetSessionListener(listener SessionListener) {
+	h.sessionListener = listener
+	h.sessionEvents = make(chan sessionEvent, sessionEventQueueSize)
+
+	go func() {
+		for {
+			select {
+			case event := <-h.sessionEvents:
+				switch event.kind {
+				case sessionConnected:
+					listener.UserConnected(event.projectID, event.userID, event.userColor)
+				case sessionDisconnected:
+					listener.UserDisconnected(event.projectID, event.userID)
+				case sessionCursorMoved:
+					listener.CursorMoved(event.projectID, event.userID, event.cursor.X, event.cursor.Y)
+				case sessionStatusChanged:
+					listener.StatusChanged(event.projectID, event.userID, event.status)
+				}
+			case <-h.done:
+				return
+			}
+		}
+	}()
+}
+
+// notifySession queues a session lifecycle event if a listener is registered.
+// While the queue is full, connect and disconnect events wait for room, so
+// sessions are never left open, but cursor and status updates are dropped and
+// counted: the shard goes on, and a later update supersedes them.
+func (h *Hub) notifySession(event sessionEvent) {
+	if h.sessionListener == nil {
+		return
+	}
+
+	if event.kind == sessionCursorMoved || event.kind == sessionStatusChanged {
+		select {
+		case h.sessionEvents <- event:
+		default:
+			h.droppedSessionEvents.Add(1)
+		}
+		return
+	}
+
+	select {
+	case h.sessionEvents <- event:
+	case <-h.done:
+	}
+}
+
+// Run drives the hub heartbeat. Project shards run independently and are
+// started on demand by RegisterClient.
 func (h *Hub) Run() {
 	defer func() {
 		h.ticker.Stop()
@@ -98,17 +342,11 @@ func (h *Hub) Run() {
 
 	for {
 		select {
-		case client := <-h.register:
-			h.registerClient(client)
-
-		case client := <-h.unregister:
-			h.unregisterClient(client)
-
-		case message := <-h.broadcast:
-			h.broadcastMessage(message)
-
 		case <-h.ticker.C:
 			h.pingClients()
+			h.sampleMessageRate()
+			h.updateStatuses()
+			h.refreshPresence()
 
 		case <-h.done:
 			return
@@ -118,248 +356,326 @@ func (h *Hub) Run() {
 
 // RegisterClient registers a new client to the hub
 func (h *Hub) RegisterClient(client *Client) {
-	h.register <- client
+	for {
+		shard := h.getOrCreateShard(client.ProjectID)
+		if shard == nil {
+			return
+		}
+
+		select {
+		case shard.register <- client:
+			return
+		case <-shard.done:
+			// The shard stopped after its last client left; retry with a fresh one
+		}
+	}
 }
 
 // UnregisterClient unregisters a client from the hub
 func (h *Hub) UnregisterClient(client *Client) {
-	h.unregister <- client
+	shard := h.getShard(client.ProjectID)
+	if shard == nil {
+		return
+	}
+
+	select {
+	case shard.unregister <- client:
+	case <-shard.done:
+	}
 }
 
 // BroadcastToProject broadcasts a message to all clients in a project
 func (h *Hub) BroadcastToProject(projectID uuid.UUID, message *WebSocketMessage, sender *Client) {
-	h.broadcast <- &BroadcastMessage{
+	if message.Sequence == 0 && message.Type.IsSchemaChange() {
+		h.assignSequence(projectID, message)
+	}
+	// Viewports are for followers only, never for observers
+	if !h.isShuttingDown.Load() && message.Type != MessageTypeViewportUpdate {
+		h.notifyObservers(projectID, message)
+	}
+	h.exportEvent(projectID, message)
+
+	// Without a shard there are no local clients to deliver to, but clients
+	// on other nodes still need the message
+	shard := h.getShard(projectID)
+	if shard == nil {
+		if h.isShuttingDown.Load() {
+			return
+		}
+		messageBytes, err := json.Marshal(message)
+		if err != nil {
+			log.Printf("Error marshaling message: %v", err)
+			return
+		}
+		h.publishToBroker(projectID, messageBytes)
+		return
+	}
+
+	select {
+	case shard.broadcast <- &BroadcastMessage{
 		ProjectID: projectID,
 		Message:   message,
 		Sender:    sender,
+	}:
+	case <-shard.done:
 	}
 }
 
-// registerClient handles client registration
-func (h *Hub) registerClient(client *Client) {
-	// Check if shutting down
-	if h.isShuttingDown.Load() {
-		log.Printf("Cannot register client %s: hub is shutting down", client.UserID)
+// RelayTableDrag relays the position of a table a client is dragging to the
+// other clients of the project, throttled to the latest position of each
+// table. Drag positions are not saved, so they take no sequence and are not
+// exported or observed.
+func (h *Hub) RelayTableDrag(client *Client, tableID uuid.UUID, message *WebSocketMessage) {
+	shard := h.getShard(client.ProjectID)
+	if shard == nil {
 		return
 	}
 
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	isFirstClient := h.projects[client.ProjectID] == nil || len(h.projects[client.ProjectID]) == 0
-
-	// Initialize project map if it doesn't exist
-	if h.projects[client.ProjectID] == nil {
-		h.projects[client.ProjectID] = make(map[*Client]bool)
-	}
-
-	// Add client to project
-	h.projects[client.ProjectID][client] = true
-	client.LastPing = time.Now()
-
-	log.Printf("Client %s joined project %s", client.UserID, client.ProjectID)
-
-	// Start Redis subscription if this is the first client for this project
-	// This is done outside the lock to avoid blocking
-	if isFirstClient {
-		// Temporarily release lock for Redis subscription
-		h.mu.Unlock()
-		h.subscribeToRedis(client.ProjectID)
-		h.mu.Lock()
+	select {
+	case shard.drags <- tableDrag{
+		tableID: tableID,
+		message: &BroadcastMessage{ProjectID: client.ProjectID, Message: message, Sender: client},
+	}:
+	case <-shard.done:
 	}
+}
 
-	// Notify other clients about the new user
-	userJoinedPayload := UserJoinedPayload{
-		UserID:    client.UserID,
-		Username:  client.Username,
-		UserColor: client.UserColor,
+// SendToClient sends a message to a single client of this node, such as an
+// error about a message it sent. Nothing is sent once the client has left.
+func (h *Hub) SendToClient(client *Client, message *WebSocketMessage) {
+	shard := h.getShard(client.ProjectID)
+	if shard == nil {
+		return
 	}
 
-	message, err := NewWebSocketMessage(MessageTypeUserJoined, userJoinedPayload, client.UserID, client.ProjectID)
+	messageBytes, err := json.Marshal(message)
 	if err != nil {
-		log.Printf("Error creating user joined message: %v", err)
+		log.Printf("Error marshaling message: %v", err)
 		return
 	}
 
-	// Broadcast to all clients in the project except the sender (lock is held)
-	h.broadcastToProjectExceptLocked(client.ProjectID, message, client)
-
-	// Send current presence to the new client (lock is held)
-	h.sendPresenceToClientLocked(client)
-}
-
-// unregisterClient handles client disconnection
-func (h *Hub) unregisterClient(client *Client) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	var shouldCloseSubscription bool
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
 
-	if clients, exists := h.projects[client.ProjectID]; exists {
-		if _, exists := clients[client]; exists {
-			delete(clients, client)
-			// Safely close the channel
-			h.safeCloseChannel(client.Send)
-
-			// Clean up empty project maps and stop Redis subscription
-			if len(clients) == 0 {
-				delete(h.projects, client.ProjectID)
-				shouldCloseSubscription = true
-			}
-		}
+	if shard.clients[client] {
+		h.deliver(client, messageBytes)
 	}
+}
 
-	log.Printf("Client %s left project %s", client.UserID, client.ProjectID)
-
-	// Notify other clients about the user leaving (lock is held)
-	userLeftPayload := UserLeftPayload{
-		UserID: client.UserID,
+// RecordPong measures a client's round trip from the timestamp of the ping it
+// answered. Timestamps that are not a recent ping of this server are ignored.
+func (h *Hub) RecordPong(client *Client, pingSentAt time.Time) {
+	rtt := time.Since(pingSentAt)
+	if pingSentAt.IsZero() || rtt < 0 || rtt > 2*time.Minute {
+		return
 	}
+	client.rtt.Store(int64(rtt))
+}
 
-	message, err := NewWebSocketMessage(MessageTypeUserLeft, userLeftPayload, client.UserID, client.ProjectID)
-	if err != nil {
-		log.Printf("Error creating user left message: %v", err)
+// TouchClient records that a client did something, making its user active
+// again if they were idle
+func (h *Hub) TouchClient(client *Client) {
+	now := time.Now()
+	last := time.Unix(0, client.lastActivity.Swap(now.UnixNano()))
+	if now.Sub(last) < h.idleTimeout {
 		return
 	}
-
-	h.broadcastToProjectExceptLocked(client.ProjectID, message, client)
-
-	// Stop Redis subscription if no more clients in project
-	// Do this outside lock to avoid blocking
-	if shouldCloseSubscription {
-		h.mu.Unlock()
-		h.unsubscribeFromRedis(client.ProjectID)
-		h.mu.Lock()
+	if shard := h.getShard(client.ProjectID); shard != nil {
+		shard.updateStatus(client.UserID)
 	}
 }
 
-// broadcastMessage handles message broadcasting
-func (h *Hub) broadcastMessage(broadcastMsg *BroadcastMessage) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	h.broadcastToProjectExceptLocked(broadcastMsg.ProjectID, broadcastMsg.Message, broadcastMsg.Sender)
-}
-
-// broadcastToProjectExcept broadcasts a message to all clients in a project except the sender
-// This is the public version that acquires its own lock
-func (h *Hub) broadcastToProjectExcept(projectID uuid.UUID, message *WebSocketMessage, except *Client) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	h.broadcastToProjectExceptLocked(projectID, message, except)
+// SetClientAway records whether a client's tab is in the background. Coming
+// back counts as activity.
+func (h *Hub) SetClientAway(client *Client, away bool) {
+	if !away {
+		client.lastActivity.Store(time.Now().UnixNano())
+	}
+	client.away.Store(away)
+	if shard := h.getShard(client.ProjectID); shard != nil {
+		shard.updateStatus(client.UserID)
+	}
 }
 
-// broadcastToProjectExceptLocked broadcasts a message to all clients in a project except the sender
-// MUST be called with h.mu lock held (either read or write lock)
-func (h *Hub) broadcastToProjectExceptLocked(projectID uuid.UUID, message *WebSocketMessage, except *Client) {
-	// Check if shutting down
+// updateStatuses makes users who did nothing for the idle timeout idle
+func (h *Hub) updateStatuses() {
 	if h.isShuttingDown.Load() {
 		return
 	}
+	for _, shard := range h.snapshotShards() {
+		shard.updateStatuses()
+	}
+}
 
-	clients, exists := h.projects[projectID]
-	if !exists {
+// FollowUser makes a client receive the viewport updates of a user of its
+// project, on any node, from the user's last viewport on. Following uuid.Nil
+// stops following.
+func (h *Hub) FollowUser(client *Client, userID uuid.UUID) {
+	shard := h.getShard(client.ProjectID)
+	if shard == nil {
 		return
 	}
+	shard.follow(client, userID)
+}
 
+// DisconnectUser closes a user's connections to a project, on every node,
+// after telling them why with an access_revoked message
+func (h *Hub) DisconnectUser(projectID, userID uuid.UUID, reason string) error {
+	payload := AccessRevokedPayload{
+		UserID:  userID,
+		Message: reason,
+	}
+	message, err := NewWebSocketMessage(MessageTypeAccessRevoked, payload, userID, projectID)
+	if err != nil {
+		return err
+	}
 	messageBytes, err := json.Marshal(message)
 	if err != nil {
-		log.Printf("Error marshaling message: %v", err)
-		return
+		return err
 	}
 
-	for client := range clients {
-		if client != except {
-			select {
-			case client.Send <- messageBytes:
-			default:
-				// Client's send channel is full, skip this client
-				// Don't modify the map or close channels here since we might only have read lock
-				log.Printf("Skipping client %s (channel full)", client.UserID)
-			}
-		}
+	if shard := h.getShard(projectID); shard != nil {
+		shard.disconnectUser(userID, messageBytes)
+	}
+	h.closeUserObservers(projectID, userID)
+	h.publishToBroker(projectID, messageBytes)
+	return nil
+}
+
+// UpdatePermissions tells a user's connections to a project, on every node,
+// about their new role, and makes them read-only if they can no longer edit
+func (h *Hub) UpdatePermissions(projectID, userID uuid.UUID, role string, canEdit bool) error {
+	payload := PermissionsUpdatedPayload{
+		UserID:  userID,
+		Role:    role,
+		CanEdit: canEdit,
+	}
+	message, err := NewWebSocketMessage(MessageTypePermissionsUpdated, payload, userID, projectID)
+	if err != nil {
+		return err
+	}
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		return err
 	}
 
-	// Publish to Redis for cross-region synchronization (async)
-	h.publishToRedis(projectID, messageBytes)
+	if shard := h.getShard(projectID); shard != nil {
+		shard.updatePermissions(userID, canEdit, messageBytes)
+	}
+	h.publishToBroker(projectID, messageBytes)
+	return nil
 }
 
-// publishToRedis publishes a message to Redis for cross-region synchronization
-func (h *Hub) publishToRedis(projectID uuid.UUID, messageBytes []byte) {
-	if h.redisClient == nil || !h.redisClient.IsEnabled() {
+// assignSequence gives a schema change the project's next sequence number. A
+// change that cannot be numbered is still broadcast, without one.
+func (h *Hub) assignSequence(projectID uuid.UUID, message *WebSocketMessage) {
+	if h.sequencer == nil {
 		return
 	}
-
-	// Publish asynchronously to avoid blocking local broadcasts
-	go func() {
-		channel := fmt.Sprintf("project:%s", projectID.String())
-		if err := h.redisClient.Publish(channel, messageBytes); err != nil {
-			log.Printf("Failed to publish to Redis channel %s: %v", channel, err)
-		}
-	}()
+	sequence, err := h.sequencer.NextSequence(projectID)
+	if err != nil {
+		log.Printf("Failed to number %s message for project %s: %v", message.Type, projectID, err)
+		return
+	}
+	message.Sequence = sequence
 }
 
-// sendPresenceToClient sends current presence information to a specific client
-// This is the public version that acquires its own lock
-func (h *Hub) sendPresenceToClient(targetClient *Client) {
+// getShard returns the shard for a project, or nil if no client is connected to it
+func (h *Hub) getShard(projectID uuid.UUID) *projectShard {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	h.sendPresenceToClientLocked(targetClient)
+	return h.shards[projectID]
 }
 
-// sendPresenceToClientLocked sends current presence information to a specific client
-// MUST be called with h.mu lock held (either read or write lock)
-func (h *Hub) sendPresenceToClientLocked(targetClient *Client) {
-	// Check if shutting down
-	if h.isShuttingDown.Load() {
-		return
+// Accepting reports whether the hub takes new clients, i.e. it is neither
+// draining nor shut down
+func (h *Hub) Accepting() bool {
+	return !h.isShuttingDown.Load() && !h.isDraining.Load()
+}
+
+// getOrCreateShard returns the shard for a project, starting one if needed.
+// Returns nil when the hub is draining or shutting down.
+func (h *Hub) getOrCreateShard(projectID uuid.UUID) *projectShard {
+	if !h.Accepting() {
+		return nil
 	}
 
-	clients, exists := h.projects[targetClient.ProjectID]
+	h.mu.Lock()
+	shard, exists := h.shards[projectID]
 	if !exists {
-		return
+		shard = newProjectShard(h, projectID)
+		h.shards[projectID] = shard
+		go shard.run()
 	}
+	h.mu.Unlock()
 
-	var activeUsers []ActiveUser
+	// Start broker subscription for the first client of this project, outside
+	// h.mu so a slow broker does not stall every other project's lookups
+	if !exists {
+		h.syncBrokerSubscription(projectID)
+	}
 
-	log.Printf("DEBUG: Preparing presence for client %s in project %s. Total clients in project: %d", targetClient.UserID, targetClient.ProjectID, len(clients))
+	return shard
+}
 
-	// Include ALL users in the project (including the target client)
-	for client := range clients {
-		log.Printf("DEBUG: Adding user %s (%s) to presence list", client.UserID, client.Username)
-		activeUsers = append(activeUsers, ActiveUser{
-			UserID:    client.UserID,
-			Username:  client.Username,
-			UserColor: client.UserColor,
-			LastSeen:  client.LastPing,
-		})
+// removeShardIfEmpty drops a shard from the hub once its last client has left.
+// Called from the shard goroutine; returns true if the shard should stop.
+func (h *Hub) removeShardIfEmpty(shard *projectShard) bool {
+	h.mu.Lock()
+	if shard.clientCount() > 0 || h.shards[shard.projectID] != shard {
+		h.mu.Unlock()
+		return false
 	}
+	delete(h.shards, shard.projectID)
+	h.mu.Unlock()
 
-	log.Printf("DEBUG: Sending presence with %d users to client %s", len(activeUsers), targetClient.UserID)
+	// Stop broker subscription unless local clients or observers came back
+	h.syncBrokerSubscription(shard.projectID)
+	return true
+}
 
-	presencePayload := UserPresencePayload{
-		ActiveUsers: activeUsers,
+// snapshotShards returns the current shards so callers can iterate without holding h.mu
+func (h *Hub) snapshotShards() []*projectShard {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	shards := make([]*projectShard, 0, len(h.shards))
+	for _, shard := range h.shards {
+		shards = append(shards, shard)
 	}
+	return shards
+}
 
-	message, err := NewWebSocketMessage(MessageTypeUserPresence, presencePayload, targetClient.UserID, targetClient.ProjectID)
-	if err != nil {
-		log.Printf("Error creating presence message: %v", err)
-		return
+// deliver queues message bytes on a client's send channel without blocking.
+// Callers must hold the owning shard's lock so the channel cannot be closed concurrently.
+func (h *Hub) deliver(client *Client, messageBytes []byte) bool {
+	select {
+	case client.Send <- messageBytes:
+		h.messagesDelivered.Add(1)
+		return true
+	default:
+		// Client's send channel is full, skip this client
+		h.droppedSends.Add(1)
+		log.Printf("Skipping client %s (channel full)", client.UserID)
+		return false
 	}
+}
 
-	messageBytes, err := json.Marshal(message)
-	if err != nil {
-		log.Printf("Error marshaling presence message: %v", err)
+// publishToBroker publishes a message to the broker for cross-node synchronization
+func (h *Hub) publishToBroker(projectID uuid.UUID, messageBytes []byte) {
+	if h.broker == nil {
 		return
 	}
 
-	select {
-	case targetClient.Send <- messageBytes:
-	default:
-		log.Printf("Failed to send presence to client %s", targetClient.UserID)
-	}
+	// Publish asynchronously to avoid blocking local broadcasts
+	go func() {
+		topic := projectTopic(projectID)
+		if err := h.broker.Publish(topic, messageBytes); err != nil {
+			h.brokerPublishFailures.Add(1)
+			log.Printf("Failed to publish to %s topic %s: %v", h.broker.Name(), topic, err)
+		}
+	}()
 }
 
 // pingClients sends ping messages to all clients for heartbeat
@@ -371,11 +687,10 @@ func (h *Hub) pingClients() {
 
 	now := time.Now()
 	pingPayload := PingPayload{Timestamp: now}
-	clientsToUnregister := make(map[*Client]struct{})
+	var clientsToUnregister []*Client
 
-	h.mu.RLock()
-	for projectID, clients := range h.projects {
-		message, err := NewWebSocketMessage(MessageTypePing, pingPayload, uuid.Nil, projectID)
+	for _, shard := range h.snapshotShards() {
+		message, err := NewWebSocketMessage(MessageTypePing, pingPayload, uuid.Nil, shard.projectID)
 		if err != nil {
 			log.Printf("Error creating ping message: %v", err)
 			continue
@@ -387,55 +702,134 @@ func (h *Hub) pingClients() {
 			continue
 		}
 
-		for client := range clients {
+		shard.mu.RLock()
+		for client := range shard.clients {
 			// Check if client is stale (no pong for 2 minutes)
 			if now.Sub(client.LastPing) > 2*time.Minute {
-				clientsToUnregister[client] = struct{}{}
+				clientsToUnregister = append(clientsToUnregister, client)
 				continue
 			}
 
 			select {
 			case client.Send <- messageBytes:
 			default:
-				clientsToUnregister[client] = struct{}{}
+				clientsToUnregister = append(clientsToUnregister, client)
 			}
 		}
+		shard.mu.RUnlock()
 	}
-	h.mu.RUnlock()
 
-	for client := range clientsToUnregister {
-		h.unregisterClient(client)
+	for _, client := range clientsToUnregister {
+		h.UnregisterClient(client)
 	}
 }
 
 // GetActiveClients returns the number of active clients in a project
 func (h *Hub) GetActiveClients(projectID uuid.UUID) int {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	if clients, exists := h.projects[projectID]; exists {
-		return len(clients)
+	shard := h.getShard(projectID)
+	if shard == nil {
+		return 0
 	}
-	return 0
+	return shard.clientCount()
 }
 
-// GetActiveUsers returns a list of active users in a project
+// GetActiveUsers returns the users connected to a project on this node and,
+// when Redis is enabled, on every other node
 func (h *Hub) GetActiveUsers(projectID uuid.UUID) []ActiveUser {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+	var localUsers []ActiveUser
+	if shard := h.getShard(projectID); shard != nil {
+		localUsers = shard.activeUsers()
+	}
+	return mergeActiveUsers(localUsers, h.remoteActiveUsers(projectID))
+}
+
+// Stats returns a snapshot of connection counts and message counters
+func (h *Hub) Stats() HubStats {
+	stats := HubStats{
+		ConnectionsByProject:  make(map[uuid.UUID]int),
+		MessagesBroadcast:     h.messagesBroadcast.Load(),
+		MessagesDelivered:     h.messagesDelivered.Load(),
+		DroppedSends:          h.droppedSends.Load(),
+		BrokerPublishFailures: h.brokerPublishFailures.Load(),
+		EventSinkFailures:     h.eventSinkFailures.Load(),
+		QueuedWrites:          h.queuedWrites(),
+		ActiveWrites:          h.activeWrites.Load(),
+		RejectedWrites:        h.rejectedWrites.Load(),
+		DroppedSessionEvents:  h.droppedSessionEvents.Load(),
+	}
+
+	for _, shard := range h.snapshotShards() {
+		count := shard.clientCount()
+		stats.ConnectionsByProject[shard.projectID] = count
+		stats.TotalConnections += count
+	}
+	stats.Observers = h.observerCount()
 
-	var activeUsers []ActiveUser
-	if clients, exists := h.projects[projectID]; exists {
-		for client := range clients {
-			activeUsers = append(activeUsers, ActiveUser{
-				UserID:    client.UserID,
-				Username:  client.Username,
-				UserColor: client.UserColor,
-				LastSeen:  client.LastPing,
-			})
+	h.rateMu.Lock()
+	stats.MessagesPerSecond = h.messagesPerSecond
+	h.rateMu.Unlock()
+
+	return stats
+}
+
+// sampleMessageRate updates the broadcast rate from the messages seen since the last sample
+func (h *Hub) sampleMessageRate() {
+	now := time.Now()
+	count := h.messagesBroadcast.Load()
+
+	h.rateMu.Lock()
+	defer h.rateMu.Unlock()
+
+	if elapsed := now.Sub(h.rateSampledAt).Seconds(); elapsed > 0 {
+		h.messagesPerSecond = float64(count-h.rateSampledCount) / elapsed
+	}
+	h.rateSampledAt = now
+	h.rateSampledCount = count
+}
+
+// drainPollInterval is how often Drain checks whether client send queues are empty
+const drainPollInterval = 50 * time.Millisecond
+
+// Drain stops accepting clients, flushes queued cursor updates, sends every
+// client a server_shutdown notice and waits for send queues to empty before
+// shutting the hub down. Gives up waiting when ctx expires.
+func (h *Hub) Drain(ctx context.Context) {
+	if !h.isDraining.CompareAndSwap(false, true) {
+		return
+	}
+	defer h.Shutdown()
+
+	shards := h.snapshotShards()
+	for _, shard := range shards {
+		reply := make(chan struct{})
+		select {
+		case shard.drain <- reply:
+			<-reply
+		case <-shard.done:
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		pending := 0
+		for _, shard := range shards {
+			pending += shard.pendingSends()
+		}
+		if pending == 0 {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.Printf("Drain deadline reached with %d messages still queued", pending)
+			return
 		}
 	}
-	return activeUsers
 }
 
 // Shutdown gracefully shuts down the hub
@@ -446,136 +840,169 @@ func (h *Hub) Shutdown() {
 		return
 	}
 
-	// Signal the hub to stop processing
+	// Signal the hub and every shard to stop processing
 	h.safeCloseDoneChannel()
 
-	// Give hub goroutines a moment to finish current operations
-	time.Sleep(50 * time.Millisecond)
-
-	// Now acquire lock and clean up
+	// Take ownership of all shards and wait for their goroutines to exit
 	h.mu.Lock()
-	defer h.mu.Unlock()
+	shards := h.shards
+	h.shards = make(map[uuid.UUID]*projectShard)
+	h.mu.Unlock()
+
+	for _, shard := range shards {
+		<-shard.done
+	}
 
-	// Close all Redis subscriptions
+	// Drop this node's presence so other nodes stop reporting its users
+	if h.presenceEnabled() {
+		for projectID := range shards {
+			if err := h.redisClient.HDel(presenceKey(projectID), h.nodeID); err != nil {
+				log.Printf("Failed to clear presence for project %s: %v", projectID, err)
+			}
+		}
+	}
+
+	// Close all broker subscriptions
 	h.subMu.Lock()
 	for projectID, cancel := range h.subscriptions {
-		log.Printf("Closing Redis subscription for project %s", projectID)
+		log.Printf("Closing broker subscription for project %s", projectID)
 		cancel()
 	}
 	h.subscriptions = make(map[uuid.UUID]context.CancelFunc)
 	h.subMu.Unlock()
 
-	// Close all client connections
-	for _, clients := range h.projects {
-		for client := range clients {
-			h.safeCloseChannel(client.Send)
-			if client.Conn != nil {
-				client.Conn.Close()
-			}
-		}
+	// Close all client connections and observers
+	for _, shard := range shards {
+		shard.closeClients()
 	}
+	h.closeObservers()
+	h.closeWrites()
+	h.closeEventSinks()
+}
 
-	// Clear all projects
-	h.projects = make(map[uuid.UUID]map[*Client]bool)
+// projectTopic returns the broker topic carrying a project's messages
+func projectTopic(projectID uuid.UUID) string {
+	return fmt.Sprintf("project:%s", projectID.String())
 }
 
-// subscribeToRedis subscribes to a Redis channel for cross-region messages
-func (h *Hub) subscribeToRedis(projectID uuid.UUID) {
-	if h.redisClient == nil || !h.redisClient.IsEnabled() {
+// syncBrokerSubscription subscribes to a project's broker topic for
+// cross-node messages while the project has a shard or observers, and cancels
+// the subscription once it has neither. Callers must not hold h.mu: subMu
+// serializes the check with the broker call, so concurrent creations and
+// removals of a shard settle on its final state.
+func (h *Hub) syncBrokerSubscription(projectID uuid.UUID) {
+	if h.broker == nil {
 		return
 	}
 
 	h.subMu.Lock()
-	// Check if already subscribed
-	if _, exists := h.subscriptions[projectID]; exists {
-		h.subMu.Unlock()
+	defer h.subMu.Unlock()
+
+	h.mu.RLock()
+	wanted := !h.isShuttingDown.Load() && (h.shards[projectID] != nil || h.hasObservers(projectID))
+	h.mu.RUnlock()
+
+	cancel, exists := h.subscriptions[projectID]
+	if !wanted {
+		if exists {
+			log.Printf("Unsubscribing from broker for project %s", projectID)
+			cancel()
+			delete(h.subscriptions, projectID)
+		}
 		return
 	}
-
-	channel := fmt.Sprintf("project:%s", projectID.String())
-	pubsub := h.redisClient.Subscribe(channel)
-	if pubsub == nil {
-		h.subMu.Unlock()
+	if exists {
 		return
 	}
 
 	// Create cancellable context for this subscription
 	ctx, cancel := context.WithCancel(context.Background())
-	h.subscriptions[projectID] = cancel
-	h.subMu.Unlock()
-
-	log.Printf("Started Redis subscription for project %s on channel %s", projectID, channel)
-
-	// Start listening in a goroutine
-	go func() {
-		defer pubsub.Close()
-		ch := pubsub.Channel()
-
-		for {
-			select {
-			case <-ctx.Done():
-				log.Printf("Redis subscription cancelled for project %s", projectID)
-				return
-
-			case msg, ok := <-ch:
-				if !ok {
-					log.Printf("Redis channel closed for project %s", projectID)
-					return
-				}
-
-				// Broadcast message to local clients only (no re-publishing to Redis)
-				h.broadcastFromRedis(projectID, []byte(msg.Payload))
-			}
-		}
-	}()
-}
-
-// unsubscribeFromRedis unsubscribes from a Redis channel
-func (h *Hub) unsubscribeFromRedis(projectID uuid.UUID) {
-	h.subMu.Lock()
-	defer h.subMu.Unlock()
-
-	if cancel, exists := h.subscriptions[projectID]; exists {
-		log.Printf("Unsubscribing from Redis for project %s", projectID)
+	topic := projectTopic(projectID)
+	err := h.broker.Subscribe(ctx, topic, func(data []byte) {
+		// Broadcast message to local clients only (no re-publishing)
+		h.broadcastFromBroker(projectID, data)
+	})
+	if err != nil {
 		cancel()
-		delete(h.subscriptions, projectID)
+		log.Printf("Failed to subscribe to %s topic %s: %v", h.broker.Name(), topic, err)
+		return
 	}
+
+	h.subscriptions[projectID] = cancel
+	log.Printf("Started %s subscription for project %s on topic %s", h.broker.Name(), projectID, topic)
 }
 
-// broadcastFromRedis broadcasts a message received from Redis to local clients
-func (h *Hub) broadcastFromRedis(projectID uuid.UUID, messageBytes []byte) {
+// broadcastFromBroker broadcasts a message received from the broker to local clients
+func (h *Hub) broadcastFromBroker(projectID uuid.UUID, messageBytes []byte) {
 	// Check if shutting down
 	if h.isShuttingDown.Load() {
 		return
 	}
 
-	h.mu.RLock()
-	clients, exists := h.projects[projectID]
-	h.mu.RUnlock()
-
-	if !exists {
-		return
-	}
+	shard := h.getShard(projectID)
 
 	// Parse the message to check if it's from this hub
 	var message WebSocketMessage
 	if err := json.Unmarshal(messageBytes, &message); err != nil {
-		log.Printf("Error unmarshaling Redis message: %v", err)
+		log.Printf("Error unmarshaling broker message: %v", err)
 		return
 	}
 
-	// Broadcast to all local clients, except the original sender
-	for client := range clients {
-		if client.UserID == message.UserID {
-			continue
+	// Durable brokers may replay messages that have had their moment
+	if message.Type.IsEphemeral() && expired(&message, time.Now()) {
+		return
+	}
+
+	switch message.Type {
+	case MessageTypeViewportUpdate:
+		// Only followers get viewports
+		if shard != nil {
+			shard.deliverViewport(messageBytes, message.UserID)
 		}
-		select {
-		case client.Send <- messageBytes:
-		default:
-			// Client's send channel is full, skip
-			log.Printf("Skipping Redis message for client %s (channel full)", client.UserID)
+		return
+	case MessageTypeUserLeft:
+		if shard != nil {
+			shard.forgetViewport(message.UserID)
+		}
+	case MessageTypeAccessRevoked:
+		// Only for the user losing access, wherever they are connected
+		if shard != nil {
+			shard.disconnectUser(message.UserID, messageBytes)
+		}
+		h.closeUserObservers(projectID, message.UserID)
+		return
+	case MessageTypePermissionsUpdated:
+		var permissions PermissionsUpdatedPayload
+		if err := message.UnmarshalData(&permissions); err != nil {
+			log.Printf("Error unmarshaling permissions from broker: %v", err)
+			return
+		}
+		if shard != nil {
+			shard.updatePermissions(message.UserID, permissions.CanEdit, messageBytes)
+		}
+		return
+	}
+
+	h.notifyObservers(projectID, &message)
+	if shard == nil {
+		return
+	}
+
+	// Broadcast to all local clients, except the original sender
+	shard.deliverLocal(messageBytes, message.UserID)
+}
+
+// expired reports whether an ephemeral message is too old to deliver
+func expired(message *WebSocketMessage, now time.Time) bool {
+	maxAge := ephemeralMaxAge
+	if message.Type == MessageTypeCanvasPing {
+		var ping CanvasPingPayload
+		if err := message.UnmarshalData(&ping); err != nil {
+			return true
 		}
+		maxAge = time.Duration(ping.TTL) * time.Millisecond
 	}
+	return now.Sub(message.Timestamp) > maxAge
 }
 
 // safeCloseChannel safely closes a channel if it's not already closed
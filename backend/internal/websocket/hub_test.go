@@ -1,10 +1,16 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/Bug-Bugger/ezmodel/internal/broker"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -32,10 +38,7 @@ func TestHubSuite(t *testing.T) {
 func (suite *HubTestSuite) TestNewHub() {
 	hub := NewHub()
 
-	assert.NotNil(suite.T(), hub.projects)
-	assert.NotNil(suite.T(), hub.register)
-	assert.NotNil(suite.T(), hub.unregister)
-	assert.NotNil(suite.T(), hub.broadcast)
+	assert.NotNil(suite.T(), hub.shards)
 	assert.NotNil(suite.T(), hub.ticker)
 	assert.NotNil(suite.T(), hub.done)
 }
@@ -52,10 +55,7 @@ func (suite *HubTestSuite) TestRegisterClient() {
 	defer suite.hub.Shutdown()
 
 	// Register client
-	suite.hub.RegisterClient(client)
-
-	// Wait a moment for registration to process
-	time.Sleep(10 * time.Millisecond)
+	suite.register(client)
 
 	// Verify client count
 	count := suite.hub.GetActiveClients(projectID)
@@ -79,15 +79,14 @@ func (suite *HubTestSuite) TestUnregisterClient() {
 	defer suite.hub.Shutdown()
 
 	// Register client
-	suite.hub.RegisterClient(client)
-	time.Sleep(10 * time.Millisecond)
+	suite.register(client)
 
 	// Verify registration
 	assert.Equal(suite.T(), 1, suite.hub.GetActiveClients(projectID))
 
 	// Unregister client
 	suite.hub.UnregisterClient(client)
-	time.Sleep(10 * time.Millisecond)
+	suite.waitRemoved(client)
 
 	// Verify unregistration
 	assert.Equal(suite.T(), 0, suite.hub.GetActiveClients(projectID))
@@ -105,9 +104,7 @@ func (suite *HubTestSuite) TestBroadcastToProject() {
 	defer suite.hub.Shutdown()
 
 	// Register clients
-	suite.hub.RegisterClient(client1)
-	suite.hub.RegisterClient(client2)
-	time.Sleep(10 * time.Millisecond)
+	suite.register(client1, client2)
 
 	// Drain any presence messages that are sent to clients upon registration
 	select {
@@ -176,9 +173,7 @@ func (suite *HubTestSuite) TestProjectIsolation() {
 	defer suite.hub.Shutdown()
 
 	// Register clients
-	suite.hub.RegisterClient(client1)
-	suite.hub.RegisterClient(client2)
-	time.Sleep(10 * time.Millisecond)
+	suite.register(client1, client2)
 
 	// Drain any presence messages that are sent to clients upon registration
 	select {
@@ -239,10 +234,7 @@ func (suite *HubTestSuite) TestMultipleClientsInProject() {
 	defer suite.hub.Shutdown()
 
 	// Register all clients
-	suite.hub.RegisterClient(client1)
-	suite.hub.RegisterClient(client2)
-	suite.hub.RegisterClient(client3)
-	time.Sleep(10 * time.Millisecond)
+	suite.register(client1, client2, client3)
 
 	// Verify all clients are registered
 	assert.Equal(suite.T(), 3, suite.hub.GetActiveClients(projectID))
@@ -261,15 +253,13 @@ func (suite *HubTestSuite) TestHubShutdown() {
 	go suite.hub.Run()
 
 	// Register client
-	suite.hub.RegisterClient(client)
-	time.Sleep(10 * time.Millisecond)
+	suite.register(client)
 
 	// Verify client is registered
 	assert.Equal(suite.T(), 1, suite.hub.GetActiveClients(projectID))
 
-	// Shutdown hub
+	// Shutdown hub, which waits for the shards to stop
 	suite.hub.Shutdown()
-	time.Sleep(10 * time.Millisecond)
 
 	// Verify client count is 0 after shutdown
 	assert.Equal(suite.T(), 0, suite.hub.GetActiveClients(projectID))
@@ -280,20 +270,938 @@ func (suite *HubTestSuite) TestPingRemovesStaleClients() {
 	projectID := uuid.New()
 	client := suite.createTestClient(projectID, uuid.New())
 
-	// Directly register client without running the main loop
-	suite.hub.registerClient(client)
+	// Shards run on their own, so the main loop is not needed
+	suite.register(client)
 	assert.Equal(suite.T(), 1, suite.hub.GetActiveClients(projectID))
 
 	// Make client stale
+	shard := suite.hub.getShard(projectID)
+	shard.mu.Lock()
 	client.LastPing = time.Now().Add(-3 * time.Minute)
+	shard.mu.Unlock()
 
 	// Trigger ping processing
 	suite.hub.pingClients()
+	suite.waitRemoved(client)
+
+	// Client should be removed after being detected as stale, along with its shard
+	assert.Equal(suite.T(), 0, suite.hub.GetActiveClients(projectID))
+	assert.Eventually(suite.T(), func() bool { return suite.hub.getShard(projectID) == nil }, time.Second, 5*time.Millisecond)
+}
+
+// Test rapid cursor updates from one user are coalesced into a single broadcast
+func (suite *HubTestSuite) TestCursorUpdatesCoalesced() {
+	projectID := uuid.New()
+	sender := suite.createTestClient(projectID, uuid.New())
+	receiver := suite.createTestClient(projectID, uuid.New())
+
+	defer suite.hub.Shutdown()
+
+	suite.register(sender, receiver)
+
+	// Drain join and presence messages
+	for len(sender.Send) > 0 {
+		<-sender.Send
+	}
+	for len(receiver.Send) > 0 {
+		<-receiver.Send
+	}
+
+	// Send several cursor moves faster than the flush interval
+	for i := 0; i < 5; i++ {
+		payload := UserCursorPayload{
+			UserID:  sender.UserID,
+			CursorX: float64(i),
+			CursorY: float64(i),
+		}
+		message, err := NewWebSocketMessage(MessageTypeUserCursor, payload, sender.UserID, projectID)
+		assert.NoError(suite.T(), err)
+		suite.hub.BroadcastToProject(projectID, message, sender)
+	}
+
+	// Only the latest cursor position is delivered
+	var receivedMessage WebSocketMessage
+	assert.NoError(suite.T(), json.Unmarshal(suite.receive(receiver), &receivedMessage))
+	var cursor UserCursorPayload
+	assert.NoError(suite.T(), receivedMessage.UnmarshalData(&cursor))
+	assert.Equal(suite.T(), 4.0, cursor.CursorX)
+	assert.Len(suite.T(), receiver.Send, 0)
+
+	// The sender is excluded
+	assert.Len(suite.T(), sender.Send, 0)
+}
+
+// Test viewport updates are throttled and relayed only to the user's followers
+// Test drag positions are throttled to the latest position of each table and never numbered
+func (suite *HubTestSuite) TestTableDragsCoalesced() {
+	projectID := uuid.New()
+	sender := suite.createTestClient(projectID, uuid.New())
+	receiver := suite.createTestClient(projectID, uuid.New())
+	sequencer := &countingSequencer{sequences: make(map[uuid.UUID]int64)}
+	suite.hub.SetSequencer(sequencer)
+
+	defer suite.hub.Shutdown()
+
+	suite.register(sender, receiver)
+
+	// Drain join and presence messages
+	for _, client := range []*Client{sender, receiver} {
+		for len(client.Send) > 0 {
+			<-client.Send
+		}
+	}
+
+	tableID := uuid.New()
+	for i := 0; i < 5; i++ {
+		message, err := NewWebSocketMessage(MessageTypeTableUpdated, TablePayload{TableID: tableID, X: float64(i)}, sender.UserID, projectID)
+		assert.NoError(suite.T(), err)
+		suite.hub.RelayTableDrag(sender, tableID, message)
+	}
+
+	var receivedMessage WebSocketMessage
+	assert.NoError(suite.T(), json.Unmarshal(suite.receive(receiver), &receivedMessage))
+	var position TablePayload
+	assert.NoError(suite.T(), receivedMessage.UnmarshalData(&position))
+	assert.Equal(suite.T(), 4.0, position.X)
+	assert.Zero(suite.T(), receivedMessage.Sequence)
+	assert.Len(suite.T(), receiver.Send, 0)
+	assert.Len(suite.T(), sender.Send, 0)
+}
+
+func (suite *HubTestSuite) TestViewportUpdatesReachFollowersOnly() {
+	projectID := uuid.New()
+	presenter := suite.createTestClient(projectID, uuid.New())
+	follower := suite.createTestClient(projectID, uuid.New())
+	bystander := suite.createTestClient(projectID, uuid.New())
+
+	defer suite.hub.Shutdown()
+
+	suite.register(presenter, follower, bystander)
+	suite.hub.FollowUser(follower, presenter.UserID)
+
+	// Drain join and presence messages
+	for _, client := range []*Client{presenter, follower, bystander} {
+		for len(client.Send) > 0 {
+			<-client.Send
+		}
+	}
+
+	for i := 1; i <= 5; i++ {
+		message, err := NewWebSocketMessage(MessageTypeViewportUpdate, ViewportPayload{UserID: presenter.UserID, X: float64(i), Zoom: 1}, presenter.UserID, projectID)
+		assert.NoError(suite.T(), err)
+		suite.hub.BroadcastToProject(projectID, message, presenter)
+	}
+
+	// The follower gets the latest viewport only
+	var receivedMessage WebSocketMessage
+	assert.NoError(suite.T(), json.Unmarshal(suite.receive(follower), &receivedMessage))
+	var viewport ViewportPayload
+	assert.NoError(suite.T(), receivedMessage.UnmarshalData(&viewport))
+	assert.Equal(suite.T(), 5.0, viewport.X)
+	assert.Len(suite.T(), follower.Send, 0)
+
+	assert.Len(suite.T(), bystander.Send, 0)
+	assert.Len(suite.T(), presenter.Send, 0)
+
+	// A new follower starts from the last viewport
+	suite.hub.FollowUser(bystander, presenter.UserID)
+	assert.Len(suite.T(), bystander.Send, 1)
+
+	// Followers that stop following get no more updates
+	suite.hub.FollowUser(follower, uuid.Nil)
+	message, err := NewWebSocketMessage(MessageTypeViewportUpdate, ViewportPayload{UserID: presenter.UserID, X: 6, Zoom: 1}, presenter.UserID, projectID)
+	assert.NoError(suite.T(), err)
+	suite.hub.BroadcastToProject(projectID, message, presenter)
+	suite.receive(bystander) // The last viewport
+	assert.NoError(suite.T(), json.Unmarshal(suite.receive(bystander), &receivedMessage))
+	assert.NoError(suite.T(), receivedMessage.UnmarshalData(&viewport))
+	assert.Equal(suite.T(), 6.0, viewport.X)
+	assert.Len(suite.T(), follower.Send, 0)
+}
+
+// Test slow clients get coalesced cursor updates less often instead of none
+func (suite *HubTestSuite) TestCursorUpdatesDownsampledForSlowClients() {
+	projectID := uuid.New()
+	sender := suite.createTestClient(projectID, uuid.New())
+	fast := suite.createTestClient(projectID, uuid.New())
+	slow := suite.createTestClient(projectID, uuid.New())
+
+	defer suite.hub.Shutdown()
+
+	suite.register(sender, fast, slow)
+	suite.hub.RecordPong(slow, time.Now().Add(-2*slowRTT))
+
+	// Drain join and presence messages
+	for _, client := range []*Client{fast, slow} {
+		for len(client.Send) > 0 {
+			<-client.Send
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		message, err := NewWebSocketMessage(MessageTypeUserCursor, UserCursorPayload{CursorX: float64(i)}, sender.UserID, projectID)
+		assert.NoError(suite.T(), err)
+		suite.hub.BroadcastToProject(projectID, message, sender)
+
+		// The fast client gets every update, each in its own flush
+		var receivedMessage WebSocketMessage
+		assert.NoError(suite.T(), json.Unmarshal(suite.receive(fast), &receivedMessage))
+		var cursor UserCursorPayload
+		assert.NoError(suite.T(), receivedMessage.UnmarshalData(&cursor))
+		assert.Equal(suite.T(), float64(i), cursor.CursorX)
+	}
+
+	// The slow client gets fewer updates, ending with the latest position
+	received := 0
+	for cursor := (UserCursorPayload{}); cursor.CursorX != 2; {
+		var receivedMessage WebSocketMessage
+		assert.NoError(suite.T(), json.Unmarshal(suite.receive(slow), &receivedMessage))
+		assert.NoError(suite.T(), receivedMessage.UnmarshalData(&cursor))
+		received++
+	}
+	assert.Less(suite.T(), received, 3)
+
+	// Presence shows how each connection keeps up
+	for _, user := range suite.hub.GetActiveUsers(projectID) {
+		suite.Require().NotNil(user.Connection)
+		assert.Equal(suite.T(), user.UserID == slow.UserID, user.Connection.Slow)
+		if user.UserID == slow.UserID {
+			assert.GreaterOrEqual(suite.T(), user.Connection.RTTMs, (2 * slowRTT).Milliseconds())
+		}
+	}
+}
+
+// Test hub stats track connections, deliveries and dropped sends
+func (suite *HubTestSuite) TestStats() {
+	projectID := uuid.New()
+	sender := suite.createTestClient(projectID, uuid.New())
+	receiver := suite.createTestClient(projectID, uuid.New())
+	full := suite.createTestClient(projectID, uuid.New())
+	full.Send = make(chan []byte) // Unbuffered so every send is dropped
+
+	defer suite.hub.Shutdown()
+
+	suite.register(sender, receiver)
+	suite.hub.RegisterClient(full)
+
+	// The presence sent to the full client is its first dropped send
+	assert.Eventually(suite.T(), func() bool { return suite.hub.Stats().DroppedSends == 1 }, time.Second, 5*time.Millisecond)
+
+	// Drain join and presence messages
+	for _, client := range []*Client{sender, receiver} {
+		for len(client.Send) > 0 {
+			<-client.Send
+		}
+	}
+
+	before := suite.hub.Stats()
+	assert.Equal(suite.T(), 3, before.TotalConnections)
+	assert.Equal(suite.T(), 3, before.ConnectionsByProject[projectID])
+
+	message, err := NewWebSocketMessage(MessageTypeTableCreated, TablePayload{Name: "users"}, sender.UserID, projectID)
+	assert.NoError(suite.T(), err)
+	suite.hub.BroadcastToProject(projectID, message, sender)
+	suite.receive(receiver)
+	assert.Eventually(suite.T(), func() bool { return suite.hub.Stats().DroppedSends == before.DroppedSends+1 }, time.Second, 5*time.Millisecond)
+
+	after := suite.hub.Stats()
+	assert.Equal(suite.T(), before.MessagesBroadcast+1, after.MessagesBroadcast)
+	assert.Equal(suite.T(), before.MessagesDelivered+1, after.MessagesDelivered)
+	assert.Equal(suite.T(), before.DroppedSends+1, after.DroppedSends)
+}
+
+// Test drain sends a shutdown notice, refuses new clients and shuts the hub down
+func (suite *HubTestSuite) TestDrain() {
+	projectID := uuid.New()
+	client := suite.createTestClient(projectID, uuid.New())
+
+	suite.register(client)
+
+	// Consume messages like a write pump so the send queue empties
+	notices := make(chan MessageType, 16)
+	go func() {
+		for messageBytes := range client.Send {
+			var message WebSocketMessage
+			if json.Unmarshal(messageBytes, &message) == nil {
+				notices <- message.Type
+			}
+		}
+		close(notices)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	suite.hub.Drain(ctx)
+
+	var received []MessageType
+	for messageType := range notices {
+		received = append(received, messageType)
+	}
+	assert.Contains(suite.T(), received, MessageTypeServerShutdown)
+	assert.True(suite.T(), suite.hub.isShuttingDown.Load())
+	assert.False(suite.T(), suite.hub.Accepting())
+
+	// New clients are refused
+	late := suite.createTestClient(projectID, uuid.New())
+	suite.hub.RegisterClient(late)
+	assert.Equal(suite.T(), 0, suite.hub.GetActiveClients(projectID))
+}
+
+// recordingSessionListener records session lifecycle events
+type recordingSessionListener struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *recordingSessionListener) UserConnected(projectID, userID uuid.UUID, userColor string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, "connected:"+userID.String())
+}
+
+func (l *recordingSessionListener) UserDisconnected(projectID, userID uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, "disconnected:"+userID.String())
+}
+
+func (l *recordingSessionListener) CursorMoved(projectID, userID uuid.UUID, cursorX, cursorY float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, fmt.Sprintf("cursor:%s:%g,%g", userID, cursorX, cursorY))
+}
+
+func (l *recordingSessionListener) StatusChanged(projectID, userID uuid.UUID, status string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, "status:"+userID.String()+":"+status)
+}
+
+func (l *recordingSessionListener) Events() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.events...)
+}
+
+// Test session listener fires on a user's first connection and last disconnection only
+func (suite *HubTestSuite) TestSessionListener() {
+	listener := &recordingSessionListener{}
+	suite.hub.SetSessionListener(listener)
+	defer suite.hub.Shutdown()
+
+	projectID := uuid.New()
+	userID := uuid.New()
+	firstTab := suite.createTestClient(projectID, userID)
+	secondTab := suite.createTestClient(projectID, userID)
+
+	suite.register(firstTab, secondTab)
+	assert.Eventually(suite.T(), func() bool { return len(listener.Events()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(suite.T(), []string{"connected:" + userID.String()}, listener.Events())
+
+	suite.hub.UnregisterClient(firstTab)
+	suite.waitRemoved(firstTab)
+
+	suite.hub.UnregisterClient(secondTab)
+	suite.waitRemoved(secondTab)
+	assert.Eventually(suite.T(), func() bool { return len(listener.Events()) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(suite.T(), []string{
+		"connected:" + userID.String(),
+		"disconnected:" + userID.String(),
+	}, listener.Events())
+}
+
+// blockingSessionListener holds the session event queue up until released
+type blockingSessionListener struct {
+	recordingSessionListener
+	blocked chan struct{}
+	release chan struct{}
+}
+
+func (l *blockingSessionListener) UserConnected(projectID, userID uuid.UUID, userColor string) {
+	close(l.blocked)
+	<-l.release
+	l.recordingSessionListener.UserConnected(projectID, userID, userColor)
+}
+
+// Test cursor and status updates are dropped and counted while the session listener is behind
+func (suite *HubTestSuite) TestSessionEventsDroppedWhenListenerBehind() {
+	listener := &blockingSessionListener{blocked: make(chan struct{}), release: make(chan struct{})}
+	suite.hub.SetSessionListener(listener)
+	defer suite.hub.Shutdown()
+
+	projectID := uuid.New()
+	userID := uuid.New()
+	suite.hub.notifySession(sessionEvent{kind: sessionConnected, projectID: projectID, userID: userID})
+	<-listener.blocked
+
+	// The queue fills up, then updates are dropped without blocking
+	for range sessionEventQueueSize + 3 {
+		suite.hub.notifySession(sessionEvent{kind: sessionCursorMoved, projectID: projectID, userID: userID})
+	}
+	suite.hub.notifySession(sessionEvent{kind: sessionStatusChanged, projectID: projectID, userID: userID, status: PresenceIdle})
+	assert.Equal(suite.T(), uint64(4), suite.hub.Stats().DroppedSessionEvents)
+
+	close(listener.release)
+	assert.Eventually(suite.T(), func() bool { return len(listener.Events()) == sessionEventQueueSize+1 }, time.Second, 5*time.Millisecond)
+}
+
+// Test the latest cursor shows in presence and is saved before the user disconnects
+func (suite *HubTestSuite) TestCursorPersistedAndInPresence() {
+	listener := &recordingSessionListener{}
+	suite.hub.SetSessionListener(listener)
+	defer suite.hub.Shutdown()
+
+	projectID := uuid.New()
+	client := suite.createTestClient(projectID, uuid.New())
+	suite.register(client)
+
+	for i := 1; i <= 3; i++ {
+		message, err := NewWebSocketMessage(MessageTypeUserCursor, UserCursorPayload{CursorX: float64(i), CursorY: 10}, client.UserID, projectID)
+		assert.NoError(suite.T(), err)
+		suite.hub.BroadcastToProject(projectID, message, nil)
+	}
+	assert.Eventually(suite.T(), func() bool {
+		users := suite.hub.GetActiveUsers(projectID)
+		return len(users) == 1 && users[0].CursorX != nil && *users[0].CursorX == 3
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(suite.T(), 10.0, *suite.hub.GetActiveUsers(projectID)[0].CursorY)
+
+	// Leaving saves the cursor without waiting for the next persist
+	suite.hub.UnregisterClient(client)
+	suite.waitRemoved(client)
+	assert.Eventually(suite.T(), func() bool { return len(listener.Events()) == 3 }, time.Second, 5*time.Millisecond)
+	assert.Equal(suite.T(), []string{
+		"connected:" + client.UserID.String(),
+		"cursor:" + client.UserID.String() + ":3,10",
+		"disconnected:" + client.UserID.String(),
+	}, listener.Events())
+}
+
+// Test presence status changes are broadcast and handed to the session listener
+func (suite *HubTestSuite) TestPresenceStatus() {
+	listener := &recordingSessionListener{}
+	suite.hub.SetSessionListener(listener)
+	suite.hub.SetIdleTimeout(50 * time.Millisecond)
+	defer suite.hub.Shutdown()
+
+	projectID := uuid.New()
+	user := suite.createTestClient(projectID, uuid.New())
+	teammate := suite.createTestClient(projectID, uuid.New())
+	suite.register(user, teammate)
+
+	// statuses returns the statuses of the user the teammate received
+	statuses := func() []string {
+		var received []string
+		for len(teammate.Send) > 0 {
+			var message WebSocketMessage
+			assert.NoError(suite.T(), json.Unmarshal(<-teammate.Send, &message))
+			var payload PresenceStatusPayload
+			if message.Type == MessageTypePresenceStatus && message.UnmarshalData(&payload) == nil && payload.UserID == user.UserID {
+				received = append(received, payload.Status)
+			}
+		}
+		return received
+	}
+	statuses()
+
+	suite.hub.SetClientAway(user, true)
+	suite.hub.SetClientAway(user, false)
+	assert.Equal(suite.T(), []string{PresenceAway, PresenceActive}, statuses())
+
+	// Doing nothing makes the user idle, doing something active again
+	for _, client := range []*Client{user, teammate} {
+		client.lastActivity.Store(time.Now().Add(-time.Minute).UnixNano())
+	}
+	suite.hub.updateStatuses()
+	assert.Equal(suite.T(), PresenceIdle, suite.hub.GetActiveUsers(projectID)[0].Status)
+	suite.hub.TouchClient(user)
+	assert.Equal(suite.T(), []string{PresenceIdle, PresenceActive}, statuses())
+
+	assert.Eventually(suite.T(), func() bool {
+		events := strings.Join(listener.Events(), " ")
+		return strings.Contains(events, "status:"+user.UserID.String()+":"+PresenceAway) &&
+			strings.Contains(events, "status:"+user.UserID.String()+":"+PresenceIdle)
+	}, time.Second, 5*time.Millisecond)
+}
+
+// fakeBroker records published messages and lets tests deliver remote ones
+type fakeBroker struct {
+	mu        sync.Mutex
+	published map[string][][]byte
+	handlers  map[string]broker.Handler
+}
+
+func newFakeBroker() *fakeBroker {
+	return &fakeBroker{
+		published: make(map[string][][]byte),
+		handlers:  make(map[string]broker.Handler),
+	}
+}
+
+func (b *fakeBroker) Name() string { return "fake" }
+
+func (b *fakeBroker) Publish(topic string, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published[topic] = append(b.published[topic], data)
+	return nil
+}
+
+func (b *fakeBroker) Subscribe(ctx context.Context, topic string, handler broker.Handler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[topic] = handler
+	return nil
+}
+
+func (b *fakeBroker) Close() error { return nil }
+
+func (b *fakeBroker) Published(topic string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.published[topic])
+}
+
+func (b *fakeBroker) Handler(topic string) broker.Handler {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.handlers[topic]
+}
 
-	// Client should be removed after being detected as stale
+// Test viewports from other nodes reach local followers only
+func (suite *HubTestSuite) TestViewportFromBrokerReachesFollowersOnly() {
+	fake := newFakeBroker()
+	suite.hub.SetBroker(fake)
+	defer suite.hub.Shutdown()
+
+	projectID := uuid.New()
+	remoteUserID := uuid.New()
+	follower := suite.createTestClient(projectID, uuid.New())
+	bystander := suite.createTestClient(projectID, uuid.New())
+
+	suite.register(follower, bystander)
+	suite.hub.FollowUser(follower, remoteUserID)
+
+	handler := fake.Handler(projectTopic(projectID))
+	suite.Require().NotNil(handler)
+
+	// Drain join and presence messages
+	for _, client := range []*Client{follower, bystander} {
+		for len(client.Send) > 0 {
+			<-client.Send
+		}
+	}
+
+	remote, err := NewWebSocketMessage(MessageTypeViewportUpdate, ViewportPayload{UserID: remoteUserID, Zoom: 2}, remoteUserID, projectID)
+	assert.NoError(suite.T(), err)
+	remoteBytes, err := json.Marshal(remote)
+	assert.NoError(suite.T(), err)
+	handler(remoteBytes)
+
+	suite.Require().Len(follower.Send, 1)
+	assert.Equal(suite.T(), remoteBytes, <-follower.Send)
+	assert.Len(suite.T(), bystander.Send, 0)
+}
+
+// Test a disconnected user is told why and unregistered on this node and others
+func (suite *HubTestSuite) TestDisconnectUser() {
+	fake := newFakeBroker()
+	suite.hub.SetBroker(fake)
+	defer suite.hub.Shutdown()
+
+	projectID := uuid.New()
+	kicked := suite.createTestClient(projectID, uuid.New())
+	other := suite.createTestClient(projectID, uuid.New())
+
+	suite.register(kicked, other)
+
+	// Drain join and presence messages
+	for _, client := range []*Client{kicked, other} {
+		for len(client.Send) > 0 {
+			<-client.Send
+		}
+	}
+
+	assert.NoError(suite.T(), suite.hub.DisconnectUser(projectID, kicked.UserID, "Removed"))
+
+	var revoked WebSocketMessage
+	assert.NoError(suite.T(), json.Unmarshal(suite.receive(kicked), &revoked))
+	assert.Equal(suite.T(), MessageTypeAccessRevoked, revoked.Type)
+	suite.waitRemoved(kicked)
+	assert.Equal(suite.T(), 1, suite.hub.GetActiveClients(projectID))
+	assert.Eventually(suite.T(), func() bool { return fake.Published(projectTopic(projectID)) >= 1 }, time.Second, 5*time.Millisecond)
+
+	// The same message from another node disconnects the user here
+	handler := fake.Handler(projectTopic(projectID))
+	suite.Require().NotNil(handler)
+	remote, err := NewWebSocketMessage(MessageTypeAccessRevoked, AccessRevokedPayload{UserID: other.UserID, Message: "Removed"}, other.UserID, projectID)
+	assert.NoError(suite.T(), err)
+	remoteBytes, err := json.Marshal(remote)
+	assert.NoError(suite.T(), err)
+	for len(other.Send) > 0 {
+		<-other.Send
+	}
+	handler(remoteBytes)
+
+	assert.Equal(suite.T(), remoteBytes, suite.receive(other))
+	suite.waitRemoved(other)
 	assert.Equal(suite.T(), 0, suite.hub.GetActiveClients(projectID))
 }
 
+// Test a user whose role changes is told and their clients follow the new permissions
+func (suite *HubTestSuite) TestUpdatePermissions() {
+	projectID := uuid.New()
+	viewer := suite.createTestClient(projectID, uuid.New())
+	other := suite.createTestClient(projectID, uuid.New())
+
+	suite.register(viewer, other)
+
+	// Drain join and presence messages
+	for _, client := range []*Client{viewer, other} {
+		for len(client.Send) > 0 {
+			<-client.Send
+		}
+	}
+
+	assert.NoError(suite.T(), suite.hub.UpdatePermissions(projectID, viewer.UserID, "viewer", false))
+
+	var message WebSocketMessage
+	suite.Require().Len(viewer.Send, 1)
+	assert.NoError(suite.T(), json.Unmarshal(<-viewer.Send, &message))
+	assert.Equal(suite.T(), MessageTypePermissionsUpdated, message.Type)
+	assert.True(suite.T(), viewer.ReadOnly())
+	assert.False(suite.T(), other.ReadOnly())
+	assert.Len(suite.T(), other.Send, 0)
+}
+
+// Test ephemeral messages from other nodes are dropped once their moment has passed
+func (suite *HubTestSuite) TestExpiredEphemeralFromBrokerDropped() {
+	fake := newFakeBroker()
+	suite.hub.SetBroker(fake)
+	defer suite.hub.Shutdown()
+
+	projectID := uuid.New()
+	client := suite.createTestClient(projectID, uuid.New())
+
+	suite.register(client)
+
+	handler := fake.Handler(projectTopic(projectID))
+	suite.Require().NotNil(handler)
+
+	// Drain join and presence messages
+	for len(client.Send) > 0 {
+		<-client.Send
+	}
+
+	send := func(messageType MessageType, payload any, age time.Duration) {
+		message, err := NewWebSocketMessage(messageType, payload, uuid.New(), projectID)
+		suite.Require().NoError(err)
+		message.Timestamp = time.Now().Add(-age)
+		messageBytes, err := json.Marshal(message)
+		suite.Require().NoError(err)
+		handler(messageBytes)
+	}
+
+	send(MessageTypeCanvasPing, CanvasPingPayload{TTL: 3000}, 5*time.Second)
+	send(MessageTypeUserCursor, UserCursorPayload{}, 5*time.Second)
+	assert.Len(suite.T(), client.Send, 0)
+
+	send(MessageTypeCanvasPing, CanvasPingPayload{TTL: 3000}, 2*time.Second)
+	assert.Len(suite.T(), client.Send, 1)
+}
+
+// Test broadcasts go out through the broker and broker messages reach local clients
+func (suite *HubTestSuite) TestBrokerSync() {
+	fake := newFakeBroker()
+	suite.hub.SetBroker(fake)
+	defer suite.hub.Shutdown()
+
+	projectID := uuid.New()
+	topic := projectTopic(projectID)
+	client := suite.createTestClient(projectID, uuid.New())
+
+	suite.register(client)
+
+	handler := fake.Handler(topic)
+	suite.Require().NotNil(handler)
+
+	// Drain join and presence messages
+	for len(client.Send) > 0 {
+		<-client.Send
+	}
+
+	// A local broadcast is published to the project topic
+	message, err := NewWebSocketMessage(MessageTypeTableCreated, TablePayload{Name: "users"}, client.UserID, projectID)
+	assert.NoError(suite.T(), err)
+	suite.hub.BroadcastToProject(projectID, message, client)
+	assert.Eventually(suite.T(), func() bool {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		for _, published := range fake.published[topic] {
+			if strings.Contains(string(published), `"users"`) {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 5*time.Millisecond)
+
+	// A message from another node is delivered to the local client
+	remote, err := NewWebSocketMessage(MessageTypeTableCreated, TablePayload{Name: "orders"}, uuid.New(), projectID)
+	assert.NoError(suite.T(), err)
+	remoteBytes, err := json.Marshal(remote)
+	assert.NoError(suite.T(), err)
+	handler(remoteBytes)
+
+	select {
+	case received := <-client.Send:
+		assert.Equal(suite.T(), remoteBytes, received)
+	case <-time.After(100 * time.Millisecond):
+		suite.T().Fatal("expected broker message to reach local client")
+	}
+}
+
+// Test schema changes are exported to event sinks in order, presence is not
+func (suite *HubTestSuite) TestEventSinks() {
+	sink := newFakeBroker()
+	suite.hub.SetEventSinks([]broker.Publisher{sink})
+
+	projectID := uuid.New()
+	topic := projectTopic(projectID)
+	for _, name := range []string{"users", "orders"} {
+		message, err := NewWebSocketMessage(MessageTypeTableCreated, TablePayload{Name: name}, uuid.New(), projectID)
+		suite.Require().NoError(err)
+		suite.hub.BroadcastToProject(projectID, message, nil)
+	}
+	cursor, err := NewWebSocketMessage(MessageTypeUserCursor, UserCursorPayload{CursorX: 1}, uuid.New(), projectID)
+	suite.Require().NoError(err)
+	suite.hub.BroadcastToProject(projectID, cursor, nil)
+
+	// Shutdown publishes what is still queued
+	suite.hub.Shutdown()
+
+	suite.Require().Equal(2, sink.Published(topic))
+	var first WebSocketMessage
+	suite.Require().NoError(json.Unmarshal(sink.published[topic][0], &first))
+	assert.Equal(suite.T(), MessageTypeTableCreated, first.Type)
+	assert.Contains(suite.T(), string(first.Data), "users")
+
+	// Broadcasts after shutdown are not exported
+	suite.hub.BroadcastToProject(projectID, cursor, nil)
+	assert.Equal(suite.T(), uint64(0), suite.hub.Stats().EventSinkFailures)
+}
+
+// Test observers receive broadcasts even when no WebSocket client is connected
+func (suite *HubTestSuite) TestObserve() {
+	defer suite.hub.Shutdown()
+
+	projectID := uuid.New()
+	messages, cancel := suite.hub.Observe(projectID, uuid.New())
+
+	message, err := NewWebSocketMessage(MessageTypeTableCreated, TablePayload{Name: "users"}, uuid.New(), projectID)
+	suite.Require().NoError(err)
+	suite.hub.BroadcastToProject(projectID, message, nil)
+
+	select {
+	case received := <-messages:
+		assert.Equal(suite.T(), message, received)
+	case <-time.After(100 * time.Millisecond):
+		suite.T().Fatal("expected broadcast to reach observer")
+	}
+
+	// Observers of other projects see nothing
+	other, cancelOther := suite.hub.Observe(uuid.New(), uuid.New())
+	defer cancelOther()
+	suite.hub.BroadcastToProject(projectID, message, nil)
+	assert.Empty(suite.T(), other)
+
+	cancel()
+	cancel()
+	<-messages // The second broadcast
+	_, open := <-messages
+	assert.False(suite.T(), open)
+}
+
+// Test a user's observers end when they lose access, on this node or another
+func (suite *HubTestSuite) TestObserve_AccessRevoked() {
+	fake := newFakeBroker()
+	suite.hub.SetBroker(fake)
+	defer suite.hub.Shutdown()
+
+	projectID := uuid.New()
+	kickedID, remoteID := uuid.New(), uuid.New()
+	kicked, cancelKicked := suite.hub.Observe(projectID, kickedID)
+	defer cancelKicked()
+	remote, cancelRemote := suite.hub.Observe(projectID, remoteID)
+	defer cancelRemote()
+	other, cancelOther := suite.hub.Observe(projectID, uuid.New())
+	defer cancelOther()
+
+	assert.NoError(suite.T(), suite.hub.DisconnectUser(projectID, kickedID, "Removed"))
+	_, open := <-kicked
+	assert.False(suite.T(), open)
+
+	// The same message from another node ends the user's observers here
+	handler := fake.Handler(projectTopic(projectID))
+	suite.Require().NotNil(handler)
+	message, err := NewWebSocketMessage(MessageTypeAccessRevoked, AccessRevokedPayload{UserID: remoteID, Message: "Removed"}, remoteID, projectID)
+	suite.Require().NoError(err)
+	messageBytes, err := json.Marshal(message)
+	suite.Require().NoError(err)
+	handler(messageBytes)
+	_, open = <-remote
+	assert.False(suite.T(), open)
+
+	assert.Equal(suite.T(), 1, suite.hub.Stats().Observers)
+	assert.Empty(suite.T(), other)
+}
+
+// countingSequencer numbers each project's changes from 1
+type countingSequencer struct {
+	mu        sync.Mutex
+	sequences map[uuid.UUID]int64
+}
+
+func (c *countingSequencer) NextSequence(projectID uuid.UUID) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sequences[projectID]++
+	return c.sequences[projectID], nil
+}
+
+// Test schema changes without a sequence are numbered, others are left alone
+func (suite *HubTestSuite) TestSequencer() {
+	defer suite.hub.Shutdown()
+	suite.hub.SetSequencer(&countingSequencer{sequences: make(map[uuid.UUID]int64)})
+
+	projectID := uuid.New()
+	messages, cancel := suite.hub.Observe(projectID, uuid.New())
+	defer cancel()
+
+	moved, err := NewWebSocketMessage(MessageTypeTableMoved, TablePayload{Name: "users"}, uuid.New(), projectID)
+	suite.Require().NoError(err)
+	cursor, err := NewWebSocketMessage(MessageTypeUserCursor, UserCursorPayload{}, uuid.New(), projectID)
+	suite.Require().NoError(err)
+	numbered, err := NewWebSocketMessage(MessageTypeTableDeleted, TablePayload{Name: "users"}, uuid.New(), projectID)
+	suite.Require().NoError(err)
+	numbered.Sequence = 42 // Numbered in the transaction of the change
+
+	for _, message := range []*WebSocketMessage{moved, cursor, numbered} {
+		suite.hub.BroadcastToProject(projectID, message, nil)
+	}
+
+	var sequences []int64
+	for range 3 {
+		select {
+		case received := <-messages:
+			sequences = append(sequences, received.Sequence)
+		case <-time.After(100 * time.Millisecond):
+			suite.T().Fatal("expected broadcast to reach observer")
+		}
+	}
+	assert.Equal(suite.T(), []int64{1, 0, 42}, sequences)
+}
+
+// Test observers receive local broadcasts and messages from other nodes, each
+// once. Brokers do not deliver a node's own messages back to it.
+func (suite *HubTestSuite) TestObserveFromBroker() {
+	fake := newFakeBroker()
+	suite.hub.SetBroker(fake)
+	defer suite.hub.Shutdown()
+
+	projectID := uuid.New()
+	topic := projectTopic(projectID)
+	messages, cancel := suite.hub.Observe(projectID, uuid.New())
+	defer cancel()
+
+	handler := fake.Handler(topic)
+	suite.Require().NotNil(handler, "observing subscribes to the project topic")
+
+	// A local client makes the node publish its broadcasts
+	client := suite.createTestClient(projectID, uuid.New())
+	suite.register(client)
+	for len(messages) > 0 {
+		<-messages
+	}
+
+	local, err := NewWebSocketMessage(MessageTypeTableCreated, TablePayload{Name: "users"}, client.UserID, projectID)
+	suite.Require().NoError(err)
+	suite.hub.BroadcastToProject(projectID, local, client)
+	select {
+	case received := <-messages:
+		assert.Equal(suite.T(), local.UserID, received.UserID)
+	case <-time.After(time.Second):
+		suite.T().Fatal("expected broadcast to reach observer")
+	}
+	assert.Eventually(suite.T(), func() bool { return fake.Published(topic) > 0 }, time.Second, 5*time.Millisecond)
+
+	remote, err := NewWebSocketMessage(MessageTypeTableCreated, TablePayload{Name: "orders"}, uuid.New(), projectID)
+	suite.Require().NoError(err)
+	remoteBytes, err := json.Marshal(remote)
+	suite.Require().NoError(err)
+	handler(remoteBytes)
+
+	select {
+	case received := <-messages:
+		assert.Equal(suite.T(), remote.UserID, received.UserID)
+	case <-time.After(100 * time.Millisecond):
+		suite.T().Fatal("expected broker message to reach observer")
+	}
+	assert.Empty(suite.T(), messages)
+}
+
+// Test shutdown closes observer channels
+func (suite *HubTestSuite) TestShutdownClosesObservers() {
+	messages, cancel := suite.hub.Observe(uuid.New(), uuid.New())
+	defer cancel()
+
+	suite.hub.Shutdown()
+
+	_, open := <-messages
+	assert.False(suite.T(), open)
+}
+
+// register registers clients one at a time, waiting for each to be sent the
+// presence that ends its registration
+func (suite *HubTestSuite) register(clients ...*Client) {
+	for _, client := range clients {
+		suite.hub.RegisterClient(client)
+		for {
+			var message WebSocketMessage
+			suite.Require().NoError(json.Unmarshal(suite.receive(client), &message))
+			if message.Type == MessageTypeUserPresence {
+				break
+			}
+		}
+	}
+}
+
+// receive waits for the next message sent to a client
+func (suite *HubTestSuite) receive(client *Client) []byte {
+	select {
+	case messageBytes := <-client.Send:
+		return messageBytes
+	case <-time.After(time.Second):
+		suite.T().Fatal("expected a message for the client")
+		return nil
+	}
+}
+
+// waitRemoved waits for the hub to close a client's send channel, which it
+// does when it removes the client
+func (suite *HubTestSuite) waitRemoved(client *Client) {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, open := <-client.Send:
+			if !open {
+				return
+			}
+		case <-timeout:
+			suite.T().Fatal("expected the client to be removed")
+		}
+	}
+}
+
 // Helper function to create a test client
 func (suite *HubTestSuite) createTestClient(projectID, userID uuid.UUID) *Client {
 	return &Client{
@@ -333,6 +1241,53 @@ func BenchmarkHubRegisterClient(b *testing.B) {
 	}
 }
 
+// Benchmark broadcasts spread across many projects, which shards handle in parallel
+func BenchmarkHubBroadcastManyProjects(b *testing.B) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Shutdown()
+
+	const projectCount = 200
+	projectIDs := make([]uuid.UUID, projectCount)
+	for i := range projectIDs {
+		projectIDs[i] = uuid.New()
+		for j := 0; j < 5; j++ {
+			hub.RegisterClient(&Client{
+				ID:        uuid.New(),
+				UserID:    uuid.New(),
+				ProjectID: projectIDs[i],
+				Username:  "testuser",
+				UserColor: "#FF6B6B",
+				Send:      make(chan []byte, 256),
+				Hub:       hub,
+				LastPing:  time.Now(),
+			})
+		}
+	}
+
+	// Let clients register
+	for _, projectID := range projectIDs {
+		for hub.GetActiveClients(projectID) < 5 {
+			runtime.Gosched()
+		}
+	}
+
+	messages := make([]*WebSocketMessage, projectCount)
+	for i, projectID := range projectIDs {
+		messages[i], _ = NewWebSocketMessage(MessageTypeTableMoved, TablePayload{Name: "users"}, uuid.New(), projectID)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			n := i % projectCount
+			hub.BroadcastToProject(projectIDs[n], messages[n], nil)
+			i++
+		}
+	})
+}
+
 func BenchmarkHubBroadcast(b *testing.B) {
 	hub := NewHub()
 	go hub.Run()
@@ -354,7 +1309,10 @@ func BenchmarkHubBroadcast(b *testing.B) {
 		hub.RegisterClient(client)
 	}
 
-	time.Sleep(100 * time.Millisecond) // Let clients register
+	// Let clients register
+	for hub.GetActiveClients(projectID) < 100 {
+		runtime.Gosched()
+	}
 
 	payload := UserCursorPayload{
 		UserID:    uuid.New(),
@@ -16,6 +16,8 @@ const (
 	MessageTypeUserLeft     MessageType = "user_left"
 	MessageTypeUserCursor   MessageType = "user_cursor"
 	MessageTypeUserPresence MessageType = "user_presence"
+	// A user became active, idle or away
+	MessageTypePresenceStatus MessageType = "presence_status"
 
 	// Schema modification events
 	MessageTypeTableCreated MessageType = "table_created"
@@ -25,20 +27,83 @@ const (
 	MessageTypeFieldCreated MessageType = "field_created"
 	MessageTypeFieldUpdated MessageType = "field_updated"
 	MessageTypeFieldDeleted MessageType = "field_deleted"
+	// Several fields added to one table at once, e.g. by an import
+	MessageTypeFieldsCreated MessageType = "fields_created"
+	// The order of a table's fields changed
+	MessageTypeFieldsReordered MessageType = "fields_reordered"
 
 	// Relationship events
 	MessageTypeRelationshipCreated MessageType = "relationship_create"
 	MessageTypeRelationshipUpdated MessageType = "relationship_update"
 	MessageTypeRelationshipDeleted MessageType = "relationship_delete"
 
+	// Region events
+	MessageTypeRegionCreated MessageType = "region_created"
+	MessageTypeRegionUpdated MessageType = "region_updated"
+	MessageTypeRegionMoved   MessageType = "region_moved"
+	MessageTypeRegionDeleted MessageType = "region_deleted"
+
 	// Canvas events
 	MessageTypeCanvasUpdated MessageType = "canvas_updated"
+	MessageTypeCanvasChunk   MessageType = "canvas_chunk"
+
+	// Doc events
+	MessageTypeDocUpdated MessageType = "doc_updated"
+
+	// Chat events
+	MessageTypeChatMessage MessageType = "chat_message"
+
+	// Follow mode events
+	MessageTypeViewportUpdate MessageType = "viewport_update"
+	MessageTypeFollowUser     MessageType = "follow_user"
+
+	// Pointer events
+	MessageTypeCanvasPing MessageType = "canvas_ping"
 
 	// System events
 	MessageTypeAuth  MessageType = "auth"
 	MessageTypeError MessageType = "error"
 	MessageTypePing  MessageType = "ping"
 	MessageTypePong  MessageType = "pong"
+
+	MessageTypeServerShutdown MessageType = "server_shutdown"
+	MessageTypeAccessRevoked  MessageType = "access_revoked"
+
+	MessageTypePermissionsUpdated MessageType = "permissions_updated"
+)
+
+// IsSchemaChange reports whether messages of the type record a change to a
+// project's schema, canvas or doc, rather than presence or connection state
+func (t MessageType) IsSchemaChange() bool {
+	switch t {
+	case MessageTypeTableCreated, MessageTypeTableUpdated, MessageTypeTableMoved, MessageTypeTableDeleted,
+		MessageTypeFieldCreated, MessageTypeFieldUpdated, MessageTypeFieldDeleted, MessageTypeFieldsCreated, MessageTypeFieldsReordered,
+		MessageTypeRelationshipCreated, MessageTypeRelationshipUpdated, MessageTypeRelationshipDeleted,
+		MessageTypeRegionCreated, MessageTypeRegionUpdated, MessageTypeRegionMoved, MessageTypeRegionDeleted,
+		MessageTypeCanvasUpdated, MessageTypeDocUpdated:
+		return true
+	}
+	return false
+}
+
+// IsEphemeral reports whether messages of the type only matter the moment
+// they are sent, like cursor moves and pings. They are relayed live but never
+// numbered, saved or exported, and the hub drops late copies of them, such as
+// those replayed by a durable broker.
+func (t MessageType) IsEphemeral() bool {
+	switch t {
+	case MessageTypeUserCursor, MessageTypeViewportUpdate, MessageTypeCanvasPing:
+		return true
+	}
+	return false
+}
+
+const (
+	// DefaultCanvasPingTTL is how long a ping shows when its sender sets no ttl
+	DefaultCanvasPingTTL = 3 * time.Second
+
+	// MaxCanvasPingTTL is the longest a ping shows
+	MaxCanvasPingTTL = 10 * time.Second
 )
 
 // WebSocketMessage represents a WebSocket message structure
@@ -48,6 +113,11 @@ type WebSocketMessage struct {
 	UserID    uuid.UUID       `json:"user_id"`
 	ProjectID uuid.UUID       `json:"project_id"`
 	Timestamp time.Time       `json:"timestamp"`
+
+	// Sequence numbers a project's schema changes in the order they were made,
+	// whether through the API or a WebSocket. It is set by the server and
+	// omitted from presence and connection messages.
+	Sequence int64 `json:"sequence,omitempty"`
 }
 
 // User presence payloads
@@ -55,6 +125,7 @@ type UserJoinedPayload struct {
 	UserID    uuid.UUID `json:"user_id"`
 	Username  string    `json:"username"`
 	UserColor string    `json:"user_color"`
+	AvatarURL string    `json:"avatar_url,omitempty"`
 }
 
 type UserLeftPayload struct {
@@ -69,6 +140,21 @@ type UserCursorPayload struct {
 	CursorY   float64   `json:"cursor_y"` // Global coordinates in SvelteFlow space
 }
 
+// Presence statuses of a user, from most to least present
+const (
+	PresenceActive = "active" // Did something in the project recently
+	PresenceIdle   = "idle"   // Did nothing for the hub's idle timeout
+	PresenceAway   = "away"   // Has the project open in a background tab only
+)
+
+// PresenceStatusPayload is a user's presence status. Clients send away when
+// their tab loses focus and active when it regains it; the server broadcasts
+// every change of a user's status, the most present of their connections.
+type PresenceStatusPayload struct {
+	UserID uuid.UUID `json:"user_id"` // Set by the server
+	Status string    `json:"status"`
+}
+
 type UserPresencePayload struct {
 	ActiveUsers []ActiveUser `json:"active_users"`
 }
@@ -77,17 +163,65 @@ type ActiveUser struct {
 	UserID    uuid.UUID `json:"user_id"`
 	Username  string    `json:"username"`
 	UserColor string    `json:"user_color"`
+	AvatarURL string    `json:"avatar_url,omitempty"`
 	CursorX   *float64  `json:"cursor_x,omitempty"` // Global coordinates in SvelteFlow space
 	CursorY   *float64  `json:"cursor_y,omitempty"` // Global coordinates in SvelteFlow space
+	Status    string    `json:"status"`             // active, idle or away
 	LastSeen  time.Time `json:"last_seen"`
+
+	// Connection of the user's most recently seen client on its node
+	Connection *ConnectionQuality `json:"connection,omitempty"`
+}
+
+// ConnectionQuality is how well a client's connection keeps up, as measured
+// by the server
+type ConnectionQuality struct {
+	RTTMs      int64 `json:"rtt_ms"`      // Round trip of the last ping, 0 until a pong comes back
+	QueueDepth int   `json:"queue_depth"` // Messages waiting to be sent to the client
+	Slow       bool  `json:"slow"`        // Gets cursor updates less often to catch up
+}
+
+// ViewportPayload is the part of the canvas a user sees. Clients send theirs
+// as they pan and zoom; the server relays it only to the user's followers.
+type ViewportPayload struct {
+	UserID uuid.UUID `json:"user_id"` // Set by the server
+	X      float64   `json:"x"`       // Pan offset in SvelteFlow space
+	Y      float64   `json:"y"`       // Pan offset in SvelteFlow space
+	Zoom   float64   `json:"zoom"`
+}
+
+// FollowUserPayload starts following a user's viewport, replacing any user
+// followed before. Without a user_id it stops following.
+type FollowUserPayload struct {
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+}
+
+// CanvasPingPayload points at a spot of the canvas, such as a table during a
+// review call, for TTL milliseconds. Pings are relayed, never saved.
+type CanvasPingPayload struct {
+	UserID    uuid.UUID  `json:"user_id"`            // Set by the server
+	Username  string     `json:"username"`           // Set by the server
+	UserColor string     `json:"user_color"`         // Set by the server
+	X         float64    `json:"x"`                  // Global coordinates in SvelteFlow space
+	Y         float64    `json:"y"`                  // Global coordinates in SvelteFlow space
+	TableID   *uuid.UUID `json:"table_id,omitempty"` // Table pointed at, if any
+	TTL       int        `json:"ttl"`                // Milliseconds, up to MaxCanvasPingTTL; DefaultCanvasPingTTL when 0
 }
 
 // Schema modification payloads
 type TablePayload struct {
-	TableID uuid.UUID `json:"table_id"`
-	Name    string    `json:"name"`
-	X       float64   `json:"x"`
-	Y       float64   `json:"y"`
+	TableID    uuid.UUID               `json:"table_id"`
+	Name       string                  `json:"name"`
+	X          float64                 `json:"x"`
+	Y          float64                 `json:"y"`
+	Appearance *TableAppearancePayload `json:"appearance,omitempty"` // Left out for moves and deletions
+}
+
+// TableAppearancePayload is how a table's node is drawn, so every client
+// shows the same colors and icons
+type TableAppearancePayload struct {
+	Color string `json:"color"` // Hex color of the header, or empty for the default
+	Icon  string `json:"icon"`  // Icon name, or empty for none
 }
 
 type FieldPayload struct {
@@ -101,15 +235,77 @@ type FieldPayload struct {
 	Position     int       `json:"position"`
 }
 
+// FieldsCreatedPayload lists fields added to a table together, in position order
+type FieldsCreatedPayload struct {
+	TableID uuid.UUID      `json:"table_id"`
+	Fields  []FieldPayload `json:"fields"`
+}
+
+// FieldsReorderedPayload is the order of a table's fields. Clients send the
+// positions they want with the last sequence they applied; the server answers
+// with the position of every field of the table, as saved.
+type FieldsReorderedPayload struct {
+	TableID      uuid.UUID              `json:"table_id"`
+	Fields       []FieldPositionPayload `json:"fields"`
+	BaseSequence int64                  `json:"base_sequence,omitempty"` // Sent by clients only
+}
+
+type FieldPositionPayload struct {
+	FieldID  uuid.UUID `json:"field_id"`
+	Position int       `json:"position"`
+}
+
 type RelationshipPayload struct {
-	RelationshipID uuid.UUID `json:"relationship_id"`
-	SourceTableID  uuid.UUID `json:"source_table_id"`
-	TargetTableID  uuid.UUID `json:"target_table_id"`
-	SourceFieldID  uuid.UUID `json:"source_field_id"`
-	TargetFieldID  uuid.UUID `json:"target_field_id"`
-	Type           string    `json:"relation_type"`
-	FromTableName  string    `json:"from_table"`
-	ToTableName    string    `json:"to_table"`
+	RelationshipID uuid.UUID         `json:"relationship_id"`
+	SourceTableID  uuid.UUID         `json:"source_table_id"`
+	TargetTableID  uuid.UUID         `json:"target_table_id"`
+	SourceFieldID  uuid.UUID         `json:"source_field_id"`
+	TargetFieldID  uuid.UUID         `json:"target_field_id"`
+	Type           string            `json:"relation_type"`
+	FromTableName  string            `json:"from_table"`
+	ToTableName    string            `json:"to_table"`
+	Route          *EdgeRoutePayload `json:"route,omitempty"` // Left out for deletions
+}
+
+// EdgeRoutePayload is how a relationship's edge is drawn, so every client
+// renders the same route
+type EdgeRoutePayload struct {
+	SourceAnchor  string  `json:"source_anchor"` // auto, top, right, bottom or left
+	TargetAnchor  string  `json:"target_anchor"` // auto, top, right, bottom or left
+	Waypoints     []Point `json:"waypoints"`
+	LabelPosition float64 `json:"label_position"` // Fraction of the edge's length from the source
+}
+
+// Point is a position on the canvas
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// RegionPayload describes a region of the canvas and the tables in it
+type RegionPayload struct {
+	RegionID uuid.UUID   `json:"region_id"`
+	Name     string      `json:"name"`
+	Color    string      `json:"color"`
+	X        float64     `json:"x"`
+	Y        float64     `json:"y"`
+	Width    float64     `json:"width"`
+	Height   float64     `json:"height"`
+	TableIDs []uuid.UUID `json:"table_ids"`
+}
+
+// RegionMovedPayload is where a moved region and each of its tables now are
+type RegionMovedPayload struct {
+	RegionID uuid.UUID              `json:"region_id"`
+	X        float64                `json:"x"`
+	Y        float64                `json:"y"`
+	Tables   []TablePositionPayload `json:"tables"`
+}
+
+type TablePositionPayload struct {
+	TableID uuid.UUID `json:"table_id"`
+	X       float64   `json:"x"`
+	Y       float64   `json:"y"`
 }
 
 // Canvas payload
@@ -117,14 +313,45 @@ type CanvasUpdatedPayload struct {
 	CanvasData string `json:"canvas_data"`
 }
 
+// CanvasChunkPayload carries one piece of a canvas update that is too large for a single message
+type CanvasChunkPayload struct {
+	ChunkID string `json:"chunk_id"` // Shared by all chunks of one update
+	Index   int    `json:"index"`    // Zero-based position of this chunk
+	Total   int    `json:"total"`    // Number of chunks in the update
+	Data    string `json:"data"`
+}
+
+// DocUpdatedPayload announces a new version of the project's doc, which
+// clients showing it fetch
+type DocUpdatedPayload struct {
+	Version int64 `json:"version"`
+}
+
+// ChatSendPayload is a chat message a client sends to the project's collaborators
+type ChatSendPayload struct {
+	Content string `json:"content"`
+}
+
+// ChatMessagePayload delivers a chat message once it is saved, to its sender too
+type ChatMessagePayload struct {
+	MessageID uuid.UUID `json:"message_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // System payloads
 type AuthPayload struct {
-	Token string `json:"token"`
+	Token    string `json:"token"`
+	Encoding string `json:"encoding,omitempty"` // Requested payload encoding: json (default) or msgpack
 }
 
 type AuthSuccessPayload struct {
-	Message string `json:"message"`
-	UserID  string `json:"user_id"`
+	Message  string `json:"message"`
+	UserID   string `json:"user_id"`
+	Encoding string `json:"encoding"` // Encoding used for all messages after auth success
+	Role     string `json:"role"`     // owner, editor or viewer
 }
 
 type ErrorPayload struct {
@@ -132,6 +359,26 @@ type ErrorPayload struct {
 	Code    string `json:"code,omitempty"`
 }
 
+// ServerShutdownPayload warns clients that the server is about to close their connection
+type ServerShutdownPayload struct {
+	Message string `json:"message"`
+}
+
+// AccessRevokedPayload tells a user their connection to the project is being
+// closed, because they were removed from it or kicked
+type AccessRevokedPayload struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Message string    `json:"message"`
+}
+
+// PermissionsUpdatedPayload tells a user their role in the project changed.
+// Changes sent by a user who cannot edit are rejected from then on.
+type PermissionsUpdatedPayload struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Role    string    `json:"role"`
+	CanEdit bool      `json:"can_edit"`
+}
+
 type PingPayload struct {
 	Timestamp time.Time `json:"timestamp"`
 }
@@ -140,6 +387,60 @@ type PongPayload struct {
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// ServerMessagePayloads maps each message type the server sends to the type of its data.
+// Generated clients build their message unions from it.
+var ServerMessagePayloads = map[MessageType]any{
+	MessageTypeUserJoined:          UserJoinedPayload{},
+	MessageTypeUserLeft:            UserLeftPayload{},
+	MessageTypeUserCursor:          UserCursorPayload{},
+	MessageTypeUserPresence:        UserPresencePayload{},
+	MessageTypePresenceStatus:      PresenceStatusPayload{},
+	MessageTypeTableCreated:        TablePayload{},
+	MessageTypeTableUpdated:        TablePayload{},
+	MessageTypeTableMoved:          TablePayload{},
+	MessageTypeTableDeleted:        TablePayload{},
+	MessageTypeFieldCreated:        FieldPayload{},
+	MessageTypeFieldUpdated:        FieldPayload{},
+	MessageTypeFieldDeleted:        FieldPayload{},
+	MessageTypeFieldsCreated:       FieldsCreatedPayload{},
+	MessageTypeFieldsReordered:     FieldsReorderedPayload{},
+	MessageTypeRelationshipCreated: RelationshipPayload{},
+	MessageTypeRelationshipUpdated: RelationshipPayload{},
+	MessageTypeRelationshipDeleted: RelationshipPayload{},
+	MessageTypeRegionCreated:       RegionPayload{},
+	MessageTypeRegionUpdated:       RegionPayload{},
+	MessageTypeRegionMoved:         RegionMovedPayload{},
+	MessageTypeRegionDeleted:       RegionPayload{},
+	MessageTypeCanvasUpdated:       CanvasUpdatedPayload{},
+	MessageTypeDocUpdated:          DocUpdatedPayload{},
+	MessageTypeChatMessage:         ChatMessagePayload{},
+	MessageTypeViewportUpdate:      ViewportPayload{},
+	MessageTypeCanvasPing:          CanvasPingPayload{},
+	MessageTypeAuth:                AuthSuccessPayload{},
+	MessageTypeError:               ErrorPayload{},
+	MessageTypePing:                PingPayload{},
+	MessageTypeServerShutdown:      ServerShutdownPayload{},
+	MessageTypeAccessRevoked:       AccessRevokedPayload{},
+	MessageTypePermissionsUpdated:  PermissionsUpdatedPayload{},
+}
+
+// ClientMessagePayloads maps each message type the server accepts from clients to the type of its data
+var ClientMessagePayloads = map[MessageType]any{
+	MessageTypeAuth:            AuthPayload{},
+	MessageTypeUserCursor:      UserCursorPayload{},
+	MessageTypePong:            PongPayload{},
+	MessageTypePresenceStatus:  PresenceStatusPayload{},
+	MessageTypeCanvasUpdated:   CanvasUpdatedPayload{},
+	MessageTypeCanvasChunk:     CanvasChunkPayload{},
+	MessageTypeTableUpdated:    TablePayload{},
+	MessageTypeTableMoved:      TablePayload{},
+	MessageTypeFieldsReordered: FieldsReorderedPayload{},
+	MessageTypeChatMessage:     ChatSendPayload{},
+	MessageTypeViewportUpdate:  ViewportPayload{},
+	MessageTypeFollowUser:      FollowUserPayload{},
+	MessageTypeCanvasPing:      CanvasPingPayload{},
+}
+
 // Helper functions to create messages
 func NewWebSocketMessage(msgType MessageType, data interface{}, userID, projectID uuid.UUID) (*WebSocketMessage, error) {
 	dataBytes, err := json.Marshal(data)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -381,6 +382,16 @@ func (suite *MessageTestSuite) TestInvalidMessageType() {
 	assert.Equal(suite.T(), MessageType("invalid_type"), message.Type)
 }
 
+// Test ephemeral message types are neither schema changes nor the other way round
+func (suite *MessageTestSuite) TestIsEphemeral() {
+	for _, messageType := range []MessageType{MessageTypeUserCursor, MessageTypeViewportUpdate, MessageTypeCanvasPing} {
+		assert.True(suite.T(), messageType.IsEphemeral(), messageType)
+		assert.False(suite.T(), messageType.IsSchemaChange(), messageType)
+	}
+	assert.False(suite.T(), MessageTypeTableMoved.IsEphemeral())
+	assert.False(suite.T(), MessageTypeChatMessage.IsEphemeral())
+}
+
 // Test UnmarshalData with Wrong Type
 func (suite *MessageTestSuite) TestUnmarshalDataWrongType() {
 	// Create message with invalid JSON data to force an unmarshaling error
@@ -443,3 +454,59 @@ func BenchmarkMessageUnmarshalData(b *testing.B) {
 		}
 	}
 }
+
+// Test encoding negotiation falls back to JSON
+func (suite *MessageTestSuite) TestNegotiateEncoding() {
+	assert.Equal(suite.T(), EncodingMessagePack, NegotiateEncoding("msgpack"))
+	assert.Equal(suite.T(), EncodingJSON, NegotiateEncoding("json"))
+	assert.Equal(suite.T(), EncodingJSON, NegotiateEncoding(""))
+	assert.Equal(suite.T(), EncodingJSON, NegotiateEncoding("protobuf"))
+}
+
+// Test MessagePack frames round trip back to the original JSON message
+func (suite *MessageTestSuite) TestMessagePackFrameRoundTrip() {
+	userID := uuid.New()
+	projectID := uuid.New()
+	payload := UserCursorPayload{
+		UserID:    userID,
+		Username:  "testuser",
+		UserColor: "#FF6B6B",
+		CursorX:   100.5,
+		CursorY:   200.5,
+	}
+
+	message, err := NewWebSocketMessage(MessageTypeUserCursor, payload, userID, projectID)
+	assert.NoError(suite.T(), err)
+	jsonBytes, err := json.Marshal(message)
+	assert.NoError(suite.T(), err)
+
+	frameType, frameBytes, err := EncodeFrame(EncodingMessagePack, jsonBytes)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), websocket.BinaryMessage, frameType)
+
+	decoded, err := DecodeFrame(frameType, frameBytes)
+	assert.NoError(suite.T(), err)
+
+	var decodedMessage WebSocketMessage
+	assert.NoError(suite.T(), json.Unmarshal(decoded, &decodedMessage))
+	assert.Equal(suite.T(), MessageTypeUserCursor, decodedMessage.Type)
+	assert.Equal(suite.T(), userID, decodedMessage.UserID)
+
+	var decodedPayload UserCursorPayload
+	assert.NoError(suite.T(), decodedMessage.UnmarshalData(&decodedPayload))
+	assert.Equal(suite.T(), payload, decodedPayload)
+}
+
+// Test JSON frames pass through unchanged
+func (suite *MessageTestSuite) TestJSONFramePassthrough() {
+	jsonBytes := []byte(`{"type":"ping"}`)
+
+	frameType, frameBytes, err := EncodeFrame(EncodingJSON, jsonBytes)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), websocket.TextMessage, frameType)
+	assert.Equal(suite.T(), jsonBytes, frameBytes)
+
+	decoded, err := DecodeFrame(websocket.TextMessage, jsonBytes)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), jsonBytes, decoded)
+}
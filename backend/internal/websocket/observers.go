@@ -0,0 +1,130 @@
+package websocket
+
+import (
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// observerBufferSize is how far an observer can fall behind before messages are dropped
+const observerBufferSize = 64
+
+// observer receives a project's broadcasts on behalf of a user without being a
+// WebSocket client, so it takes no part in presence
+type observer struct {
+	userID   uuid.UUID
+	messages chan *WebSocketMessage
+}
+
+// observerSet tracks observers by project
+type observerSet struct {
+	byProject map[uuid.UUID]map[*observer]struct{}
+}
+
+// Observe subscribes the user to every message broadcast in a project, whether
+// sent from this node or, through the broker, from another. GraphQL
+// subscriptions use it. Messages are dropped while the channel is full. The
+// channel is closed by the returned cancel function, when the user loses
+// access to the project or when the hub shuts down.
+func (h *Hub) Observe(projectID, userID uuid.UUID) (<-chan *WebSocketMessage, func()) {
+	o := &observer{userID: userID, messages: make(chan *WebSocketMessage, observerBufferSize)}
+
+	h.mu.Lock()
+	if h.isShuttingDown.Load() {
+		h.mu.Unlock()
+		close(o.messages)
+		return o.messages, func() {}
+	}
+	if h.observers.byProject[projectID] == nil {
+		h.observers.byProject[projectID] = make(map[*observer]struct{})
+	}
+	h.observers.byProject[projectID][o] = struct{}{}
+	h.mu.Unlock()
+	h.syncBrokerSubscription(projectID)
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			observers := h.observers.byProject[projectID]
+			if _, ok := observers[o]; !ok {
+				h.mu.Unlock()
+				return // Already closed by Shutdown
+			}
+			delete(observers, o)
+			close(o.messages)
+			if len(observers) == 0 {
+				delete(h.observers.byProject, projectID)
+			}
+			h.mu.Unlock()
+
+			h.syncBrokerSubscription(projectID)
+		})
+	}
+	return o.messages, cancel
+}
+
+// hasObservers reports whether a project has observers. Callers must hold h.mu.
+func (h *Hub) hasObservers(projectID uuid.UUID) bool {
+	return len(h.observers.byProject[projectID]) > 0
+}
+
+// observerCount returns the number of observers across projects
+func (h *Hub) observerCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := 0
+	for _, observers := range h.observers.byProject {
+		count += len(observers)
+	}
+	return count
+}
+
+// notifyObservers hands a message to the project's observers without blocking
+func (h *Hub) notifyObservers(projectID uuid.UUID, message *WebSocketMessage) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for o := range h.observers.byProject[projectID] {
+		select {
+		case o.messages <- message:
+		default:
+			h.droppedSends.Add(1)
+			log.Printf("Skipping observer of project %s (channel full)", projectID)
+		}
+	}
+}
+
+// closeUserObservers closes the channels of a user's observers of a project,
+// once the user has lost access to it
+func (h *Hub) closeUserObservers(projectID, userID uuid.UUID) {
+	h.mu.Lock()
+	observers := h.observers.byProject[projectID]
+	for o := range observers {
+		if o.userID == userID {
+			delete(observers, o)
+			close(o.messages)
+		}
+	}
+	if len(observers) == 0 {
+		delete(h.observers.byProject, projectID)
+	}
+	h.mu.Unlock()
+
+	h.syncBrokerSubscription(projectID)
+}
+
+// closeObservers closes every observer channel, on shutdown
+func (h *Hub) closeObservers() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, observers := range h.observers.byProject {
+		for o := range observers {
+			close(o.messages)
+		}
+	}
+	h.observers.byProject = make(map[uuid.UUID]map[*observer]struct{})
+}
@@ -0,0 +1,157 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// presenceTTL is how long a node's presence entry stays valid without a refresh.
+// Entries are refreshed on every heartbeat, so a crashed node drops out after this.
+const presenceTTL = 90 * time.Second
+
+// nodePresence is the presence entry a hub node stores for a project in Redis
+type nodePresence struct {
+	Users     []ActiveUser `json:"users"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// presenceKey returns the Redis hash holding per-node presence for a project
+func presenceKey(projectID uuid.UUID) string {
+	return fmt.Sprintf("presence:%s", projectID.String())
+}
+
+// presenceEnabled reports whether presence is shared with other nodes through Redis
+func (h *Hub) presenceEnabled() bool {
+	return h.redisClient != nil && h.redisClient.IsEnabled()
+}
+
+// markPresenceDirty schedules the local presence of a project to be written to Redis
+func (h *Hub) markPresenceDirty(projectID uuid.UUID) {
+	if !h.presenceEnabled() {
+		return
+	}
+
+	select {
+	case h.presenceUpdates <- projectID:
+	default:
+		// Queue is full; the next heartbeat refresh catches up
+	}
+}
+
+// runPresenceWriter writes queued presence updates to Redis. A single writer
+// always stores the latest local state, so updates cannot land out of order.
+func (h *Hub) runPresenceWriter() {
+	for {
+		select {
+		case projectID := <-h.presenceUpdates:
+			h.writePresence(projectID)
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// writePresence stores the users connected to this node for a project, or
+// removes this node's entry once none remain
+func (h *Hub) writePresence(projectID uuid.UUID) {
+	var users []ActiveUser
+	if shard := h.getShard(projectID); shard != nil {
+		users = shard.activeUsers()
+	}
+
+	key := presenceKey(projectID)
+	if len(users) == 0 {
+		if err := h.redisClient.HDel(key, h.nodeID); err != nil {
+			log.Printf("Failed to clear presence for project %s: %v", projectID, err)
+		}
+		return
+	}
+
+	data, err := json.Marshal(nodePresence{
+		Users:     users,
+		ExpiresAt: time.Now().Add(presenceTTL),
+	})
+	if err != nil {
+		log.Printf("Error marshaling presence for project %s: %v", projectID, err)
+		return
+	}
+
+	if err := h.redisClient.HSetWithExpiry(key, h.nodeID, data, presenceTTL); err != nil {
+		log.Printf("Failed to store presence for project %s: %v", projectID, err)
+	}
+}
+
+// refreshPresence rewrites the presence of every local project so entries do not expire
+func (h *Hub) refreshPresence() {
+	if !h.presenceEnabled() {
+		return
+	}
+
+	for _, shard := range h.snapshotShards() {
+		h.writePresence(shard.projectID)
+	}
+}
+
+// remoteActiveUsers returns the users other nodes report for a project and
+// prunes entries left behind by nodes that stopped refreshing them
+func (h *Hub) remoteActiveUsers(projectID uuid.UUID) []ActiveUser {
+	if !h.presenceEnabled() {
+		return nil
+	}
+
+	key := presenceKey(projectID)
+	entries, err := h.redisClient.HGetAll(key)
+	if err != nil {
+		log.Printf("Failed to load presence for project %s: %v", projectID, err)
+		return nil
+	}
+
+	now := time.Now()
+	var users []ActiveUser
+	var expired []string
+	for nodeID, raw := range entries {
+		if nodeID == h.nodeID {
+			continue
+		}
+
+		var entry nodePresence
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil || now.After(entry.ExpiresAt) {
+			expired = append(expired, nodeID)
+			continue
+		}
+		users = append(users, entry.Users...)
+	}
+
+	if len(expired) > 0 {
+		if err := h.redisClient.HDel(key, expired...); err != nil {
+			log.Printf("Failed to prune presence for project %s: %v", projectID, err)
+		}
+	}
+
+	return users
+}
+
+// mergeActiveUsers combines user lists into one entry per user, keeping the most recent LastSeen
+func mergeActiveUsers(lists ...[]ActiveUser) []ActiveUser {
+	var merged []ActiveUser
+	index := make(map[uuid.UUID]int)
+
+	for _, list := range lists {
+		for _, user := range list {
+			i, seen := index[user.UserID]
+			if !seen {
+				index[user.UserID] = len(merged)
+				merged = append(merged, user)
+				continue
+			}
+			if user.LastSeen.After(merged[i].LastSeen) {
+				merged[i] = user
+			}
+		}
+	}
+	return merged
+}
@@ -0,0 +1,56 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeActiveUsersDeduplicatesByUser(t *testing.T) {
+	userID := uuid.New()
+	otherID := uuid.New()
+	now := time.Now()
+
+	local := []ActiveUser{
+		{UserID: userID, Username: "alice", LastSeen: now.Add(-time.Minute)},
+		{UserID: otherID, Username: "bob", LastSeen: now},
+	}
+	remote := []ActiveUser{
+		{UserID: userID, Username: "alice", LastSeen: now},
+	}
+
+	merged := mergeActiveUsers(local, remote)
+
+	assert.Len(t, merged, 2)
+	assert.Equal(t, userID, merged[0].UserID)
+	assert.Equal(t, now, merged[0].LastSeen)
+	assert.Equal(t, otherID, merged[1].UserID)
+}
+
+func TestMergeActiveUsersEmpty(t *testing.T) {
+	assert.Nil(t, mergeActiveUsers(nil, nil))
+}
+
+func TestGetActiveUsersWithoutRedisReturnsLocalUsers(t *testing.T) {
+	hub := NewHub()
+	defer hub.Shutdown()
+
+	projectID := uuid.New()
+	userID := uuid.New()
+	for i := 0; i < 2; i++ {
+		hub.RegisterClient(&Client{
+			ID:        uuid.New(),
+			UserID:    userID,
+			ProjectID: projectID,
+			Send:      make(chan []byte, 256),
+			Hub:       hub,
+		})
+	}
+	assert.Eventually(t, func() bool { return hub.GetActiveClients(projectID) == 2 }, time.Second, 5*time.Millisecond)
+
+	users := hub.GetActiveUsers(projectID)
+	assert.Len(t, users, 1)
+	assert.Equal(t, userID, users[0].UserID)
+}
@@ -0,0 +1,757 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// projectShard owns the clients of a single project. Each shard runs its own
+// goroutine so registration and broadcasts in one project never wait on another.
+type projectShard struct {
+	projectID uuid.UUID
+	hub       *Hub
+
+	// Registered clients, guarded by mu. Only the shard goroutine mutates the map;
+	// other goroutines take the read lock to fan out pings and Redis messages.
+	clients map[*Client]bool
+	mu      sync.RWMutex
+
+	// Channels served by the shard goroutine
+	register   chan *Client
+	unregister chan *Client
+	broadcast  chan *BroadcastMessage
+	drags      chan tableDrag
+
+	// Drain requests; the shard closes the reply channel once it has flushed
+	// pending messages and sent the shutdown notice
+	drain chan chan struct{}
+
+	// Closed when the shard goroutine exits
+	done chan struct{}
+
+	// Latest pending cursor and viewport updates per user. Only touched from
+	// the shard goroutine.
+	pendingCursors   map[uuid.UUID]*BroadcastMessage
+	pendingViewports map[uuid.UUID]*BroadcastMessage
+
+	// Latest pending drag position per table. Only touched from the shard
+	// goroutine.
+	pendingDrags map[uuid.UUID]*BroadcastMessage
+
+	// Users whose cursor moved since it was last persisted. Only touched from
+	// the shard goroutine.
+	unsavedCursors map[uuid.UUID]struct{}
+
+	// Cursor updates held back for slow clients, latest per user, and the
+	// number of cursor flushes so far. Only touched from the shard goroutine.
+	slowCursors   map[*Client]map[uuid.UUID][]byte
+	cursorFlushes int
+
+	// Latest cursor of each local user, for presence. Guarded by mu.
+	cursors map[uuid.UUID]Point
+
+	// User each client follows, and the last viewport relayed of each user of
+	// the project, on any node, so new followers start where the user is.
+	// Guarded by mu.
+	following map[*Client]uuid.UUID
+	viewports map[uuid.UUID][]byte
+
+	// Presence status last broadcast for each local user. Guarded by
+	// statusMu, which is held while a change is broadcast so that changes
+	// go out in order. Never take statusMu while holding mu.
+	statuses map[uuid.UUID]string
+	statusMu sync.Mutex
+}
+
+// tableDrag is the position of a table a client is dragging
+type tableDrag struct {
+	tableID uuid.UUID
+	message *BroadcastMessage
+}
+
+func newProjectShard(hub *Hub, projectID uuid.UUID) *projectShard {
+	return &projectShard{
+		projectID:        projectID,
+		hub:              hub,
+		clients:          make(map[*Client]bool),
+		register:         make(chan *Client),
+		unregister:       make(chan *Client),
+		broadcast:        make(chan *BroadcastMessage),
+		drags:            make(chan tableDrag),
+		drain:            make(chan chan struct{}),
+		done:             make(chan struct{}),
+		pendingCursors:   make(map[uuid.UUID]*BroadcastMessage),
+		pendingViewports: make(map[uuid.UUID]*BroadcastMessage),
+		pendingDrags:     make(map[uuid.UUID]*BroadcastMessage),
+		unsavedCursors:   make(map[uuid.UUID]struct{}),
+		slowCursors:      make(map[*Client]map[uuid.UUID][]byte),
+		cursors:          make(map[uuid.UUID]Point),
+		statuses:         make(map[uuid.UUID]string),
+		following:        make(map[*Client]uuid.UUID),
+		viewports:        make(map[uuid.UUID][]byte),
+	}
+}
+
+// run serves the shard channels until the last client leaves or the hub shuts down
+func (s *projectShard) run() {
+	defer close(s.done)
+
+	// Flush timers are only armed while cursor, viewport or drag updates are
+	// pending, the persist timer while cursors are unsaved
+	var flushTimer, viewportTimer, dragTimer, persistTimer *time.Timer
+	var flush, viewportFlush, dragFlush, persist <-chan time.Time
+	defer func() {
+		for _, timer := range []*time.Timer{flushTimer, viewportTimer, dragTimer, persistTimer} {
+			if timer != nil {
+				timer.Stop()
+			}
+		}
+	}()
+
+	for {
+		select {
+		case client := <-s.register:
+			s.addClient(client)
+
+		case client := <-s.unregister:
+			s.removeClient(client)
+			if s.hub.removeShardIfEmpty(s) {
+				return
+			}
+
+		case message := <-s.broadcast:
+			switch message.Message.Type {
+			case MessageTypeUserCursor:
+				s.queueCursorUpdate(message)
+				if flush == nil {
+					flushTimer = time.NewTimer(cursorFlushInterval)
+					flush = flushTimer.C
+				}
+			case MessageTypeViewportUpdate:
+				s.pendingViewports[message.Message.UserID] = message
+				if viewportFlush == nil {
+					viewportTimer = time.NewTimer(viewportFlushInterval)
+					viewportFlush = viewportTimer.C
+				}
+			default:
+				s.broadcastExcept(message.Message, message.Sender)
+			}
+
+		case drag := <-s.drags:
+			s.pendingDrags[drag.tableID] = drag.message
+			if dragFlush == nil {
+				dragTimer = time.NewTimer(tableDragFlushInterval)
+				dragFlush = dragTimer.C
+			}
+
+		case <-dragFlush:
+			dragFlush = nil
+			s.flushDrags()
+
+		case <-flush:
+			flush = nil
+			s.flushCursorUpdates()
+			// Keep flushing until slow clients have caught up
+			if len(s.slowCursors) > 0 {
+				flushTimer = time.NewTimer(cursorFlushInterval)
+				flush = flushTimer.C
+			}
+			if persist == nil && len(s.unsavedCursors) > 0 {
+				persistTimer = time.NewTimer(cursorPersistInterval)
+				persist = persistTimer.C
+			}
+
+		case <-persist:
+			persist = nil
+			s.persistCursors()
+
+		case <-viewportFlush:
+			viewportFlush = nil
+			s.flushViewportUpdates()
+
+		case reply := <-s.drain:
+			s.flushCursorUpdates()
+			s.flushSlowCursors()
+			s.flushViewportUpdates()
+			s.flushDrags()
+			s.persistCursors()
+			s.sendShutdownNotice()
+			close(reply)
+
+		case <-s.hub.done:
+			return
+		}
+	}
+}
+
+// addClient adds a client, announces it to the project and sends it the current presence
+func (s *projectShard) addClient(client *Client) {
+	if s.hub.isShuttingDown.Load() {
+		log.Printf("Cannot register client %s: hub is shutting down", client.UserID)
+		return
+	}
+
+	s.mu.Lock()
+	firstConnection := !s.hasUserLocked(client.UserID)
+	s.clients[client] = true
+	client.LastPing = time.Now()
+	client.lastActivity.Store(client.LastPing.UnixNano())
+	s.mu.Unlock()
+
+	s.updateStatus(client.UserID)
+
+	log.Printf("Client %s joined project %s", client.UserID, client.ProjectID)
+
+	s.hub.markPresenceDirty(s.projectID)
+
+	if firstConnection {
+		s.hub.notifySession(sessionEvent{
+			kind:      sessionConnected,
+			projectID: s.projectID,
+			userID:    client.UserID,
+			userColor: client.UserColor,
+		})
+	}
+
+	// Notify other clients about the new user
+	userJoinedPayload := UserJoinedPayload{
+		UserID:    client.UserID,
+		Username:  client.Username,
+		UserColor: client.UserColor,
+		AvatarURL: client.AvatarURL,
+	}
+
+	message, err := NewWebSocketMessage(MessageTypeUserJoined, userJoinedPayload, client.UserID, client.ProjectID)
+	if err != nil {
+		log.Printf("Error creating user joined message: %v", err)
+		return
+	}
+
+	s.broadcastExcept(message, client)
+
+	// Send current presence to the new client. Presence of other nodes is read
+	// from Redis, so it is looked up off the shard goroutine.
+	go s.sendPresence(client)
+}
+
+// removeClient removes a client, closes its send channel and notifies the remaining clients
+func (s *projectShard) removeClient(client *Client) {
+	s.mu.Lock()
+	_, exists := s.clients[client]
+	if exists {
+		delete(s.clients, client)
+		delete(s.following, client)
+		delete(s.slowCursors, client)
+		s.hub.safeCloseChannel(client.Send)
+	}
+	lastConnection := exists && !s.hasUserLocked(client.UserID)
+	cursor, hasCursor := s.cursors[client.UserID]
+	if lastConnection {
+		delete(s.viewports, client.UserID)
+		delete(s.cursors, client.UserID)
+	}
+	s.mu.Unlock()
+
+	if !exists {
+		return
+	}
+
+	s.updateStatus(client.UserID)
+
+	log.Printf("Client %s left project %s", client.UserID, client.ProjectID)
+
+	s.hub.markPresenceDirty(s.projectID)
+
+	if lastConnection {
+		// Save where the user left their cursor before they disconnect
+		if _, unsaved := s.unsavedCursors[client.UserID]; unsaved && hasCursor {
+			delete(s.unsavedCursors, client.UserID)
+			s.hub.notifySession(sessionEvent{
+				kind:      sessionCursorMoved,
+				projectID: s.projectID,
+				userID:    client.UserID,
+				cursor:    cursor,
+			})
+		}
+		s.hub.notifySession(sessionEvent{
+			kind:      sessionDisconnected,
+			projectID: s.projectID,
+			userID:    client.UserID,
+		})
+	}
+
+	userLeftPayload := UserLeftPayload{
+		UserID: client.UserID,
+	}
+
+	message, err := NewWebSocketMessage(MessageTypeUserLeft, userLeftPayload, client.UserID, client.ProjectID)
+	if err != nil {
+		log.Printf("Error creating user left message: %v", err)
+		return
+	}
+
+	s.broadcastExcept(message, client)
+}
+
+// broadcastExcept sends a message to every client in the project except the sender
+// and publishes it to the broker for other nodes
+func (s *projectShard) broadcastExcept(message *WebSocketMessage, except *Client) {
+	if s.hub.isShuttingDown.Load() {
+		return
+	}
+
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Error marshaling message: %v", err)
+		return
+	}
+
+	s.hub.messagesBroadcast.Add(1)
+
+	s.mu.RLock()
+	for client := range s.clients {
+		if client != except {
+			s.hub.deliver(client, messageBytes)
+		}
+	}
+	s.mu.RUnlock()
+
+	// Publish to the broker for cross-node synchronization (async)
+	s.hub.publishToBroker(s.projectID, messageBytes)
+}
+
+// deliverLocal sends raw message bytes to local clients, skipping those owned by skipUserID.
+// Used for messages that arrived from the broker and must not be re-published.
+func (s *projectShard) deliverLocal(messageBytes []byte, skipUserID uuid.UUID) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for client := range s.clients {
+		if client.UserID == skipUserID {
+			continue
+		}
+		s.hub.deliver(client, messageBytes)
+	}
+}
+
+// sendPresence sends the list of users in the project, across all nodes, to a single client
+func (s *projectShard) sendPresence(targetClient *Client) {
+	if s.hub.isShuttingDown.Load() {
+		return
+	}
+
+	presencePayload := UserPresencePayload{
+		ActiveUsers: s.hub.GetActiveUsers(s.projectID),
+	}
+
+	message, err := NewWebSocketMessage(MessageTypeUserPresence, presencePayload, targetClient.UserID, targetClient.ProjectID)
+	if err != nil {
+		log.Printf("Error creating presence message: %v", err)
+		return
+	}
+
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Error marshaling presence message: %v", err)
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.clients[targetClient] {
+		s.hub.deliver(targetClient, messageBytes)
+	}
+}
+
+// queueCursorUpdate stores a cursor update until the next flush, replacing any
+// earlier update from the same user that has not been sent yet
+func (s *projectShard) queueCursorUpdate(broadcastMsg *BroadcastMessage) {
+	s.pendingCursors[broadcastMsg.Message.UserID] = broadcastMsg
+}
+
+// flushCursorUpdates broadcasts the latest queued cursor update of every user.
+// Slow clients get theirs every slowCursorDivisor flushes.
+func (s *projectShard) flushCursorUpdates() {
+	if s.hub.isShuttingDown.Load() {
+		return
+	}
+	s.cursorFlushes++
+
+	pending := s.pendingCursors
+	s.pendingCursors = make(map[uuid.UUID]*BroadcastMessage)
+
+	for userID, broadcastMsg := range pending {
+		messageBytes, err := json.Marshal(broadcastMsg.Message)
+		if err != nil {
+			log.Printf("Error marshaling message: %v", err)
+			continue
+		}
+		s.hub.messagesBroadcast.Add(1)
+
+		s.mu.RLock()
+		for client := range s.clients {
+			if client == broadcastMsg.Sender {
+				continue
+			}
+			if client.connectionQuality().Slow {
+				if s.slowCursors[client] == nil {
+					s.slowCursors[client] = make(map[uuid.UUID][]byte)
+				}
+				s.slowCursors[client][userID] = messageBytes
+				continue
+			}
+			s.hub.deliver(client, messageBytes)
+		}
+		s.mu.RUnlock()
+
+		s.hub.publishToBroker(s.projectID, messageBytes)
+
+		var payload UserCursorPayload
+		if err := broadcastMsg.Message.UnmarshalData(&payload); err != nil {
+			continue
+		}
+		s.mu.Lock()
+		if s.hasUserLocked(userID) {
+			s.cursors[userID] = Point{X: payload.CursorX, Y: payload.CursorY}
+			s.unsavedCursors[userID] = struct{}{}
+		}
+		s.mu.Unlock()
+	}
+
+	if s.cursorFlushes%slowCursorDivisor == 0 {
+		s.flushSlowCursors()
+	}
+}
+
+// flushSlowCursors delivers the cursor updates held back for slow clients
+func (s *projectShard) flushSlowCursors() {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for client, cursors := range s.slowCursors {
+		if !s.clients[client] {
+			continue
+		}
+		for _, messageBytes := range cursors {
+			s.hub.deliver(client, messageBytes)
+		}
+	}
+	s.slowCursors = make(map[*Client]map[uuid.UUID][]byte)
+}
+
+// persistCursors hands the cursors that moved since the last call to the
+// session listener and refreshes the project's presence on other nodes
+func (s *projectShard) persistCursors() {
+	if len(s.unsavedCursors) == 0 {
+		return
+	}
+
+	s.mu.RLock()
+	events := make([]sessionEvent, 0, len(s.unsavedCursors))
+	for userID := range s.unsavedCursors {
+		if cursor, ok := s.cursors[userID]; ok {
+			events = append(events, sessionEvent{
+				kind:      sessionCursorMoved,
+				projectID: s.projectID,
+				userID:    userID,
+				cursor:    cursor,
+			})
+		}
+	}
+	s.mu.RUnlock()
+	s.unsavedCursors = make(map[uuid.UUID]struct{})
+
+	for _, event := range events {
+		s.hub.notifySession(event)
+	}
+	s.hub.markPresenceDirty(s.projectID)
+}
+
+// flushViewportUpdates relays the latest queued viewport of every user to
+// their followers, here and on other nodes
+func (s *projectShard) flushViewportUpdates() {
+	if len(s.pendingViewports) == 0 || s.hub.isShuttingDown.Load() {
+		return
+	}
+
+	pending := s.pendingViewports
+	s.pendingViewports = make(map[uuid.UUID]*BroadcastMessage)
+
+	for userID, broadcastMsg := range pending {
+		messageBytes, err := json.Marshal(broadcastMsg.Message)
+		if err != nil {
+			log.Printf("Error marshaling message: %v", err)
+			continue
+		}
+
+		s.hub.messagesBroadcast.Add(1)
+		s.deliverViewport(messageBytes, userID)
+		s.hub.publishToBroker(s.projectID, messageBytes)
+	}
+}
+
+// flushDrags relays the latest drag position of every dragged table
+func (s *projectShard) flushDrags() {
+	pending := s.pendingDrags
+	s.pendingDrags = make(map[uuid.UUID]*BroadcastMessage)
+
+	for _, drag := range pending {
+		s.broadcastExcept(drag.Message, drag.Sender)
+	}
+}
+
+// deliverViewport remembers a user's viewport and sends it to the local
+// clients following the user
+func (s *projectShard) deliverViewport(messageBytes []byte, userID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.viewports[userID] = messageBytes
+	for client, followed := range s.following {
+		if followed == userID {
+			s.hub.deliver(client, messageBytes)
+		}
+	}
+}
+
+// follow makes a client follow a user, or with uuid.Nil stop following, and
+// sends it the user's last viewport. Clients that have left are ignored.
+func (s *projectShard) follow(client *Client, userID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.clients[client] {
+		return
+	}
+	if userID == uuid.Nil {
+		delete(s.following, client)
+		return
+	}
+	s.following[client] = userID
+	if messageBytes, ok := s.viewports[userID]; ok {
+		s.hub.deliver(client, messageBytes)
+	}
+}
+
+// forgetViewport drops the last viewport of a user that left
+func (s *projectShard) forgetViewport(userID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.viewports, userID)
+}
+
+// disconnectUser sends the message to the user's local clients and then
+// unregisters them, which closes their connections once the message is written.
+// It must not be called from the shard goroutine.
+func (s *projectShard) disconnectUser(userID uuid.UUID, messageBytes []byte) {
+	var clients []*Client
+	s.mu.RLock()
+	for client := range s.clients {
+		if client.UserID == userID {
+			s.hub.deliver(client, messageBytes)
+			clients = append(clients, client)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, client := range clients {
+		select {
+		case s.unregister <- client:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// updatePermissions sets whether the user's local clients may edit and sends
+// them the message announcing it
+func (s *projectShard) updatePermissions(userID uuid.UUID, canEdit bool, messageBytes []byte) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for client := range s.clients {
+		if client.UserID == userID {
+			client.readOnly.Store(!canEdit)
+			s.hub.deliver(client, messageBytes)
+		}
+	}
+}
+
+// sendShutdownNotice tells every local client that the server is going away.
+// The notice is node-local and never published to the broker.
+func (s *projectShard) sendShutdownNotice() {
+	payload := ServerShutdownPayload{
+		Message: "Server is restarting, please reconnect",
+	}
+
+	message, err := NewWebSocketMessage(MessageTypeServerShutdown, payload, uuid.Nil, s.projectID)
+	if err != nil {
+		log.Printf("Error creating shutdown message: %v", err)
+		return
+	}
+
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Error marshaling shutdown message: %v", err)
+		return
+	}
+
+	s.deliverLocal(messageBytes, uuid.Nil)
+}
+
+// pendingSends returns the number of messages queued on client send channels
+func (s *projectShard) pendingSends() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pending := 0
+	for client := range s.clients {
+		pending += len(client.Send)
+	}
+	return pending
+}
+
+// activeUsers returns the users connected to the project on this node
+func (s *projectShard) activeUsers() []ActiveUser {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	var activeUsers []ActiveUser
+	for client := range s.clients {
+		user := ActiveUser{
+			UserID:    client.UserID,
+			Username:  client.Username,
+			UserColor: client.UserColor,
+			AvatarURL: client.AvatarURL,
+			Status:    s.userStatusLocked(client.UserID, now),
+			LastSeen:  client.LastPing,
+		}
+		quality := client.connectionQuality()
+		user.Connection = &quality
+		if cursor, ok := s.cursors[client.UserID]; ok {
+			user.CursorX = &cursor.X
+			user.CursorY = &cursor.Y
+		}
+		activeUsers = append(activeUsers, user)
+	}
+	return activeUsers
+}
+
+// presenceRank orders presence statuses from most to least present
+var presenceRank = map[string]int{PresenceActive: 0, PresenceIdle: 1, PresenceAway: 2}
+
+// clientStatus returns the presence status of a single client
+func (s *projectShard) clientStatus(client *Client, now time.Time) string {
+	if client.away.Load() {
+		return PresenceAway
+	}
+	if now.Sub(time.Unix(0, client.lastActivity.Load())) >= s.hub.idleTimeout {
+		return PresenceIdle
+	}
+	return PresenceActive
+}
+
+// userStatusLocked returns the status of the user's most present client, or
+// "" when none is connected. MUST be called with s.mu held.
+func (s *projectShard) userStatusLocked(userID uuid.UUID, now time.Time) string {
+	status := ""
+	for client := range s.clients {
+		if client.UserID != userID {
+			continue
+		}
+		if clientStatus := s.clientStatus(client, now); status == "" || presenceRank[clientStatus] < presenceRank[status] {
+			status = clientStatus
+		}
+	}
+	return status
+}
+
+// updateStatus broadcasts a change of the user's presence status and hands
+// it to the session listener. A user's first status is not broadcast: their
+// user_joined message announces them, active.
+func (s *projectShard) updateStatus(userID uuid.UUID) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	s.mu.RLock()
+	status := s.userStatusLocked(userID, time.Now())
+	s.mu.RUnlock()
+
+	previous, known := s.statuses[userID]
+	if status == "" {
+		delete(s.statuses, userID)
+		return
+	}
+	s.statuses[userID] = status
+	if !known || previous == status {
+		return
+	}
+
+	message, err := NewWebSocketMessage(MessageTypePresenceStatus, PresenceStatusPayload{UserID: userID, Status: status}, userID, s.projectID)
+	if err != nil {
+		log.Printf("Error creating presence status message: %v", err)
+		return
+	}
+	s.broadcastExcept(message, nil)
+	s.hub.markPresenceDirty(s.projectID)
+	s.hub.notifySession(sessionEvent{
+		kind:      sessionStatusChanged,
+		projectID: s.projectID,
+		userID:    userID,
+		status:    status,
+	})
+}
+
+// updateStatuses updates the presence status of every local user
+func (s *projectShard) updateStatuses() {
+	s.mu.RLock()
+	userIDs := make(map[uuid.UUID]struct{})
+	for client := range s.clients {
+		userIDs[client.UserID] = struct{}{}
+	}
+	s.mu.RUnlock()
+
+	for userID := range userIDs {
+		s.updateStatus(userID)
+	}
+}
+
+// hasUserLocked reports whether any client of the user is connected.
+// MUST be called with s.mu held.
+func (s *projectShard) hasUserLocked(userID uuid.UUID) bool {
+	for client := range s.clients {
+		if client.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// clientCount returns the number of clients connected to the project
+func (s *projectShard) clientCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.clients)
+}
+
+// closeClients closes every client's send channel and connection
+func (s *projectShard) closeClients() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for client := range s.clients {
+		s.hub.safeCloseChannel(client.Send)
+		if client.Conn != nil {
+			client.Conn.Close()
+		}
+	}
+	s.clients = make(map[*Client]bool)
+}
@@ -0,0 +1,111 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/Bug-Bugger/ezmodel/internal/broker"
+	"github.com/google/uuid"
+)
+
+const (
+	// eventSinkQueueSize is how far a sink can fall behind before events are dropped
+	eventSinkQueueSize = 1024
+
+	// eventSinkFlushTimeout bounds how long shutdown waits for queued events
+	eventSinkFlushTimeout = 5 * time.Second
+)
+
+// eventSink publishes a queue of events in order with a single goroutine
+type eventSink struct {
+	publisher broker.Publisher
+	events    chan sinkEvent
+}
+
+type sinkEvent struct {
+	topic string
+	data  []byte
+}
+
+// SetEventSinks sets the publishers that receive the schema-change events
+// broadcast from this node. Events received through the broker are not
+// exported again, so each event leaves exactly one node.
+func (h *Hub) SetEventSinks(publishers []broker.Publisher) {
+	for _, publisher := range publishers {
+		sink := &eventSink{publisher: publisher, events: make(chan sinkEvent, eventSinkQueueSize)}
+		h.eventSinks = append(h.eventSinks, sink)
+
+		h.eventSinksWG.Add(1)
+		go h.runEventSink(sink)
+	}
+}
+
+// exportEvent queues a message for every event sink if it changes a schema
+func (h *Hub) exportEvent(projectID uuid.UUID, message *WebSocketMessage) {
+	if len(h.eventSinks) == 0 || !message.Type.IsSchemaChange() {
+		return
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Failed to encode %s event for sinks: %v", message.Type, err)
+		return
+	}
+
+	event := sinkEvent{topic: projectTopic(projectID), data: data}
+
+	// Held so the queues cannot be closed while sending
+	h.eventSinksMu.RLock()
+	defer h.eventSinksMu.RUnlock()
+	if h.eventSinksClosed {
+		return
+	}
+	for _, sink := range h.eventSinks {
+		select {
+		case sink.events <- event:
+		default:
+			h.eventSinkFailures.Add(1)
+			log.Printf("Dropping %s event for %s (queue full)", message.Type, sink.publisher.Name())
+		}
+	}
+}
+
+func (h *Hub) runEventSink(sink *eventSink) {
+	defer h.eventSinksWG.Done()
+
+	for event := range sink.events {
+		if err := sink.publisher.Publish(event.topic, event.data); err != nil {
+			h.eventSinkFailures.Add(1)
+			log.Printf("Failed to publish to %s topic %s: %v", sink.publisher.Name(), event.topic, err)
+		}
+	}
+}
+
+// closeEventSinks publishes the events still queued, waiting at most
+// eventSinkFlushTimeout, and closes the sinks
+func (h *Hub) closeEventSinks() {
+	h.eventSinksMu.Lock()
+	h.eventSinksClosed = true
+	for _, sink := range h.eventSinks {
+		close(sink.events)
+	}
+	h.eventSinksMu.Unlock()
+
+	flushed := make(chan struct{})
+	go func() {
+		h.eventSinksWG.Wait()
+		close(flushed)
+	}()
+	select {
+	case <-flushed:
+	case <-time.After(eventSinkFlushTimeout):
+		log.Println("Timed out publishing queued events to sinks")
+	}
+
+	for _, sink := range h.eventSinks {
+		if err := sink.publisher.Close(); err != nil {
+			log.Printf("Failed to close %s: %v", sink.publisher.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package websocket
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultWriteWorkers is how many database writes made for clients may run at once
+	defaultWriteWorkers = 16
+
+	// defaultWriteQueueSize is how many writes may wait for a worker before
+	// more are rejected
+	defaultWriteQueueSize = 1024
+
+	// writeFlushTimeout bounds how long shutdown waits for queued writes
+	writeFlushTimeout = 5 * time.Second
+)
+
+// writePool runs the database writes that client messages cause, such as
+// saving a canvas or a table position, on a fixed number of workers. Writes
+// wait in a bounded queue, so a burst of messages cannot open more database
+// writes than there are workers.
+type writePool struct {
+	workers   int
+	queueSize int
+	start     sync.Once
+	writes    chan func()
+	wg        sync.WaitGroup
+	mu        sync.RWMutex
+	closed    bool
+}
+
+// SetWriteWorkers sets how many database writes made for clients run at once
+// and how many may wait for a worker. Must be called before clients connect.
+func (h *Hub) SetWriteWorkers(workers, queueSize int) {
+	if workers > 0 {
+		h.writes.workers = workers
+	}
+	if queueSize > 0 {
+		h.writes.queueSize = queueSize
+	}
+}
+
+// SubmitWrite queues a database write made for a client. It reports false,
+// without running the write, when the queue is full or the hub is shutting
+// down; the client should then be told to retry.
+func (h *Hub) SubmitWrite(write func()) bool {
+	h.writes.start.Do(func() {
+		h.writes.writes = make(chan func(), h.writes.queueSize)
+		for range h.writes.workers {
+			h.writes.wg.Add(1)
+			go h.runWriteWorker()
+		}
+	})
+
+	// Held so the queue cannot be closed while sending
+	h.writes.mu.RLock()
+	defer h.writes.mu.RUnlock()
+	if h.writes.closed {
+		h.rejectedWrites.Add(1)
+		return false
+	}
+
+	select {
+	case h.writes.writes <- write:
+		return true
+	default:
+		h.rejectedWrites.Add(1)
+		return false
+	}
+}
+
+func (h *Hub) runWriteWorker() {
+	defer h.writes.wg.Done()
+
+	for write := range h.writes.writes {
+		h.activeWrites.Add(1)
+		write()
+		h.activeWrites.Add(-1)
+	}
+}
+
+// queuedWrites is how many writes wait for a worker
+func (h *Hub) queuedWrites() int {
+	h.writes.mu.RLock()
+	defer h.writes.mu.RUnlock()
+	return len(h.writes.writes)
+}
+
+// closeWrites runs the writes still queued, waiting at most writeFlushTimeout,
+// and rejects any submitted later
+func (h *Hub) closeWrites() {
+	h.writes.mu.Lock()
+	h.writes.closed = true
+	if h.writes.writes != nil {
+		close(h.writes.writes)
+	}
+	h.writes.mu.Unlock()
+
+	flushed := make(chan struct{})
+	go func() {
+		h.writes.wg.Wait()
+		close(flushed)
+	}()
+	select {
+	case <-flushed:
+	case <-time.After(writeFlushTimeout):
+		log.Println("Timed out running queued client writes")
+	}
+}
@@ -0,0 +1,59 @@
+package websocket
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitWrite_RejectsWhenQueueFull(t *testing.T) {
+	hub := NewHub()
+	hub.SetWriteWorkers(1, 1)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var completed atomic.Int32
+
+	// The only worker is busy and the one queue slot is taken
+	require.True(t, hub.SubmitWrite(func() {
+		close(started)
+		<-release
+		completed.Add(1)
+	}))
+	<-started
+	require.True(t, hub.SubmitWrite(func() { completed.Add(1) }))
+
+	assert.False(t, hub.SubmitWrite(func() { t.Error("a rejected write was run") }))
+
+	stats := hub.Stats()
+	assert.Equal(t, 1, stats.QueuedWrites)
+	assert.Equal(t, int64(1), stats.ActiveWrites)
+	assert.Equal(t, uint64(1), stats.RejectedWrites)
+
+	close(release)
+	assert.Eventually(t, func() bool { return completed.Load() == 2 }, time.Second, 5*time.Millisecond)
+	assert.Eventually(t, func() bool { return hub.Stats().ActiveWrites == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestSubmitWrite_ShutdownRunsQueuedWrites(t *testing.T) {
+	hub := NewHub()
+	hub.SetWriteWorkers(2, 8)
+
+	var completed atomic.Int32
+	for range 5 {
+		require.True(t, hub.SubmitWrite(func() {
+			time.Sleep(5 * time.Millisecond)
+			completed.Add(1)
+		}))
+	}
+
+	hub.Shutdown()
+	assert.Equal(t, int32(5), completed.Load())
+
+	// Writes submitted after shutdown are rejected
+	assert.False(t, hub.SubmitWrite(func() { t.Error("a write was run after shutdown") }))
+	assert.Equal(t, uint64(1), hub.Stats().RejectedWrites)
+}
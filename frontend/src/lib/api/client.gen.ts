@@ -0,0 +1,2234 @@
+// Code generated by cmd/sdkgen from the OpenAPI document. DO NOT EDIT.
+
+export const API_VERSION = '1.0.0';
+export const SPEC_HASH = '559f0822684e';
+
+export interface APIResponse {
+	data?: unknown;
+	errors?: unknown;
+	message?: string;
+	meta?: PageMeta | null;
+	success: boolean;
+}
+
+export interface APITokenResponse {
+	created_at: string;
+	expires_at: string | null;
+	id: string;
+	last_used_at: string | null;
+	name: string;
+	prefix: string;
+	revoked_at: string | null;
+	scopes: string[];
+	user_id: string;
+}
+
+export interface AcceptRelationshipSuggestionsRequest {
+	relationships: SchemaRelationshipRequest[];
+}
+
+export interface AccessRevokedPayload {
+	message: string;
+	user_id: string;
+}
+
+export interface ActiveCollaboratorResponse {
+	avatar_url?: string;
+	user_color: string;
+	user_id: string;
+	username: string;
+}
+
+export interface ActiveUser {
+	avatar_url?: string;
+	connection?: ConnectionQuality | null;
+	cursor_x?: number | null;
+	cursor_y?: number | null;
+	last_seen: string;
+	status: string;
+	user_color: string;
+	user_id: string;
+	username: string;
+}
+
+export interface AddCollaboratorRequest {
+	collaborator_id: string;
+}
+
+export interface AdminAuditLogResponse {
+	action: string;
+	actor_id: string;
+	created_at: string;
+	details?: string;
+	id: string;
+	ip_address: string;
+	target_user_id: string;
+}
+
+export interface AdminUserResponse {
+	created_at: string;
+	disabled_at: string | null;
+	email: string;
+	id: string;
+	is_service_account: boolean;
+	password_reset_required: boolean;
+	role: string;
+	username: string;
+}
+
+export interface ApplyLogResponse {
+	checksum: string;
+	statements: ApplyResultResponse[];
+	status: string;
+	transaction: string;
+	unsupported: string[];
+}
+
+export interface ApplyPlanResponse {
+	checksum: string;
+	destructive: boolean;
+	statements: ApplyStatementResponse[];
+	unsupported: string[];
+}
+
+export interface ApplyResultResponse {
+	duration_ms: number;
+	error?: string;
+	sql: string;
+	status: string;
+}
+
+export interface ApplyStatementResponse {
+	destructive: boolean;
+	sql: string;
+}
+
+export interface AuthPayload {
+	encoding?: string;
+	token?: string;
+}
+
+export interface AuthSuccessPayload {
+	encoding: string;
+	message: string;
+	role: string;
+	user_id: string;
+}
+
+export interface BranchMergeResponse {
+	branch: BranchResponse;
+	changes: SchemaDiffResponse;
+}
+
+export interface BranchResponse {
+	branch_id: string;
+	created_at: string;
+	created_by: string;
+	merged_at: string | null;
+	merged_by: string | null;
+	name: string;
+	project_id: string;
+	schema?: CreateSchemaRequest | null;
+	updated_at: string;
+	version: number;
+}
+
+export interface BulkCreateFieldsRequest {
+	fields: CreateFieldRequest[];
+}
+
+export interface CanvasChunkPayload {
+	chunk_id?: string;
+	data?: string;
+	index?: number;
+	total?: number;
+}
+
+export interface CanvasPingPayload {
+	table_id?: string | null;
+	ttl: number;
+	user_color: string;
+	user_id: string;
+	username: string;
+	x: number;
+	y: number;
+}
+
+export interface CanvasUpdatedPayload {
+	canvas_data: string;
+}
+
+export interface ChatMessagePayload {
+	content: string;
+	created_at: string;
+	message_id: string;
+	user_id: string;
+	username: string;
+}
+
+export interface ChatMessageResponse {
+	content: string;
+	created_at: string;
+	message_id: string;
+	project_id: string;
+	user_id: string;
+	username: string;
+}
+
+export interface ChatSendPayload {
+	content?: string;
+}
+
+export interface CheckoutResponse {
+	url: string;
+}
+
+export interface CollaborationSessionResponse {
+	cursor_x: number | null;
+	cursor_y: number | null;
+	id: string;
+	is_active: boolean;
+	joined_at: string;
+	last_ping_at: string;
+	left_at: string | null;
+	project_id: string;
+	status: string;
+	user_color: string;
+	user_id: string;
+}
+
+export interface ConnectionProfileResponse {
+	dsn: string;
+	project_id: string;
+	scheduled_checks: boolean;
+	schema_name: string;
+	updated_at: string;
+	webhook_signed: boolean;
+	webhook_url: string;
+}
+
+export interface ConnectionQuality {
+	queue_depth: number;
+	rtt_ms: number;
+	slow: boolean;
+}
+
+export interface CreateAPITokenRequest {
+	expires_at?: string | null;
+	name: string;
+	scopes: string[];
+}
+
+export interface CreateAPITokenResponse {
+	created_at: string;
+	expires_at: string | null;
+	id: string;
+	last_used_at: string | null;
+	name: string;
+	prefix: string;
+	revoked_at: string | null;
+	scopes: string[];
+	token: string;
+	user_id: string;
+}
+
+export interface CreateBranchRequest {
+	name: string;
+}
+
+export interface CreateCheckoutRequest {
+	plan: string;
+}
+
+export interface CreateFieldRequest {
+	data_type: string;
+	default_value?: string;
+	is_nullable?: boolean;
+	is_primary_key?: boolean;
+	metadata?: Record<string, unknown>;
+	name: string;
+	position?: number;
+}
+
+export interface CreateProjectRequest {
+	description?: string;
+	name: string;
+}
+
+export interface CreateRegionRequest {
+	color: string;
+	height?: number;
+	name: string;
+	pos_x?: number;
+	pos_y?: number;
+	table_ids?: string[];
+	width?: number;
+}
+
+export interface CreateRelationshipRequest {
+	label_position?: number | null;
+	metadata?: Record<string, unknown>;
+	relation_type?: 'one_to_one' | 'one_to_many' | 'many_to_many';
+	source_anchor?: 'auto' | 'top' | 'right' | 'bottom' | 'left';
+	source_field_id: string;
+	source_table_id: string;
+	target_anchor?: 'auto' | 'top' | 'right' | 'bottom' | 'left';
+	target_field_id: string;
+	target_table_id: string;
+	waypoints?: DtoPoint[];
+}
+
+export interface CreateReleaseRequest {
+	locked?: boolean;
+	name: string;
+	notes?: string;
+	snapshot_id?: string | null;
+}
+
+export interface CreateSchemaRequest {
+	conflicts?: SchemaConflictResolution[];
+	relationships?: SchemaRelationshipRequest[];
+	tables: SchemaTableRequest[];
+}
+
+export interface CreateServiceAccountRequest {
+	name: string;
+	scopes: string[];
+}
+
+export interface CreateSessionRequest {
+	user_color?: string;
+}
+
+export interface CreateSnippetRequest {
+	description?: string;
+	name: string;
+	sql: string;
+}
+
+export interface CreateTableRequest {
+	color?: string;
+	icon?: 'database' | 'users' | 'user' | 'credit-card' | 'shopping-cart' | 'package' | 'file-text' | 'settings' | 'lock' | 'globe' | 'calendar' | 'mail' | 'tag' | 'activity';
+	metadata?: Record<string, unknown>;
+	name: string;
+	pos_x?: number;
+	pos_y?: number;
+}
+
+export interface CreateUserRequest {
+	email: string;
+	password: string;
+	username: string;
+}
+
+export interface DailySignupsResponse {
+	count: number;
+	date: string;
+}
+
+export interface DataExportResponse {
+	completed_at: string | null;
+	created_at: string;
+	download_url?: string;
+	expires_at: string | null;
+	id: string;
+	size_bytes: number;
+	status: string;
+}
+
+export interface DataProfileResponse {
+	error?: string;
+	profiled_at: string | null;
+	status: string;
+	tables: TableProfileResponse[];
+}
+
+export interface DeleteAccountRequest {
+	confirm_email?: string;
+	delete_shared_projects?: boolean;
+	password?: string;
+	project_transfers?: ProjectTransferRequest[];
+}
+
+export interface DiffChangeResponse {
+	attribute: string;
+	from: string;
+	to: string;
+}
+
+export interface DiffFieldResponse {
+	data_type: string;
+	default_value: string;
+	is_nullable: boolean;
+	is_primary_key: boolean;
+	name: string;
+}
+
+export interface DiffRelationshipResponse {
+	relation_type: string;
+	source_field: string;
+	source_table: string;
+	target_field: string;
+	target_table: string;
+}
+
+export interface DiffTableResponse {
+	fields: DiffFieldResponse[];
+	name: string;
+}
+
+export interface DocResponse {
+	content: string;
+	edited_at: string | null;
+	edited_by: string | null;
+	project_id: string;
+	version: number;
+}
+
+export interface DocRevisionResponse {
+	edited_at: string;
+	edited_by: string;
+	version: number;
+}
+
+export interface DocUpdatedPayload {
+	version: number;
+}
+
+export interface DriftCheckResponse {
+	completed_at: string | null;
+	created_at: string;
+	diff?: SchemaDiffResponse | null;
+	error?: string;
+	id: string;
+	status: string;
+	trigger: string;
+}
+
+export interface DtoPoint {
+	x: number;
+	y: number;
+}
+
+export interface EdgeRoutePayload {
+	label_position: number;
+	source_anchor: string;
+	target_anchor: string;
+	waypoints: WebsocketPoint[];
+}
+
+export interface ErrorPayload {
+	code?: string;
+	message: string;
+}
+
+export interface ExecuteApplyRequest {
+	allow_destructive?: boolean;
+	checksum: string;
+	dsn: string;
+	schema_name?: string;
+	transaction?: 'single' | 'per_statement';
+}
+
+export interface Field {
+	created_at: string;
+	data_type: string;
+	default_value: string;
+	id: string;
+	is_nullable: boolean;
+	is_primary_key: boolean;
+	metadata: Record<string, unknown>;
+	name: string;
+	position: number;
+	table_id: string;
+	updated_at: string;
+	version: number;
+}
+
+export interface FieldDiffResponse {
+	changes: DiffChangeResponse[];
+	name: string;
+}
+
+export interface FieldPayload {
+	data_type: string;
+	default_value?: string | null;
+	field_id: string;
+	is_nullable: boolean;
+	is_primary_key: boolean;
+	name: string;
+	position: number;
+	table_id: string;
+}
+
+export interface FieldPositionPayload {
+	field_id: string;
+	position: number;
+}
+
+export interface FieldProfileResponse {
+	distinct_count: number;
+	field_id: string;
+	null_ratio: number;
+}
+
+export interface FieldResponse {
+	created_at: string;
+	data_type: string;
+	default_value: string;
+	field_id: string;
+	is_nullable: boolean;
+	is_primary_key: boolean;
+	metadata: Record<string, unknown>;
+	name: string;
+	position: number;
+	table_id: string;
+	updated_at: string;
+	version: number;
+}
+
+export interface FieldsCreatedPayload {
+	fields: FieldPayload[];
+	table_id: string;
+}
+
+export interface FieldsReorderedPayload {
+	base_sequence?: number;
+	fields: FieldPositionPayload[];
+	table_id: string;
+}
+
+export interface FollowUserPayload {
+	user_id?: string | null;
+}
+
+export interface FullProjectResponse {
+	active_collaborators: ActiveCollaboratorResponse[];
+	canvas_data: string;
+	collaborators?: UserResponse[];
+	created_at: string;
+	database_type: string;
+	description: string;
+	id: string;
+	metadata: Record<string, unknown>;
+	name: string;
+	owner: UserResponse;
+	owner_id: string;
+	relationships?: RelationshipResponse[];
+	sequence: number;
+	tables?: TableWithFieldsResponse[];
+	tags: string[];
+	updated_at: string;
+	version: number;
+}
+
+export interface GenerateDataRequest {
+	format?: 'sql' | 'csv';
+	rows?: number;
+	seed?: number | null;
+}
+
+export interface GraphQLRequest {
+	operationName?: string;
+	query: string;
+	variables?: Record<string, unknown>;
+}
+
+export interface HubStats {
+	active_writes: number;
+	broker_publish_failures: number;
+	connections_by_project: Record<string, number>;
+	dropped_sends: number;
+	dropped_session_events: number;
+	event_sink_failures: number;
+	messages_broadcast: number;
+	messages_delivered: number;
+	messages_per_second: number;
+	observers: number;
+	queued_writes: number;
+	rejected_writes: number;
+	total_connections: number;
+}
+
+export interface ImpersonateUserRequest {
+	reason: string;
+}
+
+export interface InferCollectionRequest {
+	documents?: unknown[];
+	/** Arbitrary JSON */
+	mongoose_schema?: unknown;
+	name: string;
+}
+
+export interface InferSchemaRequest {
+	collections: InferCollectionRequest[];
+}
+
+export interface InferSchemaResponse {
+	notes: string[];
+	schema: CreateSchemaRequest;
+}
+
+export interface InstanceStatsResponse {
+	active_connections: number;
+	database_bytes: number;
+	generated_at: string;
+	projects: number;
+	signups: DailySignupsResponse[];
+	tenants: TenantUsageResponse[];
+	users: number;
+}
+
+export interface LintFindingResponse {
+	explanation: string;
+	fields: string[];
+	message: string;
+	rule: string;
+	table: string;
+}
+
+export interface LoginEventResponse {
+	created_at: string;
+	failure_reason?: string;
+	id: string;
+	ip_address: string;
+	success: boolean;
+	suspicious: boolean;
+	user_agent: string;
+}
+
+export interface LoginRequest {
+	email: string;
+	password: string;
+}
+
+export interface MoveRegionRequest {
+	dx?: number;
+	dy?: number;
+	version?: number | null;
+}
+
+export interface MoveRegionResponse {
+	region: RegionResponse;
+	tables: TablePositionResponse[];
+}
+
+export interface MyProjectResponse {
+	collaborators?: UserResponse[];
+	created_at: string;
+	description: string;
+	id: string;
+	name: string;
+	owner_id: string;
+	starred: boolean;
+	tables_count?: number | null;
+	tags: string[];
+	updated_at: string;
+	version: number;
+}
+
+export interface PageMeta {
+	next_cursor?: string;
+	total?: number | null;
+}
+
+export interface PermissionsUpdatedPayload {
+	can_edit: boolean;
+	role: string;
+	user_id: string;
+}
+
+export interface PingPayload {
+	timestamp: string;
+}
+
+export interface PlanApplyRequest {
+	dsn: string;
+	schema_name?: string;
+}
+
+export interface PlanResponse {
+	id: string;
+	max_collaborators: number;
+	max_projects_per_user: number;
+	max_tables_per_project: number;
+	name: string;
+}
+
+export interface Point {
+	x: number;
+	y: number;
+}
+
+export interface PongPayload {
+	timestamp?: string;
+}
+
+export interface PresenceStatusPayload {
+	status: string;
+	user_id: string;
+}
+
+export interface Project {
+	canvas_data: string;
+	collaborators?: User[];
+	created_at: string;
+	database_type: string;
+	description: string;
+	id: string;
+	metadata: Record<string, unknown>;
+	name: string;
+	owner?: User;
+	owner_id: string;
+	relationships?: Relationship[];
+	sequence: number;
+	tables?: Table[];
+	tags?: ProjectTag[];
+	updated_at: string;
+	version: number;
+}
+
+export interface ProjectEventResponse {
+	created_at: string;
+	data: Record<string, unknown>;
+	sequence: number;
+	type: string;
+	user_id: string;
+}
+
+export interface ProjectEventsResponse {
+	events: ProjectEventResponse[];
+	latest_sequence: number;
+	truncated: boolean;
+}
+
+export interface ProjectResponse {
+	canvas_data: string;
+	collaborators?: UserResponse[];
+	created_at: string;
+	database_type: string;
+	description: string;
+	id: string;
+	metadata: Record<string, unknown>;
+	name: string;
+	owner: UserResponse;
+	owner_id: string;
+	relationships?: RelationshipResponse[];
+	sequence: number;
+	tables?: TableWithFieldsResponse[];
+	tags: string[];
+	updated_at: string;
+	version: number;
+}
+
+export interface ProjectSummaryResponse {
+	created_at: string;
+	description: string;
+	id: string;
+	name: string;
+	owner_id: string;
+	tags: string[];
+	updated_at: string;
+	version: number;
+}
+
+export interface ProjectTag {
+	created_at: string;
+	name: string;
+	project_id: string;
+}
+
+export interface ProjectTransferRequest {
+	new_owner_id: string;
+	project_id: string;
+}
+
+export interface ProjectUsageResponse {
+	collaborators: QuotaUsageResponse;
+	name: string;
+	project_id: string;
+	tables: QuotaUsageResponse;
+}
+
+export interface QuotaUsageResponse {
+	limit: number;
+	used: number;
+}
+
+export interface RecentProjectResponse {
+	created_at: string;
+	description: string;
+	id: string;
+	name: string;
+	owner_id: string;
+	tags: string[];
+	updated_at: string;
+	version: number;
+	viewed_at: string;
+}
+
+export interface RegionMovedPayload {
+	region_id: string;
+	tables: TablePositionPayload[];
+	x: number;
+	y: number;
+}
+
+export interface RegionPayload {
+	color: string;
+	height: number;
+	name: string;
+	region_id: string;
+	table_ids: string[];
+	width: number;
+	x: number;
+	y: number;
+}
+
+export interface RegionResponse {
+	color: string;
+	created_at: string;
+	height: number;
+	name: string;
+	pos_x: number;
+	pos_y: number;
+	project_id: string;
+	region_id: string;
+	table_ids: string[];
+	updated_at: string;
+	version: number;
+	width: number;
+}
+
+export interface Relationship {
+	created_at: string;
+	id: string;
+	label_position: number;
+	metadata: Record<string, unknown>;
+	project_id: string;
+	relation_type: string;
+	source_anchor: string;
+	source_field_id: string;
+	source_table_id: string;
+	target_anchor: string;
+	target_field_id: string;
+	target_table_id: string;
+	updated_at: string;
+	version: number;
+	waypoints: Point[];
+}
+
+export interface RelationshipDiffResponse {
+	changes: DiffChangeResponse[];
+	relationship: DiffRelationshipResponse;
+}
+
+export interface RelationshipPayload {
+	from_table: string;
+	relation_type: string;
+	relationship_id: string;
+	route?: EdgeRoutePayload | null;
+	source_field_id: string;
+	source_table_id: string;
+	target_field_id: string;
+	target_table_id: string;
+	to_table: string;
+}
+
+export interface RelationshipResponse {
+	created_at: string;
+	label_position: number;
+	metadata: Record<string, unknown>;
+	project_id: string;
+	relation_type: string;
+	relationship_id: string;
+	source_anchor: string;
+	source_field_id: string;
+	source_table_id: string;
+	target_anchor: string;
+	target_field_id: string;
+	target_table_id: string;
+	updated_at: string;
+	version: number;
+	waypoints: DtoPoint[];
+}
+
+export interface RelationshipSuggestionResponse {
+	reason: string;
+	relation_type: string;
+	source_field: string;
+	source_field_id: string;
+	source_table: string;
+	source_table_id: string;
+	target_field: string;
+	target_field_id: string;
+	target_table: string;
+	target_table_id: string;
+}
+
+export interface ReleaseResponse {
+	created_at: string;
+	created_by: string;
+	locked: boolean;
+	name: string;
+	notes: string;
+	project_id: string;
+	release_id: string;
+	snapshot_id: string;
+	updated_at: string;
+}
+
+export interface RenameRequest {
+	field_id?: string | null;
+	name: string;
+	table_id: string;
+}
+
+export interface RenameResponse {
+	created_at: string;
+	field_id: string | null;
+	id: string;
+	new_name: string;
+	old_name: string;
+	renamed_by: string;
+	table_id: string;
+	table_name: string;
+}
+
+export interface ReorderFieldsRequest {
+	base_sequence?: number | null;
+	field_positions: Record<string, number>;
+}
+
+export interface ReviewCommentResponse {
+	comment_id: string;
+	created_at: string;
+	created_by: string;
+	field_id: string | null;
+	field_name: string;
+	kind: string;
+	message: string;
+	project_id: string;
+	provider: string;
+	resolved_at: string | null;
+	resolved_by: string | null;
+	suggestion: string;
+	table_id: string | null;
+	table_name: string;
+}
+
+export interface SchemaConflictResolution {
+	new_name?: string;
+	strategy: 'skip' | 'rename' | 'overwrite' | 'merge';
+	table: string;
+}
+
+export interface SchemaConflictResponse {
+	existing_table_id: string;
+	new_name?: string;
+	strategy?: string;
+	table: string;
+}
+
+export interface SchemaDiffResponse {
+	added_relationships: DiffRelationshipResponse[];
+	added_tables: DiffTableResponse[];
+	changed_relationships: RelationshipDiffResponse[];
+	changed_tables: TableDiffResponse[];
+	identical: boolean;
+	removed_relationships: DiffRelationshipResponse[];
+	removed_tables: DiffTableResponse[];
+}
+
+export interface SchemaRelationshipRequest {
+	metadata?: Record<string, unknown>;
+	relation_type?: 'one_to_one' | 'one_to_many' | 'many_to_many';
+	source_field: string;
+	source_table: string;
+	target_field: string;
+	target_table: string;
+}
+
+export interface SchemaResponse {
+	conflicts: SchemaConflictResponse[];
+	dry_run: boolean;
+	fields: FieldResponse[];
+	relationships: RelationshipResponse[];
+	tables: TableWithFieldsResponse[];
+}
+
+export interface SchemaTableRequest {
+	color?: string;
+	fields?: CreateFieldRequest[];
+	icon?: 'database' | 'users' | 'user' | 'credit-card' | 'shopping-cart' | 'package' | 'file-text' | 'settings' | 'lock' | 'globe' | 'calendar' | 'mail' | 'tag' | 'activity';
+	metadata?: Record<string, unknown>;
+	name: string;
+	pos_x?: number;
+	pos_y?: number;
+}
+
+export interface SearchResultResponse {
+	detail: string;
+	id: string;
+	name: string;
+	project_id: string;
+	project_name: string;
+	rank: number;
+	table_id: string | null;
+	table_name: string | null;
+	type: string;
+}
+
+export interface SendChatMessageRequest {
+	content: string;
+}
+
+export interface ServerShutdownPayload {
+	message: string;
+}
+
+export interface ServiceAccountResponse {
+	created_at: string;
+	created_by_id: string;
+	id: string;
+	name: string;
+	project_id: string;
+	scopes: string[];
+	user_id: string;
+}
+
+export interface SetCollaboratorRoleRequest {
+	role: 'editor' | 'viewer';
+}
+
+export interface SetConnectionProfileRequest {
+	dsn?: string;
+	scheduled_checks?: boolean | null;
+	schema_name?: string;
+	webhook_secret?: string | null;
+	webhook_url?: string;
+}
+
+export interface SetSnapshotRetentionRequest {
+	keep_daily?: number | null;
+	keep_monthly?: number | null;
+}
+
+export interface SetUserDisabledRequest {
+	reason?: string;
+}
+
+export interface SetUserRoleRequest {
+	role: 'user' | 'admin';
+}
+
+export interface SnapshotResponse {
+	created_at: string;
+	id: string;
+	project_sequence: number;
+	project_version: number;
+	size_bytes: number;
+}
+
+export interface SnapshotRetentionResponse {
+	keep_daily: number;
+	keep_monthly: number;
+	overridden: boolean;
+}
+
+export interface SnippetResponse {
+	created_at: string;
+	created_by: string;
+	description: string;
+	name: string;
+	project_id: string;
+	snippet_id: string;
+	sql: string;
+	updated_at: string;
+}
+
+export interface SubscriptionResponse {
+	current_period_end: string | null;
+	plan: PlanResponse;
+	status: string;
+}
+
+export interface Table {
+	color: string;
+	created_at: string;
+	fields?: Field[];
+	icon: string;
+	id: string;
+	metadata: Record<string, unknown>;
+	name: string;
+	pos_x: number;
+	pos_y: number;
+	project_id: string;
+	updated_at: string;
+	version: number;
+}
+
+export interface TableAppearancePayload {
+	color: string;
+	icon: string;
+}
+
+export interface TableDiffResponse {
+	added_fields: DiffFieldResponse[];
+	changed_fields: FieldDiffResponse[];
+	name: string;
+	removed_fields: DiffFieldResponse[];
+}
+
+export interface TablePayload {
+	appearance?: TableAppearancePayload | null;
+	name: string;
+	table_id: string;
+	x: number;
+	y: number;
+}
+
+export interface TablePositionPayload {
+	table_id: string;
+	x: number;
+	y: number;
+}
+
+export interface TablePositionResponse {
+	pos_x: number;
+	pos_y: number;
+	table_id: string;
+	version: number;
+}
+
+export interface TableProfileResponse {
+	fields: FieldProfileResponse[];
+	row_count: number | null;
+	table_id: string;
+}
+
+export interface TableResponse {
+	color: string;
+	created_at: string;
+	icon: string;
+	metadata: Record<string, unknown>;
+	name: string;
+	pos_x: number;
+	pos_y: number;
+	project_id: string;
+	table_id: string;
+	updated_at: string;
+	version: number;
+}
+
+export interface TableWithFieldsResponse {
+	color: string;
+	created_at: string;
+	fields?: FieldResponse[];
+	icon: string;
+	metadata: Record<string, unknown>;
+	name: string;
+	pos_x: number;
+	pos_y: number;
+	project_id: string;
+	table_id: string;
+	updated_at: string;
+	version: number;
+}
+
+export interface TenantUsageResponse {
+	bytes: number;
+	fields: number;
+	owner_id: string;
+	projects: number;
+	tables: number;
+	username: string;
+}
+
+export interface UpdateBranchRequest {
+	name?: string | null;
+	schema?: CreateSchemaRequest | null;
+	version?: number | null;
+}
+
+export interface UpdateCursorRequest {
+	cursor_x?: number | null;
+	cursor_y?: number | null;
+}
+
+export interface UpdateDocRequest {
+	content?: string;
+	version?: number | null;
+}
+
+export interface UpdateFieldRequest {
+	data_type?: string | null;
+	default_value?: string | null;
+	is_nullable?: boolean | null;
+	is_primary_key?: boolean | null;
+	metadata?: Record<string, unknown> | null;
+	name?: string | null;
+	position?: number | null;
+	version?: number | null;
+}
+
+export interface UpdatePasswordRequest {
+	current_password: string;
+	new_password: string;
+}
+
+export interface UpdateProjectRequest {
+	canvas_data?: string | null;
+	description?: string | null;
+	metadata?: Record<string, unknown> | null;
+	name?: string | null;
+	version?: number | null;
+}
+
+export interface UpdateRegionRequest {
+	color?: string | null;
+	height?: number | null;
+	name?: string | null;
+	pos_x?: number | null;
+	pos_y?: number | null;
+	table_ids?: string[] | null;
+	version?: number | null;
+	width?: number | null;
+}
+
+export interface UpdateRelationshipRequest {
+	label_position?: number | null;
+	metadata?: Record<string, unknown> | null;
+	relation_type?: 'one_to_one' | 'one_to_many' | 'many_to_many' | null;
+	source_anchor?: 'auto' | 'top' | 'right' | 'bottom' | 'left' | null;
+	source_field_id?: string | null;
+	source_table_id?: string | null;
+	target_anchor?: 'auto' | 'top' | 'right' | 'bottom' | 'left' | null;
+	target_field_id?: string | null;
+	target_table_id?: string | null;
+	version?: number | null;
+	waypoints?: DtoPoint[] | null;
+}
+
+export interface UpdateReleaseRequest {
+	locked?: boolean | null;
+	name?: string | null;
+	notes?: string | null;
+	snapshot_id?: string | null;
+}
+
+export interface UpdateSessionRequest {
+	cursor_x?: number | null;
+	cursor_y?: number | null;
+	is_active?: boolean | null;
+	user_color?: string | null;
+}
+
+export interface UpdateSnippetRequest {
+	description?: string | null;
+	name?: string | null;
+	sql?: string | null;
+}
+
+export interface UpdateTablePositionRequest {
+	pos_x?: number;
+	pos_y?: number;
+}
+
+export interface UpdateTableRequest {
+	color?: string | null;
+	icon?: string | null;
+	metadata?: Record<string, unknown> | null;
+	name?: string | null;
+	pos_x?: number | null;
+	pos_y?: number | null;
+	version?: number | null;
+}
+
+export interface UpdateUserPreferencesRequest {
+	canvas_grid_size?: number | null;
+	canvas_show_grid?: boolean | null;
+	canvas_snap_to_grid?: boolean | null;
+	default_database_type?: 'postgresql' | 'mysql' | 'sqlite' | 'sqlserver' | null;
+	email_notifications?: boolean | null;
+	notify_collaborator_joined?: boolean | null;
+	notify_project_shared?: boolean | null;
+	theme?: 'light' | 'dark' | 'system' | null;
+}
+
+export interface UpdateUserRequest {
+	email?: string | null;
+	username?: string | null;
+}
+
+export interface UsageResponse {
+	owned_projects: ProjectUsageResponse[];
+	projects: QuotaUsageResponse;
+}
+
+export interface User {
+	avatar_url: string;
+	collaborated_projects?: Project[];
+	created_at: string;
+	disabled_at: string | null;
+	email: string;
+	id: string;
+	is_service_account: boolean;
+	owned_projects?: Project[];
+	password_reset_required: boolean;
+	role: string;
+	updated_at: string;
+	username: string;
+}
+
+export interface UserCursorPayload {
+	cursor_x: number;
+	cursor_y: number;
+	user_color: string;
+	user_id: string;
+	username: string;
+}
+
+export interface UserJoinedPayload {
+	avatar_url?: string;
+	user_color: string;
+	user_id: string;
+	username: string;
+}
+
+export interface UserLeftPayload {
+	user_id: string;
+}
+
+export interface UserPreferencesResponse {
+	canvas_grid_size: number;
+	canvas_show_grid: boolean;
+	canvas_snap_to_grid: boolean;
+	default_database_type: string;
+	email_notifications: boolean;
+	notify_collaborator_joined: boolean;
+	notify_project_shared: boolean;
+	theme: string;
+	updated_at: string | null;
+}
+
+export interface UserPresencePayload {
+	active_users: ActiveUser[];
+}
+
+export interface UserResponse {
+	avatar_url?: string;
+	email: string;
+	id: string;
+	username: string;
+}
+
+export interface UserSessionResponse {
+	created_at: string;
+	current: boolean;
+	expires_at: string;
+	id: string;
+	impersonated: boolean;
+	ip_address: string;
+	last_seen_at: string;
+	user_agent: string;
+}
+
+export interface ViewportPayload {
+	user_id: string;
+	x: number;
+	y: number;
+	zoom: number;
+}
+
+export interface WebsocketPoint {
+	x: number;
+	y: number;
+}
+
+/** Envelope of every message on the collaboration WebSocket */
+export interface WebSocketMessage<T extends string = string, D = unknown> {
+	type: T;
+	data: D;
+	user_id?: string;
+	project_id?: string;
+	timestamp?: string;
+	/** Orders a project's schema changes, REST and WebSocket alike; set only on those */
+	sequence?: number;
+}
+
+export interface ServerMessagePayloads {
+	access_revoked: AccessRevokedPayload;
+	auth: AuthSuccessPayload;
+	canvas_ping: CanvasPingPayload;
+	canvas_updated: CanvasUpdatedPayload;
+	chat_message: ChatMessagePayload;
+	doc_updated: DocUpdatedPayload;
+	error: ErrorPayload;
+	field_created: FieldPayload;
+	field_deleted: FieldPayload;
+	field_updated: FieldPayload;
+	fields_created: FieldsCreatedPayload;
+	fields_reordered: FieldsReorderedPayload;
+	permissions_updated: PermissionsUpdatedPayload;
+	ping: PingPayload;
+	presence_status: PresenceStatusPayload;
+	region_created: RegionPayload;
+	region_deleted: RegionPayload;
+	region_moved: RegionMovedPayload;
+	region_updated: RegionPayload;
+	relationship_create: RelationshipPayload;
+	relationship_delete: RelationshipPayload;
+	relationship_update: RelationshipPayload;
+	server_shutdown: ServerShutdownPayload;
+	table_created: TablePayload;
+	table_deleted: TablePayload;
+	table_moved: TablePayload;
+	table_updated: TablePayload;
+	user_cursor: UserCursorPayload;
+	user_joined: UserJoinedPayload;
+	user_left: UserLeftPayload;
+	user_presence: UserPresencePayload;
+	viewport_update: ViewportPayload;
+}
+
+export interface ClientMessagePayloads {
+	auth: AuthPayload;
+	canvas_chunk: CanvasChunkPayload;
+	canvas_ping: CanvasPingPayload;
+	canvas_updated: CanvasUpdatedPayload;
+	chat_message: ChatSendPayload;
+	fields_reordered: FieldsReorderedPayload;
+	follow_user: FollowUserPayload;
+	pong: PongPayload;
+	presence_status: PresenceStatusPayload;
+	table_moved: TablePayload;
+	table_updated: TablePayload;
+	user_cursor: UserCursorPayload;
+	viewport_update: ViewportPayload;
+}
+
+/** Messages the server sends, discriminated by type */
+export type ServerMessage = {
+	[K in keyof ServerMessagePayloads]: WebSocketMessage<K, ServerMessagePayloads[K]>;
+}[keyof ServerMessagePayloads];
+
+/** Messages the server accepts from clients, discriminated by type */
+export type ClientMessage = {
+	[K in keyof ClientMessagePayloads]: WebSocketMessage<K, ClientMessagePayloads[K]>;
+}[keyof ClientMessagePayloads];
+
+export type HttpMethod = 'GET' | 'POST' | 'PUT' | 'PATCH' | 'DELETE';
+
+export interface RequestOptions {
+	query?: Record<string, string | number | boolean | undefined>;
+	body?: unknown;
+	/** Resolve with the data and meta fields of a paginated list */
+	page?: boolean;
+}
+
+/** One page of a list; pass meta.next_cursor as the cursor query parameter for the next */
+export interface Page<T> {
+	data: T[];
+	meta: PageMeta;
+}
+
+/**
+ * Sends a request and resolves with the data field of a successful response,
+ * or with a Page when options.page is set.
+ * The path is relative to the API base URL and starts with a slash.
+ */
+export type Transport = <T>(method: HttpMethod, path: string, options: RequestOptions) => Promise<T>;
+
+/** Rejected by fetchTransport when the response is not a success */
+export class ApiRequestError extends Error {
+	constructor(
+		readonly status: number,
+		readonly response?: APIResponse
+	) {
+		super(response?.message || `Request failed with status ${status}`);
+	}
+}
+
+/** Transport built on fetch that sends cookies and echoes the CSRF cookie on writes */
+export function fetchTransport(baseUrl: string, fetchImpl: typeof fetch = fetch): Transport {
+	return async <T>(method: HttpMethod, path: string, options: RequestOptions): Promise<T> => {
+		const url = new URL(baseUrl.replace(/\/$/, '') + path, globalThis.location?.href);
+		for (const [key, value] of Object.entries(options.query ?? {})) {
+			if (value !== undefined) url.searchParams.set(key, String(value));
+		}
+
+		// FormData bodies are sent as multipart/form-data with the boundary set by fetch
+		const form = options.body instanceof FormData;
+		const headers: Record<string, string> = {};
+		if (options.body !== undefined && !form) headers['Content-Type'] = 'application/json';
+		const csrf = globalThis.document?.cookie.match(/(?:^|; )csrf_token=([^;]*)/);
+		if (csrf && method !== 'GET') headers['X-CSRF-Token'] = decodeURIComponent(csrf[1]);
+
+		const response = await fetchImpl(url, {
+			method,
+			headers,
+			credentials: 'include',
+			body: options.body === undefined || form ? (options.body as FormData | undefined) : JSON.stringify(options.body)
+		});
+		const envelope = (await response.json().catch(() => undefined)) as APIResponse | undefined;
+		if (!response.ok || !envelope?.success) {
+			throw new ApiRequestError(response.status, envelope);
+		}
+		if (options.page) {
+			return { data: envelope.data ?? [], meta: envelope.meta ?? {} } as T;
+		}
+		return envelope.data as T;
+	};
+}
+
+/** Typed methods for the JSON and file upload endpoints. Redirect, WebSocket and non-JSON endpoints are left out. */
+export class EzModelClient {
+	constructor(private readonly transport: Transport) {}
+
+	/** Admin action history */
+	adminListAuditLogs(query?: { user_id?: string; actor_id?: string; action?: string; limit?: number; cursor?: string; sort?: 'created_at' | '-created_at' }): Promise<Page<AdminAuditLogResponse>> {
+		return this.transport('GET', `/admin/audit-logs`, { query, page: true });
+	}
+
+	/** Instance-wide statistics */
+	getInstanceStats(query?: { days?: number }): Promise<InstanceStatsResponse> {
+		return this.transport('GET', `/admin/stats`, { query });
+	}
+
+	/** Revoke any API token */
+	adminRevokeToken(tokenId: string): Promise<void> {
+		return this.transport('DELETE', `/admin/tokens/${encodeURIComponent(tokenId)}`, {});
+	}
+
+	/** List users with filters */
+	adminListUsers(query?: { q?: string; role?: string; disabled?: boolean; limit?: number; cursor?: string; sort?: 'created_at' | '-created_at' | 'username' | '-username' | 'email' | '-email' }): Promise<Page<AdminUserResponse>> {
+		return this.transport('GET', `/admin/users`, { query, page: true });
+	}
+
+	/** Disable an account and revoke its sessions */
+	adminDisableUser(userId: string, body: SetUserDisabledRequest): Promise<AdminUserResponse> {
+		return this.transport('POST', `/admin/users/${encodeURIComponent(userId)}/disable`, { body });
+	}
+
+	/** Re-enable an account */
+	adminEnableUser(userId: string, body: SetUserDisabledRequest): Promise<AdminUserResponse> {
+		return this.transport('POST', `/admin/users/${encodeURIComponent(userId)}/enable`, { body });
+	}
+
+	/** Start an audited support session as the user */
+	adminImpersonateUser(userId: string, body: ImpersonateUserRequest): Promise<AdminUserResponse> {
+		return this.transport('POST', `/admin/users/${encodeURIComponent(userId)}/impersonate`, { body });
+	}
+
+	/** Require a new password */
+	adminForcePasswordReset(userId: string): Promise<AdminUserResponse> {
+		return this.transport('POST', `/admin/users/${encodeURIComponent(userId)}/password-reset`, {});
+	}
+
+	/** Grant or remove the admin role */
+	adminSetUserRole(userId: string, body: SetUserRoleRequest): Promise<AdminUserResponse> {
+		return this.transport('PUT', `/admin/users/${encodeURIComponent(userId)}/role`, { body });
+	}
+
+	/** List a user's API tokens */
+	adminListUserTokens(userId: string): Promise<APITokenResponse[]> {
+		return this.transport('GET', `/admin/users/${encodeURIComponent(userId)}/tokens`, {});
+	}
+
+	/** WebSocket hub statistics */
+	getWebSocketStats(): Promise<HubStats> {
+		return this.transport('GET', `/admin/ws/stats`, {});
+	}
+
+	/** Start buying a paid plan */
+	startCheckout(body: CreateCheckoutRequest): Promise<CheckoutResponse> {
+		return this.transport('POST', `/billing/checkout`, { body });
+	}
+
+	/** Plans on offer with their quotas */
+	listPlans(): Promise<PlanResponse[]> {
+		return this.transport('GET', `/billing/plans`, {});
+	}
+
+	/** Plan of the current user */
+	getMySubscription(): Promise<SubscriptionResponse> {
+		return this.transport('GET', `/billing/subscription`, {});
+	}
+
+	/** Stripe webhook */
+	receiveBillingWebhook(): Promise<void> {
+		return this.transport('POST', `/billing/webhook`, {});
+	}
+
+	/** Sign in with email and password */
+	login(body: LoginRequest): Promise<{ user: User }> {
+		return this.transport('POST', `/login`, { body });
+	}
+
+	/** Sign out and clear the session cookies */
+	logout(): Promise<void> {
+		return this.transport('POST', `/logout`, {});
+	}
+
+	/** Get the signed-in user */
+	getCurrentUser(): Promise<UserResponse> {
+		return this.transport('GET', `/me`, {});
+	}
+
+	/** List projects */
+	listProjects(query?: { name?: string; owner_id?: string; limit?: number; cursor?: string; sort?: 'created_at' | '-created_at' | 'updated_at' | '-updated_at' | 'name' | '-name' }): Promise<Page<ProjectSummaryResponse>> {
+		return this.transport('GET', `/projects`, { query, page: true });
+	}
+
+	/** Create a project */
+	createProject(body: CreateProjectRequest): Promise<ProjectSummaryResponse> {
+		return this.transport('POST', `/projects`, { body });
+	}
+
+	/** Projects the user owns or collaborates on */
+	listMyProjects(query?: { tag?: string; starred?: boolean; include?: string }): Promise<MyProjectResponse[]> {
+		return this.transport('GET', `/projects/my`, { query });
+	}
+
+	/** Projects the user opened last */
+	listRecentProjects(query?: { limit?: number }): Promise<RecentProjectResponse[]> {
+		return this.transport('GET', `/projects/recent`, { query });
+	}
+
+	/** Delete a project */
+	deleteProject(projectId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}`, {});
+	}
+
+	/** Get a project with its schema */
+	getProject(projectId: string): Promise<ProjectResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}`, {});
+	}
+
+	/** Change some of a project's properties */
+	patchProject(projectId: string, body: UpdateProjectRequest): Promise<ProjectSummaryResponse> {
+		return this.transport('PATCH', `/projects/${encodeURIComponent(projectId)}`, { body });
+	}
+
+	/** Update a project */
+	updateProject(projectId: string, body: UpdateProjectRequest): Promise<ProjectSummaryResponse> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}`, { body });
+	}
+
+	/** Review a project's schema */
+	reviewSchema(projectId: string): Promise<ReviewCommentResponse[]> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/ai/review`, {});
+	}
+
+	/** List a project's review comments */
+	listReviewComments(projectId: string): Promise<ReviewCommentResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/ai/review-comments`, {});
+	}
+
+	/** Resolve a review comment */
+	resolveReviewComment(projectId: string, commentId: string): Promise<ReviewCommentResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/ai/review-comments/${encodeURIComponent(commentId)}/resolve`, {});
+	}
+
+	/** Bring a database to a project's model */
+	executeApply(projectId: string, body: ExecuteApplyRequest): Promise<ApplyLogResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/apply/execute`, { body });
+	}
+
+	/** Plan bringing a database to a project's model */
+	planApply(projectId: string, body: PlanApplyRequest): Promise<ApplyPlanResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/apply/plan`, { body });
+	}
+
+	/** List a project's branches */
+	listBranches(projectId: string): Promise<BranchResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/branches`, {});
+	}
+
+	/** Branch a project's schema */
+	createBranch(projectId: string, body: CreateBranchRequest): Promise<BranchResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/branches`, { body });
+	}
+
+	/** Delete a branch */
+	deleteBranch(projectId: string, branchId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/branches/${encodeURIComponent(branchId)}`, {});
+	}
+
+	/** Get a branch with its schema */
+	getBranch(projectId: string, branchId: string): Promise<BranchResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/branches/${encodeURIComponent(branchId)}`, {});
+	}
+
+	/** Rename a branch or replace its schema */
+	updateBranch(projectId: string, branchId: string, body: UpdateBranchRequest): Promise<BranchResponse> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/branches/${encodeURIComponent(branchId)}`, { body });
+	}
+
+	/** Diff a project's schema against a branch */
+	diffBranch(projectId: string, branchId: string): Promise<SchemaDiffResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/branches/${encodeURIComponent(branchId)}/diff`, {});
+	}
+
+	/** Merge a branch into its project */
+	mergeBranch(projectId: string, branchId: string): Promise<BranchMergeResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/branches/${encodeURIComponent(branchId)}/merge`, {});
+	}
+
+	/** A project's chat history */
+	listChatMessages(projectId: string, query?: { limit?: number; cursor?: string; sort?: 'created_at' | '-created_at' }): Promise<Page<ChatMessageResponse>> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/chat`, { query, page: true });
+	}
+
+	/** Send a chat message to a project's collaborators */
+	sendChatMessage(projectId: string, body: SendChatMessageRequest): Promise<ChatMessageResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/chat`, { body });
+	}
+
+	/** Add a collaborator */
+	addCollaborator(projectId: string, body: AddCollaboratorRequest): Promise<void> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/collaborators`, { body });
+	}
+
+	/** Remove a collaborator */
+	removeCollaborator(projectId: string, userId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/collaborators/${encodeURIComponent(userId)}`, {});
+	}
+
+	/** Disconnect a collaborator */
+	kickCollaborator(projectId: string, userId: string): Promise<void> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/collaborators/${encodeURIComponent(userId)}/kick`, {});
+	}
+
+	/** Make a collaborator an editor or a viewer */
+	setCollaboratorRole(projectId: string, userId: string, body: SetCollaboratorRoleRequest): Promise<void> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/collaborators/${encodeURIComponent(userId)}/role`, { body });
+	}
+
+	/** Diff a project's schema against another's */
+	compareProjects(projectId: string, otherProjectId: string): Promise<SchemaDiffResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/compare/${encodeURIComponent(otherProjectId)}`, {});
+	}
+
+	/** Remove a project's connection profile */
+	deleteConnectionProfile(projectId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/connection-profile`, {});
+	}
+
+	/** Get how a project's live database is reached */
+	getConnectionProfile(projectId: string): Promise<ConnectionProfileResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/connection-profile`, {});
+	}
+
+	/** Set how a project's live database is reached */
+	setConnectionProfile(projectId: string, body: SetConnectionProfileRequest): Promise<ConnectionProfileResponse> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/connection-profile`, { body });
+	}
+
+	/** Get how much data a project's tables and fields hold */
+	getDataProfile(projectId: string): Promise<DataProfileResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/data-profile`, {});
+	}
+
+	/** Profile a project's live database */
+	startDataProfile(projectId: string): Promise<DataProfileResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/data-profile`, {});
+	}
+
+	/** Get a project's doc */
+	getDoc(projectId: string, query?: { version?: number }): Promise<DocResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/doc`, { query });
+	}
+
+	/** Edit a project's doc */
+	updateDoc(projectId: string, body: UpdateDocRequest): Promise<DocResponse> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/doc`, { body });
+	}
+
+	/** List the versions of a project's doc */
+	listDocRevisions(projectId: string): Promise<DocRevisionResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/doc/revisions`, {});
+	}
+
+	/** List a project's drift checks */
+	listDriftChecks(projectId: string): Promise<DriftCheckResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/drift-checks`, {});
+	}
+
+	/** Check a project's live database for drift */
+	startDriftCheck(projectId: string): Promise<DriftCheckResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/drift-checks`, {});
+	}
+
+	/** Get a drift check */
+	getDriftCheck(projectId: string, checkId: string): Promise<DriftCheckResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/drift-checks/${encodeURIComponent(checkId)}`, {});
+	}
+
+	/** Schema changes of a project after a sequence */
+	listProjectEvents(projectId: string, query?: { since_seq?: number; limit?: number }): Promise<ProjectEventsResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/events`, { query });
+	}
+
+	/** Get a project with its schema and active collaborators */
+	getFullProject(projectId: string): Promise<FullProjectResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/full`, {});
+	}
+
+	/** Check a project's schema for normalization problems */
+	lintSchema(projectId: string): Promise<LintFindingResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/lint`, {});
+	}
+
+	/** Rename a table or field */
+	renameSchemaObject(projectId: string, body: RenameRequest): Promise<RenameResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/refactor/rename`, { body });
+	}
+
+	/** List renamed tables and fields */
+	listRenames(projectId: string): Promise<RenameResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/refactor/renames`, {});
+	}
+
+	/** List regions */
+	listRegions(projectId: string): Promise<RegionResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/regions`, {});
+	}
+
+	/** Create a region */
+	createRegion(projectId: string, body: CreateRegionRequest): Promise<RegionResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/regions`, { body });
+	}
+
+	/** Delete a region */
+	deleteRegion(projectId: string, regionId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/regions/${encodeURIComponent(regionId)}`, {});
+	}
+
+	/** Get a region */
+	getRegion(projectId: string, regionId: string): Promise<RegionResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/regions/${encodeURIComponent(regionId)}`, {});
+	}
+
+	/** Change some of a region's properties */
+	patchRegion(projectId: string, regionId: string, body: UpdateRegionRequest): Promise<RegionResponse> {
+		return this.transport('PATCH', `/projects/${encodeURIComponent(projectId)}/regions/${encodeURIComponent(regionId)}`, { body });
+	}
+
+	/** Update a region */
+	updateRegion(projectId: string, regionId: string, body: UpdateRegionRequest): Promise<RegionResponse> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/regions/${encodeURIComponent(regionId)}`, { body });
+	}
+
+	/** Move a region with its tables */
+	moveRegion(projectId: string, regionId: string, body: MoveRegionRequest): Promise<MoveRegionResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/regions/${encodeURIComponent(regionId)}/move`, { body });
+	}
+
+	/** List relationships */
+	listRelationships(projectId: string): Promise<RelationshipResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/relationships`, {});
+	}
+
+	/** Create a relationship */
+	createRelationship(projectId: string, body: CreateRelationshipRequest): Promise<RelationshipResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/relationships`, { body });
+	}
+
+	/** Delete a relationship */
+	deleteRelationship(projectId: string, relationshipId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/relationships/${encodeURIComponent(relationshipId)}`, {});
+	}
+
+	/** Get a relationship */
+	getRelationship(projectId: string, relationshipId: string): Promise<RelationshipResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/relationships/${encodeURIComponent(relationshipId)}`, {});
+	}
+
+	/** Change some of a relationship's properties */
+	patchRelationship(projectId: string, relationshipId: string, body: UpdateRelationshipRequest): Promise<RelationshipResponse> {
+		return this.transport('PATCH', `/projects/${encodeURIComponent(projectId)}/relationships/${encodeURIComponent(relationshipId)}`, { body });
+	}
+
+	/** Update a relationship */
+	updateRelationship(projectId: string, relationshipId: string, body: UpdateRelationshipRequest): Promise<RelationshipResponse> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/relationships/${encodeURIComponent(relationshipId)}`, { body });
+	}
+
+	/** List a project's releases */
+	listReleases(projectId: string): Promise<ReleaseResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/releases`, {});
+	}
+
+	/** Release a project's schema */
+	createRelease(projectId: string, body: CreateReleaseRequest): Promise<ReleaseResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/releases`, { body });
+	}
+
+	/** Delete a release */
+	deleteRelease(projectId: string, releaseId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/releases/${encodeURIComponent(releaseId)}`, {});
+	}
+
+	/** Get a release */
+	getRelease(projectId: string, releaseId: string): Promise<ReleaseResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/releases/${encodeURIComponent(releaseId)}`, {});
+	}
+
+	/** Edit, lock or unlock a release */
+	updateRelease(projectId: string, releaseId: string, body: UpdateReleaseRequest): Promise<ReleaseResponse> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/releases/${encodeURIComponent(releaseId)}`, { body });
+	}
+
+	/** Add tables, fields and relationships at once */
+	createSchema(projectId: string, body: CreateSchemaRequest, query?: { dry_run?: boolean }): Promise<SchemaResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/schema`, { body, query });
+	}
+
+	/** Add tables defined in a spreadsheet */
+	importSchema(projectId: string, file: Blob, fields?: { conflicts?: SchemaConflictResolution[] }, query?: { dry_run?: boolean }): Promise<SchemaResponse> {
+		const body = new FormData();
+		body.append('file', file);
+		if (fields?.['conflicts'] !== undefined) body.append('conflicts', JSON.stringify(fields['conflicts']));
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/schema/import`, { body, query });
+	}
+
+	/** Propose tables for MongoDB collections */
+	inferSchema(projectId: string, body: InferSchemaRequest): Promise<InferSchemaResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/schema/infer`, { body });
+	}
+
+	/** List service accounts */
+	listServiceAccounts(projectId: string): Promise<ServiceAccountResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/service-accounts`, {});
+	}
+
+	/** Create a service account */
+	createServiceAccount(projectId: string, body: CreateServiceAccountRequest): Promise<ServiceAccountResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/service-accounts`, { body });
+	}
+
+	/** Delete a service account and its tokens */
+	deleteServiceAccount(projectId: string, serviceAccountId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/service-accounts/${encodeURIComponent(serviceAccountId)}`, {});
+	}
+
+	/** List service account tokens */
+	listServiceAccountTokens(projectId: string, serviceAccountId: string): Promise<APITokenResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/service-accounts/${encodeURIComponent(serviceAccountId)}/tokens`, {});
+	}
+
+	/** Create a service account token */
+	createServiceAccountToken(projectId: string, serviceAccountId: string, body: CreateAPITokenRequest): Promise<CreateAPITokenResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/service-accounts/${encodeURIComponent(serviceAccountId)}/tokens`, { body });
+	}
+
+	/** Revoke a service account token */
+	revokeServiceAccountToken(projectId: string, serviceAccountId: string, tokenId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/service-accounts/${encodeURIComponent(serviceAccountId)}/tokens/${encodeURIComponent(tokenId)}`, {});
+	}
+
+	/** List collaboration sessions */
+	listCollaborationSessions(projectId: string): Promise<CollaborationSessionResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/sessions`, {});
+	}
+
+	/** Create a collaboration session */
+	createCollaborationSession(projectId: string, body: CreateSessionRequest): Promise<CollaborationSessionResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/sessions`, { body });
+	}
+
+	/** List active collaboration sessions */
+	listActiveCollaborationSessions(projectId: string): Promise<CollaborationSessionResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/sessions/active`, {});
+	}
+
+	/** Delete a collaboration session */
+	deleteCollaborationSession(projectId: string, sessionId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/sessions/${encodeURIComponent(sessionId)}`, {});
+	}
+
+	/** Get a collaboration session */
+	getCollaborationSession(projectId: string, sessionId: string): Promise<CollaborationSessionResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/sessions/${encodeURIComponent(sessionId)}`, {});
+	}
+
+	/** Update a collaboration session */
+	updateCollaborationSession(projectId: string, sessionId: string, body: UpdateSessionRequest): Promise<CollaborationSessionResponse> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/sessions/${encodeURIComponent(sessionId)}`, { body });
+	}
+
+	/** Update the cursor position */
+	updateCollaborationCursor(projectId: string, sessionId: string, body: UpdateCursorRequest): Promise<void> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/sessions/${encodeURIComponent(sessionId)}/cursor`, { body });
+	}
+
+	/** Mark a collaboration session inactive */
+	setCollaborationSessionInactive(projectId: string, sessionId: string): Promise<void> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/sessions/${encodeURIComponent(sessionId)}/inactive`, {});
+	}
+
+	/** Get how many snapshots a project keeps */
+	getSnapshotRetention(projectId: string): Promise<SnapshotRetentionResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/snapshot-retention`, {});
+	}
+
+	/** Change how many snapshots a project keeps */
+	setSnapshotRetention(projectId: string, body: SetSnapshotRetentionRequest): Promise<SnapshotRetentionResponse> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/snapshot-retention`, { body });
+	}
+
+	/** List a project's snapshots */
+	listSnapshots(projectId: string): Promise<SnapshotResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/snapshots`, {});
+	}
+
+	/** List a project's snippets */
+	listSnippets(projectId: string): Promise<SnippetResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/snippets`, {});
+	}
+
+	/** Save SQL with a project */
+	createSnippet(projectId: string, body: CreateSnippetRequest): Promise<SnippetResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/snippets`, { body });
+	}
+
+	/** Delete a snippet */
+	deleteSnippet(projectId: string, snippetId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/snippets/${encodeURIComponent(snippetId)}`, {});
+	}
+
+	/** Get a snippet */
+	getSnippet(projectId: string, snippetId: string): Promise<SnippetResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/snippets/${encodeURIComponent(snippetId)}`, {});
+	}
+
+	/** Update a snippet */
+	updateSnippet(projectId: string, snippetId: string, body: UpdateSnippetRequest): Promise<SnippetResponse> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/snippets/${encodeURIComponent(snippetId)}`, { body });
+	}
+
+	/** Remove a project from the user's favorites */
+	unstarProject(projectId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/star`, {});
+	}
+
+	/** Add a project to the user's favorites */
+	starProject(projectId: string): Promise<void> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/star`, {});
+	}
+
+	/** Suggest missing relationships */
+	suggestRelationships(projectId: string): Promise<RelationshipSuggestionResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/suggestions/relationships`, {});
+	}
+
+	/** Create suggested relationships */
+	acceptRelationshipSuggestions(projectId: string, body: AcceptRelationshipSuggestionsRequest): Promise<RelationshipResponse[]> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/suggestions/relationships`, { body });
+	}
+
+	/** List tables */
+	listTables(projectId: string, query?: { name?: string; limit?: number; cursor?: string; sort?: 'created_at' | '-created_at' | 'updated_at' | '-updated_at' | 'name' | '-name' }): Promise<Page<TableResponse>> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/tables`, { query, page: true });
+	}
+
+	/** Create a table */
+	createTable(projectId: string, body: CreateTableRequest): Promise<TableResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/tables`, { body });
+	}
+
+	/** Delete a table */
+	deleteTable(projectId: string, tableId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}`, {});
+	}
+
+	/** Get a table */
+	getTable(projectId: string, tableId: string): Promise<TableResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}`, {});
+	}
+
+	/** Change some of a table's properties */
+	patchTable(projectId: string, tableId: string, body: UpdateTableRequest): Promise<TableResponse> {
+		return this.transport('PATCH', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}`, { body });
+	}
+
+	/** Update a table */
+	updateTable(projectId: string, tableId: string, body: UpdateTableRequest): Promise<TableResponse> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}`, { body });
+	}
+
+	/** List fields */
+	listFields(projectId: string, tableId: string): Promise<FieldResponse[]> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}/fields`, {});
+	}
+
+	/** Create a field */
+	createField(projectId: string, tableId: string, body: CreateFieldRequest): Promise<FieldResponse> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}/fields`, { body });
+	}
+
+	/** Create several fields at once */
+	createFields(projectId: string, tableId: string, body: BulkCreateFieldsRequest): Promise<FieldResponse[]> {
+		return this.transport('POST', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}/fields/bulk`, { body });
+	}
+
+	/** Reorder fields */
+	reorderFields(projectId: string, tableId: string, body: ReorderFieldsRequest): Promise<FieldResponse[]> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}/fields/reorder`, { body });
+	}
+
+	/** Delete a field */
+	deleteField(projectId: string, tableId: string, fieldId: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}/fields/${encodeURIComponent(fieldId)}`, {});
+	}
+
+	/** Get a field */
+	getField(projectId: string, tableId: string, fieldId: string): Promise<FieldResponse> {
+		return this.transport('GET', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}/fields/${encodeURIComponent(fieldId)}`, {});
+	}
+
+	/** Change some of a field's properties */
+	patchField(projectId: string, tableId: string, fieldId: string, body: UpdateFieldRequest): Promise<FieldResponse> {
+		return this.transport('PATCH', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}/fields/${encodeURIComponent(fieldId)}`, { body });
+	}
+
+	/** Update a field */
+	updateField(projectId: string, tableId: string, fieldId: string, body: UpdateFieldRequest): Promise<FieldResponse> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}/fields/${encodeURIComponent(fieldId)}`, { body });
+	}
+
+	/** Move a table on the canvas */
+	updateTablePosition(projectId: string, tableId: string, body: UpdateTablePositionRequest): Promise<void> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/tables/${encodeURIComponent(tableId)}/position`, { body });
+	}
+
+	/** Remove a label from a project */
+	removeProjectTag(projectId: string, tag: string): Promise<void> {
+		return this.transport('DELETE', `/projects/${encodeURIComponent(projectId)}/tags/${encodeURIComponent(tag)}`, {});
+	}
+
+	/** Label a project */
+	addProjectTag(projectId: string, tag: string): Promise<void> {
+		return this.transport('PUT', `/projects/${encodeURIComponent(projectId)}/tags/${encodeURIComponent(tag)}`, {});
+	}
+
+	/** Rotate the session cookies */
+	refreshToken(): Promise<void> {
+		return this.transport('POST', `/refresh-token`, {});
+	}
+
+	/** Create an account */
+	register(body: CreateUserRequest): Promise<UserResponse> {
+		return this.transport('POST', `/register`, { body });
+	}
+
+	/** Search projects, tables and fields by name */
+	search(query?: { q?: string; project_id?: string; type?: string; limit?: number }): Promise<SearchResultResponse[]> {
+		return this.transport('GET', `/search`, { query });
+	}
+
+	/** List own tokens */
+	listAPITokens(): Promise<APITokenResponse[]> {
+		return this.transport('GET', `/tokens`, {});
+	}
+
+	/** Create a personal access token */
+	createAPIToken(body: CreateAPITokenRequest): Promise<CreateAPITokenResponse> {
+		return this.transport('POST', `/tokens`, { body });
+	}
+
+	/** Revoke own token */
+	revokeAPIToken(tokenId: string): Promise<void> {
+		return this.transport('DELETE', `/tokens/${encodeURIComponent(tokenId)}`, {});
+	}
+
+	/** List users */
+	listUsers(query?: { q?: string; limit?: number; cursor?: string; sort?: 'created_at' | '-created_at' | 'username' | '-username' | 'email' | '-email' }): Promise<Page<UserResponse>> {
+		return this.transport('GET', `/users`, { query, page: true });
+	}
+
+	/** Delete the current user's account */
+	deleteMyAccount(body: DeleteAccountRequest): Promise<void> {
+		return this.transport('DELETE', `/users/me`, { body });
+	}
+
+	/** Remove the current user's avatar */
+	deleteMyAvatar(): Promise<UserResponse> {
+		return this.transport('DELETE', `/users/me/avatar`, {});
+	}
+
+	/** Set the current user's avatar */
+	uploadMyAvatar(avatar: Blob): Promise<UserResponse> {
+		const body = new FormData();
+		body.append('avatar', avatar);
+		return this.transport('POST', `/users/me/avatar`, { body });
+	}
+
+	/** Archive of the current user's data */
+	getMyDataExport(): Promise<DataExportResponse> {
+		return this.transport('GET', `/users/me/export`, {});
+	}
+
+	/** Build a fresh archive of the current user's data */
+	startMyDataExport(): Promise<DataExportResponse> {
+		return this.transport('POST', `/users/me/export`, {});
+	}
+
+	/** Settings of the current user */
+	getMyPreferences(): Promise<UserPreferencesResponse> {
+		return this.transport('GET', `/users/me/preferences`, {});
+	}
+
+	/** Change some of the current user's settings */
+	patchMyPreferences(body: UpdateUserPreferencesRequest): Promise<UserPreferencesResponse> {
+		return this.transport('PATCH', `/users/me/preferences`, { body });
+	}
+
+	/** Recent login attempts of the current user */
+	getLoginHistory(): Promise<LoginEventResponse[]> {
+		return this.transport('GET', `/users/me/security/logins`, {});
+	}
+
+	/** Sign out everywhere else */
+	revokeOtherSessions(): Promise<void> {
+		return this.transport('DELETE', `/users/me/sessions`, {});
+	}
+
+	/** Devices the current user is signed in on */
+	listMySessions(): Promise<UserSessionResponse[]> {
+		return this.transport('GET', `/users/me/sessions`, {});
+	}
+
+	/** Sign out one device */
+	revokeSession(sessionId: string): Promise<void> {
+		return this.transport('DELETE', `/users/me/sessions/${encodeURIComponent(sessionId)}`, {});
+	}
+
+	/** Usage of the current user against the quotas */
+	getMyUsage(): Promise<UsageResponse> {
+		return this.transport('GET', `/users/me/usage`, {});
+	}
+
+	/** Delete a user */
+	deleteUser(userId: string): Promise<void> {
+		return this.transport('DELETE', `/users/${encodeURIComponent(userId)}`, {});
+	}
+
+	/** Get a user */
+	getUser(userId: string): Promise<UserResponse> {
+		return this.transport('GET', `/users/${encodeURIComponent(userId)}`, {});
+	}
+
+	/** Update a user */
+	updateUser(userId: string, body: UpdateUserRequest): Promise<UserResponse> {
+		return this.transport('PUT', `/users/${encodeURIComponent(userId)}`, { body });
+	}
+
+	/** Change a password */
+	updatePassword(userId: string, body: UpdatePasswordRequest): Promise<void> {
+		return this.transport('PUT', `/users/${encodeURIComponent(userId)}/password`, { body });
+	}
+}
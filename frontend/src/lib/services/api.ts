@@ -23,7 +23,11 @@ class ApiClient {
 				'Content-Type': 'application/json'
 			},
 			timeout: 10000,
-			withCredentials: true // Include cookies in requests
+			withCredentials: true, // Include cookies in requests
+			// Echo the CSRF cookie in a header, as the backend requires for cookie-authenticated writes
+			xsrfCookieName: 'csrf_token',
+			xsrfHeaderName: 'X-CSRF-Token',
+			withXSRFToken: true
 		});
 
 		// Response interceptor for error handling
@@ -116,6 +120,23 @@ class ApiClient {
 		return response.data;
 	}
 
+	// Follows next_cursor until the last page of a paginated list
+	async getAllPages<T>(url: string): Promise<T[]> {
+		const items: T[] = [];
+		const separator = url.includes('?') ? '&' : '?';
+		let cursor: string | undefined;
+		do {
+			const query = `limit=100${cursor ? `&cursor=${encodeURIComponent(cursor)}` : ''}`;
+			const response = await this.get<T[]>(`${url}${separator}${query}`);
+			if (!response.success) {
+				throw new Error(response.message || 'Failed to load list');
+			}
+			items.push(...(response.data ?? []));
+			cursor = response.meta?.next_cursor;
+		} while (cursor);
+		return items;
+	}
+
 	async post<T>(url: string, data?: any): Promise<ApiResponse<T>> {
 		const response = await this.client.post<ApiResponse<T>>(url, data);
 		return response.data;
@@ -126,7 +147,15 @@ class ApiClient {
 		return response.data;
 	}
 
-	async delete<T>(url: string): Promise<ApiResponse<T>> {
+	// Sends a JSON Merge Patch: properties left out are unchanged, null clears nullable ones
+	async patch<T>(url: string, data: Record<string, unknown>): Promise<ApiResponse<T>> {
+		const response = await this.client.patch<ApiResponse<T>>(url, data, {
+			headers: { 'Content-Type': 'application/merge-patch+json' }
+		});
+		return response.data;
+	}
+
+		async delete<T>(url: string): Promise<ApiResponse<T>> {
 		const response = await this.client.delete<ApiResponse<T>>(url);
 		return response.data;
 	}
@@ -1,6 +1,7 @@
 import { apiClient } from './api';
 import type {
 	Project,
+	FullProject,
 	CreateProjectRequest,
 	UpdateProjectRequest,
 	Table,
@@ -10,6 +11,10 @@ import type {
 	Relationship,
 	CreateRelationshipRequest,
 	UpdateRelationshipRequest,
+	Region,
+	CreateRegionRequest,
+	UpdateRegionRequest,
+	RegionMove,
 	Field,
 	CreateFieldRequest,
 	UpdateFieldRequest
@@ -32,6 +37,15 @@ export class ProjectService {
 		throw new Error(response.message || 'Failed to fetch project');
 	}
 
+	// Schema and active collaborators in one request, for opening the canvas
+	async getFullProject(id: string): Promise<FullProject> {
+		const response = await apiClient.get<FullProject>(`/projects/${id}/full`);
+		if (response.success && response.data) {
+			return response.data;
+		}
+		throw new Error(response.message || 'Failed to fetch project');
+	}
+
 	async createProject(projectData: CreateProjectRequest): Promise<Project> {
 		const response = await apiClient.post<Project>('/projects', projectData);
 		if (response.success && response.data) {
@@ -83,11 +97,7 @@ export class ProjectService {
 	}
 
 	async getProjectTables(projectId: string): Promise<Table[]> {
-		const response = await apiClient.get<Table[]>(`/projects/${projectId}/tables`);
-		if (response.success && response.data) {
-			return response.data;
-		}
-		return [];
+		return apiClient.getAllPages<Table>(`/projects/${projectId}/tables`);
 	}
 
 	async getTable(projectId: string, tableId: string): Promise<Table> {
@@ -206,6 +216,57 @@ export class ProjectService {
 		}
 	}
 
+	// Region Management Methods
+	async getProjectRegions(projectId: string): Promise<Region[]> {
+		const response = await apiClient.get<Region[]>(`/projects/${projectId}/regions`);
+		if (response.success && response.data) {
+			return response.data;
+		}
+		return [];
+	}
+
+	async createRegion(projectId: string, regionData: CreateRegionRequest): Promise<Region> {
+		const response = await apiClient.post<Region>(`/projects/${projectId}/regions`, regionData);
+		if (response.success && response.data) {
+			return response.data;
+		}
+		throw new Error(response.message || 'Failed to create region');
+	}
+
+	async updateRegion(
+		projectId: string,
+		regionId: string,
+		regionData: UpdateRegionRequest
+	): Promise<Region> {
+		const response = await apiClient.put<Region>(
+			`/projects/${projectId}/regions/${regionId}`,
+			regionData
+		);
+		if (response.success && response.data) {
+			return response.data;
+		}
+		throw new Error(response.message || 'Failed to update region');
+	}
+
+	// Moves the region and its tables by the same offset on the server, in one go
+	async moveRegion(projectId: string, regionId: string, dx: number, dy: number): Promise<RegionMove> {
+		const response = await apiClient.post<RegionMove>(
+			`/projects/${projectId}/regions/${regionId}/move`,
+			{ dx, dy }
+		);
+		if (response.success && response.data) {
+			return response.data;
+		}
+		throw new Error(response.message || 'Failed to move region');
+	}
+
+	async deleteRegion(projectId: string, regionId: string): Promise<void> {
+		const response = await apiClient.delete(`/projects/${projectId}/regions/${regionId}`);
+		if (!response.success) {
+			throw new Error(response.message || 'Failed to delete region');
+		}
+	}
+
 	// Field Management Methods
 	async createField(
 		projectId: string,
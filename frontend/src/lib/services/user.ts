@@ -3,21 +3,18 @@ import type { User } from '$lib/types/models';
 
 export class UserService {
 	async getAllUsers(): Promise<User[]> {
-		const response = await apiClient.get<User[]>('/users');
+		return apiClient.getAllPages<User>('/users');
+	}
+
+	// First page of users whose email or username contains the query
+	async searchUsers(query: string, limit = 20): Promise<User[]> {
+		const params = new URLSearchParams({ q: query.trim(), limit: String(limit), sort: 'username' });
+		const response = await apiClient.get<User[]>(`/users?${params}`);
 		if (response.success && response.data) {
 			return response.data;
 		}
 		return [];
 	}
-
-	async searchUsers(query: string): Promise<User[]> {
-		const users = await this.getAllUsers();
-		return users.filter(
-			(user) =>
-				(user.email || '').toLowerCase().includes(query.toLowerCase()) ||
-				(user.username || '').toLowerCase().includes(query.toLowerCase())
-		);
-	}
 }
 
 export const userService = new UserService();
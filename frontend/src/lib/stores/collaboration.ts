@@ -28,11 +28,15 @@ export interface ActivityEvent {
 		| 'table_update'
 		| 'table_deleted'
 		| 'field_created'
+		| 'fields_created'
 		| 'field_updated'
 		| 'field_deleted'
 		| 'relationship_create'
 		| 'relationship_update'
-		| 'relationship_delete';
+		| 'relationship_delete'
+		| 'region_created'
+		| 'region_updated'
+		| 'region_deleted';
 	message: string;
 	timestamp: number;
 	data?: any;
@@ -59,12 +63,17 @@ function createCollaborationStore() {
 
 	let wsClient: WebSocketClient | null = null;
 
+	// Sequence of the last schema change applied, sent with reorders so the
+	// server can tell whether they were made without seeing a later one
+	let lastSequence = 0;
+
 	return {
 		subscribe,
 
 		// Connect to WebSocket for collaboration
-		async connect(projectId: string) {
+		async connect(projectId: string, sequence = 0) {
 			update((state) => ({ ...state, connectionStatus: 'connecting' }));
+			lastSequence = sequence;
 
 			try {
 				// Create WebSocket client with callbacks set up front
@@ -161,6 +170,16 @@ function createCollaborationStore() {
 			}
 		},
 
+		// Move a table's fields into the given order. Every client, this one
+		// included, applies the order the server saved once it arrives.
+		reorderFields(tableId: string, fieldIds: string[]) {
+			this.sendSchemaEvent('fields_reordered', {
+				table_id: tableId,
+				fields: fieldIds.map((fieldId, position) => ({ field_id: fieldId, position })),
+				base_sequence: lastSequence
+			});
+		},
+
 		// Clear activity events
 		clearActivity() {
 			update((state) => ({ ...state, activityEvents: [] }));
@@ -180,6 +199,10 @@ function createCollaborationStore() {
 		const currentUser = get(authStore).user;
 		const isOwnMessage = currentUser && message.user_id === currentUser.id;
 
+		if (message.sequence > lastSequence) {
+			lastSequence = message.sequence;
+		}
+
 		switch (message.type) {
 			case 'user_joined':
 				// Handle backend UserJoinedPayload structure
@@ -187,6 +210,7 @@ function createCollaborationStore() {
 					id: message.data.user_id,
 					username: message.data.username || 'Unknown User',
 					email: '', // Not provided in the payload
+					avatar: message.data.avatar_url,
 					lastActivity: Date.now()
 				};
 
@@ -225,6 +249,7 @@ function createCollaborationStore() {
 						id: user.user_id,
 						username: user.username || 'Unknown User',
 						email: '', // Not provided in the payload
+						avatar: user.avatar_url,
 						lastActivity: Date.now()
 					})) || [];
 
@@ -383,7 +408,8 @@ function createCollaborationStore() {
 						target_table_id: message.data.target_table_id,
 						source_field_id: message.data.source_field_id,
 						target_field_id: message.data.target_field_id,
-						relation_type: message.data.relation_type
+						relation_type: message.data.relation_type,
+						route: message.data.route
 					});
 				}
 
@@ -410,7 +436,8 @@ function createCollaborationStore() {
 				// Update relationship edge in flow store for real-time collaboration
 				if (message.data.relationship_id) {
 					flowStore.updateLocalRelationshipEdge(message.data.relationship_id, {
-						relation_type: message.data.relation_type
+						relation_type: message.data.relation_type,
+						route: message.data.route
 					});
 				}
 
@@ -509,6 +536,49 @@ function createCollaborationStore() {
 				});
 				break;
 
+			case 'fields_created':
+				// Handle several fields added at once - append them all, then one activity
+				if (message.data.table_id && Array.isArray(message.data.fields) && !isOwnMessage) {
+					const tableNode = get(flowStore).nodes.find((node) => node.id === message.data.table_id);
+					if (tableNode) {
+						const now = new Date().toISOString();
+						const newFields = message.data.fields.map((field: any) => ({
+							field_id: field.field_id,
+							table_id: field.table_id,
+							name: field.name,
+							data_type: field.data_type,
+							is_primary_key: field.is_primary_key || false,
+							is_nullable: field.is_nullable,
+							default_value: field.default_value || '',
+							position: field.position || 0,
+							created_at: now,
+							updated_at: now
+						}));
+						flowStore.updateTableNode(tableNode.id, {
+							fields: [...tableNode.data.fields, ...newFields]
+						});
+					}
+				}
+
+				update((state) => {
+					const userName = getUsernameFromId(message.user_id, state);
+					const newEvent: ActivityEvent = {
+						id: crypto.randomUUID(),
+						type: message.type,
+						userId: message.user_id,
+						userName: userName,
+						message: generateActivityMessage(message.type, message.data),
+						data: message.data,
+						timestamp: Date.now()
+					};
+
+					return {
+						...state,
+						activityEvents: [newEvent, ...state.activityEvents.slice(0, 49)] // Keep last 50 events
+					};
+				});
+				break;
+
 			case 'field_updated':
 				// Handle field update - update field in table and create activity
 				if (message.data.table_id && message.data.field_id) {
@@ -599,6 +669,14 @@ function createCollaborationStore() {
 				break;
 
 			case 'table_updated':
+				// Saved updates carry the table's colors and icon; drags relayed from clients do not
+				if (message.data.table_id && message.data.appearance) {
+					flowStore.updateTableNode(message.data.table_id, {
+						color: message.data.appearance.color,
+						icon: message.data.appearance.icon
+					});
+				}
+
 				// Handle table position updates from other users
 				if (message.data.table_id && message.data.x !== undefined && message.data.y !== undefined) {
 					// Update table position using static import
@@ -624,6 +702,51 @@ function createCollaborationStore() {
 				}
 				break;
 
+			case 'fields_reordered':
+				// The server's order wins, including over a reorder of our own that lost
+				if (message.data.table_id) {
+					const tableNode = get(flowStore).nodes.find((node) => node.id === message.data.table_id);
+					if (tableNode) {
+						const positions = new Map<string, number>(
+							message.data.fields.map((field: any) => [field.field_id, field.position])
+						);
+						const reordered = tableNode.data.fields
+							.map((field) => ({ ...field, position: positions.get(field.field_id) ?? field.position }))
+							.sort((a, b) => a.position - b.position);
+						flowStore.updateTableNode(tableNode.id, { fields: reordered });
+					}
+				}
+				break;
+
+			case 'region_moved':
+				// Tables move with their region; the server sends where each one ended up
+				for (const table of message.data.tables ?? []) {
+					flowStore.updateTablePositionFromExternal(table.table_id, { x: table.x, y: table.y });
+				}
+				break;
+
+			case 'region_created':
+			case 'region_updated':
+			case 'region_deleted':
+				update((state) => {
+					const userName = getUsernameFromId(message.user_id, state);
+					const newEvent: ActivityEvent = {
+						id: crypto.randomUUID(),
+						type: message.type,
+						userId: message.user_id,
+						userName: userName,
+						message: generateActivityMessage(message.type, message.data),
+						data: message.data,
+						timestamp: Date.now()
+					};
+
+					return {
+						...state,
+						activityEvents: [newEvent, ...state.activityEvents.slice(0, 49)] // Keep last 50 events
+					};
+				});
+				break;
+
 			default:
 				console.warn('Unknown message type:', message.type);
 		}
@@ -652,6 +775,8 @@ function createCollaborationStore() {
 				return `deleted table "${data.name}"`;
 			case 'field_created':
 				return `added field "${data.name}" to table`;
+			case 'fields_created':
+				return `added ${data.fields?.length ?? 0} fields to table`;
 			case 'field_updated':
 				return `updated field "${data.name}" in table`;
 			case 'field_deleted':
@@ -662,6 +787,12 @@ function createCollaborationStore() {
 				return `updated relationship between "${data.from_table}" and "${data.to_table}"`;
 			case 'relationship_delete':
 				return `removed relationship between "${data.from_table}" and "${data.to_table}"`;
+			case 'region_created':
+				return `created region "${data.name}"`;
+			case 'region_updated':
+				return `updated region "${data.name}"`;
+			case 'region_deleted':
+				return `deleted region "${data.name}"`;
 			default:
 				return 'made a change';
 		}
@@ -1,7 +1,7 @@
 import { writable } from 'svelte/store';
 import type { Node, Edge } from '@xyflow/svelte';
 import { projectService } from '$lib/services/project';
-import type { Table } from '$lib/types/models';
+import type { EdgeRoute, Relationship, Table, TableIcon } from '$lib/types/models';
 
 export interface Position {
 	x: number;
@@ -15,6 +15,8 @@ export interface TableNode extends Node {
 		name: string;
 		fields: TableField[];
 		position: Position;
+		color?: string;
+		icon?: TableIcon | '';
 	};
 }
 
@@ -29,6 +31,17 @@ export interface RelationshipEdge extends Edge {
 		source_field_id: string;
 		target_field_id: string;
 		relation_type: 'one_to_one' | 'one_to_many' | 'many_to_many';
+		route?: EdgeRoute; // Left out, the edge is routed automatically
+	};
+}
+
+// edgeRoute picks the route of a relationship returned by the API
+export function edgeRoute(relationship: Relationship): EdgeRoute {
+	return {
+		source_anchor: relationship.source_anchor,
+		target_anchor: relationship.target_anchor,
+		waypoints: relationship.waypoints ?? [],
+		label_position: relationship.label_position
 	};
 }
 
@@ -108,7 +121,9 @@ function createFlowStore() {
 				const backendTable: Table = await projectService.createTable(projectId, {
 					name: table.name,
 					pos_x: position.x,
-					pos_y: position.y
+					pos_y: position.y,
+					color: table.color || undefined,
+					icon: table.icon || undefined
 				});
 
 				// Create the flow node with backend-generated ID
@@ -120,7 +135,9 @@ function createFlowStore() {
 						table_id: backendTable.table_id,
 						name: backendTable.name,
 						fields: table.fields || [],
-						position
+						position,
+						color: backendTable.color,
+						icon: backendTable.icon
 					}
 				};
 
@@ -236,7 +253,9 @@ function createFlowStore() {
 					table_id: tableData.table_id,
 					name: tableData.name,
 					fields: tableData.fields || [],
-					position: position
+					position: position,
+					color: tableData.appearance?.color,
+					icon: tableData.appearance?.icon
 				}
 			};
 
@@ -302,7 +321,8 @@ function createFlowStore() {
 					target_table_id: newRelationship.target_table_id,
 					source_field_id: newRelationship.source_field_id,
 					target_field_id: newRelationship.target_field_id,
-					relation_type: newRelationship.relation_type
+					relation_type: newRelationship.relation_type,
+					route: edgeRoute(newRelationship)
 				};
 
 				// Add to local store
@@ -359,7 +379,8 @@ function createFlowStore() {
 									...edge,
 									data: {
 										...edge.data,
-										relation_type: updatedRelationship.relation_type
+										relation_type: updatedRelationship.relation_type,
+										route: edgeRoute(updatedRelationship)
 									}
 								}
 							: edge
@@ -370,7 +391,8 @@ function createFlowStore() {
 									...state.selectedEdge,
 									data: {
 										...state.selectedEdge.data,
-										relation_type: updatedRelationship.relation_type
+										relation_type: updatedRelationship.relation_type,
+										route: edgeRoute(updatedRelationship)
 									}
 								}
 							: state.selectedEdge
@@ -418,23 +440,27 @@ function createFlowStore() {
 			}));
 		},
 
+		// Convert backend relationships to frontend format and add them to the store
+		addRelationshipEdges(relationships: Relationship[]) {
+			for (const rel of relationships) {
+				const edgeData = {
+					relationship_id: rel.relationship_id,
+					source_table_id: rel.source_table_id,
+					target_table_id: rel.target_table_id,
+					source_field_id: rel.source_field_id,
+					target_field_id: rel.target_field_id,
+					relation_type: rel.relation_type,
+					route: edgeRoute(rel)
+				};
+				this.addLocalRelationshipEdge(edgeData);
+			}
+		},
+
 		// Load relationships from backend and convert to frontend format
 		async loadProjectRelationships(projectId: string): Promise<void> {
 			try {
 				const relationships = await projectService.getProjectRelationships(projectId);
-
-				// Convert backend format to frontend format and add to store
-				for (const rel of relationships) {
-					const edgeData = {
-						relationship_id: rel.relationship_id,
-						source_table_id: rel.source_table_id,
-						target_table_id: rel.target_table_id,
-						source_field_id: rel.source_field_id,
-						target_field_id: rel.target_field_id,
-						relation_type: rel.relation_type
-					};
-					this.addLocalRelationshipEdge(edgeData);
-				}
+				this.addRelationshipEdges(relationships);
 
 				console.log(`Loaded ${relationships.length} relationships for project ${projectId}`);
 			} catch (error) {
@@ -51,9 +51,18 @@ function createProjectStore() {
 			}
 		},
 
-		// Load project (alias for setCurrentProject for consistency)
+		// Load project with its full schema, for the designer
 		async loadProject(projectId: string) {
-			return await this.setCurrentProject(projectId);
+			update((state) => ({ ...state, isLoading: true }));
+			try {
+				const project = await projectService.getFullProject(projectId);
+				update((state) => ({ ...state, currentProject: project, isLoading: false }));
+				return project;
+			} catch (error) {
+				console.error('Failed to load project:', error);
+				update((state) => ({ ...state, isLoading: false }));
+				throw error;
+			}
 		},
 
 		// Add new project to list
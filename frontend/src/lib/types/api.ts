@@ -2,6 +2,12 @@ export interface ApiResponse<T = any> {
 	success: boolean;
 	message: string;
 	data?: T;
+	meta?: PageMeta;
+}
+
+export interface PageMeta {
+	next_cursor?: string;
+	total?: number;
 }
 
 export interface LoginRequest {
@@ -2,17 +2,51 @@ export interface User {
 	id: string;
 	email: string;
 	username: string;
+	avatar_url?: string;
 }
 
+// Settings stored server-side so they follow the user across devices
+export interface UserPreferences {
+	theme: 'light' | 'dark' | 'system';
+	default_database_type: 'postgresql' | 'mysql' | 'sqlite' | 'sqlserver';
+	canvas_show_grid: boolean;
+	canvas_snap_to_grid: boolean;
+	canvas_grid_size: number;
+	notify_collaborator_joined: boolean;
+	notify_project_shared: boolean;
+	email_notifications: boolean;
+	updated_at: string | null;
+}
+
+// Icons a table can show next to its name, as named by lucide
+export type TableIcon =
+	| 'database'
+	| 'users'
+	| 'user'
+	| 'credit-card'
+	| 'shopping-cart'
+	| 'package'
+	| 'file-text'
+	| 'settings'
+	| 'lock'
+	| 'globe'
+	| 'calendar'
+	| 'mail'
+	| 'tag'
+	| 'activity';
+
 export interface Table {
 	table_id: string;
 	name: string;
 	project_id: string;
 	pos_x: number;
 	pos_y: number;
+	color: string; // Hex color of the header, or empty for the default
+	icon: TableIcon | '';
 	fields?: Field[];
 	created_at: string;
 	updated_at: string;
+	version: number;
 }
 
 export interface Field {
@@ -26,9 +60,21 @@ export interface Field {
 	position: number;
 	created_at: string;
 	updated_at: string;
+	version: number;
+}
+
+// Side of a table an edge leaves or enters by; auto lets the canvas choose
+export type EdgeAnchor = 'auto' | 'top' | 'right' | 'bottom' | 'left';
+
+// How a relationship's edge is drawn, shared by every collaborator
+export interface EdgeRoute {
+	source_anchor: EdgeAnchor;
+	target_anchor: EdgeAnchor;
+	waypoints: { x: number; y: number }[];
+	label_position: number; // Fraction of the edge's length from the source
 }
 
-export interface Relationship {
+export interface Relationship extends EdgeRoute {
 	relationship_id: string;
 	project_id: string;
 	source_table_id: string;
@@ -38,6 +84,23 @@ export interface Relationship {
 	relation_type: 'one_to_one' | 'one_to_many' | 'many_to_many';
 	created_at: string;
 	updated_at: string;
+	version: number;
+}
+
+// Named, colored area of the canvas grouping tables; a table is in at most one region
+export interface Region {
+	region_id: string;
+	project_id: string;
+	name: string;
+	color: string;
+	pos_x: number;
+	pos_y: number;
+	width: number;
+	height: number;
+	table_ids: string[];
+	created_at: string;
+	updated_at: string;
+	version: number;
 }
 
 export interface Project {
@@ -49,12 +112,29 @@ export interface Project {
 	canvas_data: string;
 	created_at: string;
 	updated_at: string;
+	version: number;
+	sequence?: number; // Of the last change included; later WebSocket events have higher ones
+	tags?: string[];
+	starred?: boolean; // Only on the current user's project list
+	viewed_at?: string; // Only on the recently viewed projects
 	owner?: User;
 	collaborators?: User[];
 	tables?: Table[];
 	relationships?: Relationship[];
 }
 
+// Project with its schema and the users connected to it, from /projects/{id}/full
+export interface FullProject extends Project {
+	active_collaborators: ActiveCollaborator[];
+}
+
+export interface ActiveCollaborator {
+	user_id: string;
+	username: string;
+	user_color: string;
+	avatar_url?: string;
+}
+
 export interface CreateProjectRequest {
 	name: string;
 	description?: string;
@@ -65,18 +145,24 @@ export interface UpdateProjectRequest {
 	name?: string;
 	description?: string;
 	database_type?: 'postgresql' | 'mysql' | 'sqlite' | 'sqlserver';
+	version?: number;
 }
 
 export interface CreateTableRequest {
 	name: string;
 	pos_x: number;
 	pos_y: number;
+	color?: string;
+	icon?: TableIcon;
 }
 
 export interface UpdateTableRequest {
 	name?: string;
 	pos_x?: number;
 	pos_y?: number;
+	color?: string; // Empty for the default
+	icon?: TableIcon | ''; // Empty for none
+	version?: number;
 }
 
 export interface UpdateTablePositionRequest {
@@ -90,6 +176,10 @@ export interface CreateRelationshipRequest {
 	target_table_id: string;
 	target_field_id: string;
 	relation_type: 'one_to_one' | 'one_to_many' | 'many_to_many';
+	source_anchor?: EdgeAnchor;
+	target_anchor?: EdgeAnchor;
+	waypoints?: { x: number; y: number }[];
+	label_position?: number;
 }
 
 export interface UpdateRelationshipRequest {
@@ -98,6 +188,38 @@ export interface UpdateRelationshipRequest {
 	target_table_id?: string;
 	target_field_id?: string;
 	relation_type?: 'one_to_one' | 'one_to_many' | 'many_to_many';
+	source_anchor?: EdgeAnchor;
+	target_anchor?: EdgeAnchor;
+	waypoints?: { x: number; y: number }[];
+	label_position?: number;
+	version?: number;
+}
+
+export interface CreateRegionRequest {
+	name: string;
+	color: string;
+	pos_x: number;
+	pos_y: number;
+	width: number;
+	height: number;
+	table_ids?: string[];
+}
+
+export interface UpdateRegionRequest {
+	name?: string;
+	color?: string;
+	pos_x?: number;
+	pos_y?: number;
+	width?: number;
+	height?: number;
+	table_ids?: string[];
+	version?: number;
+}
+
+// Where each table of a moved region ended up
+export interface RegionMove {
+	region: Region;
+	tables: { table_id: string; pos_x: number; pos_y: number; version: number }[];
 }
 
 export interface CreateFieldRequest {
@@ -116,4 +238,5 @@ export interface UpdateFieldRequest {
 	is_nullable?: boolean;
 	default_value?: string;
 	position?: number;
+	version?: number;
 }